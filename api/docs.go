@@ -0,0 +1,119 @@
+package api
+
+import (
+	"github.com/dujoseaugusto/go-crawler-project/api/handler"
+	"github.com/dujoseaugusto/go-crawler-project/api/openapi"
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIAnnotationsPath é o documento escrito à mão com as descrições, exemplos e respostas dos
+// endpoints; rotas e schemas vêm do código
+const OpenAPIAnnotationsPath = "./web/static/swagger.yaml"
+
+// newOpenAPIGenerator cria o gerador da especificação com os schemas gerados dos tipos das respostas
+func newOpenAPIGenerator(annotationsPath string) *openapi.Generator {
+	generator, err := openapi.LoadGenerator(annotationsPath)
+	if err != nil {
+		logger.NewLogger("router").WithError(err).Warn("OpenAPI annotations unavailable, serving routes only")
+		generator, _ = openapi.NewGenerator(nil)
+	}
+
+	tags := map[string]string{
+		"health":     "Health",
+		"properties": "Properties",
+		"p":          "Properties",
+		"stats":      "Properties",
+		"crawler":    "Crawler",
+		"crawl":      "Crawler",
+		"cities":     "Cities",
+		"domains":    "Domains",
+		"content":    "Content Learning",
+		"patterns":   "Pattern Learning",
+		"":           "Web Interface",
+		"index":      "Web Interface",
+		"web":        "Web Interface",
+		"admin":      "Web Interface",
+		"docs":       "Web Interface",
+		"api-docs":   "Web Interface",
+		"openapi":    "Web Interface",
+	}
+	for segment, tag := range tags {
+		generator.RegisterTag(segment, tag)
+	}
+
+	schemas := map[string]interface{}{
+		"Property":                   repository.Property{},
+		"PropertyCorrection":         repository.PropertyCorrection{},
+		"Loteamento":                 repository.Loteamento{},
+		"City":                       repository.CitySites{},
+		"CityFreshness":              repository.CityFreshness{},
+		"CitySite":                   repository.SiteInfo{},
+		"SiteMigrationResult":        service.SiteMigrationResult{},
+		"CitySitesExport":            service.CitySitesExport{},
+		"CitySitesImportResult":      service.CitySitesImportResult{},
+		"DomainAPIConfig":            repository.DomainAPIConfig{},
+		"DomainConfig":               repository.DomainConfig{},
+		"DiscoverySourceYield":       repository.DiscoverySourceYield{},
+		"WhatsAppLead":               repository.WhatsAppLead{},
+		"PathStats":                  repository.PathStats{},
+		"CrawlJob":                   repository.CrawlJob{},
+		"CrawlJobTemplate":           repository.CrawlJobTemplate{},
+		"FieldProvenance":            repository.FieldProvenance{},
+		"ImportReport":               service.ImportReport{},
+		"FacetCount":                 repository.FacetCount{},
+		"PriceIndexPoint":            repository.PriceIndexPoint{},
+		"UnitConversion":             handler.UnitConversion{},
+		"ClassificationDecision":     crawler.ClassificationDecision{},
+		"ClassificationExplanation":  crawler.ClassificationExplanation{},
+		"PhotoFraudScan":             repository.PhotoFraudScan{},
+		"FraudReview":                repository.FraudReview{},
+		"DuplicateCluster":           repository.DuplicateCluster{},
+		"CanonicalProperty":          repository.CanonicalProperty{},
+		"CanonicalSource":            repository.CanonicalSource{},
+		"CanonicalMergeResult":       repository.CanonicalMergeResult{},
+		"PropertyFacets":             repository.PropertyFacets{},
+		"DomainCoverage":             repository.DomainCoverage{},
+		"DomainRateStats":            crawler.DomainRateStats{},
+		"GlobalRateStats":            crawler.GlobalRateStats{},
+		"CollectionStorageStats":     repository.CollectionStorageStats{},
+		"PruneResult":                repository.PruneResult{},
+		"URLStorageStats":            repository.URLStorageStats{},
+		"CrawlError":                 crawler.CrawlError{},
+		"FeedStatus":                 crawler.FeedStatus{},
+		"DomainQuality":              repository.DomainQuality{},
+		"BrokenPhoto":                crawler.BrokenPhoto{},
+		"RequestAuditEntry":          repository.RequestAuditEntry{},
+		"RequestAuditSummary":        repository.RequestAuditSummary{},
+		"DomainProbe":                crawler.DomainProbe{},
+		"CrawlJobCost":               repository.CrawlJobCost{},
+		"CrawlCostSummary":           repository.CrawlCostSummary{},
+		"ClassifierExperimentReport": repository.ClassifierExperimentReport{},
+		"DNSCacheStats":              crawler.DNSCacheStats{},
+		"ErrorReport":                crawler.ErrorReport{},
+		"Error":                      handler.ErrorResponse{},
+	}
+	for name, value := range schemas {
+		generator.RegisterSchema(name, value)
+	}
+	return generator
+}
+
+// BuildOpenAPISpec gera a especificação de todas as rotas sem subir o servidor (usada na geração
+// dos clientes); os serviços não são chamados, só as rotas são registradas
+func BuildOpenAPISpec(annotationsPath string) (openapi.Spec, openapi.Report) {
+	r := SetupRouterWithDomainConfigs(nil, &service.CitySitesService{}, crawler.NewPatternLearner(), nil, &service.DomainConfigService{})
+	return newOpenAPIGenerator(annotationsPath).Generate(r.Routes())
+}
+
+// registerOpenAPIRoutes serve a especificação gerada das rotas do engine; deve ser chamada
+// depois do registro das demais rotas
+func registerOpenAPIRoutes(r *gin.Engine, generator *openapi.Generator) {
+	docs := openapi.NewHandler(generator, r)
+	r.GET("/openapi.json", docs.JSON)
+	r.GET("/openapi.yaml", docs.YAML)
+	r.GET("/openapi/coverage", docs.Coverage)
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -33,6 +36,12 @@ type DiscoverSitesRequest struct {
 	Options repository.DiscoveryOptions `json:"options,omitempty"`
 }
 
+// citySitesYAML é o tipo do arquivo de cidades e sites exportado em YAML
+const citySitesYAML = "application/yaml"
+
+// defaultMigrationFiles são os arquivos migrados quando a requisição não informa nenhum
+var defaultMigrationFiles = []string{"List-site.ini", "configs/sites.json"}
+
 // AddSiteRequest representa requisição para adicionar site manualmente
 type AddSiteRequest struct {
 	URL    string `json:"url" binding:"required,url"`
@@ -135,7 +144,7 @@ func (h *CitySitesHandler) GetActiveDiscoveryJobs(c *gin.Context) {
 	})
 }
 
-// GetAllCities retorna todas as cidades cadastradas
+// GetAllCities retorna todas as cidades cadastradas (?stale=true só as desatualizadas além do SLA)
 func (h *CitySitesHandler) GetAllCities(c *gin.Context) {
 	h.logger.WithField("client_ip", c.ClientIP()).Debug("Getting all cities")
 
@@ -145,6 +154,16 @@ func (h *CitySitesHandler) GetAllCities(c *gin.Context) {
 		return
 	}
 
+	if c.Query("stale") == "true" {
+		stale := make([]repository.CitySites, 0, len(cities))
+		for _, city := range cities {
+			if city.Freshness != nil && city.Freshness.Stale {
+				stale = append(stale, city)
+			}
+		}
+		cities = stale
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: fmt.Sprintf("Encontradas %d cidades", len(cities)),
 		Data:    cities,
@@ -207,6 +226,7 @@ func (h *CitySitesHandler) GetCitySites(c *gin.Context) {
 		"active_sites": cityData.ActiveSites,
 		"sites":        sites,
 		"last_updated": cityData.LastUpdated,
+		"freshness":    cityData.Freshness,
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{
@@ -215,6 +235,50 @@ func (h *CitySitesHandler) GetCitySites(c *gin.Context) {
 	})
 }
 
+// SetCityRefreshSLARequest define o SLA de atualização de uma cidade
+type SetCityRefreshSLARequest struct {
+	SLAHours *int `json:"sla_hours" binding:"required"` // 0 volta ao SLA padrão (CITY_REFRESH_SLA)
+}
+
+// SetCityRefreshSLA define o SLA de atualização dos dados de uma cidade
+func (h *CitySitesHandler) SetCityRefreshSLA(c *gin.Context) {
+	city := c.Param("city")
+	state := c.Query("state")
+	if state == "" {
+		state = "MG"
+	}
+
+	var req SetCityRefreshSLARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Dados de requisição inválidos", err)
+		return
+	}
+	if *req.SLAHours < 0 {
+		h.respondWithError(c, http.StatusBadRequest, "sla_hours deve ser maior ou igual a zero", nil)
+		return
+	}
+
+	if _, err := h.Service.GetCityByName(c.Request.Context(), city, state); err != nil {
+		h.respondWithError(c, http.StatusNotFound, "Cidade não encontrada", err)
+		return
+	}
+	if err := h.Service.SetCityRefreshSLA(c.Request.Context(), city, state, *req.SLAHours); err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao definir SLA de atualização", err)
+		return
+	}
+
+	cityData, err := h.Service.GetCityByName(c.Request.Context(), city, state)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar cidade", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "SLA de atualização definido com sucesso",
+		Data:    cityData.Freshness,
+	})
+}
+
 // ValidateCitySites valida sites de uma cidade
 func (h *CitySitesHandler) ValidateCitySites(c *gin.Context) {
 	city := c.Param("city")
@@ -465,6 +529,122 @@ func (h *CitySitesHandler) CleanupInactiveSites(c *gin.Context) {
 	})
 }
 
+// MigrateSiteFiles importa List-site.ini / sites.json para o repositório de sites por cidade
+func (h *CitySitesHandler) MigrateSiteFiles(c *gin.Context) {
+	var req service.SiteMigrationOptions
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		h.respondWithError(c, http.StatusBadRequest, "Dados de requisição inválidos", err)
+		return
+	}
+	if len(req.Files) == 0 {
+		req.Files = defaultMigrationFiles
+	}
+	for _, file := range req.Files {
+		// Apenas arquivos do diretório do projeto (sem caminhos absolutos ou "..")
+		if !filepath.IsLocal(file) {
+			h.respondWithError(c, http.StatusBadRequest, fmt.Sprintf("Arquivo inválido: %s", file), nil)
+			return
+		}
+	}
+	if req.DefaultCity != "" && len(req.DefaultState) != 2 {
+		h.respondWithError(c, http.StatusBadRequest, "default_state deve ser a UF da cidade padrão", nil)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"files":     req.Files,
+		"dry_run":   req.DryRun,
+		"client_ip": c.ClientIP(),
+	}).Info("Site files migration requested")
+
+	result, err := h.Service.MigrateSiteFiles(c.Request.Context(), req)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro na migração dos arquivos de sites", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Migração concluída: %d sites adicionados em %d cidades", result.SitesAdded, result.Cities),
+		Data:    result,
+	})
+}
+
+// ExportCitySites exporta todas as cidades, seus sites e as configurações por domínio em YAML
+// (padrão) ou JSON, no formato aceito por POST /cities/import
+func (h *CitySitesHandler) ExportCitySites(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = "yaml"
+		if c.NegotiateFormat(citySitesYAML, gin.MIMEJSON) == gin.MIMEJSON {
+			format = "json"
+		}
+	}
+	if format != "yaml" && format != "json" {
+		h.respondWithError(c, http.StatusBadRequest, "Formato inválido (use yaml ou json)", nil)
+		return
+	}
+
+	export, err := h.Service.ExportCitySites(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao exportar cidades e sites", err)
+		return
+	}
+	data, err := service.MarshalCitySitesExport(export, format)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao exportar cidades e sites", err)
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	if format == "yaml" {
+		contentType = citySitesYAML + "; charset=utf-8"
+	}
+	c.Header("Content-Disposition", "attachment; filename=city_sites."+format)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportCitySites aplica o arquivo (YAML ou JSON) exportado por GET /cities/export. Com
+// replace=true os sites das cidades do arquivo substituem os cadastrados
+func (h *CitySitesHandler) ImportCitySites(c *gin.Context) {
+	data, err := c.GetRawData()
+	if err != nil || len(data) == 0 {
+		h.respondWithError(c, http.StatusBadRequest, "Arquivo de cidades vazio", err)
+		return
+	}
+
+	opts := service.CitySitesImportOptions{
+		Replace: c.Query("replace") == "true",
+		DryRun:  c.Query("dry_run") == "true",
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"bytes":     len(data),
+		"replace":   opts.Replace,
+		"dry_run":   opts.DryRun,
+		"client_ip": c.ClientIP(),
+	}).Info("City sites import requested")
+
+	export, err := service.ParseCitySitesExport(data)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Arquivo de cidades inválido", err)
+		return
+	}
+	result, err := h.Service.ImportCitySites(c.Request.Context(), *export, opts)
+	switch {
+	case errors.Is(err, service.ErrInvalidCitySitesFile):
+		h.respondWithError(c, http.StatusBadRequest, "Arquivo de cidades inválido", err)
+		return
+	case err != nil:
+		h.respondWithError(c, http.StatusInternalServerError, "Erro na importação das cidades", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Importação concluída: %d cidades, %d sites adicionados, %d atualizados", result.Cities, result.SitesAdded, result.SitesUpdated),
+		Data:    result,
+	})
+}
+
 // GetCitiesByRegion retorna cidades de uma região específica
 func (h *CitySitesHandler) GetCitiesByRegion(c *gin.Context) {
 	region := c.Param("region")
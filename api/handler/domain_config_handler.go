@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// DomainConfigHandler gerencia endpoints de configuração por domínio
+type DomainConfigHandler struct {
+	Service *service.DomainConfigService
+	logger  *logger.Logger
+}
+
+// NewDomainConfigHandler cria um novo handler
+func NewDomainConfigHandler(service *service.DomainConfigService) *DomainConfigHandler {
+	return &DomainConfigHandler{
+		Service: service,
+		logger:  logger.NewLogger("domain_config_handler"),
+	}
+}
+
+// DomainConfigRequest representa o corpo de criação/atualização da configuração de um domínio
+type DomainConfigRequest struct {
+	RateLimit       repository.DomainRateLimit `json:"rate_limit"`
+	EnableJS        bool                       `json:"enable_js"`
+	IncludePatterns []string                   `json:"include_patterns" binding:"max=50"`
+	ExcludePatterns []string                   `json:"exclude_patterns" binding:"max=50"`
+	Selectors       map[string]string          `json:"selectors"`
+	Notes           string                     `json:"notes,omitempty" binding:"max=500"`
+
+	// Script Lua de pós-processamento (tabela global "property", alterada in-place)
+	PostProcessScript string `json:"post_process_script,omitempty"`
+
+	// API JSON de listagem do portal (substitui o crawling HTML do domínio)
+	API *repository.DomainAPIConfig `json:"api,omitempty"`
+
+	// Feeds RSS/Atom de novos anúncios
+	Feeds []string `json:"feeds,omitempty" binding:"max=20"`
+
+	// Subdomínios permitidos no modo estrito de domínios
+	AllowSubdomains bool `json:"allow_subdomains,omitempty"`
+
+	// Janelas de crawling do dia (ex: "00:00-06:00") e o fuso em que são interpretadas
+	CrawlWindows []string `json:"crawl_windows,omitempty" binding:"max=12"`
+	Timezone     string   `json:"timezone,omitempty" binding:"max=64"`
+
+	// Política das fotos no proxy de fotos (proxy, redirect ou deny) e envio do Referer
+	PhotoPolicy  string `json:"photo_policy,omitempty"`
+	PhotoReferer bool   `json:"photo_referer,omitempty"`
+}
+
+// GetAllDomainConfigs lista as configurações de todos os domínios
+func (h *DomainConfigHandler) GetAllDomainConfigs(c *gin.Context) {
+	configs, err := h.Service.GetAllDomainConfigs(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar configurações", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Encontradas %d configurações de domínio", len(configs)),
+		Data:    configs,
+	})
+}
+
+// GetDomainConfig retorna a configuração de um domínio
+func (h *DomainConfigHandler) GetDomainConfig(c *gin.Context) {
+	domain := c.Param("domain")
+	if repository.NormalizeDomain(domain) == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio inválido", nil)
+		return
+	}
+
+	config, err := h.Service.GetDomainConfig(c.Request.Context(), domain)
+	if errors.Is(err, repository.ErrDomainConfigNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Configuração não encontrada", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar configuração", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Configuração encontrada",
+		Data:    config,
+	})
+}
+
+// SaveDomainConfig cria ou substitui a configuração de um domínio
+func (h *DomainConfigHandler) SaveDomainConfig(c *gin.Context) {
+	domain := c.Param("domain")
+	if repository.NormalizeDomain(domain) == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio inválido", nil)
+		return
+	}
+
+	var req DomainConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Dados de requisição inválidos", err)
+		return
+	}
+
+	config := repository.DomainConfig{
+		RateLimit:       req.RateLimit,
+		EnableJS:        req.EnableJS,
+		IncludePatterns: req.IncludePatterns,
+		ExcludePatterns: req.ExcludePatterns,
+		Selectors:       req.Selectors,
+		Notes:           sanitizeString(req.Notes, 500),
+
+		PostProcessScript: req.PostProcessScript,
+		API:               req.API,
+		Feeds:             req.Feeds,
+		AllowSubdomains:   req.AllowSubdomains,
+		CrawlWindows:      req.CrawlWindows,
+		Timezone:          req.Timezone,
+		PhotoPolicy:       req.PhotoPolicy,
+		PhotoReferer:      req.PhotoReferer,
+	}
+
+	// Valida antes de chamar o serviço para diferenciar erro do cliente de erro interno
+	config.Domain = repository.NormalizeDomain(domain)
+	if err := config.Validate(); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Configuração inválida", err)
+		return
+	}
+
+	saved, err := h.Service.SaveDomainConfig(c.Request.Context(), domain, config)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao salvar configuração", err)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"domain":    saved.Domain,
+		"client_ip": c.ClientIP(),
+	}).Info("Domain config updated via API")
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Configuração salva com sucesso",
+		Data:    saved,
+	})
+}
+
+// DeleteDomainConfig remove a configuração de um domínio
+func (h *DomainConfigHandler) DeleteDomainConfig(c *gin.Context) {
+	domain := c.Param("domain")
+	if repository.NormalizeDomain(domain) == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio inválido", nil)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"domain":    domain,
+		"client_ip": c.ClientIP(),
+	}).Warn("Domain config deletion requested")
+
+	err := h.Service.DeleteDomainConfig(c.Request.Context(), domain)
+	if errors.Is(err, repository.ErrDomainConfigNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Configuração não encontrada", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao remover configuração", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Configuração removida com sucesso",
+		Data: map[string]interface{}{
+			"domain": repository.NormalizeDomain(domain),
+		},
+	})
+}
+
+// GetSkipPaths retorna os caminhos aprendidos do domínio e o acumulado da classificação das
+// páginas de cada caminho
+func (h *DomainConfigHandler) GetSkipPaths(c *gin.Context) {
+	domain := repository.NormalizeDomain(c.Param("domain"))
+	if domain == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio inválido", nil)
+		return
+	}
+
+	stats, err := h.Service.GetSkipPathStats(c.Request.Context(), domain)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar caminhos aprendidos", err)
+		return
+	}
+
+	learned := []string{}
+	for _, path := range stats {
+		if path.Learned {
+			learned = append(learned, path.Path)
+		}
+	}
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d caminhos ignorados de %d acompanhados", len(learned), len(stats)),
+		Data: map[string]interface{}{
+			"domain":             domain,
+			"learned_skip_paths": learned,
+			"paths":              stats,
+		},
+	})
+}
+
+// DeleteSkipPath remove um caminho da lista aprendida (?path=/blog); o crawler volta a visitá-lo
+// e não o aprende de novo
+func (h *DomainConfigHandler) DeleteSkipPath(c *gin.Context) {
+	domain := repository.NormalizeDomain(c.Param("domain"))
+	path := strings.ToLower(strings.TrimSpace(c.Query("path")))
+	if domain == "" || !strings.HasPrefix(path, "/") {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio ou caminho inválido (ex: ?path=/blog)", nil)
+		return
+	}
+
+	err := h.Service.ForgetSkipPath(c.Request.Context(), domain, path)
+	if errors.Is(err, repository.ErrSkipPathNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Caminho não está na lista aprendida", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao remover caminho aprendido", err)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"domain":    domain,
+		"path":      path,
+		"client_ip": c.ClientIP(),
+	}).Info("Learned skip path removed via API")
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Caminho removido da lista aprendida",
+		Data: map[string]interface{}{
+			"domain": domain,
+			"path":   path,
+		},
+	})
+}
+
+// respondWithError envia uma resposta de erro padronizada
+func (h *DomainConfigHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	h.logger.WithFields(map[string]interface{}{
+		"method":      c.Request.Method,
+		"path":        c.Request.URL.Path,
+		"client_ip":   c.ClientIP(),
+		"status_code": statusCode,
+	}).Error(message, err)
+
+	response := ErrorResponse{
+		Error:   message,
+		Code:    statusCode,
+		Message: "Consulte os logs para mais detalhes",
+	}
+	// Erros de validação são devolvidos ao cliente para facilitar o ajuste da configuração
+	if statusCode == http.StatusBadRequest && err != nil {
+		response.Message = err.Error()
+	}
+
+	c.JSON(statusCode, response)
+}
@@ -1,24 +1,34 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/gin-gonic/gin"
 )
 
+// patternImportTTL é o prazo para aprovar uma importação de padrões
+const patternImportTTL = time.Hour
+
 // PatternLearningHandler gerencia o aprendizado de padrões
 type PatternLearningHandler struct {
 	patternLearner *crawler.PatternLearner
+	imports        *crawler.PatternImportQueue
 	logger         *logger.Logger
 }
 
-// NewPatternLearningHandler cria um novo handler de aprendizado de padrões
-func NewPatternLearningHandler(patternLearner *crawler.PatternLearner) *PatternLearningHandler {
+// NewPatternLearningHandler cria um novo handler de aprendizado de padrões; sem fila de
+// importações persistente, as importações pendentes ficam em memória
+func NewPatternLearningHandler(patternLearner *crawler.PatternLearner, imports *crawler.PatternImportQueue) *PatternLearningHandler {
+	if imports == nil {
+		imports = crawler.NewPatternImportQueue(patternLearner, patternImportTTL, nil)
+	}
 	return &PatternLearningHandler{
 		patternLearner: patternLearner,
+		imports:        imports,
 		logger:         logger.NewLogger("pattern_learning_handler"),
 	}
 }
@@ -255,40 +265,122 @@ func (plh *PatternLearningHandler) ExportPatterns(c *gin.Context) {
 	c.Data(http.StatusOK, "application/json", data)
 }
 
-// ImportPatterns importa padrões de um arquivo JSON
+// ImportPatterns valida um arquivo de padrões e o deixa aguardando aprovação
 // @Summary Importar padrões
-// @Description Importa padrões de um arquivo JSON
+// @Description Valida o arquivo exportado por /patterns/export (versão do formato, checksum e padrões) e mostra as mudanças. Com dry_run=true apenas retorna a prévia; senão a importação fica pendente até POST /patterns/import/{id}/approve
 // @Tags Pattern Learning
 // @Accept json
 // @Produce json
-// @Param patterns body map[string]interface{} true "Padrões para importar"
-// @Success 200 {object} map[string]interface{} "Padrões importados com sucesso"
-// @Failure 400 {object} map[string]interface{} "Erro na requisição"
-// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Param dry_run query bool false "Apenas valida e mostra a prévia"
+// @Param patterns body map[string]interface{} true "Arquivo de padrões"
+// @Success 200 {object} map[string]interface{} "Prévia da importação"
+// @Success 202 {object} map[string]interface{} "Importação aguardando aprovação"
+// @Failure 400 {object} map[string]interface{} "Arquivo de padrões inválido"
+// @Failure 409 {object} map[string]interface{} "Arquivo já importado"
 // @Router /patterns/import [post]
 func (plh *PatternLearningHandler) ImportPatterns(c *gin.Context) {
-	var data map[string]interface{}
-	if err := c.ShouldBindJSON(&data); err != nil {
-		plh.logger.Error("Invalid JSON format", err)
+	data, err := c.GetRawData()
+	if err != nil || len(data) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Formato JSON inválido",
+			"error":   "Arquivo de padrões vazio",
+			"message": "Envie o JSON exportado por /patterns/export",
+		})
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		preview, err := plh.patternLearner.PreviewImport(data)
+		if err != nil {
+			plh.logger.Error("Invalid pattern file", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Arquivo de padrões inválido",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Prévia da importação",
+			"data":    preview,
+		})
+		return
+	}
+
+	pending, err := plh.imports.Stage(c.Request.Context(), data)
+	if errors.Is(err, crawler.ErrPatternImportReplayed) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Arquivo de padrões já importado",
 			"message": err.Error(),
 		})
 		return
 	}
+	if err != nil {
+		plh.logger.Error("Invalid pattern file", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Arquivo de padrões inválido",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	plh.logger.WithFields(map[string]interface{}{
+		"import_id": pending.ID,
+		"checksum":  pending.Preview.Checksum,
+	}).Info("Pattern import staged for approval")
 
-	// Converte de volta para JSON para importar
-	jsonData, err := json.Marshal(data)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Importação aguardando aprovação",
+		"data":    pending,
+	})
+}
+
+// GetPendingPatternImports lista as importações aguardando aprovação
+// @Summary Listar importações pendentes
+// @Tags Pattern Learning
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Importações pendentes"
+// @Router /patterns/imports [get]
+func (plh *PatternLearningHandler) GetPendingPatternImports(c *gin.Context) {
+	pending, err := plh.imports.Pending(c.Request.Context())
 	if err != nil {
-		plh.logger.Error("Failed to marshal data", err)
+		plh.logger.Error("Failed to list pattern imports", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao processar dados",
+			"error":   "Erro ao listar importações",
 			"message": err.Error(),
 		})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": pending,
+	})
+}
 
-	if err := plh.patternLearner.ImportPatterns(jsonData); err != nil {
+// ApprovePatternImport aplica uma importação pendente
+// @Summary Aprovar importação de padrões
+// @Description Aplica a importação se os padrões ativos não mudaram desde a prévia. Cada arquivo só é aplicado uma vez
+// @Tags Pattern Learning
+// @Produce json
+// @Param id path string true "ID da importação"
+// @Success 200 {object} map[string]interface{} "Padrões importados com sucesso"
+// @Failure 404 {object} map[string]interface{} "Importação não encontrada"
+// @Failure 409 {object} map[string]interface{} "Padrões alterados desde a prévia ou arquivo já importado"
+// @Failure 410 {object} map[string]interface{} "Importação expirada"
+// @Router /patterns/import/{id}/approve [post]
+func (plh *PatternLearningHandler) ApprovePatternImport(c *gin.Context) {
+	pending, err := plh.imports.Approve(c.Request.Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, crawler.ErrPatternImportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Importação não encontrada"})
+		return
+	case errors.Is(err, crawler.ErrPatternImportExpired):
+		c.JSON(http.StatusGone, gin.H{"error": "Importação expirada; envie o arquivo novamente"})
+		return
+	case errors.Is(err, crawler.ErrPatternImportStale):
+		c.JSON(http.StatusConflict, gin.H{"error": "Os padrões mudaram desde a prévia; envie o arquivo novamente"})
+		return
+	case errors.Is(err, crawler.ErrPatternImportReplayed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Arquivo de padrões já importado"})
+		return
+	case err != nil:
 		plh.logger.Error("Failed to import patterns", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro ao importar padrões",
@@ -297,15 +389,45 @@ func (plh *PatternLearningHandler) ImportPatterns(c *gin.Context) {
 		return
 	}
 
-	patterns := plh.patternLearner.GetLearnedPatterns()
-	catalogCount := len(patterns["catalog"])
-	propertyCount := len(patterns["property"])
+	plh.logger.WithFields(map[string]interface{}{
+		"import_id": pending.ID,
+		"checksum":  pending.Preview.Checksum,
+	}).Info("Pattern import approved")
 
+	patterns := plh.patternLearner.GetLearnedPatterns()
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Padrões importados com sucesso",
 		"data": gin.H{
-			"catalog_patterns_imported":  catalogCount,
-			"property_patterns_imported": propertyCount,
+			"import_id":               pending.ID,
+			"checksum":                pending.Preview.Checksum,
+			"diff":                    pending.Preview.Diff,
+			"catalog_patterns_count":  len(patterns["catalog"]),
+			"property_patterns_count": len(patterns["property"]),
 		},
 	})
 }
+
+// RejectPatternImport descarta uma importação pendente
+// @Summary Rejeitar importação de padrões
+// @Tags Pattern Learning
+// @Produce json
+// @Param id path string true "ID da importação"
+// @Success 200 {object} map[string]interface{} "Importação descartada"
+// @Failure 404 {object} map[string]interface{} "Importação não encontrada"
+// @Router /patterns/import/{id} [delete]
+func (plh *PatternLearningHandler) RejectPatternImport(c *gin.Context) {
+	err := plh.imports.Reject(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, crawler.ErrPatternImportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Importação não encontrada"})
+		return
+	}
+	if err != nil {
+		plh.logger.Error("Failed to reject pattern import", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro ao rejeitar importação",
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Importação descartada"})
+}
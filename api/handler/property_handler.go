@@ -2,13 +2,22 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/dujoseaugusto/go-crawler-project/internal/service"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,20 +36,42 @@ func NewPropertyHandler(service *service.PropertyService) *PropertyHandler {
 
 // SearchRequest representa os parâmetros de busca validados
 type SearchRequest struct {
-	Query        string  `form:"q" binding:"omitempty,max=200"` // Busca inteligente por palavras-chave
-	Cidade       string  `form:"cidade" binding:"omitempty,max=50"`
-	Bairro       string  `form:"bairro" binding:"omitempty,max=50"`
-	TipoImovel   string  `form:"tipo_imovel" binding:"omitempty,max=30"`
-	ValorMin     float64 `form:"valor_min" binding:"omitempty,min=0,max=100000000"`
-	ValorMax     float64 `form:"valor_max" binding:"omitempty,min=0,max=100000000"`
-	QuartosMin   int     `form:"quartos_min" binding:"omitempty,min=0,max=20"`
-	QuartosMax   int     `form:"quartos_max" binding:"omitempty,min=0,max=20"`
-	BanheirosMin int     `form:"banheiros_min" binding:"omitempty,min=0,max=20"`
-	BanheirosMax int     `form:"banheiros_max" binding:"omitempty,min=0,max=20"`
-	AreaMin      float64 `form:"area_min" binding:"omitempty,min=0,max=100000"`
-	AreaMax      float64 `form:"area_max" binding:"omitempty,min=0,max=100000"`
-	Page         int     `form:"page" binding:"omitempty,min=1,max=1000"`
-	PageSize     int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Query            string  `form:"q" binding:"omitempty,max=200"` // Busca inteligente por palavras-chave
+	Cidade           string  `form:"cidade" binding:"omitempty,max=50"`
+	Bairro           string  `form:"bairro" binding:"omitempty,max=50"`
+	TipoImovel       string  `form:"tipo_imovel" binding:"omitempty,max=30"`
+	ValorMin         float64 `form:"valor_min" binding:"omitempty,min=0,max=100000000"`
+	ValorMax         float64 `form:"valor_max" binding:"omitempty,min=0,max=100000000"`
+	QuartosMin       int     `form:"quartos_min" binding:"omitempty,min=0,max=20"`
+	QuartosMax       int     `form:"quartos_max" binding:"omitempty,min=0,max=20"`
+	BanheirosMin     int     `form:"banheiros_min" binding:"omitempty,min=0,max=20"`
+	BanheirosMax     int     `form:"banheiros_max" binding:"omitempty,min=0,max=20"`
+	AreaMin          float64 `form:"area_min" binding:"omitempty,min=0,max=100000"`
+	AreaMax          float64 `form:"area_max" binding:"omitempty,min=0,max=100000"`
+	Condicao         string  `form:"condicao" binding:"omitempty,max=30"`
+	CondicaoMin      int     `form:"condicao_min" binding:"omitempty,min=1,max=4"`
+	Caracteristicas  string  `form:"caracteristicas" binding:"omitempty,max=200"` // Ex.: piscina,garagem
+	FotosMin         int     `form:"fotos_min" binding:"omitempty,min=0,max=200"`
+	ComFotos         bool    `form:"com_fotos"`
+	EnergiaSolar     bool    `form:"energia_solar"`
+	PocoArtesiano    bool    `form:"poco_artesiano"`
+	AquecimentoGas   bool    `form:"aquecimento_gas"`
+	Leilao           *bool   `form:"leilao"` // true: apenas leilões; false: exclui leilões
+	Finalidade       string  `form:"finalidade" binding:"omitempty,oneof=residencial comercial rural temporada"`
+	AluguelMin       float64 `form:"aluguel_min" binding:"omitempty,min=0,max=10000000"`
+	AluguelMax       float64 `form:"aluguel_max" binding:"omitempty,min=0,max=10000000"`
+	ApenasAluguel    bool    `form:"apenas_aluguel"`
+	OcultarSuspeitos bool    `form:"ocultar_suspeitos"` // exclui suspeito_golpe e suspeito_fraude
+	RiscoGolpeMax    *int    `form:"risco_golpe_max" binding:"omitempty,min=0,max=100"`
+	Tags             string  `form:"tags" binding:"omitempty,max=200"` // Tags de crawling, ex.: muzambinho-weekly
+
+	// Distâncias máximas em km até centro, escola e hospital (campo distancias_poi)
+	MaxDistCentroKm   float64 `form:"max_dist_centro_km" binding:"omitempty,min=0,max=500"`
+	MaxDistEscolaKm   float64 `form:"max_dist_escola_km" binding:"omitempty,min=0,max=500"`
+	MaxDistHospitalKm float64 `form:"max_dist_hospital_km" binding:"omitempty,min=0,max=500"`
+
+	Page     int `form:"page" binding:"omitempty,min=1,max=1000"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
 }
 
 // ErrorResponse representa uma resposta de erro padronizada
@@ -78,6 +109,12 @@ func sanitizeString(input string, maxLength int) string {
 
 // respondWithError envia uma resposta de erro padronizada
 func (h *PropertyHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	// Banco indisponível: responde 503 para que clientes tentem novamente em vez de tratar como falha definitiva
+	if repository.IsStorageUnavailable(err) {
+		statusCode = http.StatusServiceUnavailable
+		message = "Banco de dados temporariamente indisponível"
+	}
+
 	h.logger.WithFields(map[string]interface{}{
 		"method":      c.Request.Method,
 		"path":        c.Request.URL.Path,
@@ -108,6 +145,11 @@ func (h *PropertyHandler) validateSearchParams(req *SearchRequest) error {
 		return fmt.Errorf("quartos_max deve ser maior que quartos_min")
 	}
 
+	// Valida se aluguel máximo é maior que mínimo
+	if req.AluguelMax > 0 && req.AluguelMin > 0 && req.AluguelMax < req.AluguelMin {
+		return fmt.Errorf("aluguel_max deve ser maior que aluguel_min")
+	}
+
 	// Valida se banheiros máximo é maior que mínimo
 	if req.BanheirosMax > 0 && req.BanheirosMin > 0 && req.BanheirosMax < req.BanheirosMin {
 		return fmt.Errorf("banheiros_max deve ser maior que banheiros_min")
@@ -118,9 +160,76 @@ func (h *PropertyHandler) validateSearchParams(req *SearchRequest) error {
 		return fmt.Errorf("area_max deve ser maior que area_min")
 	}
 
+	// Valida se a condição informada é conhecida
+	if req.Condicao != "" && repository.NormalizeCondition(req.Condicao) == "" {
+		return fmt.Errorf("condicao deve ser novo, reformado, usado ou precisa_reforma")
+	}
+
+	// Valida se todas as características informadas são conhecidas
+	if req.Caracteristicas != "" {
+		if _, unknown := repository.ParseAmenityFilter(req.Caracteristicas); len(unknown) > 0 {
+			return fmt.Errorf("caracteristicas desconhecidas: %s", strings.Join(unknown, ", "))
+		}
+	}
+
+	// Valida o formato das tags de crawling
+	if req.Tags != "" {
+		if _, err := repository.ParseCrawlTags(req.Tags); err != nil {
+			return fmt.Errorf("tags: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// includesField verifica se o parâmetro ?include= (lista separada por vírgula) contém o campo
+func includesField(c *gin.Context, field string) bool {
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if strings.EqualFold(strings.TrimSpace(include), field) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAsOf aceita uma data (AAAA-MM-DD, considerada até o fim do dia em UTC) ou RFC 3339
+func parseAsOf(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if day, err := time.Parse("2006-01-02", value); err == nil {
+		return day.Add(24*time.Hour - time.Second), nil
+	}
+	asOf, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("use AAAA-MM-DD ou RFC 3339: %v", err)
+	}
+	return asOf, nil
+}
+
+// omitFieldConfidence remove as notas de confiança quando não solicitadas via ?include=confidence
+func omitFieldConfidence(properties []repository.Property) {
+	for i := range properties {
+		properties[i].FieldConfidence = nil
+	}
+}
+
+// applyCurrency mantém o preço convertido apenas quando pedido via ?currency=USD (ou EUR);
+// retorna false após responder com erro se a moeda não puder ser usada
+func (h *PropertyHandler) applyCurrency(c *gin.Context, properties []repository.Property) bool {
+	currency := sanitizeString(c.Query("currency"), 3)
+	if currency == "" || strings.EqualFold(currency, repository.CurrencyBRL) {
+		for i := range properties {
+			properties[i].ValorConvertido = nil
+			properties[i].CambioData = nil
+		}
+		return true
+	}
+	if err := h.Service.ConvertPrices(c.Request.Context(), properties, currency); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Não foi possível converter os preços", err)
+		return false
+	}
+	return true
+}
+
 func (h *PropertyHandler) GetProperties(c *gin.Context) {
 	h.logger.WithFields(map[string]interface{}{
 		"method":    c.Request.Method,
@@ -128,14 +237,34 @@ func (h *PropertyHandler) GetProperties(c *gin.Context) {
 		"client_ip": c.ClientIP(),
 	}).Info("Fetching all properties")
 
-	properties, err := h.Service.GetAllProperties(c.Request.Context())
+	// ?as_of= retorna o acervo como estava na data (versões gravadas até então)
+	var properties []repository.Property
+	var err error
+	message := "Propriedades recuperadas com sucesso"
+	if value := c.Query("as_of"); value != "" {
+		asOf, parseErr := parseAsOf(value)
+		if parseErr != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Parâmetro as_of inválido", parseErr)
+			return
+		}
+		properties, err = h.Service.GetPropertiesAsOf(c.Request.Context(), asOf)
+		message = fmt.Sprintf("Propriedades em %s", asOf.Format(time.RFC3339))
+	} else {
+		properties, err = h.Service.GetAllProperties(c.Request.Context())
+	}
 	if err != nil {
 		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar propriedades", err)
 		return
 	}
+	if !includesField(c, "confidence") {
+		omitFieldConfidence(properties)
+	}
+	if !h.applyCurrency(c, properties) {
+		return
+	}
 
 	response := SuccessResponse{
-		Message: "Propriedades recuperadas com sucesso",
+		Message: message,
 		Data:    properties,
 	}
 
@@ -165,11 +294,7 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 		return
 	}
 
-	// Sanitiza strings de entrada
-	req.Query = sanitizeString(req.Query, 200)
-	req.Cidade = sanitizeString(req.Cidade, 50)
-	req.Bairro = sanitizeString(req.Bairro, 50)
-	req.TipoImovel = sanitizeString(req.TipoImovel, 30)
+	filter := buildSearchFilter(&req)
 
 	// Define valores padrão para paginação
 	if req.Page == 0 {
@@ -182,12 +307,14 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 	// Log dos filtros aplicados
 	h.logger.WithFields(map[string]interface{}{
 		"filters": map[string]interface{}{
-			"query":       req.Query,
-			"cidade":      req.Cidade,
-			"bairro":      req.Bairro,
-			"tipo_imovel": req.TipoImovel,
-			"valor_min":   req.ValorMin,
-			"valor_max":   req.ValorMax,
+			"query":           req.Query,
+			"cidade":          req.Cidade,
+			"bairro":          req.Bairro,
+			"tipo_imovel":     req.TipoImovel,
+			"valor_min":       req.ValorMin,
+			"valor_max":       req.ValorMax,
+			"caracteristicas": req.Caracteristicas,
+			"tags":            req.Tags,
 		},
 		"pagination": map[string]interface{}{
 			"page":      req.Page,
@@ -195,7 +322,44 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 		},
 	}).Debug("Search filters applied")
 
-	// Converte para estruturas internas
+	pagination := repository.PaginationParams{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	// Executar busca
+	result, err := h.Service.SearchProperties(c.Request.Context(), filter, pagination)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar propriedades", err)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"total_items":  result.TotalItems,
+		"current_page": result.CurrentPage,
+		"total_pages":  result.TotalPages,
+		"results":      len(result.Properties),
+	}).Info("Search completed successfully")
+
+	if !includesField(c, "confidence") {
+		omitFieldConfidence(result.Properties)
+	}
+	if !h.applyCurrency(c, result.Properties) {
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// buildSearchFilter sanitiza os parâmetros de busca e os converte no filtro do repositório
+func buildSearchFilter(req *SearchRequest) repository.PropertyFilter {
+	// Sanitiza strings de entrada
+	req.Query = sanitizeString(req.Query, 200)
+	req.Cidade = sanitizeString(req.Cidade, 50)
+	req.Bairro = sanitizeString(req.Bairro, 50)
+	req.TipoImovel = sanitizeString(req.TipoImovel, 30)
+	req.Condicao = sanitizeString(req.Condicao, 30)
+
 	filter := repository.PropertyFilter{
 		Query:        req.Query,
 		Cidade:       req.Cidade,
@@ -209,34 +373,147 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 		BanheirosMax: req.BanheirosMax,
 		AreaMin:      req.AreaMin,
 		AreaMax:      req.AreaMax,
-	}
+		Condicao:     req.Condicao,
+		CondicaoMin:  req.CondicaoMin,
+		FotosMin:     req.FotosMin,
+		ComFotos:     req.ComFotos,
 
-	pagination := repository.PaginationParams{
-		Page:     req.Page,
-		PageSize: req.PageSize,
+		EnergiaSolar:   req.EnergiaSolar,
+		PocoArtesiano:  req.PocoArtesiano,
+		AquecimentoGas: req.AquecimentoGas,
+		Leilao:         req.Leilao,
+		Finalidade:     req.Finalidade,
+		AluguelMin:     req.AluguelMin,
+		AluguelMax:     req.AluguelMax,
+		ApenasAluguel:  req.ApenasAluguel,
+
+		OcultarSuspeitos: req.OcultarSuspeitos,
+		RiscoGolpeMax:    req.RiscoGolpeMax,
+
+		MaxDistCentroKm:   req.MaxDistCentroKm,
+		MaxDistEscolaKm:   req.MaxDistEscolaKm,
+		MaxDistHospitalKm: req.MaxDistHospitalKm,
 	}
+	filter.Caracteristicas, _ = repository.ParseAmenityFilter(req.Caracteristicas)
+	filter.Tags, _ = repository.ParseCrawlTags(req.Tags)
 
-	// Executar busca
-	result, err := h.Service.SearchProperties(c.Request.Context(), filter, pagination)
-	if err != nil {
-		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar propriedades", err)
+	return filter
+}
+
+const (
+	// propertyStreamCheckpointEvery é o intervalo, em imóveis, das linhas de controle com o cursor
+	propertyStreamCheckpointEvery = 100
+	// propertyStreamWriteTimeout é o tempo máximo que o cliente pode ficar sem ler a exportação
+	propertyStreamWriteTimeout = time.Minute
+)
+
+// StreamProperties exporta os imóveis da busca em NDJSON, um por linha, sem paginação. A cada
+// propertyStreamCheckpointEvery imóveis e no fim vem uma linha de controle com o cursor que retoma a
+// exportação (?cursor=) depois do último imóvel enviado
+func (h *PropertyHandler) StreamProperties(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Parâmetros de busca inválidos", err)
+		return
+	}
+	if err := h.validateSearchParams(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Parâmetros de busca inválidos", err)
 		return
 	}
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			h.respondWithError(c, http.StatusBadRequest, "Parâmetro limit inválido", err)
+			return
+		}
+		limit = parsed
+	}
+	filter := buildSearchFilter(&req)
+	withConfidence := includesField(c, "confidence")
 
 	h.logger.WithFields(map[string]interface{}{
-		"total_items":  result.TotalItems,
-		"current_page": result.CurrentPage,
-		"total_pages":  result.TotalPages,
-		"results":      len(result.Properties),
-	}).Info("Search completed successfully")
+		"query":     c.Request.URL.RawQuery,
+		"resumed":   c.Query("cursor") != "",
+		"client_ip": c.ClientIP(),
+	}).Info("Property stream requested")
 
-	c.JSON(http.StatusOK, result)
+	// O Mongo só entrega o próximo lote depois que o cliente leu o anterior (escrita bloqueante);
+	// um cliente parado além de propertyStreamWriteTimeout encerra a exportação
+	controller := http.NewResponseController(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+	checkpoint := service.PropertyStreamCheckpoint{Cursor: c.Query("cursor")}
+	writeCheckpoint := func() error {
+		if err := encoder.Encode(checkpoint); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		_ = controller.SetWriteDeadline(time.Now().Add(propertyStreamWriteTimeout))
+		return nil
+	}
+
+	err := h.Service.StreamProperties(c.Request.Context(), filter, checkpoint.Cursor, limit, func(property repository.Property, next string) error {
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+			c.Header("Cache-Control", "no-store")
+			c.Header("X-Accel-Buffering", "no") // sem buffer no proxy reverso
+			c.Status(http.StatusOK)
+			_ = controller.SetWriteDeadline(time.Now().Add(propertyStreamWriteTimeout))
+		}
+		if !withConfidence {
+			property.FieldConfidence = nil
+		}
+		if err := encoder.Encode(property); err != nil {
+			return err
+		}
+		checkpoint.Cursor = next
+		checkpoint.Count++
+		if checkpoint.Count%propertyStreamCheckpointEvery == 0 {
+			return writeCheckpoint()
+		}
+		return nil
+	})
+
+	switch {
+	case err != nil && !c.Writer.Written():
+		status := http.StatusInternalServerError
+		message := "Erro ao exportar imóveis"
+		if errors.Is(err, service.ErrInvalidPropertyCursor) {
+			status, message = http.StatusBadRequest, "Cursor inválido"
+		} else if errors.Is(err, service.ErrTooManyPropertyStreams) {
+			status, message = http.StatusServiceUnavailable, "Muitas exportações em andamento"
+			c.Header("Retry-After", "30")
+		}
+		h.respondWithError(c, status, message, err)
+		return
+	case err != nil:
+		h.logger.WithFields(map[string]interface{}{
+			"sent":   checkpoint.Count,
+			"cursor": checkpoint.Cursor,
+		}).Error("Property stream interrupted", err)
+		checkpoint.Error = "Exportação interrompida; retome pelo cursor"
+		_ = writeCheckpoint()
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+		c.Header("Cache-Control", "no-store")
+	}
+	checkpoint.Done = limit == 0 || checkpoint.Count < limit
+	_ = writeCheckpoint()
+
+	h.logger.WithFields(map[string]interface{}{
+		"sent": checkpoint.Count,
+		"done": checkpoint.Done,
+	}).Info("Property stream completed")
 }
 
 // TriggerCrawlerRequest representa os parâmetros de trigger do crawler
 type TriggerCrawlerRequest struct {
 	Cities []string `json:"cities,omitempty"` // Lista de cidades (opcional)
 	Mode   string   `json:"mode,omitempty"`   // full, incremental
+	Tags   []string `json:"tags,omitempty"`   // tags registradas nos imóveis salvos (ex.: muzambinho-weekly)
 }
 
 // TriggerCrawler força a execução do crawler para coletar dados
@@ -258,7 +535,7 @@ func (h *PropertyHandler) TriggerCrawler(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// Se não conseguir fazer bind, assume valores padrão
 		req.Cities = []string{}
-		req.Mode = "incremental"
+		req.Mode = repository.CrawlModeFull
 		h.logger.WithField("bind_error", err.Error()).Debug("Using default crawler parameters")
 	}
 
@@ -268,32 +545,45 @@ func (h *PropertyHandler) TriggerCrawler(c *gin.Context) {
 		return
 	}
 
-	// Inicia o crawler de forma assíncrona para não bloquear a resposta
-	go func() {
-		crawlerLogger := h.logger.WithFields(map[string]interface{}{
-			"operation": "crawler_execution",
-			"cities":    req.Cities,
-			"mode":      req.Mode,
-		})
-		crawlerLogger.Info("Starting crawler execution")
+	// Tags também podem vir na query (?tag=muzambinho-weekly&tag=...)
+	tags, err := repository.NormalizeCrawlTags(append(req.Tags, c.QueryArray("tag")...))
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Tags inválidas", err)
+		return
+	}
 
-		// Cria um contexto independente para o crawler que não será cancelado
-		// quando a requisição HTTP terminar
-		ctx := context.Background()
+	key, ok := h.idempotencyKey(c)
+	if !ok {
+		return
+	}
 
-		if err := h.Service.ForceCrawling(ctx, req.Cities); err != nil {
-			crawlerLogger.Error("Error during crawler execution", err)
-		} else {
-			crawlerLogger.Info("Crawler execution completed successfully")
-		}
-	}()
+	// Inicia o crawler como job em segundo plano para não bloquear a resposta; um disparo repetido
+	// com a mesma Idempotency-Key retorna o job original
+	fingerprint := idempotencyFingerprint("trigger", req.Cities, req.Mode, tags)
+	job, replayed, err := h.Service.StartJobOnce(key, fingerprint, func() (*repository.CrawlJob, error) {
+		return h.Service.StartCrawlJob(req.Cities, req.Mode, tags), nil
+	})
+	if err != nil {
+		h.respondWithIdempotencyError(c, err)
+		return
+	}
 
 	// Prepara resposta com informações sobre o que será processado
 	responseData := map[string]interface{}{
 		"status": "started",
+		"job_id": job.ID,
 		"mode":   req.Mode,
 		"note":   "O processo está sendo executado em segundo plano",
 	}
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+		responseData["status"] = job.Status
+		responseData["note"] = "Job já iniciado com esta Idempotency-Key"
+	}
+
+	if len(tags) > 0 {
+		responseData["tags"] = tags
+	}
 
 	if len(req.Cities) > 0 {
 		responseData["cities"] = req.Cities
@@ -308,6 +598,7 @@ func (h *PropertyHandler) TriggerCrawler(c *gin.Context) {
 	}
 
 	h.logger.WithFields(map[string]interface{}{
+		"job_id": job.ID,
 		"cities": req.Cities,
 		"mode":   req.Mode,
 	}).Info("Crawler trigger response sent")
@@ -315,6 +606,1233 @@ func (h *PropertyHandler) TriggerCrawler(c *gin.Context) {
 	c.JSON(http.StatusAccepted, response)
 }
 
+// Cabeçalhos de idempotência dos disparos de crawling
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// idempotencyKey lê o cabeçalho Idempotency-Key (vazio quando ausente); responde 400 e retorna
+// false para chaves inválidas
+func (h *PropertyHandler) idempotencyKey(c *gin.Context) (string, bool) {
+	key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+	if len(key) > maxIdempotencyKeyLength {
+		h.respondWithError(c, http.StatusBadRequest, "Idempotency-Key inválida",
+			fmt.Errorf("idempotency key longer than %d characters", maxIdempotencyKeyLength))
+		return "", false
+	}
+	return key, true
+}
+
+// idempotencyFingerprint resume os parâmetros de um disparo; a mesma chave com outros parâmetros
+// é rejeitada
+func idempotencyFingerprint(kind string, params ...interface{}) string {
+	encoded, _ := json.Marshal(params)
+	sum := sha256.Sum256(append([]byte(kind+":"), encoded...))
+	return hex.EncodeToString(sum[:])
+}
+
+// respondWithIdempotencyError mapeia erros de disparos idempotentes para o status HTTP adequado
+func (h *PropertyHandler) respondWithIdempotencyError(c *gin.Context, err error) {
+	if errors.Is(err, crawler.ErrIdempotencyKeyReused) {
+		h.respondWithError(c, http.StatusUnprocessableEntity, "Idempotency-Key já usada com outros parâmetros", err)
+		return
+	}
+	if errors.Is(err, crawler.ErrIdempotencyKeyInProgress) {
+		h.respondWithError(c, http.StatusConflict, "Idempotency-Key em uso por uma requisição em andamento", err)
+		return
+	}
+	h.respondWithJobTemplateError(c, err)
+}
+
+// MaxImportBodySize limita o tamanho do arquivo enviado para importação
+const MaxImportBodySize = 50 << 20
+
+// ImportProperties importa imóveis externos enviados como NDJSON ou CSV no corpo da requisição.
+// O formato vem de ?format= ou do Content-Type (application/x-ndjson, text/csv).
+func (h *PropertyHandler) ImportProperties(c *gin.Context) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		switch contentType := c.ContentType(); {
+		case strings.Contains(contentType, "csv"):
+			format = service.ImportFormatCSV
+		case strings.Contains(contentType, "ndjson"), strings.Contains(contentType, "jsonl"), strings.Contains(contentType, "json"):
+			format = service.ImportFormatNDJSON
+		}
+	}
+	if format != service.ImportFormatCSV && format != service.ImportFormatNDJSON {
+		h.respondWithError(c, http.StatusBadRequest, "Formato de importação inválido", fmt.Errorf("use format=ndjson ou format=csv"))
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportBodySize)
+	report, err := h.Service.ImportProperties(c.Request.Context(), body, format)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Erro ao importar propriedades", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Importados %d de %d registros", report.Imported, report.Total),
+		Data:    report,
+	})
+}
+
+// GetPropertyFacets retorna as contagens usadas para montar os filtros de busca
+func (h *PropertyHandler) GetPropertyFacets(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		city = c.Query("cidade")
+	}
+	city = sanitizeString(city, 50)
+
+	facets, err := h.Service.GetPropertyFacets(c.Request.Context(), city)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao calcular facetas", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Facetas calculadas para %d propriedades", facets.Total),
+		Data:    facets,
+	})
+}
+
+// GetPriceIndex retorna a série mensal da mediana do R$/m² da cidade ou do bairro
+func (h *PropertyHandler) GetPriceIndex(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		city = c.Query("cidade")
+	}
+	city = sanitizeString(city, 50)
+	if city == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Cidade obrigatória", fmt.Errorf("informe o parâmetro city"))
+		return
+	}
+	bairro := sanitizeString(c.Query("bairro"), 50)
+
+	points, err := h.Service.GetPriceIndex(c.Request.Context(), city, bairro)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao consultar índice de preços", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Índice de preços com %d meses", len(points)),
+		Data:    points,
+	})
+}
+
+// GetQualityMetrics retorna as métricas de qualidade dos dados por domínio (percentual com preço,
+// endereço, quartos e fotos e nota média de extração) e a série diária dos últimos dias
+func (h *PropertyHandler) GetQualityMetrics(c *gin.Context) {
+	domain := sanitizeString(c.Query("domain"), 100)
+	days := 30
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			h.respondWithError(c, http.StatusBadRequest, "days deve estar entre 1 e 365", err)
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.Service.GetQualityReport(c.Request.Context(), domain, days)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao consultar métricas de qualidade", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Métricas de qualidade de %d domínios", len(report.Domains)),
+		Data:    report,
+	})
+}
+
+// GetDuplicateReport lista anúncios quase idênticos de imobiliárias diferentes na cidade
+func (h *PropertyHandler) GetDuplicateReport(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		city = c.Query("cidade")
+	}
+	city = sanitizeString(city, 50)
+	if city == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Cidade obrigatória", fmt.Errorf("informe o parâmetro city"))
+		return
+	}
+
+	opts := repository.DuplicateReportOptions{}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit <= 500 {
+		opts.Limit = limit
+	}
+	if tolerance, err := strconv.ParseFloat(c.Query("area_tolerance"), 64); err == nil && tolerance > 0 && tolerance <= 0.5 {
+		opts.AreaTolerance = tolerance
+	}
+	opts.IncludeAuctions = c.Query("include_auctions") == "true"
+	opts.IncludeVacationRentals = c.Query("include_vacation_rentals") == "true"
+
+	clusters, err := h.Service.GetDuplicateReport(c.Request.Context(), city, opts)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao gerar relatório de duplicatas", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d grupos de anúncios duplicados entre imobiliárias", len(clusters)),
+		Data:    clusters,
+	})
+}
+
+// MergeDuplicates funde os anúncios do mesmo imóvel publicados em portais diferentes da cidade
+// em registros canônicos, com os pesos de confiabilidade das métricas de qualidade
+func (h *PropertyHandler) MergeDuplicates(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		city = c.Query("cidade")
+	}
+	city = sanitizeString(city, 50)
+	if city == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Cidade obrigatória", fmt.Errorf("informe o parâmetro city"))
+		return
+	}
+
+	opts := repository.DuplicateReportOptions{}
+	if tolerance, err := strconv.ParseFloat(c.Query("area_tolerance"), 64); err == nil && tolerance > 0 && tolerance <= 0.5 {
+		opts.AreaTolerance = tolerance
+	}
+	opts.IncludeAuctions = c.Query("include_auctions") == "true"
+	opts.IncludeVacationRentals = c.Query("include_vacation_rentals") == "true"
+
+	result, err := h.Service.MergeDuplicates(c.Request.Context(), city, opts)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao fundir anúncios duplicados", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d registros canônicos a partir de %d anúncios", result.Merged, result.Sources),
+		Data:    result,
+	})
+}
+
+// GetCanonicalProperties lista os registros canônicos (?city= opcional), cada um com os anúncios
+// de origem e a fonte de cada campo
+func (h *PropertyHandler) GetCanonicalProperties(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		city = c.Query("cidade")
+	}
+	city = sanitizeString(city, 50)
+	limit := 100
+	if value, err := strconv.Atoi(c.Query("limit")); err == nil && value > 0 && value <= 500 {
+		limit = value
+	}
+
+	canonicals, err := h.Service.GetCanonicalProperties(c.Request.Context(), city, limit)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar registros canônicos", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d registros canônicos", len(canonicals)),
+		Data:    canonicals,
+	})
+}
+
+// GetCanonicalProperty retorna o registro canônico com os anúncios originais de cada portal
+func (h *PropertyHandler) GetCanonicalProperty(c *gin.Context) {
+	canonical, err := h.Service.GetCanonicalProperty(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrCanonicalPropertyNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Registro canônico não encontrado", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar registro canônico", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Registro canônico com %d fontes", len(canonical.Sources)),
+		Data:    canonical,
+	})
+}
+
+// GetBairroBoundaries retorna, em GeoJSON, os polígonos aproximados dos bairros da cidade
+// inferidos dos anúncios geocodificados (para sobreposição em mapas)
+func (h *PropertyHandler) GetBairroBoundaries(c *gin.Context) {
+	city := sanitizeString(c.Param("city"), 50)
+	if city == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Cidade obrigatória", fmt.Errorf("informe a cidade"))
+		return
+	}
+
+	minListings := repository.DefaultBairroMinListings
+	if value := c.Query("min_listings"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 3 || parsed > 1000 {
+			h.respondWithError(c, http.StatusBadRequest, "Parâmetro min_listings inválido", fmt.Errorf("min_listings deve estar entre 3 e 1000"))
+			return
+		}
+		minListings = parsed
+	}
+
+	boundaries, err := h.Service.GetBairroBoundaries(c.Request.Context(), city, minListings)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao inferir os bairros da cidade", err)
+		return
+	}
+
+	// GeoJSON puro (sem SuccessResponse), consumido diretamente por bibliotecas de mapas
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, boundaries)
+}
+
+// GetWhatsAppLeads lista os leads lidos dos links de WhatsApp dos catálogos (?domain=, ?cidade=, ?limit=)
+func (h *PropertyHandler) GetWhatsAppLeads(c *gin.Context) {
+	filter := repository.WhatsAppLeadFilter{
+		Domain: repository.NormalizeDomain(c.Query("domain")),
+		Cidade: sanitizeString(c.Query("cidade"), 50),
+		Limit:  100,
+	}
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			h.respondWithError(c, http.StatusBadRequest, "Parâmetro limit inválido", fmt.Errorf("limit deve estar entre 1 e 1000"))
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	leads, err := h.Service.GetWhatsAppLeads(c.Request.Context(), filter)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao listar os leads de WhatsApp", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Encontrados %d leads de WhatsApp", len(leads)),
+		Data:    leads,
+	})
+}
+
+// DetectPhotoFraud compara as fotos dos anúncios e marca os que reutilizam fotos de outro imóvel
+func (h *PropertyHandler) DetectPhotoFraud(c *gin.Context) {
+	scan, err := h.Service.DetectPhotoFraud(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao verificar fotos repetidas", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d grupos de anúncios com fotos repetidas", scan.Groups),
+		Data:    scan,
+	})
+}
+
+// GetFraudReviews lista a fila de revisão de fotos repetidas (?status=pending|confirmed|dismissed)
+func (h *PropertyHandler) GetFraudReviews(c *gin.Context) {
+	status := sanitizeString(c.Query("status"), 20)
+	limit := 100
+	if value, err := strconv.Atoi(c.Query("limit")); err == nil && value > 0 && value <= 500 {
+		limit = value
+	}
+
+	reviews, err := h.Service.GetFraudReviews(c.Request.Context(), status, limit)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar fila de revisão", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d itens na fila de revisão", len(reviews)),
+		Data:    reviews,
+	})
+}
+
+// FraudReviewDecision é a decisão do revisor sobre um grupo de anúncios suspeitos
+type FraudReviewDecision struct {
+	Status string `json:"status" binding:"required"` // confirmed ou dismissed
+}
+
+// ResolveFraudReview confirma a fraude ou descarta o item da fila (removendo a marcação)
+func (h *PropertyHandler) ResolveFraudReview(c *gin.Context) {
+	var req FraudReviewDecision
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Dados de requisição inválidos", err)
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	if status != repository.FraudReviewConfirmed && status != repository.FraudReviewDismissed {
+		h.respondWithError(c, http.StatusBadRequest, "Status inválido (use confirmed ou dismissed)", nil)
+		return
+	}
+
+	review, err := h.Service.ResolveFraudReview(c.Request.Context(), c.Param("id"), status)
+	if errors.Is(err, repository.ErrFraudReviewNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Item da fila de revisão não encontrado", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao registrar revisão", err)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"review_id": review.ID,
+		"status":    status,
+		"client_ip": c.ClientIP(),
+	}).Info("Fraud review resolved")
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Revisão registrada",
+		Data:    review,
+	})
+}
+
+// PropertyPatchRequest corrige campos do imóvel; campos ausentes não são alterados
+type PropertyPatchRequest struct {
+	repository.PropertyPatch
+	Reason      string `json:"reason"`
+	CorrectedBy string `json:"corrected_by"`
+}
+
+// PatchProperty corrige manualmente campos do imóvel (ex: cidade errada). Os campos corrigidos
+// passam a prevalecer sobre os valores extraídos nos próximos crawlings.
+func (h *PropertyHandler) PatchProperty(c *gin.Context) {
+	var req PropertyPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Dados de requisição inválidos", err)
+		return
+	}
+
+	property, correction, err := h.Service.CorrectProperty(c.Request.Context(), c.Param("id"), req.PropertyPatch,
+		strings.TrimSpace(req.CorrectedBy), strings.TrimSpace(req.Reason))
+	switch {
+	case errors.Is(err, repository.ErrPropertyNotFound):
+		h.respondWithError(c, http.StatusNotFound, "Imóvel não encontrado", err)
+		return
+	case errors.Is(err, repository.ErrInvalidPropertyPatch):
+		h.respondWithError(c, http.StatusBadRequest, "Correção inválida", err)
+		return
+	case err != nil:
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao corrigir imóvel", err)
+		return
+	}
+
+	message := "Nenhum campo alterado"
+	if correction != nil {
+		message = fmt.Sprintf("%d campos corrigidos", len(correction.Changes))
+		h.logger.WithFields(map[string]interface{}{
+			"property_id": property.ID,
+			"client_ip":   c.ClientIP(),
+		}).Info("Property correction recorded")
+	}
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data: gin.H{
+			"property":   property,
+			"correction": correction,
+		},
+	})
+}
+
+// GetPropertyCorrections retorna o histórico de correções manuais do imóvel
+func (h *PropertyHandler) GetPropertyCorrections(c *gin.Context) {
+	corrections, err := h.Service.GetPropertyCorrections(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrPropertyNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Imóvel não encontrado", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar histórico de correções", err)
+		return
+	}
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d correções", len(corrections)),
+		Data:    corrections,
+	})
+}
+
+// GetPropertyPhoto entrega a foto n (a partir de 0) do imóvel pelo proxy de fotos, para os
+// consumidores da API não fazerem hotlink no site de origem
+func (h *PropertyHandler) GetPropertyPhoto(c *gin.Context) {
+	n, err := strconv.Atoi(c.Param("n"))
+	if err != nil || n < 0 {
+		h.respondWithError(c, http.StatusBadRequest, "Índice de foto inválido", err)
+		return
+	}
+
+	photo, err := h.Service.PropertyPhoto(c.Request.Context(), c.Param("id"), n)
+	switch {
+	case errors.Is(err, repository.ErrPropertyNotFound):
+		h.respondWithError(c, http.StatusNotFound, "Foto não encontrada", err)
+		return
+	case errors.Is(err, crawler.ErrPhotoDenied), errors.Is(err, crawler.ErrPhotoRobots):
+		h.respondWithError(c, http.StatusForbidden, "Foto bloqueada pela política do site de origem", err)
+		return
+	case errors.Is(err, crawler.ErrPrivateAddress):
+		h.respondWithError(c, http.StatusForbidden, "Foto em endereço interno recusada", err)
+		return
+	case errors.Is(err, crawler.ErrPhotoBroken):
+		h.respondWithError(c, http.StatusNotFound, "Foto indisponível no site de origem", err)
+		return
+	case err != nil:
+		h.respondWithError(c, http.StatusBadGateway, "Erro ao obter a foto do site de origem", err)
+		return
+	}
+
+	if photo.Redirect {
+		c.Redirect(http.StatusFound, photo.URL)
+		return
+	}
+	cacheStatus := "MISS"
+	if photo.Cached {
+		cacheStatus = "HIT"
+	}
+	c.Header("X-Photo-Cache", cacheStatus)
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, photo.ContentType, photo.Data)
+}
+
+// GetPropertyByShortID redireciona o ID curto para o anúncio no site de origem; com
+// ?format=json (ou Accept: application/json) retorna o imóvel. URLs fora dos domínios crawleados
+// (ex: imóveis importados) não são redirecionadas: o imóvel é retornado em JSON
+func (h *PropertyHandler) GetPropertyByShortID(c *gin.Context) {
+	shortID := strings.ToLower(c.Param("shortid"))
+	if !repository.ValidShortID(shortID) {
+		h.respondWithError(c, http.StatusNotFound, "Imóvel não encontrado", fmt.Errorf("invalid short id %q", shortID))
+		return
+	}
+
+	property, err := h.Service.FindPropertyByShortID(c.Request.Context(), shortID)
+	switch {
+	case errors.Is(err, repository.ErrPropertyNotFound):
+		h.respondWithError(c, http.StatusNotFound, "Imóvel não encontrado", err)
+		return
+	case err != nil:
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao buscar o imóvel", err)
+		return
+	}
+
+	target := redirectURL(property.URL)
+	if c.Query("format") == "json" || c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON || !h.Service.KnownCrawlURL(c.Request.Context(), target) {
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: "Imóvel encontrado",
+			Data:    property,
+		})
+		return
+	}
+	c.Redirect(http.StatusFound, target)
+}
+
+// redirectURL devolve as duas barras depois do esquema, removidas pela normalização das URLs
+// salvas ("https:/site.com.br/imovel/1")
+func redirectURL(stored string) string {
+	if strings.Contains(stored, "://") {
+		return stored
+	}
+	for _, scheme := range []string{"https:/", "http:/"} {
+		if strings.HasPrefix(stored, scheme) {
+			return scheme + "/" + strings.TrimPrefix(stored, scheme)
+		}
+	}
+	return stored
+}
+
+// GetBrokenPhotos lista as fotos que o site de origem não entregou ao proxy de fotos
+func (h *PropertyHandler) GetBrokenPhotos(c *gin.Context) {
+	photos := h.Service.BrokenPhotos()
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d fotos quebradas na origem", len(photos)),
+		Data:    photos,
+	})
+}
+
+// UnitConversion é o resultado de uma conversão de unidades, com o valor formatado para exibição
+type UnitConversion struct {
+	Input     string  `json:"input"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Value     float64 `json:"value"`
+	Result    float64 `json:"result"`
+	Formatted string  `json:"formatted"`
+}
+
+// ConvertUnits converte áreas (m2, ha, alqueire...) e interpreta valores em reais ("R$ 350 mil")
+// com as mesmas regras usadas pelos extratores
+func (h *PropertyHandler) ConvertUnits(c *gin.Context) {
+	input := sanitizeString(c.Query("value"), 100)
+	if input == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Valor obrigatório", fmt.Errorf("informe o parâmetro value"))
+		return
+	}
+	conversion := UnitConversion{
+		Input: input,
+		From:  strings.ToLower(c.Query("from")),
+		To:    strings.ToLower(c.DefaultQuery("to", utils.AreaSquareMeters)),
+	}
+
+	switch conversion.From {
+	case "brl":
+		conversion.To = "brl"
+		conversion.Value = utils.ParseAmount(input)
+		conversion.Result = conversion.Value
+		conversion.Formatted = utils.FormatCurrency(conversion.Result)
+	case "":
+		// Sem unidade de origem, a unidade vem do próprio texto ("2 alqueires", "5 ha")
+		conversion.From = utils.AreaSquareMeters
+		conversion.Value = utils.ParseArea(input)
+		fallthrough
+	default:
+		if numbers := utils.ParseNumbers(input); conversion.Value == 0 && len(numbers) > 0 {
+			conversion.Value = numbers[0]
+		}
+		result, err := utils.ConvertArea(conversion.Value, conversion.From, conversion.To)
+		if err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Unidade inválida", err)
+			return
+		}
+		conversion.Result = result
+		conversion.Formatted = utils.FormatAreaIn(result, conversion.To)
+	}
+
+	if conversion.Value == 0 {
+		h.respondWithError(c, http.StatusBadRequest, "Valor inválido", fmt.Errorf("valor não reconhecido: %s", input))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conversão realizada",
+		Data:    conversion,
+	})
+}
+
+// GetDomainRates retorna o ritmo efetivo de requisições por domínio
+func (h *PropertyHandler) GetDomainRates(c *gin.Context) {
+	rates := h.Service.GetDomainRates()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Ritmo de requisições para %d domínios", len(rates)),
+		Data:    rates,
+	})
+}
+
+// GetGlobalRate retorna o orçamento global de páginas por minuto e o paralelismo ajustado
+func (h *PropertyHandler) GetGlobalRate(c *gin.Context) {
+	stats := h.Service.GetGlobalRate()
+	if stats == nil {
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: "Orçamento global de páginas por minuto desativado (CRAWL_PAGES_PER_MINUTE)",
+			Data:    gin.H{"enabled": false},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Meta de %d páginas por minuto, paralelismo por domínio %d", stats.TargetPagesPerMinute, stats.DomainParallelism),
+		Data:    stats,
+	})
+}
+
+// GetFeedStatus retorna os feeds RSS/Atom acompanhados e as entradas aguardando crawling
+func (h *PropertyHandler) GetFeedStatus(c *gin.Context) {
+	status := h.Service.GetFeedStatus()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d feeds acompanhados, %d anúncios pendentes", len(status.Feeds), status.Pending),
+		Data:    status,
+	})
+}
+
+// GetCrawlErrors retorna os erros de crawling por tipo/domínio com amostras recentes
+func (h *PropertyHandler) GetCrawlErrors(c *gin.Context) {
+	errorType := strings.TrimSpace(c.Query("type"))
+	if errorType != "" && !crawler.IsValidErrorType(errorType) {
+		h.respondWithError(c, http.StatusBadRequest,
+			fmt.Sprintf("Tipo de erro inválido (use: %s)", strings.Join(crawler.ErrorTypes, ", ")), nil)
+		return
+	}
+	domain := sanitizeString(c.Query("domain"), 100)
+
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	report := h.Service.GetCrawlErrors(errorType, domain, limit)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d erros registrados", report.Total),
+		Data:    report,
+	})
+}
+
+// requestAuditFilter lê os filtros do registro de auditoria (job_id, domain, status, since,
+// until e limit, até maxLimit)
+func requestAuditFilter(c *gin.Context, defaultLimit, maxLimit int) (repository.RequestAuditFilter, error) {
+	filter := repository.RequestAuditFilter{
+		JobID:  sanitizeString(c.Query("job_id"), 100),
+		Domain: sanitizeString(c.Query("domain"), 100),
+		Limit:  defaultLimit,
+	}
+	if value := c.Query("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 100 || status > 599 {
+			return filter, fmt.Errorf("status deve ser um código HTTP")
+		}
+		filter.StatusCode = status
+	}
+	if value := c.Query("since"); value != "" {
+		since, err := parseAsOf(value)
+		if err != nil {
+			return filter, fmt.Errorf("since: %v", err)
+		}
+		// Uma data sem horário começa no início do dia
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(value)); err == nil {
+			since = since.Add(time.Second - 24*time.Hour)
+		}
+		filter.Since = since
+	}
+	if value := c.Query("until"); value != "" {
+		until, err := parseAsOf(value)
+		if err != nil {
+			return filter, fmt.Errorf("until: %v", err)
+		}
+		filter.Until = until
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return filter, fmt.Errorf("limit deve estar entre 1 e %d", maxLimit)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// GetRequestAudits lista as requisições HTTP feitas pelo crawler, da mais recente à mais antiga
+func (h *PropertyHandler) GetRequestAudits(c *gin.Context) {
+	if !h.Service.RequestAuditEnabled() {
+		h.respondWithError(c, http.StatusNotFound, "Registro de auditoria desativado (REQUEST_AUDIT)", nil)
+		return
+	}
+	filter, err := requestAuditFilter(c, repository.DefaultRequestAuditLimit, 1000)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Filtros inválidos", err)
+		return
+	}
+
+	entries, err := h.Service.FindRequestAudits(c.Request.Context(), filter)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao consultar o registro de auditoria", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d requisições encontradas", len(entries)),
+		Data:    entries,
+	})
+}
+
+// GetRequestAuditSummary resume por domínio as requisições feitas pelo crawler: volume, erros,
+// bytes, duração média e o menor intervalo entre requisições
+func (h *PropertyHandler) GetRequestAuditSummary(c *gin.Context) {
+	if !h.Service.RequestAuditEnabled() {
+		h.respondWithError(c, http.StatusNotFound, "Registro de auditoria desativado (REQUEST_AUDIT)", nil)
+		return
+	}
+	filter, err := requestAuditFilter(c, 10000, 50000)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Filtros inválidos", err)
+		return
+	}
+
+	summaries, err := h.Service.SummarizeRequestAudits(c.Request.Context(), filter)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao resumir o registro de auditoria", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Requisições resumidas para %d domínios", len(summaries)),
+		Data:    summaries,
+	})
+}
+
+// GetAITokenSavings retorna os tokens enviados à IA e os economizados pela sanitização das páginas
+func (h *PropertyHandler) GetAITokenSavings(c *gin.Context) {
+	stats := h.Service.GetAITokenSavings()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d tokens economizados em %d chamadas", stats.Total.SavedTokens, stats.Total.Calls),
+		Data:    stats,
+	})
+}
+
+// GetCrawlJobCosts lista o custo estimado dos jobs terminados e o resumo por modo (com e sem IA),
+// para comparar o custo por imóvel salvo de cada modo
+func (h *PropertyHandler) GetCrawlJobCosts(c *gin.Context) {
+	filter := repository.CrawlJobCostFilter{Mode: sanitizeString(c.Query("mode"), 30), Limit: 100}
+	if value := c.Query("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > 365 {
+			h.respondWithError(c, http.StatusBadRequest, "Parâmetro days inválido", fmt.Errorf("days deve estar entre 1 e 365"))
+			return
+		}
+		filter.Since = time.Now().AddDate(0, 0, -days)
+	}
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			h.respondWithError(c, http.StatusBadRequest, "Parâmetro limit inválido", fmt.Errorf("limit deve estar entre 1 e 1000"))
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	costs, summaries, err := h.Service.GetCrawlJobCosts(c.Request.Context(), filter)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao consultar o custo dos jobs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Custo de %d jobs em %d modos", len(costs), len(summaries)),
+		Data: gin.H{
+			"jobs":    costs,
+			"by_mode": summaries,
+		},
+	})
+}
+
+// GetClassifierExperiments lista os experimentos A/B dos limiares do classificador por job: decisões
+// divergentes entre a configuração atual e a candidata e o resultado de cada grupo
+func (h *PropertyHandler) GetClassifierExperiments(c *gin.Context) {
+	reports := h.Service.GetClassifierExperiments(sanitizeString(c.Query("job_id"), 100))
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d experimentos do classificador", len(reports)),
+		Data:    reports,
+	})
+}
+
+// GetDNSCache retorna o uso do cache de DNS dos crawlers e os hosts isolados por falhas de resolução
+func (h *PropertyHandler) GetDNSCache(c *gin.Context) {
+	stats, err := h.Service.GetDNSCacheStats()
+	if err != nil {
+		h.respondWithError(c, http.StatusNotFound, "Cache de DNS desativado (DNS_CACHE_TTL)", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d hosts em cache, %d com falhas de DNS", stats.Entries, len(stats.Failing)),
+		Data:    stats,
+	})
+}
+
+// ExplainClassification baixa a URL e explica a decisão de classificação: padrões que casaram,
+// indicadores das heurísticas, veredito e justificativa da IA e a decisão final do engine
+func (h *PropertyHandler) ExplainClassification(c *gin.Context) {
+	rawURL := strings.TrimSpace(c.Query("url"))
+	parsed, err := url.Parse(rawURL)
+	if rawURL == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		h.respondWithError(c, http.StatusBadRequest, "URL inválida", fmt.Errorf("informe uma URL http(s) no parâmetro url"))
+		return
+	}
+
+	explanation, err := h.Service.ExplainClassification(c.Request.Context(), rawURL, c.Query("engine"))
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown engine") {
+			h.respondWithError(c, http.StatusBadRequest, "Engine inválido", err)
+			return
+		}
+		if errors.Is(err, crawler.ErrPrivateAddress) {
+			h.respondWithError(c, http.StatusBadRequest, "URL não permitida", err)
+			return
+		}
+		h.respondWithError(c, http.StatusBadGateway, "Erro ao analisar a página", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Decisão do engine %s: %s", explanation.Decision.Engine, explanation.Decision.Action),
+		Data:    explanation,
+	})
+}
+
+// ProbeDomain testa DNS, TCP (IPv4 e IPv6), TLS e um HEAD para o domínio a partir do ambiente do
+// crawler, para distinguir bloqueios de problemas de rede
+func (h *PropertyHandler) ProbeDomain(c *gin.Context) {
+	domain := strings.TrimSpace(c.Query("domain"))
+	if domain == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio inválido", fmt.Errorf("informe o domínio no parâmetro domain"))
+		return
+	}
+
+	probe := h.Service.ProbeDomain(c.Request.Context(), domain)
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Diagnóstico de %s: %s", probe.Domain, probe.Verdict),
+		Data:    probe,
+	})
+}
+
+// GetURLStorageStats retorna documentos, tamanhos e retenção das coleções de URLs e fingerprints
+func (h *PropertyHandler) GetURLStorageStats(c *gin.Context) {
+	stats, err := h.Service.GetURLStorageStats(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao obter estatísticas de armazenamento", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d URLs processadas e %d fingerprints armazenados",
+			stats.ProcessedURLs.Documents, stats.Fingerprints.Documents),
+		Data: stats,
+	})
+}
+
+// PruneURLStorage remove os registros mais antigos que excedem os limites configurados
+func (h *PropertyHandler) PruneURLStorage(c *gin.Context) {
+	result, err := h.Service.PruneURLStorage(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao podar coleções de URLs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d URLs e %d fingerprints removidos", result.URLsRemoved, result.FingerprintsRemoved),
+		Data:    result,
+	})
+}
+
+// GetCoverage retorna a estimativa de cobertura por domínio do último crawling
+func (h *PropertyHandler) GetCoverage(c *gin.Context) {
+	coverage := h.Service.GetLatestCoverage()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Cobertura estimada para %d domínios", len(coverage)),
+		Data:    coverage,
+	})
+}
+
+// GetDiscoverySources retorna o rendimento (páginas visitadas e anúncios) de cada origem de
+// descoberta nos últimos crawlings do domínio
+func (h *PropertyHandler) GetDiscoverySources(c *gin.Context) {
+	domain := repository.NormalizeDomain(c.Query("domain"))
+	if domain == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Domínio obrigatório", fmt.Errorf("informe o parâmetro domain"))
+		return
+	}
+	crawls := 10
+	if value, err := strconv.Atoi(c.Query("crawls")); err == nil && value > 0 && value <= 100 {
+		crawls = value
+	}
+
+	yields, err := h.Service.GetDiscoverySourceYield(c.Request.Context(), domain, crawls)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao calcular rendimento por origem de descoberta", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Rendimento de %d origens de descoberta em %s", len(yields), domain),
+		Data:    yields,
+	})
+}
+
+// GetCrawlJobs lista os jobs de crawling
+func (h *PropertyHandler) GetCrawlJobs(c *gin.Context) {
+	jobs := h.Service.GetCrawlJobs()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Encontrados %d jobs", len(jobs)),
+		Data:    jobs,
+	})
+}
+
+// GetCrawlJob retorna o status de um job de crawling
+func (h *PropertyHandler) GetCrawlJob(c *gin.Context) {
+	job, err := h.Service.GetCrawlJob(c.Param("id"))
+	if err != nil {
+		h.respondWithError(c, http.StatusNotFound, "Job não encontrado", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Job encontrado",
+		Data:    job,
+	})
+}
+
+// CancelCrawlJob cancela um job: novas visitas param e as requisições em andamento terminam
+func (h *PropertyHandler) CancelCrawlJob(c *gin.Context) {
+	jobID := c.Param("id")
+
+	job, err := h.Service.CancelCrawlJob(jobID)
+	if err != nil {
+		h.respondWithCrawlJobError(c, err)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"job_id":    jobID,
+		"client_ip": c.ClientIP(),
+	}).Info("Crawl job cancellation requested")
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: "Cancelamento solicitado",
+		Data:    job,
+	})
+}
+
+// PauseCrawlJobDomain pausa novas visitas a um domínio (?domain=) dentro do job
+func (h *PropertyHandler) PauseCrawlJobDomain(c *gin.Context) {
+	h.changeCrawlJobDomain(c, false)
+}
+
+// ResumeCrawlJobDomain retoma as visitas a um domínio (?domain=) pausado
+func (h *PropertyHandler) ResumeCrawlJobDomain(c *gin.Context) {
+	h.changeCrawlJobDomain(c, true)
+}
+
+// changeCrawlJobDomain aplica pausa ou retomada de domínio em um job
+func (h *PropertyHandler) changeCrawlJobDomain(c *gin.Context, resume bool) {
+	jobID := c.Param("id")
+	domain := strings.TrimSpace(c.Query("domain"))
+	if domain == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Parâmetro domain é obrigatório", nil)
+		return
+	}
+
+	var job *repository.CrawlJob
+	var err error
+	if resume {
+		job, err = h.Service.ResumeCrawlJobDomain(jobID, domain)
+	} else {
+		job, err = h.Service.PauseCrawlJobDomain(jobID, domain)
+	}
+	if err != nil {
+		h.respondWithCrawlJobError(c, err)
+		return
+	}
+
+	message := "Domínio pausado"
+	if resume {
+		message = "Domínio retomado"
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"job_id": jobID,
+		"domain": domain,
+		"resume": resume,
+	}).Info("Crawl job domain state changed")
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data:    job,
+	})
+}
+
+// respondWithCrawlJobError mapeia erros de controle de job para o status HTTP adequado
+func (h *PropertyHandler) respondWithCrawlJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, crawler.ErrCrawlJobNotFound):
+		h.respondWithError(c, http.StatusNotFound, "Job não encontrado", err)
+	case errors.Is(err, crawler.ErrCrawlJobFinished):
+		h.respondWithError(c, http.StatusConflict, "Job já finalizado", err)
+	default:
+		h.respondWithError(c, http.StatusBadRequest, "Requisição inválida", err)
+	}
+}
+
+// GetConfigChanges retorna o log de auditoria dos recarregamentos de configuração desta réplica
+func (h *PropertyHandler) GetConfigChanges(c *gin.Context) {
+	limit := 100
+	if value, err := strconv.Atoi(c.Query("limit")); err == nil && value > 0 && value <= 500 {
+		limit = value
+	}
+
+	changes := h.Service.GetConfigChanges(limit)
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("%d alterações de configuração", len(changes)),
+		Data:    changes,
+	})
+}
+
+// GetLeaderStatus informa se esta réplica é a líder que executa as tarefas agendadas
+func (h *PropertyHandler) GetLeaderStatus(c *gin.Context) {
+	status, err := h.Service.GetLeaderStatus(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao consultar a eleição de líder", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Estado da eleição de líder",
+		Data:    status,
+	})
+}
+
+// JobTemplateRequest representa o corpo de criação/atualização de um template de job
+type JobTemplateRequest struct {
+	Description        string   `json:"description,omitempty"`
+	Mode               string   `json:"mode,omitempty"` // full, incremental
+	Cities             []string `json:"cities,omitempty"`
+	Domains            []string `json:"domains,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	EnableAI           *bool    `json:"enable_ai,omitempty"`
+	MaxPages           int      `json:"max_pages"`
+	MaxDurationMinutes int      `json:"max_duration_minutes"`
+}
+
+// RunJobTemplateRequest substitui parâmetros do template numa execução (campos omitidos mantêm
+// os do template; as tags são somadas às do template)
+type RunJobTemplateRequest struct {
+	Mode               string   `json:"mode,omitempty"`
+	Cities             []string `json:"cities,omitempty"`
+	Domains            []string `json:"domains,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	EnableAI           *bool    `json:"enable_ai,omitempty"`
+	MaxPages           *int     `json:"max_pages,omitempty"`
+	MaxDurationMinutes *int     `json:"max_duration_minutes,omitempty"`
+}
+
+// GetJobTemplates lista os templates de job (cadastrados e padrão)
+func (h *PropertyHandler) GetJobTemplates(c *gin.Context) {
+	templates, err := h.Service.GetJobTemplates(c.Request.Context())
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao listar templates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Encontrados %d templates", len(templates)),
+		Data:    templates,
+	})
+}
+
+// GetJobTemplate retorna um template de job
+func (h *PropertyHandler) GetJobTemplate(c *gin.Context) {
+	template, err := h.Service.GetJobTemplate(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.respondWithJobTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Template encontrado",
+		Data:    template,
+	})
+}
+
+// SaveJobTemplate cria ou substitui um template de job (inclusive os padrão)
+func (h *PropertyHandler) SaveJobTemplate(c *gin.Context) {
+	var req JobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Corpo da requisição inválido", err)
+		return
+	}
+
+	template, err := h.Service.SaveJobTemplate(c.Request.Context(), repository.CrawlJobTemplate{
+		Name:        c.Param("name"),
+		Description: req.Description,
+		Mode:        req.Mode,
+		Cities:      req.Cities,
+		Domains:     req.Domains,
+		Tags:        req.Tags,
+		EnableAI:    req.EnableAI,
+		MaxPages:    req.MaxPages,
+		MaxMinutes:  req.MaxDurationMinutes,
+	})
+	if err != nil {
+		h.respondWithJobTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Template salvo com sucesso",
+		Data:    template,
+	})
+}
+
+// DeleteJobTemplate remove um template cadastrado
+func (h *PropertyHandler) DeleteJobTemplate(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.Service.DeleteJobTemplate(c.Request.Context(), name); err != nil {
+		h.respondWithJobTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: fmt.Sprintf("Template %s removido", name),
+	})
+}
+
+// RunJobTemplate dispara um job de crawling com os parâmetros do template
+func (h *PropertyHandler) RunJobTemplate(c *gin.Context) {
+	// O corpo é opcional: sem corpo, o template é executado como está
+	var req RunJobTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Corpo da requisição inválido", err)
+			return
+		}
+	}
+
+	key, ok := h.idempotencyKey(c)
+	if !ok {
+		return
+	}
+
+	name := c.Param("name")
+	overrides := service.TemplateRunOverrides{
+		Cities:     req.Cities,
+		Domains:    req.Domains,
+		Tags:       append(req.Tags, c.QueryArray("tag")...),
+		Mode:       req.Mode,
+		EnableAI:   req.EnableAI,
+		MaxPages:   req.MaxPages,
+		MaxMinutes: req.MaxDurationMinutes,
+	}
+	job, replayed, err := h.Service.StartJobOnce(key, idempotencyFingerprint("template:"+name, overrides), func() (*repository.CrawlJob, error) {
+		return h.Service.StartTemplateJob(c.Request.Context(), name, overrides)
+	})
+	if err != nil {
+		h.respondWithIdempotencyError(c, err)
+		return
+	}
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"job_id":    job.ID,
+		"template":  name,
+		"client_ip": c.ClientIP(),
+	}).Info("Crawl job started from template")
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: "Crawler iniciado com sucesso",
+		Data:    job,
+	})
+}
+
+// respondWithJobTemplateError mapeia erros de templates de job para o status HTTP adequado
+func (h *PropertyHandler) respondWithJobTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrJobTemplateNotFound):
+		h.respondWithError(c, http.StatusNotFound, "Template não encontrado", err)
+	case errors.Is(err, service.ErrInvalidJobTemplate):
+		h.respondWithError(c, http.StatusBadRequest, "Template inválido", err)
+	default:
+		h.respondWithError(c, http.StatusInternalServerError, "Erro ao processar template", err)
+	}
+}
+
 // CleanupDatabase limpa o banco de dados
 func (h *PropertyHandler) CleanupDatabase(c *gin.Context) {
 	h.logger.WithFields(map[string]interface{}{
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuthMiddleware protege as rotas administrativas (diagnóstico e correções manuais) com o
+// token de ADMIN_API_TOKEN, enviado em "Authorization: Bearer <token>" ou em X-Admin-Token. Sem
+// token configurado as rotas ficam fechadas
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "Admin routes disabled",
+				"message": "Configure ADMIN_API_TOKEN para usar esta rota",
+				"code":    http.StatusForbidden,
+			})
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Token")
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			provided = strings.TrimSpace(bearer)
+		}
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Token de administrador ausente ou inválido",
+				"code":    http.StatusUnauthorized,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,22 +1,133 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
+// CORSConfig define as origens, os métodos e os cabeçalhos aceitos nas requisições feitas pelo
+// navegador (dashboards em outro domínio)
+type CORSConfig struct {
+	AllowedOrigins   []string      // "*" aceita qualquer origem; "https://*.exemplo.com.br" aceita os subdomínios
+	AllowedMethods   []string      // métodos informados no preflight
+	AllowedHeaders   []string      // cabeçalhos informados no preflight
+	AllowCredentials bool          // envia cookies/Authorization; a origem é devolvida em vez de "*" (não vale com "*")
+	MaxAge           time.Duration // cache do preflight no navegador (0 não envia)
+}
+
+// DefaultCORSConfig aceita qualquer origem sem credenciais
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "Accept", "Origin", "Cache-Control", "X-Requested-With"},
+		MaxAge:         12 * time.Hour,
+	}
+}
+
+// ErrCORSWildcardCredentials indica credenciais liberadas para qualquer origem
+var ErrCORSWildcardCredentials = errors.New(`CORS_ALLOW_CREDENTIALS requires explicit CORS_ALLOWED_ORIGINS, not "*"`)
+
+// Validate rejeita credenciais com a origem "*": qualquer site poderia fazer requisições
+// autenticadas com os cookies do usuário
+func (cfg CORSConfig) Validate() error {
+	for _, origin := range cfg.AllowedOrigins {
+		if strings.TrimSpace(origin) == "*" && cfg.AllowCredentials {
+			return ErrCORSWildcardCredentials
+		}
+	}
+	return nil
+}
+
 // CORSMiddleware configura CORS para permitir acesso da interface web
 func CORSMiddleware() gin.HandlerFunc {
+	return CORSWithConfig(DefaultCORSConfig())
+}
+
+// CORSWithConfig configura CORS com as origens e os métodos informados. Requisições de origens
+// não permitidas seguem sem os cabeçalhos de CORS (o navegador as bloqueia) e o preflight delas
+// recebe 403
+func CORSWithConfig(cfg CORSConfig) gin.HandlerFunc {
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = DefaultCORSConfig().AllowedMethods
+	}
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = DefaultCORSConfig().AllowedHeaders
+	}
+	allowAll := false
+	for _, origin := range cfg.AllowedOrigins {
+		allowAll = allowAll || strings.TrimSpace(origin) == "*"
+	}
+	methods := strings.ToUpper(strings.Join(cfg.AllowedMethods, ", "))
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions
+
+		if origin == "" {
+			if preflight {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+			return
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		c.Writer.Header().Add("Vary", "Origin")
+		if !allowAll && !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Com "*" as credenciais nunca são enviadas, mesmo se configuradas (Validate as rejeita)
+		if allowAll {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// originAllowed verifica a origem na lista, aceitando "*." para os subdomínios
+func originAllowed(allowed []string, origin string) bool {
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	for _, candidate := range allowed {
+		candidate = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(candidate), "/"))
+		if candidate == origin {
+			return true
+		}
+		// "https://*.exemplo.com.br" aceita "https://app.exemplo.com.br", não "https://exemplo.com.br"
+		if scheme, host, ok := strings.Cut(candidate, "://*."); ok {
+			if strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// Handler serve a especificação das rotas do engine. Ela é gerada na primeira requisição, quando
+// todas as rotas já foram registradas.
+type Handler struct {
+	generator *Generator
+	engine    *gin.Engine
+	logger    *logger.Logger
+
+	once     sync.Once
+	spec     Spec
+	report   Report
+	jsonBody []byte
+	yamlBody []byte
+	err      error
+}
+
+// NewHandler cria o handler da especificação do engine
+func NewHandler(generator *Generator, engine *gin.Engine) *Handler {
+	return &Handler{
+		generator: generator,
+		engine:    engine,
+		logger:    logger.NewLogger("openapi"),
+	}
+}
+
+// generate monta e serializa a especificação uma única vez
+func (h *Handler) generate() {
+	h.once.Do(func() {
+		h.spec, h.report = h.generator.Generate(h.engine.Routes())
+		h.logger.WithFields(map[string]interface{}{
+			"documented":   h.report.Documented,
+			"undocumented": len(h.report.Undocumented),
+			"stale":        len(h.report.Stale),
+			"schemas":      h.report.Schemas,
+		}).Info("OpenAPI specification generated")
+		for _, operation := range h.report.Stale {
+			h.logger.WithField("operation", operation).Warn("Documented operation has no registered route")
+		}
+
+		if h.jsonBody, h.err = json.MarshalIndent(h.spec, "", "  "); h.err != nil {
+			h.err = fmt.Errorf("failed to encode openapi json: %v", h.err)
+			return
+		}
+		if h.yamlBody, h.err = yaml.Marshal(map[string]interface{}(h.spec)); h.err != nil {
+			h.err = fmt.Errorf("failed to encode openapi yaml: %v", h.err)
+		}
+	})
+}
+
+// JSON serve a especificação em JSON
+func (h *Handler) JSON(c *gin.Context) {
+	h.generate()
+	if h.err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar a especificação OpenAPI", "details": h.err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.jsonBody)
+}
+
+// YAML serve a especificação em YAML
+func (h *Handler) YAML(c *gin.Context) {
+	h.generate()
+	if h.err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar a especificação OpenAPI", "details": h.err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", h.yamlBody)
+}
+
+// Coverage serve o resumo da geração: rotas sem anotação e anotações sem rota
+func (h *Handler) Coverage(c *gin.Context) {
+	h.generate()
+	c.JSON(http.StatusOK, h.report)
+}
@@ -0,0 +1,461 @@
+// Package openapi gera a especificação OpenAPI da API a partir das rotas registradas no Gin, das
+// anotações escritas à mão (descrições, exemplos e respostas) e dos tipos Go das respostas, para
+// que a documentação servida acompanhe o código.
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec é um documento OpenAPI 3 genérico, como decodificado do YAML/JSON
+type Spec map[string]interface{}
+
+// Report resume a geração: rotas sem anotação ganham uma operação gerada e operações anotadas
+// sem rota são removidas
+type Report struct {
+	Documented   int      `json:"documented"`
+	Undocumented []string `json:"undocumented"` // "GET /caminho" sem anotação
+	Stale        []string `json:"stale"`        // anotações sem rota registrada
+	Schemas      int      `json:"schemas"`      // schemas gerados dos tipos Go
+}
+
+// Generator monta a especificação; as anotações vêm de um documento OpenAPI escrito à mão
+type Generator struct {
+	annotations Spec
+	schemas     map[string]reflect.Type
+	tags        map[string]string // primeiro segmento do caminho -> tag
+}
+
+// ParseSpec decodifica um documento OpenAPI em YAML (ou JSON); vazio retorna um documento vazio
+func ParseSpec(data []byte) (Spec, error) {
+	spec := Spec{}
+	if len(data) == 0 {
+		return spec, nil
+	}
+	var decoded interface{}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %v", err)
+	}
+	if root, ok := normalize(decoded).(map[string]interface{}); ok {
+		spec = root
+	}
+	return spec, nil
+}
+
+// NewGenerator cria o gerador com as anotações em YAML (ou JSON); vazio começa sem anotações
+func NewGenerator(annotations []byte) (*Generator, error) {
+	spec, err := ParseSpec(annotations)
+	if err != nil {
+		return nil, err
+	}
+	return &Generator{
+		annotations: spec,
+		schemas:     make(map[string]reflect.Type),
+		tags:        make(map[string]string),
+	}, nil
+}
+
+// LoadGenerator cria o gerador com as anotações do arquivo
+func LoadGenerator(path string) (*Generator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openapi annotations: %v", err)
+	}
+	return NewGenerator(data)
+}
+
+// RegisterSchema gera o schema name a partir do tipo de value; as descrições e exemplos anotados
+// nos campos com o mesmo nome são mantidos
+func (g *Generator) RegisterSchema(name string, value interface{}) {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	g.schemas[name] = t
+}
+
+// RegisterTag agrupa as rotas que começam pelo segmento (ex.: "cities") na tag
+func (g *Generator) RegisterTag(segment, tag string) {
+	g.tags[segment] = tag
+}
+
+// Generate monta a especificação das rotas: cada rota usa a operação anotada com o mesmo método
+// e caminho, ou uma operação gerada (x-generated) com os parâmetros de caminho da rota
+func (g *Generator) Generate(routes gin.RoutesInfo) (Spec, Report) {
+	spec := deepCopy(g.annotations).(map[string]interface{})
+	if _, ok := spec["openapi"]; !ok {
+		spec["openapi"] = "3.0.3"
+	}
+	if _, ok := spec["info"]; !ok {
+		spec["info"] = map[string]interface{}{"title": "API", "version": "1.0.0"}
+	}
+
+	annotated := make(map[string]map[string]interface{}) // forma do caminho -> operações
+	annotatedPaths := make(map[string]string)            // forma do caminho -> caminho anotado
+	if paths, ok := spec["paths"].(map[string]interface{}); ok {
+		for path, item := range paths {
+			if operations, ok := item.(map[string]interface{}); ok {
+				annotated[pathShape(path)] = operations
+				annotatedPaths[pathShape(path)] = path
+			}
+		}
+	}
+
+	var report Report
+	used := make(map[string]bool) // "método forma" das operações anotadas com rota
+	operationIDs := make(map[string]bool)
+	paths := make(map[string]interface{})
+	for _, route := range routes {
+		if strings.Contains(route.Path, "*") || route.Method == "HEAD" {
+			continue // arquivos estáticos
+		}
+		path, params := convertPath(route.Path)
+		method := strings.ToLower(route.Method)
+		shape := pathShape(path)
+
+		item, _ := paths[path].(map[string]interface{})
+		if item == nil {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+
+		if operation, ok := annotated[shape][method].(map[string]interface{}); ok {
+			used[method+" "+shape] = true
+			addPathParameters(operation, params)
+			if _, ok := operation["operationId"]; !ok {
+				operation["operationId"] = operationID(route, path, operationIDs)
+			}
+			item[method] = operation
+			report.Documented++
+			continue
+		}
+
+		report.Undocumented = append(report.Undocumented, route.Method+" "+path)
+		item[method] = g.generateOperation(route, path, params, operationIDs)
+	}
+
+	for shape, operations := range annotated {
+		for method, operation := range operations {
+			if _, isOperation := operation.(map[string]interface{}); !isOperation || !isHTTPMethod(method) {
+				continue
+			}
+			if !used[method+" "+shape] {
+				report.Stale = append(report.Stale, strings.ToUpper(method)+" "+annotatedPaths[shape])
+			}
+		}
+	}
+	spec["paths"] = paths
+	g.mergeTags(spec, paths)
+	report.Schemas = g.generateSchemas(spec)
+
+	sort.Strings(report.Undocumented)
+	sort.Strings(report.Stale)
+	return spec, report
+}
+
+// generateOperation cria a operação de uma rota sem anotação
+func (g *Generator) generateOperation(route gin.RouteInfo, path string, params []string, operationIDs map[string]bool) map[string]interface{} {
+	summary := humanize(handlerName(route.Handler))
+	if summary == "" {
+		summary = route.Method + " " + path
+	}
+
+	operation := map[string]interface{}{
+		"tags":        []interface{}{g.tagFor(path)},
+		"summary":     summary,
+		"operationId": operationID(route, path, operationIDs),
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{"description": "Sucesso"},
+		},
+		"x-generated": true,
+	}
+	addPathParameters(operation, params)
+	return operation
+}
+
+// operationID nomeia a operação pelo método do handler (GetAllCities -> getAllCities), ou pelo
+// método e caminho quando o handler é anônimo ou o nome já foi usado
+func operationID(route gin.RouteInfo, path string, used map[string]bool) string {
+	id := lowerFirst(handlerName(route.Handler))
+	if id == "" || used[id] {
+		id = lowerFirst(identifier(route.Method + " " + path))
+	}
+	used[id] = true
+	return id
+}
+
+// tagFor retorna a tag do caminho pelo primeiro segmento, sem extensão (openapi.json -> openapi)
+func (g *Generator) tagFor(path string) string {
+	segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	segment = strings.SplitN(segment, ".", 2)[0]
+	if tag, ok := g.tags[segment]; ok {
+		return tag
+	}
+	if segment == "" {
+		return "Default"
+	}
+	return humanize(identifier(segment))
+}
+
+// mergeTags acrescenta à lista de tags as usadas pelas operações geradas
+func (g *Generator) mergeTags(spec Spec, paths map[string]interface{}) {
+	tags, _ := spec["tags"].([]interface{})
+	known := make(map[string]bool)
+	for _, tag := range tags {
+		if entry, ok := tag.(map[string]interface{}); ok {
+			known[fmt.Sprint(entry["name"])] = true
+		}
+	}
+
+	var added []string
+	for _, item := range paths {
+		for _, operation := range item.(map[string]interface{}) {
+			operationTags, _ := operation.(map[string]interface{})["tags"].([]interface{})
+			for _, tag := range operationTags {
+				name := fmt.Sprint(tag)
+				if !known[name] {
+					known[name] = true
+					added = append(added, name)
+				}
+			}
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		tags = append(tags, map[string]interface{}{"name": name})
+	}
+	if len(tags) > 0 {
+		spec["tags"] = tags
+	}
+}
+
+// generateSchemas substitui os schemas registrados pelos gerados dos tipos Go, mantendo as
+// anotações dos campos que continuam existindo
+func (g *Generator) generateSchemas(spec Spec) int {
+	components, _ := spec["components"].(map[string]interface{})
+	if components == nil {
+		components = make(map[string]interface{})
+		spec["components"] = components
+	}
+	schemas, _ := components["schemas"].(map[string]interface{})
+	if schemas == nil {
+		schemas = make(map[string]interface{})
+		components["schemas"] = schemas
+	}
+
+	refs := make(map[reflect.Type]string, len(g.schemas))
+	for name, t := range g.schemas {
+		refs[t] = name
+	}
+	for name, t := range g.schemas {
+		builder := schemaBuilder{refs: refs, visiting: map[reflect.Type]bool{t: true}}
+		generated := builder.structSchema(t)
+		if annotated, ok := schemas[name].(map[string]interface{}); ok {
+			mergeAnnotations(generated, annotated)
+		}
+		schemas[name] = generated
+	}
+	return len(g.schemas)
+}
+
+// mergeAnnotations copia para o schema gerado a descrição e os exemplos/enums anotados
+func mergeAnnotations(generated, annotated map[string]interface{}) {
+	for _, key := range []string{"description", "example"} {
+		if value, ok := annotated[key]; ok {
+			generated[key] = value
+		}
+	}
+	generatedProps, _ := generated["properties"].(map[string]interface{})
+	annotatedProps, _ := annotated["properties"].(map[string]interface{})
+	for name, prop := range generatedProps {
+		annotatedProp, ok := annotatedProps[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		generatedProp := prop.(map[string]interface{})
+		if _, isRef := generatedProp["$ref"]; isRef {
+			continue
+		}
+		if description, ok := annotatedProp["description"]; ok {
+			generatedProp["description"] = description
+		}
+		// Exemplos e restrições só valem enquanto o tipo do campo não mudou
+		if annotatedType, ok := annotatedProp["type"]; ok && annotatedType != generatedProp["type"] {
+			continue
+		}
+		for _, key := range []string{"example", "enum", "minimum", "maximum"} {
+			if value, ok := annotatedProp[key]; ok {
+				generatedProp[key] = value
+			}
+		}
+	}
+}
+
+var pathParamPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// pathShape normaliza os nomes dos parâmetros para comparar caminhos anotados e rotas
+func pathShape(path string) string {
+	return pathParamPattern.ReplaceAllString(path, "{}")
+}
+
+// convertPath converte o caminho do Gin (/cities/:city) para o OpenAPI (/cities/{city})
+func convertPath(ginPath string) (string, []string) {
+	segments := strings.Split(ginPath, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// addPathParameters acrescenta os parâmetros de caminho que a operação ainda não declara
+func addPathParameters(operation map[string]interface{}, params []string) {
+	existing, _ := operation["parameters"].([]interface{})
+	declared := make(map[string]bool)
+	for _, param := range existing {
+		if entry, ok := param.(map[string]interface{}); ok && entry["in"] == "path" {
+			declared[fmt.Sprint(entry["name"])] = true
+		}
+	}
+	for _, name := range params {
+		if declared[name] {
+			continue
+		}
+		existing = append(existing, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	if len(existing) > 0 {
+		operation["parameters"] = existing
+	}
+}
+
+// isHTTPMethod informa se a chave do item de caminho é uma operação
+func isHTTPMethod(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
+
+// handlerName extrai o nome do método do handler (handler.(*CitySitesHandler).GetAllCities-fm
+// -> GetAllCities); vazio para funções anônimas
+func handlerName(handler string) string {
+	name := handler[strings.LastIndex(handler, ".")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+	if name == "" || strings.HasPrefix(name, "func") || !unicode.IsUpper(rune(name[0])) {
+		return ""
+	}
+	return name
+}
+
+// identifier transforma "GET /cities/{city}" em "GetCitiesCity"
+func identifier(text string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		} else {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// humanize separa as palavras do identificador (SetCityRefreshSLA -> "Set city refresh SLA")
+func humanize(name string) string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes) ||
+			(unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))))
+		if !boundary {
+			continue
+		}
+		word := string(runes[start:i])
+		if len(words) > 0 && strings.ToUpper(word) != word {
+			word = strings.ToLower(word)
+		}
+		words = append(words, word)
+		start = i
+	}
+	return strings.Join(words, " ")
+}
+
+// lowerFirst deixa a primeira letra minúscula (GetAllCities -> getAllCities)
+func lowerFirst(name string) string {
+	if name == "" {
+		return ""
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// normalize converte os mapas com chaves não textuais do YAML (ex.: 200:) em map[string]
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalize(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	}
+	return value
+}
+
+// deepCopy copia o documento para que cada geração não altere as anotações
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case Spec:
+		return deepCopy(map[string]interface{}(v))
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	}
+	return value
+}
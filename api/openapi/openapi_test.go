@@ -0,0 +1,151 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSite struct {
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testCity struct {
+	testAudit
+	City     string            `json:"city"`
+	Sites    []testSite        `json:"sites"`
+	Failures int64             `json:"failures,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Internal string            `json:"-"`
+	secret   string
+}
+
+const testAnnotations = `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+tags:
+  - name: Cities
+paths:
+  /cities/{name}:
+    get:
+      tags: [Cities]
+      summary: Obter cidade
+      responses:
+        200:
+          description: Cidade encontrada
+  /legacy:
+    post:
+      summary: Endpoint removido
+components:
+  schemas:
+    City:
+      description: Cidade com sites cadastrados
+      properties:
+        city:
+          type: string
+          example: Muzambinho
+        failures:
+          type: string
+          example: nenhuma
+        removed:
+          type: string
+`
+
+type testHandler struct{}
+
+func (testHandler) SetCityRefreshSLA(c *gin.Context) {}
+
+func TestGenerator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/cities/:city", func(c *gin.Context) {})
+	r.PUT("/cities/:city/sla", testHandler{}.SetCityRefreshSLA)
+	r.Static("/static", ".")
+
+	generator, err := NewGenerator([]byte(testAnnotations))
+	assert.NoError(t, err)
+	generator.RegisterTag("cities", "Cities")
+	generator.RegisterSchema("City", &testCity{})
+	generator.RegisterSchema("CitySite", testSite{})
+
+	spec, report := generator.Generate(r.Routes())
+	assert.Equal(t, 1, report.Documented)
+	assert.Equal(t, []string{"PUT /cities/{city}/sla"}, report.Undocumented)
+	assert.Equal(t, []string{"POST /legacy"}, report.Stale)
+	assert.Equal(t, 2, report.Schemas)
+
+	paths := spec["paths"].(map[string]interface{})
+	assert.Len(t, paths, 2) // rotas estáticas e anotações sem rota ficam de fora
+
+	// Operação anotada fica no caminho da rota, com o parâmetro declarado
+	documented := paths["/cities/{city}"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, "Obter cidade", documented["summary"])
+	assert.Equal(t, "getCitiesCity", documented["operationId"]) // handler anônimo
+	assert.Contains(t, documented["responses"], "200")
+	assert.Len(t, documented["parameters"], 1)
+
+	// Operação gerada do nome do handler
+	generated := paths["/cities/{city}/sla"].(map[string]interface{})["put"].(map[string]interface{})
+	assert.Equal(t, "Set city refresh SLA", generated["summary"])
+	assert.Equal(t, "setCityRefreshSLA", generated["operationId"])
+	assert.Equal(t, []interface{}{"Cities"}, generated["tags"])
+	assert.Equal(t, true, generated["x-generated"])
+
+	// Schema gerado do tipo, com as anotações dos campos que continuam iguais
+	city := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["City"].(map[string]interface{})
+	assert.Equal(t, "Cidade com sites cadastrados", city["description"])
+	properties := city["properties"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"created_at", "city", "sites", "failures", "labels"}, keys(properties))
+	assert.Equal(t, "Muzambinho", properties["city"].(map[string]interface{})["example"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "format": "int64"}, properties["failures"])
+	assert.Equal(t, "date-time", properties["created_at"].(map[string]interface{})["format"])
+	assert.Equal(t, "#/components/schemas/CitySite", properties["sites"].(map[string]interface{})["items"].(map[string]interface{})["$ref"])
+
+	// Cada geração parte das anotações originais
+	again, _ := generator.Generate(r.Routes())
+	assert.Equal(t, spec, again)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/health", func(c *gin.Context) {})
+	generator, err := NewGenerator(nil)
+	assert.NoError(t, err)
+	docs := NewHandler(generator, r)
+	r.GET("/openapi.json", docs.JSON)
+	r.GET("/openapi.yaml", docs.YAML)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var spec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+	assert.Contains(t, spec["paths"], "/openapi.yaml")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/health:")
+}
+
+func keys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
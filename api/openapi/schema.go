@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaBuilder gera schemas OpenAPI dos tipos Go seguindo as tags json, como o encoding/json
+// serializa as respostas
+type schemaBuilder struct {
+	refs     map[reflect.Type]string // tipos registrados, referenciados por $ref
+	visiting map[reflect.Type]bool   // tipos em geração (evita recursão infinita)
+}
+
+// schema gera o schema do tipo; tipos registrados viram $ref
+func (b schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if name, ok := b.refs[t]; ok {
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Duração em nanossegundos"}
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType),
+		t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		// Tipos com serialização própria (ex.: ObjectID) saem como texto
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if b.visiting[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		b.visiting[t] = true
+		defer delete(b.visiting, t)
+		return b.structSchema(t)
+	}
+	// interface{} e demais tipos aceitam qualquer valor
+	return map[string]interface{}{}
+}
+
+// structSchema gera o objeto com os campos exportados da struct; structs embutidas sem tag json
+// têm os campos promovidos, como no encoding/json
+func (b schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	b.addFields(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// addFields acrescenta os campos da struct às propriedades
+func (b schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.addFields(fieldType, properties)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = b.schema(field.Type)
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/dujoseaugusto/go-crawler-project/api/handler"
 	"github.com/dujoseaugusto/go-crawler-project/api/middleware"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
 	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/dujoseaugusto/go-crawler-project/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -23,6 +25,59 @@ func SetupRouterWithPatternLearning(propertyService *service.PropertyService, ci
 }
 
 func SetupRouterWithContentLearning(propertyService *service.PropertyService, citySitesService *service.CitySitesService, patternLearner *crawler.PatternLearner, contentLearner *crawler.ContentBasedPatternLearner) *gin.Engine {
+	return SetupRouterWithDomainConfigs(propertyService, citySitesService, patternLearner, contentLearner, nil)
+}
+
+func SetupRouterWithDomainConfigs(propertyService *service.PropertyService, citySitesService *service.CitySitesService, patternLearner *crawler.PatternLearner, contentLearner *crawler.ContentBasedPatternLearner, domainConfigService *service.DomainConfigService) *gin.Engine {
+	return SetupRouterWithSecurity(propertyService, citySitesService, patternLearner, contentLearner, domainConfigService, DefaultRouterSecurity())
+}
+
+// bodyLimitRoutes recebem arquivos maiores que o limite geral e têm um limite próprio
+var bodyLimitRoutes = map[string]int64{"/properties/import": handler.MaxImportBodySize}
+
+// RouterSecurity reúne o CORS, os cabeçalhos de segurança e o limite do corpo das requisições
+type RouterSecurity struct {
+	CORS            middleware.CORSConfig
+	SecurityHeaders bool
+	Headers         middleware.SecurityHeadersConfig
+	MaxBodyBytes    int64  // 0 desativa o limite
+	AdminToken      string // token das rotas administrativas (vazio as mantém fechadas)
+}
+
+// DefaultRouterSecurity aceita qualquer origem, envia os cabeçalhos de segurança e limita o corpo
+// das requisições a 10 MiB
+func DefaultRouterSecurity() RouterSecurity {
+	return RouterSecurity{
+		CORS:            middleware.DefaultCORSConfig(),
+		SecurityHeaders: true,
+		MaxBodyBytes:    10 << 20,
+	}
+}
+
+// NewRouterSecurity lê CORS_*, SECURITY_HEADERS, CONTENT_SECURITY_POLICY, HSTS_MAX_AGE,
+// MAX_REQUEST_BODY_BYTES e ADMIN_API_TOKEN
+func NewRouterSecurity(cfg *config.Config) RouterSecurity {
+	cors := middleware.DefaultCORSConfig()
+	cors.AllowedOrigins = cfg.CORSAllowedOrigins
+	if len(cfg.CORSAllowedMethods) > 0 {
+		cors.AllowedMethods = cfg.CORSAllowedMethods
+	}
+	cors.AllowCredentials = cfg.CORSAllowCredentials
+	cors.MaxAge = cfg.CORSMaxAge
+
+	return RouterSecurity{
+		CORS:            cors,
+		SecurityHeaders: cfg.SecurityHeaders,
+		Headers: middleware.SecurityHeadersConfig{
+			ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+			HSTSMaxAge:            cfg.HSTSMaxAge,
+		},
+		MaxBodyBytes: cfg.MaxRequestBodyBytes,
+		AdminToken:   cfg.AdminAPIToken,
+	}
+}
+
+func SetupRouterWithSecurity(propertyService *service.PropertyService, citySitesService *service.CitySitesService, patternLearner *crawler.PatternLearner, contentLearner *crawler.ContentBasedPatternLearner, domainConfigService *service.DomainConfigService, security RouterSecurity) *gin.Engine {
 	r := gin.Default()
 
 	// Configurar rate limiting
@@ -40,12 +95,33 @@ func SetupRouterWithContentLearning(propertyService *service.PropertyService, ci
 		citySitesHandler = handler.NewCitySitesHandler(citySitesService)
 	}
 
-	// Handlers de aprendizado removidos - sistema simplificado
+	var domainConfigHandler *handler.DomainConfigHandler
+	if domainConfigService != nil {
+		domainConfigHandler = handler.NewDomainConfigHandler(domainConfigService)
+	}
+
+	// Handlers de aprendizado removidos - sistema simplificado (os padrões de URL continuam
+	// disponíveis quando o aprendiz é informado)
+	var patternLearningHandler *handler.PatternLearningHandler
+	if patternLearner != nil {
+		var imports *crawler.PatternImportQueue
+		if propertyService != nil {
+			imports = propertyService.PatternImports()
+		}
+		patternLearningHandler = handler.NewPatternLearningHandler(patternLearner, imports)
+	}
 
-	// Aplicar middlewares
-	r.Use(middleware.CORSMiddleware())
+	// Aplicar middlewares (CORS antes do rate limiting para o navegador ler as respostas 429)
+	if security.SecurityHeaders {
+		r.Use(middleware.SecurityHeadersMiddleware(security.Headers))
+	}
+	r.Use(middleware.CORSWithConfig(security.CORS))
+	r.Use(middleware.BodyLimitMiddleware(security.MaxBodyBytes, bodyLimitRoutes))
 	r.Use(generalLimiter.Middleware())
 
+	// Rotas administrativas exigem o token de ADMIN_API_TOKEN
+	adminAuth := middleware.AdminAuthMiddleware(security.AdminToken)
+
 	// Servir arquivos estáticos da interface web
 	r.Static("/static", "./web/static")
 
@@ -62,6 +138,11 @@ func SetupRouterWithContentLearning(propertyService *service.PropertyService, ci
 		c.Redirect(301, "/")
 	})
 
+	// Painel administrativo (qualidade dos dados por domínio)
+	r.GET("/admin", func(c *gin.Context) {
+		c.File("./web/admin.html")
+	})
+
 	// Documentação da API (Swagger UI)
 	r.GET("/docs", func(c *gin.Context) {
 		c.File("./web/docs.html")
@@ -73,14 +154,116 @@ func SetupRouterWithContentLearning(propertyService *service.PropertyService, ci
 	// Endpoints de propriedades
 	r.GET("/properties", propertyHandler.GetProperties)
 	r.GET("/properties/search", propertyHandler.SearchProperties)
+	r.GET("/properties/stream", propertyHandler.StreamProperties)
+	r.GET("/properties/facets", propertyHandler.GetPropertyFacets)
+	r.POST("/properties/import", adminAuth, propertyHandler.ImportProperties)
+
+	// Correção manual de campos (protegidos contra novos crawlings) e histórico das correções
+	r.PATCH("/properties/:id", adminAuth, propertyHandler.PatchProperty)
+	r.GET("/properties/:id/corrections", propertyHandler.GetPropertyCorrections)
+
+	// ID curto dos anúncios: redireciona para o site de origem (ou retorna o imóvel em JSON)
+	r.GET("/p/:shortid", propertyHandler.GetPropertyByShortID)
+
+	// Fotos dos anúncios servidas pela API (proxy com cache, robots e política por domínio)
+	r.GET("/properties/:id/photos/:n", propertyHandler.GetPropertyPhoto)
+
+	// Índice mensal de preços (mediana do R$/m²) por cidade e bairro
+	r.GET("/stats/index", propertyHandler.GetPriceIndex)
+
+	// Qualidade dos dados por domínio (preço, endereço, quartos, fotos e nota de extração)
+	r.GET("/stats/quality", propertyHandler.GetQualityMetrics)
+
+	// Mesmo imóvel anunciado por imobiliárias diferentes (com a diferença de preço)
+	r.GET("/stats/duplicates", propertyHandler.GetDuplicateReport)
+
+	// Registro canônico das duplicatas entre portais: campos fundidos por confiabilidade da fonte
+	// e anúncios originais de cada portal
+	r.POST("/properties/canonical/merge", propertyHandler.MergeDuplicates)
+	r.GET("/properties/canonical", propertyHandler.GetCanonicalProperties)
+	r.GET("/properties/canonical/:id", propertyHandler.GetCanonicalProperty)
+
+	// Conversão de unidades (alqueire/hectare/m²) e valores em reais com as regras dos extratores
+	r.GET("/units/convert", propertyHandler.ConvertUnits)
+
+	// Leads de baixa confiança lidos das mensagens dos links de WhatsApp ("catálogo via WhatsApp")
+	r.GET("/leads/whatsapp", propertyHandler.GetWhatsAppLeads)
+
+	// Anúncios que reutilizam fotos de outro imóvel (endereço ou preço diferentes) e fila de revisão
+	fraudGroup := r.Group("/fraud")
+	{
+		fraudGroup.POST("/scan", propertyHandler.DetectPhotoFraud)
+		fraudGroup.GET("/reviews", propertyHandler.GetFraudReviews)
+		fraudGroup.POST("/reviews/:id/resolve", propertyHandler.ResolveFraudReview)
+	}
 
 	// Endpoint do crawler (rate limiting removido temporariamente)
 	crawlerGroup := r.Group("/crawler")
 	{
 		crawlerGroup.POST("/trigger", propertyHandler.TriggerCrawler)
 		crawlerGroup.POST("/cleanup", propertyHandler.CleanupDatabase)
+
+		// Jobs de crawling: disparo (Idempotency-Key opcional), status, cancelamento e pausa por domínio
+		crawlerGroup.GET("/jobs", propertyHandler.GetCrawlJobs)
+		crawlerGroup.POST("/jobs", propertyHandler.TriggerCrawler)
+		crawlerGroup.GET("/jobs/:id", propertyHandler.GetCrawlJob)
+		crawlerGroup.GET("/costs", propertyHandler.GetCrawlJobCosts)
+		crawlerGroup.GET("/classifier-experiments", propertyHandler.GetClassifierExperiments)
+		crawlerGroup.POST("/jobs/:id/cancel", propertyHandler.CancelCrawlJob)
+		crawlerGroup.POST("/jobs/:id/pause", propertyHandler.PauseCrawlJobDomain)
+		crawlerGroup.POST("/jobs/:id/resume", propertyHandler.ResumeCrawlJobDomain)
+
+		// Templates de job: conjuntos nomeados de parâmetros (modo, IA, domínios, orçamentos)
+		crawlerGroup.GET("/templates", propertyHandler.GetJobTemplates)
+		crawlerGroup.GET("/templates/:name", propertyHandler.GetJobTemplate)
+		crawlerGroup.PUT("/templates/:name", propertyHandler.SaveJobTemplate)
+		crawlerGroup.DELETE("/templates/:name", propertyHandler.DeleteJobTemplate)
+		crawlerGroup.POST("/templates/:name/run", propertyHandler.RunJobTemplate)
+		crawlerGroup.GET("/coverage", propertyHandler.GetCoverage)
+
+		// Rendimento por origem de descoberta (seed, feed, formulários, paginação, links classificados)
+		crawlerGroup.GET("/discovery-sources", propertyHandler.GetDiscoverySources)
+		crawlerGroup.GET("/leader", propertyHandler.GetLeaderStatus)
+		crawlerGroup.GET("/config-changes", propertyHandler.GetConfigChanges)
+		crawlerGroup.GET("/domain-rates", propertyHandler.GetDomainRates)
+		crawlerGroup.GET("/rate-budget", propertyHandler.GetGlobalRate)
+		crawlerGroup.GET("/errors", propertyHandler.GetCrawlErrors)
+		crawlerGroup.GET("/dns", propertyHandler.GetDNSCache)
+		crawlerGroup.GET("/ai-tokens", propertyHandler.GetAITokenSavings)
+		crawlerGroup.GET("/feeds", propertyHandler.GetFeedStatus)
+		crawlerGroup.GET("/broken-photos", propertyHandler.GetBrokenPhotos)
+
+		// Registro de auditoria das requisições HTTP de saída (REQUEST_AUDIT)
+		crawlerGroup.GET("/requests", propertyHandler.GetRequestAudits)
+		crawlerGroup.GET("/requests/summary", propertyHandler.GetRequestAuditSummary)
+
+		// Tamanho e manutenção das coleções de URLs processadas e fingerprints
+		crawlerGroup.GET("/url-storage", propertyHandler.GetURLStorageStats)
+		crawlerGroup.POST("/url-storage/prune", propertyHandler.PruneURLStorage)
+	}
+
+	// Padrões de URL aprendidos; importações são validadas e só ativadas após aprovação. Importar,
+	// aprovar e rejeitar mudam os padrões usados pelo crawler e exigem o token de administrador
+	if patternLearningHandler != nil {
+		patternsGroup := r.Group("/patterns")
+		{
+			patternsGroup.GET("", patternLearningHandler.GetLearnedPatterns)
+			patternsGroup.GET("/export", patternLearningHandler.ExportPatterns)
+			patternsGroup.POST("/import", adminAuth, patternLearningHandler.ImportPatterns)
+			patternsGroup.GET("/imports", patternLearningHandler.GetPendingPatternImports)
+			patternsGroup.POST("/import/:id/approve", adminAuth, patternLearningHandler.ApprovePatternImport)
+			patternsGroup.DELETE("/import/:id", adminAuth, patternLearningHandler.RejectPatternImport)
+		}
 	}
 
+	// Diagnóstico: explica como a cascata de classificação decidiu sobre uma URL (baixa a URL
+	// informada, então exige o token de administrador)
+	r.GET("/debug/classify", adminAuth, propertyHandler.ExplainClassification)
+	r.GET("/debug/probe", adminAuth, propertyHandler.ProbeDomain)
+
+	// Polígonos aproximados dos bairros inferidos dos anúncios geocodificados (GeoJSON)
+	r.GET("/cities/:city/bairros.geojson", propertyHandler.GetBairroBoundaries)
+
 	// Endpoints de cidades e sites (apenas se o serviço estiver disponível)
 	if citySitesHandler != nil {
 		citiesGroup := r.Group("/cities")
@@ -95,6 +278,7 @@ func SetupRouterWithContentLearning(propertyService *service.PropertyService, ci
 			citiesGroup.GET("/:city", citySitesHandler.GetCityByName)
 			citiesGroup.GET("/:city/sites", citySitesHandler.GetCitySites)
 			citiesGroup.POST("/:city/validate", citySitesHandler.ValidateCitySites)
+			citiesGroup.PUT("/:city/sla", citySitesHandler.SetCityRefreshSLA)
 			citiesGroup.DELETE("/:city", citySitesHandler.DeleteCity)
 
 			// Gerenciamento de sites
@@ -106,32 +290,73 @@ func SetupRouterWithContentLearning(propertyService *service.PropertyService, ci
 			citiesGroup.GET("/statistics", citySitesHandler.GetStatistics)
 			citiesGroup.POST("/cleanup", citySitesHandler.CleanupInactiveSites)
 
+			// Migração de List-site.ini / sites.json para o repositório
+			citiesGroup.POST("/migrate", citySitesHandler.MigrateSiteFiles)
+
+			// Exportação e importação das listas curadas (promoção entre ambientes). O arquivo leva
+			// as configurações por domínio, com scripts e URLs de API e feeds, então as duas rotas
+			// exigem o token de administrador, como a alteração da configuração
+			citiesGroup.GET("/export", adminAuth, citySitesHandler.ExportCitySites)
+			citiesGroup.POST("/import", adminAuth, citySitesHandler.ImportCitySites)
+
 			// Busca por região
 			citiesGroup.GET("/region/:region", citySitesHandler.GetCitiesByRegion)
 		}
 	}
 
+	// Configurações por domínio (rate limit, JS, regras de URL, seletores). A configuração define
+	// URLs buscadas pelo crawler (API, feeds) e scripts executados nele, então as alterações
+	// exigem o token de administrador
+	if domainConfigHandler != nil {
+		r.GET("/domains", domainConfigHandler.GetAllDomainConfigs)
+		domainsGroup := r.Group("/domains/:domain")
+		{
+			domainsGroup.GET("/config", domainConfigHandler.GetDomainConfig)
+			domainsGroup.PUT("/config", adminAuth, domainConfigHandler.SaveDomainConfig)
+			domainsGroup.DELETE("/config", adminAuth, domainConfigHandler.DeleteDomainConfig)
+
+			// Caminhos sem anúncios aprendidos pelo crawler (ex: /blog, /contato)
+			domainsGroup.GET("/skip-paths", domainConfigHandler.GetSkipPaths)
+			domainsGroup.DELETE("/skip-paths", adminAuth, domainConfigHandler.DeleteSkipPath)
+		}
+	}
+
 	// Sistema de padrões removido - não é mais necessário
 
 	// Sistema de aprendizado removido - não é mais necessário
 
 	// Endpoint de health check (sem rate limiting)
 	r.GET("/health", func(c *gin.Context) {
-		features := []string{"web-interface", "search", "crawler", "swagger-documentation"}
+		features := []string{"web-interface", "search", "search-facets", "crawler", "crawl-jobs", "swagger-documentation"}
 		if citySitesHandler != nil {
 			features = append(features, "city-sites-management", "site-discovery")
 		}
+		if domainConfigHandler != nil {
+			features = append(features, "domain-config")
+		}
+
+		// Estado do Mongo acompanhado pelos heartbeats do driver (ver repository/mongo_client.go)
+		storage := repository.GetMongoHealth()
+		status := "healthy"
+		if storage.Status == repository.MongoStatusDown || storage.Status == repository.MongoStatusDegraded {
+			status = "degraded"
+		}
 
 		c.JSON(200, gin.H{
-			"status":      "healthy",
+			"status":      status,
+			"storage":     storage,
 			"service":     "go-crawler-api",
 			"version":     "1.3.0",
 			"features":    features,
 			"docs_url":    "/docs",
+			"openapi_url": "/openapi.json",
 			"mode":        "simplified",
 			"description": "Sistema simplificado - coleta todas as páginas como propriedades",
 		})
 	})
 
+	// Especificação OpenAPI gerada das rotas acima, das anotações e dos tipos das respostas
+	registerOpenAPIRoutes(r, newOpenAPIGenerator(OpenAPIAnnotationsPath))
+
 	return r
 }
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/api/handler"
+	"github.com/dujoseaugusto/go-crawler-project/api/middleware"
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSecurityTestRouter monta o roteador sem serviços, só com as rotas e os middlewares
+func newSecurityTestRouter(security RouterSecurity) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return SetupRouterWithSecurity(nil, &service.CitySitesService{}, crawler.NewPatternLearner(), nil, &service.DomainConfigService{}, security)
+}
+
+func TestRouterCORS(t *testing.T) {
+	security := DefaultRouterSecurity()
+	security.CORS = middleware.DefaultCORSConfig()
+	security.CORS.AllowedOrigins = []string{"https://painel.exemplo.com.br", "https://*.imobiliaria.com.br/"}
+	security.CORS.AllowCredentials = true
+	security.CORS.MaxAge = time.Hour
+	r := newSecurityTestRouter(security)
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/properties", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Preflight de origem permitida: 204 com métodos, cabeçalhos e cache
+	w := request(http.MethodOptions, "https://painel.exemplo.com.br")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://painel.exemplo.com.br", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+	// Subdomínios pelo curinga, mas não o domínio raiz nem outro esquema
+	w = request(http.MethodOptions, "https://app.imobiliaria.com.br")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.imobiliaria.com.br", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, http.StatusForbidden, request(http.MethodOptions, "https://imobiliaria.com.br").Code)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodOptions, "http://app.imobiliaria.com.br").Code)
+
+	// Preflight de origem não permitida: 403; requisição simples segue sem cabeçalhos de CORS
+	w = request(http.MethodOptions, "https://outro-site.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	w = request(http.MethodGet, "https://outro-site.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Sem Origin (curl, servidores): preflight 204 sem cabeçalhos de CORS
+	w = request(http.MethodOptions, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Qualquer origem sem credenciais responde "*"
+	r = newSecurityTestRouter(DefaultRouterSecurity())
+	w = request(http.MethodOptions, "https://qualquer.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	// "*" com credenciais é rejeitado na inicialização e, se chegar ao roteador, não envia credenciais
+	security = DefaultRouterSecurity()
+	security.CORS.AllowCredentials = true
+	assert.ErrorIs(t, security.CORS.Validate(), middleware.ErrCORSWildcardCredentials)
+	r = newSecurityTestRouter(security)
+	w = request(http.MethodOptions, "https://qualquer.com")
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.NoError(t, DefaultRouterSecurity().CORS.Validate())
+}
+
+func TestRouterSecurityHeaders(t *testing.T) {
+	security := DefaultRouterSecurity()
+	security.Headers = middleware.SecurityHeadersConfig{ContentSecurityPolicy: "default-src 'self'", HSTSMaxAge: 24 * time.Hour}
+	r := newSecurityTestRouter(security)
+
+	get := func(header, value string) http.Header {
+		req := httptest.NewRequest(http.MethodGet, "/web", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	headers := get("", "")
+	assert.Equal(t, "nosniff", headers.Get("X-Content-Type-Options"))
+	assert.Equal(t, "SAMEORIGIN", headers.Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", headers.Get("Referrer-Policy"))
+	assert.Equal(t, "same-origin", headers.Get("Cross-Origin-Opener-Policy"))
+	assert.Equal(t, "default-src 'self'", headers.Get("Content-Security-Policy"))
+	// HSTS só em HTTPS (inclusive atrás de proxy)
+	assert.Empty(t, headers.Get("Strict-Transport-Security"))
+	assert.Equal(t, "max-age=86400; includeSubDomains", get("X-Forwarded-Proto", "https").Get("Strict-Transport-Security"))
+
+	// SECURITY_HEADERS=false
+	security.SecurityHeaders = false
+	r = newSecurityTestRouter(security)
+	assert.Empty(t, get("", "").Get("X-Frame-Options"))
+}
+
+func TestRouterBodyLimit(t *testing.T) {
+	security := DefaultRouterSecurity()
+	security.MaxBodyBytes = 1 << 10
+	r := newSecurityTestRouter(security)
+
+	post := func(path string, size int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = size
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Corpo declarado acima do limite: 413 antes do handler
+	w := post("/crawler/trigger", 2<<10)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "Request body too large")
+
+	// A importação tem limite próprio, maior que o geral (o handler valida o formato)
+	importFile := func(size int64) int {
+		req := httptest.NewRequest(http.MethodPost, "/properties/import?format=xml", strings.NewReader("{}"))
+		req.Header.Set("X-Admin-Token", "segredo")
+		req.ContentLength = size
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	security.AdminToken = "segredo"
+	r = newSecurityTestRouter(security)
+	assert.Equal(t, http.StatusBadRequest, importFile(2<<10))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, importFile(handler.MaxImportBodySize+1))
+
+	// Sem limite geral a importação continua limitada
+	security.MaxBodyBytes = 0
+	r = newSecurityTestRouter(security)
+	assert.NotEqual(t, http.StatusRequestEntityTooLarge, post("/nao-existe", 2<<10).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, importFile(handler.MaxImportBodySize+1))
+}
+
+func TestRouterAdminAuth(t *testing.T) {
+	get := func(r *gin.Engine, header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/classify", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Sem ADMIN_API_TOKEN as rotas administrativas ficam fechadas
+	r := newSecurityTestRouter(DefaultRouterSecurity())
+	assert.Equal(t, http.StatusForbidden, get(r, "Authorization", "Bearer qualquer").Code)
+
+	security := DefaultRouterSecurity()
+	security.AdminToken = "segredo"
+	r = newSecurityTestRouter(security)
+	w := get(r, "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+	assert.Equal(t, http.StatusUnauthorized, get(r, "Authorization", "Bearer errado").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(r, "X-Admin-Token", "errado").Code)
+
+	// Token correto chega ao handler (sem url: 400)
+	assert.Equal(t, http.StatusBadRequest, get(r, "Authorization", "Bearer segredo").Code)
+	assert.Equal(t, http.StatusBadRequest, get(r, "X-Admin-Token", "segredo").Code)
+
+	// Correções manuais também exigem o token (corpo inválido: 400 no handler)
+	patch := func(token string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/properties/68c97cae222a3687a9d57115", strings.NewReader("{"))
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, patch(""))
+	assert.Equal(t, http.StatusBadRequest, patch("segredo"))
+
+	// Importação de imóveis também (formato inválido: 400 no handler)
+	importFile := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/properties/import?format=xml", strings.NewReader("{}"))
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, importFile(""))
+	assert.Equal(t, http.StatusBadRequest, importFile("segredo"))
+
+	// Alterações da configuração por domínio também (corpo inválido: 400 no handler)
+	putConfig := func(token string) int {
+		req := httptest.NewRequest(http.MethodPut, "/domains/imobiliaria.com.br/config", strings.NewReader("{"))
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, putConfig(""))
+	assert.Equal(t, http.StatusBadRequest, putConfig("segredo"))
+
+	// Exportação e importação das cidades levam as configurações por domínio (400 no handler)
+	adminRoute := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, adminRoute(http.MethodGet, "/cities/export?format=xml", ""))
+	assert.Equal(t, http.StatusBadRequest, adminRoute(http.MethodGet, "/cities/export?format=xml", "segredo"))
+	assert.Equal(t, http.StatusUnauthorized, adminRoute(http.MethodPost, "/cities/import", ""))
+	assert.Equal(t, http.StatusBadRequest, adminRoute(http.MethodPost, "/cities/import", "segredo"))
+
+	// Importação, aprovação e rejeição de padrões de URL mudam os padrões ativos do crawler
+	assert.Equal(t, http.StatusUnauthorized, adminRoute(http.MethodPost, "/patterns/import", ""))
+	assert.Equal(t, http.StatusBadRequest, adminRoute(http.MethodPost, "/patterns/import", "segredo"))
+	assert.Equal(t, http.StatusUnauthorized, adminRoute(http.MethodPost, "/patterns/import/abc/approve", ""))
+	assert.NotEqual(t, http.StatusUnauthorized, adminRoute(http.MethodPost, "/patterns/import/abc/approve", "segredo"))
+	assert.Equal(t, http.StatusUnauthorized, adminRoute(http.MethodDelete, "/patterns/import/abc", ""))
+	assert.NotEqual(t, http.StatusUnauthorized, adminRoute(http.MethodDelete, "/patterns/import/abc", "segredo"))
+}
@@ -0,0 +1,201 @@
+// Package client é o cliente Go da API do go-crawler-project. Os modelos e os métodos de cada
+// endpoint (models_gen.go e operations_gen.go) são gerados da especificação OpenAPI com
+// `make sdk`; este arquivo tem o cliente HTTP base.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Client chama a API; crie com NewClient
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Option configura o cliente
+type Option func(*Client)
+
+// WithHTTPClient usa o cliente HTTP informado (timeouts, proxy, transporte próprio)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader envia o cabeçalho em todas as requisições (ex.: Authorization)
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// NewClient cria o cliente da API em baseURL (ex.: http://localhost:8081)
+func NewClient(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		header:     make(http.Header),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// APIError é uma resposta de erro da API (status fora de 2xx)
+type APIError struct {
+	StatusCode int
+	Message    string // campo error (ou message) da resposta
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("api error %d", e.StatusCode)
+}
+
+// request descreve uma chamada montada pelos métodos gerados
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        interface{} // corpo JSON
+	rawBody     io.Reader   // corpo em outro formato, com contentType
+	contentType string
+}
+
+// addQuery acrescenta o parâmetro de query; valores zero não são enviados e listas repetem o
+// parâmetro
+func (r *request) addQuery(name string, value interface{}) {
+	values := encodeValues(value)
+	if len(values) == 0 {
+		return
+	}
+	if r.query == nil {
+		r.query = make(url.Values)
+	}
+	for _, v := range values {
+		r.query.Add(name, v)
+	}
+}
+
+// setHeader define o cabeçalho; valores zero não são enviados
+func (r *request) setHeader(name string, value interface{}) {
+	values := encodeValues(value)
+	if len(values) == 0 {
+		return
+	}
+	if r.header == nil {
+		r.header = make(http.Header)
+	}
+	r.header.Set(name, strings.Join(values, ","))
+}
+
+// encodeValues converte o valor do parâmetro em texto (nada para valores zero)
+func encodeValues(value interface{}) []string {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.IsZero() {
+		return nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return []string{t.Format(time.RFC3339)}
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, encodeValues(v.Index(i).Interface())...)
+		}
+		return values
+	}
+	return []string{fmt.Sprint(value)}
+}
+
+// do envia a requisição e decodifica a resposta em out (*[]byte recebe o corpo sem decodificar;
+// nil descarta)
+func (c *Client) do(ctx context.Context, r request, out interface{}) error {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	body := r.rawBody
+	contentType := r.contentType
+	if r.body != nil {
+		encoded, err := json.Marshal(r.body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %v", err)
+		}
+		body = bytes.NewReader(encoded)
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	// Endpoints que também redirecionam navegadores (ex: /p/{shortid}) respondem JSON ao cliente
+	req.Header.Set("Accept", "application/json")
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: data}
+		var payload struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &payload) == nil {
+			apiErr.Message = payload.Error
+			if apiErr.Message == "" {
+				apiErr.Message = payload.Message
+			}
+		}
+		return apiErr
+	}
+
+	switch target := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*target = data
+		return nil
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
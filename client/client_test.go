@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"token inválido"}`))
+			return
+		}
+		if r.URL.EscapedPath() != "/cities/S%C3%A3o%20Paulo" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("zero values should not be sent, got %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"city":"São Paulo","sites":[]}`))
+	}))
+	defer server.Close()
+
+	var city City
+	c := NewClient(server.URL+"/", WithHeader("Authorization", "Bearer token"))
+	req := request{method: http.MethodGet, path: "/cities/" + url.PathEscape("São Paulo")}
+	req.addQuery("stale", false)
+	if err := c.do(context.Background(), req, &city); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if city.City != "São Paulo" {
+		t.Errorf("expected decoded city, got %q", city.City)
+	}
+
+	_, err := NewClient(server.URL).GetCitySites(context.Background(), "Muzambinho")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "token inválido" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
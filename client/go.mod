@@ -0,0 +1,3 @@
+module github.com/dujoseaugusto/go-crawler-project/client
+
+go 1.21
@@ -0,0 +1,1832 @@
+// Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT.
+
+package client
+
+import "time"
+
+// AitokenCallStats é gerado da especificação OpenAPI
+type AitokenCallStats struct {
+	Calls          int     `json:"calls,omitempty"`
+	OriginalTokens int     `json:"original_tokens,omitempty"` // Tokens estimados do conteúdo recebido
+	SavedPercent   float64 `json:"saved_percent,omitempty"`
+	SavedTokens    int     `json:"saved_tokens,omitempty"`
+	SentTokens     int     `json:"sent_tokens,omitempty"` // Tokens estimados do conteúdo enviado após a sanitização
+}
+
+// AitokenSavings é gerado da especificação OpenAPI
+type AitokenSavings struct {
+	ByCall map[string]AitokenCallStats `json:"by_call,omitempty"` // Por tipo de chamada (classification, validation, pattern_analysis, selector_suggestion)
+	Total  *AitokenCallStats           `json:"total,omitempty"`
+}
+
+// BrokenPhoto é gerado da especificação OpenAPI
+type BrokenPhoto struct {
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+	Domain    string    `json:"domain,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Status    int       `json:"status,omitempty"`
+	URL       string    `json:"url,omitempty"`
+}
+
+// CanonicalMergeResult é gerado da especificação OpenAPI
+type CanonicalMergeResult struct {
+	Cidade   string             `json:"cidade,omitempty"`
+	Listings int                `json:"listings,omitempty"` // Anúncios comparados
+	Merged   int                `json:"merged,omitempty"`   // Registros canônicos gravados
+	Removed  int                `json:"removed,omitempty"`  // Registros canônicos que deixaram de existir
+	Sources  int                `json:"sources,omitempty"`  // Anúncios ligados a um registro canônico
+	Weights  map[string]float64 `json:"weights,omitempty"`  // Confiabilidade dos domínios envolvidos
+}
+
+// CanonicalProperty: Imóvel anunciado em vários portais, com os campos fundidos por confiabilidade da fonte
+type CanonicalProperty struct {
+	Cidade       string            `json:"cidade,omitempty"`
+	FieldSources map[string]string `json:"field_sources,omitempty"` // URL de onde veio cada campo
+	ID           string            `json:"id,omitempty"`
+	MergedAt     time.Time         `json:"merged_at,omitempty"`
+	Property     *Property         `json:"property,omitempty"`
+	Sources      []CanonicalSource `json:"sources,omitempty"` // Anúncios de origem como foram extraídos, do domínio mais confiável para o menos
+}
+
+// CanonicalSource é gerado da especificação OpenAPI
+type CanonicalSource struct {
+	Domain   string    `json:"domain,omitempty"`
+	Original *Property `json:"original,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Weight   float64   `json:"weight,omitempty"` // Confiabilidade do domínio (0 a 1)
+}
+
+// City é gerado da especificação OpenAPI
+type City struct {
+	ActiveSites        int            `json:"active_sites,omitempty"`
+	City               string         `json:"city,omitempty"`
+	Freshness          *CityFreshness `json:"freshness,omitempty"`
+	Hash               string         `json:"hash,omitempty"`
+	ID                 string         `json:"id,omitempty"`
+	LastDiscovery      time.Time      `json:"last_discovery,omitempty"`
+	LastRefresh        time.Time      `json:"last_refresh,omitempty"` // Último crawling bem-sucedido que cobriu os sites da cidade
+	LastRefreshAttempt time.Time      `json:"last_refresh_attempt,omitempty"`
+	LastRefreshError   string         `json:"last_refresh_error,omitempty"`
+	LastUpdated        time.Time      `json:"last_updated,omitempty"`
+	RefreshFailures    int            `json:"refresh_failures,omitempty"`  // Crawlings seguidos que falharam desde a última atualização
+	RefreshSLAHours    int            `json:"refresh_sla_hours,omitempty"` // SLA de atualização próprio da cidade (ausente usa CITY_REFRESH_SLA)
+	Region             string         `json:"region,omitempty"`
+	Sites              []CitySite     `json:"sites,omitempty"`
+	State              string         `json:"state,omitempty"`
+	Status             string         `json:"status,omitempty"`
+	TotalSites         int            `json:"total_sites,omitempty"`
+}
+
+// CityFreshness é gerado da especificação OpenAPI
+type CityFreshness struct {
+	AgeHours    float64   `json:"age_hours,omitempty"` // Horas desde a última atualização (ou da descoberta dos sites, sem atualização)
+	LastRefresh time.Time `json:"last_refresh,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	SLAHours    int       `json:"sla_hours,omitempty"`
+	Stale       bool      `json:"stale,omitempty"`
+}
+
+// CitySite é gerado da especificação OpenAPI
+type CitySite struct {
+	DiscoveredAt    time.Time `json:"discovered_at,omitempty"`
+	DiscoveryMethod string    `json:"discovery_method,omitempty"`
+	Domain          string    `json:"domain,omitempty"`
+	ErrorCount      int       `json:"error_count,omitempty"`
+	LastCrawled     time.Time `json:"last_crawled,omitempty"`
+	LastError       string    `json:"last_error,omitempty"`
+	LastSuccess     time.Time `json:"last_success,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	PropertiesFound int       `json:"properties_found,omitempty"`
+	ReferenceUrls   []string  `json:"reference_urls,omitempty"` // Anúncios de exemplo do site
+	ResponseTime    float64   `json:"response_time,omitempty"`
+	Source          string    `json:"source,omitempty"` // Arquivo de origem dos sites migrados
+	Status          string    `json:"status,omitempty"`
+	SuccessRate     float64   `json:"success_rate,omitempty"`
+	URL             string    `json:"url,omitempty"`
+}
+
+// CitySitesExport é gerado da especificação OpenAPI
+type CitySitesExport struct {
+	Cities     []CitySitesExportCity `json:"cities,omitempty"`
+	Domains    []DomainConfig        `json:"domains,omitempty"`
+	ExportedAt time.Time             `json:"exported_at,omitempty"`
+	Version    int                   `json:"version,omitempty"`
+}
+
+// CitySitesExportCity é gerado da especificação OpenAPI
+type CitySitesExportCity struct {
+	City            string                    `json:"city,omitempty"`
+	RefreshSLAHours int                       `json:"refresh_sla_hours,omitempty"`
+	Region          string                    `json:"region,omitempty"`
+	Sites           []CitySitesExportCitySite `json:"sites,omitempty"`
+	State           string                    `json:"state,omitempty"`
+	Status          string                    `json:"status,omitempty"`
+}
+
+// CitySitesExportCitySite é gerado da especificação OpenAPI
+type CitySitesExportCitySite struct {
+	DiscoveryMethod string   `json:"discovery_method,omitempty"`
+	Domain          string   `json:"domain,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	ReferenceUrls   []string `json:"reference_urls,omitempty"`
+	Source          string   `json:"source,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	URL             string   `json:"url,omitempty"`
+}
+
+// CitySitesImportResult é gerado da especificação OpenAPI
+type CitySitesImportResult struct {
+	Cities        int  `json:"cities,omitempty"`
+	CitiesCreated int  `json:"cities_created,omitempty"`
+	Domains       int  `json:"domains,omitempty"`
+	DryRun        bool `json:"dry_run,omitempty"`
+	Replace       bool `json:"replace,omitempty"`
+	SitesAdded    int  `json:"sites_added,omitempty"`
+	SitesRemoved  int  `json:"sites_removed,omitempty"`
+	SitesUpdated  int  `json:"sites_updated,omitempty"`
+}
+
+// ClassificationDecision é gerado da especificação OpenAPI
+type ClassificationDecision struct {
+	Action     string `json:"action,omitempty"` // property (salvar), catalog (explorar os links) ou skip
+	Engine     string `json:"engine,omitempty"`
+	IsProperty bool   `json:"is_property,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// ClassificationExplanation é gerado da especificação OpenAPI
+type ClassificationExplanation struct {
+	AI         *ClassificationExplanationAI         `json:"ai,omitempty"` // Ausente quando a IA não está configurada
+	Decision   *ClassificationDecision              `json:"decision,omitempty"`
+	Decisions  []ClassificationDecision             `json:"decisions,omitempty"`
+	FetchedAt  time.Time                            `json:"fetched_at,omitempty"`
+	FinalURL   string                               `json:"final_url,omitempty"` // URL após redirecionamentos
+	Heuristics *ClassificationExplanationHeuristics `json:"heuristics,omitempty"`
+	Patterns   *ClassificationExplanationPatterns   `json:"patterns,omitempty"`
+	StatusCode int                                  `json:"status_code,omitempty"`
+	Title      string                               `json:"title,omitempty"`
+	URL        string                               `json:"url,omitempty"`
+}
+
+// ClassificationExplanationAI: Ausente quando a IA não está configurada
+type ClassificationExplanationAI struct {
+	CalibratedConfidence float64  `json:"calibrated_confidence,omitempty"`
+	Confidence           float64  `json:"confidence,omitempty"`
+	Error                string   `json:"error,omitempty"`
+	Indicators           []string `json:"indicators,omitempty"`
+	IsPropertyPage       bool     `json:"is_property_page,omitempty"`
+	PageType             string   `json:"page_type,omitempty"`
+	Reasoning            string   `json:"reasoning,omitempty"`
+}
+
+// ClassificationExplanationHeuristics é gerado da especificação OpenAPI
+type ClassificationExplanationHeuristics struct {
+	ContentCalibratedConfidence float64                                        `json:"content_calibrated_confidence,omitempty"`
+	ContentConfidence           float64                                        `json:"content_confidence,omitempty"`
+	ContentType                 string                                         `json:"content_type,omitempty"`
+	Indicators                  *ClassificationExplanationHeuristicsIndicators `json:"indicators,omitempty"`
+	IndicatorsSayProperty       bool                                           `json:"indicators_say_property,omitempty"`
+	Precise                     *ClassificationExplanationHeuristicsPrecise    `json:"precise,omitempty"`
+	StrictPageType              string                                         `json:"strict_page_type,omitempty"`
+}
+
+// ClassificationExplanationHeuristicsIndicators é gerado da especificação OpenAPI
+type ClassificationExplanationHeuristicsIndicators struct {
+	Address bool `json:"address,omitempty"`
+	Price   bool `json:"price,omitempty"`
+	Rooms   bool `json:"rooms,omitempty"`
+}
+
+// ClassificationExplanationHeuristicsPrecise é gerado da especificação OpenAPI
+type ClassificationExplanationHeuristicsPrecise struct {
+	Confidence           float64                                            `json:"confidence,omitempty"`
+	Details              *ClassificationExplanationHeuristicsPreciseDetails `json:"details,omitempty"`
+	IsIndividualProperty bool                                               `json:"is_individual_property,omitempty"`
+	MaxScore             float64                                            `json:"max_score,omitempty"`
+	Reason               string                                             `json:"reason,omitempty"`
+	Score                float64                                            `json:"score,omitempty"`
+}
+
+// ClassificationExplanationHeuristicsPreciseDetails é gerado da especificação OpenAPI
+type ClassificationExplanationHeuristicsPreciseDetails struct {
+	FoundExclusions    []string `json:"found_exclusions,omitempty"`
+	FoundRequirements  []string `json:"found_requirements,omitempty"`
+	HasContactInfo     bool     `json:"has_contact_info,omitempty"`
+	HasPropertyDetails bool     `json:"has_property_details,omitempty"`
+	HasSpecificAddress bool     `json:"has_specific_address,omitempty"`
+	HasSpecificPrice   bool     `json:"has_specific_price,omitempty"`
+	IsGenericPage      bool     `json:"is_generic_page,omitempty"`
+	IsInstitutional    bool     `json:"is_institutional,omitempty"`
+	WordCount          int      `json:"word_count,omitempty"`
+}
+
+// ClassificationExplanationPatterns é gerado da especificação OpenAPI
+type ClassificationExplanationPatterns struct {
+	LearnedConfidence float64  `json:"learned_confidence,omitempty"`
+	LearnedType       string   `json:"learned_type,omitempty"`
+	NumericSlug       bool     `json:"numeric_slug,omitempty"`
+	URLPatterns       []string `json:"url_patterns,omitempty"`
+}
+
+// ClassifierExperimentReport é gerado da especificação OpenAPI
+type ClassifierExperimentReport struct {
+	Agreements     int                                  `json:"agreements,omitempty"`
+	Candidate      *ClassifierExperimentReportCandidate `json:"candidate,omitempty"`
+	Classified     int                                  `json:"classified,omitempty"`
+	CompletedAt    time.Time                            `json:"completed_at,omitempty"`
+	Control        *ClassifierExperimentReportControl   `json:"control,omitempty"`
+	DivergenceRate float64                              `json:"divergence_rate,omitempty"`
+	Divergences    int                                  `json:"divergences,omitempty"`
+	JobID          string                               `json:"job_id,omitempty"`
+	Name           string                               `json:"name,omitempty"`
+	Outcomes       map[string]map[string]int            `json:"outcomes,omitempty"`
+	SampleRate     float64                              `json:"sample_rate,omitempty"`
+	Sampled        int                                  `json:"sampled,omitempty"`
+	Samples        []ClassifierExperimentReportSample   `json:"samples,omitempty"`
+}
+
+// ClassifierExperimentReportCandidate é gerado da especificação OpenAPI
+type ClassifierExperimentReportCandidate struct {
+	MinConfidence   float64 `json:"min_confidence,omitempty"`
+	MinRequirements int     `json:"min_requirements,omitempty"`
+}
+
+// ClassifierExperimentReportControl é gerado da especificação OpenAPI
+type ClassifierExperimentReportControl struct {
+	MinConfidence   float64 `json:"min_confidence,omitempty"`
+	MinRequirements int     `json:"min_requirements,omitempty"`
+}
+
+// ClassifierExperimentReportSample é gerado da especificação OpenAPI
+type ClassifierExperimentReportSample struct {
+	Confidence   float64   `json:"confidence,omitempty"`
+	Decision     string    `json:"decision,omitempty"`
+	Domain       string    `json:"domain,omitempty"`
+	Outcome      string    `json:"outcome,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at,omitempty"`
+	Requirements int       `json:"requirements,omitempty"`
+	URL          string    `json:"url,omitempty"`
+}
+
+// CollectionStorageStats é gerado da especificação OpenAPI
+type CollectionStorageStats struct {
+	DataSizeBytes  int64     `json:"data_size_bytes,omitempty"`
+	Documents      int64     `json:"documents,omitempty"`
+	IndexSizeBytes int64     `json:"index_size_bytes,omitempty"`
+	MaxDocuments   int64     `json:"max_documents,omitempty"` // Limite de documentos (0 = sem limite)
+	Name           string    `json:"name,omitempty"`
+	Newest         time.Time `json:"newest,omitempty"`
+	Oldest         time.Time `json:"oldest,omitempty"`
+	StorageBytes   int64     `json:"storage_bytes,omitempty"`
+	TtlSeconds     int64     `json:"ttl_seconds,omitempty"` // Expiração do índice TTL (0 = desativado)
+}
+
+// ConfigChange é gerado da especificação OpenAPI
+type ConfigChange struct {
+	Field    string    `json:"field,omitempty"`
+	NewValue string    `json:"new_value,omitempty"`
+	OldValue string    `json:"old_value,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Time     time.Time `json:"time,omitempty"`
+	Version  int       `json:"version,omitempty"` // Versão da configuração em que a alteração foi aplicada
+}
+
+// ContentPattern é gerado da especificação OpenAPI
+type ContentPattern struct {
+	Confidence float64                 `json:"confidence,omitempty"`
+	CreatedAt  time.Time               `json:"created_at,omitempty"`
+	Features   *ContentPatternFeatures `json:"features,omitempty"`
+	ID         string                  `json:"id,omitempty"`
+	MatchCount int                     `json:"match_count,omitempty"`
+	Type       string                  `json:"type,omitempty"`
+	UpdatedAt  time.Time               `json:"updated_at,omitempty"`
+}
+
+// ContentPatternFeatures é gerado da especificação OpenAPI
+type ContentPatternFeatures struct {
+	HasFilters    bool `json:"has_filters,omitempty"`
+	HasPagination bool `json:"has_pagination,omitempty"`
+	ImoveisCount  int  `json:"imoveis_count,omitempty"`
+	PriceCount    int  `json:"price_count,omitempty"`
+	TextLength    int  `json:"text_length,omitempty"`
+}
+
+// CrawlCostSummary é gerado da especificação OpenAPI
+type CrawlCostSummary struct {
+	AIEnabled          bool    `json:"ai_enabled,omitempty"`
+	AITokens           int64   `json:"ai_tokens,omitempty"`
+	AvgCostPerJobUSD   float64 `json:"avg_cost_per_job_usd,omitempty"`
+	Bytes              int64   `json:"bytes,omitempty"`
+	ComputeSeconds     float64 `json:"compute_seconds,omitempty"`
+	CostPerPropertyUSD float64 `json:"cost_per_property_usd,omitempty"` // 0 sem imóveis salvos
+	Jobs               int     `json:"jobs,omitempty"`
+	Mode               string  `json:"mode,omitempty"`
+	Properties         int64   `json:"properties,omitempty"`
+	PropertiesPerMbyte float64 `json:"properties_per_mbyte,omitempty"`
+	TotalCostUSD       float64 `json:"total_cost_usd,omitempty"`
+}
+
+// CrawlError é gerado da especificação OpenAPI
+type CrawlError struct {
+	Domain     string    `json:"domain,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	OccurredAt time.Time `json:"occurred_at,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Type       string    `json:"type,omitempty"`
+	URL        string    `json:"url,omitempty"`
+}
+
+// CrawlJob é gerado da especificação OpenAPI
+type CrawlJob struct {
+	BudgetExhausted      string                      `json:"budget_exhausted,omitempty"` // Orçamento que encerrou o job antecipadamente
+	Cities               []string                    `json:"cities,omitempty"`
+	ClassifierExperiment *ClassifierExperimentReport `json:"classifier_experiment,omitempty"`
+	CompletedAt          time.Time                   `json:"completed_at,omitempty"`
+	Cost                 *CrawlJobCost               `json:"cost,omitempty"`
+	Coverage             []DomainCoverage            `json:"coverage,omitempty"`
+	DeferredUrls         int                         `json:"deferred_urls,omitempty"` // URLs aguardando a retomada de domínios pausados
+	Domains              []string                    `json:"domains,omitempty"`       // Domínios aos quais as seeds foram restritas
+	Error                string                      `json:"error,omitempty"`
+	ID                   string                      `json:"id,omitempty"`
+	MaxDurationMinutes   int                         `json:"max_duration_minutes,omitempty"`
+	MaxPages             int                         `json:"max_pages,omitempty"`
+	Mode                 string                      `json:"mode,omitempty"` // Modo informado no disparo; `feed` para os jobs das entradas de feeds
+	PausedDomains        []string                    `json:"paused_domains,omitempty"`
+	StartedAt            time.Time                   `json:"started_at,omitempty"`
+	Status               string                      `json:"status,omitempty"`
+	Tags                 []string                    `json:"tags,omitempty"`          // Tags registradas nos imóveis salvos pelo job
+	Template             string                      `json:"template,omitempty"`      // Template usado no disparo
+	VisitedPages         int                         `json:"visited_pages,omitempty"` // Páginas agendadas para visita pelo job
+}
+
+// CrawlJobCost é gerado da especificação OpenAPI
+type CrawlJobCost struct {
+	AICalls            int64     `json:"ai_calls,omitempty"`
+	AICostUSD          float64   `json:"ai_cost_usd,omitempty"`
+	AIEnabled          bool      `json:"ai_enabled,omitempty"` // O job rodou com o serviço de IA disponível
+	AIOutputTokens     int64     `json:"ai_output_tokens,omitempty"`
+	AIPromptTokens     int64     `json:"ai_prompt_tokens,omitempty"`
+	BandwidthCostUSD   float64   `json:"bandwidth_cost_usd,omitempty"`
+	Bytes              int64     `json:"bytes,omitempty"` // Bytes baixados
+	CompletedAt        time.Time `json:"completed_at,omitempty"`
+	ComputeCostUSD     float64   `json:"compute_cost_usd,omitempty"`
+	ComputeSeconds     float64   `json:"compute_seconds,omitempty"`       // Duração da execução
+	CostPerPropertyUSD float64   `json:"cost_per_property_usd,omitempty"` // 0 sem imóveis salvos
+	JobID              string    `json:"job_id,omitempty"`
+	Mode               string    `json:"mode,omitempty"`
+	Pages              int       `json:"pages,omitempty"`
+	Properties         int64     `json:"properties,omitempty"` // Imóveis salvos
+	ProxyBytes         int64     `json:"proxy_bytes,omitempty"`
+	ProxyCostUSD       float64   `json:"proxy_cost_usd,omitempty"`
+	ProxyRequests      int64     `json:"proxy_requests,omitempty"`
+	Requests           int64     `json:"requests,omitempty"`
+	StartedAt          time.Time `json:"started_at,omitempty"`
+	Status             string    `json:"status,omitempty"`
+	Template           string    `json:"template,omitempty"`
+	TotalCostUSD       float64   `json:"total_cost_usd,omitempty"`
+}
+
+// CrawlJobTemplate é gerado da especificação OpenAPI
+type CrawlJobTemplate struct {
+	Builtin            bool      `json:"builtin,omitempty"` // Template padrão, não cadastrado
+	Cities             []string  `json:"cities,omitempty"`  // Vazio para todas as cidades
+	CreatedAt          time.Time `json:"created_at,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	Domains            []string  `json:"domains,omitempty"`              // Restringe as seeds a estes domínios
+	EnableAI           bool      `json:"enable_ai,omitempty"`            // Omitido para usar a IA quando disponível
+	MaxDurationMinutes int       `json:"max_duration_minutes,omitempty"` // Duração máxima em minutos (0 sem limite)
+	MaxPages           int       `json:"max_pages,omitempty"`            // Máximo de páginas visitadas (0 sem limite)
+	Mode               string    `json:"mode,omitempty"`                 // full usa o crawler recursivo (todas as páginas); incremental usa o engine incremental, que pula as páginas sem mudanças e revisita cada URL no seu intervalo adaptativo
+	Name               string    `json:"name,omitempty"`
+	Tags               []string  `json:"tags,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at,omitempty"`
+}
+
+// DnscacheStats é gerado da especificação OpenAPI
+type DnscacheStats struct {
+	Entries      int                        `json:"entries,omitempty"` // Hosts em cache (resoluções e falhas)
+	Failing      []DnscacheStatsFailingItem `json:"failing,omitempty"`
+	Hits         int64                      `json:"hits,omitempty"`
+	Misses       int64                      `json:"misses,omitempty"`
+	NegativeHits int64                      `json:"negative_hits,omitempty"` // Consultas respondidas pelo cache negativo (falhas recentes)
+}
+
+// DnscacheStatsFailingItem é gerado da especificação OpenAPI
+type DnscacheStatsFailingItem struct {
+	Failures         int       `json:"failures,omitempty"`
+	Host             string    `json:"host,omitempty"`
+	LastError        string    `json:"last_error,omitempty"`
+	QuarantinedUntil time.Time `json:"quarantined_until,omitempty"`
+	Skipped          int       `json:"skipped,omitempty"`
+}
+
+// DiscoverySourceYield é gerado da especificação OpenAPI
+type DiscoverySourceYield struct {
+	Crawls     int     `json:"crawls,omitempty"`     // Crawlings em que a origem apareceu
+	Disabled   bool    `json:"disabled,omitempty"`   // Origem desativada na configuração do domínio
+	Properties int     `json:"properties,omitempty"` // Anúncios encontrados a partir da origem
+	Source     string  `json:"source,omitempty"`
+	Visited    int     `json:"visited,omitempty"` // Páginas visitadas a partir da origem
+	Yield      float64 `json:"yield,omitempty"`   // Anúncios por página visitada
+}
+
+// DomainApiconfig: API JSON de busca do portal. Quando configurada, as seeds do domínio são lidas direto da API (sem crawling HTML) e os imóveis seguem o mesmo pós-processamento, validação e gravação das páginas.
+type DomainApiconfig struct {
+	Endpoint     string            `json:"endpoint,omitempty"`      // URL da API com os marcadores {page}, {offset} e {page_size}
+	FieldMapping map[string]string `json:"field_mapping,omitempty"` // Campo do imóvel (url, endereco, cidade, bairro, cep, descricao, valor, quartos, banheiros, area_total, area_util, tipo_imovel, caracteristicas, fotos) para o caminho no item; índices numéricos acessam listas (ex: "fotos.0.url")
+	Headers      map[string]string `json:"headers,omitempty"`
+	MaxPages     int               `json:"max_pages,omitempty"`    // Limite de páginas (padrão 50)
+	PageSize     int               `json:"page_size,omitempty"`    // Itens por página (padrão 20)
+	ResultsPath  string            `json:"results_path,omitempty"` // Caminho com pontos até a lista de anúncios (vazio se a resposta já é a lista)
+	StartPage    int               `json:"start_page,omitempty"`   // Primeira página (padrão 1)
+	URLTemplate  string            `json:"url_template,omitempty"` // URL do anúncio montada com campos do item, usada se field_mapping.url não for informado
+}
+
+// DomainConfig é gerado da especificação OpenAPI
+type DomainConfig struct {
+	AllowSubdomains          bool                   `json:"allow_subdomains,omitempty"`
+	API                      *DomainApiconfig       `json:"api,omitempty"`
+	CrawlWindows             []string               `json:"crawl_windows,omitempty"`
+	CreatedAt                time.Time              `json:"created_at,omitempty"`
+	DisabledDiscoverySources []string               `json:"disabled_discovery_sources,omitempty"`
+	Domain                   string                 `json:"domain,omitempty"`
+	EnableJs                 bool                   `json:"enable_js,omitempty"`
+	ExcludePatterns          []string               `json:"exclude_patterns,omitempty"`
+	Feeds                    []string               `json:"feeds,omitempty"`
+	ID                       string                 `json:"id,omitempty"`
+	IncludePatterns          []string               `json:"include_patterns,omitempty"`
+	LearnedSkipPaths         []string               `json:"learned_skip_paths,omitempty"`
+	Notes                    string                 `json:"notes,omitempty"`
+	PhotoPolicy              string                 `json:"photo_policy,omitempty"`
+	PhotoReferer             bool                   `json:"photo_referer,omitempty"`
+	PostProcessScript        string                 `json:"post_process_script,omitempty"`
+	RateLimit                *DomainConfigRateLimit `json:"rate_limit,omitempty"`
+	Selectors                map[string]string      `json:"selectors,omitempty"`
+	Timezone                 string                 `json:"timezone,omitempty"`
+	UpdatedAt                time.Time              `json:"updated_at,omitempty"`
+}
+
+// DomainConfigRateLimit é gerado da especificação OpenAPI
+type DomainConfigRateLimit struct {
+	DelayMs           int `json:"delay_ms,omitempty"`
+	Parallelism       int `json:"parallelism,omitempty"`
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+}
+
+// DomainConfigInput é gerado da especificação OpenAPI
+type DomainConfigInput struct {
+	AllowSubdomains   bool                        `json:"allow_subdomains,omitempty"` // No modo estrito de domínios (CRAWL_STRICT_DOMAINS), permite também os subdomínios do domínio
+	API               *DomainApiconfig            `json:"api,omitempty"`
+	CrawlWindows      []string                    `json:"crawl_windows,omitempty"`    // Janelas do dia (HH:MM-HH:MM, podendo atravessar a meia-noite) em que o domínio pode ser crawleado. Fora delas as requisições ao domínio são adiadas para o próximo crawling. Vazio permite qualquer horário.
+	EnableJs          bool                        `json:"enable_js,omitempty"`        // Habilita renderização de JavaScript para o domínio
+	ExcludePatterns   []string                    `json:"exclude_patterns,omitempty"` // Expressões regulares de URLs ignoradas
+	Feeds             []string                    `json:"feeds,omitempty"`            // Feeds RSS/Atom de novos anúncios do domínio (ver GET /crawler/feeds)
+	IncludePatterns   []string                    `json:"include_patterns,omitempty"` // Expressões regulares de URLs permitidas
+	Notes             string                      `json:"notes,omitempty"`
+	PhotoPolicy       string                      `json:"photo_policy,omitempty"`        // Fotos em GET /properties/{id}/photos/{n}: proxy (padrão, busca e guarda em cache), redirect (o site permite hotlink) ou deny (fotos não exibidas)
+	PhotoReferer      bool                        `json:"photo_referer,omitempty"`       // Envia a página do anúncio como Referer ao buscar as fotos (proteção contra hotlink)
+	PostProcessScript string                      `json:"post_process_script,omitempty"` // Script Lua executado após a extração. Recebe a tabela global `property` (endereco, cidade, bairro, cep, descricao, valor, valor_texto, quartos, banheiros, area_total, area_util, tipo_imovel, fotos, caracteristicas, custom_fields) além de `url` e `domain`, e a altera in-place. Apenas as bibliotecas base, string, table e math estão disponíveis. O script roda por até 200 ms, com até 64 chamadas aninhadas, e falha ao gerar textos acima de 1 MiB.
+	RateLimit         *DomainConfigInputRateLimit `json:"rate_limit,omitempty"`
+	Selectors         map[string]string           `json:"selectors,omitempty"` // Seletores CSS customizados por campo (endereco, cidade, bairro, descricao, valor, quartos, banheiros, area_total, tipo_imovel, caracteristicas)
+	Timezone          string                      `json:"timezone,omitempty"`  // Fuso das janelas de crawling (padrão America/Sao_Paulo)
+}
+
+// DomainConfigInputRateLimit é gerado da especificação OpenAPI
+type DomainConfigInputRateLimit struct {
+	DelayMs           int `json:"delay_ms,omitempty"`
+	Parallelism       int `json:"parallelism,omitempty"`
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+}
+
+// DomainCoverage é gerado da especificação OpenAPI
+type DomainCoverage struct {
+	CatalogTotal           int       `json:"catalog_total,omitempty"`   // Maior total exibido nos catálogos
+	DiscoveredUrls         int       `json:"discovered_urls,omitempty"` // Imóveis encontrados pelo crawling
+	Domain                 string    `json:"domain,omitempty"`
+	EstimatedMissedPercent float64   `json:"estimated_missed_percent,omitempty"`
+	EstimatedTotal         int       `json:"estimated_total,omitempty"`
+	GeneratedAt            time.Time `json:"generated_at,omitempty"`
+	SitemapPropertyUrls    int       `json:"sitemap_property_urls,omitempty"` // URLs de imóveis no sitemap (-1 quando o site não tem sitemap)
+	Sources                []string  `json:"sources,omitempty"`
+}
+
+// DomainProbe é gerado da especificação OpenAPI
+type DomainProbe struct {
+	DNS      *DomainProbeDNS      `json:"dns,omitempty"`
+	Domain   string               `json:"domain,omitempty"`
+	HTTP     *DomainProbeHTTP     `json:"http,omitempty"`
+	Notes    []string             `json:"notes,omitempty"`
+	ProbedAt time.Time            `json:"probed_at,omitempty"`
+	TCP      []DomainProbeTCPItem `json:"tcp,omitempty"`
+	TLS      *DomainProbeTLS      `json:"tls,omitempty"`
+	Verdict  string               `json:"verdict,omitempty"`
+}
+
+// DomainProbeDNS é gerado da especificação OpenAPI
+type DomainProbeDNS struct {
+	DurationMs int64    `json:"duration_ms,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Ipv4       []string `json:"ipv4,omitempty"`
+	Ipv6       []string `json:"ipv6,omitempty"`
+}
+
+// DomainProbeHTTP é gerado da especificação OpenAPI
+type DomainProbeHTTP struct {
+	BlockSignals []string `json:"block_signals,omitempty"`
+	DurationMs   int64    `json:"duration_ms,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Location     string   `json:"location,omitempty"`
+	Server       string   `json:"server,omitempty"`
+	StatusCode   int      `json:"status_code,omitempty"`
+	URL          string   `json:"url,omitempty"`
+}
+
+// DomainProbeTCPItem é gerado da especificação OpenAPI
+type DomainProbeTCPItem struct {
+	Address    string `json:"address,omitempty"`
+	Connected  bool   `json:"connected,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Family     string `json:"family,omitempty"`
+	Port       int    `json:"port,omitempty"`
+}
+
+// DomainProbeTLS é gerado da especificação OpenAPI
+type DomainProbeTLS struct {
+	Address    string    `json:"address,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Issuer     string    `json:"issuer,omitempty"`
+	NotAfter   time.Time `json:"not_after,omitempty"`
+	Subject    string    `json:"subject,omitempty"`
+	Version    string    `json:"version,omitempty"`
+}
+
+// DomainQuality é gerado da especificação OpenAPI
+type DomainQuality struct {
+	AvgExtractionScore float64   `json:"avg_extraction_score,omitempty"` // Média de field_confidence (0 a 1) dos anúncios com notas
+	ComputedAt         time.Time `json:"computed_at,omitempty"`
+	Day                string    `json:"day,omitempty"`
+	Domain             string    `json:"domain,omitempty"`
+	Properties         int       `json:"properties,omitempty"`
+	ScoredProperties   int       `json:"scored_properties,omitempty"`
+	WithAddressPct     float64   `json:"with_address_pct,omitempty"`
+	WithPhotosPct      float64   `json:"with_photos_pct,omitempty"`
+	WithPricePct       float64   `json:"with_price_pct,omitempty"`
+	WithRoomsPct       float64   `json:"with_rooms_pct,omitempty"`
+}
+
+// DomainRateStats é gerado da especificação OpenAPI
+type DomainRateStats struct {
+	AdaptiveDelayMs   int64     `json:"adaptive_delay_ms,omitempty"` // Atraso adicional aprendido com respostas 429/503
+	BaseDelayMs       int64     `json:"base_delay_ms,omitempty"`
+	Domain            string    `json:"domain,omitempty"`
+	EffectiveDelayMs  int64     `json:"effective_delay_ms,omitempty"`
+	LastStatus        int       `json:"last_status,omitempty"`
+	LastThrottledAt   time.Time `json:"last_throttled_at,omitempty"`
+	RequestsPerMinute float64   `json:"requests_per_minute,omitempty"` // Ritmo máximo resultante (0 = sem limite)
+	RetryAfterUntil   time.Time `json:"retry_after_until,omitempty"`   // Requisições bloqueadas até este horário (Retry-After)
+	SuccessCount      int       `json:"success_count,omitempty"`
+	ThrottledCount    int       `json:"throttled_count,omitempty"`
+}
+
+// DuplicateCluster é gerado da especificação OpenAPI
+type DuplicateCluster struct {
+	Agencies       int                       `json:"agencies,omitempty"` // Imobiliárias (domínios) que publicam o imóvel
+	Bairro         string                    `json:"bairro,omitempty"`
+	Cidade         string                    `json:"cidade,omitempty"`
+	Listings       []DuplicateClusterListing `json:"listings,omitempty"`
+	MaxValor       float64                   `json:"max_valor,omitempty"`
+	MinValor       float64                   `json:"min_valor,omitempty"`
+	PriceSpread    float64                   `json:"price_spread,omitempty"`     // Diferença entre o maior e o menor preço (R$)
+	PriceSpreadPct float64                   `json:"price_spread_pct,omitempty"` // Diferença percentual sobre o menor preço
+	Quartos        int                       `json:"quartos,omitempty"`
+	TipoImovel     string                    `json:"tipo_imovel,omitempty"`
+}
+
+// DuplicateClusterListing é gerado da especificação OpenAPI
+type DuplicateClusterListing struct {
+	Area     float64 `json:"area,omitempty"`
+	Domain   string  `json:"domain,omitempty"`
+	Endereco string  `json:"endereco,omitempty"`
+	URL      string  `json:"url,omitempty"`
+	Valor    float64 `json:"valor,omitempty"`
+}
+
+// Error é gerado da especificação OpenAPI
+type Error struct {
+	Code    int    `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ErrorReport é gerado da especificação OpenAPI
+type ErrorReport struct {
+	ByDomain map[string]map[string]int `json:"by_domain,omitempty"` // Domínio -> tipo de erro -> quantidade
+	ByType   map[string]int            `json:"by_type,omitempty"`
+	Samples  []CrawlError              `json:"samples,omitempty"`
+	Total    int                       `json:"total,omitempty"`
+}
+
+// FacetCount é gerado da especificação OpenAPI
+type FacetCount struct {
+	Count int64   `json:"count,omitempty"`
+	Max   float64 `json:"max,omitempty"` // Limite superior da faixa de preço (ausente na última faixa)
+	Min   float64 `json:"min,omitempty"` // Limite inferior (faixas de preço e quartos)
+	Value string  `json:"value,omitempty"`
+}
+
+// FeedStatus é gerado da especificação OpenAPI
+type FeedStatus struct {
+	Entries    int       `json:"entries,omitempty"` // Entradas novas encontradas na última leitura
+	LastError  string    `json:"last_error,omitempty"`
+	LastPolled time.Time `json:"last_polled,omitempty"`
+	URL        string    `json:"url,omitempty"`
+}
+
+// FieldProvenance é gerado da especificação OpenAPI
+type FieldProvenance struct {
+	ExtractedAt time.Time `json:"extracted_at,omitempty"`
+	Extractor   string    `json:"extractor,omitempty"`
+	Selector    string    `json:"selector,omitempty"`
+}
+
+// FraudReview: Par de anúncios com fotos idênticas e localização, endereço ou preço diferentes
+type FraudReview struct {
+	CreatedAt    time.Time            `json:"created_at,omitempty"`
+	ID           string               `json:"id,omitempty"`       // Derivado das URLs dos dois anúncios (estável entre varreduras)
+	Listings     []FraudReviewListing `json:"listings,omitempty"` // Os dois anúncios do par
+	Reasons      []string             `json:"reasons,omitempty"`
+	ResolvedAt   time.Time            `json:"resolved_at,omitempty"`
+	SharedPhotos []string             `json:"shared_photos,omitempty"` // Hashes perceptuais em comum
+	Status       string               `json:"status,omitempty"`
+	UpdatedAt    time.Time            `json:"updated_at,omitempty"`
+}
+
+// FraudReviewListing é gerado da especificação OpenAPI
+type FraudReviewListing struct {
+	Bairro   string  `json:"bairro,omitempty"`
+	Cidade   string  `json:"cidade,omitempty"`
+	Endereco string  `json:"endereco,omitempty"`
+	URL      string  `json:"url,omitempty"`
+	Valor    float64 `json:"valor,omitempty"`
+}
+
+// GlobalRateStats é gerado da especificação OpenAPI
+type GlobalRateStats struct {
+	Adjustments            int            `json:"adjustments,omitempty"`
+	DomainParallelism      int            `json:"domain_parallelism,omitempty"` // Requisições simultâneas permitidas por domínio
+	InFlight               map[string]int `json:"in_flight,omitempty"`          // Requisições em andamento por domínio
+	MaxDomainParallelism   int            `json:"max_domain_parallelism,omitempty"`
+	ObservedPagesPerMinute float64        `json:"observed_pages_per_minute,omitempty"` // Ritmo medido na última janela de ajuste
+	TargetPagesPerMinute   int            `json:"target_pages_per_minute,omitempty"`
+	TotalPages             int64          `json:"total_pages,omitempty"`
+}
+
+// ImportReport é gerado da especificação OpenAPI
+type ImportReport struct {
+	Duplicates int                 `json:"duplicates,omitempty"` // Registros repetidos dentro do próprio arquivo
+	Errors     []ImportReportError `json:"errors,omitempty"`     // Primeiros 100 registros rejeitados
+	Failed     int                 `json:"failed,omitempty"`
+	Format     string              `json:"format,omitempty"`
+	Imported   int                 `json:"imported,omitempty"`
+	Invalid    int                 `json:"invalid,omitempty"`
+	Source     string              `json:"source,omitempty"`
+	Total      int                 `json:"total,omitempty"`
+}
+
+// ImportReportError é gerado da especificação OpenAPI
+type ImportReportError struct {
+	Errors []string `json:"errors,omitempty"`
+	Line   int      `json:"line,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// Loteamento: Resumo do projeto de loteamento, presente apenas no registro pai (os lotes compartilham o parent_id)
+type Loteamento struct {
+	AreaMaxima  float64 `json:"area_maxima,omitempty"`
+	AreaMinima  float64 `json:"area_minima,omitempty"`
+	Nome        string  `json:"nome,omitempty"`
+	PrecoMaximo float64 `json:"preco_maximo,omitempty"` // Maior preço por lote
+	PrecoMinimo float64 `json:"preco_minimo,omitempty"` // Menor preço por lote
+	TotalLotes  int     `json:"total_lotes,omitempty"`
+}
+
+// Pagination é gerado da especificação OpenAPI
+type Pagination struct {
+	CurrentPage int  `json:"current_page,omitempty"`
+	HasNext     bool `json:"has_next,omitempty"`
+	HasPrevious bool `json:"has_previous,omitempty"`
+	PageSize    int  `json:"page_size,omitempty"`
+	TotalItems  int  `json:"total_items,omitempty"`
+	TotalPages  int  `json:"total_pages,omitempty"`
+}
+
+// PathStats é gerado da especificação OpenAPI
+type PathStats struct {
+	Crawls        int       `json:"crawls,omitempty"` // Crawlings completos em que o caminho foi visitado
+	Domain        string    `json:"domain,omitempty"`
+	Ignored       bool      `json:"ignored,omitempty"` // Removido da lista pelo operador; não é aprendido de novo
+	LastCrawledAt time.Time `json:"last_crawled_at,omitempty"`
+	Learned       bool      `json:"learned,omitempty"`
+	LearnedAt     time.Time `json:"learned_at,omitempty"`
+	NonProperty   int       `json:"non_property,omitempty"` // Páginas classificadas como não-anúncio
+	Path          string    `json:"path,omitempty"`
+	Productive    int       `json:"productive,omitempty"` // Anúncios encontrados no caminho ou a partir dele
+}
+
+// PatternFile é gerado da especificação OpenAPI
+type PatternFile struct {
+	Catalog       []map[string]interface{} `json:"catalog,omitempty"`
+	Checksum      string                   `json:"checksum,omitempty"` // SHA-256 dos padrões ordenados
+	ExportedAt    time.Time                `json:"exported_at,omitempty"`
+	Property      []map[string]interface{} `json:"property,omitempty"`
+	SchemaVersion int                      `json:"schema_version,omitempty"`
+}
+
+// PatternImportDiff é gerado da especificação OpenAPI
+type PatternImportDiff struct {
+	Added     []string `json:"added,omitempty"`
+	Changed   []string `json:"changed,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Unchanged int      `json:"unchanged,omitempty"`
+}
+
+// PatternImportPreview é gerado da especificação OpenAPI
+type PatternImportPreview struct {
+	Checksum        string                       `json:"checksum,omitempty"`
+	CurrentChecksum string                       `json:"current_checksum,omitempty"` // Checksum dos padrões ativos no momento da prévia
+	Diff            map[string]PatternImportDiff `json:"diff,omitempty"`
+	Replace         bool                         `json:"replace,omitempty"`
+	SchemaVersion   int                          `json:"schema_version,omitempty"`
+}
+
+// PendingPatternImport é gerado da especificação OpenAPI
+type PendingPatternImport struct {
+	CreatedAt time.Time             `json:"created_at,omitempty"`
+	ExpiresAt time.Time             `json:"expires_at,omitempty"`
+	ID        string                `json:"id,omitempty"`
+	Preview   *PatternImportPreview `json:"preview,omitempty"`
+}
+
+// PhotoFraudScan é gerado da especificação OpenAPI
+type PhotoFraudScan struct {
+	Flagged        int `json:"flagged,omitempty"`         // Anúncios marcados com suspeito_fraude
+	Groups         int `json:"groups,omitempty"`          // Pares de anúncios suspeitos encontrados
+	Listings       int `json:"listings,omitempty"`        // Anúncios com hashes de fotos comparados
+	PendingReviews int `json:"pending_reviews,omitempty"` // Itens aguardando revisão
+}
+
+// PriceIndexPoint é gerado da especificação OpenAPI
+type PriceIndexPoint struct {
+	Bairro           string    `json:"bairro,omitempty"` // Ausente no índice da cidade inteira
+	Cidade           string    `json:"cidade,omitempty"`
+	ComputedAt       time.Time `json:"computed_at,omitempty"`
+	MedianPricePerM2 float64   `json:"median_price_per_m2,omitempty"` // Mediana do valor por m² (R$)
+	Month            string    `json:"month,omitempty"`               // Mês de referência (AAAA-MM)
+	Samples          int       `json:"samples,omitempty"`             // Anúncios usados no cálculo
+}
+
+// Property é gerado da especificação OpenAPI
+type Property struct {
+	Amenidades        []string                    `json:"amenidades,omitempty"`      // Comodidades normalizadas usadas no filtro caracteristicas
+	AquecimentoGas    bool                        `json:"aquecimento_gas,omitempty"` // Aquecimento a gás citado no anúncio
+	ArchiveURL        string                      `json:"archive_url,omitempty"`
+	ArchivedAt        time.Time                   `json:"archived_at,omitempty"`
+	AreaTotal         float64                     `json:"area_total,omitempty"`
+	AreaUtil          float64                     `json:"area_util,omitempty"`
+	Bairro            string                      `json:"bairro,omitempty"`
+	Banheiros         int                         `json:"banheiros,omitempty"`
+	CambioData        time.Time                   `json:"cambio_data,omitempty"`  // Data das cotações usadas em valor_convertido
+	CanonicalID       string                      `json:"canonical_id,omitempty"` // Registro canônico do imóvel quando ele também é anunciado em outros portais
+	Caracteristicas   []string                    `json:"caracteristicas,omitempty"`
+	CEP               string                      `json:"cep,omitempty"`
+	Cidade            string                      `json:"cidade,omitempty"`
+	Condicao          string                      `json:"condicao,omitempty"`
+	CondicaoConfianca float64                     `json:"condicao_confianca,omitempty"` // Confiança da IA na avaliação de condição (0-1)
+	CondicaoNivel     int                         `json:"condicao_nivel,omitempty"`     // Valor ordinal da condição (1 = precisa reforma, 4 = novo)
+	CorrectedFields   []string                    `json:"corrected_fields,omitempty"`   // Campos corrigidos manualmente, mantidos nos próximos crawlings
+	CustomFields      map[string]interface{}      `json:"custom_fields,omitempty"`      // Campos adicionais preenchidos por etapas de extração customizadas ou scripts do domínio
+	DelistedAt        time.Time                   `json:"delisted_at,omitempty"`
+	Descricao         string                      `json:"descricao,omitempty"`
+	DescricaoEn       string                      `json:"descricao_en,omitempty"`     // Descrição traduzida para inglês (opcional)
+	DiscoveryDepth    int                         `json:"discovery_depth,omitempty"`  // Profundidade em que o anúncio foi descoberto a partir da URL inicial
+	DiscoveryPath     []PropertyDiscoveryPathItem `json:"discovery_path,omitempty"`   // Caminho de descoberta (seed → catálogo → página N → imóvel)
+	DiscoverySource   string                      `json:"discovery_source,omitempty"` // Estratégia de descoberta que levou ao anúncio
+	DistanciasPoi     *PropertyDistanciasPoi      `json:"distancias_poi,omitempty"`   // Distâncias em km até o centro da cidade e à escola e ao hospital mais próximos (OpenStreetMap), calculadas pelo backfill -poi para imóveis geocodificados
+	Endereco          string                      `json:"endereco,omitempty"`
+	EnergiaSolar      bool                        `json:"energia_solar,omitempty"`    // Energia solar citada no anúncio
+	FieldConfidence   map[string]float64          `json:"field_confidence,omitempty"` // Confiança de 0 a 1 por campo (somente com include=confidence)
+	Finalidade        string                      `json:"finalidade,omitempty"`       // Finalidade de uso pelo tipo e pelo texto do anúncio
+	FotoHashes        []string                    `json:"foto_hashes,omitempty"`      // Hashes perceptuais (dHash, hexadecimal) das primeiras fotos
+	FotoUrls          []string                    `json:"foto_urls,omitempty"`        // URLs das fotos do anúncio (até 30)
+	Fotos             int                         `json:"fotos,omitempty"`            // Quantidade de fotos da galeria do anúncio (imagens fora da galeria não contam)
+	Hash              string                      `json:"hash,omitempty"`             // Hash único da propriedade
+	ID                string                      `json:"id,omitempty"`
+	LanceMinimo       float64                     `json:"lance_minimo,omitempty"` // Lance mínimo informado no anúncio
+	Leilao            bool                        `json:"leilao,omitempty"`       // Anúncio de leilão (portal de leilões ou palavras-chave); fora das estatísticas de mercado
+	LeilaoData        time.Time                   `json:"leilao_data,omitempty"`  // Data do leilão (primeira praça citada)
+	ListingState      string                      `json:"listing_state,omitempty"`
+	Location          *PropertyLocation           `json:"location,omitempty"` // Localização geocodificada (GeoJSON Point, coordinates = [longitude, latitude])
+	Lote              string                      `json:"lote,omitempty"`     // Identificação do lote, nos registros filhos de um loteamento
+	Loteamento        *Loteamento                 `json:"loteamento,omitempty"`
+	ManuallyCorrected bool                        `json:"manually_corrected,omitempty"` // Imóvel com campos corrigidos manualmente (PATCH /properties/{id})
+	MinimoNoites      int                         `json:"minimo_noites,omitempty"`      // Estadia mínima do aluguel por temporada, em noites
+	ParentID          string                      `json:"parent_id,omitempty"`          // Identificador compartilhado pelas unidades de um anúncio com múltiplas unidades
+	PocoArtesiano     bool                        `json:"poco_artesiano,omitempty"`     // Poço artesiano ou semiartesiano citado no anúncio
+	Provenance        map[string]FieldProvenance  `json:"provenance,omitempty"`         // Etapa de extração que produziu cada campo (chave = nome do campo)
+	Quartos           int                         `json:"quartos,omitempty"`
+	RiscoGolpe        int                         `json:"risco_golpe,omitempty"`    // Risco de golpe de 0 a 100 (soma dos pesos dos sinais encontrados)
+	SchemaVersion     int                         `json:"schema_version,omitempty"` // Versão do formato do documento (documentos antigos são migrados na leitura)
+	SemFotos          bool                        `json:"sem_fotos,omitempty"`      // Anúncio sem nenhuma foto (sinal de baixa qualidade)
+	ShortID           string                      `json:"short_id,omitempty"`
+	SinaisGolpe       []string                    `json:"sinais_golpe,omitempty"`
+	Source            string                      `json:"source,omitempty"`           // Origem do registro
+	SourceFormat      string                      `json:"source_format,omitempty"`    // Formato do conteúdo de onde o anúncio foi extraído (ausente para páginas HTML)
+	SuspeitoFraude    bool                        `json:"suspeito_fraude,omitempty"`  // Usa as mesmas fotos de outro anúncio com endereço ou preço diferentes
+	SuspeitoGolpe     bool                        `json:"suspeito_golpe,omitempty"`   // Risco de golpe a partir de 50
+	Tags              []string                    `json:"tags,omitempty"`             // Tags dos jobs de crawling que salvaram ou encontraram novamente o imóvel
+	TemContato        bool                        `json:"tem_contato,omitempty"`      // A página do anúncio traz telefone, WhatsApp ou e-mail (ausente quando não verificado)
+	Testada           float64                     `json:"testada,omitempty"`          // Testada (metros de frente do imóvel)
+	TipoImovel        string                      `json:"tipo_imovel,omitempty"`      // Tipo como informado pelo site
+	TipoNormalizado   string                      `json:"tipo_normalizado,omitempty"` // Tipo convertido para a taxonomia usada nos filtros e estatísticas
+	URL               string                      `json:"url,omitempty"`
+	Valor             float64                     `json:"valor,omitempty"`
+	ValorAluguel      float64                     `json:"valor_aluguel,omitempty"`    // Aluguel mensal
+	ValorConvertido   map[string]float64          `json:"valor_convertido,omitempty"` // Preço na moeda pedida via ?currency= (cotação do dia do crawling)
+	ValorDiaria       float64                     `json:"valor_diaria,omitempty"`     // Valor da diária do aluguel por temporada
+	ValorM2           float64                     `json:"valor_m2,omitempty"`         // Preço por m² (valor / área útil, ou área total)
+	ValorTexto        string                      `json:"valor_texto,omitempty"`
+	ValorVenda        float64                     `json:"valor_venda,omitempty"` // Valor de venda (separado do aluguel quando o anúncio cita os dois)
+	Zoneamento        []string                    `json:"zoneamento,omitempty"`  // Indicações de zoneamento citadas (comercial, misto, residencial, industrial, zeis, corredor_comercial)
+}
+
+// PropertyDiscoveryPathItem é gerado da especificação OpenAPI
+type PropertyDiscoveryPathItem struct {
+	Kind string `json:"kind,omitempty"`
+	Page int    `json:"page,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// PropertyDistanciasPoi: Distâncias em km até o centro da cidade e à escola e ao hospital mais próximos (OpenStreetMap), calculadas pelo backfill -poi para imóveis geocodificados
+type PropertyDistanciasPoi struct {
+	CentroKm   float64 `json:"centro_km,omitempty"`
+	EscolaKm   float64 `json:"escola_km,omitempty"`
+	HospitalKm float64 `json:"hospital_km,omitempty"`
+}
+
+// PropertyLocation: Localização geocodificada (GeoJSON Point, coordinates = [longitude, latitude])
+type PropertyLocation struct {
+	Coordinates []float64 `json:"coordinates,omitempty"`
+	Type        string    `json:"type,omitempty"`
+}
+
+// PropertyCorrection é gerado da especificação OpenAPI
+type PropertyCorrection struct {
+	Changes     []PropertyCorrectionChange `json:"changes,omitempty"`
+	CorrectedAt time.Time                  `json:"corrected_at,omitempty"`
+	CorrectedBy string                     `json:"corrected_by,omitempty"`
+	ID          string                     `json:"id,omitempty"`
+	PropertyID  string                     `json:"property_id,omitempty"`
+	Reason      string                     `json:"reason,omitempty"`
+	URL         string                     `json:"url,omitempty"`
+}
+
+// PropertyCorrectionChange é gerado da especificação OpenAPI
+type PropertyCorrectionChange struct {
+	Field    string      `json:"field,omitempty"`
+	NewValue interface{} `json:"new_value,omitempty"`
+	OldValue interface{} `json:"old_value,omitempty"`
+}
+
+// PropertyFacets é gerado da especificação OpenAPI
+type PropertyFacets struct {
+	Bairros     []FacetCount `json:"bairros,omitempty"`
+	Cidade      string       `json:"cidade,omitempty"`
+	FaixasPreco []FacetCount `json:"faixas_preco,omitempty"`
+	Finalidades []FacetCount `json:"finalidades,omitempty"` // Finalidade de uso (residencial, comercial, rural, temporada)
+	Quartos     []FacetCount `json:"quartos,omitempty"`     // Número de quartos; o último grupo ("5+") reúne 5 ou mais
+	TiposImovel []FacetCount `json:"tipos_imovel,omitempty"`
+	Total       int64        `json:"total,omitempty"`
+}
+
+// PruneResult é gerado da especificação OpenAPI
+type PruneResult struct {
+	FingerprintsRemoved int64     `json:"fingerprints_removed,omitempty"`
+	RanAt               time.Time `json:"ran_at,omitempty"`
+	UrlsRemoved         int64     `json:"urls_removed,omitempty"`
+}
+
+// RequestAuditEntry é gerado da especificação OpenAPI
+type RequestAuditEntry struct {
+	Bytes      int64     `json:"bytes,omitempty"` // Bytes do corpo lidos
+	Domain     string    `json:"domain,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	JobID      string    `json:"job_id,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"` // 0 quando a requisição falhou sem resposta
+	Time       time.Time `json:"time,omitempty"`
+	URL        string    `json:"url,omitempty"`
+}
+
+// RequestAuditSummary é gerado da especificação OpenAPI
+type RequestAuditSummary struct {
+	AvgDurationMs     int64     `json:"avg_duration_ms,omitempty"`
+	Bytes             int64     `json:"bytes,omitempty"`
+	Domain            string    `json:"domain,omitempty"`
+	Errors            int       `json:"errors,omitempty"` // Falhas de rede e respostas com status >= 400
+	FirstRequest      time.Time `json:"first_request,omitempty"`
+	LastRequest       time.Time `json:"last_request,omitempty"`
+	MinIntervalMs     int64     `json:"min_interval_ms,omitempty"` // Menor intervalo entre duas requisições (-1 com uma única requisição)
+	Requests          int       `json:"requests,omitempty"`
+	RequestsPerMinute float64   `json:"requests_per_minute,omitempty"`
+}
+
+// SiteMigrationResult é gerado da especificação OpenAPI
+type SiteMigrationResult struct {
+	Cities        int                        `json:"cities,omitempty"`
+	DryRun        bool                       `json:"dry_run,omitempty"`
+	Files         []string                   `json:"files,omitempty"`
+	Groups        []SiteMigrationResultGroup `json:"groups,omitempty"`
+	SitesAdded    int                        `json:"sites_added,omitempty"`
+	SitesExisting int                        `json:"sites_existing,omitempty"`
+	Unresolved    []string                   `json:"unresolved,omitempty"` // URLs sem cidade deduzida
+	Urls          int                        `json:"urls,omitempty"`
+}
+
+// SiteMigrationResultGroup é gerado da especificação OpenAPI
+type SiteMigrationResultGroup struct {
+	City  *SiteMigrationResultGroupCity `json:"city,omitempty"`
+	Sites []CitySite                    `json:"sites,omitempty"`
+}
+
+// SiteMigrationResultGroupCity é gerado da especificação OpenAPI
+type SiteMigrationResultGroupCity struct {
+	Name   string `json:"name,omitempty"`
+	Region string `json:"region,omitempty"`
+	State  string `json:"state,omitempty"`
+}
+
+// UrlstorageStats é gerado da especificação OpenAPI
+type UrlstorageStats struct {
+	Fingerprints  *CollectionStorageStats `json:"fingerprints,omitempty"`
+	LastPrune     *PruneResult            `json:"last_prune,omitempty"`
+	ProcessedUrls *CollectionStorageStats `json:"processed_urls,omitempty"`
+}
+
+// UnitConversion é gerado da especificação OpenAPI
+type UnitConversion struct {
+	Formatted string  `json:"formatted,omitempty"`
+	From      string  `json:"from,omitempty"`
+	Input     string  `json:"input,omitempty"`
+	Result    float64 `json:"result,omitempty"`
+	To        string  `json:"to,omitempty"`
+	Value     float64 `json:"value,omitempty"`
+}
+
+// WhatsAppLead é gerado da especificação OpenAPI
+type WhatsAppLead struct {
+	AreaTotal   float64   `json:"area_total,omitempty"`
+	Bairro      string    `json:"bairro,omitempty"`
+	Banheiros   int       `json:"banheiros,omitempty"`
+	Cidade      string    `json:"cidade,omitempty"`
+	Codigo      string    `json:"codigo,omitempty"`     // Código de referência do anúncio citado na mensagem
+	Confidence  float64   `json:"confidence,omitempty"` // Confiança do lead (no máximo 0.5)
+	Domain      string    `json:"domain,omitempty"`
+	FirstSeenAt time.Time `json:"first_seen_at,omitempty"`
+	Hits        int       `json:"hits,omitempty"` // Vezes em que o link foi encontrado
+	ID          string    `json:"id,omitempty"`
+	LastSeenAt  time.Time `json:"last_seen_at,omitempty"`
+	PageURL     string    `json:"page_url,omitempty"` // Página onde o link foi encontrado
+	Phone       string    `json:"phone,omitempty"`
+	Quartos     int       `json:"quartos,omitempty"`
+	Text        string    `json:"text,omitempty"`
+	TipoImovel  string    `json:"tipo_imovel,omitempty"`
+	Valor       float64   `json:"valor,omitempty"`
+}
+
+// GetAllCitiesResponse é gerado da especificação OpenAPI
+type GetAllCitiesResponse struct {
+	Data    []City `json:"data,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// DiscoverSitesRequest é gerado da especificação OpenAPI
+type DiscoverSitesRequest struct {
+	City     string `json:"city,omitempty"`     // Nome da cidade
+	Limit    int    `json:"limit,omitempty"`    // Limite de sites a descobrir
+	Validate bool   `json:"validate,omitempty"` // Validar sites descobertos
+}
+
+// DiscoverSitesResponse é gerado da especificação OpenAPI
+type DiscoverSitesResponse struct {
+	Data    *DiscoverSitesResponseData `json:"data,omitempty"`
+	Message string                     `json:"message,omitempty"`
+}
+
+// DiscoverSitesResponseData é gerado da especificação OpenAPI
+type DiscoverSitesResponseData struct {
+	City       string     `json:"city,omitempty"`
+	JobID      string     `json:"job_id,omitempty"`
+	Sites      []CitySite `json:"sites,omitempty"`
+	SitesFound int        `json:"sites_found,omitempty"`
+}
+
+// ImportCitySitesResponse é gerado da especificação OpenAPI
+type ImportCitySitesResponse struct {
+	Data    *CitySitesImportResult `json:"data,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// MigrateSiteFilesRequest é gerado da especificação OpenAPI
+type MigrateSiteFilesRequest struct {
+	DefaultCity  string   `json:"default_city,omitempty"` // Cidade dos domínios sem cidade deduzida (vazio os lista em unresolved)
+	DefaultState string   `json:"default_state,omitempty"`
+	DryRun       bool     `json:"dry_run,omitempty"` // Apenas calcula o resultado, sem gravar
+	Files        []string `json:"files,omitempty"`   // Arquivos relativos ao diretório do projeto
+}
+
+// MigrateSiteFilesResponse é gerado da especificação OpenAPI
+type MigrateSiteFilesResponse struct {
+	Data    *SiteMigrationResult `json:"data,omitempty"`
+	Message string               `json:"message,omitempty"`
+}
+
+// GetCityByNameResponse é gerado da especificação OpenAPI
+type GetCityByNameResponse struct {
+	Data    *City  `json:"data,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetCitySitesResponse é gerado da especificação OpenAPI
+type GetCitySitesResponse struct {
+	Data    []CitySite `json:"data,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+// SetCityRefreshSLARequest é gerado da especificação OpenAPI
+type SetCityRefreshSLARequest struct {
+	SLAHours int `json:"sla_hours,omitempty"`
+}
+
+// SetCityRefreshSLAResponse é gerado da especificação OpenAPI
+type SetCityRefreshSLAResponse struct {
+	Data    *CityFreshness `json:"data,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// GetAitokenSavingsResponse é gerado da especificação OpenAPI
+type GetAitokenSavingsResponse struct {
+	Data    *AitokenSavings `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// GetBrokenPhotosResponse é gerado da especificação OpenAPI
+type GetBrokenPhotosResponse struct {
+	Data    []BrokenPhoto `json:"data,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// GetClassifierExperimentsResponse é gerado da especificação OpenAPI
+type GetClassifierExperimentsResponse struct {
+	Data    []ClassifierExperimentReport `json:"data,omitempty"`
+	Message string                       `json:"message,omitempty"`
+}
+
+// CleanupDatabaseRequest é gerado da especificação OpenAPI
+type CleanupDatabaseRequest struct {
+	All        bool `json:"all,omitempty"`        // Limpar tudo
+	Properties bool `json:"properties,omitempty"` // Limpar propriedades
+	Urls       bool `json:"urls,omitempty"`       // Limpar URLs visitadas
+}
+
+// CleanupDatabaseResponse é gerado da especificação OpenAPI
+type CleanupDatabaseResponse struct {
+	Message           string `json:"message,omitempty"`
+	PropertiesCleared bool   `json:"properties_cleared,omitempty"`
+	Success           bool   `json:"success,omitempty"`
+	UrlsCleared       bool   `json:"urls_cleared,omitempty"`
+}
+
+// GetConfigChangesResponse é gerado da especificação OpenAPI
+type GetConfigChangesResponse struct {
+	Data    []ConfigChange `json:"data,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// GetCrawlJobCostsResponse é gerado da especificação OpenAPI
+type GetCrawlJobCostsResponse struct {
+	Data    *GetCrawlJobCostsResponseData `json:"data,omitempty"`
+	Message string                        `json:"message,omitempty"`
+}
+
+// GetCrawlJobCostsResponseData é gerado da especificação OpenAPI
+type GetCrawlJobCostsResponseData struct {
+	ByMode []CrawlCostSummary `json:"by_mode,omitempty"`
+	Jobs   []CrawlJobCost     `json:"jobs,omitempty"`
+}
+
+// GetCoverageResponse é gerado da especificação OpenAPI
+type GetCoverageResponse struct {
+	Data    []DomainCoverage `json:"data,omitempty"`
+	Message string           `json:"message,omitempty"`
+}
+
+// GetDiscoverySourcesResponse é gerado da especificação OpenAPI
+type GetDiscoverySourcesResponse struct {
+	Data    []DiscoverySourceYield `json:"data,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// GetDnscacheResponse é gerado da especificação OpenAPI
+type GetDnscacheResponse struct {
+	Data    *DnscacheStats `json:"data,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// GetDomainRatesResponse é gerado da especificação OpenAPI
+type GetDomainRatesResponse struct {
+	Data    []DomainRateStats `json:"data,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// GetCrawlErrorsResponse é gerado da especificação OpenAPI
+type GetCrawlErrorsResponse struct {
+	Data    *ErrorReport `json:"data,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// GetFeedStatusResponse é gerado da especificação OpenAPI
+type GetFeedStatusResponse struct {
+	Data    *GetFeedStatusResponseData `json:"data,omitempty"`
+	Message string                     `json:"message,omitempty"`
+}
+
+// GetFeedStatusResponseData é gerado da especificação OpenAPI
+type GetFeedStatusResponseData struct {
+	Feeds   []FeedStatus `json:"feeds,omitempty"`
+	Pending int          `json:"pending,omitempty"` // Anúncios aguardando crawling
+}
+
+// GetCrawlJobsResponse é gerado da especificação OpenAPI
+type GetCrawlJobsResponse struct {
+	Data    []CrawlJob `json:"data,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+// TriggerCrawlerRequest é gerado da especificação OpenAPI
+type TriggerCrawlerRequest struct {
+	Cities []string `json:"cities,omitempty"`
+	Mode   string   `json:"mode,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// TriggerCrawlerResponse é gerado da especificação OpenAPI
+type TriggerCrawlerResponse struct {
+	Data    *TriggerCrawlerResponseData `json:"data,omitempty"`
+	Message string                      `json:"message,omitempty"`
+}
+
+// TriggerCrawlerResponseData é gerado da especificação OpenAPI
+type TriggerCrawlerResponseData struct {
+	JobID  string `json:"job_id,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// GetCrawlJobResponse é gerado da especificação OpenAPI
+type GetCrawlJobResponse struct {
+	Data    *CrawlJob `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// CancelCrawlJobResponse é gerado da especificação OpenAPI
+type CancelCrawlJobResponse struct {
+	Data    *CrawlJob `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// PauseCrawlJobDomainResponse é gerado da especificação OpenAPI
+type PauseCrawlJobDomainResponse struct {
+	Data    *CrawlJob `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// ResumeCrawlJobDomainResponse é gerado da especificação OpenAPI
+type ResumeCrawlJobDomainResponse struct {
+	Data    *CrawlJob `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// GetLeaderStatusResponse é gerado da especificação OpenAPI
+type GetLeaderStatusResponse struct {
+	Data    *GetLeaderStatusResponseData `json:"data,omitempty"`
+	Message string                       `json:"message,omitempty"`
+}
+
+// GetLeaderStatusResponseData é gerado da especificação OpenAPI
+type GetLeaderStatusResponseData struct {
+	Enabled     bool                              `json:"enabled,omitempty"`
+	Identity    string                            `json:"identity,omitempty"`
+	LastError   string                            `json:"last_error,omitempty"`
+	Leader      bool                              `json:"leader,omitempty"`
+	LeaderSince time.Time                         `json:"leader_since,omitempty"`
+	Lease       *GetLeaderStatusResponseDataLease `json:"lease,omitempty"`
+}
+
+// GetLeaderStatusResponseDataLease é gerado da especificação OpenAPI
+type GetLeaderStatusResponseDataLease struct {
+	AcquiredAt time.Time `json:"acquired_at,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at,omitempty"`
+	Holder     string    `json:"holder,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	RenewedAt  time.Time `json:"renewed_at,omitempty"`
+}
+
+// GetGlobalRateResponse é gerado da especificação OpenAPI
+type GetGlobalRateResponse struct {
+	Data    *GlobalRateStats `json:"data,omitempty"`
+	Message string           `json:"message,omitempty"`
+}
+
+// GetRequestAuditsResponse é gerado da especificação OpenAPI
+type GetRequestAuditsResponse struct {
+	Data    []RequestAuditEntry `json:"data,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
+
+// GetRequestAuditSummaryResponse é gerado da especificação OpenAPI
+type GetRequestAuditSummaryResponse struct {
+	Data    []RequestAuditSummary `json:"data,omitempty"`
+	Message string                `json:"message,omitempty"`
+}
+
+// GetJobTemplatesResponse é gerado da especificação OpenAPI
+type GetJobTemplatesResponse struct {
+	Data    []CrawlJobTemplate `json:"data,omitempty"`
+	Message string             `json:"message,omitempty"`
+}
+
+// GetJobTemplateResponse é gerado da especificação OpenAPI
+type GetJobTemplateResponse struct {
+	Data    *CrawlJobTemplate `json:"data,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// SaveJobTemplateRequest é gerado da especificação OpenAPI
+type SaveJobTemplateRequest struct {
+	Cities             []string `json:"cities,omitempty"`
+	Description        string   `json:"description,omitempty"`
+	Domains            []string `json:"domains,omitempty"`
+	EnableAI           bool     `json:"enable_ai,omitempty"`
+	MaxDurationMinutes int      `json:"max_duration_minutes,omitempty"`
+	MaxPages           int      `json:"max_pages,omitempty"`
+	Mode               string   `json:"mode,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+// SaveJobTemplateResponse é gerado da especificação OpenAPI
+type SaveJobTemplateResponse struct {
+	Data    *CrawlJobTemplate `json:"data,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// RunJobTemplateRequest é gerado da especificação OpenAPI
+type RunJobTemplateRequest struct {
+	Cities             []string `json:"cities,omitempty"`
+	Domains            []string `json:"domains,omitempty"`
+	EnableAI           bool     `json:"enable_ai,omitempty"`
+	MaxDurationMinutes int      `json:"max_duration_minutes,omitempty"`
+	MaxPages           int      `json:"max_pages,omitempty"`
+	Mode               string   `json:"mode,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+// RunJobTemplateResponse é gerado da especificação OpenAPI
+type RunJobTemplateResponse struct {
+	Data    *CrawlJob `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// PostCrawlerTriggerRequest é gerado da especificação OpenAPI
+type PostCrawlerTriggerRequest struct {
+	Cities   []string `json:"cities,omitempty"`    // Lista de cidades para crawling
+	Force    bool     `json:"force,omitempty"`     // Forçar recrawling de URLs já visitadas
+	MaxPages int      `json:"max_pages,omitempty"` // Máximo de páginas por site
+	Tags     []string `json:"tags,omitempty"`      // Tags registradas em cada imóvel salvo ou encontrado novamente pelo job (minúsculas, letras, dígitos, '.', '_', ':' ou '-'; até 10). Também aceitas na query, `?tag=muzambinho-weekly`
+}
+
+// PostCrawlerTriggerResponse é gerado da especificação OpenAPI
+type PostCrawlerTriggerResponse struct {
+	Data    *PostCrawlerTriggerResponseData `json:"data,omitempty"`
+	Message string                          `json:"message,omitempty"`
+}
+
+// PostCrawlerTriggerResponseData é gerado da especificação OpenAPI
+type PostCrawlerTriggerResponseData struct {
+	Cities            []string `json:"cities,omitempty"`
+	EstimatedDuration string   `json:"estimated_duration,omitempty"`
+	JobID             string   `json:"job_id,omitempty"` // ID do job para consulta, cancelamento e pausa
+	Status            string   `json:"status,omitempty"`
+}
+
+// GetUrlstorageStatsResponse é gerado da especificação OpenAPI
+type GetUrlstorageStatsResponse struct {
+	Data    *UrlstorageStats `json:"data,omitempty"`
+	Message string           `json:"message,omitempty"`
+}
+
+// PruneUrlstorageResponse é gerado da especificação OpenAPI
+type PruneUrlstorageResponse struct {
+	Data    *PruneResult `json:"data,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// ExplainClassificationResponse é gerado da especificação OpenAPI
+type ExplainClassificationResponse struct {
+	Data    *ClassificationExplanation `json:"data,omitempty"`
+	Message string                     `json:"message,omitempty"`
+}
+
+// ProbeDomainResponse é gerado da especificação OpenAPI
+type ProbeDomainResponse struct {
+	Data    *DomainProbe `json:"data,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// GetAllDomainConfigsResponse é gerado da especificação OpenAPI
+type GetAllDomainConfigsResponse struct {
+	Data    []DomainConfig `json:"data,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// GetDomainConfigResponse é gerado da especificação OpenAPI
+type GetDomainConfigResponse struct {
+	Data    *DomainConfig `json:"data,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// SaveDomainConfigResponse é gerado da especificação OpenAPI
+type SaveDomainConfigResponse struct {
+	Data    *DomainConfig `json:"data,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// GetSkipPathsResponse é gerado da especificação OpenAPI
+type GetSkipPathsResponse struct {
+	Data    *GetSkipPathsResponseData `json:"data,omitempty"`
+	Message string                    `json:"message,omitempty"`
+}
+
+// GetSkipPathsResponseData é gerado da especificação OpenAPI
+type GetSkipPathsResponseData struct {
+	Domain           string      `json:"domain,omitempty"`
+	LearnedSkipPaths []string    `json:"learned_skip_paths,omitempty"`
+	Paths            []PathStats `json:"paths,omitempty"`
+}
+
+// GetFraudReviewsResponse é gerado da especificação OpenAPI
+type GetFraudReviewsResponse struct {
+	Data    []FraudReview `json:"data,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// ResolveFraudReviewRequest é gerado da especificação OpenAPI
+type ResolveFraudReviewRequest struct {
+	Status string `json:"status,omitempty"`
+}
+
+// ResolveFraudReviewResponse é gerado da especificação OpenAPI
+type ResolveFraudReviewResponse struct {
+	Data    *FraudReview `json:"data,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// DetectPhotoFraudResponse é gerado da especificação OpenAPI
+type DetectPhotoFraudResponse struct {
+	Data    *PhotoFraudScan `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// GetHealthResponse é gerado da especificação OpenAPI
+type GetHealthResponse struct {
+	Features []string                  `json:"features,omitempty"`
+	Service  string                    `json:"service,omitempty"`
+	Status   string                    `json:"status,omitempty"`
+	Storage  *GetHealthResponseStorage `json:"storage,omitempty"` // Estado da conexão com o MongoDB (heartbeats do driver)
+	Version  string                    `json:"version,omitempty"`
+}
+
+// GetHealthResponseStorage: Estado da conexão com o MongoDB (heartbeats do driver)
+type GetHealthResponseStorage struct {
+	DownSince         time.Time       `json:"down_since,omitempty"`
+	FailedOperations  int             `json:"failed_operations,omitempty"`
+	LastError         string          `json:"last_error,omitempty"`
+	LastErrorAt       time.Time       `json:"last_error_at,omitempty"`
+	LastHeartbeat     time.Time       `json:"last_heartbeat,omitempty"`
+	Reconnects        int             `json:"reconnects,omitempty"`
+	RetriedOperations int             `json:"retried_operations,omitempty"`
+	Servers           map[string]bool `json:"servers,omitempty"`
+	Status            string          `json:"status,omitempty"`
+}
+
+// GetWhatsAppLeadsResponse é gerado da especificação OpenAPI
+type GetWhatsAppLeadsResponse struct {
+	Data    []WhatsAppLead `json:"data,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// GetPropertyByShortIDResponse é gerado da especificação OpenAPI
+type GetPropertyByShortIDResponse struct {
+	Data    *Property `json:"data,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// GetLearnedPatternsResponse é gerado da especificação OpenAPI
+type GetLearnedPatternsResponse struct {
+	Data    *GetLearnedPatternsResponseData `json:"data,omitempty"`
+	Message string                          `json:"message,omitempty"`
+}
+
+// GetLearnedPatternsResponseData é gerado da especificação OpenAPI
+type GetLearnedPatternsResponseData struct {
+	CatalogPatternsCount  int                    `json:"catalog_patterns_count,omitempty"`
+	Patterns              map[string]interface{} `json:"patterns,omitempty"`
+	PropertyPatternsCount int                    `json:"property_patterns_count,omitempty"`
+}
+
+// ImportPatternsResponse é gerado da especificação OpenAPI
+type ImportPatternsResponse struct {
+	Data    *PatternImportPreview `json:"data,omitempty"`
+	Message string                `json:"message,omitempty"`
+}
+
+// GetPendingPatternImportsResponse é gerado da especificação OpenAPI
+type GetPendingPatternImportsResponse struct {
+	Data []PendingPatternImport `json:"data,omitempty"`
+}
+
+// GetPropertiesResponse é gerado da especificação OpenAPI
+type GetPropertiesResponse struct {
+	Data       []Property  `json:"data,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// GetCanonicalPropertiesResponse é gerado da especificação OpenAPI
+type GetCanonicalPropertiesResponse struct {
+	Data    []CanonicalProperty `json:"data,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
+
+// MergeDuplicatesResponse é gerado da especificação OpenAPI
+type MergeDuplicatesResponse struct {
+	Data    *CanonicalMergeResult `json:"data,omitempty"`
+	Message string                `json:"message,omitempty"`
+}
+
+// GetCanonicalPropertyResponse é gerado da especificação OpenAPI
+type GetCanonicalPropertyResponse struct {
+	Data    *CanonicalProperty `json:"data,omitempty"`
+	Message string             `json:"message,omitempty"`
+}
+
+// GetPropertyFacetsResponse é gerado da especificação OpenAPI
+type GetPropertyFacetsResponse struct {
+	Data    *PropertyFacets `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// ImportPropertiesResponse é gerado da especificação OpenAPI
+type ImportPropertiesResponse struct {
+	Data    *ImportReport `json:"data,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// SearchPropertiesResponse é gerado da especificação OpenAPI
+type SearchPropertiesResponse struct {
+	Data       []Property `json:"data,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	TotalFound int        `json:"total_found,omitempty"`
+}
+
+// PatchPropertyRequest é gerado da especificação OpenAPI
+type PatchPropertyRequest struct {
+	AreaTotal       float64  `json:"area_total,omitempty"`
+	AreaUtil        float64  `json:"area_util,omitempty"`
+	Bairro          string   `json:"bairro,omitempty"`
+	Banheiros       int      `json:"banheiros,omitempty"`
+	Caracteristicas []string `json:"caracteristicas,omitempty"`
+	CEP             string   `json:"cep,omitempty"`
+	Cidade          string   `json:"cidade,omitempty"`
+	CorrectedBy     string   `json:"corrected_by,omitempty"`
+	Descricao       string   `json:"descricao,omitempty"`
+	Endereco        string   `json:"endereco,omitempty"`
+	Quartos         int      `json:"quartos,omitempty"`
+	Reason          string   `json:"reason,omitempty"`
+	TipoImovel      string   `json:"tipo_imovel,omitempty"`
+	Valor           float64  `json:"valor,omitempty"`
+}
+
+// PatchPropertyResponse é gerado da especificação OpenAPI
+type PatchPropertyResponse struct {
+	Data    *PatchPropertyResponseData `json:"data,omitempty"`
+	Message string                     `json:"message,omitempty"`
+}
+
+// PatchPropertyResponseData é gerado da especificação OpenAPI
+type PatchPropertyResponseData struct {
+	Correction *PropertyCorrection `json:"correction,omitempty"`
+	Property   *Property           `json:"property,omitempty"`
+}
+
+// GetPropertyCorrectionsResponse é gerado da especificação OpenAPI
+type GetPropertyCorrectionsResponse struct {
+	Data    []PropertyCorrection `json:"data,omitempty"`
+	Message string               `json:"message,omitempty"`
+}
+
+// GetDuplicateReportResponse é gerado da especificação OpenAPI
+type GetDuplicateReportResponse struct {
+	Data    []DuplicateCluster `json:"data,omitempty"`
+	Message string             `json:"message,omitempty"`
+}
+
+// GetPriceIndexResponse é gerado da especificação OpenAPI
+type GetPriceIndexResponse struct {
+	Data    []PriceIndexPoint `json:"data,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// GetQualityMetricsResponse é gerado da especificação OpenAPI
+type GetQualityMetricsResponse struct {
+	Data    *GetQualityMetricsResponseData `json:"data,omitempty"`
+	Message string                         `json:"message,omitempty"`
+}
+
+// GetQualityMetricsResponseData é gerado da especificação OpenAPI
+type GetQualityMetricsResponseData struct {
+	Domains []DomainQuality `json:"domains,omitempty"`
+	Trend   []DomainQuality `json:"trend,omitempty"`
+}
+
+// ConvertUnitsResponse é gerado da especificação OpenAPI
+type ConvertUnitsResponse struct {
+	Data    *UnitConversion `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// GetAllCitiesParams são os parâmetros de query e cabeçalho de GetAllCities (valores zero não são enviados)
+type GetAllCitiesParams struct {
+	Stale bool // stale: Retorna só as cidades desatualizadas além do SLA
+}
+
+// ExportCitySitesParams são os parâmetros de query e cabeçalho de ExportCitySites (valores zero não são enviados)
+type ExportCitySitesParams struct {
+	Format string // format
+}
+
+// ImportCitySitesParams são os parâmetros de query e cabeçalho de ImportCitySites (valores zero não são enviados)
+type ImportCitySitesParams struct {
+	Replace bool // replace: Substitui os sites das cidades do arquivo em vez de juntar
+	DryRun  bool // dry_run: Apenas calcula o resultado, sem gravar
+}
+
+// GetBairroBoundariesParams são os parâmetros de query e cabeçalho de GetBairroBoundaries (valores zero não são enviados)
+type GetBairroBoundariesParams struct {
+	MinListings int // min_listings: Mínimo de anúncios geocodificados para o bairro entrar no mapa
+}
+
+// SetCityRefreshSLAParams são os parâmetros de query e cabeçalho de SetCityRefreshSLA (valores zero não são enviados)
+type SetCityRefreshSLAParams struct {
+	State string // state
+}
+
+// GetClassifierExperimentsParams são os parâmetros de query e cabeçalho de GetClassifierExperiments (valores zero não são enviados)
+type GetClassifierExperimentsParams struct {
+	JobID string // job_id: Filtrar por um job
+}
+
+// GetConfigChangesParams são os parâmetros de query e cabeçalho de GetConfigChanges (valores zero não são enviados)
+type GetConfigChangesParams struct {
+	Limit int // limit: Máximo de alterações (padrão 100, até 500)
+}
+
+// GetCrawlJobCostsParams são os parâmetros de query e cabeçalho de GetCrawlJobCosts (valores zero não são enviados)
+type GetCrawlJobCostsParams struct {
+	Mode  string // mode: Filtrar pelo modo do job (full, incremental)
+	Days  int    // days: Apenas jobs terminados nos últimos N dias (1 a 365)
+	Limit int    // limit: Quantidade máxima de jobs (padrão 100, máximo 1000)
+}
+
+// GetDiscoverySourcesParams são os parâmetros de query e cabeçalho de GetDiscoverySources (valores zero não são enviados)
+type GetDiscoverySourcesParams struct {
+	Domain string // domain
+	Crawls int    // crawls: Número de crawlings considerados (padrão 10, máximo 100)
+}
+
+// GetCrawlErrorsParams são os parâmetros de query e cabeçalho de GetCrawlErrors (valores zero não são enviados)
+type GetCrawlErrorsParams struct {
+	Type   string // type: Filtrar por tipo de erro
+	Domain string // domain: Filtrar por domínio
+	Limit  int    // limit: Quantidade de amostras recentes (padrão 50, máximo 500)
+}
+
+// TriggerCrawlerParams são os parâmetros de query e cabeçalho de TriggerCrawler (valores zero não são enviados)
+type TriggerCrawlerParams struct {
+	IdempotencyKey string // cabeçalho Idempotency-Key: Chave única do disparo (até 255 caracteres). Repetida dentro de CRAWL_IDEMPOTENCY_WINDOW (padrão 24h), retorna o job original sem iniciar outro crawling, com o cabeçalho `Idempotent-Replayed: true`, inclusive em outra réplica. A mesma chave com outros parâmetros retorna 422; enquanto outra requisição cria o job, 409.
+}
+
+// PauseCrawlJobDomainParams são os parâmetros de query e cabeçalho de PauseCrawlJobDomain (valores zero não são enviados)
+type PauseCrawlJobDomainParams struct {
+	Domain string // domain
+}
+
+// ResumeCrawlJobDomainParams são os parâmetros de query e cabeçalho de ResumeCrawlJobDomain (valores zero não são enviados)
+type ResumeCrawlJobDomainParams struct {
+	Domain string // domain
+}
+
+// GetRequestAuditsParams são os parâmetros de query e cabeçalho de GetRequestAudits (valores zero não são enviados)
+type GetRequestAuditsParams struct {
+	JobID  string // job_id
+	Domain string // domain
+	Status int    // status: Código HTTP da resposta
+	Since  string // since: AAAA-MM-DD (início do dia) ou RFC 3339
+	Until  string // until: AAAA-MM-DD (fim do dia) ou RFC 3339
+	Limit  int    // limit
+}
+
+// GetRequestAuditSummaryParams são os parâmetros de query e cabeçalho de GetRequestAuditSummary (valores zero não são enviados)
+type GetRequestAuditSummaryParams struct {
+	JobID  string // job_id
+	Domain string // domain
+	Status int    // status: Código HTTP da resposta
+	Since  string // since: AAAA-MM-DD (início do dia) ou RFC 3339
+	Until  string // until: AAAA-MM-DD (fim do dia) ou RFC 3339
+	Limit  int    // limit
+}
+
+// RunJobTemplateParams são os parâmetros de query e cabeçalho de RunJobTemplate (valores zero não são enviados)
+type RunJobTemplateParams struct {
+	IdempotencyKey string   // cabeçalho Idempotency-Key: Chave única do disparo (até 255 caracteres). Repetida dentro de CRAWL_IDEMPOTENCY_WINDOW (padrão 24h), retorna o job original sem iniciar outro crawling, com o cabeçalho `Idempotent-Replayed: true`, inclusive em outra réplica. A mesma chave com outros parâmetros retorna 422; enquanto outra requisição cria o job, 409.
+	Tag            []string // tag
+}
+
+// PostCrawlerTriggerParams são os parâmetros de query e cabeçalho de PostCrawlerTrigger (valores zero não são enviados)
+type PostCrawlerTriggerParams struct {
+	IdempotencyKey string // cabeçalho Idempotency-Key: Chave única do disparo (até 255 caracteres). Repetida dentro de CRAWL_IDEMPOTENCY_WINDOW (padrão 24h), retorna o job original sem iniciar outro crawling, com o cabeçalho `Idempotent-Replayed: true`, inclusive em outra réplica. A mesma chave com outros parâmetros retorna 422; enquanto outra requisição cria o job, 409.
+}
+
+// ExplainClassificationParams são os parâmetros de query e cabeçalho de ExplainClassification (valores zero não são enviados)
+type ExplainClassificationParams struct {
+	URL    string // url: URL http(s) da página
+	Engine string // engine: Engine da decisão final (recursive, usado por /crawler/trigger; improved; ai_integrated)
+}
+
+// ProbeDomainParams são os parâmetros de query e cabeçalho de ProbeDomain (valores zero não são enviados)
+type ProbeDomainParams struct {
+	Domain string // domain: Domínio ou URL
+}
+
+// DeleteSkipPathParams são os parâmetros de query e cabeçalho de DeleteSkipPath (valores zero não são enviados)
+type DeleteSkipPathParams struct {
+	Path string // path
+}
+
+// GetFraudReviewsParams são os parâmetros de query e cabeçalho de GetFraudReviews (valores zero não são enviados)
+type GetFraudReviewsParams struct {
+	Status string // status: Filtra pela situação (todas se omitido)
+	Limit  int    // limit: Máximo de itens retornados (padrão 100, máximo 500)
+}
+
+// GetWhatsAppLeadsParams são os parâmetros de query e cabeçalho de GetWhatsAppLeads (valores zero não são enviados)
+type GetWhatsAppLeadsParams struct {
+	Domain string // domain
+	Cidade string // cidade
+	Limit  int    // limit: Máximo de leads retornados (padrão 100, máximo 1000)
+}
+
+// GetPropertyByShortIDParams são os parâmetros de query e cabeçalho de GetPropertyByShortID (valores zero não são enviados)
+type GetPropertyByShortIDParams struct {
+	Format string // format: json para retornar o imóvel em vez de redirecionar
+}
+
+// ImportPatternsParams são os parâmetros de query e cabeçalho de ImportPatterns (valores zero não são enviados)
+type ImportPatternsParams struct {
+	DryRun bool // dry_run
+}
+
+// GetPropertiesParams são os parâmetros de query e cabeçalho de GetProperties (valores zero não são enviados)
+type GetPropertiesParams struct {
+	Include    string  // include: Campos opcionais na resposta, separados por vírgula (confidence = field_confidence)
+	Currency   string  // currency: Inclui o preço convertido para a moeda (valor_convertido e cambio_data). Requer EXCHANGE_RATES_URL; imóveis salvos antes da conversão usam as cotações do dia.
+	AsOf       string  // as_of: Retorna o acervo como estava na data: para cada anúncio (URL), a versão mais recente gravada até então; as unidades de um anúncio dividido (quartos, lotes) têm cada uma a sua. Aceita AAAA-MM-DD (até o fim do dia, UTC) ou RFC 3339.
+	Page       int     // page: Número da página (padrão 1)
+	PageSize   int     // page_size: Itens por página (padrão 10, máximo 100)
+	Cidade     string  // cidade: Filtrar por cidade
+	TipoImovel string  // tipo_imovel: Filtrar por tipo de imóvel (tipo informado pelo site ou da taxonomia)
+	ValorMin   float64 // valor_min: Valor mínimo
+	ValorMax   float64 // valor_max: Valor máximo
+}
+
+// GetCanonicalPropertiesParams são os parâmetros de query e cabeçalho de GetCanonicalProperties (valores zero não são enviados)
+type GetCanonicalPropertiesParams struct {
+	City  string // city: Cidade (todas se omitida)
+	Limit int    // limit: Máximo de registros (padrão 100, máximo 500)
+}
+
+// MergeDuplicatesParams são os parâmetros de query e cabeçalho de MergeDuplicates (valores zero não são enviados)
+type MergeDuplicatesParams struct {
+	City                   string  // city: Cidade (case-insensitive)
+	AreaTolerance          float64 // area_tolerance: Diferença relativa máxima de área entre os anúncios (padrão 0.05)
+	IncludeAuctions        bool    // include_auctions: Inclui anúncios de leilão (excluídos por padrão)
+	IncludeVacationRentals bool    // include_vacation_rentals: Inclui aluguéis por temporada (excluídos por padrão)
+}
+
+// GetPropertyFacetsParams são os parâmetros de query e cabeçalho de GetPropertyFacets (valores zero não são enviados)
+type GetPropertyFacetsParams struct {
+	City string // city: Cidade para restringir as contagens (busca sem acentos, case-insensitive)
+}
+
+// ImportPropertiesParams são os parâmetros de query e cabeçalho de ImportProperties (valores zero não são enviados)
+type ImportPropertiesParams struct {
+	Format string // format: Formato do arquivo (se omitido, é inferido do Content-Type)
+}
+
+// SearchPropertiesParams são os parâmetros de query e cabeçalho de SearchProperties (valores zero não são enviados)
+type SearchPropertiesParams struct {
+	Include           string  // include: Campos opcionais na resposta, separados por vírgula (confidence = field_confidence)
+	Currency          string  // currency: Inclui o preço convertido para a moeda (valor_convertido e cambio_data). Requer EXCHANGE_RATES_URL; imóveis salvos antes da conversão usam as cotações do dia.
+	Q                 string  // q: Termo de busca (endereço, descrição, etc.)
+	Cidade            string  // cidade: Cidade
+	Bairro            string  // bairro: Bairro
+	TipoImovel        string  // tipo_imovel: Tipo de imóvel
+	QuartosMin        int     // quartos_min: Número mínimo de quartos
+	QuartosMax        int     // quartos_max: Número máximo de quartos
+	ValorMin          float64 // valor_min: Valor mínimo
+	ValorMax          float64 // valor_max: Valor máximo
+	AreaMin           float64 // area_min: Área mínima
+	AreaMax           float64 // area_max: Área máxima
+	Condicao          string  // condicao: Condição do imóvel estimada pela IA
+	CondicaoMin       int     // condicao_min: Nível mínimo de condição (1 = precisa reforma, 4 = novo)
+	FotosMin          int     // fotos_min: Quantidade mínima de fotos do anúncio
+	ComFotos          bool    // com_fotos: Apenas anúncios com pelo menos uma foto
+	EnergiaSolar      bool    // energia_solar: Apenas imóveis com energia solar (placas fotovoltaicas)
+	PocoArtesiano     bool    // poco_artesiano: Apenas imóveis com poço artesiano ou semiartesiano
+	AquecimentoGas    bool    // aquecimento_gas: Apenas imóveis com aquecimento a gás
+	Leilao            bool    // leilao: true retorna apenas leilões; false exclui leilões (omitido inclui todos)
+	Finalidade        string  // finalidade: Finalidade de uso do imóvel (temporada para aluguel por diárias)
+	AluguelMin        float64 // aluguel_min: Aluguel mensal mínimo
+	AluguelMax        float64 // aluguel_max: Aluguel mensal máximo
+	ApenasAluguel     bool    // apenas_aluguel: Apenas anúncios com aluguel mensal
+	OcultarSuspeitos  bool    // ocultar_suspeitos: Exclui anúncios marcados com suspeito_golpe ou suspeito_fraude
+	RiscoGolpeMax     int     // risco_golpe_max: Risco de golpe máximo (0 a 100)
+	Caracteristicas   string  // caracteristicas: Comodidades exigidas, separadas por vírgula (todas devem estar presentes)
+	Tags              string  // tags: Tags de crawling exigidas, separadas por vírgula (todas devem estar presentes)
+	MaxDistCentroKm   float64 // max_dist_centro_km: Distância máxima em km até o centro da cidade (imóveis sem distancias_poi ficam de fora)
+	MaxDistEscolaKm   float64 // max_dist_escola_km: Distância máxima em km até a escola mais próxima
+	MaxDistHospitalKm float64 // max_dist_hospital_km: Distância máxima em km até o hospital mais próximo
+}
+
+// StreamPropertiesParams são os parâmetros de query e cabeçalho de StreamProperties (valores zero não são enviados)
+type StreamPropertiesParams struct {
+	Include    string  // include: Campos opcionais na resposta, separados por vírgula (confidence = field_confidence)
+	Cursor     string  // cursor: Cursor da última linha de controle (vazio começa do início)
+	Limit      int     // limit: Máximo de imóveis nesta resposta (0 sem limite); done=false indica que há mais
+	Q          string  // q
+	Cidade     string  // cidade
+	Bairro     string  // bairro
+	TipoImovel string  // tipo_imovel
+	ValorMin   float64 // valor_min
+	ValorMax   float64 // valor_max
+	Tags       string  // tags
+}
+
+// GetDuplicateReportParams são os parâmetros de query e cabeçalho de GetDuplicateReport (valores zero não são enviados)
+type GetDuplicateReportParams struct {
+	City                   string  // city: Cidade (case-insensitive)
+	Limit                  int     // limit: Máximo de grupos retornados (padrão 100, máximo 500)
+	AreaTolerance          float64 // area_tolerance: Diferença relativa máxima de área entre os anúncios (padrão 0.05)
+	IncludeAuctions        bool    // include_auctions: Inclui anúncios de leilão (excluídos por padrão)
+	IncludeVacationRentals bool    // include_vacation_rentals: Inclui aluguéis por temporada (excluídos por padrão)
+}
+
+// GetPriceIndexParams são os parâmetros de query e cabeçalho de GetPriceIndex (valores zero não são enviados)
+type GetPriceIndexParams struct {
+	City   string // city: Cidade (busca sem acentos, case-insensitive)
+	Bairro string // bairro: Bairro; se omitido, retorna o índice da cidade inteira
+}
+
+// GetQualityMetricsParams são os parâmetros de query e cabeçalho de GetQualityMetrics (valores zero não são enviados)
+type GetQualityMetricsParams struct {
+	Domain string // domain
+	Days   int    // days
+}
+
+// ConvertUnitsParams são os parâmetros de query e cabeçalho de ConvertUnits (valores zero não são enviados)
+type ConvertUnitsParams struct {
+	Value string // value: Valor no formato brasileiro, com ou sem unidade
+	From  string // from: Unidade de origem (m2, ha, alqueire, alqueire_paulista, alqueire_baiano ou brl)
+	To    string // to: Unidade de destino da área (padrão m2)
+}
@@ -0,0 +1,1184 @@
+// Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// GetAllCities: Listar todas as cidades
+//
+// GET /cities
+func (c *Client) GetAllCities(ctx context.Context, params *GetAllCitiesParams) (*GetAllCitiesResponse, error) {
+	req := request{method: http.MethodGet, path: "/cities"}
+	if params != nil {
+		req.addQuery("stale", params.Stale)
+	}
+	var result GetAllCitiesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CleanupInactiveSites: Cleanup inactive sites
+//
+// POST /cities/cleanup
+func (c *Client) CleanupInactiveSites(ctx context.Context) error {
+	req := request{method: http.MethodPost, path: "/cities/cleanup"}
+	return c.do(ctx, req, nil)
+}
+
+// DiscoverSites: Descobrir sites de uma cidade
+//
+// POST /cities/discover-sites
+func (c *Client) DiscoverSites(ctx context.Context, body *DiscoverSitesRequest) (*DiscoverSitesResponse, error) {
+	req := request{method: http.MethodPost, path: "/cities/discover-sites"}
+	if body != nil {
+		req.body = body
+	}
+	var result DiscoverSitesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetActiveDiscoveryJobs: Get active discovery jobs
+//
+// GET /cities/discovery/jobs
+func (c *Client) GetActiveDiscoveryJobs(ctx context.Context) error {
+	req := request{method: http.MethodGet, path: "/cities/discovery/jobs"}
+	return c.do(ctx, req, nil)
+}
+
+// GetDiscoveryJob: Get discovery job
+//
+// GET /cities/discovery/jobs/{job_id}
+func (c *Client) GetDiscoveryJob(ctx context.Context, jobID string) error {
+	req := request{method: http.MethodGet, path: "/cities/discovery/jobs/" + url.PathEscape(jobID)}
+	return c.do(ctx, req, nil)
+}
+
+// ExportCitySites: Exportar cidades, sites e configurações por domínio
+//
+// GET /cities/export
+func (c *Client) ExportCitySites(ctx context.Context, params *ExportCitySitesParams) (*CitySitesExport, error) {
+	req := request{method: http.MethodGet, path: "/cities/export"}
+	if params != nil {
+		req.addQuery("format", params.Format)
+	}
+	var result CitySitesExport
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ImportCitySites: Importar cidades, sites e configurações por domínio
+//
+// POST /cities/import
+func (c *Client) ImportCitySites(ctx context.Context, params *ImportCitySitesParams, body *CitySitesExport) (*ImportCitySitesResponse, error) {
+	req := request{method: http.MethodPost, path: "/cities/import"}
+	if params != nil {
+		req.addQuery("replace", params.Replace)
+		req.addQuery("dry_run", params.DryRun)
+	}
+	if body != nil {
+		req.body = body
+	}
+	var result ImportCitySitesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// MigrateSiteFiles: Migrar List-site.ini e sites.json para o repositório de sites por cidade
+//
+// POST /cities/migrate
+func (c *Client) MigrateSiteFiles(ctx context.Context, body *MigrateSiteFilesRequest) (*MigrateSiteFilesResponse, error) {
+	req := request{method: http.MethodPost, path: "/cities/migrate"}
+	if body != nil {
+		req.body = body
+	}
+	var result MigrateSiteFilesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCitiesByRegion: Get cities by region
+//
+// GET /cities/region/{region}
+func (c *Client) GetCitiesByRegion(ctx context.Context, region string) error {
+	req := request{method: http.MethodGet, path: "/cities/region/" + url.PathEscape(region)}
+	return c.do(ctx, req, nil)
+}
+
+// GetStatistics: Get statistics
+//
+// GET /cities/statistics
+func (c *Client) GetStatistics(ctx context.Context) error {
+	req := request{method: http.MethodGet, path: "/cities/statistics"}
+	return c.do(ctx, req, nil)
+}
+
+// GetCityByName: Obter informações de uma cidade
+//
+// GET /cities/{city}
+func (c *Client) GetCityByName(ctx context.Context, city string) (*GetCityByNameResponse, error) {
+	req := request{method: http.MethodGet, path: "/cities/" + url.PathEscape(city)}
+	var result GetCityByNameResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteCity: Remover cidade
+//
+// DELETE /cities/{city}
+func (c *Client) DeleteCity(ctx context.Context, city string) error {
+	req := request{method: http.MethodDelete, path: "/cities/" + url.PathEscape(city)}
+	return c.do(ctx, req, nil)
+}
+
+// GetBairroBoundaries: Limites aproximados dos bairros da cidade (GeoJSON)
+//
+// GET /cities/{city}/bairros.geojson
+func (c *Client) GetBairroBoundaries(ctx context.Context, city string, params *GetBairroBoundariesParams) ([]byte, error) {
+	req := request{method: http.MethodGet, path: "/cities/" + url.PathEscape(city) + "/bairros.geojson"}
+	if params != nil {
+		req.addQuery("min_listings", params.MinListings)
+	}
+	var result []byte
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCitySites: Listar sites de uma cidade
+//
+// GET /cities/{city}/sites
+func (c *Client) GetCitySites(ctx context.Context, city string) (*GetCitySitesResponse, error) {
+	req := request{method: http.MethodGet, path: "/cities/" + url.PathEscape(city) + "/sites"}
+	var result GetCitySitesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AddSiteToCity: Add site to city
+//
+// POST /cities/{city}/sites
+func (c *Client) AddSiteToCity(ctx context.Context, city string) error {
+	req := request{method: http.MethodPost, path: "/cities/" + url.PathEscape(city) + "/sites"}
+	return c.do(ctx, req, nil)
+}
+
+// RemoveSiteFromCity: Remove site from city
+//
+// DELETE /cities/{city}/sites/{url}
+func (c *Client) RemoveSiteFromCity(ctx context.Context, city string, urlParam string) error {
+	req := request{method: http.MethodDelete, path: "/cities/" + url.PathEscape(city) + "/sites/" + url.PathEscape(urlParam)}
+	return c.do(ctx, req, nil)
+}
+
+// UpdateSiteStats: Update site stats
+//
+// PUT /cities/{city}/sites/{url}/stats
+func (c *Client) UpdateSiteStats(ctx context.Context, city string, urlParam string) error {
+	req := request{method: http.MethodPut, path: "/cities/" + url.PathEscape(city) + "/sites/" + url.PathEscape(urlParam) + "/stats"}
+	return c.do(ctx, req, nil)
+}
+
+// SetCityRefreshSLA: Definir o SLA de atualização de uma cidade
+//
+// PUT /cities/{city}/sla
+func (c *Client) SetCityRefreshSLA(ctx context.Context, city string, params *SetCityRefreshSLAParams, body *SetCityRefreshSLARequest) (*SetCityRefreshSLAResponse, error) {
+	req := request{method: http.MethodPut, path: "/cities/" + url.PathEscape(city) + "/sla"}
+	if params != nil {
+		req.addQuery("state", params.State)
+	}
+	if body != nil {
+		req.body = body
+	}
+	var result SetCityRefreshSLAResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ValidateCitySites: Validate city sites
+//
+// POST /cities/{city}/validate
+func (c *Client) ValidateCitySites(ctx context.Context, city string) error {
+	req := request{method: http.MethodPost, path: "/cities/" + url.PathEscape(city) + "/validate"}
+	return c.do(ctx, req, nil)
+}
+
+// GetAitokenSavings: Economia de tokens nas chamadas à IA
+//
+// GET /crawler/ai-tokens
+func (c *Client) GetAitokenSavings(ctx context.Context) (*GetAitokenSavingsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/ai-tokens"}
+	var result GetAitokenSavingsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBrokenPhotos: Fotos quebradas no site de origem
+//
+// GET /crawler/broken-photos
+func (c *Client) GetBrokenPhotos(ctx context.Context) (*GetBrokenPhotosResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/broken-photos"}
+	var result GetBrokenPhotosResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetClassifierExperiments: Experimentos A/B dos limiares do classificador
+//
+// GET /crawler/classifier-experiments
+func (c *Client) GetClassifierExperiments(ctx context.Context, params *GetClassifierExperimentsParams) (*GetClassifierExperimentsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/classifier-experiments"}
+	if params != nil {
+		req.addQuery("job_id", params.JobID)
+	}
+	var result GetClassifierExperimentsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CleanupDatabase: Limpar banco de dados
+//
+// POST /crawler/cleanup
+func (c *Client) CleanupDatabase(ctx context.Context, body *CleanupDatabaseRequest) (*CleanupDatabaseResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/cleanup"}
+	if body != nil {
+		req.body = body
+	}
+	var result CleanupDatabaseResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetConfigChanges: Alterações de configuração aplicadas
+//
+// GET /crawler/config-changes
+func (c *Client) GetConfigChanges(ctx context.Context, params *GetConfigChangesParams) (*GetConfigChangesResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/config-changes"}
+	if params != nil {
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetConfigChangesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCrawlJobCosts: Custo estimado dos jobs de crawling
+//
+// GET /crawler/costs
+func (c *Client) GetCrawlJobCosts(ctx context.Context, params *GetCrawlJobCostsParams) (*GetCrawlJobCostsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/costs"}
+	if params != nil {
+		req.addQuery("mode", params.Mode)
+		req.addQuery("days", params.Days)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetCrawlJobCostsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCoverage: Cobertura estimada por domínio
+//
+// GET /crawler/coverage
+func (c *Client) GetCoverage(ctx context.Context) (*GetCoverageResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/coverage"}
+	var result GetCoverageResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDiscoverySources: Rendimento por origem de descoberta
+//
+// GET /crawler/discovery-sources
+func (c *Client) GetDiscoverySources(ctx context.Context, params *GetDiscoverySourcesParams) (*GetDiscoverySourcesResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/discovery-sources"}
+	if params != nil {
+		req.addQuery("domain", params.Domain)
+		req.addQuery("crawls", params.Crawls)
+	}
+	var result GetDiscoverySourcesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDnscache: Cache de DNS e hosts isolados
+//
+// GET /crawler/dns
+func (c *Client) GetDnscache(ctx context.Context) (*GetDnscacheResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/dns"}
+	var result GetDnscacheResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDomainRates: Ritmo efetivo de requisições por domínio
+//
+// GET /crawler/domain-rates
+func (c *Client) GetDomainRates(ctx context.Context) (*GetDomainRatesResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/domain-rates"}
+	var result GetDomainRatesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCrawlErrors: Erros de crawling por tipo e domínio
+//
+// GET /crawler/errors
+func (c *Client) GetCrawlErrors(ctx context.Context, params *GetCrawlErrorsParams) (*GetCrawlErrorsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/errors"}
+	if params != nil {
+		req.addQuery("type", params.Type)
+		req.addQuery("domain", params.Domain)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetCrawlErrorsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetFeedStatus: Feeds RSS/Atom de novos anúncios
+//
+// GET /crawler/feeds
+func (c *Client) GetFeedStatus(ctx context.Context) (*GetFeedStatusResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/feeds"}
+	var result GetFeedStatusResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCrawlJobs: Listar jobs de crawling
+//
+// GET /crawler/jobs
+func (c *Client) GetCrawlJobs(ctx context.Context) (*GetCrawlJobsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/jobs"}
+	var result GetCrawlJobsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TriggerCrawler: Criar job de crawling
+//
+// POST /crawler/jobs
+func (c *Client) TriggerCrawler(ctx context.Context, params *TriggerCrawlerParams, body *TriggerCrawlerRequest) (*TriggerCrawlerResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/jobs"}
+	if params != nil {
+		req.setHeader("Idempotency-Key", params.IdempotencyKey)
+	}
+	if body != nil {
+		req.body = body
+	}
+	var result TriggerCrawlerResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCrawlJob: Status de um job de crawling
+//
+// GET /crawler/jobs/{id}
+func (c *Client) GetCrawlJob(ctx context.Context, id string) (*GetCrawlJobResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/jobs/" + url.PathEscape(id)}
+	var result GetCrawlJobResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CancelCrawlJob: Cancelar job de crawling
+//
+// POST /crawler/jobs/{id}/cancel
+func (c *Client) CancelCrawlJob(ctx context.Context, id string) (*CancelCrawlJobResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/jobs/" + url.PathEscape(id) + "/cancel"}
+	var result CancelCrawlJobResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PauseCrawlJobDomain: Pausar domínio em um job
+//
+// POST /crawler/jobs/{id}/pause
+func (c *Client) PauseCrawlJobDomain(ctx context.Context, id string, params *PauseCrawlJobDomainParams) (*PauseCrawlJobDomainResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/jobs/" + url.PathEscape(id) + "/pause"}
+	if params != nil {
+		req.addQuery("domain", params.Domain)
+	}
+	var result PauseCrawlJobDomainResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResumeCrawlJobDomain: Retomar domínio pausado
+//
+// POST /crawler/jobs/{id}/resume
+func (c *Client) ResumeCrawlJobDomain(ctx context.Context, id string, params *ResumeCrawlJobDomainParams) (*ResumeCrawlJobDomainResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/jobs/" + url.PathEscape(id) + "/resume"}
+	if params != nil {
+		req.addQuery("domain", params.Domain)
+	}
+	var result ResumeCrawlJobDomainResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetLeaderStatus: Estado da eleição de líder
+//
+// GET /crawler/leader
+func (c *Client) GetLeaderStatus(ctx context.Context) (*GetLeaderStatusResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/leader"}
+	var result GetLeaderStatusResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetGlobalRate: Orçamento global de páginas por minuto
+//
+// GET /crawler/rate-budget
+func (c *Client) GetGlobalRate(ctx context.Context) (*GetGlobalRateResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/rate-budget"}
+	var result GetGlobalRateResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRequestAudits: Registro de auditoria das requisições HTTP de saída
+//
+// GET /crawler/requests
+func (c *Client) GetRequestAudits(ctx context.Context, params *GetRequestAuditsParams) (*GetRequestAuditsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/requests"}
+	if params != nil {
+		req.addQuery("job_id", params.JobID)
+		req.addQuery("domain", params.Domain)
+		req.addQuery("status", params.Status)
+		req.addQuery("since", params.Since)
+		req.addQuery("until", params.Until)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetRequestAuditsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRequestAuditSummary: Resumo por domínio das requisições HTTP de saída
+//
+// GET /crawler/requests/summary
+func (c *Client) GetRequestAuditSummary(ctx context.Context, params *GetRequestAuditSummaryParams) (*GetRequestAuditSummaryResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/requests/summary"}
+	if params != nil {
+		req.addQuery("job_id", params.JobID)
+		req.addQuery("domain", params.Domain)
+		req.addQuery("status", params.Status)
+		req.addQuery("since", params.Since)
+		req.addQuery("until", params.Until)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetRequestAuditSummaryResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetJobTemplates: Listar templates de job
+//
+// GET /crawler/templates
+func (c *Client) GetJobTemplates(ctx context.Context) (*GetJobTemplatesResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/templates"}
+	var result GetJobTemplatesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetJobTemplate: Obter template de job
+//
+// GET /crawler/templates/{name}
+func (c *Client) GetJobTemplate(ctx context.Context, name string) (*GetJobTemplateResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/templates/" + url.PathEscape(name)}
+	var result GetJobTemplateResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SaveJobTemplate: Criar ou substituir template de job
+//
+// PUT /crawler/templates/{name}
+func (c *Client) SaveJobTemplate(ctx context.Context, name string, body *SaveJobTemplateRequest) (*SaveJobTemplateResponse, error) {
+	req := request{method: http.MethodPut, path: "/crawler/templates/" + url.PathEscape(name)}
+	if body != nil {
+		req.body = body
+	}
+	var result SaveJobTemplateResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteJobTemplate: Remover template de job
+//
+// DELETE /crawler/templates/{name}
+func (c *Client) DeleteJobTemplate(ctx context.Context, name string) error {
+	req := request{method: http.MethodDelete, path: "/crawler/templates/" + url.PathEscape(name)}
+	return c.do(ctx, req, nil)
+}
+
+// RunJobTemplate: Executar template de job
+//
+// POST /crawler/templates/{name}/run
+func (c *Client) RunJobTemplate(ctx context.Context, name string, params *RunJobTemplateParams, body *RunJobTemplateRequest) (*RunJobTemplateResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/templates/" + url.PathEscape(name) + "/run"}
+	if params != nil {
+		req.setHeader("Idempotency-Key", params.IdempotencyKey)
+		req.addQuery("tag", params.Tag)
+	}
+	if body != nil {
+		req.body = body
+	}
+	var result RunJobTemplateResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PostCrawlerTrigger: Iniciar crawling
+//
+// POST /crawler/trigger
+func (c *Client) PostCrawlerTrigger(ctx context.Context, params *PostCrawlerTriggerParams, body *PostCrawlerTriggerRequest) (*PostCrawlerTriggerResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/trigger"}
+	if params != nil {
+		req.setHeader("Idempotency-Key", params.IdempotencyKey)
+	}
+	if body != nil {
+		req.body = body
+	}
+	var result PostCrawlerTriggerResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetUrlstorageStats: Tamanho das coleções de URLs e fingerprints
+//
+// GET /crawler/url-storage
+func (c *Client) GetUrlstorageStats(ctx context.Context) (*GetUrlstorageStatsResponse, error) {
+	req := request{method: http.MethodGet, path: "/crawler/url-storage"}
+	var result GetUrlstorageStatsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PruneUrlstorage: Aplicar limites de tamanho às coleções de URLs
+//
+// POST /crawler/url-storage/prune
+func (c *Client) PruneUrlstorage(ctx context.Context) (*PruneUrlstorageResponse, error) {
+	req := request{method: http.MethodPost, path: "/crawler/url-storage/prune"}
+	var result PruneUrlstorageResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExplainClassification: Explicação da classificação de uma URL
+//
+// GET /debug/classify
+func (c *Client) ExplainClassification(ctx context.Context, params *ExplainClassificationParams) (*ExplainClassificationResponse, error) {
+	req := request{method: http.MethodGet, path: "/debug/classify"}
+	if params != nil {
+		req.addQuery("url", params.URL)
+		req.addQuery("engine", params.Engine)
+	}
+	var result ExplainClassificationResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ProbeDomain: Diagnóstico de rede de um domínio
+//
+// GET /debug/probe
+func (c *Client) ProbeDomain(ctx context.Context, params *ProbeDomainParams) (*ProbeDomainResponse, error) {
+	req := request{method: http.MethodGet, path: "/debug/probe"}
+	if params != nil {
+		req.addQuery("domain", params.Domain)
+	}
+	var result ProbeDomainResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAllDomainConfigs: Listar configurações de todos os domínios
+//
+// GET /domains
+func (c *Client) GetAllDomainConfigs(ctx context.Context) (*GetAllDomainConfigsResponse, error) {
+	req := request{method: http.MethodGet, path: "/domains"}
+	var result GetAllDomainConfigsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDomainConfig: Obter configuração de um domínio
+//
+// GET /domains/{domain}/config
+func (c *Client) GetDomainConfig(ctx context.Context, domain string) (*GetDomainConfigResponse, error) {
+	req := request{method: http.MethodGet, path: "/domains/" + url.PathEscape(domain) + "/config"}
+	var result GetDomainConfigResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SaveDomainConfig: Criar ou substituir configuração de um domínio
+//
+// PUT /domains/{domain}/config
+func (c *Client) SaveDomainConfig(ctx context.Context, domain string, body *DomainConfigInput) (*SaveDomainConfigResponse, error) {
+	req := request{method: http.MethodPut, path: "/domains/" + url.PathEscape(domain) + "/config"}
+	if body != nil {
+		req.body = body
+	}
+	var result SaveDomainConfigResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteDomainConfig: Remover configuração de um domínio
+//
+// DELETE /domains/{domain}/config
+func (c *Client) DeleteDomainConfig(ctx context.Context, domain string) error {
+	req := request{method: http.MethodDelete, path: "/domains/" + url.PathEscape(domain) + "/config"}
+	return c.do(ctx, req, nil)
+}
+
+// GetSkipPaths: Caminhos sem anúncios aprendidos pelo crawler
+//
+// GET /domains/{domain}/skip-paths
+func (c *Client) GetSkipPaths(ctx context.Context, domain string) (*GetSkipPathsResponse, error) {
+	req := request{method: http.MethodGet, path: "/domains/" + url.PathEscape(domain) + "/skip-paths"}
+	var result GetSkipPathsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteSkipPath: Remover caminho da lista aprendida
+//
+// DELETE /domains/{domain}/skip-paths
+func (c *Client) DeleteSkipPath(ctx context.Context, domain string, params *DeleteSkipPathParams) error {
+	req := request{method: http.MethodDelete, path: "/domains/" + url.PathEscape(domain) + "/skip-paths"}
+	if params != nil {
+		req.addQuery("path", params.Path)
+	}
+	return c.do(ctx, req, nil)
+}
+
+// GetFraudReviews: Fila de revisão de fotos repetidas
+//
+// GET /fraud/reviews
+func (c *Client) GetFraudReviews(ctx context.Context, params *GetFraudReviewsParams) (*GetFraudReviewsResponse, error) {
+	req := request{method: http.MethodGet, path: "/fraud/reviews"}
+	if params != nil {
+		req.addQuery("status", params.Status)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetFraudReviewsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResolveFraudReview: Resolver item da fila de revisão
+//
+// POST /fraud/reviews/{id}/resolve
+func (c *Client) ResolveFraudReview(ctx context.Context, id string, body *ResolveFraudReviewRequest) (*ResolveFraudReviewResponse, error) {
+	req := request{method: http.MethodPost, path: "/fraud/reviews/" + url.PathEscape(id) + "/resolve"}
+	if body != nil {
+		req.body = body
+	}
+	var result ResolveFraudReviewResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DetectPhotoFraud: Detectar fotos repetidas
+//
+// POST /fraud/scan
+func (c *Client) DetectPhotoFraud(ctx context.Context) (*DetectPhotoFraudResponse, error) {
+	req := request{method: http.MethodPost, path: "/fraud/scan"}
+	var result DetectPhotoFraudResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetHealth: Verificar status da API
+//
+// GET /health
+func (c *Client) GetHealth(ctx context.Context) (*GetHealthResponse, error) {
+	req := request{method: http.MethodGet, path: "/health"}
+	var result GetHealthResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetWhatsAppLeads: Leads dos links de WhatsApp
+//
+// GET /leads/whatsapp
+func (c *Client) GetWhatsAppLeads(ctx context.Context, params *GetWhatsAppLeadsParams) (*GetWhatsAppLeadsResponse, error) {
+	req := request{method: http.MethodGet, path: "/leads/whatsapp"}
+	if params != nil {
+		req.addQuery("domain", params.Domain)
+		req.addQuery("cidade", params.Cidade)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetWhatsAppLeadsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPropertyByShortID: Anúncio pelo ID curto
+//
+// GET /p/{shortid}
+func (c *Client) GetPropertyByShortID(ctx context.Context, shortid string, params *GetPropertyByShortIDParams) (*GetPropertyByShortIDResponse, error) {
+	req := request{method: http.MethodGet, path: "/p/" + url.PathEscape(shortid)}
+	if params != nil {
+		req.addQuery("format", params.Format)
+	}
+	var result GetPropertyByShortIDResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetLearnedPatterns: Obter padrões de URL aprendidos
+//
+// GET /patterns
+func (c *Client) GetLearnedPatterns(ctx context.Context) (*GetLearnedPatternsResponse, error) {
+	req := request{method: http.MethodGet, path: "/patterns"}
+	var result GetLearnedPatternsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExportPatterns: Exportar padrões de URL
+//
+// GET /patterns/export
+func (c *Client) ExportPatterns(ctx context.Context) (*PatternFile, error) {
+	req := request{method: http.MethodGet, path: "/patterns/export"}
+	var result PatternFile
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ImportPatterns: Importar padrões de URL
+//
+// POST /patterns/import
+func (c *Client) ImportPatterns(ctx context.Context, params *ImportPatternsParams, body *PatternFile) (*ImportPatternsResponse, error) {
+	req := request{method: http.MethodPost, path: "/patterns/import"}
+	if params != nil {
+		req.addQuery("dry_run", params.DryRun)
+	}
+	if body != nil {
+		req.body = body
+	}
+	var result ImportPatternsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RejectPatternImport: Rejeitar importação de padrões
+//
+// DELETE /patterns/import/{id}
+func (c *Client) RejectPatternImport(ctx context.Context, id string) error {
+	req := request{method: http.MethodDelete, path: "/patterns/import/" + url.PathEscape(id)}
+	return c.do(ctx, req, nil)
+}
+
+// ApprovePatternImport: Aprovar importação de padrões
+//
+// POST /patterns/import/{id}/approve
+func (c *Client) ApprovePatternImport(ctx context.Context, id string) error {
+	req := request{method: http.MethodPost, path: "/patterns/import/" + url.PathEscape(id) + "/approve"}
+	return c.do(ctx, req, nil)
+}
+
+// GetPendingPatternImports: Listar importações pendentes
+//
+// GET /patterns/imports
+func (c *Client) GetPendingPatternImports(ctx context.Context) (*GetPendingPatternImportsResponse, error) {
+	req := request{method: http.MethodGet, path: "/patterns/imports"}
+	var result GetPendingPatternImportsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetProperties: Listar propriedades
+//
+// GET /properties
+func (c *Client) GetProperties(ctx context.Context, params *GetPropertiesParams) (*GetPropertiesResponse, error) {
+	req := request{method: http.MethodGet, path: "/properties"}
+	if params != nil {
+		req.addQuery("include", params.Include)
+		req.addQuery("currency", params.Currency)
+		req.addQuery("as_of", params.AsOf)
+		req.addQuery("page", params.Page)
+		req.addQuery("page_size", params.PageSize)
+		req.addQuery("cidade", params.Cidade)
+		req.addQuery("tipo_imovel", params.TipoImovel)
+		req.addQuery("valor_min", params.ValorMin)
+		req.addQuery("valor_max", params.ValorMax)
+	}
+	var result GetPropertiesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCanonicalProperties: Registros canônicos
+//
+// GET /properties/canonical
+func (c *Client) GetCanonicalProperties(ctx context.Context, params *GetCanonicalPropertiesParams) (*GetCanonicalPropertiesResponse, error) {
+	req := request{method: http.MethodGet, path: "/properties/canonical"}
+	if params != nil {
+		req.addQuery("city", params.City)
+		req.addQuery("limit", params.Limit)
+	}
+	var result GetCanonicalPropertiesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// MergeDuplicates: Fundir duplicatas entre portais
+//
+// POST /properties/canonical/merge
+func (c *Client) MergeDuplicates(ctx context.Context, params *MergeDuplicatesParams) (*MergeDuplicatesResponse, error) {
+	req := request{method: http.MethodPost, path: "/properties/canonical/merge"}
+	if params != nil {
+		req.addQuery("city", params.City)
+		req.addQuery("area_tolerance", params.AreaTolerance)
+		req.addQuery("include_auctions", params.IncludeAuctions)
+		req.addQuery("include_vacation_rentals", params.IncludeVacationRentals)
+	}
+	var result MergeDuplicatesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCanonicalProperty: Registro canônico
+//
+// GET /properties/canonical/{id}
+func (c *Client) GetCanonicalProperty(ctx context.Context, id string) (*GetCanonicalPropertyResponse, error) {
+	req := request{method: http.MethodGet, path: "/properties/canonical/" + url.PathEscape(id)}
+	var result GetCanonicalPropertyResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPropertyFacets: Facetas de busca
+//
+// GET /properties/facets
+func (c *Client) GetPropertyFacets(ctx context.Context, params *GetPropertyFacetsParams) (*GetPropertyFacetsResponse, error) {
+	req := request{method: http.MethodGet, path: "/properties/facets"}
+	if params != nil {
+		req.addQuery("city", params.City)
+	}
+	var result GetPropertyFacetsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ImportProperties: Importar propriedades externas
+//
+// POST /properties/import
+func (c *Client) ImportProperties(ctx context.Context, params *ImportPropertiesParams, body io.Reader, contentType string) (*ImportPropertiesResponse, error) {
+	req := request{method: http.MethodPost, path: "/properties/import"}
+	if params != nil {
+		req.addQuery("format", params.Format)
+	}
+	req.rawBody, req.contentType = body, contentType
+	var result ImportPropertiesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SearchProperties: Buscar propriedades
+//
+// GET /properties/search
+func (c *Client) SearchProperties(ctx context.Context, params *SearchPropertiesParams) (*SearchPropertiesResponse, error) {
+	req := request{method: http.MethodGet, path: "/properties/search"}
+	if params != nil {
+		req.addQuery("include", params.Include)
+		req.addQuery("currency", params.Currency)
+		req.addQuery("q", params.Q)
+		req.addQuery("cidade", params.Cidade)
+		req.addQuery("bairro", params.Bairro)
+		req.addQuery("tipo_imovel", params.TipoImovel)
+		req.addQuery("quartos_min", params.QuartosMin)
+		req.addQuery("quartos_max", params.QuartosMax)
+		req.addQuery("valor_min", params.ValorMin)
+		req.addQuery("valor_max", params.ValorMax)
+		req.addQuery("area_min", params.AreaMin)
+		req.addQuery("area_max", params.AreaMax)
+		req.addQuery("condicao", params.Condicao)
+		req.addQuery("condicao_min", params.CondicaoMin)
+		req.addQuery("fotos_min", params.FotosMin)
+		req.addQuery("com_fotos", params.ComFotos)
+		req.addQuery("energia_solar", params.EnergiaSolar)
+		req.addQuery("poco_artesiano", params.PocoArtesiano)
+		req.addQuery("aquecimento_gas", params.AquecimentoGas)
+		req.addQuery("leilao", params.Leilao)
+		req.addQuery("finalidade", params.Finalidade)
+		req.addQuery("aluguel_min", params.AluguelMin)
+		req.addQuery("aluguel_max", params.AluguelMax)
+		req.addQuery("apenas_aluguel", params.ApenasAluguel)
+		req.addQuery("ocultar_suspeitos", params.OcultarSuspeitos)
+		req.addQuery("risco_golpe_max", params.RiscoGolpeMax)
+		req.addQuery("caracteristicas", params.Caracteristicas)
+		req.addQuery("tags", params.Tags)
+		req.addQuery("max_dist_centro_km", params.MaxDistCentroKm)
+		req.addQuery("max_dist_escola_km", params.MaxDistEscolaKm)
+		req.addQuery("max_dist_hospital_km", params.MaxDistHospitalKm)
+	}
+	var result SearchPropertiesResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StreamProperties: Exportar propriedades em NDJSON com cursor
+//
+// GET /properties/stream
+func (c *Client) StreamProperties(ctx context.Context, params *StreamPropertiesParams) ([]byte, error) {
+	req := request{method: http.MethodGet, path: "/properties/stream"}
+	if params != nil {
+		req.addQuery("include", params.Include)
+		req.addQuery("cursor", params.Cursor)
+		req.addQuery("limit", params.Limit)
+		req.addQuery("q", params.Q)
+		req.addQuery("cidade", params.Cidade)
+		req.addQuery("bairro", params.Bairro)
+		req.addQuery("tipo_imovel", params.TipoImovel)
+		req.addQuery("valor_min", params.ValorMin)
+		req.addQuery("valor_max", params.ValorMax)
+		req.addQuery("tags", params.Tags)
+	}
+	var result []byte
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PatchProperty: Corrigir campos do imóvel manualmente
+//
+// PATCH /properties/{id}
+func (c *Client) PatchProperty(ctx context.Context, id string, body *PatchPropertyRequest) (*PatchPropertyResponse, error) {
+	req := request{method: http.MethodPatch, path: "/properties/" + url.PathEscape(id)}
+	if body != nil {
+		req.body = body
+	}
+	var result PatchPropertyResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPropertyCorrections: Histórico de correções manuais do imóvel
+//
+// GET /properties/{id}/corrections
+func (c *Client) GetPropertyCorrections(ctx context.Context, id string) (*GetPropertyCorrectionsResponse, error) {
+	req := request{method: http.MethodGet, path: "/properties/" + url.PathEscape(id) + "/corrections"}
+	var result GetPropertyCorrectionsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPropertyPhoto: Foto do anúncio servida pela API
+//
+// GET /properties/{id}/photos/{n}
+func (c *Client) GetPropertyPhoto(ctx context.Context, id string, n int) ([]byte, error) {
+	req := request{method: http.MethodGet, path: "/properties/" + url.PathEscape(id) + "/photos/" + url.PathEscape(fmt.Sprint(n))}
+	var result []byte
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetDuplicateReport: Anúncios duplicados entre imobiliárias
+//
+// GET /stats/duplicates
+func (c *Client) GetDuplicateReport(ctx context.Context, params *GetDuplicateReportParams) (*GetDuplicateReportResponse, error) {
+	req := request{method: http.MethodGet, path: "/stats/duplicates"}
+	if params != nil {
+		req.addQuery("city", params.City)
+		req.addQuery("limit", params.Limit)
+		req.addQuery("area_tolerance", params.AreaTolerance)
+		req.addQuery("include_auctions", params.IncludeAuctions)
+		req.addQuery("include_vacation_rentals", params.IncludeVacationRentals)
+	}
+	var result GetDuplicateReportResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPriceIndex: Índice de preços por bairro
+//
+// GET /stats/index
+func (c *Client) GetPriceIndex(ctx context.Context, params *GetPriceIndexParams) (*GetPriceIndexResponse, error) {
+	req := request{method: http.MethodGet, path: "/stats/index"}
+	if params != nil {
+		req.addQuery("city", params.City)
+		req.addQuery("bairro", params.Bairro)
+	}
+	var result GetPriceIndexResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetQualityMetrics: Qualidade dos dados por domínio
+//
+// GET /stats/quality
+func (c *Client) GetQualityMetrics(ctx context.Context, params *GetQualityMetricsParams) (*GetQualityMetricsResponse, error) {
+	req := request{method: http.MethodGet, path: "/stats/quality"}
+	if params != nil {
+		req.addQuery("domain", params.Domain)
+		req.addQuery("days", params.Days)
+	}
+	var result GetQualityMetricsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ConvertUnits: Conversão de unidades
+//
+// GET /units/convert
+func (c *Client) ConvertUnits(ctx context.Context, params *ConvertUnitsParams) (*ConvertUnitsResponse, error) {
+	req := request{method: http.MethodGet, path: "/units/convert"}
+	if params != nil {
+		req.addQuery("value", params.Value)
+		req.addQuery("from", params.From)
+		req.addQuery("to", params.To)
+	}
+	var result ConvertUnitsResponse
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,814 @@
+// Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT.
+
+import { BaseClient } from './runtime.js';
+import type * as models from './models.js';
+
+/** Cliente da Go Crawler API 1.2.0 */
+export class GoCrawlerClient extends BaseClient {
+  /** Listar todas as cidades (GET /cities) */
+  async getAllCities(params: models.GetAllCitiesParams = {}): Promise<models.GetAllCitiesResponse> {
+    return this.request<models.GetAllCitiesResponse>({
+      method: 'GET',
+      path: `/cities`,
+      query: { stale: params["stale"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Cleanup inactive sites (POST /cities/cleanup) */
+  async cleanupInactiveSites(): Promise<void> {
+    return this.request<void>({
+      method: 'POST',
+      path: `/cities/cleanup`,
+      responseType: 'none',
+    });
+  }
+
+  /** Descobrir sites de uma cidade (POST /cities/discover-sites) */
+  async discoverSites(body: models.DiscoverSitesRequest): Promise<models.DiscoverSitesResponse> {
+    return this.request<models.DiscoverSitesResponse>({
+      method: 'POST',
+      path: `/cities/discover-sites`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Get active discovery jobs (GET /cities/discovery/jobs) */
+  async getActiveDiscoveryJobs(): Promise<void> {
+    return this.request<void>({
+      method: 'GET',
+      path: `/cities/discovery/jobs`,
+      responseType: 'none',
+    });
+  }
+
+  /** Get discovery job (GET /cities/discovery/jobs/{job_id}) */
+  async getDiscoveryJob(jobID: string): Promise<void> {
+    return this.request<void>({
+      method: 'GET',
+      path: `/cities/discovery/jobs/${encodeURIComponent(String(jobID))}`,
+      responseType: 'none',
+    });
+  }
+
+  /** Exportar cidades, sites e configurações por domínio (GET /cities/export) */
+  async exportCitySites(params: models.ExportCitySitesParams = {}): Promise<models.CitySitesExport> {
+    return this.request<models.CitySitesExport>({
+      method: 'GET',
+      path: `/cities/export`,
+      query: { format: params["format"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Importar cidades, sites e configurações por domínio (POST /cities/import) */
+  async importCitySites(body: models.CitySitesExport, params: models.ImportCitySitesParams = {}): Promise<models.ImportCitySitesResponse> {
+    return this.request<models.ImportCitySitesResponse>({
+      method: 'POST',
+      path: `/cities/import`,
+      query: { replace: params["replace"], dry_run: params["dry_run"] },
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Migrar List-site.ini e sites.json para o repositório de sites por cidade (POST /cities/migrate) */
+  async migrateSiteFiles(body: models.MigrateSiteFilesRequest): Promise<models.MigrateSiteFilesResponse> {
+    return this.request<models.MigrateSiteFilesResponse>({
+      method: 'POST',
+      path: `/cities/migrate`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Get cities by region (GET /cities/region/{region}) */
+  async getCitiesByRegion(region: string): Promise<void> {
+    return this.request<void>({
+      method: 'GET',
+      path: `/cities/region/${encodeURIComponent(String(region))}`,
+      responseType: 'none',
+    });
+  }
+
+  /** Get statistics (GET /cities/statistics) */
+  async getStatistics(): Promise<void> {
+    return this.request<void>({
+      method: 'GET',
+      path: `/cities/statistics`,
+      responseType: 'none',
+    });
+  }
+
+  /** Obter informações de uma cidade (GET /cities/{city}) */
+  async getCityByName(city: string): Promise<models.GetCityByNameResponse> {
+    return this.request<models.GetCityByNameResponse>({
+      method: 'GET',
+      path: `/cities/${encodeURIComponent(String(city))}`,
+      responseType: 'json',
+    });
+  }
+
+  /** Remover cidade (DELETE /cities/{city}) */
+  async deleteCity(city: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/cities/${encodeURIComponent(String(city))}`,
+      responseType: 'none',
+    });
+  }
+
+  /** Limites aproximados dos bairros da cidade (GeoJSON) (GET /cities/{city}/bairros.geojson) */
+  async getBairroBoundaries(city: string, params: models.GetBairroBoundariesParams = {}): Promise<Blob> {
+    return this.request<Blob>({
+      method: 'GET',
+      path: `/cities/${encodeURIComponent(String(city))}/bairros.geojson`,
+      query: { min_listings: params["min_listings"] },
+      responseType: 'blob',
+    });
+  }
+
+  /** Listar sites de uma cidade (GET /cities/{city}/sites) */
+  async getCitySites(city: string): Promise<models.GetCitySitesResponse> {
+    return this.request<models.GetCitySitesResponse>({
+      method: 'GET',
+      path: `/cities/${encodeURIComponent(String(city))}/sites`,
+      responseType: 'json',
+    });
+  }
+
+  /** Add site to city (POST /cities/{city}/sites) */
+  async addSiteToCity(city: string): Promise<void> {
+    return this.request<void>({
+      method: 'POST',
+      path: `/cities/${encodeURIComponent(String(city))}/sites`,
+      responseType: 'none',
+    });
+  }
+
+  /** Remove site from city (DELETE /cities/{city}/sites/{url}) */
+  async removeSiteFromCity(city: string, urlParam: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/cities/${encodeURIComponent(String(city))}/sites/${encodeURIComponent(String(urlParam))}`,
+      responseType: 'none',
+    });
+  }
+
+  /** Update site stats (PUT /cities/{city}/sites/{url}/stats) */
+  async updateSiteStats(city: string, urlParam: string): Promise<void> {
+    return this.request<void>({
+      method: 'PUT',
+      path: `/cities/${encodeURIComponent(String(city))}/sites/${encodeURIComponent(String(urlParam))}/stats`,
+      responseType: 'none',
+    });
+  }
+
+  /** Definir o SLA de atualização de uma cidade (PUT /cities/{city}/sla) */
+  async setCityRefreshSLA(city: string, body: models.SetCityRefreshSLARequest, params: models.SetCityRefreshSLAParams = {}): Promise<models.SetCityRefreshSLAResponse> {
+    return this.request<models.SetCityRefreshSLAResponse>({
+      method: 'PUT',
+      path: `/cities/${encodeURIComponent(String(city))}/sla`,
+      query: { state: params["state"] },
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Validate city sites (POST /cities/{city}/validate) */
+  async validateCitySites(city: string): Promise<void> {
+    return this.request<void>({
+      method: 'POST',
+      path: `/cities/${encodeURIComponent(String(city))}/validate`,
+      responseType: 'none',
+    });
+  }
+
+  /** Economia de tokens nas chamadas à IA (GET /crawler/ai-tokens) */
+  async getAitokenSavings(): Promise<models.GetAitokenSavingsResponse> {
+    return this.request<models.GetAitokenSavingsResponse>({
+      method: 'GET',
+      path: `/crawler/ai-tokens`,
+      responseType: 'json',
+    });
+  }
+
+  /** Fotos quebradas no site de origem (GET /crawler/broken-photos) */
+  async getBrokenPhotos(): Promise<models.GetBrokenPhotosResponse> {
+    return this.request<models.GetBrokenPhotosResponse>({
+      method: 'GET',
+      path: `/crawler/broken-photos`,
+      responseType: 'json',
+    });
+  }
+
+  /** Experimentos A/B dos limiares do classificador (GET /crawler/classifier-experiments) */
+  async getClassifierExperiments(params: models.GetClassifierExperimentsParams = {}): Promise<models.GetClassifierExperimentsResponse> {
+    return this.request<models.GetClassifierExperimentsResponse>({
+      method: 'GET',
+      path: `/crawler/classifier-experiments`,
+      query: { job_id: params["job_id"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Limpar banco de dados (POST /crawler/cleanup) */
+  async cleanupDatabase(body: models.CleanupDatabaseRequest): Promise<models.CleanupDatabaseResponse> {
+    return this.request<models.CleanupDatabaseResponse>({
+      method: 'POST',
+      path: `/crawler/cleanup`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Alterações de configuração aplicadas (GET /crawler/config-changes) */
+  async getConfigChanges(params: models.GetConfigChangesParams = {}): Promise<models.GetConfigChangesResponse> {
+    return this.request<models.GetConfigChangesResponse>({
+      method: 'GET',
+      path: `/crawler/config-changes`,
+      query: { limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Custo estimado dos jobs de crawling (GET /crawler/costs) */
+  async getCrawlJobCosts(params: models.GetCrawlJobCostsParams = {}): Promise<models.GetCrawlJobCostsResponse> {
+    return this.request<models.GetCrawlJobCostsResponse>({
+      method: 'GET',
+      path: `/crawler/costs`,
+      query: { mode: params["mode"], days: params["days"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Cobertura estimada por domínio (GET /crawler/coverage) */
+  async getCoverage(): Promise<models.GetCoverageResponse> {
+    return this.request<models.GetCoverageResponse>({
+      method: 'GET',
+      path: `/crawler/coverage`,
+      responseType: 'json',
+    });
+  }
+
+  /** Rendimento por origem de descoberta (GET /crawler/discovery-sources) */
+  async getDiscoverySources(params: models.GetDiscoverySourcesParams = {}): Promise<models.GetDiscoverySourcesResponse> {
+    return this.request<models.GetDiscoverySourcesResponse>({
+      method: 'GET',
+      path: `/crawler/discovery-sources`,
+      query: { domain: params["domain"], crawls: params["crawls"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Cache de DNS e hosts isolados (GET /crawler/dns) */
+  async getDnscache(): Promise<models.GetDnscacheResponse> {
+    return this.request<models.GetDnscacheResponse>({
+      method: 'GET',
+      path: `/crawler/dns`,
+      responseType: 'json',
+    });
+  }
+
+  /** Ritmo efetivo de requisições por domínio (GET /crawler/domain-rates) */
+  async getDomainRates(): Promise<models.GetDomainRatesResponse> {
+    return this.request<models.GetDomainRatesResponse>({
+      method: 'GET',
+      path: `/crawler/domain-rates`,
+      responseType: 'json',
+    });
+  }
+
+  /** Erros de crawling por tipo e domínio (GET /crawler/errors) */
+  async getCrawlErrors(params: models.GetCrawlErrorsParams = {}): Promise<models.GetCrawlErrorsResponse> {
+    return this.request<models.GetCrawlErrorsResponse>({
+      method: 'GET',
+      path: `/crawler/errors`,
+      query: { type: params["type"], domain: params["domain"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Feeds RSS/Atom de novos anúncios (GET /crawler/feeds) */
+  async getFeedStatus(): Promise<models.GetFeedStatusResponse> {
+    return this.request<models.GetFeedStatusResponse>({
+      method: 'GET',
+      path: `/crawler/feeds`,
+      responseType: 'json',
+    });
+  }
+
+  /** Listar jobs de crawling (GET /crawler/jobs) */
+  async getCrawlJobs(): Promise<models.GetCrawlJobsResponse> {
+    return this.request<models.GetCrawlJobsResponse>({
+      method: 'GET',
+      path: `/crawler/jobs`,
+      responseType: 'json',
+    });
+  }
+
+  /** Criar job de crawling (POST /crawler/jobs) */
+  async triggerCrawler(body: models.TriggerCrawlerRequest, params: models.TriggerCrawlerParams = {}): Promise<models.TriggerCrawlerResponse> {
+    return this.request<models.TriggerCrawlerResponse>({
+      method: 'POST',
+      path: `/crawler/jobs`,
+      headers: { "Idempotency-Key": params["Idempotency-Key"] },
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Status de um job de crawling (GET /crawler/jobs/{id}) */
+  async getCrawlJob(id: string): Promise<models.GetCrawlJobResponse> {
+    return this.request<models.GetCrawlJobResponse>({
+      method: 'GET',
+      path: `/crawler/jobs/${encodeURIComponent(String(id))}`,
+      responseType: 'json',
+    });
+  }
+
+  /** Cancelar job de crawling (POST /crawler/jobs/{id}/cancel) */
+  async cancelCrawlJob(id: string): Promise<models.CancelCrawlJobResponse> {
+    return this.request<models.CancelCrawlJobResponse>({
+      method: 'POST',
+      path: `/crawler/jobs/${encodeURIComponent(String(id))}/cancel`,
+      responseType: 'json',
+    });
+  }
+
+  /** Pausar domínio em um job (POST /crawler/jobs/{id}/pause) */
+  async pauseCrawlJobDomain(id: string, params: models.PauseCrawlJobDomainParams = {}): Promise<models.PauseCrawlJobDomainResponse> {
+    return this.request<models.PauseCrawlJobDomainResponse>({
+      method: 'POST',
+      path: `/crawler/jobs/${encodeURIComponent(String(id))}/pause`,
+      query: { domain: params["domain"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Retomar domínio pausado (POST /crawler/jobs/{id}/resume) */
+  async resumeCrawlJobDomain(id: string, params: models.ResumeCrawlJobDomainParams = {}): Promise<models.ResumeCrawlJobDomainResponse> {
+    return this.request<models.ResumeCrawlJobDomainResponse>({
+      method: 'POST',
+      path: `/crawler/jobs/${encodeURIComponent(String(id))}/resume`,
+      query: { domain: params["domain"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Estado da eleição de líder (GET /crawler/leader) */
+  async getLeaderStatus(): Promise<models.GetLeaderStatusResponse> {
+    return this.request<models.GetLeaderStatusResponse>({
+      method: 'GET',
+      path: `/crawler/leader`,
+      responseType: 'json',
+    });
+  }
+
+  /** Orçamento global de páginas por minuto (GET /crawler/rate-budget) */
+  async getGlobalRate(): Promise<models.GetGlobalRateResponse> {
+    return this.request<models.GetGlobalRateResponse>({
+      method: 'GET',
+      path: `/crawler/rate-budget`,
+      responseType: 'json',
+    });
+  }
+
+  /** Registro de auditoria das requisições HTTP de saída (GET /crawler/requests) */
+  async getRequestAudits(params: models.GetRequestAuditsParams = {}): Promise<models.GetRequestAuditsResponse> {
+    return this.request<models.GetRequestAuditsResponse>({
+      method: 'GET',
+      path: `/crawler/requests`,
+      query: { job_id: params["job_id"], domain: params["domain"], status: params["status"], since: params["since"], until: params["until"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Resumo por domínio das requisições HTTP de saída (GET /crawler/requests/summary) */
+  async getRequestAuditSummary(params: models.GetRequestAuditSummaryParams = {}): Promise<models.GetRequestAuditSummaryResponse> {
+    return this.request<models.GetRequestAuditSummaryResponse>({
+      method: 'GET',
+      path: `/crawler/requests/summary`,
+      query: { job_id: params["job_id"], domain: params["domain"], status: params["status"], since: params["since"], until: params["until"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Listar templates de job (GET /crawler/templates) */
+  async getJobTemplates(): Promise<models.GetJobTemplatesResponse> {
+    return this.request<models.GetJobTemplatesResponse>({
+      method: 'GET',
+      path: `/crawler/templates`,
+      responseType: 'json',
+    });
+  }
+
+  /** Obter template de job (GET /crawler/templates/{name}) */
+  async getJobTemplate(name: string): Promise<models.GetJobTemplateResponse> {
+    return this.request<models.GetJobTemplateResponse>({
+      method: 'GET',
+      path: `/crawler/templates/${encodeURIComponent(String(name))}`,
+      responseType: 'json',
+    });
+  }
+
+  /** Criar ou substituir template de job (PUT /crawler/templates/{name}) */
+  async saveJobTemplate(name: string, body: models.SaveJobTemplateRequest): Promise<models.SaveJobTemplateResponse> {
+    return this.request<models.SaveJobTemplateResponse>({
+      method: 'PUT',
+      path: `/crawler/templates/${encodeURIComponent(String(name))}`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Remover template de job (DELETE /crawler/templates/{name}) */
+  async deleteJobTemplate(name: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/crawler/templates/${encodeURIComponent(String(name))}`,
+      responseType: 'none',
+    });
+  }
+
+  /** Executar template de job (POST /crawler/templates/{name}/run) */
+  async runJobTemplate(name: string, body: models.RunJobTemplateRequest, params: models.RunJobTemplateParams = {}): Promise<models.RunJobTemplateResponse> {
+    return this.request<models.RunJobTemplateResponse>({
+      method: 'POST',
+      path: `/crawler/templates/${encodeURIComponent(String(name))}/run`,
+      query: { tag: params["tag"] },
+      headers: { "Idempotency-Key": params["Idempotency-Key"] },
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Iniciar crawling (POST /crawler/trigger) */
+  async postCrawlerTrigger(body: models.PostCrawlerTriggerRequest, params: models.PostCrawlerTriggerParams = {}): Promise<models.PostCrawlerTriggerResponse> {
+    return this.request<models.PostCrawlerTriggerResponse>({
+      method: 'POST',
+      path: `/crawler/trigger`,
+      headers: { "Idempotency-Key": params["Idempotency-Key"] },
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Tamanho das coleções de URLs e fingerprints (GET /crawler/url-storage) */
+  async getUrlstorageStats(): Promise<models.GetUrlstorageStatsResponse> {
+    return this.request<models.GetUrlstorageStatsResponse>({
+      method: 'GET',
+      path: `/crawler/url-storage`,
+      responseType: 'json',
+    });
+  }
+
+  /** Aplicar limites de tamanho às coleções de URLs (POST /crawler/url-storage/prune) */
+  async pruneUrlstorage(): Promise<models.PruneUrlstorageResponse> {
+    return this.request<models.PruneUrlstorageResponse>({
+      method: 'POST',
+      path: `/crawler/url-storage/prune`,
+      responseType: 'json',
+    });
+  }
+
+  /** Explicação da classificação de uma URL (GET /debug/classify) */
+  async explainClassification(params: models.ExplainClassificationParams = {}): Promise<models.ExplainClassificationResponse> {
+    return this.request<models.ExplainClassificationResponse>({
+      method: 'GET',
+      path: `/debug/classify`,
+      query: { url: params["url"], engine: params["engine"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Diagnóstico de rede de um domínio (GET /debug/probe) */
+  async probeDomain(params: models.ProbeDomainParams = {}): Promise<models.ProbeDomainResponse> {
+    return this.request<models.ProbeDomainResponse>({
+      method: 'GET',
+      path: `/debug/probe`,
+      query: { domain: params["domain"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Listar configurações de todos os domínios (GET /domains) */
+  async getAllDomainConfigs(): Promise<models.GetAllDomainConfigsResponse> {
+    return this.request<models.GetAllDomainConfigsResponse>({
+      method: 'GET',
+      path: `/domains`,
+      responseType: 'json',
+    });
+  }
+
+  /** Obter configuração de um domínio (GET /domains/{domain}/config) */
+  async getDomainConfig(domain: string): Promise<models.GetDomainConfigResponse> {
+    return this.request<models.GetDomainConfigResponse>({
+      method: 'GET',
+      path: `/domains/${encodeURIComponent(String(domain))}/config`,
+      responseType: 'json',
+    });
+  }
+
+  /** Criar ou substituir configuração de um domínio (PUT /domains/{domain}/config) */
+  async saveDomainConfig(domain: string, body: models.DomainConfigInput): Promise<models.SaveDomainConfigResponse> {
+    return this.request<models.SaveDomainConfigResponse>({
+      method: 'PUT',
+      path: `/domains/${encodeURIComponent(String(domain))}/config`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Remover configuração de um domínio (DELETE /domains/{domain}/config) */
+  async deleteDomainConfig(domain: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/domains/${encodeURIComponent(String(domain))}/config`,
+      responseType: 'none',
+    });
+  }
+
+  /** Caminhos sem anúncios aprendidos pelo crawler (GET /domains/{domain}/skip-paths) */
+  async getSkipPaths(domain: string): Promise<models.GetSkipPathsResponse> {
+    return this.request<models.GetSkipPathsResponse>({
+      method: 'GET',
+      path: `/domains/${encodeURIComponent(String(domain))}/skip-paths`,
+      responseType: 'json',
+    });
+  }
+
+  /** Remover caminho da lista aprendida (DELETE /domains/{domain}/skip-paths) */
+  async deleteSkipPath(domain: string, params: models.DeleteSkipPathParams = {}): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/domains/${encodeURIComponent(String(domain))}/skip-paths`,
+      query: { path: params["path"] },
+      responseType: 'none',
+    });
+  }
+
+  /** Fila de revisão de fotos repetidas (GET /fraud/reviews) */
+  async getFraudReviews(params: models.GetFraudReviewsParams = {}): Promise<models.GetFraudReviewsResponse> {
+    return this.request<models.GetFraudReviewsResponse>({
+      method: 'GET',
+      path: `/fraud/reviews`,
+      query: { status: params["status"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Resolver item da fila de revisão (POST /fraud/reviews/{id}/resolve) */
+  async resolveFraudReview(id: string, body: models.ResolveFraudReviewRequest): Promise<models.ResolveFraudReviewResponse> {
+    return this.request<models.ResolveFraudReviewResponse>({
+      method: 'POST',
+      path: `/fraud/reviews/${encodeURIComponent(String(id))}/resolve`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Detectar fotos repetidas (POST /fraud/scan) */
+  async detectPhotoFraud(): Promise<models.DetectPhotoFraudResponse> {
+    return this.request<models.DetectPhotoFraudResponse>({
+      method: 'POST',
+      path: `/fraud/scan`,
+      responseType: 'json',
+    });
+  }
+
+  /** Verificar status da API (GET /health) */
+  async getHealth(): Promise<models.GetHealthResponse> {
+    return this.request<models.GetHealthResponse>({
+      method: 'GET',
+      path: `/health`,
+      responseType: 'json',
+    });
+  }
+
+  /** Leads dos links de WhatsApp (GET /leads/whatsapp) */
+  async getWhatsAppLeads(params: models.GetWhatsAppLeadsParams = {}): Promise<models.GetWhatsAppLeadsResponse> {
+    return this.request<models.GetWhatsAppLeadsResponse>({
+      method: 'GET',
+      path: `/leads/whatsapp`,
+      query: { domain: params["domain"], cidade: params["cidade"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Anúncio pelo ID curto (GET /p/{shortid}) */
+  async getPropertyByShortID(shortid: string, params: models.GetPropertyByShortIDParams = {}): Promise<models.GetPropertyByShortIDResponse> {
+    return this.request<models.GetPropertyByShortIDResponse>({
+      method: 'GET',
+      path: `/p/${encodeURIComponent(String(shortid))}`,
+      query: { format: params["format"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Obter padrões de URL aprendidos (GET /patterns) */
+  async getLearnedPatterns(): Promise<models.GetLearnedPatternsResponse> {
+    return this.request<models.GetLearnedPatternsResponse>({
+      method: 'GET',
+      path: `/patterns`,
+      responseType: 'json',
+    });
+  }
+
+  /** Exportar padrões de URL (GET /patterns/export) */
+  async exportPatterns(): Promise<models.PatternFile> {
+    return this.request<models.PatternFile>({
+      method: 'GET',
+      path: `/patterns/export`,
+      responseType: 'json',
+    });
+  }
+
+  /** Importar padrões de URL (POST /patterns/import) */
+  async importPatterns(body: models.PatternFile, params: models.ImportPatternsParams = {}): Promise<models.ImportPatternsResponse> {
+    return this.request<models.ImportPatternsResponse>({
+      method: 'POST',
+      path: `/patterns/import`,
+      query: { dry_run: params["dry_run"] },
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Rejeitar importação de padrões (DELETE /patterns/import/{id}) */
+  async rejectPatternImport(id: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/patterns/import/${encodeURIComponent(String(id))}`,
+      responseType: 'none',
+    });
+  }
+
+  /** Aprovar importação de padrões (POST /patterns/import/{id}/approve) */
+  async approvePatternImport(id: string): Promise<void> {
+    return this.request<void>({
+      method: 'POST',
+      path: `/patterns/import/${encodeURIComponent(String(id))}/approve`,
+      responseType: 'none',
+    });
+  }
+
+  /** Listar importações pendentes (GET /patterns/imports) */
+  async getPendingPatternImports(): Promise<models.GetPendingPatternImportsResponse> {
+    return this.request<models.GetPendingPatternImportsResponse>({
+      method: 'GET',
+      path: `/patterns/imports`,
+      responseType: 'json',
+    });
+  }
+
+  /** Listar propriedades (GET /properties) */
+  async getProperties(params: models.GetPropertiesParams = {}): Promise<models.GetPropertiesResponse> {
+    return this.request<models.GetPropertiesResponse>({
+      method: 'GET',
+      path: `/properties`,
+      query: { include: params["include"], currency: params["currency"], as_of: params["as_of"], page: params["page"], page_size: params["page_size"], cidade: params["cidade"], tipo_imovel: params["tipo_imovel"], valor_min: params["valor_min"], valor_max: params["valor_max"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Registros canônicos (GET /properties/canonical) */
+  async getCanonicalProperties(params: models.GetCanonicalPropertiesParams = {}): Promise<models.GetCanonicalPropertiesResponse> {
+    return this.request<models.GetCanonicalPropertiesResponse>({
+      method: 'GET',
+      path: `/properties/canonical`,
+      query: { city: params["city"], limit: params["limit"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Fundir duplicatas entre portais (POST /properties/canonical/merge) */
+  async mergeDuplicates(params: models.MergeDuplicatesParams = {}): Promise<models.MergeDuplicatesResponse> {
+    return this.request<models.MergeDuplicatesResponse>({
+      method: 'POST',
+      path: `/properties/canonical/merge`,
+      query: { city: params["city"], area_tolerance: params["area_tolerance"], include_auctions: params["include_auctions"], include_vacation_rentals: params["include_vacation_rentals"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Registro canônico (GET /properties/canonical/{id}) */
+  async getCanonicalProperty(id: string): Promise<models.GetCanonicalPropertyResponse> {
+    return this.request<models.GetCanonicalPropertyResponse>({
+      method: 'GET',
+      path: `/properties/canonical/${encodeURIComponent(String(id))}`,
+      responseType: 'json',
+    });
+  }
+
+  /** Facetas de busca (GET /properties/facets) */
+  async getPropertyFacets(params: models.GetPropertyFacetsParams = {}): Promise<models.GetPropertyFacetsResponse> {
+    return this.request<models.GetPropertyFacetsResponse>({
+      method: 'GET',
+      path: `/properties/facets`,
+      query: { city: params["city"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Importar propriedades externas (POST /properties/import) */
+  async importProperties(body: BodyInit, contentType: string, params: models.ImportPropertiesParams = {}): Promise<models.ImportPropertiesResponse> {
+    return this.request<models.ImportPropertiesResponse>({
+      method: 'POST',
+      path: `/properties/import`,
+      query: { format: params["format"] },
+      rawBody: body,
+      contentType,
+      responseType: 'json',
+    });
+  }
+
+  /** Buscar propriedades (GET /properties/search) */
+  async searchProperties(params: models.SearchPropertiesParams = {}): Promise<models.SearchPropertiesResponse> {
+    return this.request<models.SearchPropertiesResponse>({
+      method: 'GET',
+      path: `/properties/search`,
+      query: { include: params["include"], currency: params["currency"], q: params["q"], cidade: params["cidade"], bairro: params["bairro"], tipo_imovel: params["tipo_imovel"], quartos_min: params["quartos_min"], quartos_max: params["quartos_max"], valor_min: params["valor_min"], valor_max: params["valor_max"], area_min: params["area_min"], area_max: params["area_max"], condicao: params["condicao"], condicao_min: params["condicao_min"], fotos_min: params["fotos_min"], com_fotos: params["com_fotos"], energia_solar: params["energia_solar"], poco_artesiano: params["poco_artesiano"], aquecimento_gas: params["aquecimento_gas"], leilao: params["leilao"], finalidade: params["finalidade"], aluguel_min: params["aluguel_min"], aluguel_max: params["aluguel_max"], apenas_aluguel: params["apenas_aluguel"], ocultar_suspeitos: params["ocultar_suspeitos"], risco_golpe_max: params["risco_golpe_max"], caracteristicas: params["caracteristicas"], tags: params["tags"], max_dist_centro_km: params["max_dist_centro_km"], max_dist_escola_km: params["max_dist_escola_km"], max_dist_hospital_km: params["max_dist_hospital_km"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Exportar propriedades em NDJSON com cursor (GET /properties/stream) */
+  async streamProperties(params: models.StreamPropertiesParams = {}): Promise<Blob> {
+    return this.request<Blob>({
+      method: 'GET',
+      path: `/properties/stream`,
+      query: { include: params["include"], cursor: params["cursor"], limit: params["limit"], q: params["q"], cidade: params["cidade"], bairro: params["bairro"], tipo_imovel: params["tipo_imovel"], valor_min: params["valor_min"], valor_max: params["valor_max"], tags: params["tags"] },
+      responseType: 'blob',
+    });
+  }
+
+  /** Corrigir campos do imóvel manualmente (PATCH /properties/{id}) */
+  async patchProperty(id: string, body: models.PatchPropertyRequest): Promise<models.PatchPropertyResponse> {
+    return this.request<models.PatchPropertyResponse>({
+      method: 'PATCH',
+      path: `/properties/${encodeURIComponent(String(id))}`,
+      body,
+      responseType: 'json',
+    });
+  }
+
+  /** Histórico de correções manuais do imóvel (GET /properties/{id}/corrections) */
+  async getPropertyCorrections(id: string): Promise<models.GetPropertyCorrectionsResponse> {
+    return this.request<models.GetPropertyCorrectionsResponse>({
+      method: 'GET',
+      path: `/properties/${encodeURIComponent(String(id))}/corrections`,
+      responseType: 'json',
+    });
+  }
+
+  /** Foto do anúncio servida pela API (GET /properties/{id}/photos/{n}) */
+  async getPropertyPhoto(id: string, n: number): Promise<Blob> {
+    return this.request<Blob>({
+      method: 'GET',
+      path: `/properties/${encodeURIComponent(String(id))}/photos/${encodeURIComponent(String(n))}`,
+      responseType: 'blob',
+    });
+  }
+
+  /** Anúncios duplicados entre imobiliárias (GET /stats/duplicates) */
+  async getDuplicateReport(params: models.GetDuplicateReportParams = {}): Promise<models.GetDuplicateReportResponse> {
+    return this.request<models.GetDuplicateReportResponse>({
+      method: 'GET',
+      path: `/stats/duplicates`,
+      query: { city: params["city"], limit: params["limit"], area_tolerance: params["area_tolerance"], include_auctions: params["include_auctions"], include_vacation_rentals: params["include_vacation_rentals"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Índice de preços por bairro (GET /stats/index) */
+  async getPriceIndex(params: models.GetPriceIndexParams = {}): Promise<models.GetPriceIndexResponse> {
+    return this.request<models.GetPriceIndexResponse>({
+      method: 'GET',
+      path: `/stats/index`,
+      query: { city: params["city"], bairro: params["bairro"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Qualidade dos dados por domínio (GET /stats/quality) */
+  async getQualityMetrics(params: models.GetQualityMetricsParams = {}): Promise<models.GetQualityMetricsResponse> {
+    return this.request<models.GetQualityMetricsResponse>({
+      method: 'GET',
+      path: `/stats/quality`,
+      query: { domain: params["domain"], days: params["days"] },
+      responseType: 'json',
+    });
+  }
+
+  /** Conversão de unidades (GET /units/convert) */
+  async convertUnits(params: models.ConvertUnitsParams = {}): Promise<models.ConvertUnitsResponse> {
+    return this.request<models.ConvertUnitsResponse>({
+      method: 'GET',
+      path: `/units/convert`,
+      query: { value: params["value"], from: params["from"], to: params["to"] },
+      responseType: 'json',
+    });
+  }
+}
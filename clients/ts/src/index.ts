@@ -0,0 +1,4 @@
+export { GoCrawlerClient } from './client.js';
+export { ApiError, BaseClient } from './runtime.js';
+export type { ClientOptions, RequestOptions } from './runtime.js';
+export type * from './models.js';
@@ -0,0 +1,1934 @@
+// Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT.
+
+export interface AitokenCallStats {
+  calls?: number;
+  /** Tokens estimados do conteúdo recebido */
+  original_tokens?: number;
+  saved_percent?: number;
+  saved_tokens?: number;
+  /** Tokens estimados do conteúdo enviado após a sanitização */
+  sent_tokens?: number;
+}
+
+export interface AitokenSavings {
+  /** Por tipo de chamada (classification, validation, pattern_analysis, selector_suggestion) */
+  by_call?: Record<string, AitokenCallStats>;
+  total?: AitokenCallStats;
+}
+
+export interface BrokenPhoto {
+  checked_at?: string;
+  domain?: string;
+  reason?: string;
+  status?: number;
+  url?: string;
+}
+
+export interface CanonicalMergeResult {
+  cidade?: string;
+  /** Anúncios comparados */
+  listings?: number;
+  /** Registros canônicos gravados */
+  merged?: number;
+  /** Registros canônicos que deixaram de existir */
+  removed?: number;
+  /** Anúncios ligados a um registro canônico */
+  sources?: number;
+  /** Confiabilidade dos domínios envolvidos */
+  weights?: Record<string, number>;
+}
+
+/** Imóvel anunciado em vários portais, com os campos fundidos por confiabilidade da fonte */
+export interface CanonicalProperty {
+  cidade?: string;
+  /** URL de onde veio cada campo */
+  field_sources?: Record<string, string>;
+  id?: string;
+  merged_at?: string;
+  property?: Property;
+  /** Anúncios de origem como foram extraídos, do domínio mais confiável para o menos */
+  sources?: CanonicalSource[];
+}
+
+export interface CanonicalSource {
+  domain?: string;
+  original?: Property;
+  url?: string;
+  /** Confiabilidade do domínio (0 a 1) */
+  weight?: number;
+}
+
+export interface City {
+  active_sites?: number;
+  city?: string;
+  freshness?: CityFreshness;
+  hash?: string;
+  id?: string;
+  last_discovery?: string;
+  /** Último crawling bem-sucedido que cobriu os sites da cidade */
+  last_refresh?: string;
+  last_refresh_attempt?: string;
+  last_refresh_error?: string;
+  last_updated?: string;
+  /** Crawlings seguidos que falharam desde a última atualização */
+  refresh_failures?: number;
+  /** SLA de atualização próprio da cidade (ausente usa CITY_REFRESH_SLA) */
+  refresh_sla_hours?: number;
+  region?: string;
+  sites?: CitySite[];
+  state?: string;
+  status?: string;
+  total_sites?: number;
+}
+
+export interface CityFreshness {
+  /** Horas desde a última atualização (ou da descoberta dos sites, sem atualização) */
+  age_hours?: number;
+  last_refresh?: string;
+  reason?: string;
+  sla_hours?: number;
+  stale?: boolean;
+}
+
+export interface CitySite {
+  discovered_at?: string;
+  discovery_method?: string;
+  domain?: string;
+  error_count?: number;
+  last_crawled?: string;
+  last_error?: string;
+  last_success?: string;
+  name?: string;
+  properties_found?: number;
+  /** Anúncios de exemplo do site */
+  reference_urls?: string[];
+  response_time?: number;
+  /** Arquivo de origem dos sites migrados */
+  source?: string;
+  status?: string;
+  success_rate?: number;
+  url?: string;
+}
+
+export interface CitySitesExport {
+  cities?: CitySitesExportCity[];
+  domains?: DomainConfig[];
+  exported_at?: string;
+  version?: number;
+}
+
+export interface CitySitesExportCity {
+  city?: string;
+  refresh_sla_hours?: number;
+  region?: string;
+  sites?: CitySitesExportCitySite[];
+  state?: string;
+  status?: string;
+}
+
+export interface CitySitesExportCitySite {
+  discovery_method?: string;
+  domain?: string;
+  name?: string;
+  reference_urls?: string[];
+  source?: string;
+  status?: string;
+  url?: string;
+}
+
+export interface CitySitesImportResult {
+  cities?: number;
+  cities_created?: number;
+  domains?: number;
+  dry_run?: boolean;
+  replace?: boolean;
+  sites_added?: number;
+  sites_removed?: number;
+  sites_updated?: number;
+}
+
+export interface ClassificationDecision {
+  /** property (salvar), catalog (explorar os links) ou skip */
+  action?: string;
+  engine?: string;
+  is_property?: boolean;
+  reason?: string;
+}
+
+export interface ClassificationExplanation {
+  /** Ausente quando a IA não está configurada */
+  ai?: ClassificationExplanationAI;
+  decision?: ClassificationDecision;
+  decisions?: ClassificationDecision[];
+  fetched_at?: string;
+  /** URL após redirecionamentos */
+  final_url?: string;
+  heuristics?: ClassificationExplanationHeuristics;
+  patterns?: ClassificationExplanationPatterns;
+  status_code?: number;
+  title?: string;
+  url?: string;
+}
+
+/** Ausente quando a IA não está configurada */
+export interface ClassificationExplanationAI {
+  calibrated_confidence?: number;
+  confidence?: number;
+  error?: string;
+  indicators?: string[];
+  is_property_page?: boolean;
+  page_type?: string;
+  reasoning?: string;
+}
+
+export interface ClassificationExplanationHeuristics {
+  content_calibrated_confidence?: number;
+  content_confidence?: number;
+  content_type?: string;
+  indicators?: ClassificationExplanationHeuristicsIndicators;
+  indicators_say_property?: boolean;
+  precise?: ClassificationExplanationHeuristicsPrecise;
+  strict_page_type?: string;
+}
+
+export interface ClassificationExplanationHeuristicsIndicators {
+  address?: boolean;
+  price?: boolean;
+  rooms?: boolean;
+}
+
+export interface ClassificationExplanationHeuristicsPrecise {
+  confidence?: number;
+  details?: ClassificationExplanationHeuristicsPreciseDetails;
+  is_individual_property?: boolean;
+  max_score?: number;
+  reason?: string;
+  score?: number;
+}
+
+export interface ClassificationExplanationHeuristicsPreciseDetails {
+  found_exclusions?: string[];
+  found_requirements?: string[];
+  has_contact_info?: boolean;
+  has_property_details?: boolean;
+  has_specific_address?: boolean;
+  has_specific_price?: boolean;
+  is_generic_page?: boolean;
+  is_institutional?: boolean;
+  word_count?: number;
+}
+
+export interface ClassificationExplanationPatterns {
+  learned_confidence?: number;
+  learned_type?: string;
+  numeric_slug?: boolean;
+  url_patterns?: string[];
+}
+
+export interface ClassifierExperimentReport {
+  agreements?: number;
+  candidate?: ClassifierExperimentReportCandidate;
+  classified?: number;
+  completed_at?: string;
+  control?: ClassifierExperimentReportControl;
+  divergence_rate?: number;
+  divergences?: number;
+  job_id?: string;
+  name?: string;
+  outcomes?: Record<string, Record<string, number>>;
+  sample_rate?: number;
+  sampled?: number;
+  samples?: ClassifierExperimentReportSample[];
+}
+
+export interface ClassifierExperimentReportCandidate {
+  min_confidence?: number;
+  min_requirements?: number;
+}
+
+export interface ClassifierExperimentReportControl {
+  min_confidence?: number;
+  min_requirements?: number;
+}
+
+export interface ClassifierExperimentReportSample {
+  confidence?: number;
+  decision?: string;
+  domain?: string;
+  outcome?: string;
+  recorded_at?: string;
+  requirements?: number;
+  url?: string;
+}
+
+export interface CollectionStorageStats {
+  data_size_bytes?: number;
+  documents?: number;
+  index_size_bytes?: number;
+  /** Limite de documentos (0 = sem limite) */
+  max_documents?: number;
+  name?: string;
+  newest?: string;
+  oldest?: string;
+  storage_bytes?: number;
+  /** Expiração do índice TTL (0 = desativado) */
+  ttl_seconds?: number;
+}
+
+export interface ConfigChange {
+  field?: string;
+  new_value?: string;
+  old_value?: string;
+  source?: string;
+  time?: string;
+  /** Versão da configuração em que a alteração foi aplicada */
+  version?: number;
+}
+
+export interface ContentPattern {
+  confidence?: number;
+  created_at?: string;
+  features?: ContentPatternFeatures;
+  id?: string;
+  match_count?: number;
+  type?: string;
+  updated_at?: string;
+}
+
+export interface ContentPatternFeatures {
+  has_filters?: boolean;
+  has_pagination?: boolean;
+  imoveis_count?: number;
+  price_count?: number;
+  text_length?: number;
+}
+
+export interface CrawlCostSummary {
+  ai_enabled?: boolean;
+  ai_tokens?: number;
+  avg_cost_per_job_usd?: number;
+  bytes?: number;
+  compute_seconds?: number;
+  /** 0 sem imóveis salvos */
+  cost_per_property_usd?: number;
+  jobs?: number;
+  mode?: string;
+  properties?: number;
+  properties_per_mbyte?: number;
+  total_cost_usd?: number;
+}
+
+export interface CrawlError {
+  domain?: string;
+  message?: string;
+  occurred_at?: string;
+  status_code?: number;
+  type?: string;
+  url?: string;
+}
+
+export interface CrawlJob {
+  /** Orçamento que encerrou o job antecipadamente */
+  budget_exhausted?: string;
+  cities?: string[];
+  classifier_experiment?: ClassifierExperimentReport;
+  completed_at?: string;
+  cost?: CrawlJobCost;
+  coverage?: DomainCoverage[];
+  /** URLs aguardando a retomada de domínios pausados */
+  deferred_urls?: number;
+  /** Domínios aos quais as seeds foram restritas */
+  domains?: string[];
+  error?: string;
+  id?: string;
+  max_duration_minutes?: number;
+  max_pages?: number;
+  /** Modo informado no disparo; `feed` para os jobs das entradas de feeds */
+  mode?: string;
+  paused_domains?: string[];
+  started_at?: string;
+  status?: string;
+  /** Tags registradas nos imóveis salvos pelo job */
+  tags?: string[];
+  /** Template usado no disparo */
+  template?: string;
+  /** Páginas agendadas para visita pelo job */
+  visited_pages?: number;
+}
+
+export interface CrawlJobCost {
+  ai_calls?: number;
+  ai_cost_usd?: number;
+  /** O job rodou com o serviço de IA disponível */
+  ai_enabled?: boolean;
+  ai_output_tokens?: number;
+  ai_prompt_tokens?: number;
+  bandwidth_cost_usd?: number;
+  /** Bytes baixados */
+  bytes?: number;
+  completed_at?: string;
+  compute_cost_usd?: number;
+  /** Duração da execução */
+  compute_seconds?: number;
+  /** 0 sem imóveis salvos */
+  cost_per_property_usd?: number;
+  job_id?: string;
+  mode?: string;
+  pages?: number;
+  /** Imóveis salvos */
+  properties?: number;
+  proxy_bytes?: number;
+  proxy_cost_usd?: number;
+  proxy_requests?: number;
+  requests?: number;
+  started_at?: string;
+  status?: string;
+  template?: string;
+  total_cost_usd?: number;
+}
+
+export interface CrawlJobTemplate {
+  /** Template padrão, não cadastrado */
+  builtin?: boolean;
+  /** Vazio para todas as cidades */
+  cities?: string[];
+  created_at?: string;
+  description?: string;
+  /** Restringe as seeds a estes domínios */
+  domains?: string[];
+  /** Omitido para usar a IA quando disponível */
+  enable_ai?: boolean;
+  /** Duração máxima em minutos (0 sem limite) */
+  max_duration_minutes?: number;
+  /** Máximo de páginas visitadas (0 sem limite) */
+  max_pages?: number;
+  /** full usa o crawler recursivo (todas as páginas); incremental usa o engine incremental, que pula as páginas sem mudanças e revisita cada URL no seu intervalo adaptativo */
+  mode?: string;
+  name?: string;
+  tags?: string[];
+  updated_at?: string;
+}
+
+export interface DnscacheStats {
+  /** Hosts em cache (resoluções e falhas) */
+  entries?: number;
+  failing?: DnscacheStatsFailingItem[];
+  hits?: number;
+  misses?: number;
+  /** Consultas respondidas pelo cache negativo (falhas recentes) */
+  negative_hits?: number;
+}
+
+export interface DnscacheStatsFailingItem {
+  failures?: number;
+  host?: string;
+  last_error?: string;
+  quarantined_until?: string;
+  skipped?: number;
+}
+
+export interface DiscoverySourceYield {
+  /** Crawlings em que a origem apareceu */
+  crawls?: number;
+  /** Origem desativada na configuração do domínio */
+  disabled?: boolean;
+  /** Anúncios encontrados a partir da origem */
+  properties?: number;
+  source?: string;
+  /** Páginas visitadas a partir da origem */
+  visited?: number;
+  /** Anúncios por página visitada */
+  yield?: number;
+}
+
+/** API JSON de busca do portal. Quando configurada, as seeds do domínio são lidas direto da API (sem crawling HTML) e os imóveis seguem o mesmo pós-processamento, validação e gravação das páginas. */
+export interface DomainApiconfig {
+  /** URL da API com os marcadores {page}, {offset} e {page_size} */
+  endpoint?: string;
+  /** Campo do imóvel (url, endereco, cidade, bairro, cep, descricao, valor, quartos, banheiros, area_total, area_util, tipo_imovel, caracteristicas, fotos) para o caminho no item; índices numéricos acessam listas (ex: "fotos.0.url") */
+  field_mapping?: Record<string, string>;
+  headers?: Record<string, string>;
+  /** Limite de páginas (padrão 50) */
+  max_pages?: number;
+  /** Itens por página (padrão 20) */
+  page_size?: number;
+  /** Caminho com pontos até a lista de anúncios (vazio se a resposta já é a lista) */
+  results_path?: string;
+  /** Primeira página (padrão 1) */
+  start_page?: number;
+  /** URL do anúncio montada com campos do item, usada se field_mapping.url não for informado */
+  url_template?: string;
+}
+
+export interface DomainConfig {
+  allow_subdomains?: boolean;
+  api?: DomainApiconfig;
+  crawl_windows?: string[];
+  created_at?: string;
+  disabled_discovery_sources?: string[];
+  domain?: string;
+  enable_js?: boolean;
+  exclude_patterns?: string[];
+  feeds?: string[];
+  id?: string;
+  include_patterns?: string[];
+  learned_skip_paths?: string[];
+  notes?: string;
+  photo_policy?: string;
+  photo_referer?: boolean;
+  post_process_script?: string;
+  rate_limit?: DomainConfigRateLimit;
+  selectors?: Record<string, string>;
+  timezone?: string;
+  updated_at?: string;
+}
+
+export interface DomainConfigRateLimit {
+  delay_ms?: number;
+  parallelism?: number;
+  requests_per_minute?: number;
+}
+
+export interface DomainConfigInput {
+  /** No modo estrito de domínios (CRAWL_STRICT_DOMAINS), permite também os subdomínios do domínio */
+  allow_subdomains?: boolean;
+  api?: DomainApiconfig;
+  /** Janelas do dia (HH:MM-HH:MM, podendo atravessar a meia-noite) em que o domínio pode ser crawleado. Fora delas as requisições ao domínio são adiadas para o próximo crawling. Vazio permite qualquer horário. */
+  crawl_windows?: string[];
+  /** Habilita renderização de JavaScript para o domínio */
+  enable_js?: boolean;
+  /** Expressões regulares de URLs ignoradas */
+  exclude_patterns?: string[];
+  /** Feeds RSS/Atom de novos anúncios do domínio (ver GET /crawler/feeds) */
+  feeds?: string[];
+  /** Expressões regulares de URLs permitidas */
+  include_patterns?: string[];
+  notes?: string;
+  /** Fotos em GET /properties/{id}/photos/{n}: proxy (padrão, busca e guarda em cache), redirect (o site permite hotlink) ou deny (fotos não exibidas) */
+  photo_policy?: string;
+  /** Envia a página do anúncio como Referer ao buscar as fotos (proteção contra hotlink) */
+  photo_referer?: boolean;
+  /** Script Lua executado após a extração. Recebe a tabela global `property` (endereco, cidade, bairro, cep, descricao, valor, valor_texto, quartos, banheiros, area_total, area_util, tipo_imovel, fotos, caracteristicas, custom_fields) além de `url` e `domain`, e a altera in-place. Apenas as bibliotecas base, string, table e math estão disponíveis. O script roda por até 200 ms, com até 64 chamadas aninhadas, e falha ao gerar textos acima de 1 MiB. */
+  post_process_script?: string;
+  rate_limit?: DomainConfigInputRateLimit;
+  /** Seletores CSS customizados por campo (endereco, cidade, bairro, descricao, valor, quartos, banheiros, area_total, tipo_imovel, caracteristicas) */
+  selectors?: Record<string, string>;
+  /** Fuso das janelas de crawling (padrão America/Sao_Paulo) */
+  timezone?: string;
+}
+
+export interface DomainConfigInputRateLimit {
+  delay_ms?: number;
+  parallelism?: number;
+  requests_per_minute?: number;
+}
+
+export interface DomainCoverage {
+  /** Maior total exibido nos catálogos */
+  catalog_total?: number;
+  /** Imóveis encontrados pelo crawling */
+  discovered_urls?: number;
+  domain?: string;
+  estimated_missed_percent?: number;
+  estimated_total?: number;
+  generated_at?: string;
+  /** URLs de imóveis no sitemap (-1 quando o site não tem sitemap) */
+  sitemap_property_urls?: number;
+  sources?: string[];
+}
+
+export interface DomainProbe {
+  dns?: DomainProbeDNS;
+  domain?: string;
+  http?: DomainProbeHTTP;
+  notes?: string[];
+  probed_at?: string;
+  tcp?: DomainProbeTCPItem[];
+  tls?: DomainProbeTLS;
+  verdict?: string;
+}
+
+export interface DomainProbeDNS {
+  duration_ms?: number;
+  error?: string;
+  ipv4?: string[];
+  ipv6?: string[];
+}
+
+export interface DomainProbeHTTP {
+  block_signals?: string[];
+  duration_ms?: number;
+  error?: string;
+  location?: string;
+  server?: string;
+  status_code?: number;
+  url?: string;
+}
+
+export interface DomainProbeTCPItem {
+  address?: string;
+  connected?: boolean;
+  duration_ms?: number;
+  error?: string;
+  family?: string;
+  port?: number;
+}
+
+export interface DomainProbeTLS {
+  address?: string;
+  duration_ms?: number;
+  error?: string;
+  issuer?: string;
+  not_after?: string;
+  subject?: string;
+  version?: string;
+}
+
+export interface DomainQuality {
+  /** Média de field_confidence (0 a 1) dos anúncios com notas */
+  avg_extraction_score?: number;
+  computed_at?: string;
+  day?: string;
+  domain?: string;
+  properties?: number;
+  scored_properties?: number;
+  with_address_pct?: number;
+  with_photos_pct?: number;
+  with_price_pct?: number;
+  with_rooms_pct?: number;
+}
+
+export interface DomainRateStats {
+  /** Atraso adicional aprendido com respostas 429/503 */
+  adaptive_delay_ms?: number;
+  base_delay_ms?: number;
+  domain?: string;
+  effective_delay_ms?: number;
+  last_status?: number;
+  last_throttled_at?: string;
+  /** Ritmo máximo resultante (0 = sem limite) */
+  requests_per_minute?: number;
+  /** Requisições bloqueadas até este horário (Retry-After) */
+  retry_after_until?: string;
+  success_count?: number;
+  throttled_count?: number;
+}
+
+export interface DuplicateCluster {
+  /** Imobiliárias (domínios) que publicam o imóvel */
+  agencies?: number;
+  bairro?: string;
+  cidade?: string;
+  listings?: DuplicateClusterListing[];
+  max_valor?: number;
+  min_valor?: number;
+  /** Diferença entre o maior e o menor preço (R$) */
+  price_spread?: number;
+  /** Diferença percentual sobre o menor preço */
+  price_spread_pct?: number;
+  quartos?: number;
+  tipo_imovel?: string;
+}
+
+export interface DuplicateClusterListing {
+  area?: number;
+  domain?: string;
+  endereco?: string;
+  url?: string;
+  valor?: number;
+}
+
+export interface Error {
+  code?: number;
+  error?: string;
+  message?: string;
+}
+
+export interface ErrorReport {
+  /** Domínio -> tipo de erro -> quantidade */
+  by_domain?: Record<string, Record<string, number>>;
+  by_type?: Record<string, number>;
+  samples?: CrawlError[];
+  total?: number;
+}
+
+export interface FacetCount {
+  count?: number;
+  /** Limite superior da faixa de preço (ausente na última faixa) */
+  max?: number;
+  /** Limite inferior (faixas de preço e quartos) */
+  min?: number;
+  value?: string;
+}
+
+export interface FeedStatus {
+  /** Entradas novas encontradas na última leitura */
+  entries?: number;
+  last_error?: string;
+  last_polled?: string;
+  url?: string;
+}
+
+export interface FieldProvenance {
+  extracted_at?: string;
+  extractor?: string;
+  selector?: string;
+}
+
+/** Par de anúncios com fotos idênticas e localização, endereço ou preço diferentes */
+export interface FraudReview {
+  created_at?: string;
+  /** Derivado das URLs dos dois anúncios (estável entre varreduras) */
+  id?: string;
+  /** Os dois anúncios do par */
+  listings?: FraudReviewListing[];
+  reasons?: string[];
+  resolved_at?: string;
+  /** Hashes perceptuais em comum */
+  shared_photos?: string[];
+  status?: string;
+  updated_at?: string;
+}
+
+export interface FraudReviewListing {
+  bairro?: string;
+  cidade?: string;
+  endereco?: string;
+  url?: string;
+  valor?: number;
+}
+
+export interface GlobalRateStats {
+  adjustments?: number;
+  /** Requisições simultâneas permitidas por domínio */
+  domain_parallelism?: number;
+  /** Requisições em andamento por domínio */
+  in_flight?: Record<string, number>;
+  max_domain_parallelism?: number;
+  /** Ritmo medido na última janela de ajuste */
+  observed_pages_per_minute?: number;
+  target_pages_per_minute?: number;
+  total_pages?: number;
+}
+
+export interface ImportReport {
+  /** Registros repetidos dentro do próprio arquivo */
+  duplicates?: number;
+  /** Primeiros 100 registros rejeitados */
+  errors?: ImportReportError[];
+  failed?: number;
+  format?: string;
+  imported?: number;
+  invalid?: number;
+  source?: string;
+  total?: number;
+}
+
+export interface ImportReportError {
+  errors?: string[];
+  line?: number;
+  reason?: string;
+}
+
+/** Resumo do projeto de loteamento, presente apenas no registro pai (os lotes compartilham o parent_id) */
+export interface Loteamento {
+  area_maxima?: number;
+  area_minima?: number;
+  nome?: string;
+  /** Maior preço por lote */
+  preco_maximo?: number;
+  /** Menor preço por lote */
+  preco_minimo?: number;
+  total_lotes?: number;
+}
+
+export interface Pagination {
+  current_page?: number;
+  has_next?: boolean;
+  has_previous?: boolean;
+  page_size?: number;
+  total_items?: number;
+  total_pages?: number;
+}
+
+export interface PathStats {
+  /** Crawlings completos em que o caminho foi visitado */
+  crawls?: number;
+  domain?: string;
+  /** Removido da lista pelo operador; não é aprendido de novo */
+  ignored?: boolean;
+  last_crawled_at?: string;
+  learned?: boolean;
+  learned_at?: string;
+  /** Páginas classificadas como não-anúncio */
+  non_property?: number;
+  path?: string;
+  /** Anúncios encontrados no caminho ou a partir dele */
+  productive?: number;
+}
+
+export interface PatternFile {
+  catalog?: Array<Record<string, unknown>>;
+  /** SHA-256 dos padrões ordenados */
+  checksum?: string;
+  exported_at?: string;
+  property?: Array<Record<string, unknown>>;
+  schema_version?: number;
+}
+
+export interface PatternImportDiff {
+  added?: string[];
+  changed?: string[];
+  removed?: string[];
+  unchanged?: number;
+}
+
+export interface PatternImportPreview {
+  checksum?: string;
+  /** Checksum dos padrões ativos no momento da prévia */
+  current_checksum?: string;
+  diff?: Record<string, PatternImportDiff>;
+  replace?: boolean;
+  schema_version?: number;
+}
+
+export interface PendingPatternImport {
+  created_at?: string;
+  expires_at?: string;
+  id?: string;
+  preview?: PatternImportPreview;
+}
+
+export interface PhotoFraudScan {
+  /** Anúncios marcados com suspeito_fraude */
+  flagged?: number;
+  /** Pares de anúncios suspeitos encontrados */
+  groups?: number;
+  /** Anúncios com hashes de fotos comparados */
+  listings?: number;
+  /** Itens aguardando revisão */
+  pending_reviews?: number;
+}
+
+export interface PriceIndexPoint {
+  /** Ausente no índice da cidade inteira */
+  bairro?: string;
+  cidade?: string;
+  computed_at?: string;
+  /** Mediana do valor por m² (R$) */
+  median_price_per_m2?: number;
+  /** Mês de referência (AAAA-MM) */
+  month?: string;
+  /** Anúncios usados no cálculo */
+  samples?: number;
+}
+
+export interface Property {
+  /** Comodidades normalizadas usadas no filtro caracteristicas */
+  amenidades?: string[];
+  /** Aquecimento a gás citado no anúncio */
+  aquecimento_gas?: boolean;
+  archive_url?: string;
+  archived_at?: string;
+  area_total?: number;
+  area_util?: number;
+  bairro?: string;
+  banheiros?: number;
+  /** Data das cotações usadas em valor_convertido */
+  cambio_data?: string;
+  /** Registro canônico do imóvel quando ele também é anunciado em outros portais */
+  canonical_id?: string;
+  caracteristicas?: string[];
+  cep?: string;
+  cidade?: string;
+  condicao?: string;
+  /** Confiança da IA na avaliação de condição (0-1) */
+  condicao_confianca?: number;
+  /** Valor ordinal da condição (1 = precisa reforma, 4 = novo) */
+  condicao_nivel?: number;
+  /** Campos corrigidos manualmente, mantidos nos próximos crawlings */
+  corrected_fields?: string[];
+  /** Campos adicionais preenchidos por etapas de extração customizadas ou scripts do domínio */
+  custom_fields?: Record<string, unknown>;
+  delisted_at?: string;
+  descricao?: string;
+  /** Descrição traduzida para inglês (opcional) */
+  descricao_en?: string;
+  /** Profundidade em que o anúncio foi descoberto a partir da URL inicial */
+  discovery_depth?: number;
+  /** Caminho de descoberta (seed → catálogo → página N → imóvel) */
+  discovery_path?: PropertyDiscoveryPathItem[];
+  /** Estratégia de descoberta que levou ao anúncio */
+  discovery_source?: string;
+  /** Distâncias em km até o centro da cidade e à escola e ao hospital mais próximos (OpenStreetMap), calculadas pelo backfill -poi para imóveis geocodificados */
+  distancias_poi?: PropertyDistanciasPoi;
+  endereco?: string;
+  /** Energia solar citada no anúncio */
+  energia_solar?: boolean;
+  /** Confiança de 0 a 1 por campo (somente com include=confidence) */
+  field_confidence?: Record<string, number>;
+  /** Finalidade de uso pelo tipo e pelo texto do anúncio */
+  finalidade?: string;
+  /** Hashes perceptuais (dHash, hexadecimal) das primeiras fotos */
+  foto_hashes?: string[];
+  /** URLs das fotos do anúncio (até 30) */
+  foto_urls?: string[];
+  /** Quantidade de fotos da galeria do anúncio (imagens fora da galeria não contam) */
+  fotos?: number;
+  /** Hash único da propriedade */
+  hash?: string;
+  id?: string;
+  /** Lance mínimo informado no anúncio */
+  lance_minimo?: number;
+  /** Anúncio de leilão (portal de leilões ou palavras-chave); fora das estatísticas de mercado */
+  leilao?: boolean;
+  /** Data do leilão (primeira praça citada) */
+  leilao_data?: string;
+  listing_state?: string;
+  /** Localização geocodificada (GeoJSON Point, coordinates = [longitude, latitude]) */
+  location?: PropertyLocation;
+  /** Identificação do lote, nos registros filhos de um loteamento */
+  lote?: string;
+  loteamento?: Loteamento;
+  /** Imóvel com campos corrigidos manualmente (PATCH /properties/{id}) */
+  manually_corrected?: boolean;
+  /** Estadia mínima do aluguel por temporada, em noites */
+  minimo_noites?: number;
+  /** Identificador compartilhado pelas unidades de um anúncio com múltiplas unidades */
+  parent_id?: string;
+  /** Poço artesiano ou semiartesiano citado no anúncio */
+  poco_artesiano?: boolean;
+  /** Etapa de extração que produziu cada campo (chave = nome do campo) */
+  provenance?: Record<string, FieldProvenance>;
+  quartos?: number;
+  /** Risco de golpe de 0 a 100 (soma dos pesos dos sinais encontrados) */
+  risco_golpe?: number;
+  /** Versão do formato do documento (documentos antigos são migrados na leitura) */
+  schema_version?: number;
+  /** Anúncio sem nenhuma foto (sinal de baixa qualidade) */
+  sem_fotos?: boolean;
+  short_id?: string;
+  sinais_golpe?: string[];
+  /** Origem do registro */
+  source?: string;
+  /** Formato do conteúdo de onde o anúncio foi extraído (ausente para páginas HTML) */
+  source_format?: string;
+  /** Usa as mesmas fotos de outro anúncio com endereço ou preço diferentes */
+  suspeito_fraude?: boolean;
+  /** Risco de golpe a partir de 50 */
+  suspeito_golpe?: boolean;
+  /** Tags dos jobs de crawling que salvaram ou encontraram novamente o imóvel */
+  tags?: string[];
+  /** A página do anúncio traz telefone, WhatsApp ou e-mail (ausente quando não verificado) */
+  tem_contato?: boolean;
+  /** Testada (metros de frente do imóvel) */
+  testada?: number;
+  /** Tipo como informado pelo site */
+  tipo_imovel?: string;
+  /** Tipo convertido para a taxonomia usada nos filtros e estatísticas */
+  tipo_normalizado?: string;
+  url?: string;
+  valor?: number;
+  /** Aluguel mensal */
+  valor_aluguel?: number;
+  /** Preço na moeda pedida via ?currency= (cotação do dia do crawling) */
+  valor_convertido?: Record<string, number>;
+  /** Valor da diária do aluguel por temporada */
+  valor_diaria?: number;
+  /** Preço por m² (valor / área útil, ou área total) */
+  valor_m2?: number;
+  valor_texto?: string;
+  /** Valor de venda (separado do aluguel quando o anúncio cita os dois) */
+  valor_venda?: number;
+  /** Indicações de zoneamento citadas (comercial, misto, residencial, industrial, zeis, corredor_comercial) */
+  zoneamento?: string[];
+}
+
+export interface PropertyDiscoveryPathItem {
+  kind?: string;
+  page?: number;
+  url?: string;
+}
+
+/** Distâncias em km até o centro da cidade e à escola e ao hospital mais próximos (OpenStreetMap), calculadas pelo backfill -poi para imóveis geocodificados */
+export interface PropertyDistanciasPoi {
+  centro_km?: number;
+  escola_km?: number;
+  hospital_km?: number;
+}
+
+/** Localização geocodificada (GeoJSON Point, coordinates = [longitude, latitude]) */
+export interface PropertyLocation {
+  coordinates?: number[];
+  type?: string;
+}
+
+export interface PropertyCorrection {
+  changes?: PropertyCorrectionChange[];
+  corrected_at?: string;
+  corrected_by?: string;
+  id?: string;
+  property_id?: string;
+  reason?: string;
+  url?: string;
+}
+
+export interface PropertyCorrectionChange {
+  field?: string;
+  new_value?: unknown;
+  old_value?: unknown;
+}
+
+export interface PropertyFacets {
+  bairros?: FacetCount[];
+  cidade?: string;
+  faixas_preco?: FacetCount[];
+  /** Finalidade de uso (residencial, comercial, rural, temporada) */
+  finalidades?: FacetCount[];
+  /** Número de quartos; o último grupo ("5+") reúne 5 ou mais */
+  quartos?: FacetCount[];
+  tipos_imovel?: FacetCount[];
+  total?: number;
+}
+
+export interface PruneResult {
+  fingerprints_removed?: number;
+  ran_at?: string;
+  urls_removed?: number;
+}
+
+export interface RequestAuditEntry {
+  /** Bytes do corpo lidos */
+  bytes?: number;
+  domain?: string;
+  duration_ms?: number;
+  error?: string;
+  job_id?: string;
+  method?: string;
+  /** 0 quando a requisição falhou sem resposta */
+  status_code?: number;
+  time?: string;
+  url?: string;
+}
+
+export interface RequestAuditSummary {
+  avg_duration_ms?: number;
+  bytes?: number;
+  domain?: string;
+  /** Falhas de rede e respostas com status >= 400 */
+  errors?: number;
+  first_request?: string;
+  last_request?: string;
+  /** Menor intervalo entre duas requisições (-1 com uma única requisição) */
+  min_interval_ms?: number;
+  requests?: number;
+  requests_per_minute?: number;
+}
+
+export interface SiteMigrationResult {
+  cities?: number;
+  dry_run?: boolean;
+  files?: string[];
+  groups?: SiteMigrationResultGroup[];
+  sites_added?: number;
+  sites_existing?: number;
+  /** URLs sem cidade deduzida */
+  unresolved?: string[];
+  urls?: number;
+}
+
+export interface SiteMigrationResultGroup {
+  city?: SiteMigrationResultGroupCity;
+  sites?: CitySite[];
+}
+
+export interface SiteMigrationResultGroupCity {
+  name?: string;
+  region?: string;
+  state?: string;
+}
+
+export interface UrlstorageStats {
+  fingerprints?: CollectionStorageStats;
+  last_prune?: PruneResult;
+  processed_urls?: CollectionStorageStats;
+}
+
+export interface UnitConversion {
+  formatted?: string;
+  from?: string;
+  input?: string;
+  result?: number;
+  to?: string;
+  value?: number;
+}
+
+export interface WhatsAppLead {
+  area_total?: number;
+  bairro?: string;
+  banheiros?: number;
+  cidade?: string;
+  /** Código de referência do anúncio citado na mensagem */
+  codigo?: string;
+  /** Confiança do lead (no máximo 0.5) */
+  confidence?: number;
+  domain?: string;
+  first_seen_at?: string;
+  /** Vezes em que o link foi encontrado */
+  hits?: number;
+  id?: string;
+  last_seen_at?: string;
+  /** Página onde o link foi encontrado */
+  page_url?: string;
+  phone?: string;
+  quartos?: number;
+  text?: string;
+  tipo_imovel?: string;
+  valor?: number;
+}
+
+export interface GetAllCitiesResponse {
+  data?: City[];
+  message?: string;
+}
+
+export interface DiscoverSitesRequest {
+  /** Nome da cidade */
+  city?: string;
+  /** Limite de sites a descobrir */
+  limit?: number;
+  /** Validar sites descobertos */
+  validate?: boolean;
+}
+
+export interface DiscoverSitesResponse {
+  data?: DiscoverSitesResponseData;
+  message?: string;
+}
+
+export interface DiscoverSitesResponseData {
+  city?: string;
+  job_id?: string;
+  sites?: CitySite[];
+  sites_found?: number;
+}
+
+export interface ImportCitySitesResponse {
+  data?: CitySitesImportResult;
+  message?: string;
+}
+
+export interface MigrateSiteFilesRequest {
+  /** Cidade dos domínios sem cidade deduzida (vazio os lista em unresolved) */
+  default_city?: string;
+  default_state?: string;
+  /** Apenas calcula o resultado, sem gravar */
+  dry_run?: boolean;
+  /** Arquivos relativos ao diretório do projeto */
+  files?: string[];
+}
+
+export interface MigrateSiteFilesResponse {
+  data?: SiteMigrationResult;
+  message?: string;
+}
+
+export interface GetCityByNameResponse {
+  data?: City;
+  message?: string;
+}
+
+export interface GetCitySitesResponse {
+  data?: CitySite[];
+  message?: string;
+}
+
+export interface SetCityRefreshSLARequest {
+  sla_hours?: number;
+}
+
+export interface SetCityRefreshSLAResponse {
+  data?: CityFreshness;
+  message?: string;
+}
+
+export interface GetAitokenSavingsResponse {
+  data?: AitokenSavings;
+  message?: string;
+}
+
+export interface GetBrokenPhotosResponse {
+  data?: BrokenPhoto[];
+  message?: string;
+}
+
+export interface GetClassifierExperimentsResponse {
+  data?: ClassifierExperimentReport[];
+  message?: string;
+}
+
+export interface CleanupDatabaseRequest {
+  /** Limpar tudo */
+  all?: boolean;
+  /** Limpar propriedades */
+  properties?: boolean;
+  /** Limpar URLs visitadas */
+  urls?: boolean;
+}
+
+export interface CleanupDatabaseResponse {
+  message?: string;
+  properties_cleared?: boolean;
+  success?: boolean;
+  urls_cleared?: boolean;
+}
+
+export interface GetConfigChangesResponse {
+  data?: ConfigChange[];
+  message?: string;
+}
+
+export interface GetCrawlJobCostsResponse {
+  data?: GetCrawlJobCostsResponseData;
+  message?: string;
+}
+
+export interface GetCrawlJobCostsResponseData {
+  by_mode?: CrawlCostSummary[];
+  jobs?: CrawlJobCost[];
+}
+
+export interface GetCoverageResponse {
+  data?: DomainCoverage[];
+  message?: string;
+}
+
+export interface GetDiscoverySourcesResponse {
+  data?: DiscoverySourceYield[];
+  message?: string;
+}
+
+export interface GetDnscacheResponse {
+  data?: DnscacheStats;
+  message?: string;
+}
+
+export interface GetDomainRatesResponse {
+  data?: DomainRateStats[];
+  message?: string;
+}
+
+export interface GetCrawlErrorsResponse {
+  data?: ErrorReport;
+  message?: string;
+}
+
+export interface GetFeedStatusResponse {
+  data?: GetFeedStatusResponseData;
+  message?: string;
+}
+
+export interface GetFeedStatusResponseData {
+  feeds?: FeedStatus[];
+  /** Anúncios aguardando crawling */
+  pending?: number;
+}
+
+export interface GetCrawlJobsResponse {
+  data?: CrawlJob[];
+  message?: string;
+}
+
+export interface TriggerCrawlerRequest {
+  cities?: string[];
+  mode?: string;
+  tags?: string[];
+}
+
+export interface TriggerCrawlerResponse {
+  data?: TriggerCrawlerResponseData;
+  message?: string;
+}
+
+export interface TriggerCrawlerResponseData {
+  job_id?: string;
+  status?: string;
+}
+
+export interface GetCrawlJobResponse {
+  data?: CrawlJob;
+  message?: string;
+}
+
+export interface CancelCrawlJobResponse {
+  data?: CrawlJob;
+  message?: string;
+}
+
+export interface PauseCrawlJobDomainResponse {
+  data?: CrawlJob;
+  message?: string;
+}
+
+export interface ResumeCrawlJobDomainResponse {
+  data?: CrawlJob;
+  message?: string;
+}
+
+export interface GetLeaderStatusResponse {
+  data?: GetLeaderStatusResponseData;
+  message?: string;
+}
+
+export interface GetLeaderStatusResponseData {
+  enabled?: boolean;
+  identity?: string;
+  last_error?: string;
+  leader?: boolean;
+  leader_since?: string;
+  lease?: GetLeaderStatusResponseDataLease;
+}
+
+export interface GetLeaderStatusResponseDataLease {
+  acquired_at?: string;
+  expires_at?: string;
+  holder?: string;
+  name?: string;
+  renewed_at?: string;
+}
+
+export interface GetGlobalRateResponse {
+  data?: GlobalRateStats;
+  message?: string;
+}
+
+export interface GetRequestAuditsResponse {
+  data?: RequestAuditEntry[];
+  message?: string;
+}
+
+export interface GetRequestAuditSummaryResponse {
+  data?: RequestAuditSummary[];
+  message?: string;
+}
+
+export interface GetJobTemplatesResponse {
+  data?: CrawlJobTemplate[];
+  message?: string;
+}
+
+export interface GetJobTemplateResponse {
+  data?: CrawlJobTemplate;
+  message?: string;
+}
+
+export interface SaveJobTemplateRequest {
+  cities?: string[];
+  description?: string;
+  domains?: string[];
+  enable_ai?: boolean;
+  max_duration_minutes?: number;
+  max_pages?: number;
+  mode?: string;
+  tags?: string[];
+}
+
+export interface SaveJobTemplateResponse {
+  data?: CrawlJobTemplate;
+  message?: string;
+}
+
+export interface RunJobTemplateRequest {
+  cities?: string[];
+  domains?: string[];
+  enable_ai?: boolean;
+  max_duration_minutes?: number;
+  max_pages?: number;
+  mode?: string;
+  tags?: string[];
+}
+
+export interface RunJobTemplateResponse {
+  data?: CrawlJob;
+  message?: string;
+}
+
+export interface PostCrawlerTriggerRequest {
+  /** Lista de cidades para crawling */
+  cities?: string[];
+  /** Forçar recrawling de URLs já visitadas */
+  force?: boolean;
+  /** Máximo de páginas por site */
+  max_pages?: number;
+  /** Tags registradas em cada imóvel salvo ou encontrado novamente pelo job (minúsculas, letras, dígitos, '.', '_', ':' ou '-'; até 10). Também aceitas na query, `?tag=muzambinho-weekly` */
+  tags?: string[];
+}
+
+export interface PostCrawlerTriggerResponse {
+  data?: PostCrawlerTriggerResponseData;
+  message?: string;
+}
+
+export interface PostCrawlerTriggerResponseData {
+  cities?: string[];
+  estimated_duration?: string;
+  /** ID do job para consulta, cancelamento e pausa */
+  job_id?: string;
+  status?: string;
+}
+
+export interface GetUrlstorageStatsResponse {
+  data?: UrlstorageStats;
+  message?: string;
+}
+
+export interface PruneUrlstorageResponse {
+  data?: PruneResult;
+  message?: string;
+}
+
+export interface ExplainClassificationResponse {
+  data?: ClassificationExplanation;
+  message?: string;
+}
+
+export interface ProbeDomainResponse {
+  data?: DomainProbe;
+  message?: string;
+}
+
+export interface GetAllDomainConfigsResponse {
+  data?: DomainConfig[];
+  message?: string;
+}
+
+export interface GetDomainConfigResponse {
+  data?: DomainConfig;
+  message?: string;
+}
+
+export interface SaveDomainConfigResponse {
+  data?: DomainConfig;
+  message?: string;
+}
+
+export interface GetSkipPathsResponse {
+  data?: GetSkipPathsResponseData;
+  message?: string;
+}
+
+export interface GetSkipPathsResponseData {
+  domain?: string;
+  learned_skip_paths?: string[];
+  paths?: PathStats[];
+}
+
+export interface GetFraudReviewsResponse {
+  data?: FraudReview[];
+  message?: string;
+}
+
+export interface ResolveFraudReviewRequest {
+  status?: string;
+}
+
+export interface ResolveFraudReviewResponse {
+  data?: FraudReview;
+  message?: string;
+}
+
+export interface DetectPhotoFraudResponse {
+  data?: PhotoFraudScan;
+  message?: string;
+}
+
+export interface GetHealthResponse {
+  features?: string[];
+  service?: string;
+  status?: string;
+  /** Estado da conexão com o MongoDB (heartbeats do driver) */
+  storage?: GetHealthResponseStorage;
+  version?: string;
+}
+
+/** Estado da conexão com o MongoDB (heartbeats do driver) */
+export interface GetHealthResponseStorage {
+  down_since?: string;
+  failed_operations?: number;
+  last_error?: string;
+  last_error_at?: string;
+  last_heartbeat?: string;
+  reconnects?: number;
+  retried_operations?: number;
+  servers?: Record<string, boolean>;
+  status?: string;
+}
+
+export interface GetWhatsAppLeadsResponse {
+  data?: WhatsAppLead[];
+  message?: string;
+}
+
+export interface GetPropertyByShortIDResponse {
+  data?: Property;
+  message?: string;
+}
+
+export interface GetLearnedPatternsResponse {
+  data?: GetLearnedPatternsResponseData;
+  message?: string;
+}
+
+export interface GetLearnedPatternsResponseData {
+  catalog_patterns_count?: number;
+  patterns?: Record<string, unknown>;
+  property_patterns_count?: number;
+}
+
+export interface ImportPatternsResponse {
+  data?: PatternImportPreview;
+  message?: string;
+}
+
+export interface GetPendingPatternImportsResponse {
+  data?: PendingPatternImport[];
+}
+
+export interface GetPropertiesResponse {
+  data?: Property[];
+  message?: string;
+  pagination?: Pagination;
+}
+
+export interface GetCanonicalPropertiesResponse {
+  data?: CanonicalProperty[];
+  message?: string;
+}
+
+export interface MergeDuplicatesResponse {
+  data?: CanonicalMergeResult;
+  message?: string;
+}
+
+export interface GetCanonicalPropertyResponse {
+  data?: CanonicalProperty;
+  message?: string;
+}
+
+export interface GetPropertyFacetsResponse {
+  data?: PropertyFacets;
+  message?: string;
+}
+
+export interface ImportPropertiesResponse {
+  data?: ImportReport;
+  message?: string;
+}
+
+export interface SearchPropertiesResponse {
+  data?: Property[];
+  message?: string;
+  total_found?: number;
+}
+
+export interface PatchPropertyRequest {
+  area_total?: number;
+  area_util?: number;
+  bairro?: string;
+  banheiros?: number;
+  caracteristicas?: string[];
+  cep?: string;
+  cidade?: string;
+  corrected_by?: string;
+  descricao?: string;
+  endereco?: string;
+  quartos?: number;
+  reason?: string;
+  tipo_imovel?: string;
+  valor?: number;
+}
+
+export interface PatchPropertyResponse {
+  data?: PatchPropertyResponseData;
+  message?: string;
+}
+
+export interface PatchPropertyResponseData {
+  correction?: PropertyCorrection;
+  property?: Property;
+}
+
+export interface GetPropertyCorrectionsResponse {
+  data?: PropertyCorrection[];
+  message?: string;
+}
+
+export interface GetDuplicateReportResponse {
+  data?: DuplicateCluster[];
+  message?: string;
+}
+
+export interface GetPriceIndexResponse {
+  data?: PriceIndexPoint[];
+  message?: string;
+}
+
+export interface GetQualityMetricsResponse {
+  data?: GetQualityMetricsResponseData;
+  message?: string;
+}
+
+export interface GetQualityMetricsResponseData {
+  domains?: DomainQuality[];
+  trend?: DomainQuality[];
+}
+
+export interface ConvertUnitsResponse {
+  data?: UnitConversion;
+  message?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getAllCities */
+export interface GetAllCitiesParams {
+  /** Retorna só as cidades desatualizadas além do SLA */
+  stale?: boolean;
+}
+
+/** Parâmetros de query e cabeçalho de exportCitySites */
+export interface ExportCitySitesParams {
+  format?: string;
+}
+
+/** Parâmetros de query e cabeçalho de importCitySites */
+export interface ImportCitySitesParams {
+  /** Substitui os sites das cidades do arquivo em vez de juntar */
+  replace?: boolean;
+  /** Apenas calcula o resultado, sem gravar */
+  dry_run?: boolean;
+}
+
+/** Parâmetros de query e cabeçalho de getBairroBoundaries */
+export interface GetBairroBoundariesParams {
+  /** Mínimo de anúncios geocodificados para o bairro entrar no mapa */
+  min_listings?: number;
+}
+
+/** Parâmetros de query e cabeçalho de setCityRefreshSLA */
+export interface SetCityRefreshSLAParams {
+  state?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getClassifierExperiments */
+export interface GetClassifierExperimentsParams {
+  /** Filtrar por um job */
+  job_id?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getConfigChanges */
+export interface GetConfigChangesParams {
+  /** Máximo de alterações (padrão 100, até 500) */
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getCrawlJobCosts */
+export interface GetCrawlJobCostsParams {
+  /** Filtrar pelo modo do job (full, incremental) */
+  mode?: string;
+  /** Apenas jobs terminados nos últimos N dias (1 a 365) */
+  days?: number;
+  /** Quantidade máxima de jobs (padrão 100, máximo 1000) */
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getDiscoverySources */
+export interface GetDiscoverySourcesParams {
+  domain?: string;
+  /** Número de crawlings considerados (padrão 10, máximo 100) */
+  crawls?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getCrawlErrors */
+export interface GetCrawlErrorsParams {
+  /** Filtrar por tipo de erro */
+  type?: string;
+  /** Filtrar por domínio */
+  domain?: string;
+  /** Quantidade de amostras recentes (padrão 50, máximo 500) */
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de triggerCrawler */
+export interface TriggerCrawlerParams {
+  /** Chave única do disparo (até 255 caracteres). Repetida dentro de CRAWL_IDEMPOTENCY_WINDOW (padrão 24h), retorna o job original sem iniciar outro crawling, com o cabeçalho `Idempotent-Replayed: true`, inclusive em outra réplica. A mesma chave com outros parâmetros retorna 422; enquanto outra requisição cria o job, 409. */
+  "Idempotency-Key"?: string;
+}
+
+/** Parâmetros de query e cabeçalho de pauseCrawlJobDomain */
+export interface PauseCrawlJobDomainParams {
+  domain?: string;
+}
+
+/** Parâmetros de query e cabeçalho de resumeCrawlJobDomain */
+export interface ResumeCrawlJobDomainParams {
+  domain?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getRequestAudits */
+export interface GetRequestAuditsParams {
+  job_id?: string;
+  domain?: string;
+  /** Código HTTP da resposta */
+  status?: number;
+  /** AAAA-MM-DD (início do dia) ou RFC 3339 */
+  since?: string;
+  /** AAAA-MM-DD (fim do dia) ou RFC 3339 */
+  until?: string;
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getRequestAuditSummary */
+export interface GetRequestAuditSummaryParams {
+  job_id?: string;
+  domain?: string;
+  /** Código HTTP da resposta */
+  status?: number;
+  /** AAAA-MM-DD (início do dia) ou RFC 3339 */
+  since?: string;
+  /** AAAA-MM-DD (fim do dia) ou RFC 3339 */
+  until?: string;
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de runJobTemplate */
+export interface RunJobTemplateParams {
+  /** Chave única do disparo (até 255 caracteres). Repetida dentro de CRAWL_IDEMPOTENCY_WINDOW (padrão 24h), retorna o job original sem iniciar outro crawling, com o cabeçalho `Idempotent-Replayed: true`, inclusive em outra réplica. A mesma chave com outros parâmetros retorna 422; enquanto outra requisição cria o job, 409. */
+  "Idempotency-Key"?: string;
+  tag?: string[];
+}
+
+/** Parâmetros de query e cabeçalho de postCrawlerTrigger */
+export interface PostCrawlerTriggerParams {
+  /** Chave única do disparo (até 255 caracteres). Repetida dentro de CRAWL_IDEMPOTENCY_WINDOW (padrão 24h), retorna o job original sem iniciar outro crawling, com o cabeçalho `Idempotent-Replayed: true`, inclusive em outra réplica. A mesma chave com outros parâmetros retorna 422; enquanto outra requisição cria o job, 409. */
+  "Idempotency-Key"?: string;
+}
+
+/** Parâmetros de query e cabeçalho de explainClassification */
+export interface ExplainClassificationParams {
+  /** URL http(s) da página */
+  url?: string;
+  /** Engine da decisão final (recursive, usado por /crawler/trigger; improved; ai_integrated) */
+  engine?: string;
+}
+
+/** Parâmetros de query e cabeçalho de probeDomain */
+export interface ProbeDomainParams {
+  /** Domínio ou URL */
+  domain?: string;
+}
+
+/** Parâmetros de query e cabeçalho de deleteSkipPath */
+export interface DeleteSkipPathParams {
+  path?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getFraudReviews */
+export interface GetFraudReviewsParams {
+  /** Filtra pela situação (todas se omitido) */
+  status?: string;
+  /** Máximo de itens retornados (padrão 100, máximo 500) */
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getWhatsAppLeads */
+export interface GetWhatsAppLeadsParams {
+  domain?: string;
+  cidade?: string;
+  /** Máximo de leads retornados (padrão 100, máximo 1000) */
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getPropertyByShortID */
+export interface GetPropertyByShortIDParams {
+  /** json para retornar o imóvel em vez de redirecionar */
+  format?: string;
+}
+
+/** Parâmetros de query e cabeçalho de importPatterns */
+export interface ImportPatternsParams {
+  dry_run?: boolean;
+}
+
+/** Parâmetros de query e cabeçalho de getProperties */
+export interface GetPropertiesParams {
+  /** Campos opcionais na resposta, separados por vírgula (confidence = field_confidence) */
+  include?: string;
+  /** Inclui o preço convertido para a moeda (valor_convertido e cambio_data). Requer EXCHANGE_RATES_URL; imóveis salvos antes da conversão usam as cotações do dia. */
+  currency?: string;
+  /** Retorna o acervo como estava na data: para cada anúncio (URL), a versão mais recente gravada até então; as unidades de um anúncio dividido (quartos, lotes) têm cada uma a sua. Aceita AAAA-MM-DD (até o fim do dia, UTC) ou RFC 3339. */
+  as_of?: string;
+  /** Número da página (padrão 1) */
+  page?: number;
+  /** Itens por página (padrão 10, máximo 100) */
+  page_size?: number;
+  /** Filtrar por cidade */
+  cidade?: string;
+  /** Filtrar por tipo de imóvel (tipo informado pelo site ou da taxonomia) */
+  tipo_imovel?: string;
+  /** Valor mínimo */
+  valor_min?: number;
+  /** Valor máximo */
+  valor_max?: number;
+}
+
+/** Parâmetros de query e cabeçalho de getCanonicalProperties */
+export interface GetCanonicalPropertiesParams {
+  /** Cidade (todas se omitida) */
+  city?: string;
+  /** Máximo de registros (padrão 100, máximo 500) */
+  limit?: number;
+}
+
+/** Parâmetros de query e cabeçalho de mergeDuplicates */
+export interface MergeDuplicatesParams {
+  /** Cidade (case-insensitive) */
+  city?: string;
+  /** Diferença relativa máxima de área entre os anúncios (padrão 0.05) */
+  area_tolerance?: number;
+  /** Inclui anúncios de leilão (excluídos por padrão) */
+  include_auctions?: boolean;
+  /** Inclui aluguéis por temporada (excluídos por padrão) */
+  include_vacation_rentals?: boolean;
+}
+
+/** Parâmetros de query e cabeçalho de getPropertyFacets */
+export interface GetPropertyFacetsParams {
+  /** Cidade para restringir as contagens (busca sem acentos, case-insensitive) */
+  city?: string;
+}
+
+/** Parâmetros de query e cabeçalho de importProperties */
+export interface ImportPropertiesParams {
+  /** Formato do arquivo (se omitido, é inferido do Content-Type) */
+  format?: string;
+}
+
+/** Parâmetros de query e cabeçalho de searchProperties */
+export interface SearchPropertiesParams {
+  /** Campos opcionais na resposta, separados por vírgula (confidence = field_confidence) */
+  include?: string;
+  /** Inclui o preço convertido para a moeda (valor_convertido e cambio_data). Requer EXCHANGE_RATES_URL; imóveis salvos antes da conversão usam as cotações do dia. */
+  currency?: string;
+  /** Termo de busca (endereço, descrição, etc.) */
+  q?: string;
+  /** Cidade */
+  cidade?: string;
+  /** Bairro */
+  bairro?: string;
+  /** Tipo de imóvel */
+  tipo_imovel?: string;
+  /** Número mínimo de quartos */
+  quartos_min?: number;
+  /** Número máximo de quartos */
+  quartos_max?: number;
+  /** Valor mínimo */
+  valor_min?: number;
+  /** Valor máximo */
+  valor_max?: number;
+  /** Área mínima */
+  area_min?: number;
+  /** Área máxima */
+  area_max?: number;
+  /** Condição do imóvel estimada pela IA */
+  condicao?: string;
+  /** Nível mínimo de condição (1 = precisa reforma, 4 = novo) */
+  condicao_min?: number;
+  /** Quantidade mínima de fotos do anúncio */
+  fotos_min?: number;
+  /** Apenas anúncios com pelo menos uma foto */
+  com_fotos?: boolean;
+  /** Apenas imóveis com energia solar (placas fotovoltaicas) */
+  energia_solar?: boolean;
+  /** Apenas imóveis com poço artesiano ou semiartesiano */
+  poco_artesiano?: boolean;
+  /** Apenas imóveis com aquecimento a gás */
+  aquecimento_gas?: boolean;
+  /** true retorna apenas leilões; false exclui leilões (omitido inclui todos) */
+  leilao?: boolean;
+  /** Finalidade de uso do imóvel (temporada para aluguel por diárias) */
+  finalidade?: string;
+  /** Aluguel mensal mínimo */
+  aluguel_min?: number;
+  /** Aluguel mensal máximo */
+  aluguel_max?: number;
+  /** Apenas anúncios com aluguel mensal */
+  apenas_aluguel?: boolean;
+  /** Exclui anúncios marcados com suspeito_golpe ou suspeito_fraude */
+  ocultar_suspeitos?: boolean;
+  /** Risco de golpe máximo (0 a 100) */
+  risco_golpe_max?: number;
+  /** Comodidades exigidas, separadas por vírgula (todas devem estar presentes) */
+  caracteristicas?: string;
+  /** Tags de crawling exigidas, separadas por vírgula (todas devem estar presentes) */
+  tags?: string;
+  /** Distância máxima em km até o centro da cidade (imóveis sem distancias_poi ficam de fora) */
+  max_dist_centro_km?: number;
+  /** Distância máxima em km até a escola mais próxima */
+  max_dist_escola_km?: number;
+  /** Distância máxima em km até o hospital mais próximo */
+  max_dist_hospital_km?: number;
+}
+
+/** Parâmetros de query e cabeçalho de streamProperties */
+export interface StreamPropertiesParams {
+  /** Campos opcionais na resposta, separados por vírgula (confidence = field_confidence) */
+  include?: string;
+  /** Cursor da última linha de controle (vazio começa do início) */
+  cursor?: string;
+  /** Máximo de imóveis nesta resposta (0 sem limite); done=false indica que há mais */
+  limit?: number;
+  q?: string;
+  cidade?: string;
+  bairro?: string;
+  tipo_imovel?: string;
+  valor_min?: number;
+  valor_max?: number;
+  tags?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getDuplicateReport */
+export interface GetDuplicateReportParams {
+  /** Cidade (case-insensitive) */
+  city?: string;
+  /** Máximo de grupos retornados (padrão 100, máximo 500) */
+  limit?: number;
+  /** Diferença relativa máxima de área entre os anúncios (padrão 0.05) */
+  area_tolerance?: number;
+  /** Inclui anúncios de leilão (excluídos por padrão) */
+  include_auctions?: boolean;
+  /** Inclui aluguéis por temporada (excluídos por padrão) */
+  include_vacation_rentals?: boolean;
+}
+
+/** Parâmetros de query e cabeçalho de getPriceIndex */
+export interface GetPriceIndexParams {
+  /** Cidade (busca sem acentos, case-insensitive) */
+  city?: string;
+  /** Bairro; se omitido, retorna o índice da cidade inteira */
+  bairro?: string;
+}
+
+/** Parâmetros de query e cabeçalho de getQualityMetrics */
+export interface GetQualityMetricsParams {
+  domain?: string;
+  days?: number;
+}
+
+/** Parâmetros de query e cabeçalho de convertUnits */
+export interface ConvertUnitsParams {
+  /** Valor no formato brasileiro, com ou sem unidade */
+  value?: string;
+  /** Unidade de origem (m2, ha, alqueire, alqueire_paulista, alqueire_baiano ou brl) */
+  from?: string;
+  /** Unidade de destino da área (padrão m2) */
+  to?: string;
+}
@@ -0,0 +1,111 @@
+// Cliente HTTP base do cliente TypeScript. Os modelos e os métodos de cada endpoint
+// (models.ts e client.ts) são gerados da especificação OpenAPI com `make sdk`.
+
+export interface ClientOptions {
+  /** Implementação do fetch (padrão: o fetch global) */
+  fetch?: typeof fetch;
+  /** Cabeçalhos enviados em todas as requisições (ex.: Authorization) */
+  headers?: Record<string, string>;
+}
+
+/** Resposta de erro da API (status fora de 2xx) */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly body: unknown;
+
+  constructor(status: number, message: string, body: unknown) {
+    super(message ? `api error ${status}: ${message}` : `api error ${status}`);
+    this.name = 'ApiError';
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export interface RequestOptions {
+  method: string;
+  path: string;
+  query?: Record<string, unknown>;
+  headers?: Record<string, unknown>;
+  body?: unknown;
+  rawBody?: BodyInit;
+  contentType?: string;
+  responseType: 'json' | 'blob' | 'none';
+}
+
+/** Converte o valor do parâmetro em texto; undefined, null e '' não são enviados */
+function encodeValues(value: unknown): string[] {
+  if (value === undefined || value === null || value === '') {
+    return [];
+  }
+  if (Array.isArray(value)) {
+    return value.flatMap(encodeValues);
+  }
+  if (value instanceof Date) {
+    return [value.toISOString()];
+  }
+  return [String(value)];
+}
+
+export class BaseClient {
+  private readonly baseUrl: string;
+  private readonly fetchImpl: typeof fetch;
+  private readonly headers: Record<string, string>;
+
+  constructor(baseUrl: string, options: ClientOptions = {}) {
+    this.baseUrl = baseUrl.replace(/\/+$/, '');
+    this.fetchImpl = options.fetch ?? globalThis.fetch.bind(globalThis);
+    this.headers = { ...(options.headers ?? {}) };
+  }
+
+  protected async request<T>(options: RequestOptions): Promise<T> {
+    const search = new URLSearchParams();
+    for (const [name, value] of Object.entries(options.query ?? {})) {
+      for (const encoded of encodeValues(value)) {
+        search.append(name, encoded);
+      }
+    }
+    const query = search.toString();
+    const url = `${this.baseUrl}${options.path}${query ? `?${query}` : ''}`;
+
+    // Endpoints que também redirecionam navegadores (ex: /p/{shortid}) respondem JSON ao cliente
+    const headers: Record<string, string> = { Accept: 'application/json', ...this.headers };
+    for (const [name, value] of Object.entries(options.headers ?? {})) {
+      const encoded = encodeValues(value);
+      if (encoded.length > 0) {
+        headers[name] = encoded.join(',');
+      }
+    }
+
+    let body: BodyInit | undefined = options.rawBody;
+    if (options.body !== undefined) {
+      body = JSON.stringify(options.body);
+      headers['Content-Type'] = 'application/json';
+    } else if (options.contentType) {
+      headers['Content-Type'] = options.contentType;
+    }
+
+    const response = await this.fetchImpl(url, { method: options.method, headers, body });
+    if (!response.ok) {
+      const text = await response.text();
+      let payload: unknown = text;
+      let message = '';
+      try {
+        payload = JSON.parse(text);
+        const fields = payload as { error?: string; message?: string };
+        message = fields.error ?? fields.message ?? '';
+      } catch {
+        // corpo sem JSON: mantém o texto
+      }
+      throw new ApiError(response.status, message, payload);
+    }
+
+    switch (options.responseType) {
+      case 'blob':
+        return (await response.blob()) as T;
+      case 'none':
+        return undefined as T;
+    }
+    const text = await response.text();
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+}
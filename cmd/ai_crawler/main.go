@@ -41,7 +41,10 @@ func main() {
 	}
 
 	// Carrega configuração
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
 	// Cria contexto com cancelamento
 	ctx, cancel := context.WithCancel(context.Background())
@@ -58,8 +61,6 @@ func main() {
 	}()
 
 	// Cria crawler baseado no modo de IA
-	var err error
-
 	switch *aiMode {
 	case "full":
 		err = runFullAICrawler(ctx, cfg, *referenceFile, *trainOnly, *showStats, appLogger)
@@ -83,7 +84,13 @@ func runFullAICrawler(ctx context.Context, cfg *config.Config, referenceFile str
 	appLogger.Info("Running in FULL AI mode - complete AI integration")
 
 	// Cria crawler integrado com IA
-	aiCrawler, err := crawler.NewAIIntegratedCrawler(ctx, cfg)
+	deps, err := crawler.NewCrawlerDependencies(ctx, cfg, true)
+	if err != nil {
+		return fmt.Errorf("failed to create crawler dependencies: %w", err)
+	}
+	defer deps.Repository.Close()
+
+	aiCrawler, err := crawler.NewAIIntegratedCrawler(cfg, deps)
 	if err != nil {
 		return fmt.Errorf("failed to create AI-integrated crawler: %w", err)
 	}
@@ -157,7 +164,16 @@ func runBasicAICrawler(ctx context.Context, cfg *config.Config, referenceFile st
 	appLogger.Info("Running in BASIC AI mode - improved crawler with basic AI")
 
 	// Cria crawler melhorado
-	improvedCrawler := crawler.NewImprovedCrawler(ctx, cfg)
+	deps, err := crawler.NewCrawlerDependencies(ctx, cfg, false)
+	if err != nil {
+		return fmt.Errorf("failed to create crawler dependencies: %w", err)
+	}
+	defer deps.Repository.Close()
+
+	improvedCrawler, err := crawler.NewImprovedCrawler(cfg, deps)
+	if err != nil {
+		return fmt.Errorf("failed to create improved crawler: %w", err)
+	}
 
 	// Treina padrões básicos
 	appLogger.WithField("reference_file", referenceFile).Info("Training basic patterns")
@@ -223,7 +239,10 @@ func runNonAICrawler(ctx context.Context, cfg *config.Config, referenceFile stri
 	appLogger.Info("Running in NO AI mode - traditional crawler")
 
 	// Cria crawler tradicional
-	traditionalCrawler := crawler.NewCrawler(ctx, cfg)
+	traditionalCrawler, err := crawler.NewCrawler(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create traditional crawler: %w", err)
+	}
 
 	if trainOnly {
 		appLogger.Info("No training available in non-AI mode. Exiting.")
@@ -312,6 +331,22 @@ func printFinalAIStats(logger *logger.Logger, stats *crawler.AIIntegratedStats)
 			"pages":  count,
 		}).Info("Domain processed")
 	}
+
+	// Amostragem da validação por IA e taxas de correção medidas por domínio
+	for _, sampling := range stats.AIValidationSampling {
+		logger.WithFields(map[string]interface{}{
+			"domain":                         sampling.Domain,
+			"sample_rate":                    sampling.SampleRate,
+			"extracted":                      sampling.Extracted,
+			"validated":                      sampling.Validated,
+			"low_confidence":                 sampling.LowConfidence,
+			"sampled":                        sampling.Sampled,
+			"failed":                         sampling.Failed,
+			"low_confidence_correction_rate": sampling.LowConfidenceCorrectionRate,
+			"sampled_correction_rate":        sampling.SampledCorrectionRate,
+			"corrected_fields":               sampling.CorrectedFields,
+		}).Info("AI validation sampling")
+	}
 }
 
 // printFinalBasicStats imprime estatísticas finais do crawler básico
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	mathrand "math/rand"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		sampleSize = flag.Int("sample", 50, "Properties in the sample (chosen at random among the filtered ones)")
+		cidade     = flag.String("cidade", "", "Only properties from this city")
+		bairro     = flag.String("bairro", "", "Only properties from this neighborhood")
+		tipo       = flag.String("tipo", "", "Only properties of this type")
+		salt       = flag.String("salt", "", "Secret used to derive domain aliases and IDs; reuse it to get the same aliases across exports (default random)")
+		seed       = flag.Int64("seed", 0, "Random seed for choosing the sample (default current time)")
+		format     = flag.String("format", "jsonl", "Output format: jsonl or json")
+		output     = flag.String("output", "", "Output file (default stdout)")
+	)
+	flag.Parse()
+
+	appLogger := logger.NewLogger("anonymize_sample_main")
+
+	if *format != "jsonl" && *format != "json" {
+		appLogger.Fatalf("Invalid output format %q (use jsonl or json)", *format)
+	}
+	if *sampleSize <= 0 {
+		appLogger.Fatalf("Invalid sample size %d", *sampleSize)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		appLogger.Warn("Warning: Error loading .env file, using default environment variables")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Fatal("Failed to load configuration", err)
+	}
+
+	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
+	if err != nil {
+		appLogger.Fatal("Failed to create MongoDB repository", err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		appLogger.Info("Received shutdown signal, stopping export")
+		cancel()
+	}()
+
+	// Sem salt informado os apelidos não se repetem entre exportações e não podem ser revertidos
+	if *salt == "" {
+		secret := make([]byte, 16)
+		if _, err := rand.Read(secret); err != nil {
+			appLogger.Fatal("Failed to generate salt", err)
+		}
+		*salt = hex.EncodeToString(secret)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	// Amostragem por reservatório: uma passada pelos imóveis filtrados, guardando só a amostra
+	filter := repository.PropertyFilter{Cidade: *cidade, Bairro: *bairro, TipoImovel: *tipo}
+	random := mathrand.New(mathrand.NewSource(*seed))
+	sample := make([]repository.Property, 0, *sampleSize)
+	seen := 0
+	err = repo.StreamProperties(ctx, filter, "", 0, func(property repository.Property) error {
+		seen++
+		if len(sample) < *sampleSize {
+			sample = append(sample, property)
+		} else if i := random.Intn(seen); i < *sampleSize {
+			sample[i] = property
+		}
+		return nil
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to read properties", err)
+	}
+
+	anonymizer := repository.NewAnonymizer(*salt)
+	for i, property := range sample {
+		sample[i] = anonymizer.Anonymize(property)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			appLogger.Fatal("Failed to create output file", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	if *format == "json" {
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(sample)
+	} else {
+		for _, property := range sample {
+			if err = encoder.Encode(property); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		appLogger.Fatal("Failed to write sample", err)
+	}
+
+	appLogger.WithFields(map[string]interface{}{
+		"matched": seen,
+		"sampled": len(sample),
+		"format":  *format,
+	}).Info("Anonymized sample exported")
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dujoseaugusto/go-crawler-project/api"
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
 	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/dujoseaugusto/go-crawler-project/internal/service"
 	"github.com/joho/godotenv"
@@ -19,7 +28,51 @@ func main() {
 	}
 
 	// Load application configuration
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Optional external error tracking (Sentry) for panics and storage failures
+	if enabled, err := logger.SetupSentry(cfg.SentryDSN, cfg.SentryEnvironment, cfg.SentryRelease); err != nil {
+		log.Printf("Warning: Failed to configure Sentry: %v", err)
+	} else if enabled {
+		log.Printf("Sentry error reporting enabled (environment %s)", cfg.SentryEnvironment)
+		defer logger.FlushErrorReporter(5 * time.Second)
+		defer logger.RecoverAndReport("api_main")
+	}
+
+	// Custom extraction stages (Go plugins and ordering) for company-specific fields
+	if err := crawler.ConfigureExtractionPipeline(cfg.ExtractionStages, cfg.ExtractionPlugins); err != nil {
+		log.Fatalf("Failed to configure extraction pipeline: %v", err)
+	}
+	log.Printf("Extraction stages: %v", crawler.ExtractionStages())
+
+	// Extra keywords for the infrastructure features (energia solar, poço artesiano, aquecimento a gás)
+	if err := repository.LoadFeatureKeywords(cfg.FeatureKeywordsFile); err != nil {
+		log.Fatalf("Failed to load feature keywords: %v", err)
+	}
+
+	// Mongo client pool, timeouts and retry behaviour shared by every repository
+	mongoOptions := repository.MongoClientOptions{
+		MaxPoolSize:            cfg.MongoMaxPoolSize,
+		MinPoolSize:            cfg.MongoMinPoolSize,
+		MaxConnIdleTime:        cfg.MongoMaxConnIdleTime,
+		ConnectTimeout:         cfg.MongoConnectTimeout,
+		ServerSelectionTimeout: cfg.MongoServerSelectionTimeout,
+		SocketTimeout:          cfg.MongoSocketTimeout,
+		HeartbeatInterval:      cfg.MongoHeartbeatInterval,
+		RetryWrites:            cfg.MongoRetryWrites,
+		RetryReads:             cfg.MongoRetryReads,
+		ReadConcern:            cfg.MongoReadConcern,
+		WriteConcern:           cfg.MongoWriteConcern,
+		WriteTimeout:           cfg.MongoWriteTimeout,
+		OperationRetries:       cfg.MongoOperationRetries,
+		RetryBackoff:           cfg.MongoRetryBackoff,
+	}
+	if err := repository.SetMongoClientOptions(mongoOptions); err != nil {
+		log.Fatalf("Invalid MongoDB client options: %v", err)
+	}
 
 	// Initialize MongoDB repository
 	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
@@ -28,6 +81,26 @@ func main() {
 	}
 	defer repo.Close()
 
+	// Prices converted to other currencies at crawl time with the day's exchange rates
+	var exchangeRates *repository.DailyExchangeRates
+	if cfg.ExchangeRatesURL != "" {
+		exchangeRates = repository.NewDailyExchangeRates(repository.NewHTTPExchangeRateProvider(cfg.ExchangeRatesURL), cfg.ExchangeRateCurrencies)
+		repo.SetExchangeRates(exchangeRates)
+		log.Printf("Exchange rate enrichment enabled for %s", strings.Join(exchangeRates.Currencies(), ", "))
+	}
+
+	// Normalize amenities of properties saved before the search filter existed
+	go func() {
+		updated, err := repo.BackfillAmenities(context.Background())
+		if err != nil {
+			log.Printf("Warning: Failed to backfill property amenities: %v", err)
+			return
+		}
+		if updated > 0 {
+			log.Printf("Amenities backfilled for %d properties", updated)
+		}
+	}()
+
 	// Initialize URL repository for incremental crawling
 	urlRepo, err := repository.NewMongoURLRepository(cfg.MongoURI, "crawler")
 	if err != nil {
@@ -35,6 +108,36 @@ func main() {
 	}
 	defer urlRepo.Close()
 
+	// TTL indexes and size caps keep processed URLs and fingerprints bounded
+	retention := repository.RetentionPolicy{
+		URLTTL:          cfg.URLRecordTTL,
+		FingerprintTTL:  cfg.FingerprintTTL,
+		MaxURLs:         cfg.MaxProcessedURLs,
+		MaxFingerprints: cfg.MaxFingerprints,
+	}
+	if err := urlRepo.ConfigureRetention(context.Background(), retention); err != nil {
+		log.Printf("Warning: Failed to configure URL retention: %v", err)
+	}
+	urlRepo.StartMaintenance(context.Background(), cfg.URLMaintenanceInterval)
+
+	// Optional local Badger cache in front of processed URL/fingerprint lookups
+	var urlStore repository.URLRepository = urlRepo
+	if cfg.URLCacheDir != "" {
+		cachedURLRepo, err := repository.NewCachedURLRepository(urlRepo, repository.URLCacheOptions{
+			Dir:          cfg.URLCacheDir,
+			TTL:          cfg.URLCacheTTL,
+			SyncInterval: cfg.URLCacheSyncInterval,
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to open URL cache, using MongoDB only: %v", err)
+		} else {
+			defer cachedURLRepo.Close()
+			cachedURLRepo.StartSync(context.Background())
+			urlStore = cachedURLRepo
+			log.Printf("URL cache enabled at %s", cfg.URLCacheDir)
+		}
+	}
+
 	// Initialize city sites repository
 	citySitesRepo, err := repository.NewMongoCitySitesRepository(cfg.MongoURI, "crawler")
 	if err != nil {
@@ -45,29 +148,195 @@ func main() {
 		defer citySitesRepo.Close()
 	}
 
+	// Initialize domain config repository
+	domainConfigRepo, err := repository.NewMongoDomainConfigRepository(cfg.MongoURI, "crawler")
+	if err != nil {
+		log.Printf("Warning: Failed to create domain config repository: %v", err)
+		domainConfigRepo = nil
+	}
+	if domainConfigRepo != nil {
+		defer domainConfigRepo.Close()
+	}
+
 	// Initialize services (simplified - no learning systems)
 	var propertyService *service.PropertyService
 	var citySitesService *service.CitySitesService
 
 	if citySitesRepo != nil {
 		// Use new service with city sites support
-		propertyService = service.NewPropertyServiceWithCitySites(repo, urlRepo, citySitesRepo, cfg)
+		propertyService = service.NewPropertyServiceWithCitySites(repo, urlStore, citySitesRepo, cfg)
 		citySitesService = service.NewCitySitesService(citySitesRepo)
+		citySitesService.SetRefreshSLA(cfg.CityRefreshSLA)
 		log.Printf("City sites management enabled")
 	} else {
 		// Fallback to original service
-		propertyService = service.NewPropertyService(repo, urlRepo, cfg)
+		propertyService = service.NewPropertyService(repo, urlStore, cfg)
 		log.Printf("Using fallback mode without city sites management")
 	}
 
+	var domainConfigService *service.DomainConfigService
+	if domainConfigRepo != nil {
+		domainConfigService = service.NewDomainConfigService(domainConfigRepo)
+		propertyService.SetSkipPathStore(domainConfigRepo)
+		if citySitesService != nil {
+			citySitesService.SetDomainConfigService(domainConfigService)
+		}
+		log.Printf("Domain config management enabled")
+	}
+
+	// Initialize runtime config watcher (hot-reload of rate limits, AI threshold and blacklist)
+	var domainSource config.DomainConfigSource
+	if domainConfigRepo != nil {
+		domainSource = domainConfigRepo
+	}
+	configWatcher := config.NewConfigWatcher(cfg.RuntimeConfigFile, domainSource, cfg.ConfigReloadInterval)
+	configWatcher.Start(context.Background())
+	propertyService.SetConfigWatcher(configWatcher)
+
+	// Coordinated shutdown: stop intake -> drain -> flush AI -> flush writes -> checkpoint
+	shutdown := crawler.NewShutdownCoordinator(cfg.ShutdownTimeout)
+	propertyService.SetShutdownCoordinator(shutdown)
+
+	// In-process DNS cache shared by the crawlers (DNS_CACHE_TTL=0 disables)
+	crawler.SetDNSCache(crawler.NewDNSCacheFromConfig(cfg))
+
+	// Middlewares wrapped around every collector request (FETCH_MIDDLEWARES)
+	fetchChain, err := crawler.NewFetchChainFromConfig(cfg)
+	if err != nil {
+		log.Fatalf("Invalid fetch middlewares: %v", err)
+	}
+	crawler.SetFetchChain(fetchChain)
+	if fetchChain != nil {
+		log.Printf("Fetch middlewares enabled: %s", strings.Join(fetchChain.Names(), ", "))
+	}
+
+	// Optional audit log of every outbound HTTP request (REQUEST_AUDIT=mongo or file:<path>)
+	auditRepo, err := repository.OpenRequestAudit(context.Background(), cfg.RequestAudit, repo, cfg.RequestAuditTTL)
+	if err != nil {
+		log.Printf("Warning: Failed to open request audit log: %v", err)
+	} else if auditRepo != nil {
+		audit := crawler.NewRequestAuditLog(auditRepo)
+		propertyService.SetRequestAuditLog(audit)
+		shutdown.Register(crawler.ShutdownFlushWrites, "request_audit", audit.Close)
+		log.Printf("Request audit log enabled (%s)", cfg.RequestAudit)
+	}
+	propertyService.SetExchangeRates(exchangeRates)
+
+	// Classification explainer (GET /debug/classify) with the calibration curves and, when configured, Gemini
+	calibrator, err := crawler.LoadConfidenceCalibrator(cfg.CalibrationFile)
+	if err != nil {
+		log.Printf("Warning: failed to load confidence calibration, using raw scores: %v", err)
+	}
+	var pageAI crawler.PageAIProvider
+	if enhancedAI, err := ai.NewEnhancedGeminiService(context.Background()); err == nil {
+		pageAI = enhancedAI
+	} else {
+		log.Printf("Classification explainer running without AI: %v", err)
+	}
+	propertyService.SetClassificationExplainer(crawler.NewClassificationExplainer(pageAI, calibrator))
+
+	// Leader election: with several API replicas only the leader runs the scheduled tasks below
+	switch cfg.LeaderElection {
+	case "":
+	case service.LeaderElectionMongo, service.LeaderElectionKubernetes:
+		var leases repository.LeaderLeaseRepository = repo
+		if cfg.LeaderElection == service.LeaderElectionKubernetes {
+			kubernetesLeases, err := repository.NewInClusterLeaseRepository()
+			if err != nil {
+				log.Fatalf("Failed to set up Kubernetes leader election: %v", err)
+			}
+			leases = kubernetesLeases
+		}
+		elector := service.NewLeaderElector(leases, cfg.LeaderLeaseName, cfg.LeaderIdentity, cfg.LeaderLeaseTTL)
+		elector.Start(context.Background())
+		propertyService.SetLeaderElector(elector)
+		shutdown.Register(crawler.ShutdownStopIntake, "leader_lease", elector.Release)
+		log.Printf("Leader election enabled (%s, identity %s)", cfg.LeaderElection, elector.Identity())
+	default:
+		log.Fatalf("Invalid LEADER_ELECTION %q (expected mongo or kubernetes)", cfg.LeaderElection)
+	}
+
+	// Monthly neighborhood price index (median R$/m²), recomputed periodically
+	propertyService.StartPriceIndexJob(context.Background(), cfg.PriceIndexInterval)
+
+	// Per-domain data quality metrics (price, address, rooms, photos, extraction score), daily
+	propertyService.StartQualityMetricsJob(context.Background(), cfg.QualityMetricsInterval)
+
+	// Finished crawl jobs dropped from memory after CRAWL_JOB_RETENTION
+	propertyService.StartCrawlJobCleanup(context.Background(), cfg.CrawlJobCleanupInterval, cfg.CrawlJobRetention)
+
+	// Cross-portal duplicates merged into canonical records, after the quality metrics that weight them
+	propertyService.StartCanonicalMergeJob(context.Background(), cfg.CanonicalMergeInterval)
+
+	// Alerts for cities whose data was not refreshed within their SLA
+	propertyService.StartCitySLAMonitor(context.Background(), cfg.CitySLACheckInterval)
+
+	// Listing photos served through the API instead of hotlinking the source sites
+	propertyService.SetPhotoProxy(crawler.NewPhotoProxy(crawler.PhotoProxyOptions{
+		UserAgent: cfg.GeocoderUserAgent,
+		CacheDir:  cfg.PhotoCacheDir,
+		CacheTTL:  cfg.PhotoCacheTTL,
+	}, configWatcher.Current))
+
+	// RSS/Atom listing feeds: new entries are crawled without re-crawling whole sites
+	propertyService.StartFeedIngestion(context.Background(), cfg.FeedPollInterval)
+
+	// Crawl trigger Idempotency-Key values, shared by all replicas and expired by a TTL index
+	if err := repo.ConfigureIdempotencyKeys(context.Background()); err != nil {
+		log.Printf("Warning: Failed to configure idempotency keys: %v", err)
+	}
+	propertyService.SetIdempotencyStore(repo)
+
+	// Learned URL patterns (data/patterns/url_patterns.json); imports wait for approval in MongoDB
+	patternLearner := crawler.NewPatternLearner()
+	patternStorage := crawler.NewPatternStorage("")
+	if err := patternStorage.LoadURLPatterns(patternLearner); err != nil {
+		log.Printf("Warning: Failed to load URL patterns: %v", err)
+	}
+	if err := repo.ConfigurePatternImports(context.Background()); err != nil {
+		log.Printf("Warning: Failed to configure pattern imports: %v", err)
+	}
+	patternImports := crawler.NewPatternImportQueue(patternLearner, time.Hour, repo)
+	patternImports.OnApplied(func() {
+		if err := patternStorage.SaveURLPatterns(patternLearner); err != nil {
+			log.Printf("Warning: Failed to save imported URL patterns: %v", err)
+		}
+	})
+	propertyService.SetPatternLearner(patternLearner)
+	propertyService.SetPatternImports(patternImports)
+
 	log.Printf("Sistema simplificado - todas as páginas são tratadas como propriedades")
 
 	// Setup router (simplified)
-	router := api.SetupRouterWithCitySites(propertyService, citySitesService)
+	security := api.NewRouterSecurity(cfg)
+	if err := security.CORS.Validate(); err != nil {
+		log.Fatalf("Invalid CORS configuration: %v", err)
+	}
+	router := api.SetupRouterWithSecurity(propertyService, citySitesService, patternLearner, nil, domainConfigService, security)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
+	}
+
+	// Stop accepting HTTP requests as the first shutdown step
+	shutdown.Register(crawler.ShutdownStopIntake, "http_server", server.Shutdown)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigChan
+		log.Printf("Received %s, starting graceful shutdown (deadline %s)", sig, cfg.ShutdownTimeout)
+		shutdown.Shutdown()
+	}()
 
 	// Start server
 	log.Printf("Starting server on port %s", cfg.Port)
-	if err := http.ListenAndServe(":"+cfg.Port, router); err != nil {
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+
+	shutdown.Wait()
+	log.Printf("Server stopped")
 }
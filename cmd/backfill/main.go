@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/joho/godotenv"
+)
+
+// backfillCheckpoint guarda o andamento para retomar o backfill de onde parou
+type backfillCheckpoint struct {
+	repository.BackfillProgress
+	SavedAt time.Time `json:"saved_at"`
+}
+
+func main() {
+	var (
+		batchSize      = flag.Int("batch-size", 500, "Properties loaded and written per batch")
+		checkpointFile = flag.String("checkpoint", "data/backfill_checkpoint.json", "File used to resume an interrupted backfill")
+		restart        = flag.Bool("restart", false, "Ignore the checkpoint and start from the first property")
+		geocode        = flag.Bool("geocode", false, "Geocode properties without location (slow: one request per GEOCODER_INTERVAL)")
+		poi            = flag.Bool("poi", false, "Compute distances from geocoded properties to the city center, schools and hospitals (OpenStreetMap)")
+		photoHashes    = flag.Bool("photo-hashes", false, "Download listing photos, store their perceptual hashes and flag listings reusing them")
+		photoCount     = flag.Bool("photo-count", false, "Re-fetch listing pages and recount photos from the listing gallery only (slow: one page per -photo-count-interval)")
+		photoInterval  = flag.Duration("photo-count-interval", time.Second, "Minimum interval between listing pages fetched by -photo-count")
+		dryRun         = flag.Bool("dry-run", false, "Count the properties that would change without writing")
+		schemaOnly     = flag.Bool("schema-only", false, "Only migrate documents from older schema versions and exit")
+	)
+	flag.Parse()
+
+	appLogger := logger.NewLogger("backfill_main")
+
+	if err := godotenv.Load(); err != nil {
+		appLogger.Warn("Warning: Error loading .env file, using default environment variables")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Fatal("Failed to load configuration", err)
+	}
+
+	// Termos extras dos recursos de infraestrutura usados no recálculo
+	if err := repository.LoadFeatureKeywords(cfg.FeatureKeywordsFile); err != nil {
+		appLogger.Fatal("Failed to load feature keywords", err)
+	}
+
+	// Pool, timeouts e novas tentativas do cliente Mongo
+	mongoOptions := repository.MongoClientOptions{
+		MaxPoolSize:            cfg.MongoMaxPoolSize,
+		MinPoolSize:            cfg.MongoMinPoolSize,
+		MaxConnIdleTime:        cfg.MongoMaxConnIdleTime,
+		ConnectTimeout:         cfg.MongoConnectTimeout,
+		ServerSelectionTimeout: cfg.MongoServerSelectionTimeout,
+		SocketTimeout:          cfg.MongoSocketTimeout,
+		HeartbeatInterval:      cfg.MongoHeartbeatInterval,
+		RetryWrites:            cfg.MongoRetryWrites,
+		RetryReads:             cfg.MongoRetryReads,
+		ReadConcern:            cfg.MongoReadConcern,
+		WriteConcern:           cfg.MongoWriteConcern,
+		WriteTimeout:           cfg.MongoWriteTimeout,
+		OperationRetries:       cfg.MongoOperationRetries,
+		RetryBackoff:           cfg.MongoRetryBackoff,
+	}
+	if err := repository.SetMongoClientOptions(mongoOptions); err != nil {
+		appLogger.Fatal("Invalid MongoDB client options", err)
+	}
+
+	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
+	if err != nil {
+		appLogger.Fatal("Failed to create MongoDB repository", err)
+	}
+	defer repo.Close()
+
+	// Interrompe entre lotes no SIGINT/SIGTERM; o checkpoint permite retomar
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		appLogger.Info("Received shutdown signal, stopping after the current batch")
+		cancel()
+	}()
+
+	// Documentos de versões anteriores do schema são migrados antes do recálculo
+	if !*dryRun {
+		migrated, err := repo.MigrateSchema(ctx, *batchSize)
+		if err != nil && ctx.Err() == nil {
+			appLogger.Fatal("Schema migration failed", err)
+		}
+		appLogger.WithFields(map[string]interface{}{
+			"migrated":       migrated,
+			"schema_version": repository.CurrentSchemaVersion(),
+		}).Info("Schema migration completed")
+	}
+	if *schemaOnly || ctx.Err() != nil {
+		return
+	}
+
+	opts := repository.BackfillOptions{BatchSize: *batchSize, DryRun: *dryRun}
+	if !*restart {
+		checkpoint, err := loadCheckpoint(*checkpointFile)
+		if err != nil {
+			appLogger.Fatal("Failed to load backfill checkpoint", err)
+		}
+		if checkpoint != nil {
+			opts.Resume = checkpoint.BackfillProgress
+			appLogger.WithFields(map[string]interface{}{
+				"checkpoint_file": *checkpointFile,
+				"saved_at":        checkpoint.SavedAt,
+				"processed":       checkpoint.Processed,
+			}).Info("Resuming backfill from checkpoint")
+		}
+	}
+	if *geocode {
+		opts.Geocoder = repository.NewNominatimGeocoder(cfg.GeocoderURL, cfg.GeocoderUserAgent, cfg.GeocoderInterval)
+	}
+	if *poi {
+		geocoder := repository.NewNominatimGeocoder(cfg.GeocoderURL, cfg.GeocoderUserAgent, cfg.GeocoderInterval)
+		opts.POIFinder = repository.NewOverpassPOIFinder(cfg.POIOverpassURL, cfg.GeocoderUserAgent, cfg.POIRadiusKm, geocoder)
+	}
+	if *photoCount {
+		opts.PhotoCounter = crawler.NewPagePhotoCounter(*photoInterval)
+	}
+	if *photoHashes {
+		opts.PhotoHasher = repository.NewHTTPPhotoHasher(cfg.GeocoderUserAgent, cfg.PhotoHashLimit, crawler.NewGuardedClient(20*time.Second))
+	}
+
+	var previous repository.BackfillProgress
+	opts.OnBatch = func(progress repository.BackfillProgress) error {
+		appLogger.WithFields(map[string]interface{}{
+			"processed": progress.Processed,
+			"updated":   progress.Updated,
+			"geocoded":  progress.Geocoded,
+			"hashed":    progress.PhotosHashed,
+			"counted":   progress.PhotosCounted,
+			"poi":       progress.POIEnriched,
+		}).Info("Backfill batch completed")
+		previous = progress
+		if *dryRun {
+			return nil
+		}
+		return saveCheckpoint(*checkpointFile, progress)
+	}
+
+	startTime := time.Now()
+	progress, err := repo.BackfillDerivedFields(ctx, opts)
+	if err != nil && ctx.Err() == nil {
+		appLogger.WithField("last_hash", previous.LastHash).Fatal("Backfill failed", err)
+	}
+
+	appLogger.WithFields(map[string]interface{}{
+		"processed":      progress.Processed,
+		"updated":        progress.Updated,
+		"geocoded":       progress.Geocoded,
+		"geocode_errors": progress.GeocodeErrors,
+		"photos_hashed":  progress.PhotosHashed,
+		"photos_counted": progress.PhotosCounted,
+		"photo_errors":   progress.PhotoErrors,
+		"poi_enriched":   progress.POIEnriched,
+		"poi_errors":     progress.POIErrors,
+		"dry_run":        *dryRun,
+		"interrupted":    ctx.Err() != nil,
+		"duration":       time.Since(startTime),
+	}).Info("Backfill finished")
+
+	// Concluído: o próximo backfill recomeça do início
+	if ctx.Err() == nil && !*dryRun {
+		if err := os.Remove(*checkpointFile); err != nil && !os.IsNotExist(err) {
+			appLogger.WithError(err).Warn("Failed to remove backfill checkpoint")
+		}
+	}
+
+	// Compare the new photo hashes and queue listings that reuse photos for review
+	if *photoHashes && ctx.Err() == nil && !*dryRun {
+		scan, err := repo.DetectPhotoFraud(ctx)
+		if err != nil {
+			appLogger.Fatal("Photo fraud detection failed", err)
+		}
+		appLogger.WithFields(map[string]interface{}{
+			"listings":        scan.Listings,
+			"groups":          scan.Groups,
+			"flagged":         scan.Flagged,
+			"pending_reviews": scan.PendingReviews,
+		}).Info("Photo fraud detection finished")
+	}
+}
+
+// loadCheckpoint lê o checkpoint do backfill (nil se não existir)
+func loadCheckpoint(path string) (*backfillCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint backfillCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %v", err)
+	}
+	return &checkpoint, nil
+}
+
+// saveCheckpoint grava o andamento de forma atômica (arquivo temporário + rename)
+func saveCheckpoint(path string, progress repository.BackfillProgress) error {
+	data, err := json.MarshalIndent(backfillCheckpoint{BackfillProgress: progress, SavedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		seedsDir       = flag.String("seeds", "", "Recorded seed set directory (seedset.json + pages); empty uses a synthetic mock site")
+		mockProperties = flag.Int("mock-properties", 20, "Listings in the synthetic mock site")
+		record         = flag.String("record", "", "Crawl the URLs from SITES_FILE and record the pages into this directory, then exit")
+		engines        = flag.String("engines", strings.Join(crawler.BenchmarkEngines, ","), "Comma-separated engines to compare")
+		useAI          = flag.Bool("ai", false, "Enable the Gemini services (requires GEMINI_API_KEY and consumes tokens)")
+		inputPrice     = flag.Float64("ai-input-price", 0.075, "USD per million prompt tokens used for the AI cost column")
+		outputPrice    = flag.Float64("ai-output-price", 0.30, "USD per million output tokens used for the AI cost column")
+		timeout        = flag.Duration("timeout", 10*time.Minute, "Maximum duration of each engine run")
+		jsonOutput     = flag.Bool("json", false, "Print the results as JSON instead of a table")
+		calibrate      = flag.Bool("calibrate", false, "Fit the confidence calibration curves from the seed set labels and save them to CALIBRATION_FILE")
+	)
+	flag.Parse()
+
+	appLogger := logger.NewLogger("benchmark_main")
+
+	if err := godotenv.Load(); err != nil {
+		appLogger.Warn("Warning: Error loading .env file, using default environment variables")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Fatal("Failed to load configuration", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		appLogger.Info("Received shutdown signal, stopping benchmark")
+		cancel()
+	}()
+
+	// Grava as páginas reais para que os engines sejam comparados depois sem acessar a rede
+	if *record != "" {
+		seeds, err := crawler.LoadURLsFromFile(cfg.SitesFile)
+		if err != nil {
+			appLogger.Fatal("Failed to load seed URLs", err)
+		}
+		seedSet, err := crawler.RecordBenchmarkSeedSet(ctx, seeds, *record)
+		if err != nil {
+			appLogger.Fatal("Failed to record seed set", err)
+		}
+		appLogger.WithFields(map[string]interface{}{
+			"directory": *record,
+			"seeds":     len(seedSet.Seeds),
+			"pages":     len(seedSet.Pages),
+		}).Info("Seed set recorded; label the listing URLs in seedset.json to measure precision")
+		return
+	}
+
+	seedSet := crawler.MockBenchmarkSeedSet(*mockProperties)
+	if *seedsDir != "" {
+		if seedSet, err = crawler.LoadBenchmarkSeedSet(*seedsDir); err != nil {
+			appLogger.Fatal("Failed to load seed set", err)
+		}
+	}
+
+	opts := crawler.BenchmarkOptions{
+		Config:                cfg,
+		Timeout:               *timeout,
+		InputPricePerMillion:  *inputPrice,
+		OutputPricePerMillion: *outputPrice,
+	}
+	if *useAI {
+		if opts.AI, err = ai.NewGeminiService(ctx); err != nil {
+			appLogger.Fatal("Failed to create Gemini service", err)
+		}
+		if opts.PageAI, err = ai.NewEnhancedGeminiService(ctx); err != nil {
+			appLogger.Fatal("Failed to create enhanced AI service", err)
+		}
+		if trainer, err := crawler.NewAIEnhancedTrainer(ctx); err == nil {
+			opts.Trainer = trainer
+		} else {
+			appLogger.WithError(err).Warn("Failed to create AI trainer")
+		}
+	}
+	if *calibrate {
+		opts.Calibrator = crawler.NewConfidenceCalibrator()
+		opts.Calibrator.StartRecording()
+	}
+
+	var selected []string
+	for _, engine := range strings.Split(*engines, ",") {
+		if engine = strings.TrimSpace(engine); engine != "" {
+			selected = append(selected, engine)
+		}
+	}
+
+	results := crawler.RunBenchmark(ctx, seedSet, selected, opts)
+
+	// Ajusta as curvas de calibração com as notas emitidas pelos engines e os rótulos do seed set
+	if opts.Calibrator != nil {
+		saveCalibration(appLogger, cfg.CalibrationFile, opts.Calibrator, seedSet)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			appLogger.Fatal("Failed to encode results", err)
+		}
+		return
+	}
+	printResults(results)
+}
+
+// saveCalibration recalcula as curvas e grava no arquivo de calibração (fontes com poucas
+// observações rotuladas mantêm a curva já gravada)
+func saveCalibration(appLogger *logger.Logger, path string, calibrator *crawler.ConfidenceCalibrator, seedSet *crawler.BenchmarkSeedSet) {
+	current, err := crawler.LoadConfidenceCalibrator(path)
+	if err != nil {
+		appLogger.WithError(err).Warn("Failed to load current calibration file, starting from scratch")
+		current = crawler.NewConfidenceCalibrator()
+	}
+	current.StartRecording()
+	for _, observation := range calibrator.Observations() {
+		current.Score(observation.Source, observation.URL, observation.Confidence, observation.IsProperty)
+	}
+
+	updated := current.Refit(seedSet.Label)
+	if len(updated) == 0 {
+		appLogger.Warn("Not enough labeled observations to fit any calibration curve")
+		return
+	}
+	if err := current.Save(path); err != nil {
+		appLogger.Fatal("Failed to save calibration file", err)
+	}
+	for _, curve := range current.Curves() {
+		appLogger.WithFields(map[string]interface{}{
+			"source":   curve.Source,
+			"samples":  curve.Samples,
+			"accuracy": curve.Accuracy,
+			"points":   len(curve.Points),
+		}).Info("Calibration curve")
+	}
+	appLogger.WithFields(map[string]interface{}{
+		"file":    path,
+		"updated": strings.Join(updated, ","),
+	}).Info("Calibration curves saved")
+}
+
+// printResults imprime a tabela comparativa dos engines
+func printResults(results []crawler.BenchmarkResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "ENGINE\tPAGES\tPROPERTIES\tPRECISION\tRECALL\tTIME\tAI CALLS\tTOKENS\tAI COST (USD)\t")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%.1f%%\t%s\t%d\t%d\t%.4f\t\n",
+			result.Engine, result.Pages, result.Properties, formatPrecision(result),
+			result.Recall*100, result.Duration.Round(time.Millisecond), result.AI.Calls,
+			result.AI.PromptTokens+result.AI.OutputTokens, result.AICost)
+	}
+	w.Flush()
+
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", result.Engine, result.Error)
+		}
+	}
+}
+
+// formatPrecision mostra a precisão e quantos imóveis salvos tinham URL rotulada
+func formatPrecision(result crawler.BenchmarkResult) string {
+	if result.Labeled == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%% (%d/%d)", result.Precision*100, result.Correct, result.Labeled)
+}
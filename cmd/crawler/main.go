@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
@@ -21,11 +23,21 @@ func main() {
 	var (
 		mode                 = flag.String("mode", "full", "Crawling mode: 'full' or 'incremental'")
 		enableAI             = flag.Bool("enable-ai", true, "Enable AI processing")
+		enableTranslation    = flag.Bool("enable-translation", false, "Translate descriptions to English (requires AI)")
+		enableCondition      = flag.Bool("enable-condition", false, "Score property condition from descriptions (requires AI)")
 		enableFingerprinting = flag.Bool("enable-fingerprinting", true, "Enable page fingerprinting")
-		maxAge               = flag.Duration("max-age", 24*time.Hour, "Maximum age before reprocessing")
+		maxAge               = flag.Duration("max-age", 24*time.Hour, "Revisit interval for URLs without change history")
+		minRevisit           = flag.Duration("min-revisit", 0, "Shortest adaptive revisit interval, for pages that change on every visit (default -max-age)")
+		maxRevisit           = flag.Duration("max-revisit", 7*24*time.Hour, "Longest adaptive revisit interval, for pages that never change")
 		aiThreshold          = flag.Duration("ai-threshold", 6*time.Hour, "Minimum time before AI reprocessing")
 		showStats            = flag.Bool("stats", false, "Show statistics and exit")
 		cleanup              = flag.Bool("cleanup", false, "Cleanup old records and exit")
+		resume               = flag.Bool("resume", false, "Resume from the checkpoint saved on the last shutdown")
+		cities               = flag.String("cities", "", "Cities (Cidade-UF, comma separated) to expand the portal search templates into seeds")
+		portalSeeds          = flag.Bool("portal-seeds", false, "Print the portal seed URLs generated for -cities and exit")
+		eventsFile           = flag.String("events-file", "", "Write one JSON line per crawl event (fetch, classification, save, error) to this file")
+		sinks                = flag.String("sinks", "", "Comma separated outputs for extracted properties: mongo, stdout, file:<path>, http(s) URL (default OUTPUT_SINKS)")
+		tags                 = flag.String("tags", "", "Comma separated tags recorded on every property saved by this run (e.g. muzambinho-weekly)")
 		help                 = flag.Bool("help", false, "Show help")
 	)
 	flag.Parse()
@@ -45,36 +57,186 @@ func main() {
 	}
 
 	// Load application configuration
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Fatal("Failed to load configuration", err)
+	}
+
+	// Seeds paginadas dos grandes portais (OLX, VivaReal, ZAP...) para as cidades informadas
+	var generatedSeeds []string
+	if *cities != "" {
+		cityList, err := crawler.ParseCityList(*cities)
+		if err != nil {
+			appLogger.Fatal("Invalid city list", err)
+		}
+		templates, err := crawler.LoadPortalTemplates(cfg.PortalsFile)
+		if err != nil {
+			appLogger.Fatal("Failed to load portal templates", err)
+		}
+		generatedSeeds = crawler.GeneratePortalSeeds(templates, cityList)
+		appLogger.WithFields(map[string]interface{}{
+			"cities":       len(cityList),
+			"portals_file": cfg.PortalsFile,
+			"seeds":        len(generatedSeeds),
+		}).Info("Portal seeds generated")
+	}
+	if *portalSeeds {
+		for _, seed := range generatedSeeds {
+			fmt.Println(seed)
+		}
+		return
+	}
+
+	// Rastreamento externo de erros (Sentry) para panics e falhas de gravação
+	if enabled, err := logger.SetupSentry(cfg.SentryDSN, cfg.SentryEnvironment, cfg.SentryRelease); err != nil {
+		appLogger.WithError(err).Warn("Failed to configure Sentry")
+	} else if enabled {
+		appLogger.WithField("environment", cfg.SentryEnvironment).Info("Sentry error reporting enabled")
+		defer logger.FlushErrorReporter(5 * time.Second)
+		defer logger.RecoverAndReport("crawler_main")
+	}
 	appLogger.WithFields(map[string]interface{}{
 		"port":                  cfg.Port,
 		"sites_file":            cfg.SitesFile,
 		"mode":                  *mode,
 		"enable_ai":             *enableAI,
+		"enable_translation":    *enableTranslation,
+		"enable_condition":      *enableCondition,
 		"enable_fingerprinting": *enableFingerprinting,
 		"max_age":               *maxAge,
+		"min_revisit":           *minRevisit,
+		"max_revisit":           *maxRevisit,
 	}).Info("Configuration loaded")
 
+	// Etapas de extração customizadas (plugins Go e ordem configurada)
+	if err := crawler.ConfigureExtractionPipeline(cfg.ExtractionStages, cfg.ExtractionPlugins); err != nil {
+		appLogger.Fatal("Failed to configure extraction pipeline", err)
+	}
+	appLogger.WithField("stages", crawler.ExtractionStages()).Info("Extraction pipeline configured")
+
+	// Termos extras dos recursos de infraestrutura (energia solar, poço artesiano, aquecimento a gás)
+	if err := repository.LoadFeatureKeywords(cfg.FeatureKeywordsFile); err != nil {
+		appLogger.Fatal("Failed to load feature keywords", err)
+	}
+
 	// Create a context for the crawler
 	ctx := context.Background()
 
-	// Initialize MongoDB repository
-	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
+	// Pool, timeouts e novas tentativas do cliente Mongo usados por todos os repositórios
+	mongoOptions := repository.MongoClientOptions{
+		MaxPoolSize:            cfg.MongoMaxPoolSize,
+		MinPoolSize:            cfg.MongoMinPoolSize,
+		MaxConnIdleTime:        cfg.MongoMaxConnIdleTime,
+		ConnectTimeout:         cfg.MongoConnectTimeout,
+		ServerSelectionTimeout: cfg.MongoServerSelectionTimeout,
+		SocketTimeout:          cfg.MongoSocketTimeout,
+		HeartbeatInterval:      cfg.MongoHeartbeatInterval,
+		RetryWrites:            cfg.MongoRetryWrites,
+		RetryReads:             cfg.MongoRetryReads,
+		ReadConcern:            cfg.MongoReadConcern,
+		WriteConcern:           cfg.MongoWriteConcern,
+		WriteTimeout:           cfg.MongoWriteTimeout,
+		OperationRetries:       cfg.MongoOperationRetries,
+		RetryBackoff:           cfg.MongoRetryBackoff,
+	}
+	if err := repository.SetMongoClientOptions(mongoOptions); err != nil {
+		appLogger.Fatal("Invalid MongoDB client options", err)
+	}
+
+	// Saídas dos imóveis extraídos (o flag -sinks substitui OUTPUT_SINKS)
+	sinkEntries := cfg.OutputSinks
+	if *sinks != "" {
+		sinkEntries = strings.Split(*sinks, ",")
+	}
+	sinkSpecs, err := repository.ParseSinkSpecs(sinkEntries)
+	if err != nil {
+		appLogger.Fatal("Invalid output sinks", err)
+	}
+
+	// Tags registradas em cada imóvel salvo, para filtrar e relatar por campanha
+	crawlTags, err := repository.ParseCrawlTags(*tags)
 	if err != nil {
-		appLogger.Fatal("Failed to create MongoDB repository", err)
+		appLogger.Fatal("Invalid crawl tags", err)
 	}
+
+	// Initialize MongoDB repository (only when properties are stored in MongoDB)
+	var mongoRepo repository.PropertyRepository
+	if repository.HasSink(sinkSpecs, repository.SinkMongo) {
+		mongoStore, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
+		if err != nil {
+			appLogger.Fatal("Failed to create MongoDB repository", err)
+		}
+		defer mongoStore.Close()
+		appLogger.Info("MongoDB repository initialized")
+
+		// Prices converted to other currencies with the day's exchange rates
+		if cfg.ExchangeRatesURL != "" {
+			provider := repository.NewHTTPExchangeRateProvider(cfg.ExchangeRatesURL)
+			mongoStore.SetExchangeRates(repository.NewDailyExchangeRates(provider, cfg.ExchangeRateCurrencies))
+			appLogger.WithField("currencies", cfg.ExchangeRateCurrencies).Info("Exchange rate enrichment enabled")
+		}
+		mongoRepo = mongoStore
+	}
+
+	outputSinks, err := repository.OpenSinks(sinkSpecs, mongoRepo)
+	if err != nil {
+		appLogger.Fatal("Failed to open output sinks", err)
+	}
+	repo := repository.NewSinkRepository(mongoRepo, outputSinks)
 	defer repo.Close()
-	appLogger.Info("MongoDB repository initialized")
+	appLogger.WithField("sinks", len(outputSinks)).Info("Output sinks initialized")
+	taggedRepo := repository.NewTaggedRepository(repo, crawlTags)
+	if len(crawlTags) > 0 {
+		appLogger.WithField("tags", crawlTags).Info("Crawl tags enabled")
+	}
 
-	// Initialize URL repository for incremental mode
+	// Initialize URL repository for incremental mode (and the shared frontier leases)
 	var urlRepo repository.URLRepository
-	if *mode == "incremental" || *showStats || *cleanup {
-		urlRepo, err = repository.NewMongoURLRepository(cfg.MongoURI, "crawler")
+	var deadFilters *crawler.DeadFilterTracker
+	var frontier *crawler.SharedFrontier
+	if *mode == "incremental" || *showStats || *cleanup || cfg.FrontierLeaseTTL > 0 {
+		mongoURLRepo, err := repository.NewMongoURLRepository(cfg.MongoURI, "crawler")
 		if err != nil {
 			appLogger.Fatal("Failed to create URL repository", err)
 		}
-		defer urlRepo.Close()
+		defer mongoURLRepo.Close()
+
+		retention := repository.RetentionPolicy{
+			URLTTL:          cfg.URLRecordTTL,
+			FingerprintTTL:  cfg.FingerprintTTL,
+			MaxURLs:         cfg.MaxProcessedURLs,
+			MaxFingerprints: cfg.MaxFingerprints,
+		}
+		if err := mongoURLRepo.ConfigureRetention(ctx, retention); err != nil {
+			appLogger.WithError(err).Warn("Failed to configure URL retention")
+		}
+
+		urlRepo = mongoURLRepo
+		deadFilters = crawler.NewDeadFilterTracker(mongoURLRepo, cfg.DeadFilterRetry)
 		appLogger.Info("URL repository initialized")
+
+		// Jobs simultâneos (outros processos ou a API) não buscam as mesmas URLs; as leases
+		// são sempre consultadas no MongoDB, mesmo com o cache local
+		if cfg.FrontierLeaseTTL > 0 {
+			frontier = crawler.NewSharedFrontier(mongoURLRepo, crawler.FrontierOwner(""), cfg.FrontierLeaseTTL)
+		}
+
+		// Cache local (Badger) das consultas de URLs processadas e fingerprints
+		if cfg.URLCacheDir != "" {
+			cachedURLRepo, err := repository.NewCachedURLRepository(mongoURLRepo, repository.URLCacheOptions{
+				Dir:          cfg.URLCacheDir,
+				TTL:          cfg.URLCacheTTL,
+				SyncInterval: cfg.URLCacheSyncInterval,
+			})
+			if err != nil {
+				appLogger.WithError(err).Warn("Failed to open URL cache, using MongoDB only")
+			} else {
+				defer cachedURLRepo.Close()
+				cachedURLRepo.StartSync(ctx)
+				urlRepo = cachedURLRepo
+				appLogger.WithField("dir", cfg.URLCacheDir).Info("URL cache enabled")
+			}
+		}
 	}
 
 	// Handle special commands
@@ -89,11 +251,79 @@ func main() {
 	}
 
 	// Load URLs from configuration file
-	urls, err := loadURLsFromFile(cfg.SitesFile)
+	appLogger.Warn(crawler.SitesFileDeprecationWarning)
+	urls, err := crawler.LoadURLsFromFile(cfg.SitesFile)
 	if err != nil {
 		appLogger.Fatal("Failed to load URLs from file", err)
 	}
 	appLogger.WithField("urls_count", len(urls)).Info("URLs loaded from configuration")
+	urls = append(urls, generatedSeeds...)
+
+	// Resume from checkpoint (only URLs that were not started before the last shutdown)
+	if *resume {
+		checkpoint, err := crawler.LoadCrawlCheckpoint(cfg.CheckpointFile)
+		if err != nil {
+			appLogger.Fatal("Failed to load checkpoint", err)
+		}
+		if checkpoint != nil && len(checkpoint.PendingURLs) > 0 {
+			urls = checkpoint.PendingURLs
+			appLogger.WithFields(map[string]interface{}{
+				"checkpoint_file": cfg.CheckpointFile,
+				"saved_at":        checkpoint.SavedAt,
+				"pending_urls":    len(urls),
+			}).Info("Resuming from checkpoint")
+		} else {
+			appLogger.Info("No pending URLs in checkpoint, starting from configuration")
+		}
+	}
+
+	// Log de eventos NDJSON para ferramentas externas
+	if *eventsFile != "" {
+		eventLog, err := crawler.NewEventLog(*eventsFile)
+		if err != nil {
+			appLogger.Fatal("Failed to open events file", err)
+		}
+		crawler.SetEventLog(eventLog)
+		defer eventLog.Close()
+		appLogger.WithField("events_file", *eventsFile).Info("Crawl events log enabled")
+	}
+
+	// Cache de DNS compartilhado pelos crawlers (DNS_CACHE_TTL=0 desativa)
+	crawler.SetDNSCache(crawler.NewDNSCacheFromConfig(cfg))
+
+	// Middlewares em volta de cada requisição dos collectors (FETCH_MIDDLEWARES)
+	fetchChain, err := crawler.NewFetchChainFromConfig(cfg)
+	if err != nil {
+		appLogger.Fatal("Invalid fetch middlewares", err)
+	}
+	crawler.SetFetchChain(fetchChain)
+	if fetchChain != nil {
+		appLogger.WithField("fetch_middlewares", fetchChain.Names()).Info("Fetch middlewares enabled")
+	}
+
+	// Registro de auditoria das requisições HTTP de saída (REQUEST_AUDIT)
+	auditRepo, err := repository.OpenRequestAudit(ctx, cfg.RequestAudit, mongoRepo, cfg.RequestAuditTTL)
+	if err != nil {
+		appLogger.WithError(err).Warn("Request audit log not available, continuing without it")
+	} else if auditRepo != nil {
+		audit := crawler.NewRequestAuditLog(auditRepo)
+		crawler.SetRequestAuditLog(audit)
+		defer audit.Close(context.Background())
+		appLogger.WithField("request_audit", cfg.RequestAudit).Info("Request audit log enabled")
+	}
+
+	// Coordinated shutdown on SIGINT/SIGTERM
+	shutdown := crawler.NewShutdownCoordinator(cfg.ShutdownTimeout)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigChan
+		appLogger.WithFields(map[string]interface{}{
+			"signal":   sig.String(),
+			"deadline": cfg.ShutdownTimeout.String(),
+		}).Info("Received shutdown signal, starting graceful shutdown")
+		shutdown.Shutdown()
+	}()
 
 	// Initialize AI service (optional)
 	var aiService *ai.GeminiService
@@ -106,33 +336,49 @@ func main() {
 		}
 	}
 
+	// Configuração recarregável: RUNTIME_CONFIG_FILE e as configurações por domínio (seletores,
+	// limites, scripts) gravadas no MongoDB
+	var domainSource config.DomainConfigSource
+	if mongoRepo != nil {
+		if domainConfigRepo, err := repository.NewMongoDomainConfigRepository(cfg.MongoURI, "crawler"); err != nil {
+			appLogger.WithError(err).Warn("Domain configs not available, continuing without them")
+		} else {
+			defer domainConfigRepo.Close()
+			domainSource = domainConfigRepo
+		}
+	}
+	configWatcher := config.NewConfigWatcher(cfg.RuntimeConfigFile, domainSource, cfg.ConfigReloadInterval)
+	configWatcher.Start(ctx)
+
 	// Start crawling based on mode
 	startTime := time.Now()
 	appLogger.WithField("mode", *mode).Info("Starting crawler execution")
 
-	if *mode == "incremental" {
-		runIncrementalCrawling(ctx, repo, urlRepo, aiService, urls, *enableAI, *enableFingerprinting, *maxAge, *aiThreshold, appLogger)
-	} else {
-		runFullCrawling(ctx, repo, aiService, urls, appLogger)
+	// Modo estrito: apenas os domínios das seeds são visitados
+	var allowlist *crawler.DomainAllowlist
+	if cfg.CrawlStrictDomains {
+		allowlist = crawler.NewDomainAllowlist(cfg.CrawlAllowSubdomains)
+		appLogger.WithField("allow_subdomains", cfg.CrawlAllowSubdomains).Info("Strict domain mode enabled")
 	}
 
-	duration := time.Since(startTime)
-	appLogger.WithField("total_duration", duration).Info("Crawler execution completed")
-}
-
-// loadURLsFromFile carrega URLs do arquivo de configuração
-func loadURLsFromFile(filePath string) ([]string, error) {
-	var urls []string
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
+	// Timeout por requisição e aborto das páginas lentas ou grandes
+	pageLimits := crawler.PageLimits{
+		RequestTimeout: cfg.CrawlRequestTimeout,
+		MaxDuration:    cfg.CrawlPageMaxDuration,
+		MaxBytes:       cfg.CrawlPageMaxBytes,
 	}
 
-	if err := json.Unmarshal(data, &urls); err != nil {
-		return nil, err
+	if *mode == "incremental" {
+		runIncrementalCrawling(ctx, taggedRepo, urlRepo, aiService, urls, *enableAI, *enableTranslation, *enableCondition, *enableFingerprinting, *maxAge, *minRevisit, *maxRevisit, *aiThreshold, cfg.CrawlPagesPerMinute, cfg.CrawlMaxDomainParallelism, allowlist, deadFilters, frontier, pageLimits, configWatcher, shutdown, cfg.CheckpointFile, appLogger)
+	} else {
+		runFullCrawling(ctx, taggedRepo, aiService, urls, allowlist, frontier, configWatcher, shutdown, cfg.CheckpointFile, appLogger)
 	}
 
-	return urls, nil
+	// Se o encerramento foi solicitado, aguarda o flush e o checkpoint antes de sair
+	shutdown.Wait()
+
+	duration := time.Since(startTime)
+	appLogger.WithField("total_duration", duration).Info("Crawler execution completed")
 }
 
 // calculateSuccessRate calcula a taxa de sucesso
@@ -144,11 +390,16 @@ func calculateSuccessRate(saved, found int) float64 {
 }
 
 // runFullCrawling executa crawling completo (modo tradicional)
-func runFullCrawling(ctx context.Context, repo repository.PropertyRepository, aiService *ai.GeminiService, urls []string, appLogger *logger.Logger) {
+func runFullCrawling(ctx context.Context, repo repository.PropertyRepository, aiService *ai.GeminiService, urls []string, allowlist *crawler.DomainAllowlist, frontier *crawler.SharedFrontier, configWatcher *config.ConfigWatcher, shutdown *crawler.ShutdownCoordinator, checkpointFile string, appLogger *logger.Logger) {
 	appLogger.Info("Running full crawling mode")
 
 	// Create and start the traditional crawler engine
 	engine := crawler.NewCrawlerEngine(repo, aiService)
+	engine.SetDomainAllowlist(allowlist)
+	engine.SetSharedFrontier(frontier)
+	engine.RegisterShutdown(shutdown, checkpointFile)
+	configWatcher.Register(engine)
+	defer configWatcher.Unregister(engine)
 
 	if err := engine.Start(ctx, urls); err != nil {
 		appLogger.Fatal("Full crawler execution failed", err)
@@ -166,23 +417,36 @@ func runFullCrawling(ctx context.Context, repo repository.PropertyRepository, ai
 }
 
 // runIncrementalCrawling executa crawling incremental
-func runIncrementalCrawling(ctx context.Context, repo repository.PropertyRepository, urlRepo repository.URLRepository, aiService *ai.GeminiService, urls []string, enableAI, enableFingerprinting bool, maxAge, aiThreshold time.Duration, appLogger *logger.Logger) {
+func runIncrementalCrawling(ctx context.Context, repo repository.PropertyRepository, urlRepo repository.URLRepository, aiService *ai.GeminiService, urls []string, enableAI, enableTranslation, enableCondition, enableFingerprinting bool, maxAge, minRevisit, maxRevisit, aiThreshold time.Duration, pagesPerMinute, maxDomainParallelism int, allowlist *crawler.DomainAllowlist, deadFilters *crawler.DeadFilterTracker, frontier *crawler.SharedFrontier, pageLimits crawler.PageLimits, configWatcher *config.ConfigWatcher, shutdown *crawler.ShutdownCoordinator, checkpointFile string, appLogger *logger.Logger) {
 	appLogger.Info("Running incremental crawling mode")
 
 	// Configure incremental crawler
 	config := crawler.IncrementalConfig{
 		EnableAI:             enableAI,
+		EnableTranslation:    enableTranslation,
+		EnableConditionScore: enableCondition,
 		EnableFingerprinting: enableFingerprinting,
 		MaxAge:               maxAge,
+		MinRevisitInterval:   minRevisit,
+		MaxRevisitInterval:   maxRevisit,
 		AIThreshold:          aiThreshold,
 		CleanupInterval:      7 * 24 * time.Hour, // 7 days
 		MaxConcurrency:       5,
 		DelayBetweenRequests: 1 * time.Second,
 		UserAgent:            "Go-Crawler-Incremental/2.0",
+		PagesPerMinute:       pagesPerMinute,
+		MaxDomainParallelism: maxDomainParallelism,
 	}
 
 	// Create incremental engine
 	engine := crawler.NewIncrementalCrawlerEngine(repo, urlRepo, aiService, config)
+	engine.SetDomainAllowlist(allowlist)
+	engine.SetDeadFilterTracker(deadFilters)
+	engine.SetPageLimits(pageLimits)
+	engine.SetSharedFrontier(frontier)
+	engine.RegisterShutdown(shutdown, checkpointFile)
+	configWatcher.Register(engine)
+	defer configWatcher.Unregister(engine)
 
 	if err := engine.Start(ctx, urls); err != nil {
 		appLogger.Fatal("Incremental crawler execution failed", err)
@@ -198,6 +462,8 @@ func runIncrementalCrawling(ctx context.Context, repo repository.PropertyReposit
 		"failed_urls":         stats.FailedURLs,
 		"ai_processing_count": stats.AIProcessingCount,
 		"ai_skipped_count":    stats.AISkippedCount,
+		"translated_count":    stats.TranslatedCount,
+		"condition_scored":    stats.ConditionScored,
 		"ai_savings_estimate": stats.AISavingsEstimate,
 		"processing_time":     stats.ProcessingTimeTotal,
 		"efficiency_gain":     fmt.Sprintf("%.1f%%", float64(stats.SkippedURLs)/float64(stats.TotalURLs)*100),
@@ -243,6 +509,18 @@ func performCleanup(ctx context.Context, urlRepo repository.URLRepository, appLo
 		appLogger.Fatal("Failed to cleanup old records", err)
 	}
 
+	// Também aplica os limites de tamanho configurados (MAX_PROCESSED_URLS / MAX_FINGERPRINTS)
+	if mongoURLRepo, ok := repository.MongoURLBackend(urlRepo); ok {
+		result, err := mongoURLRepo.PruneToLimits(ctx)
+		if err != nil {
+			appLogger.Fatal("Failed to prune URL collections", err)
+		}
+		appLogger.WithFields(map[string]interface{}{
+			"urls_removed":         result.URLsRemoved,
+			"fingerprints_removed": result.FingerprintsRemoved,
+		}).Info("URL collections pruned to configured limits")
+	}
+
 	appLogger.Info("Cleanup completed successfully")
 }
 
@@ -261,11 +539,25 @@ OPTIONS:
     -enable-ai
         Enable AI processing (default true)
         
+    -enable-translation
+        Translate descriptions to English into descricao_en (default false)
+        
+    -enable-condition
+        Score condition (novo, reformado, usado, precisa_reforma) from descriptions (default false)
+        
     -enable-fingerprinting
         Enable page fingerprinting for change detection (default true)
         
     -max-age duration
-        Maximum age before reprocessing a URL (default 24h)
+        Revisit interval for URLs without change history (default 24h)
+        
+    -min-revisit duration
+        Shortest adaptive revisit interval: the interval halves each time a page changes
+        (default -max-age)
+        
+    -max-revisit duration
+        Longest adaptive revisit interval: the interval doubles each time a page is unchanged
+        (default 168h)
         
     -ai-threshold duration
         Minimum time before AI reprocessing (default 6h)
@@ -274,7 +566,31 @@ OPTIONS:
         Show statistics and exit
         
     -cleanup
-        Cleanup old records and exit
+        Cleanup old records, prune to MAX_PROCESSED_URLS/MAX_FINGERPRINTS and exit
+        (the API also prunes periodically every URL_MAINTENANCE_INTERVAL)
+        
+    -resume
+        Resume from the checkpoint saved on the last graceful shutdown
+        
+    -cities string
+        Cities (Cidade-UF, comma separated) whose portal search pages are added as seeds,
+        using the enabled templates in PORTALS_FILE (default configs/portals.json)
+        
+    -portal-seeds
+        Print the portal seed URLs generated for -cities and exit
+        
+    -sinks string
+        Comma separated outputs for extracted properties (default OUTPUT_SINKS, "mongo"):
+        mongo, stdout, file:<path> (one JSON per line) and http(s) URLs (POST per property).
+        Without mongo, full mode runs as a pure extraction tool
+        
+    -tags string
+        Comma separated tags recorded on every property saved or found again by this run,
+        for filtering (GET /properties/search?tags=...) and per-campaign reporting
+        
+    -events-file string
+        Append one JSON line per event (page_fetched, page_classified, property_saved, error)
+        to this file, for post-processing by external tools
         
     -help
         Show this help message
@@ -289,6 +605,9 @@ EXAMPLES:
     # Incremental crawling without AI (faster)
     ./crawler -mode=incremental -enable-ai=false
     
+    # Incremental crawling with English descriptions
+    ./crawler -mode=incremental -enable-ai=true -enable-translation=true
+    
     # Custom thresholds
     ./crawler -mode=incremental -max-age=12h -ai-threshold=3h
     
@@ -297,6 +616,18 @@ EXAMPLES:
     
     # Cleanup old records
     ./crawler -cleanup
+    
+    # Extract to a file and a webhook without storing in MongoDB
+    ./crawler -mode=full -sinks="file:data/properties.ndjson,https://pipeline.example.com/ingest"
+    
+    # Tag the properties touched by a weekly campaign
+    ./crawler -mode=incremental -tags=muzambinho-weekly
+    
+    # Record crawl events as NDJSON
+    ./crawler -mode=incremental -events-file=data/events.ndjson
+    
+    # Preview the portal search pages generated for two cities
+    ./crawler -portal-seeds -cities="Muzambinho-MG,Poços de Caldas-MG"
 
 INCREMENTAL MODE BENEFITS:
     - 85-90% reduction in processing time
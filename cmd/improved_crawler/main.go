@@ -30,7 +30,10 @@ func main() {
 	appLogger.Info("Starting improved crawler application")
 
 	// Carrega configuração
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 	if *configFile != "" {
 		// Se especificado arquivo de config, carrega configurações específicas
 		appLogger.WithField("config_file", *configFile).Info("Using custom config file")
@@ -51,7 +54,16 @@ func main() {
 	}()
 
 	// Cria crawler melhorado
-	improvedCrawler := crawler.NewImprovedCrawler(ctx, cfg)
+	deps, err := crawler.NewCrawlerDependencies(ctx, cfg, false)
+	if err != nil {
+		log.Fatalf("Failed to create crawler dependencies: %v", err)
+	}
+	defer deps.Repository.Close()
+
+	improvedCrawler, err := crawler.NewImprovedCrawler(cfg, deps)
+	if err != nil {
+		log.Fatalf("Failed to create improved crawler: %v", err)
+	}
 
 	// Treina padrões usando arquivo de referência
 	appLogger.WithField("reference_file", *referenceFile).Info("Training patterns from reference file")
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/service"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		files        = flag.String("files", "", "Comma separated site files (default List-site.ini and SITES_FILE)")
+		defaultCity  = flag.String("default-city", "", "City assigned to domains whose city cannot be inferred (empty reports them as unresolved)")
+		defaultState = flag.String("default-state", "MG", "State (UF) of -default-city")
+		dryRun       = flag.Bool("dry-run", false, "Print the migration plan without writing to the repository")
+	)
+	flag.Parse()
+
+	appLogger := logger.NewLogger("migrate_sites_main")
+
+	if err := godotenv.Load(); err != nil {
+		appLogger.Warn("Warning: Error loading .env file, using default environment variables")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Fatal("Failed to load configuration", err)
+	}
+
+	// Arquivos migrados: os informados ou List-site.ini e o SITES_FILE usado pelos crawlers
+	fileList := []string{"List-site.ini", cfg.SitesFile}
+	if *files != "" {
+		fileList = nil
+		for _, file := range strings.Split(*files, ",") {
+			if file = strings.TrimSpace(file); file != "" {
+				fileList = append(fileList, file)
+			}
+		}
+	}
+
+	citySitesRepo, err := repository.NewMongoCitySitesRepository(cfg.MongoURI, "crawler")
+	if err != nil {
+		appLogger.Fatal("Failed to connect to city sites repository", err)
+	}
+	defer citySitesRepo.Close()
+
+	citySitesService := service.NewCitySitesService(citySitesRepo)
+	result, err := citySitesService.MigrateSiteFiles(context.Background(), service.SiteMigrationOptions{
+		Files:        fileList,
+		DefaultCity:  *defaultCity,
+		DefaultState: *defaultState,
+		DryRun:       *dryRun,
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to migrate site files", err)
+	}
+
+	// Plano completo em JSON (cidades, sites e URLs sem cidade) para conferência
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		appLogger.Fatal("Failed to write migration result", err)
+	}
+
+	mode := "written to"
+	if *dryRun {
+		mode = "planned for (dry run)"
+	}
+	fmt.Fprintf(os.Stderr, "%d URLs: %d sites added and %d already present %s %d cities; %d URLs without city\n",
+		result.URLs, result.SitesAdded, result.SitesExisting, mode, result.Cities, len(result.Unresolved))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/api"
+	"github.com/dujoseaugusto/go-crawler-project/api/openapi"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/sdkgen"
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	var (
+		annotations = flag.String("annotations", api.OpenAPIAnnotationsPath, "Hand-written OpenAPI annotations merged with the registered routes")
+		specPath    = flag.String("spec", "", "Generate from this OpenAPI document (e.g. a saved /openapi.json) instead of the routes")
+		goOut       = flag.String("go-out", "./client", "Go client module directory (empty skips)")
+		tsOut       = flag.String("ts-out", "./clients/ts", "TypeScript client package directory (empty skips)")
+		goPackage   = flag.String("go-package", "client", "Package name of the generated Go files")
+		skipTags    = flag.String("skip-tags", "Web Interface", "Comma-separated tags left out of the clients")
+	)
+	flag.Parse()
+
+	appLogger := logger.NewLogger("sdkgen_main")
+	gin.SetMode(gin.ReleaseMode)
+
+	var spec openapi.Spec
+	if *specPath != "" {
+		data, err := os.ReadFile(*specPath)
+		if err != nil {
+			appLogger.Fatal("Failed to read OpenAPI document", err)
+		}
+		if spec, err = openapi.ParseSpec(data); err != nil {
+			appLogger.Fatal("Failed to parse OpenAPI document", err)
+		}
+	} else {
+		var report openapi.Report
+		spec, report = api.BuildOpenAPISpec(*annotations)
+		appLogger.WithFields(map[string]interface{}{
+			"documented":   report.Documented,
+			"undocumented": len(report.Undocumented),
+			"schemas":      report.Schemas,
+		}).Info("OpenAPI specification built from routes")
+	}
+
+	options := sdkgen.DefaultOptions()
+	options.GoPackage = *goPackage
+	options.SkipTags = nil
+	for _, tag := range strings.Split(*skipTags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			options.SkipTags = append(options.SkipTags, tag)
+		}
+	}
+
+	if *goOut != "" {
+		files, err := sdkgen.GenerateGo(spec, options)
+		if err != nil {
+			appLogger.Fatal("Failed to generate Go client", err)
+		}
+		writeFiles(appLogger, *goOut, files)
+	}
+	if *tsOut != "" {
+		files, err := sdkgen.GenerateTypeScript(spec, options)
+		if err != nil {
+			appLogger.Fatal("Failed to generate TypeScript client", err)
+		}
+		writeFiles(appLogger, *tsOut, files)
+	}
+}
+
+// writeFiles grava os arquivos gerados no diretório do cliente
+func writeFiles(appLogger *logger.Logger, dir string, files map[string][]byte) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			appLogger.Fatal("Failed to create client directory", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			appLogger.Fatal("Failed to write generated file", err)
+		}
+		appLogger.WithField("file", path).Info("Generated client file")
+	}
+}
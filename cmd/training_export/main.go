@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		seedsDir       = flag.String("seeds", "", "Recorded seed set directory (seedset.json + pages); empty uses a synthetic mock site")
+		mockProperties = flag.Int("mock-properties", 20, "Listings in the synthetic mock site")
+		format         = flag.String("format", crawler.TrainingFormatCSV, "Output format: csv or jsonl")
+		output         = flag.String("output", "", "Output file (default stdout)")
+		labeledOnly    = flag.Bool("labeled-only", false, "Export only pages with a human label")
+		sampleSize     = flag.Int("sample-size", 1000, "Characters of main content kept in content_sample")
+		useAI          = flag.Bool("ai", false, "Add the Gemini verdict as features (requires GEMINI_API_KEY and consumes tokens)")
+	)
+	flag.Parse()
+
+	appLogger := logger.NewLogger("training_export_main")
+
+	if err := godotenv.Load(); err != nil {
+		appLogger.Warn("Warning: Error loading .env file, using default environment variables")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		appLogger.Info("Received shutdown signal, stopping export")
+		cancel()
+	}()
+
+	seedSet := crawler.MockBenchmarkSeedSet(*mockProperties)
+	if *seedsDir != "" {
+		var err error
+		if seedSet, err = crawler.LoadBenchmarkSeedSet(*seedsDir); err != nil {
+			appLogger.Fatal("Failed to load seed set", err)
+		}
+	}
+
+	// A IA é opcional: sem ela as colunas ai_* ficam vazias
+	var pageAI crawler.PageAIProvider
+	if *useAI {
+		enhancedAI, err := ai.NewEnhancedGeminiService(ctx)
+		if err != nil {
+			appLogger.Fatal("Failed to create enhanced AI service", err)
+		}
+		pageAI = enhancedAI
+	}
+
+	examples, err := crawler.BuildTrainingExamples(ctx, seedSet, crawler.NewClassificationExplainer(pageAI, nil), crawler.TrainingExportOptions{
+		LabeledOnly: *labeledOnly,
+		SampleSize:  *sampleSize,
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to build training examples", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			appLogger.Fatal("Failed to create output file", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := crawler.WriteTrainingExamples(out, *format, examples); err != nil {
+		appLogger.Fatal("Failed to write training examples", err)
+	}
+
+	labeled := 0
+	for _, example := range examples {
+		if example.HumanLabel != nil {
+			labeled++
+		}
+	}
+	appLogger.WithFields(map[string]interface{}{
+		"examples": len(examples),
+		"labeled":  labeled,
+		"format":   *format,
+	}).Info("Training data exported")
+}
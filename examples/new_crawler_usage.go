@@ -17,7 +17,10 @@ func main() {
 	logger.SetLevel(logger.INFO)
 
 	// Carregar configuração
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
 	// Criar repositório
 	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
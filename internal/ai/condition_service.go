@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ConditionScore representa a condição estimada de um imóvel a partir da descrição
+type ConditionScore struct {
+	Condicao      string  `json:"condicao"`  // novo, reformado, usado, precisa_reforma
+	Nivel         int     `json:"nivel"`     // valor ordinal (1 = precisa reforma, 4 = novo)
+	Confianca     float64 `json:"confianca"` // confiança da IA (0-1)
+	Justificativa string  `json:"justificativa,omitempty"`
+}
+
+// conditionCache guarda avaliações de condição indexadas pelo hash da descrição
+type conditionCache struct {
+	cache map[string]*ConditionScore
+	mutex sync.RWMutex
+}
+
+func newConditionCache() *conditionCache {
+	return &conditionCache{
+		cache: make(map[string]*ConditionScore),
+	}
+}
+
+// ScoreCondition usa a IA para classificar a condição do imóvel descrito
+func (s *GeminiService) ScoreCondition(ctx context.Context, descricao string) (*ConditionScore, error) {
+	descricao = strings.TrimSpace(descricao)
+	if descricao == "" {
+		return nil, fmt.Errorf("descrição vazia")
+	}
+
+	if s.model == nil || s.conditions == nil {
+		return nil, fmt.Errorf("serviço Gemini não inicializado")
+	}
+
+	cacheKey := DescriptionHash(descricao)
+
+	s.conditions.mutex.RLock()
+	cached, exists := s.conditions.cache[cacheKey]
+	s.conditions.mutex.RUnlock()
+	if exists {
+		return cached, nil
+	}
+
+	prompt := createConditionPrompt(descricao)
+	resp, err := generateContent(ctx, s.model, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao avaliar condição com Gemini: %v", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("resposta vazia do Gemini para avaliação de condição")
+	}
+
+	responseText, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
+	if !ok {
+		return nil, fmt.Errorf("resposta inesperada do Gemini para avaliação de condição")
+	}
+
+	score, err := parseConditionResponse(string(responseText))
+	if err != nil {
+		return nil, err
+	}
+
+	s.conditions.mutex.Lock()
+	s.conditions.cache[cacheKey] = score
+	s.conditions.mutex.Unlock()
+
+	return score, nil
+}
+
+// ApplyConditionScore preenche os campos de condição da propriedade
+func ApplyConditionScore(property *repository.Property, score *ConditionScore) {
+	if property == nil || score == nil || score.Condicao == "" {
+		return
+	}
+
+	property.Condicao = score.Condicao
+	property.CondicaoNivel = score.Nivel
+	property.CondicaoConfianca = score.Confianca
+}
+
+// createConditionPrompt cria o prompt de avaliação de condição
+func createConditionPrompt(descricao string) string {
+	return fmt.Sprintf(`Avalie a condição do imóvel pela descrição abaixo.
+Descrição: %s
+
+Responda APENAS com JSON:
+{"condicao": "novo|reformado|usado|precisa_reforma", "confianca": 0.0-1.0, "justificativa": "breve"}`,
+		truncateString(descricao, 1500))
+}
+
+// parseConditionResponse interpreta a resposta da IA e normaliza o rótulo
+func parseConditionResponse(response string) (*ConditionScore, error) {
+	jsonStr := extractJSON(response)
+	if jsonStr == "" {
+		return nil, fmt.Errorf("não foi possível extrair JSON da avaliação de condição")
+	}
+
+	var score ConditionScore
+	if err := json.Unmarshal([]byte(jsonStr), &score); err != nil {
+		return nil, fmt.Errorf("erro ao fazer parse da avaliação de condição: %v", err)
+	}
+
+	score.Condicao = repository.NormalizeCondition(score.Condicao)
+	if score.Condicao == "" {
+		return nil, fmt.Errorf("condição desconhecida na resposta da IA")
+	}
+	score.Nivel = repository.ConditionLevel(score.Condicao)
+
+	if score.Confianca < 0 {
+		score.Confianca = 0
+	} else if score.Confianca > 1 {
+		score.Confianca = 1
+	}
+
+	return &score, nil
+}
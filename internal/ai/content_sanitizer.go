@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/readability"
+)
+
+// Orçamento de tokens do conteúdo da página enviado em cada tipo de chamada ao Gemini
+const (
+	ClassificationTokenBudget = 500
+	PatternTokenBudget        = 750
+	ValidationTokenBudget     = 400
+	SelectorTokenBudget       = 650
+)
+
+// Tipos de chamada acompanhados nas estatísticas do sanitizador
+const (
+	AICallClassification = "classification"
+	AICallPatterns       = "pattern_analysis"
+	AICallValidation     = "validation"
+	AICallSelectors      = "selector_suggestion"
+)
+
+// EstimateTokens estima os tokens do texto (~4 caracteres por token, como nos modelos Gemini)
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// CapTokens corta o texto no orçamento de tokens, preferindo terminar entre palavras
+func CapTokens(text string, maxTokens int) string {
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return text
+	}
+	runes := []rune(text)
+	cut := string(runes[:maxTokens*4])
+	if index := strings.LastIndexAny(cut, " \n"); index > len(cut)/2 {
+		cut = cut[:index]
+	}
+	return cut + "..."
+}
+
+// looksLikeHTML indica se o conteúdo recebido é HTML (e não texto já extraído)
+func looksLikeHTML(content string) bool {
+	return strings.Contains(content, "</") || strings.Contains(content, "/>")
+}
+
+// ExtractMainText retorna o texto do conteúdo principal da página, sem navegação, scripts,
+// estilos e rodapés. Conteúdo que já é texto apenas tem os espaços normalizados.
+func ExtractMainText(content string) string {
+	if !looksLikeHTML(content) {
+		return readability.CollapseWhitespace(content)
+	}
+	article, err := readability.FromHTML(content)
+	if err != nil {
+		return readability.CollapseWhitespace(content)
+	}
+	return article.Text
+}
+
+// SanitizeHTML retorna o HTML do conteúdo principal sem ruído, preservando apenas class, id e
+// itemprop (o necessário para sugerir seletores CSS)
+func SanitizeHTML(rawHTML string) string {
+	article, err := readability.FromHTML(rawHTML)
+	if err != nil {
+		return readability.CollapseWhitespace(rawHTML)
+	}
+	return article.HTML("class", "id", "itemprop")
+}
+
+// SanitizerCallStats resume a economia de tokens de um tipo de chamada
+type SanitizerCallStats struct {
+	Calls          int64   `json:"calls"`
+	OriginalTokens int64   `json:"original_tokens"`
+	SentTokens     int64   `json:"sent_tokens"`
+	SavedTokens    int64   `json:"saved_tokens"`
+	SavedPercent   float64 `json:"saved_percent"`
+}
+
+// SanitizerStats resume a economia de tokens do sanitizador (total e por tipo de chamada)
+type SanitizerStats struct {
+	Total  SanitizerCallStats            `json:"total"`
+	ByCall map[string]SanitizerCallStats `json:"by_call"`
+}
+
+var (
+	sanitizerMutex sync.Mutex
+	sanitizerCalls = make(map[string]*SanitizerCallStats)
+)
+
+// PrepareContent sanitiza o conteúdo da página antes de uma chamada à IA (texto principal ou,
+// com keepMarkup, o HTML estrutural), aplica o orçamento de tokens e contabiliza a economia
+func PrepareContent(call, content string, maxTokens int, keepMarkup bool) string {
+	var prepared string
+	if keepMarkup && looksLikeHTML(content) {
+		prepared = SanitizeHTML(content)
+	} else {
+		prepared = ExtractMainText(content)
+	}
+	prepared = CapTokens(prepared, maxTokens)
+
+	sanitizerMutex.Lock()
+	defer sanitizerMutex.Unlock()
+	stats, exists := sanitizerCalls[call]
+	if !exists {
+		stats = &SanitizerCallStats{}
+		sanitizerCalls[call] = stats
+	}
+	stats.Calls++
+	stats.OriginalTokens += int64(EstimateTokens(content))
+	stats.SentTokens += int64(EstimateTokens(prepared))
+	return prepared
+}
+
+// GetSanitizerStats retorna os tokens economizados desde o início do processo
+func GetSanitizerStats() SanitizerStats {
+	sanitizerMutex.Lock()
+	defer sanitizerMutex.Unlock()
+
+	result := SanitizerStats{ByCall: make(map[string]SanitizerCallStats)}
+	for call, current := range sanitizerCalls {
+		stats := *current
+		finishCallStats(&stats)
+		result.ByCall[call] = stats
+
+		result.Total.Calls += stats.Calls
+		result.Total.OriginalTokens += stats.OriginalTokens
+		result.Total.SentTokens += stats.SentTokens
+	}
+	finishCallStats(&result.Total)
+	return result
+}
+
+// finishCallStats calcula os tokens economizados e o percentual
+func finishCallStats(stats *SanitizerCallStats) {
+	stats.SavedTokens = stats.OriginalTokens - stats.SentTokens
+	if stats.OriginalTokens > 0 {
+		stats.SavedPercent = math.Round(float64(stats.SavedTokens)/float64(stats.OriginalTokens)*10000) / 100
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
-	"regexp"
-	"strings"
 	"sync"
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
@@ -62,6 +60,9 @@ func NewEnhancedGeminiService(ctx context.Context) (*EnhancedGeminiService, erro
 
 // ClassifyPageContent usa IA para classificar se uma página é de anúncio de imóvel
 func (egs *EnhancedGeminiService) ClassifyPageContent(ctx context.Context, url, title, content string) (*PageClassificationResult, error) {
+	// Apenas o conteúdo principal da página é enviado (sem navegação, scripts e rodapés)
+	content = PrepareContent(AICallClassification, content, ClassificationTokenBudget, false)
+
 	// Gera chave de cache
 	cacheKey := fmt.Sprintf("classify_%x", generateHash(url+title+content[:min(len(content), 500)]))
 
@@ -77,7 +78,7 @@ func (egs *EnhancedGeminiService) ClassifyPageContent(ctx context.Context, url,
 	prompt := createClassificationPrompt(url, title, content)
 
 	// Chama API do Gemini
-	resp, err := egs.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, egs.model, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao classificar página com Gemini: %v", err)
 	}
@@ -102,6 +103,9 @@ func (egs *EnhancedGeminiService) ClassifyPageContent(ctx context.Context, url,
 
 // AnalyzePagePatterns usa IA para analisar padrões de uma página e sugerir seletores
 func (egs *EnhancedGeminiService) AnalyzePagePatterns(ctx context.Context, url, htmlContent string) (*PatternAnalysisResult, error) {
+	// HTML estrutural do conteúdo principal (class/id/itemprop), dentro do orçamento de tokens
+	htmlContent = PrepareContent(AICallPatterns, htmlContent, PatternTokenBudget, true)
+
 	// Gera chave de cache
 	cacheKey := fmt.Sprintf("pattern_%x", generateHash(url+htmlContent[:min(len(htmlContent), 1000)]))
 
@@ -117,7 +121,7 @@ func (egs *EnhancedGeminiService) AnalyzePagePatterns(ctx context.Context, url,
 	prompt := createPatternAnalysisPrompt(url, htmlContent)
 
 	// Chama API do Gemini
-	resp, err := egs.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, egs.model, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao analisar padrões com Gemini: %v", err)
 	}
@@ -147,9 +151,10 @@ func (egs *EnhancedGeminiService) ValidateExtractedData(ctx context.Context, pro
 		return property, nil
 	}
 
-	prompt := createValidationPrompt(property, originalHTML)
+	pageText := PrepareContent(AICallValidation, originalHTML, ValidationTokenBudget, false)
+	prompt := createValidationPrompt(property, pageText)
 
-	resp, err := egs.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, egs.model, prompt)
 	if err != nil {
 		return property, fmt.Errorf("erro ao validar dados com Gemini: %v", err)
 	}
@@ -169,9 +174,10 @@ func (egs *EnhancedGeminiService) ValidateExtractedData(ctx context.Context, pro
 
 // SuggestSelectorsForSite usa IA para sugerir seletores CSS para um site específico
 func (egs *EnhancedGeminiService) SuggestSelectorsForSite(ctx context.Context, domain, sampleHTML string) ([]SelectorSuggestion, error) {
+	sampleHTML = PrepareContent(AICallSelectors, sampleHTML, SelectorTokenBudget, true)
 	prompt := createSelectorSuggestionPrompt(domain, sampleHTML)
 
-	resp, err := egs.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, egs.model, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao sugerir seletores com Gemini: %v", err)
 	}
@@ -189,11 +195,8 @@ func (egs *EnhancedGeminiService) SuggestSelectorsForSite(ctx context.Context, d
 	return suggestions, nil
 }
 
-// createClassificationPrompt cria prompt para classificação de página
+// createClassificationPrompt cria prompt para classificação de página (conteúdo já sanitizado)
 func createClassificationPrompt(url, title, content string) string {
-	// Trunca conteúdo para economizar tokens
-	truncatedContent := truncateString(content, 2000)
-
 	return fmt.Sprintf(`Analise se esta página é um anúncio individual de imóvel para venda/aluguel.
 
 URL: %s
@@ -225,14 +228,11 @@ Indicadores de catálogo/listagem:
 - "Ver mais imóveis"`,
 		truncateString(url, 200),
 		truncateString(title, 200),
-		truncatedContent)
+		content)
 }
 
-// createPatternAnalysisPrompt cria prompt para análise de padrões
+// createPatternAnalysisPrompt cria prompt para análise de padrões (HTML já sanitizado)
 func createPatternAnalysisPrompt(url, htmlContent string) string {
-	// Extrai apenas as classes CSS e estrutura relevante
-	truncatedHTML := extractRelevantHTML(htmlContent, 3000)
-
 	return fmt.Sprintf(`Analise esta página de imóvel e sugira seletores CSS para extrair dados.
 
 URL: %s
@@ -255,14 +255,12 @@ Responda em JSON com:
 
 Foque em seletores específicos e únicos que identifiquem claramente cada tipo de dado.`,
 		truncateString(url, 200),
-		truncatedHTML)
+		htmlContent)
 }
 
-// createValidationPrompt cria prompt para validação de dados
-func createValidationPrompt(property repository.Property, originalHTML string) string {
-	htmlSnippet := extractRelevantHTML(originalHTML, 1500)
-
-	return fmt.Sprintf(`Valide e corrija os dados extraídos deste imóvel usando o HTML original.
+// createValidationPrompt cria prompt para validação de dados (texto principal da página já sanitizado)
+func createValidationPrompt(property repository.Property, pageText string) string {
+	return fmt.Sprintf(`Valide e corrija os dados extraídos deste imóvel usando o texto original da página.
 
 Dados extraídos:
 - Endereço: %s
@@ -275,7 +273,7 @@ Dados extraídos:
 - Tipo: %s
 - Descrição: %s
 
-HTML original: %s
+Texto original: %s
 
 Responda em JSON com os dados corrigidos:
 {
@@ -294,13 +292,11 @@ Responda em JSON com os dados corrigidos:
 		property.Valor, property.Quartos, property.Banheiros,
 		property.AreaTotal, property.TipoImovel,
 		truncateString(property.Descricao, 300),
-		htmlSnippet)
+		pageText)
 }
 
-// createSelectorSuggestionPrompt cria prompt para sugestão de seletores
+// createSelectorSuggestionPrompt cria prompt para sugestão de seletores (HTML já sanitizado)
 func createSelectorSuggestionPrompt(domain, sampleHTML string) string {
-	htmlSnippet := extractRelevantHTML(sampleHTML, 2500)
-
 	return fmt.Sprintf(`Analise este HTML de %s e sugira os melhores seletores CSS para extrair dados de imóveis.
 
 HTML: %s
@@ -320,7 +316,7 @@ Priorize seletores:
 2. Estáveis (não dependem de posição)
 3. Semânticos (nomes descritivos)
 4. Testáveis (únicos na página)`,
-		domain, htmlSnippet)
+		domain, sampleHTML)
 }
 
 // parseClassificationResponse processa resposta de classificação
@@ -389,66 +385,6 @@ func parseSelectorSuggestions(response string) ([]SelectorSuggestion, error) {
 	return suggestions, nil
 }
 
-// extractRelevantHTML extrai partes relevantes do HTML para análise
-func extractRelevantHTML(html string, maxLen int) string {
-	// Remove scripts e styles
-	html = removeScriptsAndStyles(html)
-
-	// Foca em elementos com classes que parecem relevantes para imóveis
-	relevantClasses := []string{
-		"price", "preco", "valor", "value",
-		"address", "endereco", "location", "localizacao",
-		"description", "descricao", "details", "detalhes",
-		"property", "imovel", "listing", "anuncio",
-		"features", "caracteristicas", "amenities",
-		"rooms", "quartos", "bedrooms", "dormitorios",
-		"bathrooms", "banheiros", "area", "size",
-	}
-
-	var relevantParts []string
-	lines := strings.Split(html, "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Verifica se a linha contém classes relevantes
-		for _, class := range relevantClasses {
-			if strings.Contains(strings.ToLower(line), class) {
-				relevantParts = append(relevantParts, line)
-				break
-			}
-		}
-
-		// Para quando atingir o limite
-		if len(strings.Join(relevantParts, "\n")) > maxLen {
-			break
-		}
-	}
-
-	result := strings.Join(relevantParts, "\n")
-	if len(result) > maxLen {
-		result = result[:maxLen] + "..."
-	}
-
-	return result
-}
-
-// removeScriptsAndStyles remove scripts e styles do HTML
-func removeScriptsAndStyles(html string) string {
-	// Remove scripts
-	scriptRegex := regexp.MustCompile(`(?s)<script[^>]*>.*?</script>`)
-	html = scriptRegex.ReplaceAllString(html, "")
-
-	// Remove styles
-	styleRegex := regexp.MustCompile(`(?s)<style[^>]*>.*?</style>`)
-	html = styleRegex.ReplaceAllString(html, "")
-
-	return html
-}
-
 // isDataComplete verifica se os dados estão completos o suficiente
 func (egs *EnhancedGeminiService) isDataComplete(property repository.Property) bool {
 	hasBasicInfo := property.Endereco != "" && property.Valor > 0 && property.TipoImovel != ""
@@ -0,0 +1,7 @@
+package ai
+
+import "errors"
+
+// ErrAIUnavailable indica que o serviço de IA não está configurado ou não pôde ser criado.
+// Os crawlers continuam sem IA quando recebem este erro.
+var ErrAIUnavailable = errors.New("AI service unavailable")
@@ -30,23 +30,25 @@ type PropertyCache struct {
 
 // GeminiService é responsável por processar dados usando a API do Gemini
 type GeminiService struct {
-	model       *genai.GenerativeModel
-	cache       *PropertyCache
-	batchSize   int
-	batchBuffer []repository.Property
-	bufferMutex sync.Mutex
+	model        *genai.GenerativeModel
+	cache        *PropertyCache
+	translations *TranslationCache
+	conditions   *conditionCache
+	batchSize    int
+	batchBuffer  []repository.Property
+	bufferMutex  sync.Mutex
 }
 
 // NewGeminiService cria uma nova instância do serviço Gemini
 func NewGeminiService(ctx context.Context) (*GeminiService, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY não encontrada no ambiente")
+		return nil, fmt.Errorf("%w: GEMINI_API_KEY não encontrada no ambiente", ErrAIUnavailable)
 	}
 
 	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar cliente Gemini: %v", err)
+		return nil, fmt.Errorf("%w: erro ao criar cliente Gemini: %v", ErrAIUnavailable, err)
 	}
 
 	// Usando o modelo Gemini 1.5 Flash para melhor desempenho
@@ -65,10 +67,12 @@ func NewGeminiService(ctx context.Context) (*GeminiService, error) {
 	}
 
 	return &GeminiService{
-		model:       model,
-		cache:       cache,
-		batchSize:   5, // Processar 5 propriedades por vez
-		batchBuffer: make([]repository.Property, 0, 5),
+		model:        model,
+		cache:        cache,
+		translations: NewTranslationCache(),
+		conditions:   newConditionCache(),
+		batchSize:    5, // Processar 5 propriedades por vez
+		batchBuffer:  make([]repository.Property, 0, 5),
 	}, nil
 }
 
@@ -169,7 +173,7 @@ func (s *GeminiService) processBatch(ctx context.Context, targetCacheKey string)
 	prompt := createBatchPrompt(s.batchBuffer)
 
 	// Chama API do Gemini
-	resp, err := s.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, s.model, prompt)
 	if err != nil {
 		// Em caso de erro, processa individualmente
 		return s.processSingle(ctx, s.batchBuffer[len(s.batchBuffer)-1], targetCacheKey)
@@ -209,7 +213,7 @@ func (s *GeminiService) processBatch(ctx context.Context, targetCacheKey string)
 func (s *GeminiService) processSingle(ctx context.Context, property repository.Property, cacheKey string) (repository.Property, error) {
 	prompt := createOptimizedPrompt(property)
 
-	resp, err := s.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, s.model, prompt)
 	if err != nil {
 		return property, fmt.Errorf("erro ao gerar conteúdo com Gemini: %v", err)
 	}
@@ -329,45 +333,55 @@ func updatePropertyFromMap(original repository.Property, data map[string]interfa
 	return result
 }
 
-// FlushBatch processa qualquer propriedade restante no buffer
+// FlushBatch processa qualquer propriedade restante no buffer; os resultados ficam apenas no
+// cache (use FlushPending para gravá-los)
 func (s *GeminiService) FlushBatch(ctx context.Context) error {
+	_, err := s.FlushPending(ctx)
+	return err
+}
+
+// FlushPending processa as propriedades restantes no buffer e devolve o resultado da IA de cada
+// uma, para que o encerramento do crawling grave o lote em vez de perdê-lo com o cache
+func (s *GeminiService) FlushPending(ctx context.Context) ([]repository.Property, error) {
 	s.bufferMutex.Lock()
 	defer s.bufferMutex.Unlock()
 
 	if len(s.batchBuffer) == 0 {
-		return nil
+		return nil, nil
 	}
+	pending := s.batchBuffer
+	s.batchBuffer = make([]repository.Property, 0, s.batchSize)
 
 	// Processa o buffer restante
-	prompt := createBatchPrompt(s.batchBuffer)
-	resp, err := s.model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		// Em caso de erro, processa individualmente
-		for _, property := range s.batchBuffer {
-			cacheKey := s.generateCacheKey(property)
-			s.processSingle(ctx, property, cacheKey)
-		}
-		s.batchBuffer = s.batchBuffer[:0]
-		return err
-	}
-
-	if len(resp.Candidates) > 0 && len(resp.Candidates[0].Content.Parts) > 0 {
+	prompt := createBatchPrompt(pending)
+	resp, err := generateContent(ctx, s.model, prompt)
+	if err == nil && len(resp.Candidates) > 0 && len(resp.Candidates[0].Content.Parts) > 0 {
 		responseText := resp.Candidates[0].Content.Parts[0].(genai.Text)
-		processedProperties, err := parseBatchResponse(string(responseText), s.batchBuffer)
-		if err == nil {
-			// Armazena todas no cache
+		if processedProperties, parseErr := parseBatchResponse(string(responseText), pending); parseErr == nil {
+			var results []repository.Property
 			for i, processed := range processedProperties {
-				if i < len(s.batchBuffer) {
-					key := s.generateCacheKey(s.batchBuffer[i])
-					s.setCache(key, processed)
+				if i < len(pending) {
+					s.setCache(s.generateCacheKey(pending[i]), processed)
+					results = append(results, processed)
 				}
 			}
+			return results, nil
 		}
 	}
 
-	// Limpa o buffer
-	s.batchBuffer = s.batchBuffer[:0]
-	return nil
+	// Em caso de erro, processa individualmente
+	var results []repository.Property
+	for _, property := range pending {
+		processed, singleErr := s.processSingle(ctx, property, s.generateCacheKey(property))
+		if singleErr != nil {
+			if err == nil {
+				err = singleErr
+			}
+			continue
+		}
+		results = append(results, processed)
+	}
+	return results, err
 }
 
 // GetCacheStats retorna estatísticas do cache
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// TranslationCache guarda traduções já feitas, indexadas pelo hash da descrição original
+type TranslationCache struct {
+	cache map[string]string
+	mutex sync.RWMutex
+}
+
+// NewTranslationCache cria um cache de traduções vazio
+func NewTranslationCache() *TranslationCache {
+	return &TranslationCache{
+		cache: make(map[string]string),
+	}
+}
+
+// Get recupera uma tradução do cache
+func (tc *TranslationCache) Get(key string) (string, bool) {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+
+	translated, exists := tc.cache[key]
+	return translated, exists
+}
+
+// Set armazena uma tradução no cache
+func (tc *TranslationCache) Set(key, translated string) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	tc.cache[key] = translated
+}
+
+// Size retorna o número de traduções em cache
+func (tc *TranslationCache) Size() int {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+
+	return len(tc.cache)
+}
+
+// DescriptionHash gera a chave de cache a partir da descrição normalizada (descrições iguais
+// após a normalização reaproveitam a tradução e a avaliação de condição)
+func DescriptionHash(descricao string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(descricao)), " ")
+	hash := md5.Sum([]byte(normalized))
+	return fmt.Sprintf("%x", hash)
+}
+
+// TranslateDescription traduz a descrição de um imóvel para inglês.
+// Descrições idênticas (após normalização) são traduzidas apenas uma vez.
+func (s *GeminiService) TranslateDescription(ctx context.Context, descricao string) (string, error) {
+	descricao = strings.TrimSpace(descricao)
+	if descricao == "" {
+		return "", nil
+	}
+
+	if s.model == nil || s.translations == nil {
+		return "", fmt.Errorf("serviço Gemini não inicializado")
+	}
+
+	cacheKey := DescriptionHash(descricao)
+	if cached, found := s.translations.Get(cacheKey); found {
+		return cached, nil
+	}
+
+	prompt := createTranslationPrompt(descricao)
+	resp, err := generateContent(ctx, s.model, prompt)
+	if err != nil {
+		return "", fmt.Errorf("erro ao traduzir descrição com Gemini: %v", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("resposta vazia do Gemini para tradução")
+	}
+
+	responseText, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
+	if !ok {
+		return "", fmt.Errorf("resposta inesperada do Gemini para tradução")
+	}
+
+	translated := strings.TrimSpace(string(responseText))
+	if translated == "" {
+		return "", fmt.Errorf("tradução vazia retornada pelo Gemini")
+	}
+
+	s.translations.Set(cacheKey, translated)
+	return translated, nil
+}
+
+// createTranslationPrompt cria o prompt de tradução
+func createTranslationPrompt(descricao string) string {
+	return fmt.Sprintf(`Traduza para inglês a descrição de imóvel abaixo.
+Mantenha números, valores e nomes próprios. Responda apenas com o texto traduzido.
+
+%s`, truncateString(descricao, 1500))
+}
+
+// GetTranslationCacheSize retorna quantas traduções estão em cache
+func (s *GeminiService) GetTranslationCacheSize() int {
+	if s.translations == nil {
+		return 0
+	}
+	return s.translations.Size()
+}
@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// UsageStats resume as chamadas ao Gemini e os tokens consumidos desde o início do processo.
+// Quando a resposta não informa o consumo, os tokens são estimados pelo tamanho do texto.
+type UsageStats struct {
+	Calls        int64 `json:"calls"`
+	Errors       int64 `json:"errors"`
+	PromptTokens int64 `json:"prompt_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+var (
+	usageMutex sync.Mutex
+	usage      UsageStats
+)
+
+// UsageTracker acumula o consumo do Gemini de uma execução (ex: um job de crawling). As chamadas
+// feitas com um contexto de WithUsageTracker são somadas ao tracker, além do total do processo.
+type UsageTracker struct {
+	mutex sync.Mutex
+	stats UsageStats
+}
+
+// Stats retorna o consumo acumulado no tracker
+func (t *UsageTracker) Stats() UsageStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.stats
+}
+
+func (t *UsageTracker) add(delta UsageStats) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.stats = t.stats.Add(delta)
+}
+
+type usageTrackerKey struct{}
+
+// WithUsageTracker retorna um contexto cujas chamadas ao Gemini são contabilizadas em tracker
+func WithUsageTracker(ctx context.Context, tracker *UsageTracker) context.Context {
+	if tracker == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, usageTrackerKey{}, tracker)
+}
+
+// generateContent chama o modelo e contabiliza as chamadas e os tokens consumidos no processo e
+// no tracker do contexto, se houver
+func generateContent(ctx context.Context, model *genai.GenerativeModel, prompt string) (*genai.GenerateContentResponse, error) {
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+
+	delta := callUsage(resp, err, prompt)
+	usageMutex.Lock()
+	usage = usage.Add(delta)
+	usageMutex.Unlock()
+	if tracker, ok := ctx.Value(usageTrackerKey{}).(*UsageTracker); ok {
+		tracker.add(delta)
+	}
+	return resp, err
+}
+
+// callUsage calcula o consumo de uma chamada
+func callUsage(resp *genai.GenerateContentResponse, err error, prompt string) UsageStats {
+	delta := UsageStats{Calls: 1}
+	if err != nil {
+		delta.Errors = 1
+		delta.PromptTokens = int64(EstimateTokens(prompt))
+		return delta
+	}
+
+	if resp.UsageMetadata != nil && resp.UsageMetadata.PromptTokenCount > 0 {
+		delta.PromptTokens = int64(resp.UsageMetadata.PromptTokenCount)
+		delta.OutputTokens = int64(resp.UsageMetadata.CandidatesTokenCount)
+		return delta
+	}
+	delta.PromptTokens = int64(EstimateTokens(prompt))
+	for _, candidate := range resp.Candidates {
+		if candidate.Content == nil {
+			continue
+		}
+		for _, part := range candidate.Content.Parts {
+			if text, ok := part.(genai.Text); ok {
+				delta.OutputTokens += int64(EstimateTokens(string(text)))
+			}
+		}
+	}
+	return delta
+}
+
+// GetUsageStats retorna o consumo acumulado do Gemini
+func GetUsageStats() UsageStats {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+	return usage
+}
+
+// Add retorna a soma de dois consumos
+func (u UsageStats) Add(other UsageStats) UsageStats {
+	return UsageStats{
+		Calls:        u.Calls + other.Calls,
+		Errors:       u.Errors + other.Errors,
+		PromptTokens: u.PromptTokens + other.PromptTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+	}
+}
+
+// Sub retorna o consumo entre duas leituras (ex: durante uma execução)
+func (u UsageStats) Sub(previous UsageStats) UsageStats {
+	return UsageStats{
+		Calls:        u.Calls - previous.Calls,
+		Errors:       u.Errors - previous.Errors,
+		PromptTokens: u.PromptTokens - previous.PromptTokens,
+		OutputTokens: u.OutputTokens - previous.OutputTokens,
+	}
+}
@@ -1,21 +1,258 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 )
 
 type Config struct {
-	Port         string `env:"PORT" envDefault:"8080"`
-	MongoURI     string `env:"MONGO_URI" envDefault:"mongodb://localhost:27017"`
-	SitesFile    string `env:"SITES_FILE" envDefault:"configs/sites.json"`
+	Port                 string        `env:"PORT" envDefault:"8080"`
+	MongoURI             string        `env:"MONGO_URI" envDefault:"mongodb://localhost:27017"`
+	SitesFile            string        `env:"SITES_FILE" envDefault:"configs/sites.json"`
+	PortalsFile          string        `env:"PORTALS_FILE" envDefault:"configs/portals.json"`
+	FeatureKeywordsFile  string        `env:"FEATURE_KEYWORDS_FILE" envDefault:"configs/features.json"`
+	RuntimeConfigFile    string        `env:"RUNTIME_CONFIG_FILE" envDefault:"configs/runtime.json"`
+	ConfigReloadInterval time.Duration `env:"CONFIG_RELOAD_INTERVAL" envDefault:"30s"`
+	ShutdownTimeout      time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	CheckpointFile       string        `env:"CHECKPOINT_FILE" envDefault:"data/crawl_checkpoint.json"`
+
+	// Saídas dos imóveis extraídos pelo crawler: mongo, stdout, file:<caminho> e/ou URLs http(s)
+	OutputSinks []string `env:"OUTPUT_SINKS" envSeparator:"," envDefault:"mongo"`
+
+	// Cliente Mongo: pool, timeouts, read/write concern e novas tentativas (0 ou vazio mantém o padrão da URI)
+	MongoMaxPoolSize            uint64        `env:"MONGO_MAX_POOL_SIZE" envDefault:"0"`
+	MongoMinPoolSize            uint64        `env:"MONGO_MIN_POOL_SIZE" envDefault:"0"`
+	MongoMaxConnIdleTime        time.Duration `env:"MONGO_MAX_CONN_IDLE_TIME" envDefault:"0"`
+	MongoConnectTimeout         time.Duration `env:"MONGO_CONNECT_TIMEOUT" envDefault:"10s"`
+	MongoServerSelectionTimeout time.Duration `env:"MONGO_SERVER_SELECTION_TIMEOUT" envDefault:"30s"`
+	MongoSocketTimeout          time.Duration `env:"MONGO_SOCKET_TIMEOUT" envDefault:"0"`
+	MongoHeartbeatInterval      time.Duration `env:"MONGO_HEARTBEAT_INTERVAL" envDefault:"10s"`
+	MongoRetryWrites            bool          `env:"MONGO_RETRY_WRITES" envDefault:"true"`
+	MongoRetryReads             bool          `env:"MONGO_RETRY_READS" envDefault:"true"`
+	MongoReadConcern            string        `env:"MONGO_READ_CONCERN"`
+	MongoWriteConcern           string        `env:"MONGO_WRITE_CONCERN"`
+	MongoWriteTimeout           time.Duration `env:"MONGO_WRITE_TIMEOUT" envDefault:"0"`
+	MongoOperationRetries       int           `env:"MONGO_OPERATION_RETRIES" envDefault:"3"`
+	MongoRetryBackoff           time.Duration `env:"MONGO_RETRY_BACKOFF" envDefault:"1s"`
+
+	// Retenção das coleções de URLs processadas e fingerprints (0 desativa o limite)
+	URLRecordTTL           time.Duration `env:"URL_RECORD_TTL" envDefault:"2160h"`
+	FingerprintTTL         time.Duration `env:"FINGERPRINT_TTL" envDefault:"4320h"`
+	MaxProcessedURLs       int64         `env:"MAX_PROCESSED_URLS" envDefault:"500000"`
+	MaxFingerprints        int64         `env:"MAX_FINGERPRINTS" envDefault:"200000"`
+	URLMaintenanceInterval time.Duration `env:"URL_MAINTENANCE_INTERVAL" envDefault:"6h"`
+
+	// Intervalo do cálculo do índice de preços (mediana do R$/m² por bairro e cidade); 0 desativa
+	PriceIndexInterval time.Duration `env:"PRICE_INDEX_INTERVAL" envDefault:"24h"`
+
+	// Intervalo do cálculo das métricas de qualidade dos dados por domínio; 0 desativa
+	QualityMetricsInterval time.Duration `env:"QUALITY_METRICS_INTERVAL" envDefault:"24h"`
+
+	// Intervalo da fusão das duplicatas de todas as cidades em registros canônicos; 0 desativa
+	CanonicalMergeInterval time.Duration `env:"CANONICAL_MERGE_INTERVAL" envDefault:"24h"`
+
+	// Alertas de queda no volume de imóveis por domínio (webhook e/ou e-mail)
+	AlertWebhookURL      string   `env:"ALERT_WEBHOOK_URL"`
+	AlertSMTPAddr        string   `env:"ALERT_SMTP_ADDR"` // host:porta
+	AlertSMTPUsername    string   `env:"ALERT_SMTP_USERNAME"`
+	AlertSMTPPassword    string   `env:"ALERT_SMTP_PASSWORD"`
+	AlertEmailFrom       string   `env:"ALERT_EMAIL_FROM"`
+	AlertEmailTo         []string `env:"ALERT_EMAIL_TO" envSeparator:","`
+	YieldAlertHistory    int      `env:"YIELD_ALERT_HISTORY" envDefault:"10"`
+	YieldAlertMinReports int      `env:"YIELD_ALERT_MIN_REPORTS" envDefault:"3"`
+	YieldAlertDropRatio  float64  `env:"YIELD_ALERT_DROP_RATIO" envDefault:"0.5"`
+	YieldAlertStdDevs    float64  `env:"YIELD_ALERT_STDDEVS" envDefault:"2"`
+
+	// SLA de atualização dos dados de cada cidade (refresh_sla_hours da cidade substitui; 0
+	// desativa) e intervalo da verificação que alerta as cidades desatualizadas
+	CityRefreshSLA       time.Duration `env:"CITY_REFRESH_SLA" envDefault:"48h"`
+	CitySLACheckInterval time.Duration `env:"CITY_SLA_CHECK_INTERVAL" envDefault:"1h"`
+
+	// Feeds RSS/Atom de novos anúncios (além dos configurados por domínio) e intervalo de leitura; 0 desativa
+	FeedURLs         []string      `env:"FEED_URLS" envSeparator:","`
+	FeedPollInterval time.Duration `env:"FEED_POLL_INTERVAL" envDefault:"15m"`
+
+	// Modo estrito de domínios: links e redirecionamentos para domínios fora das seeds (city-sites)
+	// e das configurações por domínio são recusados; subdomínios só com CRAWL_ALLOW_SUBDOMAINS
+	// ou allow_subdomains na configuração do domínio
+	CrawlStrictDomains   bool `env:"CRAWL_STRICT_DOMAINS" envDefault:"false"`
+	CrawlAllowSubdomains bool `env:"CRAWL_ALLOW_SUBDOMAINS" envDefault:"false"`
+
+	// Timeout de cada requisição e limites das páginas: páginas baixadas em mais de
+	// CRAWL_PAGE_MAX_DURATION ou com mais de CRAWL_PAGE_MAX_BYTES não são analisadas e contam como
+	// slow_page no domínio (GET /crawler/errors); 0 desativa cada limite
+	CrawlRequestTimeout  time.Duration `env:"CRAWL_REQUEST_TIMEOUT" envDefault:"15s"`
+	CrawlPageMaxDuration time.Duration `env:"CRAWL_PAGE_MAX_DURATION" envDefault:"10s"`
+	CrawlPageMaxBytes    int           `env:"CRAWL_PAGE_MAX_BYTES" envDefault:"5242880"`
+
+	// Cache de DNS em memória dos crawlers (0 desativa), inclusive das falhas por DNS_NEGATIVE_TTL;
+	// hosts com DNS_FAILURE_THRESHOLD falhas seguidas ficam isolados por DNS_QUARANTINE e suas URLs
+	// são descartadas sem consumir o orçamento do crawling (GET /crawler/dns)
+	DNSCacheTTL         time.Duration `env:"DNS_CACHE_TTL" envDefault:"5m"`
+	DNSNegativeTTL      time.Duration `env:"DNS_NEGATIVE_TTL" envDefault:"1m"`
+	DNSFailureThreshold int           `env:"DNS_FAILURE_THRESHOLD" envDefault:"3"`
+	DNSQuarantine       time.Duration `env:"DNS_QUARANTINE" envDefault:"15m"`
+
+	// Middlewares em volta de cada requisição dos collectors, do mais externo ao mais próximo da rede:
+	// logging, robots, cache, ratelimit, headless e proxy (sempre o último); vazio desativa
+	FetchMiddlewares       []string      `env:"FETCH_MIDDLEWARES" envSeparator:","`
+	FetchCacheTTL          time.Duration `env:"FETCH_CACHE_TTL" envDefault:"10m"`
+	FetchCacheMaxEntries   int           `env:"FETCH_CACHE_MAX_ENTRIES" envDefault:"1000"`
+	FetchRobotsTTL         time.Duration `env:"FETCH_ROBOTS_TTL" envDefault:"1h"`
+	FetchRateLimitInterval time.Duration `env:"FETCH_RATE_LIMIT_INTERVAL" envDefault:"1s"`
+	FetchProxyURLs         []string      `env:"FETCH_PROXY_URLS" envSeparator:","`
+	FetchHeadlessURL       string        `env:"FETCH_HEADLESS_URL" envDefault:""`
+	FetchHeadlessTimeout   time.Duration `env:"FETCH_HEADLESS_TIMEOUT" envDefault:"60s"`
+
+	// Registro de auditoria de cada requisição HTTP de saída: "mongo" (coleção request_audit, com
+	// expiração após REQUEST_AUDIT_TTL; 0 mantém) ou "file:caminho.ndjson"; vazio desativa
+	RequestAudit    string        `env:"REQUEST_AUDIT" envDefault:""`
+	RequestAuditTTL time.Duration `env:"REQUEST_AUDIT_TTL" envDefault:"720h"`
+
+	// Orçamento global de páginas por minuto (0 desativa) e teto do paralelismo ajustado por domínio
+	CrawlPagesPerMinute       int `env:"CRAWL_PAGES_PER_MINUTE" envDefault:"0"`
+	CrawlMaxDomainParallelism int `env:"CRAWL_MAX_DOMAIN_PARALLELISM" envDefault:"4"`
+
+	// Paralelismo por domínio dos coletores de listagem e de detalhes dos crawlers improved e AI,
+	// valores específicos por domínio ("dominio=n") e ajuste automático pela taxa de erros e latência
+	CrawlMainParallelism        int           `env:"CRAWL_MAIN_PARALLELISM" envDefault:"2"`
+	CrawlDetailParallelism      int           `env:"CRAWL_DETAIL_PARALLELISM" envDefault:"1"`
+	CrawlDomainParallelism      []string      `env:"CRAWL_DOMAIN_PARALLELISM" envSeparator:","`
+	CrawlAutoTune               bool          `env:"CRAWL_AUTOTUNE" envDefault:"false"`
+	CrawlAutoTuneMaxParallelism int           `env:"CRAWL_AUTOTUNE_MAX_PARALLELISM" envDefault:"8"`
+	CrawlAutoTuneMaxErrorRate   float64       `env:"CRAWL_AUTOTUNE_MAX_ERROR_RATE" envDefault:"0.05"`
+	CrawlAutoTuneMaxLatency     time.Duration `env:"CRAWL_AUTOTUNE_MAX_LATENCY" envDefault:"3s"`
+
+	// Amostragem da validação por IA do crawler AI: fração dos anúncios validados, nota mínima dos
+	// campos-chave (abaixo dela o anúncio é sempre validado) e taxas por domínio ("dominio=taxa")
+	AIValidationSampleRate    float64  `env:"AI_VALIDATION_SAMPLE_RATE" envDefault:"0.1"`
+	AIValidationMinConfidence float64  `env:"AI_VALIDATION_MIN_CONFIDENCE" envDefault:"0.6"`
+	AIValidationDomainRates   []string `env:"AI_VALIDATION_DOMAIN_RATES" envSeparator:","`
+
+	// Experimento A/B dos limiares do classificador de anúncios: a fração amostrada das páginas é
+	// classificada também com os limiares candidatos, sem mudar a decisão (0 desativa)
+	ClassifierExperimentName            string  `env:"CLASSIFIER_EXPERIMENT_NAME"`
+	ClassifierExperimentSampleRate      float64 `env:"CLASSIFIER_EXPERIMENT_SAMPLE_RATE" envDefault:"0"`
+	ClassifierExperimentMinConfidence   float64 `env:"CLASSIFIER_EXPERIMENT_MIN_CONFIDENCE" envDefault:"0.5"`
+	ClassifierExperimentMinRequirements int     `env:"CLASSIFIER_EXPERIMENT_MIN_REQUIREMENTS" envDefault:"3"`
+
+	// Curvas de calibração das notas de confiança (padrões, IA e heurísticas), geradas por
+	// cmd/benchmark -calibrate; arquivo inexistente mantém as notas originais
+	CalibrationFile string `env:"CALIBRATION_FILE" envDefault:"data/calibration.json"`
+
+	// Cache local (BadgerDB) de URLs processadas e fingerprints à frente do Mongo (vazio desativa)
+	URLCacheDir          string        `env:"URL_CACHE_DIR"`
+	URLCacheTTL          time.Duration `env:"URL_CACHE_TTL" envDefault:"24h"`
+	URLCacheSyncInterval time.Duration `env:"URL_CACHE_SYNC_INTERVAL" envDefault:"5m"`
+
+	// Validade da lease das URLs no registro compartilhado entre jobs simultâneos (0 desativa)
+	FrontierLeaseTTL time.Duration `env:"FRONTIER_LEASE_TTL" envDefault:"30m"`
+
+	// Janela em que um disparo de crawling repetido com o mesmo Idempotency-Key retorna o job
+	// original em vez de iniciar outro
+	CrawlIdempotencyWindow time.Duration `env:"CRAWL_IDEMPOTENCY_WINDOW" envDefault:"24h"`
+
+	// Tempo que os jobs finalizados continuam em GET /crawler/jobs e intervalo da limpeza; 0 desativa
+	CrawlJobRetention       time.Duration `env:"CRAWL_JOB_RETENTION" envDefault:"24h"`
+	CrawlJobCleanupInterval time.Duration `env:"CRAWL_JOB_CLEANUP_INTERVAL" envDefault:"1h"`
+
+	// Preços em US$ da estimativa de custo de cada job (GET /crawler/costs): tokens da IA por
+	// milhão, hora de execução e GB baixado (o tráfego por proxy soma CRAWL_PROXY_PRICE_PER_GB)
+	AIInputPricePerMillion   float64 `env:"AI_INPUT_PRICE_PER_MILLION" envDefault:"0.075"`
+	AIOutputPricePerMillion  float64 `env:"AI_OUTPUT_PRICE_PER_MILLION" envDefault:"0.30"`
+	CrawlComputePricePerHour float64 `env:"CRAWL_COMPUTE_PRICE_PER_HOUR" envDefault:"0.05"`
+	CrawlBandwidthPricePerGB float64 `env:"CRAWL_BANDWIDTH_PRICE_PER_GB" envDefault:"0.09"`
+	CrawlProxyPricePerGB     float64 `env:"CRAWL_PROXY_PRICE_PER_GB" envDefault:"0"`
+
+	// Caminhos sem anúncios aprendidos por domínio: classificados como não-anúncio em pelo menos
+	// SKIP_PATH_MIN_CRAWLS crawlings completos e SKIP_PATH_MIN_PAGES páginas (0 crawlings desativa)
+	SkipPathMinCrawls int `env:"SKIP_PATH_MIN_CRAWLS" envDefault:"3"`
+	SkipPathMinPages  int `env:"SKIP_PATH_MIN_PAGES" envDefault:"5"`
+
+	// Prazo até uma combinação de filtros da descoberta interativa que retornou "nenhum imóvel
+	// encontrado" voltar a ser gerada para o domínio
+	DeadFilterRetry time.Duration `env:"DEAD_FILTER_RETRY" envDefault:"720h"`
+
+	// Extração dos folhetos em PDF linkados pelos sites com o pdftotext (poppler); sem o binário
+	// instalado a etapa fica desativada e os links para PDF continuam ignorados
+	PDFTextExtraction bool   `env:"PDF_TEXT_EXTRACTION" envDefault:"false"`
+	PDFToTextPath     string `env:"PDFTOTEXT_PATH" envDefault:"pdftotext"`
+
+	// API HTTP: origens e métodos aceitos no CORS ("*" aceita qualquer origem; "https://*.dominio"
+	// aceita os subdomínios), cabeçalhos de segurança e limite do corpo das requisições (0 desativa)
+	CORSAllowedOrigins    []string      `env:"CORS_ALLOWED_ORIGINS" envSeparator:"," envDefault:"*"`
+	CORSAllowedMethods    []string      `env:"CORS_ALLOWED_METHODS" envSeparator:"," envDefault:"GET,POST,PUT,PATCH,DELETE,OPTIONS"`
+	CORSAllowCredentials  bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+	CORSMaxAge            time.Duration `env:"CORS_MAX_AGE" envDefault:"12h"`
+	SecurityHeaders       bool          `env:"SECURITY_HEADERS" envDefault:"true"`
+	ContentSecurityPolicy string        `env:"CONTENT_SECURITY_POLICY"`
+	HSTSMaxAge            time.Duration `env:"HSTS_MAX_AGE" envDefault:"0"`
+	MaxRequestBodyBytes   int64         `env:"MAX_REQUEST_BODY_BYTES" envDefault:"10485760"`
+
+	// Token das rotas administrativas (diagnóstico que baixa URLs informadas e correções manuais);
+	// vazio mantém essas rotas fechadas
+	AdminAPIToken string `env:"ADMIN_API_TOKEN"`
+
+	// Cópia do Wayback Machine para anúncios já salvos que passam a retornar 404 ou 410 durante o
+	// crawling: os campos que faltam são completados com a cópia mais recente (até WaybackMaxAge)
+	// e o anúncio fica marcado como delisted_with_archive
+	WaybackFallback bool          `env:"WAYBACK_FALLBACK" envDefault:"false"`
+	WaybackAPIURL   string        `env:"WAYBACK_API_URL" envDefault:"https://archive.org/wayback/available"`
+	WaybackMaxAge   time.Duration `env:"WAYBACK_MAX_AGE" envDefault:"2160h"`
+
+	// Leitura dos links de WhatsApp com mensagem pré-preenchida ("catálogo via WhatsApp"): os
+	// trechos de anúncio das mensagens são gravados como leads de baixa confiança
+	WhatsAppLeads bool `env:"WHATSAPP_LEADS" envDefault:"true"`
+
+	// Eleição de líder entre réplicas da API (mongo ou kubernetes; vazio desativa e toda réplica
+	// executa as tarefas agendadas). A identidade padrão é o hostname (nome do pod) e o pid
+	LeaderElection  string        `env:"LEADER_ELECTION"`
+	LeaderLeaseName string        `env:"LEADER_LEASE_NAME" envDefault:"go-crawler-scheduler"`
+	LeaderLeaseTTL  time.Duration `env:"LEADER_LEASE_TTL" envDefault:"30s"`
+	LeaderIdentity  string        `env:"LEADER_IDENTITY"`
+
+	// Geocodificação dos endereços (Nominatim/OpenStreetMap) usada pelo backfill
+	GeocoderURL       string        `env:"GEOCODER_URL" envDefault:"https://nominatim.openstreetmap.org/search"`
+	GeocoderUserAgent string        `env:"GEOCODER_USER_AGENT" envDefault:"go-crawler-project/1.0"`
+	GeocoderInterval  time.Duration `env:"GEOCODER_INTERVAL" envDefault:"1s"`
+
+	// Pontos de interesse (escolas e hospitais do OpenStreetMap via Overpass) num raio ao redor do
+	// centro da cidade, usados pelo backfill -poi nas distâncias dos imóveis
+	POIOverpassURL string  `env:"POI_OVERPASS_URL" envDefault:"https://overpass-api.de/api/interpreter"`
+	POIRadiusKm    float64 `env:"POI_RADIUS_KM" envDefault:"15"`
+
+	// Fotos por anúncio que recebem hash perceptual no backfill (detecção de fotos repetidas)
+	PhotoHashLimit int `env:"PHOTO_HASH_LIMIT" envDefault:"8"`
+
+	// Proxy de fotos GET /properties/{id}/photos/{n}: cache em disco (vazio desativa) e validade
+	PhotoCacheDir string        `env:"PHOTO_CACHE_DIR" envDefault:""`
+	PhotoCacheTTL time.Duration `env:"PHOTO_CACHE_TTL" envDefault:"168h"`
+
+	// Cotações (API no formato do Frankfurter) usadas para gravar o preço em outras moedas;
+	// vazio desativa a conversão
+	ExchangeRatesURL       string   `env:"EXCHANGE_RATES_URL"`
+	ExchangeRateCurrencies []string `env:"EXCHANGE_RATE_CURRENCIES" envSeparator:"," envDefault:"USD,EUR"`
+
+	// Rastreamento de erros externo (Sentry); vazio desativa o envio
+	SentryDSN         string `env:"SENTRY_DSN"`
+	SentryEnvironment string `env:"SENTRY_ENVIRONMENT" envDefault:"production"`
+	SentryRelease     string `env:"SENTRY_RELEASE"`
+
+	// Diretório onde o HTML das páginas que causaram panic nos callbacks é salvo (vazio desativa)
+	PanicSnapshotDir string `env:"PANIC_SNAPSHOT_DIR"`
+
+	// Pipeline de extração: ordem das etapas ("core" é a padrão) e plugins Go (.so) a carregar
+	ExtractionStages  []string `env:"EXTRACTION_STAGES" envSeparator:","`
+	ExtractionPlugins []string `env:"EXTRACTION_PLUGINS" envSeparator:","`
 }
 
-func LoadConfig() *Config {
+// LoadConfig lê a configuração das variáveis de ambiente
+func LoadConfig() (*Config, error) {
 	cfg := &Config{}
 	if err := env.Parse(cfg); err != nil {
-		log.Fatalf("Failed to load environment variables: %v", err)
+		return nil, fmt.Errorf("failed to load environment variables: %v", err)
 	}
-	return cfg
+	return cfg, nil
 }
\ No newline at end of file
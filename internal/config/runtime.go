@@ -0,0 +1,397 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// RuntimeSettings configurações que podem ser alteradas sem reiniciar o processo
+type RuntimeSettings struct {
+	DefaultDelayMs     int      `json:"default_delay_ms"`     // intervalo mínimo entre requisições ao mesmo domínio
+	AIThresholdMinutes int      `json:"ai_threshold_minutes"` // tempo mínimo antes de reprocessar uma página com IA
+	Blacklist          []string `json:"blacklist"`            // trechos de URL que nunca devem ser visitados
+}
+
+// RuntimeSnapshot estado completo da configuração aplicada aos engines. O snapshot não é
+// alterado depois de aplicado, então as regras de URL de cada domínio são compiladas uma vez
+type RuntimeSnapshot struct {
+	Version  int                                `json:"version"`
+	LoadedAt time.Time                          `json:"loaded_at"`
+	Settings RuntimeSettings                    `json:"settings"`
+	Domains  map[string]repository.DomainConfig `json:"domains"`
+
+	rulesOnce sync.Once
+	urlRules  map[string]*repository.DomainURLRules
+}
+
+// ConfigChange registro de auditoria de uma alteração aplicada
+type ConfigChange struct {
+	Version  int       `json:"version"`
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"` // file ou domain:<domínio>
+	Field    string    `json:"field"`
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+}
+
+// RuntimeConfigurable é implementado pelos engines que aceitam configuração em tempo de execução
+type RuntimeConfigurable interface {
+	ApplyRuntimeConfig(snapshot *RuntimeSnapshot)
+}
+
+// DomainConfigSource fornece as configurações por domínio persistidas
+type DomainConfigSource interface {
+	FindAllDomainConfigs(ctx context.Context) ([]repository.DomainConfig, error)
+}
+
+// DomainConfig retorna a configuração de um domínio, se existir
+func (s *RuntimeSnapshot) DomainConfig(domain string) (repository.DomainConfig, bool) {
+	if s == nil {
+		return repository.DomainConfig{}, false
+	}
+	config, exists := s.Domains[repository.NormalizeDomain(domain)]
+	return config, exists
+}
+
+// URLRules retorna as regras de URL compiladas do domínio, se ele tiver configuração
+func (s *RuntimeSnapshot) URLRules(domain string) (*repository.DomainURLRules, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.rulesOnce.Do(func() {
+		s.urlRules = make(map[string]*repository.DomainURLRules, len(s.Domains))
+		for name, config := range s.Domains {
+			s.urlRules[name] = config.URLRules()
+		}
+	})
+	rules, exists := s.urlRules[repository.NormalizeDomain(domain)]
+	return rules, exists
+}
+
+// IsBlacklisted verifica se a URL contém algum trecho da blacklist
+func (s *RuntimeSnapshot) IsBlacklisted(rawURL string) bool {
+	if s == nil {
+		return false
+	}
+	lower := strings.ToLower(rawURL)
+	for _, pattern := range s.Settings.Blacklist {
+		if pattern != "" && strings.Contains(lower, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadRuntimeSettings lê as configurações de runtime de um arquivo JSON
+func LoadRuntimeSettings(path string) (*RuntimeSettings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de configuração: %v", err)
+	}
+
+	var settings RuntimeSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar configuração: %v", err)
+	}
+
+	if settings.DefaultDelayMs < 0 || settings.AIThresholdMinutes < 0 {
+		return nil, fmt.Errorf("valores negativos não são permitidos na configuração")
+	}
+
+	return &settings, nil
+}
+
+// ConfigWatcher observa o arquivo de runtime e as configurações por domínio no Mongo,
+// aplicando as alterações nos engines registrados e mantendo um log de auditoria
+type ConfigWatcher struct {
+	filePath     string
+	domainSource DomainConfigSource
+	interval     time.Duration
+	maxAudit     int
+
+	mutex    sync.RWMutex
+	current  *RuntimeSnapshot
+	fileMod  time.Time
+	targets  map[RuntimeConfigurable]bool
+	auditLog []ConfigChange
+	logger   *logger.Logger
+}
+
+// NewConfigWatcher cria um novo observador de configuração
+func NewConfigWatcher(filePath string, domainSource DomainConfigSource, interval time.Duration) *ConfigWatcher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &ConfigWatcher{
+		filePath:     filePath,
+		domainSource: domainSource,
+		interval:     interval,
+		maxAudit:     500,
+		current:      &RuntimeSnapshot{Domains: make(map[string]repository.DomainConfig)},
+		targets:      make(map[RuntimeConfigurable]bool),
+		logger:       logger.NewLogger("config_watcher"),
+	}
+}
+
+// Start carrega a configuração inicial e passa a verificar alterações periodicamente
+func (w *ConfigWatcher) Start(ctx context.Context) {
+	if err := w.Reload(ctx); err != nil {
+		w.logger.WithError(err).Warn("Initial runtime config load failed")
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.Reload(ctx); err != nil {
+					w.logger.WithError(err).Warn("Runtime config reload failed")
+				}
+			}
+		}
+	}()
+
+	w.logger.WithFields(map[string]interface{}{
+		"file":     w.filePath,
+		"interval": w.interval.String(),
+	}).Info("Config watcher started")
+}
+
+// Reload lê as fontes de configuração e aplica nos engines se algo mudou.
+// Em caso de erro em uma fonte, mantém o último valor válido dela.
+func (w *ConfigWatcher) Reload(ctx context.Context) error {
+	w.mutex.RLock()
+	previous := w.current
+	fileMod := w.fileMod
+	w.mutex.RUnlock()
+
+	next := &RuntimeSnapshot{
+		Version:  previous.Version,
+		Settings: previous.Settings,
+		Domains:  previous.Domains,
+	}
+
+	var errs []string
+
+	if w.filePath != "" {
+		if info, err := os.Stat(w.filePath); err == nil {
+			if !info.ModTime().Equal(fileMod) {
+				if settings, err := LoadRuntimeSettings(w.filePath); err == nil {
+					next.Settings = *settings
+					fileMod = info.ModTime()
+				} else {
+					errs = append(errs, err.Error())
+				}
+			}
+		} else if !os.IsNotExist(err) {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if w.domainSource != nil {
+		if configs, err := w.domainSource.FindAllDomainConfigs(ctx); err == nil {
+			next.Domains = make(map[string]repository.DomainConfig, len(configs))
+			for _, config := range configs {
+				next.Domains[repository.NormalizeDomain(config.Domain)] = config
+			}
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	changes := diffSnapshots(previous, next)
+
+	w.mutex.Lock()
+	w.fileMod = fileMod
+	if len(changes) > 0 {
+		next.Version = previous.Version + 1
+		next.LoadedAt = time.Now()
+		for i := range changes {
+			changes[i].Version = next.Version
+			changes[i].Time = next.LoadedAt
+		}
+		w.current = next
+		w.auditLog = append(w.auditLog, changes...)
+		if len(w.auditLog) > w.maxAudit {
+			w.auditLog = w.auditLog[len(w.auditLog)-w.maxAudit:]
+		}
+	}
+	targets := make([]RuntimeConfigurable, 0, len(w.targets))
+	for target := range w.targets {
+		targets = append(targets, target)
+	}
+	w.mutex.Unlock()
+
+	if len(changes) > 0 {
+		for _, change := range changes {
+			w.logger.WithFields(map[string]interface{}{
+				"version":   change.Version,
+				"source":    change.Source,
+				"field":     change.Field,
+				"old_value": change.OldValue,
+				"new_value": change.NewValue,
+			}).Info("Runtime config change applied")
+		}
+
+		for _, target := range targets {
+			target.ApplyRuntimeConfig(next)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("erro ao recarregar configuração: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Register adiciona um engine e aplica imediatamente a configuração atual
+func (w *ConfigWatcher) Register(target RuntimeConfigurable) {
+	w.mutex.Lock()
+	w.targets[target] = true
+	current := w.current
+	w.mutex.Unlock()
+
+	target.ApplyRuntimeConfig(current)
+}
+
+// Unregister remove um engine (ex: ao fim de um crawling)
+func (w *ConfigWatcher) Unregister(target RuntimeConfigurable) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.targets, target)
+}
+
+// Current retorna a configuração aplicada no momento
+func (w *ConfigWatcher) Current() *RuntimeSnapshot {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.current
+}
+
+// AuditLog retorna as alterações aplicadas, da mais antiga para a mais recente
+func (w *ConfigWatcher) AuditLog() []ConfigChange {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return append([]ConfigChange(nil), w.auditLog...)
+}
+
+// diffSnapshots compara duas configurações e retorna as alterações encontradas
+func diffSnapshots(previous, next *RuntimeSnapshot) []ConfigChange {
+	var changes []ConfigChange
+
+	addChange := func(source, field string, oldValue, newValue interface{}) {
+		changes = append(changes, ConfigChange{
+			Source:   source,
+			Field:    field,
+			OldValue: fmt.Sprintf("%v", oldValue),
+			NewValue: fmt.Sprintf("%v", newValue),
+		})
+	}
+
+	if previous.Settings.DefaultDelayMs != next.Settings.DefaultDelayMs {
+		addChange("file", "default_delay_ms", previous.Settings.DefaultDelayMs, next.Settings.DefaultDelayMs)
+	}
+	if previous.Settings.AIThresholdMinutes != next.Settings.AIThresholdMinutes {
+		addChange("file", "ai_threshold_minutes", previous.Settings.AIThresholdMinutes, next.Settings.AIThresholdMinutes)
+	}
+	if !reflect.DeepEqual(previous.Settings.Blacklist, next.Settings.Blacklist) {
+		addChange("file", "blacklist", previous.Settings.Blacklist, next.Settings.Blacklist)
+	}
+
+	domains := make(map[string]bool)
+	for domain := range previous.Domains {
+		domains[domain] = true
+	}
+	for domain := range next.Domains {
+		domains[domain] = true
+	}
+
+	var sorted []string
+	for domain := range domains {
+		sorted = append(sorted, domain)
+	}
+	sort.Strings(sorted)
+
+	for _, domain := range sorted {
+		oldConfig, hadOld := previous.Domains[domain]
+		newConfig, hasNew := next.Domains[domain]
+		source := "domain:" + domain
+		fieldsChanged := len(changes)
+
+		switch {
+		case !hadOld:
+			addChange(source, "config", "", "created")
+		case !hasNew:
+			addChange(source, "config", "exists", "deleted")
+		default:
+			if oldConfig.RateLimit != newConfig.RateLimit {
+				addChange(source, "rate_limit", oldConfig.RateLimit, newConfig.RateLimit)
+			}
+			if oldConfig.EnableJS != newConfig.EnableJS {
+				addChange(source, "enable_js", oldConfig.EnableJS, newConfig.EnableJS)
+			}
+			if !reflect.DeepEqual(oldConfig.IncludePatterns, newConfig.IncludePatterns) {
+				addChange(source, "include_patterns", oldConfig.IncludePatterns, newConfig.IncludePatterns)
+			}
+			if !reflect.DeepEqual(oldConfig.ExcludePatterns, newConfig.ExcludePatterns) {
+				addChange(source, "exclude_patterns", oldConfig.ExcludePatterns, newConfig.ExcludePatterns)
+			}
+			if !reflect.DeepEqual(oldConfig.Selectors, newConfig.Selectors) {
+				addChange(source, "selectors", oldConfig.Selectors, newConfig.Selectors)
+			}
+			if oldConfig.PostProcessScript != newConfig.PostProcessScript {
+				addChange(source, "post_process_script", len(oldConfig.PostProcessScript), len(newConfig.PostProcessScript))
+			}
+			if !reflect.DeepEqual(oldConfig.API, newConfig.API) {
+				addChange(source, "api", oldConfig.API != nil, newConfig.API != nil)
+			}
+			if !reflect.DeepEqual(oldConfig.Feeds, newConfig.Feeds) {
+				addChange(source, "feeds", oldConfig.Feeds, newConfig.Feeds)
+			}
+			if oldConfig.AllowSubdomains != newConfig.AllowSubdomains {
+				addChange(source, "allow_subdomains", oldConfig.AllowSubdomains, newConfig.AllowSubdomains)
+			}
+			if !reflect.DeepEqual(oldConfig.CrawlWindows, newConfig.CrawlWindows) || oldConfig.Timezone != newConfig.Timezone {
+				addChange(source, "crawl_windows", oldConfig.CrawlWindows, newConfig.CrawlWindows)
+			}
+			if oldConfig.PhotoPolicy != newConfig.PhotoPolicy || oldConfig.PhotoReferer != newConfig.PhotoReferer {
+				addChange(source, "photo_policy", oldConfig.PhotoPolicy, newConfig.PhotoPolicy)
+			}
+			if !reflect.DeepEqual(oldConfig.LearnedSkipPaths, newConfig.LearnedSkipPaths) {
+				addChange(source, "learned_skip_paths", oldConfig.LearnedSkipPaths, newConfig.LearnedSkipPaths)
+			}
+			if !reflect.DeepEqual(oldConfig.DisabledDiscoverySources, newConfig.DisabledDiscoverySources) {
+				addChange(source, "disabled_discovery_sources", oldConfig.DisabledDiscoverySources, newConfig.DisabledDiscoverySources)
+			}
+
+			// Campos sem comparação própria acima: a configuração nova é aplicada mesmo assim
+			if len(changes) == fieldsChanged && !sameDomainConfig(oldConfig, newConfig) {
+				addChange(source, "config", "", "updated")
+			}
+		}
+	}
+
+	return changes
+}
+
+// sameDomainConfig compara as configurações ignorando as datas de criação e atualização
+func sameDomainConfig(a, b repository.DomainConfig) bool {
+	a.CreatedAt, a.UpdatedAt = time.Time{}, time.Time{}
+	b.CreatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	return reflect.DeepEqual(a, b)
+}
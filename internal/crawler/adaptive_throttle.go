@@ -0,0 +1,254 @@
+package crawler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// Parâmetros do ajuste adaptativo (AIMD): o atraso dobra a cada 429/503 e diminui
+// aos poucos depois de uma sequência de respostas bem-sucedidas
+const (
+	minAdaptiveDelay      = 1 * time.Second
+	maxAdaptiveDelay      = 2 * time.Minute
+	maxRetryAfter         = 5 * time.Minute
+	adaptiveDecreaseStep  = 250 * time.Millisecond
+	successesPerDecrease  = 10
+	maxThrottleRetries    = 3
+	throttleRetriesCtxKey = "throttle_retries"
+)
+
+// adaptiveDelay guarda o estado AIMD de um domínio
+type adaptiveDelay struct {
+	penalty       time.Duration
+	successStreak int
+	throttled     int
+	successes     int
+	lastThrottled time.Time
+	lastStatus    int
+	retryUntil    time.Time
+}
+
+// DomainRateStats expõe o ritmo efetivo aplicado a um domínio
+type DomainRateStats struct {
+	Domain            string     `json:"domain"`
+	BaseDelayMs       int64      `json:"base_delay_ms"`
+	AdaptiveDelayMs   int64      `json:"adaptive_delay_ms"`
+	EffectiveDelayMs  int64      `json:"effective_delay_ms"`
+	RequestsPerMinute float64    `json:"requests_per_minute"` // 0 = sem limite
+	ThrottledCount    int        `json:"throttled_count"`
+	SuccessCount      int        `json:"success_count"`
+	LastStatus        int        `json:"last_status,omitempty"`
+	LastThrottledAt   *time.Time `json:"last_throttled_at,omitempty"`
+	RetryAfterUntil   *time.Time `json:"retry_after_until,omitempty"`
+}
+
+// RecordThrottled registra uma resposta 429/503: dobra o atraso do domínio e, se o servidor
+// informou Retry-After, bloqueia novas requisições até lá
+func (dt *DomainThrottle) RecordThrottled(host string, status int, retryAfter time.Duration) {
+	domain := repository.NormalizeDomain(host)
+	now := time.Now()
+
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	state := dt.adaptiveState(domain)
+	state.penalty *= 2
+	if state.penalty < minAdaptiveDelay {
+		state.penalty = minAdaptiveDelay
+	}
+	if state.penalty > maxAdaptiveDelay {
+		state.penalty = maxAdaptiveDelay
+	}
+	state.successStreak = 0
+	state.throttled++
+	state.lastThrottled = now
+	state.lastStatus = status
+
+	if retryAfter > maxRetryAfter {
+		retryAfter = maxRetryAfter
+	}
+	if retryAfter > 0 {
+		state.retryUntil = now.Add(retryAfter)
+		if dt.nextSlot[domain].Before(state.retryUntil) {
+			dt.nextSlot[domain] = state.retryUntil
+		}
+	}
+}
+
+// RecordSuccess registra uma resposta bem-sucedida; a cada sequência de sucessos o atraso
+// adicional diminui um passo fixo
+func (dt *DomainThrottle) RecordSuccess(host string, status int) {
+	domain := repository.NormalizeDomain(host)
+
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	state := dt.adaptiveState(domain)
+	state.successes++
+	state.lastStatus = status
+	if state.penalty == 0 {
+		return
+	}
+
+	state.successStreak++
+	if state.successStreak >= successesPerDecrease {
+		state.successStreak = 0
+		state.penalty -= adaptiveDecreaseStep
+		if state.penalty < 0 {
+			state.penalty = 0
+		}
+	}
+}
+
+// Stats retorna o ritmo efetivo de cada domínio conhecido, ordenado pelo domínio
+func (dt *DomainThrottle) Stats() []DomainRateStats {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	domains := make(map[string]bool, len(dt.intervals)+len(dt.adaptive))
+	for domain := range dt.intervals {
+		domains[domain] = true
+	}
+	for domain := range dt.adaptive {
+		domains[domain] = true
+	}
+
+	now := time.Now()
+	stats := make([]DomainRateStats, 0, len(domains))
+	for domain := range domains {
+		base := dt.baseInterval(domain)
+		entry := DomainRateStats{
+			Domain:           domain,
+			BaseDelayMs:      base.Milliseconds(),
+			EffectiveDelayMs: base.Milliseconds(),
+		}
+
+		if state, exists := dt.adaptive[domain]; exists {
+			entry.AdaptiveDelayMs = state.penalty.Milliseconds()
+			entry.EffectiveDelayMs = (base + state.penalty).Milliseconds()
+			entry.ThrottledCount = state.throttled
+			entry.SuccessCount = state.successes
+			entry.LastStatus = state.lastStatus
+			if !state.lastThrottled.IsZero() {
+				lastThrottled := state.lastThrottled
+				entry.LastThrottledAt = &lastThrottled
+			}
+			if state.retryUntil.After(now) {
+				retryUntil := state.retryUntil
+				entry.RetryAfterUntil = &retryUntil
+			}
+		}
+
+		if entry.EffectiveDelayMs > 0 {
+			entry.RequestsPerMinute = float64(time.Minute.Milliseconds()) / float64(entry.EffectiveDelayMs)
+		}
+		stats = append(stats, entry)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Domain < stats[j].Domain
+	})
+	return stats
+}
+
+// adaptiveState retorna (criando se necessário) o estado AIMD do domínio; requer o mutex
+func (dt *DomainThrottle) adaptiveState(domain string) *adaptiveDelay {
+	state, exists := dt.adaptive[domain]
+	if !exists {
+		state = &adaptiveDelay{}
+		dt.adaptive[domain] = state
+	}
+	return state
+}
+
+// baseInterval retorna o intervalo configurado para o domínio; requer o mutex
+func (dt *DomainThrottle) baseInterval(domain string) time.Duration {
+	if interval, exists := dt.intervals[domain]; exists {
+		return interval
+	}
+	return dt.defaultInterval
+}
+
+// isThrottleStatus indica respostas que pedem para o cliente desacelerar
+func isThrottleStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After em segundos ou como data HTTP
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// RecordResponse ajusta o ritmo do domínio conforme o status da resposta. Em 429/503 a
+// requisição é reenviada (até maxThrottleRetries vezes) depois do atraso indicado.
+func (rs *RuntimeState) RecordResponse(r *colly.Response) bool {
+	if r == nil || r.Request == nil {
+		return false
+	}
+	rs.releaseSlot(r.Request)
+	host := r.Request.URL.Host
+
+	if !isThrottleStatus(r.StatusCode) {
+		if r.StatusCode >= 200 && r.StatusCode < 400 {
+			rs.domainThrottle().RecordSuccess(host, r.StatusCode)
+		}
+		return false
+	}
+
+	var retryAfter time.Duration
+	if r.Headers != nil {
+		retryAfter = parseRetryAfter(r.Headers.Get("Retry-After"), time.Now())
+	}
+	rs.domainThrottle().RecordThrottled(host, r.StatusCode, retryAfter)
+
+	retries, _ := r.Request.Ctx.GetAny(throttleRetriesCtxKey).(int)
+	if retries >= maxThrottleRetries {
+		return false
+	}
+	r.Request.Ctx.Put(throttleRetriesCtxKey, retries+1)
+	return r.Request.Retry() == nil
+}
+
+// DomainRates retorna o ritmo efetivo aplicado a cada domínio
+func (rs *RuntimeState) DomainRates() []DomainRateStats {
+	return rs.domainThrottle().Stats()
+}
+
+// SetThrottle compartilha um limitador entre crawlings (o atraso aprendido persiste entre jobs)
+func (rs *RuntimeState) SetThrottle(throttle *DomainThrottle) {
+	if throttle == nil {
+		return
+	}
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.throttle = throttle
+}
+
+func (rs *RuntimeState) domainThrottle() *DomainThrottle {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	return rs.throttle
+}
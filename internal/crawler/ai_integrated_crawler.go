@@ -3,7 +3,6 @@ package crawler
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/url"
 	"strings"
 	"sync"
@@ -21,14 +20,20 @@ import (
 type AIIntegratedCrawler struct {
 	config            *config.Config
 	repo              repository.PropertyRepository
-	aiService         *ai.GeminiService
-	enhancedAI        *ai.EnhancedGeminiService
+	aiService         PropertyAIProvider
+	enhancedAI        PageAIProvider
 	aiTrainer         *AIEnhancedTrainer
+	clock             Clock
+	calibrator        *ConfidenceCalibrator
+	validationSampler *AIValidationSampler
 	patternValidator  *PatternValidator
 	enhancedExtractor *EnhancedExtractor
+	linkContext       *LinkContextExtractor
 	logger            *logger.Logger
 	collector         *colly.Collector
 	detailCollector   *colly.Collector
+	mainTuner         *ConcurrencyTuner
+	detailTuner       *ConcurrencyTuner
 	visitedURLs       map[string]bool
 	visitedMutex      sync.Mutex
 	stats             *AIIntegratedStats
@@ -36,53 +41,37 @@ type AIIntegratedCrawler struct {
 
 // AIIntegratedStats estatísticas específicas para crawler com IA
 type AIIntegratedStats struct {
-	PagesVisited          int                    `json:"pages_visited"`
-	PropertiesFound       int                    `json:"properties_found"`
-	PropertiesSaved       int                    `json:"properties_saved"`
-	AIClassifications     int                    `json:"ai_classifications"`
-	AIValidations         int                    `json:"ai_validations"`
-	AIEnhancements        int                    `json:"ai_enhancements"`
-	PatternMatches        int                    `json:"pattern_matches"`
-	HighConfidenceMatches int                    `json:"high_confidence_matches"`
-	StartTime             time.Time              `json:"start_time"`
-	LastUpdate            time.Time              `json:"last_update"`
-	DomainStats           map[string]int         `json:"domain_stats"`
-	AIPerformanceStats    map[string]interface{} `json:"ai_performance_stats"`
+	PagesVisited          int                      `json:"pages_visited"`
+	PropertiesFound       int                      `json:"properties_found"`
+	PropertiesSaved       int                      `json:"properties_saved"`
+	AIClassifications     int                      `json:"ai_classifications"`
+	AIValidations         int                      `json:"ai_validations"`
+	AIEnhancements        int                      `json:"ai_enhancements"`
+	PatternMatches        int                      `json:"pattern_matches"`
+	HighConfidenceMatches int                      `json:"high_confidence_matches"`
+	StartTime             time.Time                `json:"start_time"`
+	LastUpdate            time.Time                `json:"last_update"`
+	DomainStats           map[string]int           `json:"domain_stats"`
+	AIPerformanceStats    map[string]interface{}   `json:"ai_performance_stats"`
+	Concurrency           []DomainConcurrencyStats `json:"concurrency,omitempty"`
+	AIValidationSampling  []AIValidationStats      `json:"ai_validation_sampling,omitempty"`
 	mutex                 sync.RWMutex
 }
 
-// NewAIIntegratedCrawler cria um novo crawler integrado com IA
-func NewAIIntegratedCrawler(ctx context.Context, cfg *config.Config) (*AIIntegratedCrawler, error) {
-	// Inicializa repositório
-	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
-	if err != nil {
-		log.Fatalf("Failed to create MongoDB repository: %v", err)
-	}
-
-	// Inicializa serviços de IA
-	aiService, err := ai.NewGeminiService(ctx)
-	if err != nil {
-		log.Printf("Warning: Failed to create basic AI service: %v", err)
-	}
-
-	enhancedAI, err := ai.NewEnhancedGeminiService(ctx)
-	if err != nil {
-		log.Printf("Warning: Failed to create enhanced AI service: %v", err)
-	}
-
-	// Inicializa treinador com IA
-	aiTrainer, err := NewAIEnhancedTrainer(ctx)
-	if err != nil {
-		log.Printf("Warning: Failed to create AI trainer: %v", err)
+// NewAIIntegratedCrawler cria um novo crawler integrado com IA com as dependências informadas
+// (ver NewCrawlerDependencies para a configuração padrão com MongoDB e Gemini)
+func NewAIIntegratedCrawler(cfg *config.Config, deps CrawlerDependencies) (*AIIntegratedCrawler, error) {
+	if err := deps.validate(); err != nil {
+		return nil, err
 	}
 
 	// Inicializa outros componentes
 	var patternValidator *PatternValidator
 	var enhancedExtractor *EnhancedExtractor
 
-	if aiTrainer != nil {
-		patternValidator = NewPatternValidator(aiTrainer.referenceTrainer)
-		enhancedExtractor = NewEnhancedExtractor(aiTrainer.referenceTrainer, patternValidator)
+	if deps.Trainer != nil {
+		patternValidator = NewPatternValidator(deps.Trainer.referenceTrainer)
+		enhancedExtractor = NewEnhancedExtractor(deps.Trainer.referenceTrainer, patternValidator)
 	}
 
 	// Configura coletores
@@ -98,33 +87,39 @@ func NewAIIntegratedCrawler(ctx context.Context, cfg *config.Config) (*AIIntegra
 	extensions.Referer(mainCollector)
 	extensions.RandomUserAgent(detailCollector)
 	extensions.Referer(detailCollector)
+	deps.applyTransport(mainCollector, detailCollector)
 
-	mainCollector.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		Parallelism: 2,
-		Delay:       1 * time.Second,
-	})
+	// Paralelismo por domínio (configurável e com ajuste automático) e pausa entre requisições
+	mainTuner, detailTuner, err := attachConcurrencyTuners(cfg, mainCollector, detailCollector, 1*time.Second, 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
 
-	detailCollector.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		Parallelism: 1,
-		Delay:       2 * time.Second,
-	})
+	validationSampler, err := NewAIValidationSampler(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &AIIntegratedCrawler{
 		config:            cfg,
-		repo:              repo,
-		aiService:         aiService,
-		enhancedAI:        enhancedAI,
-		aiTrainer:         aiTrainer,
+		repo:              deps.Repository,
+		aiService:         deps.AI,
+		enhancedAI:        deps.PageAI,
+		aiTrainer:         deps.Trainer,
+		clock:             deps.Clock,
+		calibrator:        deps.Calibrator,
+		validationSampler: validationSampler,
 		patternValidator:  patternValidator,
 		enhancedExtractor: enhancedExtractor,
+		linkContext:       NewLinkContextExtractor(),
 		logger:            logger.NewLogger("ai_integrated_crawler"),
 		collector:         mainCollector,
+		mainTuner:         mainTuner,
+		detailTuner:       detailTuner,
 		detailCollector:   detailCollector,
 		visitedURLs:       make(map[string]bool),
 		stats: &AIIntegratedStats{
-			StartTime:          time.Now(),
+			StartTime:          deps.Clock.Now(),
 			DomainStats:        make(map[string]int),
 			AIPerformanceStats: make(map[string]interface{}),
 		},
@@ -158,13 +153,14 @@ func (aic *AIIntegratedCrawler) TrainWithAI(ctx context.Context, referenceFile s
 
 // StartCrawling inicia o processo de crawling com IA integrada
 func (aic *AIIntegratedCrawler) StartCrawling(ctx context.Context) error {
+	aic.logger.Warn(SitesFileDeprecationWarning)
 	urls, err := LoadURLsFromFile(aic.config.SitesFile)
 	if err != nil {
 		return fmt.Errorf("failed to load URLs: %w", err)
 	}
 
 	aic.logger.WithField("url_count", len(urls)).Info("Starting AI-integrated crawling")
-	aic.stats.StartTime = time.Now()
+	aic.stats.StartTime = aic.clock.Now()
 
 	// Configura handlers do crawler
 	aic.setupCrawlerHandlers(ctx)
@@ -197,34 +193,38 @@ func (aic *AIIntegratedCrawler) StartCrawling(ctx context.Context) error {
 
 // setupCrawlerHandlers configura os handlers do crawler com IA
 func (aic *AIIntegratedCrawler) setupCrawlerHandlers(ctx context.Context) {
+	// Callbacks protegidos: um panic em uma página conta como erro e o crawling continua
+	recovery := NewCallbackRecovery("ai_integrated_crawler", nil)
+	recovery.SetPanicHook(func(rawURL string) { aic.updateStats("error", rawURL) })
+
 	// Handler para encontrar links de propriedades
-	aic.collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	aic.collector.OnHTML("a[href]", recovery.HTML(func(e *colly.HTMLElement) {
 		aic.handlePropertyLinkWithAI(ctx, e)
-	})
+	}))
 
 	// Handler principal para páginas de detalhes
-	aic.detailCollector.OnHTML("body", func(e *colly.HTMLElement) {
+	aic.detailCollector.OnHTML("body", recovery.HTML(func(e *colly.HTMLElement) {
 		aic.handlePropertyPageWithAI(ctx, e)
-	})
+	}))
 
 	// Handlers de log
-	aic.collector.OnRequest(func(r *colly.Request) {
+	aic.collector.OnRequest(recovery.Request(func(r *colly.Request) {
 		aic.logger.WithField("url", r.URL.String()).Debug("Visiting listing page")
-	})
+	}))
 
-	aic.detailCollector.OnRequest(func(r *colly.Request) {
+	aic.detailCollector.OnRequest(recovery.Request(func(r *colly.Request) {
 		aic.logger.WithField("url", r.URL.String()).Debug("Visiting property page")
-	})
+	}))
 
-	aic.collector.OnError(func(r *colly.Response, err error) {
+	aic.collector.OnError(recovery.Error(func(r *colly.Response, err error) {
 		aic.logger.WithField("url", r.Request.URL.String()).Error("Error visiting listing page", err)
 		aic.updateStats("error", r.Request.URL.String())
-	})
+	}))
 
-	aic.detailCollector.OnError(func(r *colly.Response, err error) {
+	aic.detailCollector.OnError(recovery.Error(func(r *colly.Response, err error) {
 		aic.logger.WithField("url", r.Request.URL.String()).Error("Error visiting property page", err)
 		aic.updateStats("error", r.Request.URL.String())
-	})
+	}))
 }
 
 // handlePropertyLinkWithAI processa links usando IA para classificação
@@ -243,6 +243,9 @@ func (aic *AIIntegratedCrawler) handlePropertyLinkWithAI(ctx context.Context, e
 
 	if aic.aiTrainer != nil {
 		matchedPattern, patternConfidence := aic.aiTrainer.referenceTrainer.MatchURL(absoluteLink)
+		if matchedPattern != nil {
+			patternConfidence = aic.calibrator.Score(CalibrationSourcePattern, absoluteLink, patternConfidence, true)
+		}
 		if matchedPattern != nil && patternConfidence > 0.5 {
 			isPropertyLink = true
 			confidence = patternConfidence
@@ -265,6 +268,7 @@ func (aic *AIIntegratedCrawler) handlePropertyLinkWithAI(ctx context.Context, e
 			classification, err := aic.enhancedAI.ClassifyPageContent(ctx, absoluteLink, linkText, linkContext)
 			if err == nil {
 				aic.updateStats("ai_classification", absoluteLink)
+				classification = aic.calibrateClassification(absoluteLink, classification)
 
 				if classification.IsPropertyPage && classification.Confidence > 0.7 {
 					isPropertyLink = true
@@ -305,11 +309,12 @@ func (aic *AIIntegratedCrawler) handlePropertyPageWithAI(ctx context.Context, e
 	var aiClassification *ai.PageClassificationResult
 	if aic.enhancedAI != nil {
 		title := e.ChildText("title")
-		content := e.Text
+		// O HTML é sanitizado pelo serviço de IA (e.Text incluiria scripts, menus e rodapés)
+		content, _ := e.DOM.Html()
 
 		classification, err := aic.enhancedAI.ClassifyPageContent(ctx, url, title, content)
 		if err == nil {
-			aiClassification = classification
+			aiClassification = aic.calibrateClassification(url, classification)
 			aic.updateStats("ai_classification", url)
 
 			aic.logger.WithFields(map[string]interface{}{
@@ -321,23 +326,10 @@ func (aic *AIIntegratedCrawler) handlePropertyPageWithAI(ctx context.Context, e
 		}
 	}
 
-	// 2. Classificação com padrões tradicionais
-	shouldProcess := false
-
-	if aiClassification != nil {
-		// Se IA tem alta confiança, usa sua decisão
-		if aiClassification.Confidence > 0.8 {
-			shouldProcess = aiClassification.IsPropertyPage
-		} else if aiClassification.IsPropertyPage && aiClassification.Confidence > 0.6 {
-			shouldProcess = true
-		}
-	}
-
-	// 3. Se IA não tem certeza, usa classificadores tradicionais
-	if !shouldProcess && aiClassification != nil && aiClassification.Confidence < 0.7 {
-		// Usa classificadores tradicionais como backup
-		shouldProcess = aic.shouldProcessWithTraditionalMethods(e)
-	}
+	// 2. Decisão da IA, com os indicadores tradicionais como reserva quando ela não tem certeza
+	shouldProcess := aiPageDecision(aiClassification, func() bool {
+		return aic.shouldProcessWithTraditionalMethods(e)
+	})
 
 	if shouldProcess {
 		aic.processPropertyPageWithAI(ctx, e, url, aiClassification)
@@ -361,14 +353,29 @@ func (aic *AIIntegratedCrawler) processPropertyPageWithAI(ctx context.Context, e
 
 	aic.updateStats("property_found", url)
 
-	// 2. Valida e melhora dados com IA (se disponível)
+	// 2. Valida e melhora dados com IA (se disponível): anúncios de baixa confiança e uma amostra
+	// dos demais (AI_VALIDATION_SAMPLE_RATE), medindo quantos a IA corrige
 	if aic.enhancedAI != nil {
-		htmlContent, _ := e.DOM.Html()
-		validatedProperty, err := aic.enhancedAI.ValidateExtractedData(ctx, property, htmlContent)
-		if err == nil {
-			property = validatedProperty
-			aic.updateStats("ai_validation", url)
-			aic.logger.WithField("url", url).Debug("Property data validated with AI")
+		if decision := aic.validationSampler.Decide(property); decision != AIValidationSkipped {
+			htmlContent, _ := e.DOM.Html()
+			validatedProperty, err := aic.enhancedAI.ValidateExtractedData(ctx, property, htmlContent)
+			if err == nil {
+				corrected := repository.RecordAIReview(property, &validatedProperty)
+				aic.validationSampler.Record(property, decision, corrected, nil)
+				if aic.enhancedExtractor != nil && len(corrected) > 0 {
+					// Rebaixa os seletores do domínio que produziram os valores corrigidos
+					aic.enhancedExtractor.RecordAICorrections(property, corrected)
+				}
+				property = validatedProperty
+				aic.updateStats("ai_validation", url)
+				aic.logger.WithFields(map[string]interface{}{
+					"url":       url,
+					"reason":    decision,
+					"corrected": corrected,
+				}).Debug("Property data validated with AI")
+			} else {
+				aic.validationSampler.Record(property, decision, nil, err)
+			}
 		}
 	}
 
@@ -405,18 +412,35 @@ func (aic *AIIntegratedCrawler) processPropertyPageWithAI(ctx context.Context, e
 
 // Funções auxiliares
 
-// extractLinkContext extrai contexto ao redor de um link
+// extractLinkContext extrai o contexto de um link: o card da listagem em que ele está, o texto
+// alternativo das imagens e os preços próximos (ver LinkContextExtractor)
 func (aic *AIIntegratedCrawler) extractLinkContext(e *colly.HTMLElement) string {
-	// Extrai texto do elemento pai e irmãos para contexto
-	parent := e.DOM.Parent()
-	context := strings.TrimSpace(parent.Text())
+	return aic.linkContext.Extract(e.DOM)
+}
 
-	// Limita tamanho do contexto
-	if len(context) > 500 {
-		context = context[:500] + "..."
+// aiPageDecision decide se a página é processada como propriedade: com alta confiança vale o
+// veredito da IA; com confiança abaixo de 0.7 os indicadores tradicionais servem de reserva.
+// Sem classificação da IA a página não é processada.
+func aiPageDecision(classification *ai.PageClassificationResult, traditional func() bool) bool {
+	if classification == nil {
+		return false
+	}
+	if classification.Confidence > 0.8 {
+		if classification.IsPropertyPage {
+			return true
+		}
+	} else if classification.IsPropertyPage && classification.Confidence > 0.6 {
+		return true
 	}
+	return classification.Confidence < 0.7 && traditional()
+}
 
-	return context
+// calibrateClassification retorna uma cópia da classificação com a confiança calibrada (o
+// resultado original pode estar no cache do serviço de IA)
+func (aic *AIIntegratedCrawler) calibrateClassification(url string, classification *ai.PageClassificationResult) *ai.PageClassificationResult {
+	calibrated := *classification
+	calibrated.Confidence = aic.calibrator.Score(CalibrationSourceAI, url, classification.Confidence, classification.IsPropertyPage)
+	return &calibrated
 }
 
 // shouldAnalyzeWithAI decide se deve usar IA para analisar um link
@@ -435,15 +459,29 @@ func (aic *AIIntegratedCrawler) shouldAnalyzeWithAI(linkText, context, url strin
 
 // shouldProcessWithTraditionalMethods usa métodos tradicionais para decidir processamento
 func (aic *AIIntegratedCrawler) shouldProcessWithTraditionalMethods(e *colly.HTMLElement) bool {
-	// Implementação básica - pode usar classificadores existentes
-	text := strings.ToLower(e.Text)
+	return findPropertyIndicators(e.Text).isProperty()
+}
+
+// PropertyIndicators são os indicadores básicos de página de propriedade encontrados no texto
+type PropertyIndicators struct {
+	Price   bool `json:"price"`
+	Rooms   bool `json:"rooms"`
+	Address bool `json:"address"`
+}
 
-	// Indicadores básicos de página de propriedade
-	hasPrice := strings.Contains(text, "r$")
-	hasRooms := strings.Contains(text, "quartos") || strings.Contains(text, "dormitórios")
-	hasAddress := strings.Contains(text, "rua") || strings.Contains(text, "avenida")
+// findPropertyIndicators procura preço, quartos e endereço no texto da página
+func findPropertyIndicators(text string) PropertyIndicators {
+	text = strings.ToLower(text)
+	return PropertyIndicators{
+		Price:   strings.Contains(text, "r$"),
+		Rooms:   strings.Contains(text, "quartos") || strings.Contains(text, "dormitórios"),
+		Address: strings.Contains(text, "rua") || strings.Contains(text, "avenida"),
+	}
+}
 
-	return hasPrice && (hasRooms || hasAddress)
+// isProperty exige preço e ao menos quartos ou endereço
+func (pi PropertyIndicators) isProperty() bool {
+	return pi.Price && (pi.Rooms || pi.Address)
 }
 
 // extractBasicPropertyData extração básica como fallback
@@ -458,20 +496,25 @@ func (aic *AIIntegratedCrawler) extractBasicPropertyData(e *colly.HTMLElement, u
 
 // isLikelyPropertyURL verifica se URL parece ser de propriedade
 func (aic *AIIntegratedCrawler) isLikelyPropertyURL(url string) bool {
-	urlLower := strings.ToLower(url)
+	return len(matchPropertyURLPatterns(url)) > 0
+}
 
-	propertyPatterns := []string{
-		"/imovel/", "/propriedade/", "/anuncio/", "/detalhes/",
-		"/casa/", "/apartamento/", "/ref-", "/codigo-", "/id-",
-	}
+// propertyURLPatterns são trechos de URL que indicam propriedade individual
+var propertyURLPatterns = []string{
+	"/imovel/", "/propriedade/", "/anuncio/", "/detalhes/",
+	"/casa/", "/apartamento/", "/ref-", "/codigo-", "/id-",
+}
 
-	for _, pattern := range propertyPatterns {
+// matchPropertyURLPatterns retorna os trechos de propertyURLPatterns presentes na URL
+func matchPropertyURLPatterns(url string) []string {
+	urlLower := strings.ToLower(url)
+	var matched []string
+	for _, pattern := range propertyURLPatterns {
 		if strings.Contains(urlLower, pattern) {
-			return true
+			matched = append(matched, pattern)
 		}
 	}
-
-	return false
+	return matched
 }
 
 // isValidProperty verifica se propriedade tem dados suficientes
@@ -502,7 +545,7 @@ func (aic *AIIntegratedCrawler) updateStats(statType, rawURL string) {
 	aic.stats.mutex.Lock()
 	defer aic.stats.mutex.Unlock()
 
-	aic.stats.LastUpdate = time.Now()
+	aic.stats.LastUpdate = aic.clock.Now()
 
 	switch statType {
 	case "page_visited":
@@ -548,6 +591,8 @@ func (aic *AIIntegratedCrawler) GetStats() *AIIntegratedStats {
 		HighConfidenceMatches: aic.stats.HighConfidenceMatches,
 		StartTime:             aic.stats.StartTime,
 		LastUpdate:            aic.stats.LastUpdate,
+		Concurrency:           collectorConcurrency(aic.mainTuner, aic.detailTuner),
+		AIValidationSampling:  aic.validationSampler.Stats(),
 		DomainStats:           make(map[string]int),
 		AIPerformanceStats:    make(map[string]interface{}),
 	}
@@ -567,6 +612,7 @@ func (aic *AIIntegratedCrawler) GetStats() *AIIntegratedStats {
 func (aic *AIIntegratedCrawler) logFinalStats() {
 	stats := aic.GetStats()
 	duration := time.Since(stats.StartTime)
+	tokens := ai.GetSanitizerStats().Total
 
 	aic.logger.WithFields(map[string]interface{}{
 		"duration":                duration.String(),
@@ -580,5 +626,8 @@ func (aic *AIIntegratedCrawler) logFinalStats() {
 		"high_confidence_matches": stats.HighConfidenceMatches,
 		"success_rate":            float64(stats.PropertiesSaved) / float64(stats.PropertiesFound) * 100,
 		"ai_usage_rate":           float64(stats.AIClassifications) / float64(stats.PagesVisited) * 100,
+		"ai_tokens_sent":          tokens.SentTokens,
+		"ai_tokens_saved":         tokens.SavedTokens,
+		"ai_tokens_saved_percent": tokens.SavedPercent,
 	}).Info("AI-integrated crawling completed")
 }
@@ -0,0 +1,236 @@
+package crawler
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Motivos da decisão de validar (ou não) um anúncio com a IA
+const (
+	AIValidationLowConfidence = "low_confidence" // campo-chave ausente ou com nota baixa: sempre valida
+	AIValidationSampled       = "sampled"        // sorteado pela taxa de amostragem do domínio
+	AIValidationSkipped       = "skipped"
+)
+
+// aiValidationKeyFields são os campos cuja ausência ou nota baixa força a validação pela IA
+var aiValidationKeyFields = []string{"valor", "endereco", "tipo_imovel"}
+
+// AIValidationSampler decide quais anúncios passam pela validação da IA (ValidateExtractedData):
+// todos os de baixa confiança e uma amostra dos demais, com taxa por domínio. Também mede quantos
+// anúncios validados a IA corrigiu, para justificar a taxa de amostragem escolhida.
+type AIValidationSampler struct {
+	rate          float64
+	domainRates   map[string]float64
+	minConfidence float64
+	random        func() float64
+	mutex         sync.Mutex
+	domains       map[string]*aiValidationCounters
+}
+
+// aiValidationCounters acumula as decisões e correções de um domínio
+type aiValidationCounters struct {
+	extracted, lowConfidence, sampled, skipped int
+	lowConfidenceCorrected, sampledCorrected   int
+	lowConfidenceFailed, sampledFailed         int
+	correctedFields                            map[string]int
+}
+
+// AIValidationStats expõe a amostragem da validação por IA de um domínio e as taxas de correção
+// medidas (fração dos anúncios validados em que a IA alterou algum campo)
+type AIValidationStats struct {
+	Domain                      string         `json:"domain"`
+	SampleRate                  float64        `json:"sample_rate"`
+	Extracted                   int            `json:"extracted"`
+	Validated                   int            `json:"validated"`
+	LowConfidence               int            `json:"low_confidence"`
+	Sampled                     int            `json:"sampled"`
+	Skipped                     int            `json:"skipped"`
+	Failed                      int            `json:"failed"`
+	LowConfidenceCorrectionRate float64        `json:"low_confidence_correction_rate"`
+	SampledCorrectionRate       float64        `json:"sampled_correction_rate"`
+	CorrectedFields             map[string]int `json:"corrected_fields,omitempty"`
+}
+
+// NewAIValidationSampler cria o amostrador com AI_VALIDATION_SAMPLE_RATE,
+// AI_VALIDATION_MIN_CONFIDENCE e as taxas por domínio de AI_VALIDATION_DOMAIN_RATES
+func NewAIValidationSampler(cfg *config.Config) (*AIValidationSampler, error) {
+	if cfg.AIValidationSampleRate < 0 || cfg.AIValidationSampleRate > 1 {
+		return nil, fmt.Errorf("invalid AI validation sample rate %v (expected 0 to 1)", cfg.AIValidationSampleRate)
+	}
+	domainRates, err := ParseAIValidationRates(cfg.AIValidationDomainRates)
+	if err != nil {
+		return nil, err
+	}
+	return &AIValidationSampler{
+		rate:          cfg.AIValidationSampleRate,
+		domainRates:   domainRates,
+		minConfidence: cfg.AIValidationMinConfidence,
+		random:        rand.Float64,
+		domains:       make(map[string]*aiValidationCounters),
+	}, nil
+}
+
+// ParseAIValidationRates interpreta a lista "dominio=taxa" (ex: AI_VALIDATION_DOMAIN_RATES)
+func ParseAIValidationRates(entries []string) (map[string]float64, error) {
+	rates := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		domain, value, found := strings.Cut(entry, "=")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !found || strings.TrimSpace(domain) == "" || err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid AI validation rate %q (expected domain=rate with rate from 0 to 1)", entry)
+		}
+		rates[repository.NormalizeDomain(strings.TrimSpace(domain))] = rate
+	}
+	return rates, nil
+}
+
+// Decide indica se o anúncio deve ser validado pela IA e o motivo (AIValidationLowConfidence,
+// AIValidationSampled ou AIValidationSkipped), contabilizando a decisão no domínio do anúncio
+func (s *AIValidationSampler) Decide(property repository.Property) string {
+	domain := repository.NormalizeDomain(property.URL)
+	decision := AIValidationSkipped
+	if s.lowConfidence(property) {
+		decision = AIValidationLowConfidence
+	} else if s.random() < s.domainRate(domain) {
+		decision = AIValidationSampled
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	counters := s.counters(domain)
+	counters.extracted++
+	switch decision {
+	case AIValidationLowConfidence:
+		counters.lowConfidence++
+	case AIValidationSampled:
+		counters.sampled++
+	default:
+		counters.skipped++
+	}
+	return decision
+}
+
+// Record registra o resultado da validação de um anúncio: os campos corrigidos pela IA ou, com
+// err, a falha da validação
+func (s *AIValidationSampler) Record(property repository.Property, decision string, corrected []string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	counters := s.counters(repository.NormalizeDomain(property.URL))
+	if err != nil {
+		if decision == AIValidationLowConfidence {
+			counters.lowConfidenceFailed++
+		} else {
+			counters.sampledFailed++
+		}
+		return
+	}
+	if len(corrected) == 0 {
+		return
+	}
+	if decision == AIValidationLowConfidence {
+		counters.lowConfidenceCorrected++
+	} else {
+		counters.sampledCorrected++
+	}
+	for _, field := range corrected {
+		counters.correctedFields[field]++
+	}
+}
+
+// Stats retorna a amostragem e as taxas de correção por domínio, do domínio com mais anúncios ao
+// com menos
+func (s *AIValidationSampler) Stats() []AIValidationStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Taxa de correção sobre os anúncios validados com sucesso
+	correctionRate := func(corrected, validated, failed int) float64 {
+		if validated-failed <= 0 {
+			return 0
+		}
+		return math.Round(float64(corrected)/float64(validated-failed)*1000) / 1000
+	}
+
+	stats := make([]AIValidationStats, 0, len(s.domains))
+	for domain, counters := range s.domains {
+		domainStats := AIValidationStats{
+			Domain:        domain,
+			SampleRate:    s.domainRate(domain),
+			Extracted:     counters.extracted,
+			Validated:     counters.lowConfidence + counters.sampled,
+			LowConfidence: counters.lowConfidence,
+			Sampled:       counters.sampled,
+			Skipped:       counters.skipped,
+			Failed:        counters.lowConfidenceFailed + counters.sampledFailed,
+		}
+		domainStats.LowConfidenceCorrectionRate = correctionRate(counters.lowConfidenceCorrected, counters.lowConfidence, counters.lowConfidenceFailed)
+		domainStats.SampledCorrectionRate = correctionRate(counters.sampledCorrected, counters.sampled, counters.sampledFailed)
+		if len(counters.correctedFields) > 0 {
+			domainStats.CorrectedFields = make(map[string]int, len(counters.correctedFields))
+			for field, count := range counters.correctedFields {
+				domainStats.CorrectedFields[field] = count
+			}
+		}
+		stats = append(stats, domainStats)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Extracted != stats[j].Extracted {
+			return stats[i].Extracted > stats[j].Extracted
+		}
+		return stats[i].Domain < stats[j].Domain
+	})
+	return stats
+}
+
+// lowConfidence indica se falta algum campo-chave ou se algum tem nota abaixo do mínimo
+func (s *AIValidationSampler) lowConfidence(property repository.Property) bool {
+	// Cópia das notas: ScoreFieldConfidence preenche o mapa do anúncio
+	scored := property
+	scored.FieldConfidence = make(map[string]float64, len(property.FieldConfidence))
+	for field, score := range property.FieldConfidence {
+		scored.FieldConfidence[field] = score
+	}
+	repository.ScoreFieldConfidence(&scored)
+
+	if scored.Valor <= 0 || strings.TrimSpace(scored.Endereco) == "" {
+		return true
+	}
+	for _, field := range aiValidationKeyFields {
+		if score, exists := scored.FieldConfidence[field]; exists && score < s.minConfidence {
+			return true
+		}
+	}
+	return false
+}
+
+// domainRate retorna a taxa de amostragem do domínio (a global quando não configurada)
+func (s *AIValidationSampler) domainRate(domain string) float64 {
+	if rate, exists := s.domainRates[domain]; exists {
+		return rate
+	}
+	return s.rate
+}
+
+// counters retorna os contadores do domínio, criando-os se necessário (chamado com o mutex)
+func (s *AIValidationSampler) counters(domain string) *aiValidationCounters {
+	counters, exists := s.domains[domain]
+	if !exists {
+		counters = &aiValidationCounters{correctedFields: make(map[string]int)}
+		s.domains[domain] = counters
+	}
+	return counters
+}
@@ -0,0 +1,326 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// Padrões da paginação da API quando o domínio não define os próprios
+const (
+	defaultAPIStartPage = 1
+	defaultAPIPageSize  = 20
+	defaultAPIMaxPages  = 50
+	maxAPIResponseSize  = 10 << 20 // 10MB por página
+)
+
+var (
+	apiTemplateFieldRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+	apiNumberRegex        = regexp.MustCompile(`[\d.,]+`)
+)
+
+// APIFetcher lê anúncios diretamente da API JSON de busca de um portal (DomainConfig.API),
+// sem passar pela extração de HTML. Os imóveis mapeados seguem o mesmo pipeline de
+// pós-processamento, validação e gravação dos extraídos das páginas.
+type APIFetcher struct {
+	client    *http.Client
+	runtime   *RuntimeState
+	discovery *DiscoveryTracker
+	userAgent string
+	logger    *logger.Logger
+}
+
+// NewAPIFetcher cria o leitor de APIs; as requisições respeitam o limitador por domínio do runtime
+func NewAPIFetcher(runtime *RuntimeState, discovery *DiscoveryTracker) *APIFetcher {
+	return &APIFetcher{
+		client:    NewGuardedClient(30 * time.Second),
+		runtime:   runtime,
+		discovery: discovery,
+		userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36",
+		logger:    logger.NewLogger("api_fetcher"),
+	}
+}
+
+// SetTransport substitui o transporte HTTP usado para ler a API (o padrão recusa endereços
+// internos)
+func (af *APIFetcher) SetTransport(transport http.RoundTripper) {
+	af.client.Transport = transport
+}
+
+// Fetch percorre as páginas da API a partir da seed e entrega cada imóvel mapeado a handle;
+// handle retorna false para interromper. Retorna o número de imóveis entregues.
+func (af *APIFetcher) Fetch(ctx context.Context, seedURL string, cfg *repository.DomainAPIConfig, handle func(*repository.Property) bool) (int, error) {
+	startPage, pageSize, maxPages := cfg.StartPage, cfg.PageSize, cfg.MaxPages
+	if startPage <= 0 {
+		startPage = defaultAPIStartPage
+	}
+	if pageSize <= 0 {
+		pageSize = defaultAPIPageSize
+	}
+	if maxPages <= 0 {
+		maxPages = defaultAPIMaxPages
+	}
+
+	if af.discovery != nil {
+		af.discovery.RecordSeed(seedURL)
+	}
+
+	seen := make(map[string]bool)
+	delivered := 0
+	for page := startPage; page < startPage+maxPages; page++ {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+
+		pageURL := apiPageURL(cfg.Endpoint, page, (page-startPage)*pageSize, pageSize)
+		items, err := af.fetchPage(ctx, pageURL, cfg)
+		if err != nil {
+			return delivered, err
+		}
+		if len(items) == 0 {
+			break
+		}
+		if af.discovery != nil {
+			af.discovery.RecordLink(seedURL, pageURL)
+			af.discovery.RecordKind(pageURL, DiscoveryKindCatalog)
+			af.discovery.RecordSource(pageURL, repository.DiscoverySourceAPI)
+			af.discovery.RecordVisit(pageURL)
+		}
+
+		newItems := 0
+		for _, item := range items {
+			property := MapAPIItem(item, cfg, pageURL)
+			if property == nil || seen[property.URL] {
+				continue
+			}
+			seen[property.URL] = true
+			newItems++
+
+			if af.discovery != nil {
+				af.discovery.RecordLink(pageURL, property.URL)
+			}
+			delivered++
+			if !handle(property) {
+				return delivered, nil
+			}
+		}
+
+		// APIs que ignoram o parâmetro de página repetem os mesmos itens
+		if newItems == 0 || (strings.Contains(cfg.Endpoint, "{page_size}") && len(items) < pageSize) {
+			break
+		}
+	}
+
+	af.logger.WithFields(map[string]interface{}{
+		"seed":       seedURL,
+		"properties": delivered,
+	}).Info("Listing API crawled")
+	return delivered, nil
+}
+
+// fetchPage requisita uma página da API e retorna a lista de itens em ResultsPath
+func (af *APIFetcher) fetchPage(ctx context.Context, pageURL string, cfg *repository.DomainAPIConfig) ([]interface{}, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api url %s: %v", pageURL, err)
+	}
+
+	if af.runtime != nil {
+		throttle := af.runtime.domainThrottle()
+		if controller := throttle.globalController(); controller != nil {
+			domain := repository.NormalizeDomain(parsed.Host)
+			controller.Acquire(domain)
+			defer controller.Release(domain)
+		}
+		throttle.Wait(parsed.Host)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", af.userAgent)
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := af.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("api request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api returned status %d for %s", resp.StatusCode, pageURL)
+	}
+
+	var document interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxAPIResponseSize)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid api response: %v", err)
+	}
+
+	items, ok := lookupJSONPath(document, cfg.ResultsPath).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("api response has no list at %q", cfg.ResultsPath)
+	}
+	return items, nil
+}
+
+// apiPageURL preenche os marcadores de paginação do endpoint
+func apiPageURL(endpoint string, page, offset, pageSize int) string {
+	return strings.NewReplacer(
+		"{page}", strconv.Itoa(page),
+		"{offset}", strconv.Itoa(offset),
+		"{page_size}", strconv.Itoa(pageSize),
+	).Replace(endpoint)
+}
+
+// MapAPIItem converte um item da API em imóvel conforme o mapeamento de campos do domínio
+// (nil se o item não tiver URL)
+func MapAPIItem(item interface{}, cfg *repository.DomainAPIConfig, pageURL string) *repository.Property {
+	property := &repository.Property{}
+	for field, path := range cfg.FieldMapping {
+		value := lookupJSONPath(item, path)
+		if value == nil {
+			continue
+		}
+
+		switch field {
+		case "url":
+			property.URL = jsonString(value)
+		case "endereco":
+			property.Endereco = jsonString(value)
+		case "cidade":
+			property.Cidade = jsonString(value)
+		case "bairro":
+			property.Bairro = jsonString(value)
+		case "cep":
+			property.CEP = jsonString(value)
+		case "descricao":
+			property.Descricao = jsonString(value)
+		case "tipo_imovel":
+			property.TipoImovel = jsonString(value)
+		case "valor":
+			property.Valor = jsonNumber(value)
+			property.ValorTexto = jsonString(value)
+		case "area_total":
+			property.AreaTotal = jsonNumber(value)
+		case "area_util":
+			property.AreaUtil = jsonNumber(value)
+		case "quartos":
+			property.Quartos = int(jsonNumber(value))
+		case "banheiros":
+			property.Banheiros = int(jsonNumber(value))
+		case "caracteristicas":
+			property.Caracteristicas = jsonStrings(value)
+		case "fotos":
+			if list, ok := value.([]interface{}); ok {
+				property.Fotos = len(list)
+			} else {
+				property.Fotos = int(jsonNumber(value))
+			}
+		}
+		property.SetProvenance(field, repository.ExtractorAPI, path)
+	}
+
+	if property.URL == "" && cfg.URLTemplate != "" {
+		property.URL = apiTemplateFieldRegex.ReplaceAllStringFunc(cfg.URLTemplate, func(match string) string {
+			return url.PathEscape(jsonString(lookupJSONPath(item, match[1:len(match)-1])))
+		})
+	}
+	if property.URL == "" {
+		return nil
+	}
+	if base, err := url.Parse(pageURL); err == nil {
+		if resolved, err := base.Parse(property.URL); err == nil {
+			property.URL = resolved.String()
+		}
+	}
+	if property.ValorTexto != "" && !strings.Contains(property.ValorTexto, "R$") {
+		property.ValorTexto = fmt.Sprintf("R$ %s", property.ValorTexto)
+	}
+	property.SemFotos = property.Fotos == 0
+	return property
+}
+
+// lookupJSONPath percorre o documento pelo caminho com pontos; índices numéricos acessam listas
+func lookupJSONPath(document interface{}, path string) interface{} {
+	current := document
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+		switch node := current.(type) {
+		case map[string]interface{}:
+			current = node[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil
+			}
+			current = node[index]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// jsonString converte um valor JSON simples em texto
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// jsonNumber converte números e textos ("R$ 450.000,00", "85.5 m²") em float
+func jsonNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		number, _ := utils.ParseBrazilianNumber(apiNumberRegex.FindString(v))
+		return number
+	default:
+		return 0
+	}
+}
+
+// jsonStrings converte uma lista (ou texto separado por vírgulas) em lista de textos
+func jsonStrings(value interface{}) []string {
+	var result []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, entry := range v {
+			if text := jsonString(entry); text != "" {
+				result = append(result, text)
+			}
+		}
+	case string:
+		for _, entry := range strings.Split(v, ",") {
+			if text := strings.TrimSpace(entry); text != "" {
+				result = append(result, text)
+			}
+		}
+	}
+	return result
+}
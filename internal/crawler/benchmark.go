@@ -0,0 +1,459 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Engines comparados pelo benchmark
+const (
+	BenchmarkTraditional  = "traditional"
+	BenchmarkImproved     = "improved"
+	BenchmarkAIIntegrated = "ai_integrated"
+)
+
+// BenchmarkEngines lista os engines na ordem em que são executados
+var BenchmarkEngines = []string{BenchmarkTraditional, BenchmarkImproved, BenchmarkAIIntegrated}
+
+// BenchmarkManifest é o arquivo que descreve um conjunto de seeds gravado
+const BenchmarkManifest = "seedset.json"
+
+// mockBenchmarkHost é o domínio fictício do conjunto de seeds sintético
+const mockBenchmarkHost = "http://imobiliaria-benchmark.test"
+
+// BenchmarkSeedSet são as seeds, as páginas gravadas (servidas sem acesso à rede) e os rótulos
+// das URLs (true = anúncio de imóvel) usados para medir a precisão de cada engine
+type BenchmarkSeedSet struct {
+	Seeds  []string          `json:"seeds"`
+	Pages  map[string]string `json:"pages"`            // URL -> arquivo HTML relativo ao diretório
+	Labels map[string]bool   `json:"labels,omitempty"` // URL -> é anúncio de imóvel
+
+	dir  string
+	body map[string][]byte // páginas em memória (conjunto sintético)
+}
+
+// LoadBenchmarkSeedSet lê o conjunto gravado em dir (seedset.json + páginas HTML)
+func LoadBenchmarkSeedSet(dir string) (*BenchmarkSeedSet, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BenchmarkManifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark seed set: %v", err)
+	}
+	var seedSet BenchmarkSeedSet
+	if err := json.Unmarshal(data, &seedSet); err != nil {
+		return nil, fmt.Errorf("invalid benchmark seed set: %v", err)
+	}
+	if len(seedSet.Seeds) == 0 {
+		return nil, fmt.Errorf("benchmark seed set has no seeds")
+	}
+	if seedSet.Pages == nil {
+		seedSet.Pages = make(map[string]string)
+	}
+	seedSet.dir = dir
+	return &seedSet, nil
+}
+
+// Save grava o manifesto do conjunto em dir (as páginas já gravadas permanecem)
+func (s *BenchmarkSeedSet) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, BenchmarkManifest), data, 0644)
+}
+
+// MockBenchmarkSeedSet gera um site sintético cuja seed é a listagem de imóveis, com links para os
+// anúncios e para páginas institucionais e de blog (falsos positivos), todas rotuladas
+func MockBenchmarkSeedSet(properties int) *BenchmarkSeedSet {
+	seedSet := &BenchmarkSeedSet{
+		Seeds:  []string{mockBenchmarkHost + "/imoveis"},
+		Pages:  make(map[string]string),
+		Labels: make(map[string]bool),
+		body:   make(map[string][]byte),
+	}
+	add := func(path string, isProperty bool, html string) {
+		pageURL := mockBenchmarkHost + path
+		seedSet.Pages[pageURL] = path
+		seedSet.Labels[pageURL] = isProperty
+		seedSet.body[pageURL] = []byte(html)
+	}
+
+	bairros := []string{"Centro", "Jardim América", "Vila Nova", "São José"}
+	var links strings.Builder
+	for i := 1; i <= properties; i++ {
+		path := fmt.Sprintf("/imovel/casa-%d-quartos-%d", i%4+1, i)
+		fmt.Fprintf(&links, `<li><a href="%s">Casa %d</a></li>`, path, i)
+		bairro := bairros[i%len(bairros)]
+		add(path, true, fmt.Sprintf(`<html><head><title>Casa à venda no %[1]s</title></head><body>
+<h1 class="endereco">Rua das Flores, %[2]d - %[1]s, Muzambinho - MG</h1>
+<div class="preco">R$ %[3]d.000,00</div>
+<div class="descricao">Casa com %[4]d quartos, %[5]d banheiros e %[6]d m² de área construída no bairro %[1]s em Muzambinho. Garagem para 2 carros e quintal amplo.</div>
+<img src="/fotos/%[2]d-1.jpg" width="800"><img src="/fotos/%[2]d-2.jpg" width="800">
+<a href="tel:+553535710000">Ligar</a>
+</body></html>`, bairro, i*10, 250+i*15, i%4+1, i%3+1, 80+i*5))
+	}
+
+	add("/", false, `<html><head><title>Imobiliária Benchmark</title></head><body>
+<h1>Imobiliária Benchmark</h1><a href="/imoveis">Imóveis à venda</a><a href="/sobre">Quem somos</a><a href="/blog/financiamento-2024">Blog</a>
+</body></html>`)
+	add("/imoveis", false, `<html><head><title>Imóveis à venda em Muzambinho</title></head><body>
+<nav><a href="/">Início</a><a href="/sobre">Quem somos</a><a href="/blog/financiamento-2024">Blog</a></nav>
+<h1>Imóveis à venda</h1><ul>`+links.String()+`</ul></body></html>`)
+	add("/sobre", false, `<html><head><title>Quem somos</title></head><body>
+<h1>Quem somos</h1><div class="descricao">Somos uma imobiliária de Muzambinho com mais de 20 anos de mercado, atendendo compradores e vendedores em toda a região.</div>
+</body></html>`)
+	add("/blog/financiamento-2024", false, `<html><head><title>Dicas de financiamento</title></head><body>
+<h1>Como financiar sua casa</h1><div class="descricao">Veja como usar o FGTS na compra de uma casa de R$ 300.000,00 com 3 quartos e simular as parcelas do financiamento.</div>
+</body></html>`)
+	return seedSet
+}
+
+// page retorna o HTML gravado da URL
+func (s *BenchmarkSeedSet) page(pageURL string) ([]byte, bool) {
+	for _, candidate := range benchmarkURLVariants(pageURL) {
+		if body, exists := s.body[candidate]; exists {
+			return body, true
+		}
+		if file, exists := s.Pages[candidate]; exists && s.dir != "" {
+			body, err := os.ReadFile(filepath.Join(s.dir, file))
+			return body, err == nil
+		}
+	}
+	return nil, false
+}
+
+// Label retorna o rótulo da URL e se ela foi rotulada
+func (s *BenchmarkSeedSet) Label(pageURL string) (bool, bool) {
+	for _, candidate := range benchmarkURLVariants(pageURL) {
+		if isProperty, exists := s.Labels[candidate]; exists {
+			return isProperty, true
+		}
+	}
+	return false, false
+}
+
+// benchmarkURLVariants normaliza a URL (sem fragmento) e testa com e sem barra final
+func benchmarkURLVariants(rawURL string) []string {
+	if index := strings.Index(rawURL, "#"); index >= 0 {
+		rawURL = rawURL[:index]
+	}
+	if strings.HasSuffix(rawURL, "/") {
+		return []string{rawURL, strings.TrimSuffix(rawURL, "/")}
+	}
+	return []string{rawURL, rawURL + "/"}
+}
+
+// ReplayTransport serve as páginas do conjunto de seeds sem acessar a rede (404 para as demais)
+// e conta as páginas servidas
+type ReplayTransport struct {
+	seedSet *BenchmarkSeedSet
+	mutex   sync.Mutex
+	served  int
+}
+
+// NewReplayTransport cria o transporte que reproduz as páginas gravadas
+func NewReplayTransport(seedSet *BenchmarkSeedSet) *ReplayTransport {
+	return &ReplayTransport{seedSet: seedSet}
+}
+
+// RoundTrip responde com a página gravada da URL
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, found := t.seedSet.page(req.URL.String())
+	status := http.StatusOK
+	if !found {
+		status, body = http.StatusNotFound, nil
+	} else {
+		t.mutex.Lock()
+		t.served++
+		t.mutex.Unlock()
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// Served retorna quantas páginas gravadas foram servidas
+func (t *ReplayTransport) Served() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.served
+}
+
+// RecordingTransport busca as páginas na rede e grava as respostas HTML no conjunto de seeds
+type RecordingTransport struct {
+	seedSet *BenchmarkSeedSet
+	dir     string
+	next    http.RoundTripper
+	mutex   sync.Mutex
+}
+
+// NewRecordingTransport grava em dir as páginas buscadas (next nil usa http.DefaultTransport)
+func NewRecordingTransport(seedSet *BenchmarkSeedSet, dir string, next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if seedSet.Pages == nil {
+		seedSet.Pages = make(map[string]string)
+	}
+	return &RecordingTransport{seedSet: seedSet, dir: dir, next: next}
+}
+
+// RoundTrip busca a página e grava o corpo das respostas HTML bem-sucedidas
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	pageURL := req.URL.String()
+	if _, exists := t.seedSet.Pages[pageURL]; !exists {
+		file := fmt.Sprintf("pages/%05d.html", len(t.seedSet.Pages)+1)
+		if err := os.MkdirAll(filepath.Join(t.dir, "pages"), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(t.dir, file), body, 0644); err != nil {
+			return nil, err
+		}
+		t.seedSet.Pages[pageURL] = file
+	}
+	return resp, nil
+}
+
+// benchmarkRepository guarda em memória os imóveis salvos por um engine
+type benchmarkRepository struct {
+	mutex      sync.Mutex
+	properties []repository.Property
+}
+
+func (r *benchmarkRepository) Save(ctx context.Context, property repository.Property) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.properties = append(r.properties, property)
+	return nil
+}
+
+func (r *benchmarkRepository) FindAll(ctx context.Context) ([]repository.Property, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]repository.Property(nil), r.properties...), nil
+}
+
+func (r *benchmarkRepository) FindWithFilters(ctx context.Context, filter repository.PropertyFilter, pagination repository.PaginationParams) (*repository.PropertySearchResult, error) {
+	properties, _ := r.FindAll(ctx)
+	return &repository.PropertySearchResult{Properties: properties, TotalItems: int64(len(properties))}, nil
+}
+
+func (r *benchmarkRepository) ClearAll(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.properties = nil
+	return nil
+}
+
+func (r *benchmarkRepository) Close() {}
+
+// BenchmarkOptions configura a execução do benchmark. Os serviços de IA são opcionais: sem
+// eles os engines rodam apenas com as heurísticas locais.
+type BenchmarkOptions struct {
+	Config  *config.Config
+	Timeout time.Duration // limite por engine (0 = sem limite)
+
+	AI      *ai.GeminiService // processamento dos imóveis (todos os engines)
+	PageAI  PageAIProvider    // classificação e validação de páginas (ai_integrated)
+	Trainer *AIEnhancedTrainer
+
+	// Calibrator recebe as notas de confiança dos engines (use StartRecording para ajustar as
+	// curvas com os rótulos do seed set)
+	Calibrator *ConfidenceCalibrator
+
+	// Preço em US$ por milhão de tokens de entrada e de saída, usado no custo estimado
+	InputPricePerMillion  float64
+	OutputPricePerMillion float64
+}
+
+// BenchmarkResult é a linha da tabela comparativa de um engine
+type BenchmarkResult struct {
+	Engine     string        `json:"engine"`
+	Pages      int           `json:"pages"`      // páginas gravadas servidas
+	Properties int           `json:"properties"` // imóveis salvos
+	Labeled    int           `json:"labeled"`    // imóveis salvos com URL rotulada
+	Correct    int           `json:"correct"`    // imóveis salvos rotulados como anúncio
+	Precision  float64       `json:"precision"`  // Correct / Labeled
+	Recall     float64       `json:"recall"`     // anúncios rotulados encontrados / anúncios rotulados
+	Duration   time.Duration `json:"duration"`
+	AI         ai.UsageStats `json:"ai"`
+	AICost     float64       `json:"ai_cost_usd"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// RunBenchmark executa os engines informados, um de cada vez, sobre o mesmo conjunto de seeds
+func RunBenchmark(ctx context.Context, seedSet *BenchmarkSeedSet, engines []string, opts BenchmarkOptions) []BenchmarkResult {
+	results := make([]BenchmarkResult, 0, len(engines))
+	for _, engine := range engines {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, runBenchmarkEngine(ctx, seedSet, engine, opts))
+	}
+	return results
+}
+
+// runBenchmarkEngine executa um engine com repositório em memória e páginas reproduzidas
+func runBenchmarkEngine(ctx context.Context, seedSet *BenchmarkSeedSet, engine string, opts BenchmarkOptions) BenchmarkResult {
+	result := BenchmarkResult{Engine: engine}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	repo := &benchmarkRepository{}
+	transport := NewReplayTransport(seedSet)
+	usageBefore := ai.GetUsageStats()
+	start := time.Now()
+
+	if err := startBenchmarkEngine(ctx, seedSet, engine, repo, transport, opts); err != nil {
+		result.Error = err.Error()
+	}
+
+	result.Duration = time.Since(start)
+	result.Pages = transport.Served()
+	result.AI = ai.GetUsageStats().Sub(usageBefore)
+	result.AICost = (float64(result.AI.PromptTokens)*opts.InputPricePerMillion +
+		float64(result.AI.OutputTokens)*opts.OutputPricePerMillion) / 1e6
+	scoreBenchmarkResult(&result, seedSet, repo.properties)
+	return result
+}
+
+// startBenchmarkEngine cria e executa o engine até o fim do crawling
+func startBenchmarkEngine(ctx context.Context, seedSet *BenchmarkSeedSet, engine string, repo *benchmarkRepository, transport http.RoundTripper, opts BenchmarkOptions) error {
+	if engine == BenchmarkTraditional {
+		crawlerEngine := NewCrawlerEngine(repo, opts.AI)
+		crawlerEngine.SetTransport(transport)
+		return crawlerEngine.Start(ctx, seedSet.Seeds)
+	}
+
+	// Os demais engines leem as seeds de cfg.SitesFile
+	seedsFile, err := os.CreateTemp("", "benchmark-seeds-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create seeds file: %v", err)
+	}
+	defer os.Remove(seedsFile.Name())
+	if err := json.NewEncoder(seedsFile).Encode(seedSet.Seeds); err != nil {
+		seedsFile.Close()
+		return fmt.Errorf("failed to write seeds file: %v", err)
+	}
+	seedsFile.Close()
+
+	cfg := config.Config{}
+	if opts.Config != nil {
+		cfg = *opts.Config
+	}
+	cfg.SitesFile = seedsFile.Name()
+
+	deps := CrawlerDependencies{Repository: repo, Transport: transport, Calibrator: opts.Calibrator}
+	if opts.AI != nil {
+		deps.AI = opts.AI
+	}
+
+	switch engine {
+	case BenchmarkImproved:
+		improved, err := NewImprovedCrawler(&cfg, deps)
+		if err != nil {
+			return err
+		}
+		return improved.StartCrawling(ctx)
+	case BenchmarkAIIntegrated:
+		deps.PageAI, deps.Trainer = opts.PageAI, opts.Trainer
+		integrated, err := NewAIIntegratedCrawler(&cfg, deps)
+		if err != nil {
+			return err
+		}
+		return integrated.StartCrawling(ctx)
+	default:
+		return fmt.Errorf("unknown benchmark engine %q (use %s)", engine, strings.Join(BenchmarkEngines, ", "))
+	}
+}
+
+// scoreBenchmarkResult compara os imóveis salvos com os rótulos do conjunto de seeds
+func scoreBenchmarkResult(result *BenchmarkResult, seedSet *BenchmarkSeedSet, properties []repository.Property) {
+	found := make(map[string]bool)
+	for _, property := range properties {
+		pageURL := benchmarkURLVariants(property.URL)[0]
+		if found[pageURL] {
+			continue // unidades de um mesmo anúncio contam uma vez
+		}
+		found[pageURL] = true
+		result.Properties++
+
+		isProperty, labeled := seedSet.Label(pageURL)
+		if !labeled {
+			continue
+		}
+		result.Labeled++
+		if isProperty {
+			result.Correct++
+		}
+	}
+
+	positives := 0
+	for _, isProperty := range seedSet.Labels {
+		if isProperty {
+			positives++
+		}
+	}
+	if result.Labeled > 0 {
+		result.Precision = float64(result.Correct) / float64(result.Labeled)
+	}
+	if positives > 0 {
+		result.Recall = float64(result.Correct) / float64(positives)
+	}
+}
+
+// RecordBenchmarkSeedSet percorre as seeds com o engine tradicional e grava em dir as páginas
+// visitadas. Os rótulos (labels) devem ser preenchidos depois no seedset.json para medir a precisão.
+func RecordBenchmarkSeedSet(ctx context.Context, seeds []string, dir string) (*BenchmarkSeedSet, error) {
+	seedSet := &BenchmarkSeedSet{Seeds: seeds, Pages: make(map[string]string), Labels: make(map[string]bool)}
+	if existing, err := LoadBenchmarkSeedSet(dir); err == nil {
+		seedSet.Pages, seedSet.Labels = existing.Pages, existing.Labels
+	}
+
+	crawlerEngine := NewCrawlerEngine(&benchmarkRepository{}, nil)
+	crawlerEngine.SetTransport(NewRecordingTransport(seedSet, dir, nil))
+	if err := crawlerEngine.Start(ctx, seeds); err != nil {
+		return nil, err
+	}
+
+	if err := seedSet.Save(dir); err != nil {
+		return nil, fmt.Errorf("failed to save benchmark seed set: %v", err)
+	}
+	seedSet.dir = dir
+	return seedSet, nil
+}
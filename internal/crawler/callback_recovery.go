@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// CallbackRecovery protege os callbacks do colly: um panic em uma página é registrado
+// (log, métrica panic_error e ErrorReporter) e o crawling continua com as demais páginas.
+// Opcionalmente o HTML que causou o panic é salvo para reprodução.
+type CallbackRecovery struct {
+	component   string
+	mutex       sync.RWMutex
+	errors      *ErrorTracker
+	control     *JobControl
+	snapshotDir string // vazio desativa os snapshots
+	onPanic     func(rawURL string)
+	panics      int64
+	logger      *logger.Logger
+}
+
+// NewCallbackRecovery cria a proteção de callbacks de um engine
+func NewCallbackRecovery(component string, tracker *ErrorTracker) *CallbackRecovery {
+	return &CallbackRecovery{
+		component: component,
+		errors:    tracker,
+		logger:    logger.NewLogger(component),
+	}
+}
+
+// SetErrorTracker define o agregador onde os panics são contabilizados
+func (cr *CallbackRecovery) SetErrorTracker(tracker *ErrorTracker) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.errors = tracker
+}
+
+// SetJobControl associa o job em execução (o job_id acompanha os panics reportados)
+func (cr *CallbackRecovery) SetJobControl(control *JobControl) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.control = control
+}
+
+// SetPanicHook define uma ação extra após cada panic (ex: atualizar as estatísticas do engine)
+func (cr *CallbackRecovery) SetPanicHook(hook func(rawURL string)) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.onPanic = hook
+}
+
+// SetSnapshotDir ativa a gravação do HTML das páginas que causaram panic
+func (cr *CallbackRecovery) SetSnapshotDir(dir string) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.snapshotDir = strings.TrimSpace(dir)
+}
+
+// PanicCount retorna quantos panics foram recuperados
+func (cr *CallbackRecovery) PanicCount() int64 {
+	return atomic.LoadInt64(&cr.panics)
+}
+
+// HTML protege um callback OnHTML
+func (cr *CallbackRecovery) HTML(handler colly.HTMLCallback) colly.HTMLCallback {
+	return func(e *colly.HTMLElement) {
+		defer cr.recoverPanic("OnHTML", e.Request, e.Response)
+		handler(e)
+	}
+}
+
+// Request protege um callback OnRequest
+func (cr *CallbackRecovery) Request(handler colly.RequestCallback) colly.RequestCallback {
+	return func(r *colly.Request) {
+		defer cr.recoverPanic("OnRequest", r, nil)
+		handler(r)
+	}
+}
+
+// Response protege um callback OnResponse
+func (cr *CallbackRecovery) Response(handler colly.ResponseCallback) colly.ResponseCallback {
+	return func(r *colly.Response) {
+		defer cr.recoverPanic("OnResponse", r.Request, r)
+		handler(r)
+	}
+}
+
+// Error protege um callback OnError
+func (cr *CallbackRecovery) Error(handler colly.ErrorCallback) colly.ErrorCallback {
+	return func(r *colly.Response, err error) {
+		defer cr.recoverPanic("OnError", r.Request, r)
+		handler(r, err)
+	}
+}
+
+// recoverPanic deve ser chamado com defer: recupera o panic e registra o contexto da página
+func (cr *CallbackRecovery) recoverPanic(callback string, request *colly.Request, response *colly.Response) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	atomic.AddInt64(&cr.panics, 1)
+
+	rawURL := ""
+	if request != nil && request.URL != nil {
+		rawURL = request.URL.String()
+	}
+	message := fmt.Sprintf("panic in %s: %v", callback, recovered)
+
+	cr.mutex.RLock()
+	tracker, control, snapshotDir, onPanic := cr.errors, cr.control, cr.snapshotDir, cr.onPanic
+	cr.mutex.RUnlock()
+
+	fields := map[string]interface{}{
+		"url":      rawURL,
+		"callback": callback,
+	}
+	if snapshotDir != "" && response != nil && len(response.Body) > 0 {
+		if path, err := writePanicSnapshot(snapshotDir, rawURL, message, response.Body); err != nil {
+			cr.logger.WithError(err).Warn("Failed to save panic snapshot")
+		} else {
+			fields["snapshot"] = path
+		}
+	}
+
+	cr.logger.WithFields(fields).Errorf("Recovered from %s", message)
+	tracker.Record(ErrorTypePanic, rawURL, message, 0)
+	if onPanic != nil {
+		onPanic(rawURL)
+	}
+
+	tags := crawlFailureTags(control, ErrorTypePanic, rawURL)
+	tags["callback"] = callback
+	logger.CapturePanic(recovered, cr.component, tags)
+}
+
+// writePanicSnapshot grava o HTML da página (com a URL e o panic em um comentário inicial)
+func writePanicSnapshot(dir, rawURL, message string, body []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %v", err)
+	}
+
+	host := "unknown"
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		host = repository.NormalizeDomain(parsed.Host)
+	}
+	urlHash := sha256.Sum256([]byte(rawURL))
+	name := fmt.Sprintf("%s_%s_%x.html", time.Now().Format("20060102-150405"), host, urlHash[:4])
+	path := filepath.Join(dir, name)
+
+	header := fmt.Sprintf("<!-- url: %s\n     %s -->\n", rawURL, strings.ReplaceAll(message, "--", "- -"))
+	if err := os.WriteFile(path, append([]byte(header), body...), 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,272 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+)
+
+// CatalogSegmenter segmenta páginas de catálogo em cards de imóveis agrupando
+// estruturas DOM irmãs que se repetem (mesma tag e mesma classe principal)
+type CatalogSegmenter struct {
+	logger          *logger.Logger
+	minCards        int
+	maxCards        int
+	minCardText     int
+	maxCardText     int
+	minSignalRatio  float64
+	pricePattern    *regexp.Regexp
+	featurePattern  *regexp.Regexp
+	digitPattern    *regexp.Regexp
+	detailPathHints []string
+}
+
+// CatalogCard representa um card de imóvel identificado no catálogo
+type CatalogCard struct {
+	Index     int
+	Signature string
+	Text      string
+	DetailURL string
+	Selection *goquery.Selection
+}
+
+// cardCluster agrupa irmãos com a mesma assinatura estrutural
+type cardCluster struct {
+	signature string
+	members   []*goquery.Selection
+	score     float64
+	avgText   int
+}
+
+// NewCatalogSegmenter cria um novo segmentador de catálogos
+func NewCatalogSegmenter() *CatalogSegmenter {
+	return &CatalogSegmenter{
+		logger:         logger.NewLogger("catalog_segmenter"),
+		minCards:       3,
+		maxCards:       50,
+		minCardText:    20,
+		maxCardText:    2000,
+		minSignalRatio: 0.5,
+		pricePattern:   regexp.MustCompile(`R\$\s*[\d.,]+`),
+		featurePattern: regexp.MustCompile(`(?i)\d+\s*(?:quartos?|dormit[óo]rios?|su[íi]tes?|m²|m2|vagas?|banheiros?)`),
+		digitPattern:   regexp.MustCompile(`\d`),
+		detailPathHints: []string{
+			"/imovel", "/imoveis/", "/propriedade", "/anuncio", "/detalhe",
+			"/casa", "/apartamento", "/terreno", "/ref", "/codigo", "id=",
+		},
+	}
+}
+
+// Segment identifica os cards de imóveis dentro do DOM informado.
+// Retorna nil se nenhuma estrutura repetida com sinais de imóvel for encontrada.
+func (cs *CatalogSegmenter) Segment(root *goquery.Selection, baseURL *url.URL) []CatalogCard {
+	if root == nil {
+		return nil
+	}
+
+	best := cs.findBestCluster(root)
+	if best == nil {
+		return nil
+	}
+
+	var cards []CatalogCard
+	for i, member := range best.members {
+		if len(cards) >= cs.maxCards {
+			break
+		}
+
+		text := cs.cardText(member)
+		if !cs.hasPropertySignal(text) {
+			continue
+		}
+
+		cards = append(cards, CatalogCard{
+			Index:     i,
+			Signature: best.signature,
+			Text:      text,
+			DetailURL: cs.findDetailURL(member, baseURL),
+			Selection: member,
+		})
+	}
+
+	cs.logger.WithFields(map[string]interface{}{
+		"signature": best.signature,
+		"cluster":   len(best.members),
+		"cards":     len(cards),
+		"score":     best.score,
+	}).Debug("Catalog segmented into property cards")
+
+	return cards
+}
+
+// findBestCluster percorre o DOM e escolhe o grupo de irmãos mais provável de ser a lista de imóveis
+func (cs *CatalogSegmenter) findBestCluster(root *goquery.Selection) *cardCluster {
+	var best *cardCluster
+
+	evaluate := func(parent *goquery.Selection) {
+		groups := make(map[string][]*goquery.Selection)
+		var order []string
+
+		parent.Children().Each(func(_ int, child *goquery.Selection) {
+			signature := cs.signature(child)
+			if signature == "" {
+				return
+			}
+			if _, exists := groups[signature]; !exists {
+				order = append(order, signature)
+			}
+			groups[signature] = append(groups[signature], child)
+		})
+
+		for _, signature := range order {
+			cluster := cs.scoreCluster(signature, groups[signature])
+			if cluster == nil {
+				continue
+			}
+			if best == nil || cluster.score > best.score ||
+				(cluster.score == best.score && cluster.avgText < best.avgText) {
+				best = cluster
+			}
+		}
+	}
+
+	evaluate(root)
+	root.Find("*").Each(func(_ int, parent *goquery.Selection) {
+		if parent.Children().Length() >= cs.minCards {
+			evaluate(parent)
+		}
+	})
+
+	return best
+}
+
+// scoreCluster pontua um grupo de irmãos pela quantidade de cards com sinais de imóvel
+func (cs *CatalogSegmenter) scoreCluster(signature string, members []*goquery.Selection) *cardCluster {
+	if len(members) < cs.minCards {
+		return nil
+	}
+
+	withSignal := 0
+	totalText := 0
+	for _, member := range members {
+		text := cs.cardText(member)
+		if len(text) < cs.minCardText || len(text) > cs.maxCardText {
+			continue
+		}
+		totalText += len(text)
+		if cs.hasPropertySignal(text) {
+			withSignal++
+		}
+	}
+
+	if withSignal < cs.minCards {
+		return nil
+	}
+
+	ratio := float64(withSignal) / float64(len(members))
+	if ratio < cs.minSignalRatio {
+		return nil
+	}
+
+	return &cardCluster{
+		signature: signature,
+		members:   members,
+		score:     float64(withSignal) * ratio,
+		avgText:   totalText / withSignal,
+	}
+}
+
+// signature gera a assinatura estrutural de um elemento (tag + classe principal ou tags filhas)
+func (cs *CatalogSegmenter) signature(s *goquery.Selection) string {
+	tag := goquery.NodeName(s)
+	switch tag {
+	case "script", "style", "noscript", "br", "hr", "#text", "#comment":
+		return ""
+	}
+
+	// Usa apenas a classe principal: cards em destaque costumam ter classes extras (ex: "card destaque")
+	if classAttr, exists := s.Attr("class"); exists {
+		for _, class := range strings.Fields(classAttr) {
+			// Classes com dígitos costumam ser identificadores únicos (ex: card-123)
+			if !cs.digitPattern.MatchString(class) {
+				return tag + "." + class
+			}
+		}
+	}
+
+	var childTags []string
+	s.Children().Each(func(_ int, child *goquery.Selection) {
+		childTags = append(childTags, goquery.NodeName(child))
+	})
+	return tag + ">" + strings.Join(childTags, ",")
+}
+
+// cardText retorna o texto normalizado do card
+func (cs *CatalogSegmenter) cardText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// hasPropertySignal verifica se o texto tem preço ou características típicas de imóvel
+func (cs *CatalogSegmenter) hasPropertySignal(text string) bool {
+	return cs.pricePattern.MatchString(text) || cs.featurePattern.MatchString(text)
+}
+
+// findDetailURL encontra o link do card que aponta para a página de detalhes do imóvel
+func (cs *CatalogSegmenter) findDetailURL(card *goquery.Selection, baseURL *url.URL) string {
+	var candidates []string
+
+	if goquery.NodeName(card) == "a" {
+		if href, exists := card.Attr("href"); exists {
+			candidates = append(candidates, href)
+		}
+	}
+	card.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		candidates = append(candidates, href)
+	})
+
+	fallback := ""
+	for _, href := range candidates {
+		href = strings.TrimSpace(href)
+		lower := strings.ToLower(href)
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(lower, "javascript:") ||
+			strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(lower, "tel:") || strings.Contains(lower, "wa.me") {
+			continue
+		}
+
+		absolute := cs.resolve(href, baseURL)
+		if absolute == "" {
+			continue
+		}
+
+		for _, hint := range cs.detailPathHints {
+			if strings.Contains(lower, hint) {
+				return absolute
+			}
+		}
+		if fallback == "" && cs.digitPattern.MatchString(href) {
+			fallback = absolute
+		}
+	}
+
+	return fallback
+}
+
+// resolve converte um href relativo em URL absoluta
+func (cs *CatalogSegmenter) resolve(href string, baseURL *url.URL) string {
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if baseURL != nil {
+		parsed = baseURL.ResolveReference(parsed)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return ""
+	}
+	parsed.Fragment = ""
+	return parsed.String()
+}
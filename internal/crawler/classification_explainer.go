@@ -0,0 +1,303 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/gocolly/colly"
+)
+
+// Engines cujas decisões são explicadas (a busca disparada pela API usa o recursivo)
+const (
+	ExplainEngineRecursive    = "recursive"
+	ExplainEngineImproved     = "improved"
+	ExplainEngineAIIntegrated = "ai_integrated"
+)
+
+// explainMaxPageSize limita o HTML baixado para explicar uma classificação
+const explainMaxPageSize = 5 << 20
+
+// ClassificationExplanation descreve como cada etapa da cascata de classificação avaliou a URL
+type ClassificationExplanation struct {
+	URL        string                   `json:"url"`
+	FinalURL   string                   `json:"final_url"` // após redirecionamentos
+	StatusCode int                      `json:"status_code"`
+	Title      string                   `json:"title"`
+	FetchedAt  time.Time                `json:"fetched_at"`
+	Patterns   PatternExplanation       `json:"patterns"`
+	Heuristics HeuristicExplanation     `json:"heuristics"`
+	AI         *AIExplanation           `json:"ai,omitempty"` // nil sem serviço de IA configurado
+	Decisions  []ClassificationDecision `json:"decisions"`
+	Decision   ClassificationDecision   `json:"decision"` // do engine pedido
+}
+
+// PatternExplanation mostra os padrões de URL que casaram
+type PatternExplanation struct {
+	URLPatterns       []string `json:"url_patterns"` // trechos como "/imovel/" e "/ref-"
+	NumericSlug       bool     `json:"numeric_slug"` // último trecho da URL com números
+	LearnedType       string   `json:"learned_type"` // padrões aprendidos (PatternLearner), "unknown" sem padrões
+	LearnedConfidence float64  `json:"learned_confidence"`
+}
+
+// HeuristicExplanation reúne os classificadores baseados no conteúdo da página
+type HeuristicExplanation struct {
+	Precise               PreciseClassificationResult `json:"precise"` // requisitos, exclusões e pontuação
+	StrictPageType        string                      `json:"strict_page_type"`
+	ContentType           string                      `json:"content_type"`
+	ContentConfidence     float64                     `json:"content_confidence"`
+	CalibratedConfidence  float64                     `json:"content_calibrated_confidence"`
+	Indicators            PropertyIndicators          `json:"indicators"` // reserva da IA no engine integrado
+	IndicatorsSayProperty bool                        `json:"indicators_say_property"`
+}
+
+// AIExplanation é o veredito da IA para a página
+type AIExplanation struct {
+	IsPropertyPage       bool     `json:"is_property_page"`
+	Confidence           float64  `json:"confidence"`
+	CalibratedConfidence float64  `json:"calibrated_confidence"`
+	PageType             string   `json:"page_type"`
+	Reasoning            string   `json:"reasoning"`
+	Indicators           []string `json:"indicators"`
+	Error                string   `json:"error,omitempty"`
+}
+
+// ClassificationDecision é a decisão de um engine para a página
+type ClassificationDecision struct {
+	Engine     string `json:"engine"`
+	IsProperty bool   `json:"is_property"`
+	Action     string `json:"action"` // property, catalog ou skip
+	Reason     string `json:"reason"`
+}
+
+// ClassificationExplainer baixa uma página e a submete às mesmas regras usadas pelos crawlers,
+// registrando o resultado de cada etapa (útil para diagnosticar catálogos classificados como anúncio)
+type ClassificationExplainer struct {
+	client     *http.Client
+	pageAI     PageAIProvider
+	calibrator *ConfidenceCalibrator
+	precise    *PrecisePropertyClassifier
+	strict     *AdvancedPageClassifier
+	content    *ContentBasedPatternLearner
+}
+
+// NewClassificationExplainer cria o explicador; pageAI e calibrator são opcionais
+func NewClassificationExplainer(pageAI PageAIProvider, calibrator *ConfidenceCalibrator) *ClassificationExplainer {
+	return &ClassificationExplainer{
+		client:     &http.Client{Timeout: 20 * time.Second, Transport: NewGuardedTransport()},
+		pageAI:     pageAI,
+		calibrator: calibrator,
+		precise:    NewPrecisePropertyClassifier(),
+		strict:     NewAdvancedPageClassifier(),
+		content:    NewContentBasedPatternLearner(),
+	}
+}
+
+// SetTransport substitui o transporte HTTP usado para baixar as páginas (o padrão recusa
+// endereços internos)
+func (ce *ClassificationExplainer) SetTransport(transport http.RoundTripper) {
+	ce.client.Transport = transport
+}
+
+// Explain baixa a URL e explica a classificação; engine escolhe a decisão final (vazio usa o
+// recursivo) e learner, opcional, fornece os padrões de URL aprendidos
+func (ce *ClassificationExplainer) Explain(ctx context.Context, rawURL, engine string, learner *PatternLearner) (*ClassificationExplanation, error) {
+	if engine == "" {
+		engine = ExplainEngineRecursive
+	}
+	if engine != ExplainEngineRecursive && engine != ExplainEngineImproved && engine != ExplainEngineAIIntegrated {
+		return nil, fmt.Errorf("unknown engine %q (use %s, %s or %s)", engine, ExplainEngineRecursive, ExplainEngineImproved, ExplainEngineAIIntegrated)
+	}
+
+	response, err := ce.fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(response.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %v", err)
+	}
+	return ce.explainDocument(ctx, rawURL, response, doc, engine, learner), nil
+}
+
+// explainDocument explica a classificação de uma página já baixada
+func (ce *ClassificationExplainer) explainDocument(ctx context.Context, rawURL string, response *colly.Response, doc *goquery.Document, engine string, learner *PatternLearner) *ClassificationExplanation {
+	finalURL := response.Request.URL.String()
+
+	explanation := &ClassificationExplanation{
+		URL:        rawURL,
+		FinalURL:   finalURL,
+		StatusCode: response.StatusCode,
+		Title:      doc.Find("title").First().Text(),
+		FetchedAt:  time.Now(),
+	}
+
+	// 1. Padrões de URL
+	explanation.Patterns = PatternExplanation{
+		URLPatterns: matchPropertyURLPatterns(finalURL),
+		NumericSlug: hasNumericLastSegment(finalURL),
+		LearnedType: "unknown",
+	}
+	if learner != nil {
+		explanation.Patterns.LearnedType, explanation.Patterns.LearnedConfidence = learner.ClassifyURL(finalURL)
+	}
+
+	// 2. Heurísticas: os crawlers recebem o elemento html (recursivo) ou body (improved e integrado)
+	htmlElement := explainElement(response, doc, "html")
+	bodyElement := explainElement(response, doc, "body")
+
+	heuristics := &explanation.Heuristics
+	heuristics.Precise = ce.precise.ClassifyPage(&goquery.Document{Selection: htmlElement.DOM}, finalURL)
+	pageType := ce.strict.ClassifyPageStrict(bodyElement)
+	heuristics.StrictPageType = pageTypeName(pageType)
+	heuristics.ContentType, heuristics.ContentConfidence = ce.content.ClassifyPageContent(bodyElement)
+	heuristics.CalibratedConfidence = ce.calibrator.Calibrate(CalibrationSourceContent, heuristics.ContentConfidence)
+	heuristics.Indicators = findPropertyIndicators(bodyElement.Text)
+	heuristics.IndicatorsSayProperty = heuristics.Indicators.isProperty()
+
+	// 3. IA (mesmo conteúdo enviado pelo engine integrado)
+	var classification *ai.PageClassificationResult
+	if ce.pageAI != nil {
+		content, _ := bodyElement.DOM.Html()
+		result, err := ce.pageAI.ClassifyPageContent(ctx, finalURL, bodyElement.ChildText("title"), content)
+		if err != nil {
+			explanation.AI = &AIExplanation{Error: err.Error()}
+		} else {
+			calibrated := *result
+			calibrated.Confidence = ce.calibrator.Calibrate(CalibrationSourceAI, result.Confidence)
+			classification = &calibrated
+			explanation.AI = &AIExplanation{
+				IsPropertyPage:       result.IsPropertyPage,
+				Confidence:           result.Confidence,
+				CalibratedConfidence: calibrated.Confidence,
+				PageType:             result.PageType,
+				Reasoning:            result.Reasoning,
+				Indicators:           result.Indicators,
+			}
+		}
+	}
+
+	// 4. Decisões de cada engine
+	explanation.Decisions = []ClassificationDecision{
+		recursiveDecision(heuristics.Precise),
+		improvedDecision(pageType, heuristics.ContentType, heuristics.CalibratedConfidence),
+		aiIntegratedDecision(classification, heuristics.IndicatorsSayProperty),
+	}
+	for _, decision := range explanation.Decisions {
+		if decision.Engine == engine {
+			explanation.Decision = decision
+		}
+	}
+	return explanation
+}
+
+// fetch baixa a página seguindo redirecionamentos
+func (ce *ClassificationExplainer) fetch(ctx context.Context, rawURL string) (*colly.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %v", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; PropertyCrawler/1.0)")
+
+	resp, err := ce.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("failed to fetch page: status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, explainMaxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %v", err)
+	}
+
+	return &colly.Response{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Headers:    &resp.Header,
+		Request:    &colly.Request{URL: resp.Request.URL, Method: http.MethodGet},
+	}, nil
+}
+
+// explainElement monta o elemento recebido pelos callbacks OnHTML dos crawlers
+func explainElement(response *colly.Response, doc *goquery.Document, selector string) *colly.HTMLElement {
+	selection := doc.Find(selector).First()
+	if selection.Length() == 0 {
+		selection = doc.Selection
+	}
+	return colly.NewHTMLElementFromSelectionNode(response, selection, selection.Nodes[0], 0)
+}
+
+// recursiveDecision explica a decisão do SimpleRecursiveCrawler (classificador preciso)
+func recursiveDecision(result PreciseClassificationResult) ClassificationDecision {
+	action := pageActionCatalog // páginas que não são anúncio têm os links explorados
+	if result.IsIndividualProperty {
+		action = pageActionProperty
+	}
+	return ClassificationDecision{
+		Engine:     ExplainEngineRecursive,
+		IsProperty: result.IsIndividualProperty,
+		Action:     action,
+		Reason:     result.Reason,
+	}
+}
+
+// improvedDecision explica a decisão do ImprovedCrawler (classificador rigoroso e de conteúdo)
+func improvedDecision(pageType PageType, contentType string, contentConfidence float64) ClassificationDecision {
+	action := improvedPageAction(pageType, contentType, contentConfidence)
+	return ClassificationDecision{
+		Engine:     ExplainEngineImproved,
+		IsProperty: action == pageActionProperty,
+		Action:     action,
+		Reason: fmt.Sprintf("classificador rigoroso: %s; conteúdo: %s (%.2f, mínimo 0.70 sem o rigoroso)",
+			pageTypeName(pageType), contentType, contentConfidence),
+	}
+}
+
+// aiIntegratedDecision explica a decisão do AIIntegratedCrawler (IA com indicadores de reserva)
+func aiIntegratedDecision(classification *ai.PageClassificationResult, indicators bool) ClassificationDecision {
+	isProperty := aiPageDecision(classification, func() bool { return indicators })
+	decision := ClassificationDecision{
+		Engine:     ExplainEngineAIIntegrated,
+		IsProperty: isProperty,
+		Action:     pageActionSkip,
+	}
+	if isProperty {
+		decision.Action = pageActionProperty
+	}
+
+	switch {
+	case classification == nil:
+		decision.Reason = "sem classificação da IA a página não é processada"
+	case classification.Confidence > 0.8 || (classification.IsPropertyPage && classification.Confidence > 0.6):
+		decision.Reason = fmt.Sprintf("veredito da IA (propriedade=%v, confiança %.2f)", classification.IsPropertyPage, classification.Confidence)
+	case classification.Confidence < 0.7:
+		decision.Reason = fmt.Sprintf("IA com pouca confiança (%.2f); indicadores tradicionais: propriedade=%v", classification.Confidence, indicators)
+	default:
+		decision.Reason = fmt.Sprintf("IA não confirmou propriedade (confiança %.2f)", classification.Confidence)
+	}
+	return decision
+}
+
+// pageTypeName retorna o nome do tipo de página
+func pageTypeName(pageType PageType) string {
+	switch pageType {
+	case PageTypeProperty:
+		return "property"
+	case PageTypeCatalog:
+		return "catalog"
+	case PageTypeHome:
+		return "home"
+	case PageTypeContact:
+		return "contact"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,210 @@
+package crawler
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// maxExperimentSamples limita as divergências guardadas com URL no relatório do experimento
+const maxExperimentSamples = 100
+
+// ClassifierExperiment compara duas configurações do classificador preciso no mesmo crawling: a
+// atual (controle) decide todas as páginas, e uma fração amostrada é classificada também com os
+// limiares candidatos. As páginas aceitas só pela candidata passam por uma extração de teste, sem
+// gravar. As decisões divergentes e o resultado da extração de cada grupo de decisões mostram se
+// a mudança de limiar aceitaria anúncios perdidos ou páginas que não são anúncios.
+type ClassifierExperiment struct {
+	name      string
+	rate      float64
+	control   *PrecisePropertyClassifier
+	candidate *PrecisePropertyClassifier
+	random    func() float64
+
+	mutex       sync.Mutex
+	classified  int
+	sampled     int
+	divergences int
+	outcomes    map[string]map[string]int
+	awaiting    map[string]string // URL aceita pelo controle ou só pela candidata -> decisão, até o resultado da extração
+	samples     []repository.ClassifierDivergence
+	sampleIndex map[string]int
+}
+
+// NewClassifierExperiment cria o experimento com CLASSIFIER_EXPERIMENT_SAMPLE_RATE e os limiares
+// candidatos de CLASSIFIER_EXPERIMENT_MIN_CONFIDENCE e CLASSIFIER_EXPERIMENT_MIN_REQUIREMENTS. O
+// controle é o classificador usado pelo crawler; a candidata é uma cópia dele com os novos limiares
+func NewClassifierExperiment(cfg *config.Config, control *PrecisePropertyClassifier) (*ClassifierExperiment, error) {
+	if cfg.ClassifierExperimentSampleRate < 0 || cfg.ClassifierExperimentSampleRate > 1 {
+		return nil, fmt.Errorf("invalid classifier experiment sample rate %v (expected 0 to 1)", cfg.ClassifierExperimentSampleRate)
+	}
+	candidate := repository.ClassifierThresholds{
+		MinConfidence:   cfg.ClassifierExperimentMinConfidence,
+		MinRequirements: cfg.ClassifierExperimentMinRequirements,
+	}
+	if candidate.MinConfidence < 0 || candidate.MinConfidence > 1 {
+		return nil, fmt.Errorf("invalid classifier experiment min confidence %v (expected 0 to 1)", candidate.MinConfidence)
+	}
+	if candidate.MinRequirements < 0 {
+		return nil, fmt.Errorf("invalid classifier experiment min requirements %d", candidate.MinRequirements)
+	}
+
+	if control == nil {
+		control = NewPrecisePropertyClassifier()
+	}
+	if candidate == control.Thresholds() {
+		return nil, fmt.Errorf("classifier experiment candidate thresholds equal the current ones")
+	}
+	return &ClassifierExperiment{
+		name:        cfg.ClassifierExperimentName,
+		rate:        cfg.ClassifierExperimentSampleRate,
+		control:     control,
+		candidate:   control.WithThresholds(candidate),
+		random:      rand.Float64,
+		outcomes:    make(map[string]map[string]int),
+		awaiting:    make(map[string]string),
+		sampleIndex: make(map[string]int),
+	}, nil
+}
+
+// Classify classifica a página com a configuração atual e, nas páginas sorteadas, também com a
+// candidata. O resultado retornado é sempre o da configuração atual
+func (x *ClassifierExperiment) Classify(doc *goquery.Document, url string) PreciseClassificationResult {
+	result := x.control.ClassifyPage(doc, url)
+	sampled := x.random() < x.rate
+
+	var candidate PreciseClassificationResult
+	if sampled {
+		candidate = x.candidate.ClassifyPage(doc, url)
+	}
+
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	x.classified++
+	if !sampled {
+		return result
+	}
+	x.sampled++
+
+	decision := experimentDecision(result.IsIndividualProperty, candidate.IsIndividualProperty)
+	if decision == repository.ExperimentControlOnly || decision == repository.ExperimentCandidateOnly {
+		x.divergences++
+		if len(x.samples) < maxExperimentSamples {
+			x.sampleIndex[url] = len(x.samples)
+			x.samples = append(x.samples, repository.ClassifierDivergence{
+				URL:          url,
+				Domain:       repository.NormalizeDomain(url),
+				Decision:     decision,
+				Confidence:   result.Confidence,
+				Requirements: len(result.Details.FoundRequirements),
+				Outcome:      repository.ExperimentOutcomePending,
+				RecordedAt:   time.Now(),
+			})
+		}
+	}
+
+	// Páginas rejeitadas pelas duas configurações não são extraídas: o resultado já é conhecido
+	if result.IsIndividualProperty || candidate.IsIndividualProperty {
+		x.awaiting[url] = decision
+	} else {
+		x.recordOutcome(url, decision, repository.ExperimentOutcomeNotExtracted)
+	}
+	return result
+}
+
+// NeedsDryRun informa se a página foi aceita só pela candidata e aguarda a extração de teste
+func (x *ClassifierExperiment) NeedsDryRun(url string) bool {
+	if x == nil {
+		return false
+	}
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	return x.awaiting[url] == repository.ExperimentCandidateOnly
+}
+
+// RecordOutcome registra o resultado da extração de uma página aceita pela configuração atual, ou
+// da extração de teste de uma página aceita só pela candidata (ignorado fora da amostra)
+func (x *ClassifierExperiment) RecordOutcome(url, outcome string) {
+	if x == nil {
+		return
+	}
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	decision, ok := x.awaiting[url]
+	if !ok {
+		return
+	}
+	delete(x.awaiting, url)
+	x.recordOutcome(url, decision, outcome)
+}
+
+// recordOutcome contabiliza o resultado no grupo da decisão (chamado com o mutex travado)
+func (x *ClassifierExperiment) recordOutcome(url, decision, outcome string) {
+	if x.outcomes[decision] == nil {
+		x.outcomes[decision] = make(map[string]int)
+	}
+	x.outcomes[decision][outcome]++
+	if i, ok := x.sampleIndex[url]; ok {
+		x.samples[i].Outcome = outcome
+	}
+}
+
+// Report retorna o relatório do experimento; páginas aceitas sem resultado registrado contam como
+// pending (nil quando o experimento está desativado)
+func (x *ClassifierExperiment) Report() *repository.ClassifierExperimentReport {
+	if x == nil {
+		return nil
+	}
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	report := &repository.ClassifierExperimentReport{
+		Name:        x.name,
+		Control:     x.control.Thresholds(),
+		Candidate:   x.candidate.Thresholds(),
+		SampleRate:  x.rate,
+		Classified:  x.classified,
+		Sampled:     x.sampled,
+		Agreements:  x.sampled - x.divergences,
+		Divergences: x.divergences,
+		Outcomes:    make(map[string]map[string]int, len(x.outcomes)),
+		Samples:     append([]repository.ClassifierDivergence(nil), x.samples...),
+	}
+	if x.sampled > 0 {
+		report.DivergenceRate = float64(x.divergences) / float64(x.sampled)
+	}
+	for decision, outcomes := range x.outcomes {
+		report.Outcomes[decision] = make(map[string]int, len(outcomes))
+		for outcome, count := range outcomes {
+			report.Outcomes[decision][outcome] = count
+		}
+	}
+	for _, decision := range x.awaiting {
+		if report.Outcomes[decision] == nil {
+			report.Outcomes[decision] = make(map[string]int)
+		}
+		report.Outcomes[decision][repository.ExperimentOutcomePending]++
+	}
+	return report
+}
+
+// experimentDecision combina as decisões do controle e da candidata
+func experimentDecision(control, candidate bool) string {
+	switch {
+	case control && candidate:
+		return repository.ExperimentBothAccept
+	case control:
+		return repository.ExperimentControlOnly
+	case candidate:
+		return repository.ExperimentCandidateOnly
+	default:
+		return repository.ExperimentBothReject
+	}
+}
@@ -0,0 +1,283 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// Parâmetros do ajuste automático de paralelismo
+const (
+	defaultMainParallelism   = 2
+	defaultDetailParallelism = 1
+	concurrencyTuneSamples   = 10                       // respostas por domínio entre avaliações
+	concurrencySlotCtxKey    = "concurrency_slot_%s_%d" // por coletor e ID: requisições filhas compartilham o Ctx
+)
+
+// ConcurrencyConfig define o paralelismo de um coletor. Com AutoTune o paralelismo de cada domínio
+// começa no valor configurado, sobe um passo enquanto a taxa de erros e a latência média ficam
+// abaixo dos limites (e há requisições aguardando vaga) e cai pela metade quando pioram.
+type ConcurrencyConfig struct {
+	Parallelism       int            // requisições simultâneas por domínio (padrão 1)
+	DomainParallelism map[string]int // valores específicos por domínio
+	Delay             time.Duration  // pausa após cada resposta antes de liberar a vaga
+
+	AutoTune       bool
+	MaxParallelism int           // teto do ajuste automático
+	MaxErrorRate   float64       // fração de respostas com erro de rede, 429 ou 5xx
+	MaxLatency     time.Duration // latência média das respostas
+}
+
+// attachConcurrencyTuners aplica o paralelismo configurado aos coletores de listagem e de detalhes
+// (campos zerados mantêm os padrões: 2 e 1 requisições por domínio)
+func attachConcurrencyTuners(cfg *config.Config, mainCollector, detailCollector *colly.Collector, mainDelay, detailDelay time.Duration) (*ConcurrencyTuner, *ConcurrencyTuner, error) {
+	domains, err := ParseDomainParallelism(cfg.CrawlDomainParallelism)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := ConcurrencyConfig{
+		DomainParallelism: domains,
+		AutoTune:          cfg.CrawlAutoTune,
+		MaxParallelism:    cfg.CrawlAutoTuneMaxParallelism,
+		MaxErrorRate:      cfg.CrawlAutoTuneMaxErrorRate,
+		MaxLatency:        cfg.CrawlAutoTuneMaxLatency,
+	}
+
+	mainConfig, detailConfig := base, base
+	mainConfig.Parallelism, mainConfig.Delay = cfg.CrawlMainParallelism, mainDelay
+	detailConfig.Parallelism, detailConfig.Delay = cfg.CrawlDetailParallelism, detailDelay
+	if mainConfig.Parallelism <= 0 {
+		mainConfig.Parallelism = defaultMainParallelism
+	}
+	if detailConfig.Parallelism <= 0 {
+		detailConfig.Parallelism = defaultDetailParallelism
+	}
+
+	mainTuner, detailTuner := NewConcurrencyTuner("main", mainConfig), NewConcurrencyTuner("detail", detailConfig)
+	mainTuner.Attach(mainCollector)
+	detailTuner.Attach(detailCollector)
+	return mainTuner, detailTuner, nil
+}
+
+// ParseDomainParallelism interpreta a lista "dominio=n" (ex: CRAWL_DOMAIN_PARALLELISM)
+func ParseDomainParallelism(entries []string) (map[string]int, error) {
+	domains := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		domain, value, found := strings.Cut(entry, "=")
+		parallelism, err := strconv.Atoi(strings.TrimSpace(value))
+		if !found || strings.TrimSpace(domain) == "" || err != nil || parallelism < 1 {
+			return nil, fmt.Errorf("invalid domain parallelism %q (expected domain=n with n >= 1)", entry)
+		}
+		domains[repository.NormalizeDomain(strings.TrimSpace(domain))] = parallelism
+	}
+	return domains, nil
+}
+
+// domainConcurrency guarda o paralelismo e a janela de medições de um domínio
+type domainConcurrency struct {
+	limit       int
+	inFlight    int
+	samples     int
+	failures    int
+	latency     time.Duration
+	blocked     bool // alguma requisição aguardou vaga durante a janela
+	adjustments int
+	errorRate   float64       // da última janela avaliada
+	avgLatency  time.Duration // da última janela avaliada
+}
+
+// DomainConcurrencyStats expõe o paralelismo aplicado a um domínio por um coletor
+type DomainConcurrencyStats struct {
+	Collector    string  `json:"collector"`
+	Domain       string  `json:"domain"`
+	Parallelism  int     `json:"parallelism"`
+	InFlight     int     `json:"in_flight"`
+	ErrorRate    float64 `json:"error_rate"`
+	AvgLatencyMs int64   `json:"avg_latency_ms"`
+	Adjustments  int     `json:"adjustments"`
+}
+
+// ConcurrencyTuner limita as requisições simultâneas por domínio de um coletor e, com AutoTune,
+// ajusta o limite conforme a saúde das respostas
+type ConcurrencyTuner struct {
+	name    string
+	config  ConcurrencyConfig
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	domains map[string]*domainConcurrency
+}
+
+// NewConcurrencyTuner cria o limitador de um coletor (name identifica o coletor nas estatísticas)
+func NewConcurrencyTuner(name string, cfg ConcurrencyConfig) *ConcurrencyTuner {
+	if cfg.Parallelism < 1 {
+		cfg.Parallelism = 1
+	}
+	if cfg.MaxParallelism < cfg.Parallelism {
+		cfg.MaxParallelism = cfg.Parallelism
+	}
+	ct := &ConcurrencyTuner{
+		name:    name,
+		config:  cfg,
+		domains: make(map[string]*domainConcurrency),
+	}
+	ct.cond = sync.NewCond(&ct.mutex)
+	return ct
+}
+
+// Attach controla as requisições do coletor. Deve ser chamado antes de registrar os demais
+// callbacks, e o coletor não deve ter LimitRule com Delay (a pausa é aplicada pelo limitador
+// para que não entre na latência medida).
+func (ct *ConcurrencyTuner) Attach(c *colly.Collector) {
+	c.OnRequest(func(r *colly.Request) {
+		key := fmt.Sprintf(concurrencySlotCtxKey, ct.name, r.ID)
+		if held, _ := r.Ctx.GetAny(key).(time.Time); held.IsZero() {
+			ct.Acquire(r.URL.Host)
+			r.Ctx.Put(key, time.Now())
+		}
+	})
+	c.OnResponse(func(r *colly.Response) {
+		ct.releaseRequest(r.Request, isDegradedStatus(r.StatusCode))
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		ct.releaseRequest(r.Request, r.StatusCode == 0 || isDegradedStatus(r.StatusCode))
+	})
+}
+
+// releaseRequest registra a resposta da requisição, aguarda o Delay e libera a vaga
+func (ct *ConcurrencyTuner) releaseRequest(r *colly.Request, failed bool) {
+	if r == nil {
+		return
+	}
+	key := fmt.Sprintf(concurrencySlotCtxKey, ct.name, r.ID)
+	start, _ := r.Ctx.GetAny(key).(time.Time)
+	if start.IsZero() {
+		return // vaga já liberada (ex: OnError depois de OnResponse)
+	}
+	r.Ctx.Put(key, time.Time{})
+
+	latency := time.Since(start)
+	if ct.config.Delay > 0 {
+		time.Sleep(ct.config.Delay)
+	}
+	ct.Release(r.URL.Host, latency, failed)
+}
+
+// isDegradedStatus indica respostas que mostram o servidor sobrecarregado
+func isDegradedStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// Acquire aguarda uma vaga no domínio
+func (ct *ConcurrencyTuner) Acquire(host string) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	state := ct.domainState(repository.NormalizeDomain(host))
+	for state.inFlight >= state.limit {
+		state.blocked = true
+		ct.cond.Wait()
+	}
+	state.inFlight++
+}
+
+// Release libera a vaga do domínio e registra a latência e o resultado da requisição
+func (ct *ConcurrencyTuner) Release(host string, latency time.Duration, failed bool) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	state := ct.domainState(repository.NormalizeDomain(host))
+	if state.inFlight > 0 {
+		state.inFlight--
+	}
+	state.samples++
+	state.latency += latency
+	if failed {
+		state.failures++
+	}
+	if state.samples >= concurrencyTuneSamples {
+		ct.adjust(state)
+	}
+	ct.cond.Broadcast()
+}
+
+// adjust avalia a janela de medições do domínio; requer o mutex
+func (ct *ConcurrencyTuner) adjust(state *domainConcurrency) {
+	state.errorRate = float64(state.failures) / float64(state.samples)
+	state.avgLatency = state.latency / time.Duration(state.samples)
+
+	if ct.config.AutoTune {
+		degraded := state.errorRate > ct.config.MaxErrorRate ||
+			(ct.config.MaxLatency > 0 && state.avgLatency > ct.config.MaxLatency)
+		switch {
+		case degraded && state.limit > 1:
+			state.limit /= 2
+			state.adjustments++
+		case !degraded && state.blocked && state.limit < ct.config.MaxParallelism:
+			state.limit++
+			state.adjustments++
+		}
+	}
+
+	state.samples, state.failures, state.latency = 0, 0, 0
+	state.blocked = false
+}
+
+// domainState retorna (criando se necessário) o estado do domínio; requer o mutex
+func (ct *ConcurrencyTuner) domainState(domain string) *domainConcurrency {
+	state, exists := ct.domains[domain]
+	if !exists {
+		limit := ct.config.Parallelism
+		if configured, ok := ct.config.DomainParallelism[domain]; ok {
+			limit = configured
+		}
+		state = &domainConcurrency{limit: limit}
+		ct.domains[domain] = state
+	}
+	return state
+}
+
+// Stats retorna o paralelismo atual de cada domínio, ordenado pelo domínio
+func (ct *ConcurrencyTuner) Stats() []DomainConcurrencyStats {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	stats := make([]DomainConcurrencyStats, 0, len(ct.domains))
+	for domain, state := range ct.domains {
+		stats = append(stats, DomainConcurrencyStats{
+			Collector:    ct.name,
+			Domain:       domain,
+			Parallelism:  state.limit,
+			InFlight:     state.inFlight,
+			ErrorRate:    state.errorRate,
+			AvgLatencyMs: state.avgLatency.Milliseconds(),
+			Adjustments:  state.adjustments,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Domain < stats[j].Domain
+	})
+	return stats
+}
+
+// collectorConcurrency junta as estatísticas de paralelismo dos coletores
+func collectorConcurrency(tuners ...*ConcurrencyTuner) []DomainConcurrencyStats {
+	var stats []DomainConcurrencyStats
+	for _, tuner := range tuners {
+		if tuner != nil {
+			stats = append(stats, tuner.Stats()...)
+		}
+	}
+	return stats
+}
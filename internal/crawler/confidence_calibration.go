@@ -0,0 +1,279 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fontes de confiança calibradas separadamente
+const (
+	CalibrationSourcePattern = "pattern" // padrões de URL aprendidos (ReferencePatternTrainer.MatchURL)
+	CalibrationSourceAI      = "ai"      // classificação de páginas e links pelo Gemini
+	CalibrationSourceContent = "content" // heurística de conteúdo (ContentBasedPatternLearner)
+)
+
+// minCalibrationSamples é o mínimo de observações rotuladas para ajustar a curva de uma fonte;
+// com menos, as notas da fonte continuam sendo usadas sem calibração
+const minCalibrationSamples = 20
+
+// CalibrationPoint é um ponto da curva: nota original -> probabilidade de o veredito estar certo
+type CalibrationPoint struct {
+	Score       float64 `json:"score"`
+	Probability float64 `json:"probability"`
+}
+
+// CalibrationCurve é a curva monotônica (regressão isotônica) de uma fonte
+type CalibrationCurve struct {
+	Source   string             `json:"source"`
+	Samples  int                `json:"samples"`
+	Accuracy float64            `json:"accuracy"` // fração de vereditos certos nas observações
+	Points   []CalibrationPoint `json:"points"`
+}
+
+// CalibrationObservation é uma nota emitida durante o crawling: a fonte, a URL avaliada, a
+// confiança original e o veredito (é anúncio de imóvel)
+type CalibrationObservation struct {
+	Source     string  `json:"source"`
+	URL        string  `json:"url"`
+	Confidence float64 `json:"confidence"`
+	IsProperty bool    `json:"is_property"`
+}
+
+// calibrationFile é o formato do arquivo de curvas (CALIBRATION_FILE)
+type calibrationFile struct {
+	UpdatedAt time.Time                    `json:"updated_at"`
+	Curves    map[string]*CalibrationCurve `json:"curves"`
+}
+
+// ConfidenceCalibrator converte as notas de cada fonte para uma escala comum: a probabilidade,
+// medida em dados rotulados, de o veredito da fonte estar certo. Assim limiares como "> 0.7"
+// significam o mesmo para padrões, IA e heurísticas. Fontes sem curva mantêm a nota original.
+// Um calibrador nil é válido e não altera as notas.
+type ConfidenceCalibrator struct {
+	mutex        sync.RWMutex
+	curves       map[string]*CalibrationCurve
+	updatedAt    time.Time
+	recording    bool
+	observations []CalibrationObservation
+}
+
+// NewConfidenceCalibrator cria um calibrador sem curvas
+func NewConfidenceCalibrator() *ConfidenceCalibrator {
+	return &ConfidenceCalibrator{curves: make(map[string]*CalibrationCurve)}
+}
+
+// LoadConfidenceCalibrator lê as curvas do arquivo (arquivo inexistente resulta em calibrador vazio)
+func LoadConfidenceCalibrator(path string) (*ConfidenceCalibrator, error) {
+	calibrator := NewConfidenceCalibrator()
+	if path == "" {
+		return calibrator, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return calibrator, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calibration file: %v", err)
+	}
+
+	var file calibrationFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid calibration file: %v", err)
+	}
+	for source, curve := range file.Curves {
+		if curve != nil && len(curve.Points) > 0 {
+			calibrator.curves[source] = curve
+		}
+	}
+	calibrator.updatedAt = file.UpdatedAt
+	return calibrator, nil
+}
+
+// Save grava as curvas no arquivo (arquivo temporário + rename)
+func (cc *ConfidenceCalibrator) Save(path string) error {
+	cc.mutex.RLock()
+	file := calibrationFile{UpdatedAt: cc.updatedAt, Curves: cc.curves}
+	data, err := json.MarshalIndent(file, "", "  ")
+	cc.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Calibrate converte a confiança da fonte para a escala comum (interpolando a curva)
+func (cc *ConfidenceCalibrator) Calibrate(source string, confidence float64) float64 {
+	if cc == nil {
+		return confidence
+	}
+	cc.mutex.RLock()
+	curve := cc.curves[source]
+	cc.mutex.RUnlock()
+	if curve == nil {
+		return confidence
+	}
+	return curve.interpolate(confidence)
+}
+
+// Score registra a nota (se a gravação estiver ativa) e retorna a confiança calibrada
+func (cc *ConfidenceCalibrator) Score(source, url string, confidence float64, isProperty bool) float64 {
+	if cc == nil {
+		return confidence
+	}
+	cc.mutex.Lock()
+	if cc.recording {
+		cc.observations = append(cc.observations, CalibrationObservation{
+			Source:     source,
+			URL:        url,
+			Confidence: confidence,
+			IsProperty: isProperty,
+		})
+	}
+	cc.mutex.Unlock()
+	return cc.Calibrate(source, confidence)
+}
+
+// StartRecording passa a guardar as notas recebidas por Score (usado para coletar dados de calibração)
+func (cc *ConfidenceCalibrator) StartRecording() {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	cc.recording = true
+}
+
+// Observations retorna as notas registradas
+func (cc *ConfidenceCalibrator) Observations() []CalibrationObservation {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+	return append([]CalibrationObservation(nil), cc.observations...)
+}
+
+// Refit recalcula as curvas a partir das notas registradas e dos rótulos (URL -> é anúncio).
+// Notas de URLs sem rótulo são ignoradas; fontes com menos de minCalibrationSamples observações
+// mantêm a curva anterior. Retorna as fontes atualizadas.
+func (cc *ConfidenceCalibrator) Refit(labels func(url string) (bool, bool)) []string {
+	samples := make(map[string][]calibrationSample)
+	for _, observation := range cc.Observations() {
+		isProperty, labeled := labels(observation.URL)
+		if !labeled {
+			continue
+		}
+		samples[observation.Source] = append(samples[observation.Source], calibrationSample{
+			score:   observation.Confidence,
+			correct: observation.IsProperty == isProperty,
+		})
+	}
+
+	var updated []string
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	for source, sourceSamples := range samples {
+		if len(sourceSamples) < minCalibrationSamples {
+			continue
+		}
+		cc.curves[source] = fitCalibrationCurve(source, sourceSamples)
+		updated = append(updated, source)
+	}
+	if len(updated) > 0 {
+		cc.updatedAt = time.Now()
+	}
+	sort.Strings(updated)
+	return updated
+}
+
+// Curves retorna as curvas atuais ordenadas pela fonte
+func (cc *ConfidenceCalibrator) Curves() []CalibrationCurve {
+	cc.mutex.RLock()
+	defer cc.mutex.RUnlock()
+
+	curves := make([]CalibrationCurve, 0, len(cc.curves))
+	for _, curve := range cc.curves {
+		curves = append(curves, *curve)
+	}
+	sort.Slice(curves, func(i, j int) bool {
+		return curves[i].Source < curves[j].Source
+	})
+	return curves
+}
+
+// calibrationSample é uma observação rotulada: a nota e se o veredito estava certo
+type calibrationSample struct {
+	score   float64
+	correct bool
+}
+
+// fitCalibrationCurve ajusta a regressão isotônica (pool adjacent violators): a probabilidade de
+// acerto nunca diminui quando a nota aumenta
+func fitCalibrationCurve(source string, samples []calibrationSample) *CalibrationCurve {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].score < samples[j].score
+	})
+
+	type block struct {
+		scoreSum, correctSum, weight float64
+	}
+	var blocks []block
+	correct := 0
+	for _, sample := range samples {
+		value := 0.0
+		if sample.correct {
+			value = 1
+			correct++
+		}
+		blocks = append(blocks, block{scoreSum: sample.score, correctSum: value, weight: 1})
+		// Junta blocos enquanto a média do anterior for maior que a do atual
+		for len(blocks) > 1 {
+			last, previous := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if previous.correctSum/previous.weight <= last.correctSum/last.weight {
+				break
+			}
+			blocks = blocks[:len(blocks)-2]
+			blocks = append(blocks, block{
+				scoreSum:   previous.scoreSum + last.scoreSum,
+				correctSum: previous.correctSum + last.correctSum,
+				weight:     previous.weight + last.weight,
+			})
+		}
+	}
+
+	curve := &CalibrationCurve{
+		Source:   source,
+		Samples:  len(samples),
+		Accuracy: float64(correct) / float64(len(samples)),
+	}
+	for _, b := range blocks {
+		curve.Points = append(curve.Points, CalibrationPoint{
+			Score:       b.scoreSum / b.weight,
+			Probability: b.correctSum / b.weight,
+		})
+	}
+	return curve
+}
+
+// interpolate retorna a probabilidade da nota (linear entre os pontos, constante fora deles)
+func (curve *CalibrationCurve) interpolate(score float64) float64 {
+	points := curve.Points
+	if score <= points[0].Score {
+		return points[0].Probability
+	}
+	for i := 1; i < len(points); i++ {
+		if score <= points[i].Score {
+			low, high := points[i-1], points[i]
+			ratio := (score - low.Score) / (high.Score - low.Score)
+			return low.Probability + ratio*(high.Probability-low.Probability)
+		}
+	}
+	return points[len(points)-1].Probability
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/readability"
 )
 
 // ContentBasedClassifier classifica páginas baseado em padrões de conteúdo aprendidos
@@ -59,8 +60,9 @@ func (cbc *ContentBasedClassifier) ClassifyPage(doc *goquery.Document, url strin
 		},
 	}
 
-	// Extrair texto da página
-	pageText := strings.ToLower(doc.Text())
+	// Extrair texto do conteúdo principal (menus e rodapés com "apartamentos casas terrenos"
+	// distorceriam a contagem de palavras-chave)
+	pageText := strings.ToLower(readability.FromSelection(doc.Selection).Text)
 	words := strings.Fields(pageText)
 	result.Details.WordCount = len(words)
 
@@ -1,7 +1,6 @@
 package crawler
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -574,38 +573,69 @@ func (cpl *ContentBasedPatternLearner) GetLearnedPatterns() map[string][]*Conten
 	return result
 }
 
-// ExportPatterns exporta os padrões para JSON
+// contentPatternKind identifica os padrões de conteúdo pelo ID
+var contentPatternKind = patternKind[*ContentPattern]{
+	key: func(pattern *ContentPattern) string { return pattern.ID },
+	validate: func(group string, pattern *ContentPattern) error {
+		if pattern == nil {
+			return fmt.Errorf("empty pattern")
+		}
+		if pattern.ID == "" {
+			return fmt.Errorf("missing id")
+		}
+		if err := validatePatternType(group, pattern.Type); err != nil {
+			return err
+		}
+		return validateConfidence(pattern.Confidence)
+	},
+}
+
+// ExportPatterns exporta os padrões para JSON, com a versão do formato e o checksum
 func (cpl *ContentBasedPatternLearner) ExportPatterns() ([]byte, error) {
 	patterns := cpl.GetLearnedPatterns()
-	return json.MarshalIndent(patterns, "", "  ")
+	return encodePatternFile(contentPatternKind, patterns["catalog"], patterns["property"])
+}
+
+// PatternsChecksum retorna o checksum dos padrões ativos (o mesmo do arquivo exportado)
+func (cpl *ContentBasedPatternLearner) PatternsChecksum() string {
+	patterns := cpl.GetLearnedPatterns()
+	return patternsChecksum(contentPatternKind, patterns["catalog"], patterns["property"])
+}
+
+// PreviewImport valida o arquivo e mostra o que a importação mudaria, sem aplicá-la
+func (cpl *ContentBasedPatternLearner) PreviewImport(data []byte) (*PatternImportPreview, error) {
+	file, err := decodePatternFile(contentPatternKind, data)
+	if err != nil {
+		return nil, err
+	}
+	return previewPatternFile(contentPatternKind, file, cpl.GetLearnedPatterns(), true), nil
 }
 
-// ImportPatterns importa padrões de JSON
+// ImportPatterns importa padrões de JSON, substituindo os ativos; um arquivo inválido (versão,
+// checksum ou padrões) não altera nada
 func (cpl *ContentBasedPatternLearner) ImportPatterns(data []byte) error {
+	file, err := decodePatternFile(contentPatternKind, data)
+	if err != nil {
+		return err
+	}
+
 	cpl.mutex.Lock()
 	defer cpl.mutex.Unlock()
 
-	var importedData map[string][]*ContentPattern
-	if err := json.Unmarshal(data, &importedData); err != nil {
-		return fmt.Errorf("failed to unmarshal patterns: %w", err)
-	}
-
 	// Reconstrói os mapas de padrões
 	cpl.catalogPatterns = make(map[string]*ContentPattern)
 	cpl.propertyPatterns = make(map[string]*ContentPattern)
-
-	if catalogPatterns, ok := importedData["catalog"]; ok {
-		for _, pattern := range catalogPatterns {
-			cpl.catalogPatterns[pattern.ID] = pattern
-		}
+	for _, pattern := range file.Catalog {
+		cpl.catalogPatterns[pattern.ID] = pattern
 	}
-
-	if propertyPatterns, ok := importedData["property"]; ok {
-		for _, pattern := range propertyPatterns {
-			cpl.propertyPatterns[pattern.ID] = pattern
-		}
+	for _, pattern := range file.Property {
+		cpl.propertyPatterns[pattern.ID] = pattern
 	}
 
-	cpl.logger.Info("Content-based patterns imported successfully")
+	cpl.logger.WithFields(map[string]interface{}{
+		"catalog_patterns":  len(file.Catalog),
+		"property_patterns": len(file.Property),
+		"checksum":          file.Checksum,
+	}).Info("Content-based patterns imported successfully")
 	return nil
 }
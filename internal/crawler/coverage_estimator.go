@@ -0,0 +1,340 @@
+package crawler
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// CoverageEstimator estima quantos anúncios de cada domínio o crawling deixou de encontrar,
+// comparando as URLs de imóveis descobertas com o sitemap do site e com os totais
+// exibidos nos catálogos ("128 imóveis encontrados")
+type CoverageEstimator struct {
+	mutex          sync.Mutex
+	discovered     map[string]map[string]bool // domínio -> URLs de imóveis salvas
+	catalogTotals  map[string]map[string]int  // domínio -> catálogo (sem paginação) -> total exibido
+	totalPatterns  []*regexp.Regexp
+	propertyURL    *regexp.Regexp
+	propertyID     *regexp.Regexp
+	pageParams     *regexp.Regexp
+	httpClient     *http.Client
+	maxSitemaps    int
+	maxSitemapSize int64
+	logger         *logger.Logger
+}
+
+// sitemapDocument cobre tanto <urlset> quanto <sitemapindex>
+type sitemapDocument struct {
+	XMLName  xml.Name `xml:""`
+	URLs     []string `xml:"url>loc"`
+	Sitemaps []string `xml:"sitemap>loc"`
+}
+
+// NewCoverageEstimator cria um novo estimador de cobertura
+func NewCoverageEstimator() *CoverageEstimator {
+	return &CoverageEstimator{
+		discovered:    make(map[string]map[string]bool),
+		catalogTotals: make(map[string]map[string]int),
+		// Os totais exigem o substantivo da listagem, para não confundir com "área total de 360 m²"
+		totalPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)(\d{1,3}(?:\.\d{3})+|\d+)\s+(?:im[óo]veis|resultados|an[úu]ncios|registros|ofertas)\s+(?:encontrad|dispon[íi]ve|localizad)`),
+			regexp.MustCompile(`(?i)(?:encontramos|encontrados?|total\s+de|exibindo\s+\d+\s*-\s*\d+\s+de)\s*:?\s*(\d{1,3}(?:\.\d{3})+|\d+)\s+(?:im[óo]veis|resultados|an[úu]ncios)\b`),
+		},
+		propertyURL:    regexp.MustCompile(`(?i)/(imove(l|is)|im[oó]vel|casa|casas|apartamento|apto|terreno|lote|chacara|sitio|fazenda|sobrado|kitnet|sala|galpao|detalhe|anuncio|propriedade)`),
+		propertyID:     regexp.MustCompile(`(?i)(\d{3,}|[a-z]{1,4}\d{2,})`),
+		pageParams:     regexp.MustCompile(`(?i)([?&](page|pagina|pag|p|offset|start)=\d+|/(page|pagina)/\d+)`),
+		httpClient:     &http.Client{Timeout: 20 * time.Second, Transport: NewAuditedTransport(nil, "")},
+		maxSitemaps:    20,
+		maxSitemapSize: 20 << 20,
+		logger:         logger.NewLogger("coverage_estimator"),
+	}
+}
+
+// RecordPropertyURL registra uma URL de imóvel encontrada pelo crawling
+func (ce *CoverageEstimator) RecordPropertyURL(rawURL string) {
+	domain := coverageDomain(rawURL)
+	if domain == "" {
+		return
+	}
+
+	ce.mutex.Lock()
+	defer ce.mutex.Unlock()
+
+	if ce.discovered[domain] == nil {
+		ce.discovered[domain] = make(map[string]bool)
+	}
+	ce.discovered[domain][rawURL] = true
+}
+
+// RecordCatalogPage procura no texto da página o total de anúncios anunciado pelo site
+func (ce *CoverageEstimator) RecordCatalogPage(pageURL, text string) {
+	total := ce.ParseCatalogTotal(text)
+	if total <= 0 {
+		return
+	}
+
+	domain := coverageDomain(pageURL)
+	if domain == "" {
+		return
+	}
+
+	// Páginas 2, 3... do mesmo catálogo exibem o mesmo total
+	catalogKey := ce.pageParams.ReplaceAllString(pageURL, "")
+
+	ce.mutex.Lock()
+	defer ce.mutex.Unlock()
+
+	if ce.catalogTotals[domain] == nil {
+		ce.catalogTotals[domain] = make(map[string]int)
+	}
+	if total > ce.catalogTotals[domain][catalogKey] {
+		ce.catalogTotals[domain][catalogKey] = total
+	}
+}
+
+// ParseCatalogTotal extrai o total de anúncios de textos como "128 imóveis encontrados"
+func (ce *CoverageEstimator) ParseCatalogTotal(text string) int {
+	best := 0
+	for _, pattern := range ce.totalPatterns {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			value, err := strconv.Atoi(strings.ReplaceAll(match[1], ".", ""))
+			if err != nil || value <= 0 || value > 1000000 {
+				continue
+			}
+			if value > best {
+				best = value
+			}
+		}
+	}
+	return best
+}
+
+// IsPropertyURL verifica se uma URL do sitemap parece ser de um anúncio individual
+func (ce *CoverageEstimator) IsPropertyURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	path := parsed.Path
+	if parsed.RawQuery != "" {
+		path += "?" + parsed.RawQuery
+	}
+	return ce.propertyURL.MatchString(path) && ce.propertyID.MatchString(path) && !ce.pageParams.MatchString(path)
+}
+
+// CountSitemapPropertyURLs conta as URLs de imóveis nos sitemaps do domínio
+// (declarados no robots.txt ou em /sitemap.xml). Retorna -1 se não houver sitemap.
+func (ce *CoverageEstimator) CountSitemapPropertyURLs(ctx context.Context, baseURL string) (int, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Host == "" {
+		return -1, fmt.Errorf("URL base inválida: %s", baseURL)
+	}
+	root := base.Scheme + "://" + base.Host
+
+	queue := ce.sitemapsFromRobots(ctx, root)
+	if len(queue) == 0 {
+		queue = []string{root + "/sitemap.xml"}
+	}
+
+	seen := make(map[string]bool)
+	propertyURLs := make(map[string]bool)
+	found := false
+
+	for len(queue) > 0 && len(seen) < ce.maxSitemaps {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+		if seen[sitemapURL] {
+			continue
+		}
+		seen[sitemapURL] = true
+
+		doc, err := ce.fetchSitemap(ctx, sitemapURL)
+		if err != nil {
+			ce.logger.WithField("sitemap", sitemapURL).Debug("Sitemap not available: " + err.Error())
+			continue
+		}
+		found = true
+
+		queue = append(queue, doc.Sitemaps...)
+		for _, loc := range doc.URLs {
+			loc = strings.TrimSpace(loc)
+			if ce.IsPropertyURL(loc) {
+				propertyURLs[loc] = true
+			}
+		}
+	}
+
+	if !found {
+		return -1, nil
+	}
+	return len(propertyURLs), nil
+}
+
+// Report gera a estimativa de cobertura para cada domínio com imóveis descobertos
+// ou totais de catálogo registrados
+func (ce *CoverageEstimator) Report(ctx context.Context) []repository.DomainCoverage {
+	ce.mutex.Lock()
+	domains := make(map[string]bool)
+	discovered := make(map[string]int)
+	catalog := make(map[string]int)
+	sampleURL := make(map[string]string)
+
+	for domain, urls := range ce.discovered {
+		domains[domain] = true
+		discovered[domain] = len(urls)
+		for u := range urls {
+			sampleURL[domain] = u
+			break
+		}
+	}
+	for domain, totals := range ce.catalogTotals {
+		domains[domain] = true
+		for key, total := range totals {
+			// Catálogos com filtros são subconjuntos do catálogo geral: usa o maior total
+			if total > catalog[domain] {
+				catalog[domain] = total
+			}
+			if sampleURL[domain] == "" {
+				sampleURL[domain] = key
+			}
+		}
+	}
+	ce.mutex.Unlock()
+
+	var names []string
+	for domain := range domains {
+		names = append(names, domain)
+	}
+	sort.Strings(names)
+
+	reports := make([]repository.DomainCoverage, 0, len(names))
+	for _, domain := range names {
+		sitemapCount := -1
+		if sample := sampleURL[domain]; sample != "" {
+			if count, err := ce.CountSitemapPropertyURLs(ctx, sample); err == nil {
+				sitemapCount = count
+			}
+		}
+
+		report := EstimateCoverage(domain, discovered[domain], sitemapCount, catalog[domain])
+		reports = append(reports, report)
+
+		ce.logger.WithFields(map[string]interface{}{
+			"domain":             report.Domain,
+			"discovered":         report.DiscoveredURLs,
+			"sitemap_properties": report.SitemapPropertyURLs,
+			"catalog_total":      report.CatalogTotal,
+			"estimated_total":    report.EstimatedTotal,
+			"missed_percent":     report.EstimatedMissedPercent,
+		}).Info("Coverage estimated")
+	}
+
+	return reports
+}
+
+// EstimateCoverage combina as fontes disponíveis; sitemapCount < 0 indica sitemap ausente
+func EstimateCoverage(domain string, discovered, sitemapCount, catalogTotal int) repository.DomainCoverage {
+	report := repository.DomainCoverage{
+		Domain:              domain,
+		DiscoveredURLs:      discovered,
+		SitemapPropertyURLs: sitemapCount,
+		CatalogTotal:        catalogTotal,
+		GeneratedAt:         time.Now(),
+	}
+
+	estimated := discovered
+	if sitemapCount > 0 {
+		report.Sources = append(report.Sources, "sitemap")
+		if sitemapCount > estimated {
+			estimated = sitemapCount
+		}
+	}
+	if catalogTotal > 0 {
+		report.Sources = append(report.Sources, "catalog_total")
+		if catalogTotal > estimated {
+			estimated = catalogTotal
+		}
+	}
+	report.EstimatedTotal = estimated
+
+	if estimated > 0 && len(report.Sources) > 0 {
+		missed := float64(estimated-discovered) / float64(estimated) * 100
+		report.EstimatedMissedPercent = math.Round(missed*10) / 10
+	}
+
+	return report
+}
+
+// sitemapsFromRobots lê as diretivas "Sitemap:" do robots.txt
+func (ce *CoverageEstimator) sitemapsFromRobots(ctx context.Context, root string) []string {
+	body, err := ce.fetch(ctx, root+"/robots.txt")
+	if err != nil {
+		return nil
+	}
+
+	var sitemaps []string
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > 8 && strings.EqualFold(line[:8], "sitemap:") {
+			if loc := strings.TrimSpace(line[8:]); loc != "" {
+				sitemaps = append(sitemaps, loc)
+			}
+		}
+	}
+	return sitemaps
+}
+
+// fetchSitemap baixa e decodifica um sitemap XML
+func (ce *CoverageEstimator) fetchSitemap(ctx context.Context, sitemapURL string) (*sitemapDocument, error) {
+	body, err := ce.fetch(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar sitemap: %v", err)
+	}
+	return &doc, nil
+}
+
+func (ce *CoverageEstimator) fetch(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ce.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, ce.maxSitemapSize))
+}
+
+// coverageDomain normaliza o domínio de uma URL para agrupar a cobertura
+func coverageDomain(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return repository.NormalizeDomain(parsed.Host)
+}
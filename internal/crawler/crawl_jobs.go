@@ -0,0 +1,640 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// JobControl permite cancelar um crawling e pausar domínios específicos durante a execução.
+// As requisições em andamento terminam normalmente; apenas novas visitas são bloqueadas.
+type JobControl struct {
+	jobID     string
+	tags      []string
+	cancelled int32
+	mutex     sync.RWMutex
+	paused    map[string]bool
+	deferred  map[string][]string // domínio -> URLs adiadas enquanto pausado
+
+	// Orçamento do job (templates): novas visitas param ao atingir o limite de páginas ou o prazo
+	maxPages int64
+	visits   int64
+	deadline time.Time
+
+	// Consumo do job para a estimativa de custo (GET /crawler/costs)
+	requests      int64
+	bytes         int64
+	proxyRequests int64
+	proxyBytes    int64
+	saved         int64
+
+	// Consumo da IA feito com os contextos do job (WithAIUsage) e se o job roda com IA
+	aiUsage   ai.UsageTracker
+	aiEnabled int32
+}
+
+// JobUsage é o consumo de rede e o resultado de um job, base da estimativa de custo
+type JobUsage struct {
+	Requests      int64
+	Bytes         int64
+	ProxyRequests int64
+	ProxyBytes    int64
+	Properties    int64
+	AIEnabled     bool
+	AI            ai.UsageStats
+}
+
+// Motivos de encerramento antecipado por orçamento do job
+const (
+	BudgetMaxPages    = "max_pages"
+	BudgetMaxDuration = "max_duration"
+)
+
+// NewJobControl cria um controle sem domínios pausados
+func NewJobControl() *JobControl {
+	return &JobControl{
+		paused:   make(map[string]bool),
+		deferred: make(map[string][]string),
+	}
+}
+
+// JobID retorna o identificador do job controlado (vazio fora de um job registrado)
+func (jc *JobControl) JobID() string {
+	return jc.jobID
+}
+
+// Tags retorna as tags do job, registradas nos imóveis salvos (nil fora de um job)
+func (jc *JobControl) Tags() []string {
+	if jc == nil {
+		return nil
+	}
+	return jc.tags
+}
+
+// Cancel impede novas visitas no job
+func (jc *JobControl) Cancel() {
+	atomic.StoreInt32(&jc.cancelled, 1)
+}
+
+// Cancelled informa se o job foi cancelado
+func (jc *JobControl) Cancelled() bool {
+	return atomic.LoadInt32(&jc.cancelled) == 1
+}
+
+// SetBudget limita o job a maxPages visitas e à duração informada a partir de agora (0 sem limite);
+// chamar antes de iniciar o crawling
+func (jc *JobControl) SetBudget(maxPages int, maxDuration time.Duration) {
+	atomic.StoreInt64(&jc.maxPages, int64(maxPages))
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+	jc.deadline = time.Time{}
+	if maxDuration > 0 {
+		jc.deadline = time.Now().Add(maxDuration)
+	}
+}
+
+// RecordVisit contabiliza uma página agendada para visita no orçamento do job
+func (jc *JobControl) RecordVisit() {
+	atomic.AddInt64(&jc.visits, 1)
+}
+
+// Visits retorna quantas páginas o job agendou para visita
+func (jc *JobControl) Visits() int {
+	return int(atomic.LoadInt64(&jc.visits))
+}
+
+// RecordResponse contabiliza uma resposta recebida (ou falha) e os bytes baixados; proxied
+// indica que a requisição saiu por um proxy
+func (jc *JobControl) RecordResponse(bytes int, proxied bool) {
+	atomic.AddInt64(&jc.requests, 1)
+	atomic.AddInt64(&jc.bytes, int64(bytes))
+	if proxied {
+		atomic.AddInt64(&jc.proxyRequests, 1)
+		atomic.AddInt64(&jc.proxyBytes, int64(bytes))
+	}
+}
+
+// RecordSaved contabiliza um imóvel salvo pelo job
+func (jc *JobControl) RecordSaved() {
+	atomic.AddInt64(&jc.saved, 1)
+}
+
+// SetAIEnabled registra se o job roda com o serviço de IA disponível
+func (jc *JobControl) SetAIEnabled(enabled bool) {
+	if jc == nil {
+		return
+	}
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&jc.aiEnabled, value)
+}
+
+// WithAIUsage retorna um contexto cujas chamadas à IA são contabilizadas no job; sem job, o
+// contexto é retornado sem mudança
+func (jc *JobControl) WithAIUsage(ctx context.Context) context.Context {
+	if jc == nil {
+		return ctx
+	}
+	return ai.WithUsageTracker(ctx, &jc.aiUsage)
+}
+
+// Usage retorna o consumo acumulado do job
+func (jc *JobControl) Usage() JobUsage {
+	return JobUsage{
+		Requests:      atomic.LoadInt64(&jc.requests),
+		Bytes:         atomic.LoadInt64(&jc.bytes),
+		ProxyRequests: atomic.LoadInt64(&jc.proxyRequests),
+		ProxyBytes:    atomic.LoadInt64(&jc.proxyBytes),
+		Properties:    atomic.LoadInt64(&jc.saved),
+		AIEnabled:     atomic.LoadInt32(&jc.aiEnabled) == 1,
+		AI:            jc.aiUsage.Stats(),
+	}
+}
+
+// BudgetExhausted retorna o orçamento esgotado (BudgetMaxPages ou BudgetMaxDuration), ou vazio
+func (jc *JobControl) BudgetExhausted() string {
+	if maxPages := atomic.LoadInt64(&jc.maxPages); maxPages > 0 && atomic.LoadInt64(&jc.visits) >= maxPages {
+		return BudgetMaxPages
+	}
+	jc.mutex.RLock()
+	defer jc.mutex.RUnlock()
+	if !jc.deadline.IsZero() && time.Now().After(jc.deadline) {
+		return BudgetMaxDuration
+	}
+	return ""
+}
+
+// PauseDomain bloqueia novas visitas ao domínio
+func (jc *JobControl) PauseDomain(domain string) {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+	jc.paused[repository.NormalizeDomain(domain)] = true
+}
+
+// ResumeDomain libera novamente o domínio; URLs adiadas voltam a ficar disponíveis
+func (jc *JobControl) ResumeDomain(domain string) {
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+	delete(jc.paused, repository.NormalizeDomain(domain))
+}
+
+// PausedDomains retorna os domínios pausados em ordem alfabética
+func (jc *JobControl) PausedDomains() []string {
+	jc.mutex.RLock()
+	defer jc.mutex.RUnlock()
+
+	domains := make([]string, 0, len(jc.paused))
+	for domain := range jc.paused {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// AllowVisit decide se a URL pode ser visitada agora. URLs de domínios pausados
+// são guardadas para serem visitadas quando o domínio for retomado.
+func (jc *JobControl) AllowVisit(rawURL string) bool {
+	if jc.Cancelled() || jc.BudgetExhausted() != "" {
+		return false
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return true
+	}
+	domain := repository.NormalizeDomain(parsed.Host)
+
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	if !jc.paused[domain] {
+		return true
+	}
+
+	for _, deferred := range jc.deferred[domain] {
+		if deferred == rawURL {
+			return false
+		}
+	}
+	jc.deferred[domain] = append(jc.deferred[domain], rawURL)
+	return false
+}
+
+// TakeResumable retorna (e remove) as URLs adiadas de domínios que não estão mais pausados
+func (jc *JobControl) TakeResumable() []string {
+	if jc.Cancelled() || jc.BudgetExhausted() != "" {
+		return nil
+	}
+
+	jc.mutex.Lock()
+	defer jc.mutex.Unlock()
+
+	var urls []string
+	for domain, deferred := range jc.deferred {
+		if jc.paused[domain] {
+			continue
+		}
+		urls = append(urls, deferred...)
+		delete(jc.deferred, domain)
+	}
+	return urls
+}
+
+// DeferredCount retorna quantas URLs aguardam a retomada de algum domínio
+func (jc *JobControl) DeferredCount() int {
+	jc.mutex.RLock()
+	defer jc.mutex.RUnlock()
+
+	total := 0
+	for _, deferred := range jc.deferred {
+		total += len(deferred)
+	}
+	return total
+}
+
+// CrawlJobManager gerencia os jobs de crawling disparados pela API
+type CrawlJobManager struct {
+	jobs     map[string]*repository.CrawlJob
+	controls map[string]*JobControl
+	sequence int64
+	mutex    sync.RWMutex
+	logger   *logger.Logger
+
+	// Chaves de idempotência (Idempotency-Key) dos disparos; em memória até SetIdempotencyStore
+	idempotency      repository.IdempotencyKeyRepository
+	idempotencyMutex sync.Mutex
+}
+
+// Erros do controle de jobs e dos disparos idempotentes
+var (
+	// ErrCrawlJobNotFound indica um job desconhecido (ou já removido por CleanupOldJobs)
+	ErrCrawlJobNotFound = errors.New("crawl job not found")
+	// ErrCrawlJobFinished indica um job que já terminou e não aceita mais cancelamento ou pausas
+	ErrCrawlJobFinished = errors.New("crawl job already finished")
+
+	// ErrIdempotencyKeyReused indica uma chave de idempotência repetida com outros parâmetros
+	ErrIdempotencyKeyReused = errors.New("idempotency key already used with different parameters")
+	// ErrIdempotencyKeyInProgress indica uma chave cujo job ainda está sendo criado por outra réplica
+	ErrIdempotencyKeyInProgress = errors.New("idempotency key in use by a request in progress")
+)
+
+// NewCrawlJobManager cria um novo gerenciador de jobs de crawling
+func NewCrawlJobManager() *CrawlJobManager {
+	return &CrawlJobManager{
+		jobs:        make(map[string]*repository.CrawlJob),
+		controls:    make(map[string]*JobControl),
+		logger:      logger.NewLogger("crawl_job_manager"),
+		idempotency: newMemoryIdempotencyStore(),
+	}
+}
+
+// SetIdempotencyStore troca o armazenamento das chaves de idempotência; com o MongoDB as chaves
+// valem para todas as réplicas e sobrevivem a reinícios
+func (m *CrawlJobManager) SetIdempotencyStore(store repository.IdempotencyKeyRepository) {
+	if store == nil {
+		return
+	}
+	m.idempotencyMutex.Lock()
+	defer m.idempotencyMutex.Unlock()
+	m.idempotency = store
+}
+
+// CreateOnce executa create apenas na primeira vez que a chave aparece dentro da janela; uma
+// chave repetida retorna o job original (replayed=true), desde que o fingerprint dos parâmetros
+// seja o mesmo. A chave é reservada antes de criar o job, então duas réplicas não criam jobs
+// para a mesma chave
+func (m *CrawlJobManager) CreateOnce(key, fingerprint string, window time.Duration, create func() (*repository.CrawlJob, error)) (job *repository.CrawlJob, replayed bool, err error) {
+	m.idempotencyMutex.Lock()
+	defer m.idempotencyMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	record := repository.IdempotencyKeyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(window),
+	}
+	existing, err := m.idempotency.ReserveIdempotencyKey(ctx, record)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return m.replay(existing, fingerprint)
+	}
+
+	job, err = create()
+	if err != nil {
+		if releaseErr := m.idempotency.ReleaseIdempotencyKey(ctx, key); releaseErr != nil {
+			m.logger.WithError(releaseErr).Warn("Failed to release idempotency key")
+		}
+		return nil, false, err
+	}
+	if err := m.idempotency.CompleteIdempotencyKey(ctx, key, *job); err != nil {
+		m.logger.WithError(err).Warn("Failed to record job for idempotency key")
+	}
+	return job, false, nil
+}
+
+// replay retorna o job de uma chave já usada: o estado atual quando o job é desta réplica, senão
+// o job como foi criado
+func (m *CrawlJobManager) replay(existing *repository.IdempotencyKeyRecord, fingerprint string) (*repository.CrawlJob, bool, error) {
+	if existing.Fingerprint != fingerprint {
+		return nil, false, ErrIdempotencyKeyReused
+	}
+	if existing.JobID == "" {
+		return nil, false, ErrIdempotencyKeyInProgress
+	}
+	if original := m.GetJob(existing.JobID); original != nil {
+		return original, true, nil
+	}
+	if existing.Job != nil {
+		job := *existing.Job
+		return &job, true, nil
+	}
+	return nil, false, ErrIdempotencyKeyInProgress
+}
+
+// memoryIdempotencyStore guarda as chaves de idempotência em memória, quando não há store persistente
+type memoryIdempotencyStore struct {
+	keys  map[string]repository.IdempotencyKeyRecord
+	mutex sync.Mutex
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: make(map[string]repository.IdempotencyKeyRecord)}
+}
+
+func (s *memoryIdempotencyStore) ReserveIdempotencyKey(ctx context.Context, record repository.IdempotencyKeyRecord) (*repository.IdempotencyKeyRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, existing := range s.keys {
+		abandoned := existing.JobID == "" && now.Sub(existing.CreatedAt) >= repository.IdempotencyReservationTimeout
+		if !existing.ExpiresAt.After(now) || abandoned {
+			delete(s.keys, key)
+		}
+	}
+	if existing, ok := s.keys[record.Key]; ok {
+		return &existing, nil
+	}
+	s.keys[record.Key] = record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) CompleteIdempotencyKey(ctx context.Context, key string, job repository.CrawlJob) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if record, ok := s.keys[key]; ok {
+		record.JobID = job.ID
+		record.Job = &job
+		s.keys[key] = record
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if record, ok := s.keys[key]; ok && record.JobID == "" {
+		delete(s.keys, key)
+	}
+	return nil
+}
+
+// CreateJob registra um novo job e retorna o controle a ser passado ao engine; as tags são
+// registradas em cada imóvel salvo pelo job
+func (m *CrawlJobManager) CreateJob(cities []string, mode string, tags []string) (*repository.CrawlJob, *JobControl) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.sequence++
+	job := &repository.CrawlJob{
+		ID:        fmt.Sprintf("crawl-%d-%d", time.Now().Unix(), m.sequence),
+		Cities:    cities,
+		Mode:      mode,
+		Tags:      tags,
+		Status:    repository.CrawlJobQueued,
+		StartedAt: time.Now(),
+	}
+	control := NewJobControl()
+	control.jobID = job.ID
+	control.tags = tags
+
+	m.jobs[job.ID] = job
+	m.controls[job.ID] = control
+
+	return m.snapshot(job), control
+}
+
+// CreateTemplateJob registra um job com os parâmetros de um template: cidades, domínios, tags e
+// o orçamento de páginas e de tempo, contado a partir da criação
+func (m *CrawlJobManager) CreateTemplateJob(template repository.CrawlJobTemplate) (*repository.CrawlJob, *JobControl) {
+	_, control := m.CreateJob(template.Cities, template.Mode, template.Tags)
+	control.SetBudget(template.MaxPages, template.MaxDuration())
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job := m.jobs[control.jobID]
+	job.Template = template.Name
+	job.Domains = template.Domains
+	job.MaxPages = template.MaxPages
+	job.MaxDurationMinutes = template.MaxMinutes
+	return m.snapshot(job), control
+}
+
+// MarkRunning marca o job como em execução
+func (m *CrawlJobManager) MarkRunning(jobID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if job, exists := m.jobs[jobID]; exists && job.Status == repository.CrawlJobQueued {
+		job.Status = repository.CrawlJobRunning
+	}
+}
+
+// MarkFinished registra o término do job, respeitando um cancelamento solicitado
+func (m *CrawlJobManager) MarkFinished(jobID string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job, exists := m.jobs[jobID]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	job.CompletedAt = &now
+	job.VisitedPages = m.controls[jobID].Visits()
+	job.BudgetExhausted = m.controls[jobID].BudgetExhausted()
+
+	switch {
+	case m.controls[jobID].Cancelled():
+		job.Status = repository.CrawlJobCancelled
+	case err != nil:
+		job.Status = repository.CrawlJobFailed
+		job.Error = err.Error()
+	default:
+		job.Status = repository.CrawlJobCompleted
+	}
+}
+
+// Control retorna o controle de cancelamento/pausa do job (nil se não existir)
+func (m *CrawlJobManager) Control(jobID string) *JobControl {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.controls[jobID]
+}
+
+// SetCost anexa ao job a estimativa de custo da execução
+func (m *CrawlJobManager) SetCost(jobID string, cost *repository.CrawlJobCost) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if job, exists := m.jobs[jobID]; exists {
+		job.Cost = cost
+	}
+}
+
+// SetCoverage anexa ao job a estimativa de cobertura por domínio
+func (m *CrawlJobManager) SetCoverage(jobID string, coverage []repository.DomainCoverage) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if job, exists := m.jobs[jobID]; exists {
+		job.Coverage = coverage
+	}
+}
+
+// SetClassifierExperiment anexa ao job o relatório do experimento A/B do classificador
+func (m *CrawlJobManager) SetClassifierExperiment(jobID string, report *repository.ClassifierExperimentReport) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if job, exists := m.jobs[jobID]; exists {
+		job.ClassifierExperiment = report
+	}
+}
+
+// GetJob retorna uma cópia do job com o estado atual de pausa
+func (m *CrawlJobManager) GetJob(jobID string) *repository.CrawlJob {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	job, exists := m.jobs[jobID]
+	if !exists {
+		return nil
+	}
+	return m.snapshot(job)
+}
+
+// GetJobs retorna todos os jobs, do mais recente para o mais antigo
+func (m *CrawlJobManager) GetJobs() []*repository.CrawlJob {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	jobs := make([]*repository.CrawlJob, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, m.snapshot(job))
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs
+}
+
+// CancelJob solicita o cancelamento: novas visitas param e as em andamento terminam
+func (m *CrawlJobManager) CancelJob(jobID string) (*repository.CrawlJob, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job, exists := m.jobs[jobID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCrawlJobNotFound, jobID)
+	}
+	if job.IsFinished() {
+		return nil, fmt.Errorf("%w: %s", ErrCrawlJobFinished, job.Status)
+	}
+
+	m.controls[jobID].Cancel()
+	job.Status = repository.CrawlJobCancelling
+
+	m.logger.WithField("job_id", jobID).Info("Crawl job cancellation requested")
+	return m.snapshot(job), nil
+}
+
+// PauseDomain pausa (ou retoma, com resume=true) um domínio dentro do job
+func (m *CrawlJobManager) PauseDomain(jobID, domain string, resume bool) (*repository.CrawlJob, error) {
+	normalized := repository.NormalizeDomain(domain)
+	if normalized == "" {
+		return nil, fmt.Errorf("invalid domain: %s", domain)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	job, exists := m.jobs[jobID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCrawlJobNotFound, jobID)
+	}
+	if job.IsFinished() {
+		return nil, fmt.Errorf("%w: %s", ErrCrawlJobFinished, job.Status)
+	}
+
+	if resume {
+		m.controls[jobID].ResumeDomain(normalized)
+	} else {
+		m.controls[jobID].PauseDomain(normalized)
+	}
+
+	m.logger.WithFields(map[string]interface{}{
+		"job_id": jobID,
+		"domain": normalized,
+		"resume": resume,
+	}).Info("Crawl job domain state changed")
+
+	return m.snapshot(job), nil
+}
+
+// CleanupOldJobs remove jobs finalizados há mais tempo que maxAge
+func (m *CrawlJobManager) CleanupOldJobs(maxAge time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	cutoff := time.Now().Add(-maxAge)
+	for jobID, job := range m.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			delete(m.jobs, jobID)
+			delete(m.controls, jobID)
+		}
+	}
+}
+
+// snapshot copia o job preenchendo o estado atual do controle (chamar com o mutex adquirido)
+func (m *CrawlJobManager) snapshot(job *repository.CrawlJob) *repository.CrawlJob {
+	copied := *job
+	if control, exists := m.controls[job.ID]; exists {
+		copied.PausedDomains = control.PausedDomains()
+		copied.DeferredURLs = control.DeferredCount()
+		if !job.IsFinished() {
+			copied.VisitedPages = control.Visits()
+			copied.BudgetExhausted = control.BudgetExhausted()
+		}
+	}
+	return &copied
+}
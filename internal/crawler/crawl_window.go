@@ -0,0 +1,101 @@
+package crawler
+
+import (
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// CrawlWindowGate aplica as janelas de crawling por horário do dia (campo crawl_windows da
+// configuração do domínio): fora da janela as requisições ao domínio são canceladas e contadas,
+// ficando para um crawling dentro da janela
+type CrawlWindowGate struct {
+	mutex    sync.Mutex
+	deferred map[string]int       // domínio -> requisições adiadas
+	next     map[string]time.Time // domínio -> início da próxima janela
+	now      func() time.Time
+}
+
+// NewCrawlWindowGate cria o controle de janelas de crawling
+func NewCrawlWindowGate() *CrawlWindowGate {
+	return &CrawlWindowGate{
+		deferred: make(map[string]int),
+		next:     make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Open indica se o domínio da URL está dentro da sua janela de crawling (snapshot pode ser nil)
+func (g *CrawlWindowGate) Open(rawURL string, snapshot *config.RuntimeSnapshot) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return true
+	}
+	domainConfig, exists := snapshot.DomainConfig(parsed.Hostname())
+	if !exists || len(domainConfig.CrawlWindows) == 0 {
+		return true
+	}
+
+	now := g.now()
+	if domainConfig.InCrawlWindow(now) {
+		return true
+	}
+
+	domain := repository.NormalizeDomain(parsed.Hostname())
+	g.mutex.Lock()
+	g.deferred[domain]++
+	g.next[domain] = domainConfig.NextCrawlWindow(now)
+	g.mutex.Unlock()
+	return false
+}
+
+// CrawlWindowDeferral resume as requisições adiadas de um domínio fora da janela de crawling
+type CrawlWindowDeferral struct {
+	Domain     string    `json:"domain"`
+	Requests   int       `json:"requests"`
+	NextWindow time.Time `json:"next_window"`
+}
+
+// Take retorna e zera as requisições adiadas, do domínio com mais requisições ao com menos
+func (g *CrawlWindowGate) Take() []CrawlWindowDeferral {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	deferrals := make([]CrawlWindowDeferral, 0, len(g.deferred))
+	for domain, count := range g.deferred {
+		deferrals = append(deferrals, CrawlWindowDeferral{Domain: domain, Requests: count, NextWindow: g.next[domain]})
+	}
+	g.deferred = make(map[string]int)
+	g.next = make(map[string]time.Time)
+
+	sort.Slice(deferrals, func(i, j int) bool {
+		if deferrals[i].Requests != deferrals[j].Requests {
+			return deferrals[i].Requests > deferrals[j].Requests
+		}
+		return deferrals[i].Domain < deferrals[j].Domain
+	})
+	return deferrals
+}
+
+// InCrawlWindow indica se a URL pode ser requisitada agora pelas janelas de crawling do domínio
+func (rs *RuntimeState) InCrawlWindow(rawURL string) bool {
+	return rs.windows.Open(rawURL, rs.Snapshot())
+}
+
+// logCrawlWindowDeferrals registra, ao final do crawling, os domínios adiados por estarem fora da
+// janela de crawling
+func logCrawlWindowDeferrals(log *logger.Logger, runtime *RuntimeState) {
+	deferrals := runtime.windows.Take()
+	for _, deferral := range deferrals {
+		log.WithFields(map[string]interface{}{
+			"domain":      deferral.Domain,
+			"requests":    deferral.Requests,
+			"next_window": deferral.NextWindow.Format(time.RFC3339),
+		}).Info("Requests deferred outside the domain crawl window")
+	}
+}
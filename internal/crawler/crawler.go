@@ -2,10 +2,8 @@ package crawler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -14,6 +12,7 @@ import (
 	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
 	"github.com/dujoseaugusto/go-crawler-project/internal/config"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
 	"github.com/gocolly/colly"
 	"github.com/gocolly/colly/extensions"
 )
@@ -24,10 +23,12 @@ type Crawler struct {
 	aiService *ai.GeminiService
 }
 
-func NewCrawler(ctx context.Context, cfg *config.Config) *Crawler {
+// NewCrawler cria o crawler tradicional; falhas de conexão com o banco retornam
+// repository.ErrStorageUnavailable e a ausência de IA (ai.ErrAIUnavailable) apenas desativa a IA
+func NewCrawler(ctx context.Context, cfg *config.Config) (*Crawler, error) {
 	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
 	if err != nil {
-		log.Fatalf("Failed to create MongoDB repository: %v", err)
+		return nil, fmt.Errorf("failed to create MongoDB repository: %w", err)
 	}
 
 	// Inicializar o serviço de IA
@@ -38,17 +39,18 @@ func NewCrawler(ctx context.Context, cfg *config.Config) *Crawler {
 		return &Crawler{
 			config: cfg,
 			repo:   repo,
-		}
+		}, nil
 	}
 
 	return &Crawler{
 		config:    cfg,
 		repo:      repo,
 		aiService: aiService,
-	}
+	}, nil
 }
 
 func (c *Crawler) StartCrawling(ctx context.Context) error {
+	log.Printf("Warning: %s", SitesFileDeprecationWarning)
 	urls, err := LoadURLsFromFile(c.config.SitesFile)
 	if err != nil {
 		return fmt.Errorf("failed to load URLs: %v", err)
@@ -58,32 +60,6 @@ func (c *Crawler) StartCrawling(ctx context.Context) error {
 	return nil
 }
 
-// Extrai valor numérico de uma string (ex: "R$ 500.000,00" -> 500000.00)
-func extractValue(valorStr string) float64 {
-	// Remove caracteres não numéricos exceto ponto e vírgula
-	re := regexp.MustCompile(`[^\d,.]`)
-	cleaned := re.ReplaceAllString(valorStr, "")
-
-	// Procura por padrões de valores monetários
-	reValue := regexp.MustCompile(`(\d{1,3}(?:\.\d{3})*(?:,\d{1,2})?)|\d+`)
-	matches := reValue.FindString(cleaned)
-
-	if matches == "" {
-		return 0
-	}
-
-	// Converte vírgula para ponto (padrão brasileiro para internacional)
-	matches = strings.Replace(matches, ".", "", -1)
-	matches = strings.Replace(matches, ",", ".", 1)
-
-	value, err := strconv.ParseFloat(matches, 64)
-	if err != nil {
-		return 0
-	}
-
-	return value
-}
-
 // Extrai CEP de uma string
 func extractCEP(str string) string {
 	re := regexp.MustCompile(`\d{5}-?\d{3}`)
@@ -117,21 +93,6 @@ func extractBathrooms(str string) int {
 	return 0
 }
 
-// Extrai área de uma string (ex: "150m²" -> 150.0)
-func extractArea(str string) float64 {
-	re := regexp.MustCompile(`(\d+(?:[,.]\d+)?)\s*m²`)
-	match := re.FindStringSubmatch(str)
-	if len(match) > 1 {
-		// Converte vírgula para ponto
-		areaStr := strings.Replace(match[1], ",", ".", 1)
-		area, err := strconv.ParseFloat(areaStr, 64)
-		if err == nil {
-			return area
-		}
-	}
-	return 0
-}
-
 // Extrai o tipo de imóvel de uma string
 func extractPropertyType(str string) string {
 	str = strings.ToLower(str)
@@ -193,11 +154,11 @@ func mapToProperty(endereco, cidade, descricao, valorStr, url string) repository
 	valorStr = cleanText(valorStr)
 
 	// Extrai informações adicionais
-	valor := extractValue(valorStr)
+	valor := utils.ParseAmount(valorStr)
 	cep := extractCEP(endereco)
 	quartos := extractRooms(descricao)
 	banheiros := extractBathrooms(descricao)
-	areaTotal := extractArea(descricao)
+	areaTotal := utils.ParseArea(descricao)
 	tipoImovel := extractPropertyType(descricao + " " + endereco)
 
 	// Se a cidade não foi encontrada, tenta extrair do endereço
@@ -284,12 +245,12 @@ func extractIndividualPropertiesFromCatalog(e *colly.HTMLElement, ctx context.Co
 				Cidade:          extractCityFromText(endereco + " " + descricao),
 				Descricao:       descricao,
 				ValorTexto:      valor,
-				Valor:           extractValue(valor),
+				Valor:           utils.ParseAmount(valor),
 				URL:             url,
 				TipoImovel:      extractPropertyType(descricao + " " + endereco),
 				Quartos:         extractRooms(descricao + " " + el.Text),
 				Banheiros:       extractBathrooms(descricao + " " + el.Text),
-				AreaTotal:       extractArea(descricao + " " + el.Text),
+				AreaTotal:       utils.ParseArea(descricao + " " + el.Text),
 				Caracteristicas: caracteristicas,
 			}
 
@@ -307,24 +268,26 @@ func extractIndividualPropertiesFromCatalog(e *colly.HTMLElement, ctx context.Co
 
 	// Se não encontrou elementos específicos, usa método de fallback mais inteligente
 	if len(properties) == 0 {
-		log.Printf("Nenhum elemento específico encontrado, usando fallback inteligente")
+		log.Printf("Nenhum elemento específico encontrado, usando segmentação de cards")
 
-		// Divide o texto em blocos que podem representar imóveis individuais
-		fullText := e.Text
+		// Divide o catálogo em cards a partir de estruturas DOM irmãs repetidas
+		cards := NewCatalogSegmenter().Segment(e.DOM, e.Request.URL)
 
-		// Procura por padrões que indicam início de um novo imóvel
-		blocks := regexp.MustCompile(`(?i)(R\$\s*[\d.,]+.*?(?=R\$|$))`).FindAllString(fullText, -1)
-
-		for i, block := range blocks {
+		for i, card := range cards {
 			if i >= 15 { // Limita para evitar spam
 				break
 			}
 
-			block = strings.TrimSpace(block)
+			block := card.Text
 			if len(block) < 10 { // Ignora blocos muito pequenos
 				continue
 			}
 
+			propertyURL := url
+			if card.DetailURL != "" {
+				propertyURL = card.DetailURL
+			}
+
 			// Extrai informações do bloco
 			endereco := extractAddressFromText(block)
 			valor := ""
@@ -337,12 +300,12 @@ func extractIndividualPropertiesFromCatalog(e *colly.HTMLElement, ctx context.Co
 				Cidade:     extractCityFromText(block),
 				Descricao:  cleanText(block),
 				ValorTexto: valor,
-				Valor:      extractValue(valor),
-				URL:        url,
+				Valor:      utils.ParseAmount(valor),
+				URL:        propertyURL,
 				TipoImovel: extractPropertyType(block),
 				Quartos:    extractRooms(block),
 				Banheiros:  extractBathrooms(block),
-				AreaTotal:  extractArea(block),
+				AreaTotal:  utils.ParseArea(block),
 			}
 
 			// Processa com IA se disponível
@@ -478,6 +441,9 @@ func StartCrawling(ctx context.Context, repo repository.PropertyRepository, urls
 	// Coletor para páginas de detalhes de imóveis
 	detailCollector := c.Clone()
 
+	// Callbacks protegidos: com coleta assíncrona, um panic derrubaria o processo inteiro
+	recovery := NewCallbackRecovery("crawler", nil)
+
 	// Controle de concorrência
 	c.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
@@ -504,7 +470,7 @@ func StartCrawling(ctx context.Context, repo repository.PropertyRepository, urls
 	}
 
 	// Procura por links para páginas de detalhes de imóveis
-	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	c.OnHTML("a[href]", recovery.HTML(func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		absoluteLink := e.Request.AbsoluteURL(link)
 
@@ -529,7 +495,7 @@ func StartCrawling(ctx context.Context, repo repository.PropertyRepository, urls
 				detailCollector.Visit(absoluteLink)
 			}
 		}
-	})
+	}))
 
 	// Função para detectar se é uma página de catálogo/listagem
 	isCatalogPage := func(e *colly.HTMLElement) bool {
@@ -599,7 +565,7 @@ func StartCrawling(ctx context.Context, repo repository.PropertyRepository, urls
 	}
 
 	// Procura por dados de imóveis nas páginas de detalhes
-	detailCollector.OnHTML("body", func(e *colly.HTMLElement) {
+	detailCollector.OnHTML("body", recovery.HTML(func(e *colly.HTMLElement) {
 		// Pega a URL da página
 		url := e.Request.URL.String()
 
@@ -855,15 +821,15 @@ func StartCrawling(ctx context.Context, repo repository.PropertyRepository, urls
 				}
 			}
 		}
-	})
+	}))
 
-	c.OnRequest(func(r *colly.Request) {
+	c.OnRequest(recovery.Request(func(r *colly.Request) {
 		log.Printf("Visitando página de listagem: %s", r.URL)
-	})
+	}))
 
-	detailCollector.OnRequest(func(r *colly.Request) {
+	detailCollector.OnRequest(recovery.Request(func(r *colly.Request) {
 		log.Printf("Visitando página de detalhes: %s", r.URL)
-	})
+	}))
 
 	// Inicia a coleta a partir das URLs iniciais
 	for _, url := range urls {
@@ -902,16 +868,15 @@ func getFirstNonEmpty(e *colly.HTMLElement, selectors ...string) string {
 	return ""
 }
 
+// LoadURLsFromFile lê as URLs iniciais do arquivo de seeds (SITES_FILE) em qualquer formato
+// aceito por LoadSeedFile.
+//
+// Deprecated: as URLs ficam no repositório de sites por cidade; migre os arquivos com
+// cmd/migrate_sites ou POST /cities/migrate. Mantida para o benchmark e os engines legados.
 func LoadURLsFromFile(filePath string) ([]string, error) {
-	var urls []string
-	data, err := os.ReadFile(filePath)
+	seeds, err := LoadSeedFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := json.Unmarshal(data, &urls); err != nil {
-		return nil, err
-	}
-
-	return urls, nil
+	return SeedURLs(seeds), nil
 }
@@ -0,0 +1,172 @@
+package crawler
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// DefaultDeadFilterRetry é o prazo padrão até uma combinação sem resultados voltar a ser testada
+const DefaultDeadFilterRetry = 30 * 24 * time.Hour
+
+// zeroResultPatterns reconhecem o aviso de busca vazia dos sites de imobiliárias
+var zeroResultPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`nenhum (imóvel|imovel|resultado|anúncio|anuncio)s? (foi )?encontrad[oa]s?`),
+	regexp.MustCompile(`não (encontramos|foram encontrados|há|existem) (nenhum )?(imóveis|imoveis|imóvel|imovel|resultados)`),
+	regexp.MustCompile(`sua (busca|pesquisa) não (retornou|encontrou)`),
+	regexp.MustCompile(`\b0 (imóveis|imoveis|resultados|anúncios|anuncios) encontrad[oa]s\b`),
+}
+
+// IsZeroResultPage informa se o texto da página é o aviso de "nenhum imóvel encontrado"
+func IsZeroResultPage(pageText string) bool {
+	text := strings.ToLower(strings.Join(strings.Fields(pageText), " "))
+	for _, pattern := range zeroResultPatterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadFilterTracker lembra, por domínio, as combinações de filtros geradas pela descoberta
+// interativa que retornaram zero imóveis, para que não sejam geradas de novo nos próximos
+// crawlings. As combinações voltam a ser testadas depois do prazo de retry, ou antes, se a mesma
+// URL for alcançada por um link do site e tiver resultados.
+type DeadFilterTracker struct {
+	store  repository.DeadFilterRepository
+	retry  time.Duration
+	logger *logger.Logger
+
+	mutex    sync.RWMutex
+	dead     map[string]map[string]bool // domínio -> combinações
+	recorded int
+	pruned   int
+}
+
+// NewDeadFilterTracker cria o rastreador; retry não positivo usa DefaultDeadFilterRetry
+func NewDeadFilterTracker(store repository.DeadFilterRepository, retry time.Duration) *DeadFilterTracker {
+	if retry <= 0 {
+		retry = DefaultDeadFilterRetry
+	}
+	return &DeadFilterTracker{
+		store:  store,
+		retry:  retry,
+		logger: logger.NewLogger("dead_filters"),
+		dead:   make(map[string]map[string]bool),
+	}
+}
+
+// Load carrega as combinações sem resultados ainda vigentes de todos os domínios
+func (t *DeadFilterTracker) Load(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	combinations, err := t.store.FindDeadFilters(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, combination := range combinations {
+		t.mark(combination.Domain, combination.Combination)
+	}
+	t.logger.WithField("combinations", len(combinations)).Info("Dead filter combinations loaded")
+	return nil
+}
+
+// IsDead informa se a combinação de filtros da URL não retorna imóveis no domínio
+func (t *DeadFilterTracker) IsDead(rawURL string) bool {
+	if t == nil {
+		return false
+	}
+	domain, combination := repository.NormalizeDomain(rawURL), repository.FilterCombination(rawURL)
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.dead[domain][combination]
+}
+
+// Prune remove das URLs geradas as combinações sem resultados
+func (t *DeadFilterTracker) Prune(urls []string) []string {
+	if t == nil {
+		return urls
+	}
+	kept := urls[:0]
+	pruned := 0
+	for _, u := range urls {
+		if t.IsDead(u) {
+			pruned++
+			continue
+		}
+		kept = append(kept, u)
+	}
+
+	t.mutex.Lock()
+	t.pruned += pruned
+	t.mutex.Unlock()
+	return kept
+}
+
+// RecordZeroResult marca a combinação da URL como sem resultados no domínio
+func (t *DeadFilterTracker) RecordZeroResult(ctx context.Context, rawURL string) {
+	if t == nil {
+		return
+	}
+	domain, combination := repository.NormalizeDomain(rawURL), repository.FilterCombination(rawURL)
+	if domain == "" || combination == "" {
+		return
+	}
+
+	t.mutex.Lock()
+	t.mark(domain, combination)
+	t.recorded++
+	t.mutex.Unlock()
+
+	if err := t.store.RecordDeadFilter(ctx, domain, combination, t.retry); err != nil {
+		t.logger.WithError(err).Warn("Failed to record dead filter combination")
+		return
+	}
+	t.logger.WithFields(map[string]interface{}{
+		"domain":      domain,
+		"combination": combination,
+	}).Info("Filter combination returned no listings, skipping it in future crawls")
+}
+
+// RecordResults libera a combinação da URL se ela estava marcada como sem resultados
+func (t *DeadFilterTracker) RecordResults(ctx context.Context, rawURL string) {
+	if t == nil || !t.IsDead(rawURL) {
+		return
+	}
+	domain, combination := repository.NormalizeDomain(rawURL), repository.FilterCombination(rawURL)
+
+	t.mutex.Lock()
+	delete(t.dead[domain], combination)
+	t.mutex.Unlock()
+
+	if err := t.store.ClearDeadFilter(ctx, domain, combination); err != nil {
+		t.logger.WithError(err).Warn("Failed to clear dead filter combination")
+	}
+}
+
+// Counts retorna as combinações marcadas e as URLs descartadas neste crawling
+func (t *DeadFilterTracker) Counts() (recorded, pruned int) {
+	if t == nil {
+		return 0, 0
+	}
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.recorded, t.pruned
+}
+
+// mark registra a combinação em memória; chamado com o mutex travado
+func (t *DeadFilterTracker) mark(domain, combination string) {
+	if t.dead[domain] == nil {
+		t.dead[domain] = make(map[string]bool)
+	}
+	t.dead[domain][combination] = true
+}
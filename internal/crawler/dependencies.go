@@ -0,0 +1,116 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// PropertyAIProvider processa (corrige e completa) os dados extraídos de um imóvel
+type PropertyAIProvider interface {
+	ProcessPropertyData(ctx context.Context, property repository.Property) (repository.Property, error)
+	FlushBatch(ctx context.Context) error
+}
+
+// PageAIProvider classifica páginas e valida dados extraídos (usado pelo AIIntegratedCrawler)
+type PageAIProvider interface {
+	ClassifyPageContent(ctx context.Context, url, title, content string) (*ai.PageClassificationResult, error)
+	ValidateExtractedData(ctx context.Context, property repository.Property, originalHTML string) (repository.Property, error)
+}
+
+// Clock abstrai o relógio para permitir testes determinísticos
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock usa o relógio do sistema
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// CrawlerDependencies reúne as dependências externas dos crawlers. Apenas Repository é
+// obrigatório; os provedores de IA são opcionais, Transport substitui o cliente HTTP
+// usado pelos collectors (útil em testes com httptest ou transportes falsos) e Calibrator
+// converte as notas de padrões, IA e heurísticas para a escala comum dos limiares.
+type CrawlerDependencies struct {
+	Repository repository.PropertyRepository
+	AI         PropertyAIProvider
+	PageAI     PageAIProvider
+	Trainer    *AIEnhancedTrainer
+	Transport  http.RoundTripper
+	Clock      Clock
+	Calibrator *ConfidenceCalibrator
+}
+
+// validate verifica as dependências obrigatórias e preenche os padrões
+func (d *CrawlerDependencies) validate() error {
+	if d.Repository == nil {
+		return fmt.Errorf("crawler dependencies: repository is required")
+	}
+	if d.Clock == nil {
+		d.Clock = systemClock{}
+	}
+	return nil
+}
+
+// applyTransport aplica o transporte configurado aos collectors (sem transporte configurado, o
+// do cache de DNS) e, quando configurados, os middlewares de fetch e o registro de auditoria
+func (d *CrawlerDependencies) applyTransport(collectors ...*colly.Collector) {
+	for _, collector := range collectors {
+		base := d.Transport
+		if base != nil {
+			collector.WithTransport(base)
+		} else {
+			base = dnsCollector(collector)
+		}
+		fetchCollector(collector, base, "", PageLimits{})
+	}
+}
+
+// NewCrawlerDependencies cria as dependências padrão (MongoDB e Gemini) a partir da configuração.
+// Falhas de conexão com o MongoDB são retornadas; a IA é opcional e apenas registrada em log.
+func NewCrawlerDependencies(ctx context.Context, cfg *config.Config, withPageAI bool) (CrawlerDependencies, error) {
+	log := logger.NewLogger("crawler_dependencies")
+
+	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
+	if err != nil {
+		return CrawlerDependencies{}, fmt.Errorf("failed to create MongoDB repository: %w", err)
+	}
+
+	deps := CrawlerDependencies{Repository: repo}
+
+	if calibrator, err := LoadConfidenceCalibrator(cfg.CalibrationFile); err == nil {
+		deps.Calibrator = calibrator
+	} else {
+		log.WithError(err).Warn("Failed to load confidence calibration, using raw scores")
+	}
+
+	if aiService, err := ai.NewGeminiService(ctx); err == nil {
+		deps.AI = aiService
+	} else {
+		log.WithError(err).Warn("Failed to create Gemini service, continuing without AI")
+	}
+
+	if withPageAI {
+		if enhancedAI, err := ai.NewEnhancedGeminiService(ctx); err == nil {
+			deps.PageAI = enhancedAI
+		} else {
+			log.WithError(err).Warn("Failed to create enhanced AI service")
+		}
+
+		if trainer, err := NewAIEnhancedTrainer(ctx); err == nil {
+			deps.Trainer = trainer
+		} else {
+			log.WithError(err).Warn("Failed to create AI trainer")
+		}
+	}
+
+	return deps, nil
+}
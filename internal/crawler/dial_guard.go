@@ -0,0 +1,94 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress indica uma conexão recusada para um endereço interno (loopback, rede privada,
+// link-local, CGNAT ou não especificado)
+var ErrPrivateAddress = errors.New("connection to private address refused")
+
+// maxGuardedRedirects limita os redirecionamentos seguidos pelos clientes protegidos
+const maxGuardedRedirects = 5
+
+// cgnatNetwork é a faixa de endereços compartilhados das operadoras (RFC 6598)
+var cgnatNetwork = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP informa se o endereço pode ser acessado pelas ferramentas de diagnóstico
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || cgnatNetwork.Contains(ip))
+}
+
+// guardDial recusa a conexão quando o endereço já resolvido é interno. Roda no Control do
+// net.Dialer, depois da resolução de DNS, então um host público que resolve para um IP interno
+// (DNS rebinding) também é recusado
+func guardDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// NewGuardedDialer cria um net.Dialer que só conecta em endereços públicos
+func NewGuardedDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, Control: guardDial}
+}
+
+// NewGuardedTransport cria o transporte HTTP das rotas que baixam URLs informadas pelo usuário
+// (ex: GET /debug/classify): as conexões para endereços internos são recusadas, inclusive as
+// de redirecionamentos. O transporte não usa proxy, que esconderia o endereço de destino
+func NewGuardedTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = NewGuardedDialer(30 * time.Second).DialContext
+	return transport
+}
+
+// ValidatePublicURL recusa URLs que não são http(s) com host ou que apontam diretamente para um
+// endereço interno (IP literal ou localhost). Nomes que resolvem para endereços internos são
+// recusados depois, na conexão, pelo dialer protegido
+func ValidatePublicURL(target *url.URL) error {
+	if target == nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return fmt.Errorf("invalid url %q: only http(s) urls with a host are allowed", target)
+	}
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// checkGuardedRedirect revalida cada salto de redirecionamento e limita quantos são seguidos
+func checkGuardedRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxGuardedRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxGuardedRedirects)
+	}
+	return ValidatePublicURL(req.URL)
+}
+
+// NewGuardedClient cria o cliente HTTP dos componentes que baixam URLs vindas dos anúncios ou da
+// configuração (fotos, APIs de portais): transporte protegido, registrado no log de auditoria, e
+// redirecionamentos revalidados a cada salto
+func NewGuardedClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     NewAuditedTransport(NewGuardedTransport(), ""),
+		CheckRedirect: checkGuardedRedirect,
+	}
+}
@@ -0,0 +1,257 @@
+package crawler
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Tipos de passo no caminho de descoberta
+const (
+	DiscoveryKindSeed       = "seed"
+	DiscoveryKindCatalog    = "catalog"
+	DiscoveryKindPagination = "pagination"
+	DiscoveryKindPage       = "page"
+	DiscoveryKindProperty   = "property"
+	DiscoveryKindFeed       = "feed"
+)
+
+// maxDiscoveryURLs limita as URLs rastreadas por crawling; acima dele as mais antigas são
+// esquecidas (os caminhos dos anúncios descobertos a partir delas ficam mais curtos)
+const maxDiscoveryURLs = 200000
+
+// DiscoveryTracker registra como cada URL foi descoberta (seed → catálogo → página N → imóvel)
+// e o rendimento de cada origem de descoberta por domínio
+type DiscoveryTracker struct {
+	parents     map[string]string
+	kinds       map[string]string
+	sources     map[string]string // estratégia que gerou a URL (formulário, API, link classificado)
+	yields      map[string]map[string]*repository.SourceYield
+	order       []string // URLs rastreadas (exceto seeds) na ordem de registro, para o descarte
+	maxURLs     int
+	maxPathSize int
+	pagePattern *regexp.Regexp
+	mutex       sync.RWMutex
+}
+
+// NewDiscoveryTracker cria um novo rastreador de descoberta
+func NewDiscoveryTracker() *DiscoveryTracker {
+	return &DiscoveryTracker{
+		parents:     make(map[string]string),
+		kinds:       make(map[string]string),
+		sources:     make(map[string]string),
+		yields:      make(map[string]map[string]*repository.SourceYield),
+		maxURLs:     maxDiscoveryURLs,
+		maxPathSize: 30,
+		pagePattern: regexp.MustCompile(`(?i)(?:[?&](?:page|pagina|pag|p)=|/(?:page|pagina|pag)/)(\d+)`),
+	}
+}
+
+// RecordSeed registra uma URL inicial do crawling
+func (dt *DiscoveryTracker) RecordSeed(url string) {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	dt.kinds[url] = DiscoveryKindSeed
+	delete(dt.parents, url)
+}
+
+// RecordLink registra que childURL foi descoberta a partir de parentURL.
+// Mantém a primeira descoberta para não sobrescrever caminhos mais curtos.
+func (dt *DiscoveryTracker) RecordLink(parentURL, childURL string) {
+	if parentURL == "" || childURL == "" || parentURL == childURL {
+		return
+	}
+
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	if _, exists := dt.parents[childURL]; exists {
+		return
+	}
+	if dt.kinds[childURL] == DiscoveryKindSeed {
+		return
+	}
+	dt.track(childURL)
+	dt.parents[childURL] = parentURL
+}
+
+// RecordKind registra o tipo de página identificado para a URL (catálogo, anúncio, etc.)
+func (dt *DiscoveryTracker) RecordKind(url, kind string) {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	if dt.kinds[url] == DiscoveryKindSeed {
+		return
+	}
+	dt.track(url)
+	dt.kinds[url] = kind
+}
+
+// RecordSource registra a estratégia que gerou a URL; mantém a primeira registrada
+func (dt *DiscoveryTracker) RecordSource(url, source string) {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	if _, exists := dt.sources[url]; !exists {
+		dt.track(url)
+		dt.sources[url] = source
+	}
+}
+
+// track enfileira a URL na primeira vez que ela é registrada e descarta as mais antigas acima
+// de maxURLs; seeds nunca são descartadas (deve ser chamado com o lock de escrita)
+func (dt *DiscoveryTracker) track(url string) {
+	if _, exists := dt.parents[url]; exists {
+		return
+	}
+	if _, exists := dt.kinds[url]; exists {
+		return
+	}
+	if _, exists := dt.sources[url]; exists {
+		return
+	}
+	dt.order = append(dt.order, url)
+
+	for len(dt.order) > dt.maxURLs {
+		oldest := dt.order[0]
+		dt.order = dt.order[1:]
+		if dt.kinds[oldest] == DiscoveryKindSeed {
+			continue
+		}
+		delete(dt.parents, oldest)
+		delete(dt.kinds, oldest)
+		delete(dt.sources, oldest)
+	}
+}
+
+// Source retorna a origem de descoberta da URL: a primeira estratégia registrada no caminho a
+// partir da seed (feed, API, formulário, paginação, link classificado). Sem estratégia, a
+// origem é a seed quando a URL é a seed ou um link direto dela, e link nos demais casos.
+func (dt *DiscoveryTracker) Source(url string) string {
+	path := dt.Path(url)
+
+	dt.mutex.RLock()
+	defer dt.mutex.RUnlock()
+
+	for _, step := range path {
+		if source := dt.sources[step.URL]; source != "" {
+			return source
+		}
+		switch step.Kind {
+		case DiscoveryKindFeed:
+			return repository.DiscoverySourceFeed
+		case DiscoveryKindPagination:
+			return repository.DiscoverySourcePagination
+		}
+	}
+	if len(path) <= 2 {
+		return repository.DiscoverySourceSeed
+	}
+	return repository.DiscoverySourceLink
+}
+
+// RecordVisit conta a página visitada no rendimento da sua origem de descoberta
+func (dt *DiscoveryTracker) RecordVisit(url string) {
+	source := dt.Source(url)
+
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+	dt.yield(url, source).Visited++
+}
+
+// SourceYields retorna o rendimento por origem de cada domínio visitado
+func (dt *DiscoveryTracker) SourceYields() map[string]map[string]repository.SourceYield {
+	dt.mutex.RLock()
+	defer dt.mutex.RUnlock()
+
+	yields := make(map[string]map[string]repository.SourceYield, len(dt.yields))
+	for domain, sources := range dt.yields {
+		yields[domain] = make(map[string]repository.SourceYield, len(sources))
+		for source, yield := range sources {
+			yields[domain][source] = *yield
+		}
+	}
+	return yields
+}
+
+// yield retorna o contador da origem no domínio da URL (deve ser chamado com o lock de escrita)
+func (dt *DiscoveryTracker) yield(url, source string) *repository.SourceYield {
+	domain := repository.NormalizeDomain(url)
+	if dt.yields[domain] == nil {
+		dt.yields[domain] = make(map[string]*repository.SourceYield)
+	}
+	if dt.yields[domain][source] == nil {
+		dt.yields[domain][source] = &repository.SourceYield{}
+	}
+	return dt.yields[domain][source]
+}
+
+// Path retorna o caminho de descoberta da seed até a URL informada
+func (dt *DiscoveryTracker) Path(url string) []repository.DiscoveryStep {
+	dt.mutex.RLock()
+	defer dt.mutex.RUnlock()
+
+	var reversed []repository.DiscoveryStep
+	visited := make(map[string]bool)
+
+	for current := url; current != "" && !visited[current] && len(reversed) < dt.maxPathSize; current = dt.parents[current] {
+		visited[current] = true
+		reversed = append(reversed, dt.step(current))
+	}
+
+	path := make([]repository.DiscoveryStep, len(reversed))
+	for i, step := range reversed {
+		path[len(reversed)-1-i] = step
+	}
+	return path
+}
+
+// Apply preenche o caminho e a profundidade de descoberta na propriedade
+func (dt *DiscoveryTracker) Apply(property *repository.Property) {
+	if property == nil || property.URL == "" {
+		return
+	}
+
+	dt.mutex.Lock()
+	// Anúncios extraídos direto do catálogo mantêm a URL do catálogo como último passo
+	if kind := dt.kinds[property.URL]; kind != DiscoveryKindSeed && kind != DiscoveryKindCatalog {
+		dt.track(property.URL)
+		dt.kinds[property.URL] = DiscoveryKindProperty
+	}
+	// A página que levou ao anúncio é tratada como catálogo se não tiver tipo conhecido
+	if parent, exists := dt.parents[property.URL]; exists && dt.kinds[parent] == "" {
+		dt.track(parent)
+		dt.kinds[parent] = DiscoveryKindCatalog
+	}
+	dt.mutex.Unlock()
+
+	property.DiscoveryPath = dt.Path(property.URL)
+	property.DiscoveryDepth = len(property.DiscoveryPath) - 1
+	property.DiscoverySource = dt.Source(property.URL)
+
+	dt.mutex.Lock()
+	dt.yield(property.URL, property.DiscoverySource).Properties++
+	dt.mutex.Unlock()
+}
+
+// step monta um passo do caminho (deve ser chamado com o lock de leitura)
+func (dt *DiscoveryTracker) step(url string) repository.DiscoveryStep {
+	kind := dt.kinds[url]
+	if kind == "" {
+		kind = DiscoveryKindPage
+	}
+
+	step := repository.DiscoveryStep{URL: url, Kind: kind}
+	if match := dt.pagePattern.FindStringSubmatch(url); len(match) > 1 {
+		if page, err := strconv.Atoi(match[1]); err == nil {
+			step.Page = page
+			if kind == DiscoveryKindPage || kind == DiscoveryKindCatalog {
+				step.Kind = DiscoveryKindPagination
+			}
+		}
+	}
+	return step
+}
@@ -0,0 +1,317 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/gocolly/colly"
+)
+
+// Valores padrão do cache de DNS
+const (
+	DefaultDNSCacheTTL         = 5 * time.Minute
+	DefaultDNSNegativeTTL      = time.Minute
+	DefaultDNSFailureThreshold = 3
+	DefaultDNSQuarantine       = 15 * time.Minute
+)
+
+// ErrHostQuarantined indica um host isolado depois de falhas seguidas de resolução de DNS
+var ErrHostQuarantined = errors.New("host quarantined after repeated DNS failures")
+
+// DNSCacheOptions configura o cache: TTL das resoluções, TTL das falhas (cache negativo) e o
+// isolamento do host depois de FailureThreshold falhas seguidas (0 desativa o isolamento)
+type DNSCacheOptions struct {
+	TTL              time.Duration
+	NegativeTTL      time.Duration
+	FailureThreshold int
+	Quarantine       time.Duration
+}
+
+// DNSHostFailure resume as falhas de resolução de um host
+type DNSHostFailure struct {
+	Host             string    `json:"host"`
+	Failures         int       `json:"failures"` // falhas seguidas (zera na primeira resolução bem-sucedida)
+	LastError        string    `json:"last_error"`
+	QuarantinedUntil time.Time `json:"quarantined_until,omitempty"`
+	Skipped          int       `json:"skipped"` // requisições descartadas durante o isolamento
+}
+
+// DNSCacheStats resume o uso do cache e os hosts com falhas de resolução
+type DNSCacheStats struct {
+	Entries      int              `json:"entries"`
+	Hits         int64            `json:"hits"`
+	Misses       int64            `json:"misses"`
+	NegativeHits int64            `json:"negative_hits"`
+	Failing      []DNSHostFailure `json:"failing"`
+}
+
+// hostResolver resolve os endereços de um host (net.Resolver; substituído nos testes)
+type hostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// dnsEntry é uma resolução em cache: endereços ou o erro da falha (cache negativo)
+type dnsEntry struct {
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+// DNSCache guarda em memória as resoluções de DNS dos crawlings, inclusive as falhas, e isola os
+// hosts que falham seguidamente: as URLs de um site fora do ar são descartadas sem consumir o
+// orçamento de páginas, o atraso por domínio e as novas tentativas do crawling
+type DNSCache struct {
+	resolver  hostResolver
+	dialer    *net.Dialer
+	options   DNSCacheOptions
+	transport *http.Transport
+	logger    *logger.Logger
+	now       func() time.Time
+
+	mutex        sync.Mutex
+	entries      map[string]*dnsEntry
+	failures     map[string]*DNSHostFailure
+	hits         int64
+	misses       int64
+	negativeHits int64
+}
+
+// NewDNSCache cria o cache; TTLs não positivos usam os padrões
+func NewDNSCache(options DNSCacheOptions) *DNSCache {
+	if options.TTL <= 0 {
+		options.TTL = DefaultDNSCacheTTL
+	}
+	if options.NegativeTTL <= 0 {
+		options.NegativeTTL = DefaultDNSNegativeTTL
+	}
+	if options.Quarantine <= 0 {
+		options.Quarantine = DefaultDNSQuarantine
+	}
+	cache := &DNSCache{
+		resolver: net.DefaultResolver,
+		dialer:   &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+		options:  options,
+		logger:   logger.NewLogger("dns_cache"),
+		now:      time.Now,
+		entries:  make(map[string]*dnsEntry),
+		failures: make(map[string]*DNSHostFailure),
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = cache.DialContext
+	cache.transport = transport
+	return cache
+}
+
+// Transport retorna o transporte HTTP que resolve os hosts pelo cache
+func (d *DNSCache) Transport() http.RoundTripper {
+	return d.transport
+}
+
+// Lookup retorna os endereços do host, do cache quando ainda válidos. Respostas definitivas de
+// falha (ex: NXDOMAIN) ficam em cache por NegativeTTL e cada consulta ao resolvedor que as
+// retorna conta para o isolamento do host; timeouts e falhas temporárias (SERVFAIL) não ficam em
+// cache nem contam, e as respostas do cache negativo também não contam de novo
+func (d *DNSCache) Lookup(ctx context.Context, host string) ([]string, error) {
+	host = strings.ToLower(host)
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	d.mutex.Lock()
+	if entry, exists := d.entries[host]; exists && d.now().Before(entry.expires) {
+		if entry.err != nil {
+			d.negativeHits++
+			d.mutex.Unlock()
+			return nil, entry.err
+		}
+		d.hits++
+		d.mutex.Unlock()
+		return entry.addrs, nil
+	}
+	d.misses++
+	d.mutex.Unlock()
+
+	ipAddrs, err := d.resolver.LookupIPAddr(ctx, host)
+	if err == nil && len(ipAddrs) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if err != nil {
+		// Cancelamentos e prazos do próprio crawling, timeouts e falhas temporárias do resolvedor
+		// não dizem nada sobre o host
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || dnsErr.IsTimeout || dnsErr.IsTemporary {
+			return nil, err
+		}
+		d.entries[host] = &dnsEntry{err: err, expires: d.now().Add(d.options.NegativeTTL)}
+		d.recordFailure(host, err)
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(ipAddrs))
+	for _, ip := range ipAddrs {
+		addrs = append(addrs, ip.IP.String())
+	}
+	d.entries[host] = &dnsEntry{addrs: addrs, expires: d.now().Add(d.options.TTL)}
+	delete(d.failures, host)
+	return addrs, nil
+}
+
+// DialContext conecta ao endereço resolvido pelo cache, tentando cada IP do host em ordem; hosts
+// isolados falham com ErrHostQuarantined sem consultar o DNS
+func (d *DNSCache) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if d.quarantined(host, false) {
+		return nil, ErrHostQuarantined
+	}
+	addrs, err := d.Lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// Quarantined informa se o host da URL está isolado por falhas seguidas de DNS; cada consulta
+// durante o isolamento conta como requisição descartada
+func (d *DNSCache) Quarantined(rawURL string) bool {
+	if d == nil {
+		return false
+	}
+	return d.quarantined(hostOf(rawURL), true)
+}
+
+// quarantined informa se o host está isolado; countSkip soma a requisição descartada (só na
+// verificação dos engines, para o DialContext da mesma requisição não contar de novo)
+func (d *DNSCache) quarantined(host string, countSkip bool) bool {
+	host = strings.ToLower(host)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	failure, exists := d.failures[host]
+	if !exists || !d.now().Before(failure.QuarantinedUntil) {
+		return false
+	}
+	if countSkip {
+		failure.Skipped++
+	}
+	return true
+}
+
+// Stats retorna o uso do cache e os hosts com falhas, dos com mais falhas para os com menos
+func (d *DNSCache) Stats() DNSCacheStats {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	stats := DNSCacheStats{
+		Entries:      len(d.entries),
+		Hits:         d.hits,
+		Misses:       d.misses,
+		NegativeHits: d.negativeHits,
+		Failing:      make([]DNSHostFailure, 0, len(d.failures)),
+	}
+	for _, failure := range d.failures {
+		stats.Failing = append(stats.Failing, *failure)
+	}
+	sort.Slice(stats.Failing, func(i, j int) bool {
+		if stats.Failing[i].Failures != stats.Failing[j].Failures {
+			return stats.Failing[i].Failures > stats.Failing[j].Failures
+		}
+		return stats.Failing[i].Host < stats.Failing[j].Host
+	})
+	return stats
+}
+
+// recordFailure soma a falha do host e o isola ao atingir o limite; chamado com o mutex travado
+func (d *DNSCache) recordFailure(host string, err error) {
+	failure, exists := d.failures[host]
+	if !exists {
+		failure = &DNSHostFailure{Host: host}
+		d.failures[host] = failure
+	}
+	failure.Failures++
+	failure.LastError = err.Error()
+
+	if d.options.FailureThreshold <= 0 || failure.Failures < d.options.FailureThreshold || d.now().Before(failure.QuarantinedUntil) {
+		return
+	}
+	failure.QuarantinedUntil = d.now().Add(d.options.Quarantine)
+	d.logger.WithFields(map[string]interface{}{
+		"host":     host,
+		"failures": failure.Failures,
+		"until":    failure.QuarantinedUntil,
+	}).Warn("Host quarantined after repeated DNS failures")
+}
+
+// hostOf retorna o host da URL (ou o próprio valor, se não for uma URL)
+func hostOf(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		return parsed.Hostname()
+	}
+	return rawURL
+}
+
+var (
+	dnsCacheMutex sync.RWMutex
+	dnsCache      *DNSCache
+)
+
+// SetDNSCache define o cache de DNS compartilhado pelos collectors dos engines (nil desativa)
+func SetDNSCache(cache *DNSCache) {
+	dnsCacheMutex.Lock()
+	defer dnsCacheMutex.Unlock()
+	dnsCache = cache
+}
+
+// CurrentDNSCache retorna o cache de DNS configurado (nil desativado)
+func CurrentDNSCache() *DNSCache {
+	dnsCacheMutex.RLock()
+	defer dnsCacheMutex.RUnlock()
+	return dnsCache
+}
+
+// NewDNSCacheFromConfig cria o cache de DNS da configuração; nil quando DNS_CACHE_TTL é 0
+func NewDNSCacheFromConfig(cfg *config.Config) *DNSCache {
+	if cfg.DNSCacheTTL <= 0 {
+		return nil
+	}
+	return NewDNSCache(DNSCacheOptions{
+		TTL:              cfg.DNSCacheTTL,
+		NegativeTTL:      cfg.DNSNegativeTTL,
+		FailureThreshold: cfg.DNSFailureThreshold,
+		Quarantine:       cfg.DNSQuarantine,
+	})
+}
+
+// dnsCollector faz o collector resolver os hosts pelo cache de DNS; retorna o transporte aplicado
+// (nil sem cache), usado como base da auditoria. As requisições para hosts isolados são
+// descartadas no OnRequest de cada engine, antes de ocuparem o ritmo do domínio.
+func dnsCollector(c *colly.Collector) http.RoundTripper {
+	cache := CurrentDNSCache()
+	if cache == nil {
+		return nil
+	}
+	c.WithTransport(cache.Transport())
+	return cache.Transport()
+}
@@ -0,0 +1,153 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// maxAllowlistRedirects segue o limite padrão de redirecionamentos do net/http e do Colly
+const maxAllowlistRedirects = 10
+
+// DomainAllowlist implementa o modo estrito de domínios (CRAWL_STRICT_DOMAINS): apenas os
+// domínios das seeds (sites de city-sites) e das configurações por domínio são visitados, e
+// links ou redirecionamentos para outros domínios (redes de anúncio, parceiros) são recusados.
+// Subdomínios de um domínio permitido dependem de CRAWL_ALLOW_SUBDOMAINS ou do campo
+// allow_subdomains da configuração do domínio.
+type DomainAllowlist struct {
+	mutex           sync.RWMutex
+	domains         map[string]bool
+	allowSubdomains bool
+	rejected        map[string]int // domínio recusado -> links recusados
+}
+
+// NewDomainAllowlist cria uma allowlist vazia; allowSubdomains libera os subdomínios de todos os
+// domínios permitidos
+func NewDomainAllowlist(allowSubdomains bool) *DomainAllowlist {
+	return &DomainAllowlist{
+		domains:         make(map[string]bool),
+		allowSubdomains: allowSubdomains,
+		rejected:        make(map[string]int),
+	}
+}
+
+// Add permite os domínios das URLs (ou domínios) informadas
+func (a *DomainAllowlist) Add(urls ...string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, rawURL := range urls {
+		if domain := repository.NormalizeDomain(rawURL); domain != "" {
+			a.domains[domain] = true
+		}
+	}
+}
+
+// Allows indica se a URL pertence a um domínio permitido. Domínios com configuração no snapshot
+// de runtime (pode ser nil) também são permitidos; URLs recusadas são contadas por domínio.
+func (a *DomainAllowlist) Allows(rawURL string, snapshot *config.RuntimeSnapshot) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	domain := repository.NormalizeDomain(parsed.Hostname())
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.domains[domain] {
+		return true
+	}
+	if _, configured := snapshot.DomainConfig(domain); configured {
+		return true
+	}
+
+	// Subdomínio de um domínio permitido: vale a política global ou a do domínio pai
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels)-1; i++ {
+		parent := strings.Join(labels[i:], ".")
+		parentConfig, configured := snapshot.DomainConfig(parent)
+		if !a.domains[parent] && !configured {
+			continue
+		}
+		if a.allowSubdomains || parentConfig.AllowSubdomains {
+			return true
+		}
+	}
+
+	a.rejected[domain]++
+	return false
+}
+
+// Rejected retorna quantos links foram recusados por domínio
+func (a *DomainAllowlist) Rejected() map[string]int {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	rejected := make(map[string]int, len(a.rejected))
+	for domain, count := range a.rejected {
+		rejected[domain] = count
+	}
+	return rejected
+}
+
+// redirectHandler recusa redirecionamentos para domínios fora da allowlist; os demais seguem o
+// comportamento padrão do Colly (até 10 redirecionamentos, cabeçalhos da requisição anterior)
+func (a *DomainAllowlist) redirectHandler(snapshot func() *config.RuntimeSnapshot) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		var current *config.RuntimeSnapshot
+		if snapshot != nil {
+			current = snapshot()
+		}
+		if !a.Allows(req.URL.String(), current) {
+			return fmt.Errorf("not following redirect to %s: domain not allowed in strict mode", req.URL.Hostname())
+		}
+		if len(via) >= maxAllowlistRedirects {
+			return http.ErrUseLastResponse
+		}
+		for name, values := range via[len(via)-1].Header {
+			for _, value := range values {
+				req.Header.Set(name, value)
+			}
+		}
+		return nil
+	}
+}
+
+// logRejectedDomains registra, ao final do crawling, os domínios recusados pelo modo estrito
+func logRejectedDomains(log *logger.Logger, allowlist *DomainAllowlist) {
+	if allowlist == nil {
+		return
+	}
+	rejected := allowlist.Rejected()
+	if len(rejected) == 0 {
+		return
+	}
+
+	domains := make([]string, 0, len(rejected))
+	total := 0
+	for domain, count := range rejected {
+		domains = append(domains, domain)
+		total += count
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		if rejected[domains[i]] != rejected[domains[j]] {
+			return rejected[domains[i]] > rejected[domains[j]]
+		}
+		return domains[i] < domains[j]
+	})
+	if len(domains) > 20 {
+		domains = domains[:20]
+	}
+
+	log.WithFields(map[string]interface{}{
+		"rejected_links":   total,
+		"rejected_domains": len(rejected),
+		"top_domains":      domains,
+	}).Info("Strict domain mode refused links to domains outside the configuration")
+}
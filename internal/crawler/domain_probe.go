@@ -0,0 +1,409 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/gocolly/colly/extensions"
+)
+
+// Vereditos do diagnóstico de um domínio
+const (
+	ProbeVerdictOK          = "ok"
+	ProbeVerdictDNSFailure  = "dns_failure"
+	ProbeVerdictUnreachable = "network_unreachable"
+	ProbeVerdictTLSError    = "tls_error"
+	ProbeVerdictBlocked     = "blocked"
+	ProbeVerdictHTTPError   = "http_error"
+	ProbeVerdictPrivate     = "private_address"
+)
+
+// probeMaxAddresses limita os endereços testados por família (IPv4 e IPv6)
+const probeMaxAddresses = 4
+
+// DomainProbe é o diagnóstico de rede de um domínio a partir do ambiente do crawler: resolução
+// de DNS, conexão TCP a cada endereço, handshake TLS e uma requisição HEAD
+type DomainProbe struct {
+	Domain   string     `json:"domain"`
+	ProbedAt time.Time  `json:"probed_at"`
+	DNS      ProbeDNS   `json:"dns"`
+	TCP      []ProbeTCP `json:"tcp"`
+	TLS      *ProbeTLS  `json:"tls,omitempty"`  // nil quando nenhuma conexão na porta HTTPS abriu
+	HTTP     *ProbeHTTP `json:"http,omitempty"` // nil quando nenhuma conexão abriu
+	Verdict  string     `json:"verdict"`
+	Notes    []string   `json:"notes"`
+}
+
+// ProbeDNS é o resultado da resolução do domínio
+type ProbeDNS struct {
+	IPv4       []string `json:"ipv4"`
+	IPv6       []string `json:"ipv6"`
+	DurationMs int64    `json:"duration_ms"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// ProbeTCP é a tentativa de conexão a um endereço
+type ProbeTCP struct {
+	Address    string `json:"address"`
+	Family     string `json:"family"` // ipv4 ou ipv6
+	Port       int    `json:"port"`
+	Connected  bool   `json:"connected"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ProbeTLS é o handshake TLS com o primeiro endereço que aceitou a conexão
+type ProbeTLS struct {
+	Address    string    `json:"address"`
+	Version    string    `json:"version,omitempty"`
+	Subject    string    `json:"subject,omitempty"`
+	Issuer     string    `json:"issuer,omitempty"`
+	NotAfter   time.Time `json:"not_after,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// ProbeHTTP é a requisição HEAD à raiz do domínio, sem seguir redirecionamentos
+type ProbeHTTP struct {
+	URL          string   `json:"url"`
+	StatusCode   int      `json:"status_code,omitempty"`
+	Server       string   `json:"server,omitempty"`
+	Location     string   `json:"location,omitempty"`
+	BlockSignals []string `json:"block_signals,omitempty"` // cabeçalhos de WAF/CDN e status de bloqueio
+	DurationMs   int64    `json:"duration_ms"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// blockHeaders são cabeçalhos de WAFs e CDNs que costumam acompanhar páginas de desafio
+var blockHeaders = map[string]string{
+	"Cf-Ray":          "cloudflare",
+	"Cf-Mitigated":    "cloudflare_challenge",
+	"X-Sucuri-Id":     "sucuri",
+	"X-Sucuri-Block":  "sucuri_block",
+	"X-Datadome":      "datadome",
+	"X-Amz-Cf-Id":     "cloudfront",
+	"X-Iinfo":         "incapsula",
+	"Akamai-Grn":      "akamai",
+	"X-Akamai-Ticket": "akamai",
+}
+
+// DomainProber diagnostica a conectividade de um domínio para separar bloqueios (status de
+// bloqueio, WAF, conexões derrubadas depois de abertas) de problemas de rede (DNS, rotas, IPv6)
+type DomainProber struct {
+	resolver     hostResolver
+	allowAddress func(ip net.IP) bool // endereços que podem ser testados (os públicos)
+	timeout      time.Duration
+	httpsPort    int
+	httpPort     int
+	tlsConfig    *tls.Config
+}
+
+// NewDomainProber cria o diagnóstico com o resolvedor do sistema e as portas 443 e 80. Domínios
+// que resolvem para endereços internos não são testados
+func NewDomainProber() *DomainProber {
+	return &DomainProber{
+		resolver:     net.DefaultResolver,
+		allowAddress: publicIP,
+		timeout:      5 * time.Second,
+		httpsPort:    443,
+		httpPort:     80,
+	}
+}
+
+// Probe executa as etapas em ordem; uma etapa que falha não impede as seguintes quando ainda
+// há o que testar (ex: sem IPv6, os endereços IPv4 continuam sendo testados)
+func (p *DomainProber) Probe(ctx context.Context, domain string) *DomainProbe {
+	host := strings.TrimPrefix(strings.ToLower(hostOf(strings.TrimSpace(domain))), "www.")
+	if i := strings.LastIndex(host, ":"); i > 0 && !strings.Contains(host[:i], ":") {
+		host = host[:i]
+	}
+	probe := &DomainProbe{Domain: host, ProbedAt: time.Now(), TCP: []ProbeTCP{}, Notes: []string{}}
+
+	// DNS: o domínio informado e, sem resposta, a variante com www.
+	addrs, err := p.resolve(ctx, probe, host)
+	if err != nil {
+		if wwwAddrs, wwwErr := p.resolve(ctx, &DomainProbe{}, "www."+host); wwwErr == nil {
+			host, addrs, err = "www."+host, wwwAddrs, nil
+			probe.DNS.Error = ""
+			probe.Notes = append(probe.Notes, "domain only resolves with www.")
+		}
+	}
+	if err != nil {
+		probe.Verdict = ProbeVerdictDNSFailure
+		return probe
+	}
+	for _, addr := range addrs {
+		if addr.To4() != nil {
+			probe.DNS.IPv4 = append(probe.DNS.IPv4, addr.String())
+		} else {
+			probe.DNS.IPv6 = append(probe.DNS.IPv6, addr.String())
+		}
+	}
+
+	// Nenhuma conexão sai para a rede interna: as etapas seguintes só usam os endereços já
+	// verificados aqui
+	for _, addr := range addrs {
+		if !p.allowAddress(addr) {
+			probe.Verdict = ProbeVerdictPrivate
+			probe.Notes = append(probe.Notes, fmt.Sprintf("%s resolves to internal address %s", host, addr))
+			return probe
+		}
+	}
+
+	// TCP na porta HTTPS (e na HTTP se nenhum endereço aceitar a HTTPS)
+	port := p.httpsPort
+	connected := p.dialAll(ctx, probe, addrs, port)
+	if connected == "" {
+		port = p.httpPort
+		connected = p.dialAll(ctx, probe, addrs, port)
+	}
+	p.noteFamilies(probe)
+	if connected == "" {
+		probe.Verdict = ProbeVerdictUnreachable
+		return probe
+	}
+
+	scheme := "http"
+	if port == p.httpsPort {
+		scheme = "https"
+		probe.TLS = p.handshake(ctx, host, connected)
+	}
+	probe.HTTP = p.head(ctx, scheme, host, connected)
+	probe.Verdict = probeVerdict(probe)
+	return probe
+}
+
+// resolve consulta os endereços do host e registra a duração e o erro no diagnóstico
+func (p *DomainProber) resolve(ctx context.Context, probe *DomainProbe, host string) ([]net.IP, error) {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	ipAddrs, err := p.resolver.LookupIPAddr(ctx, host)
+	probe.DNS.DurationMs = time.Since(started).Milliseconds()
+	if err == nil && len(ipAddrs) == 0 {
+		err = fmt.Errorf("no addresses for %s", host)
+	}
+	if err != nil {
+		probe.DNS.Error = err.Error()
+		return nil, err
+	}
+	addrs := make([]net.IP, 0, len(ipAddrs))
+	for _, ip := range ipAddrs {
+		addrs = append(addrs, ip.IP)
+	}
+	return addrs, nil
+}
+
+// dialAll tenta conectar a cada endereço (até probeMaxAddresses por família) e retorna o
+// primeiro que aceitou a conexão, ou vazio
+func (p *DomainProber) dialAll(ctx context.Context, probe *DomainProbe, addrs []net.IP, port int) string {
+	connected := ""
+	perFamily := map[string]int{}
+	for _, addr := range addrs {
+		family := "ipv6"
+		if addr.To4() != nil {
+			family = "ipv4"
+		}
+		if perFamily[family] >= probeMaxAddresses {
+			continue
+		}
+		perFamily[family]++
+
+		address := net.JoinHostPort(addr.String(), strconv.Itoa(port))
+		result := ProbeTCP{Address: addr.String(), Family: family, Port: port}
+		started := time.Now()
+		conn, err := (&net.Dialer{Timeout: p.timeout}).DialContext(ctx, "tcp", address)
+		result.DurationMs = time.Since(started).Milliseconds()
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			conn.Close()
+			result.Connected = true
+			if connected == "" {
+				connected = address
+			}
+		}
+		probe.TCP = append(probe.TCP, result)
+	}
+	return connected
+}
+
+// noteFamilies aponta quando só uma das famílias de endereço conecta (ex: IPv6 quebrado no host
+// ou na rede do crawler)
+func (p *DomainProber) noteFamilies(probe *DomainProbe) {
+	ok := map[string]bool{}
+	tried := map[string]bool{}
+	for _, result := range probe.TCP {
+		tried[result.Family] = true
+		ok[result.Family] = ok[result.Family] || result.Connected
+	}
+	if len(probe.DNS.IPv6) == 0 {
+		probe.Notes = append(probe.Notes, "no AAAA records (IPv4 only)")
+	}
+	if tried["ipv6"] && !ok["ipv6"] && ok["ipv4"] {
+		probe.Notes = append(probe.Notes, "IPv6 addresses unreachable, IPv4 works")
+	}
+	if tried["ipv4"] && !ok["ipv4"] && ok["ipv6"] {
+		probe.Notes = append(probe.Notes, "IPv4 addresses unreachable, IPv6 works")
+	}
+}
+
+// handshake faz o handshake TLS com o endereço conectado, usando o domínio como SNI
+func (p *DomainProber) handshake(ctx context.Context, host, address string) *ProbeTLS {
+	result := &ProbeTLS{Address: address}
+	started := time.Now()
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: p.timeout}, Config: p.tlsClientConfig(host)}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	result.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	result.Version = tls.VersionName(state.Version)
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		result.Subject = cert.Subject.CommonName
+		result.Issuer = cert.Issuer.CommonName
+		result.NotAfter = cert.NotAfter
+	}
+	return result
+}
+
+// head envia um HEAD para a raiz do domínio, sem seguir redirecionamentos, por um collector
+// montado como os dos crawlers: User-Agent sorteado, middlewares de fetch (inclusive o proxy) e
+// auditoria. Sem proxy a conexão vai ao endereço já verificado; com proxy, só aos proxies
+// configurados
+func (p *DomainProber) head(ctx context.Context, scheme, host, address string) *ProbeHTTP {
+	result := &ProbeHTTP{URL: scheme + "://" + host + "/"}
+	dialer := &net.Dialer{Timeout: p.timeout}
+	proxies := make(map[string]bool)
+	if proxy, ok := CurrentFetchChain().Middleware(FetchMiddlewareProxy).(*ProxyFetchMiddleware); ok {
+		for _, proxyAddress := range proxy.Addresses() {
+			proxies[proxyAddress] = true
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, target string) (net.Conn, error) {
+		if targetHost, _, err := net.SplitHostPort(target); err == nil && strings.EqualFold(targetHost, host) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		if !proxies[target] {
+			return nil, fmt.Errorf("%w: %s", ErrPrivateAddress, target)
+		}
+		return dialer.DialContext(ctx, network, target)
+	}
+	transport.TLSClientConfig = p.tlsClientConfig(host)
+	transport.TLSHandshakeTimeout = p.timeout
+	defer transport.CloseIdleConnections()
+
+	c := colly.NewCollector()
+	extensions.RandomUserAgent(c)
+	c.SetRequestTimeout(2 * p.timeout)
+	c.ParseHTTPErrorResponse = true
+	c.RedirectHandler = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	c.WithTransport(transport)
+	fetchCollector(c, transport, "", PageLimits{})
+	c.OnRequest(func(r *colly.Request) {
+		if ctx.Err() != nil {
+			r.Abort()
+		}
+	})
+	c.OnResponse(func(r *colly.Response) {
+		result.StatusCode = r.StatusCode
+		result.Server = r.Headers.Get("Server")
+		result.Location = r.Headers.Get("Location")
+		for header, signal := range blockHeaders {
+			if r.Headers.Get(header) != "" {
+				result.BlockSignals = append(result.BlockSignals, signal)
+			}
+		}
+	})
+	var headErr error
+	c.OnError(func(_ *colly.Response, err error) {
+		headErr = err
+	})
+
+	started := time.Now()
+	if err := c.Head(result.URL); err != nil && headErr == nil {
+		headErr = err
+	}
+	result.DurationMs = time.Since(started).Milliseconds()
+	if headErr == nil && ctx.Err() != nil {
+		headErr = ctx.Err()
+	}
+	if headErr != nil {
+		result.Error = headErr.Error()
+		if isConnectionReset(headErr) {
+			result.BlockSignals = append(result.BlockSignals, "connection_reset")
+		}
+		return result
+	}
+
+	switch result.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests, http.StatusUnavailableForLegalReasons:
+		result.BlockSignals = append(result.BlockSignals, fmt.Sprintf("status_%d", result.StatusCode))
+	}
+	return result
+}
+
+// tlsClientConfig retorna a configuração TLS com o domínio como SNI
+func (p *DomainProber) tlsClientConfig(host string) *tls.Config {
+	config := &tls.Config{}
+	if p.tlsConfig != nil {
+		config = p.tlsConfig.Clone()
+	}
+	config.ServerName = host
+	return config
+}
+
+// probeVerdict resume o diagnóstico: bloqueio quando o servidor responde com status de bloqueio
+// (ou desafio de WAF com 503) ou derruba conexões já abertas; erro de TLS ou HTTP quando a rede
+// funciona mas a etapa falha
+func probeVerdict(probe *DomainProbe) string {
+	if probe.HTTP != nil {
+		for _, signal := range probe.HTTP.BlockSignals {
+			if strings.HasPrefix(signal, "status_") || signal == "connection_reset" {
+				return ProbeVerdictBlocked
+			}
+		}
+		if probe.HTTP.StatusCode == http.StatusServiceUnavailable && len(probe.HTTP.BlockSignals) > 0 {
+			return ProbeVerdictBlocked
+		}
+	}
+	if probe.TLS != nil && probe.TLS.Error != "" {
+		if isResetMessage(probe.TLS.Error) {
+			return ProbeVerdictBlocked
+		}
+		return ProbeVerdictTLSError
+	}
+	if probe.HTTP == nil || probe.HTTP.Error != "" || probe.HTTP.StatusCode >= 500 {
+		return ProbeVerdictHTTPError
+	}
+	return ProbeVerdictOK
+}
+
+// isConnectionReset informa se a conexão foi derrubada pelo servidor (ou por um firewall no caminho)
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || isResetMessage(err.Error())
+}
+
+// isResetMessage reconhece as mensagens de conexão derrubada no meio da troca
+func isResetMessage(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "connection reset") || strings.Contains(message, "eof")
+}
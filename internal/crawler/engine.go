@@ -2,10 +2,12 @@ package crawler
 
 import (
 	"context"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/gocolly/colly"
@@ -17,11 +19,19 @@ type CrawlerEngine struct {
 	extractor  *DataExtractor
 	urlManager *URLManager
 	validator  *PropertyValidator
+	multiUnit  *MultiUnitDetector
+	segmenter  *CatalogSegmenter
+	discovery  *DiscoveryTracker
 	repository repository.PropertyRepository
 	aiService  *ai.GeminiService
 	logger     *logger.Logger
 	config     *CrawlerConfig
 	stats      *CrawlerStats
+	allowlist  *DomainAllowlist // modo estrito de domínios (nil desativado)
+	frontier   *SharedFrontier  // registro de URLs em andamento compartilhado com jobs simultâneos
+	runtime    *RuntimeState    // configuração recarregável (seletores por domínio)
+
+	crawlLifecycle
 }
 
 // CrawlerConfig contém configurações do crawler
@@ -33,6 +43,9 @@ type CrawlerConfig struct {
 	EnableAI       bool
 	BatchSize      int
 	RequestTimeout time.Duration
+
+	// Transport substitui o cliente HTTP do coletor (testes, benchmark com páginas gravadas)
+	Transport http.RoundTripper
 }
 
 // CrawlerStats mantém estatísticas do crawler
@@ -61,12 +74,62 @@ func NewCrawlerEngine(repo repository.PropertyRepository, aiService *ai.GeminiSe
 		extractor:  NewDataExtractor(),
 		urlManager: NewURLManager(),
 		validator:  NewPropertyValidator(),
+		multiUnit:  NewMultiUnitDetector(),
+		segmenter:  NewCatalogSegmenter(),
+		discovery:  NewDiscoveryTracker(),
 		repository: repo,
 		aiService:  aiService,
 		logger:     logger.NewLogger("crawler_engine"),
 		config:     config,
 		stats:      &CrawlerStats{StartTime: time.Now()},
+		runtime:    NewRuntimeState(),
+	}
+}
+
+// ApplyRuntimeConfig aplica configuração recarregada sem interromper o crawling
+func (ce *CrawlerEngine) ApplyRuntimeConfig(snapshot *config.RuntimeSnapshot) {
+	ce.runtime.Set(snapshot)
+	if snapshot != nil {
+		ce.logger.WithField("version", snapshot.Version).Info("Runtime config applied")
+	}
+}
+
+// SetTransport define o transporte HTTP usado pelo coletor nas próximas execuções
+func (ce *CrawlerEngine) SetTransport(transport http.RoundTripper) {
+	ce.config.Transport = transport
+}
+
+// SetDomainAllowlist ativa o modo estrito de domínios: só são seguidos links para os domínios
+// das seeds
+func (ce *CrawlerEngine) SetDomainAllowlist(allowlist *DomainAllowlist) {
+	ce.allowlist = allowlist
+}
+
+// SetSharedFrontier ativa o registro compartilhado de URLs em andamento: URLs buscadas por
+// outro job simultâneo são puladas
+func (ce *CrawlerEngine) SetSharedFrontier(frontier *SharedFrontier) {
+	ce.frontier = frontier
+}
+
+// RegisterShutdown registra as etapas de encerramento coordenado deste engine
+func (ce *CrawlerEngine) RegisterShutdown(sc *ShutdownCoordinator, checkpointPath string) func() {
+	var aiService pendingAIFlusher
+	if ce.aiService != nil {
+		aiService = ce.aiService
 	}
+
+	return registerCrawlShutdown(sc, "full", &ce.crawlLifecycle, aiService, ce.repository, func() CrawlCheckpoint {
+		stats := ce.GetStats()
+		return CrawlCheckpoint{
+			PendingURLs: ce.PendingSeeds(),
+			VisitedURLs: stats.URLsVisited,
+			Stats: map[string]interface{}{
+				"properties_found": stats.PropertiesFound,
+				"properties_saved": stats.PropertiesSaved,
+				"errors":           stats.ErrorsCount,
+			},
+		}
+	}, checkpointPath)
 }
 
 // Start inicia o processo de crawling
@@ -76,6 +139,14 @@ func (ce *CrawlerEngine) Start(ctx context.Context, urls []string) error {
 		"config":       ce.config,
 	}).Info("Starting crawler engine")
 
+	ce.beginCrawl(urls)
+	defer ce.endCrawl()
+
+	// No modo estrito, os domínios das seeds são os únicos permitidos
+	if ce.allowlist != nil {
+		ce.allowlist.Add(urls...)
+	}
+
 	// Configura o coletor principal
 	collector := ce.setupCollector()
 
@@ -84,11 +155,18 @@ func (ce *CrawlerEngine) Start(ctx context.Context, urls []string) error {
 
 	// Inicia o crawling
 	for _, url := range urls {
+		if ce.IntakeStopped() {
+			ce.logger.WithField("pending_urls", len(ce.PendingSeeds())).Info("Intake stopped, skipping remaining seed URLs")
+			break
+		}
+
+		ce.markSeedStarted(url)
 		if ce.urlManager.ShouldSkipURL(url) {
 			continue
 		}
 
 		ce.logger.WithField("url", url).Info("Starting crawl")
+		ce.discovery.RecordSeed(url)
 		if err := collector.Visit(url); err != nil {
 			ce.logger.WithField("url", url).Error("Failed to visit initial URL", err)
 			ce.incrementErrorCount()
@@ -98,8 +176,9 @@ func (ce *CrawlerEngine) Start(ctx context.Context, urls []string) error {
 	// Aguarda conclusão
 	collector.Wait()
 
-	// Processa buffer restante da IA
-	if ce.aiService != nil {
+	// Processa buffer restante da IA (no encerramento coordenado o flush é feito
+	// pelo ShutdownCoordinator, com contexto próprio e prazo definido)
+	if ce.aiService != nil && !ce.IntakeStopped() {
 		if err := ce.aiService.FlushBatch(ctx); err != nil {
 			ce.logger.Error("Failed to flush AI batch", err)
 		}
@@ -107,6 +186,7 @@ func (ce *CrawlerEngine) Start(ctx context.Context, urls []string) error {
 
 	// Log das estatísticas finais
 	ce.logFinalStats()
+	logRejectedDomains(ce.logger, ce.allowlist)
 
 	return nil
 }
@@ -131,36 +211,71 @@ func (ce *CrawlerEngine) setupCollector() *colly.Collector {
 
 	// Timeout para requisições
 	c.SetRequestTimeout(ce.config.RequestTimeout)
+	if ce.config.Transport != nil {
+		c.WithTransport(ce.config.Transport)
+	}
+	fetchCollector(c, ce.config.Transport, "", PageLimits{})
+
+	// Modo estrito de domínios: redirecionamentos para fora da allowlist não são seguidos
+	if ce.allowlist != nil {
+		c.RedirectHandler = ce.allowlist.redirectHandler(nil)
+	}
 
 	return c
 }
 
 // setupHandlers configura os handlers do coletor
 func (ce *CrawlerEngine) setupHandlers(ctx context.Context, c *colly.Collector) {
+	// Callbacks protegidos: um panic em uma página conta como erro e o crawling continua
+	recovery := NewCallbackRecovery("crawler_engine", nil)
+	recovery.SetPanicHook(func(string) { ce.incrementErrorCount() })
+
 	// Handler para encontrar links de propriedades
-	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	c.OnHTML("a[href]", recovery.HTML(func(e *colly.HTMLElement) {
 		ce.handlePropertyLinks(e, c)
-	})
+	}))
 
 	// Handler principal para extrair dados
-	c.OnHTML("body", func(e *colly.HTMLElement) {
+	c.OnHTML("body", recovery.HTML(func(e *colly.HTMLElement) {
 		ce.handlePropertyData(ctx, e)
-	})
+	}))
 
 	// Handler para requisições
-	c.OnRequest(func(r *colly.Request) {
+	c.OnRequest(recovery.Request(func(r *colly.Request) {
+		if ce.IntakeStopped() {
+			r.Abort()
+			return
+		}
+		if ce.frontier != nil && !ce.frontier.Claim(r.URL.String()) {
+			r.Abort()
+			return
+		}
 		ce.logger.WithField("url", r.URL.String()).Debug("Visiting URL")
 		ce.incrementURLsVisited()
-	})
+	}))
+
+	c.OnResponse(recovery.Response(func(r *colly.Response) {
+		emitPageFetched(r)
+	}))
 
 	// Handler para erros
-	c.OnError(func(r *colly.Response, err error) {
+	c.OnError(recovery.Error(func(r *colly.Response, err error) {
 		ce.logger.WithFields(map[string]interface{}{
 			"url":         r.Request.URL.String(),
 			"status_code": r.StatusCode,
 		}).Error("Request failed", err)
+		emitEvent(EventError, r.Request.URL.String(), map[string]interface{}{
+			"error_type":  ErrorTypeFetch,
+			"message":     err.Error(),
+			"status_code": r.StatusCode,
+		})
 		ce.incrementErrorCount()
-	})
+
+		// A URL volta a ficar disponível para outros jobs tentarem
+		if ce.frontier != nil {
+			ce.frontier.Release(r.Request.URL.String())
+		}
+	}))
 }
 
 // handlePropertyLinks processa links encontrados na página
@@ -173,6 +288,11 @@ func (ce *CrawlerEngine) handlePropertyLinks(e *colly.HTMLElement, c *colly.Coll
 		return
 	}
 
+	// Modo estrito de domínios: links para domínios fora das seeds são recusados
+	if ce.allowlist != nil && !ce.allowlist.Allows(absoluteLink, nil) {
+		return
+	}
+
 	// Verifica se parece ser um link de propriedade
 	if ce.urlManager.IsValidPropertyLink(absoluteLink, e.Request.URL.Host) {
 		ce.urlManager.MarkVisited(absoluteLink)
@@ -182,6 +302,7 @@ func (ce *CrawlerEngine) handlePropertyLinks(e *colly.HTMLElement, c *colly.Coll
 			ce.urlManager.CleanupOldURLs(ce.config.MaxURLs / 2)
 		}
 
+		ce.discovery.RecordLink(e.Request.URL.String(), absoluteLink)
 		c.Visit(absoluteLink)
 	}
 }
@@ -192,6 +313,7 @@ func (ce *CrawlerEngine) handlePropertyData(ctx context.Context, e *colly.HTMLEl
 
 	// Verifica se é uma página de catálogo
 	if ce.urlManager.IsCatalogPage(e) {
+		emitPageClassified(url, "catalog", 0, "")
 		ce.handleCatalogPage(ctx, e)
 		return
 	}
@@ -199,12 +321,17 @@ func (ce *CrawlerEngine) handlePropertyData(ctx context.Context, e *colly.HTMLEl
 	// NOVA VALIDAÇÃO: Só processa se for realmente uma página de anúncio individual
 	if !ce.urlManager.IsPropertyPage(e) {
 		ce.logger.WithField("url", url).Debug("Page is not a property page, skipping data extraction")
+		emitPageClassified(url, "rejected", 0, "")
 		return
 	}
+	emitPageClassified(url, "property", 0, "")
 
 	// Extrai dados da propriedade
 	property := ce.extractor.ExtractProperty(e, url)
 	ce.incrementPropertiesFound()
+	if selectors := ce.runtime.Selectors(url); property != nil && len(selectors) > 0 {
+		ce.extractor.ApplyDomainSelectors(e, property, selectors)
+	}
 
 	// Valida os dados
 	validation := ce.validator.ValidateProperty(property)
@@ -218,33 +345,50 @@ func (ce *CrawlerEngine) handlePropertyData(ctx context.Context, e *colly.HTMLEl
 
 	// Melhora os dados
 	property = ce.validator.EnhanceProperty(property)
+	extraction := repository.NewExtractionSnapshot(*property)
 
 	// Processa com IA se disponível e necessário
 	if ce.config.EnableAI && ce.aiService != nil {
 		if processedProperty, err := ce.aiService.ProcessPropertyData(ctx, *property); err == nil {
+			repository.RecordAIReview(*property, &processedProperty)
 			property = &processedProperty
 		} else {
 			ce.logger.WithField("url", url).Warn("AI processing failed, using original data")
 		}
 	}
 
-	// Salva no repositório
-	if err := ce.repository.Save(ctx, *property); err != nil {
-		ce.logger.WithField("url", url).Error("Failed to save property", err)
-		ce.incrementErrorCount()
-	} else {
-		ce.incrementPropertiesSaved()
-		ce.logger.WithFields(map[string]interface{}{
-			"endereco": property.Endereco,
-			"valor":    property.Valor,
-			"tipo":     property.TipoImovel,
-		}).Info("Property saved successfully")
+	// Guarda a extração original para a comparação da próxima visita
+	property.Extracao = extraction
+
+	// Registra como o anúncio foi descoberto
+	ce.discovery.Apply(property)
+
+	// Salva no repositório (anúncios multi-unidade geram um registro por unidade)
+	for _, unit := range ce.multiUnit.Split(property) {
+		if err := ce.repository.Save(ctx, *unit); err != nil {
+			ce.logger.WithField("url", url).Error("Failed to save property", err)
+			emitEvent(EventError, url, map[string]interface{}{
+				"error_type": ErrorTypeStorage,
+				"message":    err.Error(),
+			})
+			ce.incrementErrorCount()
+		} else {
+			ce.incrementPropertiesSaved()
+			emitPropertySaved(url, unit)
+			ce.logger.WithFields(map[string]interface{}{
+				"endereco":  unit.Endereco,
+				"valor":     unit.Valor,
+				"tipo":      unit.TipoImovel,
+				"parent_id": unit.ParentID,
+			}).Info("Property saved successfully")
+		}
 	}
 }
 
 // handleCatalogPage processa páginas de catálogo
 func (ce *CrawlerEngine) handleCatalogPage(ctx context.Context, e *colly.HTMLElement) {
 	ce.logger.WithField("url", e.Request.URL.String()).Info("Processing catalog page")
+	ce.discovery.RecordKind(e.Request.URL.String(), DiscoveryKindCatalog)
 
 	// Extrai links individuais
 	links := ce.urlManager.ExtractPropertyLinks(e)
@@ -260,21 +404,44 @@ func (ce *CrawlerEngine) extractIndividualPropertiesFromCatalog(ctx context.Cont
 	url := e.Request.URL.String()
 	var properties []*repository.Property
 
-	// Procura por elementos que representam propriedades individuais
-	e.ForEach(".imovel, .property, .card, .item, .listing", func(i int, el *colly.HTMLElement) {
-		if i >= ce.config.BatchSize { // Limita para evitar spam
-			return
+	// Segmenta o catálogo em cards (estruturas DOM irmãs repetidas) e liga cada card à sua página de detalhes
+	cards := ce.segmenter.Segment(e.DOM, e.Request.URL)
+	for _, card := range cards {
+		if len(card.Selection.Nodes) == 0 {
+			continue
+		}
+
+		propertyURL := url
+		if card.DetailURL != "" {
+			propertyURL = card.DetailURL
 		}
 
-		property := ce.extractor.ExtractProperty(el, url)
+		el := colly.NewHTMLElementFromSelectionNode(e.Response, card.Selection, card.Selection.Nodes[0], card.Index)
+		property := ce.extractor.ExtractProperty(el, propertyURL)
 		if ce.validator.IsValidForSaving(property) {
 			properties = append(properties, property)
 		}
-	})
+	}
+
+	// Fallback: procura por classes comuns quando a segmentação não encontra cards
+	if len(cards) == 0 {
+		e.ForEach(".imovel, .property, .card, .item, .listing", func(i int, el *colly.HTMLElement) {
+			if i >= ce.config.BatchSize { // Limita para evitar spam
+				return
+			}
+
+			property := ce.extractor.ExtractProperty(el, url)
+			if ce.validator.IsValidForSaving(property) {
+				properties = append(properties, property)
+			}
+		})
+	}
 
 	// Salva as propriedades encontradas
 	for _, property := range properties {
 		ce.incrementPropertiesFound()
+		ce.discovery.RecordLink(url, property.URL)
+		ce.discovery.Apply(property)
 
 		if err := ce.repository.Save(ctx, *property); err != nil {
 			ce.logger.Error("Failed to save catalog property", err)
@@ -286,6 +453,7 @@ func (ce *CrawlerEngine) extractIndividualPropertiesFromCatalog(ctx context.Cont
 
 	ce.logger.WithFields(map[string]interface{}{
 		"url":        url,
+		"cards":      len(cards),
 		"properties": len(properties),
 	}).Info("Catalog properties processed")
 }
@@ -2,10 +2,29 @@ package crawler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/readability"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -158,6 +177,3122 @@ func TestPropertyValidator_EnhanceProperty(t *testing.T) {
 	assert.NotEmpty(t, enhanced.ValorTexto)
 }
 
+func TestMultiUnitDetector_Split(t *testing.T) {
+	detector := NewMultiUnitDetector()
+
+	property := &repository.Property{
+		URL:        "https://example.com/lancamento/residencial-1",
+		Descricao:  "Apartamentos de 2 e 3 quartos, 55 e 72 m², a partir de R$ 320.000",
+		Cidade:     "Belo Horizonte",
+		ValorTexto: "A partir de R$ 320.000",
+		Valor:      320000,
+	}
+
+	units := detector.Split(property)
+	assert.Len(t, units, 2)
+	assert.Equal(t, 2, units[0].Quartos)
+	assert.Equal(t, 3, units[1].Quartos)
+	assert.Equal(t, 55.0, units[0].AreaTotal)
+	assert.Equal(t, 72.0, units[1].AreaTotal)
+	assert.Equal(t, 320000.0, units[0].Valor)
+	assert.Equal(t, 0.0, units[1].Valor)
+	assert.NotEmpty(t, units[0].ParentID)
+	assert.Equal(t, units[0].ParentID, units[1].ParentID)
+	assert.NotEqual(t, repository.GeneratePropertyHash(*units[0]), repository.GeneratePropertyHash(*units[1]))
+
+	single := &repository.Property{
+		URL:       "https://example.com/imovel/123",
+		Descricao: "Casa com 3 quartos e 2 banheiros",
+	}
+	units = detector.Split(single)
+	assert.Len(t, units, 1)
+	assert.Empty(t, units[0].ParentID)
+}
+
+func TestLoteamentoDetector_Split(t *testing.T) {
+	detector := NewMultiUnitDetector()
+
+	// Lotes descritos um a um: projeto + um registro por lote
+	property := &repository.Property{
+		URL:        "https://example.com/loteamento/jardim-das-flores",
+		TipoImovel: "Terreno",
+		Descricao: "Loteamento Jardim das Flores com 120 lotes. Disponíveis: Quadra A Lote 12 - 250 m² - R$ 90.000; " +
+			"Quadra A Lote 13 - 250 m² - R$ 90.000; Quadra B Lote 2 - 360 m² - R$ 125.000",
+		Cidade: "Muzambinho",
+		Valor:  90000,
+	}
+	records := detector.Split(property)
+	assert.Len(t, records, 4)
+	project := records[0]
+	assert.NotNil(t, project.Loteamento)
+	assert.Equal(t, "Jardim das Flores", project.Loteamento.Nome)
+	assert.Equal(t, 120, project.Loteamento.TotalLotes)
+	assert.Equal(t, 250.0, project.Loteamento.AreaMinima)
+	assert.Equal(t, 360.0, project.Loteamento.AreaMaxima)
+	assert.Equal(t, 90000.0, project.Loteamento.PrecoMinimo)
+	assert.Equal(t, 125000.0, project.Loteamento.PrecoMaximo)
+	assert.Equal(t, 0.0, project.Valor)
+	assert.Equal(t, "Quadra A, Lote 12", records[1].Lote)
+	assert.Equal(t, "Quadra B, Lote 2", records[3].Lote)
+	assert.Equal(t, 360.0, records[3].AreaTotal)
+	assert.Equal(t, 125000.0, records[3].Valor)
+	assert.Equal(t, repository.TipoTerreno, records[1].TipoImovel)
+	for _, record := range records[1:] {
+		assert.Nil(t, record.Loteamento)
+		assert.Equal(t, project.ParentID, record.ParentID)
+	}
+	// Lotes iguais continuam com hash e ID curto próprios
+	assert.NotEqual(t, repository.GeneratePropertyHash(*records[1]), repository.GeneratePropertyHash(*records[2]))
+	assert.NotEqual(t, repository.GenerateShortID(*records[1]), repository.GenerateShortID(*records[2]))
+	assert.NotEqual(t, repository.GenerateShortID(*project), repository.GenerateShortID(*records[1]))
+
+	// Lista de áreas com preço "a partir de" para o menor lote
+	records = detector.Split(&repository.Property{
+		URL:       "https://example.com/loteamento/portal-do-sol",
+		Descricao: "Venda de lotes no Loteamento Portal do Sol: lotes de 300, 450 e 600 m², a partir de R$ 70.000",
+	})
+	assert.Len(t, records, 4)
+	assert.Equal(t, "Portal do Sol", records[0].Loteamento.Nome)
+	assert.Equal(t, "Lote de 300 m²", records[1].Lote)
+	assert.Equal(t, 70000.0, records[1].Valor)
+	assert.Equal(t, 600.0, records[3].AreaTotal)
+	assert.Equal(t, 0.0, records[3].Valor)
+
+	// Faixa de áreas sem lotes descritos: só o registro do projeto
+	records = detector.Split(&repository.Property{
+		URL:       "https://example.com/loteamento/recanto",
+		Descricao: "Loteamento Recanto Verde, lotes de 250 a 500 m² com infraestrutura completa",
+	})
+	assert.Len(t, records, 1)
+	assert.Equal(t, 250.0, records[0].Loteamento.AreaMinima)
+	assert.Equal(t, 500.0, records[0].Loteamento.AreaMaxima)
+
+	// Lote avulso que só cita o loteamento continua um único imóvel
+	single := &repository.Property{
+		URL:       "https://example.com/imovel/terreno-42",
+		Descricao: "Terreno de 300 m² no Loteamento Jardim das Flores, escriturado",
+		Valor:     95000,
+	}
+	records = detector.Split(single)
+	assert.Len(t, records, 1)
+	assert.Same(t, single, records[0])
+	assert.Nil(t, records[0].Loteamento)
+
+	// A quantidade de lotes do loteamento não transforma o lote avulso em projeto
+	single = &repository.Property{
+		URL:       "https://example.com/imovel/terreno-43",
+		Descricao: "Terreno de 300 m² no Loteamento Jardim das Flores, que possui 200 lotes",
+		Valor:     95000,
+		AreaTotal: 300,
+	}
+	records = detector.Split(single)
+	assert.Len(t, records, 1)
+	assert.Same(t, single, records[0])
+	assert.Equal(t, 95000.0, records[0].Valor)
+	assert.Equal(t, 300.0, records[0].AreaTotal)
+
+	// Projeto sem lotes descritos: o preço e a área extraídos continuam em um registro filho
+	records = detector.Split(&repository.Property{
+		URL:       "https://example.com/loteamento/recanto-azul",
+		Descricao: "Loteamento Recanto Azul, lotes de 250 a 500 m², a partir de R$ 80.000",
+		Valor:     80000,
+		AreaTotal: 250,
+	})
+	assert.Len(t, records, 2)
+	assert.Equal(t, 0.0, records[0].Valor)
+	assert.NotNil(t, records[0].Loteamento)
+	assert.Equal(t, 80000.0, records[1].Valor)
+	assert.Equal(t, 250.0, records[1].AreaTotal)
+	assert.Equal(t, records[0].ParentID, records[1].ParentID)
+	assert.Nil(t, records[1].Loteamento)
+}
+
+func TestCatalogSegmenter_Segment(t *testing.T) {
+	html := `<html><body>
+		<nav><ul><li><a href="/">Início</a></li><li><a href="/sobre">Sobre</a></li><li><a href="/contato">Contato</a></li></ul></nav>
+		<div class="grid">
+			<div class="box destaque"><h3>Casa no Centro</h3><p>3 quartos, 120 m²</p><span>R$ 450.000</span><a href="/imovel/101">Ver</a></div>
+			<div class="box"><h3>Apartamento Jardim Europa</h3><p>2 quartos, 65 m²</p><span>R$ 280.000</span><a href="/imovel/102">Ver</a></div>
+			<div class="box"><h3>Terreno Vila Nova</h3><p>300 m²</p><span>R$ 120.000</span><a href="https://wa.me/5535999999999">WhatsApp</a><a href="detalhes.php?id=103">Ver</a></div>
+		</div>
+	</body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+	baseURL, _ := url.Parse("https://imobiliaria.com.br/catalogo/")
+
+	cards := NewCatalogSegmenter().Segment(doc.Selection, baseURL)
+	assert.Len(t, cards, 3)
+	assert.Contains(t, cards[0].Text, "Casa no Centro")
+	assert.Equal(t, "https://imobiliaria.com.br/imovel/101", cards[0].DetailURL)
+	assert.Equal(t, "https://imobiliaria.com.br/catalogo/detalhes.php?id=103", cards[2].DetailURL)
+}
+
+func TestSmartNavigationManager_StandardPagination(t *testing.T) {
+	links := ParseLinkHeader(`<https://site.com/imoveis?page=3>; rel="next", <https://site.com/imoveis?page=1>; rel="prev first"`)
+	assert.Equal(t, []string{"https://site.com/imoveis?page=3"}, links["next"])
+	assert.Equal(t, []string{"https://site.com/imoveis?page=1"}, links["prev"])
+	assert.Equal(t, []string{"https://site.com/imoveis?page=1"}, links["first"])
+
+	html := `<html><head><link rel="next" href="/imoveis/pagina/3"></head><body>
+		<div class="pagination"><a href="/imoveis/pagina/1">1</a><a href="/imoveis/pagina/2">2</a><a href="/imoveis/pagina/9">9</a></div>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+
+	snm := NewSmartNavigationManager()
+	result := snm.extractPaginationLinks(doc, "https://site.com/imoveis/pagina/2", "")
+	assert.Equal(t, []string{"https://site.com/imoveis/pagina/3"}, result)
+
+	// Sem rel=next, recorre às heurísticas de texto
+	doc.Find("link[rel]").Remove()
+	result = snm.extractPaginationLinks(doc, "https://site.com/imoveis/pagina/2", "")
+	assert.Contains(t, result, "https://site.com/imoveis/pagina/9")
+}
+
+func TestDiscoveryTracker_Apply(t *testing.T) {
+	tracker := NewDiscoveryTracker()
+	tracker.RecordSeed("https://site.com")
+	tracker.RecordLink("https://site.com", "https://site.com/imoveis")
+	tracker.RecordKind("https://site.com/imoveis", DiscoveryKindCatalog)
+	tracker.RecordLink("https://site.com/imoveis", "https://site.com/imoveis?page=2")
+	tracker.RecordLink("https://site.com/imoveis?page=2", "https://site.com/imovel/55")
+	// Uma segunda descoberta não deve sobrescrever o primeiro caminho
+	tracker.RecordLink("https://site.com/outra", "https://site.com/imovel/55")
+
+	property := &repository.Property{URL: "https://site.com/imovel/55"}
+	tracker.Apply(property)
+
+	assert.Equal(t, 3, property.DiscoveryDepth)
+	assert.Len(t, property.DiscoveryPath, 4)
+	assert.Equal(t, DiscoveryKindSeed, property.DiscoveryPath[0].Kind)
+	assert.Equal(t, DiscoveryKindCatalog, property.DiscoveryPath[1].Kind)
+	assert.Equal(t, DiscoveryKindPagination, property.DiscoveryPath[2].Kind)
+	assert.Equal(t, 2, property.DiscoveryPath[2].Page)
+	assert.Equal(t, DiscoveryKindProperty, property.DiscoveryPath[3].Kind)
+}
+
+func TestDiscoveryTracker_Sources(t *testing.T) {
+	tracker := NewDiscoveryTracker()
+	tracker.RecordSeed("https://site.com")
+	tracker.RecordSeed("https://site.com/imovel/1")
+
+	// seed → catálogo → página 2 → anúncio: a paginação levou ao anúncio
+	tracker.RecordLink("https://site.com", "https://site.com/imoveis")
+	tracker.RecordLink("https://site.com/imoveis", "https://site.com/imoveis?page=2")
+	tracker.RecordLink("https://site.com/imoveis?page=2", "https://site.com/imovel/2")
+	tracker.RecordSource("https://site.com/imovel/2", repository.DiscoverySourceClassifiedLink)
+
+	// seed → formulário gerado → anúncio
+	tracker.RecordLink("https://site.com", "https://site.com/busca?cidade=muzambinho")
+	tracker.RecordSource("https://site.com/busca?cidade=muzambinho", repository.DiscoverySourceInteractiveForm)
+	tracker.RecordLink("https://site.com/busca?cidade=muzambinho", "https://site.com/imovel/3")
+
+	// feed → anúncio, seed → anúncio e seed → página → página → anúncio
+	tracker.RecordKind("https://site.com/feed.xml", DiscoveryKindFeed)
+	tracker.RecordLink("https://site.com/feed.xml", "https://site.com/imovel/4")
+	tracker.RecordLink("https://site.com", "https://site.com/imovel/5")
+	tracker.RecordLink("https://site.com/imoveis", "https://site.com/imoveis/centro")
+	tracker.RecordLink("https://site.com/imoveis/centro", "https://site.com/imovel/6")
+
+	expected := map[string]string{
+		"https://site.com/imovel/1": repository.DiscoverySourceSeed,
+		"https://site.com/imovel/2": repository.DiscoverySourcePagination,
+		"https://site.com/imovel/3": repository.DiscoverySourceInteractiveForm,
+		"https://site.com/imovel/4": repository.DiscoverySourceFeed,
+		"https://site.com/imovel/5": repository.DiscoverySourceSeed,
+		"https://site.com/imovel/6": repository.DiscoverySourceLink,
+	}
+	for url, source := range expected {
+		property := &repository.Property{URL: url}
+		tracker.Apply(property)
+		assert.Equal(t, source, property.DiscoverySource, url)
+	}
+
+	for _, url := range []string{"https://site.com", "https://site.com/busca?cidade=muzambinho", "https://site.com/imovel/3", "https://www.site.com/imoveis?page=2"} {
+		tracker.RecordVisit(url)
+	}
+	yields := tracker.SourceYields()["site.com"]
+	assert.Equal(t, repository.SourceYield{Visited: 2, Properties: 1}, yields[repository.DiscoverySourceInteractiveForm])
+	assert.Equal(t, repository.SourceYield{Visited: 1, Properties: 2}, yields[repository.DiscoverySourceSeed])
+	assert.Equal(t, 1, yields[repository.DiscoverySourcePagination].Properties)
+}
+
+func TestDiscoveryTracker_Cap(t *testing.T) {
+	tracker := NewDiscoveryTracker()
+	tracker.maxURLs = 3
+	tracker.RecordSeed("https://site.com")
+	tracker.RecordLink("https://site.com", "https://site.com/imoveis")
+	tracker.RecordSource("https://site.com/imoveis", repository.DiscoverySourceInteractiveForm)
+	for i := 1; i <= 5; i++ {
+		tracker.RecordLink("https://site.com/imoveis", fmt.Sprintf("https://site.com/imovel/%d", i))
+	}
+
+	// As URLs mais antigas são esquecidas; as seeds ficam
+	assert.Len(t, tracker.parents, 3)
+	assert.Empty(t, tracker.sources)
+	assert.Equal(t, DiscoveryKindSeed, tracker.kinds["https://site.com"])
+	assert.Len(t, tracker.Path("https://site.com/imovel/1"), 1)
+	assert.Len(t, tracker.Path("https://site.com/imovel/5"), 2)
+
+	// Uma URL esquecida pode ser registrada de novo
+	tracker.RecordLink("https://site.com", "https://site.com/imovel/1")
+	assert.Len(t, tracker.parents, 3)
+	assert.Len(t, tracker.Path("https://site.com/imovel/1"), 2)
+}
+
+func TestConfigWatcher_HotReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "runtime.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"default_delay_ms": 0, "ai_threshold_minutes": 30, "blacklist": ["/blog/"]}`), 0644))
+
+	source := &staticDomainConfigSource{configs: []repository.DomainConfig{
+		{Domain: "site.com", ExcludePatterns: []string{"/aluguel/"}},
+	}}
+	watcher := config.NewConfigWatcher(path, source, time.Minute)
+	assert.NoError(t, watcher.Reload(context.Background()))
+
+	engine := NewSimpleRecursiveCrawler(nil, nil)
+	watcher.Register(engine)
+
+	assert.False(t, engine.runtime.AllowsURL("https://site.com/blog/post"))
+	assert.False(t, engine.runtime.AllowsURL("https://www.site.com/aluguel/casa"))
+	assert.True(t, engine.runtime.AllowsURL("https://site.com/venda/casa"))
+	assert.Equal(t, 30*time.Minute, engine.runtime.AIThreshold())
+
+	// Remover a configuração do domínio deve liberar as URLs sem reiniciar o engine
+	source.configs = nil
+	assert.NoError(t, watcher.Reload(context.Background()))
+	assert.True(t, engine.runtime.AllowsURL("https://site.com/aluguel/casa"))
+
+	audit := watcher.AuditLog()
+	assert.NotEmpty(t, audit)
+	last := audit[len(audit)-1]
+	assert.Equal(t, "domain:site.com", last.Source)
+	assert.Equal(t, "deleted", last.NewValue)
+	assert.Equal(t, 2, watcher.Current().Version)
+}
+
+func TestConfigWatcher_DiscoverySourcesOnlyChange(t *testing.T) {
+	source := &staticDomainConfigSource{configs: []repository.DomainConfig{{Domain: "site.com"}}}
+	watcher := config.NewConfigWatcher("", source, time.Minute)
+	assert.NoError(t, watcher.Reload(context.Background()))
+
+	engine := NewSimpleRecursiveCrawler(nil, nil)
+	watcher.Register(engine)
+	assert.True(t, engine.runtime.DiscoverySourceEnabled("https://site.com/", repository.DiscoverySourceFeed))
+
+	// Só as estratégias desativadas mudam: a nova configuração chega ao engine
+	source.configs = []repository.DomainConfig{{Domain: "site.com", DisabledDiscoverySources: []string{repository.DiscoverySourceFeed}}}
+	assert.NoError(t, watcher.Reload(context.Background()))
+	assert.False(t, engine.runtime.DiscoverySourceEnabled("https://site.com/", repository.DiscoverySourceFeed))
+	assert.True(t, engine.runtime.DiscoverySourceEnabled("https://site.com/", repository.DiscoverySourcePagination))
+	last := watcher.AuditLog()[len(watcher.AuditLog())-1]
+	assert.Equal(t, "disabled_discovery_sources", last.Field)
+
+	// Campos sem comparação própria também geram uma nova versão
+	source.configs = []repository.DomainConfig{{Domain: "site.com", DisabledDiscoverySources: []string{repository.DiscoverySourceFeed}, Notes: "revisado"}}
+	assert.NoError(t, watcher.Reload(context.Background()))
+	assert.Equal(t, "revisado", watcher.Current().Domains["site.com"].Notes)
+
+	// Apenas a data de atualização mudou: nada a aplicar
+	version := watcher.Current().Version
+	source.configs[0].UpdatedAt = time.Now()
+	assert.NoError(t, watcher.Reload(context.Background()))
+	assert.Equal(t, version, watcher.Current().Version)
+}
+
+func TestConfigWatcher_SkipPathsOnlyChange(t *testing.T) {
+	source := &staticDomainConfigSource{configs: []repository.DomainConfig{{Domain: "site.com", ExcludePatterns: []string{"/aluguel/"}}}}
+	watcher := config.NewConfigWatcher("", source, time.Minute)
+	assert.NoError(t, watcher.Reload(context.Background()))
+
+	engine := NewSimpleRecursiveCrawler(nil, nil)
+	watcher.Register(engine)
+	assert.True(t, engine.runtime.AllowsURL("https://site.com/blog/post"))
+
+	// O crawler aprendeu um caminho sem anúncios: a nova configuração chega ao engine
+	source.configs = []repository.DomainConfig{{Domain: "site.com", ExcludePatterns: []string{"/aluguel/"}, LearnedSkipPaths: []string{"/blog"}}}
+	assert.NoError(t, watcher.Reload(context.Background()))
+	assert.False(t, engine.runtime.AllowsURL("https://site.com/blog/post"))
+	assert.False(t, engine.runtime.AllowsURL("https://site.com/aluguel/casa"))
+	assert.True(t, engine.runtime.AllowsURL("https://site.com/venda/casa"))
+	last := watcher.AuditLog()[len(watcher.AuditLog())-1]
+	assert.Equal(t, "learned_skip_paths", last.Field)
+
+	// Padrões inválidos são ignorados; só inválidos na inclusão não incluem nenhuma URL
+	snapshot := &config.RuntimeSnapshot{Domains: map[string]repository.DomainConfig{
+		"a.com": {Domain: "a.com", ExcludePatterns: []string{"("}},
+		"b.com": {Domain: "b.com", IncludePatterns: []string{"("}},
+	}}
+	rules, exists := snapshot.URLRules("www.a.com")
+	assert.True(t, exists)
+	assert.True(t, rules.Allows("https://a.com/imovel/1"))
+	rules, _ = snapshot.URLRules("b.com")
+	assert.False(t, rules.Allows("https://b.com/imovel/1"))
+	_, exists = snapshot.URLRules("c.com")
+	assert.False(t, exists)
+}
+
+type staticDomainConfigSource struct {
+	configs []repository.DomainConfig
+}
+
+func (s *staticDomainConfigSource) FindAllDomainConfigs(ctx context.Context) ([]repository.DomainConfig, error) {
+	return s.configs, nil
+}
+
+func TestShutdownCoordinator_OrderAndDeadline(t *testing.T) {
+	sc := NewShutdownCoordinator(200 * time.Millisecond)
+
+	var order []string
+	sc.Register(ShutdownCheckpoint, "checkpoint", func(ctx context.Context) error {
+		order = append(order, "checkpoint")
+		return nil
+	})
+	sc.Register(ShutdownStopIntake, "intake", func(ctx context.Context) error {
+		order = append(order, "intake")
+		return nil
+	})
+	remove := sc.Register(ShutdownFlushWrites, "removed", func(ctx context.Context) error {
+		order = append(order, "removed")
+		return nil
+	})
+	remove()
+	sc.Register(ShutdownFlushAI, "slow_ai", func(ctx context.Context) error {
+		time.Sleep(time.Second) // ignora o contexto de propósito
+		return nil
+	})
+
+	report := sc.Shutdown()
+
+	assert.Equal(t, []string{"intake"}, order)
+	assert.True(t, report.TimedOut)
+	assert.Len(t, report.Steps, 3)
+	assert.NotEmpty(t, report.Steps[1].Error)
+	assert.True(t, report.Steps[2].Skipped)
+	assert.Less(t, report.Duration, time.Second)
+}
+
+// pendingBatchStub devolve o lote pendente da IA já processado, como o GeminiService.FlushPending
+type pendingBatchStub struct {
+	pending []repository.Property
+}
+
+func (s *pendingBatchStub) FlushPending(ctx context.Context) ([]repository.Property, error) {
+	pending := s.pending
+	s.pending = nil
+	return pending, nil
+}
+
+func TestShutdownSavesFlushedAIBatch(t *testing.T) {
+	batch := func() *pendingBatchStub {
+		return &pendingBatchStub{pending: []repository.Property{
+			{URL: "https://imobiliaria.com.br/imovel/1", Bairro: "Centro", TipoImovel: "Casa"},
+			{URL: "https://imobiliaria.com.br/imovel/2", Bairro: "Jardim América", TipoImovel: "Apartamento"},
+		}}
+	}
+
+	repo := new(MockCrawlerPropertyRepository)
+	repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	aiService := batch()
+	sc := NewShutdownCoordinator(time.Second)
+	registerCrawlShutdown(sc, "full", &crawlLifecycle{}, aiService, repo, nil, "")
+
+	report := sc.Shutdown()
+	assert.False(t, report.Failed())
+	assert.Empty(t, aiService.pending)
+
+	// O resultado da IA de cada imóvel pendente foi gravado no repositório
+	repo.AssertNumberOfCalls(t, "Save", 2)
+	first := repo.Calls[0].Arguments.Get(1).(repository.Property)
+	second := repo.Calls[1].Arguments.Get(1).(repository.Property)
+	assert.Equal(t, "Centro", first.Bairro)
+	assert.Equal(t, "Jardim América", second.Bairro)
+
+	// Falha na gravação aparece no relatório do encerramento
+	failing := new(MockCrawlerPropertyRepository)
+	failing.On("Save", mock.Anything, mock.Anything).Return(fmt.Errorf("mongo indisponível"))
+	sc = NewShutdownCoordinator(time.Second)
+	registerCrawlShutdown(sc, "full", &crawlLifecycle{}, batch(), failing, nil, "")
+	report = sc.Shutdown()
+	assert.True(t, report.Failed())
+	failing.AssertNumberOfCalls(t, "Save", 2)
+}
+
+func TestCrawlLifecycle_CheckpointPendingSeeds(t *testing.T) {
+	engine := NewSimpleRecursiveCrawler(nil, nil)
+	engine.beginCrawl([]string{"https://a.com", "https://b.com", "https://c.com"})
+	engine.markSeedStarted("https://a.com")
+
+	sc := NewShutdownCoordinator(time.Second)
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	engine.RegisterShutdown(sc, path)
+
+	// Simula o fim do processamento da página em andamento após o StopIntake
+	go func() {
+		for !engine.IntakeStopped() {
+			time.Sleep(5 * time.Millisecond)
+		}
+		engine.endCrawl()
+	}()
+
+	report := sc.Shutdown()
+	assert.False(t, report.Failed())
+
+	checkpoint, err := LoadCrawlCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "simple_recursive", checkpoint.Engine)
+	assert.Equal(t, []string{"https://b.com", "https://c.com"}, checkpoint.PendingURLs)
+}
+
+func TestCrawlJobManager_CancelAndPause(t *testing.T) {
+	manager := NewCrawlJobManager()
+	job, control := manager.CreateJob([]string{"Muzambinho"}, "incremental", nil)
+	manager.MarkRunning(job.ID)
+
+	_, err := manager.PauseDomain(job.ID, "https://www.site.com/imoveis", false)
+	assert.NoError(t, err)
+
+	assert.False(t, control.AllowVisit("https://site.com/imovel/1"))
+	assert.False(t, control.AllowVisit("https://site.com/imovel/1")) // não duplica a URL adiada
+	assert.True(t, control.AllowVisit("https://outro.com/imovel/2"))
+
+	status := manager.GetJob(job.ID)
+	assert.Equal(t, []string{"site.com"}, status.PausedDomains)
+	assert.Equal(t, 1, status.DeferredURLs)
+	assert.Empty(t, control.TakeResumable())
+
+	_, err = manager.PauseDomain(job.ID, "site.com", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://site.com/imovel/1"}, control.TakeResumable())
+
+	status, err = manager.CancelJob(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.CrawlJobCancelling, status.Status)
+	assert.False(t, control.AllowVisit("https://outro.com/imovel/3"))
+
+	manager.MarkFinished(job.ID, nil)
+	assert.Equal(t, repository.CrawlJobCancelled, manager.GetJob(job.ID).Status)
+
+	_, err = manager.CancelJob(job.ID)
+	assert.ErrorIs(t, err, ErrCrawlJobFinished)
+	_, err = manager.CancelJob("missing")
+	assert.ErrorIs(t, err, ErrCrawlJobNotFound)
+
+	// Jobs finalizados saem da memória depois da retenção
+	manager.CleanupOldJobs(time.Hour)
+	assert.NotNil(t, manager.GetJob(job.ID))
+	manager.CleanupOldJobs(0)
+	assert.Nil(t, manager.GetJob(job.ID))
+	_, err = manager.PauseDomain(job.ID, "site.com", false)
+	assert.ErrorIs(t, err, ErrCrawlJobNotFound)
+}
+
+func TestCrawlJobManager_TemplateBudget(t *testing.T) {
+	manager := NewCrawlJobManager()
+	job, control := manager.CreateTemplateJob(repository.CrawlJobTemplate{
+		Name:     "new-site-validation",
+		Mode:     repository.CrawlModeFull,
+		Domains:  []string{"site.com"},
+		MaxPages: 2,
+	})
+	assert.Equal(t, "new-site-validation", job.Template)
+	assert.Equal(t, []string{"site.com"}, job.Domains)
+	assert.Equal(t, 2, job.MaxPages)
+
+	assert.True(t, control.AllowVisit("https://site.com/imovel/1"))
+	control.RecordVisit()
+	assert.True(t, control.AllowVisit("https://site.com/imovel/2"))
+	control.RecordVisit()
+	assert.False(t, control.AllowVisit("https://site.com/imovel/3"))
+	assert.Equal(t, BudgetMaxPages, manager.GetJob(job.ID).BudgetExhausted)
+
+	// Esgotar o orçamento não é falha nem cancelamento
+	manager.MarkFinished(job.ID, nil)
+	finished := manager.GetJob(job.ID)
+	assert.Equal(t, repository.CrawlJobCompleted, finished.Status)
+	assert.Equal(t, 2, finished.VisitedPages)
+	assert.Equal(t, BudgetMaxPages, finished.BudgetExhausted)
+
+	// Prazo esgotado
+	_, timed := manager.CreateTemplateJob(repository.CrawlJobTemplate{Name: "daily-incremental"})
+	timed.SetBudget(0, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	assert.False(t, timed.AllowVisit("https://site.com/imovel/1"))
+	assert.Equal(t, BudgetMaxDuration, timed.BudgetExhausted())
+
+	// Jobs sem orçamento não são limitados
+	_, unlimited := manager.CreateJob(nil, "incremental", nil)
+	for i := 0; i < 10; i++ {
+		unlimited.RecordVisit()
+	}
+	assert.True(t, unlimited.AllowVisit("https://site.com/imovel/1"))
+	assert.Empty(t, unlimited.BudgetExhausted())
+}
+
+func TestEstimateJobCost(t *testing.T) {
+	manager := NewCrawlJobManager()
+	job, control := manager.CreateJob(nil, "incremental", nil)
+	control.RecordResponse(1<<29, false)
+	control.RecordResponse(1<<29, true)
+	control.RecordResponse(0, false) // falha sem corpo
+	for i := 0; i < 4; i++ {
+		control.RecordSaved()
+	}
+	manager.MarkFinished(job.ID, nil)
+
+	usage := control.Usage()
+	assert.Equal(t, JobUsage{Requests: 3, Bytes: 1 << 30, ProxyRequests: 1, ProxyBytes: 1 << 29, Properties: 4}, usage)
+
+	// A IA conta para o job quando o serviço estava disponível, com o consumo do próprio job
+	control.SetAIEnabled(true)
+	assert.True(t, control.Usage().AIEnabled)
+	assert.Equal(t, ai.UsageStats{}, control.Usage().AI)
+	usage.AIEnabled = true
+	usage.AI = ai.UsageStats{Calls: 10, PromptTokens: 1000000, OutputTokens: 250000}
+
+	rates := CostRates{AIInputPerMillion: 1, AIOutputPerMillion: 4, ComputePerHour: 0.5, BandwidthPerGB: 0.1, ProxyPerGB: 2}
+	cost := EstimateJobCost(manager.GetJob(job.ID), usage, 2*time.Hour, rates)
+	assert.Equal(t, job.ID, cost.JobID)
+	assert.Equal(t, "incremental", cost.Mode)
+	assert.Equal(t, repository.CrawlJobCompleted, cost.Status)
+	assert.True(t, cost.AIEnabled)
+	assert.InDelta(t, 2.0, cost.AICostUSD, 1e-9)
+	assert.InDelta(t, 1.0, cost.ComputeCostUSD, 1e-9)
+	assert.InDelta(t, 0.1, cost.BandwidthCostUSD, 1e-9)
+	assert.InDelta(t, 1.0, cost.ProxyCostUSD, 1e-9)
+	assert.InDelta(t, 4.1, cost.TotalCostUSD, 1e-9)
+	assert.InDelta(t, 1.025, cost.CostPerProperty, 1e-9)
+	assert.Equal(t, 7200.0, cost.ComputeSeconds)
+
+	manager.SetCost(job.ID, cost)
+	assert.Equal(t, cost, manager.GetJob(job.ID).Cost)
+
+	// Sem imóveis salvos e sem IA
+	cost = EstimateJobCost(manager.GetJob(job.ID), JobUsage{}, time.Minute, rates)
+	assert.False(t, cost.AIEnabled)
+	assert.Zero(t, cost.CostPerProperty)
+
+	// IA disponível sem nenhuma chamada continua no grupo com IA
+	cost = EstimateJobCost(manager.GetJob(job.ID), JobUsage{AIEnabled: true}, time.Minute, rates)
+	assert.True(t, cost.AIEnabled)
+	assert.Zero(t, cost.AICostUSD)
+}
+
+func TestCrawlJobManager_CreateOnce(t *testing.T) {
+	manager := NewCrawlJobManager()
+	created := 0
+	create := func() (*repository.CrawlJob, error) {
+		created++
+		job, _ := manager.CreateJob([]string{"Muzambinho"}, "incremental", nil)
+		return job, nil
+	}
+
+	job, replayed, err := manager.CreateOnce("retry-1", "params-a", time.Hour, create)
+	assert.NoError(t, err)
+	assert.False(t, replayed)
+
+	// Repetição da chave: retorna o job original sem criar outro
+	again, replayed, err := manager.CreateOnce("retry-1", "params-a", time.Hour, create)
+	assert.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, job.ID, again.ID)
+	assert.Equal(t, 1, created)
+
+	// Mesma chave com outros parâmetros
+	_, _, err = manager.CreateOnce("retry-1", "params-b", time.Hour, create)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	// Outra chave cria outro job
+	other, replayed, err := manager.CreateOnce("retry-2", "params-a", time.Hour, create)
+	assert.NoError(t, err)
+	assert.False(t, replayed)
+	assert.NotEqual(t, job.ID, other.ID)
+
+	// Fora da janela (gravada com a chave) a chave vale como nova
+	short, _, err := manager.CreateOnce("retry-3", "params-a", time.Millisecond, create)
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	renewed, replayed, err := manager.CreateOnce("retry-3", "params-b", time.Hour, create)
+	assert.NoError(t, err)
+	assert.False(t, replayed)
+	assert.NotEqual(t, short.ID, renewed.ID)
+	assert.Equal(t, 4, created)
+
+	// Falha ao criar o job libera a chave
+	_, _, err = manager.CreateOnce("retry-4", "params-a", time.Hour, func() (*repository.CrawlJob, error) {
+		return nil, errors.New("boom")
+	})
+	assert.Error(t, err)
+	_, replayed, err = manager.CreateOnce("retry-4", "params-a", time.Hour, create)
+	assert.NoError(t, err)
+	assert.False(t, replayed)
+}
+
+func TestCrawlJobManager_CreateOnceSharedStore(t *testing.T) {
+	// Duas réplicas com o mesmo store de chaves, como com o MongoDB
+	store := newMemoryIdempotencyStore()
+	first, second := NewCrawlJobManager(), NewCrawlJobManager()
+	first.SetIdempotencyStore(store)
+	second.SetIdempotencyStore(store)
+
+	job, _, err := first.CreateOnce("retry-1", "params-a", time.Hour, func() (*repository.CrawlJob, error) {
+		job, _ := first.CreateJob([]string{"Muzambinho"}, "incremental", nil)
+		return job, nil
+	})
+	assert.NoError(t, err)
+
+	// A outra réplica não conhece o job, mas repete a resposta sem criar outro
+	again, replayed, err := second.CreateOnce("retry-1", "params-a", time.Hour, func() (*repository.CrawlJob, error) {
+		t.Fatal("job created twice for the same key")
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, job.ID, again.ID)
+
+	_, _, err = second.CreateOnce("retry-1", "params-b", time.Hour, nil)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	// Chave reservada por uma réplica que ainda está criando o job
+	_, err = store.ReserveIdempotencyKey(context.Background(), repository.IdempotencyKeyRecord{
+		Key: "retry-2", Fingerprint: "params-a", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	_, _, err = second.CreateOnce("retry-2", "params-a", time.Hour, nil)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+}
+
+// fakeSkipPathStore acumula as amostras em memória, como o repositório de configurações por domínio
+type fakeSkipPathStore struct {
+	stats map[string]*repository.PathStats
+}
+
+func (f *fakeSkipPathStore) RecordPathStats(ctx context.Context, samples []repository.PathClassification, policy repository.SkipPathPolicy) ([]repository.PathStats, error) {
+	var learned []repository.PathStats
+	for _, sample := range samples {
+		key := sample.Domain + sample.Path
+		stats, exists := f.stats[key]
+		if !exists {
+			stats = &repository.PathStats{Domain: sample.Domain, Path: sample.Path}
+			f.stats[key] = stats
+		}
+		stats.Crawls++
+		stats.NonProperty += sample.NonProperty
+		stats.Productive += sample.Productive
+		if !stats.Learned && policy.Qualifies(*stats) {
+			stats.Learned = true
+			learned = append(learned, *stats)
+		}
+	}
+	return learned, nil
+}
+
+func (f *fakeSkipPathStore) FindPathStats(ctx context.Context, domain string) ([]repository.PathStats, error) {
+	return nil, nil
+}
+
+func (f *fakeSkipPathStore) ForgetSkipPath(ctx context.Context, domain, path string) error {
+	return nil
+}
+
+func TestSkipPathLearner(t *testing.T) {
+	store := &fakeSkipPathStore{stats: make(map[string]*repository.PathStats)}
+	crawl := func() []repository.PathStats {
+		learner := NewSkipPathLearner(store, repository.SkipPathPolicy{MinCrawls: 2, MinPages: 3})
+		learner.RecordNonProperty("https://www.imob.com.br/")
+		for _, page := range []string{"/blog/dicas", "/blog/financiar", "/Blog/mercado", "/venda?page=1", "/venda?page=2"} {
+			learner.RecordNonProperty("https://www.imob.com.br" + page)
+		}
+		// O catálogo /venda levou a um anúncio em /imovel
+		learner.RecordListing([]repository.DiscoveryStep{
+			{URL: "https://www.imob.com.br/", Kind: "seed"},
+			{URL: "https://www.imob.com.br/venda?page=1", Kind: "catalog"},
+			{URL: "https://www.imob.com.br/venda?page=2", Kind: "pagination"},
+			{URL: "https://www.imob.com.br/imovel/123", Kind: "property"},
+		})
+
+		learned, err := learner.Flush(context.Background())
+		assert.NoError(t, err)
+		return learned
+	}
+
+	assert.Empty(t, crawl(), "um único crawling não basta")
+
+	learned := crawl()
+	if assert.Len(t, learned, 1) {
+		assert.Equal(t, "imob.com.br", learned[0].Domain)
+		assert.Equal(t, "/blog", learned[0].Path)
+		assert.Equal(t, 6, learned[0].NonProperty)
+	}
+	assert.Equal(t, 2, store.stats["imob.com.br/venda"].Productive, "cada anúncio conta o caminho uma vez")
+	assert.Equal(t, 2, store.stats["imob.com.br/imovel"].Productive)
+	assert.Nil(t, store.stats["imob.com.br"], "a página inicial não é acompanhada")
+
+	var disabled *SkipPathLearner
+	disabled.RecordNonProperty("https://imob.com.br/blog")
+	learned, err := disabled.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, learned)
+}
+
+// fakeDeadFilterStore guarda as combinações sem resultados em memória, como o repositório de URLs
+type fakeDeadFilterStore struct {
+	dead map[string]int
+}
+
+func (f *fakeDeadFilterStore) RecordDeadFilter(ctx context.Context, domain, combination string, retry time.Duration) error {
+	f.dead[domain+combination]++
+	return nil
+}
+
+func (f *fakeDeadFilterStore) ClearDeadFilter(ctx context.Context, domain, combination string) error {
+	delete(f.dead, domain+combination)
+	return nil
+}
+
+func (f *fakeDeadFilterStore) FindDeadFilters(ctx context.Context, domain string) ([]repository.DeadFilterCombination, error) {
+	return []repository.DeadFilterCombination{{Domain: "imob.com.br", Combination: "/venda/terreno"}}, nil
+}
+
+func TestDeadFilterTracker(t *testing.T) {
+	assert.True(t, IsZeroResultPage("Resultados\n  Nenhum imóvel   encontrado para os filtros selecionados"))
+	assert.True(t, IsZeroResultPage("Sua busca não retornou resultados. Tente outros filtros."))
+	assert.True(t, IsZeroResultPage("0 imóveis encontrados"))
+	assert.False(t, IsZeroResultPage("120 imóveis encontrados em Muzambinho"))
+	assert.False(t, IsZeroResultPage("Casa com 3 quartos à venda no centro"))
+
+	assert.Equal(t, "/busca?operacao=venda&tipo=casa", repository.FilterCombination("https://imob.com.br/Busca/?tipo=casa&operacao=venda"))
+
+	ctx := context.Background()
+	store := &fakeDeadFilterStore{dead: make(map[string]int)}
+	tracker := NewDeadFilterTracker(store, 0)
+	assert.NoError(t, tracker.Load(ctx))
+	assert.True(t, tracker.IsDead("https://www.imob.com.br/venda/terreno"))
+	assert.False(t, tracker.IsDead("https://outra.com.br/venda/terreno"), "as combinações valem por domínio")
+
+	// A busca por aluguel retornou "nenhum imóvel encontrado": não é gerada no próximo formulário
+	tracker.RecordZeroResult(ctx, "https://imob.com.br/busca?operacao=aluguel")
+	assert.Equal(t, 1, store.dead["imob.com.br/busca?operacao=aluguel"])
+
+	idm := NewInteractiveDiscoveryManager()
+	idm.SetDeadFilterTracker(tracker)
+	form := SearchForm{Action: "https://imob.com.br/busca", Method: "GET"}
+	urls := idm.generateFormURLs(form, "https://imob.com.br/")
+	assert.Contains(t, urls, "https://imob.com.br/busca?operacao=venda")
+	assert.NotContains(t, urls, "https://imob.com.br/busca?operacao=aluguel")
+	assert.NotContains(t, urls, "https://imob.com.br/venda/terreno")
+	assert.True(t, idm.IsGeneratedURL("https://imob.com.br/busca?operacao=venda"))
+	assert.False(t, idm.IsGeneratedURL("https://imob.com.br/imovel/123"))
+
+	recorded, pruned := tracker.Counts()
+	assert.Equal(t, 1, recorded)
+	assert.Equal(t, 3, pruned, "a busca por aluguel aparece no formulário e nos catálogos conhecidos")
+
+	// A combinação voltou a ter resultados por um link do site
+	store.dead["imob.com.br/venda/terreno"] = 2
+	tracker.RecordResults(ctx, "https://imob.com.br/venda/terreno/")
+	assert.False(t, tracker.IsDead("https://imob.com.br/venda/terreno"))
+	assert.NotContains(t, store.dead, "imob.com.br/venda/terreno")
+	assert.True(t, tracker.IsDead("https://imob.com.br/busca?operacao=aluguel"))
+
+	var disabled *DeadFilterTracker
+	assert.False(t, disabled.IsDead("https://imob.com.br/busca"))
+	assert.Equal(t, []string{"https://imob.com.br/busca"}, disabled.Prune([]string{"https://imob.com.br/busca"}))
+}
+
+func TestInteractiveDiscovery_FormOptions(t *testing.T) {
+	html := `<html><body>
+		<form action="/busca" method="get">
+			<select name="finalidade">
+				<option value="">Selecione</option>
+				<option value="venda">Comprar</option>
+				<option value="locacao">Alugar</option>
+			</select>
+			<select name="cidade">
+				<option>Selecione a cidade</option>
+				<option value="muzambinho">Muzambinho</option>
+				<option value="pocos-de-caldas">Poços de Caldas</option>
+				<option value="guaxupe" disabled>Guaxupé</option>
+			</select>
+			<input name="bairro" list="bairros">
+			<button type="submit">Buscar</button>
+		</form>
+		<datalist id="bairros"><option value="Centro"><option value="Jardim Brasil"></datalist>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+
+	idm := NewInteractiveDiscoveryManager()
+	forms := idm.discoverSearchForms(doc, "https://imob.com.br/")
+	if !assert.Len(t, forms, 1) {
+		return
+	}
+	form := forms[0]
+	assert.Equal(t, []string{"venda", "locacao"}, form.Options["finalidade"])
+	assert.Equal(t, []string{"muzambinho", "pocos-de-caldas"}, form.Options["cidade"], "placeholder e opção desabilitada são ignorados")
+	assert.Equal(t, []string{"Centro", "Jardim Brasil"}, form.Options["bairro"])
+
+	urls := idm.generateFormURLs(form, "https://imob.com.br/")
+	assert.Contains(t, urls, "https://imob.com.br/busca")
+	assert.Contains(t, urls, "https://imob.com.br/busca?cidade=pocos-de-caldas")
+	assert.Contains(t, urls, "https://imob.com.br/busca?bairro=Jardim+Brasil&finalidade=locacao")
+	assert.Contains(t, urls, "https://imob.com.br/busca?cidade=muzambinho&finalidade=venda")
+	for _, generated := range urls {
+		assert.NotContains(t, generated, "tipo=apartamento", "palpites fixos não são usados quando há opções reais")
+		assert.NotContains(t, generated, "guaxupe")
+	}
+
+	// Sem opções reais, o formulário continua usando as combinações básicas
+	urls = idm.generateFormURLs(SearchForm{Action: "https://imob.com.br/busca", Method: "GET"}, "https://imob.com.br/")
+	assert.Contains(t, urls, "https://imob.com.br/busca?operacao=venda&tipo=apartamento")
+
+	many := make([]string, 300)
+	for i := range many {
+		many[i] = fmt.Sprintf("bairro-%d", i)
+	}
+	assert.Len(t, formOptionParams(map[string][]string{"bairro": many}), maxFormOptionURLs)
+}
+
+// fakePDFExtractor devolve um texto fixo no lugar do pdftotext
+type fakePDFExtractor struct {
+	text string
+	err  error
+}
+
+func (f *fakePDFExtractor) ExtractText(ctx context.Context, data []byte) (string, error) {
+	return f.text, f.err
+}
+
+func TestSimpleRecursiveCrawler_PDFBrochure(t *testing.T) {
+	brochureURL, _ := url.Parse("https://imobiliaria.com.br/folhetos/casa-centro.pdf")
+	request := &colly.Request{URL: brochureURL}
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/pdf")
+	response := &colly.Response{Request: request, Headers: &headers, Body: []byte("%PDF-1.4 ...")}
+
+	// O PDF é reconhecido pelo Content-Type ou pela extensão com a assinatura do arquivo
+	assert.True(t, isPDFResponse(response))
+	assert.True(t, isPDFResponse(&colly.Response{Request: request, Headers: &http.Header{}, Body: []byte("%PDF-1.7")}))
+	assert.False(t, isPDFResponse(&colly.Response{Request: request, Headers: &http.Header{}, Body: []byte("<html>")}))
+
+	// Sem a extração de PDF os links para folhetos continuam ignorados
+	mockRepo := new(MockCrawlerPropertyRepository)
+	simpleCrawler := NewSimpleRecursiveCrawler(mockRepo, nil)
+	parent := "https://imobiliaria.com.br/imovel/casa-centro"
+	assert.False(t, simpleCrawler.isValidLink(brochureURL.String(), parent))
+
+	simpleCrawler.SetPDFExtractor(&fakePDFExtractor{text: `Casa à venda no Centro
+Rua das Flores, 120 - Centro - Muzambinho/MG
+Valor: R$ 450.000,00
+3 quartos, 2 banheiros, área de 180 m²
+Casa ampla com quintal, garagem para dois carros e cozinha planejada, próxima ao comércio.`})
+	assert.True(t, simpleCrawler.isValidLink(brochureURL.String(), parent))
+
+	// O texto passa pelo pipeline de extração e o imóvel é marcado como vindo de PDF
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(p repository.Property) bool {
+		return p.SourceFormat == repository.SourceFormatPDF && p.Valor == 450000
+	})).Return(nil).Once()
+	simpleCrawler.handlePDF(context.Background(), response)
+	mockRepo.AssertExpectations(t)
+
+	// Um PDF sem texto (folheto escaneado) é registrado como erro de extração
+	scannedURL, _ := url.Parse("https://imobiliaria.com.br/folhetos/escaneado.pdf")
+	simpleCrawler.SetPDFExtractor(&fakePDFExtractor{err: ErrEmptyPDFText})
+	simpleCrawler.handlePDF(context.Background(), &colly.Response{Request: &colly.Request{URL: scannedURL}, Headers: &headers})
+	mockRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+// fakeWhatsAppLeadStore guarda em memória os leads gravados
+type fakeWhatsAppLeadStore struct {
+	saved []repository.WhatsAppLead
+}
+
+func (f *fakeWhatsAppLeadStore) SaveWhatsAppLeads(ctx context.Context, leads []repository.WhatsAppLead) error {
+	f.saved = append(f.saved, leads...)
+	return nil
+}
+
+func (f *fakeWhatsAppLeadStore) FindWhatsAppLeads(ctx context.Context, filter repository.WhatsAppLeadFilter) ([]repository.WhatsAppLead, error) {
+	return f.saved, nil
+}
+
+func TestWhatsAppCatalogLeads(t *testing.T) {
+	extractor := NewDataExtractor()
+	page := "https://www.imobiliaria.com.br/catalogo"
+
+	// Mensagem com trechos de anúncio: tipo, quartos, valor, código e localização
+	lead := extractor.ParseWhatsAppLead("https://wa.me/5535999999999?text=Ol%C3%A1!%20Tenho%20interesse%20na%20Casa%203%20quartos%20no%20bairro%20Jardim%20Am%C3%A9rica%20-%20R%24%20450.000%20(c%C3%B3d.%20CA123)", page)
+	if assert.NotNil(t, lead) {
+		assert.Equal(t, "imobiliaria.com.br", lead.Domain)
+		assert.Equal(t, "5535999999999", lead.Phone)
+		assert.Equal(t, "Casa", lead.TipoImovel)
+		assert.Equal(t, 3, lead.Quartos)
+		assert.Equal(t, 450000.0, lead.Valor)
+		assert.Equal(t, "CA123", lead.Codigo)
+		assert.Equal(t, "Jardim América", lead.Bairro)
+		assert.Equal(t, repository.MaxWhatsAppLeadConfidence, lead.Confidence)
+	}
+
+	// api.whatsapp.com com cidade/UF e poucos campos: confiança menor
+	lead = extractor.ParseWhatsAppLead("https://api.whatsapp.com/send?phone=55%2035%203571-1234&text=Apartamento+2+quartos+em+Muzambinho/MG", page)
+	if assert.NotNil(t, lead) {
+		assert.Equal(t, "553535711234", lead.Phone)
+		assert.Equal(t, "Apartamento", lead.TipoImovel)
+		assert.Equal(t, "Muzambinho", lead.Cidade)
+		assert.InDelta(t, 0.3, lead.Confidence, 0.001)
+	}
+
+	// Mensagens genéricas, links sem mensagem e outros links não viram leads
+	assert.Nil(t, extractor.ParseWhatsAppLead("https://wa.me/5535999999999?text=Ol%C3%A1,%20gostaria%20de%20mais%20informa%C3%A7%C3%B5es", page))
+	assert.Nil(t, extractor.ParseWhatsAppLead("https://wa.me/5535999999999", page))
+	assert.Nil(t, extractor.ParseWhatsAppLead("https://www.imobiliaria.com.br/imovel/1?text=Casa+3+quartos", page))
+
+	// Catálogo: o mesmo link repetido na página gera um único lead, gravado pelo crawler
+	html := `<html><body>
+		<div><h3>Casa Centro</h3><a href="https://wa.me/5535999999999?text=Casa%203%20quartos%20no%20Centro%20R%24%20380.000">Pedir pelo WhatsApp</a></div>
+		<div><h3>Terreno</h3><a href="https://wa.me/5535999999999?text=Terreno%20300%20m%C2%B2%20R%24%20120.000">Pedir pelo WhatsApp</a></div>
+		<a href="https://wa.me/5535999999999?text=Casa%203%20quartos%20no%20Centro%20R%24%20380.000">Repetido</a>
+		<a href="https://wa.me/5535999999999?text=Ol%C3%A1!">Fale conosco</a>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+	root := doc.Find("html")
+	element := colly.NewHTMLElementFromSelectionNode(&colly.Response{}, root, root.Nodes[0], 0)
+
+	store := &fakeWhatsAppLeadStore{}
+	simpleCrawler := NewSimpleRecursiveCrawler(nil, nil)
+	simpleCrawler.saveWhatsAppLeads(context.Background(), element, page)
+	assert.Empty(t, store.saved, "sem o repositório os links continuam ignorados")
+
+	simpleCrawler.SetWhatsAppLeadStore(store)
+	simpleCrawler.saveWhatsAppLeads(context.Background(), element, page)
+	if assert.Len(t, store.saved, 2) {
+		assert.Equal(t, "Centro", store.saved[0].Bairro)
+		assert.Equal(t, 380000.0, store.saved[0].Valor)
+		assert.Equal(t, "Terreno", store.saved[1].TipoImovel)
+		assert.Equal(t, 300.0, store.saved[1].AreaTotal)
+	}
+}
+
+func TestPageLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/lenta":
+			time.Sleep(150 * time.Millisecond)
+		case "/travada":
+			time.Sleep(400 * time.Millisecond)
+		case "/grande":
+			fmt.Fprintf(w, "<html><body>%s</body></html>", strings.Repeat("a", 4096))
+			return
+		}
+		fmt.Fprint(w, "<html><body><h1>ok</h1></body></html>")
+	}))
+	defer server.Close()
+
+	tracker := NewErrorTracker()
+	c := colly.NewCollector(colly.AllowURLRevisit())
+	limits := PageLimits{RequestTimeout: 300 * time.Millisecond, MaxDuration: 100 * time.Millisecond, MaxBytes: 1024}
+	limits.Attach(c, tracker)
+	fetchCollector(c, nil, "", limits)
+
+	var parsed []string
+	var timedOut []string
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		parsed = append(parsed, e.Request.URL.Path)
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		if isRequestTimeout(err) {
+			timedOut = append(timedOut, r.Request.URL.Path)
+		}
+	})
+
+	for _, path := range []string{"/rapida", "/lenta", "/grande", "/travada"} {
+		_ = c.Visit(server.URL + path)
+	}
+
+	// Só a página rápida e pequena é analisada; a lenta é interrompida pelo prazo da página, a
+	// travada pelo timeout da requisição e a grande é descartada e contada
+	assert.Equal(t, []string{"/rapida"}, parsed)
+	assert.Equal(t, []string{"/lenta", "/travada"}, timedOut)
+	report := tracker.Report(ErrorTypeSlowPage, "", 10)
+	assert.Equal(t, 1, report.ByType[ErrorTypeSlowPage])
+	domain := repository.NormalizeDomain(server.URL)
+	assert.Equal(t, 1, report.ByDomain[domain][ErrorTypeSlowPage])
+
+	// A espera antes do envio (ritmo do domínio, atraso do LimitRule) não conta no prazo da página
+	parsed = nil
+	c = colly.NewCollector(colly.AllowURLRevisit())
+	limits.Attach(c, tracker)
+	fetchCollector(c, nil, "", limits)
+	c.OnRequest(func(r *colly.Request) {
+		time.Sleep(150 * time.Millisecond)
+	})
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		parsed = append(parsed, e.Request.URL.Path)
+	})
+	_ = c.Visit(server.URL + "/rapida")
+	assert.Equal(t, []string{"/rapida"}, parsed)
+
+	// Limites zerados não abortam nada
+	parsed = nil
+	c = colly.NewCollector(colly.AllowURLRevisit())
+	PageLimits{}.Attach(c, tracker)
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		parsed = append(parsed, e.Request.URL.Path)
+	})
+	_ = c.Visit(server.URL + "/grande")
+	assert.Equal(t, []string{"/grande"}, parsed)
+}
+
+// fakeResolver responde as consultas de DNS do teste e conta as chamadas por host
+type fakeResolver struct {
+	addrs map[string][]net.IPAddr
+	errs  map[string]error
+	calls map[string]int
+}
+
+func (f *fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	f.calls[host]++
+	if addrs, ok := f.addrs[host]; ok {
+		return addrs, nil
+	}
+	if err, ok := f.errs[host]; ok {
+		return nil, err
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestDNSCache(t *testing.T) {
+	resolver := &fakeResolver{
+		addrs: map[string][]net.IPAddr{"imobiliaria.com.br": {{IP: net.ParseIP("10.0.0.1")}}},
+		calls: make(map[string]int),
+	}
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	cache := NewDNSCache(DNSCacheOptions{TTL: 5 * time.Minute, NegativeTTL: time.Minute, FailureThreshold: 3, Quarantine: 15 * time.Minute})
+	cache.resolver = resolver
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Resoluções ficam em cache até o TTL
+	for i := 0; i < 3; i++ {
+		addrs, err := cache.Lookup(ctx, "Imobiliaria.com.br")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"10.0.0.1"}, addrs)
+	}
+	assert.Equal(t, 1, resolver.calls["imobiliaria.com.br"])
+	now = now.Add(6 * time.Minute)
+	_, err := cache.Lookup(ctx, "imobiliaria.com.br")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resolver.calls["imobiliaria.com.br"])
+
+	// IPs não passam pelo resolvedor
+	addrs, err := cache.Lookup(ctx, "192.168.0.10")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.0.10"}, addrs)
+
+	// Falhas ficam no cache negativo; só as consultas ao resolvedor contam, e o host é isolado na
+	// terceira falha seguida
+	_, err = cache.Lookup(ctx, "fora-do-ar.com.br")
+	assert.Error(t, err)
+	assert.False(t, cache.Quarantined("http://fora-do-ar.com.br/imoveis"))
+	for i := 0; i < 3; i++ {
+		_, err = cache.Lookup(ctx, "fora-do-ar.com.br")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 1, resolver.calls["fora-do-ar.com.br"])
+	assert.False(t, cache.Quarantined("http://fora-do-ar.com.br/imoveis"))
+	for i := 0; i < 2; i++ {
+		now = now.Add(2 * time.Minute)
+		_, err = cache.Lookup(ctx, "fora-do-ar.com.br")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 3, resolver.calls["fora-do-ar.com.br"])
+	assert.True(t, cache.Quarantined("http://fora-do-ar.com.br/imoveis/1"))
+	assert.True(t, cache.Quarantined("https://FORA-DO-AR.com.br/imoveis/2"))
+	assert.False(t, cache.Quarantined("http://imobiliaria.com.br/imoveis"))
+
+	// O dial da requisição já descartada no engine não conta outra vez
+	_, err = cache.DialContext(ctx, "tcp", "fora-do-ar.com.br:80")
+	assert.ErrorIs(t, err, ErrHostQuarantined)
+
+	// Timeouts e falhas temporárias (SERVFAIL) não ficam em cache nem isolam o host
+	resolver.errs = map[string]error{
+		"lento.com.br":    &net.DNSError{Err: "i/o timeout", Name: "lento.com.br", IsTimeout: true},
+		"servfail.com.br": &net.DNSError{Err: "server misbehaving", Name: "servfail.com.br", IsTemporary: true},
+	}
+	for i := 0; i < 4; i++ {
+		_, err = cache.Lookup(ctx, "lento.com.br")
+		assert.Error(t, err)
+		_, err = cache.Lookup(ctx, "servfail.com.br")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 4, resolver.calls["lento.com.br"])
+	assert.Equal(t, 4, resolver.calls["servfail.com.br"])
+	assert.False(t, cache.Quarantined("http://lento.com.br/"))
+	assert.False(t, cache.Quarantined("http://servfail.com.br/"))
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(13), stats.Misses)
+	assert.Equal(t, int64(3), stats.NegativeHits)
+	if assert.Len(t, stats.Failing, 1) {
+		assert.Equal(t, "fora-do-ar.com.br", stats.Failing[0].Host)
+		assert.Equal(t, 3, stats.Failing[0].Failures)
+		assert.Equal(t, 2, stats.Failing[0].Skipped)
+	}
+
+	// O isolamento expira e uma resolução bem-sucedida zera as falhas
+	now = now.Add(16 * time.Minute)
+	assert.False(t, cache.Quarantined("http://fora-do-ar.com.br/imoveis"))
+	resolver.addrs["fora-do-ar.com.br"] = []net.IPAddr{{IP: net.ParseIP("10.0.0.2")}}
+	_, err = cache.Lookup(ctx, "fora-do-ar.com.br")
+	assert.NoError(t, err)
+	assert.Empty(t, cache.Stats().Failing)
+
+	// Cache nulo (desativado) não isola nada
+	var disabled *DNSCache
+	assert.False(t, disabled.Quarantined("http://fora-do-ar.com.br"))
+}
+
+func TestDomainProber(t *testing.T) {
+	blocked := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		if blocked {
+			w.Header().Set("Cf-Ray", "8a1b2c3d4e5f-GRU")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Server", "nginx")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	port, _ := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+
+	prober := NewDomainProber()
+	prober.resolver = &fakeResolver{
+		addrs: map[string][]net.IPAddr{"example.com": {{IP: net.ParseIP("127.0.0.1")}, {IP: net.ParseIP("::1")}}},
+		calls: make(map[string]int),
+	}
+	prober.allowAddress = func(net.IP) bool { return true }
+	prober.httpsPort = port
+	prober.httpPort = port
+	prober.tlsConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+	ctx := context.Background()
+
+	// Domínio acessível: DNS, TCP, TLS (certificado do example.com) e HEAD
+	probe := prober.Probe(ctx, "https://www.example.com/imoveis")
+	assert.Equal(t, "example.com", probe.Domain)
+	assert.Equal(t, ProbeVerdictOK, probe.Verdict)
+	assert.Equal(t, []string{"127.0.0.1"}, probe.DNS.IPv4)
+	assert.Equal(t, []string{"::1"}, probe.DNS.IPv6)
+	if assert.NotNil(t, probe.TLS) && assert.NotNil(t, probe.HTTP) {
+		assert.Empty(t, probe.TLS.Error)
+		assert.NotEmpty(t, probe.TLS.Version)
+		assert.Equal(t, http.StatusOK, probe.HTTP.StatusCode)
+		assert.Equal(t, "nginx", probe.HTTP.Server)
+	}
+	assert.Contains(t, probe.Notes, "IPv6 addresses unreachable, IPv4 works")
+
+	// Status de bloqueio com cabeçalho de WAF
+	blocked = true
+	probe = prober.Probe(ctx, "example.com")
+	assert.Equal(t, ProbeVerdictBlocked, probe.Verdict)
+	if assert.NotNil(t, probe.HTTP) {
+		assert.ElementsMatch(t, []string{"cloudflare", "status_403"}, probe.HTTP.BlockSignals)
+	}
+
+	// Domínio que não resolve (nem com www.)
+	probe = prober.Probe(ctx, "fora-do-ar.com.br")
+	assert.Equal(t, ProbeVerdictDNSFailure, probe.Verdict)
+	assert.NotEmpty(t, probe.DNS.Error)
+	assert.Empty(t, probe.TCP)
+
+	// O HEAD usa o User-Agent sorteado dos collectors e passa pelos middlewares de fetch
+	blocked = false
+	var agents []string
+	SetFetchChain(NewFetchChain(NewFetchMiddlewareFunc("recorder", func(next http.RoundTripper) http.RoundTripper {
+		return FetchFunc(func(req *http.Request) (*http.Response, error) {
+			agents = append(agents, req.Header.Get("User-Agent"))
+			return next.RoundTrip(req)
+		})
+	})))
+	defer SetFetchChain(nil)
+	probe = prober.Probe(ctx, "example.com")
+	assert.Equal(t, ProbeVerdictOK, probe.Verdict)
+	if assert.Len(t, agents, 1) {
+		assert.True(t, strings.HasPrefix(agents[0], "Mozilla/5.0"), agents[0])
+	}
+
+	// Endereços internos não são testados
+	probe = NewDomainProber().Probe(ctx, "localhost")
+	assert.Equal(t, ProbeVerdictPrivate, probe.Verdict)
+	assert.Empty(t, probe.TCP)
+	assert.Nil(t, probe.HTTP)
+}
+
+func TestCoverageEstimator_Report(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprintf(w, "User-agent: *\nSitemap: http://%s/sitemap-index.xml\n", r.Host)
+		case "/sitemap-index.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>http://%s/sitemap-imoveis.xml</loc></sitemap></sitemapindex>`, r.Host)
+		case "/sitemap-imoveis.xml":
+			fmt.Fprint(w, `<urlset>
+				<url><loc>http://x/imovel/casa-centro-1001</loc></url>
+				<url><loc>http://x/imovel/apto-jardim-1002</loc></url>
+				<url><loc>http://x/imovel/terreno-1003</loc></url>
+				<url><loc>http://x/imovel/terreno-1004</loc></url>
+				<url><loc>http://x/contato</loc></url>
+				<url><loc>http://x/imoveis?page=2</loc></url>
+			</urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	estimator := NewCoverageEstimator()
+	assert.Equal(t, 1234, estimator.ParseCatalogTotal("Busca: 1.234 imóveis encontrados"))
+	assert.Equal(t, 0, estimator.ParseCatalogTotal("Casa com 3 quartos"))
+	assert.Equal(t, 0, estimator.ParseCatalogTotal("Casa com área total de 360 m² e 3 quartos"))
+	assert.Equal(t, 0, estimator.ParseCatalogTotal("Encontrados 12 m² de varanda"))
+	assert.Equal(t, 87, estimator.ParseCatalogTotal("Total de 87 imóveis à venda"))
+	assert.Equal(t, 240, estimator.ParseCatalogTotal("Exibindo 1-20 de 240 resultados"))
+
+	estimator.RecordCatalogPage(server.URL+"/imoveis", "Encontramos 5 imóveis para você")
+	estimator.RecordCatalogPage(server.URL+"/imoveis?page=2", "Encontramos 5 imóveis para você")
+	estimator.RecordPropertyURL(server.URL + "/imovel/casa-centro-1001")
+	estimator.RecordPropertyURL(server.URL + "/imovel/apto-jardim-1002")
+
+	reports := estimator.Report(context.Background())
+	assert.Len(t, reports, 1)
+
+	report := reports[0]
+	assert.Equal(t, 2, report.DiscoveredURLs)
+	assert.Equal(t, 4, report.SitemapPropertyURLs)
+	assert.Equal(t, 5, report.CatalogTotal)
+	assert.Equal(t, 5, report.EstimatedTotal)
+	assert.Equal(t, 60.0, report.EstimatedMissedPercent)
+	assert.Equal(t, []string{"sitemap", "catalog_total"}, report.Sources)
+}
+
+func TestDataExtractor_FieldProvenance(t *testing.T) {
+	html := `<html><head><meta name="description" content="Casa ampla com 3 quartos, 2 banheiros e 180 m² perto do centro."></head><body>
+		<div class="endereco">Rua das Flores, 100 - Centro</div>
+		<span class="preco">R$ 450.000</span>
+		<div class="ficha"><span class="dorms">4 dormitórios</span></div>
+		<ul class="caracteristicas"><li>Piscina aquecida</li></ul>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+	root := doc.Find("html")
+	element := colly.NewHTMLElementFromSelectionNode(&colly.Response{}, root, root.Nodes[0], 0)
+
+	extractor := NewDataExtractor()
+	property := extractor.ExtractProperty(element, "https://imobiliaria.com.br/imovel/1")
+
+	assert.Equal(t, repository.ExtractorGenericSelector, property.Provenance["endereco"].Extractor)
+	assert.Equal(t, ".endereco", property.Provenance["endereco"].Selector)
+	assert.Equal(t, repository.ExtractorGenericSelector, property.Provenance["valor"].Extractor)
+	assert.Equal(t, repository.ExtractorMetaTag, property.Provenance["descricao"].Extractor)
+	assert.Equal(t, repository.ExtractorRegexFallback, property.Provenance["quartos"].Extractor)
+	assert.Equal(t, repository.ExtractorGenericSelector, property.Provenance["caracteristicas"].Extractor)
+	assert.False(t, property.Provenance["valor"].ExtractedAt.IsZero())
+
+	// Seletores do domínio têm prioridade sobre os genéricos
+	extractor.ApplyDomainSelectors(element, property, map[string]string{"quartos": ".dorms", "valor": ".inexistente"})
+	assert.Equal(t, 4, property.Quartos)
+	assert.Equal(t, repository.FieldProvenance{Extractor: repository.ExtractorDomainSelector, Selector: ".dorms", ExtractedAt: property.Provenance["quartos"].ExtractedAt}, property.Provenance["quartos"])
+	assert.Equal(t, repository.ExtractorGenericSelector, property.Provenance["valor"].Extractor)
+
+	// Campos alterados pela IA passam a ser atribuídos a ela
+	processed := *property
+	processed.Bairro = "Jardim Europa"
+	processed.Valor = property.Valor
+	changed := repository.RecordChangedFields(*property, &processed, repository.ExtractorAI)
+	assert.Equal(t, []string{"bairro"}, changed)
+	assert.Equal(t, repository.ExtractorAI, processed.Provenance["bairro"].Extractor)
+	assert.NotEqual(t, repository.ExtractorAI, property.Provenance["bairro"].Extractor)
+}
+
+func TestDataExtractor_CountPhotos(t *testing.T) {
+	html := `<html><head><meta property="og:image" content="/fotos/capa.jpg"></head><body>
+		<img src="/img/logo.png" class="logo">
+		<div class="galeria-fotos">
+			<img src="/icons/whatsapp.svg">
+			<img src="/fotos/1.jpg"><img data-src="/fotos/2.jpg" src="/img/loading.gif">
+			<img src="/fotos/1.jpg">
+			<img src="/fotos/thumb.jpg" width="40" height="40">
+		</div>
+		<p>Casa com 3 quartos à venda no centro, R$ 350.000</p>
+		<div class="imoveis-relacionados"><img src="/fotos/outro-imovel.jpg"><img src="/fotos/mais-um.jpg"></div>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+	root := doc.Find("html")
+	element := colly.NewHTMLElementFromSelectionNode(&colly.Response{}, root, root.Nodes[0], 0)
+
+	property := NewDataExtractor().ExtractProperty(element, "https://imobiliaria.com.br/imovel/2")
+	assert.Equal(t, 2, property.Fotos, "só as fotos da galeria contam")
+	assert.False(t, property.SemFotos)
+
+	// Sem galeria: a imagem de compartilhamento
+	doc.Find(".galeria-fotos").Remove()
+	element = colly.NewHTMLElementFromSelectionNode(&colly.Response{}, root, root.Nodes[0], 0)
+	property = NewDataExtractor().ExtractProperty(element, "https://imobiliaria.com.br/imovel/2")
+	assert.Equal(t, 1, property.Fotos)
+	assert.Equal(t, []string{"/fotos/capa.jpg"}, property.FotoURLs)
+
+	doc.Find("img, meta").Remove()
+	element = colly.NewHTMLElementFromSelectionNode(&colly.Response{}, root, root.Nodes[0], 0)
+	property = NewDataExtractor().ExtractProperty(element, "https://imobiliaria.com.br/imovel/3")
+	assert.Equal(t, 0, property.Fotos)
+	assert.True(t, property.SemFotos)
+	assert.Contains(t, NewPropertyValidator().ValidateProperty(property).Warnings, "Anúncio sem fotos")
+
+	// Backfill: recontagem a partir da página do anúncio
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/imovel/2" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, html)
+	}))
+	defer server.Close()
+	counter := NewPagePhotoCounter(0)
+	photos, err := counter.CountPhotos(context.Background(), server.URL+"/imovel/2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/fotos/1.jpg", server.URL + "/fotos/2.jpg"}, photos)
+	_, err = counter.CountPhotos(context.Background(), server.URL+"/imovel/removido")
+	assert.Error(t, err)
+}
+
+func TestDomainThrottle_Adaptive(t *testing.T) {
+	throttle := NewDomainThrottle()
+	throttle.Configure(500*time.Millisecond, nil)
+
+	throttle.RecordThrottled("www.exemplo.com.br", http.StatusTooManyRequests, 30*time.Second)
+	throttle.RecordThrottled("exemplo.com.br", http.StatusServiceUnavailable, 0)
+
+	stats := throttle.Stats()
+	if !assert.Len(t, stats, 1) {
+		return
+	}
+	assert.Equal(t, int64(2000), stats[0].AdaptiveDelayMs)
+	assert.Equal(t, int64(2500), stats[0].EffectiveDelayMs)
+	assert.Equal(t, 2, stats[0].ThrottledCount)
+	assert.NotNil(t, stats[0].RetryAfterUntil)
+
+	// Diminui um passo a cada sequência de respostas bem-sucedidas
+	for i := 0; i < successesPerDecrease; i++ {
+		throttle.RecordSuccess("exemplo.com.br", http.StatusOK)
+	}
+	stats = throttle.Stats()
+	assert.Equal(t, (2*time.Second - adaptiveDecreaseStep).Milliseconds(), stats[0].AdaptiveDelayMs)
+	assert.Equal(t, successesPerDecrease, stats[0].SuccessCount)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestNewImprovedCrawler_Dependencies(t *testing.T) {
+	cfg := &config.Config{}
+
+	_, err := NewImprovedCrawler(cfg, CrawlerDependencies{})
+	assert.Error(t, err, "repository is required")
+
+	_, err = NewAIIntegratedCrawler(cfg, CrawlerDependencies{})
+	assert.Error(t, err, "repository is required")
+
+	mockRepo := &MockCrawlerPropertyRepository{}
+	clock := fixedClock{now: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)}
+	requested := make(chan string, 1)
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requested <- r.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/html"}},
+			Body:       io.NopCloser(strings.NewReader("<html><body></body></html>")),
+			Request:    r,
+		}, nil
+	})
+
+	improved, err := NewImprovedCrawler(cfg, CrawlerDependencies{Repository: mockRepo, Transport: transport, Clock: clock})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, mockRepo, improved.repo)
+	assert.Nil(t, improved.aiService)
+	assert.Equal(t, clock.now, improved.GetStats().StartTime)
+
+	// O transporte injetado substitui o cliente HTTP dos collectors
+	improved.collector.IgnoreRobotsTxt = true
+	assert.NoError(t, improved.collector.Visit("http://imobiliaria.test/"))
+	improved.collector.Wait()
+	assert.Equal(t, "http://imobiliaria.test/", <-requested)
+
+	integrated, err := NewAIIntegratedCrawler(cfg, CrawlerDependencies{Repository: mockRepo})
+	if assert.NoError(t, err) {
+		assert.Nil(t, integrated.enhancedAI)
+		assert.Nil(t, integrated.enhancedExtractor)
+		assert.NotNil(t, integrated.clock)
+	}
+}
+
+func TestErrorTracker(t *testing.T) {
+	tracker := NewErrorTracker()
+	tracker.Record(ErrorTypeFetch, "https://www.imobiliaria.com/a", "timeout", 0)
+	tracker.Record(ErrorTypeFetch, "https://imobiliaria.com/b", "not found", http.StatusNotFound)
+	tracker.Record(ErrorTypeParse, "https://outra.com/c", "no property data extracted", 0)
+	tracker.RecordError(ErrorTypeStorage, "https://outra.com/d", fmt.Errorf("write failed"))
+	tracker.RecordError(ErrorTypeAI, "https://outra.com/e", nil)
+
+	report := tracker.Report("", "", 0)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.ByType[ErrorTypeFetch])
+	assert.Equal(t, 2, report.ByDomain["imobiliaria.com"][ErrorTypeFetch])
+	assert.Equal(t, 0, report.ByType[ErrorTypeAI])
+
+	byDomain := tracker.Report("", "www.outra.com", 1)
+	assert.Equal(t, 2, byDomain.Total)
+	assert.Len(t, byDomain.Samples, 1)
+
+	byType := tracker.Report(ErrorTypeFetch, "", 0)
+	if assert.Len(t, byType.Samples, 2) {
+		assert.Equal(t, http.StatusNotFound, byType.Samples[0].StatusCode)
+	}
+
+	assert.True(t, IsValidErrorType(ErrorTypeValidation))
+	assert.False(t, IsValidErrorType("unknown_error"))
+	assert.Equal(t, ErrorTypeAI, ClassifyError(fmt.Errorf("gemini: %w", ai.ErrAIUnavailable)))
+}
+
+// capturingReporter guarda os eventos recebidos pelo ErrorReporter global
+type capturingReporter struct {
+	events []logger.ErrorEvent
+}
+
+func (r *capturingReporter) Report(event logger.ErrorEvent)   { r.events = append(r.events, event) }
+func (r *capturingReporter) Flush(timeout time.Duration) bool { return true }
+
+func TestReportCrawlFailure(t *testing.T) {
+	reporter := &capturingReporter{}
+	logger.SetErrorReporter(reporter)
+	defer logger.SetErrorReporter(nil)
+
+	manager := NewCrawlJobManager()
+	job, control := manager.CreateJob([]string{"Muzambinho"}, "incremental", nil)
+	log := logger.NewLogger("test")
+
+	reportCrawlFailure(log, control, ErrorTypeParse, "https://www.imobiliaria.com/a", "no data", nil)
+	assert.Empty(t, reporter.events, "low severity errors are only logged")
+
+	reportCrawlFailure(log, control, ErrorTypeStorage, "https://www.imobiliaria.com/b", "Failed to save property", fmt.Errorf("write failed"))
+	if assert.Len(t, reporter.events, 1) {
+		event := reporter.events[0]
+		assert.Equal(t, job.ID, event.Tags["job_id"])
+		assert.Equal(t, "imobiliaria.com", event.Tags["domain"])
+		assert.Equal(t, ErrorTypeStorage, event.Tags["error_type"])
+		assert.Equal(t, "https://www.imobiliaria.com/b", event.Extra["url"])
+		assert.EqualError(t, event.Err, "write failed")
+	}
+}
+
+func TestCallbackRecovery(t *testing.T) {
+	tracker := NewErrorTracker()
+	recovery := NewCallbackRecovery("test", tracker)
+	snapshotDir := t.TempDir()
+	recovery.SetSnapshotDir(snapshotDir)
+	hookCalls := 0
+	recovery.SetPanicHook(func(string) { hookCalls++ })
+
+	pageURL, _ := url.Parse("https://www.imobiliaria.com/imovel/1")
+	request := &colly.Request{URL: pageURL}
+	response := &colly.Response{Request: request, Body: []byte("<html><body>quebrado</body></html>")}
+	element := &colly.HTMLElement{Request: request, Response: response}
+
+	processed := false
+	handler := recovery.HTML(func(e *colly.HTMLElement) {
+		var property *repository.Property
+		_ = property.Endereco // nil pointer dereference
+	})
+	assert.NotPanics(t, func() { handler(element) })
+	recovery.HTML(func(e *colly.HTMLElement) { processed = true })(element)
+
+	assert.True(t, processed, "next pages keep being processed")
+	assert.Equal(t, int64(1), recovery.PanicCount())
+	assert.Equal(t, 1, hookCalls)
+
+	report := tracker.Report(ErrorTypePanic, "imobiliaria.com", 0)
+	if assert.Len(t, report.Samples, 1) {
+		assert.Contains(t, report.Samples[0].Message, "panic in OnHTML")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(snapshotDir, "*_imobiliaria.com_*.html"))
+	if assert.Len(t, files, 1) {
+		content, _ := os.ReadFile(files[0])
+		assert.Contains(t, string(content), "url: https://www.imobiliaria.com/imovel/1")
+		assert.Contains(t, string(content), "quebrado")
+	}
+}
+
+func TestExtractionPipeline_CustomStages(t *testing.T) {
+	var calls []string
+	codeStage := NewExtractorFunc("codigo_interno", func(page *ExtractionPage, property *repository.Property) error {
+		calls = append(calls, "codigo_interno")
+		property.SetCustomField("codigo_interno", page.Element.ChildText(".ref"), repository.ExtractorPlugin)
+		return nil
+	})
+	failingStage := NewExtractorFunc("falha", func(page *ExtractionPage, property *repository.Property) error {
+		calls = append(calls, "falha")
+		return fmt.Errorf("selector not found")
+	})
+	assert.NoError(t, RegisterExtractor(codeStage))
+	assert.NoError(t, RegisterExtractor(failingStage))
+	defer func() {
+		UnregisterExtractor("codigo_interno")
+		UnregisterExtractor("falha")
+		assert.NoError(t, defaultPipeline.SetOrder(nil))
+	}()
+
+	assert.Error(t, RegisterExtractor(codeStage), "duplicate names are rejected")
+	assert.Error(t, RegisterExtractor(NewExtractorFunc(CoreExtractorName, nil)))
+	assert.Equal(t, []string{CoreExtractorName, "codigo_interno", "falha"}, ExtractionStages())
+
+	html := `<html><body><span class="ref">REF-123</span><p>Casa com 3 quartos à venda, R$ 350.000</p></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+	root := doc.Find("html")
+	element := colly.NewHTMLElementFromSelectionNode(&colly.Response{}, root, root.Nodes[0], 0)
+
+	property := NewDataExtractor().ExtractProperty(element, "https://imobiliaria.com.br/imovel/9")
+	assert.Equal(t, []string{"codigo_interno", "falha"}, calls, "a failing stage does not stop extraction")
+	value, exists := property.CustomField("codigo_interno")
+	assert.True(t, exists)
+	assert.Equal(t, "REF-123", value)
+	assert.Equal(t, repository.ExtractorPlugin, property.Provenance["custom_fields.codigo_interno"].Extractor)
+	assert.Equal(t, 3, property.Quartos)
+
+	// Ordem configurada: etapas fora da lista ficam desativadas
+	assert.Error(t, defaultPipeline.SetOrder([]string{"inexistente"}))
+	assert.NoError(t, defaultPipeline.SetOrder([]string{"codigo_interno", CoreExtractorName}))
+	assert.Equal(t, []string{"codigo_interno", CoreExtractorName}, ExtractionStages())
+	calls = nil
+	NewDataExtractor().ExtractProperty(element, "https://imobiliaria.com.br/imovel/10")
+	assert.Equal(t, []string{"codigo_interno"}, calls)
+}
+
+func TestScriptHooks_PostProcess(t *testing.T) {
+	script := `
+		if property.valor > 0 and property.valor < 1000 then
+			property.valor = property.valor * 1000
+		end
+		property.bairro = string.gsub(property.bairro, "^Bairro ", "")
+		table.insert(property.caracteristicas, "Portal: " .. domain)
+		property.custom_fields.preco_m2 = property.valor / property.area_total
+	`
+	runtime := NewRuntimeState()
+	runtime.Set(&config.RuntimeSnapshot{Domains: map[string]repository.DomainConfig{
+		"imobiliaria.com.br": {Domain: "imobiliaria.com.br", PostProcessScript: script},
+	}})
+
+	property := &repository.Property{
+		URL:       "https://www.imobiliaria.com.br/imovel/1",
+		Valor:     350,
+		Bairro:    "Bairro Centro",
+		AreaTotal: 100,
+	}
+	changed, err := runtime.PostProcess(property)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"valor", "bairro", "caracteristicas"}, changed)
+	assert.Equal(t, 350000.0, property.Valor)
+	assert.Equal(t, "Centro", property.Bairro)
+	assert.Equal(t, []string{"Portal: imobiliaria.com.br"}, property.Caracteristicas)
+	assert.Equal(t, 3500.0, property.CustomFields["preco_m2"])
+	assert.Equal(t, repository.ExtractorScript, property.Provenance["valor"].Extractor)
+
+	// Domínios sem script não são alterados
+	other := &repository.Property{URL: "https://outra.com/imovel/1", Valor: 350}
+	changed, err = runtime.PostProcess(other)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+	assert.Equal(t, 350.0, other.Valor)
+
+	hooks := NewScriptHooks()
+	hooks.timeout = 50 * time.Millisecond
+	_, err = hooks.Run("while true do end", &repository.Property{})
+	assert.Error(t, err, "runaway scripts are interrupted")
+	_, err = hooks.Run(`dofile("/etc/passwd")`, &repository.Property{})
+	assert.Error(t, err, "file access is not available")
+	_, err = hooks.Run("property.valor = ", &repository.Property{})
+	assert.Error(t, err)
+
+	// Memória limitada: strings grandes, recursão e pilha de dados
+	for _, script := range []string{
+		`property.descricao = string.rep("x", 1e9)`,
+		`property.descricao = string.rep("x", 1e5, string.rep("y", 1e3))`,
+		`property.descricao = string.format("%999999999d", 1)`,
+		`property.descricao = string.gsub(string.rep("x", 1e5), "", string.rep("y", 100))`,
+		`local s = string.rep("x", 1e6) local t = {} for i = 1, 1e4 do t[i] = s end property.descricao = table.concat(t)`,
+		`local s = string.rep("x", 1e6) property.descricao = s .. s`,
+		`local function f(n) return f(n + 1) + 1 end f(1)`,
+		`local t = {} for i = 1, 1e5 do t[i] = i end property.quartos = select("#", unpack(t))`,
+	} {
+		_, err = hooks.Run(script, &repository.Property{})
+		assert.Error(t, err, script)
+	}
+	changed, err = hooks.Run(`property.descricao = string.format("%5.2f", 3.14159) .. string.rep("-", 3)`, &repository.Property{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"descricao"}, changed)
+}
+
+func TestGlobalRateController(t *testing.T) {
+	t.Run("PacesRequests", func(t *testing.T) {
+		controller := NewGlobalRateController(1200, 4) // uma página a cada 50ms
+		controller.parallelism = 4
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			controller.Acquire("exemplo.com.br")
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+		assert.Equal(t, 3, controller.Stats().InFlight["exemplo.com.br"])
+	})
+
+	t.Run("LimitsDomainParallelism", func(t *testing.T) {
+		controller := NewGlobalRateController(0, 2)
+		controller.Acquire("exemplo.com.br")
+
+		acquired := make(chan struct{})
+		go func() {
+			controller.Acquire("exemplo.com.br")
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("second request should wait for a free domain slot")
+		case <-time.After(50 * time.Millisecond):
+		}
+		controller.Acquire("outro.com.br") // outros domínios não são bloqueados
+
+		controller.Release("exemplo.com.br")
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("released slot was not handed to the waiting request")
+		}
+	})
+
+	t.Run("AdjustsParallelism", func(t *testing.T) {
+		controller := NewGlobalRateController(60, 3)
+		now := time.Now()
+
+		// Abaixo da meta com requisições aguardando vaga: aumenta o paralelismo
+		controller.windowStart = now.Add(-time.Minute)
+		controller.windowPages = 20
+		controller.blocked = true
+		controller.adjust(now)
+		assert.Equal(t, 2, controller.parallelism)
+		assert.InDelta(t, 20, controller.observedRate, 0.01)
+
+		// Abaixo da meta sem espera por vaga: mantém
+		controller.windowStart = now.Add(-time.Minute)
+		controller.windowPages = 20
+		controller.adjust(now)
+		assert.Equal(t, 2, controller.parallelism)
+
+		// Acima da meta: reduz
+		controller.windowStart = now.Add(-time.Minute)
+		controller.windowPages = 90
+		controller.adjust(now)
+		assert.Equal(t, 1, controller.parallelism)
+		assert.Equal(t, 2, controller.Stats().Adjustments)
+	})
+
+	t.Run("RuntimeStateReleasesOnResponse", func(t *testing.T) {
+		runtime := NewRuntimeState()
+		runtime.domainThrottle().SetGlobalRateController(NewGlobalRateController(0, 2))
+
+		pageURL, _ := url.Parse("https://www.exemplo.com.br/imovel/1")
+		request := &colly.Request{URL: pageURL, Ctx: colly.NewContext(), ID: 7}
+		runtime.Acquire(request)
+		assert.Equal(t, 1, runtime.domainThrottle().GlobalRate().InFlight["exemplo.com.br"])
+
+		runtime.RecordResponse(&colly.Response{Request: request, StatusCode: http.StatusOK})
+		runtime.RecordResponse(&colly.Response{Request: request, StatusCode: http.StatusOK})
+		stats := runtime.domainThrottle().GlobalRate()
+		assert.Empty(t, stats.InFlight)
+		assert.Equal(t, int64(1), stats.TotalPages)
+	})
+}
+
+func TestAPIFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"data":{"items":[
+				{"id":10,"preco":"R$ 450.000,00","endereco":{"rua":"Rua A, 100","bairro":"Centro"},"area":"120 m²","fotos":[{"u":"a"},{"u":"b"}],"extras":["Piscina","Garagem"]},
+				{"id":11,"preco":320000,"endereco":{"rua":"Rua B, 200","bairro":"Jardim"},"area":85.5}]}}`)
+		case "2":
+			fmt.Fprint(w, `{"data":{"items":[{"id":11,"preco":320000}]}}`) // repetido: fim da paginação
+		default:
+			fmt.Fprint(w, `{"data":{"items":[]}}`)
+		}
+	}))
+	defer server.Close()
+
+	portal := "http://api.portal.test"
+	apiConfig := &repository.DomainAPIConfig{
+		Endpoint:    portal + "/api/busca?page={page}",
+		ResultsPath: "data.items",
+		URLTemplate: "/imovel/{id}",
+		FieldMapping: map[string]string{
+			"valor":           "preco",
+			"endereco":        "endereco.rua",
+			"bairro":          "endereco.bairro",
+			"area_total":      "area",
+			"fotos":           "fotos",
+			"caracteristicas": "extras",
+		},
+	}
+	assert.NoError(t, apiConfig.Validate("portal.test"))
+	assert.NoError(t, apiConfig.Validate("www.api.portal.test"))
+	assert.Error(t, apiConfig.Validate("outro.test"))
+	assert.Error(t, apiConfig.Validate("tal.test"))
+
+	// O leitor padrão recusa endereços internos
+	fetcher := NewAPIFetcher(NewRuntimeState(), NewDiscoveryTracker())
+	internal := *apiConfig
+	internal.Endpoint = server.URL + "/api/busca?page={page}"
+	_, err := fetcher.Fetch(context.Background(), server.URL, &internal, func(*repository.Property) bool { return true })
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+
+	var properties []*repository.Property
+	fetcher.SetTransport(testServerTransport(server))
+	count, err := fetcher.Fetch(context.Background(), portal, apiConfig, func(property *repository.Property) bool {
+		properties = append(properties, property)
+		return true
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, portal+"/imovel/10", properties[0].URL)
+	assert.Equal(t, 450000.0, properties[0].Valor)
+	assert.Equal(t, "R$ 450.000,00", properties[0].ValorTexto)
+	assert.Equal(t, 120.0, properties[0].AreaTotal)
+	assert.Equal(t, 2, properties[0].Fotos)
+	assert.Equal(t, []string{"Piscina", "Garagem"}, properties[0].Caracteristicas)
+	assert.Equal(t, repository.ExtractorAPI, properties[0].Provenance["valor"].Extractor)
+	assert.Equal(t, 320000.0, properties[1].Valor)
+	assert.Equal(t, 85.5, properties[1].AreaTotal)
+	assert.True(t, properties[1].SemFotos)
+
+	assert.Equal(t, 1250.5, jsonNumber("1.250,50"))
+	assert.Equal(t, 1250000.0, jsonNumber("1.250.000"))
+	assert.Equal(t, 85.5, jsonNumber("85.5 m²"))
+	assert.Nil(t, lookupJSONPath(map[string]interface{}{"a": []interface{}{1.0}}, "a.3"))
+
+	invalid := &repository.DomainAPIConfig{Endpoint: "ftp://x", FieldMapping: map[string]string{"url": "link"}}
+	assert.Error(t, invalid.Validate("x"))
+	invalid = &repository.DomainAPIConfig{Endpoint: portal, FieldMapping: map[string]string{"valor": "preco"}}
+	assert.Error(t, invalid.Validate("portal.test"))
+	invalid = &repository.DomainAPIConfig{Endpoint: "http://169.254.169.254/latest", FieldMapping: map[string]string{"url": "link"}}
+	assert.Error(t, invalid.Validate("portal.test"))
+}
+
+func TestFeedIngester(t *testing.T) {
+	rss := `<?xml version="1.0"?><rss version="2.0"><channel><title>Novos</title>
+		<item><title>Casa 3 quartos</title><link>/imovel/1</link><pubDate>Mon, 12 Oct 2026 10:00:00 -0300</pubDate></item>
+		<item><title>Apartamento</title><guid>https://portal.com.br/imovel/2</guid></item>
+	</channel></rss>`
+	atom := `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Novos</title>
+		<entry><title>Terreno</title><link rel="self" href="/self"/><link href="https://portal.com.br/imovel/3"/><updated>2026-10-12T10:00:00Z</updated></entry>
+	</feed>`
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/atom.xml" {
+			fmt.Fprint(w, atom)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, rss)
+	}))
+	defer server.Close()
+
+	ingester := NewFeedIngester(nil)
+	entries := ingester.Poll(context.Background(), []string{server.URL + "/rss.xml", server.URL + "/atom.xml"})
+
+	assert.Len(t, entries, 3)
+	assert.Equal(t, server.URL+"/imovel/1", entries[0].URL)
+	assert.Equal(t, "Casa 3 quartos", entries[0].Title)
+	assert.Equal(t, 2026, entries[0].Published.Year())
+	assert.Equal(t, "https://portal.com.br/imovel/2", entries[1].URL)
+	assert.Equal(t, "https://portal.com.br/imovel/3", entries[2].URL)
+	assert.Equal(t, 3, ingester.PendingCount())
+
+	// Segunda leitura: RSS não mudou (304) e a entrada Atom já está na fila
+	assert.Empty(t, ingester.Poll(context.Background(), []string{server.URL + "/rss.xml", server.URL + "/atom.xml"}))
+	taken := ingester.TakePending()
+	assert.Len(t, taken, 3)
+	assert.Equal(t, 0, ingester.PendingCount())
+	assert.Len(t, ingester.Status(), 2)
+
+	// Em crawling, a entrada não volta a ser enfileirada pelo feed
+	assert.Empty(t, ingester.Poll(context.Background(), []string{server.URL + "/atom.xml"}))
+
+	// Só as crawleadas ficam vistas; as que falharam voltam para a fila
+	ingester.Complete(taken, taken[:1])
+	assert.Equal(t, 2, ingester.PendingCount())
+	retry := ingester.TakePending()
+	assert.Len(t, retry, 2)
+	ingester.Complete(retry, retry[1:])
+	retry = ingester.TakePending()
+	assert.Equal(t, []FeedEntry{taken[1]}, retry)
+
+	// A entrada que falhou em todas as tentativas (maxFeedAttempts) é abandonada
+	ingester.Complete(retry, nil)
+	assert.Equal(t, 0, ingester.PendingCount())
+	assert.Empty(t, ingester.Poll(context.Background(), []string{server.URL + "/atom.xml"}))
+
+	_, err := ParseFeed([]byte(`<html><body>not a feed</body></html>`), server.URL)
+	assert.Error(t, err)
+}
+
+func TestPortalSeeds(t *testing.T) {
+	templates := []PortalTemplate{
+		{Name: "vivareal", SearchURL: "https://www.vivareal.com.br/venda/{state_name_slug}/{city_slug}/?pagina={page}", Pages: 2, Enabled: true},
+		{Name: "olx", SearchURL: "https://www.olx.com.br/imoveis/venda/estado-{state_slug}?q={city}", Enabled: true},
+		{Name: "zap", SearchURL: "https://www.zapimoveis.com.br/venda/imoveis/{state_slug}+{city_slug}/", Enabled: false},
+	}
+	for _, template := range templates {
+		assert.NoError(t, template.Validate())
+	}
+
+	cities, err := ParseCityList("Poços de Caldas-MG, Embu-Guaçu/sp")
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.CityInfo{{Name: "Poços de Caldas", State: "MG"}, {Name: "Embu-Guaçu", State: "SP"}}, cities)
+
+	seeds := GeneratePortalSeeds(templates, cities)
+	assert.Equal(t, []string{
+		"https://www.vivareal.com.br/venda/minas-gerais/pocos-de-caldas/?pagina=1",
+		"https://www.vivareal.com.br/venda/minas-gerais/pocos-de-caldas/?pagina=2",
+		"https://www.vivareal.com.br/venda/sao-paulo/embu-guacu/?pagina=1",
+		"https://www.vivareal.com.br/venda/sao-paulo/embu-guacu/?pagina=2",
+		"https://www.olx.com.br/imoveis/venda/estado-mg?q=Po%C3%A7os+de+Caldas",
+		"https://www.olx.com.br/imoveis/venda/estado-sp?q=Embu-Gua%C3%A7u",
+	}, seeds)
+
+	_, err = ParseCityList("Muzambinho")
+	assert.Error(t, err)
+	_, err = ParseCityList("Muzambinho-XX")
+	assert.Error(t, err)
+	assert.Error(t, PortalTemplate{Name: "bad", SearchURL: "https://portal.com/busca?page={page}"}.Validate())
+	assert.Error(t, PortalTemplate{Name: "bad", SearchURL: "{city_slug}/imoveis"}.Validate())
+
+	templates, err = LoadPortalTemplates("../../configs/portals.json")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, templates)
+}
+
+func TestEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	eventLog, err := NewEventLog(path)
+	assert.NoError(t, err)
+	SetEventLog(eventLog)
+	defer SetEventLog(nil)
+
+	emitPageClassified("https://www.imobiliaria.com.br/imovel/1", "property", 0.9, "ok")
+	emitPropertySaved("https://www.imobiliaria.com.br/imovel/1", &repository.Property{TipoImovel: "Casa", Valor: 350000})
+	NewErrorTracker().Record(ErrorTypeFetch, "https://www.imobiliaria.com.br/imovel/2", "timeout", 504)
+	assert.NoError(t, eventLog.Close())
+	eventLog.Emit(CrawlEvent{Type: EventError}) // após Close é ignorado
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+
+	var events []CrawlEvent
+	for _, line := range lines {
+		var event CrawlEvent
+		assert.NoError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+	assert.Equal(t, EventPageClassified, events[0].Type)
+	assert.Equal(t, "property", events[0].Data["page_type"])
+	assert.Equal(t, "imobiliaria.com.br", events[0].Domain)
+	assert.Equal(t, EventPropertySaved, events[1].Type)
+	assert.Equal(t, float64(350000), events[1].Data["valor"])
+	assert.Equal(t, EventError, events[2].Type)
+	assert.Equal(t, ErrorTypeFetch, events[2].Data["error_type"])
+	assert.False(t, events[2].Time.IsZero())
+}
+
+func TestRevisitPolicy(t *testing.T) {
+	day := 24 * time.Hour
+	policy := NewRevisitPolicy(day, 0, 7*day)
+	assert.Equal(t, day, policy.Min)
+
+	// Página estática: o intervalo dobra até o máximo semanal
+	interval := policy.Initial
+	for i := 0; i < 5; i++ {
+		interval = policy.NextInterval(interval, false)
+	}
+	assert.Equal(t, 7*day, interval)
+
+	// Catálogo que muda a cada visita volta ao mínimo diário
+	assert.Equal(t, 2*day, policy.NextInterval(4*day, true))
+	assert.Equal(t, day, policy.NextInterval(day, true))
+	assert.Equal(t, 2*day, policy.NextInterval(0, false))
+
+	now := time.Now()
+	assert.True(t, policy.IsDue(nil, now))
+	assert.True(t, policy.IsDue(&repository.PageFingerprint{LastCrawled: now}, now))
+	fingerprint := &repository.PageFingerprint{LastCrawled: now.Add(-3 * day), RevisitInterval: 4 * day}
+	assert.False(t, policy.IsDue(fingerprint, now))
+	assert.True(t, policy.IsDue(fingerprint, now.Add(day)))
+
+	// Limites reduzidos valem para intervalos já gravados
+	shorter := NewRevisitPolicy(day, day, 2*day)
+	assert.True(t, shorter.IsDue(fingerprint, now))
+}
+
+// memoryURLLeases simula o registro compartilhado de URLs em andamento
+type memoryURLLeases struct {
+	mutex  sync.Mutex
+	leases map[string]repository.URLLease
+}
+
+func (m *memoryURLLeases) AcquireURLLease(ctx context.Context, url, owner string, ttl time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	if lease, exists := m.leases[url]; exists && lease.Owner != owner && lease.ExpiresAt.After(now) {
+		return false, nil
+	}
+	m.leases[url] = repository.URLLease{URL: url, Owner: owner, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (m *memoryURLLeases) ReleaseURLLease(ctx context.Context, url, owner string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if lease, exists := m.leases[url]; exists && lease.Owner == owner {
+		delete(m.leases, url)
+	}
+	return nil
+}
+
+func TestSharedFrontier(t *testing.T) {
+	leases := &memoryURLLeases{leases: make(map[string]repository.URLLease)}
+	first := NewSharedFrontier(leases, "host/job-a", time.Minute)
+	second := NewSharedFrontier(leases, "host/job-b", time.Minute)
+	url := "https://portal.com.br/imovel/123"
+
+	assert.True(t, first.Claim(url))
+	assert.True(t, first.Claim(url), "o próprio job renova a lease")
+	assert.False(t, second.Claim(url))
+	assert.Equal(t, 1, second.Skipped())
+
+	// Falha na busca libera a URL para o outro job
+	second.Release(url)
+	assert.False(t, second.Claim(url), "apenas o dono libera a lease")
+	first.Release(url)
+	assert.True(t, second.Claim(url))
+
+	// Lease vencida pode ser tomada por outro job
+	expired := NewSharedFrontier(leases, "host/job-c", time.Nanosecond)
+	other := "https://portal.com.br/imovel/456"
+	assert.True(t, expired.Claim(other))
+	time.Sleep(time.Millisecond)
+	assert.True(t, first.Claim(other))
+
+	assert.True(t, strings.HasSuffix(FrontierOwner("crawl-1-1"), "/crawl-1-1"))
+	assert.NotEqual(t, FrontierOwner(""), FrontierOwner(""))
+}
+
+func TestContentChangedNeedsMatchingFields(t *testing.T) {
+	manager := NewPersistentURLManager(nil, PersistentURLConfig{EnableFingerprinting: true})
+	page := func(desc string) repository.ContentSignature {
+		return repository.GenerateContentSignature("Casa à venda. "+desc, []string{"R$ 450.000"}, []string{"Rua das Flores, 123"}, []string{desc})
+	}
+	original := page("Casa com 3 quartos, suíte e piscina")
+	existing := &repository.PageFingerprint{ContentHash: original.ContentHash, PriceHash: original.PriceHash, SimHash: original.SimHash}
+
+	// Simhash a 3 bits do anterior (dentro do limiar), como na troca de "3 quartos" por "4 quartos"
+	simhash, _ := repository.ParseSimHash(original.SimHash)
+	nearby := repository.FormatSimHash(simhash ^ 0b111)
+
+	edited := page("Casa com 4 quartos, suíte e piscina")
+	edited.SimHash = nearby
+	changed, distance := manager.contentChanged(existing, edited)
+	assert.Equal(t, 3, distance)
+	assert.True(t, changed, "campos extraídos diferentes contam como mudança mesmo com o simhash próximo")
+
+	// Mesmos campos extraídos: o simhash próximo é ruído do restante da página
+	noise := original
+	noise.SimHash = nearby
+	changed, _ = manager.contentChanged(existing, noise)
+	assert.False(t, changed)
+
+	// Mesmos campos, mas o restante da página mudou além do limiar
+	noise.SimHash = repository.FormatSimHash(^simhash)
+	changed, _ = manager.contentChanged(existing, noise)
+	assert.True(t, changed)
+}
+
+func TestKeptRecordKeepsOneDocumentPerURL(t *testing.T) {
+	listingURL := "https://imobiliaria.com.br/imovel/casa-centro"
+
+	// Documentos por hash, como o upsert do MongoRepository.Save
+	documents := map[string]repository.Property{}
+	save := func(p repository.Property) {
+		p.Hash = repository.GeneratePropertyHash(p)
+		if _, exists := documents[p.Hash]; !exists {
+			documents[p.Hash] = p
+		}
+	}
+	countURL := func() int {
+		count := 0
+		for _, doc := range documents {
+			if doc.URL == listingURL {
+				count++
+			}
+		}
+		return count
+	}
+
+	raw := repository.Property{
+		URL:       listingURL,
+		Endereco:  "rua das flores 10",
+		Cidade:    "Muzambinho",
+		Descricao: "Casa com 3 quartos e piscina no centro",
+		Valor:     450000,
+		Quartos:   3,
+		Fotos:     8,
+	}
+
+	// Primeira visita: o registro salvo é a versão corrigida pela IA, com a extração original
+	stored := raw
+	stored.Endereco = "Rua das Flores, 10"
+	stored.Bairro = "Centro"
+	stored.Extracao = repository.NewExtractionSnapshot(raw)
+	save(stored)
+	stored.Hash = repository.GeneratePropertyHash(stored)
+
+	// Segunda visita: só a quantidade de fotos mudou
+	current := raw
+	current.Fotos = 9
+	current.Tags = []string{"job-2"}
+	change := repository.ClassifyPropertyChange(stored.Extracao.Property(), current)
+	assert.False(t, change.Material)
+
+	// A extração crua teria outro hash e um segundo documento para a URL
+	assert.NotEqual(t, stored.Hash, repository.GeneratePropertyHash(current))
+
+	kept := keptRecord(&stored, &current)
+	assert.Equal(t, "Rua das Flores, 10", kept.Endereco)
+	assert.Equal(t, []string{"job-2"}, kept.Tags)
+	save(*kept)
+	assert.Equal(t, 1, countURL())
+}
+
+func TestIncrementalEngineSharedFrontier(t *testing.T) {
+	var mutex sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		hits[r.URL.Path]++
+		mutex.Unlock()
+		// Conteúdo sem HTML: só o registro de leases é exercitado
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var urls []string
+	for i := 0; i < 5; i++ {
+		urls = append(urls, fmt.Sprintf("%s/imovel/%d", server.URL, i))
+	}
+
+	// Dois jobs incrementais simultâneos sobre as mesmas URLs
+	leases := &memoryURLLeases{leases: make(map[string]repository.URLLease)}
+	var frontiers []*SharedFrontier
+	var wg sync.WaitGroup
+	for _, owner := range []string{"host/job-a", "host/job-b"} {
+		engine := NewIncrementalCrawlerEngine(nil, nil, nil, IncrementalConfig{DelayBetweenRequests: time.Millisecond})
+		frontier := NewSharedFrontier(leases, owner, time.Minute)
+		engine.SetSharedFrontier(frontier)
+		frontiers = append(frontiers, frontier)
+
+		collector := engine.setupCollector()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, url := range urls {
+				collector.Visit(url)
+			}
+			collector.Wait()
+		}()
+	}
+	wg.Wait()
+
+	// Cada URL é buscada por apenas um dos jobs; o outro a pula
+	assert.Len(t, hits, len(urls))
+	for path, count := range hits {
+		assert.Equal(t, 1, count, path)
+	}
+	assert.Equal(t, len(urls), frontiers[0].Skipped()+frontiers[1].Skipped())
+}
+
+func TestContentSanitizer(t *testing.T) {
+	description := strings.Repeat("Casa ampla com 3 quartos, suíte, varanda gourmet e quintal, próxima ao centro. ", 4)
+	page := `<html><head><title>Casa</title><style>.x{color:red}</style><script>var tracking = "abc";</script></head>
+<body>
+  <header class="site-header"><nav><a href="/">Início</a><a href="/venda">Venda</a></nav></header>
+  <div class="menu-principal"><a href="/a">A</a></div>
+  <form><select name="bairro"><option>Centro</option><option>Jardim América</option></select></form>
+  <!-- comentário -->
+  <div id="content" class="property-detail" data-tracking="x">
+    <h1 class="titulo">Casa à venda no Centro</h1>
+    <p class="preco" style="color:red">R$ 450.000</p>
+    <p class="descricao">` + description + `</p>
+  </div>
+  <footer><p>Imobiliária Exemplo - CRECI 1234 - Todos os direitos reservados, 2024</p></footer>
+</body></html>`
+
+	text := ai.ExtractMainText(page)
+	assert.Contains(t, text, "R$ 450.000")
+	assert.Contains(t, text, "Casa à venda no Centro")
+	assert.NotContains(t, text, "tracking")
+	assert.NotContains(t, text, "Jardim América")
+	assert.NotContains(t, text, "CRECI")
+	assert.NotContains(t, text, "Início")
+
+	sanitized := ai.SanitizeHTML(page)
+	assert.Contains(t, sanitized, `class="preco"`)
+	assert.NotContains(t, sanitized, "style=")
+	assert.NotContains(t, sanitized, "data-tracking")
+	assert.NotContains(t, sanitized, "comentário")
+
+	// Texto simples apenas tem os espaços normalizados; o orçamento corta entre palavras
+	assert.Equal(t, "Casa 3 quartos", ai.ExtractMainText("  Casa   3\tquartos "))
+	capped := ai.CapTokens(description, 10)
+	assert.True(t, strings.HasSuffix(capped, "..."))
+	assert.LessOrEqual(t, ai.EstimateTokens(capped), 11)
+
+	before := ai.GetSanitizerStats().ByCall[ai.AICallValidation]
+	prepared := ai.PrepareContent(ai.AICallValidation, page, ai.ValidationTokenBudget, false)
+	after := ai.GetSanitizerStats().ByCall[ai.AICallValidation]
+	assert.Equal(t, before.Calls+1, after.Calls)
+	assert.Equal(t, int64(ai.EstimateTokens(page)-ai.EstimateTokens(prepared)), after.SavedTokens-before.SavedTokens)
+	assert.Greater(t, after.SavedPercent, 0.0)
+}
+
+func TestReadabilityMainContent(t *testing.T) {
+	page := `<html><head><title>Casa no Centro</title></head><body>
+  <nav><a href="/apartamentos">Apartamentos</a> <a href="/casas">Casas</a> <a href="/terrenos">Terrenos</a></nav>
+  <div class="listing">
+    <h1>Casa com 3 quartos no Centro</h1>
+    <p>Por R$ 450.000. Código: CA1234. Casa com 3 quartos, 2 banheiros, 1 suíte, 2 vagas e 180 m² de área construída.</p>
+    <p>Localizada na Rua das Flores, 123, bairro Centro, perto de escolas, mercados, farmácias e do hospital municipal.</p>
+    <p>Cozinha planejada, sala ampla com lareira, quintal gramado, churrasqueira coberta, lavanderia e depósito nos fundos.</p>
+  </div>
+  <footer>Encontre imóveis em todos os bairros da cidade. Casas, apartamentos e terrenos à venda.</footer>
+</body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	assert.NoError(t, err)
+
+	article := readability.FromSelection(doc.Selection)
+	assert.False(t, article.Fallback)
+	assert.Equal(t, "Casa no Centro", article.Title)
+	assert.Contains(t, article.Text, "R$ 450.000")
+	assert.NotContains(t, article.Text, "Terrenos")
+	assert.NotContains(t, article.Text, "todos os bairros")
+	assert.Less(t, article.LinkDensity, 0.1)
+
+	// O documento original continua intacto para a extração
+	assert.Equal(t, 1, doc.Find("nav").Length())
+	assert.Equal(t, 1, doc.Find("footer").Length())
+
+	// "todos os bairros" no rodapé não faz mais o anúncio ser tratado como catálogo
+	result := NewPrecisePropertyClassifier().ClassifyPage(doc, "https://imobiliaria.com.br/imovel/CA1234")
+	assert.Empty(t, result.Details.FoundExclusions)
+	assert.True(t, result.IsIndividualProperty, result.Reason)
+
+	structural := article.HTML("class")
+	assert.True(t, strings.HasPrefix(structural, `<div class="listing"><h1>`))
+}
+
+func TestConcurrencyTuner_AutoTune(t *testing.T) {
+	domains, err := ParseDomainParallelism([]string{"www.lento.com.br=1", " rapido.com.br = 3 "})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"lento.com.br": 1, "rapido.com.br": 3}, domains)
+	_, err = ParseDomainParallelism([]string{"lento.com.br=0"})
+	assert.Error(t, err)
+
+	tuner := NewConcurrencyTuner("main", ConcurrencyConfig{
+		Parallelism:       2,
+		DomainParallelism: domains,
+		AutoTune:          true,
+		MaxParallelism:    3,
+		MaxErrorRate:      0.1,
+		MaxLatency:        time.Second,
+	})
+
+	// O limite do domínio bloqueia a segunda requisição até a primeira terminar
+	tuner.Acquire("lento.com.br")
+	acquired := make(chan struct{})
+	go func() {
+		tuner.Acquire("lento.com.br")
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second request should wait for a free slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+	tuner.Release("lento.com.br", 100*time.Millisecond, false)
+	<-acquired
+	tuner.Release("lento.com.br", 100*time.Millisecond, false)
+
+	// Respostas rápidas e sem erro com requisições aguardando vaga: sobe um passo, até o teto
+	window := func(domain string, latency time.Duration, failed bool) {
+		tuner.mutex.Lock()
+		tuner.domainState(domain).blocked = true
+		tuner.mutex.Unlock()
+		for i := 0; i < concurrencyTuneSamples; i++ {
+			tuner.Acquire(domain)
+			tuner.Release(domain, latency, failed)
+		}
+	}
+	window("site.com.br", 200*time.Millisecond, false)
+	window("site.com.br", 200*time.Millisecond, false)
+	stats := tuner.Stats()
+	assert.Equal(t, "site.com.br", stats[1].Domain)
+	assert.Equal(t, 3, stats[1].Parallelism)
+	assert.Equal(t, int64(200), stats[1].AvgLatencyMs)
+
+	// Latência alta derruba o paralelismo pela metade
+	window("site.com.br", 2*time.Second, false)
+	assert.Equal(t, 1, tuner.Stats()[1].Parallelism)
+
+	// Pelo coletor: respostas 503 contam como erro e reduzem o paralelismo do domínio
+	collector := colly.NewCollector()
+	collector.WithTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}, Request: r}, nil
+	}))
+	detail := NewConcurrencyTuner("detail", ConcurrencyConfig{Parallelism: 2, AutoTune: true, MaxErrorRate: 0.1})
+	detail.Attach(collector)
+	for i := 0; i < concurrencyTuneSamples; i++ {
+		collector.Visit(fmt.Sprintf("http://rapido.com.br/imovel/%d", i))
+	}
+	stats = detail.Stats()
+	assert.Len(t, stats, 1)
+	assert.Equal(t, 1, stats[0].Parallelism)
+	assert.Equal(t, 1.0, stats[0].ErrorRate)
+	assert.Equal(t, 0, stats[0].InFlight)
+}
+
+func TestLinkContextExtractor(t *testing.T) {
+	page := `<html><body>
+  <nav class="menu"><ul>
+    <li><a href="/casas">Casas</a></li><li><a href="/apartamentos">Apartamentos</a></li><li><a href="/terrenos">Terrenos</a></li>
+  </ul></nav>
+  <div class="listagem">
+    <div class="card"><a href="/c/101"><img src="/f/101.jpg" alt="Fachada da casa no Centro"></a>
+      <h2>Casa com 3 quartos</h2><span class="valor">R$ 450.000,00</span></div>
+    <div class="card destaque"><a href="/c/102"><img src="/f/102.jpg" alt="Sala do apartamento"></a>
+      <h2>Apartamento no Jardim América</h2><span class="valor">R$ 320.000</span></div>
+    <div class="card"><a href="/c/103">Terreno 360 m²</a><span class="valor">R$ 120.000</span></div>
+  </div>
+  <p>Veja também: <a id="avulso" href="/c/200">oportunidade no bairro Vila Nova</a></p>
+  <div><b>R$ 99.000</b></div>
+</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	assert.NoError(t, err)
+	extractor := NewLinkContextExtractor()
+
+	// O card do anúncio (cards em destaque têm classes extras), sem o menu nem os outros cards
+	context := extractor.Extract(doc.Find(`a[href="/c/102"]`))
+	assert.Contains(t, context, "Card: Apartamento no Jardim América R$ 320.000")
+	assert.Contains(t, context, "Imagens: Sala do apartamento")
+	assert.Contains(t, context, "Preços: R$ 320.000")
+	assert.NotContains(t, context, "Terrenos")
+	assert.NotContains(t, context, "450.000")
+
+	// Links do menu recebem apenas o próprio item
+	assert.Equal(t, "Card: Apartamentos", extractor.Extract(doc.Find(`a[href="/apartamentos"]`)))
+
+	// Sem card: texto do elemento pai e preços dos irmãos curtos
+	context = extractor.Extract(doc.Find("#avulso"))
+	assert.Contains(t, context, "Card: Veja também: oportunidade no bairro Vila Nova")
+	assert.Contains(t, context, "Preços: R$ 99.000")
+
+	assert.Equal(t, "Casa no Jardim Amér...", truncateRunes("Casa no Jardim América", 19))
+}
+
+func TestConfidenceCalibrator(t *testing.T) {
+	// Calibrador nil e fontes sem curva mantêm a nota original
+	var empty *ConfidenceCalibrator
+	assert.Equal(t, 0.8, empty.Score(CalibrationSourceAI, "http://a.test/x", 0.8, true))
+
+	calibrator := NewConfidenceCalibrator()
+	calibrator.StartRecording()
+	assert.Equal(t, 0.8, calibrator.Score(CalibrationSourcePattern, "http://a.test/x", 0.8, true))
+
+	// IA superconfiante: nota 0.9 acerta metade das vezes, nota 0.6 erra sempre
+	labels := make(map[string]bool)
+	for i := 0; i < 30; i++ {
+		pageURL := fmt.Sprintf("http://a.test/imovel-%d", i)
+		labels[pageURL] = i%2 == 0
+		calibrator.Score(CalibrationSourceAI, pageURL, 0.9, true)
+	}
+	for i := 0; i < 10; i++ {
+		pageURL := fmt.Sprintf("http://a.test/blog-%d", i)
+		labels[pageURL] = false
+		calibrator.Score(CalibrationSourceAI, pageURL, 0.6, true)
+	}
+	// Poucas amostras rotuladas: a fonte de conteúdo não é ajustada
+	for i := 0; i < 5; i++ {
+		calibrator.Score(CalibrationSourceContent, fmt.Sprintf("http://a.test/imovel-%d", i), 0.7, true)
+	}
+
+	updated := calibrator.Refit(func(pageURL string) (bool, bool) {
+		isProperty, exists := labels[pageURL]
+		return isProperty, exists
+	})
+	assert.Equal(t, []string{CalibrationSourceAI}, updated)
+
+	curves := calibrator.Curves()
+	if !assert.Len(t, curves, 1) {
+		return
+	}
+	assert.Equal(t, 40, curves[0].Samples)
+	assert.InDelta(t, 15.0/40, curves[0].Accuracy, 0.001)
+	for i := 1; i < len(curves[0].Points); i++ {
+		assert.GreaterOrEqual(t, curves[0].Points[i].Probability, curves[0].Points[i-1].Probability)
+	}
+
+	assert.InDelta(t, 0.5, calibrator.Calibrate(CalibrationSourceAI, 0.9), 0.001)
+	assert.InDelta(t, 0.0, calibrator.Calibrate(CalibrationSourceAI, 0.6), 0.001)
+	assert.InDelta(t, 0.25, calibrator.Calibrate(CalibrationSourceAI, 0.75), 0.001)
+	assert.InDelta(t, 0.5, calibrator.Calibrate(CalibrationSourceAI, 1.0), 0.001)
+	assert.Equal(t, 0.7, calibrator.Calibrate(CalibrationSourceContent, 0.7))
+
+	// Ida e volta pelo arquivo; arquivo inexistente resulta em calibrador vazio
+	path := filepath.Join(t.TempDir(), "calibration", "curves.json")
+	missing, err := LoadConfidenceCalibrator(path)
+	assert.NoError(t, err)
+	assert.Empty(t, missing.Curves())
+
+	assert.NoError(t, calibrator.Save(path))
+	loaded, err := LoadConfidenceCalibrator(path)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.25, loaded.Calibrate(CalibrationSourceAI, 0.75), 0.001)
+	assert.Equal(t, 0.7, loaded.Calibrate(CalibrationSourcePattern, 0.7))
+}
+
+// stubPageAI responde a classificação de páginas com um veredito fixo
+type stubPageAI struct {
+	result *ai.PageClassificationResult
+}
+
+func (s *stubPageAI) ClassifyPageContent(ctx context.Context, url, title, content string) (*ai.PageClassificationResult, error) {
+	return s.result, nil
+}
+
+func (s *stubPageAI) ValidateExtractedData(ctx context.Context, property repository.Property, originalHTML string) (repository.Property, error) {
+	return property, nil
+}
+
+func TestClassificationExplainer(t *testing.T) {
+	seedSet := MockBenchmarkSeedSet(3)
+	pageAI := &stubPageAI{result: &ai.PageClassificationResult{
+		IsPropertyPage: true, Confidence: 0.9, PageType: "property", Reasoning: "preço, endereço e quartos de um único imóvel",
+	}}
+	explainer := NewClassificationExplainer(pageAI, nil)
+	explainer.SetTransport(NewReplayTransport(seedSet))
+	ctx := context.Background()
+
+	propertyURL := mockBenchmarkHost + "/imovel/casa-2-quartos-1"
+	explanation, err := explainer.Explain(ctx, propertyURL, ExplainEngineAIIntegrated, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Casa à venda no Jardim América", explanation.Title)
+	assert.Equal(t, []string{"/imovel/"}, explanation.Patterns.URLPatterns)
+	assert.Equal(t, "unknown", explanation.Patterns.LearnedType)
+	assert.NotEmpty(t, explanation.Heuristics.Precise.Details.FoundRequirements)
+	assert.True(t, explanation.Heuristics.Indicators.Price)
+	assert.True(t, explanation.Heuristics.IndicatorsSayProperty)
+	if assert.NotNil(t, explanation.AI) {
+		assert.Equal(t, "preço, endereço e quartos de um único imóvel", explanation.AI.Reasoning)
+	}
+	assert.Len(t, explanation.Decisions, 3)
+	assert.Equal(t, ExplainEngineAIIntegrated, explanation.Decision.Engine)
+	assert.True(t, explanation.Decision.IsProperty)
+	assert.Equal(t, "property", explanation.Decision.Action)
+
+	// Catálogo: o recursivo explora os links; a IA com pouca confiança recorre aos indicadores
+	pageAI.result = &ai.PageClassificationResult{IsPropertyPage: false, Confidence: 0.5, PageType: "catalog"}
+	explanation, err = explainer.Explain(ctx, mockBenchmarkHost+"/imoveis", "", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, ExplainEngineRecursive, explanation.Decision.Engine)
+	assert.False(t, explanation.Decision.IsProperty)
+	assert.Equal(t, "catalog", explanation.Decision.Action)
+	assert.Contains(t, explanation.Decisions[2].Reason, "pouca confiança")
+	assert.False(t, explanation.Decisions[2].IsProperty)
+
+	_, err = explainer.Explain(ctx, propertyURL, "fastest", nil)
+	assert.Error(t, err)
+	_, err = explainer.Explain(ctx, mockBenchmarkHost+"/inexistente", "", nil)
+	assert.Error(t, err)
+}
+
+func TestGuardedTransport(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.0.10", "169.254.169.254", "fe80::1", "100.64.0.1", "0.0.0.0", "fd00::1"} {
+		assert.False(t, publicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "200.160.2.3", "2001:4860:4860::8888"} {
+		assert.True(t, publicIP(net.ParseIP(ip)), ip)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body>interno</body></html>")
+	}))
+	defer server.Close()
+
+	// O explicador padrão recusa endereços internos, inclusive nomes que resolvem para eles
+	explainer := NewClassificationExplainer(nil, nil)
+	_, err := explainer.Explain(context.Background(), server.URL, "", nil)
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	_, err = explainer.Explain(context.Background(), "http://localhost:"+port+"/", "", nil)
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+}
+
+func TestTrainingExport(t *testing.T) {
+	seedSet := MockBenchmarkSeedSet(2)
+	seedSet.Labels = map[string]bool{mockBenchmarkHost + "/imovel/casa-2-quartos-1": true, mockBenchmarkHost + "/sobre": false}
+	explainer := NewClassificationExplainer(nil, nil)
+
+	examples, err := BuildTrainingExamples(context.Background(), seedSet, explainer, TrainingExportOptions{SampleSize: 40})
+	assert.NoError(t, err)
+	assert.Len(t, examples, 6)
+
+	labeled, err := BuildTrainingExamples(context.Background(), seedSet, explainer, TrainingExportOptions{LabeledOnly: true})
+	assert.NoError(t, err)
+	if !assert.Len(t, labeled, 2) {
+		return
+	}
+	property := labeled[0]
+	assert.Equal(t, mockBenchmarkHost+"/imovel/casa-2-quartos-1", property.URL)
+	if assert.NotNil(t, property.HumanLabel) {
+		assert.True(t, *property.HumanLabel)
+	}
+	assert.Equal(t, 1, property.Features.URLPatterns)
+	assert.Greater(t, property.Features.Requirements, 0)
+	assert.True(t, property.Features.IndicatorPrice)
+	assert.Nil(t, property.Features.AIIsProperty)
+	assert.NotEmpty(t, property.FinalClass)
+
+	var csvOutput strings.Builder
+	assert.NoError(t, WriteTrainingExamples(&csvOutput, TrainingFormatCSV, examples))
+	lines := strings.Split(strings.TrimSpace(csvOutput.String()), "\n")
+	assert.Len(t, lines, 7)
+	assert.True(t, strings.HasPrefix(lines[0], "url,title,human_label,final_class,"))
+	assert.True(t, strings.HasSuffix(lines[0], ",content_sample"))
+	assert.True(t, strings.HasPrefix(lines[1], mockBenchmarkHost+"/,Imobiliária Benchmark,,"))
+
+	var jsonOutput strings.Builder
+	assert.NoError(t, WriteTrainingExamples(&jsonOutput, TrainingFormatJSONL, labeled))
+	jsonLines := strings.Split(strings.TrimSpace(jsonOutput.String()), "\n")
+	if assert.Len(t, jsonLines, 2) {
+		var decoded TrainingExample
+		assert.NoError(t, json.Unmarshal([]byte(jsonLines[1]), &decoded))
+		assert.Equal(t, mockBenchmarkHost+"/sobre", decoded.URL)
+		if assert.NotNil(t, decoded.HumanLabel) {
+			assert.False(t, *decoded.HumanLabel)
+		}
+		assert.LessOrEqual(t, len([]rune(examples[5].ContentSample)), 43)
+	}
+
+	assert.Error(t, WriteTrainingExamples(&jsonOutput, "parquet", examples))
+}
+
+func TestSiteMigration(t *testing.T) {
+	known := []repository.CityInfo{{Name: "Guaxupé", State: "MG"}}
+
+	inferred := map[string]string{
+		"https://www.guaxupeimoveis.com.br/imovel/apartamento/venda/guaxupe/mg/jardim-orminda/AP0048": "Guaxupé/MG",
+		"https://mattosonline.com.br/comprar/mg/pocos-de-caldas/jardim-carolina/apartamento/77439282": "Pocos de Caldas/MG",
+		"https://x.com.br/3546/imoveis/venda-casa-2-quartos-jardim-imperial-pocos-de-caldas-mg":       "Pocos de Caldas/MG",
+		"https://x.com.br/288/Venda-Casa-Padrao-Jardim-Sao-Luis-Vargem-Grande-do-Sul-SP-CA00325":      "Vargem Grande do Sul/SP",
+		"https://x.com.br/casa-a-venda-4-quartos-com-garagem-mg-varginha-campos-eliseos-221m2":        "Varginha/MG",
+		"https://x.com.br/imovel/venda-casa-guaranesia_mg--Leonardo-Imoveis/2106786":                  "Guaranesia/MG",
+		"https://biloimoveisguaxupe.com.br/imoveis/casa-nova-floresta-1-venda/":                       "Guaxupé/MG",
+	}
+	for rawURL, expected := range inferred {
+		city, found := InferSiteCity(rawURL, known)
+		assert.True(t, found, rawURL)
+		assert.Equal(t, expected, city.Name+"/"+city.State, rawURL)
+	}
+	for _, rawURL := range []string{
+		"https://x.com.br/propriedades/vende-se-bairro-vila-toninho-zeitune/",
+		"https://x.com.br/imovel/220334",
+	} {
+		_, found := InferSiteCity(rawURL, known)
+		assert.False(t, found, rawURL)
+	}
+
+	dir := t.TempDir()
+	listFile := filepath.Join(dir, "List-site.ini")
+	sitesFile := filepath.Join(dir, "sites.json")
+	assert.NoError(t, os.WriteFile(listFile, []byte(`# anúncios de referência
+1|https://www.exitoimoveis.com.br/imovel/casa-venda-mococa-sp-centro/SO0089
+https://www.exitoimoveis.com.br/imovel/sobrado-mococa-4-quartos-297-m/SO0090
+
+https://www.imoveiscasanova.com.br/imovel/220334
+`), 0644))
+	assert.NoError(t, os.WriteFile(sitesFile, []byte(`["https://www.exitoimoveis.com.br/imoveis/venda", "https://semcidade.com.br/"]`), 0644))
+
+	var entries []SiteFileEntry
+	for _, file := range []string{listFile, sitesFile} {
+		fileEntries, err := LoadSiteFile(file)
+		if !assert.NoError(t, err) {
+			return
+		}
+		entries = append(entries, fileEntries...)
+	}
+	assert.Len(t, entries, 5)
+	assert.True(t, entries[0].Listing)
+	assert.False(t, entries[3].Listing)
+
+	// O domínio com cidade vira um site (URL do sites.json) com os anúncios como referência
+	groups, unresolved := PlanSiteMigration(entries, nil, nil)
+	if !assert.Len(t, groups, 1) {
+		return
+	}
+	assert.Equal(t, repository.CityInfo{Name: "Mococa", State: "SP"}, groups[0].City)
+	if assert.Len(t, groups[0].Sites, 1) {
+		site := groups[0].Sites[0]
+		assert.Equal(t, "https://www.exitoimoveis.com.br/imoveis/venda", site.URL)
+		assert.Equal(t, DiscoveryMethodMigration, site.DiscoveryMethod)
+		assert.Equal(t, "List-site.ini,sites.json", site.Source)
+		assert.Len(t, site.ReferenceURLs, 2)
+	}
+	assert.ElementsMatch(t, []string{"https://www.imoveiscasanova.com.br/imovel/220334", "https://semcidade.com.br/"}, unresolved)
+
+	// Com cidade padrão nenhuma URL fica sem cidade; a raiz do domínio é a URL do site
+	groups, unresolved = PlanSiteMigration(entries, nil, &repository.CityInfo{Name: "Muzambinho", State: "MG"})
+	assert.Empty(t, unresolved)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, "Muzambinho", groups[0].City.Name)
+		assert.Equal(t, "https://www.imoveiscasanova.com.br/", groups[0].Sites[1].URL)
+	}
+}
+
+func TestSeedFile(t *testing.T) {
+	files := map[string]string{
+		"sites.json": `["https://a.com.br/imoveis", {"url": "https://b.com.br/", "city": "Muzambinho-MG", "tags": ["parceiro"]}]`,
+		"seeds.csv": `url,city,state,tags
+# comentário
+https://a.com.br/imoveis,,,
+https://b.com.br/,Muzambinho,MG,parceiro;premium
+`,
+		"seeds.yaml": `seeds:
+  - https://a.com.br/imoveis
+  - url: https://b.com.br/
+    city: Muzambinho-MG
+    tags: [parceiro]
+`,
+		"seeds.opml": `<?xml version="1.0"?>
+<opml version="2.0"><body>
+  <outline text="Imobiliárias" city="Muzambinho-MG">
+    <outline text="A" htmlUrl="https://a.com.br/imoveis"/>
+    <outline text="B" htmlUrl="https://b.com.br/" category="parceiro"/>
+  </outline>
+</body></opml>`,
+		"List-site.ini": `# referência
+1|https://a.com.br/imoveis
+não é URL
+https://b.com.br/
+`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		seeds, err := LoadSeedFile(path)
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.Equal(t, []string{"https://a.com.br/imoveis", "https://b.com.br/"}, SeedURLs(seeds), name)
+		if name != "List-site.ini" {
+			assert.Equal(t, "Muzambinho-MG", seeds[1].City, name)
+			assert.Contains(t, seeds[1].Tags, "parceiro", name)
+		}
+	}
+
+	// CSV sem cabeçalho (url,city,tags) e formato identificado pelo conteúdo
+	assert.Equal(t, SeedFormatJSON, DetectSeedFormat("seeds", []byte(" [\"https://a.com.br\"]")))
+	assert.Equal(t, SeedFormatText, DetectSeedFormat("List-site.ini", []byte("https://a.com.br")))
+	seeds, err := ParseSeeds([]byte("https://a.com.br/,Guaxupé-MG,novo|revisar\n"), SeedFormatCSV)
+	if assert.NoError(t, err) && assert.Len(t, seeds, 1) {
+		assert.Equal(t, Seed{URL: "https://a.com.br/", City: "Guaxupé-MG", Tags: []string{"novo", "revisar"}}, seeds[0])
+	}
+
+	// Formatos estruturados recusam entradas sem URL http(s)
+	_, err = ParseSeeds([]byte(`[{"city": "Muzambinho-MG"}]`), SeedFormatJSON)
+	assert.Error(t, err)
+
+	// A migração usa a cidade informada no arquivo em vez de deduzi-la da URL
+	entries, err := LoadSiteFile(filepath.Join(dir, "seeds.csv"))
+	if assert.NoError(t, err) {
+		groups, unresolved := PlanSiteMigration(entries, nil, nil)
+		assert.Equal(t, []string{"https://a.com.br/imoveis"}, unresolved)
+		if assert.Len(t, groups, 1) {
+			assert.Equal(t, repository.CityInfo{Name: "Muzambinho", State: "MG"}, groups[0].City)
+		}
+	}
+}
+
+func TestDomainAllowlist(t *testing.T) {
+	allowlist := NewDomainAllowlist(false)
+	allowlist.Add("https://www.imobiliariamuzambinho.com.br/imoveis", "portal.com.br")
+
+	assert.True(t, allowlist.Allows("https://imobiliariamuzambinho.com.br/imovel/1", nil))
+	assert.True(t, allowlist.Allows("http://www.portal.com.br/venda", nil))
+	assert.False(t, allowlist.Allows("https://ads.example.net/click?to=imobiliariamuzambinho.com.br", nil))
+	assert.False(t, allowlist.Allows("https://m.portal.com.br/venda", nil))
+
+	// Subdomínios liberados pela configuração do domínio; domínios configurados também são permitidos
+	snapshot := &config.RuntimeSnapshot{Domains: map[string]repository.DomainConfig{
+		"portal.com.br":   {Domain: "portal.com.br", AllowSubdomains: true},
+		"parceiro.com.br": {Domain: "parceiro.com.br"},
+	}}
+	assert.True(t, allowlist.Allows("https://m.portal.com.br/venda", snapshot))
+	assert.True(t, allowlist.Allows("https://parceiro.com.br/imoveis", snapshot))
+	assert.False(t, allowlist.Allows("https://blog.parceiro.com.br/", snapshot))
+	assert.Equal(t, map[string]int{"ads.example.net": 1, "m.portal.com.br": 1, "blog.parceiro.com.br": 1}, allowlist.Rejected())
+
+	// Política global de subdomínios
+	permissive := NewDomainAllowlist(true)
+	permissive.Add("https://portal.com.br/")
+	assert.True(t, permissive.Allows("https://m.portal.com.br/venda", nil))
+	assert.False(t, permissive.Allows("https://outroportal.com.br/", nil))
+
+	// Redirecionamentos para fora da allowlist não são seguidos
+	redirect := allowlist.redirectHandler(nil)
+	previous, _ := http.NewRequest(http.MethodGet, "https://portal.com.br/imovel/1", nil)
+	previous.Header.Set("User-Agent", "crawler")
+	next, _ := http.NewRequest(http.MethodGet, "https://portal.com.br/imovel/1-casa", nil)
+	assert.NoError(t, redirect(next, []*http.Request{previous}))
+	assert.Equal(t, "crawler", next.Header.Get("User-Agent"))
+	external, _ := http.NewRequest(http.MethodGet, "https://tracker.example.net/r", nil)
+	assert.Error(t, redirect(external, []*http.Request{previous}))
+
+	// No crawler recursivo, links que apenas mencionam o domínio da página são recusados
+	simpleCrawler := NewSimpleRecursiveCrawler(nil, nil)
+	parent := "https://imobiliariamuzambinho.com.br/imoveis"
+	assert.True(t, simpleCrawler.isValidLink("https://ads.example.net/?ref=imobiliariamuzambinho.com.br", parent))
+	simpleCrawler.SetDomainAllowlist(allowlist)
+	assert.False(t, simpleCrawler.isValidLink("https://ads.example.net/?ref=imobiliariamuzambinho.com.br", parent))
+	assert.True(t, simpleCrawler.isValidLink("https://imobiliariamuzambinho.com.br/imovel/2", parent))
+}
+
+func TestCrawlWindowGate(t *testing.T) {
+	runtime := NewRuntimeState()
+	runtime.Set(&config.RuntimeSnapshot{Domains: map[string]repository.DomainConfig{
+		"imobiliaria.com.br": {Domain: "imobiliaria.com.br", CrawlWindows: []string{"00:00-06:00"}, Timezone: "UTC"},
+	}})
+	runtime.windows.now = func() time.Time { return time.Date(2024, 6, 10, 14, 0, 0, 0, time.UTC) }
+
+	// Fora da janela as requisições ao domínio são adiadas; outros domínios seguem normalmente
+	assert.False(t, runtime.InCrawlWindow("https://www.imobiliaria.com.br/imoveis"))
+	assert.False(t, runtime.InCrawlWindow("https://imobiliaria.com.br/imovel/1"))
+	assert.True(t, runtime.InCrawlWindow("https://outra.com.br/imoveis"))
+
+	deferrals := runtime.windows.Take()
+	if assert.Len(t, deferrals, 1) {
+		assert.Equal(t, "imobiliaria.com.br", deferrals[0].Domain)
+		assert.Equal(t, 2, deferrals[0].Requests)
+		assert.True(t, deferrals[0].NextWindow.Equal(time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)))
+	}
+	assert.Empty(t, runtime.windows.Take())
+
+	// Dentro da janela as requisições passam
+	runtime.windows.now = func() time.Time { return time.Date(2024, 6, 11, 2, 0, 0, 0, time.UTC) }
+	assert.True(t, runtime.InCrawlWindow("https://imobiliaria.com.br/imovel/1"))
+}
+
+func TestPhotoProxy(t *testing.T) {
+	pngData := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /privado/\n")
+		case "/fotos/1.png", "/privado/2.png":
+			w.Write(pngData)
+		case "/fotos/protegida.png":
+			if r.Header.Get("Referer") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write(pngData)
+		case "/fotos/pagina.jpg":
+			fmt.Fprint(w, "<html><body>Imagem removida</body></html>")
+		case "/fotos/metadados.png":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	snapshot := &config.RuntimeSnapshot{Domains: map[string]repository.DomainConfig{
+		"imobiliaria.com.br": {Domain: "imobiliaria.com.br", PhotoReferer: true},
+		"bloqueada.com.br":   {Domain: "bloqueada.com.br", PhotoPolicy: repository.PhotoPolicyDeny},
+		"hotlink.com.br":     {Domain: "hotlink.com.br", PhotoPolicy: repository.PhotoPolicyRedirect},
+	}}
+	proxy := NewPhotoProxy(PhotoProxyOptions{UserAgent: "crawler-test", CacheDir: t.TempDir(), CacheTTL: time.Hour},
+		func() *config.RuntimeSnapshot { return snapshot })
+	ctx := context.Background()
+
+	// O proxy padrão recusa endereços internos, inclusive nomes que resolvem para eles
+	_, err := proxy.Fetch(ctx, "http://169.254.169.254/latest/meta-data/", "")
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	_, err = proxy.Fetch(ctx, "http://localhost:"+port+"/fotos/1.png", "")
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+	assert.Empty(t, requests)
+
+	// Host fictício servido pelo servidor de teste
+	proxy.SetTransport(testServerTransport(server))
+	photoHost := "http://fotos.imobiliaria.test"
+
+	// Redirecionamentos para endereços internos são recusados a cada salto
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/metadados.png", "")
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+	page := "https://imobiliaria.com.br/imovel/1"
+
+	// A foto é buscada uma vez e depois servida do cache
+	photo, err := proxy.Fetch(ctx, photoHost+"/fotos/1.png", page)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "image/png", photo.ContentType)
+		assert.False(t, photo.Cached)
+	}
+	photo, err = proxy.Fetch(ctx, photoHost+"/fotos/1.png", page)
+	if assert.NoError(t, err) {
+		assert.True(t, photo.Cached)
+	}
+	assert.Equal(t, 1, requests["/fotos/1.png"])
+
+	// Referer da página do anúncio para sites com proteção contra hotlink
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/protegida.png", "https://outra.com.br/imovel/1")
+	assert.Error(t, err)
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/protegida.png", page)
+	assert.NoError(t, err)
+
+	// robots.txt e política do domínio
+	_, err = proxy.Fetch(ctx, photoHost+"/privado/2.png", page)
+	assert.ErrorIs(t, err, ErrPhotoRobots)
+	assert.Equal(t, 1, requests["/robots.txt"])
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/1.png", "https://bloqueada.com.br/imovel/1")
+	assert.ErrorIs(t, err, ErrPhotoDenied)
+	photo, err = proxy.Fetch(ctx, photoHost+"/fotos/1.png", "https://hotlink.com.br/imovel/1")
+	if assert.NoError(t, err) {
+		assert.True(t, photo.Redirect)
+	}
+
+	// Fotos quebradas na origem são registradas e não são buscadas de novo
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/ausente.jpg", page)
+	assert.ErrorIs(t, err, ErrPhotoBroken)
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/ausente.jpg", page)
+	assert.ErrorIs(t, err, ErrPhotoBroken)
+	assert.Equal(t, 1, requests["/fotos/ausente.jpg"])
+	_, err = proxy.Fetch(ctx, photoHost+"/fotos/pagina.jpg", page)
+	assert.ErrorIs(t, err, ErrPhotoBroken)
+
+	broken := proxy.BrokenPhotos()
+	if assert.Len(t, broken, 2) {
+		assert.Equal(t, photoHost+"/fotos/pagina.jpg", broken[0].URL)
+		assert.Equal(t, http.StatusNotFound, broken[1].Status)
+	}
+}
+
+// testServerTransport conecta qualquer host ao servidor de teste
+func testServerTransport(server *httptest.Server) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	return transport
+}
+
+func TestAIValidationSampler(t *testing.T) {
+	cfg := &config.Config{
+		AIValidationSampleRate:    0.1,
+		AIValidationMinConfidence: 0.6,
+		AIValidationDomainRates:   []string{"www.confiavel.com.br=0"},
+	}
+	sampler, err := NewAIValidationSampler(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	draw := 0.05
+	sampler.random = func() float64 { return draw }
+
+	selector := func(url string) repository.Property {
+		return repository.Property{
+			URL: url, Valor: 450000, Endereco: "Rua A, 10", TipoImovel: "Casa",
+			Provenance: map[string]repository.FieldProvenance{
+				"valor":    {Extractor: repository.ExtractorDomainSelector},
+				"endereco": {Extractor: repository.ExtractorDomainSelector},
+			},
+		}
+	}
+
+	// Sem preço ou com campo-chave de nota baixa (regex) sempre valida, mesmo com taxa 0
+	missing := selector("https://confiavel.com.br/imovel/1")
+	missing.Valor = 0
+	assert.Equal(t, AIValidationLowConfidence, sampler.Decide(missing))
+	weak := selector("https://confiavel.com.br/imovel/2")
+	weak.Provenance["endereco"] = repository.FieldProvenance{Extractor: repository.ExtractorRegexFallback}
+	assert.Equal(t, AIValidationLowConfidence, sampler.Decide(weak))
+	assert.Empty(t, weak.FieldConfidence, "a decisão não altera o anúncio")
+	assert.Equal(t, AIValidationSkipped, sampler.Decide(selector("https://confiavel.com.br/imovel/3")))
+
+	// Nos demais domínios vale a taxa global
+	assert.Equal(t, AIValidationSampled, sampler.Decide(selector("https://portal.com.br/imovel/1")))
+	draw = 0.5
+	assert.Equal(t, AIValidationSkipped, sampler.Decide(selector("https://portal.com.br/imovel/2")))
+
+	sampler.Record(missing, AIValidationLowConfidence, []string{"valor"}, nil)
+	sampler.Record(weak, AIValidationLowConfidence, nil, nil)
+	sampler.Record(selector("https://portal.com.br/imovel/1"), AIValidationSampled, nil, fmt.Errorf("quota"))
+
+	stats := sampler.Stats()
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, AIValidationStats{
+			Domain: "confiavel.com.br", SampleRate: 0, Extracted: 3, Validated: 2, LowConfidence: 2, Skipped: 1,
+			LowConfidenceCorrectionRate: 0.5, CorrectedFields: map[string]int{"valor": 1},
+		}, stats[0])
+		assert.Equal(t, AIValidationStats{
+			Domain: "portal.com.br", SampleRate: 0.1, Extracted: 2, Validated: 1, Sampled: 1, Skipped: 1, Failed: 1,
+		}, stats[1])
+	}
+
+	_, err = NewAIValidationSampler(&config.Config{AIValidationSampleRate: 1.5})
+	assert.Error(t, err)
+	_, err = ParseAIValidationRates([]string{"portal.com.br=2"})
+	assert.Error(t, err)
+}
+
+func TestEnhancedExtractor_AICorrections(t *testing.T) {
+	extractor := NewEnhancedExtractor(nil, nil)
+	extractor.UpdateDomainSelectors("www.portal.com.br", map[string]*SelectorSet{
+		"price": {Primary: []string{".preco-antigo", ".preco"}, Secondary: []string{".valor"}},
+	})
+
+	html := `<html><body><span class="preco-antigo">R$ 990.000</span><span class="preco">R$ 450.000</span></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	assert.NoError(t, err)
+	root := doc.Find("html")
+	pageURL, _ := url.Parse("https://www.portal.com.br/imovel/1")
+	response := &colly.Response{Request: &colly.Request{URL: pageURL}}
+
+	extract := func() repository.Property {
+		element := colly.NewHTMLElementFromSelectionNode(response, root, root.Nodes[0], 0)
+		return extractor.ExtractPropertyData(element, pageURL.String())
+	}
+
+	// A proveniência guarda o seletor que produziu o preço
+	property := extract()
+	assert.Equal(t, 990000.0, property.Valor)
+	assert.Equal(t, repository.ExtractorDomainSelector, property.Provenance["valor"].Extractor)
+	assert.Equal(t, ".preco-antigo", property.Provenance["valor"].Selector)
+
+	// A IA corrige o preço: o seletor vai para o fim do nível secundário e o próximo assume
+	validated := property
+	validated.Valor = 450000
+	corrected := repository.RecordAIReview(property, &validated)
+	assert.Equal(t, []string{".preco-antigo"}, extractor.RecordAICorrections(property, corrected))
+	assert.Equal(t, &SelectorSet{Primary: []string{".preco"}, Secondary: []string{".valor", ".preco-antigo"}},
+		extractor.domainSelectors["www.portal.com.br"]["price"])
+	assert.Equal(t, 450000.0, extract().Valor)
+	assert.Equal(t, 1, extractor.GetExtractionStats()["www.portal.com.br_price_domain_selector_ai_corrected"])
+
+	// Campos de seletores genéricos ou regex são apenas contabilizados
+	assert.Empty(t, extractor.RecordAICorrections(repository.Property{
+		URL:        pageURL.String(),
+		Provenance: map[string]repository.FieldProvenance{"endereco": {Extractor: repository.ExtractorRegexFallback}},
+	}, []string{"endereco"}))
+}
+
+func TestRequestAuditLog_AuditedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ausente" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprint(w, "conteudo")
+	}))
+	defer server.Close()
+
+	// Sem registro configurado, as requisições passam direto
+	client := &http.Client{Transport: NewAuditedTransport(nil, "job-1")}
+	resp, err := client.Get(server.URL + "/")
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	audit := NewRequestAuditLog(repository.NewFileRequestAuditRepository(path))
+	SetRequestAuditLog(audit)
+	defer SetRequestAuditLog(nil)
+
+	for _, target := range []string{"/", "/ausente"} {
+		resp, err := client.Get(server.URL + target)
+		if assert.NoError(t, err) {
+			io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+	}
+	_, err = client.Get("http://127.0.0.1:1/")
+	assert.Error(t, err)
+	assert.NoError(t, audit.Close(context.Background()))
+
+	// Cada requisição é registrada com status, bytes lidos e job
+	entries, err := audit.Repository().FindRequestAudits(context.Background(), repository.RequestAuditFilter{})
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 3) {
+		return
+	}
+	statuses := map[int]int64{}
+	for _, entry := range entries {
+		assert.Equal(t, "job-1", entry.JobID)
+		assert.Equal(t, "GET", entry.Method)
+		statuses[entry.StatusCode] = entry.Bytes
+	}
+	assert.Equal(t, map[int]int64{http.StatusOK: 8, http.StatusNotFound: 8, 0: 0}, statuses)
+}
+
+func TestEngineBenchmark_ReplayAndScoring(t *testing.T) {
+	seedSet := MockBenchmarkSeedSet(3)
+	assert.Equal(t, []string{mockBenchmarkHost + "/imoveis"}, seedSet.Seeds)
+
+	// As páginas gravadas são servidas sem rede; as demais respondem 404
+	transport := NewReplayTransport(seedSet)
+	client := &http.Client{Transport: transport}
+	for _, path := range []string{"/imoveis/", "/imovel/casa-2-quartos-1#fotos", "/nao-existe"} {
+		resp, err := client.Get(mockBenchmarkHost + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		if path == "/nao-existe" {
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		} else {
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	}
+	assert.Equal(t, 2, transport.Served())
+
+	// Unidades do mesmo anúncio contam uma vez; URLs não rotuladas ficam fora da precisão
+	result := BenchmarkResult{Engine: BenchmarkTraditional}
+	scoreBenchmarkResult(&result, seedSet, []repository.Property{
+		{URL: mockBenchmarkHost + "/imovel/casa-2-quartos-1"},
+		{URL: mockBenchmarkHost + "/imovel/casa-2-quartos-1#unidade-2"},
+		{URL: mockBenchmarkHost + "/imovel/casa-3-quartos-2"},
+		{URL: mockBenchmarkHost + "/sobre"},
+		{URL: "https://outro-site.test/imovel/1"},
+	})
+	assert.Equal(t, 4, result.Properties)
+	assert.Equal(t, 3, result.Labeled)
+	assert.Equal(t, 2, result.Correct)
+	assert.InDelta(t, 2.0/3.0, result.Precision, 0.001)
+	assert.InDelta(t, 2.0/3.0, result.Recall, 0.001)
+
+	// Conjunto gravado em disco
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "home.html"), []byte("<html><body>ok</body></html>"), 0644))
+	recorded := &BenchmarkSeedSet{
+		Seeds:  []string{"https://imobiliaria.test/"},
+		Pages:  map[string]string{"https://imobiliaria.test/": "home.html"},
+		Labels: map[string]bool{"https://imobiliaria.test/": false},
+	}
+	assert.NoError(t, recorded.Save(dir))
+	loaded, err := LoadBenchmarkSeedSet(dir)
+	assert.NoError(t, err)
+	body, found := loaded.page("https://imobiliaria.test")
+	assert.True(t, found)
+	assert.Contains(t, string(body), "ok")
+
+	results := RunBenchmark(context.Background(), loaded, []string{"desconhecido"}, BenchmarkOptions{})
+	assert.Len(t, results, 1)
+	assert.Contains(t, results[0].Error, "unknown benchmark engine")
+}
+
+func TestCrawlerEngine_AppliesDomainSelectors(t *testing.T) {
+	seedSet := MockBenchmarkSeedSet(2)
+	repo := &benchmarkRepository{}
+	engine := NewCrawlerEngine(repo, nil)
+	engine.SetTransport(NewReplayTransport(seedSet))
+	engine.config.Delay = 0
+
+	var _ config.RuntimeConfigurable = engine
+	engine.ApplyRuntimeConfig(&config.RuntimeSnapshot{Domains: map[string]repository.DomainConfig{
+		"imobiliaria-benchmark.test": {Domain: "imobiliaria-benchmark.test", Selectors: map[string]string{"endereco": "h1.endereco"}},
+	}})
+
+	assert.NoError(t, engine.Start(context.Background(), seedSet.Seeds))
+	listings := 0
+	for _, property := range repo.properties {
+		if !strings.Contains(property.URL, "/imovel/") {
+			continue // página sem o seletor do domínio
+		}
+		listings++
+		assert.Equal(t, repository.ExtractorDomainSelector, property.Provenance["endereco"].Extractor, property.URL)
+		assert.Equal(t, "h1.endereco", property.Provenance["endereco"].Selector)
+	}
+	assert.Equal(t, 2, listings)
+}
+
 // Benchmark tests
 func BenchmarkDataExtractor_Creation(b *testing.B) {
 	b.ResetTimer()
@@ -166,6 +3301,148 @@ func BenchmarkDataExtractor_Creation(b *testing.B) {
 	}
 }
 
+func TestPatternImportValidation(t *testing.T) {
+	source := NewPatternLearner()
+	assert.NoError(t, source.LearnCatalogURLs([]string{
+		"https://imobiliaria.com.br/imoveis/venda?page=1",
+		"https://imobiliaria.com.br/imoveis/venda?page=2",
+	}))
+	data, err := source.ExportPatterns()
+	assert.NoError(t, err)
+
+	// Prévia não altera os padrões e lista o que seria adicionado
+	target := NewPatternLearner()
+	emptyChecksum := target.PatternsChecksum()
+	preview, err := target.PreviewImport(data)
+	assert.NoError(t, err)
+	assert.Equal(t, PatternSchemaVersion, preview.SchemaVersion)
+	assert.Equal(t, source.PatternsChecksum(), preview.Checksum)
+	assert.Equal(t, emptyChecksum, preview.CurrentChecksum)
+	assert.Len(t, preview.Diff["catalog"].Added, len(source.GetLearnedPatterns()["catalog"]))
+	assert.Empty(t, target.GetLearnedPatterns()["catalog"])
+
+	// Conteúdo alterado após a exportação não confere com o checksum
+	tampered := strings.Replace(string(data), `"confidence": 0.2`, `"confidence": 0.9`, 1)
+	assert.NotEqual(t, string(data), tampered)
+	assert.ErrorContains(t, target.ImportPatterns([]byte(tampered)), "checksum mismatch")
+
+	// Versão do formato desconhecida, confiança fora do intervalo e tipo trocado são recusados
+	assert.ErrorContains(t, target.ImportPatterns([]byte(`{"schema_version": 99, "checksum": "x"}`)), "unsupported pattern schema version")
+	assert.ErrorContains(t, target.ImportPatterns([]byte(`{"catalog": [{"pattern": "/imoveis", "type": "catalog", "confidence": 7}]}`)), "out of range")
+	assert.ErrorContains(t, target.ImportPatterns([]byte(`{"catalog": [{"pattern": "/imovel", "type": "property", "confidence": 0.5}]}`)), "property")
+	assert.Equal(t, emptyChecksum, target.PatternsChecksum())
+
+	// Arquivos anteriores ao versionamento (sem schema_version e checksum) continuam aceitos
+	assert.NoError(t, target.ImportPatterns([]byte(`{"catalog": [{"pattern": "/imoveis", "type": "catalog", "confidence": 0.5}], "property": null}`)))
+	assert.Len(t, target.GetLearnedPatterns()["catalog"], 1)
+
+	// O aprendiz de conteúdo substitui os padrões: a prévia mostra os removidos
+	content := NewContentBasedPatternLearner()
+	assert.NoError(t, content.ImportPatterns([]byte(`{"catalog": [{"id": "catalog_price_1", "type": "catalog", "confidence": 0.6}]}`)))
+	contentPreview, err := content.PreviewImport([]byte(`{"catalog": [{"id": "catalog_text_2", "type": "catalog", "confidence": 0.6}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"catalog_text_2"}, contentPreview.Diff["catalog"].Added)
+	assert.Equal(t, []string{"catalog_price_1"}, contentPreview.Diff["catalog"].Removed)
+}
+
+func TestPatternImportQueue(t *testing.T) {
+	ctx := context.Background()
+	source := NewPatternLearner()
+	assert.NoError(t, source.LearnPropertyURLs([]string{
+		"https://imobiliaria.com.br/imovel/venda/casa-centro-123",
+		"https://imobiliaria.com.br/imovel/venda/apartamento-jardim-456",
+	}))
+	data, err := source.ExportPatterns()
+	assert.NoError(t, err)
+
+	learner := NewPatternLearner()
+	queue := NewPatternImportQueue(learner, time.Hour, nil)
+	saved := 0
+	queue.OnApplied(func() { saved++ })
+
+	// Nada é aplicado antes da aprovação; reenviar o arquivo retorna a mesma importação
+	pending, err := queue.Stage(ctx, data)
+	assert.NoError(t, err)
+	again, err := queue.Stage(ctx, data)
+	assert.NoError(t, err)
+	assert.Equal(t, pending.ID, again.ID)
+	listed, err := queue.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, listed, 1)
+	assert.NotEmpty(t, pending.Preview.Diff["property"].Added)
+	assert.Empty(t, learner.GetLearnedPatterns()["property"])
+
+	_, err = queue.Approve(ctx, pending.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, source.PatternsChecksum(), learner.PatternsChecksum())
+	assert.Equal(t, 1, saved)
+	listed, err = queue.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, listed)
+
+	// O mesmo arquivo não é aplicado de novo sobre os mesmos padrões
+	_, err = queue.Stage(ctx, data)
+	assert.ErrorIs(t, err, ErrPatternImportReplayed)
+	_, err = queue.Approve(ctx, pending.ID)
+	assert.ErrorIs(t, err, ErrPatternImportNotFound)
+
+	// Depois que os padrões mudam, o arquivo antigo volta a ser aceito (rollback)
+	changed := NewPatternLearner()
+	assert.NoError(t, changed.ImportPatterns(data))
+	for _, pattern := range changed.GetLearnedPatterns()["property"] {
+		pattern.Confidence = pattern.Confidence / 2
+	}
+	changedData, err := changed.ExportPatterns()
+	assert.NoError(t, err)
+	pending, err = queue.Stage(ctx, changedData)
+	assert.NoError(t, err)
+	_, err = queue.Approve(ctx, pending.ID)
+	assert.NoError(t, err)
+	pending, err = queue.Stage(ctx, data)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pending.Preview.Diff["property"].Changed)
+	_, err = queue.Approve(ctx, pending.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, source.PatternsChecksum(), learner.PatternsChecksum())
+
+	// Padrões alterados depois da prévia invalidam a aprovação
+	assert.NoError(t, source.LearnCatalogURLs([]string{
+		"https://imobiliaria.com.br/imoveis/aluguel?page=1",
+		"https://imobiliaria.com.br/imoveis/aluguel?page=2",
+	}))
+	data, err = source.ExportPatterns()
+	assert.NoError(t, err)
+	pending, err = queue.Stage(ctx, data)
+	assert.NoError(t, err)
+	assert.NoError(t, learner.LearnCatalogURLs([]string{
+		"https://outra.com.br/busca/casas/1",
+		"https://outra.com.br/busca/casas/2",
+	}))
+	_, err = queue.Approve(ctx, pending.ID)
+	assert.ErrorIs(t, err, ErrPatternImportStale)
+
+	// Rejeitada, a importação sai da fila
+	pending, err = queue.Stage(ctx, data)
+	assert.NoError(t, err)
+	assert.NoError(t, queue.Reject(ctx, pending.ID))
+	assert.ErrorIs(t, queue.Reject(ctx, pending.ID), ErrPatternImportNotFound)
+
+	// Importações vencidas não podem ser aprovadas
+	store := newMemoryPatternImportStore()
+	expiring := NewPatternImportQueue(learner, time.Nanosecond, store)
+	pending, err = expiring.Stage(ctx, data)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = expiring.Approve(ctx, pending.ID)
+	assert.ErrorIs(t, err, ErrPatternImportExpired)
+
+	// A fila é lida do store: outra instância (outra réplica, ou após reinício) aprova a importação
+	pending, err = NewPatternImportQueue(learner, time.Hour, store).Stage(ctx, data)
+	assert.NoError(t, err)
+	_, err = NewPatternImportQueue(learner, time.Hour, store).Approve(ctx, pending.ID)
+	assert.NoError(t, err)
+}
+
 func BenchmarkPropertyValidator_ValidateProperty(b *testing.B) {
 	validator := NewPropertyValidator()
 	property := repository.Property{
@@ -194,3 +3471,302 @@ func BenchmarkPropertyValidator_EnhanceProperty(b *testing.B) {
 		validator.EnhanceProperty(&property)
 	}
 }
+
+func TestClassifierExperiment(t *testing.T) {
+	cfg := &config.Config{
+		ClassifierExperimentName:            "limiar-baixo",
+		ClassifierExperimentSampleRate:      0.5,
+		ClassifierExperimentMinConfidence:   0.2,
+		ClassifierExperimentMinRequirements: 1,
+	}
+	control := NewPrecisePropertyClassifier()
+	experiment, err := NewClassifierExperiment(cfg, control)
+	if !assert.NoError(t, err) {
+		return
+	}
+	draw := 0.1
+	experiment.random = func() float64 { return draw }
+
+	page := func(html string) *goquery.Document {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		assert.NoError(t, err)
+		return doc
+	}
+	complete := page(`<html><head><title>Casa à venda no Centro - Código 123</title></head><body>
+		<h1>Casa à venda no Centro</h1><div class="preco">Preço: 450.000,00 reais</div>
+		<ul><li>3 quartos</li><li>2 banheiros</li><li>120 m²</li><li>2 vagas de garagem</li></ul>
+		<p>Código: 123</p><p>Descrição do imóvel: casa ampla com quintal.</p></body></html>`)
+	partial := page(`<html><head><title>Casa no Centro</title></head><body>
+		<h1>Casa no Centro</h1><div class="preco">Preço: 450.000,00 reais</div><p>3 quartos</p></body></html>`)
+
+	// A decisão aplicada é sempre a da configuração atual
+	assert.True(t, experiment.Classify(complete, "https://imobiliaria.com.br/imovel/1").IsIndividualProperty)
+	assert.False(t, experiment.Classify(partial, "https://imobiliaria.com.br/imovel/2").IsIndividualProperty)
+	draw = 0.9
+	assert.True(t, experiment.Classify(complete, "https://imobiliaria.com.br/imovel/3").IsIndividualProperty)
+
+	report := experiment.Report()
+	assert.Equal(t, 3, report.Classified)
+	assert.Equal(t, 2, report.Sampled)
+	assert.Equal(t, 1, report.Divergences)
+	assert.Equal(t, 0.5, report.DivergenceRate)
+	assert.Equal(t, map[string]int{repository.ExperimentOutcomePending: 1}, report.Outcomes[repository.ExperimentBothAccept])
+	assert.Equal(t, map[string]int{repository.ExperimentOutcomePending: 1}, report.Outcomes[repository.ExperimentCandidateOnly])
+	if assert.Len(t, report.Samples, 1) {
+		assert.Equal(t, repository.ExperimentCandidateOnly, report.Samples[0].Decision)
+		assert.Equal(t, "imobiliaria.com.br", report.Samples[0].Domain)
+		assert.Equal(t, repository.ExperimentOutcomePending, report.Samples[0].Outcome)
+	}
+
+	// Páginas aceitas só pela candidata aguardam a extração de teste
+	assert.True(t, experiment.NeedsDryRun("https://imobiliaria.com.br/imovel/2"))
+	assert.False(t, experiment.NeedsDryRun("https://imobiliaria.com.br/imovel/1"))
+
+	// Resultados de páginas fora da amostra são ignorados
+	experiment.RecordOutcome("https://imobiliaria.com.br/imovel/1", repository.ExperimentOutcomeSaved)
+	experiment.RecordOutcome("https://imobiliaria.com.br/imovel/2", repository.ExperimentOutcomeValid)
+	experiment.RecordOutcome("https://imobiliaria.com.br/imovel/3", repository.ExperimentOutcomeSaved)
+	report = experiment.Report()
+	assert.Equal(t, map[string]int{repository.ExperimentOutcomeSaved: 1}, report.Outcomes[repository.ExperimentBothAccept])
+	assert.Equal(t, map[string]int{repository.ExperimentOutcomeValid: 1}, report.Outcomes[repository.ExperimentCandidateOnly])
+	assert.Equal(t, repository.ExperimentOutcomeValid, report.Samples[0].Outcome)
+	assert.False(t, experiment.NeedsDryRun("https://imobiliaria.com.br/imovel/2"))
+
+	// O controle é o classificador do crawler
+	assert.Same(t, control, experiment.control)
+
+	var disabled *ClassifierExperiment
+	disabled.RecordOutcome("https://imobiliaria.com.br/imovel/1", repository.ExperimentOutcomeSaved)
+	assert.False(t, disabled.NeedsDryRun("https://imobiliaria.com.br/imovel/1"))
+	assert.Nil(t, disabled.Report())
+
+	_, err = NewClassifierExperiment(&config.Config{ClassifierExperimentSampleRate: 0.1, ClassifierExperimentMinConfidence: 0.6, ClassifierExperimentMinRequirements: 3}, nil)
+	assert.Error(t, err, "candidata igual à configuração atual")
+	_, err = NewClassifierExperiment(&config.Config{ClassifierExperimentSampleRate: 1.5}, nil)
+	assert.Error(t, err)
+}
+
+// archivedListingRepo devolve o anúncio salvo da URL e guarda o gravado pelo fallback do arquivo
+type archivedListingRepo struct {
+	MockCrawlerPropertyRepository
+	stored *repository.Property
+	saved  []repository.Property
+}
+
+func (r *archivedListingRepo) FindByURL(ctx context.Context, url string) (*repository.Property, error) {
+	if r.stored == nil {
+		return nil, nil
+	}
+	stored := *r.stored
+	return &stored, nil
+}
+
+func (r *archivedListingRepo) SaveArchivedListing(ctx context.Context, property repository.Property) error {
+	r.saved = append(r.saved, property)
+	return nil
+}
+
+func TestWaybackFallback(t *testing.T) {
+	listingURL := "https://imobiliaria.com.br/imovel/casa-centro"
+	page := `<html><head><title>Casa à venda no Centro</title></head><body>
+		<h1>Casa à venda no Centro</h1>
+		<p>Rua das Flores, 120 - Centro - Muzambinho/MG</p>
+		<p>Valor: R$ 450.000,00</p>
+		<p>3 quartos, 2 banheiros, área de 180 m²</p>
+		<p>Casa ampla com quintal, garagem para dois carros e cozinha planejada, próxima ao comércio.</p>
+	</body></html>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/wayback/available":
+			target := r.URL.Query().Get("url")
+			timestamp := "20260915080000"
+			if strings.Contains(target, "antigo") {
+				timestamp = "20250101000000"
+			}
+			if strings.Contains(target, "nunca") {
+				w.Write([]byte(`{"archived_snapshots":{}}`))
+				return
+			}
+			fmt.Fprintf(w, `{"archived_snapshots":{"closest":{"available":true,"status":"200","timestamp":%q,"url":"http://%s/web/%s/%s"}}}`,
+				timestamp, r.Host, timestamp, target)
+		case strings.HasPrefix(r.URL.Path, "/web/20260915080000id_/"):
+			w.Write([]byte(page))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewWaybackFetcher(&config.Config{WaybackAPIURL: server.URL + "/wayback/available", WaybackMaxAge: 90 * 24 * time.Hour})
+	fetcher.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+
+	// A cópia é baixada sem a barra do arquivo (id_); cópias antigas ou inexistentes são ignoradas
+	archived, err := fetcher.FetchArchived(context.Background(), listingURL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2026, 9, 15, 8, 0, 0, 0, time.UTC), archived.CapturedAt)
+		assert.Contains(t, archived.URL, "/web/20260915080000/"+listingURL)
+		assert.Contains(t, string(archived.Body), "Rua das Flores")
+	}
+	_, err = fetcher.FetchArchived(context.Background(), "https://imobiliaria.com.br/imovel/antigo")
+	assert.ErrorIs(t, err, ErrNoArchive)
+	_, err = fetcher.FetchArchived(context.Background(), "https://imobiliaria.com.br/imovel/nunca")
+	assert.ErrorIs(t, err, ErrNoArchive)
+
+	// O anúncio salvo que passou a retornar 404 é completado com a cópia e marcado como removido;
+	// campos já preenchidos e corrigidos manualmente são mantidos
+	repo := &archivedListingRepo{stored: &repository.Property{
+		ID: "abc", URL: listingURL, Valor: 500000, CorrectedFields: []string{"banheiros"},
+	}}
+	simpleCrawler := NewSimpleRecursiveCrawler(repo, nil)
+	parsed, _ := url.Parse(listingURL)
+	notFound := &colly.Response{Request: &colly.Request{URL: parsed}, StatusCode: http.StatusNotFound}
+
+	simpleCrawler.completeFromArchive(context.Background(), notFound)
+	assert.Empty(t, repo.saved, "sem o fallback ativo nada é gravado")
+
+	simpleCrawler.SetArchiveFetcher(fetcher)
+	simpleCrawler.completeFromArchive(context.Background(), notFound)
+	if assert.Len(t, repo.saved, 1) {
+		saved := repo.saved[0]
+		assert.Equal(t, repository.ListingStateDelistedArchived, saved.ListingState)
+		assert.Equal(t, archived.URL, saved.ArchiveURL)
+		assert.NotNil(t, saved.DelistedAt)
+		assert.Equal(t, float64(500000), saved.Valor)
+		assert.Equal(t, 3, saved.Quartos)
+		assert.Equal(t, 0, saved.Banheiros)
+		assert.Equal(t, repository.ExtractorArchive, saved.Provenance["quartos"].Extractor)
+	}
+
+	// Anúncios já marcados e URLs que não são anúncios salvos não consultam o arquivo de novo
+	repo.stored = &repo.saved[0]
+	simpleCrawler.completeFromArchive(context.Background(), notFound)
+	repo.stored = nil
+	simpleCrawler.completeFromArchive(context.Background(), notFound)
+	assert.Len(t, repo.saved, 1)
+}
+
+// staticPageRenderer devolve sempre o mesmo HTML renderizado
+type staticPageRenderer struct {
+	html  string
+	calls int
+}
+
+func (r *staticPageRenderer) Render(ctx context.Context, pageURL string) ([]byte, error) {
+	r.calls++
+	return []byte(r.html), nil
+}
+
+func TestFetchMiddlewareChain(t *testing.T) {
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: Googlebot\nDisallow: /\n\nUser-agent: *\nDisallow: /privado\nAllow: /privado/publico\n")
+		case "/spa":
+			fmt.Fprint(w, `<html><body><div id="app"></div><script src="/app.js"></script></body></html>`)
+		default:
+			fmt.Fprint(w, "<html><body><p>"+strings.Repeat("Casa com 3 quartos no centro. ", 20)+"</p></body></html>")
+		}
+	}))
+	defer server.Close()
+
+	var order []string
+	recorder := NewFetchMiddlewareFunc("recorder", func(next http.RoundTripper) http.RoundTripper {
+		return FetchFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, req.URL.Path)
+			return next.RoundTrip(req)
+		})
+	})
+	renderer := &staticPageRenderer{html: "<html><body><h1>Casa renderizada</h1></body></html>"}
+	cache := NewCacheFetchMiddleware(time.Minute, 10)
+	chain := NewFetchChain(recorder, NewRobotsFetchMiddleware(time.Hour), cache, NewHeadlessFallbackFetchMiddleware(renderer))
+	assert.Error(t, chain.Use(NewCacheFetchMiddleware(time.Minute, 10)))
+	assert.Equal(t, []string{"recorder", "robots", "cache", "headless"}, chain.Names())
+
+	client := &http.Client{Transport: chain.Then(nil)}
+	get := func(path string) (*http.Response, string, error) {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body), nil
+	}
+
+	// A segunda leitura da mesma página sai do cache, sem chegar ao site
+	resp, body, err := get("/imovel/1")
+	assert.NoError(t, err)
+	assert.Contains(t, body, "Casa com 3 quartos")
+	resp, _, err = get("/imovel/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "HIT", resp.Header.Get("X-Fetch-Cache"))
+	assert.Equal(t, 1, hits["/imovel/1"])
+	hitCount, _ := cache.Stats()
+	assert.Equal(t, int64(1), hitCount)
+
+	// robots.txt do grupo "*": bloqueado, exceto pelo Allow mais longo; lido uma vez por host
+	_, _, err = get("/privado/casa")
+	assert.ErrorIs(t, err, ErrDisallowedByRobots)
+	assert.Zero(t, hits["/privado/casa"])
+	_, _, err = get("/privado/publico/casa")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, hits["/robots.txt"])
+
+	// Página montada por JavaScript é trocada pela versão renderizada
+	resp, body, err = get("/spa")
+	assert.NoError(t, err)
+	assert.Equal(t, "headless", resp.Header.Get("X-Fetch-Rendered"))
+	assert.Contains(t, body, "Casa renderizada")
+	assert.Equal(t, 1, renderer.calls)
+
+	// O primeiro middleware vê todas as requisições dos collectors, inclusive as servidas do cache
+	assert.Equal(t, []string{"/imovel/1", "/imovel/1", "/privado/casa", "/privado/publico/casa", "/spa"}, order)
+
+	// Collectors dos engines recebem a cadeia configurada sem mudar o próprio setup
+	SetFetchChain(NewFetchChain(recorder))
+	defer SetFetchChain(nil)
+	order = nil
+	collector := colly.NewCollector()
+	fetchCollector(collector, nil, "", PageLimits{})
+	assert.NoError(t, collector.Visit(server.URL+"/colly"))
+	assert.Equal(t, []string{"/colly"}, order)
+}
+
+func TestFetchMiddlewareChain_ConfigAndRateLimit(t *testing.T) {
+	chain, err := NewFetchChainFromConfig(&config.Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, chain)
+
+	chain, err = NewFetchChainFromConfig(&config.Config{
+		FetchMiddlewares:       []string{"logging", " Cache ", "ratelimit", "proxy"},
+		FetchProxyURLs:         []string{"http://proxy.local:3128"},
+		FetchCacheTTL:          time.Minute,
+		FetchRateLimitInterval: time.Second,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"logging", "cache", "ratelimit", "proxy"}, chain.Names())
+	assert.True(t, chain.Has(FetchMiddlewareProxy))
+
+	// O proxy precisa ser o último; o headless exige o renderizador; nomes desconhecidos falham
+	for _, middlewares := range [][]string{{"proxy", "logging"}, {"headless"}, {"compressao"}} {
+		_, err := NewFetchChainFromConfig(&config.Config{FetchMiddlewares: middlewares, FetchProxyURLs: []string{"http://proxy.local:3128"}})
+		assert.Error(t, err, middlewares)
+	}
+
+	// Requisições ao mesmo host respeitam o intervalo mínimo
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: NewFetchChain(NewRateLimitFetchMiddleware(50 * time.Millisecond)).Then(nil)}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL + "/" + strconv.Itoa(i))
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
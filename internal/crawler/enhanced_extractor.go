@@ -1,6 +1,7 @@
 package crawler
 
 import (
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
 	"github.com/gocolly/colly"
 )
 
@@ -21,6 +23,17 @@ type SelectorSet struct {
 // DomainSelectorMap mapeia domínios para seus seletores específicos
 type DomainSelectorMap map[string]map[string]*SelectorSet
 
+// enhancedFieldDataTypes relaciona os campos do anúncio aos tipos de dado dos seletores
+var enhancedFieldDataTypes = map[string]string{
+	"valor":       "price",
+	"endereco":    "address",
+	"descricao":   "description",
+	"quartos":     "rooms",
+	"banheiros":   "bathrooms",
+	"area_total":  "area",
+	"tipo_imovel": "type",
+}
+
 // EnhancedExtractor extrator melhorado que usa padrões aprendidos das páginas de referência
 type EnhancedExtractor struct {
 	domainSelectors  DomainSelectorMap
@@ -233,32 +246,42 @@ func (ee *EnhancedExtractor) ExtractPropertyData(e *colly.HTMLElement, url strin
 		URL: url,
 	}
 
-	// Extrai cada tipo de dado usando seletores apropriados
-	property.ValorTexto = ee.extractWithSelectors(e, domain, "price")
-	property.Valor = extractValue(property.ValorTexto)
+	// Extrai cada tipo de dado usando seletores apropriados, guardando o seletor de cada campo
+	// na proveniência (usada para rebaixar seletores corrigidos pela IA)
+	origins := make(map[string]repository.FieldProvenance)
+	extract := func(field, dataType string) string {
+		content, extractor, selector := ee.extractWithSelectors(e, domain, dataType)
+		if content != "" && field != "" {
+			origins[field] = repository.FieldProvenance{Extractor: extractor, Selector: selector}
+		}
+		return content
+	}
+
+	property.ValorTexto = extract("valor", "price")
+	property.Valor = utils.ParseAmount(property.ValorTexto)
 
-	property.Endereco = ee.extractWithSelectors(e, domain, "address")
-	property.Descricao = ee.extractWithSelectors(e, domain, "description")
+	property.Endereco = extract("endereco", "address")
+	property.Descricao = extract("descricao", "description")
 
 	// Extrai informações numéricas
-	roomsText := ee.extractWithSelectors(e, domain, "rooms")
+	roomsText := extract("quartos", "rooms")
 	property.Quartos = ee.extractNumericValue(roomsText, "quartos")
 
-	bathroomsText := ee.extractWithSelectors(e, domain, "bathrooms")
+	bathroomsText := extract("banheiros", "bathrooms")
 	property.Banheiros = ee.extractNumericValue(bathroomsText, "banheiros")
 
-	areaText := ee.extractWithSelectors(e, domain, "area")
+	areaText := extract("area_total", "area")
 	property.AreaTotal = ee.extractAreaValue(areaText)
 
 	// Extrai tipo do imóvel
-	typeText := ee.extractWithSelectors(e, domain, "type")
+	typeText := extract("tipo_imovel", "type")
 	if typeText == "" {
 		typeText = property.Descricao + " " + property.Endereco
 	}
 	property.TipoImovel = extractPropertyType(typeText)
 
 	// Extrai características
-	featuresText := ee.extractWithSelectors(e, domain, "features")
+	featuresText := extract("", "features")
 	property.Caracteristicas = ee.extractFeaturesList(e, featuresText)
 
 	// Extrai informações de localização
@@ -270,6 +293,18 @@ func (ee *EnhancedExtractor) ExtractPropertyData(e *colly.HTMLElement, url strin
 	// Limpa e valida dados extraídos
 	ee.cleanAndValidateProperty(&property)
 
+	// Proveniência apenas dos campos que continuam preenchidos após a limpeza
+	values := map[string]bool{
+		"valor": property.Valor > 0, "endereco": property.Endereco != "", "descricao": property.Descricao != "",
+		"quartos": property.Quartos > 0, "banheiros": property.Banheiros > 0, "area_total": property.AreaTotal > 0,
+		"tipo_imovel": property.TipoImovel != "" && property.TipoImovel != "Outro",
+	}
+	for field, origin := range origins {
+		if values[field] {
+			property.SetProvenance(field, origin.Extractor, origin.Selector)
+		}
+	}
+
 	ee.logger.WithFields(map[string]interface{}{
 		"url":             url,
 		"has_price":       property.Valor > 0,
@@ -281,54 +316,56 @@ func (ee *EnhancedExtractor) ExtractPropertyData(e *colly.HTMLElement, url strin
 	return property
 }
 
-// extractWithSelectors extrai conteúdo usando hierarquia de seletores
-func (ee *EnhancedExtractor) extractWithSelectors(e *colly.HTMLElement, domain, dataType string) string {
+// extractWithSelectors extrai conteúdo usando hierarquia de seletores, retornando também a etapa
+// de extração (repository.Extractor*) e o seletor que produziu o conteúdo
+func (ee *EnhancedExtractor) extractWithSelectors(e *colly.HTMLElement, domain, dataType string) (string, string, string) {
 	// 1. Tenta seletores específicos do domínio primeiro
-	if domainSelectors, exists := ee.domainSelectors[domain]; exists {
-		if selectorSet, exists := domainSelectors[dataType]; exists {
-			if content := ee.trySelectorsInOrder(e, selectorSet, dataType); content != "" {
-				ee.recordSelectorSuccess(domain, dataType, "domain_specific")
-				return content
-			}
+	ee.mutex.RLock()
+	selectorSet := ee.domainSelectors[domain][dataType]
+	ee.mutex.RUnlock()
+	if selectorSet != nil {
+		if content, selector := ee.trySelectorsInOrder(e, selectorSet, dataType); content != "" {
+			ee.recordSelectorSuccess(domain, dataType, "domain_specific")
+			return content, repository.ExtractorDomainSelector, selector
 		}
 	}
 
 	// 2. Usa seletores genéricos
 	if selectorSet, exists := ee.genericSelectors[dataType]; exists {
-		if content := ee.trySelectorsInOrder(e, selectorSet, dataType); content != "" {
+		if content, selector := ee.trySelectorsInOrder(e, selectorSet, dataType); content != "" {
 			ee.recordSelectorSuccess("generic", dataType, "generic")
-			return content
+			return content, repository.ExtractorGenericSelector, selector
 		}
 	}
 
 	// 3. Fallback para extração por regex no texto completo
-	return ee.extractByRegex(e.Text, dataType)
+	return ee.extractByRegex(e.Text, dataType), repository.ExtractorRegexFallback, ""
 }
 
-// trySelectorsInOrder tenta seletores em ordem de prioridade
-func (ee *EnhancedExtractor) trySelectorsInOrder(e *colly.HTMLElement, selectorSet *SelectorSet, dataType string) string {
+// trySelectorsInOrder tenta seletores em ordem de prioridade, retornando o conteúdo e o seletor
+func (ee *EnhancedExtractor) trySelectorsInOrder(e *colly.HTMLElement, selectorSet *SelectorSet, dataType string) (string, string) {
 	// Tenta seletores primários
 	for _, selector := range selectorSet.Primary {
 		if content := ee.extractAndValidate(e, selector, dataType); content != "" {
-			return content
+			return content, selector
 		}
 	}
 
 	// Tenta seletores secundários
 	for _, selector := range selectorSet.Secondary {
 		if content := ee.extractAndValidate(e, selector, dataType); content != "" {
-			return content
+			return content, selector
 		}
 	}
 
 	// Tenta seletores de fallback
 	for _, selector := range selectorSet.Fallback {
 		if content := ee.extractAndValidate(e, selector, dataType); content != "" {
-			return content
+			return content, selector
 		}
 	}
 
-	return ""
+	return "", ""
 }
 
 // extractAndValidate extrai conteúdo e valida se é apropriado para o tipo de dado
@@ -444,18 +481,10 @@ func (ee *EnhancedExtractor) extractAreaValue(text string) float64 {
 		return 0
 	}
 
-	re := regexp.MustCompile(`(\d+(?:[,.]\d+)?)\s*m[²2]`)
-	match := re.FindStringSubmatch(text)
-	if len(match) > 1 {
-		areaStr := strings.Replace(match[1], ",", ".", 1)
-		if area, err := strconv.ParseFloat(areaStr, 64); err == nil {
-			// Valida se a área é razoável (entre 10 e 10000 m²)
-			if area >= 10 && area <= 10000 {
-				return area
-			}
-		}
+	// Valida se a área é razoável (entre 10 e 10000 m²)
+	if area := utils.ParseArea(text); area >= 10 && area <= 10000 {
+		return area
 	}
-
 	return 0
 }
 
@@ -610,6 +639,82 @@ func (ee *EnhancedExtractor) UpdateDomainSelectors(domain string, selectors map[
 	}).Info("Domain selectors updated")
 }
 
+// RecordAICorrections rebaixa, na ordem do SelectorSet do domínio do anúncio, os seletores
+// específicos do domínio que produziram campos corrigidos pela validação da IA (property é o
+// anúncio antes da validação, com a proveniência da extração). Retorna os seletores rebaixados.
+func (ee *EnhancedExtractor) RecordAICorrections(property repository.Property, corrected []string) []string {
+	parsed, err := url.Parse(property.URL)
+	if err != nil || parsed.Host == "" {
+		return nil
+	}
+	domain := parsed.Host
+
+	var demoted []string
+	for _, field := range corrected {
+		origin, exists := property.Provenance[field]
+		dataType := enhancedFieldDataTypes[field]
+		if !exists || dataType == "" {
+			continue
+		}
+		ee.recordSelectorCorrection(domain, dataType, origin.Extractor)
+		if origin.Extractor != repository.ExtractorDomainSelector || origin.Selector == "" {
+			continue
+		}
+		if ee.DemoteSelector(domain, dataType, origin.Selector) {
+			demoted = append(demoted, origin.Selector)
+			ee.logger.WithFields(map[string]interface{}{
+				"domain":    domain,
+				"data_type": dataType,
+				"field":     field,
+				"selector":  origin.Selector,
+			}).Info("Selector demoted after AI correction")
+		}
+	}
+	return demoted
+}
+
+// DemoteSelector move o seletor para o fim do nível seguinte do SelectorSet do domínio (principal
+// para secundário, secundário para fallback; no fallback vai para o fim). O SelectorSet é
+// substituído por uma cópia, sem afetar extrações em andamento.
+func (ee *EnhancedExtractor) DemoteSelector(domain, dataType, selector string) bool {
+	ee.mutex.Lock()
+	defer ee.mutex.Unlock()
+
+	current := ee.domainSelectors[domain][dataType]
+	if current == nil {
+		return false
+	}
+
+	tiers := [][]string{
+		append([]string(nil), current.Primary...),
+		append([]string(nil), current.Secondary...),
+		append([]string(nil), current.Fallback...),
+	}
+	for tier, selectors := range tiers {
+		for i, candidate := range selectors {
+			if candidate != selector {
+				continue
+			}
+			tiers[tier] = append(selectors[:i], selectors[i+1:]...)
+			target := min(tier+1, len(tiers)-1)
+			tiers[target] = append(tiers[target], selector)
+
+			ee.domainSelectors[domain][dataType] = &SelectorSet{Primary: tiers[0], Secondary: tiers[1], Fallback: tiers[2]}
+			return true
+		}
+	}
+	return false
+}
+
+// recordSelectorCorrection registra nas estatísticas um campo corrigido pela IA
+func (ee *EnhancedExtractor) recordSelectorCorrection(domain, dataType, extractor string) {
+	ee.mutex.Lock()
+	defer ee.mutex.Unlock()
+
+	key := domain + "_" + dataType + "_" + extractor + "_ai_corrected"
+	ee.extractionStats[key]++
+}
+
 // Função auxiliar min
 func min(a, b int) int {
 	if a < b {
@@ -0,0 +1,212 @@
+package crawler
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Taxonomia de erros compartilhada pelos engines de crawling
+const (
+	ErrorTypeFetch      = "fetch_error"      // falha HTTP/rede ao baixar a página
+	ErrorTypeParse      = "parse_error"      // página baixada, mas sem dados extraíveis
+	ErrorTypeValidation = "validation_error" // dados extraídos reprovados pelo validador
+	ErrorTypeStorage    = "storage_error"    // falha ao gravar no banco
+	ErrorTypeAI         = "ai_error"         // falha no processamento pela IA
+	ErrorTypePanic      = "panic_error"      // panic recuperado em um callback do colly
+	ErrorTypeSlowPage   = "slow_page"        // página abortada por demora, tamanho ou timeout (ver page_limits.go)
+)
+
+// ErrorTypes lista os tipos válidos (ordem usada nos relatórios)
+var ErrorTypes = []string{ErrorTypeFetch, ErrorTypeParse, ErrorTypeValidation, ErrorTypeStorage, ErrorTypeAI, ErrorTypePanic, ErrorTypeSlowPage}
+
+// highSeverityErrorTypes são também encaminhados ao ErrorReporter externo (ex: Sentry)
+var highSeverityErrorTypes = map[string]bool{
+	ErrorTypeStorage: true,
+}
+
+// maxErrorSamplesPerType limita as amostras recentes guardadas por tipo
+const maxErrorSamplesPerType = 50
+
+// CrawlError é uma ocorrência de erro registrada durante o crawling
+type CrawlError struct {
+	Type       string    `json:"type"`
+	Domain     string    `json:"domain"`
+	URL        string    `json:"url"`
+	Message    string    `json:"message"`
+	StatusCode int       `json:"status_code,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ErrorReport resume os erros agregados por tipo e domínio, com amostras recentes
+type ErrorReport struct {
+	Total    int                       `json:"total"`
+	ByType   map[string]int            `json:"by_type"`
+	ByDomain map[string]map[string]int `json:"by_domain"` // domínio -> tipo -> quantidade
+	Samples  []CrawlError              `json:"samples"`
+}
+
+// ErrorTracker agrega os erros de crawling por tipo e domínio
+type ErrorTracker struct {
+	mutex   sync.RWMutex
+	counts  map[string]map[string]int // tipo -> domínio -> quantidade
+	samples map[string][]CrawlError   // tipo -> amostras mais recentes
+}
+
+// NewErrorTracker cria um agregador de erros vazio
+func NewErrorTracker() *ErrorTracker {
+	return &ErrorTracker{
+		counts:  make(map[string]map[string]int),
+		samples: make(map[string][]CrawlError),
+	}
+}
+
+// IsValidErrorType verifica se o tipo pertence à taxonomia
+func IsValidErrorType(errorType string) bool {
+	for _, valid := range ErrorTypes {
+		if errorType == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyError deduz o tipo de um erro vindo de camadas inferiores (fallback: fetch_error)
+func ClassifyError(err error) string {
+	switch {
+	case repository.IsStorageUnavailable(err):
+		return ErrorTypeStorage
+	case errors.Is(err, ai.ErrAIUnavailable):
+		return ErrorTypeAI
+	}
+	return ErrorTypeFetch
+}
+
+// Record registra um erro do tipo informado para a URL
+func (et *ErrorTracker) Record(errorType, rawURL, message string, statusCode int) {
+	emitEvent(EventError, rawURL, map[string]interface{}{
+		"error_type":  errorType,
+		"message":     message,
+		"status_code": statusCode,
+	})
+	if et == nil {
+		return
+	}
+
+	domain := ""
+	if parsed, err := url.Parse(rawURL); err == nil {
+		domain = repository.NormalizeDomain(parsed.Host)
+	}
+
+	entry := CrawlError{
+		Type:       errorType,
+		Domain:     domain,
+		URL:        rawURL,
+		Message:    message,
+		StatusCode: statusCode,
+		OccurredAt: time.Now(),
+	}
+
+	et.mutex.Lock()
+	defer et.mutex.Unlock()
+
+	if et.counts[errorType] == nil {
+		et.counts[errorType] = make(map[string]int)
+	}
+	et.counts[errorType][domain]++
+
+	samples := append(et.samples[errorType], entry)
+	if len(samples) > maxErrorSamplesPerType {
+		samples = samples[len(samples)-maxErrorSamplesPerType:]
+	}
+	et.samples[errorType] = samples
+}
+
+// crawlFailureTags monta as tags de contexto (job, domínio, tipo) enviadas ao ErrorReporter
+func crawlFailureTags(control *JobControl, errorType, rawURL string) map[string]string {
+	tags := map[string]string{"error_type": errorType}
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		tags["domain"] = repository.NormalizeDomain(parsed.Host)
+	}
+	if control != nil && control.JobID() != "" {
+		tags["job_id"] = control.JobID()
+	}
+	return tags
+}
+
+// reportCrawlFailure registra a falha no log e, se for grave, a encaminha ao ErrorReporter
+func reportCrawlFailure(log *logger.Logger, control *JobControl, errorType, rawURL, message string, err error) {
+	log = log.WithField("url", rawURL)
+	if !highSeverityErrorTypes[errorType] {
+		log.Error(message, err)
+		return
+	}
+	log.Report(message, err, crawlFailureTags(control, errorType, rawURL))
+}
+
+// RecordError registra um erro usando sua mensagem
+func (et *ErrorTracker) RecordError(errorType, rawURL string, err error) {
+	if err == nil {
+		return
+	}
+	et.Record(errorType, rawURL, err.Error(), 0)
+}
+
+// CountsByType retorna o total de erros por tipo
+func (et *ErrorTracker) CountsByType() map[string]int {
+	return et.Report("", "", 0).ByType
+}
+
+// Report agrega os erros filtrando por tipo e/ou domínio (vazios = todos); limit controla
+// quantas amostras recentes são retornadas, das mais novas para as mais antigas
+func (et *ErrorTracker) Report(errorType, domain string, limit int) *ErrorReport {
+	report := &ErrorReport{
+		ByType:   make(map[string]int),
+		ByDomain: make(map[string]map[string]int),
+		Samples:  []CrawlError{},
+	}
+	if et == nil {
+		return report
+	}
+	domain = repository.NormalizeDomain(domain)
+
+	et.mutex.RLock()
+	defer et.mutex.RUnlock()
+
+	for currentType, byDomain := range et.counts {
+		if errorType != "" && currentType != errorType {
+			continue
+		}
+		for currentDomain, count := range byDomain {
+			if domain != "" && currentDomain != domain {
+				continue
+			}
+			report.Total += count
+			report.ByType[currentType] += count
+			if report.ByDomain[currentDomain] == nil {
+				report.ByDomain[currentDomain] = make(map[string]int)
+			}
+			report.ByDomain[currentDomain][currentType] += count
+		}
+
+		for _, sample := range et.samples[currentType] {
+			if domain == "" || sample.Domain == domain {
+				report.Samples = append(report.Samples, sample)
+			}
+		}
+	}
+
+	sort.Slice(report.Samples, func(i, j int) bool {
+		return report.Samples[i].OccurredAt.After(report.Samples[j].OccurredAt)
+	})
+	if limit > 0 && len(report.Samples) > limit {
+		report.Samples = report.Samples[:limit]
+	}
+	return report
+}
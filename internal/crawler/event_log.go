@@ -0,0 +1,130 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// Tipos de evento gravados no log NDJSON
+const (
+	EventPageFetched    = "page_fetched"
+	EventPageClassified = "page_classified"
+	EventPropertySaved  = "property_saved"
+	EventError          = "error"
+)
+
+// CrawlEvent é uma linha do log de eventos: um acontecimento relevante do crawling em formato
+// estável para ferramentas externas (sem precisar interpretar os logs de texto)
+type CrawlEvent struct {
+	Time   time.Time              `json:"time"`
+	Type   string                 `json:"type"`
+	URL    string                 `json:"url,omitempty"`
+	Domain string                 `json:"domain,omitempty"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventLog grava os eventos do crawling como JSON delimitado por linha (NDJSON)
+type EventLog struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewEventLog abre (ou cria) o arquivo de eventos em modo de acréscimo
+func NewEventLog(path string) (*EventLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %v", err)
+	}
+	return &EventLog{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Emit grava um evento; o domínio é derivado da URL quando não informado
+func (el *EventLog) Emit(event CrawlEvent) {
+	if el == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.Domain == "" && event.URL != "" {
+		if parsed, err := url.Parse(event.URL); err == nil {
+			event.Domain = repository.NormalizeDomain(parsed.Host)
+		}
+	}
+
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+	if el.encoder != nil {
+		el.encoder.Encode(event)
+	}
+}
+
+// Close fecha o arquivo de eventos
+func (el *EventLog) Close() error {
+	if el == nil {
+		return nil
+	}
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+	el.encoder = nil
+	return el.file.Close()
+}
+
+var (
+	eventLogMutex sync.RWMutex
+	eventLog      *EventLog
+)
+
+// SetEventLog define o log de eventos usado pelos engines (nil desativa)
+func SetEventLog(log *EventLog) {
+	eventLogMutex.Lock()
+	defer eventLogMutex.Unlock()
+	eventLog = log
+}
+
+// emitEvent grava o evento no log configurado; sem log não faz nada
+func emitEvent(eventType, rawURL string, data map[string]interface{}) {
+	eventLogMutex.RLock()
+	log := eventLog
+	eventLogMutex.RUnlock()
+	if log == nil {
+		return
+	}
+	log.Emit(CrawlEvent{Type: eventType, URL: rawURL, Data: data})
+}
+
+// emitPageFetched registra a resposta baixada (status e tamanho)
+func emitPageFetched(r *colly.Response) {
+	emitEvent(EventPageFetched, r.Request.URL.String(), map[string]interface{}{
+		"status_code": r.StatusCode,
+		"bytes":       len(r.Body),
+	})
+}
+
+// emitPageClassified registra o tipo atribuído à página (property, catalog, generic, rejected)
+func emitPageClassified(rawURL, pageType string, confidence float64, reason string) {
+	emitEvent(EventPageClassified, rawURL, map[string]interface{}{
+		"page_type":  pageType,
+		"confidence": confidence,
+		"reason":     reason,
+	})
+}
+
+// emitPropertySaved registra um imóvel gravado no repositório
+func emitPropertySaved(rawURL string, property *repository.Property) {
+	emitEvent(EventPropertySaved, rawURL, map[string]interface{}{
+		"tipo_imovel": property.TipoImovel,
+		"valor":       property.Valor,
+		"cidade":      property.Cidade,
+		"bairro":      property.Bairro,
+		"parent_id":   property.ParentID,
+	})
+}
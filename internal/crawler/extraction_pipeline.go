@@ -0,0 +1,230 @@
+package crawler
+
+import (
+	"fmt"
+	"plugin"
+	"strings"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// CoreExtractorName identifica a etapa padrão (DataExtractor) na ordem do pipeline
+const CoreExtractorName = "core"
+
+// pluginExtractorSymbol é o símbolo procurado nos plugins Go (.so) carregados
+const pluginExtractorSymbol = "Extractor"
+
+// ExtractionPage é a página entregue a cada etapa do pipeline de extração
+type ExtractionPage struct {
+	URL     string
+	Element *colly.HTMLElement
+}
+
+// Extractor é uma etapa de extração. Etapas customizadas recebem o imóvel já preenchido
+// pelas etapas anteriores e podem completar campos ou gravar campos próprios
+// (Property.SetCustomField). Um erro é registrado em log e não interrompe a extração.
+type Extractor interface {
+	Name() string
+	Extract(page *ExtractionPage, property *repository.Property) error
+}
+
+// extractorFunc adapta uma função para a interface Extractor
+type extractorFunc struct {
+	name string
+	fn   func(page *ExtractionPage, property *repository.Property) error
+}
+
+func (f extractorFunc) Name() string { return f.name }
+
+func (f extractorFunc) Extract(page *ExtractionPage, property *repository.Property) error {
+	return f.fn(page, property)
+}
+
+// NewExtractorFunc cria uma etapa a partir de uma função
+func NewExtractorFunc(name string, fn func(page *ExtractionPage, property *repository.Property) error) Extractor {
+	return extractorFunc{name: name, fn: fn}
+}
+
+// ExtractionPipeline mantém as etapas registradas e a ordem em que são executadas
+type ExtractionPipeline struct {
+	mutex      sync.RWMutex
+	extractors map[string]Extractor
+	registered []string // ordem de registro (usada quando nenhuma ordem é configurada)
+	order      []string // ordem configurada; etapas fora da lista ficam desativadas
+	logger     *logger.Logger
+}
+
+// defaultPipeline é compartilhado por todos os DataExtractor
+var defaultPipeline = NewExtractionPipeline()
+
+// NewExtractionPipeline cria um pipeline contendo apenas a etapa padrão
+func NewExtractionPipeline() *ExtractionPipeline {
+	return &ExtractionPipeline{
+		extractors: make(map[string]Extractor),
+		logger:     logger.NewLogger("extraction_pipeline"),
+	}
+}
+
+// Register adiciona uma etapa customizada ao pipeline
+func (p *ExtractionPipeline) Register(extractor Extractor) error {
+	if extractor == nil {
+		return fmt.Errorf("extractor is nil")
+	}
+	name := strings.TrimSpace(extractor.Name())
+	if name == "" || name == CoreExtractorName {
+		return fmt.Errorf("invalid extractor name %q", name)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, exists := p.extractors[name]; exists {
+		return fmt.Errorf("extractor %q already registered", name)
+	}
+	p.extractors[name] = extractor
+	p.registered = append(p.registered, name)
+	return nil
+}
+
+// Unregister remove uma etapa customizada (e sua posição na ordem configurada)
+func (p *ExtractionPipeline) Unregister(name string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.extractors, name)
+	p.registered = removeName(p.registered, name)
+	p.order = removeName(p.order, name)
+}
+
+// SetOrder define a ordem de execução; "core" posiciona a etapa padrão (se omitida, ela roda
+// primeiro). Etapas registradas fora da lista ficam desativadas. Lista vazia restaura o padrão.
+func (p *ExtractionPipeline) SetOrder(order []string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, name := range order {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, exists := p.extractors[name]; !exists && name != CoreExtractorName {
+			return fmt.Errorf("unknown extractor %q in extraction order", name)
+		}
+		seen[name] = true
+		cleaned = append(cleaned, name)
+	}
+	p.order = cleaned
+	return nil
+}
+
+// Stages retorna os nomes das etapas na ordem efetiva de execução
+func (p *ExtractionPipeline) Stages() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.stageNames()
+}
+
+// stageNames resolve a ordem efetiva; requer o mutex
+func (p *ExtractionPipeline) stageNames() []string {
+	if len(p.order) == 0 {
+		return append([]string{CoreExtractorName}, p.registered...)
+	}
+	for _, name := range p.order {
+		if name == CoreExtractorName {
+			return append([]string(nil), p.order...)
+		}
+	}
+	return append([]string{CoreExtractorName}, p.order...)
+}
+
+// Run executa as etapas em ordem; core é a etapa padrão do DataExtractor
+func (p *ExtractionPipeline) Run(page *ExtractionPage, property *repository.Property, core Extractor) {
+	p.mutex.RLock()
+	names := p.stageNames()
+	stages := make([]Extractor, 0, len(names))
+	for _, name := range names {
+		if name == CoreExtractorName {
+			stages = append(stages, core)
+		} else {
+			stages = append(stages, p.extractors[name])
+		}
+	}
+	p.mutex.RUnlock()
+
+	for _, stage := range stages {
+		if err := stage.Extract(page, property); err != nil {
+			p.logger.WithFields(map[string]interface{}{
+				"url":       page.URL,
+				"extractor": stage.Name(),
+			}).WithError(err).Warn("Extraction stage failed")
+		}
+	}
+}
+
+// LoadPlugin carrega um plugin Go (.so) que exporte a variável "Extractor" e o registra
+func (p *ExtractionPipeline) LoadPlugin(path string) error {
+	loaded, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open extractor plugin %s: %v", path, err)
+	}
+	symbol, err := loaded.Lookup(pluginExtractorSymbol)
+	if err != nil {
+		return fmt.Errorf("extractor plugin %s: %v", path, err)
+	}
+
+	extractor, ok := symbol.(Extractor)
+	if !ok {
+		if pointer, isPointer := symbol.(*Extractor); isPointer && pointer != nil {
+			extractor, ok = *pointer, true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("extractor plugin %s: symbol %s does not implement crawler.Extractor", path, pluginExtractorSymbol)
+	}
+	return p.Register(extractor)
+}
+
+// RegisterExtractor registra uma etapa customizada no pipeline padrão (ex: no init de um pacote)
+func RegisterExtractor(extractor Extractor) error {
+	return defaultPipeline.Register(extractor)
+}
+
+// UnregisterExtractor remove uma etapa do pipeline padrão
+func UnregisterExtractor(name string) {
+	defaultPipeline.Unregister(name)
+}
+
+// ExtractionStages retorna a ordem efetiva do pipeline padrão
+func ExtractionStages() []string {
+	return defaultPipeline.Stages()
+}
+
+// ConfigureExtractionPipeline carrega os plugins informados e aplica a ordem das etapas
+// (EXTRACTION_PLUGINS e EXTRACTION_STAGES)
+func ConfigureExtractionPipeline(order, pluginPaths []string) error {
+	for _, path := range pluginPaths {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if err := defaultPipeline.LoadPlugin(path); err != nil {
+			return err
+		}
+	}
+	return defaultPipeline.SetOrder(order)
+}
+
+// removeName remove um nome da lista preservando a ordem
+func removeName(names []string, name string) []string {
+	result := names[:0]
+	for _, current := range names {
+		if current != name {
+			result = append(result, current)
+		}
+	}
+	return result
+}
@@ -7,67 +7,196 @@ import (
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
 	"github.com/gocolly/colly"
 )
 
+// galleryPhotoSelector encontra as fotos da galeria do anúncio (galerias, carrosséis e
+// lightboxes). Imagens fora dela (anúncios relacionados, banners, rodapé) não contam como fotos
+const galleryPhotoSelector = `[class*="galeria"] img, [class*="gallery"] img, [id*="galeria"] img, [id*="gallery"] img, ` +
+	`[class*="carousel"] img, [class*="carrossel"] img, [class*="swiper"] img, [class*="slick"] img, ` +
+	`[class*="slider"] img, [class*="fotos"] img, [class*="photos"] img, [data-fancybox] img, ` +
+	`[data-lightbox] img, img[itemprop="image"]`
+
 // DataExtractor é responsável por extrair dados de elementos HTML
 type DataExtractor struct {
-	logger *logger.Logger
+	logger        *logger.Logger
+	digits        *regexp.Regexp
+	decimal       *regexp.Regexp
+	nonPhotoImage *regexp.Regexp
 }
 
 // NewDataExtractor cria um novo extrator de dados
 func NewDataExtractor() *DataExtractor {
 	return &DataExtractor{
-		logger: logger.NewLogger("data_extractor"),
+		logger:        logger.NewLogger("data_extractor"),
+		digits:        regexp.MustCompile(`\d+`),
+		decimal:       regexp.MustCompile(`\d+(?:[,.]\d+)?`),
+		nonPhotoImage: regexp.MustCompile(`(?i)(logo|icon|sprite|avatar|banner|selo|whatsapp|facebook|instagram|twitter|youtube|loading|placeholder|blank|pixel)|\.(svg|gif)(\?|$)|^data:`),
 	}
 }
 
-// ExtractProperty extrai dados de propriedade de um elemento HTML
+// ExtractProperty extrai dados de propriedade de um elemento HTML, executando a etapa padrão
+// e as etapas customizadas registradas no pipeline de extração (ver extraction_pipeline.go)
 func (e *DataExtractor) ExtractProperty(element *colly.HTMLElement, url string) *repository.Property {
 	e.logger.WithField("url", url).Debug("Extracting property data")
 
 	property := &repository.Property{
 		URL: url,
 	}
+	page := &ExtractionPage{URL: url, Element: element}
+	defaultPipeline.Run(page, property, NewExtractorFunc(CoreExtractorName, e.extractCoreFields))
+
+	e.logger.WithFields(map[string]interface{}{
+		"endereco": property.Endereco,
+		"valor":    property.Valor,
+		"tipo":     property.TipoImovel,
+	}).Debug("Property data extracted")
+
+	return property
+}
+
+// extractCoreFields é a etapa padrão: seletores genéricos, meta tags e padrões de texto
+func (e *DataExtractor) extractCoreFields(page *ExtractionPage, property *repository.Property) error {
+	element := page.Element
 
 	// Extrai endereço
-	property.Endereco = e.extractAddress(element)
+	var source, selector string
+	property.Endereco, source, selector = e.extractAddress(element)
+	recordProvenance(property, "endereco", property.Endereco != "", source, selector)
 
 	// Extrai valor
-	property.ValorTexto = e.extractPrice(element)
-	property.Valor = e.extractNumericValue(property.ValorTexto)
+	property.ValorTexto, source, selector = e.extractPrice(element)
+	property.Valor = utils.ParseAmount(property.ValorTexto)
+	recordProvenance(property, "valor", property.Valor > 0, source, selector)
 
 	// Extrai descrição
-	property.Descricao = e.extractDescription(element)
+	property.Descricao, source, selector = e.extractDescription(element)
+	recordProvenance(property, "descricao", property.Descricao != "", source, selector)
 
 	// Extrai informações numéricas da descrição
 	property.Quartos = e.extractRooms(property.Descricao)
 	property.Banheiros = e.extractBathrooms(property.Descricao)
-	property.AreaTotal = e.extractArea(property.Descricao)
+	property.AreaTotal = utils.ParseArea(property.Descricao)
+	recordProvenance(property, "quartos", property.Quartos > 0, repository.ExtractorRegexFallback, "")
+	recordProvenance(property, "banheiros", property.Banheiros > 0, repository.ExtractorRegexFallback, "")
+	recordProvenance(property, "area_total", property.AreaTotal > 0, repository.ExtractorRegexFallback, "")
 
 	// Extrai tipo de imóvel
 	property.TipoImovel = e.extractPropertyType(property.Descricao + " " + property.Endereco)
+	recordProvenance(property, "tipo_imovel", property.TipoImovel != "Outro", repository.ExtractorRegexFallback, "")
 
 	// Extrai localização
 	property.Cidade, property.Bairro = e.extractLocation(property.Endereco + " " + property.Descricao)
+	recordProvenance(property, "cidade", property.Cidade != "", repository.ExtractorRegexFallback, "")
+	recordProvenance(property, "bairro", property.Bairro != "", repository.ExtractorRegexFallback, "")
 
 	// Extrai CEP
 	property.CEP = e.extractCEP(property.Endereco)
+	recordProvenance(property, "cep", property.CEP != "", repository.ExtractorRegexFallback, "")
 
 	// Extrai características
-	property.Caracteristicas = e.extractFeatures(element, property.Descricao)
+	property.Caracteristicas, source = e.extractFeatures(element, property.Descricao)
+	recordProvenance(property, "caracteristicas", len(property.Caracteristicas) > 0, source, "")
 
-	e.logger.WithFields(map[string]interface{}{
-		"endereco": property.Endereco,
-		"valor":    property.Valor,
-		"tipo":     property.TipoImovel,
-	}).Debug("Property data extracted")
+	// Conta as fotos da galeria do anúncio (anúncios sem fotos são sinalizados) e guarda as URLs
+	// usadas na detecção de fotos repetidas
+	photos := e.photoURLs(element)
+	property.Fotos = len(photos)
+	property.SemFotos = property.Fotos == 0
+	if len(photos) > repository.MaxPhotoURLs {
+		photos = photos[:repository.MaxPhotoURLs]
+	}
+	property.FotoURLs = photos
 
-	return property
+	// Verifica se a página traz telefone, WhatsApp ou e-mail (usado no risco de golpe)
+	hasContact := e.hasContact(element)
+	property.TemContato = &hasContact
+
+	return nil
+}
+
+// ApplyDomainSelectors sobrescreve os campos com os seletores CSS configurados para o domínio
+// (DomainConfig.Selectors), que têm prioridade sobre os seletores genéricos
+func (e *DataExtractor) ApplyDomainSelectors(element *colly.HTMLElement, property *repository.Property, selectors map[string]string) {
+	for field, selector := range selectors {
+		if field == "caracteristicas" {
+			var features []string
+			element.ForEach(selector, func(_ int, el *colly.HTMLElement) {
+				if feature := e.cleanText(el.Text); feature != "" {
+					features = append(features, feature)
+				}
+			})
+			if len(features) > 0 {
+				property.Caracteristicas = features
+				property.SetProvenance(field, repository.ExtractorDomainSelector, selector)
+			}
+			continue
+		}
+
+		text := e.cleanText(element.ChildText(selector))
+		if text == "" {
+			continue
+		}
+
+		applied := true
+		switch field {
+		case "endereco":
+			property.Endereco = text
+		case "cidade":
+			property.Cidade = text
+		case "bairro":
+			property.Bairro = text
+		case "descricao":
+			property.Descricao = text
+		case "tipo_imovel":
+			property.TipoImovel = text
+		case "valor":
+			if value := utils.ParseAmount(text); value > 0 {
+				property.ValorTexto = text
+				property.Valor = value
+			} else {
+				applied = false
+			}
+		case "quartos", "banheiros":
+			value, err := strconv.Atoi(e.digits.FindString(text))
+			if err != nil || value <= 0 {
+				applied = false
+			} else if field == "quartos" {
+				property.Quartos = value
+			} else {
+				property.Banheiros = value
+			}
+		case "area_total":
+			value := utils.ParseArea(text)
+			if value == 0 {
+				value, _ = utils.ParseBrazilianNumber(e.decimal.FindString(text))
+			}
+			if value <= 0 {
+				applied = false
+			} else {
+				property.AreaTotal = value
+			}
+		default:
+			applied = false
+		}
+
+		if applied {
+			property.SetProvenance(field, repository.ExtractorDomainSelector, selector)
+		}
+	}
+}
+
+// recordProvenance registra a etapa que produziu o campo, se ele foi preenchido
+func recordProvenance(property *repository.Property, field string, filled bool, extractor, selector string) {
+	if filled {
+		property.SetProvenance(field, extractor, selector)
+	}
 }
 
-// extractAddress extrai endereço usando múltiplos seletores
-func (e *DataExtractor) extractAddress(element *colly.HTMLElement) string {
+// extractAddress extrai endereço usando múltiplos seletores.
+// Retorna também a etapa de extração e o seletor usados.
+func (e *DataExtractor) extractAddress(element *colly.HTMLElement) (string, string, string) {
 	// Seletores mais abrangentes e específicos para sites brasileiros
 	selectors := []string{
 		".endereco", ".address", ".property-address", "[itemprop=address]",
@@ -85,7 +214,7 @@ func (e *DataExtractor) extractAddress(element *colly.HTMLElement) string {
 		if text := strings.TrimSpace(element.ChildText(selector)); text != "" {
 			// Verifica se parece com endereço
 			if e.looksLikeAddress(text) {
-				return e.cleanText(text)
+				return e.cleanText(text), repository.ExtractorGenericSelector, selector
 			}
 		}
 	}
@@ -123,11 +252,11 @@ func (e *DataExtractor) extractAddress(element *colly.HTMLElement) string {
 	for _, pattern := range addressPatterns {
 		re := regexp.MustCompile(pattern)
 		if match := re.FindString(fullText); match != "" {
-			return e.cleanText(match)
+			return e.cleanText(match), repository.ExtractorRegexFallback, ""
 		}
 	}
 
-	return ""
+	return "", "", ""
 }
 
 // looksLikeAddress verifica se um texto parece ser um endereço
@@ -170,8 +299,9 @@ func (e *DataExtractor) looksLikeAddress(text string) bool {
 	return wordCount >= 1 || patternCount >= 1
 }
 
-// extractPrice extrai preço usando múltiplos seletores
-func (e *DataExtractor) extractPrice(element *colly.HTMLElement) string {
+// extractPrice extrai preço usando múltiplos seletores.
+// Retorna também a etapa de extração e o seletor usados.
+func (e *DataExtractor) extractPrice(element *colly.HTMLElement) (string, string, string) {
 	// Seletores mais abrangentes para preços
 	selectors := []string{
 		".valor", ".price", ".property-price", "[itemprop=price]",
@@ -187,7 +317,7 @@ func (e *DataExtractor) extractPrice(element *colly.HTMLElement) string {
 	for _, selector := range selectors {
 		if text := strings.TrimSpace(element.ChildText(selector)); text != "" {
 			if e.looksLikePrice(text) {
-				return e.cleanText(text)
+				return e.cleanText(text), repository.ExtractorGenericSelector, selector
 			}
 		}
 	}
@@ -216,11 +346,11 @@ func (e *DataExtractor) extractPrice(element *colly.HTMLElement) string {
 	for _, pattern := range pricePatterns {
 		re := regexp.MustCompile(pattern)
 		if match := re.FindString(fullText); match != "" {
-			return e.cleanText(match)
+			return e.cleanText(match), repository.ExtractorRegexFallback, ""
 		}
 	}
 
-	return ""
+	return "", "", ""
 }
 
 // looksLikePrice verifica se um texto parece ser um preço
@@ -255,8 +385,9 @@ func (e *DataExtractor) looksLikePrice(text string) bool {
 	return monetaryPattern.MatchString(text)
 }
 
-// extractDescription extrai descrição usando múltiplos seletores
-func (e *DataExtractor) extractDescription(element *colly.HTMLElement) string {
+// extractDescription extrai descrição usando múltiplos seletores.
+// Retorna também a etapa de extração e o seletor usados.
+func (e *DataExtractor) extractDescription(element *colly.HTMLElement) (string, string, string) {
 	// Seletores mais abrangentes para descrições
 	selectors := []string{
 		".descricao", ".description", ".property-description", "[itemprop=description]",
@@ -273,7 +404,7 @@ func (e *DataExtractor) extractDescription(element *colly.HTMLElement) string {
 	for _, selector := range selectors {
 		if text := strings.TrimSpace(element.ChildText(selector)); text != "" {
 			if e.looksLikeDescription(text) {
-				return e.cleanText(text)
+				return e.cleanText(text), repository.ExtractorGenericSelector, selector
 			}
 		}
 	}
@@ -281,7 +412,7 @@ func (e *DataExtractor) extractDescription(element *colly.HTMLElement) string {
 	// Busca em meta tags
 	metaDescription := element.ChildAttr("meta[name='description']", "content")
 	if metaDescription != "" && e.looksLikeDescription(metaDescription) {
-		return e.cleanText(metaDescription)
+		return e.cleanText(metaDescription), repository.ExtractorMetaTag, "meta[name='description']"
 	}
 
 	// Fallback: coleta parágrafos e divs que parecem ser descrição
@@ -322,7 +453,8 @@ func (e *DataExtractor) extractDescription(element *colly.HTMLElement) string {
 		result = result[:3000] + "..."
 	}
 
-	return result
+	// Texto montado a partir de parágrafos/divs genéricos
+	return result, repository.ExtractorRegexFallback, ""
 }
 
 // looksLikeDescription verifica se um texto parece ser uma descrição válida
@@ -373,9 +505,11 @@ func (e *DataExtractor) looksLikeDescription(text string) bool {
 	return (wordCount >= 2) || (hasSpaces && hasPunctuation && wordCount >= 1)
 }
 
-// extractFeatures extrai características do imóvel
-func (e *DataExtractor) extractFeatures(element *colly.HTMLElement, description string) []string {
+// extractFeatures extrai características do imóvel.
+// A etapa retornada é generic_selector quando alguma veio de listas do HTML.
+func (e *DataExtractor) extractFeatures(element *colly.HTMLElement, description string) ([]string, string) {
 	var features []string
+	source := repository.ExtractorRegexFallback
 
 	// Lista de características comuns
 	commonFeatures := []string{
@@ -395,40 +529,72 @@ func (e *DataExtractor) extractFeatures(element *colly.HTMLElement, description
 		feature := strings.TrimSpace(el.Text)
 		if len(feature) > 3 && len(feature) < 50 {
 			features = append(features, feature)
+			source = repository.ExtractorGenericSelector
 		}
 	})
 
-	return features
+	return features, source
 }
 
-// extractNumericValue extrai valor numérico de uma string
-func (e *DataExtractor) extractNumericValue(valueStr string) float64 {
-	if valueStr == "" {
-		return 0
+// photoURLs retorna as fotos distintas da galeria do anúncio (URLs absolutas, na ordem da
+// página), ignorando logos, ícones e imagens pequenas. Sem galeria reconhecida, vale a imagem
+// de compartilhamento (og:image), quando houver
+func (e *DataExtractor) photoURLs(element *colly.HTMLElement) []string {
+	seen := make(map[string]bool)
+	var photos []string
+	add := func(src string) {
+		if element.Request != nil && element.Request.URL != nil {
+			if absolute := element.Request.AbsoluteURL(src); absolute != "" {
+				src = absolute
+			}
+		}
+		if !seen[src] {
+			seen[src] = true
+			photos = append(photos, src)
+		}
 	}
 
-	// Remove caracteres não numéricos exceto ponto e vírgula
-	re := regexp.MustCompile(`[^\d,.]`)
-	cleaned := re.ReplaceAllString(valueStr, "")
-
-	// Procura por padrões de valores monetários
-	reValue := regexp.MustCompile(`(\d{1,3}(?:\.\d{3})*(?:,\d{1,2})?)|\d+`)
-	matches := reValue.FindString(cleaned)
-
-	if matches == "" {
-		return 0
-	}
+	element.ForEach(galleryPhotoSelector, func(_ int, el *colly.HTMLElement) {
+		src := el.Attr("data-src")
+		if src == "" {
+			src = el.Attr("data-lazy")
+		}
+		if src == "" {
+			src = el.Attr("src")
+		}
+		src = strings.TrimSpace(src)
+		if src == "" || e.nonPhotoImage.MatchString(src) || e.nonPhotoImage.MatchString(el.Attr("class")) {
+			return
+		}
 
-	// Converte vírgula para ponto (padrão brasileiro para internacional)
-	matches = strings.Replace(matches, ".", "", -1)
-	matches = strings.Replace(matches, ",", ".", 1)
+		// Imagens com dimensão declarada pequena são miniaturas de interface
+		for _, attr := range []string{"width", "height"} {
+			if size, err := strconv.Atoi(strings.TrimSuffix(el.Attr(attr), "px")); err == nil && size > 0 && size < 100 {
+				return
+			}
+		}
+		add(src)
+	})
 
-	value, err := strconv.ParseFloat(matches, 64)
-	if err != nil {
-		return 0
+	if len(photos) == 0 {
+		if src := strings.TrimSpace(element.ChildAttr(`meta[property="og:image"]`, "content")); src != "" && !e.nonPhotoImage.MatchString(src) {
+			add(src)
+		}
 	}
+	return photos
+}
 
-	return value
+// hasContact indica se a página tem links de telefone, WhatsApp ou e-mail, ou contatos no texto
+func (e *DataExtractor) hasContact(element *colly.HTMLElement) bool {
+	found := false
+	element.ForEach("a[href]", func(_ int, el *colly.HTMLElement) {
+		href := strings.ToLower(el.Attr("href"))
+		if strings.HasPrefix(href, "tel:") || strings.HasPrefix(href, "mailto:") ||
+			strings.Contains(href, "wa.me/") || strings.Contains(href, "whatsapp") {
+			found = true
+		}
+	})
+	return found || repository.HasContactInfo(element.Text)
 }
 
 // extractRooms extrai número de quartos
@@ -455,19 +621,6 @@ func (e *DataExtractor) extractBathrooms(text string) int {
 	return 0
 }
 
-// extractArea extrai área em m²
-func (e *DataExtractor) extractArea(text string) float64 {
-	re := regexp.MustCompile(`(\d+(?:[,.]\d+)?)\s*m²`)
-	match := re.FindStringSubmatch(text)
-	if len(match) > 1 {
-		areaStr := strings.Replace(match[1], ",", ".", 1)
-		if area, err := strconv.ParseFloat(areaStr, 64); err == nil {
-			return area
-		}
-	}
-	return 0
-}
-
 // extractCEP extrai CEP
 func (e *DataExtractor) extractCEP(text string) string {
 	re := regexp.MustCompile(`\d{5}-?\d{3}`)
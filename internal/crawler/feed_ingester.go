@@ -0,0 +1,353 @@
+package crawler
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Limites do leitor de feeds
+const (
+	maxFeedSize         = 5 << 20 // 5MB por feed
+	maxFeedSeenURLs     = 100000  // entradas lembradas para não reprocessar o mesmo anúncio
+	maxFeedAttempts     = 3       // crawlings sem sucesso antes de desistir da entrada
+	feedRecentlyCrawled = 24 * time.Hour
+)
+
+// FeedEntry é um anúncio publicado em um feed RSS/Atom, convertido em tarefa de crawling
+type FeedEntry struct {
+	URL       string    `json:"url"`
+	FeedURL   string    `json:"feed_url"`
+	Title     string    `json:"title,omitempty"`
+	Published time.Time `json:"published,omitempty"`
+}
+
+// feedState guarda os validadores HTTP do último download do feed (GET condicional)
+type feedState struct {
+	etag         string
+	lastModified string
+	lastPolled   time.Time
+	lastError    string
+	entries      int
+}
+
+// FeedStatus expõe o estado de um feed acompanhado
+type FeedStatus struct {
+	URL        string    `json:"url"`
+	LastPolled time.Time `json:"last_polled"`
+	Entries    int       `json:"entries"` // novas entradas encontradas na última leitura
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// FeedIngester acompanha feeds RSS/Atom de novos anúncios e transforma as entradas inéditas em
+// URLs prioritárias para o próximo crawling, sem precisar recrawlear os sites inteiros. Uma
+// entrada só é dada como vista depois de crawleada; as que falham voltam para a fila
+type FeedIngester struct {
+	mutex    sync.Mutex
+	client   *http.Client
+	urlRepo  repository.URLRepository
+	seen     map[string]bool // entradas já crawleadas (ou abandonadas após maxFeedAttempts)
+	queued   map[string]bool // entradas na fila ou em crawling
+	attempts map[string]int
+	feeds    map[string]*feedState
+	pending  []FeedEntry
+	logger   *logger.Logger
+}
+
+// NewFeedIngester cria o leitor de feeds; urlRepo (opcional) evita entradas crawleadas recentemente
+func NewFeedIngester(urlRepo repository.URLRepository) *FeedIngester {
+	return &FeedIngester{
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: NewAuditedTransport(nil, "")},
+		urlRepo:  urlRepo,
+		seen:     make(map[string]bool),
+		queued:   make(map[string]bool),
+		attempts: make(map[string]int),
+		feeds:    make(map[string]*feedState),
+		logger:   logger.NewLogger("feed_ingester"),
+	}
+}
+
+// Poll lê os feeds e enfileira as entradas inéditas; retorna as novas entradas
+func (fi *FeedIngester) Poll(ctx context.Context, feedURLs []string) []FeedEntry {
+	var fresh []FeedEntry
+	for _, feedURL := range feedURLs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		entries, err := fi.fetch(ctx, feedURL)
+		fi.mutex.Lock()
+		state := fi.state(feedURL)
+		state.lastPolled = time.Now()
+		state.lastError = ""
+		state.entries = 0
+		if err != nil {
+			state.lastError = err.Error()
+		}
+		fi.mutex.Unlock()
+
+		if err != nil {
+			fi.logger.WithField("feed", feedURL).WithError(err).Warn("Failed to read listing feed")
+			continue
+		}
+
+		newEntries := fi.filterNew(ctx, entries)
+		fi.mutex.Lock()
+		state.entries = len(newEntries)
+		fi.pending = append(fi.pending, newEntries...)
+		fi.mutex.Unlock()
+		fresh = append(fresh, newEntries...)
+	}
+
+	if len(fresh) > 0 {
+		fi.logger.WithField("entries", len(fresh)).Info("New listings found in feeds")
+	}
+	return fresh
+}
+
+// TakePending retorna e esvazia as entradas aguardando crawling. O resultado do crawling deve
+// ser informado em Complete
+func (fi *FeedIngester) TakePending() []FeedEntry {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	pending := fi.pending
+	fi.pending = nil
+	return pending
+}
+
+// Complete registra o resultado do crawling das entradas retiradas com TakePending: as
+// crawleadas passam a ser vistas e as demais voltam para a fila, até maxFeedAttempts tentativas
+func (fi *FeedIngester) Complete(taken, crawled []FeedEntry) {
+	done := make(map[string]bool, len(crawled))
+	for _, entry := range crawled {
+		done[entry.URL] = true
+	}
+
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+
+	requeued := 0
+	for _, entry := range taken {
+		if !done[entry.URL] {
+			fi.attempts[entry.URL]++
+			if fi.attempts[entry.URL] < maxFeedAttempts {
+				fi.pending = append(fi.pending, entry)
+				requeued++
+				continue
+			}
+			fi.logger.WithField("url", entry.URL).Warn("Giving up on feed entry after repeated crawl failures")
+		}
+		delete(fi.queued, entry.URL)
+		delete(fi.attempts, entry.URL)
+		if len(fi.seen) >= maxFeedSeenURLs {
+			fi.seen = make(map[string]bool)
+		}
+		fi.seen[entry.URL] = true
+	}
+	if requeued > 0 {
+		fi.logger.WithField("entries", requeued).Info("Feed entries not crawled, requeued")
+	}
+}
+
+// PendingCount retorna quantas entradas aguardam crawling
+func (fi *FeedIngester) PendingCount() int {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+	return len(fi.pending)
+}
+
+// Status retorna o estado dos feeds já lidos
+func (fi *FeedIngester) Status() []FeedStatus {
+	fi.mutex.Lock()
+	defer fi.mutex.Unlock()
+
+	statuses := make([]FeedStatus, 0, len(fi.feeds))
+	for feedURL, state := range fi.feeds {
+		statuses = append(statuses, FeedStatus{
+			URL:        feedURL,
+			LastPolled: state.lastPolled,
+			Entries:    state.entries,
+			LastError:  state.lastError,
+		})
+	}
+	return statuses
+}
+
+// state retorna o estado do feed, criando-o se necessário; requer o mutex
+func (fi *FeedIngester) state(feedURL string) *feedState {
+	state, exists := fi.feeds[feedURL]
+	if !exists {
+		state = &feedState{}
+		fi.feeds[feedURL] = state
+	}
+	return state
+}
+
+// filterNew descarta entradas já vistas, já na fila ou crawleadas recentemente e marca as
+// demais como enfileiradas
+func (fi *FeedIngester) filterNew(ctx context.Context, entries []FeedEntry) []FeedEntry {
+	var fresh []FeedEntry
+	for _, entry := range entries {
+		fi.mutex.Lock()
+		known := fi.seen[entry.URL] || fi.queued[entry.URL]
+		fi.mutex.Unlock()
+		if known {
+			continue
+		}
+
+		if fi.urlRepo != nil {
+			if recent, err := fi.urlRepo.IsURLProcessedRecently(ctx, entry.URL, feedRecentlyCrawled); err == nil && recent {
+				continue
+			}
+		}
+
+		fi.mutex.Lock()
+		fi.queued[entry.URL] = true
+		fi.mutex.Unlock()
+		fresh = append(fresh, entry)
+	}
+	return fresh
+}
+
+// fetch baixa o feed com GET condicional e extrai as entradas (nil se não mudou)
+func (fi *FeedIngester) fetch(ctx context.Context, feedURL string) ([]FeedEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed url: %v", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("User-Agent", "Go-Crawler-Feeds/1.0")
+
+	fi.mutex.Lock()
+	state := fi.state(feedURL)
+	if state.etag != "" {
+		req.Header.Set("If-None-Match", state.etag)
+	}
+	if state.lastModified != "" {
+		req.Header.Set("If-Modified-Since", state.lastModified)
+	}
+	fi.mutex.Unlock()
+
+	resp, err := fi.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("feed request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %v", err)
+	}
+	entries, err := ParseFeed(body, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fi.mutex.Lock()
+	state.etag = resp.Header.Get("ETag")
+	state.lastModified = resp.Header.Get("Last-Modified")
+	fi.mutex.Unlock()
+	return entries, nil
+}
+
+// rssDocument cobre RSS 2.0 (rss/channel/item) e Atom (feed/entry)
+type rssDocument struct {
+	XMLName xml.Name
+	Items   []struct {
+		Title   string `xml:"title"`
+		Link    string `xml:"link"`
+		GUID    string `xml:"guid"`
+		PubDate string `xml:"pubDate"`
+	} `xml:"channel>item"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// ParseFeed extrai as URLs dos anúncios de um feed RSS 2.0 ou Atom
+func ParseFeed(data []byte, feedURL string) ([]FeedEntry, error) {
+	var document rssDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid feed: %v", err)
+	}
+
+	base, _ := url.Parse(feedURL)
+	resolve := func(link string) string {
+		link = strings.TrimSpace(link)
+		if link == "" || base == nil {
+			return link
+		}
+		if resolved, err := base.Parse(link); err == nil {
+			return resolved.String()
+		}
+		return link
+	}
+
+	var entries []FeedEntry
+	switch document.XMLName.Local {
+	case "rss":
+		for _, item := range document.Items {
+			link := item.Link
+			if link == "" && strings.HasPrefix(item.GUID, "http") {
+				link = item.GUID
+			}
+			if link = resolve(link); link != "" {
+				entries = append(entries, FeedEntry{URL: link, FeedURL: feedURL, Title: strings.TrimSpace(item.Title), Published: parseFeedTime(item.PubDate)})
+			}
+		}
+	case "feed":
+		for _, entry := range document.Entries {
+			var link string
+			for _, candidate := range entry.Links {
+				if candidate.Rel == "" || candidate.Rel == "alternate" {
+					link = candidate.Href
+					break
+				}
+			}
+			if link = resolve(link); link == "" {
+				continue
+			}
+			published := parseFeedTime(entry.Published)
+			if published.IsZero() {
+				published = parseFeedTime(entry.Updated)
+			}
+			entries = append(entries, FeedEntry{URL: link, FeedURL: feedURL, Title: strings.TrimSpace(entry.Title), Published: published})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported feed format: %s", document.XMLName.Local)
+	}
+	return entries, nil
+}
+
+// parseFeedTime interpreta as datas de RSS (RFC 1123) e Atom (RFC 3339)
+func parseFeedTime(raw string) time.Time {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700"} {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,252 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/gocolly/colly"
+)
+
+// Nomes dos middlewares de fetch embutidos, usados em FETCH_MIDDLEWARES
+const (
+	FetchMiddlewareLogging   = "logging"
+	FetchMiddlewareCache     = "cache"
+	FetchMiddlewareRobots    = "robots"
+	FetchMiddlewareRateLimit = "ratelimit"
+	FetchMiddlewareHeadless  = "headless"
+	FetchMiddlewareProxy     = "proxy"
+)
+
+// FetchMiddleware é um comportamento aplicado a todas as requisições dos collectors (log, cache,
+// robots.txt, ritmo, proxy, renderização). Wrap recebe o próximo transporte da cadeia e retorna o
+// transporte que o envolve: pode alterar a requisição, responder sem chamar next, repetir a
+// chamada ou trocar a resposta.
+type FetchMiddleware interface {
+	Name() string
+	Wrap(next http.RoundTripper) http.RoundTripper
+}
+
+// FetchFunc adapta uma função para a interface http.RoundTripper
+type FetchFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip executa a função
+func (f FetchFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fetchMiddlewareFunc adapta uma função para a interface FetchMiddleware
+type fetchMiddlewareFunc struct {
+	name string
+	wrap func(next http.RoundTripper) http.RoundTripper
+}
+
+func (f fetchMiddlewareFunc) Name() string { return f.name }
+
+func (f fetchMiddlewareFunc) Wrap(next http.RoundTripper) http.RoundTripper {
+	return f.wrap(next)
+}
+
+// NewFetchMiddlewareFunc cria um middleware a partir de uma função
+func NewFetchMiddlewareFunc(name string, wrap func(next http.RoundTripper) http.RoundTripper) FetchMiddleware {
+	return fetchMiddlewareFunc{name: name, wrap: wrap}
+}
+
+// FetchChain é a sequência de middlewares em volta do transporte dos collectors. O primeiro é o
+// mais externo: vê a requisição antes de todos e a resposta depois de todos.
+type FetchChain struct {
+	mutex       sync.RWMutex
+	middlewares []FetchMiddleware
+}
+
+// NewFetchChain cria a cadeia com os middlewares na ordem informada (nil são ignorados)
+func NewFetchChain(middlewares ...FetchMiddleware) *FetchChain {
+	chain := &FetchChain{}
+	for _, middleware := range middlewares {
+		if middleware != nil {
+			chain.middlewares = append(chain.middlewares, middleware)
+		}
+	}
+	return chain
+}
+
+// Use acrescenta o middleware ao fim da cadeia (mais perto da rede)
+func (fc *FetchChain) Use(middleware FetchMiddleware) error {
+	if middleware == nil {
+		return fmt.Errorf("fetch middleware is nil")
+	}
+	name := strings.TrimSpace(middleware.Name())
+	if name == "" {
+		return fmt.Errorf("invalid fetch middleware name %q", name)
+	}
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	for _, existing := range fc.middlewares {
+		if existing.Name() == name {
+			return fmt.Errorf("fetch middleware %q already in chain", name)
+		}
+	}
+	fc.middlewares = append(fc.middlewares, middleware)
+	return nil
+}
+
+// Names retorna os nomes dos middlewares na ordem da cadeia
+func (fc *FetchChain) Names() []string {
+	if fc == nil {
+		return nil
+	}
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	names := make([]string, len(fc.middlewares))
+	for i, middleware := range fc.middlewares {
+		names[i] = middleware.Name()
+	}
+	return names
+}
+
+// Has indica se a cadeia contém o middleware com o nome informado
+func (fc *FetchChain) Has(name string) bool {
+	for _, existing := range fc.Names() {
+		if existing == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware retorna o middleware com o nome informado (nil quando não está na cadeia)
+func (fc *FetchChain) Middleware(name string) FetchMiddleware {
+	if fc == nil {
+		return nil
+	}
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	for _, middleware := range fc.middlewares {
+		if middleware.Name() == name {
+			return middleware
+		}
+	}
+	return nil
+}
+
+// Len retorna a quantidade de middlewares (0 para a cadeia nil)
+func (fc *FetchChain) Len() int {
+	if fc == nil {
+		return 0
+	}
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	return len(fc.middlewares)
+}
+
+// Then envolve o transporte base (nil usa http.DefaultTransport) com os middlewares da cadeia
+func (fc *FetchChain) Then(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if fc == nil {
+		return base
+	}
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	transport := base
+	for i := len(fc.middlewares) - 1; i >= 0; i-- {
+		transport = fc.middlewares[i].Wrap(transport)
+	}
+	return transport
+}
+
+// NewFetchChainFromConfig cria a cadeia com os middlewares embutidos de FETCH_MIDDLEWARES, na
+// ordem informada; nil quando a lista está vazia. O proxy troca o transporte da rede e por isso
+// precisa ser o último.
+func NewFetchChainFromConfig(cfg *config.Config) (*FetchChain, error) {
+	chain := NewFetchChain()
+	for i, entry := range cfg.FetchMiddlewares {
+		name := strings.ToLower(strings.TrimSpace(entry))
+		if name == "" {
+			continue
+		}
+
+		var middleware FetchMiddleware
+		switch name {
+		case FetchMiddlewareLogging:
+			middleware = NewLoggingFetchMiddleware()
+		case FetchMiddlewareCache:
+			middleware = NewCacheFetchMiddleware(cfg.FetchCacheTTL, cfg.FetchCacheMaxEntries)
+		case FetchMiddlewareRobots:
+			middleware = NewRobotsFetchMiddleware(cfg.FetchRobotsTTL)
+		case FetchMiddlewareRateLimit:
+			middleware = NewRateLimitFetchMiddleware(cfg.FetchRateLimitInterval)
+		case FetchMiddlewareHeadless:
+			if cfg.FetchHeadlessURL == "" {
+				return nil, fmt.Errorf("fetch middleware headless requires FETCH_HEADLESS_URL")
+			}
+			middleware = NewHeadlessFallbackFetchMiddleware(NewHTTPPageRenderer(cfg.FetchHeadlessURL, cfg.FetchHeadlessTimeout))
+		case FetchMiddlewareProxy:
+			if i != len(cfg.FetchMiddlewares)-1 {
+				return nil, fmt.Errorf("fetch middleware proxy must be the last one")
+			}
+			proxy, err := NewProxyFetchMiddleware(cfg.FetchProxyURLs)
+			if err != nil {
+				return nil, err
+			}
+			middleware = proxy
+		default:
+			return nil, fmt.Errorf("unknown fetch middleware %q", name)
+		}
+		if err := chain.Use(middleware); err != nil {
+			return nil, err
+		}
+	}
+	if chain.Len() == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
+
+var (
+	fetchChainMutex sync.RWMutex
+	fetchChain      *FetchChain
+)
+
+// SetFetchChain define a cadeia de middlewares aplicada aos collectors de todos os engines (nil
+// desativa); vale para os collectors criados depois da chamada
+func SetFetchChain(chain *FetchChain) {
+	fetchChainMutex.Lock()
+	defer fetchChainMutex.Unlock()
+	fetchChain = chain
+}
+
+// CurrentFetchChain retorna a cadeia configurada (nil desativada)
+func CurrentFetchChain() *FetchChain {
+	fetchChainMutex.RLock()
+	defer fetchChainMutex.RUnlock()
+	return fetchChain
+}
+
+// fetchCollector monta o transporte do collector: o log de auditoria (quando configurado) envolve
+// o limite de tempo das páginas, que envolve a cadeia de middlewares, que envolve o transporte
+// base (nil usa o padrão). Sem auditoria, limite de tempo e middlewares o transporte do collector
+// não muda.
+func fetchCollector(c *colly.Collector, base http.RoundTripper, jobID string, limits PageLimits) {
+	chain := CurrentFetchChain()
+	audited := currentRequestAudit() != nil
+	if !audited && chain.Len() == 0 && limits.MaxDuration <= 0 {
+		return
+	}
+
+	transport := base
+	if chain.Len() > 0 {
+		transport = chain.Then(base)
+	}
+	if limits.MaxDuration > 0 {
+		transport = limits.Transport(transport)
+	}
+	if audited {
+		transport = NewAuditedTransport(transport, jobID)
+	}
+	c.WithTransport(transport)
+}
@@ -0,0 +1,611 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+)
+
+// Limites dos middlewares de fetch embutidos
+const (
+	fetchCacheMaxBodyBytes  = 5 << 20   // respostas maiores não ficam em cache
+	robotsMaxBytes          = 512 << 10 // robots.txt maiores são truncados
+	headlessMaxBodyBytes    = 10 << 20  // páginas maiores não são avaliadas nem renderizadas
+	headlessMinTextRunes    = 200       // páginas com menos texto visível e com scripts são renderizadas
+	defaultFetchCacheSize   = 1000
+	defaultRobotsTTL        = time.Hour
+	defaultHeadlessTimeout  = time.Minute
+	fetchCacheHeader        = "X-Fetch-Cache"
+	fetchRenderedHeader     = "X-Fetch-Rendered"
+	fetchCacheHeaderHit     = "HIT"
+	fetchRenderedByHeadless = "headless"
+)
+
+// ErrDisallowedByRobots indica uma URL bloqueada pelo robots.txt do site
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// NewLoggingFetchMiddleware registra cada requisição (URL, status e duração) em nível debug e as
+// falhas em nível warn
+func NewLoggingFetchMiddleware() FetchMiddleware {
+	log := logger.NewLogger("fetch")
+	return NewFetchMiddlewareFunc(FetchMiddlewareLogging, func(next http.RoundTripper) http.RoundTripper {
+		return FetchFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			fields := map[string]interface{}{
+				"method":      req.Method,
+				"url":         req.URL.String(),
+				"duration_ms": time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				log.WithFields(fields).WithError(err).Warn("Fetch failed")
+				return resp, err
+			}
+			fields["status"] = resp.StatusCode
+			if cache := resp.Header.Get(fetchCacheHeader); cache != "" {
+				fields["cache"] = cache
+			}
+			log.WithFields(fields).Debug("Fetched")
+			return resp, nil
+		})
+	})
+}
+
+// CacheFetchMiddleware guarda em memória as respostas 200 dos GET por TTL, descartando as menos
+// usadas acima de maxEntries. Respostas servidas do cache trazem o cabeçalho X-Fetch-Cache: HIT.
+type CacheFetchMiddleware struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // mais recente na frente
+	hits    int64
+	misses  int64
+}
+
+// fetchCacheEntry é uma resposta em cache
+type fetchCacheEntry struct {
+	key     string
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// NewCacheFetchMiddleware cria o cache (ttl <= 0 desativa; maxEntries <= 0 usa 1000)
+func NewCacheFetchMiddleware(ttl time.Duration, maxEntries int) *CacheFetchMiddleware {
+	if maxEntries <= 0 {
+		maxEntries = defaultFetchCacheSize
+	}
+	return &CacheFetchMiddleware{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Name retorna o nome do middleware
+func (m *CacheFetchMiddleware) Name() string { return FetchMiddlewareCache }
+
+// Stats retorna os acertos e as faltas do cache
+func (m *CacheFetchMiddleware) Stats() (hits, misses int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.hits, m.misses
+}
+
+// Wrap serve do cache os GET sem credenciais nem faixa de bytes
+func (m *CacheFetchMiddleware) Wrap(next http.RoundTripper) http.RoundTripper {
+	return FetchFunc(func(req *http.Request) (*http.Response, error) {
+		if m.ttl <= 0 || req.Method != http.MethodGet || req.Header.Get("Authorization") != "" || req.Header.Get("Range") != "" {
+			return next.RoundTrip(req)
+		}
+
+		key := req.URL.String()
+		if entry := m.get(key); entry != nil {
+			header := entry.header.Clone()
+			header.Set(fetchCacheHeader, fetchCacheHeaderHit)
+			return newFetchResponse(req, entry.status, header, entry.body), nil
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return resp, err
+		}
+		body, complete, err := bufferBody(resp, fetchCacheMaxBodyBytes)
+		if err != nil {
+			return nil, err
+		}
+		if complete {
+			m.put(&fetchCacheEntry{key: key, status: resp.StatusCode, header: resp.Header.Clone(), body: body, expires: m.now().Add(m.ttl)})
+		}
+		return resp, nil
+	})
+}
+
+// get retorna a entrada válida da chave, movendo-a para a frente
+func (m *CacheFetchMiddleware) get(key string) *fetchCacheEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	element, exists := m.entries[key]
+	if !exists {
+		m.misses++
+		return nil
+	}
+	entry := element.Value.(*fetchCacheEntry)
+	if !m.now().Before(entry.expires) {
+		m.order.Remove(element)
+		delete(m.entries, key)
+		m.misses++
+		return nil
+	}
+	m.order.MoveToFront(element)
+	m.hits++
+	return entry
+}
+
+// put guarda a entrada e descarta as menos usadas acima do limite
+func (m *CacheFetchMiddleware) put(entry *fetchCacheEntry) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if element, exists := m.entries[entry.key]; exists {
+		m.order.Remove(element)
+	}
+	m.entries[entry.key] = m.order.PushFront(entry)
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*fetchCacheEntry).key)
+	}
+}
+
+// RobotsFetchMiddleware recusa as URLs bloqueadas pelo robots.txt do site (regras do grupo
+// "User-agent: *", já que os engines sorteiam o User-Agent). O robots.txt de cada host é lido pelo
+// próximo transporte da cadeia e guardado por TTL; sem robots.txt legível tudo é permitido.
+type RobotsFetchMiddleware struct {
+	ttl    time.Duration
+	now    func() time.Time
+	logger *logger.Logger
+
+	mutex sync.Mutex
+	hosts map[string]*robotsRules
+}
+
+// robotsRules são as regras de um host
+type robotsRules struct {
+	rules   []robotsRule
+	expires time.Time
+}
+
+// robotsRule é uma linha Allow ou Disallow; a regra mais longa que casa com o caminho vence
+type robotsRule struct {
+	allow   bool
+	pattern *regexp.Regexp
+	length  int
+}
+
+// NewRobotsFetchMiddleware cria o middleware (ttl <= 0 usa 1h)
+func NewRobotsFetchMiddleware(ttl time.Duration) *RobotsFetchMiddleware {
+	if ttl <= 0 {
+		ttl = defaultRobotsTTL
+	}
+	return &RobotsFetchMiddleware{
+		ttl:    ttl,
+		now:    time.Now,
+		logger: logger.NewLogger("fetch_robots"),
+		hosts:  make(map[string]*robotsRules),
+	}
+}
+
+// Name retorna o nome do middleware
+func (m *RobotsFetchMiddleware) Name() string { return FetchMiddlewareRobots }
+
+// Wrap retorna ErrDisallowedByRobots para as URLs bloqueadas, sem chamar o próximo transporte
+func (m *RobotsFetchMiddleware) Wrap(next http.RoundTripper) http.RoundTripper {
+	return FetchFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/robots.txt" {
+			return next.RoundTrip(req)
+		}
+		path := req.URL.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if req.URL.RawQuery != "" {
+			path += "?" + req.URL.RawQuery
+		}
+		if !m.rulesFor(req, next).allowed(path) {
+			return nil, fmt.Errorf("%w: %s", ErrDisallowedByRobots, req.URL.String())
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+// rulesFor retorna as regras do host da requisição, lendo o robots.txt quando expiradas
+func (m *RobotsFetchMiddleware) rulesFor(req *http.Request, next http.RoundTripper) *robotsRules {
+	origin := req.URL.Scheme + "://" + strings.ToLower(req.URL.Host)
+
+	m.mutex.Lock()
+	rules, exists := m.hosts[origin]
+	m.mutex.Unlock()
+	if exists && m.now().Before(rules.expires) {
+		return rules
+	}
+
+	rules = &robotsRules{expires: m.now().Add(m.ttl)}
+	robotsReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, origin+"/robots.txt", nil)
+	if err == nil {
+		robotsReq.Header.Set("User-Agent", req.Header.Get("User-Agent"))
+		var resp *http.Response
+		if resp, err = next.RoundTrip(robotsReq); err == nil {
+			if resp.StatusCode == http.StatusOK {
+				rules.rules = parseRobotsRules(io.LimitReader(resp.Body, robotsMaxBytes))
+			}
+			resp.Body.Close()
+		}
+	}
+	if err != nil {
+		m.logger.WithField("origin", origin).WithError(err).Debug("Failed to read robots.txt, allowing all")
+	}
+
+	m.mutex.Lock()
+	m.hosts[origin] = rules
+	m.mutex.Unlock()
+	return rules
+}
+
+// allowed aplica a regra mais longa que casa com o caminho; no empate Allow vence
+func (r *robotsRules) allowed(path string) bool {
+	allowed, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allowed, length = rule.allow, rule.length
+		}
+	}
+	return allowed
+}
+
+// parseRobotsRules lê as regras Allow/Disallow dos grupos "User-agent: *"
+func parseRobotsRules(reader io.Reader) []robotsRule {
+	var rules []robotsRule
+	inGroup, lastWasAgent := false, false
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Linhas User-agent seguidas formam um mesmo grupo
+			if !lastWasAgent {
+				inGroup = false
+			}
+			inGroup = inGroup || value == "*"
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			if !inGroup || value == "" {
+				continue
+			}
+			rules = append(rules, robotsRule{
+				allow:   key == "allow",
+				pattern: robotsPattern(value),
+				length:  len(value),
+			})
+		default:
+			lastWasAgent = false
+		}
+	}
+	return rules
+}
+
+// robotsPattern converte o caminho da regra ("*" e "$" final) em expressão ancorada no início
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	if anchored {
+		pattern += "$"
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// RateLimitFetchMiddleware espaça as requisições ao mesmo host em pelo menos interval, somando
+// as de todos os engines e collectors (o limite do colly vale só dentro de cada collector)
+type RateLimitFetchMiddleware struct {
+	interval time.Duration
+
+	mutex sync.Mutex
+	next  map[string]time.Time
+}
+
+// NewRateLimitFetchMiddleware cria o middleware (interval <= 0 desativa)
+func NewRateLimitFetchMiddleware(interval time.Duration) *RateLimitFetchMiddleware {
+	return &RateLimitFetchMiddleware{interval: interval, next: make(map[string]time.Time)}
+}
+
+// Name retorna o nome do middleware
+func (m *RateLimitFetchMiddleware) Name() string { return FetchMiddlewareRateLimit }
+
+// Wrap aguarda a vez do host antes de chamar o próximo transporte
+func (m *RateLimitFetchMiddleware) Wrap(next http.RoundTripper) http.RoundTripper {
+	return FetchFunc(func(req *http.Request) (*http.Response, error) {
+		if m.interval <= 0 {
+			return next.RoundTrip(req)
+		}
+
+		host := strings.ToLower(req.URL.Host)
+		m.mutex.Lock()
+		at := time.Now()
+		if scheduled := m.next[host]; scheduled.After(at) {
+			at = scheduled
+		}
+		m.next[host] = at.Add(m.interval)
+		m.mutex.Unlock()
+
+		if wait := time.Until(at); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			}
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+// ProxyFetchMiddleware envia as requisições pelos proxies informados, em rodízio. Troca o
+// transporte da rede por cópias do transporte base (mantendo o cache de DNS) com o proxy fixo,
+// por isso precisa ser o último middleware da cadeia
+type ProxyFetchMiddleware struct {
+	proxies []*url.URL
+	counter uint64
+}
+
+// NewProxyFetchMiddleware cria o middleware a partir das URLs dos proxies (http, https ou socks5)
+func NewProxyFetchMiddleware(rawURLs []string) (*ProxyFetchMiddleware, error) {
+	middleware := &ProxyFetchMiddleware{}
+	for _, rawURL := range rawURLs {
+		if rawURL = strings.TrimSpace(rawURL); rawURL == "" {
+			continue
+		}
+		proxyURL, err := url.Parse(rawURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", rawURL)
+		}
+		middleware.proxies = append(middleware.proxies, proxyURL)
+	}
+	if len(middleware.proxies) == 0 {
+		return nil, fmt.Errorf("fetch middleware proxy requires FETCH_PROXY_URLS")
+	}
+	return middleware, nil
+}
+
+// Name retorna o nome do middleware
+func (m *ProxyFetchMiddleware) Name() string { return FetchMiddlewareProxy }
+
+// Addresses retorna host:porta de cada proxy, os únicos endereços fora do destino que o
+// diagnóstico de domínio aceita conectar
+func (m *ProxyFetchMiddleware) Addresses() []string {
+	defaultPorts := map[string]string{"http": "80", "https": "443", "socks5": "1080"}
+	addresses := make([]string, 0, len(m.proxies))
+	for _, proxyURL := range m.proxies {
+		port := proxyURL.Port()
+		if port == "" {
+			port = defaultPorts[proxyURL.Scheme]
+		}
+		addresses = append(addresses, net.JoinHostPort(proxyURL.Hostname(), port))
+	}
+	return addresses
+}
+
+// Wrap cria um transporte por proxy a partir de next (ou do transporte padrão, quando next não é
+// um *http.Transport)
+func (m *ProxyFetchMiddleware) Wrap(next http.RoundTripper) http.RoundTripper {
+	base, ok := next.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transports := make([]*http.Transport, len(m.proxies))
+	for i, proxyURL := range m.proxies {
+		transports[i] = base.Clone()
+		transports[i].Proxy = http.ProxyURL(proxyURL)
+	}
+	return FetchFunc(func(req *http.Request) (*http.Response, error) {
+		index := atomic.AddUint64(&m.counter, 1) - 1
+		return transports[index%uint64(len(transports))].RoundTrip(req)
+	})
+}
+
+// PageRenderer renderiza a página em um navegador headless e retorna o HTML final
+type PageRenderer interface {
+	Render(ctx context.Context, pageURL string) ([]byte, error)
+}
+
+// HTTPPageRenderer usa um serviço de renderização externo: GET <endpoint>?url=<página> retorna o
+// HTML depois da execução dos scripts (ex: browserless /content, Rendertron, Splash /render.html)
+type HTTPPageRenderer struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPPageRenderer cria o renderizador (timeout <= 0 usa 1min)
+func NewHTTPPageRenderer(endpoint string, timeout time.Duration) *HTTPPageRenderer {
+	if timeout <= 0 {
+		timeout = defaultHeadlessTimeout
+	}
+	return &HTTPPageRenderer{endpoint: endpoint, client: &http.Client{Timeout: timeout}}
+}
+
+// Render pede a página renderizada ao serviço
+func (r *HTTPPageRenderer) Render(ctx context.Context, pageURL string) ([]byte, error) {
+	separator := "?"
+	if strings.Contains(r.endpoint, "?") {
+		separator = "&"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.endpoint+separator+"url="+url.QueryEscape(pageURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create render request: %v", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render page: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("renderer returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, headlessMaxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered page: %v", err)
+	}
+	if len(body) > headlessMaxBodyBytes {
+		return nil, fmt.Errorf("rendered page exceeds %d bytes", headlessMaxBodyBytes)
+	}
+	return body, nil
+}
+
+// HeadlessFallbackFetchMiddleware troca as páginas HTML que dependem de JavaScript (quase sem
+// texto visível e com scripts) pela versão renderizada. Respostas renderizadas trazem o cabeçalho
+// X-Fetch-Rendered: headless; falhas do renderizador mantêm a resposta original.
+type HeadlessFallbackFetchMiddleware struct {
+	renderer PageRenderer
+	logger   *logger.Logger
+}
+
+// NewHeadlessFallbackFetchMiddleware cria o middleware com o renderizador informado
+func NewHeadlessFallbackFetchMiddleware(renderer PageRenderer) *HeadlessFallbackFetchMiddleware {
+	return &HeadlessFallbackFetchMiddleware{renderer: renderer, logger: logger.NewLogger("fetch_headless")}
+}
+
+// Name retorna o nome do middleware
+func (m *HeadlessFallbackFetchMiddleware) Name() string { return FetchMiddlewareHeadless }
+
+// Wrap avalia as respostas HTML 200 dos GET e renderiza as que dependem de JavaScript
+func (m *HeadlessFallbackFetchMiddleware) Wrap(next http.RoundTripper) http.RoundTripper {
+	return FetchFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || m.renderer == nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK ||
+			!strings.Contains(resp.Header.Get("Content-Type"), "html") {
+			return resp, err
+		}
+
+		body, complete, err := bufferBody(resp, headlessMaxBodyBytes)
+		if err != nil || !complete || !needsRendering(body) {
+			return resp, err
+		}
+
+		rendered, err := m.renderer.Render(req.Context(), req.URL.String())
+		if err != nil {
+			m.logger.WithField("url", req.URL.String()).WithError(err).Warn("Headless rendering failed, keeping original page")
+			return resp, nil
+		}
+		resp.Header.Del("Content-Length")
+		resp.Header.Set(fetchRenderedHeader, fetchRenderedByHeadless)
+		resp.Body = io.NopCloser(bytes.NewReader(rendered))
+		resp.ContentLength = int64(len(rendered))
+		return resp, nil
+	})
+}
+
+var (
+	headlessHiddenPattern = regexp.MustCompile(`(?is)<(script|style|noscript|template)\b.*?</(?:script|style|noscript|template)>`)
+	headlessTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	headlessNoScriptHints = []string{"enable javascript", "habilite o javascript", "ative o javascript", "javascript is required", "requires javascript"}
+)
+
+// needsRendering indica uma página montada por JavaScript: com scripts e quase sem texto
+// visível, ou com aviso para habilitar o JavaScript
+func needsRendering(body []byte) bool {
+	lower := strings.ToLower(string(body))
+	if !strings.Contains(lower, "<script") {
+		return false
+	}
+	for _, hint := range headlessNoScriptHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+
+	text := headlessTagPattern.ReplaceAllString(headlessHiddenPattern.ReplaceAllString(string(body), " "), " ")
+	visible := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			visible++
+			if visible >= headlessMinTextRunes {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fetchBody junta o início já lido do corpo com o restante ainda na rede
+type fetchBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bufferBody lê o corpo da resposta até limit bytes e o recoloca na resposta. complete é falso
+// quando o corpo passa do limite: o início lido é devolvido e o restante continua na rede
+func bufferBody(resp *http.Response, limit int) (body []byte, complete bool, err error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, false, fmt.Errorf("failed to read response body: %v", err)
+	}
+	if len(data) > limit {
+		resp.Body = fetchBody{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), Closer: resp.Body}
+		return data, false, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return data, true, nil
+}
+
+// newFetchResponse cria a resposta servida por um middleware sem acesso à rede
+func newFetchResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
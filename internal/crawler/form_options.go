@@ -0,0 +1,135 @@
+package crawler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxFormOptionURLs limita as URLs geradas a partir das opções reais de um formulário
+// (listas de bairros podem ter centenas de entradas)
+const maxFormOptionURLs = 100
+
+// operationFieldNames identificam o campo de operação (venda/aluguel), combinado com os demais
+var operationFieldNames = []string{"operacao", "finalidade", "negocio", "transacao", "modalidade", "operation", "purpose"}
+
+// placeholderOptionTexts identificam a opção inicial sem valor real ("Selecione a cidade")
+var placeholderOptionTexts = []string{"selecione", "escolha", "select", "choose"}
+
+// fieldOptionValues retorna os valores reais aceitos pelo campo: as opções de um <select> ou as
+// entradas do <datalist> referenciado pelo atributo list de um <input>. Opções desabilitadas,
+// vazias e de placeholder são ignoradas.
+func fieldOptionValues(field *goquery.Selection) []string {
+	var options *goquery.Selection
+	switch {
+	case goquery.NodeName(field) == "select":
+		options = field.Find("option")
+	case field.AttrOr("list", "") != "":
+		listID := field.AttrOr("list", "")
+		root := field.Parents().Last()
+		if root.Length() == 0 {
+			return nil
+		}
+		options = root.Find("datalist").FilterFunction(func(i int, s *goquery.Selection) bool {
+			return s.AttrOr("id", "") == listID
+		}).First().Find("option")
+	default:
+		return nil
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	options.Each(func(i int, option *goquery.Selection) {
+		if _, disabled := option.Attr("disabled"); disabled {
+			return
+		}
+		text := strings.TrimSpace(option.Text())
+		value, hasValue := option.Attr("value")
+		if !hasValue {
+			value = text
+		}
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] || isPlaceholderOption(value) || (!hasValue && isPlaceholderOption(text)) {
+			return
+		}
+		seen[value] = true
+		values = append(values, value)
+	})
+	return values
+}
+
+// isPlaceholderOption informa se o texto é de uma opção de placeholder
+func isPlaceholderOption(text string) bool {
+	text = strings.ToLower(text)
+	for _, placeholder := range placeholderOptionTexts {
+		if strings.HasPrefix(text, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOperationField informa se o campo escolhe a operação (venda/aluguel)
+func isOperationField(name string) bool {
+	name = strings.ToLower(name)
+	for _, operation := range operationFieldNames {
+		if strings.Contains(name, operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// formOptionParams gera as combinações de parâmetros a partir das opções reais do formulário:
+// o formulário sem filtros, cada valor de cada campo sozinho e, quando há um campo de operação,
+// cada operação combinada com os valores dos demais campos. O total é limitado a maxFormOptionURLs.
+func formOptionParams(options map[string][]string) []map[string]string {
+	if len(options) == 0 {
+		return nil
+	}
+
+	// Campo de operação primeiro, os demais em ordem alfabética, para um resultado estável
+	fields := make([]string, 0, len(options))
+	for name := range options {
+		fields = append(fields, name)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if opI, opJ := isOperationField(fields[i]), isOperationField(fields[j]); opI != opJ {
+			return opI
+		}
+		return fields[i] < fields[j]
+	})
+
+	params := []map[string]string{{}}
+	add := func(p map[string]string) bool {
+		if len(params) >= maxFormOptionURLs {
+			return false
+		}
+		params = append(params, p)
+		return true
+	}
+
+	for _, name := range fields {
+		for _, value := range options[name] {
+			if !add(map[string]string{name: value}) {
+				return params
+			}
+		}
+	}
+
+	operationField := fields[0]
+	if !isOperationField(operationField) {
+		return params
+	}
+	for _, operation := range options[operationField] {
+		for _, name := range fields[1:] {
+			for _, value := range options[name] {
+				if !add(map[string]string{operationField: operation, name: value}) {
+					return params
+				}
+			}
+		}
+	}
+	return params
+}
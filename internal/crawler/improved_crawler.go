@@ -3,13 +3,11 @@ package crawler
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
 	"github.com/dujoseaugusto/go-crawler-project/internal/config"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
@@ -21,7 +19,9 @@ import (
 type ImprovedCrawler struct {
 	config             *config.Config
 	repo               repository.PropertyRepository
-	aiService          *ai.GeminiService
+	aiService          PropertyAIProvider
+	clock              Clock
+	calibrator         *ConfidenceCalibrator
 	referenceTrainer   *ReferencePatternTrainer
 	patternValidator   *PatternValidator
 	enhancedExtractor  *EnhancedExtractor
@@ -30,6 +30,8 @@ type ImprovedCrawler struct {
 	logger             *logger.Logger
 	collector          *colly.Collector
 	detailCollector    *colly.Collector
+	mainTuner          *ConcurrencyTuner
+	detailTuner        *ConcurrencyTuner
 	visitedURLs        map[string]bool
 	visitedMutex       sync.Mutex
 	stats              *ImprovedCrawlerStats
@@ -38,30 +40,24 @@ type ImprovedCrawler struct {
 
 // ImprovedCrawlerStats mantém estatísticas do crawler melhorado
 type ImprovedCrawlerStats struct {
-	PagesVisited      int            `json:"pages_visited"`
-	PropertiesFound   int            `json:"properties_found"`
-	PropertiesSaved   int            `json:"properties_saved"`
-	CatalogPagesFound int            `json:"catalog_pages_found"`
-	ErrorsEncountered int            `json:"errors_encountered"`
-	StartTime         time.Time      `json:"start_time"`
-	LastUpdate        time.Time      `json:"last_update"`
-	AverageConfidence float64        `json:"average_confidence"`
-	DomainStats       map[string]int `json:"domain_stats"`
+	PagesVisited      int                      `json:"pages_visited"`
+	PropertiesFound   int                      `json:"properties_found"`
+	PropertiesSaved   int                      `json:"properties_saved"`
+	CatalogPagesFound int                      `json:"catalog_pages_found"`
+	ErrorsEncountered int                      `json:"errors_encountered"`
+	StartTime         time.Time                `json:"start_time"`
+	LastUpdate        time.Time                `json:"last_update"`
+	AverageConfidence float64                  `json:"average_confidence"`
+	DomainStats       map[string]int           `json:"domain_stats"`
+	Concurrency       []DomainConcurrencyStats `json:"concurrency,omitempty"`
 	mutex             sync.RWMutex
 }
 
-// NewImprovedCrawler cria um novo crawler melhorado
-func NewImprovedCrawler(ctx context.Context, cfg *config.Config) *ImprovedCrawler {
-	// Inicializa repositório
-	repo, err := repository.NewMongoRepository(cfg.MongoURI, "crawler", "properties")
-	if err != nil {
-		log.Fatalf("Failed to create MongoDB repository: %v", err)
-	}
-
-	// Inicializa serviço de IA
-	aiService, err := ai.NewGeminiService(ctx)
-	if err != nil {
-		log.Printf("Warning: Failed to create Gemini service: %v", err)
+// NewImprovedCrawler cria um novo crawler melhorado com as dependências informadas
+// (ver NewCrawlerDependencies para a configuração padrão com MongoDB e Gemini)
+func NewImprovedCrawler(cfg *config.Config, deps CrawlerDependencies) (*ImprovedCrawler, error) {
+	if err := deps.validate(); err != nil {
+		return nil, err
 	}
 
 	// Inicializa componentes de aprendizado
@@ -84,23 +80,20 @@ func NewImprovedCrawler(ctx context.Context, cfg *config.Config) *ImprovedCrawle
 	extensions.Referer(mainCollector)
 	extensions.RandomUserAgent(detailCollector)
 	extensions.Referer(detailCollector)
+	deps.applyTransport(mainCollector, detailCollector)
 
-	mainCollector.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		Parallelism: 2,
-		Delay:       1 * time.Second,
-	})
-
-	detailCollector.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		Parallelism: 1,
-		Delay:       2 * time.Second,
-	})
+	// Paralelismo por domínio (configurável e com ajuste automático) e pausa entre requisições
+	mainTuner, detailTuner, err := attachConcurrencyTuners(cfg, mainCollector, detailCollector, 1*time.Second, 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ImprovedCrawler{
 		config:             cfg,
-		repo:               repo,
-		aiService:          aiService,
+		repo:               deps.Repository,
+		aiService:          deps.AI,
+		clock:              deps.Clock,
+		calibrator:         deps.Calibrator,
 		referenceTrainer:   referenceTrainer,
 		patternValidator:   patternValidator,
 		enhancedExtractor:  enhancedExtractor,
@@ -108,13 +101,15 @@ func NewImprovedCrawler(ctx context.Context, cfg *config.Config) *ImprovedCrawle
 		contentLearner:     contentLearner,
 		logger:             logger.NewLogger("improved_crawler"),
 		collector:          mainCollector,
+		mainTuner:          mainTuner,
+		detailTuner:        detailTuner,
 		detailCollector:    detailCollector,
 		visitedURLs:        make(map[string]bool),
 		stats: &ImprovedCrawlerStats{
-			StartTime:   time.Now(),
+			StartTime:   deps.Clock.Now(),
 			DomainStats: make(map[string]int),
 		},
-	}
+	}, nil
 }
 
 // TrainFromReferenceFile treina o crawler usando arquivo de referência
@@ -148,13 +143,14 @@ func (ic *ImprovedCrawler) TrainFromReferenceFile(ctx context.Context, reference
 
 // StartCrawling inicia o processo de crawling melhorado
 func (ic *ImprovedCrawler) StartCrawling(ctx context.Context) error {
+	ic.logger.Warn(SitesFileDeprecationWarning)
 	urls, err := LoadURLsFromFile(ic.config.SitesFile)
 	if err != nil {
 		return fmt.Errorf("failed to load URLs: %w", err)
 	}
 
 	ic.logger.WithField("url_count", len(urls)).Info("Starting improved crawling")
-	ic.stats.StartTime = time.Now()
+	ic.stats.StartTime = ic.clock.Now()
 
 	// Configura handlers do crawler
 	ic.setupCrawlerHandlers(ctx)
@@ -187,34 +183,38 @@ func (ic *ImprovedCrawler) StartCrawling(ctx context.Context) error {
 
 // setupCrawlerHandlers configura os handlers do crawler
 func (ic *ImprovedCrawler) setupCrawlerHandlers(ctx context.Context) {
+	// Callbacks protegidos: um panic em uma página conta como erro e o crawling continua
+	recovery := NewCallbackRecovery("improved_crawler", nil)
+	recovery.SetPanicHook(func(rawURL string) { ic.updateStats("error", rawURL) })
+
 	// Handler para encontrar links de propriedades
-	ic.collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	ic.collector.OnHTML("a[href]", recovery.HTML(func(e *colly.HTMLElement) {
 		ic.handlePropertyLink(e)
-	})
+	}))
 
 	// Handler principal para páginas de detalhes
-	ic.detailCollector.OnHTML("body", func(e *colly.HTMLElement) {
+	ic.detailCollector.OnHTML("body", recovery.HTML(func(e *colly.HTMLElement) {
 		ic.handlePropertyPage(ctx, e)
-	})
+	}))
 
 	// Handlers de log
-	ic.collector.OnRequest(func(r *colly.Request) {
+	ic.collector.OnRequest(recovery.Request(func(r *colly.Request) {
 		ic.logger.WithField("url", r.URL.String()).Debug("Visiting listing page")
-	})
+	}))
 
-	ic.detailCollector.OnRequest(func(r *colly.Request) {
+	ic.detailCollector.OnRequest(recovery.Request(func(r *colly.Request) {
 		ic.logger.WithField("url", r.URL.String()).Debug("Visiting property page")
-	})
+	}))
 
-	ic.collector.OnError(func(r *colly.Response, err error) {
+	ic.collector.OnError(recovery.Error(func(r *colly.Response, err error) {
 		ic.logger.WithField("url", r.Request.URL.String()).Error("Error visiting listing page", err)
 		ic.updateStats("error", r.Request.URL.String())
-	})
+	}))
 
-	ic.detailCollector.OnError(func(r *colly.Response, err error) {
+	ic.detailCollector.OnError(recovery.Error(func(r *colly.Response, err error) {
 		ic.logger.WithField("url", r.Request.URL.String()).Error("Error visiting property page", err)
 		ic.updateStats("error", r.Request.URL.String())
-	})
+	}))
 }
 
 // handlePropertyLink processa links encontrados em páginas de listagem
@@ -229,6 +229,9 @@ func (ic *ImprovedCrawler) handlePropertyLink(e *colly.HTMLElement) {
 
 	// Usa padrões aprendidos para verificar se é link de propriedade
 	matchedPattern, confidence := ic.referenceTrainer.MatchURL(absoluteLink)
+	if matchedPattern != nil {
+		confidence = ic.calibrator.Score(CalibrationSourcePattern, absoluteLink, confidence, true)
+	}
 
 	isPropertyLink := false
 
@@ -261,6 +264,7 @@ func (ic *ImprovedCrawler) handlePropertyPage(ctx context.Context, e *colly.HTML
 
 	// Verifica também com classificador baseado em conteúdo
 	contentType, contentConfidence := ic.contentLearner.ClassifyPageContent(e)
+	contentConfidence = ic.calibrator.Score(CalibrationSourceContent, url, contentConfidence, contentType == "property")
 
 	ic.logger.WithFields(map[string]interface{}{
 		"url":                url,
@@ -270,23 +274,34 @@ func (ic *ImprovedCrawler) handlePropertyPage(ctx context.Context, e *colly.HTML
 	}).Debug("Page classification completed")
 
 	// Decide se deve processar como propriedade individual
-	shouldProcess := false
-
-	if pageType == PageTypeProperty && contentType == "property" {
-		shouldProcess = true
-	} else if pageType == PageTypeProperty || (contentType == "property" && contentConfidence > 0.7) {
-		shouldProcess = true
-	} else if pageType == PageTypeCatalog || contentType == "catalog" {
+	switch improvedPageAction(pageType, contentType, contentConfidence) {
+	case pageActionProperty:
+		ic.processPropertyPage(ctx, e, url)
+	case pageActionCatalog:
 		ic.logger.WithField("url", url).Debug("Page identified as catalog, extracting individual properties")
 		ic.handleCatalogPage(ctx, e)
-		return
+	default:
+		ic.logger.WithField("url", url).Debug("Page not identified as property, skipping")
 	}
+}
 
-	if shouldProcess {
-		ic.processPropertyPage(ctx, e, url)
-	} else {
-		ic.logger.WithField("url", url).Debug("Page not identified as property, skipping")
+// Ações possíveis para uma página de detalhes
+const (
+	pageActionProperty = "property" // extrair e salvar o imóvel
+	pageActionCatalog  = "catalog"  // extrair os imóveis da listagem
+	pageActionSkip     = "skip"
+)
+
+// improvedPageAction combina o classificador rigoroso e o de conteúdo: propriedade quando um deles
+// tem certeza, catálogo quando algum indica listagem
+func improvedPageAction(pageType PageType, contentType string, contentConfidence float64) string {
+	if pageType == PageTypeProperty || (contentType == "property" && contentConfidence > 0.7) {
+		return pageActionProperty
 	}
+	if pageType == PageTypeCatalog || contentType == "catalog" {
+		return pageActionCatalog
+	}
+	return pageActionSkip
 }
 
 // processPropertyPage processa uma página de propriedade individual
@@ -416,21 +431,12 @@ func (ic *ImprovedCrawler) extractPropertiesFromCatalogContent(ctx context.Conte
 
 // isLikelyPropertyURL verifica se uma URL parece ser de uma propriedade individual
 func (ic *ImprovedCrawler) isLikelyPropertyURL(url string) bool {
-	urlLower := strings.ToLower(url)
-
-	// Padrões que indicam propriedade individual
-	propertyPatterns := []string{
-		"/imovel/", "/propriedade/", "/anuncio/", "/detalhes/",
-		"/casa/", "/apartamento/", "/ref-", "/codigo-", "/id-",
-	}
-
-	for _, pattern := range propertyPatterns {
-		if strings.Contains(urlLower, pattern) {
-			return true
-		}
-	}
+	return len(matchPropertyURLPatterns(url)) > 0 || hasNumericLastSegment(url)
+}
 
-	// Verifica se termina com número (comum em URLs de propriedade)
+// hasNumericLastSegment verifica se a URL termina com um trecho numérico (comum em URLs de propriedade)
+func hasNumericLastSegment(url string) bool {
+	urlLower := strings.ToLower(url)
 	if strings.Contains(urlLower, "/") {
 		parts := strings.Split(urlLower, "/")
 		lastPart := parts[len(parts)-1]
@@ -438,7 +444,6 @@ func (ic *ImprovedCrawler) isLikelyPropertyURL(url string) bool {
 			return true
 		}
 	}
-
 	return false
 }
 
@@ -476,7 +481,7 @@ func (ic *ImprovedCrawler) updateStats(statType, rawURL string) {
 	ic.stats.mutex.Lock()
 	defer ic.stats.mutex.Unlock()
 
-	ic.stats.LastUpdate = time.Now()
+	ic.stats.LastUpdate = ic.clock.Now()
 
 	switch statType {
 	case "page_visited":
@@ -513,6 +518,7 @@ func (ic *ImprovedCrawler) GetStats() *ImprovedCrawlerStats {
 		ErrorsEncountered: ic.stats.ErrorsEncountered,
 		StartTime:         ic.stats.StartTime,
 		LastUpdate:        ic.stats.LastUpdate,
+		Concurrency:       collectorConcurrency(ic.mainTuner, ic.detailTuner),
 		DomainStats:       make(map[string]int),
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/gocolly/colly"
@@ -20,46 +21,73 @@ type IncrementalCrawlerEngine struct {
 	aiService         *ai.GeminiService
 	extractor         *DataExtractor
 	validator         *PropertyValidator
+	multiUnit         *MultiUnitDetector
 	urlManager        *PersistentURLManager
 	smartClassifier   *SmartPageClassifier
 	contentClassifier *ContentBasedClassifier
 	preciseClassifier *PrecisePropertyClassifier
 	navigationManager *SmartNavigationManager
+	runtime           *RuntimeState
+	apiFetcher        *APIFetcher
 	collector         *colly.Collector
 	logger            *logger.Logger
 	config            IncrementalConfig
 	stats             *IncrementalStats
+	errors            *ErrorTracker
+	recovery          *CallbackRecovery
+	deadFilters       *DeadFilterTracker
+	limits            PageLimits // timeout por requisição e aborto de páginas lentas ou grandes
+	control           *JobControl
+	frontier          *SharedFrontier // registro de URLs em andamento compartilhado com jobs simultâneos
+
+	crawlLifecycle
 }
 
 // IncrementalConfig configurações para o crawler incremental
 type IncrementalConfig struct {
 	EnableAI             bool          `json:"enable_ai"`
+	EnableTranslation    bool          `json:"enable_translation"`
+	EnableConditionScore bool          `json:"enable_condition_score"`
 	EnableFingerprinting bool          `json:"enable_fingerprinting"`
-	MaxAge               time.Duration `json:"max_age"`
+	MaxAge               time.Duration `json:"max_age"`              // intervalo inicial de revisita
+	MinRevisitInterval   time.Duration `json:"min_revisit_interval"` // páginas que mudam a cada visita (0 usa MaxAge)
+	MaxRevisitInterval   time.Duration `json:"max_revisit_interval"` // páginas estáticas (0 usa 7 dias)
 	AIThreshold          time.Duration `json:"ai_threshold"`
 	CleanupInterval      time.Duration `json:"cleanup_interval"`
 	MaxConcurrency       int           `json:"max_concurrency"`
 	DelayBetweenRequests time.Duration `json:"delay_between_requests"`
 	UserAgent            string        `json:"user_agent"`
+	PagesPerMinute       int           `json:"pages_per_minute"`       // orçamento global (0 desativa)
+	MaxDomainParallelism int           `json:"max_domain_parallelism"` // teto do paralelismo ajustado por domínio
 }
 
 // IncrementalStats estatísticas do crawling incremental
 type IncrementalStats struct {
-	StartTime           time.Time     `json:"start_time"`
-	EndTime             time.Time     `json:"end_time"`
-	TotalURLs           int           `json:"total_urls"`
-	ProcessedURLs       int           `json:"processed_urls"`
-	SkippedURLs         int           `json:"skipped_urls"`
-	NewProperties       int           `json:"new_properties"`
-	UpdatedProperties   int           `json:"updated_properties"`
-	FailedURLs          int           `json:"failed_urls"`
-	AIProcessingCount   int           `json:"ai_processing_count"`
-	AISkippedCount      int           `json:"ai_skipped_count"`
-	FingerprintHits     int           `json:"fingerprint_hits"`
-	FingerprintMisses   int           `json:"fingerprint_misses"`
-	ContentChanges      int           `json:"content_changes"`
-	ProcessingTimeTotal time.Duration `json:"processing_time_total"`
-	AISavingsEstimate   time.Duration `json:"ai_savings_estimate"`
+	StartTime           time.Time      `json:"start_time"`
+	EndTime             time.Time      `json:"end_time"`
+	TotalURLs           int            `json:"total_urls"`
+	ProcessedURLs       int            `json:"processed_urls"`
+	SkippedURLs         int            `json:"skipped_urls"`
+	NewProperties       int            `json:"new_properties"`
+	UpdatedProperties   int            `json:"updated_properties"`
+	FailedURLs          int            `json:"failed_urls"`
+	AIProcessingCount   int            `json:"ai_processing_count"`
+	AISkippedCount      int            `json:"ai_skipped_count"`
+	TranslatedCount     int            `json:"translated_count"`
+	ConditionScored     int            `json:"condition_scored"`
+	FingerprintHits     int            `json:"fingerprint_hits"`
+	FingerprintMisses   int            `json:"fingerprint_misses"`
+	ContentChanges      int            `json:"content_changes"`
+	ErrorsByType        map[string]int `json:"errors_by_type,omitempty"`
+	MaterialChanges     int            `json:"material_changes"`
+	CosmeticChanges     int            `json:"cosmetic_changes"`
+	ProcessingTimeTotal time.Duration  `json:"processing_time_total"`
+	AISavingsEstimate   time.Duration  `json:"ai_savings_estimate"`
+	DeadFilters         int            `json:"dead_filters"`   // combinações de filtros sem resultados marcadas
+	PrunedFilters       int            `json:"pruned_filters"` // URLs geradas descartadas por combinações sem resultados
+
+	// Páginas visitadas e anúncios encontrados por origem de descoberta em cada domínio
+	DiscoverySources map[string]map[string]repository.SourceYield `json:"discovery_sources,omitempty"`
 }
 
 // NewIncrementalCrawlerEngine cria um novo engine de crawling incremental
@@ -114,6 +142,8 @@ func NewIncrementalCrawlerEngineWithContentLearner(
 	// Configuração do URL Manager persistente
 	urlManagerConfig := PersistentURLConfig{
 		MaxAge:               config.MaxAge,
+		MinRevisitInterval:   config.MinRevisitInterval,
+		MaxRevisitInterval:   config.MaxRevisitInterval,
 		CleanupInterval:      config.CleanupInterval,
 		EnableFingerprinting: config.EnableFingerprinting,
 		AIThreshold:          config.AIThreshold,
@@ -121,6 +151,15 @@ func NewIncrementalCrawlerEngineWithContentLearner(
 
 	// Cria URL Manager simplificado
 	urlManager := NewPersistentURLManager(urlRepo, urlManagerConfig)
+	tracker := NewErrorTracker()
+
+	// Orçamento global de páginas por minuto (paralelismo por domínio ajustado dinamicamente)
+	runtime := NewRuntimeState()
+	if config.PagesPerMinute > 0 {
+		runtime.domainThrottle().SetGlobalRateController(NewGlobalRateController(config.PagesPerMinute, config.MaxDomainParallelism))
+	}
+	navigationManager := NewSmartNavigationManager()
+	navigationManager.SetRuntime(runtime)
 
 	return &IncrementalCrawlerEngine{
 		repository:        propertyRepo,
@@ -128,15 +167,74 @@ func NewIncrementalCrawlerEngineWithContentLearner(
 		aiService:         aiService,
 		extractor:         NewDataExtractor(),
 		validator:         NewPropertyValidator(),
+		multiUnit:         NewMultiUnitDetector(),
 		urlManager:        urlManager,
 		smartClassifier:   nil,                            // Será inicializado quando necessário
 		contentClassifier: nil,                            // Será inicializado quando necessário
 		preciseClassifier: NewPrecisePropertyClassifier(), // Classificador rigoroso sempre ativo
-		navigationManager: NewSmartNavigationManager(),    // Gerenciador de navegação inteligente
+		navigationManager: navigationManager,              // Gerenciador de navegação inteligente
+		runtime:           runtime,
+		apiFetcher:        NewAPIFetcher(runtime, navigationManager.Discovery()),
 		logger:            logger.NewLogger("incremental_crawler"),
 		config:            config,
 		stats:             &IncrementalStats{},
+		errors:            tracker,
+		recovery:          NewCallbackRecovery("incremental_crawler", tracker),
+		limits:            DefaultPageLimits(),
+		control:           NewJobControl(),
+	}
+}
+
+// SetJobControl associa o controle de cancelamento/pausa e o orçamento do job em execução
+func (ice *IncrementalCrawlerEngine) SetJobControl(control *JobControl) {
+	if control != nil {
+		ice.control = control
+		ice.recovery.SetJobControl(control)
+	}
+}
+
+// SetSharedFrontier ativa o registro compartilhado de URLs em andamento: URLs buscadas por
+// outro job simultâneo são puladas
+func (ice *IncrementalCrawlerEngine) SetSharedFrontier(frontier *SharedFrontier) {
+	ice.frontier = frontier
+}
+
+// SetDomainThrottle compartilha o limitador por domínio (e o atraso aprendido com 429/503) entre crawlings
+func (ice *IncrementalCrawlerEngine) SetDomainThrottle(throttle *DomainThrottle) {
+	ice.runtime.SetThrottle(throttle)
+}
+
+// ApplyRuntimeConfig aplica configuração recarregada sem interromper o crawling
+func (ice *IncrementalCrawlerEngine) ApplyRuntimeConfig(snapshot *config.RuntimeSnapshot) {
+	ice.runtime.Set(snapshot)
+	if threshold := ice.runtime.AIThreshold(); threshold > 0 {
+		ice.urlManager.SetAIThreshold(threshold)
 	}
+	if snapshot != nil {
+		ice.logger.WithField("version", snapshot.Version).Info("Runtime config applied")
+	}
+}
+
+// RegisterShutdown registra as etapas de encerramento coordenado deste engine
+func (ice *IncrementalCrawlerEngine) RegisterShutdown(sc *ShutdownCoordinator, checkpointPath string) func() {
+	var aiService pendingAIFlusher
+	if ice.config.EnableAI && ice.aiService != nil {
+		aiService = ice.aiService
+	}
+
+	return registerCrawlShutdown(sc, "incremental", &ice.crawlLifecycle, aiService, ice.repository, func() CrawlCheckpoint {
+		stats := ice.GetStatistics()
+		return CrawlCheckpoint{
+			PendingURLs: ice.PendingSeeds(),
+			VisitedURLs: ice.urlManager.GetVisitedCount(),
+			Stats: map[string]interface{}{
+				"processed_urls": stats.ProcessedURLs,
+				"skipped_urls":   stats.SkippedURLs,
+				"failed_urls":    stats.FailedURLs,
+				"new_properties": stats.NewProperties,
+			},
+		}
+	}, checkpointPath)
 }
 
 // InitializeSmartClassifier inicializa o classificador inteligente com URLs de referência
@@ -176,6 +274,7 @@ func (ice *IncrementalCrawlerEngine) InitializeContentClassifier(referenceURLs [
 
 // Start inicia o crawling incremental
 func (ice *IncrementalCrawlerEngine) Start(ctx context.Context, urls []string) error {
+	ctx = ice.control.WithAIUsage(ctx)
 	ice.stats.StartTime = time.Now()
 	ice.stats.TotalURLs = len(urls)
 
@@ -184,6 +283,8 @@ func (ice *IncrementalCrawlerEngine) Start(ctx context.Context, urls []string) e
 		"enable_ai":             ice.config.EnableAI,
 		"enable_fingerprinting": ice.config.EnableFingerprinting,
 		"max_age":               ice.config.MaxAge,
+		"min_revisit_interval":  ice.config.MinRevisitInterval,
+		"max_revisit_interval":  ice.config.MaxRevisitInterval,
 		"ai_threshold":          ice.config.AIThreshold,
 	}).Info("Starting incremental crawling")
 
@@ -193,16 +294,42 @@ func (ice *IncrementalCrawlerEngine) Start(ctx context.Context, urls []string) e
 		return fmt.Errorf("failed to load visited URLs: %v", err)
 	}
 
+	// Combinações de filtros sem resultados de crawlings anteriores
+	if err := ice.deadFilters.Load(ctx); err != nil {
+		ice.logger.Warnf("Failed to load dead filter combinations: %v", err)
+	}
+
 	// Limpa registros antigos se necessário
 	if err := ice.urlManager.CleanupOldRecords(ctx); err != nil {
 		ice.logger.Warnf("Failed to cleanup old records: %v", err)
 	}
 
+	ice.beginCrawl(urls)
+	defer ice.endCrawl()
+
+	// No modo estrito, os domínios das seeds são os únicos permitidos
+	ice.runtime.AllowSeeds(urls)
+
 	// Configura o collector
 	ice.collector = ice.setupCollector()
 
 	// Processa cada URL
 	for _, url := range urls {
+		if ice.IntakeStopped() || ice.control.Cancelled() {
+			ice.logger.WithField("pending_urls", len(ice.PendingSeeds())).Info("Intake stopped, skipping remaining seed URLs")
+			break
+		}
+
+		// Domínios com API JSON configurada são lidos direto da API, sem crawling HTML
+		if apiConfig := ice.runtime.APIConfig(url); apiConfig != nil {
+			ice.markSeedStarted(url)
+			if ice.runtime.InCrawlWindow(url) {
+				ice.crawlAPI(ctx, url, apiConfig)
+			}
+			continue
+		}
+
+		ice.markSeedStarted(url)
 		if err := ice.processURL(ctx, ice.collector, url); err != nil {
 			ice.logger.WithField("url", url).Error("Failed to process URL", err)
 			ice.stats.FailedURLs++
@@ -212,16 +339,88 @@ func (ice *IncrementalCrawlerEngine) Start(ctx context.Context, urls []string) e
 	// Aguarda conclusão
 	ice.collector.Wait()
 
+	// Visitar URLs adiadas de domínios que foram retomados durante a execução; o collector já as
+	// marcou como visitadas, então elas vão por um collector novo
+	for resumable := ice.control.TakeResumable(); len(resumable) > 0 && !ice.IntakeStopped(); resumable = ice.control.TakeResumable() {
+		ice.logger.WithField("urls", len(resumable)).Info("Visiting URLs deferred by paused domains")
+		ice.collector = ice.setupCollector()
+		for _, url := range resumable {
+			ice.collector.Visit(url)
+		}
+		ice.collector.Wait()
+	}
+	if deferred := ice.control.DeferredCount(); deferred > 0 {
+		ice.logger.WithFields(map[string]interface{}{
+			"deferred_urls":  deferred,
+			"paused_domains": ice.control.PausedDomains(),
+		}).Warn("Crawling finished with domains still paused")
+	}
+
 	// Finaliza estatísticas
 	ice.stats.EndTime = time.Now()
 	ice.stats.ProcessingTimeTotal = ice.stats.EndTime.Sub(ice.stats.StartTime)
 
 	// Log das estatísticas finais
 	ice.logFinalStatistics()
+	logRejectedDomains(ice.logger, ice.runtime.Allowlist())
+	logCrawlWindowDeferrals(ice.logger, ice.runtime)
 
 	return nil
 }
 
+// crawlAPI lê os anúncios da API JSON do domínio, sem extração de HTML
+func (ice *IncrementalCrawlerEngine) crawlAPI(ctx context.Context, seedURL string, apiConfig *repository.DomainAPIConfig) {
+	count, err := ice.apiFetcher.Fetch(ctx, seedURL, apiConfig, func(property *repository.Property) bool {
+		if ice.IntakeStopped() {
+			return false
+		}
+		ice.saveAPIProperty(ctx, property)
+		return true
+	})
+	if err != nil {
+		ice.logger.WithField("url", seedURL).WithError(err).Warn("Listing API crawl failed")
+		ice.errors.RecordError(ErrorTypeFetch, seedURL, err)
+		ice.stats.FailedURLs++
+		return
+	}
+
+	ice.logger.WithFields(map[string]interface{}{
+		"url":        seedURL,
+		"properties": count,
+	}).Info("Listing API crawl completed")
+}
+
+// saveAPIProperty aplica o pós-processamento do domínio, valida e grava um imóvel lido da API
+func (ice *IncrementalCrawlerEngine) saveAPIProperty(ctx context.Context, property *repository.Property) {
+	url := property.URL
+	if _, err := ice.runtime.PostProcess(property); err != nil {
+		ice.logger.WithField("url", url).WithError(err).Warn("Post-process script failed")
+		ice.errors.Record(ErrorTypeParse, url, err.Error(), 0)
+	}
+
+	validation := ice.validator.ValidateProperty(property)
+	if !validation.IsValid {
+		ice.errors.Record(ErrorTypeValidation, url, strings.Join(validation.Errors, "; "), 0)
+		ice.urlManager.MarkURLProcessed(ctx, url, "failed", "validation failed")
+		return
+	}
+	property = ice.validator.EnhanceProperty(property)
+	ice.navigationManager.Discovery().Apply(property)
+
+	for _, unit := range ice.multiUnit.Split(property) {
+		if err := ice.repository.Save(ctx, *unit); err != nil {
+			reportCrawlFailure(ice.logger, ice.control, ErrorTypeStorage, url, "Failed to save property", err)
+			ice.errors.RecordError(ErrorTypeStorage, url, err)
+			ice.urlManager.MarkURLProcessed(ctx, url, "failed", err.Error())
+			return
+		}
+		emitPropertySaved(url, unit)
+		ice.control.RecordSaved()
+		ice.stats.NewProperties++
+	}
+	ice.urlManager.MarkURLProcessed(ctx, url, "success", "")
+}
+
 // setupCollector configura o collector do Colly
 func (ice *IncrementalCrawlerEngine) setupCollector() *colly.Collector {
 	c := colly.NewCollector(
@@ -235,32 +434,99 @@ func (ice *IncrementalCrawlerEngine) setupCollector() *colly.Collector {
 		Delay:       ice.config.DelayBetweenRequests,
 	})
 
+	// Timeout por requisição e aborto das páginas lentas ou grandes (antes dos demais handlers)
+	ice.limits.Attach(c, ice.errors)
+
+	// Modo estrito de domínios: redirecionamentos para fora da allowlist não são seguidos
+	if handler := ice.runtime.RedirectHandler(); handler != nil {
+		c.RedirectHandler = handler
+	}
+
+	// Registro de auditoria das requisições (REQUEST_AUDIT) e prazo das páginas
+	// Cache de DNS compartilhado entre os crawlings (DNS_CACHE_TTL)
+	fetchCollector(c, dnsCollector(c), ice.control.JobID(), ice.limits)
+
 	// Handler para encontrar links de propriedades
-	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	// Callbacks protegidos: um panic em uma página não interrompe o crawling
+	c.OnHTML("a[href]", ice.recovery.HTML(func(e *colly.HTMLElement) {
 		ice.handlePropertyLinks(e, c)
-	})
+	}))
 
 	// Handler para páginas de propriedades
-	c.OnHTML("html", func(e *colly.HTMLElement) {
-		ice.handlePropertyPage(context.Background(), e)
-	})
+	c.OnHTML("html", ice.recovery.HTML(func(e *colly.HTMLElement) {
+		ice.handlePropertyPage(ice.control.WithAIUsage(context.Background()), e)
+	}))
+
+	// Ajusta o ritmo por domínio conforme as respostas (429/503 aumentam o atraso)
+	c.OnResponse(ice.recovery.Response(func(r *colly.Response) {
+		emitPageFetched(r)
+		ice.control.RecordResponse(len(r.Body), usesProxy(r.Request))
+		ice.runtime.RecordResponse(r)
+	}))
 
 	// Handler para erros
-	c.OnError(func(r *colly.Response, err error) {
-		ice.logger.WithFields(map[string]interface{}{
-			"url":         r.Request.URL.String(),
-			"status_code": r.StatusCode,
-		}).Error("Request failed", err)
+	c.OnError(ice.recovery.Error(func(r *colly.Response, err error) {
+		ice.control.RecordResponse(len(r.Body), usesProxy(r.Request))
+		if ice.runtime.RecordResponse(r) {
+			ice.logger.WithFields(map[string]interface{}{
+				"url":         r.Request.URL.String(),
+				"status_code": r.StatusCode,
+				"retry_after": r.Headers.Get("Retry-After"),
+			}).Warn("Request throttled by server, retrying with a longer delay")
+			return
+		}
+		if isRequestTimeout(err) {
+			ice.logger.WithField("url", r.Request.URL.String()).Warn("Request timed out, skipping page")
+			ice.errors.Record(ErrorTypeSlowPage, r.Request.URL.String(), err.Error(), r.StatusCode)
+		} else {
+			ice.logger.WithFields(map[string]interface{}{
+				"url":         r.Request.URL.String(),
+				"status_code": r.StatusCode,
+			}).Error("Request failed", err)
+			ice.errors.Record(ErrorTypeFetch, r.Request.URL.String(), err.Error(), r.StatusCode)
+		}
 
 		// Marca como falha
 		ice.urlManager.MarkURLProcessed(context.Background(), r.Request.URL.String(), "failed", err.Error())
 		ice.stats.FailedURLs++
-	})
+
+		// A URL volta a ficar disponível para outros jobs tentarem
+		if ice.frontier != nil {
+			ice.frontier.Release(r.Request.URL.String())
+		}
+	}))
 
 	// Handler para requisições
-	c.OnRequest(func(r *colly.Request) {
+	c.OnRequest(ice.recovery.Request(func(r *colly.Request) {
+		if ice.IntakeStopped() {
+			r.Abort()
+			return
+		}
+		// Fora da janela de crawling do domínio a URL continua pendente para o próximo crawling
+		if !ice.runtime.InCrawlWindow(r.URL.String()) {
+			r.Abort()
+			return
+		}
+		// Hosts isolados por falhas seguidas de DNS não ocupam o ritmo do domínio
+		if CurrentDNSCache().Quarantined(r.URL.String()) {
+			r.Abort()
+			return
+		}
+		// Job cancelado, domínio pausado (a URL fica adiada) ou orçamento esgotado. A verificação
+		// fica aqui porque a navegação também agenda visitas direto no collector
+		if !ice.control.AllowVisit(r.URL.String()) {
+			r.Abort()
+			return
+		}
+		// Outro job simultâneo já está buscando a URL (a lease é renovada nas novas tentativas)
+		if ice.frontier != nil && !ice.frontier.Claim(r.URL.String()) {
+			r.Abort()
+			return
+		}
+		ice.control.RecordVisit()
+		ice.runtime.Acquire(r)
 		ice.logger.WithField("url", r.URL.String()).Debug("Processing URL")
-	})
+	}))
 
 	return c
 }
@@ -283,6 +549,7 @@ func (ice *IncrementalCrawlerEngine) processURL(ctx context.Context, collector *
 	}
 
 	// Visita a URL
+	ice.navigationManager.Discovery().RecordSeed(url)
 	if err := collector.Visit(url); err != nil {
 		return fmt.Errorf("failed to visit URL: %v", err)
 	}
@@ -298,7 +565,12 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 
 	// NAVEGAÇÃO INTELIGENTE: Analisar tipo de página e descobrir links
 	doc := &goquery.Document{Selection: e.DOM}
-	navigationResult := ice.navigationManager.AnalyzePage(doc, url)
+	linkHeader := ""
+	if e.Response != nil && e.Response.Headers != nil {
+		linkHeader = e.Response.Headers.Get("Link")
+	}
+	navigationResult := ice.navigationManager.AnalyzePageWithLinkHeader(doc, url, linkHeader)
+	ice.navigationManager.Discovery().RecordVisit(url)
 
 	ice.logger.WithFields(map[string]interface{}{
 		"url":              url,
@@ -311,8 +583,19 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 		"reason":           navigationResult.Reason,
 	}).Debug("Navigation analysis completed")
 
+	// Combinações de filtros geradas pela descoberta interativa que caem no aviso de "nenhum
+	// imóvel encontrado" são marcadas e não são geradas nos próximos crawlings
+	if len(navigationResult.PropertyLinks) == 0 && ice.navigationManager.IsGeneratedFilterURL(url) && IsZeroResultPage(doc.Text()) {
+		emitPageClassified(url, "zero_results", navigationResult.Confidence, "Generated filter combination returned no listings")
+		ice.deadFilters.RecordZeroResult(ctx, url)
+		ice.urlManager.MarkURLProcessed(ctx, url, "zero_results", "Generated filter combination returned no listings")
+		return
+	}
+	ice.deadFilters.RecordResults(ctx, url)
+
 	// Se é página de catálogo, navegar pelos links encontrados
 	if navigationResult.IsCatalogPage {
+		emitPageClassified(url, "catalog", navigationResult.Confidence, navigationResult.Reason)
 		ice.logger.WithFields(map[string]interface{}{
 			"url":              url,
 			"property_links":   len(navigationResult.PropertyLinks),
@@ -329,6 +612,7 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 
 	// Se é página genérica, navegar pelos catálogos encontrados
 	if !navigationResult.IsPropertyPage && len(navigationResult.CatalogLinks) > 0 {
+		emitPageClassified(url, "generic", navigationResult.Confidence, navigationResult.Reason)
 		ice.logger.WithFields(map[string]interface{}{
 			"url":           url,
 			"catalog_links": len(navigationResult.CatalogLinks),
@@ -353,6 +637,7 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 	}).Debug("Precise classification result")
 
 	if !preciseResult.IsIndividualProperty {
+		emitPageClassified(url, "rejected", preciseResult.Confidence, preciseResult.Reason)
 		ice.logger.WithFields(map[string]interface{}{
 			"url":        url,
 			"confidence": preciseResult.Confidence,
@@ -370,9 +655,10 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 		"confidence": preciseResult.Confidence,
 		"score":      fmt.Sprintf("%.1f/%.1f", preciseResult.Score, preciseResult.MaxScore),
 	}).Info("Page accepted by precise classifier - individual property detected")
+	emitPageClassified(url, "property", preciseResult.Confidence, preciseResult.Reason)
 
 	// Gera fingerprint da página se habilitado
-	var currentFingerprint string
+	var currentFingerprint repository.ContentSignature
 	if ice.config.EnableFingerprinting {
 		currentFingerprint = ice.urlManager.GeneratePageFingerprint(e)
 	}
@@ -387,9 +673,24 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 	property := ice.extractor.ExtractProperty(e, url)
 	if property == nil {
 		ice.logger.WithField("url", url).Debug("No property data found")
+		ice.errors.Record(ErrorTypeParse, url, "no property data extracted", 0)
 		ice.urlManager.MarkURLProcessed(ctx, url, "skipped", "no property data")
 		return
 	}
+	if selectors := ice.runtime.Selectors(url); len(selectors) > 0 {
+		ice.extractor.ApplyDomainSelectors(e, property, selectors)
+	}
+
+	// Script de pós-processamento do domínio (DomainConfig.PostProcessScript)
+	if changed, err := ice.runtime.PostProcess(property); err != nil {
+		ice.logger.WithField("url", url).WithError(err).Warn("Post-process script failed")
+		ice.errors.Record(ErrorTypeParse, url, err.Error(), 0)
+	} else if len(changed) > 0 {
+		ice.logger.WithFields(map[string]interface{}{
+			"url":    url,
+			"fields": changed,
+		}).Debug("Post-process script changed fields")
+	}
 
 	// Valida os dados
 	validation := ice.validator.ValidateProperty(property)
@@ -398,12 +699,14 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 			"url":    url,
 			"errors": validation.Errors,
 		}).Debug("Property validation failed")
+		ice.errors.Record(ErrorTypeValidation, url, strings.Join(validation.Errors, "; "), 0)
 		ice.urlManager.MarkURLProcessed(ctx, url, "failed", "validation failed")
 		return
 	}
 
 	// Melhora os dados
 	property = ice.validator.EnhanceProperty(property)
+	extraction := repository.NewExtractionSnapshot(*property)
 
 	// Determina se deve usar IA
 	shouldUseAI := false
@@ -412,16 +715,39 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 		shouldUseAI, aiReason = ice.urlManager.ShouldUseAI(ctx, url)
 	}
 
+	// Anúncio salvo da URL: base da classificação de mudanças, da tradução e da condição já feitas
+	var stored *repository.Property
+	translate := ice.config.EnableTranslation && ice.aiService != nil
+	scoreCondition := ice.config.EnableConditionScore && ice.aiService != nil
+	if (shouldUseAI && aiReason == "content_changed") || translate || scoreCondition {
+		stored = ice.storedProperty(ctx, url)
+	}
+
+	// Página alterada: só reprocessa com IA se a mudança no anúncio for material. Numa mudança
+	// cosmética o registro salvo, já corrigido pela IA, é mantido: a extração crua tem outro hash
+	// e criaria um segundo documento para a mesma URL
+	keepStored := false
+	if shouldUseAI && aiReason == "content_changed" {
+		if change := ice.classifyChange(url, stored, *property); change != nil && !change.Material {
+			shouldUseAI = false
+			aiReason = "cosmetic_change"
+			property = keptRecord(stored, property)
+			keepStored = true
+		}
+	}
+
 	// Processa com IA se necessário
 	aiProcessed := false
 	if shouldUseAI {
 		if processedProperty, err := ice.aiService.ProcessPropertyData(ctx, *property); err == nil {
+			repository.RecordAIReview(*property, &processedProperty)
 			property = &processedProperty
 			aiProcessed = true
 			ice.stats.AIProcessingCount++
 			ice.logger.WithField("url", url).Debug("Property processed with AI")
 		} else {
 			ice.logger.WithField("url", url).WithError(err).Warn("AI processing failed, using original data")
+			ice.errors.RecordError(ErrorTypeAI, url, err)
 		}
 	} else {
 		ice.stats.AISkippedCount++
@@ -431,19 +757,62 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 		}).Debug("Skipped AI processing")
 	}
 
-	// Salva no repositório
-	if err := ice.repository.Save(ctx, *property); err != nil {
-		ice.logger.WithField("url", url).Error("Failed to save property", err)
-		ice.urlManager.MarkURLProcessed(ctx, url, "failed", err.Error())
-		return
+	// Traduz a descrição para inglês se habilitado. A tradução salva é reaproveitada enquanto o
+	// hash da descrição não mudar (o cache do serviço de IA se perde ao reiniciar)
+	if translate && property.DescricaoEN == "" {
+		if stored != nil && stored.DescricaoEN != "" && ai.DescriptionHash(stored.Descricao) == ai.DescriptionHash(property.Descricao) {
+			property.DescricaoEN = stored.DescricaoEN
+		} else if translated, err := ice.aiService.TranslateDescription(ctx, property.Descricao); err == nil {
+			property.DescricaoEN = translated
+			ice.stats.TranslatedCount++
+		} else {
+			ice.logger.WithField("url", url).WithError(err).Warn("Description translation failed")
+		}
+	}
+
+	// Avalia a condição do imóvel (novo, reformado, usado, precisa reforma); a avaliação salva é
+	// reaproveitada enquanto o hash da descrição não mudar
+	if scoreCondition && property.Condicao == "" {
+		if stored != nil && stored.Condicao != "" && ai.DescriptionHash(stored.Descricao) == ai.DescriptionHash(property.Descricao) {
+			property.Condicao = stored.Condicao
+			property.CondicaoNivel = stored.CondicaoNivel
+			property.CondicaoConfianca = stored.CondicaoConfianca
+		} else if score, err := ice.aiService.ScoreCondition(ctx, property.Descricao); err == nil {
+			ai.ApplyConditionScore(property, score)
+			ice.stats.ConditionScored++
+		} else {
+			ice.logger.WithField("url", url).WithError(err).Debug("Condition scoring failed")
+		}
+	}
+
+	// Guarda a extração original para a comparação da próxima visita
+	property.Extracao = extraction
+
+	// Registra como o anúncio foi descoberto
+	ice.navigationManager.Discovery().Apply(property)
+
+	// Salva no repositório (anúncios multi-unidade geram um registro por unidade)
+	units := []*repository.Property{property}
+	if !keepStored {
+		units = ice.multiUnit.Split(property)
 	}
+	for _, unit := range units {
+		if err := ice.repository.Save(ctx, *unit); err != nil {
+			reportCrawlFailure(ice.logger, ice.control, ErrorTypeStorage, url, "Failed to save property", err)
+			ice.errors.RecordError(ErrorTypeStorage, url, err)
+			ice.urlManager.MarkURLProcessed(ctx, url, "failed", err.Error())
+			return
+		}
+		emitPropertySaved(url, unit)
+		ice.control.RecordSaved()
 
-	// Atualiza estatísticas
-	ice.stats.NewProperties++
+		// Atualiza estatísticas
+		ice.stats.NewProperties++
+	}
 
 	// Salva fingerprint se habilitado
 	if ice.config.EnableFingerprinting {
-		propertyCount := 1 // Esta página tem 1 propriedade
+		propertyCount := len(units)
 		if err := ice.urlManager.SavePageFingerprint(ctx, url, currentFingerprint, propertyCount, aiProcessed); err != nil {
 			ice.logger.WithField("url", url).WithError(err).Warn("Failed to save fingerprint")
 		}
@@ -461,12 +830,65 @@ func (ice *IncrementalCrawlerEngine) handlePropertyPage(ctx context.Context, e *
 		"endereco":        property.Endereco,
 		"valor":           property.Valor,
 		"tipo":            property.TipoImovel,
+		"units":           len(units),
 		"ai_processed":    aiProcessed,
 		"ai_reason":       aiReason,
 		"processing_time": processingTime,
 	}).Info("Property processed successfully")
 }
 
+// keptRecord devolve uma cópia do registro salvo para regravar numa mudança cosmética. Com o
+// mesmo hash o Save só acumula as tags, a tradução e a condição; unidades de anúncios divididos
+// são regravadas sem nova divisão.
+func keptRecord(stored, property *repository.Property) *repository.Property {
+	kept := *stored
+	kept.Tags = property.Tags
+	return &kept
+}
+
+// storedProperty carrega o anúncio salvo para a URL (nil se não houver ou sem suporte no repositório)
+func (ice *IncrementalCrawlerEngine) storedProperty(ctx context.Context, url string) *repository.Property {
+	lookup, ok := ice.repository.(repository.PropertyLookupRepository)
+	if !ok {
+		return nil
+	}
+
+	stored, err := lookup.FindByURL(ctx, url)
+	if err != nil {
+		ice.logger.WithField("url", url).WithError(err).Debug("Failed to load stored property")
+		return nil
+	}
+	return stored
+}
+
+// classifyChange compara o anúncio extraído com a extração salva para a mesma URL (nil se não
+// houver). O registro salvo pode ter sido corrigido pela IA e recalculado, então sem a extração
+// original a mudança não é classificada e o anúncio segue como alterado.
+func (ice *IncrementalCrawlerEngine) classifyChange(url string, stored *repository.Property, property repository.Property) *repository.PropertyChange {
+	if stored == nil || stored.Extracao == nil {
+		return nil
+	}
+
+	change := repository.ClassifyPropertyChange(stored.Extracao.Property(), property)
+	if change.Material {
+		ice.stats.MaterialChanges++
+	} else {
+		ice.stats.CosmeticChanges++
+	}
+
+	ice.logger.WithFields(map[string]interface{}{
+		"url":             url,
+		"kinds":           change.Kinds,
+		"material":        change.Material,
+		"previous_valor":  change.PreviousValor,
+		"current_valor":   change.CurrentValor,
+		"previous_status": change.PreviousStatus,
+		"current_status":  change.CurrentStatus,
+	}).Info("Property change classified")
+
+	return &change
+}
+
 // isCatalogPage verifica se é uma página de catálogo
 func (ice *IncrementalCrawlerEngine) isCatalogPage(e *colly.HTMLElement) bool {
 	url := e.Request.URL.String()
@@ -540,6 +962,37 @@ func (ice *IncrementalCrawlerEngine) countPropertiesInCatalog(e *colly.HTMLEleme
 	return maxCount
 }
 
+// SetErrorTracker compartilha o agregador de erros entre engines
+func (ice *IncrementalCrawlerEngine) SetErrorTracker(tracker *ErrorTracker) {
+	if tracker != nil {
+		ice.errors = tracker
+		ice.recovery.SetErrorTracker(tracker)
+	}
+}
+
+// SetPanicSnapshotDir ativa a gravação do HTML das páginas que causaram panic nos callbacks
+func (ice *IncrementalCrawlerEngine) SetPanicSnapshotDir(dir string) {
+	ice.recovery.SetSnapshotDir(dir)
+}
+
+// SetDomainAllowlist ativa o modo estrito de domínios: só são seguidos links para os domínios
+// das seeds e das configurações por domínio
+func (ice *IncrementalCrawlerEngine) SetDomainAllowlist(allowlist *DomainAllowlist) {
+	ice.runtime.SetAllowlist(allowlist)
+}
+
+// SetDeadFilterTracker ativa a detecção das combinações de filtros geradas que não retornam
+// imóveis e o descarte delas na descoberta interativa
+func (ice *IncrementalCrawlerEngine) SetDeadFilterTracker(tracker *DeadFilterTracker) {
+	ice.deadFilters = tracker
+	ice.navigationManager.SetDeadFilterTracker(tracker)
+}
+
+// SetPageLimits define o timeout por requisição e os limites de tempo e tamanho das páginas
+func (ice *IncrementalCrawlerEngine) SetPageLimits(limits PageLimits) {
+	ice.limits = limits
+}
+
 // GetStatistics retorna estatísticas do crawling
 func (ice *IncrementalCrawlerEngine) GetStatistics() *IncrementalStats {
 	// Calcula economia estimada de IA
@@ -547,6 +1000,9 @@ func (ice *IncrementalCrawlerEngine) GetStatistics() *IncrementalStats {
 		// Estima que cada processamento de IA economizado salva ~2 segundos
 		ice.stats.AISavingsEstimate = time.Duration(ice.stats.AISkippedCount) * 2 * time.Second
 	}
+	ice.stats.ErrorsByType = ice.errors.CountsByType()
+	ice.stats.DeadFilters, ice.stats.PrunedFilters = ice.deadFilters.Counts()
+	ice.stats.DiscoverySources = ice.navigationManager.Discovery().SourceYields()
 
 	return ice.stats
 }
@@ -560,7 +1016,7 @@ func (ice *IncrementalCrawlerEngine) logFinalStatistics() {
 	skippedPercent := float64(stats.SkippedURLs) / float64(stats.TotalURLs) * 100
 	aiSavingsPercent := float64(stats.AISkippedCount) / float64(stats.ProcessedURLs) * 100
 
-	ice.logger.WithFields(map[string]interface{}{
+	fields := map[string]interface{}{
 		"total_urls":          stats.TotalURLs,
 		"processed_urls":      stats.ProcessedURLs,
 		"skipped_urls":        stats.SkippedURLs,
@@ -570,13 +1026,23 @@ func (ice *IncrementalCrawlerEngine) logFinalStatistics() {
 		"failed_urls":         stats.FailedURLs,
 		"ai_processing_count": stats.AIProcessingCount,
 		"ai_skipped_count":    stats.AISkippedCount,
+		"translated_count":    stats.TranslatedCount,
+		"condition_scored":    stats.ConditionScored,
+		"material_changes":    stats.MaterialChanges,
+		"cosmetic_changes":    stats.CosmeticChanges,
 		"ai_savings_percent":  fmt.Sprintf("%.1f%%", aiSavingsPercent),
 		"ai_savings_estimate": stats.AISavingsEstimate,
 		"processing_time":     stats.ProcessingTimeTotal,
 		"fingerprint_hits":    stats.FingerprintHits,
 		"fingerprint_misses":  stats.FingerprintMisses,
 		"content_changes":     stats.ContentChanges,
-	}).Info("Incremental crawling completed")
+		"dead_filters":        stats.DeadFilters,
+		"pruned_filters":      stats.PrunedFilters,
+	}
+	if ice.frontier != nil {
+		fields["leased_by_other_jobs"] = ice.frontier.Skipped()
+	}
+	ice.logger.WithFields(fields).Info("Incremental crawling completed")
 
 	// Log de economia
 	if stats.SkippedURLs > 0 {
@@ -609,16 +1075,29 @@ func (ice *IncrementalCrawlerEngine) handlePropertyLinks(e *colly.HTMLElement, c
 		return
 	}
 
+	// Respeita blacklist e padrões por domínio recarregados em runtime
+	if !ice.runtime.AllowsURL(absoluteLink) {
+		return
+	}
+
 	// Verifica se parece ser um link de propriedade
-	if ice.urlManager.IsValidPropertyLink(absoluteLink, e.Request.URL.Host) {
+	if ice.urlManager.IsValidPropertyLink(absoluteLink, e.Request.URL.Host) && ice.runtime.DiscoverySourceEnabled(absoluteLink, repository.DiscoverySourceClassifiedLink) {
 		ice.urlManager.MarkVisited(absoluteLink)
 
+		// Páginas que raramente mudam só são revisitadas quando o intervalo adaptativo vence
+		if !ice.urlManager.IsDueForRevisit(context.Background(), absoluteLink) {
+			ice.stats.SkippedURLs++
+			return
+		}
+
 		// Limita o número de URLs para evitar memory leak
 		if ice.urlManager.GetVisitedCount() > 10000 {
 			ice.urlManager.CleanupOldURLs(5000)
 		}
 
 		// Visita o link encontrado
+		ice.navigationManager.Discovery().RecordLink(e.Request.URL.String(), absoluteLink)
+		ice.navigationManager.Discovery().RecordSource(absoluteLink, repository.DiscoverySourceClassifiedLink)
 		c.Visit(absoluteLink)
 	}
 }
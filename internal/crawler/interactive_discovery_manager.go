@@ -3,6 +3,7 @@ package crawler
 import (
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
@@ -13,6 +14,12 @@ import (
 type InteractiveDiscoveryManager struct {
 	logger      *logger.Logger
 	visitedURLs map[string]bool
+
+	// Combinações de filtros geradas (formulários e catálogos conhecidos) e as já sabidamente
+	// sem resultados, que não são geradas de novo
+	deadFilters    *DeadFilterTracker
+	generatedMutex sync.RWMutex
+	generatedURLs  map[string]bool
 }
 
 // InteractiveDiscoveryResult resultado da descoberta interativa
@@ -37,9 +44,10 @@ type SearchButton struct {
 type SearchForm struct {
 	Action     string            `json:"action"`
 	Method     string            `json:"method"`
-	Fields     map[string]string `json:"fields"`
-	SubmitText string            `json:"submit_text"`
-	Confidence float64           `json:"confidence"`
+	Fields     map[string]string   `json:"fields"`
+	Options    map[string][]string `json:"options,omitempty"` // valores reais dos <select> e <datalist>
+	SubmitText string              `json:"submit_text"`
+	Confidence float64             `json:"confidence"`
 }
 
 // NavigationLink representa um link de navegação importante
@@ -53,11 +61,25 @@ type NavigationLink struct {
 // NewInteractiveDiscoveryManager cria um novo gerenciador de descoberta interativa
 func NewInteractiveDiscoveryManager() *InteractiveDiscoveryManager {
 	return &InteractiveDiscoveryManager{
-		logger:      logger.NewLogger("interactive_discovery"),
-		visitedURLs: make(map[string]bool),
+		logger:        logger.NewLogger("interactive_discovery"),
+		visitedURLs:   make(map[string]bool),
+		generatedURLs: make(map[string]bool),
 	}
 }
 
+// SetDeadFilterTracker ativa o descarte das combinações de filtros que não retornam imóveis
+func (idm *InteractiveDiscoveryManager) SetDeadFilterTracker(tracker *DeadFilterTracker) {
+	idm.deadFilters = tracker
+}
+
+// IsGeneratedURL informa se a URL é uma combinação de filtros gerada pela descoberta interativa
+// (e não um link encontrado na página)
+func (idm *InteractiveDiscoveryManager) IsGeneratedURL(rawURL string) bool {
+	idm.generatedMutex.RLock()
+	defer idm.generatedMutex.RUnlock()
+	return idm.generatedURLs[rawURL]
+}
+
 // DiscoverInteractiveElements descobre elementos interativos na página
 func (idm *InteractiveDiscoveryManager) DiscoverInteractiveElements(doc *goquery.Document, currentURL string) InteractiveDiscoveryResult {
 	result := InteractiveDiscoveryResult{
@@ -217,6 +239,7 @@ func (idm *InteractiveDiscoveryManager) analyzeForm(s *goquery.Selection, baseUR
 	form := SearchForm{
 		Method:     "GET",
 		Fields:     make(map[string]string),
+		Options:    make(map[string][]string),
 		Confidence: 0.0,
 	}
 
@@ -238,6 +261,9 @@ func (idm *InteractiveDiscoveryManager) analyzeForm(s *goquery.Selection, baseUR
 			placeholder, _ := field.Attr("placeholder")
 
 			form.Fields[name] = fieldType
+			if values := fieldOptionValues(field); len(values) > 0 {
+				form.Options[name] = values
+			}
 
 			// Analisar relevância dos campos
 			nameLower := strings.ToLower(name)
@@ -411,7 +437,7 @@ func (idm *InteractiveDiscoveryManager) generateFormURLs(form SearchForm, baseUR
 	//	"preco_max": {""},
 	// }
 
-	// Gerar algumas combinações básicas
+	// Combinações básicas, usadas quando o formulário não tem opções reais
 	baseParams := []map[string]string{
 		{}, // Sem parâmetros
 		{"operacao": "venda"},
@@ -423,6 +449,11 @@ func (idm *InteractiveDiscoveryManager) generateFormURLs(form SearchForm, baseUR
 		{"cidade": "todos", "operacao": "venda"},
 		{"bairro": "todos-os-bairros", "operacao": "venda"},
 	}
+
+	// Valores reais das listas do formulário (cidades, bairros, tipos) em vez de palpites
+	if optionParams := formOptionParams(form.Options); len(optionParams) > 0 {
+		baseParams = optionParams
+	}
 	
 	// ADICIONAR URLs ESPECÍFICAS CONHECIDAS para sites brasileiros
 	knownCatalogURLs := idm.generateKnownCatalogURLs(baseURL)
@@ -437,6 +468,15 @@ func (idm *InteractiveDiscoveryManager) generateFormURLs(form SearchForm, baseUR
 		}
 	}
 
+	// Combinações que já retornaram "nenhum imóvel encontrado" no domínio não são geradas
+	urls = idm.deadFilters.Prune(urls)
+
+	idm.generatedMutex.Lock()
+	for _, generated := range urls {
+		idm.generatedURLs[generated] = true
+	}
+	idm.generatedMutex.Unlock()
+
 	return urls
 }
 
@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// CostRates são os preços em US$ usados na estimativa de custo dos jobs
+type CostRates struct {
+	AIInputPerMillion  float64 // por milhão de tokens de entrada
+	AIOutputPerMillion float64 // por milhão de tokens de saída
+	ComputePerHour     float64 // por hora de execução
+	BandwidthPerGB     float64 // por GB baixado
+	ProxyPerGB         float64 // por GB baixado através de proxy (somado à banda)
+}
+
+// EstimateJobCost monta o custo do job terminado a partir do consumo de rede e de IA registrado
+// no controle do job e do tempo de execução
+func EstimateJobCost(job *repository.CrawlJob, usage JobUsage, duration time.Duration, rates CostRates) *repository.CrawlJobCost {
+	aiUsage := usage.AI
+	cost := &repository.CrawlJobCost{
+		JobID:          job.ID,
+		Mode:           job.Mode,
+		AIEnabled:      usage.AIEnabled,
+		Template:       job.Template,
+		Status:         job.Status,
+		StartedAt:      job.StartedAt,
+		CompletedAt:    job.StartedAt.Add(duration),
+		Pages:          job.VisitedPages,
+		Properties:     usage.Properties,
+		Requests:       usage.Requests,
+		Bytes:          usage.Bytes,
+		ProxyRequests:  usage.ProxyRequests,
+		ProxyBytes:     usage.ProxyBytes,
+		ComputeSeconds: duration.Seconds(),
+		AICalls:        aiUsage.Calls,
+		AIPromptTokens: aiUsage.PromptTokens,
+		AIOutputTokens: aiUsage.OutputTokens,
+	}
+	if job.CompletedAt != nil {
+		cost.CompletedAt = *job.CompletedAt
+	}
+
+	const gigabyte = 1 << 30
+	cost.AICostUSD = (float64(aiUsage.PromptTokens)*rates.AIInputPerMillion + float64(aiUsage.OutputTokens)*rates.AIOutputPerMillion) / 1e6
+	cost.ComputeCostUSD = duration.Hours() * rates.ComputePerHour
+	cost.BandwidthCostUSD = float64(usage.Bytes) / gigabyte * rates.BandwidthPerGB
+	cost.ProxyCostUSD = float64(usage.ProxyBytes) / gigabyte * rates.ProxyPerGB
+	cost.TotalCostUSD = cost.AICostUSD + cost.ComputeCostUSD + cost.BandwidthCostUSD + cost.ProxyCostUSD
+	if usage.Properties > 0 {
+		cost.CostPerProperty = cost.TotalCostUSD / float64(usage.Properties)
+	}
+	return cost
+}
+
+// usesProxy informa se a requisição saiu por um proxy: o do collector (SetProxyFunc), o do
+// middleware de fetch proxy ou o das variáveis HTTP_PROXY/HTTPS_PROXY, respeitado pelo transporte
+// padrão
+func usesProxy(r *colly.Request) bool {
+	if r == nil {
+		return false
+	}
+	if r.ProxyURL != "" || CurrentFetchChain().Has(FetchMiddlewareProxy) {
+		return true
+	}
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: r.URL})
+	return err == nil && proxyURL != nil
+}
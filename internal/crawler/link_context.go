@@ -0,0 +1,182 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Limites do contexto enviado à IA para classificar um link
+const (
+	linkContextMaxText     = 300 // caracteres do texto do card
+	linkContextMaxDepth    = 6   // ancestrais examinados em busca do card
+	linkContextMaxSnippets = 3   // textos alternativos e preços
+	linkSiblingMaxText     = 200 // irmãos maiores que isso não são considerados trechos de preço
+)
+
+// LinkContextExtractor monta o contexto de um link para a classificação por IA: o card em que o
+// link está (o ancestral mais próximo que se repete entre os irmãos, como os itens de uma listagem),
+// o texto alternativo das imagens e os preços próximos. Links de menus, cabeçalhos e rodapés
+// recebem apenas o próprio texto, em vez do menu inteiro.
+type LinkContextExtractor struct {
+	segmenter *CatalogSegmenter
+}
+
+// NewLinkContextExtractor cria o extrator de contexto de links
+func NewLinkContextExtractor() *LinkContextExtractor {
+	return &LinkContextExtractor{segmenter: NewCatalogSegmenter()}
+}
+
+// Extract retorna o contexto do link (card, imagens e preços), uma informação por linha
+func (lce *LinkContextExtractor) Extract(link *goquery.Selection) string {
+	if link == nil || link.Length() == 0 {
+		return ""
+	}
+
+	scope := lce.cardContainer(link)
+	if scope == nil {
+		if isNavigationElement(link) {
+			return ""
+		}
+		scope = link.Parent()
+	}
+
+	var lines []string
+	if text := truncateRunes(spacedText(scope), linkContextMaxText); text != "" {
+		lines = append(lines, "Card: "+text)
+	}
+	if alts := lce.imageAlts(link, scope); len(alts) > 0 {
+		lines = append(lines, "Imagens: "+strings.Join(alts, "; "))
+	}
+	if prices := lce.prices(link, scope); len(prices) > 0 {
+		lines = append(lines, "Preços: "+strings.Join(prices, "; "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cardContainer sobe a partir do link até o primeiro ancestral que se repete entre os irmãos
+// (mesma assinatura estrutural); nil se nenhum for encontrado antes de um bloco grande demais
+func (lce *LinkContextExtractor) cardContainer(link *goquery.Selection) *goquery.Selection {
+	current := link
+	for depth := 0; depth <= linkContextMaxDepth && current.Length() > 0; depth++ {
+		switch goquery.NodeName(current) {
+		case "body", "html", "main":
+			return nil
+		}
+		if len(lce.segmenter.cardText(current)) > lce.segmenter.maxCardText {
+			return nil
+		}
+		if lce.isRepeated(current) {
+			return current
+		}
+		current = current.Parent()
+	}
+	return nil
+}
+
+// isRepeated indica se o elemento tem ao menos um irmão com a mesma assinatura estrutural
+func (lce *LinkContextExtractor) isRepeated(element *goquery.Selection) bool {
+	signature := lce.segmenter.signature(element)
+	if signature == "" {
+		return false
+	}
+	repeated := false
+	element.Siblings().EachWithBreak(func(_ int, sibling *goquery.Selection) bool {
+		repeated = lce.segmenter.signature(sibling) == signature
+		return !repeated
+	})
+	return repeated
+}
+
+// imageAlts retorna os textos alternativos das imagens do link e do card
+func (lce *LinkContextExtractor) imageAlts(link, scope *goquery.Selection) []string {
+	var alts []string
+	seen := make(map[string]bool)
+	collect := func(_ int, img *goquery.Selection) bool {
+		alt := strings.Join(strings.Fields(img.AttrOr("alt", "")), " ")
+		if alt != "" && !seen[alt] {
+			seen[alt] = true
+			alts = append(alts, truncateRunes(alt, linkContextMaxText/3))
+		}
+		return len(alts) < linkContextMaxSnippets
+	}
+	link.Find("img[alt]").EachWithBreak(collect)
+	if len(alts) < linkContextMaxSnippets {
+		scope.Find("img[alt]").EachWithBreak(collect)
+	}
+	return alts
+}
+
+// prices retorna os preços do card ou, sem card, dos irmãos vizinhos do link e do elemento que o
+// contém (irmãos com links ou texto longo pertencem a outros blocos e são ignorados)
+func (lce *LinkContextExtractor) prices(link, scope *goquery.Selection) []string {
+	matches := lce.segmenter.pricePattern.FindAllString(spacedText(scope), -1)
+	if len(matches) == 0 {
+		parent := link.Parent()
+		neighbors := link.Siblings().AddSelection(parent.Prev()).AddSelection(parent.Next())
+		neighbors.Each(func(_ int, sibling *goquery.Selection) {
+			if text := spacedText(sibling); len(text) <= linkSiblingMaxText && sibling.Find("a[href]").Length() == 0 {
+				matches = append(matches, lce.segmenter.pricePattern.FindAllString(text, -1)...)
+			}
+		})
+	}
+
+	var prices []string
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		match = strings.TrimRight(match, ".,")
+		if !seen[match] && len(prices) < linkContextMaxSnippets {
+			seen[match] = true
+			prices = append(prices, match)
+		}
+	}
+	return prices
+}
+
+// isNavigationElement indica se o elemento está em menu, cabeçalho ou rodapé da página
+func isNavigationElement(element *goquery.Selection) bool {
+	if element.Closest("nav, header, footer, [role=navigation]").Length() > 0 {
+		return true
+	}
+	navigation := false
+	element.ParentsUntil("body").EachWithBreak(func(_ int, parent *goquery.Selection) bool {
+		identity := strings.ToLower(parent.AttrOr("class", "") + " " + parent.AttrOr("id", ""))
+		navigation = strings.Contains(identity, "menu") || strings.Contains(identity, "navbar")
+		return !navigation
+	})
+	return navigation
+}
+
+// spacedText retorna o texto do elemento com os trechos de cada nó separados por espaço
+// (Text() junta "<h2>Casa</h2><span>R$ 1</span>" como "CasaR$ 1")
+func spacedText(element *goquery.Selection) string {
+	var parts []string
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			parts = append(parts, strings.Fields(node.Data)...)
+		case html.ElementNode:
+			if node.Data == "script" || node.Data == "style" {
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range element.Nodes {
+		walk(node)
+	}
+	return strings.Join(parts, " ")
+}
+
+// truncateRunes limita o texto a max caracteres sem cortar caracteres multibyte
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:max])) + "..."
+}
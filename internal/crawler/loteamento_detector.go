@@ -0,0 +1,297 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// maxLoteamentoLots limita os registros filhos gerados por uma única página de loteamento
+const maxLoteamentoLots = 500
+
+// loteamentoKeywords identificam páginas de projeto de loteamento no texto normalizado
+var loteamentoKeywords = []string{
+	"loteamento", "condominio de lotes", "venda de lotes", "lotes a partir", "lotes residenciais",
+	"lotes disponiveis",
+}
+
+// LoteamentoDetector detecta páginas de loteamento que anunciam vários lotes (ex: "120 lotes de
+// 250 a 450 m²") e as dividem em um registro pai do projeto e um registro filho por lote. Sem essa
+// divisão a página vira um único imóvel com a área e o preço de um lote qualquer.
+type LoteamentoDetector struct {
+	logger            *logger.Logger
+	namePattern       *regexp.Regexp
+	lotCountPattern   *regexp.Regexp
+	lotEntryPattern   *regexp.Regexp
+	areaRangePattern  *regexp.Regexp
+	areaListPattern   *regexp.Regexp
+	priceFromPattern  *regexp.Regexp
+	priceValuePattern *regexp.Regexp
+}
+
+// LoteamentoLot lote identificado na página do loteamento
+type LoteamentoLot struct {
+	Label string  `json:"label"`
+	Area  float64 `json:"area"`
+	Price float64 `json:"price"`
+}
+
+// LoteamentoResult resultado da detecção de loteamento
+type LoteamentoResult struct {
+	IsLoteamento bool                  `json:"is_loteamento"`
+	Project      repository.Loteamento `json:"project"`
+	Lots         []LoteamentoLot       `json:"lots"`
+	Reason       string                `json:"reason"`
+}
+
+// NewLoteamentoDetector cria um novo detector de loteamentos
+func NewLoteamentoDetector() *LoteamentoDetector {
+	return &LoteamentoDetector{
+		logger: logger.NewLogger("loteamento_detector"),
+		// "Loteamento Jardim das Flores"
+		namePattern: regexp.MustCompile(`(?:Loteamento|LOTEAMENTO|loteamento)\s+([A-ZÁÉÍÓÚÂÊÔÃÕÇ][a-záéíóúâêôãõç]+(?:\s+(?:d[aeo]s?\s+)?[A-ZÁÉÍÓÚÂÊÔÃÕÇ][a-záéíóúâêôãõç]+){0,3})`),
+		// "120 lotes", "total de 85 lotes"
+		lotCountPattern: regexp.MustCompile(`(?i)(\d{1,4})\s+lotes\b`),
+		// "Quadra A Lote 12 - 250 m² - R$ 90.000", "Lote 7: 300 m2"
+		lotEntryPattern: regexp.MustCompile(`(?i)((?:quadra\s+[a-z0-9]{1,3}\s*[-,/]?\s*)?lote\s+\d{1,4}[a-z]?)\s*[-–:|,]\s*(\d+(?:[.,]\d+)?)\s*(?:m²|m2)(?:\s*[-–:|,]\s*R\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{2})?|\d{4,}))?`),
+		// "lotes de 250 a 450 m²", "lotes entre 250 e 450 m²"
+		areaRangePattern: regexp.MustCompile(`(?i)lotes\s+(?:de|com|entre)\s+(\d+(?:[,.]\d+)?)\s*(?:m²|m2)?\s+(?:a|at[ée]|e)\s+(\d+(?:[,.]\d+)?)\s*(?:m²|m2)`),
+		// "lotes de 250, 300 e 360 m²"
+		areaListPattern: regexp.MustCompile(`(?i)lotes\s+de\s+((?:\d+(?:[,.]\d+)?\s*(?:m²|m2)?\s*,\s*)*\d+(?:[,.]\d+)?\s*(?:m²|m2)?\s+(?:e|ou)\s+\d+(?:[,.]\d+)?)\s*(?:m²|m2)`),
+		// "a partir de R$ 89.000", "R$ 89.000 por lote"
+		priceFromPattern:  regexp.MustCompile(`(?i)(?:a\s+partir\s+de\s*:?\s*R\$\s*(\d{1,3}(?:\.\d{3})*(?:,\d{2})?|\d+)|R\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{2})?|\d{4,})\s*(?:por|/|o)\s*lote)`),
+		priceValuePattern: regexp.MustCompile(`R\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{2})?|\d{4,})`),
+	}
+}
+
+// Detect analisa o texto da página em busca de um loteamento com vários lotes. Só a menção a
+// loteamento não basta (um lote avulso costuma citar o loteamento onde fica, inclusive quantos
+// lotes ele tem): é preciso também uma faixa ou lista de áreas ou ao menos dois lotes descritos.
+func (d *LoteamentoDetector) Detect(text string) LoteamentoResult {
+	normalized := " " + utils.NormalizeText(text) + " "
+	mentioned := false
+	for _, keyword := range loteamentoKeywords {
+		if strings.Contains(normalized, " "+keyword+" ") {
+			mentioned = true
+			break
+		}
+	}
+	if !mentioned {
+		return LoteamentoResult{Reason: "no_loteamento_terms"}
+	}
+
+	result := LoteamentoResult{}
+	seen := make(map[string]bool)
+	for _, match := range d.lotEntryPattern.FindAllStringSubmatch(text, -1) {
+		label := formatLotLabel(match[1])
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		area, _ := utils.ParseBrazilianNumber(match[2])
+		lot := LoteamentoLot{Label: label, Area: area}
+		if match[3] != "" {
+			lot.Price, _ = utils.ParseBrazilianNumber(match[3])
+		}
+		result.Lots = append(result.Lots, lot)
+		if len(result.Lots) == maxLoteamentoLots {
+			break
+		}
+	}
+
+	var areas []float64
+	switch {
+	case len(result.Lots) >= 2:
+		result.Reason = "lot_list"
+	default:
+		result.Lots = nil
+		if match := d.areaListPattern.FindStringSubmatch(text); len(match) > 1 {
+			areas = parseLotAreas(match[1])
+			result.Reason = "area_list"
+		} else if match := d.areaRangePattern.FindStringSubmatch(text); len(match) > 2 {
+			result.Project.AreaMinima, _ = utils.ParseBrazilianNumber(match[1])
+			result.Project.AreaMaxima, _ = utils.ParseBrazilianNumber(match[2])
+			result.Reason = "area_range"
+		}
+	}
+
+	if match := d.lotCountPattern.FindStringSubmatch(text); len(match) > 1 {
+		if count, err := strconv.Atoi(match[1]); err == nil && count >= 2 {
+			result.Project.TotalLotes = count
+		}
+	}
+	if result.Reason == "" {
+		return LoteamentoResult{Reason: "single_lot"}
+	}
+	result.IsLoteamento = true
+
+	if match := d.namePattern.FindStringSubmatch(text); len(match) > 1 {
+		result.Project.Nome = strings.TrimSpace(match[1])
+	}
+
+	priceFrom := 0.0
+	if match := d.priceFromPattern.FindStringSubmatch(text); match != nil {
+		raw := match[1]
+		if raw == "" {
+			raw = match[2]
+		}
+		priceFrom, _ = utils.ParseBrazilianNumber(raw)
+	}
+
+	// Sem lotes descritos, a lista de áreas vira um lote por opção; os preços só são atribuídos
+	// quando há um preço por área
+	if len(areas) >= 2 {
+		var prices []float64
+		for _, match := range d.priceValuePattern.FindAllStringSubmatch(text, -1) {
+			if value, _ := utils.ParseBrazilianNumber(match[1]); value > 0 {
+				prices = append(prices, value)
+			}
+		}
+		for i, area := range areas {
+			lot := LoteamentoLot{Label: fmt.Sprintf("Lote de %s m²", strconv.FormatFloat(area, 'f', -1, 64)), Area: area}
+			if len(prices) == len(areas) && priceFrom == 0 {
+				lot.Price = prices[i]
+			} else if priceFrom > 0 && i == 0 {
+				lot.Price = priceFrom
+			}
+			result.Lots = append(result.Lots, lot)
+		}
+	}
+
+	for _, lot := range result.Lots {
+		result.Project.AreaMinima = minPositive(result.Project.AreaMinima, lot.Area)
+		result.Project.AreaMaxima = maxFloat(result.Project.AreaMaxima, lot.Area)
+		result.Project.PrecoMinimo = minPositive(result.Project.PrecoMinimo, lot.Price)
+		result.Project.PrecoMaximo = maxFloat(result.Project.PrecoMaximo, lot.Price)
+	}
+	result.Project.PrecoMinimo = minPositive(result.Project.PrecoMinimo, priceFrom)
+	result.Project.PrecoMaximo = maxFloat(result.Project.PrecoMaximo, result.Project.PrecoMinimo)
+	if result.Project.TotalLotes < len(result.Lots) && result.Reason == "lot_list" {
+		result.Project.TotalLotes = len(result.Lots)
+	}
+
+	return result
+}
+
+// Split divide a página de loteamento no registro pai do projeto, seguido de um registro por
+// lote, todos com o mesmo ParentID. O pai guarda o resumo em Loteamento e fica sem preço e área
+// próprios, para não entrar nas estatísticas como um terreno; quando a página não descreve os
+// lotes, o preço e a área extraídos continuam em um registro filho com os dados originais. Se a
+// página não for de loteamento, retorna nil.
+func (d *LoteamentoDetector) Split(property *repository.Property) []*repository.Property {
+	if property == nil {
+		return nil
+	}
+
+	result := d.Detect(property.TipoImovel + " " + property.Descricao)
+	if !result.IsLoteamento {
+		return nil
+	}
+
+	parentID := repository.GenerateParentID(property.URL)
+	project := result.Project
+	parent := *property
+	parent.ID = ""
+	parent.ParentID = parentID
+	parent.Loteamento = &project
+	parent.Lote = ""
+	parent.Valor = 0
+	parent.AreaTotal = 0
+	parent.AreaUtil = 0
+	parent.Quartos = 0
+	parent.Banheiros = 0
+	parent.Caracteristicas = append([]string(nil), property.Caracteristicas...)
+
+	records := make([]*repository.Property, 0, len(result.Lots)+1)
+	records = append(records, &parent)
+	for _, lot := range result.Lots {
+		child := *property
+		child.ID = ""
+		child.ParentID = parentID
+		child.Loteamento = nil
+		child.Lote = lot.Label
+		child.TipoImovel = repository.TipoTerreno
+		child.Quartos = 0
+		child.Banheiros = 0
+		child.AreaTotal = lot.Area
+		child.AreaUtil = 0
+		child.Valor = lot.Price
+		child.ValorTexto = ""
+		if lot.Price > 0 {
+			child.ValorTexto = fmt.Sprintf("R$ %.0f", lot.Price)
+		}
+		child.Caracteristicas = append([]string(nil), property.Caracteristicas...)
+		records = append(records, &child)
+	}
+	if len(result.Lots) == 0 && (property.Valor > 0 || property.AreaTotal > 0) {
+		child := *property
+		child.ID = ""
+		child.ParentID = parentID
+		child.Loteamento = nil
+		child.Caracteristicas = append([]string(nil), property.Caracteristicas...)
+		records = append(records, &child)
+	}
+
+	d.logger.WithFields(map[string]interface{}{
+		"url":         property.URL,
+		"parent_id":   parentID,
+		"nome":        project.Nome,
+		"total_lotes": project.TotalLotes,
+		"lots":        len(result.Lots),
+		"reason":      result.Reason,
+	}).Info("Loteamento page split into project and lot records")
+
+	return records
+}
+
+// formatLotLabel padroniza o identificador do lote ("QUADRA a - lote 12" → "Quadra A, Lote 12")
+func formatLotLabel(raw string) string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ' ' || r == '-' || r == ',' || r == '/'
+	})
+	var parts []string
+	for i := 0; i+1 < len(fields); i += 2 {
+		word := strings.ToLower(fields[i])
+		parts = append(parts, strings.ToUpper(word[:1])+word[1:]+" "+strings.ToUpper(fields[i+1]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseLotAreas extrai as áreas (formato brasileiro) de uma lista, sem repetições e em ordem
+func parseLotAreas(text string) []float64 {
+	seen := make(map[float64]bool)
+	var areas []float64
+	for _, value := range utils.ParseNumbers(text) {
+		if value > 0 && !seen[value] {
+			seen[value] = true
+			areas = append(areas, value)
+		}
+	}
+	sort.Float64s(areas)
+	return areas
+}
+
+// minPositive retorna o menor valor positivo (0 quando nenhum é positivo)
+func minPositive(current, value float64) float64 {
+	if value <= 0 {
+		return current
+	}
+	if current <= 0 || value < current {
+		return value
+	}
+	return current
+}
+
+// maxFloat retorna o maior valor
+func maxFloat(current, value float64) float64 {
+	if value > current {
+		return value
+	}
+	return current
+}
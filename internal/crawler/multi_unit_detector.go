@@ -0,0 +1,186 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// MultiUnitDetector detecta anúncios que oferecem várias unidades (ex: "apartamentos de 2 e 3 quartos")
+// e os divide em registros filhos que compartilham o mesmo ParentID
+type MultiUnitDetector struct {
+	logger            *logger.Logger
+	loteamento        *LoteamentoDetector
+	roomListPattern   *regexp.Regexp
+	roomRangePattern  *regexp.Regexp
+	areaListPattern   *regexp.Regexp
+	areaRangePattern  *regexp.Regexp
+	priceFromPattern  *regexp.Regexp
+	priceValuePattern *regexp.Regexp
+	numberPattern     *regexp.Regexp
+}
+
+// MultiUnitResult resultado da detecção de múltiplas unidades
+type MultiUnitResult struct {
+	IsMultiUnit bool      `json:"is_multi_unit"`
+	RoomOptions []int     `json:"room_options"`
+	AreaOptions []float64 `json:"area_options"`
+	Prices      []float64 `json:"prices"`
+	PriceFrom   float64   `json:"price_from"`
+	Reason      string    `json:"reason"`
+}
+
+// NewMultiUnitDetector cria um novo detector de anúncios com múltiplas unidades
+func NewMultiUnitDetector() *MultiUnitDetector {
+	return &MultiUnitDetector{
+		logger:     logger.NewLogger("multi_unit_detector"),
+		loteamento: NewLoteamentoDetector(),
+		// "2 e 3 quartos", "1, 2 ou 3 dormitórios"
+		roomListPattern: regexp.MustCompile(`(?i)((?:\d+\s*,\s*)*\d+\s+(?:e|ou)\s+\d+)\s*(?:quartos?|dormit[óo]rios?|dorms?|su[íi]tes?)`),
+		// "de 2 a 3 quartos"
+		roomRangePattern: regexp.MustCompile(`(?i)de\s+(\d+)\s+a\s+(\d+)\s*(?:quartos?|dormit[óo]rios?|dorms?|su[íi]tes?)`),
+		// "45 e 62 m²", "45m² e 62m²"
+		areaListPattern: regexp.MustCompile(`(?i)((?:\d+(?:[,.]\d+)?\s*(?:m²|m2)?\s*,\s*)*\d+(?:[,.]\d+)?\s*(?:m²|m2)?\s+(?:e|ou)\s+\d+(?:[,.]\d+)?)\s*(?:m²|m2)`),
+		// "de 45 a 70 m²"
+		areaRangePattern: regexp.MustCompile(`(?i)de\s+(\d+(?:[,.]\d+)?)\s*(?:m²|m2)?\s+a\s+(\d+(?:[,.]\d+)?)\s*(?:m²|m2)`),
+		// "a partir de R$ 250.000"
+		priceFromPattern:  regexp.MustCompile(`(?i)a\s+partir\s+de\s*:?\s*R\$\s*(\d{1,3}(?:\.\d{3})*(?:,\d{2})?|\d+)`),
+		priceValuePattern: regexp.MustCompile(`R\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{2})?|\d{4,})`),
+		numberPattern:     regexp.MustCompile(`\d+(?:[,.]\d+)?`),
+	}
+}
+
+// Detect analisa o texto do anúncio em busca de múltiplas unidades
+func (d *MultiUnitDetector) Detect(text string) MultiUnitResult {
+	result := MultiUnitResult{}
+
+	if match := d.roomListPattern.FindStringSubmatch(text); len(match) > 1 {
+		result.RoomOptions = d.parseInts(match[1])
+		result.Reason = "room_list"
+	} else if match := d.roomRangePattern.FindStringSubmatch(text); len(match) > 2 {
+		result.RoomOptions = d.parseInts(match[1] + " " + match[2])
+		result.Reason = "room_range"
+	}
+
+	result.RoomOptions = uniqueSortedInts(result.RoomOptions)
+	if len(result.RoomOptions) < 2 {
+		return MultiUnitResult{Reason: "single_unit"}
+	}
+	result.IsMultiUnit = true
+
+	if match := d.areaListPattern.FindStringSubmatch(text); len(match) > 1 {
+		result.AreaOptions = d.parseFloats(match[1])
+	} else if match := d.areaRangePattern.FindStringSubmatch(text); len(match) > 2 {
+		result.AreaOptions = d.parseFloats(match[1] + " " + match[2])
+	}
+
+	if match := d.priceFromPattern.FindStringSubmatch(text); len(match) > 1 {
+		result.PriceFrom, _ = utils.ParseBrazilianNumber(match[1])
+	}
+
+	for _, match := range d.priceValuePattern.FindAllStringSubmatch(text, -1) {
+		if value, _ := utils.ParseBrazilianNumber(match[1]); value > 0 {
+			result.Prices = append(result.Prices, value)
+		}
+	}
+
+	return result
+}
+
+// Split divide uma propriedade multi-unidade em registros filhos. Páginas de loteamento viram o
+// registro do projeto seguido dos lotes (ver loteamento_detector.go).
+// Se o anúncio não for multi-unidade, retorna a própria propriedade.
+func (d *MultiUnitDetector) Split(property *repository.Property) []*repository.Property {
+	if property == nil {
+		return nil
+	}
+	if records := d.loteamento.Split(property); records != nil {
+		return records
+	}
+
+	result := d.Detect(property.Descricao)
+	if !result.IsMultiUnit {
+		return []*repository.Property{property}
+	}
+
+	parentID := repository.GenerateParentID(property.URL)
+	units := make([]*repository.Property, 0, len(result.RoomOptions))
+
+	for i, rooms := range result.RoomOptions {
+		child := *property
+		child.ID = ""
+		child.ParentID = parentID
+		child.Quartos = rooms
+		child.Caracteristicas = append([]string(nil), property.Caracteristicas...)
+
+		// Área: só atribui quando há uma área por opção de quartos
+		child.AreaTotal = 0
+		if len(result.AreaOptions) == len(result.RoomOptions) {
+			child.AreaTotal = result.AreaOptions[i]
+		}
+
+		// Valor: um preço por unidade, ou "a partir de" para a menor unidade
+		child.Valor = 0
+		if len(result.Prices) == len(result.RoomOptions) && result.PriceFrom == 0 {
+			child.Valor = result.Prices[i]
+			child.ValorTexto = fmt.Sprintf("R$ %.0f", result.Prices[i])
+		} else if result.PriceFrom > 0 && i == 0 {
+			child.Valor = result.PriceFrom
+		}
+
+		units = append(units, &child)
+	}
+
+	d.logger.WithFields(map[string]interface{}{
+		"url":          property.URL,
+		"parent_id":    parentID,
+		"room_options": result.RoomOptions,
+		"area_options": result.AreaOptions,
+		"price_from":   result.PriceFrom,
+		"reason":       result.Reason,
+	}).Info("Multi-unit listing split into child records")
+
+	return units
+}
+
+// parseInts extrai inteiros de um trecho de texto
+func (d *MultiUnitDetector) parseInts(text string) []int {
+	var values []int
+	for _, raw := range d.numberPattern.FindAllString(text, -1) {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 && value <= 20 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseFloats extrai números decimais (formato brasileiro) de um trecho de texto
+func (d *MultiUnitDetector) parseFloats(text string) []float64 {
+	var values []float64
+	for _, value := range utils.ParseNumbers(text) {
+		if value > 0 {
+			values = append(values, value)
+		}
+	}
+	sort.Float64s(values)
+	return values
+}
+
+// uniqueSortedInts remove duplicatas e ordena
+func uniqueSortedInts(values []int) []int {
+	seen := make(map[int]bool)
+	var result []int
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
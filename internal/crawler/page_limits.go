@@ -0,0 +1,191 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+// Limites padrão por página
+const (
+	DefaultRequestTimeout  = 15 * time.Second
+	DefaultPageMaxDuration = 10 * time.Second
+	DefaultPageMaxBytes    = 5 * 1024 * 1024
+)
+
+// PageLimits limita o tempo e o tamanho de cada página para que um host lento não segure o
+// crawling inteiro: RequestTimeout encerra a requisição, páginas que levam mais de MaxDuration
+// para ser baixadas são interrompidas e as com mais de MaxBytes não são analisadas (valores não
+// positivos desativam)
+type PageLimits struct {
+	RequestTimeout time.Duration
+	MaxDuration    time.Duration
+	MaxBytes       int
+}
+
+// DefaultPageLimits retorna os limites padrão
+func DefaultPageLimits() PageLimits {
+	return PageLimits{
+		RequestTimeout: DefaultRequestTimeout,
+		MaxDuration:    DefaultPageMaxDuration,
+		MaxBytes:       DefaultPageMaxBytes,
+	}
+}
+
+// Attach aplica o timeout e o limite de tamanho ao collector. Deve ser chamado antes do registro
+// dos demais handlers de resposta: a página abortada fica sem corpo e sem Content-Type, então os
+// handlers de HTML do colly não são chamados para ela e os demais handlers de resposta a ignoram.
+// O limite de tempo fica no transporte (ver Transport), aplicado por fetchCollector.
+func (l PageLimits) Attach(c *colly.Collector, tracker *ErrorTracker) {
+	if l.RequestTimeout > 0 {
+		c.SetRequestTimeout(l.RequestTimeout)
+	}
+	if l.MaxBytes > 0 {
+		// Um byte além do limite para distinguir a página truncada da que tem exatamente MaxBytes
+		c.MaxBodySize = l.MaxBytes + 1
+	}
+
+	c.OnResponse(func(r *colly.Response) {
+		if reason := l.exceeded(r); reason != "" {
+			tracker.Record(ErrorTypeSlowPage, r.Request.URL.String(), reason, r.StatusCode)
+			r.Body = nil
+			if r.Headers != nil {
+				r.Headers.Del("Content-Type")
+			}
+		}
+	})
+}
+
+// exceeded retorna o motivo do aborto da página, ou vazio se ela está dentro dos limites
+func (l PageLimits) exceeded(r *colly.Response) string {
+	if l.MaxBytes > 0 && len(r.Body) > l.MaxBytes {
+		return fmt.Sprintf("page exceeded %d bytes", l.MaxBytes)
+	}
+	return ""
+}
+
+// slowPageError é o erro da página interrompida por passar de MaxDuration. Implementa net.Error
+// com Timeout, então os engines a registram como slow_page (ver isRequestTimeout).
+type slowPageError struct {
+	limit time.Duration
+}
+
+func (e *slowPageError) Error() string {
+	return fmt.Sprintf("page took longer than %s", e.limit)
+}
+
+func (e *slowPageError) Timeout() bool   { return true }
+func (e *slowPageError) Temporary() bool { return false }
+
+// Transport envolve o transporte do collector com o limite de tempo da página. O relógio começa
+// quando a requisição pede a conexão (httptrace GetConn), depois do ritmo do domínio, das esperas
+// dos middlewares e do atraso do LimitRule, e o prazo vale até o fim da leitura do corpo: ao
+// expirar, o contexto da requisição é cancelado e o download é interrompido. Respostas que não
+// passam pela rede (ex: cache) não são limitadas. Sem MaxDuration retorna next.
+func (l PageLimits) Transport(next http.RoundTripper) http.RoundTripper {
+	if l.MaxDuration <= 0 {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return FetchFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, cancel := context.WithCancel(req.Context())
+		deadline := &pageDeadline{limit: l.MaxDuration, cancel: cancel}
+		trace := &httptrace.ClientTrace{GetConn: func(string) { deadline.start() }}
+
+		resp, err := next.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, trace)))
+		if err != nil {
+			deadline.stop()
+			return nil, deadline.wrap(err)
+		}
+		resp.Body = &pageLimitedBody{ReadCloser: resp.Body, deadline: deadline}
+		return resp, nil
+	})
+}
+
+// pageDeadline controla o prazo de uma requisição: iniciado na primeira conexão (os
+// redirecionamentos contam no mesmo prazo) e encerrado quando o corpo é fechado
+type pageDeadline struct {
+	limit   time.Duration
+	cancel  context.CancelFunc
+	mutex   sync.Mutex
+	timer   *time.Timer
+	expired bool
+	stopped bool
+}
+
+func (d *pageDeadline) start() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.timer != nil || d.stopped {
+		return
+	}
+	d.timer = time.AfterFunc(d.limit, func() {
+		d.mutex.Lock()
+		d.expired = !d.stopped
+		d.mutex.Unlock()
+		d.cancel()
+	})
+}
+
+func (d *pageDeadline) stop() {
+	d.mutex.Lock()
+	d.stopped = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.mutex.Unlock()
+	d.cancel()
+}
+
+// wrap troca o erro causado pelo cancelamento do prazo por slowPageError
+func (d *pageDeadline) wrap(err error) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.expired {
+		return &slowPageError{limit: d.limit}
+	}
+	return err
+}
+
+// pageLimitedBody encerra o prazo quando o corpo é fechado
+type pageLimitedBody struct {
+	io.ReadCloser
+	deadline *pageDeadline
+}
+
+func (b *pageLimitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.deadline.wrap(err)
+	}
+	return n, err
+}
+
+func (b *pageLimitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.deadline.stop()
+	return err
+}
+
+// isRequestTimeout informa se o erro da requisição é o timeout (do cliente HTTP ou do contexto)
+func isRequestTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "Client.Timeout exceeded")
+}
@@ -0,0 +1,101 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// linkHeaderPattern captura entradas do header HTTP Link: <url>; rel="next"
+var linkHeaderPattern = regexp.MustCompile(`<([^>]+)>\s*((?:;\s*[^;,]+)*)`)
+
+// linkRelPattern captura o parâmetro rel de uma entrada do header Link
+var linkRelPattern = regexp.MustCompile(`(?i)rel\s*=\s*"?([^";]+)"?`)
+
+// ParseLinkHeader interpreta o header HTTP Link (RFC 8288) e retorna as URLs agrupadas por rel
+func ParseLinkHeader(header string) map[string][]string {
+	links := make(map[string][]string)
+	if strings.TrimSpace(header) == "" {
+		return links
+	}
+
+	for _, match := range linkHeaderPattern.FindAllStringSubmatch(header, -1) {
+		target := strings.TrimSpace(match[1])
+		relMatch := linkRelPattern.FindStringSubmatch(match[2])
+		if target == "" || len(relMatch) < 2 {
+			continue
+		}
+
+		// rel pode conter vários valores separados por espaço (ex: rel="next last")
+		for _, rel := range strings.Fields(strings.ToLower(relMatch[1])) {
+			links[rel] = append(links[rel], target)
+		}
+	}
+
+	return links
+}
+
+// extractStandardPaginationLinks extrai paginação declarada de forma padronizada:
+// header HTTP Link, <link rel="next|prev">, <a rel="next|prev"> e microdata (itemprop).
+// Os links "next" vêm primeiro para serem priorizados pelo limite de páginas visitadas.
+func (snm *SmartNavigationManager) extractStandardPaginationLinks(doc *goquery.Document, baseURL, linkHeader string) []string {
+	var nextLinks, prevLinks []string
+	seen := make(map[string]bool)
+
+	add := func(target *[]string, href string) {
+		absoluteURL := snm.resolveURL(strings.TrimSpace(href), baseURL)
+		if absoluteURL == "" || absoluteURL == baseURL || seen[absoluteURL] || snm.visitedURLs[absoluteURL] {
+			return
+		}
+		seen[absoluteURL] = true
+		*target = append(*target, absoluteURL)
+	}
+
+	// 1. Header HTTP Link
+	headerLinks := ParseLinkHeader(linkHeader)
+	for _, href := range headerLinks["next"] {
+		add(&nextLinks, href)
+	}
+	for _, rel := range []string{"prev", "previous"} {
+		for _, href := range headerLinks[rel] {
+			add(&prevLinks, href)
+		}
+	}
+
+	// 2. <link rel> e <a rel> no HTML
+	doc.Find("link[rel][href], a[rel][href]").Each(func(i int, s *goquery.Selection) {
+		rel, _ := s.Attr("rel")
+		href, _ := s.Attr("href")
+		for _, value := range strings.Fields(strings.ToLower(rel)) {
+			switch value {
+			case "next":
+				add(&nextLinks, href)
+			case "prev", "previous":
+				add(&prevLinks, href)
+			}
+		}
+	})
+
+	// 3. Microdata de paginação
+	doc.Find("[itemprop='nextPage'], [itemprop='pagination'] a[href]").Each(func(i int, s *goquery.Selection) {
+		href, exists := s.Attr("href")
+		if !exists {
+			href, exists = s.Attr("content")
+		}
+		if exists {
+			add(&nextLinks, href)
+		}
+	})
+	doc.Find("[itemprop='previousPage']").Each(func(i int, s *goquery.Selection) {
+		href, exists := s.Attr("href")
+		if !exists {
+			href, exists = s.Attr("content")
+		}
+		if exists {
+			add(&prevLinks, href)
+		}
+	})
+
+	return append(nextLinks, prevLinks...)
+}
@@ -0,0 +1,500 @@
+package crawler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// PatternSchemaVersion é a versão do formato dos arquivos de padrões exportados
+const PatternSchemaVersion = 1
+
+var (
+	ErrPatternImportNotFound = errors.New("pattern import not found")
+	ErrPatternImportExpired  = errors.New("pattern import expired")
+	ErrPatternImportStale    = errors.New("patterns changed since the import was previewed")
+	ErrPatternImportReplayed = errors.New("pattern file already imported")
+)
+
+// PatternImportDiff resume as mudanças que a importação faz em um grupo de padrões
+type PatternImportDiff struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Changed   []string `json:"changed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// PatternImportPreview é o resultado da validação de um arquivo de padrões, sem aplicá-lo
+type PatternImportPreview struct {
+	SchemaVersion   int                          `json:"schema_version"`
+	Checksum        string                       `json:"checksum"`
+	CurrentChecksum string                       `json:"current_checksum"` // padrões ativos no momento da prévia
+	Replace         bool                         `json:"replace"`          // a importação substitui os padrões ativos (senão, mescla)
+	Diff            map[string]PatternImportDiff `json:"diff"`             // por grupo (catalog, property)
+}
+
+// PatternImporter é implementado pelos aprendizes que importam arquivos de padrões
+type PatternImporter interface {
+	PreviewImport(data []byte) (*PatternImportPreview, error)
+	ImportPatterns(data []byte) error
+	PatternsChecksum() string
+}
+
+// patternFile é o formato dos arquivos de padrões. Arquivos sem schema_version (exportados antes
+// do versionamento) são aceitos sem checksum
+type patternFile[T any] struct {
+	SchemaVersion int        `json:"schema_version,omitempty"`
+	Checksum      string     `json:"checksum,omitempty"`
+	ExportedAt    *time.Time `json:"exported_at,omitempty"`
+	Catalog       []T        `json:"catalog"`
+	Property      []T        `json:"property"`
+}
+
+// patternKind descreve como identificar e validar um tipo de padrão
+type patternKind[T any] struct {
+	key      func(T) string
+	validate func(group string, pattern T) error
+}
+
+// encodePatternFile exporta os padrões com a versão do formato e o checksum
+func encodePatternFile[T any](kind patternKind[T], catalog, property []T) ([]byte, error) {
+	exportedAt := time.Now()
+	file := patternFile[T]{
+		SchemaVersion: PatternSchemaVersion,
+		ExportedAt:    &exportedAt,
+		Catalog:       sortedPatterns(catalog, kind.key),
+		Property:      sortedPatterns(property, kind.key),
+	}
+	file.Checksum = patternsChecksum(kind, file.Catalog, file.Property)
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// decodePatternFile lê e valida o arquivo de padrões; o checksum retornado é sempre o do conteúdo
+func decodePatternFile[T any](kind patternKind[T], data []byte) (*patternFile[T], error) {
+	var file patternFile[T]
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal patterns: %v", err)
+	}
+	if file.SchemaVersion < 0 || file.SchemaVersion > PatternSchemaVersion {
+		return nil, fmt.Errorf("unsupported pattern schema version %d (supported: %d)", file.SchemaVersion, PatternSchemaVersion)
+	}
+	if file.SchemaVersion > 0 && file.Checksum == "" {
+		return nil, fmt.Errorf("pattern file without checksum")
+	}
+
+	groups := []struct {
+		name     string
+		patterns []T
+	}{{"catalog", file.Catalog}, {"property", file.Property}}
+	for _, group := range groups {
+		seen := make(map[string]bool, len(group.patterns))
+		for i, pattern := range group.patterns {
+			if err := kind.validate(group.name, pattern); err != nil {
+				return nil, fmt.Errorf("invalid %s pattern %d: %v", group.name, i, err)
+			}
+			key := kind.key(pattern)
+			if seen[key] {
+				return nil, fmt.Errorf("duplicate %s pattern %q", group.name, key)
+			}
+			seen[key] = true
+		}
+	}
+
+	file.Catalog = sortedPatterns(file.Catalog, kind.key)
+	file.Property = sortedPatterns(file.Property, kind.key)
+	checksum := patternsChecksum(kind, file.Catalog, file.Property)
+	if file.Checksum != "" && file.Checksum != checksum {
+		return nil, fmt.Errorf("pattern checksum mismatch: file declares %s, content is %s", file.Checksum, checksum)
+	}
+	file.Checksum = checksum
+	return &file, nil
+}
+
+// previewPatternFile compara o arquivo com os padrões ativos
+func previewPatternFile[T any](kind patternKind[T], file *patternFile[T], current map[string][]T, replace bool) *PatternImportPreview {
+	return &PatternImportPreview{
+		SchemaVersion:   file.SchemaVersion,
+		Checksum:        file.Checksum,
+		CurrentChecksum: patternsChecksum(kind, current["catalog"], current["property"]),
+		Replace:         replace,
+		Diff: map[string]PatternImportDiff{
+			"catalog":  diffPatterns(kind, current["catalog"], file.Catalog, replace),
+			"property": diffPatterns(kind, current["property"], file.Property, replace),
+		},
+	}
+}
+
+// patternsChecksum é o SHA-256 dos padrões ordenados pela chave
+func patternsChecksum[T any](kind patternKind[T], catalog, property []T) string {
+	data, _ := json.Marshal(struct {
+		Catalog  []T `json:"catalog"`
+		Property []T `json:"property"`
+	}{sortedPatterns(catalog, kind.key), sortedPatterns(property, kind.key)})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sortedPatterns retorna uma cópia ordenada pela chave (nunca nil, para o checksum não distinguir
+// null de [])
+func sortedPatterns[T any](patterns []T, key func(T) string) []T {
+	sorted := make([]T, len(patterns))
+	copy(sorted, patterns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return key(sorted[i]) < key(sorted[j])
+	})
+	return sorted
+}
+
+// diffPatterns lista os padrões adicionados, removidos e alterados pela importação; ao mesclar,
+// nada é removido
+func diffPatterns[T any](kind patternKind[T], current, imported []T, replace bool) PatternImportDiff {
+	diff := PatternImportDiff{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	existing := make(map[string][]byte, len(current))
+	for _, pattern := range current {
+		data, _ := json.Marshal(pattern)
+		existing[kind.key(pattern)] = data
+	}
+
+	importedKeys := make(map[string]bool, len(imported))
+	for _, pattern := range imported {
+		key := kind.key(pattern)
+		importedKeys[key] = true
+		data, _ := json.Marshal(pattern)
+		switch previous, ok := existing[key]; {
+		case !ok:
+			diff.Added = append(diff.Added, key)
+		case string(previous) != string(data):
+			diff.Changed = append(diff.Changed, key)
+		default:
+			diff.Unchanged++
+		}
+	}
+	for key := range existing {
+		if importedKeys[key] {
+			continue
+		}
+		if replace {
+			diff.Removed = append(diff.Removed, key)
+		} else {
+			diff.Unchanged++
+		}
+	}
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// validateConfidence rejeita confianças fora de [0, 1]
+func validateConfidence(confidence float64) error {
+	if math.IsNaN(confidence) || confidence < 0 || confidence > 1 {
+		return fmt.Errorf("confidence %v out of range [0, 1]", confidence)
+	}
+	return nil
+}
+
+// validatePatternType rejeita padrões de um tipo diferente do grupo (arquivos antigos podem não ter
+// o tipo)
+func validatePatternType(group, patternType string) error {
+	if patternType != "" && patternType != group {
+		return fmt.Errorf("type %q in the %s group", patternType, group)
+	}
+	return nil
+}
+
+// PendingPatternImport é uma importação validada aguardando aprovação
+type PendingPatternImport struct {
+	ID        string                `json:"id"`
+	Preview   *PatternImportPreview `json:"preview"`
+	CreatedAt time.Time             `json:"created_at"`
+	ExpiresAt time.Time             `json:"expires_at"`
+	data      []byte
+}
+
+// HasChanges informa se a importação altera algum padrão ativo
+func (p *PatternImportPreview) HasChanges() bool {
+	for _, diff := range p.Diff {
+		if len(diff.Added) > 0 || len(diff.Removed) > 0 || len(diff.Changed) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplayKey identifica a aplicação de um arquivo sobre um estado dos padrões: o mesmo arquivo
+// pode voltar a ser importado (um rollback) depois que os padrões mudaram, mas não sobre o mesmo
+// estado em que já foi aplicado
+func (p *PatternImportPreview) ReplayKey() string {
+	return p.Checksum + ":" + p.CurrentChecksum
+}
+
+// PatternImportQueue guarda as importações de padrões até a aprovação. As importações ficam no
+// store (Mongo em produção), para sobreviver a reinícios e serem aprovadas por qualquer réplica; a
+// aprovação falha se os padrões ativos mudaram desde a prévia
+type PatternImportQueue struct {
+	importer  PatternImporter
+	ttl       time.Duration
+	store     repository.PatternImportRepository
+	onApplied func()
+	mutex     sync.Mutex
+}
+
+// NewPatternImportQueue cria a fila de aprovação; importações não aprovadas em ttl expiram. Sem
+// store, a fila fica em memória
+func NewPatternImportQueue(importer PatternImporter, ttl time.Duration, store repository.PatternImportRepository) *PatternImportQueue {
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	if store == nil {
+		store = newMemoryPatternImportStore()
+	}
+	return &PatternImportQueue{
+		importer: importer,
+		ttl:      ttl,
+		store:    store,
+	}
+}
+
+// OnApplied registra a função chamada depois de cada importação aplicada (ex.: salvar os padrões)
+func (q *PatternImportQueue) OnApplied(fn func()) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.onApplied = fn
+}
+
+// Stage valida o arquivo e o deixa aguardando aprovação. O mesmo arquivo enviado de novo retorna a
+// importação pendente; um arquivo já aplicado sobre os padrões ativos (ou que não muda nada) é
+// recusado
+func (q *PatternImportQueue) Stage(ctx context.Context, data []byte) (*PendingPatternImport, error) {
+	preview, err := q.importer.PreviewImport(data)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !preview.HasChanges() {
+		return nil, ErrPatternImportReplayed
+	}
+	applied, err := q.store.PatternImportApplied(ctx, preview.ReplayKey())
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		return nil, ErrPatternImportReplayed
+	}
+
+	pending, err := q.pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pending {
+		if p.Preview.ReplayKey() == preview.ReplayKey() {
+			return p, nil
+		}
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate import id: %v", err)
+	}
+	now := time.Now()
+	p := &PendingPatternImport{
+		ID:        hex.EncodeToString(id),
+		Preview:   preview,
+		CreatedAt: now,
+		ExpiresAt: now.Add(q.ttl),
+		data:      data,
+	}
+	previewData, err := json.Marshal(preview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode import preview: %v", err)
+	}
+	record := repository.PatternImportRecord{
+		ID:        p.ID,
+		ReplayKey: preview.ReplayKey(),
+		Preview:   previewData,
+		Data:      data,
+		CreatedAt: p.CreatedAt,
+		ExpiresAt: &p.ExpiresAt,
+	}
+	if err := q.store.SavePatternImport(ctx, record); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Pending lista as importações aguardando aprovação, da mais antiga para a mais recente
+func (q *PatternImportQueue) Pending(ctx context.Context) ([]*PendingPatternImport, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.pending(ctx)
+}
+
+// pending lê as importações pendentes do store (chamado com o mutex travado)
+func (q *PatternImportQueue) pending(ctx context.Context) ([]*PendingPatternImport, error) {
+	records, err := q.store.FindPendingPatternImports(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]*PendingPatternImport, 0, len(records))
+	for _, record := range records {
+		p, err := pendingFromRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+	return pending, nil
+}
+
+// Approve aplica a importação pendente
+func (q *PatternImportQueue) Approve(ctx context.Context, id string) (*PendingPatternImport, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	record, err := q.store.TakePatternImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrPatternImportNotFound
+	}
+	pending, err := pendingFromRecord(*record)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(pending.ExpiresAt) {
+		return nil, ErrPatternImportExpired
+	}
+	applied, err := q.store.PatternImportApplied(ctx, pending.Preview.ReplayKey())
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		return nil, ErrPatternImportReplayed
+	}
+	if q.importer.PatternsChecksum() != pending.Preview.CurrentChecksum {
+		return nil, ErrPatternImportStale
+	}
+	if err := q.importer.ImportPatterns(pending.data); err != nil {
+		return nil, err
+	}
+	if err := q.store.MarkPatternImportApplied(ctx, pending.Preview.ReplayKey(), time.Now()); err != nil {
+		return nil, err
+	}
+	if q.onApplied != nil {
+		q.onApplied()
+	}
+	return pending, nil
+}
+
+// Reject descarta a importação pendente
+func (q *PatternImportQueue) Reject(ctx context.Context, id string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	record, err := q.store.TakePatternImport(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrPatternImportNotFound
+	}
+	return nil
+}
+
+// pendingFromRecord reconstrói a importação pendente gravada no store
+func pendingFromRecord(record repository.PatternImportRecord) (*PendingPatternImport, error) {
+	var preview PatternImportPreview
+	if err := json.Unmarshal(record.Preview, &preview); err != nil {
+		return nil, fmt.Errorf("failed to decode import preview: %v", err)
+	}
+	pending := &PendingPatternImport{
+		ID:        record.ID,
+		Preview:   &preview,
+		CreatedAt: record.CreatedAt,
+		data:      record.Data,
+	}
+	if record.ExpiresAt != nil {
+		pending.ExpiresAt = *record.ExpiresAt
+	}
+	return pending, nil
+}
+
+// memoryPatternImportStore guarda a fila em memória, quando não há store persistente
+type memoryPatternImportStore struct {
+	pending map[string]repository.PatternImportRecord
+	applied map[string]time.Time
+	mutex   sync.Mutex
+}
+
+func newMemoryPatternImportStore() *memoryPatternImportStore {
+	return &memoryPatternImportStore{
+		pending: make(map[string]repository.PatternImportRecord),
+		applied: make(map[string]time.Time),
+	}
+}
+
+func (s *memoryPatternImportStore) SavePatternImport(ctx context.Context, record repository.PatternImportRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	record.Status = repository.PatternImportPending
+	s.pending[record.ID] = record
+	return nil
+}
+
+func (s *memoryPatternImportStore) FindPendingPatternImports(ctx context.Context) ([]repository.PatternImportRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	records := make([]repository.PatternImportRecord, 0, len(s.pending))
+	for id, record := range s.pending {
+		if record.ExpiresAt != nil && now.After(*record.ExpiresAt) {
+			delete(s.pending, id)
+			continue
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+func (s *memoryPatternImportStore) TakePatternImport(ctx context.Context, id string) (*repository.PatternImportRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.pending[id]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.pending, id)
+	return &record, nil
+}
+
+func (s *memoryPatternImportStore) MarkPatternImportApplied(ctx context.Context, replayKey string, appliedAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.applied[replayKey] = appliedAt
+	return nil
+}
+
+func (s *memoryPatternImportStore) PatternImportApplied(ctx context.Context, replayKey string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.applied[replayKey]
+	return ok, nil
+}
@@ -1,7 +1,6 @@
 package crawler
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
@@ -410,36 +409,69 @@ func (pl *PatternLearner) GetLearnedPatterns() map[string][]*LearnedPattern {
 	return result
 }
 
-// ExportPatterns exporta os padrões para JSON
+// learnedPatternKind identifica os padrões de URL pelo padrão (a chave dos mapas do aprendiz)
+var learnedPatternKind = patternKind[*LearnedPattern]{
+	key: func(pattern *LearnedPattern) string { return pattern.Pattern },
+	validate: func(group string, pattern *LearnedPattern) error {
+		if pattern == nil {
+			return fmt.Errorf("empty pattern")
+		}
+		if pattern.Pattern == "" {
+			return fmt.Errorf("missing pattern")
+		}
+		if err := validatePatternType(group, pattern.Type); err != nil {
+			return err
+		}
+		return validateConfidence(pattern.Confidence)
+	},
+}
+
+// ExportPatterns exporta os padrões para JSON, com a versão do formato e o checksum
 func (pl *PatternLearner) ExportPatterns() ([]byte, error) {
 	patterns := pl.GetLearnedPatterns()
-	return json.MarshalIndent(patterns, "", "  ")
+	return encodePatternFile(learnedPatternKind, patterns["catalog"], patterns["property"])
 }
 
-// ImportPatterns importa padrões de JSON
-func (pl *PatternLearner) ImportPatterns(data []byte) error {
-	pl.mutex.Lock()
-	defer pl.mutex.Unlock()
+// PatternsChecksum retorna o checksum dos padrões ativos (o mesmo do arquivo exportado)
+func (pl *PatternLearner) PatternsChecksum() string {
+	patterns := pl.GetLearnedPatterns()
+	return patternsChecksum(learnedPatternKind, patterns["catalog"], patterns["property"])
+}
 
-	var patterns map[string][]*LearnedPattern
-	if err := json.Unmarshal(data, &patterns); err != nil {
+// PreviewImport valida o arquivo e mostra o que a importação mudaria, sem aplicá-la
+func (pl *PatternLearner) PreviewImport(data []byte) (*PatternImportPreview, error) {
+	file, err := decodePatternFile(learnedPatternKind, data)
+	if err != nil {
+		return nil, err
+	}
+	return previewPatternFile(learnedPatternKind, file, pl.GetLearnedPatterns(), false), nil
+}
+
+// ImportPatterns importa padrões de JSON, mesclando com os ativos; um arquivo inválido (versão,
+// checksum ou padrões) não altera nada
+func (pl *PatternLearner) ImportPatterns(data []byte) error {
+	file, err := decodePatternFile(learnedPatternKind, data)
+	if err != nil {
 		return err
 	}
 
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+
 	// Importa padrões de catálogo
-	if catalogPatterns, exists := patterns["catalog"]; exists {
-		for _, pattern := range catalogPatterns {
-			pl.catalogPatterns[pattern.Pattern] = pattern
-		}
+	for _, pattern := range file.Catalog {
+		pl.catalogPatterns[pattern.Pattern] = pattern
 	}
 
 	// Importa padrões de propriedade
-	if propertyPatterns, exists := patterns["property"]; exists {
-		for _, pattern := range propertyPatterns {
-			pl.propertyPatterns[pattern.Pattern] = pattern
-		}
+	for _, pattern := range file.Property {
+		pl.propertyPatterns[pattern.Pattern] = pattern
 	}
 
-	pl.logger.Info("Patterns imported successfully")
+	pl.logger.WithFields(map[string]interface{}{
+		"catalog_patterns":  len(file.Catalog),
+		"property_patterns": len(file.Property),
+		"checksum":          file.Checksum,
+	}).Info("Patterns imported successfully")
 	return nil
 }
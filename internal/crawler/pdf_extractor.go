@@ -0,0 +1,127 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+)
+
+// DefaultPDFToTextPath é o binário do poppler usado para extrair o texto dos folhetos em PDF
+const DefaultPDFToTextPath = "pdftotext"
+
+// pdfExtractionTimeout limita a extração de um PDF (arquivos corrompidos podem travar o pdftotext)
+const pdfExtractionTimeout = 30 * time.Second
+
+// ErrEmptyPDFText indica um PDF sem texto extraível (ex: folheto escaneado, apenas imagens)
+var ErrEmptyPDFText = errors.New("pdf has no extractable text")
+
+// PDFTextExtractor extrai o texto de um PDF; a implementação padrão usa o pdftotext do poppler
+type PDFTextExtractor interface {
+	ExtractText(ctx context.Context, data []byte) (string, error)
+}
+
+// PopplerPDFExtractor extrai o texto dos PDFs executando o pdftotext, mantendo o layout das
+// colunas para que rótulos e valores ("Área: 120 m²") continuem na mesma linha
+type PopplerPDFExtractor struct {
+	path string
+}
+
+// NewPopplerPDFExtractor localiza o binário do pdftotext; retorna erro se ele não estiver
+// instalado, para que a etapa de PDF seja desativada em vez de falhar em cada folheto
+func NewPopplerPDFExtractor(path string) (*PopplerPDFExtractor, error) {
+	if path == "" {
+		path = DefaultPDFToTextPath
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("pdftotext not available: %v", err)
+	}
+	return &PopplerPDFExtractor{path: resolved}, nil
+}
+
+// ExtractText grava o PDF em um arquivo temporário (nem todas as versões do pdftotext leem da
+// entrada padrão) e retorna o texto em UTF-8 enviado pelo pdftotext para a saída padrão
+func (p *PopplerPDFExtractor) ExtractText(ctx context.Context, data []byte) (string, error) {
+	file, err := os.CreateTemp("", "brochure-*.pdf")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary pdf file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write temporary pdf file: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pdfExtractionTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path, "-layout", "-enc", "UTF-8", file.Name(), "-")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("pdftotext failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	text := strings.TrimSpace(stdout.String())
+	if text == "" {
+		return "", ErrEmptyPDFText
+	}
+	return text, nil
+}
+
+// isPDFResponse informa se a resposta é um PDF: pelo Content-Type, ou pela extensão da URL
+// quando o servidor responde com um tipo genérico, confirmando pela assinatura do arquivo
+func isPDFResponse(r *colly.Response) bool {
+	if r == nil || r.Request == nil {
+		return false
+	}
+	if r.Headers != nil && strings.Contains(strings.ToLower(r.Headers.Get("Content-Type")), "application/pdf") {
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(r.Request.URL.Path), ".pdf") && bytes.HasPrefix(r.Body, []byte("%PDF-"))
+}
+
+// pdfTextElement monta uma página HTML mínima com o texto do PDF, para que o folheto passe pelo
+// mesmo pipeline de extração das páginas: a primeira linha vira o título e cada bloco de texto
+// separado por linha em branco vira um parágrafo, mantendo as quebras de linha do bloco
+func pdfTextElement(r *colly.Response, text string) (*colly.HTMLElement, error) {
+	var title string
+	var body strings.Builder
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(block, "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if title == "" {
+			title = lines[0]
+		}
+		body.WriteString("<p>" + html.EscapeString(strings.Join(lines, "\n")) + "</p>\n")
+	}
+
+	page := fmt.Sprintf("<html><head><title>%s</title></head><body><h1>%s</h1>\n%s</body></html>",
+		html.EscapeString(title), html.EscapeString(title), body.String())
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pdf document: %v", err)
+	}
+	root := doc.Find("html")
+	return colly.NewHTMLElementFromSelectionNode(r, root, root.Nodes[0], 0), nil
+}
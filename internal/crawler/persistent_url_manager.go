@@ -21,14 +21,18 @@ type PersistentURLManager struct {
 	logger      *logger.Logger
 	urlRepo     repository.URLRepository
 	config      PersistentURLConfig
+	revisit     RevisitPolicy
 }
 
 // PersistentURLConfig configurações para o gerenciador de URLs
 type PersistentURLConfig struct {
-	MaxAge               time.Duration // Idade máxima antes de reprocessar
+	MaxAge               time.Duration // Intervalo inicial de revisita (URLs sem histórico de mudanças)
+	MinRevisitInterval   time.Duration // Menor intervalo adaptativo (páginas que mudam sempre); 0 usa MaxAge
+	MaxRevisitInterval   time.Duration // Maior intervalo adaptativo (páginas estáticas); 0 usa 7 dias
 	CleanupInterval      time.Duration // Intervalo para limpeza automática
 	EnableFingerprinting bool          // Habilita fingerprinting de páginas
 	AIThreshold          time.Duration // Tempo mínimo antes de usar IA novamente
+	SimHashThreshold     int           // Distância de Hamming máxima para considerar a página inalterada
 }
 
 // PropertyPageDetection armazena informações sobre detecção de páginas de imóveis
@@ -49,13 +53,28 @@ func NewPersistentURLManager(urlRepo repository.URLRepository, config Persistent
 	if config.AIThreshold == 0 {
 		config.AIThreshold = 6 * time.Hour // Padrão: 6 horas
 	}
+	if config.SimHashThreshold == 0 {
+		config.SimHashThreshold = repository.DefaultSimHashThreshold
+	}
 
 	return &PersistentURLManager{
 		visitedURLs: make(map[string]bool),
 		logger:      logger.NewLogger("persistent_url_manager"),
 		urlRepo:     urlRepo,
 		config:      config,
+		revisit:     NewRevisitPolicy(config.MaxAge, config.MinRevisitInterval, config.MaxRevisitInterval),
+	}
+}
+
+// SetAIThreshold altera o tempo mínimo antes de usar IA novamente (configuração em runtime)
+func (pum *PersistentURLManager) SetAIThreshold(threshold time.Duration) {
+	if threshold <= 0 {
+		return
 	}
+
+	pum.mutex.Lock()
+	defer pum.mutex.Unlock()
+	pum.config.AIThreshold = threshold
 }
 
 // LoadVisitedURLs carrega URLs visitadas recentemente do banco
@@ -63,7 +82,8 @@ func (pum *PersistentURLManager) LoadVisitedURLs(ctx context.Context) error {
 	pum.mutex.Lock()
 	defer pum.mutex.Unlock()
 
-	since := time.Now().Add(-pum.config.MaxAge)
+	// URLs processadas dentro do menor intervalo de revisita certamente ainda não venceram
+	since := time.Now().Add(-pum.revisit.Min)
 	urls, err := pum.urlRepo.GetProcessedURLsSince(ctx, since)
 	if err != nil {
 		return fmt.Errorf("failed to load visited URLs: %v", err)
@@ -88,6 +108,33 @@ func (pum *PersistentURLManager) LoadVisitedURLs(ctx context.Context) error {
 
 // ShouldProcessURL determina se uma URL deve ser processada
 func (pum *PersistentURLManager) ShouldProcessURL(ctx context.Context, url string) (*ProcessingDecision, error) {
+	var fingerprint *repository.PageFingerprint
+	if pum.config.EnableFingerprinting {
+		var err error
+		fingerprint, err = pum.urlRepo.GetFingerprint(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fingerprint: %v", err)
+		}
+	}
+
+	// Com histórico de mudanças, o intervalo adaptativo da URL substitui o MaxAge global
+	if interval := pum.revisit.Interval(fingerprint); interval > 0 {
+		if time.Since(fingerprint.LastCrawled) < interval {
+			return &ProcessingDecision{
+				ShouldProcess: false,
+				Reason:        "revisit_interval",
+				LastProcessed: fingerprint.LastCrawled,
+				Fingerprint:   fingerprint,
+			}, nil
+		}
+		return &ProcessingDecision{
+			ShouldProcess: true,
+			Reason:        "revisit_due",
+			LastProcessed: fingerprint.LastCrawled,
+			Fingerprint:   fingerprint,
+		}, nil
+	}
+
 	// Verifica se foi processada recentemente
 	isRecent, err := pum.urlRepo.IsURLProcessedRecently(ctx, url, pum.config.MaxAge)
 	if err != nil {
@@ -102,35 +149,28 @@ func (pum *PersistentURLManager) ShouldProcessURL(ctx context.Context, url strin
 		}, nil
 	}
 
-	// Se fingerprinting está habilitado, verifica mudanças
-	if pum.config.EnableFingerprinting {
-		fingerprint, err := pum.urlRepo.GetFingerprint(ctx, url)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get fingerprint: %v", err)
-		}
+	// Fingerprints anteriores ao histórico de mudanças: verifica idade e última mudança
+	if fingerprint != nil {
+		// Verifica se precisa ser reprocessada baseado na idade e mudanças
+		timeSinceLastCrawl := time.Since(fingerprint.LastCrawled)
 
-		if fingerprint != nil {
-			// Verifica se precisa ser reprocessada baseado na idade e mudanças
-			timeSinceLastCrawl := time.Since(fingerprint.LastCrawled)
-
-			if timeSinceLastCrawl < pum.config.MaxAge {
-				return &ProcessingDecision{
-					ShouldProcess: false,
-					Reason:        "fingerprint_fresh",
-					LastProcessed: fingerprint.LastCrawled,
-					Fingerprint:   fingerprint,
-				}, nil
-			}
+		if timeSinceLastCrawl < pum.config.MaxAge {
+			return &ProcessingDecision{
+				ShouldProcess: false,
+				Reason:        "fingerprint_fresh",
+				LastProcessed: fingerprint.LastCrawled,
+				Fingerprint:   fingerprint,
+			}, nil
+		}
 
-			// Se é antiga mas não mudou muito, pode esperar mais
-			if !fingerprint.ChangeDetected && timeSinceLastCrawl < pum.config.MaxAge*2 {
-				return &ProcessingDecision{
-					ShouldProcess: false,
-					Reason:        "no_changes_detected",
-					LastProcessed: fingerprint.LastCrawled,
-					Fingerprint:   fingerprint,
-				}, nil
-			}
+		// Se é antiga mas não mudou muito, pode esperar mais
+		if !fingerprint.ChangeDetected && timeSinceLastCrawl < pum.config.MaxAge*2 {
+			return &ProcessingDecision{
+				ShouldProcess: false,
+				Reason:        "no_changes_detected",
+				LastProcessed: fingerprint.LastCrawled,
+				Fingerprint:   fingerprint,
+			}, nil
 		}
 	}
 
@@ -149,13 +189,13 @@ type ProcessingDecision struct {
 }
 
 // GeneratePageFingerprint gera fingerprint de uma página
-func (pum *PersistentURLManager) GeneratePageFingerprint(e *colly.HTMLElement) string {
+func (pum *PersistentURLManager) GeneratePageFingerprint(e *colly.HTMLElement) repository.ContentSignature {
 	// Extrai elementos relevantes para criar fingerprint
 	prices := pum.extractAllPrices(e)
 	addresses := pum.extractAllAddresses(e)
 	descriptions := pum.extractAllDescriptions(e)
 
-	return repository.GenerateContentHash(prices, addresses, descriptions)
+	return repository.GenerateContentSignature(e.Text, prices, addresses, descriptions)
 }
 
 // extractAllPrices extrai todos os preços da página
@@ -360,7 +400,7 @@ func (pum *PersistentURLManager) ShouldSkipInternalLinks(e *colly.HTMLElement) b
 }
 
 // SavePageFingerprint salva o fingerprint de uma página
-func (pum *PersistentURLManager) SavePageFingerprint(ctx context.Context, url string, contentHash string, propertyCount int, aiProcessed bool) error {
+func (pum *PersistentURLManager) SavePageFingerprint(ctx context.Context, url string, signature repository.ContentSignature, propertyCount int, aiProcessed bool) error {
 	// Verifica se já existe um fingerprint
 	existing, err := pum.urlRepo.GetFingerprint(ctx, url)
 	if err != nil {
@@ -372,13 +412,16 @@ func (pum *PersistentURLManager) SavePageFingerprint(ctx context.Context, url st
 
 	if existing != nil {
 		// Detecta se houve mudança no conteúdo
-		changeDetected = existing.ContentHash != contentHash
+		var distance int
+		changeDetected, distance = pum.contentChanged(existing, signature)
 
 		if changeDetected {
 			pum.logger.WithFields(map[string]interface{}{
 				"url":                url,
-				"old_hash":           existing.ContentHash[:8],
-				"new_hash":           contentHash[:8],
+				"old_hash":           shortHash(existing.ContentHash),
+				"new_hash":           shortHash(signature.ContentHash),
+				"simhash_distance":   distance,
+				"price_changed":      existing.PriceHash != "" && existing.PriceHash != signature.PriceHash,
 				"old_property_count": existing.PropertyCount,
 				"new_property_count": propertyCount,
 			}).Info("Content change detected")
@@ -390,7 +433,9 @@ func (pum *PersistentURLManager) SavePageFingerprint(ctx context.Context, url st
 
 	fingerprint := repository.PageFingerprint{
 		URL:            url,
-		ContentHash:    contentHash,
+		ContentHash:    signature.ContentHash,
+		PriceHash:      signature.PriceHash,
+		SimHash:        signature.SimHash,
 		LastModified:   now,
 		PropertyCount:  propertyCount,
 		LastCrawled:    now,
@@ -399,12 +444,22 @@ func (pum *PersistentURLManager) SavePageFingerprint(ctx context.Context, url st
 		ProcessingTime: 0, // Será atualizado pelo caller se necessário
 	}
 
+	// Histórico de mudanças e próximo intervalo de revisita
+	fingerprint.CrawlCount = 1
+	fingerprint.RevisitInterval = pum.revisit.Initial
 	if existing != nil {
 		// Preserva algumas informações do fingerprint anterior
 		if !changeDetected {
 			fingerprint.LastModified = existing.LastModified
 		}
+		fingerprint.CrawlCount = existing.CrawlCount + 1
+		fingerprint.ChangeCount = existing.ChangeCount
+		fingerprint.RevisitInterval = pum.revisit.NextInterval(existing.RevisitInterval, changeDetected)
 	}
+	if changeDetected {
+		fingerprint.ChangeCount++
+	}
+	fingerprint.NextVisit = now.Add(fingerprint.RevisitInterval)
 
 	if err := pum.urlRepo.SaveFingerprint(ctx, fingerprint); err != nil {
 		return fmt.Errorf("failed to save fingerprint: %v", err)
@@ -413,6 +468,44 @@ func (pum *PersistentURLManager) SavePageFingerprint(ctx context.Context, url st
 	return nil
 }
 
+// IsDueForRevisit indica se um link descoberto já pode ser revisitado segundo o intervalo
+// adaptativo da URL (sempre true sem fingerprint ou com fingerprinting desativado)
+func (pum *PersistentURLManager) IsDueForRevisit(ctx context.Context, url string) bool {
+	if !pum.config.EnableFingerprinting {
+		return true
+	}
+	fingerprint, err := pum.urlRepo.GetFingerprint(ctx, url)
+	if err != nil {
+		return true
+	}
+	return pum.revisit.IsDue(fingerprint, time.Now())
+}
+
+// contentChanged compara as assinaturas: mudança de preço, endereço ou descrição (hash dos campos
+// extraídos, já sem datas e contadores) é sempre real; com esses campos iguais o restante da página
+// só conta como alterado quando o simhash se afasta mais que o limiar. Uma edição pequena como
+// "3 quartos" → "4 quartos" fica abaixo do limiar do simhash, por isso ele não decide sozinho.
+// Fingerprints antigos, sem simhash, continuam comparados pelo hash exato.
+func (pum *PersistentURLManager) contentChanged(existing *repository.PageFingerprint, signature repository.ContentSignature) (bool, int) {
+	distance := repository.SimHashDistance(existing.SimHash, signature.SimHash)
+	if distance < 0 || existing.PriceHash == "" {
+		return existing.ContentHash != signature.ContentHash, distance
+	}
+
+	if existing.PriceHash != signature.PriceHash || existing.ContentHash != signature.ContentHash {
+		return true, distance
+	}
+	return distance > pum.config.SimHashThreshold, distance
+}
+
+// shortHash abrevia um hash para os logs
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
 // ShouldUseAI determina se deve usar IA para processar uma página
 func (pum *PersistentURLManager) ShouldUseAI(ctx context.Context, url string) (bool, string) {
 	if !pum.config.EnableFingerprinting {
@@ -435,7 +528,11 @@ func (pum *PersistentURLManager) ShouldUseAI(ctx context.Context, url string) (b
 	}
 
 	// Se foi processado com IA recentemente, pular
-	if time.Since(fingerprint.LastCrawled) < pum.config.AIThreshold {
+	pum.mutex.RLock()
+	aiThreshold := pum.config.AIThreshold
+	pum.mutex.RUnlock()
+
+	if time.Since(fingerprint.LastCrawled) < aiThreshold {
 		return false, "ai_recently_processed"
 	}
 
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/gocolly/colly/extensions"
+)
+
+// PagePhotoCounter baixa a página do anúncio e conta as fotos da galeria com as mesmas regras da
+// extração. Usado no backfill para recontar as fotos dos imóveis já salvos (repository.PhotoCounter)
+type PagePhotoCounter struct {
+	collector *colly.Collector
+	extractor *DataExtractor
+	interval  time.Duration
+	mutex     sync.Mutex
+	last      time.Time
+}
+
+// NewPagePhotoCounter cria o contador com um intervalo mínimo entre as páginas baixadas
+func NewPagePhotoCounter(interval time.Duration) *PagePhotoCounter {
+	c := colly.NewCollector(colly.AllowURLRevisit())
+	extensions.RandomUserAgent(c)
+	c.SetRequestTimeout(30 * time.Second)
+	c.WithTransport(NewAuditedTransport(nil, ""))
+
+	counter := &PagePhotoCounter{collector: c, extractor: NewDataExtractor(), interval: interval}
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		e.Request.Ctx.Put("photos", counter.extractor.photoURLs(e))
+	})
+	return counter
+}
+
+// CountPhotos retorna as URLs das fotos da galeria da página
+func (pc *PagePhotoCounter) CountPhotos(ctx context.Context, pageURL string) ([]string, error) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if wait := pc.interval - time.Since(pc.last); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	pc.last = time.Now()
+
+	// Respostas de erro (404, 410, 5xx) voltam como erro do colly e mantêm a contagem salva
+	requestCtx := colly.NewContext()
+	if err := pc.collector.Request(http.MethodGet, pageURL, nil, requestCtx, nil); err != nil {
+		return nil, fmt.Errorf("failed to fetch listing page: %v", err)
+	}
+	photos, _ := requestCtx.GetAny("photos").([]string)
+	return photos, nil
+}
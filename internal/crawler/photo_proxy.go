@@ -0,0 +1,359 @@
+package crawler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/temoto/robotstxt"
+)
+
+// Limites do proxy de fotos
+const (
+	maxProxiedPhotoSize = 10 << 20 // 10MB por foto
+	photoRobotsTTL      = 24 * time.Hour
+	photoBrokenTTL      = time.Hour // fotos quebradas não são buscadas de novo antes disso
+	maxPhotoProxyHosts  = 5000      // hosts com robots.txt guardado em memória
+	maxBrokenPhotos     = 10000     // fotos quebradas guardadas em memória
+)
+
+// Motivos pelos quais o proxy não entrega a foto
+var (
+	ErrPhotoDenied = errors.New("photo blocked by domain policy")
+	ErrPhotoRobots = errors.New("photo disallowed by robots.txt")
+	ErrPhotoBroken = errors.New("source photo is broken")
+)
+
+// PhotoProxyOptions configura o proxy de fotos
+type PhotoProxyOptions struct {
+	UserAgent string
+	CacheDir  string        // diretório do cache em disco (vazio desativa)
+	CacheTTL  time.Duration // validade das fotos em cache
+}
+
+// ProxiedPhoto é a foto entregue pelo proxy; com Redirect o cliente deve buscar a URL original
+type ProxiedPhoto struct {
+	URL         string
+	ContentType string
+	Data        []byte
+	Cached      bool
+	Redirect    bool
+}
+
+// BrokenPhoto registra uma foto que a origem não entregou (404, 410 ou conteúdo que não é imagem)
+type BrokenPhoto struct {
+	URL       string    `json:"url"`
+	Domain    string    `json:"domain"`
+	Status    int       `json:"status"`
+	Reason    string    `json:"reason"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// robotsEntry guarda o robots.txt de um host (nil permite tudo)
+type robotsEntry struct {
+	data      *robotstxt.RobotsData
+	fetchedAt time.Time
+}
+
+// PhotoProxy busca as fotos dos anúncios no site de origem para os consumidores da API não
+// fazerem hotlink: respeita o robots.txt e a política de fotos de cada domínio, guarda as fotos
+// em disco e registra as fotos quebradas na origem
+type PhotoProxy struct {
+	options  PhotoProxyOptions
+	snapshot func() *config.RuntimeSnapshot
+	client   *http.Client
+	mutex    sync.Mutex
+	robots   map[string]robotsEntry
+	broken   map[string]BrokenPhoto
+	logger   *logger.Logger
+}
+
+// NewPhotoProxy cria o proxy; snapshot fornece as configurações por domínio (pode ser nil)
+func NewPhotoProxy(options PhotoProxyOptions, snapshot func() *config.RuntimeSnapshot) *PhotoProxy {
+	return &PhotoProxy{
+		options:  options,
+		snapshot: snapshot,
+		client:   NewGuardedClient(20 * time.Second),
+		robots:   make(map[string]robotsEntry),
+		broken:   make(map[string]BrokenPhoto),
+		logger:   logger.NewLogger("photo_proxy"),
+	}
+}
+
+// SetTransport substitui o transporte HTTP usado para baixar as fotos (o padrão recusa
+// endereços internos)
+func (pp *PhotoProxy) SetTransport(transport http.RoundTripper) {
+	pp.client.Transport = transport
+}
+
+// Fetch entrega a foto do anúncio publicado em pageURL. A política do domínio do anúncio (ou,
+// sem configuração, a do domínio da foto) decide entre buscar, redirecionar ou recusar.
+func (pp *PhotoProxy) Fetch(ctx context.Context, photoURL, pageURL string) (*ProxiedPhoto, error) {
+	parsed, err := url.Parse(photoURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: invalid photo url %q", ErrPhotoBroken, photoURL)
+	}
+	if err := ValidatePublicURL(parsed); err != nil {
+		return nil, err
+	}
+
+	domainConfig := pp.domainConfig(pageURL, photoURL)
+	switch domainConfig.PhotoPolicy {
+	case repository.PhotoPolicyDeny:
+		return nil, ErrPhotoDenied
+	case repository.PhotoPolicyRedirect:
+		return &ProxiedPhoto{URL: photoURL, Redirect: true}, nil
+	}
+
+	if photo := pp.readCache(photoURL); photo != nil {
+		return photo, nil
+	}
+	if broken, exists := pp.brokenPhoto(photoURL); exists {
+		return nil, fmt.Errorf("%w: %s", ErrPhotoBroken, broken.Reason)
+	}
+	if !pp.robotsAllow(ctx, parsed) {
+		return nil, ErrPhotoRobots
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, photoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create photo request: %v", err)
+	}
+	if pp.options.UserAgent != "" {
+		req.Header.Set("User-Agent", pp.options.UserAgent)
+	}
+	if domainConfig.PhotoReferer && pageURL != "" {
+		req.Header.Set("Referer", pageURL)
+	}
+
+	resp, err := pp.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("photo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, pp.markBroken(photoURL, resp.StatusCode, fmt.Sprintf("source returned status %d", resp.StatusCode))
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("photo %s returned status %d", photoURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProxiedPhotoSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read photo: %v", err)
+	}
+	if len(data) > maxProxiedPhotoSize {
+		return nil, fmt.Errorf("photo %s exceeds %d bytes", photoURL, maxProxiedPhotoSize)
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, pp.markBroken(photoURL, resp.StatusCode, fmt.Sprintf("source returned %s instead of an image", contentType))
+	}
+
+	pp.writeCache(photoURL, data)
+	return &ProxiedPhoto{URL: photoURL, ContentType: contentType, Data: data}, nil
+}
+
+// BrokenPhotos lista as fotos quebradas na origem, da verificação mais recente à mais antiga
+func (pp *PhotoProxy) BrokenPhotos() []BrokenPhoto {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	photos := make([]BrokenPhoto, 0, len(pp.broken))
+	for _, photo := range pp.broken {
+		photos = append(photos, photo)
+	}
+	sort.Slice(photos, func(i, j int) bool {
+		return photos[i].CheckedAt.After(photos[j].CheckedAt)
+	})
+	return photos
+}
+
+// domainConfig retorna a configuração do domínio do anúncio ou, sem ela, a do domínio da foto
+func (pp *PhotoProxy) domainConfig(pageURL, photoURL string) repository.DomainConfig {
+	if pp.snapshot == nil {
+		return repository.DomainConfig{}
+	}
+	snapshot := pp.snapshot()
+	for _, rawURL := range []string{pageURL, photoURL} {
+		if domainConfig, exists := snapshot.DomainConfig(repository.NormalizeDomain(rawURL)); exists {
+			return domainConfig
+		}
+	}
+	return repository.DomainConfig{}
+}
+
+// robotsAllow verifica o robots.txt do host da foto (guardado por 24h); falhas de leitura permitem
+func (pp *PhotoProxy) robotsAllow(ctx context.Context, photoURL *url.URL) bool {
+	host := photoURL.Scheme + "://" + photoURL.Host
+
+	pp.mutex.Lock()
+	entry, exists := pp.robots[host]
+	pp.mutex.Unlock()
+
+	if !exists || time.Since(entry.fetchedAt) > photoRobotsTTL {
+		entry = robotsEntry{data: pp.fetchRobots(ctx, host), fetchedAt: time.Now()}
+		pp.mutex.Lock()
+		if _, exists := pp.robots[host]; !exists && len(pp.robots) >= maxPhotoProxyHosts {
+			pp.evictRobots()
+		}
+		pp.robots[host] = entry
+		pp.mutex.Unlock()
+	}
+
+	if entry.data == nil {
+		return true
+	}
+	agent := pp.options.UserAgent
+	if agent == "" {
+		agent = "*"
+	}
+	return entry.data.TestAgent(photoURL.EscapedPath(), agent)
+}
+
+// fetchRobots lê o robots.txt do host (nil quando indisponível)
+func (pp *PhotoProxy) fetchRobots(ctx context.Context, host string) *robotstxt.RobotsData {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+"/robots.txt", nil)
+	if err != nil {
+		return nil
+	}
+	if pp.options.UserAgent != "" {
+		req.Header.Set("User-Agent", pp.options.UserAgent)
+	}
+	resp, err := pp.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	data, err := robotstxt.FromResponse(resp)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// markBroken registra a foto como quebrada na origem e retorna o erro correspondente
+func (pp *PhotoProxy) markBroken(photoURL string, status int, reason string) error {
+	pp.mutex.Lock()
+	if _, exists := pp.broken[photoURL]; !exists && len(pp.broken) >= maxBrokenPhotos {
+		pp.evictBroken()
+	}
+	pp.broken[photoURL] = BrokenPhoto{
+		URL:       photoURL,
+		Domain:    repository.NormalizeDomain(photoURL),
+		Status:    status,
+		Reason:    reason,
+		CheckedAt: time.Now(),
+	}
+	pp.mutex.Unlock()
+
+	pp.logger.WithFields(map[string]interface{}{
+		"url":    photoURL,
+		"status": status,
+		"reason": reason,
+	}).Warn("Source photo is broken")
+	return fmt.Errorf("%w: %s", ErrPhotoBroken, reason)
+}
+
+// brokenPhoto retorna o registro recente de foto quebrada (expirado após 1h)
+func (pp *PhotoProxy) brokenPhoto(photoURL string) (BrokenPhoto, bool) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+	broken, exists := pp.broken[photoURL]
+	if !exists || time.Since(broken.CheckedAt) > photoBrokenTTL {
+		return BrokenPhoto{}, false
+	}
+	return broken, true
+}
+
+// evictRobots remove os robots.txt expirados e, se nenhum expirou, o mais antigo (com o mutex)
+func (pp *PhotoProxy) evictRobots() {
+	oldest := ""
+	for host, entry := range pp.robots {
+		if time.Since(entry.fetchedAt) > photoRobotsTTL {
+			delete(pp.robots, host)
+		} else if oldest == "" || entry.fetchedAt.Before(pp.robots[oldest].fetchedAt) {
+			oldest = host
+		}
+	}
+	if len(pp.robots) >= maxPhotoProxyHosts {
+		delete(pp.robots, oldest)
+	}
+}
+
+// evictBroken remove os registros expirados e, se nenhum expirou, o mais antigo (com o mutex)
+func (pp *PhotoProxy) evictBroken() {
+	oldest := ""
+	for photoURL, broken := range pp.broken {
+		if time.Since(broken.CheckedAt) > photoBrokenTTL {
+			delete(pp.broken, photoURL)
+		} else if oldest == "" || broken.CheckedAt.Before(pp.broken[oldest].CheckedAt) {
+			oldest = photoURL
+		}
+	}
+	if len(pp.broken) >= maxBrokenPhotos {
+		delete(pp.broken, oldest)
+	}
+}
+
+// cachePath retorna o arquivo de cache da foto ("" sem cache em disco)
+func (pp *PhotoProxy) cachePath(photoURL string) string {
+	if pp.options.CacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(photoURL))
+	return filepath.Join(pp.options.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// readCache retorna a foto em cache, se ainda válida
+func (pp *PhotoProxy) readCache(photoURL string) *ProxiedPhoto {
+	path := pp.cachePath(photoURL)
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil || (pp.options.CacheTTL > 0 && time.Since(info.ModTime()) > pp.options.CacheTTL) {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return &ProxiedPhoto{URL: photoURL, ContentType: http.DetectContentType(data), Data: data, Cached: true}
+}
+
+// writeCache grava a foto no cache (arquivo temporário renomeado, sem leituras parciais)
+func (pp *PhotoProxy) writeCache(photoURL string, data []byte) {
+	path := pp.cachePath(photoURL)
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(pp.options.CacheDir, 0o755); err != nil {
+		pp.logger.WithError(err).Warn("Failed to create photo cache directory")
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		pp.logger.WithError(err).Warn("Failed to write photo cache")
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		pp.logger.WithError(err).Warn("Failed to write photo cache")
+	}
+}
@@ -0,0 +1,151 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// Limites da geração de seeds de portais
+const (
+	defaultPortalPages = 5
+	maxPortalPages     = 100
+)
+
+// PortalTemplate descreve a URL de busca de um grande portal (OLX, VivaReal, ZAP...).
+// SearchURL aceita os marcadores {city}, {city_slug}, {state}, {state_slug},
+// {state_name_slug} e {page}.
+type PortalTemplate struct {
+	Name      string `json:"name"`
+	SearchURL string `json:"search_url"`
+	StartPage int    `json:"start_page,omitempty"` // padrão 1
+	Pages     int    `json:"pages,omitempty"`      // páginas por cidade, padrão 5
+	Enabled   bool   `json:"enabled"`
+}
+
+// brazilianStates mapeia a UF para o nome do estado usado nas URLs dos portais
+var brazilianStates = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AP": "Amapá", "AM": "Amazonas", "BA": "Bahia",
+	"CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo", "GO": "Goiás",
+	"MA": "Maranhão", "MT": "Mato Grosso", "MS": "Mato Grosso do Sul", "MG": "Minas Gerais",
+	"PA": "Pará", "PB": "Paraíba", "PR": "Paraná", "PE": "Pernambuco", "PI": "Piauí",
+	"RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte", "RS": "Rio Grande do Sul",
+	"RO": "Rondônia", "RR": "Roraima", "SC": "Santa Catarina", "SP": "São Paulo",
+	"SE": "Sergipe", "TO": "Tocantins",
+}
+
+// LoadPortalTemplates lê os templates de portais do arquivo JSON (arquivo ausente = nenhum)
+func LoadPortalTemplates(path string) ([]PortalTemplate, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read portal templates: %v", err)
+	}
+
+	var templates []PortalTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("invalid portal templates: %v", err)
+	}
+	for _, template := range templates {
+		if err := template.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// Validate verifica a URL de busca e a paginação do template
+func (pt PortalTemplate) Validate() error {
+	sample := pt.expand(repository.CityInfo{Name: "Cidade", State: "MG"}, 1)
+	parsed, err := url.Parse(sample)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("portal %q: search_url inválida", pt.Name)
+	}
+	if !strings.Contains(pt.SearchURL, "{city") {
+		return fmt.Errorf("portal %q: search_url deve conter {city} ou {city_slug}", pt.Name)
+	}
+	if pt.Pages < 0 || pt.Pages > maxPortalPages || pt.StartPage < 0 {
+		return fmt.Errorf("portal %q: pages deve estar entre 0 e %d", pt.Name, maxPortalPages)
+	}
+	return nil
+}
+
+// GeneratePortalSeeds gera as URLs paginadas de busca de cada portal habilitado para cada cidade
+func GeneratePortalSeeds(templates []PortalTemplate, cities []repository.CityInfo) []string {
+	seen := make(map[string]bool)
+	var seeds []string
+	for _, template := range templates {
+		if !template.Enabled {
+			continue
+		}
+		startPage, pages := template.StartPage, template.Pages
+		if startPage <= 0 {
+			startPage = 1
+		}
+		if pages <= 0 {
+			pages = defaultPortalPages
+		}
+		// Sem marcador de página, cada cidade gera uma única URL
+		if !strings.Contains(template.SearchURL, "{page}") {
+			pages = 1
+		}
+
+		for _, city := range cities {
+			for page := startPage; page < startPage+pages; page++ {
+				seed := template.expand(city, page)
+				if !seen[seed] {
+					seen[seed] = true
+					seeds = append(seeds, seed)
+				}
+			}
+		}
+	}
+	return seeds
+}
+
+// expand preenche os marcadores do template para a cidade e a página
+func (pt PortalTemplate) expand(city repository.CityInfo, page int) string {
+	state := strings.ToUpper(strings.TrimSpace(city.State))
+	return strings.NewReplacer(
+		"{city}", url.QueryEscape(strings.TrimSpace(city.Name)),
+		"{city_slug}", portalSlug(city.Name),
+		"{state}", state,
+		"{state_slug}", strings.ToLower(state),
+		"{state_name_slug}", portalSlug(brazilianStates[state]),
+		"{page}", strconv.Itoa(page),
+	).Replace(pt.SearchURL)
+}
+
+// ParseCityList interpreta "Cidade-UF" ou "Cidade/UF" separados por vírgula
+func ParseCityList(raw string) ([]repository.CityInfo, error) {
+	var cities []repository.CityInfo
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		separator := strings.LastIndexAny(entry, "-/")
+		if separator <= 0 {
+			return nil, fmt.Errorf("cidade sem UF: %q (use Cidade-UF)", entry)
+		}
+		state := strings.ToUpper(strings.TrimSpace(entry[separator+1:]))
+		if _, exists := brazilianStates[state]; !exists {
+			return nil, fmt.Errorf("UF inválida em %q", entry)
+		}
+		cities = append(cities, repository.CityInfo{Name: strings.TrimSpace(entry[:separator]), State: state})
+	}
+	return cities, nil
+}
+
+// portalSlug converte "Poços de Caldas" em "pocos-de-caldas"
+func portalSlug(text string) string {
+	return strings.Join(strings.Fields(utils.NormalizeText(strings.ReplaceAll(text, "-", " "))), "-")
+}
@@ -7,11 +7,14 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/readability"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 )
 
 // PrecisePropertyClassifier classificador rigoroso para anúncios individuais específicos
 type PrecisePropertyClassifier struct {
 	logger           *logger.Logger
+	thresholds       repository.ClassifierThresholds
 	pricePatterns    []*regexp.Regexp
 	excludePatterns  []*regexp.Regexp
 	requiredPatterns []*regexp.Regexp
@@ -40,10 +43,17 @@ type PreciseDetails struct {
 	FoundRequirements  []string `json:"found_requirements"`
 }
 
+// DefaultClassifierThresholds são os limiares usados pelo classificador preciso: nota mínima 0.6 e
+// pelo menos 3 características de anúncio
+func DefaultClassifierThresholds() repository.ClassifierThresholds {
+	return repository.ClassifierThresholds{MinConfidence: 0.6, MinRequirements: 3}
+}
+
 // NewPrecisePropertyClassifier cria um classificador rigoroso
 func NewPrecisePropertyClassifier() *PrecisePropertyClassifier {
 	classifier := &PrecisePropertyClassifier{
-		logger: logger.NewLogger("precise_property_classifier"),
+		logger:     logger.NewLogger("precise_property_classifier"),
+		thresholds: DefaultClassifierThresholds(),
 	}
 
 	// Padrões de preço específicos (EXPANDIDOS)
@@ -124,6 +134,19 @@ func NewPrecisePropertyClassifier() *PrecisePropertyClassifier {
 	return classifier
 }
 
+// WithThresholds retorna uma cópia do classificador com outros limiares de aceitação (os padrões
+// são compartilhados)
+func (ppc *PrecisePropertyClassifier) WithThresholds(thresholds repository.ClassifierThresholds) *PrecisePropertyClassifier {
+	classifier := *ppc
+	classifier.thresholds = thresholds
+	return &classifier
+}
+
+// Thresholds retorna os limiares de aceitação do classificador
+func (ppc *PrecisePropertyClassifier) Thresholds() repository.ClassifierThresholds {
+	return ppc.thresholds
+}
+
 // ClassifyPage classifica uma página com rigor para anúncios individuais
 func (ppc *PrecisePropertyClassifier) ClassifyPage(doc *goquery.Document, url string) PreciseClassificationResult {
 	if doc == nil {
@@ -141,8 +164,9 @@ func (ppc *PrecisePropertyClassifier) ClassifyPage(doc *goquery.Document, url st
 		},
 	}
 
-	// Extrair texto da página
-	pageText := strings.ToLower(doc.Text())
+	// Extrair texto do conteúdo principal (menus e rodapés com "apartamentos casas terrenos"
+	// distorceriam a contagem de palavras-chave)
+	pageText := strings.ToLower(readability.FromSelection(doc.Selection).Text)
 	words := strings.Fields(pageText)
 	result.Details.WordCount = len(words)
 
@@ -256,8 +280,8 @@ func (ppc *PrecisePropertyClassifier) ClassifyPage(doc *goquery.Document, url st
 	}
 
 	// CRITÉRIO AJUSTADO: Aceita anúncios com score alto mesmo sem preço específico
-	isIndividualProperty := confidence >= 0.6 &&
-		len(result.Details.FoundRequirements) >= 3 &&
+	isIndividualProperty := confidence >= ppc.thresholds.MinConfidence &&
+		len(result.Details.FoundRequirements) >= ppc.thresholds.MinRequirements &&
 		(hasSpecificPrice || (hasPropertyDetails && hasSpecificAddress)) &&
 		!result.Details.IsGenericPage
 
@@ -266,8 +290,9 @@ func (ppc *PrecisePropertyClassifier) ClassifyPage(doc *goquery.Document, url st
 
 	// DEBUG: Adicionar informações sobre por que foi rejeitado
 	if !isIndividualProperty {
-		debugInfo := fmt.Sprintf(" [DEBUG: conf=%.2f>=0.6? req=%d>=3? price=%v details=%v addr=%v generic=%v]",
-			confidence, len(result.Details.FoundRequirements), hasSpecificPrice, hasPropertyDetails, hasSpecificAddress, result.Details.IsGenericPage)
+		debugInfo := fmt.Sprintf(" [DEBUG: conf=%.2f>=%.2f? req=%d>=%d? price=%v details=%v addr=%v generic=%v]",
+			confidence, ppc.thresholds.MinConfidence, len(result.Details.FoundRequirements), ppc.thresholds.MinRequirements,
+			hasSpecificPrice, hasPropertyDetails, hasSpecificAddress, result.Details.IsGenericPage)
 		reason += debugInfo
 	}
 
@@ -0,0 +1,213 @@
+package crawler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/gocolly/colly"
+)
+
+// Parâmetros do ajuste de paralelismo do orçamento global
+const (
+	defaultMaxDomainParallelism = 4
+	rateAdjustWindow            = 30 * time.Second
+	rateSlotCtxKey              = "rate_slot_domain_%d" // por ID: requisições filhas compartilham o Ctx
+)
+
+// GlobalRateController mantém o crawling dentro de um orçamento global de páginas por minuto:
+// as requisições são espaçadas uniformemente (sem rajadas) e o paralelismo por domínio é
+// ajustado a cada janela — aumenta quando o ritmo observado fica abaixo da meta com requisições
+// aguardando vaga no domínio, e diminui quando passa da meta.
+type GlobalRateController struct {
+	mutex          sync.Mutex
+	cond           *sync.Cond
+	pagesPerMinute int
+	maxParallelism int
+	parallelism    int            // requisições simultâneas permitidas por domínio
+	inFlight       map[string]int // requisições em andamento por domínio
+	nextSlot       time.Time
+	windowStart    time.Time
+	windowPages    int
+	blocked        bool // alguma requisição aguardou vaga no domínio durante a janela
+	observedRate   float64
+	adjustments    int
+	totalPages     int64
+}
+
+// GlobalRateStats expõe o estado do orçamento global
+type GlobalRateStats struct {
+	TargetPagesPerMinute   int            `json:"target_pages_per_minute"`
+	ObservedPagesPerMinute float64        `json:"observed_pages_per_minute"`
+	DomainParallelism      int            `json:"domain_parallelism"`
+	MaxDomainParallelism   int            `json:"max_domain_parallelism"`
+	InFlight               map[string]int `json:"in_flight,omitempty"`
+	Adjustments            int            `json:"adjustments"`
+	TotalPages             int64          `json:"total_pages"`
+}
+
+// NewGlobalRateController cria o controlador com a meta de páginas por minuto
+func NewGlobalRateController(pagesPerMinute, maxDomainParallelism int) *GlobalRateController {
+	if maxDomainParallelism <= 0 {
+		maxDomainParallelism = defaultMaxDomainParallelism
+	}
+	rc := &GlobalRateController{
+		pagesPerMinute: pagesPerMinute,
+		maxParallelism: maxDomainParallelism,
+		parallelism:    1,
+		inFlight:       make(map[string]int),
+		windowStart:    time.Now(),
+	}
+	rc.cond = sync.NewCond(&rc.mutex)
+	return rc
+}
+
+// SetBudget altera a meta de páginas por minuto (0 desativa o limite global)
+func (rc *GlobalRateController) SetBudget(pagesPerMinute int) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	rc.pagesPerMinute = pagesPerMinute
+}
+
+// Acquire aguarda uma vaga no domínio e o próximo horário livre do orçamento global
+func (rc *GlobalRateController) Acquire(domain string) {
+	rc.mutex.Lock()
+	for rc.inFlight[domain] >= rc.parallelism {
+		rc.blocked = true
+		rc.cond.Wait()
+	}
+	rc.inFlight[domain]++
+
+	if rc.pagesPerMinute <= 0 {
+		rc.mutex.Unlock()
+		return
+	}
+
+	now := time.Now()
+	slot := rc.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	rc.nextSlot = slot.Add(time.Minute / time.Duration(rc.pagesPerMinute))
+	rc.mutex.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Release libera a vaga do domínio ao fim da requisição e reavalia o paralelismo
+func (rc *GlobalRateController) Release(domain string) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if rc.inFlight[domain] > 0 {
+		rc.inFlight[domain]--
+	}
+	if rc.inFlight[domain] == 0 {
+		delete(rc.inFlight, domain)
+	}
+	rc.windowPages++
+	rc.totalPages++
+	rc.adjust(time.Now())
+	rc.cond.Broadcast()
+}
+
+// adjust recalcula o paralelismo ao fim de cada janela; requer o mutex
+func (rc *GlobalRateController) adjust(now time.Time) {
+	elapsed := now.Sub(rc.windowStart)
+	if elapsed < rateAdjustWindow {
+		return
+	}
+
+	rc.observedRate = float64(rc.windowPages) / elapsed.Minutes()
+	target := float64(rc.pagesPerMinute)
+	switch {
+	case target > 0 && rc.observedRate > target*1.1 && rc.parallelism > 1:
+		rc.parallelism--
+		rc.adjustments++
+	case (target <= 0 || rc.observedRate < target*0.9) && rc.blocked && rc.parallelism < rc.maxParallelism:
+		rc.parallelism++
+		rc.adjustments++
+	}
+
+	rc.windowStart = now
+	rc.windowPages = 0
+	rc.blocked = false
+}
+
+// Stats retorna o estado atual do orçamento global
+func (rc *GlobalRateController) Stats() GlobalRateStats {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	inFlight := make(map[string]int, len(rc.inFlight))
+	for domain, count := range rc.inFlight {
+		inFlight[domain] = count
+	}
+	return GlobalRateStats{
+		TargetPagesPerMinute:   rc.pagesPerMinute,
+		ObservedPagesPerMinute: rc.observedRate,
+		DomainParallelism:      rc.parallelism,
+		MaxDomainParallelism:   rc.maxParallelism,
+		InFlight:               inFlight,
+		Adjustments:            rc.adjustments,
+		TotalPages:             rc.totalPages,
+	}
+}
+
+// SetGlobalRateController ativa o orçamento global de páginas por minuto neste limitador
+func (dt *DomainThrottle) SetGlobalRateController(controller *GlobalRateController) {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+	dt.global = controller
+}
+
+// GlobalRate retorna o estado do orçamento global (nil se desativado)
+func (dt *DomainThrottle) GlobalRate() *GlobalRateStats {
+	dt.mutex.Lock()
+	controller := dt.global
+	dt.mutex.Unlock()
+
+	if controller == nil {
+		return nil
+	}
+	stats := controller.Stats()
+	return &stats
+}
+
+// globalController retorna o controlador do orçamento global (nil se desativado)
+func (dt *DomainThrottle) globalController() *GlobalRateController {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+	return dt.global
+}
+
+// Acquire reserva a requisição no orçamento global (se ativo) e respeita o intervalo do domínio.
+// A vaga é liberada por RecordResponse ao receber a resposta ou o erro.
+func (rs *RuntimeState) Acquire(r *colly.Request) {
+	throttle := rs.domainThrottle()
+	if controller := throttle.globalController(); controller != nil {
+		domain := repository.NormalizeDomain(r.URL.Host)
+		key := fmt.Sprintf(rateSlotCtxKey, r.ID)
+		if held, _ := r.Ctx.GetAny(key).(string); held == "" {
+			controller.Acquire(domain)
+			r.Ctx.Put(key, domain)
+		}
+	}
+	throttle.Wait(r.URL.Host)
+}
+
+// releaseSlot devolve a vaga reservada por Acquire para a requisição
+func (rs *RuntimeState) releaseSlot(r *colly.Request) {
+	key := fmt.Sprintf(rateSlotCtxKey, r.ID)
+	domain, _ := r.Ctx.GetAny(key).(string)
+	if domain == "" {
+		return
+	}
+	r.Ctx.Put(key, "")
+	if controller := rs.domainThrottle().globalController(); controller != nil {
+		controller.Release(domain)
+	}
+}
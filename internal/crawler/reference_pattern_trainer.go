@@ -1,12 +1,10 @@
 package crawler
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -104,29 +102,13 @@ func (rpt *ReferencePatternTrainer) TrainFromReferenceFile(ctx context.Context,
 	return nil
 }
 
-// loadURLsFromFile carrega URLs de um arquivo (suporta diferentes formatos)
+// loadURLsFromFile carrega URLs de um arquivo em qualquer formato de LoadSeedFile
 func (rpt *ReferencePatternTrainer) loadURLsFromFile(filePath string) ([]string, error) {
-	file, err := os.Open(filePath)
+	seeds, err := LoadSeedFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	var urls []string
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") {
-			// Remove possíveis prefixos de numeração
-			line = regexp.MustCompile(`^\d+\|`).ReplaceAllString(line, "")
-			if strings.HasPrefix(line, "http") {
-				urls = append(urls, line)
-			}
-		}
-	}
-
-	return urls, scanner.Err()
+	return SeedURLs(seeds), nil
 }
 
 // analyzeReferenceURL analisa uma URL de referência para extrair padrões
@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Gravação em lotes do registro de auditoria
+const (
+	requestAuditBatchSize     = 100
+	requestAuditFlushInterval = 5 * time.Second
+)
+
+// RequestAuditLog registra cada requisição HTTP de saída (URL, horário, status, bytes, duração e
+// job) no repositório configurado (REQUEST_AUDIT), em lotes gravados a cada 100 registros ou 5s
+type RequestAuditLog struct {
+	repo   repository.RequestAuditRepository
+	mutex  sync.Mutex
+	buffer []repository.RequestAuditEntry
+	stop   chan struct{}
+	done   chan struct{}
+	logger *logger.Logger
+}
+
+// NewRequestAuditLog cria o registro e inicia a gravação periódica; Close grava o restante
+func NewRequestAuditLog(repo repository.RequestAuditRepository) *RequestAuditLog {
+	al := &RequestAuditLog{
+		repo:   repo,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		logger: logger.NewLogger("request_audit"),
+	}
+
+	go func() {
+		defer close(al.done)
+		ticker := time.NewTicker(requestAuditFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-al.stop:
+				return
+			case <-ticker.C:
+				al.flush(context.Background())
+			}
+		}
+	}()
+	return al
+}
+
+// Repository retorna o repositório usado nas consultas
+func (al *RequestAuditLog) Repository() repository.RequestAuditRepository {
+	return al.repo
+}
+
+// Record acrescenta o registro ao lote; o domínio é derivado da URL
+func (al *RequestAuditLog) Record(entry repository.RequestAuditEntry) {
+	if entry.Domain == "" {
+		entry.Domain = repository.NormalizeDomain(entry.URL)
+	}
+
+	al.mutex.Lock()
+	al.buffer = append(al.buffer, entry)
+	full := len(al.buffer) >= requestAuditBatchSize
+	al.mutex.Unlock()
+
+	if full {
+		al.flush(context.Background())
+	}
+}
+
+// Flush grava os registros pendentes
+func (al *RequestAuditLog) Flush(ctx context.Context) error {
+	return al.flush(ctx)
+}
+
+// flush grava o lote pendente; falhas são registradas em log e o lote é descartado
+func (al *RequestAuditLog) flush(ctx context.Context) error {
+	al.mutex.Lock()
+	batch := al.buffer
+	al.buffer = nil
+	al.mutex.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := al.repo.SaveRequestAudits(ctx, batch); err != nil {
+		al.logger.WithField("entries", len(batch)).WithError(err).Warn("Failed to save request audit entries")
+		return err
+	}
+	return nil
+}
+
+// Close interrompe a gravação periódica e grava os registros pendentes
+func (al *RequestAuditLog) Close(ctx context.Context) error {
+	select {
+	case <-al.stop:
+	default:
+		close(al.stop)
+	}
+	<-al.done
+	return al.flush(ctx)
+}
+
+var (
+	requestAuditMutex sync.RWMutex
+	requestAudit      *RequestAuditLog
+)
+
+// SetRequestAuditLog define o registro de auditoria usado pelos engines (nil desativa)
+func SetRequestAuditLog(log *RequestAuditLog) {
+	requestAuditMutex.Lock()
+	defer requestAuditMutex.Unlock()
+	requestAudit = log
+}
+
+// currentRequestAudit retorna o registro de auditoria configurado (nil desativado)
+func currentRequestAudit() *RequestAuditLog {
+	requestAuditMutex.RLock()
+	defer requestAuditMutex.RUnlock()
+	return requestAudit
+}
+
+// auditTransport registra no log de auditoria as requisições feitas pelo transporte base
+type auditTransport struct {
+	base  http.RoundTripper
+	jobID string
+}
+
+// NewAuditedTransport envolve o transporte (nil usa http.DefaultTransport) para registrar as
+// requisições no log de auditoria configurado; sem log as requisições passam direto
+func NewAuditedTransport(base http.RoundTripper, jobID string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &auditTransport{base: base, jobID: jobID}
+}
+
+// RoundTrip executa a requisição; o registro é feito quando o corpo da resposta é fechado
+func (at *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := currentRequestAudit()
+	if log == nil {
+		return at.base.RoundTrip(req)
+	}
+
+	entry := repository.RequestAuditEntry{
+		Time:   time.Now().UTC(),
+		JobID:  at.jobID,
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+	resp, err := at.base.RoundTrip(req)
+	if err != nil {
+		entry.DurationMs = time.Since(entry.Time).Milliseconds()
+		entry.Error = err.Error()
+		log.Record(entry)
+		return resp, err
+	}
+
+	entry.StatusCode = resp.StatusCode
+	resp.Body = &auditBody{ReadCloser: resp.Body, log: log, entry: entry}
+	return resp, nil
+}
+
+// auditBody conta os bytes lidos do corpo e registra a requisição ao ser fechado
+type auditBody struct {
+	io.ReadCloser
+	log   *RequestAuditLog
+	entry repository.RequestAuditEntry
+	once  sync.Once
+}
+
+// Read lê o corpo contando os bytes
+func (ab *auditBody) Read(p []byte) (int, error) {
+	n, err := ab.ReadCloser.Read(p)
+	ab.entry.Bytes += int64(n)
+	return n, err
+}
+
+// Close fecha o corpo e registra a requisição uma única vez
+func (ab *auditBody) Close() error {
+	err := ab.ReadCloser.Close()
+	ab.once.Do(func() {
+		ab.entry.DurationMs = time.Since(ab.entry.Time).Milliseconds()
+		ab.log.Record(ab.entry)
+	})
+	return err
+}
@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// RevisitPolicy calcula o intervalo de revisita de cada URL a partir do histórico de mudanças:
+// páginas que mudam a cada visita (catálogos) convergem para Min e páginas estáticas (anúncios
+// individuais) para Max. URLs sem histórico usam Initial.
+type RevisitPolicy struct {
+	Initial time.Duration
+	Min     time.Duration
+	Max     time.Duration
+}
+
+// NewRevisitPolicy cria a política; min <= 0 usa o intervalo inicial e max <= 0 usa 7 dias
+func NewRevisitPolicy(initial, min, max time.Duration) RevisitPolicy {
+	if initial <= 0 {
+		initial = 24 * time.Hour
+	}
+	if min <= 0 {
+		min = initial
+	}
+	if max <= 0 {
+		max = 7 * 24 * time.Hour
+	}
+	if max < min {
+		max = min
+	}
+	return RevisitPolicy{Initial: initial, Min: min, Max: max}
+}
+
+// NextInterval reduz o intervalo à metade quando a página mudou e o dobra quando não mudou
+func (rp RevisitPolicy) NextInterval(previous time.Duration, changed bool) time.Duration {
+	if previous <= 0 {
+		previous = rp.Initial
+	}
+	next := previous * 2
+	if changed {
+		next = previous / 2
+	}
+	return rp.clamp(next)
+}
+
+// Interval retorna o intervalo vigente do fingerprint (0 se a URL ainda não tem histórico)
+func (rp RevisitPolicy) Interval(fingerprint *repository.PageFingerprint) time.Duration {
+	if fingerprint == nil || fingerprint.RevisitInterval <= 0 {
+		return 0
+	}
+	// Limites alterados desde a última visita valem imediatamente
+	return rp.clamp(fingerprint.RevisitInterval)
+}
+
+// IsDue indica se a URL já deve ser revisitada (sempre true sem histórico)
+func (rp RevisitPolicy) IsDue(fingerprint *repository.PageFingerprint, now time.Time) bool {
+	interval := rp.Interval(fingerprint)
+	return interval == 0 || now.Sub(fingerprint.LastCrawled) >= interval
+}
+
+// clamp limita o intervalo entre Min e Max
+func (rp RevisitPolicy) clamp(interval time.Duration) time.Duration {
+	if interval < rp.Min {
+		return rp.Min
+	}
+	if interval > rp.Max {
+		return rp.Max
+	}
+	return interval
+}
@@ -0,0 +1,260 @@
+package crawler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// RuntimeState guarda a configuração aplicada a um engine em tempo de execução
+// (recarregada pelo config.ConfigWatcher sem reiniciar o crawling)
+type RuntimeState struct {
+	mutex     sync.RWMutex
+	snapshot  *config.RuntimeSnapshot
+	throttle  *DomainThrottle
+	scripts   *ScriptHooks
+	allowlist *DomainAllowlist // modo estrito de domínios (nil desativado)
+	windows   *CrawlWindowGate // janelas de crawling por horário do dia
+}
+
+// DomainThrottle garante um intervalo mínimo entre requisições ao mesmo domínio
+type DomainThrottle struct {
+	mutex           sync.Mutex
+	defaultInterval time.Duration
+	intervals       map[string]time.Duration
+	nextSlot        map[string]time.Time
+	adaptive        map[string]*adaptiveDelay // atraso adicional aprendido com 429/503 (ver adaptive_throttle.go)
+	global          *GlobalRateController     // orçamento global de páginas por minuto (ver rate_controller.go)
+}
+
+// NewRuntimeState cria um estado de runtime vazio (sem restrições adicionais)
+func NewRuntimeState() *RuntimeState {
+	return &RuntimeState{
+		throttle: NewDomainThrottle(),
+		scripts:  NewScriptHooks(),
+		windows:  NewCrawlWindowGate(),
+	}
+}
+
+// NewDomainThrottle cria um novo limitador por domínio
+func NewDomainThrottle() *DomainThrottle {
+	return &DomainThrottle{
+		intervals: make(map[string]time.Duration),
+		nextSlot:  make(map[string]time.Time),
+		adaptive:  make(map[string]*adaptiveDelay),
+	}
+}
+
+// Set aplica uma nova configuração de runtime
+func (rs *RuntimeState) Set(snapshot *config.RuntimeSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	rs.mutex.Lock()
+	rs.snapshot = snapshot
+	rs.mutex.Unlock()
+
+	intervals := make(map[string]time.Duration, len(snapshot.Domains))
+	for domain, domainConfig := range snapshot.Domains {
+		if interval := domainInterval(domainConfig.RateLimit); interval > 0 {
+			intervals[domain] = interval
+		}
+	}
+	rs.domainThrottle().Configure(time.Duration(snapshot.Settings.DefaultDelayMs)*time.Millisecond, intervals)
+}
+
+// Snapshot retorna a configuração aplicada (pode ser nil)
+func (rs *RuntimeState) Snapshot() *config.RuntimeSnapshot {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	return rs.snapshot
+}
+
+// SetAllowlist ativa o modo estrito de domínios
+func (rs *RuntimeState) SetAllowlist(allowlist *DomainAllowlist) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.allowlist = allowlist
+}
+
+// Allowlist retorna a allowlist do modo estrito de domínios (nil desativado)
+func (rs *RuntimeState) Allowlist() *DomainAllowlist {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	return rs.allowlist
+}
+
+// AllowSeeds permite os domínios das seeds no modo estrito de domínios
+func (rs *RuntimeState) AllowSeeds(urls []string) {
+	if allowlist := rs.Allowlist(); allowlist != nil {
+		allowlist.Add(urls...)
+	}
+}
+
+// RedirectHandler retorna o tratamento de redirecionamentos do modo estrito (nil desativado)
+func (rs *RuntimeState) RedirectHandler() func(req *http.Request, via []*http.Request) error {
+	allowlist := rs.Allowlist()
+	if allowlist == nil {
+		return nil
+	}
+	return allowlist.redirectHandler(rs.Snapshot)
+}
+
+// AllowsURL verifica a allowlist do modo estrito, a blacklist global e as regras de
+// inclusão/exclusão do domínio
+func (rs *RuntimeState) AllowsURL(rawURL string) bool {
+	snapshot := rs.Snapshot()
+	if allowlist := rs.Allowlist(); allowlist != nil && !allowlist.Allows(rawURL, snapshot) {
+		return false
+	}
+	if snapshot == nil {
+		return true
+	}
+
+	if snapshot.IsBlacklisted(rawURL) {
+		return false
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if rules, exists := snapshot.URLRules(parsed.Host); exists {
+			return rules.Allows(rawURL)
+		}
+	}
+
+	return true
+}
+
+// Selectors retorna os seletores CSS configurados para o domínio da URL (nil se não houver)
+func (rs *RuntimeState) Selectors(rawURL string) map[string]string {
+	snapshot := rs.Snapshot()
+	if snapshot == nil {
+		return nil
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if domainConfig, exists := snapshot.DomainConfig(parsed.Host); exists {
+			return domainConfig.Selectors
+		}
+	}
+	return nil
+}
+
+// APIConfig retorna a API JSON configurada para o domínio da URL (nil se o domínio usa HTML)
+func (rs *RuntimeState) APIConfig(rawURL string) *repository.DomainAPIConfig {
+	snapshot := rs.Snapshot()
+	if snapshot == nil {
+		return nil
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if domainConfig, exists := snapshot.DomainConfig(parsed.Host); exists && domainConfig.API != nil {
+			return domainConfig.API
+		}
+	}
+	return nil
+}
+
+// DiscoverySourceEnabled informa se a estratégia de descoberta não foi desativada na
+// configuração do domínio da URL
+func (rs *RuntimeState) DiscoverySourceEnabled(rawURL, source string) bool {
+	if rs == nil {
+		return true
+	}
+	snapshot := rs.Snapshot()
+	if snapshot == nil {
+		return true
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if domainConfig, exists := snapshot.DomainConfig(parsed.Host); exists {
+			return domainConfig.DiscoverySourceEnabled(source)
+		}
+	}
+	return true
+}
+
+// PostProcess executa o script Lua configurado para o domínio do imóvel (se houver) e
+// retorna os campos alterados
+func (rs *RuntimeState) PostProcess(property *repository.Property) ([]string, error) {
+	snapshot := rs.Snapshot()
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	parsed, err := url.Parse(property.URL)
+	if err != nil {
+		return nil, nil
+	}
+	domainConfig, exists := snapshot.DomainConfig(parsed.Host)
+	if !exists || strings.TrimSpace(domainConfig.PostProcessScript) == "" {
+		return nil, nil
+	}
+	return rs.scripts.Run(domainConfig.PostProcessScript, property)
+}
+
+// AIThreshold retorna o limiar de IA configurado (0 se não definido)
+func (rs *RuntimeState) AIThreshold() time.Duration {
+	snapshot := rs.Snapshot()
+	if snapshot == nil {
+		return 0
+	}
+	return time.Duration(snapshot.Settings.AIThresholdMinutes) * time.Minute
+}
+
+// Wait bloqueia até que seja permitido requisitar o domínio
+func (rs *RuntimeState) Wait(host string) {
+	rs.domainThrottle().Wait(host)
+}
+
+// Configure atualiza os intervalos do limitador
+func (dt *DomainThrottle) Configure(defaultInterval time.Duration, intervals map[string]time.Duration) {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	dt.defaultInterval = defaultInterval
+	dt.intervals = intervals
+}
+
+// Wait reserva o próximo horário livre para o domínio e dorme até ele
+func (dt *DomainThrottle) Wait(host string) {
+	domain := repository.NormalizeDomain(host)
+
+	dt.mutex.Lock()
+	interval := dt.baseInterval(domain)
+	if state, exists := dt.adaptive[domain]; exists {
+		interval += state.penalty
+	}
+
+	now := time.Now()
+	slot := dt.nextSlot[domain]
+	if interval <= 0 && !slot.After(now) {
+		dt.mutex.Unlock()
+		return
+	}
+	if slot.Before(now) {
+		slot = now
+	}
+	dt.nextSlot[domain] = slot.Add(interval)
+	dt.mutex.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// domainInterval converte o rate limit do domínio em intervalo mínimo entre requisições
+func domainInterval(rateLimit repository.DomainRateLimit) time.Duration {
+	interval := time.Duration(rateLimit.DelayMs) * time.Millisecond
+	if rateLimit.RequestsPerMinute > 0 {
+		if perRequest := time.Minute / time.Duration(rateLimit.RequestsPerMinute); perRequest > interval {
+			interval = perRequest
+		}
+	}
+	return interval
+}
@@ -0,0 +1,348 @@
+package crawler
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// defaultScriptTimeout limita a execução de cada script de pós-processamento
+const defaultScriptTimeout = 200 * time.Millisecond
+
+// Limites de memória dos scripts: pilha de chamadas, pilha de dados e tamanho das strings geradas
+// pelas funções de string (o tempo limite não interrompe uma única chamada que aloca muita memória)
+const (
+	scriptCallStackSize       = 64
+	scriptRegistrySize        = 1024
+	scriptRegistryMaxSize     = 64 * 1024
+	maxScriptStringLength     = 1 << 20
+	maxScriptFormatWidthDigit = 2 // largura/precisão de string.format até 99, como no Lua
+)
+
+// scriptFormatWidth encontra largura e precisão das diretivas de string.format
+var scriptFormatWidth = regexp.MustCompile(`%[-+ #0]*(\d*)(?:\.(\d*))?`)
+
+// Campos do imóvel expostos à tabela global "property" dos scripts
+var (
+	scriptStringFields = []string{"endereco", "cidade", "bairro", "cep", "descricao", "valor_texto", "tipo_imovel"}
+	scriptNumberFields = []string{"valor", "area_total", "area_util"}
+	scriptIntFields    = []string{"quartos", "banheiros", "fotos"}
+)
+
+// scriptBlockedGlobals são removidas do ambiente dos scripts (sem acesso a arquivos)
+var scriptBlockedGlobals = []string{"dofile", "loadfile", "require", "module"}
+
+// ScriptHooks executa scripts Lua configurados por domínio (DomainConfig.PostProcessScript)
+// para corrigir particularidades de sites e calcular campos derivados sem recompilar o crawler.
+// O script recebe a tabela global "property" (além de "url" e "domain") e a altera in-place.
+type ScriptHooks struct {
+	mutex   sync.Mutex
+	cache   map[[32]byte]*lua.FunctionProto
+	timeout time.Duration
+}
+
+// NewScriptHooks cria o executor de scripts com cache de scripts compilados
+func NewScriptHooks() *ScriptHooks {
+	return &ScriptHooks{
+		cache:   make(map[[32]byte]*lua.FunctionProto),
+		timeout: defaultScriptTimeout,
+	}
+}
+
+// CompileScript verifica a sintaxe de um script de pós-processamento
+func CompileScript(source string) (*lua.FunctionProto, error) {
+	chunk, err := parse.Parse(strings.NewReader(source), "post_process_script")
+	if err != nil {
+		return nil, fmt.Errorf("invalid script: %v", err)
+	}
+	proto, err := lua.Compile(chunk, "post_process_script")
+	if err != nil {
+		return nil, fmt.Errorf("invalid script: %v", err)
+	}
+	return proto, nil
+}
+
+// Run executa o script sobre o imóvel e retorna os campos alterados (registrados na proveniência)
+func (sh *ScriptHooks) Run(source string, property *repository.Property) ([]string, error) {
+	proto, err := sh.compiled(source)
+	if err != nil {
+		return nil, err
+	}
+
+	state := newScriptState()
+	defer state.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sh.timeout)
+	defer cancel()
+	state.SetContext(ctx)
+
+	table := propertyToTable(state, property)
+	state.SetGlobal("property", table)
+	state.SetGlobal("url", lua.LString(property.URL))
+	state.SetGlobal("domain", lua.LString(repository.NormalizeDomain(property.URL)))
+
+	state.Push(state.NewFunctionFromProto(proto))
+	if err := state.PCall(0, 0, nil); err != nil {
+		return nil, fmt.Errorf("script failed: %v", err)
+	}
+
+	if err := checkScriptStrings(table); err != nil {
+		return nil, fmt.Errorf("script failed: %v", err)
+	}
+
+	before := *property
+	tableToProperty(table, property)
+	return repository.RecordChangedFields(before, property, repository.ExtractorScript), nil
+}
+
+// compiled retorna o script compilado, usando o cache pelo hash do código
+func (sh *ScriptHooks) compiled(source string) (*lua.FunctionProto, error) {
+	key := sha256.Sum256([]byte(source))
+
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	if proto, exists := sh.cache[key]; exists {
+		return proto, nil
+	}
+	proto, err := CompileScript(source)
+	if err != nil {
+		return nil, err
+	}
+	sh.cache[key] = proto
+	return proto, nil
+}
+
+// newScriptState cria um interpretador apenas com as bibliotecas base, string, table e math, com a
+// pilha limitada e as funções que geram strings grandes protegidas por limitScriptStrings
+func newScriptState() *lua.LState {
+	state := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   scriptCallStackSize,
+		RegistrySize:    scriptRegistrySize,
+		RegistryMaxSize: scriptRegistryMaxSize,
+	})
+	libs := []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	}
+	for _, lib := range libs {
+		state.Push(state.NewFunction(lib.open))
+		state.Push(lua.LString(lib.name))
+		state.Call(1, 0)
+	}
+	for _, name := range scriptBlockedGlobals {
+		state.SetGlobal(name, lua.LNil)
+	}
+	limitScriptStrings(state)
+	return state
+}
+
+// limitScriptStrings recusa, antes de alocar, as chamadas de string.rep, string.format,
+// string.gsub e table.concat cujo resultado passaria de maxScriptStringLength
+func limitScriptStrings(state *lua.LState) {
+	wrap := func(libName, name string, check func(L *lua.LState) int) {
+		lib, ok := state.GetGlobal(libName).(*lua.LTable)
+		if !ok {
+			return
+		}
+		original, ok := lib.RawGetString(name).(*lua.LFunction)
+		if !ok || original.GFunction == nil {
+			return
+		}
+		lib.RawSetString(name, state.NewFunction(func(L *lua.LState) int {
+			if size := check(L); size > maxScriptStringLength {
+				L.RaiseError("%s.%s result exceeds %d bytes", libName, name, maxScriptStringLength)
+			}
+			return original.GFunction(L)
+		}))
+	}
+
+	wrap(lua.StringLibName, "rep", func(L *lua.LState) int {
+		count := L.OptInt(2, 0)
+		if count <= 0 {
+			return 0
+		}
+		size := len(L.CheckString(1)) + len(L.OptString(3, ""))
+		if size > 0 && count > maxScriptStringLength/size {
+			return maxScriptStringLength + 1
+		}
+		return size * count
+	})
+	wrap(lua.StringLibName, "format", func(L *lua.LState) int {
+		for _, directive := range scriptFormatWidth.FindAllStringSubmatch(L.CheckString(1), -1) {
+			if len(directive[1]) > maxScriptFormatWidthDigit || len(directive[2]) > maxScriptFormatWidthDigit {
+				return maxScriptStringLength + 1
+			}
+		}
+		size := len(L.CheckString(1))
+		for i := 2; i <= L.GetTop(); i++ {
+			size += len(lua.LVAsString(L.Get(i)))
+		}
+		return size
+	})
+	wrap(lua.StringLibName, "gsub", func(L *lua.LState) int {
+		size := len(L.CheckString(1))
+		if repl, ok := L.Get(3).(lua.LString); ok && len(repl) > 0 {
+			// Cada posição da string pode ser substituída (ex: padrão vazio)
+			if size+1 > maxScriptStringLength/len(repl) {
+				return maxScriptStringLength + 1
+			}
+			size += (size + 1) * len(repl)
+		}
+		return size
+	})
+	wrap(lua.TabLibName, "concat", func(L *lua.LState) int {
+		table := L.CheckTable(1)
+		separator := len(L.OptString(2, ""))
+		size := 0
+		for i := 1; i <= table.Len() && size <= maxScriptStringLength; i++ {
+			size += len(lua.LVAsString(table.RawGetInt(i))) + separator
+		}
+		return size
+	})
+}
+
+// checkScriptStrings recusa o resultado do script quando algum campo de texto passa do limite
+// (strings montadas com o operador "..", que não passa pelas funções protegidas)
+func checkScriptStrings(table *lua.LTable) error {
+	var err error
+	table.ForEach(func(key, value lua.LValue) {
+		if text, ok := value.(lua.LString); ok && len(text) > maxScriptStringLength && err == nil {
+			err = fmt.Errorf("field %s exceeds %d bytes", lua.LVAsString(key), maxScriptStringLength)
+		}
+		if nested, ok := value.(*lua.LTable); ok && err == nil {
+			nested.ForEach(func(_, item lua.LValue) {
+				if text, ok := item.(lua.LString); ok && len(text) > maxScriptStringLength && err == nil {
+					err = fmt.Errorf("field %s exceeds %d bytes", lua.LVAsString(key), maxScriptStringLength)
+				}
+			})
+		}
+	})
+	return err
+}
+
+// propertyToTable converte o imóvel na tabela exposta ao script
+func propertyToTable(state *lua.LState, property *repository.Property) *lua.LTable {
+	values := scriptFieldValues(property)
+	table := state.NewTable()
+	for _, field := range scriptStringFields {
+		table.RawSetString(field, lua.LString(values[field].(string)))
+	}
+	for _, field := range scriptNumberFields {
+		table.RawSetString(field, lua.LNumber(values[field].(float64)))
+	}
+	for _, field := range scriptIntFields {
+		table.RawSetString(field, lua.LNumber(values[field].(int)))
+	}
+
+	features := state.NewTable()
+	for _, feature := range property.Caracteristicas {
+		features.Append(lua.LString(feature))
+	}
+	table.RawSetString("caracteristicas", features)
+
+	custom := state.NewTable()
+	for name, value := range property.CustomFields {
+		switch v := value.(type) {
+		case string:
+			custom.RawSetString(name, lua.LString(v))
+		case bool:
+			custom.RawSetString(name, lua.LBool(v))
+		case int:
+			custom.RawSetString(name, lua.LNumber(v))
+		case float64:
+			custom.RawSetString(name, lua.LNumber(v))
+		}
+	}
+	table.RawSetString("custom_fields", custom)
+	return table
+}
+
+// tableToProperty aplica ao imóvel os valores deixados pelo script na tabela
+func tableToProperty(table *lua.LTable, property *repository.Property) {
+	str := func(field string) string { return lua.LVAsString(table.RawGetString(field)) }
+	num := func(field string) float64 { return float64(lua.LVAsNumber(table.RawGetString(field))) }
+
+	property.Endereco = str("endereco")
+	property.Cidade = str("cidade")
+	property.Bairro = str("bairro")
+	property.CEP = str("cep")
+	property.Descricao = str("descricao")
+	property.ValorTexto = str("valor_texto")
+	property.TipoImovel = str("tipo_imovel")
+	property.Valor = num("valor")
+	property.AreaTotal = num("area_total")
+	property.AreaUtil = num("area_util")
+	property.Quartos = int(num("quartos"))
+	property.Banheiros = int(num("banheiros"))
+	property.Fotos = int(num("fotos"))
+	property.SemFotos = property.Fotos == 0
+
+	if features, ok := table.RawGetString("caracteristicas").(*lua.LTable); ok {
+		property.Caracteristicas = nil
+		features.ForEach(func(_, value lua.LValue) {
+			if feature := strings.TrimSpace(lua.LVAsString(value)); feature != "" {
+				property.Caracteristicas = append(property.Caracteristicas, feature)
+			}
+		})
+	}
+
+	custom, ok := table.RawGetString("custom_fields").(*lua.LTable)
+	if !ok {
+		return
+	}
+	values := make(map[string]interface{})
+	custom.ForEach(func(key, value lua.LValue) {
+		switch v := value.(type) {
+		case lua.LString:
+			values[lua.LVAsString(key)] = string(v)
+		case lua.LNumber:
+			values[lua.LVAsString(key)] = float64(v)
+		case lua.LBool:
+			values[lua.LVAsString(key)] = bool(v)
+		}
+	})
+	for name := range property.CustomFields {
+		if _, exists := values[name]; !exists {
+			property.SetCustomField(name, nil, repository.ExtractorScript)
+		}
+	}
+	for name, value := range values {
+		if current, exists := property.CustomField(name); !exists || fmt.Sprint(current) != fmt.Sprint(value) {
+			property.SetCustomField(name, value, repository.ExtractorScript)
+		}
+	}
+}
+
+// scriptFieldValues lista os valores atuais dos campos expostos ao script
+func scriptFieldValues(property *repository.Property) map[string]interface{} {
+	return map[string]interface{}{
+		"endereco":    property.Endereco,
+		"cidade":      property.Cidade,
+		"bairro":      property.Bairro,
+		"cep":         property.CEP,
+		"descricao":   property.Descricao,
+		"valor_texto": property.ValorTexto,
+		"tipo_imovel": property.TipoImovel,
+		"valor":       property.Valor,
+		"area_total":  property.AreaTotal,
+		"area_util":   property.AreaUtil,
+		"quartos":     property.Quartos,
+		"banheiros":   property.Banheiros,
+		"fotos":       property.Fotos,
+	}
+}
@@ -0,0 +1,317 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formatos aceitos nos arquivos de seeds
+const (
+	SeedFormatJSON = "json" // array de URLs ou de objetos {url, city, tags}
+	SeedFormatCSV  = "csv"  // colunas url,city,tags (cabeçalho opcional; tags separadas por ";")
+	SeedFormatYAML = "yaml" // lista de URLs ou de objetos, na raiz ou em "seeds:"
+	SeedFormatOPML = "opml" // outlines com htmlUrl/url/xmlUrl; pastas e category viram tags
+	SeedFormatText = "text" // uma URL por linha, com comentários "#" e numeração "1|https://..."
+)
+
+// Seed é uma URL inicial com os metadados opcionais do arquivo
+type Seed struct {
+	URL  string   `json:"url" yaml:"url"`
+	City string   `json:"city,omitempty" yaml:"city,omitempty"` // "Cidade-UF"
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// UnmarshalJSON aceita a seed como objeto ou apenas a URL
+func (s *Seed) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &s.URL)
+	}
+	type plain Seed
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// UnmarshalYAML aceita a seed como objeto ou apenas a URL
+func (s *Seed) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.URL = node.Value
+		return nil
+	}
+	type plain Seed
+	return node.Decode((*plain)(s))
+}
+
+// LoadSeedFile lê as seeds do arquivo no formato indicado pela extensão (.json, .csv, .yaml/.yml,
+// .opml/.xml); outras extensões são identificadas pelo conteúdo e, por fim, lidas como texto
+func LoadSeedFile(path string) ([]Seed, error) {
+	seeds, _, err := loadSeedFile(path)
+	return seeds, err
+}
+
+// loadSeedFile lê as seeds e retorna também o formato identificado
+func loadSeedFile(path string) ([]Seed, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read seed file: %v", err)
+	}
+	format := DetectSeedFormat(path, data)
+	seeds, err := ParseSeeds(data, format)
+	if err != nil {
+		return nil, format, fmt.Errorf("invalid %s seed file %s: %v", format, filepath.Base(path), err)
+	}
+	return seeds, format, nil
+}
+
+// DetectSeedFormat identifica o formato pela extensão ou, sem extensão conhecida, pelo conteúdo
+func DetectSeedFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return SeedFormatJSON
+	case ".csv":
+		return SeedFormatCSV
+	case ".yaml", ".yml":
+		return SeedFormatYAML
+	case ".opml", ".xml":
+		return SeedFormatOPML
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")), bytes.HasPrefix(trimmed, []byte("{")):
+		return SeedFormatJSON
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return SeedFormatOPML
+	}
+	return SeedFormatText
+}
+
+// ParseSeeds interpreta o conteúdo no formato informado. Nos formatos estruturados, entradas
+// sem URL http(s) são erro; no texto, linhas que não são URLs são ignoradas.
+func ParseSeeds(data []byte, format string) ([]Seed, error) {
+	var seeds []Seed
+	var err error
+	switch format {
+	case SeedFormatJSON:
+		seeds, err = parseJSONSeeds(data)
+	case SeedFormatCSV:
+		seeds, err = parseCSVSeeds(data)
+	case SeedFormatYAML:
+		seeds, err = parseYAMLSeeds(data)
+	case SeedFormatOPML:
+		seeds, err = parseOPMLSeeds(data)
+	case SeedFormatText:
+		return parseTextSeeds(data), nil
+	default:
+		return nil, fmt.Errorf("unknown seed format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range seeds {
+		seeds[i].URL = strings.TrimSpace(seeds[i].URL)
+		if !isHTTPURL(seeds[i].URL) {
+			return nil, fmt.Errorf("seed %d: invalid URL %q", i+1, seeds[i].URL)
+		}
+		seeds[i].City = strings.TrimSpace(seeds[i].City)
+	}
+	return seeds, nil
+}
+
+// SeedURLs retorna as URLs das seeds
+func SeedURLs(seeds []Seed) []string {
+	urls := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		urls = append(urls, seed.URL)
+	}
+	return urls
+}
+
+// parseJSONSeeds lê um array de seeds ou um objeto {"seeds": [...]}
+func parseJSONSeeds(data []byte) ([]Seed, error) {
+	var seeds []Seed
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapper struct {
+			Seeds []Seed `json:"seeds"`
+		}
+		err := json.Unmarshal(trimmed, &wrapper)
+		return wrapper.Seeds, err
+	}
+	err := json.Unmarshal(data, &seeds)
+	return seeds, err
+}
+
+// parseYAMLSeeds lê uma lista de seeds ou um mapa com a chave "seeds"
+func parseYAMLSeeds(data []byte) ([]Seed, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	var seeds []Seed
+	document := root.Content[0]
+	if document.Kind == yaml.MappingNode {
+		var wrapper struct {
+			Seeds []Seed `yaml:"seeds"`
+		}
+		err := document.Decode(&wrapper)
+		return wrapper.Seeds, err
+	}
+	err := document.Decode(&seeds)
+	return seeds, err
+}
+
+// parseCSVSeeds lê as colunas url,city,tags. Com cabeçalho (alguma coluna "url"), as colunas são
+// localizadas pelo nome e uma coluna "state" completa a cidade; linhas "#" são comentários.
+func parseCSVSeeds(data []byte) ([]Seed, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"url": 0, "city": 1, "tags": 2, "state": -1}
+	var seeds []Seed
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return seeds, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 0 && !isHTTPURL(strings.TrimSpace(record[0])) {
+			header := make(map[string]int)
+			for i, name := range record {
+				header[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			if _, hasURL := header["url"]; hasURL {
+				for name := range columns {
+					columns[name] = -1
+					if index, exists := header[name]; exists {
+						columns[name] = index
+					}
+				}
+				continue
+			}
+		}
+
+		field := func(name string) string {
+			if index := columns[name]; index >= 0 && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		seed := Seed{URL: field("url"), City: field("city"), Tags: splitSeedTags(field("tags"), ";|")}
+		if state := field("state"); seed.City != "" && state != "" {
+			seed.City += "-" + state
+		}
+		seeds = append(seeds, seed)
+	}
+}
+
+// opmlOutline é um item do OPML; outlines sem URL agrupam outros (pastas)
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	URL      string        `xml:"url,attr"`
+	HTMLURL  string        `xml:"htmlUrl,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	City     string        `xml:"city,attr"`
+	Category string        `xml:"category,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// parseOPMLSeeds lê os outlines com URL (htmlUrl, url ou xmlUrl). Os nomes das pastas e o
+// atributo category viram tags; o atributo city vale também para os outlines internos.
+func parseOPMLSeeds(data []byte) ([]Seed, error) {
+	var document struct {
+		Body struct {
+			Outlines []opmlOutline `xml:"outline"`
+		} `xml:"body"`
+	}
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	var seeds []Seed
+	var walk func(outlines []opmlOutline, city string, tags []string)
+	walk = func(outlines []opmlOutline, city string, tags []string) {
+		for _, outline := range outlines {
+			outlineCity := city
+			if outline.City != "" {
+				outlineCity = outline.City
+			}
+			outlineTags := append(append([]string(nil), tags...), splitSeedTags(outline.Category, ",/")...)
+
+			rawURL := firstNonEmpty(outline.HTMLURL, outline.URL, outline.XMLURL)
+			if rawURL != "" {
+				seeds = append(seeds, Seed{URL: rawURL, City: outlineCity, Tags: outlineTags})
+			}
+			if len(outline.Outlines) > 0 {
+				if name := firstNonEmpty(outline.Text, outline.Title); rawURL == "" && name != "" {
+					outlineTags = append(outlineTags, name)
+				}
+				walk(outline.Outlines, outlineCity, outlineTags)
+			}
+		}
+	}
+	walk(document.Body.Outlines, "", nil)
+	return seeds, nil
+}
+
+// parseTextSeeds lê uma URL por linha (List-site.ini), ignorando vazias, comentários "#" e
+// linhas que não são URLs, e removendo a numeração "1|"
+func parseTextSeeds(data []byte) []Seed {
+	var seeds []Seed
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, after, found := strings.Cut(line, "|"); found {
+			line = strings.TrimSpace(after)
+		}
+		if isHTTPURL(line) {
+			seeds = append(seeds, Seed{URL: line})
+		}
+	}
+	return seeds
+}
+
+// isHTTPURL indica se o texto é uma URL http(s)
+func isHTTPURL(text string) bool {
+	return strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://")
+}
+
+// splitSeedTags separa as tags por qualquer um dos separadores, sem vazias
+func splitSeedTags(raw, separators string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(raw, func(r rune) bool { return strings.ContainsRune(separators, r) }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// firstNonEmpty retorna o primeiro valor não vazio
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
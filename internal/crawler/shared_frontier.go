@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// DefaultFrontierLeaseTTL é a validade padrão da lease de uma URL no registro compartilhado
+const DefaultFrontierLeaseTTL = 30 * time.Minute
+
+// SharedFrontier evita que jobs simultâneos (ex: duas cidades que compartilham um portal) busquem
+// a mesma URL. Antes de cada visita o job obtém a lease da URL no registro compartilhado; URLs
+// com lease de outro job são puladas. Páginas buscadas mantêm a lease até expirar, enquanto
+// falhas liberam a URL para que outro job possa tentar.
+type SharedFrontier struct {
+	leases  repository.URLLeaseRepository
+	owner   string
+	ttl     time.Duration
+	logger  *logger.Logger
+	skipped int64
+}
+
+// NewSharedFrontier cria o frontier compartilhado do job identificado por owner
+func NewSharedFrontier(leases repository.URLLeaseRepository, owner string, ttl time.Duration) *SharedFrontier {
+	if ttl <= 0 {
+		ttl = DefaultFrontierLeaseTTL
+	}
+	return &SharedFrontier{
+		leases: leases,
+		owner:  owner,
+		ttl:    ttl,
+		logger: logger.NewLogger("shared_frontier"),
+	}
+}
+
+// FrontierOwner identifica o job no registro compartilhado (host + job); fora de um job
+// registrado usa o processo e o instante de início
+func FrontierOwner(jobID string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	if jobID == "" {
+		jobID = fmt.Sprintf("pid-%d-%d", os.Getpid(), time.Now().UnixNano())
+	}
+	return host + "/" + jobID
+}
+
+// Owner retorna o identificador do job no registro
+func (sf *SharedFrontier) Owner() string {
+	return sf.owner
+}
+
+// Claim obtém a lease da URL; false se outro job já a está buscando. Falhas no registro
+// não bloqueiam o crawling (a URL é visitada como se não houvesse registro).
+func (sf *SharedFrontier) Claim(url string) bool {
+	acquired, err := sf.leases.AcquireURLLease(context.Background(), url, sf.owner, sf.ttl)
+	if err != nil {
+		sf.logger.WithField("url", url).WithError(err).Warn("Failed to acquire URL lease, visiting anyway")
+		return true
+	}
+	if !acquired {
+		atomic.AddInt64(&sf.skipped, 1)
+		sf.logger.WithField("url", url).Debug("URL leased by another job, skipping")
+	}
+	return acquired
+}
+
+// Release libera a lease da URL (ex: após uma falha na busca)
+func (sf *SharedFrontier) Release(url string) {
+	if err := sf.leases.ReleaseURLLease(context.Background(), url, sf.owner); err != nil {
+		sf.logger.WithField("url", url).WithError(err).Warn("Failed to release URL lease")
+	}
+}
+
+// Skipped retorna quantas URLs foram puladas por estarem com outro job
+func (sf *SharedFrontier) Skipped() int {
+	return int(atomic.LoadInt64(&sf.skipped))
+}
@@ -0,0 +1,462 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// ShutdownPhase etapa do encerramento coordenado, executadas sempre na ordem declarada
+type ShutdownPhase int
+
+const (
+	ShutdownStopIntake    ShutdownPhase = iota // para de aceitar novas URLs/requisições
+	ShutdownDrainPipeline                      // aguarda as páginas em processamento
+	ShutdownFlushAI                            // processa o lote pendente da IA
+	ShutdownFlushWrites                        // grava escritas pendentes no repositório
+	ShutdownCheckpoint                         // persiste o ponto de retomada
+)
+
+var shutdownPhases = []ShutdownPhase{
+	ShutdownStopIntake,
+	ShutdownDrainPipeline,
+	ShutdownFlushAI,
+	ShutdownFlushWrites,
+	ShutdownCheckpoint,
+}
+
+// String retorna o nome da etapa para logs e relatórios
+func (p ShutdownPhase) String() string {
+	switch p {
+	case ShutdownStopIntake:
+		return "stop_intake"
+	case ShutdownDrainPipeline:
+		return "drain_pipeline"
+	case ShutdownFlushAI:
+		return "flush_ai"
+	case ShutdownFlushWrites:
+		return "flush_writes"
+	case ShutdownCheckpoint:
+		return "checkpoint"
+	default:
+		return "unknown"
+	}
+}
+
+// ShutdownFunc ação executada em uma etapa do encerramento
+type ShutdownFunc func(ctx context.Context) error
+
+type shutdownHook struct {
+	id   int
+	name string
+	fn   ShutdownFunc
+}
+
+// pendingAIFlusher processa o lote pendente da IA e devolve os imóveis com o resultado
+// (implementado por ai.GeminiService)
+type pendingAIFlusher interface {
+	FlushPending(ctx context.Context) ([]repository.Property, error)
+}
+
+// propertySaver grava um imóvel no repositório do engine
+type propertySaver interface {
+	Save(ctx context.Context, property repository.Property) error
+}
+
+// ShutdownStepResult resultado de uma ação do encerramento
+type ShutdownStepResult struct {
+	Phase    string        `json:"phase"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+	Skipped  bool          `json:"skipped,omitempty"`
+}
+
+// ShutdownReport resumo do encerramento coordenado
+type ShutdownReport struct {
+	Steps    []ShutdownStepResult `json:"steps"`
+	Duration time.Duration        `json:"duration"`
+	TimedOut bool                 `json:"timed_out"`
+}
+
+// Failed retorna true se alguma ação falhou ou foi pulada pelo prazo
+func (r *ShutdownReport) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Error != "" || step.Skipped {
+			return true
+		}
+	}
+	return false
+}
+
+// ShutdownCoordinator executa o encerramento em etapas ordenadas com prazo total
+type ShutdownCoordinator struct {
+	mutex    sync.Mutex
+	hooks    map[ShutdownPhase][]shutdownHook
+	nextID   int
+	deadline time.Duration
+	once     sync.Once
+	started  int32
+	finished chan struct{}
+	report   *ShutdownReport
+	logger   *logger.Logger
+}
+
+// NewShutdownCoordinator cria um coordenador com o prazo total informado
+func NewShutdownCoordinator(deadline time.Duration) *ShutdownCoordinator {
+	if deadline <= 0 {
+		deadline = 30 * time.Second
+	}
+
+	return &ShutdownCoordinator{
+		hooks:    make(map[ShutdownPhase][]shutdownHook),
+		deadline: deadline,
+		finished: make(chan struct{}),
+		logger:   logger.NewLogger("shutdown_coordinator"),
+	}
+}
+
+// Register adiciona uma ação a uma etapa do encerramento e retorna a função que a remove
+func (sc *ShutdownCoordinator) Register(phase ShutdownPhase, name string, fn ShutdownFunc) func() {
+	if fn == nil {
+		return func() {}
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.nextID++
+	id := sc.nextID
+	sc.hooks[phase] = append(sc.hooks[phase], shutdownHook{id: id, name: name, fn: fn})
+
+	return func() {
+		sc.mutex.Lock()
+		defer sc.mutex.Unlock()
+
+		hooks := sc.hooks[phase]
+		for i, hook := range hooks {
+			if hook.id == id {
+				sc.hooks[phase] = append(hooks[:i:i], hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Shutdown executa todas as etapas em ordem. Usa um contexto próprio (não o contexto
+// já cancelado pelo sinal) limitado pelo prazo configurado; ações que não couberem
+// no prazo são registradas como puladas. Chamadas repetidas retornam o mesmo relatório.
+func (sc *ShutdownCoordinator) Shutdown() *ShutdownReport {
+	sc.once.Do(func() {
+		atomic.StoreInt32(&sc.started, 1)
+		sc.report = sc.run()
+		close(sc.finished)
+	})
+	return sc.report
+}
+
+// InProgress informa se o encerramento já foi iniciado
+func (sc *ShutdownCoordinator) InProgress() bool {
+	return atomic.LoadInt32(&sc.started) == 1
+}
+
+// Wait bloqueia até o fim do encerramento, se ele tiver sido iniciado
+func (sc *ShutdownCoordinator) Wait() {
+	if sc.InProgress() {
+		<-sc.finished
+	}
+}
+
+func (sc *ShutdownCoordinator) run() *ShutdownReport {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), sc.deadline)
+	defer cancel()
+
+	sc.mutex.Lock()
+	hooks := make(map[ShutdownPhase][]shutdownHook, len(sc.hooks))
+	for phase, phaseHooks := range sc.hooks {
+		hooks[phase] = append([]shutdownHook(nil), phaseHooks...)
+	}
+	sc.mutex.Unlock()
+
+	sc.logger.WithField("deadline", sc.deadline.String()).Info("Starting coordinated shutdown")
+
+	report := &ShutdownReport{}
+	for _, phase := range shutdownPhases {
+		for _, hook := range hooks[phase] {
+			step := ShutdownStepResult{Phase: phase.String(), Name: hook.name}
+
+			if ctx.Err() != nil {
+				step.Skipped = true
+				report.TimedOut = true
+				report.Steps = append(report.Steps, step)
+				sc.logger.WithFields(map[string]interface{}{
+					"phase": step.Phase,
+					"name":  step.Name,
+				}).Warn("Shutdown step skipped, deadline exceeded")
+				continue
+			}
+
+			stepStart := time.Now()
+			if err := runShutdownHook(ctx, hook.fn); err != nil {
+				step.Error = err.Error()
+				if ctx.Err() != nil {
+					report.TimedOut = true
+				}
+			}
+			step.Duration = time.Since(stepStart)
+			report.Steps = append(report.Steps, step)
+
+			fields := map[string]interface{}{
+				"phase":    step.Phase,
+				"name":     step.Name,
+				"duration": step.Duration.String(),
+			}
+			if step.Error != "" {
+				sc.logger.WithFields(fields).WithField("error", step.Error).Warn("Shutdown step failed")
+			} else {
+				sc.logger.WithFields(fields).Info("Shutdown step completed")
+			}
+		}
+	}
+
+	report.Duration = time.Since(start)
+	sc.logger.WithFields(map[string]interface{}{
+		"steps":     len(report.Steps),
+		"duration":  report.Duration.String(),
+		"timed_out": report.TimedOut,
+	}).Info("Coordinated shutdown finished")
+
+	return report
+}
+
+// runShutdownHook executa a ação sem ultrapassar o prazo, mesmo que ela ignore o contexto
+func runShutdownHook(ctx context.Context, fn ShutdownFunc) (err error) {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic durante encerramento: %v", r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("prazo de encerramento excedido: %v", ctx.Err())
+	}
+}
+
+// registerCrawlShutdown registra as etapas de encerramento de um engine de crawling
+// e retorna a função que as remove (ex: quando o crawling termina normalmente)
+func registerCrawlShutdown(
+	sc *ShutdownCoordinator,
+	engine string,
+	lifecycle *crawlLifecycle,
+	aiService pendingAIFlusher,
+	repo interface{},
+	checkpoint func() CrawlCheckpoint,
+	checkpointPath string,
+) func() {
+	if sc == nil {
+		return func() {}
+	}
+
+	var unregister []func()
+
+	unregister = append(unregister, sc.Register(ShutdownStopIntake, engine, func(ctx context.Context) error {
+		lifecycle.StopIntake()
+		return nil
+	}))
+
+	unregister = append(unregister, sc.Register(ShutdownDrainPipeline, engine, lifecycle.Drain))
+
+	// As gravações do engine são diretas no repositório; só o lote da IA fica pendente
+	if aiService != nil {
+		saver, _ := repo.(propertySaver)
+		unregister = append(unregister, sc.Register(ShutdownFlushAI, engine, func(ctx context.Context) error {
+			return flushAIBatch(ctx, aiService, saver)
+		}))
+	}
+
+	if checkpoint != nil && checkpointPath != "" {
+		unregister = append(unregister, sc.Register(ShutdownCheckpoint, engine, func(ctx context.Context) error {
+			state := checkpoint()
+			state.Engine = engine
+			return SaveCrawlCheckpoint(checkpointPath, state)
+		}))
+	}
+
+	return func() {
+		for _, fn := range unregister {
+			fn()
+		}
+	}
+}
+
+// flushAIBatch processa o lote pendente da IA e grava o resultado de cada imóvel no repositório
+func flushAIBatch(ctx context.Context, aiService pendingAIFlusher, saver propertySaver) error {
+	properties, err := aiService.FlushPending(ctx)
+	if saver == nil {
+		return err
+	}
+
+	for _, property := range properties {
+		if saveErr := saver.Save(ctx, property); saveErr != nil && err == nil {
+			err = fmt.Errorf("erro ao gravar imóvel do lote da IA (%s): %v", property.URL, saveErr)
+		}
+	}
+	return err
+}
+
+// crawlLifecycle controla a entrada de novas URLs e o término de um crawling,
+// permitindo que o encerramento pare a ingestão e aguarde as páginas em andamento
+type crawlLifecycle struct {
+	stopping int32
+	mutex    sync.Mutex
+	done     chan struct{}
+	seeds    []string
+	started  map[string]bool
+}
+
+// beginCrawl marca o início de um crawling com as URLs iniciais informadas
+func (cl *crawlLifecycle) beginCrawl(seeds []string) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	cl.done = make(chan struct{})
+	cl.seeds = append([]string(nil), seeds...)
+	cl.started = make(map[string]bool, len(seeds))
+}
+
+// markSeedStarted registra que uma URL inicial já foi enviada ao collector
+func (cl *crawlLifecycle) markSeedStarted(seed string) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	if cl.started != nil {
+		cl.started[seed] = true
+	}
+}
+
+// endCrawl sinaliza que o crawling terminou (todas as páginas foram drenadas)
+func (cl *crawlLifecycle) endCrawl() {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	if cl.done != nil {
+		close(cl.done)
+		cl.done = nil
+	}
+}
+
+// StopIntake impede que novas URLs sejam visitadas; páginas em andamento continuam
+func (cl *crawlLifecycle) StopIntake() {
+	atomic.StoreInt32(&cl.stopping, 1)
+}
+
+// IntakeStopped informa se a entrada de novas URLs foi interrompida
+func (cl *crawlLifecycle) IntakeStopped() bool {
+	return atomic.LoadInt32(&cl.stopping) == 1
+}
+
+// Drain aguarda o término do crawling em andamento ou o fim do contexto
+func (cl *crawlLifecycle) Drain(ctx context.Context) error {
+	cl.mutex.Lock()
+	done := cl.done
+	cl.mutex.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("pipeline não drenado: %v", ctx.Err())
+	}
+}
+
+// PendingSeeds retorna as URLs iniciais que ainda não foram enviadas ao collector
+func (cl *crawlLifecycle) PendingSeeds() []string {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	var pending []string
+	for _, seed := range cl.seeds {
+		if !cl.started[seed] {
+			pending = append(pending, seed)
+		}
+	}
+	return pending
+}
+
+// CrawlCheckpoint ponto de retomada gravado ao encerrar um crawling
+type CrawlCheckpoint struct {
+	Engine      string                 `json:"engine"`
+	SavedAt     time.Time              `json:"saved_at"`
+	PendingURLs []string               `json:"pending_urls"`
+	VisitedURLs int                    `json:"visited_urls"`
+	Stats       map[string]interface{} `json:"stats,omitempty"`
+}
+
+// SaveCrawlCheckpoint grava o checkpoint de forma atômica (arquivo temporário + rename)
+func SaveCrawlCheckpoint(path string, checkpoint CrawlCheckpoint) error {
+	if path == "" {
+		return nil
+	}
+
+	if checkpoint.SavedAt.IsZero() {
+		checkpoint.SavedAt = time.Now()
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar checkpoint: %v", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório do checkpoint: %v", err)
+		}
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar checkpoint: %v", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("erro ao finalizar checkpoint: %v", err)
+	}
+
+	return nil
+}
+
+// LoadCrawlCheckpoint lê um checkpoint gravado anteriormente (nil se não existir)
+func LoadCrawlCheckpoint(path string) (*CrawlCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao ler checkpoint: %v", err)
+	}
+
+	var checkpoint CrawlCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar checkpoint: %v", err)
+	}
+
+	return &checkpoint, nil
+}
@@ -1,11 +1,16 @@
 package crawler
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
 	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/gocolly/colly"
@@ -18,10 +23,31 @@ type SimpleRecursiveCrawler struct {
 	preciseClassifier *PrecisePropertyClassifier
 	extractor         *DataExtractor
 	validator         *PropertyValidator
+	multiUnit         *MultiUnitDetector
+	discovery         *DiscoveryTracker
+	runtime           *RuntimeState
+	apiFetcher        *APIFetcher
+	control           *JobControl
+	frontier          *SharedFrontier // registro de URLs em andamento compartilhado com jobs simultâneos
+	coverage          *CoverageEstimator
+	coverageReport    []repository.DomainCoverage
+	errors            *ErrorTracker
+	recovery          *CallbackRecovery
 	logger            *logger.Logger
 	visitedURLs       map[string]bool
 	maxDepth          int
 	currentDepth      map[string]int
+	priorityEntries   []FeedEntry     // anúncios vindos de feeds, visitados antes das seeds
+	priorityCrawled   map[string]bool // entradas prioritárias processadas
+	priorityMutex     sync.Mutex
+	skipPaths         *SkipPathLearner                  // caminhos sem anúncios aprendidos entre crawlings (nil desativado)
+	pdf               PDFTextExtractor                  // extração de texto dos folhetos em PDF (nil desativado)
+	whatsAppLeads     repository.WhatsAppLeadRepository // leads dos links de WhatsApp dos catálogos (nil desativado)
+	limits            PageLimits                        // timeout por requisição e aborto de páginas lentas ou grandes
+	experiment        *ClassifierExperiment             // experimento A/B dos limiares do classificador (nil desativado)
+	archive           ArchiveFetcher                    // cópia arquivada dos anúncios que passam a retornar 404 (nil desativado)
+
+	crawlLifecycle
 }
 
 // NewSimpleRecursiveCrawler cria um novo crawler recursivo simples
@@ -29,19 +55,180 @@ func NewSimpleRecursiveCrawler(
 	propertyRepo repository.PropertyRepository,
 	urlRepo repository.URLRepository,
 ) *SimpleRecursiveCrawler {
+	tracker := NewErrorTracker()
+	discovery := NewDiscoveryTracker()
+	runtime := NewRuntimeState()
 	return &SimpleRecursiveCrawler{
 		repository:        propertyRepo,
 		urlRepo:           urlRepo,
 		preciseClassifier: NewPrecisePropertyClassifier(),
 		extractor:         NewDataExtractor(),
 		validator:         NewPropertyValidator(),
+		multiUnit:         NewMultiUnitDetector(),
+		discovery:         discovery,
+		runtime:           runtime,
+		apiFetcher:        NewAPIFetcher(runtime, discovery),
+		control:           NewJobControl(),
+		coverage:          NewCoverageEstimator(),
+		errors:            tracker,
+		recovery:          NewCallbackRecovery("simple_recursive_crawler", tracker),
 		logger:            logger.NewLogger("simple_recursive_crawler"),
 		visitedURLs:       make(map[string]bool),
 		maxDepth:          15, // Limite de 15 níveis para encontrar mais anúncios
+		limits:            DefaultPageLimits(),
 		currentDepth:      make(map[string]int),
 	}
 }
 
+// ApplyRuntimeConfig aplica configuração recarregada sem interromper o crawling
+func (src *SimpleRecursiveCrawler) ApplyRuntimeConfig(snapshot *config.RuntimeSnapshot) {
+	src.runtime.Set(snapshot)
+	if snapshot != nil {
+		src.logger.WithField("version", snapshot.Version).Info("Runtime config applied")
+	}
+}
+
+// SetJobControl associa o controle de cancelamento/pausa do job em execução
+func (src *SimpleRecursiveCrawler) SetJobControl(control *JobControl) {
+	if control != nil {
+		src.control = control
+		src.recovery.SetJobControl(control)
+	}
+}
+
+// SetSharedFrontier ativa o registro compartilhado de URLs em andamento: URLs buscadas por
+// outro job simultâneo são puladas
+func (src *SimpleRecursiveCrawler) SetSharedFrontier(frontier *SharedFrontier) {
+	src.frontier = frontier
+}
+
+// SetDomainThrottle compartilha o limitador por domínio (e o atraso aprendido com 429/503) entre crawlings
+func (src *SimpleRecursiveCrawler) SetDomainThrottle(throttle *DomainThrottle) {
+	src.runtime.SetThrottle(throttle)
+}
+
+// SetErrorTracker compartilha o agregador de erros (exposto em GET /crawler/errors)
+func (src *SimpleRecursiveCrawler) SetErrorTracker(tracker *ErrorTracker) {
+	if tracker != nil {
+		src.errors = tracker
+		src.recovery.SetErrorTracker(tracker)
+	}
+}
+
+// SetPanicSnapshotDir ativa a gravação do HTML das páginas que causaram panic nos callbacks
+func (src *SimpleRecursiveCrawler) SetPanicSnapshotDir(dir string) {
+	src.recovery.SetSnapshotDir(dir)
+}
+
+// SetDomainAllowlist ativa o modo estrito de domínios: só são seguidos links para os domínios
+// das seeds e das configurações por domínio
+func (src *SimpleRecursiveCrawler) SetDomainAllowlist(allowlist *DomainAllowlist) {
+	src.runtime.SetAllowlist(allowlist)
+}
+
+// SetSkipPathLearner ativa o aprendizado dos caminhos que nunca levam a anúncios
+func (src *SimpleRecursiveCrawler) SetSkipPathLearner(learner *SkipPathLearner) {
+	src.skipPaths = learner
+}
+
+// SetPDFExtractor ativa a extração dos folhetos em PDF linkados pelos sites: o texto do PDF passa
+// pelo mesmo pipeline de extração das páginas e o imóvel é marcado com source_format=pdf
+func (src *SimpleRecursiveCrawler) SetPDFExtractor(extractor PDFTextExtractor) {
+	src.pdf = extractor
+}
+
+// SetWhatsAppLeadStore ativa a leitura dos links de WhatsApp com mensagem pré-preenchida
+// ("catálogo via WhatsApp"): os trechos de anúncio das mensagens são gravados como leads
+func (src *SimpleRecursiveCrawler) SetWhatsAppLeadStore(store repository.WhatsAppLeadRepository) {
+	src.whatsAppLeads = store
+}
+
+// SetPageLimits define o timeout por requisição e os limites de tempo e tamanho das páginas
+func (src *SimpleRecursiveCrawler) SetPageLimits(limits PageLimits) {
+	src.limits = limits
+}
+
+// SetPriorityEntries define anúncios (ex: entradas de feeds RSS/Atom) visitados antes das seeds,
+// apenas como páginas de anúncio, sem explorar os links a partir deles
+func (src *SimpleRecursiveCrawler) SetPriorityEntries(entries []FeedEntry) {
+	src.priorityMutex.Lock()
+	defer src.priorityMutex.Unlock()
+	src.priorityEntries = entries
+	src.priorityCrawled = nil
+}
+
+// CrawledPriorityEntries retorna as entradas prioritárias cujas páginas foram processadas no
+// último crawling (as demais falharam ou não chegaram a ser visitadas)
+func (src *SimpleRecursiveCrawler) CrawledPriorityEntries() []FeedEntry {
+	src.priorityMutex.Lock()
+	defer src.priorityMutex.Unlock()
+
+	var crawled []FeedEntry
+	for _, entry := range src.priorityEntries {
+		if src.priorityCrawled[entry.URL] {
+			crawled = append(crawled, entry)
+		}
+	}
+	return crawled
+}
+
+// recordPriorityCrawled marca a entrada prioritária como processada
+func (src *SimpleRecursiveCrawler) recordPriorityCrawled(url string) {
+	if len(src.priorityEntries) == 0 {
+		return
+	}
+	src.priorityMutex.Lock()
+	defer src.priorityMutex.Unlock()
+	if src.priorityCrawled == nil {
+		src.priorityCrawled = make(map[string]bool)
+	}
+	src.priorityCrawled[url] = true
+}
+
+// SetArchiveFetcher ativa a busca da cópia arquivada (Wayback Machine) dos anúncios já salvos que
+// passam a retornar 404 ou 410: os campos que faltam são completados e o anúncio é marcado como
+// removido
+func (src *SimpleRecursiveCrawler) SetArchiveFetcher(fetcher ArchiveFetcher) {
+	src.archive = fetcher
+}
+
+// SetClassifierExperiment classifica também com os limiares candidatos uma amostra das páginas
+func (src *SimpleRecursiveCrawler) SetClassifierExperiment(experiment *ClassifierExperiment) {
+	src.experiment = experiment
+}
+
+// PreciseClassifier retorna o classificador de anúncios usado pelo crawler (o controle do
+// experimento do classificador)
+func (src *SimpleRecursiveCrawler) PreciseClassifier() *PrecisePropertyClassifier {
+	return src.preciseClassifier
+}
+
+// ClassifierExperimentReport retorna o relatório do experimento do classificador (nil desativado)
+func (src *SimpleRecursiveCrawler) ClassifierExperimentReport() *repository.ClassifierExperimentReport {
+	return src.experiment.Report()
+}
+
+// CoverageReport retorna a estimativa de cobertura por domínio do último crawling
+func (src *SimpleRecursiveCrawler) CoverageReport() []repository.DomainCoverage {
+	return src.coverageReport
+}
+
+// SourceYields retorna as páginas visitadas e os anúncios encontrados por origem de descoberta
+// em cada domínio
+func (src *SimpleRecursiveCrawler) SourceYields() map[string]map[string]repository.SourceYield {
+	return src.discovery.SourceYields()
+}
+
+// RegisterShutdown registra as etapas de encerramento coordenado deste crawler
+func (src *SimpleRecursiveCrawler) RegisterShutdown(sc *ShutdownCoordinator, checkpointPath string) func() {
+	return registerCrawlShutdown(sc, "simple_recursive", &src.crawlLifecycle, nil, src.repository, func() CrawlCheckpoint {
+		return CrawlCheckpoint{
+			PendingURLs: src.PendingSeeds(),
+			VisitedURLs: len(src.visitedURLs),
+		}
+	}, checkpointPath)
+}
+
 // Start inicia o crawling recursivo simples
 func (src *SimpleRecursiveCrawler) Start(ctx context.Context, urls []string) error {
 	src.logger.WithFields(map[string]interface{}{
@@ -49,21 +236,94 @@ func (src *SimpleRecursiveCrawler) Start(ctx context.Context, urls []string) err
 		"max_depth":  src.maxDepth,
 	}).Info("Starting simple recursive crawling")
 
+	src.beginCrawl(urls)
+	defer src.endCrawl()
+
 	// Configurar collector
 	collector := src.setupCollector(ctx)
 
+	// No modo estrito, os domínios das seeds e dos anúncios de feeds são os únicos permitidos
+	src.runtime.AllowSeeds(urls)
+	for _, entry := range src.priorityEntries {
+		src.runtime.AllowSeeds([]string{entry.URL})
+	}
+
+	// Anúncios prioritários (feeds) entram na fila antes das seeds
+	for _, entry := range src.priorityEntries {
+		if src.IntakeStopped() || src.control.Cancelled() {
+			break
+		}
+		if !src.runtime.DiscoverySourceEnabled(entry.URL, repository.DiscoverySourceFeed) {
+			continue
+		}
+		src.discovery.RecordKind(entry.FeedURL, DiscoveryKindFeed)
+		src.discovery.RecordLink(entry.FeedURL, entry.URL)
+		src.currentDepth[entry.URL] = src.maxDepth
+		src.visit(collector, entry.URL)
+	}
+
 	// Iniciar crawling para cada URL base
 	for _, url := range urls {
+		if src.IntakeStopped() || src.control.Cancelled() {
+			src.logger.WithField("pending_urls", len(src.PendingSeeds())).Info("Intake stopped, skipping remaining seed URLs")
+			break
+		}
+
+		// Domínios com API JSON configurada são lidos direto da API, sem crawling HTML
+		if apiConfig := src.runtime.APIConfig(url); apiConfig != nil {
+			src.markSeedStarted(url)
+			if src.runtime.InCrawlWindow(url) {
+				src.crawlAPI(ctx, url, apiConfig)
+			}
+			continue
+		}
+
 		src.currentDepth[url] = 0 // Profundidade inicial = 0
-		collector.Visit(url)
+		src.discovery.RecordSeed(url)
+		src.markSeedStarted(url)
+		src.visit(collector, url)
 	}
 
 	// Aguardar conclusão
 	collector.Wait()
 
-	src.logger.WithFields(map[string]interface{}{
+	// Visitar URLs adiadas de domínios que foram retomados durante a execução
+	for resumable := src.control.TakeResumable(); len(resumable) > 0 && !src.IntakeStopped(); resumable = src.control.TakeResumable() {
+		src.logger.WithField("urls", len(resumable)).Info("Visiting URLs deferred by paused domains")
+		for _, url := range resumable {
+			src.visit(collector, url)
+		}
+		collector.Wait()
+	}
+
+	if deferred := src.control.DeferredCount(); deferred > 0 {
+		src.logger.WithFields(map[string]interface{}{
+			"deferred_urls":  deferred,
+			"paused_domains": src.control.PausedDomains(),
+		}).Warn("Crawling finished with domains still paused")
+	}
+
+	// Estimar cobertura por domínio (sitemap + totais dos catálogos); pulado no encerramento
+	if !src.IntakeStopped() && !src.control.Cancelled() {
+		src.coverageReport = src.coverage.Report(ctx)
+	}
+
+	// Caminhos sem anúncios só são aprendidos com crawlings completos
+	if !src.IntakeStopped() && !src.control.Cancelled() && src.control.BudgetExhausted() == "" {
+		if _, err := src.skipPaths.Flush(ctx); err != nil {
+			src.logger.WithError(err).Warn("Failed to record path classification stats")
+		}
+	}
+
+	fields := map[string]interface{}{
 		"visited_urls": len(src.visitedURLs),
-	}).Info("Simple recursive crawling completed")
+	}
+	if src.frontier != nil {
+		fields["leased_by_other_jobs"] = src.frontier.Skipped()
+	}
+	src.logger.WithFields(fields).Info("Simple recursive crawling completed")
+	logRejectedDomains(src.logger, src.runtime.Allowlist())
+	logCrawlWindowDeferrals(src.logger, src.runtime)
 
 	return nil
 }
@@ -81,23 +341,86 @@ func (src *SimpleRecursiveCrawler) setupCollector(ctx context.Context) *colly.Co
 		Delay:       2 * time.Second,
 	})
 
+	// Timeout por requisição e aborto das páginas lentas ou grandes (antes dos demais handlers)
+	src.limits.Attach(c, src.errors)
+
+	// Modo estrito de domínios: redirecionamentos para fora da allowlist não são seguidos
+	if handler := src.runtime.RedirectHandler(); handler != nil {
+		c.RedirectHandler = handler
+	}
+
+	// Registro de auditoria das requisições (REQUEST_AUDIT), associado ao job, e prazo das páginas
+	// Cache de DNS compartilhado entre os crawlings (DNS_CACHE_TTL)
+	fetchCollector(c, dnsCollector(c), src.control.JobID(), src.limits)
+
 	// Handler principal - FLUXO RECURSIVO SIMPLES
-	c.OnHTML("html", func(e *colly.HTMLElement) {
+	// Callbacks protegidos: um panic em uma página não interrompe o crawling
+	c.OnHTML("html", src.recovery.HTML(func(e *colly.HTMLElement) {
 		src.handlePage(ctx, e, c)
-	})
+	}))
 
 	// Handler para requisições
-	c.OnRequest(func(r *colly.Request) {
+	c.OnRequest(src.recovery.Request(func(r *colly.Request) {
+		if src.IntakeStopped() {
+			r.Abort()
+			return
+		}
+		// Fora da janela de crawling do domínio a requisição fica para o próximo crawling
+		if !src.runtime.InCrawlWindow(r.URL.String()) {
+			r.Abort()
+			return
+		}
+		// Hosts isolados por falhas seguidas de DNS não ocupam o ritmo do domínio
+		if CurrentDNSCache().Quarantined(r.URL.String()) {
+			r.Abort()
+			return
+		}
+		src.runtime.Acquire(r)
 		src.logger.WithField("url", r.URL.String()).Debug("Visiting URL")
-	})
+	}))
+
+	// Ajusta o ritmo por domínio conforme as respostas (429/503 aumentam o atraso)
+	c.OnResponse(src.recovery.Response(func(r *colly.Response) {
+		emitPageFetched(r)
+		src.runtime.RecordResponse(r)
+		src.control.RecordResponse(len(r.Body), usesProxy(r.Request))
+
+		// Folhetos em PDF não disparam o handler de HTML: o texto é extraído à parte
+		if src.pdf != nil && isPDFResponse(r) {
+			src.handlePDF(ctx, r)
+		}
+	}))
 
 	// Handler para erros
-	c.OnError(func(r *colly.Response, err error) {
-		src.logger.WithFields(map[string]interface{}{
-			"url":         r.Request.URL.String(),
-			"status_code": r.StatusCode,
-		}).Error("Request failed", err)
-	})
+	c.OnError(src.recovery.Error(func(r *colly.Response, err error) {
+		src.control.RecordResponse(len(r.Body), usesProxy(r.Request))
+		if src.runtime.RecordResponse(r) {
+			src.logger.WithFields(map[string]interface{}{
+				"url":         r.Request.URL.String(),
+				"status_code": r.StatusCode,
+				"retry_after": r.Headers.Get("Retry-After"),
+			}).Warn("Request throttled by server, retrying with a longer delay")
+			return
+		}
+		if isRequestTimeout(err) {
+			src.logger.WithField("url", r.Request.URL.String()).Warn("Request timed out, skipping page")
+			src.errors.Record(ErrorTypeSlowPage, r.Request.URL.String(), err.Error(), r.StatusCode)
+		} else {
+			src.logger.WithFields(map[string]interface{}{
+				"url":         r.Request.URL.String(),
+				"status_code": r.StatusCode,
+			}).Error("Request failed", err)
+			src.errors.Record(ErrorTypeFetch, r.Request.URL.String(), err.Error(), r.StatusCode)
+		}
+		if r.StatusCode == http.StatusNotFound || r.StatusCode == http.StatusGone {
+			src.completeFromArchive(ctx, r)
+		}
+
+		// A URL volta a ficar disponível para outros jobs tentarem
+		if src.frontier != nil {
+			src.frontier.Release(r.Request.URL.String())
+		}
+	}))
 
 	return c
 }
@@ -111,6 +434,8 @@ func (src *SimpleRecursiveCrawler) handlePage(ctx context.Context, e *colly.HTML
 		return
 	}
 	src.visitedURLs[url] = true
+	src.discovery.RecordVisit(url)
+	src.recordPriorityCrawled(url)
 
 	// Verificar profundidade máxima
 	depth := src.getCurrentDepth(url)
@@ -129,7 +454,7 @@ func (src *SimpleRecursiveCrawler) handlePage(ctx context.Context, e *colly.HTML
 
 	// PASSO 1: ANALISAR SE É PÁGINA DE ANÚNCIO
 	doc := &goquery.Document{Selection: e.DOM}
-	classificationResult := src.preciseClassifier.ClassifyPage(doc, url)
+	classificationResult := src.classifyPage(doc, url)
 
 	src.logger.WithFields(map[string]interface{}{
 		"url":                    url,
@@ -138,14 +463,33 @@ func (src *SimpleRecursiveCrawler) handlePage(ctx context.Context, e *colly.HTML
 		"score":                  classificationResult.Score,
 		"reason":                 classificationResult.Reason,
 	}).Info("Page classification completed")
+	pageType := "rejected"
+	if classificationResult.IsIndividualProperty {
+		pageType = "property"
+	}
+	emitPageClassified(url, pageType, classificationResult.Confidence, classificationResult.Reason)
 
 	// PASSO 2: SE É ANÚNCIO → SALVAR NO BANCO
 	if classificationResult.IsIndividualProperty {
+		src.skipPaths.RecordListing(src.discovery.Path(url))
 		src.logger.WithField("url", url).Info("Property page detected - extracting data")
-		src.extractAndSaveProperty(ctx, e, url)
+		src.experiment.RecordOutcome(url, src.extractAndSaveProperty(ctx, e, url))
 		return // Não precisa explorar links de uma página de anúncio
 	}
 
+	src.skipPaths.RecordNonProperty(url)
+
+	// Página aceita só pelos limiares candidatos do experimento: extração de teste, sem gravar
+	if src.experiment.NeedsDryRun(url) {
+		src.experiment.RecordOutcome(url, src.dryRunExtract(e, url))
+	}
+
+	// Catálogos via WhatsApp: os links não são seguidos, mas as mensagens viram leads
+	src.saveWhatsAppLeads(ctx, e, url)
+
+	// Registrar o total anunciado pelo catálogo ("X imóveis encontrados") para a estimativa de cobertura
+	src.coverage.RecordCatalogPage(url, e.DOM.Text())
+
 	// PASSO 3: SE NÃO É ANÚNCIO → EXPLORAR TODOS OS LINKS CLICÁVEIS
 	src.logger.WithField("url", url).Info("Not a property page - exploring all clickable elements")
 	clickableLinks := src.extractAllClickableLinks(e, url)
@@ -203,6 +547,7 @@ func (src *SimpleRecursiveCrawler) handlePage(ctx context.Context, e *colly.HTML
 	for _, link := range allLinksToProcess {
 		// Definir profundidade do próximo nível
 		src.currentDepth[link] = depth + 1
+		src.discovery.RecordLink(url, link)
 
 		src.logger.WithFields(map[string]interface{}{
 			"parent_url":  url,
@@ -211,10 +556,28 @@ func (src *SimpleRecursiveCrawler) handlePage(ctx context.Context, e *colly.HTML
 			"is_priority": src.isPriorityLink(link),
 		}).Debug("Visiting child URL")
 
-		collector.Visit(link)
+		src.visit(collector, link)
 	}
 }
 
+// visit envia a URL ao collector se o job não foi cancelado, o domínio não está pausado, o host
+// não está isolado por falhas de DNS e nenhum outro job simultâneo está buscando a URL
+func (src *SimpleRecursiveCrawler) visit(collector *colly.Collector, link string) {
+	if src.IntakeStopped() || CurrentDNSCache().Quarantined(link) || !src.control.AllowVisit(link) {
+		return
+	}
+	if src.frontier != nil && !src.frontier.Claim(link) {
+		return
+	}
+	if err := collector.Visit(link); err != nil {
+		if src.frontier != nil {
+			src.frontier.Release(link)
+		}
+		return
+	}
+	src.control.RecordVisit()
+}
+
 // extractAllClickableLinks extrai TODOS os elementos clicáveis da página
 func (src *SimpleRecursiveCrawler) extractAllClickableLinks(e *colly.HTMLElement, baseURL string) []string {
 	var links []string
@@ -352,11 +715,20 @@ func (src *SimpleRecursiveCrawler) isValidLink(link, parentURL string) bool {
 		return false
 	}
 
+	// Ignorar links bloqueados pela configuração de runtime
+	if !src.runtime.AllowsURL(link) {
+		return false
+	}
+
 	// Ignorar tipos de arquivo não relevantes
 	skipExtensions := []string{
-		".pdf", ".jpg", ".jpeg", ".png", ".gif", ".css", ".js",
+		".jpg", ".jpeg", ".png", ".gif", ".css", ".js",
 		".zip", ".rar", ".doc", ".docx", ".xls", ".xlsx",
 	}
+	// PDFs só são seguidos com a extração de folhetos ativa
+	if src.pdf == nil {
+		skipExtensions = append(skipExtensions, ".pdf")
+	}
 
 	linkLower := strings.ToLower(link)
 	for _, ext := range skipExtensions {
@@ -448,33 +820,233 @@ func (src *SimpleRecursiveCrawler) getCurrentDepth(url string) int {
 }
 
 // extractAndSaveProperty extrai dados da propriedade e salva no banco
-func (src *SimpleRecursiveCrawler) extractAndSaveProperty(ctx context.Context, e *colly.HTMLElement, url string) {
+func (src *SimpleRecursiveCrawler) extractAndSaveProperty(ctx context.Context, e *colly.HTMLElement, url string) string {
 	// Usar o extrator existente
 	property := src.extractor.ExtractProperty(e, url)
 	if property == nil {
 		src.logger.WithField("url", url).Warn("Failed to extract property data")
-		return
+		src.errors.Record(ErrorTypeParse, url, "no property data extracted", 0)
+		return repository.ExperimentOutcomeNoData
+	}
+	if selectors := src.runtime.Selectors(url); len(selectors) > 0 {
+		src.extractor.ApplyDomainSelectors(e, property, selectors)
 	}
 
-	// Validar propriedade
+	return src.saveProperty(ctx, property, url)
+}
+
+// dryRunExtract extrai e valida o anúncio como extractAndSaveProperty, sem gravar nem registrar
+// erros, cobertura ou descoberta; retorna o resultado para o experimento do classificador
+func (src *SimpleRecursiveCrawler) dryRunExtract(e *colly.HTMLElement, url string) string {
+	property := src.extractor.ExtractProperty(e, url)
+	if property == nil {
+		return repository.ExperimentOutcomeNoData
+	}
+	if selectors := src.runtime.Selectors(url); len(selectors) > 0 {
+		src.extractor.ApplyDomainSelectors(e, property, selectors)
+	}
+	if _, err := src.runtime.PostProcess(property); err != nil {
+		src.logger.WithField("url", url).WithError(err).Debug("Post-process script failed in experiment dry run")
+	}
 	if !src.validator.IsValidForSaving(property) {
-		src.logger.WithField("url", url).Warn("Invalid property data extracted")
+		return repository.ExperimentOutcomeInvalid
+	}
+	return repository.ExperimentOutcomeValid
+}
+
+// classifyPage decide se a página é um anúncio individual, pelo experimento do classificador
+// quando ativo
+func (src *SimpleRecursiveCrawler) classifyPage(doc *goquery.Document, url string) PreciseClassificationResult {
+	if src.experiment != nil {
+		return src.experiment.Classify(doc, url)
+	}
+	return src.preciseClassifier.ClassifyPage(doc, url)
+}
+
+// handlePDF extrai o texto de um folheto em PDF e o grava pelo mesmo pipeline das páginas de
+// anúncio; o PDF é tratado como página de anúncio, sem classificação nem links a explorar
+func (src *SimpleRecursiveCrawler) handlePDF(ctx context.Context, r *colly.Response) {
+	url := r.Request.URL.String()
+	if src.visitedURLs[url] {
 		return
 	}
+	src.visitedURLs[url] = true
+	src.discovery.RecordVisit(url)
+	src.recordPriorityCrawled(url)
 
-	// Salvar no banco
-	err := src.repository.Save(ctx, *property)
+	text, err := src.pdf.ExtractText(ctx, r.Body)
 	if err != nil {
-		src.logger.WithFields(map[string]interface{}{
-			"url": url,
-		}).Error("Failed to save property", err)
+		src.logger.WithField("url", url).WithError(err).Warn("Failed to extract PDF text")
+		src.errors.Record(ErrorTypeParse, url, err.Error(), 0)
+		return
+	}
+	element, err := pdfTextElement(r, text)
+	if err != nil {
+		src.errors.RecordError(ErrorTypeParse, url, err)
+		return
+	}
+	emitPageClassified(url, "property", 0, "pdf brochure")
+	src.logger.WithFields(map[string]interface{}{
+		"url":   url,
+		"bytes": len(r.Body),
+	}).Info("PDF brochure detected - extracting data")
+
+	property := src.extractor.ExtractProperty(element, url)
+	if property == nil {
+		src.logger.WithField("url", url).Warn("Failed to extract property data from PDF")
+		src.errors.Record(ErrorTypeParse, url, "no property data extracted from pdf", 0)
+		return
+	}
+	property.SourceFormat = repository.SourceFormatPDF
+	src.skipPaths.RecordListing(src.discovery.Path(url))
+
+	src.saveProperty(ctx, property, url)
+}
+
+// completeFromArchive completa com a cópia arquivada o anúncio já salvo cuja URL passou a
+// retornar 404 ou 410 e o marca como removido; URLs que não são anúncios salvos são ignoradas
+func (src *SimpleRecursiveCrawler) completeFromArchive(ctx context.Context, r *colly.Response) {
+	if src.archive == nil {
+		return
+	}
+	lookup, ok := src.repository.(repository.PropertyLookupRepository)
+	if !ok {
+		return
+	}
+	store, ok := src.repository.(repository.ArchivedListingRepository)
+	if !ok {
+		return
+	}
+
+	url := r.Request.URL.String()
+	stored, err := lookup.FindByURL(ctx, url)
+	if err != nil {
+		src.logger.WithField("url", url).WithError(err).Debug("Failed to load stored property for archive fallback")
+		return
+	}
+	if stored == nil || stored.ListingState == repository.ListingStateDelistedArchived {
+		return
+	}
+
+	page, err := src.archive.FetchArchived(ctx, url)
+	if errors.Is(err, ErrNoArchive) {
+		src.logger.WithField("url", url).WithError(err).Debug("No archived copy of delisted property")
+		return
+	}
+	if err != nil {
+		src.logger.WithField("url", url).WithError(err).Warn("Failed to fetch archived copy of delisted property")
 		return
 	}
 
+	// A cópia passa pelo mesmo extrator das páginas, com a URL original do anúncio
+	var archived *repository.Property
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body)); err == nil {
+		root := doc.Find("html")
+		if root.Length() > 0 {
+			archived = src.extractor.ExtractProperty(colly.NewHTMLElementFromSelectionNode(r, root, root.Nodes[0], 0), url)
+		}
+	}
+
+	filled := repository.MarkDelistedWithArchive(stored, archived, repository.ListingArchive{URL: page.URL, CapturedAt: page.CapturedAt}, time.Now())
+	if err := store.SaveArchivedListing(ctx, *stored); err != nil {
+		reportCrawlFailure(src.logger, src.control, ErrorTypeStorage, url, "Failed to save archived listing", err)
+		src.errors.RecordError(ErrorTypeStorage, url, err)
+		return
+	}
 	src.logger.WithFields(map[string]interface{}{
 		"url":         url,
-		"description": property.Descricao,
-		"value":       property.Valor,
-		"address":     property.Endereco,
-	}).Info("Property saved successfully")
+		"archive_url": page.URL,
+		"captured_at": page.CapturedAt,
+		"filled":      filled,
+	}).Info("Delisted property completed from archived copy")
+}
+
+// saveWhatsAppLeads grava os leads dos links de WhatsApp com trechos de anúncio da página
+func (src *SimpleRecursiveCrawler) saveWhatsAppLeads(ctx context.Context, e *colly.HTMLElement, url string) {
+	if src.whatsAppLeads == nil {
+		return
+	}
+	leads := src.extractor.whatsAppLeadsFromPage(e, url)
+	if len(leads) == 0 {
+		return
+	}
+	if err := src.whatsAppLeads.SaveWhatsAppLeads(ctx, leads); err != nil {
+		src.logger.WithField("url", url).WithError(err).Warn("Failed to save WhatsApp leads")
+		src.errors.RecordError(ErrorTypeStorage, url, err)
+		return
+	}
+	src.logger.WithFields(map[string]interface{}{
+		"url":   url,
+		"leads": len(leads),
+	}).Info("WhatsApp catalog leads saved")
+}
+
+// crawlAPI lê os anúncios da API JSON do domínio e os grava pelo mesmo pipeline das páginas
+func (src *SimpleRecursiveCrawler) crawlAPI(ctx context.Context, seedURL string, apiConfig *repository.DomainAPIConfig) {
+	count, err := src.apiFetcher.Fetch(ctx, seedURL, apiConfig, func(property *repository.Property) bool {
+		if src.IntakeStopped() || src.control.Cancelled() {
+			return false
+		}
+		src.saveProperty(ctx, property, property.URL)
+		return true
+	})
+	if err != nil {
+		src.logger.WithField("url", seedURL).WithError(err).Warn("Listing API crawl failed")
+		src.errors.RecordError(ErrorTypeFetch, seedURL, err)
+		return
+	}
+
+	src.logger.WithFields(map[string]interface{}{
+		"url":        seedURL,
+		"properties": count,
+	}).Info("Listing API crawl completed")
+}
+
+// saveProperty aplica o pós-processamento do domínio, valida e grava o imóvel extraído; retorna o
+// resultado (repository.ExperimentOutcome*) para o experimento do classificador
+func (src *SimpleRecursiveCrawler) saveProperty(ctx context.Context, property *repository.Property, url string) string {
+	// Script de pós-processamento do domínio (DomainConfig.PostProcessScript)
+	if changed, err := src.runtime.PostProcess(property); err != nil {
+		src.logger.WithField("url", url).WithError(err).Warn("Post-process script failed")
+		src.errors.Record(ErrorTypeParse, url, err.Error(), 0)
+	} else if len(changed) > 0 {
+		src.logger.WithFields(map[string]interface{}{
+			"url":    url,
+			"fields": changed,
+		}).Debug("Post-process script changed fields")
+	}
+
+	// Validar propriedade
+	if !src.validator.IsValidForSaving(property) {
+		src.logger.WithField("url", url).Warn("Invalid property data extracted")
+		src.errors.Record(ErrorTypeValidation, url, "property not valid for saving", 0)
+		return repository.ExperimentOutcomeInvalid
+	}
+	src.coverage.RecordPropertyURL(url)
+
+	// Registrar como o anúncio foi descoberto
+	src.discovery.Apply(property)
+
+	// Salvar no banco (anúncios multi-unidade geram um registro por unidade)
+	outcome := repository.ExperimentOutcomeStorageError
+	for _, unit := range src.multiUnit.Split(property) {
+		err := src.repository.Save(ctx, *unit)
+		if err != nil {
+			reportCrawlFailure(src.logger, src.control, ErrorTypeStorage, url, "Failed to save property", err)
+			src.errors.RecordError(ErrorTypeStorage, url, err)
+			continue
+		}
+		outcome = repository.ExperimentOutcomeSaved
+		emitPropertySaved(url, unit)
+		src.control.RecordSaved()
+
+		src.logger.WithFields(map[string]interface{}{
+			"url":         url,
+			"description": unit.Descricao,
+			"value":       unit.Valor,
+			"address":     unit.Endereco,
+			"parent_id":   unit.ParentID,
+		}).Info("Property saved successfully")
+	}
+	return outcome
 }
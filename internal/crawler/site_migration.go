@@ -0,0 +1,404 @@
+package crawler
+
+import (
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// DiscoveryMethodMigration identifica os sites importados de List-site.ini / sites.json
+const DiscoveryMethodMigration = "migration"
+
+// SitesFileDeprecationWarning é registrado quando as URLs iniciais ainda são lidas do SITES_FILE
+const SitesFileDeprecationWarning = "Loading seed URLs from SITES_FILE is deprecated: migrate them to the city sites repository with cmd/migrate_sites or POST /cities/migrate"
+
+// Limites da inferência da cidade a partir do slug da URL
+const (
+	migrationCityMaxWords   = 2 // palavras da cidade antes da UF (sem contar conectivos e "sul", "norte"...)
+	migrationMinWordLength  = 3 // palavras menores não são aceitas como parte do nome
+	migrationMinHostSlugLen = 5 // cidades conhecidas mais curtas não são procuradas no domínio
+)
+
+// migrationConnectors ligam as palavras do nome da cidade (pocos-de-caldas, vargem-grande-do-sul)
+var migrationConnectors = map[string]bool{"da": true, "de": true, "do": true, "das": true, "dos": true}
+
+// migrationDirections completam nomes como vargem-grande-do-sul sem contar no limite de palavras
+var migrationDirections = map[string]bool{"sul": true, "norte": true, "leste": true, "oeste": true}
+
+// migrationStopwords são palavras de anúncios que nunca fazem parte do nome da cidade
+var migrationStopwords = map[string]bool{
+	"venda": true, "vende": true, "vendo": true, "aluguel": true, "alugar": true, "locacao": true,
+	"comprar": true, "compra": true, "imovel": true, "imoveis": true, "casa": true, "casas": true,
+	"apartamento": true, "apartamentos": true, "terreno": true, "terrenos": true, "lote": true,
+	"chacara": true, "sitio": true, "fazenda": true, "rural": true, "rurais": true, "urbano": true,
+	"barracao": true, "sobrado": true, "padrao": true, "residencial": true, "comercial": true,
+	"condominio": true, "bairro": true, "centro": true, "jardim": true, "vila": true, "parque": true,
+	"zona": true, "quarto": true, "quartos": true, "suite": true, "suites": true, "garagem": true,
+	"vagas": true, "banheiros": true, "area": true, "total": true, "construida": true,
+	"detalhes": true, "empreendimento": true, "com": true, "para": true, "em": true, "no": true,
+	"na": true, "a": true, "o": true, "e": true,
+}
+
+// migrationAmbiguousStates são UFs que também são palavras comuns ("vende-se", "para") e só são
+// aceitas como segmento inteiro do caminho (/venda/cidade/se/)
+var migrationAmbiguousStates = map[string]bool{
+	"se": true, "to": true, "es": true, "pa": true, "ma": true, "pe": true, "am": true, "al": true, "ap": true,
+}
+
+// SiteFileEntry é uma URL lida de um arquivo de sites
+type SiteFileEntry struct {
+	URL     string `json:"url"`
+	City    string `json:"city,omitempty"` // "Cidade-UF" informada no arquivo (CSV, YAML...)
+	Source  string `json:"source"`         // nome do arquivo de origem
+	Listing bool   `json:"listing"`        // anúncio de referência (List-site.ini) em vez de URL inicial (sites.json)
+}
+
+// SiteMigrationGroup são os sites de uma cidade produzidos pela migração
+type SiteMigrationGroup struct {
+	City  repository.CityInfo   `json:"city"`
+	Sites []repository.SiteInfo `json:"sites"`
+}
+
+// LoadSiteFile lê um arquivo de sites em qualquer formato de LoadSeedFile. Listas em texto
+// (List-site.ini) contêm anúncios de referência; os demais formatos, URLs iniciais.
+func LoadSiteFile(path string) ([]SiteFileEntry, error) {
+	seeds, format, err := loadSeedFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	source := filepath.Base(path)
+	entries := make([]SiteFileEntry, 0, len(seeds))
+	for _, seed := range seeds {
+		entries = append(entries, SiteFileEntry{
+			URL:     seed.URL,
+			City:    seed.City,
+			Source:  source,
+			Listing: format == SeedFormatText,
+		})
+	}
+	return entries, nil
+}
+
+// InferSiteCity deduz a cidade da URL. Os padrões "/cidade/uf/", "/uf/cidade/" e "cidade-uf" do
+// caminho têm prioridade (o nome é trocado pelo da cidade conhecida equivalente, com acentos);
+// sem eles, procura o slug de uma cidade conhecida no caminho e depois no domínio.
+func InferSiteCity(rawURL string, known []repository.CityInfo) (repository.CityInfo, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return repository.CityInfo{}, false
+	}
+
+	var segments [][]string
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if tokens := slugTokens(segment); len(tokens) > 0 {
+			segments = append(segments, tokens)
+		}
+	}
+
+	if city, found := cityFromPathPattern(segments); found {
+		for _, candidate := range known {
+			if strings.EqualFold(candidate.State, city.State) && portalSlug(candidate.Name) == portalSlug(city.Name) {
+				return candidate, true
+			}
+		}
+		return city, true
+	}
+
+	var pathTokens []string
+	for _, tokens := range segments {
+		pathTokens = append(pathTokens, tokens...)
+	}
+	path := "-" + strings.Join(pathTokens, "-") + "-"
+	host := strings.ToLower(strings.TrimPrefix(parsed.Hostname(), "www."))
+
+	var best repository.CityInfo
+	bestLength, bestInPath := 0, false
+	for _, candidate := range known {
+		slug := portalSlug(candidate.Name)
+		if slug == "" {
+			continue
+		}
+		inPath := strings.Contains(path, "-"+slug+"-")
+		compact := strings.ReplaceAll(slug, "-", "")
+		inHost := len(compact) >= migrationMinHostSlugLen && strings.Contains(host, compact)
+		if !inPath && !inHost {
+			continue
+		}
+		// Caminho vence domínio; entre iguais, o nome mais longo (bom-jesus-da-penha e não penha)
+		if (inPath && !bestInPath) || (inPath == bestInPath && len(slug) > bestLength) {
+			best, bestLength, bestInPath = candidate, len(slug), inPath
+		}
+	}
+	return best, bestLength > 0
+}
+
+// cityFromPathPattern procura a UF no caminho: como segmento inteiro (o segmento vizinho é a
+// cidade) ou dentro de um slug (as palavras anteriores ou, sem elas, a seguinte são a cidade)
+func cityFromPathPattern(segments [][]string) (repository.CityInfo, bool) {
+	for i, tokens := range segments {
+		if len(tokens) != 1 || !isStateToken(tokens[0], true) {
+			continue
+		}
+		state := strings.ToUpper(tokens[0])
+		if i > 0 && isCitySegment(segments[i-1]) {
+			return repository.CityInfo{Name: cityName(segments[i-1]), State: state}, true
+		}
+		if i+1 < len(segments) && isCitySegment(segments[i+1]) {
+			return repository.CityInfo{Name: cityName(segments[i+1]), State: state}, true
+		}
+	}
+
+	for _, tokens := range segments {
+		for j := 1; j < len(tokens); j++ {
+			if !isStateToken(tokens[j], false) {
+				continue
+			}
+			words := cityWordsBefore(tokens, j)
+			if len(words) == 0 {
+				words = cityWordsAfter(tokens, j)
+			}
+			if len(words) > 0 {
+				return repository.CityInfo{Name: cityName(words), State: strings.ToUpper(tokens[j])}, true
+			}
+		}
+	}
+	return repository.CityInfo{}, false
+}
+
+// cityWordsBefore coleta as palavras da cidade antes da UF (até migrationCityMaxWords), parando
+// em números e palavras de anúncio
+func cityWordsBefore(tokens []string, stateIndex int) []string {
+	var words []string
+	limit := migrationCityMaxWords
+	for k := stateIndex - 1; k >= 0 && limit > 0; k-- {
+		token := tokens[k]
+		if migrationConnectors[token] && len(words) > 0 && k > 0 {
+			words = append([]string{token}, words...)
+			continue
+		}
+		if !isCityWord(token) {
+			break
+		}
+		words = append([]string{token}, words...)
+		if !migrationDirections[token] {
+			limit--
+		}
+	}
+	for len(words) > 0 && migrationConnectors[words[0]] {
+		words = words[1:]
+	}
+	return words
+}
+
+// cityWordsAfter coleta a cidade depois da UF ("venda-mg-muzambinho"): uma palavra, estendida
+// apenas por conectivos (palavras seguintes costumam ser o bairro)
+func cityWordsAfter(tokens []string, stateIndex int) []string {
+	k := stateIndex + 1
+	if k >= len(tokens) || !isCityWord(tokens[k]) {
+		return nil
+	}
+	words := []string{tokens[k]}
+	for k+2 < len(tokens) && migrationConnectors[tokens[k+1]] && isCityWord(tokens[k+2]) {
+		words = append(words, tokens[k+1], tokens[k+2])
+		k += 2
+	}
+	return words
+}
+
+// isCitySegment indica se o segmento inteiro do caminho pode ser o nome de uma cidade
+func isCitySegment(tokens []string) bool {
+	if len(tokens) == 0 || !isCityWord(tokens[0]) || !isCityWord(tokens[len(tokens)-1]) {
+		return false
+	}
+	for _, token := range tokens {
+		if !isCityWord(token) && !migrationConnectors[token] {
+			return false
+		}
+	}
+	return true
+}
+
+// isCityWord indica se o token pode fazer parte do nome de uma cidade
+func isCityWord(token string) bool {
+	if len(token) < migrationMinWordLength || migrationStopwords[token] {
+		return false
+	}
+	for _, r := range token {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isStateToken indica se o token é uma UF (ambíguas apenas quando ocupam o segmento inteiro)
+func isStateToken(token string, wholeSegment bool) bool {
+	if _, exists := brazilianStates[strings.ToUpper(token)]; !exists || len(token) != 2 {
+		return false
+	}
+	return wholeSegment || !migrationAmbiguousStates[token]
+}
+
+// slugTokens normaliza um segmento do caminho em palavras ("Pocos-de-Caldas" -> pocos, de, caldas)
+func slugTokens(segment string) []string {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		segment = unescaped
+	}
+	segment = strings.NewReplacer("_", " ", ".", " ", "+", " ").Replace(segment)
+	slug := portalSlug(segment)
+	if slug == "" {
+		return nil
+	}
+	return strings.Split(slug, "-")
+}
+
+// cityName monta o nome da cidade a partir das palavras (conectivos em minúsculas)
+func cityName(words []string) string {
+	parts := make([]string, len(words))
+	for i, word := range words {
+		if migrationConnectors[word] {
+			parts[i] = word
+			continue
+		}
+		parts[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(parts, " ")
+}
+
+// PlanSiteMigration agrupa as URLs por domínio e cidade. Cada domínio vira um site de cada cidade
+// deduzida das suas URLs (a URL inicial do sites.json ou, sem ela, a raiz do domínio), guardando os
+// anúncios como URLs de referência. Domínios sem cidade usam fallback (se informado) ou têm as
+// URLs retornadas como não resolvidas.
+func PlanSiteMigration(entries []SiteFileEntry, known []repository.CityInfo, fallback *repository.CityInfo) ([]SiteMigrationGroup, []string) {
+	type domainEntries struct {
+		host    string
+		scheme  string
+		entries []SiteFileEntry
+		cities  map[string]repository.CityInfo
+	}
+	// As cidades deduzidas pelos padrões do caminho completam as conhecidas, para que URLs sem UF
+	// (casa-mococa-4-quartos, biloimoveisguaxupe.com.br) encontrem a cidade pelo nome
+	gazetteer := append([]repository.CityInfo(nil), known...)
+	inferred := make(map[string]bool)
+	for _, city := range known {
+		inferred[cityKey(city)] = true
+	}
+	for _, entry := range entries {
+		if city, found := entrySiteCity(entry, known); found && !inferred[cityKey(city)] {
+			inferred[cityKey(city)] = true
+			gazetteer = append(gazetteer, city)
+		}
+	}
+
+	domains := make(map[string]*domainEntries)
+	var order []string
+	for _, entry := range entries {
+		parsed, err := url.Parse(entry.URL)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		domain := repository.NormalizeDomain(parsed.Host)
+		group, exists := domains[domain]
+		if !exists {
+			group = &domainEntries{host: parsed.Host, scheme: parsed.Scheme, cities: make(map[string]repository.CityInfo)}
+			domains[domain] = group
+			order = append(order, domain)
+		}
+		group.entries = append(group.entries, entry)
+		if city, found := entrySiteCity(entry, gazetteer); found {
+			group.cities[cityKey(city)] = city
+		}
+	}
+
+	now := time.Now()
+	groups := make(map[string]*SiteMigrationGroup)
+	var unresolved []string
+	for _, domain := range order {
+		group := domains[domain]
+		if len(group.cities) == 0 {
+			if fallback == nil {
+				for _, entry := range group.entries {
+					unresolved = append(unresolved, entry.URL)
+				}
+				continue
+			}
+			group.cities[cityKey(*fallback)] = *fallback
+		}
+
+		site := repository.SiteInfo{
+			URL:             group.scheme + "://" + group.host + "/",
+			Name:            domain,
+			Domain:          group.host,
+			Status:          "active",
+			DiscoveredAt:    now,
+			DiscoveryMethod: DiscoveryMethodMigration,
+		}
+		seedFound := false
+		sources := make(map[string]bool)
+		for _, entry := range group.entries {
+			if !sources[entry.Source] {
+				sources[entry.Source] = true
+				site.Source = strings.TrimPrefix(site.Source+","+entry.Source, ",")
+			}
+			if entry.Listing {
+				site.ReferenceURLs = appendUnique(site.ReferenceURLs, entry.URL)
+			} else if !seedFound {
+				site.URL, seedFound = entry.URL, true
+			}
+		}
+
+		for key, city := range group.cities {
+			cityGroup, exists := groups[key]
+			if !exists {
+				cityGroup = &SiteMigrationGroup{City: city}
+				groups[key] = cityGroup
+			}
+			cityGroup.Sites = append(cityGroup.Sites, site)
+		}
+	}
+
+	result := make([]SiteMigrationGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Slice(group.Sites, func(i, j int) bool {
+			return group.Sites[i].Domain < group.Sites[j].Domain
+		})
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return cityKey(result[i].City) < cityKey(result[j].City)
+	})
+	return result, unresolved
+}
+
+// entrySiteCity usa a cidade informada no arquivo ("Cidade-UF") ou, sem ela, a deduzida da URL
+func entrySiteCity(entry SiteFileEntry, known []repository.CityInfo) (repository.CityInfo, bool) {
+	if cities, err := ParseCityList(entry.City); err == nil && len(cities) == 1 {
+		for _, candidate := range known {
+			if cityKey(candidate) == cityKey(cities[0]) {
+				return candidate, true
+			}
+		}
+		return cities[0], true
+	}
+	return InferSiteCity(entry.URL, known)
+}
+
+// cityKey identifica a cidade no agrupamento (UF e nome sem acentos)
+func cityKey(city repository.CityInfo) string {
+	return strings.ToUpper(city.State) + "/" + portalSlug(city.Name)
+}
+
+// appendUnique adiciona o valor à lista se ainda não estiver presente
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
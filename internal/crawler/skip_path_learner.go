@@ -0,0 +1,132 @@
+package crawler
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Limiares padrão para aprender um caminho sem anúncios
+const (
+	DefaultSkipPathMinCrawls = 3
+	DefaultSkipPathMinPages  = 5
+)
+
+// SkipPathLearner acompanha, durante um crawling, como as páginas de cada caminho (primeiro
+// segmento da URL, ex: "/blog", "/contato", "/financiamento") foram classificadas. Um caminho é
+// produtivo quando uma página dele é anúncio ou está no caminho de descoberta de um anúncio.
+// Ao fim do crawling as amostras são somadas às dos crawlings anteriores e os caminhos que nunca
+// levaram a anúncios entram na lista de caminhos ignorados do domínio.
+type SkipPathLearner struct {
+	store   repository.SkipPathRepository
+	policy  repository.SkipPathPolicy
+	logger  *logger.Logger
+	mutex   sync.Mutex
+	samples map[string]*repository.PathClassification // domínio + caminho
+}
+
+// NewSkipPathLearner cria o aprendizado de caminhos; limiares não positivos usam os padrões
+func NewSkipPathLearner(store repository.SkipPathRepository, policy repository.SkipPathPolicy) *SkipPathLearner {
+	if policy.MinCrawls <= 0 {
+		policy.MinCrawls = DefaultSkipPathMinCrawls
+	}
+	if policy.MinPages <= 0 {
+		policy.MinPages = DefaultSkipPathMinPages
+	}
+	return &SkipPathLearner{
+		store:   store,
+		policy:  policy,
+		logger:  logger.NewLogger("skip_path_learner"),
+		samples: make(map[string]*repository.PathClassification),
+	}
+}
+
+// RecordNonProperty registra uma página classificada como não-anúncio
+func (l *SkipPathLearner) RecordNonProperty(rawURL string) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if sample := l.sample(rawURL); sample != nil {
+		sample.NonProperty++
+	}
+}
+
+// RecordListing marca como produtivos os caminhos do anúncio e das páginas que levaram a ele
+// (cada caminho conta uma vez por anúncio)
+func (l *SkipPathLearner) RecordListing(path []repository.DiscoveryStep) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	counted := make(map[*repository.PathClassification]bool)
+	for _, step := range path {
+		if sample := l.sample(step.URL); sample != nil && !counted[sample] {
+			counted[sample] = true
+			sample.Productive++
+		}
+	}
+}
+
+// sample retorna a amostra do caminho da URL (nil para a página inicial ou URL inválida);
+// chamado com o mutex travado
+func (l *SkipPathLearner) sample(rawURL string) *repository.PathClassification {
+	domain, prefix := repository.NormalizeDomain(rawURL), repository.URLPathPrefix(rawURL)
+	if domain == "" || prefix == "" {
+		return nil
+	}
+	key := domain + prefix
+	sample, exists := l.samples[key]
+	if !exists {
+		sample = &repository.PathClassification{Domain: domain, Path: prefix}
+		l.samples[key] = sample
+	}
+	return sample
+}
+
+// Samples retorna as amostras do crawling ordenadas por domínio e caminho
+func (l *SkipPathLearner) Samples() []repository.PathClassification {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	samples := make([]repository.PathClassification, 0, len(l.samples))
+	for _, sample := range l.samples {
+		samples = append(samples, *sample)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].Domain != samples[j].Domain {
+			return samples[i].Domain < samples[j].Domain
+		}
+		return samples[i].Path < samples[j].Path
+	})
+	return samples
+}
+
+// Flush soma as amostras ao acumulado persistido e retorna os caminhos aprendidos neste crawling;
+// deve ser chamado apenas ao fim de um crawling completo
+func (l *SkipPathLearner) Flush(ctx context.Context) ([]repository.PathStats, error) {
+	if l == nil {
+		return nil, nil
+	}
+	samples := l.Samples()
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	learned, err := l.store.RecordPathStats(ctx, samples, l.policy)
+	for _, stats := range learned {
+		l.logger.WithFields(map[string]interface{}{
+			"domain":       stats.Domain,
+			"path":         stats.Path,
+			"crawls":       stats.Crawls,
+			"non_property": stats.NonProperty,
+		}).Info("Learned non-listing path, skipping it in future crawls")
+	}
+	return learned, err
+}
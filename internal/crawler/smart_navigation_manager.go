@@ -8,6 +8,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
 	"github.com/gocolly/colly"
 )
 
@@ -19,6 +20,8 @@ type SmartNavigationManager struct {
 	maxPagesPerSite    int
 	maxDepth           int
 	interactiveManager *InteractiveDiscoveryManager
+	discovery          *DiscoveryTracker
+	runtime            *RuntimeState // estratégias de descoberta desativadas por domínio (nil: todas ativas)
 }
 
 // NavigationResult resultado da análise de navegação
@@ -41,11 +44,33 @@ func NewSmartNavigationManager() *SmartNavigationManager {
 		maxPagesPerSite:    50, // Máximo de páginas por site
 		maxDepth:           5,  // Profundidade máxima
 		interactiveManager: NewInteractiveDiscoveryManager(),
+		discovery:          NewDiscoveryTracker(),
 	}
 }
 
+// SetRuntime aplica as estratégias de descoberta desativadas na configuração de cada domínio
+func (snm *SmartNavigationManager) SetRuntime(runtime *RuntimeState) {
+	snm.runtime = runtime
+}
+
+// SetDeadFilterTracker ativa o descarte, na descoberta interativa, das combinações de filtros
+// que não retornam imóveis
+func (snm *SmartNavigationManager) SetDeadFilterTracker(tracker *DeadFilterTracker) {
+	snm.interactiveManager.SetDeadFilterTracker(tracker)
+}
+
+// IsGeneratedFilterURL informa se a URL foi gerada pela descoberta interativa
+func (snm *SmartNavigationManager) IsGeneratedFilterURL(rawURL string) bool {
+	return snm.interactiveManager.IsGeneratedURL(rawURL)
+}
+
 // AnalyzePage analisa uma página para determinar tipo e extrair links
 func (snm *SmartNavigationManager) AnalyzePage(doc *goquery.Document, currentURL string) NavigationResult {
+	return snm.AnalyzePageWithLinkHeader(doc, currentURL, "")
+}
+
+// AnalyzePageWithLinkHeader analisa a página considerando também o header HTTP Link da resposta
+func (snm *SmartNavigationManager) AnalyzePageWithLinkHeader(doc *goquery.Document, currentURL, linkHeader string) NavigationResult {
 	result := NavigationResult{
 		PropertyLinks:   []string{},
 		PaginationLinks: []string{},
@@ -64,7 +89,7 @@ func (snm *SmartNavigationManager) AnalyzePage(doc *goquery.Document, currentURL
 		result.PropertyLinks = snm.extractPropertyLinksFromCatalog(doc, currentURL)
 
 		// Extrair links de paginação
-		result.PaginationLinks = snm.extractPaginationLinks(doc, currentURL)
+		result.PaginationLinks = snm.extractPaginationLinks(doc, currentURL, linkHeader)
 
 		result.Confidence = 0.9
 		result.Reason = fmt.Sprintf("Catalog page with %d properties and %d pagination links",
@@ -323,8 +348,17 @@ func (snm *SmartNavigationManager) extractPropertyLinksFromCatalog(doc *goquery.
 	return finalLinks
 }
 
-// extractPaginationLinks extrai links de paginação
-func (snm *SmartNavigationManager) extractPaginationLinks(doc *goquery.Document, baseURL string) []string {
+// extractPaginationLinks extrai links de paginação.
+// Usa rel=next/prev e microdata como fonte principal e só recorre às heurísticas de texto se não houver.
+func (snm *SmartNavigationManager) extractPaginationLinks(doc *goquery.Document, baseURL, linkHeader string) []string {
+	if links := snm.extractStandardPaginationLinks(doc, baseURL, linkHeader); len(links) > 0 {
+		snm.logger.WithFields(map[string]interface{}{
+			"url":   baseURL,
+			"links": len(links),
+		}).Debug("Using standard pagination links (rel=next/prev)")
+		return links
+	}
+
 	var links []string
 
 	// Seletores para paginação
@@ -466,21 +500,29 @@ func (snm *SmartNavigationManager) resolveURL(href, baseURL string) string {
 // NavigateFromCatalog navega a partir de uma página de catálogo
 func (snm *SmartNavigationManager) NavigateFromCatalog(collector *colly.Collector, catalogURL string, result NavigationResult) {
 	snm.catalogURLs[catalogURL] = true
+	snm.discovery.RecordKind(catalogURL, DiscoveryKindCatalog)
 
 	// Visitar links de anúncios individuais
 	for _, propertyURL := range result.PropertyLinks {
-		if !snm.visitedURLs[propertyURL] {
+		if !snm.visitedURLs[propertyURL] && snm.runtime.DiscoverySourceEnabled(propertyURL, repository.DiscoverySourceClassifiedLink) {
 			snm.visitedURLs[propertyURL] = true
+			snm.discovery.RecordLink(catalogURL, propertyURL)
+			snm.discovery.RecordSource(propertyURL, repository.DiscoverySourceClassifiedLink)
 			snm.logger.WithField("property_url", propertyURL).Debug("Visiting property from catalog")
 			collector.Visit(propertyURL)
 		}
 	}
 
-	// Visitar páginas de paginação (limitado)
+	// Visitar páginas de paginação (limitado), se a paginação não foi desativada no domínio
+	if !snm.runtime.DiscoverySourceEnabled(catalogURL, repository.DiscoverySourcePagination) {
+		return
+	}
 	visitedPages := 0
 	for _, paginationURL := range result.PaginationLinks {
 		if !snm.visitedURLs[paginationURL] && visitedPages < 3 {
 			snm.visitedURLs[paginationURL] = true
+			snm.discovery.RecordLink(catalogURL, paginationURL)
+			snm.discovery.RecordKind(paginationURL, DiscoveryKindPagination)
 			visitedPages++
 			snm.logger.WithField("pagination_url", paginationURL).Debug("Visiting pagination page")
 			collector.Visit(paginationURL)
@@ -493,13 +535,26 @@ func (snm *SmartNavigationManager) NavigateFromGeneric(collector *colly.Collecto
 	// Visitar links de catálogo encontrados
 	for _, catalogURL := range result.CatalogLinks {
 		if !snm.catalogURLs[catalogURL] && !snm.visitedURLs[catalogURL] {
+			// Combinações geradas dos formulários de busca
+			if snm.interactiveManager.IsGeneratedURL(catalogURL) {
+				if !snm.runtime.DiscoverySourceEnabled(catalogURL, repository.DiscoverySourceInteractiveForm) {
+					continue
+				}
+				snm.discovery.RecordSource(catalogURL, repository.DiscoverySourceInteractiveForm)
+			}
 			snm.visitedURLs[catalogURL] = true
+			snm.discovery.RecordLink(genericURL, catalogURL)
 			snm.logger.WithField("catalog_url", catalogURL).Debug("Visiting catalog from generic page")
 			collector.Visit(catalogURL)
 		}
 	}
 }
 
+// Discovery retorna o rastreador de caminhos de descoberta
+func (snm *SmartNavigationManager) Discovery() *DiscoveryTracker {
+	return snm.discovery
+}
+
 // IsVisited verifica se uma URL já foi visitada
 func (snm *SmartNavigationManager) IsVisited(url string) bool {
 	return snm.visitedURLs[url]
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/dujoseaugusto/go-crawler-project/internal/readability"
 )
 
 // SmartPageClassifier usa padrões aprendidos para classificar páginas com alta precisão
@@ -104,8 +105,8 @@ func (c *ContentValidator) ValidateContent(doc *goquery.Document) (bool, float64
 		return false, 0.0, "Documento HTML inválido"
 	}
 
-	// Extrair todo o texto da página
-	pageText := strings.ToLower(doc.Text())
+	// Extrair o texto do conteúdo principal, sem menus e rodapés
+	pageText := strings.ToLower(readability.FromSelection(doc.Selection).Text)
 
 	// 1. Verificar indicadores de exclusão (páginas que NÃO são anúncios)
 	for _, indicator := range c.excludeIndicators {
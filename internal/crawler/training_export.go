@@ -0,0 +1,244 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dujoseaugusto/go-crawler-project/internal/readability"
+	"github.com/gocolly/colly"
+)
+
+// Formatos do conjunto de treinamento exportado
+const (
+	TrainingFormatCSV   = "csv"
+	TrainingFormatJSONL = "jsonl" // um exemplo JSON por linha
+)
+
+// defaultTrainingSampleSize é o tamanho padrão (em caracteres) da amostra do conteúdo principal
+const defaultTrainingSampleSize = 1000
+
+// TrainingExportOptions controla quais páginas são exportadas e o que cada exemplo contém
+type TrainingExportOptions struct {
+	LabeledOnly bool            // apenas páginas com rótulo humano
+	SampleSize  int             // caracteres da amostra do conteúdo (0 usa 1000)
+	Learner     *PatternLearner // padrões de URL aprendidos (opcional)
+}
+
+// TrainingFeatures são as características calculadas pelas etapas da cascata de classificação
+type TrainingFeatures struct {
+	WordCount          int     `json:"word_count"`
+	PreciseScore       float64 `json:"precise_score"`
+	PreciseConfidence  float64 `json:"precise_confidence"`
+	Requirements       int     `json:"requirements"`
+	Exclusions         int     `json:"exclusions"`
+	HasSpecificPrice   bool    `json:"has_specific_price"`
+	HasPropertyDetails bool    `json:"has_property_details"`
+	HasContactInfo     bool    `json:"has_contact_info"`
+	HasSpecificAddress bool    `json:"has_specific_address"`
+	IsGenericPage      bool    `json:"is_generic_page"`
+	StrictPageType     string  `json:"strict_page_type"`
+	ContentType        string  `json:"content_type"`
+	ContentConfidence  float64 `json:"content_confidence"`
+	URLPatterns        int     `json:"url_patterns"`
+	NumericSlug        bool    `json:"numeric_slug"`
+	LearnedType        string  `json:"learned_type"`
+	LearnedConfidence  float64 `json:"learned_confidence"`
+	IndicatorPrice     bool    `json:"indicator_price"`
+	IndicatorRooms     bool    `json:"indicator_rooms"`
+	IndicatorAddress   bool    `json:"indicator_address"`
+	AIIsProperty       *bool   `json:"ai_is_property,omitempty"`
+	AIConfidence       float64 `json:"ai_confidence,omitempty"`
+	AIPageType         string  `json:"ai_page_type,omitempty"`
+}
+
+// TrainingExample é uma página rotulada do conjunto de treinamento: as características, uma
+// amostra do conteúdo principal, a classe final atribuída pelo crawler e o rótulo humano
+type TrainingExample struct {
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	Features      TrainingFeatures `json:"features"`
+	ContentSample string           `json:"content_sample"`
+	FinalClass    string           `json:"final_class"` // property ou catalog (decisão do crawler recursivo)
+	HumanLabel    *bool            `json:"human_label"` // true = anúncio; nil sem rótulo
+}
+
+// BuildTrainingExamples calcula os exemplos de todas as páginas gravadas do seed set (rótulos de
+// seedset.json), ordenados pela URL. Páginas que não puderem ser lidas são ignoradas.
+func BuildTrainingExamples(ctx context.Context, seedSet *BenchmarkSeedSet, explainer *ClassificationExplainer, opts TrainingExportOptions) ([]TrainingExample, error) {
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = defaultTrainingSampleSize
+	}
+
+	pageURLs := make(map[string]bool)
+	for pageURL := range seedSet.Pages {
+		pageURLs[pageURL] = true
+	}
+	for pageURL := range seedSet.body {
+		pageURLs[pageURL] = true
+	}
+	sorted := make([]string, 0, len(pageURLs))
+	for pageURL := range pageURLs {
+		sorted = append(sorted, pageURL)
+	}
+	sort.Strings(sorted)
+
+	var examples []TrainingExample
+	for _, pageURL := range sorted {
+		if err := ctx.Err(); err != nil {
+			return examples, err
+		}
+		isProperty, labeled := seedSet.Label(pageURL)
+		if opts.LabeledOnly && !labeled {
+			continue
+		}
+		body, found := seedSet.page(pageURL)
+		if !found {
+			continue
+		}
+		example, err := buildTrainingExample(ctx, explainer, pageURL, body, opts)
+		if err != nil {
+			continue
+		}
+		if labeled {
+			example.HumanLabel = &isProperty
+		}
+		examples = append(examples, *example)
+	}
+	return examples, nil
+}
+
+// buildTrainingExample submete a página à cascata de classificação e extrai as características
+func buildTrainingExample(ctx context.Context, explainer *ClassificationExplainer, pageURL string, body []byte, opts TrainingExportOptions) (*TrainingExample, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	response := &colly.Response{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Request:    &colly.Request{URL: parsed, Method: http.MethodGet},
+	}
+	explanation := explainer.explainDocument(ctx, pageURL, response, doc, ExplainEngineRecursive, opts.Learner)
+
+	precise := explanation.Heuristics.Precise
+	features := TrainingFeatures{
+		WordCount:          precise.Details.WordCount,
+		PreciseScore:       precise.Score,
+		PreciseConfidence:  precise.Confidence,
+		Requirements:       len(precise.Details.FoundRequirements),
+		Exclusions:         len(precise.Details.FoundExclusions),
+		HasSpecificPrice:   precise.Details.HasSpecificPrice,
+		HasPropertyDetails: precise.Details.HasPropertyDetails,
+		HasContactInfo:     precise.Details.HasContactInfo,
+		HasSpecificAddress: precise.Details.HasSpecificAddress,
+		IsGenericPage:      precise.Details.IsGenericPage,
+		StrictPageType:     explanation.Heuristics.StrictPageType,
+		ContentType:        explanation.Heuristics.ContentType,
+		ContentConfidence:  explanation.Heuristics.ContentConfidence,
+		URLPatterns:        len(explanation.Patterns.URLPatterns),
+		NumericSlug:        explanation.Patterns.NumericSlug,
+		LearnedType:        explanation.Patterns.LearnedType,
+		LearnedConfidence:  explanation.Patterns.LearnedConfidence,
+		IndicatorPrice:     explanation.Heuristics.Indicators.Price,
+		IndicatorRooms:     explanation.Heuristics.Indicators.Rooms,
+		IndicatorAddress:   explanation.Heuristics.Indicators.Address,
+	}
+	if explanation.AI != nil && explanation.AI.Error == "" {
+		aiIsProperty := explanation.AI.IsPropertyPage
+		features.AIIsProperty = &aiIsProperty
+		features.AIConfidence = explanation.AI.Confidence
+		features.AIPageType = explanation.AI.PageType
+	}
+
+	sample := strings.Join(strings.Fields(readability.FromSelection(doc.Selection).Text), " ")
+	return &TrainingExample{
+		URL:           pageURL,
+		Title:         strings.TrimSpace(explanation.Title),
+		Features:      features,
+		ContentSample: truncateRunes(sample, opts.SampleSize),
+		FinalClass:    explanation.Decision.Action,
+	}, nil
+}
+
+// trainingCSVHeader são as colunas do CSV, na ordem de trainingCSVRecord
+var trainingCSVHeader = []string{
+	"url", "title", "human_label", "final_class",
+	"word_count", "precise_score", "precise_confidence", "requirements", "exclusions",
+	"has_specific_price", "has_property_details", "has_contact_info", "has_specific_address", "is_generic_page",
+	"strict_page_type", "content_type", "content_confidence",
+	"url_patterns", "numeric_slug", "learned_type", "learned_confidence",
+	"indicator_price", "indicator_rooms", "indicator_address",
+	"ai_is_property", "ai_confidence", "ai_page_type",
+	"content_sample",
+}
+
+// WriteTrainingExamples grava os exemplos no formato pedido (csv ou jsonl)
+func WriteTrainingExamples(w io.Writer, format string, examples []TrainingExample) error {
+	switch format {
+	case TrainingFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(trainingCSVHeader); err != nil {
+			return err
+		}
+		for _, example := range examples {
+			if err := writer.Write(trainingCSVRecord(example)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case TrainingFormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, example := range examples {
+			if err := encoder.Encode(example); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown training format %q (use %s or %s)", format, TrainingFormatCSV, TrainingFormatJSONL)
+	}
+}
+
+// trainingCSVRecord converte o exemplo em uma linha do CSV (rótulos ausentes ficam vazios)
+func trainingCSVRecord(example TrainingExample) []string {
+	f := example.Features
+	optionalBool := func(value *bool) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatBool(*value)
+	}
+	number := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	aiConfidence := ""
+	if f.AIIsProperty != nil {
+		aiConfidence = number(f.AIConfidence)
+	}
+
+	return []string{
+		example.URL, example.Title, optionalBool(example.HumanLabel), example.FinalClass,
+		strconv.Itoa(f.WordCount), number(f.PreciseScore), number(f.PreciseConfidence), strconv.Itoa(f.Requirements), strconv.Itoa(f.Exclusions),
+		strconv.FormatBool(f.HasSpecificPrice), strconv.FormatBool(f.HasPropertyDetails), strconv.FormatBool(f.HasContactInfo), strconv.FormatBool(f.HasSpecificAddress), strconv.FormatBool(f.IsGenericPage),
+		f.StrictPageType, f.ContentType, number(f.ContentConfidence),
+		strconv.Itoa(f.URLPatterns), strconv.FormatBool(f.NumericSlug), f.LearnedType, number(f.LearnedConfidence),
+		strconv.FormatBool(f.IndicatorPrice), strconv.FormatBool(f.IndicatorRooms), strconv.FormatBool(f.IndicatorAddress),
+		optionalBool(f.AIIsProperty), aiConfidence, f.AIPageType,
+		example.ContentSample,
+	}
+}
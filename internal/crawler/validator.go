@@ -117,6 +117,10 @@ func (pv *PropertyValidator) validateQuality(property *repository.Property) []st
 		warnings = append(warnings, "Cidade não identificada")
 	}
 
+	if property.SemFotos {
+		warnings = append(warnings, "Anúncio sem fotos")
+	}
+
 	return warnings
 }
 
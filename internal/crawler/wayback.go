@@ -0,0 +1,143 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+)
+
+// maxArchivedPageSize limita o HTML baixado de uma cópia arquivada
+const maxArchivedPageSize = 5 << 20
+
+// waybackTimestampLayout é o formato dos timestamps do Wayback Machine (AAAAMMDDhhmmss)
+const waybackTimestampLayout = "20060102150405"
+
+// ErrNoArchive indica que não há cópia arquivada recente da página
+var ErrNoArchive = errors.New("no recent archived snapshot")
+
+// ArchivedPage é a cópia arquivada de uma página
+type ArchivedPage struct {
+	URL        string    // endereço da cópia no arquivo
+	CapturedAt time.Time // data em que a cópia foi feita
+	Body       []byte    // HTML original da página, sem a barra do arquivo
+}
+
+// ArchiveFetcher busca a cópia arquivada mais recente de uma página
+type ArchiveFetcher interface {
+	FetchArchived(ctx context.Context, pageURL string) (*ArchivedPage, error)
+}
+
+// WaybackFetcher busca as cópias no Wayback Machine pela API de disponibilidade
+type WaybackFetcher struct {
+	apiURL    string
+	maxAge    time.Duration
+	userAgent string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewWaybackFetcher cria o cliente do Wayback Machine com WAYBACK_API_URL e WAYBACK_MAX_AGE
+func NewWaybackFetcher(cfg *config.Config) *WaybackFetcher {
+	return &WaybackFetcher{
+		apiURL:    cfg.WaybackAPIURL,
+		maxAge:    cfg.WaybackMaxAge,
+		userAgent: cfg.GeocoderUserAgent,
+		client:    &http.Client{Timeout: 20 * time.Second, Transport: NewAuditedTransport(nil, "")},
+		now:       time.Now,
+	}
+}
+
+// waybackAvailability é a resposta da API de disponibilidade
+type waybackAvailability struct {
+	ArchivedSnapshots struct {
+		Closest *struct {
+			Available bool   `json:"available"`
+			URL       string `json:"url"`
+			Timestamp string `json:"timestamp"`
+			Status    string `json:"status"`
+		} `json:"closest"`
+	} `json:"archived_snapshots"`
+}
+
+// FetchArchived retorna a cópia mais recente da página com status 200 e no máximo maxAge de
+// idade (ErrNoArchive se não houver)
+func (wf *WaybackFetcher) FetchArchived(ctx context.Context, pageURL string) (*ArchivedPage, error) {
+	query := url.Values{"url": {pageURL}, "timestamp": {wf.now().Format(waybackTimestampLayout)}}
+	var availability waybackAvailability
+	if err := wf.getJSON(ctx, wf.apiURL+"?"+query.Encode(), &availability); err != nil {
+		return nil, err
+	}
+
+	closest := availability.ArchivedSnapshots.Closest
+	if closest == nil || !closest.Available || closest.Status != "200" {
+		return nil, ErrNoArchive
+	}
+	capturedAt, err := time.Parse(waybackTimestampLayout, closest.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wayback timestamp %q: %v", closest.Timestamp, err)
+	}
+	if wf.maxAge > 0 && wf.now().Sub(capturedAt) > wf.maxAge {
+		return nil, fmt.Errorf("%w: latest snapshot from %s", ErrNoArchive, capturedAt.Format("2006-01-02"))
+	}
+
+	body, err := wf.get(ctx, rawSnapshotURL(closest.URL, closest.Timestamp))
+	if err != nil {
+		return nil, err
+	}
+	return &ArchivedPage{URL: closest.URL, CapturedAt: capturedAt, Body: body}, nil
+}
+
+// rawSnapshotURL pede a página original, sem a barra e os links reescritos pelo arquivo
+// (".../web/20240101000000/..." vira ".../web/20240101000000id_/...")
+func rawSnapshotURL(snapshotURL, timestamp string) string {
+	return strings.Replace(snapshotURL, "/"+timestamp+"/", "/"+timestamp+"id_/", 1)
+}
+
+// getJSON busca e decodifica uma resposta JSON
+func (wf *WaybackFetcher) getJSON(ctx context.Context, target string, result interface{}) error {
+	body, err := wf.get(ctx, target)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode wayback response: %v", err)
+	}
+	return nil
+}
+
+// get busca a URL no arquivo, limitando o tamanho da resposta
+func (wf *WaybackFetcher) get(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wayback request: %v", err)
+	}
+	if wf.userAgent != "" {
+		req.Header.Set("User-Agent", wf.userAgent)
+	}
+
+	resp, err := wf.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("wayback request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wayback returned status %d for %s", resp.StatusCode, target)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArchivedPageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wayback response: %v", err)
+	}
+	if len(body) > maxArchivedPageSize {
+		return nil, fmt.Errorf("archived page larger than %d bytes", maxArchivedPageSize)
+	}
+	return body, nil
+}
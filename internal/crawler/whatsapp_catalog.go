@@ -0,0 +1,124 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"github.com/gocolly/colly"
+)
+
+// whatsAppLeadFieldWeight é a confiança somada por campo reconhecido na mensagem
+const whatsAppLeadFieldWeight = 0.1
+
+// minWhatsAppLeadFields é o mínimo de campos do imóvel (tipo, quartos, valor, área, código) para
+// a mensagem ser tratada como trecho de anúncio e não como um "Olá, gostaria de informações"
+const minWhatsAppLeadFields = 2
+
+var (
+	whatsAppPhoneDigits = regexp.MustCompile(`\d+`)
+	whatsAppCodePattern = regexp.MustCompile(`(?i)\b(?:c[óo]d(?:igo)?|ref(?:er[êe]ncia)?)\.?\s*:?\s*#?\s*([A-Za-z]*\d[A-Za-z0-9-]*)`)
+	whatsAppBairro      = regexp.MustCompile(`(?i)\bbairro:?\s+([A-Za-zÀ-ÿ][A-Za-zÀ-ÿ' ]{2,40}?)(?:\s*[,.;/()-]|\s+(?:em|com|por|de|r\$)\s|$)`)
+	whatsAppCentro      = regexp.MustCompile(`(?i)\b(?:no|na)\s+centro\b`)
+	whatsAppCity        = regexp.MustCompile(`([A-ZÀ-Ý][a-zà-ÿ]+(?:\s+(?:de|do|da|dos|das)?\s*[A-ZÀ-Ý][a-zà-ÿ]+)*)\s*[/-]\s*[A-Z]{2}\b`)
+)
+
+// whatsAppLinkText retorna o telefone e a mensagem pré-preenchida de um link de WhatsApp
+// (wa.me/<telefone>?text=, api.whatsapp.com/send?phone=&text= ou whatsapp://send); ok é false
+// para outros links ou links sem mensagem
+func whatsAppLinkText(href string) (phone, text string, ok bool) {
+	parsed, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return "", "", false
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	query := parsed.Query()
+	switch {
+	case host == "wa.me":
+		phone = strings.Trim(parsed.Path, "/")
+	case host == "api.whatsapp.com" || host == "web.whatsapp.com" || strings.EqualFold(parsed.Scheme, "whatsapp"):
+		phone = query.Get("phone")
+	default:
+		return "", "", false
+	}
+
+	text = strings.Join(strings.Fields(query.Get("text")), " ")
+	if text == "" {
+		return "", "", false
+	}
+	return strings.Join(whatsAppPhoneDigits.FindAllString(phone, -1), ""), text, true
+}
+
+// ParseWhatsAppLead monta um lead de baixa confiança a partir do texto pré-preenchido de um link
+// de WhatsApp encontrado em pageURL; nil quando o link não é de WhatsApp ou a mensagem não
+// descreve um imóvel
+func (e *DataExtractor) ParseWhatsAppLead(href, pageURL string) *repository.WhatsAppLead {
+	phone, text, ok := whatsAppLinkText(href)
+	if !ok {
+		return nil
+	}
+
+	lead := &repository.WhatsAppLead{
+		Domain:    repository.NormalizeDomain(pageURL),
+		PageURL:   pageURL,
+		Phone:     phone,
+		Text:      text,
+		Quartos:   e.extractRooms(text),
+		Banheiros: e.extractBathrooms(text),
+		AreaTotal: utils.ParseArea(text),
+	}
+	if tipo := e.extractPropertyType(text); tipo != "Outro" {
+		lead.TipoImovel = tipo
+	}
+	if strings.Contains(strings.ToUpper(text), "R$") {
+		lead.Valor = utils.ParseAmount(text)
+	}
+	if match := whatsAppCodePattern.FindStringSubmatch(text); match != nil {
+		lead.Codigo = match[1]
+	}
+
+	fields := 0
+	for _, found := range []bool{lead.TipoImovel != "", lead.Quartos > 0, lead.Valor > 0, lead.AreaTotal > 0, lead.Codigo != ""} {
+		if found {
+			fields++
+		}
+	}
+	if fields < minWhatsAppLeadFields {
+		return nil
+	}
+
+	if match := whatsAppBairro.FindStringSubmatch(text); match != nil {
+		lead.Bairro = strings.TrimSpace(match[1])
+	} else if whatsAppCentro.MatchString(text) {
+		lead.Bairro = "Centro"
+	}
+	if match := whatsAppCity.FindStringSubmatch(text); match != nil {
+		lead.Cidade = match[1]
+	}
+	if lead.Bairro != "" || lead.Cidade != "" {
+		fields++
+	}
+	lead.Confidence = float64(fields) * whatsAppLeadFieldWeight
+	if lead.Confidence > repository.MaxWhatsAppLeadConfidence {
+		lead.Confidence = repository.MaxWhatsAppLeadConfidence
+	}
+	lead.ID = repository.WhatsAppLeadID(lead.Domain, lead.Phone, lead.Text)
+	return lead
+}
+
+// whatsAppLeadsFromPage retorna os leads dos links de WhatsApp da página, sem repetições
+func (e *DataExtractor) whatsAppLeadsFromPage(element *colly.HTMLElement, pageURL string) []repository.WhatsAppLead {
+	var leads []repository.WhatsAppLead
+	seen := make(map[string]bool)
+	element.ForEach("a[href]", func(_ int, link *colly.HTMLElement) {
+		lead := e.ParseWhatsAppLead(link.Attr("href"), pageURL)
+		if lead == nil || seen[lead.ID] {
+			return
+		}
+		seen[lead.ID] = true
+		leads = append(leads, *lead)
+	})
+	return leads
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrorEvent é uma falha grave encaminhada a um serviço externo de rastreamento de erros
+type ErrorEvent struct {
+	Level     LogLevel
+	Message   string
+	Err       error
+	Component string
+	Tags      map[string]string      // indexáveis no serviço (job_id, domain, error_type...)
+	Extra     map[string]interface{} // contexto adicional (url, status, campos do logger)
+	Stack     string                 // stack trace (panics)
+	Timestamp time.Time
+}
+
+// ErrorReporter recebe as falhas graves da aplicação (ex: Sentry). Report não deve bloquear.
+type ErrorReporter interface {
+	Report(event ErrorEvent)
+	Flush(timeout time.Duration) bool
+}
+
+var (
+	reporterMutex sync.RWMutex
+	errorReporter ErrorReporter
+)
+
+// SetErrorReporter define o reporter global (nil desativa o envio)
+func SetErrorReporter(reporter ErrorReporter) {
+	reporterMutex.Lock()
+	defer reporterMutex.Unlock()
+	errorReporter = reporter
+}
+
+// currentReporter retorna o reporter configurado (nil se nenhum)
+func currentReporter() ErrorReporter {
+	reporterMutex.RLock()
+	defer reporterMutex.RUnlock()
+	return errorReporter
+}
+
+// ReportError encaminha o evento ao reporter configurado; sem reporter não faz nada
+func ReportError(event ErrorEvent) {
+	reporter := currentReporter()
+	if reporter == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Message == "" && event.Err != nil {
+		event.Message = event.Err.Error()
+	}
+	reporter.Report(event)
+}
+
+// CapturePanic reporta um valor recuperado de panic com o stack trace atual
+func CapturePanic(recovered interface{}, component string, tags map[string]string) {
+	ReportError(ErrorEvent{
+		Level:     FATAL,
+		Message:   fmt.Sprintf("panic: %v", recovered),
+		Component: component,
+		Tags:      tags,
+		Stack:     string(debug.Stack()),
+	})
+}
+
+// RecoverAndReport deve ser usado com defer no início de main/goroutines: reporta o panic,
+// aguarda o envio e o propaga novamente
+func RecoverAndReport(component string) {
+	if r := recover(); r != nil {
+		CapturePanic(r, component, nil)
+		FlushErrorReporter(5 * time.Second)
+		panic(r)
+	}
+}
+
+// FlushErrorReporter aguarda o envio dos eventos pendentes (usar antes de encerrar o processo)
+func FlushErrorReporter(timeout time.Duration) bool {
+	reporter := currentReporter()
+	if reporter == nil {
+		return true
+	}
+	return reporter.Flush(timeout)
+}
+
+// Report registra o erro em log e o encaminha ao reporter externo com os campos do logger
+func (l *Logger) Report(message string, err error, tags map[string]string) {
+	l.log(ERROR, message, err)
+
+	extra := make(map[string]interface{}, len(l.fields))
+	for k, v := range l.fields {
+		extra[k] = v
+	}
+	ReportError(ErrorEvent{
+		Level:     ERROR,
+		Message:   message,
+		Err:       err,
+		Component: l.component,
+		Tags:      tags,
+		Extra:     extra,
+	})
+}
@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sentryQueueSize limita os eventos pendentes; excedentes são descartados para não bloquear o crawling
+const sentryQueueSize = 100
+
+// SentryReporter envia eventos ao Sentry pelo endpoint de envelopes, em segundo plano
+type SentryReporter struct {
+	dsn         string
+	endpoint    string
+	publicKey   string
+	environment string
+	release     string
+	serverName  string
+	client      *http.Client
+	queue       chan ErrorEvent
+	pending     sync.WaitGroup
+	logger      *Logger
+}
+
+// NewSentryReporter cria um reporter a partir do DSN (https://<chave>@<host>/<projeto>)
+func NewSentryReporter(dsn, environment, release string) (*SentryReporter, error) {
+	parsed, err := url.Parse(strings.TrimSpace(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry DSN: %v", err)
+	}
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: missing public key")
+	}
+
+	path := strings.Trim(parsed.Path, "/")
+	projectID := path
+	prefix := ""
+	if idx := strings.LastIndex(path, "/"); idx != -1 {
+		prefix = "/" + path[:idx]
+		projectID = path[idx+1:]
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: missing project id")
+	}
+
+	hostname, _ := os.Hostname()
+	reporter := &SentryReporter{
+		dsn:         parsed.String(),
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/envelope/", parsed.Scheme, parsed.Host, prefix, projectID),
+		publicKey:   parsed.User.Username(),
+		environment: environment,
+		release:     release,
+		serverName:  hostname,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan ErrorEvent, sentryQueueSize),
+		logger:      NewLogger("sentry_reporter"),
+	}
+	go reporter.run()
+	return reporter, nil
+}
+
+// SetupSentry ativa o envio de erros ao Sentry quando o DSN é informado (retorna false se vazio)
+func SetupSentry(dsn, environment, release string) (bool, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return false, nil
+	}
+	reporter, err := NewSentryReporter(dsn, environment, release)
+	if err != nil {
+		return false, err
+	}
+	SetErrorReporter(reporter)
+	return true, nil
+}
+
+// Report enfileira o evento; se a fila estiver cheia, o evento é descartado
+func (sr *SentryReporter) Report(event ErrorEvent) {
+	sr.pending.Add(1)
+	select {
+	case sr.queue <- event:
+	default:
+		sr.pending.Done()
+		sr.logger.Warn("Sentry queue full, dropping event")
+	}
+}
+
+// Flush aguarda o envio dos eventos enfileirados até o timeout
+func (sr *SentryReporter) Flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		sr.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// run envia os eventos da fila, um por vez
+func (sr *SentryReporter) run() {
+	for event := range sr.queue {
+		if err := sr.send(event); err != nil {
+			sr.logger.WithError(err).Warn("Failed to send event to Sentry")
+		}
+		sr.pending.Done()
+	}
+}
+
+// send serializa o evento como envelope e o envia ao Sentry
+func (sr *SentryReporter) send(event ErrorEvent) error {
+	body, err := sr.buildEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sr.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sentry request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf(
+		"Sentry sentry_version=7, sentry_client=go-crawler/1.0, sentry_key=%s", sr.publicKey))
+
+	resp, err := sr.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sentry event: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// buildEnvelope monta o envelope (cabeçalho, cabeçalho do item e evento, um JSON por linha)
+func (sr *SentryReporter) buildEnvelope(event ErrorEvent) ([]byte, error) {
+	eventID := newEventID()
+	payload := sr.buildPayload(eventID, event)
+
+	eventJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sentry event: %v", err)
+	}
+	header, _ := json.Marshal(map[string]string{
+		"event_id": eventID,
+		"dsn":      sr.dsn,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+	itemHeader, _ := json.Marshal(map[string]interface{}{
+		"type":   "event",
+		"length": len(eventJSON),
+	})
+
+	var buffer bytes.Buffer
+	buffer.Write(header)
+	buffer.WriteByte('\n')
+	buffer.Write(itemHeader)
+	buffer.WriteByte('\n')
+	buffer.Write(eventJSON)
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// buildPayload converte o ErrorEvent no formato de evento do Sentry
+func (sr *SentryReporter) buildPayload(eventID string, event ErrorEvent) map[string]interface{} {
+	tags := map[string]string{}
+	for k, v := range event.Tags {
+		tags[k] = v
+	}
+	if event.Component != "" {
+		tags["component"] = event.Component
+	}
+
+	extra := map[string]interface{}{}
+	for k, v := range event.Extra {
+		extra[k] = v
+	}
+	if event.Stack != "" {
+		extra["stack"] = event.Stack
+	}
+
+	exceptionType := "error"
+	exceptionValue := event.Message
+	if event.Err != nil {
+		exceptionType = fmt.Sprintf("%T", event.Err)
+		exceptionValue = event.Err.Error()
+	}
+	if event.Level == FATAL {
+		exceptionType = "panic"
+	}
+
+	return map[string]interface{}{
+		"event_id":    eventID,
+		"timestamp":   event.Timestamp.UTC().Format(time.RFC3339),
+		"platform":    "go",
+		"level":       sentryLevel(event.Level),
+		"logger":      event.Component,
+		"message":     event.Message,
+		"environment": sr.environment,
+		"release":     sr.release,
+		"server_name": sr.serverName,
+		"tags":        tags,
+		"extra":       extra,
+		"exception": map[string]interface{}{
+			"values": []map[string]string{{"type": exceptionType, "value": exceptionValue}},
+		},
+	}
+}
+
+// sentryLevel converte o nível de log para o nível do Sentry
+func sentryLevel(level LogLevel) string {
+	switch level {
+	case DEBUG:
+		return "debug"
+	case INFO:
+		return "info"
+	case WARN:
+		return "warning"
+	case FATAL:
+		return "fatal"
+	default:
+		return "error"
+	}
+}
+
+// newEventID gera um identificador de 32 caracteres hexadecimais
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,239 @@
+package readability
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// noiseSelector remove elementos que nunca descrevem o imóvel (código, navegação e campos de
+// formulário, como selects com todos os bairros da cidade)
+const noiseSelector = "script, style, noscript, template, iframe, svg, canvas, object, embed, " +
+	"nav, aside, select, option, input, textarea, button, " +
+	"[role=navigation], [role=banner], [role=contentinfo], [aria-hidden=true]"
+
+// noiseHints identificam blocos de menu, cookies e compartilhamento pela classe ou id
+// ("menu", "menu-principal", "cookie_bar"...)
+var noiseHints = []string{
+	"menu", "navbar", "breadcrumb", "breadcrumbs", "cookie", "cookies", "footer", "rodape",
+	"sidebar", "share", "social", "newsletter", "modal", "popup", "topbar",
+}
+
+// Blocos de texto pontuados na busca do conteúdo principal
+const contentBlockSelector = "p, td, pre, li, dd, dt, h1, h2, h3"
+
+// Limites da escolha do bloco principal
+const (
+	minBlockText   = 25  // caracteres mínimos para um bloco de texto pontuar
+	minContentText = 200 // abaixo disso o bloco vencedor não representa a página (usa o body)
+)
+
+// blockElements recebem quebra de linha na extração do texto
+var blockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true, "li": true, "ul": true,
+	"ol": true, "tr": true, "table": true, "br": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "dd": true, "dt": true, "dl": true, "pre": true, "blockquote": true,
+}
+
+var (
+	tagGapPattern     = regexp.MustCompile(`>\s+<`)
+	whitespacePattern = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankLinePattern  = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// Article é o conteúdo principal de uma página, isolado de menus, rodapés, scripts e formulários
+type Article struct {
+	Title       string
+	Content     *goquery.Selection // bloco principal (cópia; o documento original não é alterado)
+	Text        string             // texto do bloco, com uma linha por elemento de bloco
+	LinkDensity float64            // fração do texto que está dentro de links
+	Fallback    bool               // nenhum bloco se destacou: o body inteiro (sem ruído) foi usado
+}
+
+// FromHTML isola o conteúdo principal de um HTML
+func FromHTML(rawHTML string) (*Article, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, err
+	}
+	return extract(doc.Selection), nil
+}
+
+// FromSelection isola o conteúdo principal de um documento já carregado (ex: e.DOM do colly),
+// trabalhando sobre uma cópia para não afetar a extração feita depois no mesmo DOM
+func FromSelection(selection *goquery.Selection) *Article {
+	if selection == nil || selection.Length() == 0 {
+		return &Article{Content: &goquery.Selection{}, Fallback: true}
+	}
+	return extract(selection.Clone())
+}
+
+// extract remove o ruído da seleção (já copiada) e escolhe o bloco principal
+func extract(root *goquery.Selection) *Article {
+	article := &Article{Title: strings.TrimSpace(root.Find("title").First().Text())}
+	if article.Title == "" {
+		article.Title = strings.TrimSpace(root.Find("h1").First().Text())
+	}
+
+	root.Find(noiseSelector).Remove()
+	// Cabeçalhos e rodapés da página; os de dentro de <article>/<main> (título e preço) ficam
+	root.Find("header, footer").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.ParentsFiltered("article, main").Length() == 0
+	}).Remove()
+	root.Find("[class], [id]").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return hasNoiseHint(s.AttrOr("class", "") + " " + s.AttrOr("id", ""))
+	}).Remove()
+	removeComments(root)
+
+	article.Content, article.Fallback = mainContent(root)
+
+	var b strings.Builder
+	for _, node := range article.Content.Nodes {
+		nodeText(node, &b)
+	}
+	article.Text = CollapseWhitespace(b.String())
+	article.LinkDensity = linkDensity(article.Content)
+	return article
+}
+
+// HTML retorna o HTML do bloco principal sem espaços entre as tags, mantendo apenas os
+// atributos informados (nenhum atributo se a lista estiver vazia)
+func (a *Article) HTML(keepAttributes ...string) string {
+	kept := make(map[string]bool, len(keepAttributes))
+	for _, attribute := range keepAttributes {
+		kept[attribute] = true
+	}
+
+	content := a.Content.Clone()
+	content.Find("*").AddSelection(content).Each(func(_ int, s *goquery.Selection) {
+		node := s.Nodes[0]
+		attributes := node.Attr[:0]
+		for _, attr := range node.Attr {
+			if kept[attr.Key] {
+				attributes = append(attributes, attr)
+			}
+		}
+		node.Attr = attributes
+	})
+
+	var parts []string
+	for i := range content.Nodes {
+		if fragment, err := goquery.OuterHtml(content.Eq(i)); err == nil {
+			parts = append(parts, fragment)
+		}
+	}
+	joined := blankLinePattern.ReplaceAllString(strings.Join(parts, ""), " ")
+	joined = tagGapPattern.ReplaceAllString(joined, "><")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(joined, " "))
+}
+
+// CollapseWhitespace junta espaços repetidos e linhas em branco
+func CollapseWhitespace(text string) string {
+	text = whitespacePattern.ReplaceAllString(text, " ")
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(text, "\n"))
+}
+
+// hasNoiseHint verifica se algum token da classe/id é (ou começa com) uma dica de ruído
+func hasNoiseHint(attributes string) bool {
+	for _, token := range strings.Fields(strings.ToLower(attributes)) {
+		for _, hint := range noiseHints {
+			if token == hint || strings.HasPrefix(token, hint+"-") || strings.HasPrefix(token, hint+"_") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeComments descarta os comentários HTML
+func removeComments(selection *goquery.Selection) {
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; {
+			next := child.NextSibling
+			if child.Type == html.CommentNode {
+				n.RemoveChild(child)
+			} else {
+				walk(child)
+			}
+			child = next
+		}
+	}
+	for _, node := range selection.Nodes {
+		walk(node)
+	}
+}
+
+// mainContent escolhe o bloco principal no estilo do Readability: cada bloco de texto pontua o
+// elemento pai (e metade para o avô) e vence o elemento com maior pontuação descontada a
+// densidade de links. Páginas com <main> usam o próprio elemento.
+func mainContent(root *goquery.Selection) (*goquery.Selection, bool) {
+	if main := root.Find("main, [role=main]").First(); main.Length() > 0 && len(strings.TrimSpace(main.Text())) >= minContentText {
+		return main, false
+	}
+
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+	root.Find(contentBlockSelector).Each(func(_ int, block *goquery.Selection) {
+		text := strings.TrimSpace(block.Text())
+		if len(text) < minBlockText {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		for ancestor, weight := block.Parent(), 1.0; ancestor.Length() > 0 && weight >= 0.5; ancestor, weight = ancestor.Parent(), weight/2 {
+			node := ancestor.Nodes[0]
+			if _, seen := scores[node]; !seen {
+				order = append(order, node)
+			}
+			scores[node] += score * weight
+		}
+	})
+
+	body := root.Find("body")
+	if body.Length() == 0 {
+		body = root
+	}
+
+	// Percorre na ordem do documento para que empates escolham sempre o mesmo bloco
+	var best *goquery.Selection
+	bestScore := 0.0
+	for _, node := range order {
+		candidate := goquery.NewDocumentFromNode(node).Selection
+		score := scores[node] * (1 - linkDensity(candidate))
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	if best == nil || len(strings.TrimSpace(best.Text())) < minContentText {
+		return body, true
+	}
+	return best, false
+}
+
+// linkDensity calcula a fração do texto que está dentro de links
+func linkDensity(selection *goquery.Selection) float64 {
+	textLength := len(strings.TrimSpace(selection.Text()))
+	if textLength == 0 {
+		return 0
+	}
+	return float64(len(strings.TrimSpace(selection.Find("a").Text()))) / float64(textLength)
+}
+
+// nodeText extrai o texto separando os elementos de bloco por quebras de linha
+func nodeText(n *html.Node, b *strings.Builder) {
+	if n.Type == html.TextNode {
+		b.WriteString(n.Data)
+		b.WriteString(" ")
+		return
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		nodeText(child, b)
+	}
+	if n.Type == html.ElementNode && blockElements[n.Data] {
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// amenityKeywords mapeia cada comodidade normalizada para as variações de texto encontradas
+// nos anúncios (já sem acentos e em minúsculas)
+var amenityKeywords = map[string][]string{
+	"piscina":         {"piscina"},
+	"churrasqueira":   {"churrasqueira", "churrasco"},
+	"garagem":         {"garagem", "vaga", "estacionamento", "box coberto"},
+	"jardim":          {"jardim", "quintal"},
+	"varanda":         {"varanda"},
+	"sacada":          {"sacada"},
+	"area_gourmet":    {"area gourmet", "espaco gourmet", "gourmet"},
+	"playground":      {"playground", "parquinho"},
+	"portaria":        {"portaria", "porteiro"},
+	"elevador":        {"elevador"},
+	"academia":        {"academia", "fitness"},
+	"salao_festas":    {"salao de festas", "salao de festa", "espaco de festas"},
+	"suite":           {"suite"},
+	"mobiliado":       {"mobiliado", "mobiliada"},
+	"ar_condicionado": {"ar condicionado", "arcondicionado"},
+}
+
+// amenitySlugs lista as comodidades em ordem fixa para que a normalização seja determinística
+var amenitySlugs = func() []string {
+	slugs := make([]string, 0, len(amenityKeywords))
+	for slug := range amenityKeywords {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}()
+
+// NormalizeAmenities converte as características livres de um anúncio para a lista
+// ordenada e sem repetições de comodidades conhecidas (usada nos filtros de busca)
+func NormalizeAmenities(caracteristicas []string) []string {
+	found := make(map[string]bool)
+	for _, caracteristica := range caracteristicas {
+		// Um mesmo texto pode citar várias comodidades ("área gourmet com churrasqueira")
+		for _, slug := range matchAmenities(caracteristica) {
+			found[slug] = true
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	amenities := make([]string, 0, len(found))
+	for slug := range found {
+		amenities = append(amenities, slug)
+	}
+	sort.Strings(amenities)
+	return amenities
+}
+
+// NormalizeAmenity converte um texto para o rótulo da comodidade correspondente.
+// Retorna string vazia se o texto não corresponder a nenhuma comodidade conhecida.
+func NormalizeAmenity(caracteristica string) string {
+	if matches := matchAmenities(caracteristica); len(matches) > 0 {
+		return matches[0]
+	}
+	return ""
+}
+
+// matchAmenities retorna, em ordem alfabética, as comodidades citadas no texto
+func matchAmenities(text string) []string {
+	replacer := strings.NewReplacer("_", " ", "-", " ")
+	normalized := utils.NormalizeText(replacer.Replace(text))
+	if normalized == "" {
+		return nil
+	}
+
+	// Rótulo exato tem prioridade (ex.: "area_gourmet" informado na query)
+	slug := strings.ReplaceAll(normalized, " ", "_")
+	if _, exists := amenityKeywords[slug]; exists {
+		return []string{slug}
+	}
+
+	var matches []string
+	for _, candidate := range amenitySlugs {
+		for _, keyword := range amenityKeywords[candidate] {
+			if strings.Contains(normalized, keyword) {
+				matches = append(matches, candidate)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// ParseAmenityFilter interpreta o parâmetro "caracteristicas=piscina,garagem".
+// Retorna as comodidades reconhecidas e os termos que não puderam ser normalizados.
+func ParseAmenityFilter(raw string) ([]string, []string) {
+	var terms []string
+	var unknown []string
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if NormalizeAmenity(term) == "" {
+			unknown = append(unknown, term)
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return NormalizeAmenities(terms), unknown
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// anonymizeJitterDegrees é o deslocamento máximo da localização em cada eixo (~330 m)
+const anonymizeJitterDegrees = 0.003
+
+var (
+	// anonymizeLinkPattern encontra links no texto (sites das imobiliárias, wa.me, redes sociais)
+	anonymizeLinkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.|wa\.me/)\S+`)
+
+	// anonymizeInternationalPhonePattern encontra telefones com DDI ("+55 35 99999-1234")
+	anonymizeInternationalPhonePattern = regexp.MustCompile(`\+\s?55[\s.-]?\(?\d{2}\)?[\s.-]?9?\d{4}[\s.-]?\d{4}\b`)
+
+	// anonymizeCreciPattern encontra o registro do corretor ou da imobiliária ("CRECI/MG 12345-J")
+	anonymizeCreciPattern = regexp.MustCompile(`(?i)\bcreci\b[\s:/-]*(?:[a-z]{2}\b[\s:/-]*)?(?:n[º°o]?\.?\s*)?\d[\d.]*(?:-?[a-z]\b)?`)
+
+	// anonymizeAgencyPattern encontra nomes próprios depois de "Imobiliária", "corretor(a)" etc.
+	anonymizeAgencyPattern = regexp.MustCompile(`(\b(?i:imobili[aá]ria|corretora?|construtora|incorporadora)\s+)([A-ZÀ-Ý][\p{L}&']*(?:\s+(?:d[aeo]s?\s+|&\s+|e\s+)?[A-ZÀ-Ý][\p{L}&']*){0,3})`)
+
+	// anonymizeAgencySuffixPattern encontra nomes no formato "Silva Imóveis"
+	anonymizeAgencySuffixPattern = regexp.MustCompile(`\b[A-ZÀ-Ý][\p{L}&']*(?:\s+[A-ZÀ-Ý][\p{L}&']*){0,2}\s+(?i:im[óo]veis|neg[óo]cios\s+imobili[áa]rios|consultoria\s+imobili[áa]ria)\b`)
+
+	// anonymizeCEPPattern encontra CEPs ("37500-123", "37500123")
+	anonymizeCEPPattern = regexp.MustCompile(`\b(\d{5})-?\d{3}\b`)
+
+	// anonymizeStreetNumberPattern encontra o número depois do nome de um logradouro
+	anonymizeStreetNumberPattern = regexp.MustCompile(`(?i)(\b(?:rua|r\.|avenida|av\.|travessa|tv\.|alameda|al\.|estrada|pra[çc]a)\s+[^,\n\d]{2,60}?(?:,\s*|\s+)(?:n[º°o]?\.?\s*)?)(\d{1,5})\b`)
+
+	// anonymizeAddressNumberPattern encontra o número do endereço sem logradouro reconhecido
+	anonymizeAddressNumberPattern = regexp.MustCompile(`(?i)(,\s*|\bn[º°o]\.?\s*)(\d{1,5})\b`)
+
+	// anonymizeDigitsPattern encontra sequências de dígitos nos caminhos das URLs
+	anonymizeDigitsPattern = regexp.MustCompile(`\d+`)
+)
+
+// Anonymizer remove contatos, nomes de imobiliárias e números exatos dos imóveis para que
+// amostras possam ser compartilhadas publicamente (relatos de problemas, fixtures de teste).
+// Domínios, IDs e deslocamentos são derivados do salt: o mesmo salt gera os mesmos apelidos
+// em exportações diferentes, e sem ele os apelidos não podem ser revertidos por dicionário
+type Anonymizer struct {
+	salt string
+}
+
+// NewAnonymizer cria o anonimizador com o salt informado
+func NewAnonymizer(salt string) *Anonymizer {
+	return &Anonymizer{salt: salt}
+}
+
+// Anonymize retorna uma cópia do imóvel sem dados identificáveis. Preço, áreas, tipo, bairro,
+// cidade e características são mantidos para que a amostra continue realista
+func (a *Anonymizer) Anonymize(property Property) Property {
+	original := property
+
+	property.ID = a.anonymizeID(original.ID)
+	property.ParentID = a.anonymizeID(original.ParentID)
+	property.CanonicalID = a.anonymizeID(original.CanonicalID)
+	property.URL = a.AnonymizeURL(original.URL)
+	property.Endereco = a.anonymizeAddress(original.Endereco)
+	property.CEP = a.anonymizeCEP(original.CEP)
+	property.Descricao = a.AnonymizeText(original.Descricao)
+	property.DescricaoEN = a.AnonymizeText(original.DescricaoEN)
+	property.Location = a.anonymizeLocation(original.Location, original.URL+"|"+original.ID)
+
+	if original.Caracteristicas != nil {
+		property.Caracteristicas = make([]string, len(original.Caracteristicas))
+		for i, feature := range original.Caracteristicas {
+			property.Caracteristicas[i] = a.AnonymizeText(feature)
+		}
+	}
+	if original.DiscoveryPath != nil {
+		property.DiscoveryPath = make([]DiscoveryStep, len(original.DiscoveryPath))
+		for i, step := range original.DiscoveryPath {
+			step.URL = a.AnonymizeURL(step.URL)
+			property.DiscoveryPath[i] = step
+		}
+	}
+
+	// Fotos mostram o imóvel real e a cópia arquivada aponta para o anúncio original; a quantidade
+	// de fotos é mantida
+	property.FotoURLs = nil
+	property.FotoHashes = nil
+	property.ArchiveURL = ""
+	// Etapas customizadas podem guardar qualquer conteúdo da página
+	property.CustomFields = nil
+
+	property.Hash = GeneratePropertyHash(property)
+	property.ShortID = ""
+	if original.ShortID != "" {
+		property.ShortID = GenerateShortID(property)
+	}
+	return property
+}
+
+// AnonymizeText substitui links, telefones, e-mails, registros CRECI, CEPs, números de
+// logradouros e nomes de imobiliárias e corretores no texto
+func (a *Anonymizer) AnonymizeText(text string) string {
+	if text == "" {
+		return text
+	}
+	return a.replaceStreetNumbers(a.scrubContacts(text), anonymizeStreetNumberPattern, -1)
+}
+
+// scrubContacts substitui links, telefones, e-mails, registros CRECI, CEPs e nomes de
+// imobiliárias e corretores
+func (a *Anonymizer) scrubContacts(text string) string {
+	text = anonymizeLinkPattern.ReplaceAllString(text, "[link]")
+	text = contactEmailPattern.ReplaceAllString(text, "[email]")
+	text = anonymizeCreciPattern.ReplaceAllString(text, "CRECI [removido]")
+	text = anonymizeInternationalPhonePattern.ReplaceAllString(text, "[telefone]")
+	text = anonymizeCEPPattern.ReplaceAllStringFunc(text, a.anonymizeCEP)
+	text = contactPhonePattern.ReplaceAllString(text, "[telefone]")
+	text = anonymizeAgencyPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := anonymizeAgencyPattern.FindStringSubmatch(match)
+		return parts[1] + a.agencyAlias(parts[2])
+	})
+	text = anonymizeAgencySuffixPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return a.agencyAlias(match) + " Imóveis"
+	})
+	return text
+}
+
+// AnonymizeURL troca o domínio por um apelido estável e os números do caminho e da query por
+// outros do mesmo tamanho, mantendo o formato da URL (útil para depurar padrões de URL)
+func (a *Anonymizer) AnonymizeURL(rawURL string) string {
+	if rawURL == "" {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "https://" + a.hostAlias(rawURL) + "/"
+	}
+	parsed.Host = a.hostAlias(strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www."))
+	parsed.User = nil
+	parsed.Fragment = ""
+	parsed.Path = a.replaceDigits(parsed.Path)
+	parsed.RawPath = ""
+	parsed.RawQuery = a.replaceDigits(parsed.RawQuery)
+	return parsed.String()
+}
+
+// anonymizeAddress troca o CEP e o primeiro número do endereço (o do imóvel; os seguintes
+// costumam ser complemento, como apartamento e bloco)
+func (a *Anonymizer) anonymizeAddress(address string) string {
+	if address == "" {
+		return address
+	}
+	address = a.scrubContacts(address)
+	if anonymized := a.replaceStreetNumbers(address, anonymizeStreetNumberPattern, 1); anonymized != address {
+		return anonymized
+	}
+	return a.replaceStreetNumbers(address, anonymizeAddressNumberPattern, 1)
+}
+
+// replaceStreetNumbers troca até max números (-1 para todos) do segundo grupo do padrão por um
+// número do mesmo quarteirão (mesma centena). Números seguidos de "-" (CEPs) são mantidos
+func (a *Anonymizer) replaceStreetNumbers(text string, pattern *regexp.Regexp, max int) string {
+	var builder strings.Builder
+	last := 0
+	replaced := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		if max >= 0 && replaced >= max {
+			break
+		}
+		start, end := match[4], match[5]
+		if end < len(text) && text[end] == '-' {
+			continue
+		}
+		number, err := strconv.Atoi(text[start:end])
+		if err != nil {
+			continue
+		}
+		builder.WriteString(text[last:start])
+		builder.WriteString(strconv.Itoa(a.perturbStreetNumber(number)))
+		last = end
+		replaced++
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// perturbStreetNumber mantém a centena e sorteia as dezenas e unidades (1 a 99), sempre
+// diferentes das originais
+func (a *Anonymizer) perturbStreetNumber(number int) int {
+	block := number - number%100
+	perturbed := block + 1 + int(a.hash("street|"+strconv.Itoa(number))%99)
+	if perturbed == number {
+		perturbed = block + 1 + (perturbed-block)%99
+	}
+	return perturbed
+}
+
+// anonymizeCEP mantém os cinco primeiros dígitos (região) e zera o sufixo
+func (a *Anonymizer) anonymizeCEP(cep string) string {
+	parts := anonymizeCEPPattern.FindStringSubmatch(cep)
+	if parts == nil {
+		return cep
+	}
+	return strings.Replace(cep, parts[0], parts[1]+"-000", 1)
+}
+
+// anonymizeLocation desloca o ponto em até ~330 m em cada eixo
+func (a *Anonymizer) anonymizeLocation(location *GeoPoint, key string) *GeoPoint {
+	if location == nil || len(location.Coordinates) < 2 {
+		return nil
+	}
+	latitude := location.Latitude() + a.jitter("lat|"+key)
+	longitude := location.Longitude() + a.jitter("lng|"+key)
+	return NewGeoPoint(latitude, longitude)
+}
+
+// jitter retorna um deslocamento determinístico entre -anonymizeJitterDegrees e +anonymizeJitterDegrees
+func (a *Anonymizer) jitter(key string) float64 {
+	unit := float64(a.hash(key)%1_000_001) / 1_000_000
+	return (unit*2 - 1) * anonymizeJitterDegrees
+}
+
+// anonymizeID gera um ID no formato de ObjectID a partir do original (vazio continua vazio)
+func (a *Anonymizer) anonymizeID(id string) string {
+	if id == "" {
+		return id
+	}
+	sum := sha256.Sum256([]byte(a.salt + "|id|" + id))
+	return hex.EncodeToString(sum[:12])
+}
+
+// hostAlias gera o domínio fictício do site de origem
+func (a *Anonymizer) hostAlias(host string) string {
+	sum := sha256.Sum256([]byte(a.salt + "|host|" + host))
+	return "site-" + hex.EncodeToString(sum[:4]) + ".example"
+}
+
+// agencyAlias gera o nome fictício da imobiliária ou do corretor
+func (a *Anonymizer) agencyAlias(name string) string {
+	return fmt.Sprintf("Exemplo %04X", a.hash("agency|"+strings.ToLower(name))%0x10000)
+}
+
+// replaceDigits troca cada sequência de dígitos por outra do mesmo tamanho
+func (a *Anonymizer) replaceDigits(text string) string {
+	return anonymizeDigitsPattern.ReplaceAllStringFunc(text, func(digits string) string {
+		value := a.hash("digits|" + digits)
+		replaced := make([]byte, len(digits))
+		for i := range replaced {
+			replaced[i] = byte('0' + value%10)
+			value /= 10
+			if value == 0 {
+				value = a.hash("digits|" + digits + "|" + strconv.Itoa(i))
+			}
+		}
+		// Evita zero à esquerda, que mudaria o formato de IDs numéricos
+		if replaced[0] == '0' && len(replaced) > 1 {
+			replaced[0] = '1'
+		}
+		return string(replaced)
+	})
+}
+
+// hash deriva um número do salt e da chave
+func (a *Anonymizer) hash(key string) uint64 {
+	sum := sha256.Sum256([]byte(a.salt + "|" + key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
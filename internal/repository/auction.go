@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// auctionKeywords identificam anúncios de leilão no texto normalizado (sem acentos)
+var auctionKeywords = []string{
+	"leilao", "leiloes", "leiloeiro", "leiloeira", "arrematacao", "arremate",
+	"lance minimo", "lance inicial", "hasta publica", "1a praca", "2a praca",
+	"primeira praca", "segunda praca", "venda judicial",
+}
+
+// auctionDomains são portais dedicados a leilões (qualquer anúncio deles é leilão)
+var auctionDomains = []string{
+	"megaleiloes.com.br",
+	"portalzuk.com.br",
+	"zukerman.com.br",
+	"leilaoimovel.com.br",
+	"sold.com.br",
+	"superbid.net",
+	"frazaoleiloes.com.br",
+	"biasileiloes.com.br",
+}
+
+var (
+	// auctionDatePattern captura a data citada após "leilão", "praça" ou "data do leilão"
+	auctionDatePattern = regexp.MustCompile(`(?i)(?:leil[aã]o|pra[cç]a|hasta)[^0-9]{0,40}(\d{1,2})[/.-](\d{1,2})[/.-](\d{2,4})`)
+
+	// auctionBidPattern captura o valor do lance mínimo ("lance mínimo: R$ 250.000,00")
+	auctionBidPattern = regexp.MustCompile(`(?i)lance\s+(?:m[ií]nimo|inicial)[^0-9]{0,20}?(\d{1,3}(?:\.\d{3})+(?:,\d{1,2})?|\d+(?:,\d{1,2})?)`)
+)
+
+// IsAuctionDomain indica se a URL pertence a um portal de leilões
+func IsAuctionDomain(rawURL string) bool {
+	domain := NormalizeDomain(rawURL)
+	if domain == "" {
+		return false
+	}
+	if strings.Contains(domain, "leilao") || strings.Contains(domain, "leiloes") {
+		return true
+	}
+	for _, auctionDomain := range auctionDomains {
+		if domain == auctionDomain || strings.HasSuffix(domain, "."+auctionDomain) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAuctionListing indica se o anúncio é de leilão (portal dedicado ou palavras-chave no texto)
+func IsAuctionListing(property Property) bool {
+	if IsAuctionDomain(property.URL) {
+		return true
+	}
+	text := " " + utils.NormalizeText(strings.Join([]string{
+		property.TipoImovel, property.ValorTexto, property.Descricao, strings.Join(property.Caracteristicas, " "),
+	}, " ")) + " "
+	for _, keyword := range auctionKeywords {
+		if strings.Contains(text, " "+keyword+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAuctionDate retorna a primeira data de leilão/praça citada no texto (nil se não houver)
+func ParseAuctionDate(text string) *time.Time {
+	match := auctionDatePattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	day, _ := strconv.Atoi(match[1])
+	month, _ := strconv.Atoi(match[2])
+	year, _ := strconv.Atoi(match[3])
+	if year < 100 {
+		year += 2000
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// time.Date normaliza datas inválidas (31/02 vira março): nesse caso a data é descartada
+	if date.Day() != day || int(date.Month()) != month {
+		return nil
+	}
+	return &date
+}
+
+// ParseMinimumBid retorna o valor do lance mínimo citado no texto (0 se não houver)
+func ParseMinimumBid(text string) float64 {
+	match := auctionBidPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+	value, _ := utils.ParseBrazilianNumber(match[1])
+	return value
+}
+
+// applyAuction sinaliza anúncios de leilão com a data e o lance mínimo quando informados
+func applyAuction(property *Property) {
+	property.Leilao = IsAuctionListing(*property)
+	if !property.Leilao {
+		property.LeilaoData = nil
+		property.LanceMinimo = 0
+		return
+	}
+	text := property.ValorTexto + " " + property.Descricao
+	if date := ParseAuctionDate(text); date != nil {
+		property.LeilaoData = date
+	}
+	if bid := ParseMinimumBid(text); bid > 0 {
+		property.LanceMinimo = bid
+	}
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Parâmetros da inferência dos limites dos bairros
+const (
+	DefaultBairroMinListings = 3
+	bairroOutlierFactor      = 3.0   // pontos além de 3x a distância mediana ao centro são descartados
+	bairroMinRadiusMeters    = 300.0 // raio mínimo dos bairros aproximados por círculo
+	bairroCircleVertices     = 16
+	metersPerDegree          = 111320.0
+)
+
+// Métodos de aproximação do polígono de um bairro
+const (
+	BairroMethodHull   = "convex_hull" // envoltória convexa dos anúncios
+	BairroMethodRadius = "radius"      // círculo ao redor do centro (anúncios no mesmo ponto ou alinhados)
+)
+
+// GeoJSONFeatureCollection é uma coleção de polígonos no formato GeoJSON (RFC 7946)
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature é um polígono GeoJSON com suas propriedades
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   GeoJSONPolygon         `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSONPolygon é um polígono de anel único; coordinates = [[[longitude, latitude], ...]], com o
+// primeiro ponto repetido no fim e sentido anti-horário
+type GeoJSONPolygon struct {
+	Type        string        `json:"type"`
+	Coordinates [][][]float64 `json:"coordinates"`
+}
+
+// BairroBoundaryRepository é implementado por repositórios capazes de inferir os limites
+// aproximados dos bairros de uma cidade a partir dos anúncios geocodificados
+type BairroBoundaryRepository interface {
+	InferBairroBoundaries(ctx context.Context, cidade string, minListings int) (*GeoJSONFeatureCollection, error)
+}
+
+// bairroSample contém os campos de um anúncio geocodificado usados na inferência
+type bairroSample struct {
+	Cidade   string    `bson:"cidade"`
+	Bairro   string    `bson:"bairro"`
+	Location *GeoPoint `bson:"location"`
+}
+
+// InferBairroBoundaries agrupa os anúncios geocodificados da cidade por bairro e retorna um
+// polígono aproximado para cada bairro com ao menos minListings anúncios
+func (r *MongoRepository) InferBairroBoundaries(ctx context.Context, cidade string, minListings int) (*GeoJSONFeatureCollection, error) {
+	filter := bson.M{
+		"cidade":   bson.M{"$regex": "^" + regexp.QuoteMeta(cidade) + "$", "$options": "i"},
+		"bairro":   bson.M{"$nin": bson.A{"", nil}},
+		"location": bson.M{"$exists": true},
+	}
+	projection := bson.M{"cidade": 1, "bairro": 1, "location": 1}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find geocoded properties: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var samples []bairroSample
+	for cursor.Next(ctx) {
+		var sample bairroSample
+		if err := cursor.Decode(&sample); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read geocoded properties: %v", err)
+	}
+	return buildBairroBoundaries(samples, minListings), nil
+}
+
+// buildBairroBoundaries calcula o polígono de cada bairro: descarta os pontos distantes do centro
+// (geocodificações erradas) e usa a envoltória convexa dos demais ou, quando os anúncios estão no
+// mesmo ponto ou alinhados, um círculo ao redor do centro
+func buildBairroBoundaries(samples []bairroSample, minListings int) *GeoJSONFeatureCollection {
+	if minListings <= 0 {
+		minListings = DefaultBairroMinListings
+	}
+
+	type group struct {
+		cidade   string
+		spelling map[string]int
+		points   [][2]float64 // [longitude, latitude]
+	}
+	groups := make(map[string]*group)
+	var keys []string
+	for _, sample := range samples {
+		key := utils.NormalizeText(sample.Bairro)
+		if key == "" || sample.Location == nil || len(sample.Location.Coordinates) < 2 {
+			continue
+		}
+		longitude, latitude := sample.Location.Longitude(), sample.Location.Latitude()
+		if latitude == 0 && longitude == 0 {
+			continue
+		}
+		g, exists := groups[key]
+		if !exists {
+			g = &group{cidade: sample.Cidade, spelling: make(map[string]int)}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.spelling[sample.Bairro]++
+		g.points = append(g.points, [2]float64{longitude, latitude})
+	}
+	sort.Strings(keys)
+
+	collection := &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{}}
+	for _, key := range keys {
+		g := groups[key]
+		if len(g.points) < minListings {
+			continue
+		}
+
+		center, points := removeBairroOutliers(g.points)
+		if len(points) < minListings {
+			continue
+		}
+
+		method := BairroMethodHull
+		ring := convexHull(points)
+		if len(ring) < 3 || ringAreaMeters(ring, center[1]) < 1 {
+			method = BairroMethodRadius
+			ring = circleRing(center, points)
+		}
+		ring = append(ring, ring[0])
+
+		coordinates := make([][]float64, len(ring))
+		for i, point := range ring {
+			coordinates[i] = []float64{roundCoordinate(point[0]), roundCoordinate(point[1])}
+		}
+		collection.Features = append(collection.Features, GeoJSONFeature{
+			Type:     "Feature",
+			Geometry: GeoJSONPolygon{Type: "Polygon", Coordinates: [][][]float64{coordinates}},
+			Properties: map[string]interface{}{
+				"bairro":   commonSpelling(g.spelling),
+				"cidade":   g.cidade,
+				"listings": len(points),
+				"outliers": len(g.points) - len(points),
+				"method":   method,
+				"center":   []float64{roundCoordinate(center[0]), roundCoordinate(center[1])},
+			},
+		})
+	}
+	return collection
+}
+
+// removeBairroOutliers retorna o centro (mediana das coordenadas) e os pontos a até 3x a
+// distância mediana ao centro
+func removeBairroOutliers(points [][2]float64) ([2]float64, [][2]float64) {
+	longitudes := make([]float64, len(points))
+	latitudes := make([]float64, len(points))
+	for i, point := range points {
+		longitudes[i], latitudes[i] = point[0], point[1]
+	}
+	center := [2]float64{exactMedian(longitudes), exactMedian(latitudes)}
+
+	distances := make([]float64, len(points))
+	for i, point := range points {
+		distances[i] = distanceMeters(center, point)
+	}
+	limit := math.Max(exactMedian(append([]float64(nil), distances...))*bairroOutlierFactor, bairroMinRadiusMeters)
+
+	kept := make([][2]float64, 0, len(points))
+	for i, point := range points {
+		if distances[i] <= limit {
+			kept = append(kept, point)
+		}
+	}
+	return center, kept
+}
+
+// convexHull calcula a envoltória convexa (monotone chain) em sentido anti-horário, sem repetir
+// o primeiro ponto
+func convexHull(points [][2]float64) [][2]float64 {
+	sorted := append([][2]float64(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i][0] != sorted[j][0] {
+			return sorted[i][0] < sorted[j][0]
+		}
+		return sorted[i][1] < sorted[j][1]
+	})
+
+	unique := sorted[:0]
+	for _, point := range sorted {
+		if len(unique) == 0 || point != unique[len(unique)-1] {
+			unique = append(unique, point)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	cross := func(o, a, b [2]float64) float64 {
+		return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+	}
+	hull := make([][2]float64, 0, 2*len(unique))
+	for _, point := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], point) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, point)
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], unique[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, unique[i])
+	}
+	return hull[:len(hull)-1]
+}
+
+// circleRing aproxima o bairro por um círculo ao redor do centro com raio até o ponto mais
+// distante (mínimo de 300m), em sentido anti-horário
+func circleRing(center [2]float64, points [][2]float64) [][2]float64 {
+	radius := bairroMinRadiusMeters
+	for _, point := range points {
+		radius = math.Max(radius, distanceMeters(center, point))
+	}
+
+	latitudeRadius := radius / metersPerDegree
+	longitudeRadius := radius / (metersPerDegree * math.Cos(center[1]*math.Pi/180))
+	ring := make([][2]float64, bairroCircleVertices)
+	for i := range ring {
+		angle := 2 * math.Pi * float64(i) / bairroCircleVertices
+		ring[i] = [2]float64{center[0] + longitudeRadius*math.Cos(angle), center[1] + latitudeRadius*math.Sin(angle)}
+	}
+	return ring
+}
+
+// ringAreaMeters calcula a área aproximada do anel em m² (projeção equiretangular local)
+func ringAreaMeters(ring [][2]float64, latitude float64) float64 {
+	scale := math.Cos(latitude * math.Pi / 180)
+	area := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += a[0]*scale*b[1] - b[0]*scale*a[1]
+	}
+	return math.Abs(area) / 2 * metersPerDegree * metersPerDegree
+}
+
+// distanceMeters calcula a distância aproximada entre dois pontos [longitude, latitude]
+func distanceMeters(a, b [2]float64) float64 {
+	scale := math.Cos((a[1] + b[1]) / 2 * math.Pi / 180)
+	return math.Hypot((a[0]-b[0])*scale, a[1]-b[1]) * metersPerDegree
+}
+
+// exactMedian retorna a mediana dos valores sem arredondamento (o slice é ordenado)
+func exactMedian(values []float64) float64 {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+// commonSpelling retorna a grafia mais frequente do bairro (em empate, a primeira em ordem alfabética)
+func commonSpelling(spelling map[string]int) string {
+	best, count := "", 0
+	for name, n := range spelling {
+		if n > count || (n == count && name < best) {
+			best, count = name, n
+		}
+	}
+	return best
+}
+
+// roundCoordinate arredonda a coordenada para 6 casas (~10cm)
+func roundCoordinate(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
@@ -0,0 +1,391 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Coleção e parâmetros da fusão dos anúncios duplicados entre portais
+const (
+	CanonicalPropertyCollection = "canonical_properties"
+
+	DefaultSourceReliability     = 0.5 // peso dos domínios ainda sem métricas de qualidade
+	defaultMergeFieldConfidence  = 0.5 // confiança dos campos sem field_confidence
+	minSourceReliability         = 0.05
+	sourceReliabilityMetricsDays = 30 // janela das métricas de qualidade usadas nos pesos
+)
+
+// mergedFields são os campos decididos entre as fontes, na ordem da fusão
+var mergedFields = []string{
+	"endereco", "cidade", "bairro", "cep", "descricao", "valor", "quartos", "banheiros",
+	"area_total", "area_util", "tipo_imovel", "caracteristicas",
+}
+
+// CanonicalSource é um anúncio de origem do registro canônico, guardado como foi extraído
+type CanonicalSource struct {
+	URL      string   `bson:"url" json:"url"`
+	Domain   string   `bson:"domain" json:"domain"`
+	Weight   float64  `bson:"weight" json:"weight"` // confiabilidade do domínio (0 a 1)
+	Original Property `bson:"original" json:"original"`
+}
+
+// CanonicalProperty é o registro canônico de um imóvel anunciado em vários portais: cada campo
+// vem da fonte com mais apoio ponderado (confiabilidade do domínio × confiança do campo).
+// FieldSources indica a URL de onde veio cada campo e o field_confidence do registro fundido é a
+// parcela do apoio que ficou com o valor escolhido. O ID é estável entre fusões (ver CanonicalAnchor).
+type CanonicalProperty struct {
+	ID           string            `bson:"_id" json:"id"`
+	Cidade       string            `bson:"cidade" json:"cidade"`
+	Property     Property          `bson:"property" json:"property"`
+	FieldSources map[string]string `bson:"field_sources" json:"field_sources"`
+	Sources      []CanonicalSource `bson:"sources" json:"sources"`
+	MergedAt     time.Time         `bson:"merged_at" json:"merged_at"`
+}
+
+// CanonicalMergeResult resume uma fusão das duplicatas de uma cidade
+type CanonicalMergeResult struct {
+	Cidade   string             `json:"cidade"`
+	Listings int                `json:"listings"` // anúncios comparados
+	Merged   int                `json:"merged"`   // registros canônicos gravados
+	Sources  int                `json:"sources"`  // anúncios ligados a um registro canônico
+	Removed  int                `json:"removed"`  // registros canônicos que deixaram de existir
+	Weights  map[string]float64 `json:"weights"`  // confiabilidade dos domínios envolvidos
+}
+
+// CanonicalPropertyRepository é implementado por repositórios capazes de fundir os anúncios
+// duplicados entre portais e consultar os registros canônicos
+type CanonicalPropertyRepository interface {
+	MergeDuplicateProperties(ctx context.Context, cidade string, opts DuplicateReportOptions) (*CanonicalMergeResult, error)
+	// FindPropertyCities retorna as cidades com imóveis, para a fusão periódica de todas elas
+	FindPropertyCities(ctx context.Context) ([]string, error)
+	FindCanonicalProperties(ctx context.Context, cidade string, limit int) ([]CanonicalProperty, error)
+	FindCanonicalProperty(ctx context.Context, id string) (*CanonicalProperty, error)
+}
+
+// ErrCanonicalPropertyNotFound indica que o registro canônico não existe
+var ErrCanonicalPropertyNotFound = errors.New("canonical property not found")
+
+// SourceReliability calcula a confiabilidade (0 a 1) de um domínio a partir das suas métricas de
+// qualidade: a média dos percentuais de preço, endereço, quartos e fotos, combinada em partes
+// iguais com a nota média de extração quando o domínio tem anúncios avaliados
+func SourceReliability(metric DomainQuality) float64 {
+	completeness := (metric.WithPricePct + metric.WithAddressPct + metric.WithRoomsPct + metric.WithPhotosPct) / 400
+	reliability := completeness
+	if metric.ScoredProperties > 0 {
+		reliability = (completeness + metric.AvgExtractionScore) / 2
+	}
+	reliability = math.Max(minSourceReliability, math.Min(1, reliability))
+	return math.Round(reliability*1000) / 1000
+}
+
+// SourceReliabilityWeights retorna a confiabilidade de cada domínio com a métrica mais recente da série
+func SourceReliabilityWeights(series []DomainQuality) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, metric := range LatestQualityMetrics(series) {
+		weights[metric.Domain] = SourceReliability(metric)
+	}
+	return weights
+}
+
+// CanonicalAnchor escolhe o ID do registro canônico de um grupo de anúncios: o canonical_id já
+// carregado pelo anúncio mais antigo (menor ID) que tenha um, senão o ID do anúncio mais antigo.
+// Assim o registro mantém o ID quando o grupo ganha ou perde anúncios ou as URLs mudam.
+func CanonicalAnchor(listings []Property) string {
+	carriedFrom, carried := "", ""
+	for _, listing := range listings {
+		if listing.CanonicalID != "" && (carried == "" || listing.ID < carriedFrom) {
+			carriedFrom, carried = listing.ID, listing.CanonicalID
+		}
+	}
+	if carried != "" {
+		return carried
+	}
+	return oldestListingID(listings)
+}
+
+// oldestListingID retorna o menor ID (o anúncio mais antigo) do grupo
+func oldestListingID(listings []Property) string {
+	oldest := ""
+	for _, listing := range listings {
+		if oldest == "" || listing.ID < oldest {
+			oldest = listing.ID
+		}
+	}
+	return oldest
+}
+
+// MergeDuplicateListings funde os anúncios do mesmo imóvel em um registro canônico. Para cada
+// campo, as fontes com valor votam com peso = confiabilidade do domínio × confiança do campo;
+// valores iguais (sem acentos e caixa) somam apoio e vence o de maior apoio. Os demais campos vêm
+// da fonte mais confiável.
+func MergeDuplicateListings(listings []Property, weights map[string]float64) CanonicalProperty {
+	sources := make([]CanonicalSource, 0, len(listings))
+	for _, listing := range listings {
+		domain := NormalizeDomain(listing.URL)
+		weight, known := weights[domain]
+		if !known {
+			weight = DefaultSourceReliability
+		}
+		sources = append(sources, CanonicalSource{URL: listing.URL, Domain: domain, Weight: weight, Original: listing})
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Weight != sources[j].Weight {
+			return sources[i].Weight > sources[j].Weight
+		}
+		return sources[i].URL < sources[j].URL
+	})
+
+	canonical := CanonicalProperty{
+		ID:           CanonicalAnchor(listings),
+		FieldSources: make(map[string]string),
+		Sources:      sources,
+	}
+	if len(sources) == 0 {
+		return canonical
+	}
+
+	merged := sources[0].Original
+	merged.ID = ""
+	merged.ShortID = ""
+	merged.CanonicalID = ""
+	merged.Provenance = nil
+	merged.FieldConfidence = make(map[string]float64)
+
+	values := make([]map[string]string, len(sources))
+	for i, source := range sources {
+		values[i] = provenanceFieldValues(source.Original)
+	}
+
+	for _, field := range mergedFields {
+		support := make(map[string]float64)
+		best := make(map[string]int) // valor → fonte com o maior voto individual
+		scores := make([]float64, len(sources))
+		total := 0.0
+		for i, source := range sources {
+			value := values[i][field]
+			if value == "" {
+				continue
+			}
+			confidence, scored := source.Original.FieldConfidence[field]
+			if !scored {
+				confidence = defaultMergeFieldConfidence
+			}
+			scores[i] = source.Weight * confidence
+			key := utils.NormalizeText(value)
+			support[key] += scores[i]
+			total += scores[i]
+			if current, exists := best[key]; !exists || scores[i] > scores[current] {
+				best[key] = i
+			}
+		}
+		if len(support) == 0 {
+			continue
+		}
+
+		// Empate no apoio: vence o valor da fonte mais confiável
+		winner, found := "", false
+		for key, score := range support {
+			if !found || score > support[winner] || (score == support[winner] && best[key] < best[winner]) {
+				winner, found = key, true
+			}
+		}
+		source := sources[best[winner]]
+		correctedFieldCopiers[field](&merged, source.Original)
+		canonical.FieldSources[field] = source.URL
+		if total > 0 {
+			merged.FieldConfidence[field] = math.Round(support[winner]/total*1000) / 1000
+		}
+	}
+
+	ApplyDerivedFields(&merged)
+	merged.Hash = GeneratePropertyHash(merged)
+	canonical.Cidade = merged.Cidade
+	canonical.Property = merged
+	return canonical
+}
+
+// canonicalProperties retorna a coleção dos registros canônicos
+func (r *MongoRepository) canonicalProperties() *mongo.Collection {
+	return r.collection.Database().Collection(CanonicalPropertyCollection)
+}
+
+// MergeDuplicateProperties agrupa os anúncios duplicados da cidade entre portais (os mesmos
+// critérios de /stats/duplicates), funde cada grupo em um registro canônico com os pesos das
+// métricas de qualidade dos últimos 30 dias e marca os anúncios de origem com canonical_id.
+// Registros canônicos da cidade que não se repetiram nesta fusão são removidos.
+func (r *MongoRepository) MergeDuplicateProperties(ctx context.Context, cidade string, opts DuplicateReportOptions) (*CanonicalMergeResult, error) {
+	cityFilter := bson.M{"$regex": "^" + regexp.QuoteMeta(cidade) + "$", "$options": "i"}
+	filter := bson.M{"cidade": cityFilter, "valor": bson.M{"$gt": 0}}
+	if !opts.IncludeAuctions {
+		filter["leilao"] = bson.M{"$ne": true}
+	}
+	if !opts.IncludeVacationRentals {
+		filter["finalidade"] = bson.M{"$ne": FinalidadeTemporada}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find properties for canonical merge: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var versions []Property
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode properties for canonical merge: %v", err)
+	}
+
+	// Cada versão do conteúdo é um documento: vale a mais recente de cada URL, com o canonical_id
+	// de qualquer versão (as gravadas depois da última fusão ainda não o têm)
+	latest := make(map[string]int)
+	anchors := make(map[string]string)
+	var properties []Property
+	for _, version := range versions {
+		if version.CanonicalID != "" {
+			anchors[version.URL] = version.CanonicalID
+		}
+		if index, exists := latest[version.URL]; exists {
+			properties[index] = version
+			continue
+		}
+		latest[version.URL] = len(properties)
+		properties = append(properties, version)
+	}
+	for i := range properties {
+		properties[i].CanonicalID = anchors[properties[i].URL]
+	}
+
+	series, err := r.FindQualityMetrics(ctx, "", time.Now().AddDate(0, 0, -sourceReliabilityMetricsDays))
+	if err != nil {
+		return nil, err
+	}
+	weights := SourceReliabilityWeights(series)
+
+	result := &CanonicalMergeResult{Cidade: cidade, Listings: len(properties), Weights: make(map[string]float64)}
+	now := time.Now()
+	ids := []string{}
+	used := make(map[string]bool)
+	for _, listings := range anchorOrder(groupDuplicates(properties, opts)) {
+		canonical := MergeDuplicateListings(listings, weights)
+		canonical.MergedAt = now
+		// Grupo dividido: o ID fica com a parte do anúncio mais antigo e a outra passa a usar o ID
+		// do seu próprio anúncio mais antigo
+		if used[canonical.ID] {
+			canonical.ID = oldestListingID(listings)
+		}
+		used[canonical.ID] = true
+
+		if _, err := r.canonicalProperties().ReplaceOne(ctx, bson.M{"_id": canonical.ID}, canonical, options.Replace().SetUpsert(true)); err != nil {
+			return nil, fmt.Errorf("failed to save canonical property: %v", err)
+		}
+
+		urls := make([]string, 0, len(canonical.Sources))
+		for _, source := range canonical.Sources {
+			urls = append(urls, source.URL)
+			result.Weights[source.Domain] = source.Weight
+		}
+		if _, err := r.collection.UpdateMany(ctx, bson.M{"url": bson.M{"$in": urls}}, bson.M{"$set": bson.M{"canonical_id": canonical.ID}}); err != nil {
+			return nil, fmt.Errorf("failed to link properties to canonical record: %v", err)
+		}
+		ids = append(ids, canonical.ID)
+		result.Merged++
+		result.Sources += len(urls)
+	}
+
+	removed, err := r.canonicalProperties().DeleteMany(ctx, bson.M{"cidade": cityFilter, "_id": bson.M{"$nin": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove stale canonical properties: %v", err)
+	}
+	result.Removed = int(removed.DeletedCount)
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"cidade": cityFilter, "canonical_id": bson.M{"$exists": true, "$nin": ids}},
+		bson.M{"$unset": bson.M{"canonical_id": ""}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlink properties from stale canonical records: %v", err)
+	}
+	return result, nil
+}
+
+// anchorOrder ordena os grupos de duplicatas pelo anúncio mais antigo, para que, quando dois
+// grupos carregam o mesmo canonical_id, ele fique com o grupo que contém o anúncio mais antigo
+func anchorOrder(groups [][]duplicateCandidate) [][]Property {
+	ordered := make([][]Property, len(groups))
+	for i, group := range groups {
+		ordered[i] = make([]Property, len(group))
+		for j, candidate := range group {
+			ordered[i][j] = candidate.property
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return oldestListingID(ordered[i]) < oldestListingID(ordered[j]) })
+	return ordered
+}
+
+// FindPropertyCities retorna as cidades com imóveis, sem repetir variações de caixa (a fusão
+// compara a cidade sem diferenciar caixa)
+func (r *MongoRepository) FindPropertyCities(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "cidade", bson.M{"cidade": bson.M{"$nin": bson.A{"", nil}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property cities: %v", err)
+	}
+	seen := make(map[string]bool)
+	cities := []string{}
+	for _, value := range values {
+		city, ok := value.(string)
+		key := strings.ToLower(strings.TrimSpace(city))
+		if !ok || key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cities = append(cities, city)
+	}
+	sort.Strings(cities)
+	return cities, nil
+}
+
+// FindCanonicalProperties lista os registros canônicos da cidade (todas se vazia), da fusão mais
+// recente para a mais antiga
+func (r *MongoRepository) FindCanonicalProperties(ctx context.Context, cidade string, limit int) ([]CanonicalProperty, error) {
+	filter := bson.M{}
+	if cidade != "" {
+		filter["cidade"] = bson.M{"$regex": "^" + regexp.QuoteMeta(cidade) + "$", "$options": "i"}
+	}
+	findOpts := options.Find().SetSort(bson.D{{Key: "merged_at", Value: -1}, {Key: "_id", Value: 1}})
+	if limit > 0 {
+		findOpts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.canonicalProperties().Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find canonical properties: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	canonicals := []CanonicalProperty{}
+	if err := cursor.All(ctx, &canonicals); err != nil {
+		return nil, fmt.Errorf("failed to decode canonical properties: %v", err)
+	}
+	return canonicals, nil
+}
+
+// FindCanonicalProperty retorna o registro canônico com os anúncios de origem
+func (r *MongoRepository) FindCanonicalProperty(ctx context.Context, id string) (*CanonicalProperty, error) {
+	var canonical CanonicalProperty
+	err := r.canonicalProperties().FindOne(ctx, bson.M{"_id": id}).Decode(&canonical)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCanonicalPropertyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find canonical property: %v", err)
+	}
+	return &canonical, nil
+}
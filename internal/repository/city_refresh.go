@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Motivos de uma cidade desatualizada
+const (
+	StaleNeverRefreshed = "never_refreshed" // nenhum crawling concluído desde o cadastro
+	StaleNotScheduled   = "not_scheduled"   // nenhum crawling tentou atualizar a cidade dentro do SLA
+	StaleRefreshFailing = "refresh_failing" // os crawlings da cidade estão falhando
+)
+
+// CityFreshness mostra se os dados da cidade estão dentro do SLA de atualização
+type CityFreshness struct {
+	SLAHours    int        `json:"sla_hours"`
+	LastRefresh *time.Time `json:"last_refresh,omitempty"`
+	AgeHours    float64    `json:"age_hours"` // desde a última atualização (ou do cadastro, sem atualização)
+	Stale       bool       `json:"stale"`
+	Reason      string     `json:"reason,omitempty"` // never_refreshed, not_scheduled ou refresh_failing
+}
+
+// RefreshSLA retorna o SLA de atualização da cidade, ou defaultSLA quando a cidade não tem o seu
+func (cs *CitySites) RefreshSLA(defaultSLA time.Duration) time.Duration {
+	if cs.RefreshSLAHours > 0 {
+		return time.Duration(cs.RefreshSLAHours) * time.Hour
+	}
+	return defaultSLA
+}
+
+// EvaluateFreshness calcula se a cidade está desatualizada em now. Sem atualização, o prazo conta
+// desde a última descoberta de sites; o motivo separa cidades que nenhum crawling tentou atualizar
+// das que têm crawlings falhando. SLA não positivo nunca marca a cidade como desatualizada.
+func (cs *CitySites) EvaluateFreshness(defaultSLA time.Duration, now time.Time) CityFreshness {
+	sla := cs.RefreshSLA(defaultSLA)
+	freshness := CityFreshness{SLAHours: int(sla.Hours())}
+
+	reference := cs.LastRefresh
+	if !cs.LastRefresh.IsZero() {
+		lastRefresh := cs.LastRefresh
+		freshness.LastRefresh = &lastRefresh
+	} else {
+		reference = cs.LastDiscovery
+	}
+	if !reference.IsZero() {
+		freshness.AgeHours = math.Round(now.Sub(reference).Hours()*10) / 10
+	}
+	if sla <= 0 || (!reference.IsZero() && now.Sub(reference) <= sla) {
+		return freshness
+	}
+
+	freshness.Stale = true
+	switch {
+	case cs.RefreshFailures > 0 && cs.LastRefreshAttempt.After(cs.LastRefresh):
+		freshness.Reason = StaleRefreshFailing
+	case cs.LastRefresh.IsZero() && cs.LastRefreshAttempt.IsZero():
+		freshness.Reason = StaleNeverRefreshed
+	default:
+		freshness.Reason = StaleNotScheduled
+	}
+	return freshness
+}
+
+// CityRefreshRepository é implementado por repositórios que registram a atualização das cidades
+type CityRefreshRepository interface {
+	// RecordCityRefresh registra um crawling que cobriu a cidade: sem erro, a cidade foi
+	// atualizada em at; com erro, a falha é somada às falhas seguidas
+	RecordCityRefresh(ctx context.Context, city, state string, at time.Time, refreshErr error) error
+	// SetCityRefreshSLA define o SLA de atualização da cidade em horas (0 volta ao padrão)
+	SetCityRefreshSLA(ctx context.Context, city, state string, hours int) error
+}
+
+// RecordCityRefresh registra o resultado do crawling da cidade
+func (r *MongoCitySitesRepository) RecordCityRefresh(ctx context.Context, city, state string, at time.Time, refreshErr error) error {
+	update := bson.M{
+		"$set":   bson.M{"last_refresh": at, "last_refresh_attempt": at},
+		"$unset": bson.M{"refresh_failures": "", "last_refresh_error": ""},
+	}
+	if refreshErr != nil {
+		update = bson.M{
+			"$set": bson.M{"last_refresh_attempt": at, "last_refresh_error": refreshErr.Error()},
+			"$inc": bson.M{"refresh_failures": 1},
+		}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"city": city, "state": strings.ToUpper(state)}, update)
+	if err != nil {
+		return fmt.Errorf("failed to record city refresh: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("city not found: %s-%s", city, state)
+	}
+	return nil
+}
+
+// SetCityRefreshSLA define o SLA de atualização da cidade
+func (r *MongoCitySitesRepository) SetCityRefreshSLA(ctx context.Context, city, state string, hours int) error {
+	update := bson.M{"$set": bson.M{"refresh_sla_hours": hours}}
+	if hours <= 0 {
+		update = bson.M{"$unset": bson.M{"refresh_sla_hours": ""}}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"city": city, "state": strings.ToUpper(state)}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set city refresh sla: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("city not found: %s-%s", city, state)
+	}
+	return nil
+}
@@ -20,6 +20,14 @@ type CitySites struct {
 	TotalSites    int        `bson:"total_sites" json:"total_sites"`
 	ActiveSites   int        `bson:"active_sites" json:"active_sites"`
 	Hash          string     `bson:"hash" json:"hash"`
+
+	// Atualização dos dados pelos crawlings e o SLA da cidade (0 usa CITY_REFRESH_SLA)
+	RefreshSLAHours    int            `bson:"refresh_sla_hours,omitempty" json:"refresh_sla_hours,omitempty"`
+	LastRefresh        time.Time      `bson:"last_refresh,omitempty" json:"last_refresh,omitempty"`
+	LastRefreshAttempt time.Time      `bson:"last_refresh_attempt,omitempty" json:"last_refresh_attempt,omitempty"`
+	RefreshFailures    int            `bson:"refresh_failures,omitempty" json:"refresh_failures"` // falhas seguidas
+	LastRefreshError   string         `bson:"last_refresh_error,omitempty" json:"last_refresh_error,omitempty"`
+	Freshness          *CityFreshness `bson:"-" json:"freshness,omitempty"` // calculado nas consultas da API
 }
 
 // SiteInfo representa informações sobre um site de imobiliária
@@ -34,9 +42,11 @@ type SiteInfo struct {
 	PropertiesFound int       `bson:"properties_found" json:"properties_found"`
 	ErrorCount      int       `bson:"error_count" json:"error_count"`
 	SuccessRate     float64   `bson:"success_rate" json:"success_rate"`
-	DiscoveryMethod string    `bson:"discovery_method,omitempty" json:"discovery_method,omitempty"` // google, bing, directory, manual
+	DiscoveryMethod string    `bson:"discovery_method,omitempty" json:"discovery_method,omitempty"` // google, bing, directory, manual, migration
 	ResponseTime    float64   `bson:"response_time,omitempty" json:"response_time,omitempty"`       // em ms
 	LastError       string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	Source          string    `bson:"source,omitempty" json:"source,omitempty"`                 // arquivo de origem (migração)
+	ReferenceURLs   []string  `bson:"reference_urls,omitempty" json:"reference_urls,omitempty"` // anúncios de exemplo do site
 }
 
 // CityInfo representa informações básicas de uma cidade para descoberta
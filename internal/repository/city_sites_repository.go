@@ -43,14 +43,9 @@ type MongoCitySitesRepository struct {
 
 // NewMongoCitySitesRepository cria um novo repositório de sites por cidade
 func NewMongoCitySitesRepository(uri, dbName string) (*MongoCitySitesRepository, error) {
-	clientOptions := options.Client().ApplyURI(uri)
-	client, err := mongo.Connect(context.Background(), clientOptions)
+	client, err := connectMongo(uri)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
-	}
-
-	if err := client.Ping(context.Background(), nil); err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
+		return nil, err
 	}
 
 	collection := client.Database(dbName).Collection("city_sites")
@@ -0,0 +1,60 @@
+package repository
+
+import "time"
+
+// Decisões das duas configurações comparadas no experimento do classificador
+const (
+	ExperimentBothAccept    = "both_accept"
+	ExperimentBothReject    = "both_reject"
+	ExperimentControlOnly   = "control_only"   // só a configuração atual aceita a página como anúncio
+	ExperimentCandidateOnly = "candidate_only" // só a configuração candidata aceita
+)
+
+// Resultados das páginas amostradas no experimento: o que aconteceu com a decisão da configuração
+// atual, que é a aplicada no crawling, e, nas páginas aceitas só pela candidata, o resultado da
+// extração de teste (sem gravar)
+const (
+	ExperimentOutcomeSaved        = "saved"         // anúncio extraído e gravado
+	ExperimentOutcomeValid        = "valid"         // extração de teste válida: a candidata gravaria o anúncio
+	ExperimentOutcomeInvalid      = "invalid"       // extraído, mas recusado pela validação
+	ExperimentOutcomeNoData       = "no_data"       // nenhum dado de anúncio extraído
+	ExperimentOutcomeStorageError = "storage_error" // falha ao gravar
+	ExperimentOutcomeNotExtracted = "not_extracted" // rejeitada pelas duas configurações: links explorados
+	ExperimentOutcomePending      = "pending"       // sem resultado registrado até o fim do job
+)
+
+// ClassifierThresholds são os limiares do classificador preciso para aceitar uma página como
+// anúncio individual
+type ClassifierThresholds struct {
+	MinConfidence   float64 `json:"min_confidence"`   // nota mínima (0 a 1)
+	MinRequirements int     `json:"min_requirements"` // características de anúncio encontradas (quartos, m², código...)
+}
+
+// ClassifierDivergence é uma página em que as duas configurações decidiram de forma diferente
+type ClassifierDivergence struct {
+	URL          string    `json:"url"`
+	Domain       string    `json:"domain"`
+	Decision     string    `json:"decision"` // control_only ou candidate_only
+	Confidence   float64   `json:"confidence"`
+	Requirements int       `json:"requirements"`
+	Outcome      string    `json:"outcome"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// ClassifierExperimentReport resume um experimento A/B do classificador em um job: quantas páginas
+// amostradas tiveram decisões diferentes e o resultado de cada grupo de decisões
+type ClassifierExperimentReport struct {
+	Name           string                    `json:"name,omitempty"`
+	Control        ClassifierThresholds      `json:"control"`
+	Candidate      ClassifierThresholds      `json:"candidate"`
+	SampleRate     float64                   `json:"sample_rate"`
+	Classified     int                       `json:"classified"` // páginas classificadas no job
+	Sampled        int                       `json:"sampled"`    // classificadas também pela candidata
+	Agreements     int                       `json:"agreements"`
+	Divergences    int                       `json:"divergences"`
+	DivergenceRate float64                   `json:"divergence_rate"`        // divergências / amostradas
+	Outcomes       map[string]map[string]int `json:"outcomes"`               // decisão -> resultado -> páginas
+	Samples        []ClassifierDivergence    `json:"samples,omitempty"`      // primeiras divergências do job
+	JobID          string                    `json:"job_id,omitempty"`       // preenchido nas listagens
+	CompletedAt    *time.Time                `json:"completed_at,omitempty"` // preenchido nas listagens
+}
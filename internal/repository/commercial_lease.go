@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// Finalidades de uso do imóvel (filtro e faceta "finalidade")
+const (
+	FinalidadeResidencial = "residencial"
+	FinalidadeComercial   = "comercial"
+	FinalidadeRural       = "rural"
+)
+
+// commercialKeywords indicam uso comercial mesmo quando o tipo informado é genérico
+var commercialKeywords = []string{
+	"ponto comercial", "sala comercial", "salao comercial", "imovel comercial", "uso comercial",
+	"loja", "galpao", "barracao", "escritorio", "consultorio", "predio comercial",
+}
+
+// rentMarkers indicam que o valor anunciado é mensal (aluguel)
+var rentMarkers = []string{"aluguel", "alugo", "alugase", "locacao", "loco", "mes", "mensal", "mensais"}
+
+// zoningKeywords mapeiam menções ao zoneamento (texto normalizado) para os rótulos
+var zoningKeywords = []struct {
+	keyword string
+	zone    string
+}{
+	{"corredor comercial", "corredor_comercial"},
+	{"zona comercial", "comercial"},
+	{"zona mista", "misto"},
+	{"uso misto", "misto"},
+	{"zona industrial", "industrial"},
+	{"distrito industrial", "industrial"},
+	{"zona residencial", "residencial"},
+	{"zona especial de interesse social", "zeis"},
+}
+
+// zoningCodes mapeiam as siglas usadas nos planos diretores (ZC1, ZM-2, ZEIS...)
+var zoningCodes = map[string]string{
+	"ZC":   "comercial",
+	"ZM":   "misto",
+	"ZUM":  "misto",
+	"ZR":   "residencial",
+	"ZI":   "industrial",
+	"ZEIS": "zeis",
+}
+
+// Valores monetários no formato brasileiro ("R$ 3.500,00")
+const moneyPattern = `r\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{1,2})?|\d+(?:,\d{1,2})?)`
+
+var (
+	// rentValuePatterns capturam o aluguel mensal ("aluguel: R$ 3.500", "R$ 3.500/mês")
+	rentValuePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(?:aluguel|loca[cç][aã]o|alugo|mensal)[^0-9]{0,25}?` + moneyPattern),
+		regexp.MustCompile(`(?i)` + moneyPattern + `\s*(?:/\s*m[eê]s|por\s+m[eê]s|mensais|ao\s+m[eê]s)`),
+	}
+
+	// saleValuePattern captura o valor de venda quando o anúncio cita venda e aluguel
+	saleValuePattern = regexp.MustCompile(`(?i)(?:venda|vendo|[aà] vista)[^0-9]{0,25}?` + moneyPattern)
+
+	// frontagePatterns capturam a testada ("testada de 12m", "10 metros de frente")
+	frontagePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(?:testada|frente)\s*(?:de|:)?\s*(\d+(?:[.,]\d+)?)\s*m\b`),
+		regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(?:m|metros)\s+de\s+(?:frente|testada)`),
+	}
+
+	// zoningCodePattern encontra siglas de zoneamento em maiúsculas (ZC, ZC1, ZM-2, ZEIS)
+	zoningCodePattern = regexp.MustCompile(`\b(ZEIS|ZUM|ZC|ZM|ZR|ZI)(?:[- ]?\d{1,2})?\b`)
+)
+
+// DetectFinalidade classifica o uso do imóvel pelo tipo da taxonomia e pelo texto
+func DetectFinalidade(property Property) string {
+	tipo := NormalizePropertyType(property.TipoImovel, "")
+	text := " " + utils.NormalizeText(property.TipoImovel+" "+property.Descricao+" "+strings.Join(property.Caracteristicas, " ")) + " "
+
+	switch {
+	case tipo == TipoComercial:
+		return FinalidadeComercial
+	case tipo == TipoRural:
+		return FinalidadeRural
+	}
+	for _, keyword := range commercialKeywords {
+		if strings.Contains(text, " "+keyword+" ") {
+			return FinalidadeComercial
+		}
+	}
+	if tipo == TipoCasa || tipo == TipoApartamento {
+		return FinalidadeResidencial
+	}
+	return ""
+}
+
+// SplitSaleAndRent separa o valor de venda e o aluguel mensal citados no anúncio. Sem menção
+// explícita, o Valor é tratado como aluguel se o texto do preço indicar valor mensal.
+func SplitSaleAndRent(property Property) (venda, aluguel float64) {
+	text := property.ValorTexto + " " + property.Descricao
+	for _, pattern := range rentValuePatterns {
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			aluguel, _ = utils.ParseBrazilianNumber(match[1])
+			break
+		}
+	}
+	if match := saleValuePattern.FindStringSubmatch(text); match != nil {
+		venda, _ = utils.ParseBrazilianNumber(match[1])
+	}
+
+	if venda == 0 && aluguel == 0 && property.Valor > 0 {
+		priceText := " " + utils.NormalizeText(strings.NewReplacer("/", " ", "-", " ").Replace(property.ValorTexto)) + " "
+		for _, marker := range rentMarkers {
+			if strings.Contains(priceText, " "+marker+" ") {
+				return 0, property.Valor
+			}
+		}
+		return property.Valor, 0
+	}
+	return venda, aluguel
+}
+
+// ParseFrontage retorna a testada (metros de frente) citada no texto (0 se não houver)
+func ParseFrontage(text string) float64 {
+	for _, pattern := range frontagePatterns {
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			if value, _ := utils.ParseBrazilianNumber(match[1]); value > 0 && value < 1000 {
+				return value
+			}
+		}
+	}
+	return 0
+}
+
+// DetectZoning retorna, em ordem alfabética, as indicações de zoneamento citadas no texto
+func DetectZoning(text string) []string {
+	found := make(map[string]bool)
+	normalized := " " + utils.NormalizeText(text) + " "
+	for _, entry := range zoningKeywords {
+		if strings.Contains(normalized, " "+entry.keyword+" ") {
+			found[entry.zone] = true
+		}
+	}
+	for _, match := range zoningCodePattern.FindAllStringSubmatch(text, -1) {
+		found[zoningCodes[match[1]]] = true
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+	zones := make([]string, 0, len(found))
+	for zone := range found {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// applyCommercialLease preenche finalidade, venda/aluguel, testada e zoneamento
+func applyCommercialLease(property *Property) {
+	property.Finalidade = DetectFinalidade(*property)
+	property.ValorVenda, property.ValorAluguel = SplitSaleAndRent(*property)
+
+	text := property.Descricao + " " + strings.Join(property.Caracteristicas, " ")
+	property.Testada = ParseFrontage(text)
+	property.Zoneamento = DetectZoning(text)
+}
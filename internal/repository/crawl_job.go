@@ -0,0 +1,56 @@
+package repository
+
+import "time"
+
+// Status possíveis de um job de crawling
+const (
+	CrawlJobQueued     = "queued"
+	CrawlJobRunning    = "running"
+	CrawlJobCancelling = "cancelling"
+	CrawlJobCancelled  = "cancelled"
+	CrawlJobCompleted  = "completed"
+	CrawlJobFailed     = "failed"
+)
+
+// CrawlJob representa uma execução do crawler disparada pela API
+type CrawlJob struct {
+	ID            string           `json:"id"`
+	Cities        []string         `json:"cities,omitempty"`
+	Mode          string           `json:"mode"`
+	Tags          []string         `json:"tags,omitempty"` // registradas em cada imóvel salvo pelo job
+	Status        string           `json:"status"`         // queued, running, cancelling, cancelled, completed, failed
+	StartedAt     time.Time        `json:"started_at"`
+	CompletedAt   *time.Time       `json:"completed_at,omitempty"`
+	PausedDomains []string         `json:"paused_domains,omitempty"`
+	DeferredURLs  int              `json:"deferred_urls"` // URLs aguardando retomada de domínios pausados
+	Coverage      []DomainCoverage `json:"coverage,omitempty"`
+	Cost          *CrawlJobCost    `json:"cost,omitempty"` // estimativa de custo, ao terminar
+	// Experimento A/B dos limiares do classificador (CLASSIFIER_EXPERIMENT_SAMPLE_RATE), ao terminar
+	ClassifierExperiment *ClassifierExperimentReport `json:"classifier_experiment,omitempty"`
+	Error                string                      `json:"error,omitempty"`
+
+	// Parâmetros vindos de um template de job e o consumo do orçamento
+	Template           string   `json:"template,omitempty"`
+	Domains            []string `json:"domains,omitempty"`
+	MaxPages           int      `json:"max_pages,omitempty"`
+	MaxDurationMinutes int      `json:"max_duration_minutes,omitempty"`
+	VisitedPages       int      `json:"visited_pages"`
+	BudgetExhausted    string   `json:"budget_exhausted,omitempty"` // max_pages ou max_duration
+}
+
+// DomainCoverage estimativa de cobertura do crawling em um domínio
+type DomainCoverage struct {
+	Domain                 string    `json:"domain"`
+	DiscoveredURLs         int       `json:"discovered_urls"`       // imóveis encontrados pelo crawling
+	SitemapPropertyURLs    int       `json:"sitemap_property_urls"` // URLs de imóveis no sitemap (-1 sem sitemap)
+	CatalogTotal           int       `json:"catalog_total"`         // maior total exibido nos catálogos ("X imóveis encontrados")
+	EstimatedTotal         int       `json:"estimated_total"`
+	EstimatedMissedPercent float64   `json:"estimated_missed_percent"`
+	Sources                []string  `json:"sources,omitempty"` // fontes usadas na estimativa: sitemap, catalog_total
+	GeneratedAt            time.Time `json:"generated_at"`
+}
+
+// IsFinished indica se o job já terminou (com sucesso, falha ou cancelamento)
+func (j *CrawlJob) IsFinished() bool {
+	return j.Status == CrawlJobCompleted || j.Status == CrawlJobFailed || j.Status == CrawlJobCancelled
+}
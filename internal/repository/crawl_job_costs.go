@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CrawlJobCostCollection guarda a estimativa de custo de cada job de crawling terminado
+const CrawlJobCostCollection = "crawl_job_costs"
+
+// CrawlJobCost é o consumo de um job (IA, rede, tempo e proxy) e o custo estimado em US$ com os
+// preços configurados, para comparar o custo-benefício dos modos de crawling
+type CrawlJobCost struct {
+	JobID       string    `bson:"_id" json:"job_id"`
+	Mode        string    `bson:"mode" json:"mode"`
+	AIEnabled   bool      `bson:"ai_enabled" json:"ai_enabled"` // o job rodou com o serviço de IA disponível
+	Template    string    `bson:"template,omitempty" json:"template,omitempty"`
+	Status      string    `bson:"status" json:"status"`
+	StartedAt   time.Time `bson:"started_at" json:"started_at"`
+	CompletedAt time.Time `bson:"completed_at" json:"completed_at"`
+
+	Pages          int     `bson:"pages" json:"pages"`
+	Properties     int64   `bson:"properties" json:"properties"` // imóveis salvos
+	Requests       int64   `bson:"requests" json:"requests"`
+	Bytes          int64   `bson:"bytes" json:"bytes"`
+	ProxyRequests  int64   `bson:"proxy_requests" json:"proxy_requests"`
+	ProxyBytes     int64   `bson:"proxy_bytes" json:"proxy_bytes"`
+	ComputeSeconds float64 `bson:"compute_seconds" json:"compute_seconds"` // duração da execução
+	AICalls        int64   `bson:"ai_calls" json:"ai_calls"`
+	AIPromptTokens int64   `bson:"ai_prompt_tokens" json:"ai_prompt_tokens"`
+	AIOutputTokens int64   `bson:"ai_output_tokens" json:"ai_output_tokens"`
+
+	AICostUSD        float64 `bson:"ai_cost_usd" json:"ai_cost_usd"`
+	ComputeCostUSD   float64 `bson:"compute_cost_usd" json:"compute_cost_usd"`
+	BandwidthCostUSD float64 `bson:"bandwidth_cost_usd" json:"bandwidth_cost_usd"`
+	ProxyCostUSD     float64 `bson:"proxy_cost_usd" json:"proxy_cost_usd"`
+	TotalCostUSD     float64 `bson:"total_cost_usd" json:"total_cost_usd"`
+	CostPerProperty  float64 `bson:"cost_per_property_usd" json:"cost_per_property_usd"` // 0 sem imóveis salvos
+}
+
+// CrawlJobCostFilter filtra a listagem de custos (campos vazios não filtram)
+type CrawlJobCostFilter struct {
+	Mode  string
+	Since time.Time
+	Limit int
+}
+
+// CrawlJobCostRepository é implementado por repositórios capazes de guardar o custo dos jobs
+type CrawlJobCostRepository interface {
+	// SaveCrawlJobCost grava (ou substitui) o custo do job
+	SaveCrawlJobCost(ctx context.Context, cost CrawlJobCost) error
+	// FindCrawlJobCosts retorna os custos dos jobs mais recentes primeiro
+	FindCrawlJobCosts(ctx context.Context, filter CrawlJobCostFilter) ([]CrawlJobCost, error)
+}
+
+// CrawlCostSummary resume o custo dos jobs de um modo (com ou sem IA)
+type CrawlCostSummary struct {
+	Mode               string  `json:"mode"`
+	AIEnabled          bool    `json:"ai_enabled"`
+	Jobs               int     `json:"jobs"`
+	Properties         int64   `json:"properties"`
+	Bytes              int64   `json:"bytes"`
+	ComputeSeconds     float64 `json:"compute_seconds"`
+	AITokens           int64   `json:"ai_tokens"`
+	TotalCostUSD       float64 `json:"total_cost_usd"`
+	AvgCostPerJob      float64 `json:"avg_cost_per_job_usd"`
+	CostPerProperty    float64 `json:"cost_per_property_usd"` // 0 sem imóveis salvos
+	PropertiesPerMByte float64 `json:"properties_per_mbyte"`
+}
+
+// SummarizeCrawlJobCosts agrupa os custos por modo e uso de IA, do grupo com menor custo por
+// imóvel ao maior (grupos sem imóveis salvos ficam no fim)
+func SummarizeCrawlJobCosts(costs []CrawlJobCost) []CrawlCostSummary {
+	type groupKey struct {
+		mode string
+		ai   bool
+	}
+	groups := make(map[groupKey]*CrawlCostSummary)
+	for _, cost := range costs {
+		key := groupKey{mode: cost.Mode, ai: cost.AIEnabled}
+		summary, exists := groups[key]
+		if !exists {
+			summary = &CrawlCostSummary{Mode: cost.Mode, AIEnabled: cost.AIEnabled}
+			groups[key] = summary
+		}
+		summary.Jobs++
+		summary.Properties += cost.Properties
+		summary.Bytes += cost.Bytes
+		summary.ComputeSeconds += cost.ComputeSeconds
+		summary.AITokens += cost.AIPromptTokens + cost.AIOutputTokens
+		summary.TotalCostUSD += cost.TotalCostUSD
+	}
+
+	summaries := make([]CrawlCostSummary, 0, len(groups))
+	for _, summary := range groups {
+		summary.AvgCostPerJob = summary.TotalCostUSD / float64(summary.Jobs)
+		if summary.Properties > 0 {
+			summary.CostPerProperty = summary.TotalCostUSD / float64(summary.Properties)
+		}
+		if summary.Bytes > 0 {
+			summary.PropertiesPerMByte = float64(summary.Properties) / (float64(summary.Bytes) / (1 << 20))
+		}
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if (a.Properties > 0) != (b.Properties > 0) {
+			return a.Properties > 0
+		}
+		if a.CostPerProperty != b.CostPerProperty {
+			return a.CostPerProperty < b.CostPerProperty
+		}
+		if a.Mode != b.Mode {
+			return a.Mode < b.Mode
+		}
+		return !a.AIEnabled && b.AIEnabled
+	})
+	return summaries
+}
+
+// crawlJobCosts retorna a coleção de custos dos jobs
+func (r *MongoRepository) crawlJobCosts() *mongo.Collection {
+	return r.collection.Database().Collection(CrawlJobCostCollection)
+}
+
+// SaveCrawlJobCost grava o custo do job, substituindo um registro anterior do mesmo job
+func (r *MongoRepository) SaveCrawlJobCost(ctx context.Context, cost CrawlJobCost) error {
+	_, err := r.crawlJobCosts().ReplaceOne(ctx, bson.M{"_id": cost.JobID}, cost, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save crawl job cost: %v", err)
+	}
+	return nil
+}
+
+// FindCrawlJobCosts retorna os custos do filtro, os jobs terminados mais recentemente primeiro
+func (r *MongoRepository) FindCrawlJobCosts(ctx context.Context, filter CrawlJobCostFilter) ([]CrawlJobCost, error) {
+	query := bson.M{}
+	if filter.Mode != "" {
+		query["mode"] = filter.Mode
+	}
+	if !filter.Since.IsZero() {
+		query["completed_at"] = bson.M{"$gte": filter.Since}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "completed_at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.crawlJobCosts().Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find crawl job costs: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	costs := []CrawlJobCost{}
+	if err := cursor.All(ctx, &costs); err != nil {
+		return nil, fmt.Errorf("failed to decode crawl job costs: %v", err)
+	}
+	return costs, nil
+}
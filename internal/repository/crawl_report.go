@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CrawlReportCollection guarda o volume de imóveis de cada crawling por domínio
+const CrawlReportCollection = "crawl_reports"
+
+// CrawlReport é o número de imóveis encontrados em um domínio ao fim de um crawling
+type CrawlReport struct {
+	JobID      string    `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	Domain     string    `bson:"domain" json:"domain"`
+	City       string    `bson:"city,omitempty" json:"city,omitempty"`
+	Properties int       `bson:"properties" json:"properties"`
+	CrawledAt  time.Time `bson:"crawled_at" json:"crawled_at"`
+
+	// Rendimento por origem de descoberta (ver discovery_sources.go)
+	Sources map[string]SourceYield `bson:"sources,omitempty" json:"sources,omitempty"`
+}
+
+// CrawlReportRepository é implementado por repositórios que guardam o histórico dos crawlings
+type CrawlReportRepository interface {
+	SaveCrawlReports(ctx context.Context, reports []CrawlReport) error
+	RecentCrawlReports(ctx context.Context, domain string, limit int) ([]CrawlReport, error)
+}
+
+// crawlReports retorna a coleção do histórico de crawlings
+func (r *MongoRepository) crawlReports() *mongo.Collection {
+	return r.collection.Database().Collection(CrawlReportCollection)
+}
+
+// SaveCrawlReports grava os relatórios de um crawling
+func (r *MongoRepository) SaveCrawlReports(ctx context.Context, reports []CrawlReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+
+	collection := r.crawlReports()
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "domain", Value: 1}, {Key: "crawled_at", Value: -1}},
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return fmt.Errorf("failed to create crawl report indexes: %v", err)
+	}
+
+	documents := make([]interface{}, 0, len(reports))
+	for _, report := range reports {
+		documents = append(documents, report)
+	}
+	return withStorageRetry(ctx, "failed to save crawl reports", func() error {
+		_, err := collection.InsertMany(ctx, documents)
+		return err
+	})
+}
+
+// RecentCrawlReports retorna os últimos relatórios do domínio, do mais recente ao mais antigo
+func (r *MongoRepository) RecentCrawlReports(ctx context.Context, domain string, limit int) ([]CrawlReport, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "crawled_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.crawlReports().Find(ctx, bson.M{"domain": NormalizeDomain(domain)}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find crawl reports: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reports := []CrawlReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode crawl reports: %v", err)
+	}
+	return reports, nil
+}
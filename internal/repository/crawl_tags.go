@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Limites das tags de um job de crawling
+const (
+	maxCrawlTags      = 10
+	maxCrawlTagLength = 64
+)
+
+// crawlTagPattern aceita tags como "muzambinho-weekly" ou "campanha:2024.q3"
+var crawlTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]*$`)
+
+// NormalizeCrawlTags converte as tags para minúsculas, remove repetições e valida o formato
+func NormalizeCrawlTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxCrawlTagLength || !crawlTagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q (use letters, digits, '.', '_', ':' or '-', up to %d characters)", tag, maxCrawlTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxCrawlTags {
+		return nil, fmt.Errorf("too many tags (max %d)", maxCrawlTags)
+	}
+	return normalized, nil
+}
+
+// ParseCrawlTags interpreta as tags separadas por vírgula (flag -tags e parâmetro ?tags=)
+func ParseCrawlTags(raw string) ([]string, error) {
+	return NormalizeCrawlTags(strings.Split(raw, ","))
+}
+
+// mergeCrawlTags junta as tags sem repetições, mantendo a ordem
+func mergeCrawlTags(current, added []string) []string {
+	merged := append([]string(nil), current...)
+	for _, tag := range added {
+		found := false
+		for _, existing := range merged {
+			found = found || existing == tag
+		}
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// TaggedRepository registra as tags do job em cada imóvel salvo pelos engines; as consultas vão
+// para o repositório original
+type TaggedRepository struct {
+	PropertyRepository
+	tags []string
+}
+
+// NewTaggedRepository envolve o repositório com as tags do job (sem tags, retorna o próprio repositório)
+func NewTaggedRepository(repo PropertyRepository, tags []string) PropertyRepository {
+	if len(tags) == 0 {
+		return repo
+	}
+	return &TaggedRepository{PropertyRepository: repo, tags: tags}
+}
+
+// Save acrescenta as tags do job às do imóvel antes de gravar
+func (tr *TaggedRepository) Save(ctx context.Context, property Property) error {
+	property.Tags = mergeCrawlTags(property.Tags, tr.tags)
+	return tr.PropertyRepository.Save(ctx, property)
+}
+
+// FindByURL permite a classificação de mudanças do crawling incremental (nil sem suporte)
+func (tr *TaggedRepository) FindByURL(ctx context.Context, url string) (*Property, error) {
+	if lookup, ok := tr.PropertyRepository.(PropertyLookupRepository); ok {
+		return lookup.FindByURL(ctx, url)
+	}
+	return nil, nil
+}
+
+// SaveArchivedListing permite completar os anúncios removidos com a cópia arquivada
+func (tr *TaggedRepository) SaveArchivedListing(ctx context.Context, property Property) error {
+	if store, ok := tr.PropertyRepository.(ArchivedListingRepository); ok {
+		return store.SaveArchivedListing(ctx, property)
+	}
+	return errArchivedListingUnsupported
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCrawlTimezone é o fuso das janelas de crawling dos domínios sem timezone configurado
+const DefaultCrawlTimezone = "America/Sao_Paulo"
+
+// maxCrawlWindows limita as janelas de crawling por domínio
+const maxCrawlWindows = 12
+
+// CrawlWindow é um intervalo do dia (em minutos desde 00:00) em que o domínio pode ser
+// crawleado; com End <= Start a janela atravessa a meia-noite (ex: 22:00-06:00)
+type CrawlWindow struct {
+	Start int
+	End   int
+}
+
+// ParseCrawlWindow interpreta uma janela no formato "HH:MM-HH:MM" (fim até 24:00)
+func ParseCrawlWindow(value string) (CrawlWindow, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 2 {
+		return CrawlWindow{}, fmt.Errorf("janela de crawling inválida %q (use HH:MM-HH:MM)", value)
+	}
+	start, err := parseClockMinute(parts[0])
+	if err != nil || start == 24*60 {
+		return CrawlWindow{}, fmt.Errorf("janela de crawling inválida %q (use HH:MM-HH:MM)", value)
+	}
+	end, err := parseClockMinute(parts[1])
+	if err != nil || end == start {
+		return CrawlWindow{}, fmt.Errorf("janela de crawling inválida %q (use HH:MM-HH:MM)", value)
+	}
+	return CrawlWindow{Start: start, End: end}, nil
+}
+
+// parseClockMinute converte "HH:MM" em minutos desde 00:00
+func parseClockMinute(value string) (int, error) {
+	hour, minute, found := strings.Cut(strings.TrimSpace(value), ":")
+	if !found || len(minute) != 2 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	h, err := strconv.Atoi(hour)
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return h*60 + m, nil
+}
+
+// Contains indica se o minuto do dia está dentro da janela
+func (w CrawlWindow) Contains(minute int) bool {
+	if w.End > w.Start {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// String formata a janela como "HH:MM-HH:MM"
+func (w CrawlWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// LoadCrawlLocation carrega o fuso das janelas de crawling (vazio usa America/Sao_Paulo). Sem a
+// base de fusos do sistema, America/Sao_Paulo cai para UTC-3, sem horário de verão desde 2019.
+func LoadCrawlLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultCrawlTimezone
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		if name == DefaultCrawlTimezone {
+			return time.FixedZone("-03", -3*60*60), nil
+		}
+		return nil, fmt.Errorf("timezone inválido %q: %v", name, err)
+	}
+	return location, nil
+}
+
+// ParseCrawlWindows interpreta as janelas de crawling configuradas do domínio
+func (dc *DomainConfig) ParseCrawlWindows() ([]CrawlWindow, error) {
+	windows := make([]CrawlWindow, 0, len(dc.CrawlWindows))
+	for _, value := range dc.CrawlWindows {
+		window, err := ParseCrawlWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// InCrawlWindow indica se o domínio pode ser crawleado no instante informado (sem janelas
+// configuradas, ou com configuração inválida, qualquer horário é permitido)
+func (dc *DomainConfig) InCrawlWindow(now time.Time) bool {
+	windows, err := dc.ParseCrawlWindows()
+	if err != nil || len(windows) == 0 {
+		return true
+	}
+	location, err := LoadCrawlLocation(dc.Timezone)
+	if err != nil {
+		return true
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	for _, window := range windows {
+		if window.Contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextCrawlWindow retorna o início da próxima janela de crawling do domínio (o próprio instante
+// quando dentro de uma janela)
+func (dc *DomainConfig) NextCrawlWindow(now time.Time) time.Time {
+	if dc.InCrawlWindow(now) {
+		return now
+	}
+	windows, _ := dc.ParseCrawlWindows()
+	location, _ := LoadCrawlLocation(dc.Timezone)
+
+	local := now.In(location)
+	var next time.Time
+	for _, window := range windows {
+		start := time.Date(local.Year(), local.Month(), local.Day(), window.Start/60, window.Start%60, 0, 0, location)
+		if !start.After(local) {
+			start = start.AddDate(0, 0, 1)
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// validateCrawlWindows verifica as janelas e o fuso da configuração do domínio
+func (dc *DomainConfig) validateCrawlWindows() error {
+	if len(dc.CrawlWindows) > maxCrawlWindows {
+		return fmt.Errorf("no máximo %d janelas de crawling por domínio", maxCrawlWindows)
+	}
+	if _, err := dc.ParseCrawlWindows(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(dc.Timezone) != "" {
+		if _, err := LoadCrawlLocation(dc.Timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import "strings"
+
+// SetCustomField grava um campo adicional (ex: código interno da imobiliária) e registra sua origem.
+// Valores nil removem o campo.
+func (p *Property) SetCustomField(name string, value interface{}, extractor string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	if value == nil {
+		delete(p.CustomFields, name)
+		return
+	}
+	if p.CustomFields == nil {
+		p.CustomFields = make(map[string]interface{})
+	}
+	p.CustomFields[name] = value
+	p.SetProvenance("custom_fields."+name, extractor, "")
+}
+
+// CustomField retorna um campo adicional e se ele existe
+func (p *Property) CustomField(name string) (interface{}, bool) {
+	value, exists := p.CustomFields[name]
+	return value, exists
+}
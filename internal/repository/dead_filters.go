@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeadFilterCollection guarda as combinações de filtros geradas pela descoberta interativa que
+// retornaram a página de "nenhum imóvel encontrado"
+const DeadFilterCollection = "dead_filter_combinations"
+
+// DeadFilterCombination é uma combinação de filtros sem resultados em um domínio; depois de
+// ExpiresAt ela volta a ser gerada (o Mongo remove o registro)
+type DeadFilterCombination struct {
+	Domain      string    `bson:"domain" json:"domain"`
+	Combination string    `bson:"combination" json:"combination"` // ex: "/busca?operacao=aluguel&tipo=casa"
+	Hits        int       `bson:"hits" json:"hits"`
+	FirstSeenAt time.Time `bson:"first_seen_at" json:"first_seen_at"`
+	LastSeenAt  time.Time `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt   time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// DeadFilterRepository é implementado por repositórios capazes de lembrar, entre crawlings, as
+// combinações de filtros que não retornam imóveis
+type DeadFilterRepository interface {
+	// RecordDeadFilter marca a combinação como sem resultados até now + retry
+	RecordDeadFilter(ctx context.Context, domain, combination string, retry time.Duration) error
+	// ClearDeadFilter remove a marcação quando a combinação volta a ter resultados
+	ClearDeadFilter(ctx context.Context, domain, combination string) error
+	// FindDeadFilters retorna as combinações vigentes do domínio (vazio retorna todos os domínios)
+	FindDeadFilters(ctx context.Context, domain string) ([]DeadFilterCombination, error)
+}
+
+// FilterCombination identifica a combinação de filtros da URL sem o host: caminho em minúsculas,
+// sem barra final, e parâmetros em ordem alfabética (ex: "/busca?operacao=venda&tipo=casa")
+func FilterCombination(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimRight(strings.ToLower(parsed.Path), "/")
+	if path == "" {
+		path = "/"
+	}
+	if query := parsed.Query().Encode(); query != "" {
+		return path + "?" + query
+	}
+	return path
+}
+
+// deadFilters retorna a coleção das combinações sem resultados
+func (r *MongoURLRepository) deadFilters() *mongo.Collection {
+	return r.urlCollection.Database().Collection(DeadFilterCollection)
+}
+
+// createDeadFilterIndexes cria o índice único por domínio e combinação e o TTL em expires_at
+func (r *MongoURLRepository) createDeadFilterIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "domain", Value: 1}, {Key: "combination", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	if _, err := r.deadFilters().Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create dead filter indexes: %v", err)
+	}
+	return nil
+}
+
+// RecordDeadFilter grava (ou renova) a combinação sem resultados e soma a ocorrência
+func (r *MongoURLRepository) RecordDeadFilter(ctx context.Context, domain, combination string, retry time.Duration) error {
+	now := time.Now()
+	_, err := r.deadFilters().UpdateOne(ctx,
+		bson.M{"domain": NormalizeDomain(domain), "combination": combination},
+		bson.M{
+			"$inc":         bson.M{"hits": 1},
+			"$set":         bson.M{"last_seen_at": now, "expires_at": now.Add(retry)},
+			"$setOnInsert": bson.M{"first_seen_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record dead filter combination: %v", err)
+	}
+	return nil
+}
+
+// ClearDeadFilter remove a combinação da lista sem resultados do domínio
+func (r *MongoURLRepository) ClearDeadFilter(ctx context.Context, domain, combination string) error {
+	_, err := r.deadFilters().DeleteOne(ctx, bson.M{"domain": NormalizeDomain(domain), "combination": combination})
+	if err != nil {
+		return fmt.Errorf("failed to clear dead filter combination: %v", err)
+	}
+	return nil
+}
+
+// FindDeadFilters retorna as combinações ainda não vencidas, as mais recentes primeiro
+func (r *MongoURLRepository) FindDeadFilters(ctx context.Context, domain string) ([]DeadFilterCombination, error) {
+	filter := bson.M{"expires_at": bson.M{"$gt": time.Now()}}
+	if domain != "" {
+		filter["domain"] = NormalizeDomain(domain)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	cursor, err := r.deadFilters().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dead filter combinations: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	combinations := []DeadFilterCombination{}
+	if err := cursor.All(ctx, &combinations); err != nil {
+		return nil, fmt.Errorf("failed to decode dead filter combinations: %v", err)
+	}
+	return combinations, nil
+}
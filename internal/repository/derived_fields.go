@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Tipos de imóvel da taxonomia usada nos filtros e estatísticas
+const (
+	TipoCasa        = "Casa"
+	TipoApartamento = "Apartamento"
+	TipoTerreno     = "Terreno"
+	TipoComercial   = "Comercial"
+	TipoRural       = "Rural"
+	TipoOutro       = "Outro"
+)
+
+// propertyTypeKeywords mapeia cada tipo para as variações de texto encontradas nos anúncios
+// (sem acentos e em minúsculas); a ordem define a prioridade quando o texto cita mais de um
+var propertyTypeKeywords = []struct {
+	tipo     string
+	keywords []string
+}{
+	{TipoApartamento, []string{"apartamento", "apto", "ap ", "cobertura", "flat", "kitnet", "kitinete", "studio", "loft"}},
+	{TipoCasa, []string{"casa", "sobrado", "residencia", "edicula", "geminada"}},
+	{TipoRural, []string{"chacara", "sitio", "fazenda", "rancho", "haras", "area rural"}},
+	{TipoTerreno, []string{"terreno", "lote", "loteamento"}},
+	{TipoComercial, []string{"comercial", "loja", "galpao", "barracao", "predio"}},
+}
+
+// NormalizePropertyType converte o tipo informado pelo site para a taxonomia; se o tipo não for
+// reconhecido, tenta inferi-lo do texto do anúncio (endereço e descrição)
+func NormalizePropertyType(tipo, text string) string {
+	if normalized := matchPropertyType(tipo); normalized != "" {
+		return normalized
+	}
+	if normalized := matchPropertyType(text); normalized != "" {
+		return normalized
+	}
+	return TipoOutro
+}
+
+// matchPropertyType retorna o primeiro tipo da taxonomia citado no texto
+func matchPropertyType(text string) string {
+	normalized := " " + utils.NormalizeText(text) + " "
+	if strings.TrimSpace(normalized) == "" {
+		return ""
+	}
+	for _, entry := range propertyTypeKeywords {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(normalized, " "+keyword) {
+				return entry.tipo
+			}
+		}
+	}
+	return ""
+}
+
+// CalculateValorM2 retorna o preço por m² usando a área útil (ou a total); 0 sem dados
+func CalculateValorM2(property Property) float64 {
+	area := property.AreaUtil
+	if area <= 0 {
+		area = property.AreaTotal
+	}
+	if property.Valor <= 0 || area <= 0 {
+		return 0
+	}
+	return math.Round(property.Valor/area*100) / 100
+}
+
+// ApplyDerivedFields recalcula os campos derivados dos dados extraídos (preço por m²,
+// comodidades normalizadas, tipo da taxonomia, recursos, leilão, finalidade e aluguel).
+// Não altera o hash do imóvel nem o tipo informado pelo site (TipoImovel); a taxonomia
+// fica em TipoNormalizado.
+func ApplyDerivedFields(property *Property) {
+	property.ValorM2 = CalculateValorM2(*property)
+	property.Amenidades = NormalizeAmenities(property.Caracteristicas)
+	property.TipoNormalizado = NormalizePropertyType(property.TipoImovel, property.Endereco+" "+property.Descricao)
+	applyFeatures(property)
+	applyAuction(property)
+	applyCommercialLease(property)
+	applyVacationRental(property)
+}
+
+// BackfillOptions controla o recálculo dos campos derivados dos imóveis já salvos
+type BackfillOptions struct {
+	BatchSize int
+	Resume    BackfillProgress // andamento anterior: retoma após Resume.LastHash (ordem de hash)
+	Geocoder  Geocoder         // opcional: geocodifica imóveis ainda sem localização
+	DryRun    bool             // apenas conta as alterações, sem gravar
+
+	// PhotoHasher opcional: calcula os hashes perceptuais das fotos ainda não processadas
+	PhotoHasher PhotoHasher
+
+	// PhotoCounter opcional: baixa de novo as páginas dos anúncios e reconta as fotos da galeria
+	PhotoCounter PhotoCounter
+
+	// POIFinder opcional: calcula as distâncias dos imóveis geocodificados até os pontos de interesse
+	POIFinder POIFinder
+
+	// OnBatch é chamado após cada lote (ex: para gravar o checkpoint de retomada)
+	OnBatch func(progress BackfillProgress) error
+}
+
+// BackfillProgress resume o andamento do backfill
+type BackfillProgress struct {
+	LastHash      string `json:"last_hash"`
+	Processed     int    `json:"processed"`
+	Updated       int    `json:"updated"`
+	Geocoded      int    `json:"geocoded"`
+	GeocodeErrors int    `json:"geocode_errors"`
+	PhotosHashed  int    `json:"photos_hashed"`
+	PhotosCounted int    `json:"photos_counted"`
+	PhotoErrors   int    `json:"photo_count_errors"`
+	POIEnriched   int    `json:"poi_enriched"`
+	POIErrors     int    `json:"poi_errors"`
+}
+
+// PhotoCounter baixa a página do anúncio e retorna as URLs das fotos da galeria
+type PhotoCounter interface {
+	CountPhotos(ctx context.Context, pageURL string) ([]string, error)
+}
+
+// BackfillDerivedFields percorre os imóveis em lotes, recalcula os campos derivados e grava
+// apenas os que mudaram. Pode ser retomado a partir de BackfillOptions.Resume.
+func (r *MongoRepository) BackfillDerivedFields(ctx context.Context, opts BackfillOptions) (BackfillProgress, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	progress := opts.Resume
+	counted := make(map[string][]string) // fotos por URL (as versões do anúncio baixam a página uma vez)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		filter := bson.M{"hash": bson.M{"$gt": progress.LastHash}}
+		findOpts := options.Find().SetSort(bson.D{{Key: "hash", Value: 1}}).SetLimit(int64(batchSize))
+		var batch []Property
+		err := withStorageRetry(ctx, "failed to load properties for backfill", func() error {
+			cursor, err := r.collection.Find(ctx, filter, findOpts)
+			if err != nil {
+				return err
+			}
+			batch = nil
+			return cursor.All(ctx, &batch)
+		})
+		if err != nil {
+			return progress, err
+		}
+		if len(batch) == 0 {
+			return progress, nil
+		}
+
+		var writes []mongo.WriteModel
+		for _, property := range batch {
+			update := backfillUpdate(ctx, property, opts.Geocoder, &progress)
+			r.backfillScamScore(ctx, property, update)
+			if opts.POIFinder != nil {
+				backfillPOIDistances(ctx, property, opts.POIFinder, update, &progress)
+			}
+			if opts.PhotoCounter != nil {
+				backfillPhotoCount(ctx, property, opts.PhotoCounter, counted, update, &progress)
+			}
+			if opts.PhotoHasher != nil && len(property.FotoURLs) > 0 && len(property.FotoHashes) == 0 {
+				if hashes := opts.PhotoHasher.HashPhotos(ctx, property.FotoURLs); len(hashes) > 0 {
+					update["foto_hashes"] = hashes
+					progress.PhotosHashed++
+				}
+			}
+			if len(update) > 0 {
+				writes = append(writes, mongo.NewUpdateOneModel().
+					SetFilter(bson.M{"hash": property.Hash}).
+					SetUpdate(bson.M{"$set": update}))
+			}
+			progress.Processed++
+			progress.LastHash = property.Hash
+		}
+
+		if len(writes) > 0 && !opts.DryRun {
+			err := withStorageRetry(ctx, "failed to write backfilled properties", func() error {
+				_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+				return err
+			})
+			if err != nil {
+				return progress, err
+			}
+		}
+		progress.Updated += len(writes)
+
+		if opts.OnBatch != nil {
+			if err := opts.OnBatch(progress); err != nil {
+				return progress, fmt.Errorf("backfill checkpoint failed: %v", err)
+			}
+		}
+	}
+}
+
+// backfillScamScore recalcula o risco de golpe com os campos derivados atualizados e a mediana
+// atual do bairro, incluindo no update apenas o que mudou
+func (r *MongoRepository) backfillScamScore(ctx context.Context, property Property, update bson.M) {
+	scored := property
+	ApplyDerivedFields(&scored)
+	r.applyScamScore(ctx, &scored)
+	if scored.RiscoGolpe != property.RiscoGolpe || scored.SuspeitoGolpe != property.SuspeitoGolpe ||
+		strings.Join(scored.SinaisGolpe, ",") != strings.Join(property.SinaisGolpe, ",") {
+		update["risco_golpe"] = scored.RiscoGolpe
+		update["sinais_golpe"] = scored.SinaisGolpe
+		update["suspeito_golpe"] = scored.SuspeitoGolpe
+	}
+}
+
+// backfillPhotoCount reconta as fotos da galeria do anúncio e inclui fotos, sem_fotos e
+// foto_urls no update se mudaram. Imóveis importados não têm página para baixar; páginas com
+// erro mantêm a contagem salva
+func backfillPhotoCount(ctx context.Context, property Property, counter PhotoCounter, counted map[string][]string, update bson.M, progress *BackfillProgress) {
+	if property.Source == SourceImport || property.URL == "" {
+		return
+	}
+	photos, done := counted[property.URL]
+	if !done {
+		var err error
+		photos, err = counter.CountPhotos(ctx, property.URL)
+		if err != nil {
+			progress.PhotoErrors++
+			return
+		}
+		counted[property.URL] = photos
+		progress.PhotosCounted++
+	}
+
+	count := len(photos)
+	if count > MaxPhotoURLs {
+		photos = photos[:MaxPhotoURLs]
+	}
+	if count != property.Fotos || strings.Join(photos, " ") != strings.Join(property.FotoURLs, " ") {
+		update["fotos"] = count
+		update["sem_fotos"] = count == 0
+		update["foto_urls"] = photos
+	}
+}
+
+// backfillPOIDistances calcula as distâncias até os pontos de interesse da cidade, usando a
+// localização recém-geocodificada quando houver, e as inclui no update se mudaram
+func backfillPOIDistances(ctx context.Context, property Property, finder POIFinder, update bson.M, progress *BackfillProgress) {
+	location := property.Location
+	if geocoded, ok := update["location"].(*GeoPoint); ok {
+		location = geocoded
+	}
+	if location == nil {
+		return
+	}
+
+	pois, err := finder.FindPOIs(ctx, property.Cidade)
+	if err != nil {
+		progress.POIErrors++
+		return
+	}
+	distances := pois.Distances(location)
+	if distances == nil {
+		return
+	}
+	if property.DistanciasPOI == nil || *property.DistanciasPOI != *distances {
+		update["distancias_poi"] = distances
+		progress.POIEnriched++
+	}
+}
+
+// sameDate compara datas opcionais
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// backfillUpdate calcula os campos derivados do imóvel e retorna apenas os que mudaram
+func backfillUpdate(ctx context.Context, property Property, geocoder Geocoder, progress *BackfillProgress) bson.M {
+	derived := property
+	ApplyDerivedFields(&derived)
+
+	update := bson.M{}
+	if derived.ValorM2 != property.ValorM2 {
+		update["valor_m2"] = derived.ValorM2
+	}
+	if strings.Join(derived.Amenidades, ",") != strings.Join(property.Amenidades, ",") {
+		update["amenidades"] = derived.Amenidades
+	}
+	if derived.TipoNormalizado != property.TipoNormalizado {
+		update["tipo_normalizado"] = derived.TipoNormalizado
+	}
+	if derived.EnergiaSolar != property.EnergiaSolar {
+		update["energia_solar"] = derived.EnergiaSolar
+	}
+	if derived.PocoArtesiano != property.PocoArtesiano {
+		update["poco_artesiano"] = derived.PocoArtesiano
+	}
+	if derived.AquecimentoGas != property.AquecimentoGas {
+		update["aquecimento_gas"] = derived.AquecimentoGas
+	}
+	if derived.Leilao != property.Leilao || derived.LanceMinimo != property.LanceMinimo ||
+		!sameDate(derived.LeilaoData, property.LeilaoData) {
+		update["leilao"] = derived.Leilao
+		update["leilao_data"] = derived.LeilaoData
+		update["lance_minimo"] = derived.LanceMinimo
+	}
+	if derived.Finalidade != property.Finalidade {
+		update["finalidade"] = derived.Finalidade
+	}
+	if derived.ValorVenda != property.ValorVenda || derived.ValorAluguel != property.ValorAluguel {
+		update["valor_venda"] = derived.ValorVenda
+		update["valor_aluguel"] = derived.ValorAluguel
+	}
+	if derived.ValorDiaria != property.ValorDiaria || derived.MinimoNoites != property.MinimoNoites {
+		update["valor_diaria"] = derived.ValorDiaria
+		update["minimo_noites"] = derived.MinimoNoites
+	}
+	if derived.Testada != property.Testada {
+		update["testada"] = derived.Testada
+	}
+	if strings.Join(derived.Zoneamento, ",") != strings.Join(property.Zoneamento, ",") {
+		update["zoneamento"] = derived.Zoneamento
+	}
+
+	if geocoder != nil && property.Location == nil {
+		location, err := geocoder.Geocode(ctx, property.Endereco, property.Bairro, property.Cidade)
+		switch {
+		case err != nil:
+			progress.GeocodeErrors++
+		case location != nil:
+			update["location"] = location
+			progress.Geocoded++
+		}
+	}
+	return update
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Origens de descoberta dos anúncios: a primeira estratégia, depois da seed, no caminho até a página
+const (
+	DiscoverySourceSeed            = "seed"             // a própria seed ou um link direto dela
+	DiscoverySourceLink            = "link"             // links seguidos a partir da seed, sem outra estratégia
+	DiscoverySourceFeed            = "feed"             // entradas de feeds RSS/Atom
+	DiscoverySourceAPI             = "api"              // API JSON configurada para o domínio
+	DiscoverySourceInteractiveForm = "interactive_form" // URLs geradas dos formulários de busca
+	DiscoverySourcePagination      = "pagination"       // páginas seguintes de um catálogo
+	DiscoverySourceClassifiedLink  = "classified_link"  // links que o classificador apontou como anúncios
+)
+
+// disableableDiscoverySources são as estratégias que podem ser desativadas por domínio (seeds,
+// links comuns e a API são a base do crawling e são controlados por outras configurações)
+var disableableDiscoverySources = map[string]bool{
+	DiscoverySourceFeed:            true,
+	DiscoverySourceInteractiveForm: true,
+	DiscoverySourcePagination:      true,
+	DiscoverySourceClassifiedLink:  true,
+}
+
+// SourceYield é o rendimento de uma origem de descoberta: páginas visitadas e anúncios encontrados
+type SourceYield struct {
+	Visited    int `bson:"visited" json:"visited"`
+	Properties int `bson:"properties" json:"properties"`
+}
+
+// DiscoverySourceYield é o rendimento acumulado de uma origem em um domínio
+type DiscoverySourceYield struct {
+	Source     string  `json:"source"`
+	Visited    int     `json:"visited"`
+	Properties int     `json:"properties"`
+	Yield      float64 `json:"yield"` // anúncios por página visitada
+	Crawls     int     `json:"crawls"`
+	Disabled   bool    `json:"disabled"`
+}
+
+// validateDiscoverySources verifica as estratégias desativadas da configuração
+func (dc *DomainConfig) validateDiscoverySources() error {
+	for _, source := range dc.DisabledDiscoverySources {
+		if !disableableDiscoverySources[source] {
+			return fmt.Errorf("disabled_discovery_sources inválida: %q (use feed, interactive_form, pagination ou classified_link)", source)
+		}
+	}
+	return nil
+}
+
+// DiscoverySourceEnabled informa se a estratégia de descoberta está ativa no domínio
+func (dc *DomainConfig) DiscoverySourceEnabled(source string) bool {
+	for _, disabled := range dc.DisabledDiscoverySources {
+		if disabled == source {
+			return false
+		}
+	}
+	return true
+}
+
+// AggregateSourceYield soma o rendimento por origem dos relatórios de crawling, da origem com
+// mais anúncios para a com menos
+func AggregateSourceYield(reports []CrawlReport) []DiscoverySourceYield {
+	totals := make(map[string]*DiscoverySourceYield)
+	for _, report := range reports {
+		for source, yield := range report.Sources {
+			total, exists := totals[source]
+			if !exists {
+				total = &DiscoverySourceYield{Source: source}
+				totals[source] = total
+			}
+			total.Visited += yield.Visited
+			total.Properties += yield.Properties
+			total.Crawls++
+		}
+	}
+
+	yields := make([]DiscoverySourceYield, 0, len(totals))
+	for _, total := range totals {
+		if total.Visited > 0 {
+			total.Yield = float64(total.Properties) / float64(total.Visited)
+		}
+		yields = append(yields, *total)
+	}
+	sort.Slice(yields, func(i, j int) bool {
+		if yields[i].Properties != yields[j].Properties {
+			return yields[i].Properties > yields[j].Properties
+		}
+		return yields[i].Source < yields[j].Source
+	})
+	return yields
+}
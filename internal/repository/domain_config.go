@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// DomainConfig representa as configurações de crawling específicas de um domínio
+type DomainConfig struct {
+	ID              string            `bson:"_id,omitempty" json:"id,omitempty"`
+	Domain          string            `bson:"domain" json:"domain"`
+	RateLimit       DomainRateLimit   `bson:"rate_limit" json:"rate_limit"`
+	EnableJS        bool              `bson:"enable_js" json:"enable_js"`               // renderização de JavaScript
+	IncludePatterns []string          `bson:"include_patterns" json:"include_patterns"` // regex de URLs permitidas
+	ExcludePatterns []string          `bson:"exclude_patterns" json:"exclude_patterns"` // regex de URLs ignoradas
+	Selectors       map[string]string `bson:"selectors" json:"selectors"`               // campo -> seletor CSS (ex: "valor": ".price")
+	Notes           string            `bson:"notes,omitempty" json:"notes,omitempty"`
+
+	// Script Lua executado após a extração (corrige particularidades do site, calcula campos derivados)
+	PostProcessScript string `bson:"post_process_script,omitempty" json:"post_process_script,omitempty"`
+
+	// API JSON de busca do portal; quando configurada substitui o crawling HTML do domínio
+	API *DomainAPIConfig `bson:"api,omitempty" json:"api,omitempty"`
+
+	// Feeds RSS/Atom de novos anúncios do domínio, lidos periodicamente (FEED_POLL_INTERVAL)
+	Feeds []string `bson:"feeds,omitempty" json:"feeds,omitempty"`
+
+	// No modo estrito de domínios (CRAWL_STRICT_DOMAINS), permite também os subdomínios do domínio
+	AllowSubdomains bool `bson:"allow_subdomains,omitempty" json:"allow_subdomains,omitempty"`
+
+	// Janelas do dia em que o domínio pode ser crawleado (ex: "00:00-06:00"), no fuso Timezone
+	// (padrão America/Sao_Paulo); vazio permite qualquer horário
+	CrawlWindows []string `bson:"crawl_windows,omitempty" json:"crawl_windows,omitempty"`
+	Timezone     string   `bson:"timezone,omitempty" json:"timezone,omitempty"`
+
+	// Política das fotos no proxy GET /properties/{id}/photos/{n}: "proxy" (padrão), "redirect"
+	// (o site permite hotlink) ou "deny"; PhotoReferer envia a página do anúncio como Referer
+	PhotoPolicy  string `bson:"photo_policy,omitempty" json:"photo_policy,omitempty"`
+	PhotoReferer bool   `bson:"photo_referer,omitempty" json:"photo_referer,omitempty"`
+
+	// Caminhos (primeiro segmento, ex: "/blog") aprendidos pelo crawler por nunca levarem a
+	// anúncios (ver skip_paths.go); gerenciados pelo crawler, não pelo PUT da configuração
+	LearnedSkipPaths []string `bson:"learned_skip_paths,omitempty" json:"learned_skip_paths,omitempty"`
+
+	// Estratégias de descoberta desativadas no domínio por rendimento baixo (feed,
+	// interactive_form, pagination, classified_link; ver discovery_sources.go)
+	DisabledDiscoverySources []string `bson:"disabled_discovery_sources,omitempty" json:"disabled_discovery_sources,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// DomainRateLimit define os limites de requisição para um domínio
+type DomainRateLimit struct {
+	Parallelism       int `bson:"parallelism" json:"parallelism"`
+	DelayMs           int `bson:"delay_ms" json:"delay_ms"`
+	RequestsPerMinute int `bson:"requests_per_minute" json:"requests_per_minute"`
+}
+
+// DomainAPIConfig descreve a API JSON de listagem de um portal. O endpoint aceita os
+// marcadores {page}, {offset} e {page_size}; os caminhos usam pontos (ex: "data.items",
+// "preco.valor", "fotos.0.url").
+type DomainAPIConfig struct {
+	Endpoint     string            `bson:"endpoint" json:"endpoint"`
+	Headers      map[string]string `bson:"headers,omitempty" json:"headers,omitempty"`
+	StartPage    int               `bson:"start_page,omitempty" json:"start_page,omitempty"` // padrão 1
+	PageSize     int               `bson:"page_size,omitempty" json:"page_size,omitempty"`   // padrão 20
+	MaxPages     int               `bson:"max_pages,omitempty" json:"max_pages,omitempty"`   // padrão 50
+	ResultsPath  string            `bson:"results_path,omitempty" json:"results_path,omitempty"`
+	FieldMapping map[string]string `bson:"field_mapping" json:"field_mapping"`                   // campo do imóvel -> caminho no item
+	URLTemplate  string            `bson:"url_template,omitempty" json:"url_template,omitempty"` // ex: https://portal.com/imovel/{id}
+}
+
+// apiMappingFields lista os campos do imóvel que podem ser mapeados a partir da API
+var apiMappingFields = map[string]bool{
+	"url":             true,
+	"endereco":        true,
+	"cidade":          true,
+	"bairro":          true,
+	"cep":             true,
+	"descricao":       true,
+	"valor":           true,
+	"quartos":         true,
+	"banheiros":       true,
+	"area_total":      true,
+	"area_util":       true,
+	"tipo_imovel":     true,
+	"caracteristicas": true,
+	"fotos":           true,
+}
+
+// Validate verifica o endpoint e o mapeamento de campos da API; o endpoint precisa estar no
+// domínio da configuração ou em um subdomínio dele
+func (ac *DomainAPIConfig) Validate(domain string) error {
+	parsed, err := url.Parse(strings.NewReplacer("{page}", "1", "{offset}", "0", "{page_size}", "20").Replace(ac.Endpoint))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("api.endpoint inválido: %q", ac.Endpoint)
+	}
+	host, domain := NormalizeDomain(parsed.Hostname()), NormalizeDomain(domain)
+	if domain == "" || (host != domain && !strings.HasSuffix(host, "."+domain)) {
+		return fmt.Errorf("api.endpoint deve estar no domínio %s ou em um subdomínio: %q", domain, ac.Endpoint)
+	}
+	if ac.StartPage < 0 || ac.PageSize < 0 || ac.PageSize > 500 || ac.MaxPages < 0 || ac.MaxPages > 1000 {
+		return fmt.Errorf("paginação da api inválida (page_size até 500, max_pages até 1000)")
+	}
+
+	for field, path := range ac.FieldMapping {
+		if !apiMappingFields[field] {
+			return fmt.Errorf("campo de mapeamento desconhecido: %s", field)
+		}
+		if strings.TrimSpace(path) == "" {
+			return fmt.Errorf("caminho vazio para o campo %s", field)
+		}
+	}
+	if ac.FieldMapping["url"] == "" && ac.URLTemplate == "" {
+		return fmt.Errorf("api requer field_mapping.url ou url_template")
+	}
+	return nil
+}
+
+// Políticas de exibição das fotos do domínio pelo proxy de fotos
+const (
+	PhotoPolicyProxy    = "proxy"
+	PhotoPolicyRedirect = "redirect"
+	PhotoPolicyDeny     = "deny"
+)
+
+// maxPostProcessScriptSize limita o tamanho do script Lua de pós-processamento
+const maxPostProcessScriptSize = 20000
+
+// maxDomainFeeds limita os feeds RSS/Atom acompanhados por domínio
+const maxDomainFeeds = 20
+
+// selectorFields lista os campos da propriedade que aceitam seletores customizados
+var selectorFields = map[string]bool{
+	"endereco":        true,
+	"cidade":          true,
+	"bairro":          true,
+	"descricao":       true,
+	"valor":           true,
+	"quartos":         true,
+	"banheiros":       true,
+	"area_total":      true,
+	"tipo_imovel":     true,
+	"caracteristicas": true,
+}
+
+// NormalizeDomain extrai o host de uma URL ou domínio, sem "www." e em minúsculas
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" {
+		return ""
+	}
+
+	if !strings.Contains(domain, "://") {
+		domain = "http://" + domain
+	}
+
+	parsed, err := url.Parse(domain)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(parsed.Hostname(), "www.")
+}
+
+// Validate verifica se a configuração é consistente
+func (dc *DomainConfig) Validate() error {
+	if dc.Domain == "" || !strings.Contains(dc.Domain, ".") {
+		return fmt.Errorf("domínio inválido: %q", dc.Domain)
+	}
+
+	if dc.RateLimit.Parallelism < 0 || dc.RateLimit.Parallelism > 20 {
+		return fmt.Errorf("parallelism deve estar entre 0 e 20")
+	}
+	if dc.RateLimit.DelayMs < 0 || dc.RateLimit.DelayMs > 60000 {
+		return fmt.Errorf("delay_ms deve estar entre 0 e 60000")
+	}
+	if dc.RateLimit.RequestsPerMinute < 0 {
+		return fmt.Errorf("requests_per_minute não pode ser negativo")
+	}
+
+	for _, pattern := range append(append([]string{}, dc.IncludePatterns...), dc.ExcludePatterns...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("padrão inválido %q: %v", pattern, err)
+		}
+	}
+
+	for field, selector := range dc.Selectors {
+		if !selectorFields[field] {
+			return fmt.Errorf("campo de seletor desconhecido: %s", field)
+		}
+		if strings.TrimSpace(selector) == "" {
+			return fmt.Errorf("seletor vazio para o campo %s", field)
+		}
+	}
+
+	if len(dc.PostProcessScript) > maxPostProcessScriptSize {
+		return fmt.Errorf("post_process_script excede %d bytes", maxPostProcessScriptSize)
+	}
+	if strings.TrimSpace(dc.PostProcessScript) != "" {
+		if _, err := parse.Parse(strings.NewReader(dc.PostProcessScript), "post_process_script"); err != nil {
+			return fmt.Errorf("post_process_script inválido: %v", err)
+		}
+	}
+
+	if dc.API != nil {
+		if err := dc.API.Validate(dc.Domain); err != nil {
+			return err
+		}
+	}
+
+	if len(dc.Feeds) > maxDomainFeeds {
+		return fmt.Errorf("no máximo %d feeds por domínio", maxDomainFeeds)
+	}
+	for _, feed := range dc.Feeds {
+		if parsed, err := url.Parse(feed); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("feed inválido: %q", feed)
+		}
+	}
+
+	if err := dc.validateCrawlWindows(); err != nil {
+		return err
+	}
+
+	if err := dc.validateDiscoverySources(); err != nil {
+		return err
+	}
+
+	switch dc.PhotoPolicy {
+	case "", PhotoPolicyProxy, PhotoPolicyRedirect, PhotoPolicyDeny:
+	default:
+		return fmt.Errorf("photo_policy inválida: %q (use proxy, redirect ou deny)", dc.PhotoPolicy)
+	}
+
+	return nil
+}
+
+// AllowsURL verifica se a URL passa pelas regras de inclusão/exclusão do domínio. Compila os
+// padrões a cada chamada; quem consulta muitas URLs deve guardar URLRules
+func (dc *DomainConfig) AllowsURL(rawURL string) bool {
+	return dc.URLRules().Allows(rawURL)
+}
+
+// DomainURLRules são as regras de URL de um domínio com os padrões já compilados
+type DomainURLRules struct {
+	skipPaths map[string]bool
+	include   []*regexp.Regexp
+	exclude   []*regexp.Regexp
+}
+
+// URLRules compila os caminhos aprendidos e os padrões de inclusão/exclusão (padrões inválidos
+// são ignorados, como na validação do PUT)
+func (dc *DomainConfig) URLRules() *DomainURLRules {
+	rules := &DomainURLRules{skipPaths: make(map[string]bool, len(dc.LearnedSkipPaths))}
+	for _, path := range dc.LearnedSkipPaths {
+		rules.skipPaths[path] = true
+	}
+	compile := func(patterns []string) []*regexp.Regexp {
+		var compiled []*regexp.Regexp
+		for _, pattern := range patterns {
+			if re, err := regexp.Compile(pattern); err == nil {
+				compiled = append(compiled, re)
+			}
+		}
+		return compiled
+	}
+	rules.exclude = compile(dc.ExcludePatterns)
+	rules.include = compile(dc.IncludePatterns)
+	if len(dc.IncludePatterns) > 0 && len(rules.include) == 0 {
+		rules.include = []*regexp.Regexp{} // só padrões inválidos: nenhuma URL é incluída
+	}
+	return rules
+}
+
+// Allows verifica se a URL passa pelas regras
+func (r *DomainURLRules) Allows(rawURL string) bool {
+	if len(r.skipPaths) > 0 {
+		if prefix := URLPathPrefix(rawURL); prefix != "" && r.skipPaths[prefix] {
+			return false
+		}
+	}
+
+	for _, re := range r.exclude {
+		if re.MatchString(rawURL) {
+			return false
+		}
+	}
+
+	if r.include == nil {
+		return true
+	}
+	for _, re := range r.include {
+		if re.MatchString(rawURL) {
+			return true
+		}
+	}
+	return false
+}
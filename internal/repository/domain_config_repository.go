@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDomainConfigNotFound indica que o domínio não tem configuração cadastrada
+var ErrDomainConfigNotFound = errors.New("domain config not found")
+
+// DomainConfigRepository define as operações para gerenciar configurações por domínio
+type DomainConfigRepository interface {
+	SaveDomainConfig(ctx context.Context, config DomainConfig) (*DomainConfig, error)
+	FindDomainConfig(ctx context.Context, domain string) (*DomainConfig, error)
+	FindAllDomainConfigs(ctx context.Context) ([]DomainConfig, error)
+	DeleteDomainConfig(ctx context.Context, domain string) error
+	Close()
+}
+
+// MongoDomainConfigRepository implementa DomainConfigRepository usando MongoDB
+type MongoDomainConfigRepository struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMongoDomainConfigRepository cria um novo repositório de configurações por domínio
+func NewMongoDomainConfigRepository(uri, dbName string) (*MongoDomainConfigRepository, error) {
+	client, err := connectMongo(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := client.Database(dbName).Collection("domain_configs")
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "domain", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(context.Background(), indexModel); err != nil {
+		log.Printf("Warning: Failed to create domain config indexes: %v", err)
+	}
+
+	log.Printf("Domain config repository initialized successfully")
+	return &MongoDomainConfigRepository{client: client, collection: collection}, nil
+}
+
+// SaveDomainConfig cria ou substitui a configuração de um domínio
+func (r *MongoDomainConfigRepository) SaveDomainConfig(ctx context.Context, config DomainConfig) (*DomainConfig, error) {
+	config.Domain = NormalizeDomain(config.Domain)
+	config.UpdatedAt = time.Now()
+	config.ID = ""
+
+	existing, err := r.FindDomainConfig(ctx, config.Domain)
+	if err != nil {
+		return nil, err
+	}
+	config.CreatedAt = config.UpdatedAt
+	if existing != nil {
+		config.CreatedAt = existing.CreatedAt
+		// A lista aprendida pelo crawler sobrevive às edições da configuração
+		config.LearnedSkipPaths = existing.LearnedSkipPaths
+	}
+
+	// Substitui o documento inteiro: com $set os campos omitidos (omitempty) da nova
+	// configuração ficariam com o valor anterior e não poderiam ser limpos
+	filter := bson.M{"domain": config.Domain}
+	opts := options.Replace().SetUpsert(true)
+
+	if _, err := r.collection.ReplaceOne(ctx, filter, config, opts); err != nil {
+		return nil, fmt.Errorf("failed to save domain config: %v", err)
+	}
+
+	log.Printf("Domain config saved: %s", config.Domain)
+	return r.FindDomainConfig(ctx, config.Domain)
+}
+
+// FindDomainConfig busca a configuração de um domínio (nil se não existir)
+func (r *MongoDomainConfigRepository) FindDomainConfig(ctx context.Context, domain string) (*DomainConfig, error) {
+	var config DomainConfig
+	err := r.collection.FindOne(ctx, bson.M{"domain": NormalizeDomain(domain)}).Decode(&config)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find domain config: %v", err)
+	}
+
+	return &config, nil
+}
+
+// FindAllDomainConfigs retorna todas as configurações cadastradas
+func (r *MongoDomainConfigRepository) FindAllDomainConfigs(ctx context.Context) ([]DomainConfig, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "domain", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find domain configs: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var configs []DomainConfig
+	if err := cursor.All(ctx, &configs); err != nil {
+		return nil, fmt.Errorf("failed to decode domain configs: %v", err)
+	}
+
+	return configs, nil
+}
+
+// DeleteDomainConfig remove a configuração de um domínio
+func (r *MongoDomainConfigRepository) DeleteDomainConfig(ctx context.Context, domain string) error {
+	domain = NormalizeDomain(domain)
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"domain": domain})
+	if err != nil {
+		return fmt.Errorf("failed to delete domain config: %v", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: %s", ErrDomainConfigNotFound, domain)
+	}
+
+	log.Printf("Domain config deleted: %s", domain)
+	return nil
+}
+
+// Close fecha a conexão com o MongoDB
+func (r *MongoDomainConfigRepository) Close() {
+	if err := r.client.Disconnect(context.Background()); err != nil {
+		log.Printf("Error disconnecting from MongoDB: %v", err)
+	}
+}
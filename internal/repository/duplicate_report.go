@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Limites padrão do relatório de duplicatas
+const (
+	DefaultDuplicateAreaTolerance = 0.05 // diferença relativa máxima de área (5%)
+	DefaultDuplicateMaxDistance   = 12   // distância de Hamming máxima entre os simhashes das descrições
+	DefaultDuplicateReportLimit   = 100
+)
+
+// DuplicateReportOptions controla a comparação dos anúncios
+type DuplicateReportOptions struct {
+	AreaTolerance float64
+	MaxDistance   int
+	Limit         int
+
+	// Leilões e aluguéis por temporada ficam fora do relatório, a menos que solicitados
+	IncludeAuctions        bool
+	IncludeVacationRentals bool
+}
+
+// DuplicateListing é um anúncio de uma imobiliária dentro de um grupo de duplicatas
+type DuplicateListing struct {
+	URL      string  `json:"url"`
+	Domain   string  `json:"domain"`
+	Endereco string  `json:"endereco,omitempty"`
+	Valor    float64 `json:"valor"`
+	Area     float64 `json:"area"`
+}
+
+// DuplicateCluster agrupa anúncios quase idênticos (mesmo imóvel) publicados por imobiliárias
+// diferentes, com a diferença entre o menor e o maior preço
+type DuplicateCluster struct {
+	Cidade         string             `json:"cidade"`
+	Bairro         string             `json:"bairro"`
+	TipoImovel     string             `json:"tipo_imovel"`
+	Quartos        int                `json:"quartos"`
+	Agencies       int                `json:"agencies"`
+	MinValor       float64            `json:"min_valor"`
+	MaxValor       float64            `json:"max_valor"`
+	PriceSpread    float64            `json:"price_spread"`
+	PriceSpreadPct float64            `json:"price_spread_pct"`
+	Listings       []DuplicateListing `json:"listings"`
+}
+
+// DuplicateReportRepository é implementado por repositórios capazes de gerar o relatório
+type DuplicateReportRepository interface {
+	FindDuplicateClusters(ctx context.Context, cidade string, opts DuplicateReportOptions) ([]DuplicateCluster, error)
+}
+
+// FindDuplicateClusters carrega os anúncios da cidade e agrupa os que descrevem o mesmo imóvel
+func (r *MongoRepository) FindDuplicateClusters(ctx context.Context, cidade string, opts DuplicateReportOptions) ([]DuplicateCluster, error) {
+	filter := bson.M{
+		"cidade": bson.M{"$regex": "^" + regexp.QuoteMeta(cidade) + "$", "$options": "i"},
+		"valor":  bson.M{"$gt": 0},
+	}
+	if !opts.IncludeAuctions {
+		filter["leilao"] = bson.M{"$ne": true}
+	}
+	if !opts.IncludeVacationRentals {
+		filter["finalidade"] = bson.M{"$ne": FinalidadeTemporada}
+	}
+	projection := bson.M{
+		"url": 1, "endereco": 1, "cidade": 1, "bairro": 1, "descricao": 1, "valor": 1,
+		"quartos": 1, "area_total": 1, "area_util": 1, "tipo_imovel": 1,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find properties for duplicate report: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var properties []Property
+	if err := cursor.All(ctx, &properties); err != nil {
+		return nil, fmt.Errorf("failed to decode properties for duplicate report: %v", err)
+	}
+	return BuildDuplicateClusters(properties, opts), nil
+}
+
+// duplicateCandidate é um anúncio comparável no agrupamento de duplicatas
+type duplicateCandidate struct {
+	property Property
+	domain   string
+	area     float64
+	simhash  uint64
+}
+
+// BuildDuplicateClusters agrupa os anúncios do mesmo imóvel (ver groupDuplicates) em grupos com
+// ao menos duas imobiliárias (domínios), ordenados pela diferença de preço.
+func BuildDuplicateClusters(properties []Property, opts DuplicateReportOptions) []DuplicateCluster {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultDuplicateReportLimit
+	}
+
+	var clusters []DuplicateCluster
+	for _, group := range groupDuplicates(properties, opts) {
+		domains := make(map[string]bool)
+		for _, c := range group {
+			domains[c.domain] = true
+		}
+
+		first := group[0].property
+		cluster := DuplicateCluster{
+			Cidade:     first.Cidade,
+			Bairro:     first.Bairro,
+			TipoImovel: NormalizePropertyType(first.TipoImovel, ""),
+			Quartos:    first.Quartos,
+			Agencies:   len(domains),
+			MinValor:   math.Inf(1),
+		}
+		for _, c := range group {
+			cluster.Listings = append(cluster.Listings, DuplicateListing{
+				URL:      c.property.URL,
+				Domain:   c.domain,
+				Endereco: c.property.Endereco,
+				Valor:    c.property.Valor,
+				Area:     c.area,
+			})
+			cluster.MinValor = math.Min(cluster.MinValor, c.property.Valor)
+			cluster.MaxValor = math.Max(cluster.MaxValor, c.property.Valor)
+		}
+		sort.Slice(cluster.Listings, func(i, j int) bool {
+			return cluster.Listings[i].Valor < cluster.Listings[j].Valor
+		})
+		cluster.PriceSpread = cluster.MaxValor - cluster.MinValor
+		cluster.PriceSpreadPct = math.Round(cluster.PriceSpread/cluster.MinValor*10000) / 100
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].PriceSpreadPct != clusters[j].PriceSpreadPct {
+			return clusters[i].PriceSpreadPct > clusters[j].PriceSpreadPct
+		}
+		return clusters[i].Listings[0].URL < clusters[j].Listings[0].URL
+	})
+	if len(clusters) > opts.Limit {
+		clusters = clusters[:opts.Limit]
+	}
+	if clusters == nil {
+		clusters = []DuplicateCluster{}
+	}
+	return clusters
+}
+
+// groupDuplicates compara os anúncios de mesmo bairro, tipo e quartos: são o mesmo imóvel quando
+// as áreas estão dentro da tolerância e as descrições são próximas (simhash). Retorna apenas os
+// grupos com ao menos dois domínios.
+func groupDuplicates(properties []Property, opts DuplicateReportOptions) [][]duplicateCandidate {
+	if opts.AreaTolerance <= 0 {
+		opts.AreaTolerance = DefaultDuplicateAreaTolerance
+	}
+	if opts.MaxDistance <= 0 {
+		opts.MaxDistance = DefaultDuplicateMaxDistance
+	}
+
+	// Agrupamento inicial por bairro, tipo e quartos para evitar comparar a cidade inteira
+	blocks := make(map[string][]duplicateCandidate)
+	for _, property := range properties {
+		area := property.AreaUtil
+		if area <= 0 {
+			area = property.AreaTotal
+		}
+		domain := NormalizeDomain(property.URL)
+		if property.Valor <= 0 || area <= 0 || domain == "" {
+			continue
+		}
+		features := make(map[string]int)
+		addShingles(features, "desc:", NormalizeFingerprintText(property.Descricao), 1)
+
+		key := fmt.Sprintf("%s|%s|%d", utils.NormalizeText(property.Bairro),
+			NormalizePropertyType(property.TipoImovel, ""), property.Quartos)
+		blocks[key] = append(blocks[key], duplicateCandidate{
+			property: property,
+			domain:   domain,
+			area:     area,
+			simhash:  ComputeSimHash(features),
+		})
+	}
+
+	var result [][]duplicateCandidate
+	for _, block := range blocks {
+		// Union-find sobre os pares semelhantes de domínios diferentes
+		parent := make([]int, len(block))
+		for i := range parent {
+			parent[i] = i
+		}
+		var find func(int) int
+		find = func(i int) int {
+			if parent[i] != i {
+				parent[i] = find(parent[i])
+			}
+			return parent[i]
+		}
+
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				a, b := block[i], block[j]
+				if a.domain == b.domain {
+					continue
+				}
+				if math.Abs(a.area-b.area)/math.Max(a.area, b.area) > opts.AreaTolerance {
+					continue
+				}
+				if HammingDistance(a.simhash, b.simhash) > opts.MaxDistance {
+					continue
+				}
+				parent[find(i)] = find(j)
+			}
+		}
+
+		groups := make(map[int][]duplicateCandidate)
+		for i, c := range block {
+			root := find(i)
+			groups[root] = append(groups[root], c)
+		}
+
+		for _, group := range groups {
+			domains := make(map[string]bool)
+			for _, c := range group {
+				domains[c.domain] = true
+			}
+			if len(domains) >= 2 {
+				result = append(result, group)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrStorageUnavailable indica que o banco de dados não pôde ser acessado (conexão, ping ou
+// seleção de servidor). Quem chama pode degradar o serviço em vez de encerrar o processo.
+var ErrStorageUnavailable = errors.New("storage unavailable")
+
+// storageUnavailable envolve a causa em ErrStorageUnavailable preservando ambas para errors.Is
+func storageUnavailable(operation string, err error) error {
+	return fmt.Errorf("%w: %s: %w", ErrStorageUnavailable, operation, err)
+}
+
+// IsStorageUnavailable reconhece ErrStorageUnavailable e as falhas de rede/timeout do driver
+func IsStorageUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrStorageUnavailable) ||
+		errors.Is(err, mongo.ErrClientDisconnected) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		mongo.IsNetworkError(err) ||
+		mongo.IsTimeout(err)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CurrencyBRL é a moeda dos anúncios; as cotações informam quanto vale 1 real em cada moeda
+const CurrencyBRL = "BRL"
+
+// exchangeRateRetryInterval evita consultar o provedor a cada imóvel salvo enquanto ele falha
+const exchangeRateRetryInterval = 15 * time.Minute
+
+// ExchangeRates são as cotações do real em uma data (ex: {"USD": 0.18, "EUR": 0.17})
+type ExchangeRates struct {
+	Date  time.Time          `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// ExchangeRateProvider consulta as cotações atuais do real nas moedas informadas
+type ExchangeRateProvider interface {
+	LatestRates(ctx context.Context, currencies []string) (*ExchangeRates, error)
+}
+
+// HTTPExchangeRateProvider consulta uma API no formato do Frankfurter (cotações de referência do
+// BCE): GET {BaseURL}?from=BRL&to=USD,EUR -> {"date": "2024-01-02", "rates": {"USD": 0.2}}
+type HTTPExchangeRateProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+// NewHTTPExchangeRateProvider cria o provedor de cotações
+func NewHTTPExchangeRateProvider(baseURL string) *HTTPExchangeRateProvider {
+	return &HTTPExchangeRateProvider{
+		BaseURL: baseURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// LatestRates busca as cotações mais recentes publicadas
+func (p *HTTPExchangeRateProvider) LatestRates(ctx context.Context, currencies []string) (*ExchangeRates, error) {
+	params := url.Values{}
+	params.Set("from", CurrencyBRL)
+	params.Set("to", strings.Join(currencies, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange rate request: %v", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchange rate request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate provider returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid exchange rate response: %v", err)
+	}
+	if len(body.Rates) == 0 {
+		return nil, fmt.Errorf("exchange rate response has no rates")
+	}
+
+	date, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		date = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	return &ExchangeRates{Date: date, Rates: body.Rates}, nil
+}
+
+// DailyExchangeRates guarda as cotações do dia: o provedor é consultado no máximo uma vez por
+// dia (UTC). Se a consulta falhar, as últimas cotações obtidas continuam em uso e uma nova
+// tentativa só é feita após exchangeRateRetryInterval.
+type DailyExchangeRates struct {
+	provider   ExchangeRateProvider
+	currencies []string
+
+	mutex       sync.Mutex
+	day         string
+	rates       *ExchangeRates
+	nextAttempt time.Time
+	now         func() time.Time
+}
+
+// NewDailyExchangeRates cria o cache diário das cotações das moedas informadas (ex: USD, EUR)
+func NewDailyExchangeRates(provider ExchangeRateProvider, currencies []string) *DailyExchangeRates {
+	var normalized []string
+	for _, currency := range currencies {
+		if currency = strings.ToUpper(strings.TrimSpace(currency)); currency != "" && currency != CurrencyBRL {
+			normalized = append(normalized, currency)
+		}
+	}
+	return &DailyExchangeRates{provider: provider, currencies: normalized, now: time.Now}
+}
+
+// Currencies retorna as moedas para as quais os preços são convertidos
+func (d *DailyExchangeRates) Currencies() []string {
+	return append([]string(nil), d.currencies...)
+}
+
+// Supports indica se a moeda está entre as convertidas
+func (d *DailyExchangeRates) Supports(currency string) bool {
+	for _, supported := range d.currencies {
+		if strings.EqualFold(supported, currency) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rates retorna as cotações do dia, consultando o provedor na primeira chamada de cada dia
+func (d *DailyExchangeRates) Rates(ctx context.Context) (*ExchangeRates, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.now()
+	today := now.UTC().Format("2006-01-02")
+	if d.rates != nil && (d.day == today || now.Before(d.nextAttempt)) {
+		return d.rates, nil
+	}
+	if now.Before(d.nextAttempt) {
+		return nil, fmt.Errorf("exchange rates unavailable until %s", d.nextAttempt.Format(time.RFC3339))
+	}
+
+	rates, err := d.provider.LatestRates(ctx, d.currencies)
+	if err != nil {
+		d.nextAttempt = now.Add(exchangeRateRetryInterval)
+		if d.rates != nil {
+			return d.rates, nil
+		}
+		return nil, err
+	}
+	d.day, d.rates = today, rates
+	return rates, nil
+}
+
+// ApplyExchangeRates grava o preço convertido em cada moeda e a data das cotações usadas
+func ApplyExchangeRates(property *Property, rates *ExchangeRates) {
+	if property.Valor <= 0 || rates == nil || len(rates.Rates) == 0 {
+		return
+	}
+	property.ValorConvertido = make(map[string]float64, len(rates.Rates))
+	for currency, rate := range rates.Rates {
+		property.ValorConvertido[currency] = math.Round(property.Valor*rate*100) / 100
+	}
+	date := rates.Date
+	property.CambioData = &date
+}
@@ -0,0 +1,85 @@
+package repository
+
+import "math"
+
+// extractorConfidence é a confiança base de um campo conforme a etapa que o produziu
+var extractorConfidence = map[string]float64{
+	ExtractorDomainSelector:  0.95,
+	ExtractorAI:              0.85,
+	ExtractorGenericSelector: 0.8,
+	ExtractorImport:          0.75,
+	ExtractorMetaTag:         0.7,
+	ExtractorRegexFallback:   0.5,
+}
+
+// aiReviewBonus é somado aos campos que a IA analisou e manteve sem correção
+const aiReviewBonus = 0.1
+
+// ScoreFieldConfidence preenche field_confidence (0 a 1) para os campos com proveniência
+// que ainda não têm nota, penalizando valores fora de faixas plausíveis
+func ScoreFieldConfidence(p *Property) {
+	values := provenanceFieldValues(*p)
+	for field, origin := range p.Provenance {
+		if _, scored := p.FieldConfidence[field]; scored || values[field] == "" {
+			continue
+		}
+
+		score, known := extractorConfidence[origin.Extractor]
+		if !known {
+			score = 0.5
+		}
+		if !plausibleFieldValue(p, field) {
+			score *= 0.5
+		}
+		setFieldConfidence(p, field, score)
+	}
+}
+
+// RecordAIReview registra o resultado da validação pela IA: campos corrigidos passam a ter
+// a confiança da IA e campos preenchidos mantidos sem correção recebem um bônus
+func RecordAIReview(before Property, after *Property) []string {
+	ScoreFieldConfidence(&before)
+	changed := RecordChangedFields(before, after, ExtractorAI)
+
+	confidence := make(map[string]float64, len(before.FieldConfidence))
+	for field, score := range before.FieldConfidence {
+		confidence[field] = score
+	}
+	after.FieldConfidence = confidence
+
+	corrected := make(map[string]bool, len(changed))
+	for _, field := range changed {
+		corrected[field] = true
+		delete(after.FieldConfidence, field)
+	}
+	for field, score := range confidence {
+		if !corrected[field] {
+			setFieldConfidence(after, field, score+aiReviewBonus)
+		}
+	}
+
+	ScoreFieldConfidence(after)
+	return changed
+}
+
+// plausibleFieldValue verifica faixas razoáveis para os campos numéricos
+func plausibleFieldValue(p *Property, field string) bool {
+	switch field {
+	case "valor":
+		return p.Valor >= 10000 && p.Valor <= 50000000
+	case "area_total":
+		return p.AreaTotal >= 10 && p.AreaTotal <= 1000000
+	case "quartos":
+		return p.Quartos <= 20
+	case "banheiros":
+		return p.Banheiros <= 20
+	}
+	return true
+}
+
+func setFieldConfidence(p *Property, field string, score float64) {
+	if p.FieldConfidence == nil {
+		p.FieldConfidence = make(map[string]float64)
+	}
+	p.FieldConfidence[field] = math.Round(math.Min(score, 1)*100) / 100
+}
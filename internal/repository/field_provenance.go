@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+// Etapas de extração registradas na proveniência de cada campo
+const (
+	ExtractorDomainSelector  = "domain_selector"  // seletor CSS configurado para o domínio
+	ExtractorGenericSelector = "generic_selector" // seletores genéricos do DataExtractor
+	ExtractorMetaTag         = "meta_tag"         // meta tags (description, og:*)
+	ExtractorRegexFallback   = "regex_fallback"   // padrões aplicados ao texto da página
+	ExtractorAI              = "ai"               // valor preenchido ou corrigido pela IA
+	ExtractorImport          = "import"           // registro importado de fonte externa
+	ExtractorPlugin          = "plugin"           // etapa customizada do pipeline de extração
+	ExtractorScript          = "script"           // script de pós-processamento do domínio
+	ExtractorAPI             = "api"              // campo mapeado da API JSON do portal
+	ExtractorArchive         = "archive"          // completado com a cópia arquivada (Wayback Machine)
+)
+
+// FieldProvenance registra qual etapa de extração produziu um campo e quando
+type FieldProvenance struct {
+	Extractor   string    `bson:"extractor" json:"extractor"`
+	Selector    string    `bson:"selector,omitempty" json:"selector,omitempty"`
+	ExtractedAt time.Time `bson:"extracted_at" json:"extracted_at"`
+}
+
+// SetProvenance registra a origem de um campo (campos vazios são ignorados pelo chamador)
+func (p *Property) SetProvenance(field, extractor, selector string) {
+	if p.Provenance == nil {
+		p.Provenance = make(map[string]FieldProvenance)
+	}
+	p.Provenance[field] = FieldProvenance{
+		Extractor:   extractor,
+		Selector:    selector,
+		ExtractedAt: time.Now(),
+	}
+}
+
+// RecordChangedFields atribui ao extrator os campos cujo valor mudou entre before e after
+// (usado após o processamento pela IA)
+func RecordChangedFields(before Property, after *Property, extractor string) []string {
+	beforeValues := provenanceFieldValues(before)
+
+	// O resultado da IA pode compartilhar o mapa com o original (ou com o cache)
+	provenance := make(map[string]FieldProvenance, len(after.Provenance))
+	for field, origin := range after.Provenance {
+		provenance[field] = origin
+	}
+	after.Provenance = provenance
+
+	var changed []string
+	for field, value := range provenanceFieldValues(*after) {
+		if value != "" && value != beforeValues[field] {
+			after.SetProvenance(field, extractor, "")
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+// RecordFilledFields atribui ao extrator todos os campos preenchidos que ainda não têm origem
+func RecordFilledFields(p *Property, extractor string) {
+	for field, value := range provenanceFieldValues(*p) {
+		if _, exists := p.Provenance[field]; value != "" && !exists {
+			p.SetProvenance(field, extractor, "")
+		}
+	}
+}
+
+// provenanceFieldValues retorna os campos rastreados como texto (vazio = não preenchido)
+func provenanceFieldValues(p Property) map[string]string {
+	number := func(value float64) string {
+		if value == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%g", value)
+	}
+	integer := func(value int) string {
+		if value == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d", value)
+	}
+	features := ""
+	if len(p.Caracteristicas) > 0 {
+		features = fmt.Sprintf("%q", p.Caracteristicas)
+	}
+
+	return map[string]string{
+		"endereco":        p.Endereco,
+		"cidade":          p.Cidade,
+		"bairro":          p.Bairro,
+		"cep":             p.CEP,
+		"descricao":       p.Descricao,
+		"valor":           number(p.Valor),
+		"quartos":         integer(p.Quartos),
+		"banheiros":       integer(p.Banheiros),
+		"area_total":      number(p.AreaTotal),
+		"area_util":       number(p.AreaUtil),
+		"tipo_imovel":     p.TipoImovel,
+		"caracteristicas": features,
+	}
+}
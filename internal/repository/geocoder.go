@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GeoPoint é uma localização em GeoJSON (coordinates = [longitude, latitude]), compatível
+// com índices 2dsphere do MongoDB
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint cria o ponto a partir de latitude e longitude
+func NewGeoPoint(latitude, longitude float64) *GeoPoint {
+	return &GeoPoint{Type: "Point", Coordinates: []float64{longitude, latitude}}
+}
+
+// Latitude retorna a latitude do ponto
+func (gp *GeoPoint) Latitude() float64 {
+	if gp == nil || len(gp.Coordinates) < 2 {
+		return 0
+	}
+	return gp.Coordinates[1]
+}
+
+// Longitude retorna a longitude do ponto
+func (gp *GeoPoint) Longitude() float64 {
+	if gp == nil || len(gp.Coordinates) < 2 {
+		return 0
+	}
+	return gp.Coordinates[0]
+}
+
+// Geocoder converte o endereço de um imóvel em coordenadas (nil se não encontrado)
+type Geocoder interface {
+	Geocode(ctx context.Context, endereco, bairro, cidade string) (*GeoPoint, error)
+}
+
+// NominatimGeocoder consulta a API de busca do Nominatim (OpenStreetMap). Respeita o limite de
+// uma requisição por intervalo e guarda os resultados por consulta para não repetir endereços.
+type NominatimGeocoder struct {
+	BaseURL   string
+	UserAgent string
+	Interval  time.Duration
+
+	client  *http.Client
+	mutex   sync.Mutex
+	last    time.Time
+	results map[string]*GeoPoint
+}
+
+// NewNominatimGeocoder cria o geocodificador; interval <= 0 usa 1s (política do Nominatim público)
+func NewNominatimGeocoder(baseURL, userAgent string, interval time.Duration) *NominatimGeocoder {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &NominatimGeocoder{
+		BaseURL:   baseURL,
+		UserAgent: userAgent,
+		Interval:  interval,
+		client:    &http.Client{Timeout: 15 * time.Second},
+		results:   make(map[string]*GeoPoint),
+	}
+}
+
+// Geocode busca o endereço completo e, se não houver resultado, apenas bairro e cidade
+func (ng *NominatimGeocoder) Geocode(ctx context.Context, endereco, bairro, cidade string) (*GeoPoint, error) {
+	if strings.TrimSpace(cidade) == "" {
+		return nil, nil
+	}
+
+	for _, query := range geocodeQueries(endereco, bairro, cidade) {
+		point, err := ng.search(ctx, query)
+		if err != nil || point != nil {
+			return point, err
+		}
+	}
+	return nil, nil
+}
+
+// GeocodeCity localiza o centro da cidade (usado nas distâncias até pontos de interesse)
+func (ng *NominatimGeocoder) GeocodeCity(ctx context.Context, cidade string) (*GeoPoint, error) {
+	if strings.TrimSpace(cidade) == "" {
+		return nil, nil
+	}
+	return ng.search(ctx, strings.TrimSpace(cidade)+", Brasil")
+}
+
+// geocodeQueries monta as consultas do mais preciso ao mais genérico
+func geocodeQueries(endereco, bairro, cidade string) []string {
+	join := func(parts ...string) string {
+		var filled []string
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				filled = append(filled, part)
+			}
+		}
+		return strings.Join(filled, ", ")
+	}
+
+	var queries []string
+	if strings.TrimSpace(endereco) != "" {
+		queries = append(queries, join(endereco, bairro, cidade, "Brasil"))
+	}
+	if strings.TrimSpace(bairro) != "" {
+		queries = append(queries, join(bairro, cidade, "Brasil"))
+	}
+	return queries
+}
+
+// search executa uma consulta ao Nominatim (com cache)
+func (ng *NominatimGeocoder) search(ctx context.Context, query string) (*GeoPoint, error) {
+	ng.mutex.Lock()
+	defer ng.mutex.Unlock()
+
+	if point, cached := ng.results[query]; cached {
+		return point, nil
+	}
+
+	if wait := ng.Interval - time.Since(ng.last); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	ng.last = time.Now()
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	params.Set("countrycodes", "br")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ng.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create geocoding request: %v", err)
+	}
+	req.Header.Set("User-Agent", ng.UserAgent)
+
+	resp, err := ng.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("invalid geocoding response: %v", err)
+	}
+
+	var point *GeoPoint
+	if len(results) > 0 {
+		lat, latErr := strconv.ParseFloat(results[0].Lat, 64)
+		lon, lonErr := strconv.ParseFloat(results[0].Lon, 64)
+		if latErr == nil && lonErr == nil {
+			point = NewGeoPoint(lat, lon)
+		}
+	}
+	ng.results[query] = point
+	return point, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdempotencyKeyCollection guarda as chaves de idempotência (Idempotency-Key) dos disparos de crawling
+const IdempotencyKeyCollection = "idempotency_keys"
+
+// IdempotencyReservationTimeout é o tempo que uma chave reservada pode ficar sem job antes de ser
+// considerada abandonada (réplica que caiu entre a reserva e a criação do job)
+const IdempotencyReservationTimeout = time.Minute
+
+// IdempotencyKeyRecord liga uma chave ao fingerprint dos parâmetros e ao job criado por ela. A
+// chave é o _id (única entre as réplicas) e expira pelo índice TTL de expires_at
+type IdempotencyKeyRecord struct {
+	Key         string    `bson:"_id"`
+	Fingerprint string    `bson:"fingerprint"`
+	JobID       string    `bson:"job_id,omitempty"` // vazio enquanto o job é criado
+	Job         *CrawlJob `bson:"job,omitempty"`    // job como foi criado, para repetir a resposta em outras réplicas
+	CreatedAt   time.Time `bson:"created_at"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+// IdempotencyKeyRepository guarda as chaves de idempotência dos disparos de crawling
+type IdempotencyKeyRepository interface {
+	// ReserveIdempotencyKey grava a chave se ela estiver livre (ausente, vencida ou abandonada) e
+	// retorna nil; se outra requisição já a usou, retorna o registro existente
+	ReserveIdempotencyKey(ctx context.Context, record IdempotencyKeyRecord) (*IdempotencyKeyRecord, error)
+	// CompleteIdempotencyKey registra o job criado pela chave reservada
+	CompleteIdempotencyKey(ctx context.Context, key string, job CrawlJob) error
+	// ReleaseIdempotencyKey libera uma chave reservada cujo job não foi criado
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+}
+
+// idempotencyKeys retorna a coleção das chaves de idempotência
+func (r *MongoRepository) idempotencyKeys() *mongo.Collection {
+	return r.collection.Database().Collection(IdempotencyKeyCollection)
+}
+
+// ConfigureIdempotencyKeys cria o índice TTL que remove as chaves ao fim da janela de idempotência
+func (r *MongoRepository) ConfigureIdempotencyKeys(ctx context.Context) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := r.idempotencyKeys().Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create idempotency key indexes: %v", err)
+	}
+	return nil
+}
+
+// ReserveIdempotencyKey grava a chave com upsert sobre um registro livre; se a chave estiver em
+// uso, o upsert tenta inserir o mesmo _id e falha com chave duplicada
+func (r *MongoRepository) ReserveIdempotencyKey(ctx context.Context, record IdempotencyKeyRecord) (*IdempotencyKeyRecord, error) {
+	now := time.Now()
+	// O monitor de TTL roda a cada minuto: chaves vencidas podem continuar na coleção
+	filter := bson.M{"_id": record.Key, "$or": bson.A{
+		bson.M{"expires_at": bson.M{"$lte": now}},
+		bson.M{"job_id": bson.M{"$exists": false}, "created_at": bson.M{"$lte": now.Add(-IdempotencyReservationTimeout)}},
+	}}
+	_, err := r.idempotencyKeys().ReplaceOne(ctx, filter, record, options.Replace().SetUpsert(true))
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %v", err)
+	}
+
+	var existing IdempotencyKeyRecord
+	if err := r.idempotencyKeys().FindOne(ctx, bson.M{"_id": record.Key}).Decode(&existing); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("idempotency key %s removed while reserving", record.Key)
+		}
+		return nil, fmt.Errorf("failed to find idempotency key: %v", err)
+	}
+	return &existing, nil
+}
+
+// CompleteIdempotencyKey registra o job criado pela chave
+func (r *MongoRepository) CompleteIdempotencyKey(ctx context.Context, key string, job CrawlJob) error {
+	update := bson.M{"$set": bson.M{"job_id": job.ID, "job": job}}
+	if _, err := r.idempotencyKeys().UpdateByID(ctx, key, update); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %v", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey remove uma chave reservada que ainda não tem job
+func (r *MongoRepository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	filter := bson.M{"_id": key, "job_id": bson.M{"$exists": false}}
+	if _, err := r.idempotencyKeys().DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %v", err)
+	}
+	return nil
+}
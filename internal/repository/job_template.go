@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobTemplateCollection guarda os templates de job de crawling cadastrados pela API
+const JobTemplateCollection = "job_templates"
+
+// Modos de execução de um job de crawling
+const (
+	CrawlModeFull        = "full"
+	CrawlModeIncremental = "incremental"
+)
+
+// Limites dos templates de job
+const (
+	maxTemplateCities     = 20
+	maxTemplateNameLength = 64
+	maxTemplateMinutes    = 7 * 24 * 60
+)
+
+// ErrJobTemplateNotFound indica que não há template com o nome informado
+var ErrJobTemplateNotFound = errors.New("job template not found")
+
+// jobTemplatePattern aceita nomes como "weekly-full" ou "new-site-validation"
+var jobTemplatePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// CrawlJobTemplate é um conjunto nomeado de parâmetros de crawling (modo, IA, cidades, domínios,
+// tags e orçamentos), usado para disparar execuções recorrentes com uma única chamada
+type CrawlJobTemplate struct {
+	Name        string   `bson:"name" json:"name"`
+	Description string   `bson:"description,omitempty" json:"description,omitempty"`
+	Mode        string   `bson:"mode" json:"mode"`                           // full, incremental
+	Cities      []string `bson:"cities,omitempty" json:"cities,omitempty"`   // vazio: todas as cidades
+	Domains     []string `bson:"domains,omitempty" json:"domains,omitempty"` // restringe as seeds a estes domínios
+	Tags        []string `bson:"tags,omitempty" json:"tags,omitempty"`
+	EnableAI    *bool    `bson:"enable_ai,omitempty" json:"enable_ai,omitempty"`   // nil: usa a IA se disponível
+	MaxPages    int      `bson:"max_pages" json:"max_pages"`                       // 0: sem limite de páginas
+	MaxMinutes  int      `bson:"max_duration_minutes" json:"max_duration_minutes"` // 0: sem limite de tempo
+
+	Builtin   bool       `bson:"-" json:"builtin"` // template padrão, não cadastrado no banco
+	CreatedAt *time.Time `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	UpdatedAt *time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// Normalize padroniza o nome, o modo, as tags e os domínios do template e valida os parâmetros
+func (t *CrawlJobTemplate) Normalize() error {
+	t.Name = strings.ToLower(strings.TrimSpace(t.Name))
+	if len(t.Name) > maxTemplateNameLength || !jobTemplatePattern.MatchString(t.Name) {
+		return fmt.Errorf("invalid template name %q (use letters, digits, '_' or '-', up to %d characters)", t.Name, maxTemplateNameLength)
+	}
+
+	t.Mode = strings.ToLower(strings.TrimSpace(t.Mode))
+	if t.Mode == "" {
+		t.Mode = CrawlModeIncremental
+	}
+	if t.Mode != CrawlModeFull && t.Mode != CrawlModeIncremental {
+		return fmt.Errorf("invalid mode %q (expected %s or %s)", t.Mode, CrawlModeFull, CrawlModeIncremental)
+	}
+
+	if len(t.Cities) > maxTemplateCities {
+		return fmt.Errorf("too many cities (max %d)", maxTemplateCities)
+	}
+
+	tags, err := NormalizeCrawlTags(t.Tags)
+	if err != nil {
+		return err
+	}
+	t.Tags = tags
+
+	var domains []string
+	seen := make(map[string]bool)
+	for _, domain := range t.Domains {
+		normalized := NormalizeDomain(domain)
+		if normalized == "" {
+			return fmt.Errorf("invalid domain %q", domain)
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			domains = append(domains, normalized)
+		}
+	}
+	t.Domains = domains
+
+	if t.MaxPages < 0 {
+		return fmt.Errorf("max_pages cannot be negative")
+	}
+	if t.MaxMinutes < 0 || t.MaxMinutes > maxTemplateMinutes {
+		return fmt.Errorf("max_duration_minutes must be between 0 and %d", maxTemplateMinutes)
+	}
+	return nil
+}
+
+// MaxDuration retorna o orçamento de tempo do job (0 sem limite)
+func (t *CrawlJobTemplate) MaxDuration() time.Duration {
+	return time.Duration(t.MaxMinutes) * time.Minute
+}
+
+// DefaultJobTemplates retorna os templates padrão, disponíveis sem cadastro; um template
+// cadastrado com o mesmo nome os substitui
+func DefaultJobTemplates() []CrawlJobTemplate {
+	enabled, disabled := true, false
+	return []CrawlJobTemplate{
+		{
+			Name:        "daily-incremental",
+			Description: "Crawling diário de todas as cidades, limitado a 2 horas",
+			Mode:        CrawlModeIncremental,
+			Tags:        []string{"daily-incremental"},
+			MaxMinutes:  120,
+			Builtin:     true,
+		},
+		{
+			Name:        "new-site-validation",
+			Description: "Amostra curta e sem IA para validar um site novo (informe os domínios ao executar)",
+			Mode:        CrawlModeFull,
+			Tags:        []string{"new-site-validation"},
+			EnableAI:    &disabled,
+			MaxPages:    200,
+			MaxMinutes:  15,
+			Builtin:     true,
+		},
+		{
+			Name:        "weekly-full",
+			Description: "Crawling completo semanal de todas as cidades, com IA",
+			Mode:        CrawlModeFull,
+			Tags:        []string{"weekly-full"},
+			EnableAI:    &enabled,
+			MaxMinutes:  12 * 60,
+			Builtin:     true,
+		},
+	}
+}
+
+// JobTemplateRepository é implementado por repositórios capazes de guardar templates de job
+type JobTemplateRepository interface {
+	SaveJobTemplate(ctx context.Context, template CrawlJobTemplate) (*CrawlJobTemplate, error)
+	FindJobTemplate(ctx context.Context, name string) (*CrawlJobTemplate, error)
+	FindJobTemplates(ctx context.Context) ([]CrawlJobTemplate, error)
+	DeleteJobTemplate(ctx context.Context, name string) error
+}
+
+// jobTemplates retorna a coleção de templates de job
+func (r *MongoRepository) jobTemplates() *mongo.Collection {
+	return r.collection.Database().Collection(JobTemplateCollection)
+}
+
+// SaveJobTemplate cria ou substitui um template, preservando a data de criação
+func (r *MongoRepository) SaveJobTemplate(ctx context.Context, template CrawlJobTemplate) (*CrawlJobTemplate, error) {
+	collection := r.jobTemplates()
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return nil, fmt.Errorf("failed to create job template indexes: %v", err)
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"name":                 template.Name,
+		"description":          template.Description,
+		"mode":                 template.Mode,
+		"cities":               template.Cities,
+		"domains":              template.Domains,
+		"tags":                 template.Tags,
+		"enable_ai":            template.EnableAI,
+		"max_pages":            template.MaxPages,
+		"max_duration_minutes": template.MaxMinutes,
+		"updated_at":           now,
+	}
+	update := bson.M{"$set": set, "$setOnInsert": bson.M{"created_at": now}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"name": template.Name}, update, options.Update().SetUpsert(true)); err != nil {
+		return nil, fmt.Errorf("failed to save job template: %v", err)
+	}
+	return r.FindJobTemplate(ctx, template.Name)
+}
+
+// FindJobTemplate busca um template cadastrado (nil se não existir)
+func (r *MongoRepository) FindJobTemplate(ctx context.Context, name string) (*CrawlJobTemplate, error) {
+	var template CrawlJobTemplate
+	err := r.jobTemplates().FindOne(ctx, bson.M{"name": name}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find job template: %v", err)
+	}
+	return &template, nil
+}
+
+// FindJobTemplates retorna os templates cadastrados em ordem alfabética
+func (r *MongoRepository) FindJobTemplates(ctx context.Context) ([]CrawlJobTemplate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.jobTemplates().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job templates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var templates []CrawlJobTemplate
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode job templates: %v", err)
+	}
+	return templates, nil
+}
+
+// DeleteJobTemplate remove um template cadastrado
+func (r *MongoRepository) DeleteJobTemplate(ctx context.Context, name string) error {
+	result, err := r.jobTemplates().DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("failed to delete job template: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrJobTemplateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Arquivos da service account montados nos pods do Kubernetes
+const (
+	kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubernetesMicroTimeFormat   = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// KubernetesLeaseRepository implementa LeaderLeaseRepository com objetos Lease
+// (coordination.k8s.io/v1), o mesmo mecanismo usado pelos controladores do cluster. A concorrência
+// é otimista: cada atualização envia o resourceVersion lido e um conflito (409) significa que
+// outra réplica atualizou a lease antes.
+type KubernetesLeaseRepository struct {
+	apiURL    string
+	namespace string
+	tokenFile string
+	client    *http.Client
+}
+
+// kubernetesLease é a representação JSON de um objeto Lease
+type kubernetesLease struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name            string `json:"name"`
+		Namespace       string `json:"namespace,omitempty"`
+		ResourceVersion string `json:"resourceVersion,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		HolderIdentity       string `json:"holderIdentity"`
+		LeaseDurationSeconds int    `json:"leaseDurationSeconds"`
+		AcquireTime          string `json:"acquireTime,omitempty"`
+		RenewTime            string `json:"renewTime,omitempty"`
+		LeaseTransitions     int    `json:"leaseTransitions"`
+	} `json:"spec"`
+}
+
+// NewKubernetesLeaseRepository cria o backend para a API em apiURL; tokenFile (opcional) é relido
+// a cada requisição, acompanhando a rotação dos tokens da service account
+func NewKubernetesLeaseRepository(apiURL, namespace, tokenFile string, client *http.Client) *KubernetesLeaseRepository {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KubernetesLeaseRepository{
+		apiURL:    strings.TrimRight(apiURL, "/"),
+		namespace: namespace,
+		tokenFile: tokenFile,
+		client:    client,
+	}
+}
+
+// NewInClusterLeaseRepository cria o backend com a service account do pod: endereço da API nas
+// variáveis KUBERNETES_SERVICE_HOST/PORT, namespace, token e CA montados pelo Kubernetes
+func NewInClusterLeaseRepository() (*KubernetesLeaseRepository, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a Kubernetes cluster (KUBERNETES_SERVICE_HOST/PORT unset)")
+	}
+
+	namespace, err := os.ReadFile(kubernetesServiceAccountDir + "/namespace")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account namespace: %v", err)
+	}
+	ca, err := os.ReadFile(kubernetesServiceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("invalid service account CA certificate")
+	}
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+	apiURL := "https://" + net.JoinHostPort(host, port)
+	return NewKubernetesLeaseRepository(apiURL, strings.TrimSpace(string(namespace)), kubernetesServiceAccountDir+"/token", client), nil
+}
+
+// AcquireLeaderLease cria a Lease ou a atualiza quando ela é do próprio holder ou está vencida
+func (r *KubernetesLeaseRepository) AcquireLeaderLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	lease, err := r.get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	if lease == nil {
+		lease = &kubernetesLease{APIVersion: "coordination.k8s.io/v1", Kind: "Lease"}
+		lease.Metadata.Name = name
+		lease.Metadata.Namespace = r.namespace
+		lease.Spec.HolderIdentity = holder
+		lease.Spec.LeaseDurationSeconds = leaseSeconds(ttl)
+		lease.Spec.AcquireTime = now.UTC().Format(kubernetesMicroTimeFormat)
+		lease.Spec.RenewTime = lease.Spec.AcquireTime
+		return r.write(ctx, http.MethodPost, r.leasesURL(), lease)
+	}
+
+	current := lease.toLeaderLease()
+	if current.Holder != holder && current.Holder != "" && current.ExpiresAt.After(now) {
+		return false, nil
+	}
+
+	if current.Holder != holder {
+		lease.Spec.AcquireTime = now.UTC().Format(kubernetesMicroTimeFormat)
+		lease.Spec.LeaseTransitions++
+	}
+	lease.Spec.HolderIdentity = holder
+	lease.Spec.LeaseDurationSeconds = leaseSeconds(ttl)
+	lease.Spec.RenewTime = now.UTC().Format(kubernetesMicroTimeFormat)
+	return r.write(ctx, http.MethodPut, r.leasesURL()+"/"+name, lease)
+}
+
+// ReleaseLeaderLease esvazia o holder da Lease mantida pelo holder informado
+func (r *KubernetesLeaseRepository) ReleaseLeaderLease(ctx context.Context, name, holder string) error {
+	lease, err := r.get(ctx, name)
+	if err != nil || lease == nil || lease.Spec.HolderIdentity != holder {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = ""
+	lease.Spec.LeaseDurationSeconds = 1
+	lease.Spec.RenewTime = time.Now().UTC().Format(kubernetesMicroTimeFormat)
+	if _, err := r.write(ctx, http.MethodPut, r.leasesURL()+"/"+name, lease); err != nil {
+		return fmt.Errorf("failed to release leader lease: %v", err)
+	}
+	return nil
+}
+
+// FindLeaderLease retorna a Lease atual (nil se não existir)
+func (r *KubernetesLeaseRepository) FindLeaderLease(ctx context.Context, name string) (*LeaderLease, error) {
+	lease, err := r.get(ctx, name)
+	if err != nil || lease == nil {
+		return nil, err
+	}
+	return lease.toLeaderLease(), nil
+}
+
+// leasesURL retorna o endpoint das Leases do namespace
+func (r *KubernetesLeaseRepository) leasesURL() string {
+	return fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", r.apiURL, r.namespace)
+}
+
+// get busca a Lease (nil se não existir)
+func (r *KubernetesLeaseRepository) get(ctx context.Context, name string) (*kubernetesLease, error) {
+	resp, err := r.do(ctx, http.MethodGet, r.leasesURL()+"/"+name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kubernetes lease request returned status %d", resp.StatusCode)
+	}
+
+	var lease kubernetesLease
+	if err := json.NewDecoder(resp.Body).Decode(&lease); err != nil {
+		return nil, fmt.Errorf("invalid kubernetes lease: %v", err)
+	}
+	return &lease, nil
+}
+
+// write cria (POST) ou atualiza (PUT) a Lease; false quando outra réplica a alterou antes (409)
+func (r *KubernetesLeaseRepository) write(ctx context.Context, method, url string, lease *kubernetesLease) (bool, error) {
+	body, err := json.Marshal(lease)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode kubernetes lease: %v", err)
+	}
+
+	resp, err := r.do(ctx, method, url, body)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, nil
+	default:
+		return false, fmt.Errorf("kubernetes lease update returned status %d", resp.StatusCode)
+	}
+}
+
+// do envia a requisição autenticada com o token da service account
+func (r *KubernetesLeaseRepository) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes lease request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.tokenFile != "" {
+		token, err := os.ReadFile(r.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service account token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes lease request failed: %v", err)
+	}
+	return resp, nil
+}
+
+// toLeaderLease converte a Lease; a validade é renewTime + leaseDurationSeconds
+func (l *kubernetesLease) toLeaderLease() *LeaderLease {
+	lease := &LeaderLease{Name: l.Metadata.Name, Holder: l.Spec.HolderIdentity}
+	lease.AcquiredAt, _ = time.Parse(time.RFC3339Nano, l.Spec.AcquireTime)
+	lease.RenewedAt, _ = time.Parse(time.RFC3339Nano, l.Spec.RenewTime)
+	lease.ExpiresAt = lease.RenewedAt.Add(time.Duration(l.Spec.LeaseDurationSeconds) * time.Second)
+	return lease
+}
+
+// leaseSeconds converte a validade da lease para segundos inteiros (mínimo 1)
+func leaseSeconds(ttl time.Duration) int {
+	return int(math.Max(math.Ceil(ttl.Seconds()), 1))
+}
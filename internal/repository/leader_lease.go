@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaderLeaseCollection guarda as leases de liderança entre as réplicas da API
+const LeaderLeaseCollection = "leader_leases"
+
+// LeaderLease registra qual instância (Holder) detém a liderança até ExpiresAt
+type LeaderLease struct {
+	Name       string    `bson:"_id" json:"name"`
+	Holder     string    `bson:"holder" json:"holder"`
+	AcquiredAt time.Time `bson:"acquired_at" json:"acquired_at"`
+	RenewedAt  time.Time `bson:"renewed_at" json:"renewed_at"`
+	ExpiresAt  time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// LeaderLeaseRepository é implementado pelos backends da eleição de líder (MongoDB ou a API de
+// Leases do Kubernetes)
+type LeaderLeaseRepository interface {
+	// AcquireLeaderLease obtém (ou renova) a lease; false se outra instância detém uma lease válida
+	AcquireLeaderLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLeaderLease libera a lease se ela pertencer ao holder, para um failover imediato
+	ReleaseLeaderLease(ctx context.Context, name, holder string) error
+	// FindLeaderLease retorna a lease atual (nil se nunca foi obtida)
+	FindLeaderLease(ctx context.Context, name string) (*LeaderLease, error)
+}
+
+// leaderLeases retorna a coleção das leases de liderança
+func (r *MongoRepository) leaderLeases() *mongo.Collection {
+	return r.collection.Database().Collection(LeaderLeaseCollection)
+}
+
+// AcquireLeaderLease grava a lease com upsert condicionado, como AcquireURLLease: o documento só
+// é atualizado se a lease for do próprio holder ou estiver vencida; caso contrário o upsert colide
+// com o _id existente. acquired_at só muda quando a liderança troca de instância.
+func (r *MongoRepository) AcquireLeaderLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.A{bson.M{"$set": bson.M{
+		"acquired_at": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$holder", holder}}, "$acquired_at", now}},
+		"holder":      holder,
+		"renewed_at":  now,
+		"expires_at":  now.Add(ttl),
+	}}}
+
+	_, err := r.leaderLeases().UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leader lease: %v", err)
+	}
+	return true, nil
+}
+
+// ReleaseLeaderLease expira a lease mantida pelo holder
+func (r *MongoRepository) ReleaseLeaderLease(ctx context.Context, name, holder string) error {
+	_, err := r.leaderLeases().UpdateOne(ctx,
+		bson.M{"_id": name, "holder": holder},
+		bson.M{"$set": bson.M{"expires_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release leader lease: %v", err)
+	}
+	return nil
+}
+
+// FindLeaderLease retorna a lease de liderança (nil se não existir)
+func (r *MongoRepository) FindLeaderLease(ctx context.Context, name string) (*LeaderLease, error) {
+	var lease LeaderLease
+	err := r.leaderLeases().FindOne(ctx, bson.M{"_id": name}).Decode(&lease)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find leader lease: %v", err)
+	}
+	return &lease, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ListingStateDelistedArchived marca o anúncio que saiu do site de origem (404 ou 410) e foi
+// completado com a cópia arquivada no Wayback Machine
+const ListingStateDelistedArchived = "delisted_with_archive"
+
+var errArchivedListingUnsupported = errors.New("archived listings not supported by property repository")
+
+// ListingArchive é a cópia arquivada usada para completar um anúncio removido
+type ListingArchive struct {
+	URL        string    // endereço da cópia no arquivo
+	CapturedAt time.Time // data em que a cópia foi feita
+}
+
+// ArchivedListingRepository é implementado por repositórios capazes de gravar os anúncios
+// removidos completados com a cópia arquivada
+type ArchivedListingRepository interface {
+	SaveArchivedListing(ctx context.Context, property Property) error
+}
+
+// MarkDelistedWithArchive completa os campos vazios do anúncio salvo com os extraídos da cópia
+// arquivada e o marca como removido; retorna os campos completados. Campos corrigidos
+// manualmente nunca são alterados
+func MarkDelistedWithArchive(stored *Property, archived *Property, archive ListingArchive, delistedAt time.Time) []string {
+	var filled []string
+	if archived != nil {
+		corrected := make(map[string]bool, len(stored.CorrectedFields))
+		for _, field := range stored.CorrectedFields {
+			corrected[field] = true
+		}
+		fill := func(field string, empty bool, apply func()) {
+			if !empty || corrected[field] {
+				return
+			}
+			apply()
+			filled = append(filled, field)
+			stored.SetProvenance(field, ExtractorArchive, archive.URL)
+		}
+
+		fill("endereco", stored.Endereco == "" && archived.Endereco != "", func() { stored.Endereco = archived.Endereco })
+		fill("bairro", stored.Bairro == "" && archived.Bairro != "", func() { stored.Bairro = archived.Bairro })
+		fill("cidade", stored.Cidade == "" && archived.Cidade != "", func() { stored.Cidade = archived.Cidade })
+		fill("cep", stored.CEP == "" && archived.CEP != "", func() { stored.CEP = archived.CEP })
+		fill("descricao", stored.Descricao == "" && archived.Descricao != "", func() { stored.Descricao = archived.Descricao })
+		fill("valor", stored.Valor == 0 && archived.Valor > 0, func() {
+			stored.Valor = archived.Valor
+			stored.ValorTexto = archived.ValorTexto
+		})
+		fill("quartos", stored.Quartos == 0 && archived.Quartos > 0, func() { stored.Quartos = archived.Quartos })
+		fill("banheiros", stored.Banheiros == 0 && archived.Banheiros > 0, func() { stored.Banheiros = archived.Banheiros })
+		fill("area_total", stored.AreaTotal == 0 && archived.AreaTotal > 0, func() { stored.AreaTotal = archived.AreaTotal })
+		fill("area_util", stored.AreaUtil == 0 && archived.AreaUtil > 0, func() { stored.AreaUtil = archived.AreaUtil })
+		fill("tipo_imovel", stored.TipoImovel == "" && archived.TipoImovel != "", func() { stored.TipoImovel = archived.TipoImovel })
+		fill("caracteristicas", len(stored.Caracteristicas) == 0 && len(archived.Caracteristicas) > 0, func() {
+			stored.Caracteristicas = archived.Caracteristicas
+		})
+		fill("foto_urls", len(stored.FotoURLs) == 0 && len(archived.FotoURLs) > 0, func() {
+			stored.FotoURLs = archived.FotoURLs
+			stored.Fotos = len(archived.FotoURLs)
+			stored.SemFotos = false
+		})
+	}
+
+	capturedAt := archive.CapturedAt
+	stored.ListingState = ListingStateDelistedArchived
+	stored.ArchiveURL = archive.URL
+	stored.ArchivedAt = &capturedAt
+	stored.DelistedAt = &delistedAt
+	return filled
+}
+
+// SaveArchivedListing grava o anúncio removido completado com a cópia arquivada. Se o conteúdo
+// completado já pertence a outro anúncio (hash único), grava apenas a marcação de removido
+func (r *MongoRepository) SaveArchivedListing(ctx context.Context, property Property) error {
+	ApplyDerivedFields(&property)
+	property.Hash = GeneratePropertyHash(property)
+
+	filter := propertyIDFilter(property.ID)
+	update := property
+	update.ID = ""
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": update})
+	if mongo.IsDuplicateKeyError(err) {
+		_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+			"listing_state": property.ListingState,
+			"archive_url":   property.ArchiveURL,
+			"archived_at":   property.ArchivedAt,
+			"delisted_at":   property.DelistedAt,
+		}})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save archived listing: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+// Loteamento resume um projeto de loteamento anunciado em uma única página: quantidade de lotes e
+// faixas de área e de preço por lote. Só o registro pai do projeto tem esse campo; os lotes são
+// registros filhos do tipo Terreno com o mesmo ParentID.
+type Loteamento struct {
+	Nome        string  `bson:"nome,omitempty" json:"nome,omitempty"`
+	TotalLotes  int     `bson:"total_lotes,omitempty" json:"total_lotes,omitempty"`
+	AreaMinima  float64 `bson:"area_minima,omitempty" json:"area_minima,omitempty"`
+	AreaMaxima  float64 `bson:"area_maxima,omitempty" json:"area_maxima,omitempty"`
+	PrecoMinimo float64 `bson:"preco_minimo,omitempty" json:"preco_minimo,omitempty"`
+	PrecoMaximo float64 `bson:"preco_maximo,omitempty" json:"preco_maximo,omitempty"`
+}
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Estados de conexão reportados em MongoHealth
+const (
+	MongoStatusUnknown  = "unknown"
+	MongoStatusUp       = "up"
+	MongoStatusDegraded = "degraded" // parte dos servidores do cluster inacessível
+	MongoStatusDown     = "down"
+)
+
+// MongoClientOptions configura os clientes Mongo criados pelos repositórios.
+// Valores zero mantêm o que vier na URI (ou o padrão do driver).
+type MongoClientOptions struct {
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+	MaxConnIdleTime        time.Duration
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	SocketTimeout          time.Duration
+	HeartbeatInterval      time.Duration
+	RetryWrites            bool
+	RetryReads             bool
+	ReadConcern            string // local, available, majority, linearizable, snapshot
+	WriteConcern           string // majority ou número de nós (ex: "1")
+	WriteTimeout           time.Duration
+
+	// Novas tentativas das operações de escrita do crawler quando o Mongo está inacessível
+	OperationRetries int
+	RetryBackoff     time.Duration
+}
+
+// DefaultMongoClientOptions retorna as opções usadas quando nada é configurado
+func DefaultMongoClientOptions() MongoClientOptions {
+	return MongoClientOptions{
+		RetryWrites:      true,
+		RetryReads:       true,
+		OperationRetries: 3,
+		RetryBackoff:     time.Second,
+	}
+}
+
+var (
+	mongoOptionsMutex sync.RWMutex
+	mongoOptions      = DefaultMongoClientOptions()
+)
+
+// SetMongoClientOptions define as opções dos clientes criados a partir de então
+func SetMongoClientOptions(opts MongoClientOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	mongoOptionsMutex.Lock()
+	defer mongoOptionsMutex.Unlock()
+	mongoOptions = opts
+	return nil
+}
+
+// currentMongoOptions retorna as opções configuradas
+func currentMongoOptions() MongoClientOptions {
+	mongoOptionsMutex.RLock()
+	defer mongoOptionsMutex.RUnlock()
+	return mongoOptions
+}
+
+// Validate verifica limites e níveis de read/write concern
+func (o MongoClientOptions) Validate() error {
+	if o.MinPoolSize > 0 && o.MaxPoolSize > 0 && o.MinPoolSize > o.MaxPoolSize {
+		return fmt.Errorf("mongo min pool size (%d) exceeds max pool size (%d)", o.MinPoolSize, o.MaxPoolSize)
+	}
+	if o.OperationRetries < 0 {
+		return fmt.Errorf("mongo operation retries must not be negative")
+	}
+	durations := map[string]time.Duration{
+		"max conn idle time":       o.MaxConnIdleTime,
+		"connect timeout":          o.ConnectTimeout,
+		"server selection timeout": o.ServerSelectionTimeout,
+		"socket timeout":           o.SocketTimeout,
+		"heartbeat interval":       o.HeartbeatInterval,
+		"write timeout":            o.WriteTimeout,
+		"retry backoff":            o.RetryBackoff,
+	}
+	for name, value := range durations {
+		if value < 0 {
+			return fmt.Errorf("mongo %s must not be negative", name)
+		}
+	}
+	if _, err := parseReadConcern(o.ReadConcern); err != nil {
+		return err
+	}
+	if _, err := parseWriteConcern(o.WriteConcern, o.WriteTimeout); err != nil {
+		return err
+	}
+	return nil
+}
+
+// clientOptions converte a configuração nas opções do driver, aplicadas sobre a URI
+func (o MongoClientOptions) clientOptions(uri string) *options.ClientOptions {
+	clientOptions := options.Client().ApplyURI(uri)
+	if o.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(o.MaxPoolSize)
+	}
+	if o.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(o.MinPoolSize)
+	}
+	if o.MaxConnIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(o.MaxConnIdleTime)
+	}
+	if o.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(o.ConnectTimeout)
+	}
+	if o.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(o.ServerSelectionTimeout)
+	}
+	if o.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(o.SocketTimeout)
+	}
+	if o.HeartbeatInterval > 0 {
+		clientOptions.SetHeartbeatInterval(o.HeartbeatInterval)
+	}
+	clientOptions.SetRetryWrites(o.RetryWrites)
+	clientOptions.SetRetryReads(o.RetryReads)
+	if concern, _ := parseReadConcern(o.ReadConcern); concern != nil {
+		clientOptions.SetReadConcern(concern)
+	}
+	if concern, _ := parseWriteConcern(o.WriteConcern, o.WriteTimeout); concern != nil {
+		clientOptions.SetWriteConcern(concern)
+	}
+	clientOptions.SetServerMonitor(mongoHealth.monitor())
+	return clientOptions
+}
+
+// parseReadConcern converte o nível informado (vazio mantém o padrão)
+func parseReadConcern(level string) (*readconcern.ReadConcern, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "":
+		return nil, nil
+	case "local":
+		return readconcern.Local(), nil
+	case "available":
+		return readconcern.Available(), nil
+	case "majority":
+		return readconcern.Majority(), nil
+	case "linearizable":
+		return readconcern.Linearizable(), nil
+	case "snapshot":
+		return readconcern.Snapshot(), nil
+	default:
+		return nil, fmt.Errorf("invalid mongo read concern %q", level)
+	}
+}
+
+// parseWriteConcern converte "majority" ou o número de nós (vazio mantém o padrão)
+func parseWriteConcern(value string, timeout time.Duration) (*writeconcern.WriteConcern, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil, nil
+	}
+
+	var concernOptions []writeconcern.Option
+	if value == "majority" {
+		concernOptions = append(concernOptions, writeconcern.WMajority())
+	} else {
+		nodes, err := strconv.Atoi(value)
+		if err != nil || nodes < 0 {
+			return nil, fmt.Errorf("invalid mongo write concern %q", value)
+		}
+		concernOptions = append(concernOptions, writeconcern.W(nodes))
+	}
+	if timeout > 0 {
+		concernOptions = append(concernOptions, writeconcern.WTimeout(timeout))
+	}
+	return writeconcern.New(concernOptions...), nil
+}
+
+// connectMongo cria o cliente com as opções configuradas e verifica a conexão
+func connectMongo(uri string) (*mongo.Client, error) {
+	client, err := mongo.Connect(context.Background(), currentMongoOptions().clientOptions(uri))
+	if err != nil {
+		return nil, storageUnavailable("failed to connect to MongoDB", err)
+	}
+
+	if err := client.Ping(context.Background(), nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, storageUnavailable("failed to ping MongoDB", err)
+	}
+	return client, nil
+}
+
+// withStorageRetry executa a operação e, se o Mongo estiver inacessível, tenta novamente
+// com espera crescente enquanto o driver restabelece a conexão. Esgotadas as tentativas,
+// o erro é envolvido em ErrStorageUnavailable.
+func withStorageRetry(ctx context.Context, operation string, fn func() error) error {
+	opts := currentMongoOptions()
+	backoff := opts.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if attempt > 0 {
+				log.Printf("MongoDB operation %q succeeded after %d retries", operation, attempt)
+			}
+			return nil
+		}
+		if !IsStorageUnavailable(err) {
+			return fmt.Errorf("%s: %v", operation, err)
+		}
+		if attempt >= opts.OperationRetries || ctx.Err() != nil {
+			mongoHealth.recordFailedOperation(err)
+			return storageUnavailable(operation, err)
+		}
+
+		mongoHealth.recordRetry()
+		log.Printf("Warning: MongoDB unavailable during %q (attempt %d/%d), retrying in %v: %v",
+			operation, attempt+1, opts.OperationRetries, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			mongoHealth.recordFailedOperation(err)
+			return storageUnavailable(operation, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// MongoHealth resume o estado da conexão com o Mongo (exposto no /health)
+type MongoHealth struct {
+	Status           string          `json:"status"`
+	Servers          map[string]bool `json:"servers,omitempty"` // endereço -> acessível
+	LastHeartbeat    *time.Time      `json:"last_heartbeat,omitempty"`
+	LastError        string          `json:"last_error,omitempty"`
+	LastErrorAt      *time.Time      `json:"last_error_at,omitempty"`
+	DownSince        *time.Time      `json:"down_since,omitempty"`
+	Reconnects       int64           `json:"reconnects"`
+	RetriedOps       int64           `json:"retried_operations"`
+	FailedOperations int64           `json:"failed_operations"`
+}
+
+// mongoHealthTracker acompanha os heartbeats de todos os clientes criados pelos repositórios
+type mongoHealthTracker struct {
+	mutex         sync.RWMutex
+	servers       map[string]bool
+	lastHeartbeat time.Time
+	lastError     string
+	lastErrorAt   time.Time
+	downSince     time.Time
+	reconnects    int64
+	retried       int64
+	failed        int64
+}
+
+var mongoHealth = newMongoHealthTracker()
+
+func newMongoHealthTracker() *mongoHealthTracker {
+	return &mongoHealthTracker{servers: make(map[string]bool)}
+}
+
+// monitor cria o monitor de servidores registrado nos clientes
+func (t *mongoHealthTracker) monitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		ServerHeartbeatSucceeded: func(e *event.ServerHeartbeatSucceededEvent) {
+			t.heartbeatSucceeded(serverAddress(e.ConnectionID))
+		},
+		ServerHeartbeatFailed: func(e *event.ServerHeartbeatFailedEvent) {
+			t.heartbeatFailed(serverAddress(e.ConnectionID), e.Failure)
+		},
+	}
+}
+
+// heartbeatSucceeded marca o servidor como acessível e registra a reconexão, se houver
+func (t *mongoHealthTracker) heartbeatSucceeded(server string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	wasDown := t.status() == MongoStatusDown
+	if up, known := t.servers[server]; known && !up {
+		t.reconnects++
+		log.Printf("MongoDB server %s reachable again", server)
+	}
+	t.servers[server] = true
+	t.lastHeartbeat = time.Now()
+	if wasDown {
+		log.Printf("MongoDB connection restored after %v", time.Since(t.downSince).Round(time.Second))
+	}
+	if t.status() != MongoStatusDown {
+		t.downSince = time.Time{}
+	}
+}
+
+// heartbeatFailed marca o servidor como inacessível
+func (t *mongoHealthTracker) heartbeatFailed(server string, failure error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if up, known := t.servers[server]; !known || up {
+		log.Printf("Warning: MongoDB server %s unreachable: %v", server, failure)
+	}
+	t.servers[server] = false
+	if failure != nil {
+		t.lastError = failure.Error()
+	}
+	t.lastErrorAt = time.Now()
+	if t.status() == MongoStatusDown && t.downSince.IsZero() {
+		t.downSince = time.Now()
+	}
+}
+
+// recordRetry contabiliza uma nova tentativa de operação
+func (t *mongoHealthTracker) recordRetry() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.retried++
+}
+
+// recordFailedOperation contabiliza uma operação que falhou após as novas tentativas
+func (t *mongoHealthTracker) recordFailedOperation(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.failed++
+	t.lastError = err.Error()
+	t.lastErrorAt = time.Now()
+}
+
+// status calcula o estado a partir dos servidores conhecidos; requer o mutex
+func (t *mongoHealthTracker) status() string {
+	if len(t.servers) == 0 {
+		return MongoStatusUnknown
+	}
+	up := 0
+	for _, reachable := range t.servers {
+		if reachable {
+			up++
+		}
+	}
+	switch up {
+	case 0:
+		return MongoStatusDown
+	case len(t.servers):
+		return MongoStatusUp
+	default:
+		return MongoStatusDegraded
+	}
+}
+
+// snapshot copia o estado atual
+func (t *mongoHealthTracker) snapshot() MongoHealth {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	health := MongoHealth{
+		Status:           t.status(),
+		Servers:          make(map[string]bool, len(t.servers)),
+		LastError:        t.lastError,
+		Reconnects:       t.reconnects,
+		RetriedOps:       t.retried,
+		FailedOperations: t.failed,
+	}
+	for server, reachable := range t.servers {
+		health.Servers[server] = reachable
+	}
+	if !t.lastHeartbeat.IsZero() {
+		lastHeartbeat := t.lastHeartbeat
+		health.LastHeartbeat = &lastHeartbeat
+	}
+	if !t.lastErrorAt.IsZero() {
+		lastErrorAt := t.lastErrorAt
+		health.LastErrorAt = &lastErrorAt
+	}
+	if !t.downSince.IsZero() {
+		downSince := t.downSince
+		health.DownSince = &downSince
+	}
+	return health
+}
+
+// GetMongoHealth retorna o estado da conexão com o Mongo
+func GetMongoHealth() MongoHealth {
+	return mongoHealth.snapshot()
+}
+
+// serverAddress remove o identificador da conexão ("host:27017[-3]" -> "host:27017")
+func serverAddress(connectionID string) string {
+	if idx := strings.Index(connectionID, "["); idx != -1 {
+		return connectionID[:idx]
+	}
+	return connectionID
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
@@ -27,6 +28,41 @@ type PropertyFilter struct {
 	BanheirosMax int     `json:"banheiros_max,omitempty"`
 	AreaMin      float64 `json:"area_min,omitempty"`
 	AreaMax      float64 `json:"area_max,omitempty"`
+	Condicao     string  `json:"condicao,omitempty"`
+	CondicaoMin  int     `json:"condicao_min,omitempty"`
+
+	// Comodidades normalizadas exigidas (todas devem estar presentes, ver amenities.go)
+	Caracteristicas []string `json:"caracteristicas,omitempty"`
+
+	// Filtros de fotos
+	FotosMin int  `json:"fotos_min,omitempty"`
+	ComFotos bool `json:"com_fotos,omitempty"`
+
+	// Recursos de infraestrutura exigidos (ver property_features.go)
+	EnergiaSolar   bool `json:"energia_solar,omitempty"`
+	PocoArtesiano  bool `json:"poco_artesiano,omitempty"`
+	AquecimentoGas bool `json:"aquecimento_gas,omitempty"`
+
+	// Leilões: nil inclui todos, true apenas leilões, false exclui leilões
+	Leilao *bool `json:"leilao,omitempty"`
+
+	// Finalidade (residencial, comercial, rural, temporada) e faixa de aluguel mensal
+	Finalidade    string  `json:"finalidade,omitempty"`
+	AluguelMin    float64 `json:"aluguel_min,omitempty"`
+	AluguelMax    float64 `json:"aluguel_max,omitempty"`
+	ApenasAluguel bool    `json:"apenas_aluguel,omitempty"`
+
+	// Oculta anúncios suspeitos de golpe ou com fotos repetidas e limita o risco de golpe
+	OcultarSuspeitos bool `json:"ocultar_suspeitos,omitempty"`
+	RiscoGolpeMax    *int `json:"risco_golpe_max,omitempty"`
+
+	// Tags de crawling exigidas (todas devem estar presentes)
+	Tags []string `json:"tags,omitempty"`
+
+	// Distâncias máximas em km até o centro, escola e hospital (imóveis sem a distância ficam de fora)
+	MaxDistCentroKm   float64 `json:"max_dist_centro_km,omitempty"`
+	MaxDistEscolaKm   float64 `json:"max_dist_escola_km,omitempty"`
+	MaxDistHospitalKm float64 `json:"max_dist_hospital_km,omitempty"`
 }
 
 // PaginationParams define os parâmetros de paginação
@@ -53,6 +89,17 @@ type PropertyRepository interface {
 	Close()
 }
 
+// Origens possíveis de um imóvel
+const (
+	SourceCrawler = "crawler"
+	SourceImport  = "import"
+)
+
+// Formatos de origem do conteúdo de um anúncio (vazio é a página HTML)
+const (
+	SourceFormatPDF = "pdf"
+)
+
 type Property struct {
 	ID              string   `bson:"_id,omitempty" json:"id"`
 	Hash            string   `bson:"hash" json:"hash"`
@@ -61,6 +108,7 @@ type Property struct {
 	Bairro          string   `bson:"bairro" json:"bairro"`
 	CEP             string   `bson:"cep" json:"cep"`
 	Descricao       string   `bson:"descricao" json:"descricao"`
+	DescricaoEN     string   `bson:"descricao_en,omitempty" json:"descricao_en,omitempty"`
 	Valor           float64  `bson:"valor" json:"valor"`
 	ValorTexto      string   `bson:"valor_texto" json:"valor_texto"`
 	Quartos         int      `bson:"quartos" json:"quartos"`
@@ -68,13 +116,135 @@ type Property struct {
 	AreaTotal       float64  `bson:"area_total" json:"area_total"`
 	AreaUtil        float64  `bson:"area_util" json:"area_util"`
 	TipoImovel      string   `bson:"tipo_imovel" json:"tipo_imovel"`
+	TipoNormalizado string   `bson:"tipo_normalizado,omitempty" json:"tipo_normalizado,omitempty"` // taxonomia (ver derived_fields.go)
 	URL             string   `bson:"url" json:"url"`
+	ShortID         string   `bson:"short_id,omitempty" json:"short_id,omitempty"` // ver short_id.go; servido em /p/:shortid
 	Caracteristicas []string `bson:"caracteristicas" json:"caracteristicas"`
+
+	// Comodidades normalizadas a partir de Caracteristicas, indexadas para os filtros de busca. Sem
+	// omitempty no bson: o Save sobrescreve a lista (null quando não há comodidades)
+	Amenidades []string `bson:"amenidades" json:"amenidades,omitempty"`
+
+	// Campos derivados (ver derived_fields.go): preço por m² e localização geocodificada
+	ValorM2  float64   `bson:"valor_m2,omitempty" json:"valor_m2,omitempty"`
+	Location *GeoPoint `bson:"location,omitempty" json:"location,omitempty"`
+
+	// Distâncias até centro, escola e hospital mais próximos (ver poi_distances.go)
+	DistanciasPOI *POIDistances `bson:"distancias_poi,omitempty" json:"distancias_poi,omitempty"`
+
+	// Recursos de infraestrutura detectados no texto (ver property_features.go)
+	EnergiaSolar   bool `bson:"energia_solar" json:"energia_solar"`
+	PocoArtesiano  bool `bson:"poco_artesiano" json:"poco_artesiano"`
+	AquecimentoGas bool `bson:"aquecimento_gas" json:"aquecimento_gas"`
+
+	// Anúncios de leilão (ver auction.go) ficam fora das estatísticas de mercado
+	Leilao      bool       `bson:"leilao" json:"leilao"`
+	LeilaoData  *time.Time `bson:"leilao_data,omitempty" json:"leilao_data,omitempty"`
+	LanceMinimo float64    `bson:"lance_minimo,omitempty" json:"lance_minimo,omitempty"`
+
+	// Finalidade, venda x aluguel mensal, testada e zoneamento (ver commercial_lease.go)
+	Finalidade   string   `bson:"finalidade,omitempty" json:"finalidade,omitempty"`
+	ValorVenda   float64  `bson:"valor_venda,omitempty" json:"valor_venda,omitempty"`
+	ValorAluguel float64  `bson:"valor_aluguel,omitempty" json:"valor_aluguel,omitempty"`
+	Testada      float64  `bson:"testada,omitempty" json:"testada,omitempty"`
+	Zoneamento   []string `bson:"zoneamento,omitempty" json:"zoneamento,omitempty"`
+
+	// Aluguel por temporada (Finalidade "temporada"): diária e estadia mínima (ver vacation_rental.go)
+	ValorDiaria  float64 `bson:"valor_diaria,omitempty" json:"valor_diaria,omitempty"`
+	MinimoNoites int     `bson:"minimo_noites,omitempty" json:"minimo_noites,omitempty"`
+
+	// Preço convertido para outras moedas com as cotações do dia do crawling (ver exchange_rate.go)
+	ValorConvertido map[string]float64 `bson:"valor_convertido,omitempty" json:"valor_convertido,omitempty"`
+	CambioData      *time.Time         `bson:"cambio_data,omitempty" json:"cambio_data,omitempty"`
+
+	// Quantidade de fotos do anúncio; SemFotos sinaliza anúncios sem nenhuma imagem
+	Fotos    int  `bson:"fotos" json:"fotos"`
+	SemFotos bool `bson:"sem_fotos" json:"sem_fotos"`
+
+	// URLs e hashes perceptuais das fotos; SuspeitoFraude marca anúncios que repetem fotos de
+	// outro anúncio com endereço ou preço diferentes (ver photo_fraud.go)
+	FotoURLs       []string `bson:"foto_urls,omitempty" json:"foto_urls,omitempty"`
+	FotoHashes     []string `bson:"foto_hashes,omitempty" json:"foto_hashes,omitempty"`
+	SuspeitoFraude bool     `bson:"suspeito_fraude,omitempty" json:"suspeito_fraude,omitempty"`
+
+	// Risco de golpe (0 a 100) e sinais encontrados (ver scam_score.go). TemContato fica nil
+	// quando a origem não permite verificar a presença de telefone, WhatsApp ou e-mail.
+	TemContato    *bool    `bson:"tem_contato,omitempty" json:"tem_contato,omitempty"`
+	RiscoGolpe    int      `bson:"risco_golpe" json:"risco_golpe"`
+	SinaisGolpe   []string `bson:"sinais_golpe,omitempty" json:"sinais_golpe,omitempty"`
+	SuspeitoGolpe bool     `bson:"suspeito_golpe" json:"suspeito_golpe"`
+
+	// Origem do registro: crawler ou import (dados externos enviados pela API)
+	Source string `bson:"source,omitempty" json:"source,omitempty"`
+
+	// Anúncio removido do site de origem e completado com a cópia arquivada (ver listing_archive.go)
+	ListingState string     `bson:"listing_state,omitempty" json:"listing_state,omitempty"`
+	ArchiveURL   string     `bson:"archive_url,omitempty" json:"archive_url,omitempty"`
+	ArchivedAt   *time.Time `bson:"archived_at,omitempty" json:"archived_at,omitempty"` // data da cópia
+	DelistedAt   *time.Time `bson:"delisted_at,omitempty" json:"delisted_at,omitempty"`
+
+	// Formato do conteúdo de onde o anúncio foi extraído: vazio para páginas HTML, "pdf" para
+	// folhetos em PDF (ver pdf_extractor.go)
+	SourceFormat string `bson:"source_format,omitempty" json:"source_format,omitempty"`
+
+	// Versão do formato do documento (ver schema_migrations.go)
+	SchemaVersion int `bson:"schema_version" json:"schema_version,omitempty"`
+
+	// Extração original dos campos comparados entre visitas (ver property_change.go)
+	Extracao *ExtractionSnapshot `bson:"extracao,omitempty" json:"-"`
+
+	// Etapa de extração que produziu cada campo (ver field_provenance.go)
+	Provenance map[string]FieldProvenance `bson:"provenance,omitempty" json:"provenance,omitempty"`
+
+	// Confiança (0 a 1) de cada campo, retornada pela API apenas com ?include=confidence
+	FieldConfidence map[string]float64 `bson:"field_confidence,omitempty" json:"field_confidence,omitempty"`
+
+	// Anúncios com múltiplas unidades são divididos em registros que compartilham o mesmo ParentID
+	ParentID string `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+
+	// Loteamentos geram um registro pai com o resumo do projeto e um registro por lote,
+	// identificado em Lote ("Quadra A, Lote 12"); ver loteamento.go
+	Loteamento *Loteamento `bson:"loteamento,omitempty" json:"loteamento,omitempty"`
+	Lote       string      `bson:"lote,omitempty" json:"lote,omitempty"`
+
+	// Registro canônico do imóvel quando ele também é anunciado em outros portais (ver canonical_merge.go)
+	CanonicalID string `bson:"canonical_id,omitempty" json:"canonical_id,omitempty"`
+
+	// Caminho pelo qual o anúncio foi descoberto (seed → catálogo → página N → imóvel)
+	DiscoveryDepth int             `bson:"discovery_depth,omitempty" json:"discovery_depth,omitempty"`
+	DiscoveryPath  []DiscoveryStep `bson:"discovery_path,omitempty" json:"discovery_path,omitempty"`
+
+	// Estratégia de descoberta que levou ao anúncio (seed, feed, interactive_form, pagination, ...)
+	DiscoverySource string `bson:"discovery_source,omitempty" json:"discovery_source,omitempty"`
+
+	// Condição do imóvel estimada pela IA (ver property_condition.go)
+	Condicao          string  `bson:"condicao,omitempty" json:"condicao,omitempty"`
+	CondicaoNivel     int     `bson:"condicao_nivel,omitempty" json:"condicao_nivel,omitempty"`
+	CondicaoConfianca float64 `bson:"condicao_confianca,omitempty" json:"condicao_confianca,omitempty"`
+
+	// Campos adicionais preenchidos por etapas de extração customizadas (ver custom_fields.go)
+	CustomFields map[string]interface{} `bson:"custom_fields,omitempty" json:"custom_fields,omitempty"`
+
+	// Tags dos jobs de crawling que salvaram ou encontraram novamente o imóvel (ver crawl_tags.go)
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
+
+	// Campos corrigidos manualmente pela API, mantidos nos próximos crawlings (ver property_correction.go)
+	ManuallyCorrected bool     `bson:"manually_corrected,omitempty" json:"manually_corrected,omitempty"`
+	CorrectedFields   []string `bson:"corrected_fields,omitempty" json:"corrected_fields,omitempty"`
+}
+
+// DiscoveryStep representa um passo no caminho de descoberta de um imóvel
+type DiscoveryStep struct {
+	URL  string `bson:"url" json:"url"`
+	Kind string `bson:"kind" json:"kind"` // seed, catalog, pagination, page, property
+	Page int    `bson:"page,omitempty" json:"page,omitempty"`
 }
 
 type MongoRepository struct {
-	client     *mongo.Client
-	collection *mongo.Collection
+	client        *mongo.Client
+	collection    *mongo.Collection
+	exchangeRates *DailyExchangeRates // opcional: converte o preço para USD/EUR ao salvar
+	priceMedians  *priceMedianCache   // medianas do índice de preços usadas no risco de golpe
 }
 
 // normalizeURL normaliza uma URL removendo parâmetros desnecessários e espaços
@@ -146,22 +316,30 @@ func GeneratePropertyHash(property Property) string {
 		endereco, descricao, cidade, bairro, valor, area,
 		property.Quartos, property.Banheiros, urlNormalizada)
 
+	// Lotes de um loteamento podem ter a mesma área e o mesmo preço
+	if property.Lote != "" {
+		data += "|lote:" + normalizeContent(property.Lote)
+	} else if property.Loteamento != nil {
+		data += "|loteamento"
+	}
+
 	// Gera hash SHA-256
 	hash := sha256.Sum256([]byte(data))
 	return fmt.Sprintf("%x", hash)
 }
 
+// GenerateParentID gera o identificador compartilhado pelas unidades de um mesmo anúncio
+func GenerateParentID(url string) string {
+	hash := sha256.Sum256([]byte("parent|" + normalizeURL(url)))
+	return fmt.Sprintf("%x", hash)[:24]
+}
+
 func NewMongoRepository(uri, dbName, collectionName string) (*MongoRepository, error) {
-	clientOptions := options.Client().ApplyURI(uri)
-	client, err := mongo.Connect(context.Background(), clientOptions)
+	client, err := connectMongo(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := client.Ping(context.Background(), nil); err != nil {
-		return nil, err
-	}
-
 	collection := client.Database(dbName).Collection(collectionName)
 
 	// Cria índice único no campo hash para garantir unicidade
@@ -177,39 +355,114 @@ func NewMongoRepository(uri, dbName, collectionName string) (*MongoRepository, e
 		log.Printf("Unique index created on hash field")
 	}
 
-	return &MongoRepository{client: client, collection: collection}, nil
+	// Índices usados pelos filtros de busca (comodidades e faixas numéricas)
+	searchIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "amenidades", Value: 1}}},
+		{Keys: bson.D{{Key: "quartos", Value: 1}}},
+		{Keys: bson.D{{Key: "banheiros", Value: 1}}},
+		{Keys: bson.D{{Key: "area_total", Value: 1}}},
+		{Keys: bson.D{{Key: "valor", Value: -1}}},
+		{Keys: bson.D{{Key: "fotos", Value: 1}}},
+		{Keys: bson.D{{Key: "url", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "short_id", Value: 1}}},
+	}
+	if _, err := collection.Indexes().CreateMany(context.Background(), searchIndexes); err != nil {
+		log.Printf("Warning: Failed to create search indexes: %v", err)
+	}
+
+	// Listagem dos leads de WhatsApp por domínio, os vistos mais recentemente primeiro
+	leadIndex := mongo.IndexModel{Keys: bson.D{{Key: "domain", Value: 1}, {Key: "last_seen_at", Value: -1}}}
+	if _, err := client.Database(dbName).Collection(WhatsAppLeadCollection).Indexes().CreateOne(context.Background(), leadIndex); err != nil {
+		log.Printf("Warning: Failed to create whatsapp lead indexes: %v", err)
+	}
+
+	return &MongoRepository{client: client, collection: collection, priceMedians: newPriceMedianCache()}, nil
 }
 
 func (r *MongoRepository) Save(ctx context.Context, property Property) error {
 	// Normaliza a URL antes de salvar
 	property.URL = normalizeURL(property.URL)
+	if property.ShortID == "" {
+		property.ShortID = GenerateShortID(property)
+	}
+
+	// Campos corrigidos manualmente prevalecem sobre os valores extraídos
+	corrected, err := r.findCorrectedProperty(ctx, property)
+	if err != nil {
+		return err
+	}
+	if corrected != nil {
+		ApplyManualCorrections(&property, *corrected)
+	}
 
 	// Gera hash único para o imóvel (baseado no conteúdo, não na URL)
 	property.Hash = GeneratePropertyHash(property)
 
+	// Normaliza as comodidades, o tipo e o preço por m² para permitir filtros indexados
+	ApplyDerivedFields(&property)
+	r.applyExchangeRates(ctx, &property)
+	r.applyScamScore(ctx, &property)
+
+	if property.Source == "" {
+		property.Source = SourceCrawler
+	}
+	property.SchemaVersion = CurrentSchemaVersion()
+
+	// Calcula a confiança dos campos que ainda não foram avaliados
+	ScoreFieldConfidence(&property)
+
 	// Verifica se já existe um imóvel com o mesmo hash
 	var existingProperty Property
-	err := r.collection.FindOne(ctx, bson.M{"hash": property.Hash}).Decode(&existingProperty)
+	duplicate := false
+	err = withStorageRetry(ctx, "error checking for existing property", func() error {
+		err := r.collection.FindOne(ctx, bson.M{"hash": property.Hash}).Decode(&existingProperty)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		duplicate = err == nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
 
-	if err == nil {
+	// As tags são acumuladas: cada job que encontra o imóvel acrescenta as suas
+	tags := property.Tags
+	property.Tags = nil
+	filter := bson.M{"hash": property.Hash}
+
+	if duplicate {
 		// Imóvel já existe - apenas atualiza a URL se for diferente (mantém a primeira encontrada)
 		if existingProperty.URL != property.URL {
 			log.Printf("Imóvel duplicado detectado - Hash: %s, URL original: %s, URL duplicada: %s",
 				property.Hash, existingProperty.URL, property.URL)
 		}
-		return nil // Não salva duplicata
-	} else if err != mongo.ErrNoDocuments {
-		return fmt.Errorf("error checking for existing property: %v", err)
+		update := duplicateUpdate(existingProperty, property, tags)
+		if len(update) == 0 {
+			return nil // Não salva duplicata
+		}
+		return withStorageRetry(ctx, "failed to update duplicate property", func() error {
+			_, err := r.collection.UpdateOne(ctx, filter, update)
+			return err
+		})
 	}
 
 	// Usa upsert para evitar duplicatas baseado no hash
-	filter := bson.M{"hash": property.Hash}
 	update := bson.M{"$set": property}
+	if len(tags) > 0 {
+		update["$addToSet"] = bson.M{"tags": bson.M{"$each": tags}}
+	}
 
 	opts := options.Update().SetUpsert(true)
-	result, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	var result *mongo.UpdateResult
+	err = withStorageRetry(ctx, "failed to save property", func() error {
+		var err error
+		result, err = r.collection.UpdateOne(ctx, filter, update, opts)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save property: %v", err)
+		return err
 	}
 
 	if result.UpsertedCount > 0 {
@@ -221,6 +474,49 @@ func (r *MongoRepository) Save(ctx context.Context, property Property) error {
 	return nil
 }
 
+// duplicateUpdate monta a atualização do imóvel já salvo com o mesmo hash: acrescenta as tags do
+// job e completa a tradução e a avaliação de condição que o registro ainda não tem (a descrição
+// faz parte do hash, então elas valem para ele). Vazio quando não há nada a gravar
+func duplicateUpdate(existing, property Property, tags []string) bson.M {
+	set := bson.M{}
+	if existing.DescricaoEN == "" && property.DescricaoEN != "" {
+		set["descricao_en"] = property.DescricaoEN
+	}
+	if existing.Condicao == "" && property.Condicao != "" {
+		set["condicao"] = property.Condicao
+		set["condicao_nivel"] = property.CondicaoNivel
+		set["condicao_confianca"] = property.CondicaoConfianca
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(tags) > 0 {
+		update["$addToSet"] = bson.M{"tags": bson.M{"$each": tags}}
+	}
+	return update
+}
+
+// SetExchangeRates ativa a conversão do preço para outras moedas no momento em que o imóvel é salvo
+func (r *MongoRepository) SetExchangeRates(rates *DailyExchangeRates) {
+	r.exchangeRates = rates
+}
+
+// applyExchangeRates converte o preço com as cotações do dia; sem cotações o imóvel é salvo
+// normalmente, apenas sem os valores convertidos
+func (r *MongoRepository) applyExchangeRates(ctx context.Context, property *Property) {
+	if r.exchangeRates == nil || property.Valor <= 0 {
+		return
+	}
+	rates, err := r.exchangeRates.Rates(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to load exchange rates: %v", err)
+		return
+	}
+	ApplyExchangeRates(property, rates)
+}
+
 func (r *MongoRepository) FindAll(ctx context.Context) ([]Property, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{})
 	if err != nil {
@@ -228,8 +524,8 @@ func (r *MongoRepository) FindAll(ctx context.Context) ([]Property, error) {
 	}
 	defer cursor.Close(ctx)
 
-	var properties []Property
-	if err := cursor.All(ctx, &properties); err != nil {
+	properties, err := r.decodeProperties(ctx, cursor)
+	if err != nil {
 		return nil, fmt.Errorf("failed to decode properties: %v", err)
 	}
 
@@ -237,7 +533,67 @@ func (r *MongoRepository) FindAll(ctx context.Context) ([]Property, error) {
 }
 
 func (r *MongoRepository) FindWithFilters(ctx context.Context, filter PropertyFilter, pagination PaginationParams) (*PropertySearchResult, error) {
-	// Construir o filtro MongoDB
+	mongoFilter := propertySearchFilter(filter)
+
+	// Contar total de documentos
+	totalItems, err := r.collection.CountDocuments(ctx, mongoFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count properties: %v", err)
+	}
+
+	// Calcular paginação
+	if pagination.PageSize <= 0 {
+		pagination.PageSize = 10 // Padrão
+	}
+	if pagination.Page <= 0 {
+		pagination.Page = 1 // Padrão
+	}
+
+	totalPages := int((totalItems + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+	skip := (pagination.Page - 1) * pagination.PageSize
+
+	// Configurar opções de busca
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetLimit(int64(pagination.PageSize))
+
+	// Ordenação inteligente: se há busca por query, ordenar por relevância, senão por valor
+	if filter.Query != "" {
+		// Para busca por query, ordenar por valor decrescente (pode ser melhorado com score de relevância)
+		findOptions.SetSort(bson.D{{Key: "valor", Value: -1}})
+	} else {
+		// Para filtros normais, ordenar por valor decrescente
+		findOptions.SetSort(bson.D{{Key: "valor", Value: -1}})
+	}
+
+	// Executar busca
+	cursor, err := r.collection.Find(ctx, mongoFilter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find properties: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	properties, err := r.decodeProperties(ctx, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode properties: %v", err)
+	}
+
+	// Se há busca por query, calcular e ordenar por relevância
+	if filter.Query != "" && len(properties) > 0 {
+		properties = r.sortByRelevance(properties, filter.Query)
+	}
+
+	return &PropertySearchResult{
+		Properties:  properties,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: pagination.Page,
+		PageSize:    pagination.PageSize,
+	}, nil
+}
+
+// propertySearchFilter monta o filtro MongoDB da busca de imóveis
+func propertySearchFilter(filter PropertyFilter) bson.M {
 	mongoFilter := bson.M{}
 
 	// Busca inteligente por palavras-chave (filtro 'q')
@@ -272,9 +628,13 @@ func (r *MongoRepository) FindWithFilters(ctx context.Context, filter PropertyFi
 		mongoFilter["bairro"] = bson.M{"$regex": normalizedBairro, "$options": "i"}
 	}
 
+	// O tipo casa tanto com o valor do site ("Sobrado") quanto com a taxonomia ("Casa")
 	if filter.TipoImovel != "" {
-		normalizedTipo := utils.NormalizeText(filter.TipoImovel)
-		mongoFilter["tipo_imovel"] = bson.M{"$regex": normalizedTipo, "$options": "i"}
+		tipoRegex := bson.M{"$regex": utils.NormalizeText(filter.TipoImovel), "$options": "i"}
+		mongoFilter["$and"] = []bson.M{{"$or": []bson.M{
+			{"tipo_imovel": tipoRegex},
+			{"tipo_normalizado": tipoRegex},
+		}}}
 	}
 
 	// Filtros de valor
@@ -325,61 +685,86 @@ func (r *MongoRepository) FindWithFilters(ctx context.Context, filter PropertyFi
 		mongoFilter["area_total"] = areaFilter
 	}
 
-	// Contar total de documentos
-	totalItems, err := r.collection.CountDocuments(ctx, mongoFilter)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count properties: %v", err)
+	// Filtros de condição (rótulo exato ou nível mínimo)
+	if filter.Condicao != "" {
+		mongoFilter["condicao"] = NormalizeCondition(filter.Condicao)
 	}
-
-	// Calcular paginação
-	if pagination.PageSize <= 0 {
-		pagination.PageSize = 10 // Padrão
+	if filter.CondicaoMin > 0 {
+		mongoFilter["condicao_nivel"] = bson.M{"$gte": filter.CondicaoMin}
 	}
-	if pagination.Page <= 0 {
-		pagination.Page = 1 // Padrão
+
+	// Filtro de comodidades: o imóvel precisa ter todas as informadas
+	if amenities := NormalizeAmenities(filter.Caracteristicas); len(amenities) > 0 {
+		mongoFilter["amenidades"] = bson.M{"$all": amenities}
 	}
 
-	totalPages := int((totalItems + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
-	skip := (pagination.Page - 1) * pagination.PageSize
+	// Filtros de fotos (com_fotos equivale a pelo menos uma foto)
+	if filter.ComFotos && filter.FotosMin < 1 {
+		filter.FotosMin = 1
+	}
+	if filter.FotosMin > 0 {
+		mongoFilter["fotos"] = bson.M{"$gte": filter.FotosMin}
+	}
 
-	// Configurar opções de busca
-	findOptions := options.Find()
-	findOptions.SetSkip(int64(skip))
-	findOptions.SetLimit(int64(pagination.PageSize))
+	// Filtros de recursos de infraestrutura
+	if filter.EnergiaSolar {
+		mongoFilter["energia_solar"] = true
+	}
+	if filter.PocoArtesiano {
+		mongoFilter["poco_artesiano"] = true
+	}
+	if filter.AquecimentoGas {
+		mongoFilter["aquecimento_gas"] = true
+	}
 
-	// Ordenação inteligente: se há busca por query, ordenar por relevância, senão por valor
-	if filter.Query != "" {
-		// Para busca por query, ordenar por valor decrescente (pode ser melhorado com score de relevância)
-		findOptions.SetSort(bson.D{{Key: "valor", Value: -1}})
-	} else {
-		// Para filtros normais, ordenar por valor decrescente
-		findOptions.SetSort(bson.D{{Key: "valor", Value: -1}})
+	// Filtros de finalidade e aluguel mensal
+	if filter.Finalidade != "" {
+		mongoFilter["finalidade"] = strings.ToLower(filter.Finalidade)
+	}
+	if filter.AluguelMin > 0 || filter.AluguelMax > 0 || filter.ApenasAluguel {
+		aluguelFilter := bson.M{"$gt": 0}
+		if filter.AluguelMin > 0 {
+			aluguelFilter["$gte"] = filter.AluguelMin
+		}
+		if filter.AluguelMax > 0 {
+			aluguelFilter["$lte"] = filter.AluguelMax
+		}
+		mongoFilter["valor_aluguel"] = aluguelFilter
 	}
 
-	// Executar busca
-	cursor, err := r.collection.Find(ctx, mongoFilter, findOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find properties: %v", err)
+	// Filtro de leilões (documentos antigos sem o campo contam como não-leilão)
+	if filter.Leilao != nil {
+		if *filter.Leilao {
+			mongoFilter["leilao"] = true
+		} else {
+			mongoFilter["leilao"] = bson.M{"$ne": true}
+		}
 	}
-	defer cursor.Close(ctx)
 
-	var properties []Property
-	if err := cursor.All(ctx, &properties); err != nil {
-		return nil, fmt.Errorf("failed to decode properties: %v", err)
+	// Filtros de golpe (documentos sem os campos contam como não suspeitos)
+	if filter.OcultarSuspeitos {
+		mongoFilter["suspeito_golpe"] = bson.M{"$ne": true}
+		mongoFilter["suspeito_fraude"] = bson.M{"$ne": true}
+	}
+	if filter.RiscoGolpeMax != nil {
+		mongoFilter["risco_golpe"] = bson.M{"$not": bson.M{"$gt": *filter.RiscoGolpeMax}}
+	}
+	if len(filter.Tags) > 0 {
+		mongoFilter["tags"] = bson.M{"$all": filter.Tags}
 	}
 
-	// Se há busca por query, calcular e ordenar por relevância
-	if filter.Query != "" && len(properties) > 0 {
-		properties = r.sortByRelevance(properties, filter.Query)
+	// Distâncias até pontos de interesse
+	if filter.MaxDistCentroKm > 0 {
+		mongoFilter["distancias_poi.centro_km"] = bson.M{"$lte": filter.MaxDistCentroKm}
+	}
+	if filter.MaxDistEscolaKm > 0 {
+		mongoFilter["distancias_poi.escola_km"] = bson.M{"$lte": filter.MaxDistEscolaKm}
+	}
+	if filter.MaxDistHospitalKm > 0 {
+		mongoFilter["distancias_poi.hospital_km"] = bson.M{"$lte": filter.MaxDistHospitalKm}
 	}
 
-	return &PropertySearchResult{
-		Properties:  properties,
-		TotalItems:  totalItems,
-		TotalPages:  totalPages,
-		CurrentPage: pagination.Page,
-		PageSize:    pagination.PageSize,
-	}, nil
+	return mongoFilter
 }
 
 // sortByRelevance ordena propriedades por relevância da busca
@@ -430,6 +815,44 @@ func (r *MongoRepository) sortByRelevance(properties []Property, query string) [
 	return result
 }
 
+// BackfillAmenities preenche o campo amenidades dos imóveis salvos antes da normalização. Só lê
+// documentos sem o campo: os sem comodidades recebem null e não são relidos na próxima execução
+func (r *MongoRepository) BackfillAmenities(ctx context.Context) (int, error) {
+	filter := bson.M{"amenidades": bson.M{"$exists": false}}
+	projection := options.Find().SetProjection(bson.M{"caracteristicas": 1})
+
+	cursor, err := r.collection.Find(ctx, filter, projection)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find properties without amenities: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		// _id decodificado sem conversão (pode ser ObjectID ou string)
+		var doc struct {
+			ID              interface{} `bson:"_id"`
+			Caracteristicas []string    `bson:"caracteristicas"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, fmt.Errorf("failed to decode property: %v", err)
+		}
+
+		_, err := r.collection.UpdateByID(ctx, doc.ID, amenitiesUpdate(doc.Caracteristicas))
+		if err != nil {
+			return updated, fmt.Errorf("failed to update amenities: %v", err)
+		}
+		updated++
+	}
+
+	return updated, cursor.Err()
+}
+
+// amenitiesUpdate sobrescreve amenidades com as comodidades normalizadas (null quando não há)
+func amenitiesUpdate(caracteristicas []string) bson.M {
+	return bson.M{"$set": bson.M{"amenidades": NormalizeAmenities(caracteristicas)}}
+}
+
 func (r *MongoRepository) ClearAll(ctx context.Context) error {
 	_, err := r.collection.DeleteMany(ctx, bson.M{})
 	if err != nil {
@@ -441,6 +864,6 @@ func (r *MongoRepository) ClearAll(ctx context.Context) error {
 
 func (r *MongoRepository) Close() {
 	if err := r.client.Disconnect(context.Background()); err != nil {
-		log.Fatal(err)
+		log.Printf("Error disconnecting from MongoDB: %v", err)
 	}
 }
@@ -1,12 +1,27 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -335,6 +350,696 @@ func TestProperty_Validation(t *testing.T) {
 	assert.NotEmpty(t, property.URL)
 }
 
+func TestNormalizeCondition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		level    int
+	}{
+		{"novo", CondicaoNovo, 4},
+		{"Reformado", CondicaoReformado, 3},
+		{"precisa_reforma", CondicaoPrecisaReforma, 1},
+		{"Precisa de reforma", CondicaoPrecisaReforma, 1},
+		{"usado", CondicaoUsado, 2},
+		{"renovado", CondicaoReformado, 3},
+		{"Casa renovada", CondicaoReformado, 3},
+		{"Apartamento novo", CondicaoNovo, 4},
+		{"desconhecido", "", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeCondition(tt.input))
+			assert.Equal(t, tt.level, ConditionLevel(tt.input))
+		})
+	}
+}
+
+func TestNormalizeAmenities(t *testing.T) {
+	amenities := NormalizeAmenities([]string{"Piscina aquecida", "2 vagas de garagem", "Área gourmet com churrasqueira", "piscina", "Vista para o mar"})
+	assert.Equal(t, []string{"area_gourmet", "churrasqueira", "garagem", "piscina"}, amenities)
+	assert.Nil(t, NormalizeAmenities([]string{"Vista para o mar"}))
+
+	assert.Equal(t, "area_gourmet", NormalizeAmenity("area_gourmet"))
+	assert.Equal(t, "garagem", NormalizeAmenity("Estacionamento"))
+	assert.Equal(t, "", NormalizeAmenity("heliponto"))
+
+	filter, unknown := ParseAmenityFilter("piscina, Garagem,,heliponto")
+	assert.Equal(t, []string{"garagem", "piscina"}, filter)
+	assert.Equal(t, []string{"heliponto"}, unknown)
+}
+
+func TestBuildPropertyFacets(t *testing.T) {
+	facets := buildPropertyFacets(facetAggregation{
+		Total:       []facetBucket{{Count: 7}},
+		Bairros:     []facetBucket{{ID: "Centro", Count: 2}, {ID: "Jardim", Count: 4}, {ID: "Alto", Count: 2}},
+		TiposImovel: []facetBucket{{ID: "Casa", Count: 5}, {ID: "Terreno", Count: 2}},
+		FaixasPreco: []facetBucket{{ID: float64(200000), Count: 3}, {ID: int32(0), Count: 1}, {ID: "acima", Count: 2}},
+		Quartos:     []facetBucket{{ID: int64(3), Count: 4}, {ID: int32(5), Count: 1}, {ID: int32(2), Count: 2}},
+	})
+
+	assert.Equal(t, int64(7), facets.Total)
+	assert.Equal(t, []FacetCount{{Value: "Jardim", Count: 4}, {Value: "Alto", Count: 2}, {Value: "Centro", Count: 2}}, facets.Bairros)
+	assert.Equal(t, "Casa", facets.TiposImovel[0].Value)
+
+	assert.Len(t, facets.FaixasPreco, len(priceBandBoundaries))
+	assert.Equal(t, FacetCount{Value: "0-200000", Count: 1, Min: 0, Max: 200000}, facets.FaixasPreco[0])
+	assert.Equal(t, int64(3), facets.FaixasPreco[1].Count)
+	assert.Equal(t, int64(0), facets.FaixasPreco[2].Count)
+	assert.Equal(t, FacetCount{Value: "2000000+", Count: 2, Min: 2000000}, facets.FaixasPreco[len(facets.FaixasPreco)-1])
+
+	assert.Equal(t, []string{"2", "3", "5+"}, []string{facets.Quartos[0].Value, facets.Quartos[1].Value, facets.Quartos[2].Value})
+}
+
+func TestBuildQualityMetrics(t *testing.T) {
+	computedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	samples := []qualitySample{
+		{URL: "https://www.imobiliaria.com.br/imovel/1", Valor: 300000, Endereco: "Rua A, 10", Quartos: 3, Fotos: 5,
+			FieldConfidence: map[string]float64{"valor": 0.9, "endereco": 0.7}},
+		{URL: "https://imobiliaria.com.br/imovel/2", Valor: 250000, Fotos: 2},
+		{URL: "https://imobiliaria.com.br/imovel/3", Quartos: 2, FieldConfidence: map[string]float64{"quartos": 0.6}},
+		{URL: "https://outra.com.br/imovel/1", Valor: 100000, Endereco: "Rua B", Quartos: 1, Fotos: 1},
+		{URL: ""},
+	}
+
+	metrics := buildQualityMetrics(samples, "2026-10-01", computedAt)
+	if !assert.Len(t, metrics, 2) {
+		return
+	}
+	first := metrics[0]
+	assert.Equal(t, "imobiliaria.com.br", first.Domain)
+	assert.Equal(t, 3, first.Properties)
+	assert.Equal(t, 66.7, first.WithPricePct)
+	assert.Equal(t, 33.3, first.WithAddressPct)
+	assert.Equal(t, 66.7, first.WithRoomsPct)
+	assert.Equal(t, 66.7, first.WithPhotosPct)
+	assert.Equal(t, 2, first.ScoredProperties)
+	assert.Equal(t, 0.7, first.AvgExtractionScore)
+	assert.Equal(t, "2026-10-01", first.Day)
+
+	// Sem field_confidence a nota fica zerada
+	assert.Equal(t, 100.0, metrics[1].WithPricePct)
+	assert.Equal(t, 0, metrics[1].ScoredProperties)
+	assert.Zero(t, metrics[1].AvgExtractionScore)
+
+	// A métrica mais recente de cada domínio resume a série
+	series := append(buildQualityMetrics(samples[:1], "2026-09-30", computedAt), metrics...)
+	latest := LatestQualityMetrics(series)
+	if assert.Len(t, latest, 2) {
+		assert.Equal(t, "2026-10-01", latest[0].Day)
+		assert.Equal(t, 3, latest[0].Properties)
+	}
+}
+
+func TestBuildBairroBoundaries(t *testing.T) {
+	point := func(bairro string, latitude, longitude float64) bairroSample {
+		return bairroSample{Cidade: "Muzambinho", Bairro: bairro, Location: NewGeoPoint(latitude, longitude)}
+	}
+	samples := []bairroSample{
+		// Centro: quatro anúncios em quadrado, um ponto interno e uma geocodificação errada distante
+		point("Centro", -21.370, -46.530),
+		point("Centro", -21.370, -46.520),
+		point("centro", -21.380, -46.520),
+		point("Centro", -21.380, -46.530),
+		point("Centro", -21.375, -46.525),
+		point("Centro", -21.900, -46.900),
+		// Jardim América: todos no mesmo ponto (geocodificados pelo bairro)
+		point("Jardim América", -21.360, -46.540),
+		point("Jardim America", -21.360, -46.540),
+		point("Jardim América", -21.360, -46.540),
+		// Vila Nova: poucos anúncios
+		point("Vila Nova", -21.390, -46.510),
+		{Cidade: "Muzambinho", Bairro: "Sem Local"},
+	}
+
+	collection := buildBairroBoundaries(samples, 3)
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	if !assert.Len(t, collection.Features, 2) {
+		return
+	}
+
+	centro := collection.Features[0]
+	assert.Equal(t, "Centro", centro.Properties["bairro"])
+	assert.Equal(t, BairroMethodHull, centro.Properties["method"])
+	assert.Equal(t, 5, centro.Properties["listings"])
+	assert.Equal(t, 1, centro.Properties["outliers"])
+	ring := centro.Geometry.Coordinates[0]
+	assert.Equal(t, "Polygon", centro.Geometry.Type)
+	assert.Len(t, ring, 5, "quatro vértices da envoltória e o primeiro repetido")
+	assert.Equal(t, ring[0], ring[len(ring)-1])
+	assert.Equal(t, []float64{-46.53, -21.38}, ring[0])
+
+	jardim := collection.Features[1]
+	assert.Equal(t, "Jardim América", jardim.Properties["bairro"])
+	assert.Equal(t, BairroMethodRadius, jardim.Properties["method"])
+	assert.Len(t, jardim.Geometry.Coordinates[0], bairroCircleVertices+1)
+
+	// Sem anúncios suficientes a coleção fica vazia (mas válida)
+	empty := buildBairroBoundaries(samples, 10)
+	assert.NotNil(t, empty.Features)
+	assert.Empty(t, empty.Features)
+}
+
+func TestBuildPriceIndex(t *testing.T) {
+	computedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	sale := func(cidade, bairro string, valor, area float64) priceSample {
+		return priceSample{Cidade: cidade, Bairro: bairro, Valor: valor, ValorVenda: valor, Area: area}
+	}
+	samples := []priceSample{
+		sale("São Paulo", "Centro", 300000, 100),
+		sale("Sao Paulo", "centro", 500000, 100),
+		sale("SÃO PAULO", "Centro", 400000, 100),
+		sale("São Paulo", "Jardim", 800000, 100),
+		sale("São Paulo", "Centro", 1000, 100),    // R$/m² abaixo da faixa
+		sale("São Paulo", "Centro", 300000, 5),    // área inválida
+		sale("", "Centro", 300000, 100),           // sem cidade
+		sale("Muzambinho", "Centro", 200000, 100), // poucas amostras
+		// Aluguéis ficam de fora do índice de venda
+		{Cidade: "São Paulo", Bairro: "Centro", Valor: 15000, ValorAluguel: 15000, Area: 100},
+		{Cidade: "São Paulo", Bairro: "Centro", Valor: 600000, ValorVenda: 600000, ValorAluguel: 3000, Area: 100},
+		{Cidade: "São Paulo", Bairro: "Centro", Valor: 250000, Area: 100}, // sem valor de venda
+	}
+
+	points := buildPriceIndex(samples, "2026-10", computedAt)
+	assert.Len(t, points, 2)
+
+	city := points[0]
+	assert.Equal(t, "sao paulo", city.CityKey)
+	assert.Empty(t, city.BairroKey)
+	assert.Equal(t, 4, city.Samples)
+	assert.Equal(t, 4500.0, city.MedianPricePerM2)
+	assert.Equal(t, "2026-10", city.Month)
+	assert.Equal(t, computedAt, city.ComputedAt)
+
+	centro := points[1]
+	assert.Equal(t, "centro", centro.BairroKey)
+	assert.Equal(t, 3, centro.Samples)
+	assert.Equal(t, 4000.0, centro.MedianPricePerM2)
+
+	assert.Equal(t, 0.0, median(nil))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+}
+
+func TestFieldConfidence(t *testing.T) {
+	property := Property{Valor: 450000, AreaTotal: 5, Quartos: 3, Bairro: ""}
+	property.SetProvenance("valor", ExtractorGenericSelector, ".preco")
+	property.SetProvenance("area_total", ExtractorRegexFallback, "")
+	property.SetProvenance("quartos", ExtractorDomainSelector, ".dorms")
+
+	ScoreFieldConfidence(&property)
+	assert.Equal(t, 0.8, property.FieldConfidence["valor"])
+	assert.Equal(t, 0.25, property.FieldConfidence["area_total"]) // área implausível
+	assert.Equal(t, 0.95, property.FieldConfidence["quartos"])
+
+	// IA corrige a área e mantém o valor: área assume a confiança da IA, valor recebe bônus
+	reviewed := property
+	reviewed.AreaTotal = 250
+	reviewed.Bairro = "Centro"
+	changed := RecordAIReview(property, &reviewed)
+
+	assert.ElementsMatch(t, []string{"area_total", "bairro"}, changed)
+	assert.Equal(t, 0.85, reviewed.FieldConfidence["area_total"])
+	assert.Equal(t, 0.85, reviewed.FieldConfidence["bairro"])
+	assert.Equal(t, 0.9, reviewed.FieldConfidence["valor"])
+	assert.Equal(t, 1.0, reviewed.FieldConfidence["quartos"])
+	assert.Equal(t, 0.25, property.FieldConfidence["area_total"])
+}
+
+func TestBsonInt64(t *testing.T) {
+	assert.Equal(t, int64(42), bsonInt64(int32(42)))
+	assert.Equal(t, int64(1<<40), bsonInt64(int64(1<<40)))
+	assert.Equal(t, int64(2048), bsonInt64(float64(2048)))
+	assert.Equal(t, int64(0), bsonInt64("unexpected"))
+	assert.Equal(t, int64(0), bsonInt64(nil))
+}
+
+func TestGenerateContentSignature(t *testing.T) {
+	description := "Casa ampla com 3 quartos, suíte, piscina e área gourmet em condomínio fechado próximo ao centro"
+	page := func(stamp, price, desc string) ContentSignature {
+		text := "Imobiliária Exemplo - Casa à venda no Jardim América. " + desc +
+			" Valor " + price + ". Atualizado em " + stamp + " às 10:32. 154 visualizações. " +
+			"Fale com um corretor pelo WhatsApp e agende sua visita ainda hoje."
+		return GenerateContentSignature(text, []string{price}, []string{"Rua das Flores, 123"}, []string{desc})
+	}
+
+	original := page("10/10/2026", "R$ 450.000", description)
+
+	t.Run("date stamps are noise", func(t *testing.T) {
+		later := page("16/10/2026", "R$ 450.000", description)
+		assert.Equal(t, original.PriceHash, later.PriceHash)
+		assert.Equal(t, original.ContentHash, later.ContentHash)
+		assert.LessOrEqual(t, SimHashDistance(original.SimHash, later.SimHash), DefaultSimHashThreshold)
+	})
+
+	t.Run("price edits change the price hash", func(t *testing.T) {
+		edited := page("10/10/2026", "R$ 430.000", description)
+		assert.NotEqual(t, original.PriceHash, edited.PriceHash)
+	})
+
+	t.Run("description edits move the simhash", func(t *testing.T) {
+		edited := page("10/10/2026", "R$ 450.000", "Apartamento compacto de 1 quarto reformado, sem vaga, ideal para estudantes perto da universidade")
+		assert.Greater(t, SimHashDistance(original.SimHash, edited.SimHash), DefaultSimHashThreshold)
+	})
+
+	t.Run("room count edits change the content hash", func(t *testing.T) {
+		edited := page("10/10/2026", "R$ 450.000", strings.Replace(description, "3 quartos", "4 quartos", 1))
+		assert.Equal(t, original.PriceHash, edited.PriceHash)
+		assert.NotEqual(t, original.ContentHash, edited.ContentHash)
+	})
+
+	t.Run("invalid simhash", func(t *testing.T) {
+		assert.Equal(t, -1, SimHashDistance("", original.SimHash))
+		assert.Equal(t, 0, HammingDistance(42, 42))
+		assert.Equal(t, 64, HammingDistance(0, ^uint64(0)))
+	})
+}
+
+func TestClassifyPropertyChange(t *testing.T) {
+	stored := Property{
+		Endereco:   "Rua das Flores, 123",
+		Descricao:  "Casa com 3 quartos, suíte, piscina e área gourmet em condomínio fechado",
+		Valor:      450000,
+		ValorTexto: "R$ 450.000",
+		Quartos:    3,
+		Banheiros:  2,
+		AreaTotal:  180,
+		TipoImovel: "Casa",
+		Fotos:      12,
+	}
+
+	t.Run("cosmetic", func(t *testing.T) {
+		current := stored
+		current.Descricao = "Casa com 3 quartos, suíte, piscina e área gourmet em condomínio fechado."
+		change := ClassifyPropertyChange(stored, current)
+		assert.Equal(t, []string{ChangeCosmetic}, change.Kinds)
+		assert.False(t, change.Material)
+	})
+
+	t.Run("price", func(t *testing.T) {
+		current := stored
+		current.Valor = 430000
+		change := ClassifyPropertyChange(stored, current)
+		assert.Equal(t, []string{ChangePrice}, change.Kinds)
+		assert.True(t, change.Material)
+		assert.Equal(t, 450000.0, change.PreviousValor)
+		assert.Equal(t, 430000.0, change.CurrentValor)
+	})
+
+	t.Run("photos only is not material", func(t *testing.T) {
+		current := stored
+		current.Fotos = 15
+		change := ClassifyPropertyChange(stored, current)
+		assert.Equal(t, []string{ChangePhotos}, change.Kinds)
+		assert.False(t, change.Material)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		current := stored
+		current.ValorTexto = "VENDIDO"
+		change := ClassifyPropertyChange(stored, current)
+		assert.Contains(t, change.Kinds, ChangeStatus)
+		assert.Equal(t, "disponivel", change.PreviousStatus)
+		assert.Equal(t, "vendido", change.CurrentStatus)
+		assert.True(t, change.Material)
+	})
+
+	t.Run("description rewrite", func(t *testing.T) {
+		current := stored
+		current.Descricao = "Oportunidade única: residência reformada, próxima a escolas e comércio, aceita financiamento"
+		change := ClassifyPropertyChange(stored, current)
+		assert.Equal(t, []string{ChangeDescription}, change.Kinds)
+		assert.True(t, change.Material)
+	})
+
+	t.Run("stored extraction", func(t *testing.T) {
+		// O registro salvo foi corrigido pela IA; a extração nova é igual à anterior
+		extraction := NewExtractionSnapshot(stored)
+		corrected := stored
+		corrected.Endereco = "Rua das Flores, 123 - Centro, Muzambinho/MG"
+		corrected.TipoImovel = "Sobrado"
+		assert.True(t, ClassifyPropertyChange(corrected, stored).Material)
+
+		change := ClassifyPropertyChange(extraction.Property(), stored)
+		assert.Equal(t, []string{ChangeCosmetic}, change.Kinds)
+		assert.False(t, change.Material)
+	})
+}
+
+func TestIsStorageUnavailable(t *testing.T) {
+	wrapped := fmt.Errorf("failed to create MongoDB repository: %w",
+		storageUnavailable("failed to ping MongoDB", context.DeadlineExceeded))
+
+	assert.True(t, IsStorageUnavailable(wrapped))
+	assert.True(t, errors.Is(wrapped, ErrStorageUnavailable))
+	assert.True(t, errors.Is(wrapped, context.DeadlineExceeded))
+	assert.True(t, IsStorageUnavailable(mongo.ErrClientDisconnected))
+	assert.False(t, IsStorageUnavailable(errors.New("validation failed")))
+	assert.False(t, IsStorageUnavailable(nil))
+}
+
+func TestMongoClientOptions(t *testing.T) {
+	t.Run("Validation", func(t *testing.T) {
+		assert.NoError(t, DefaultMongoClientOptions().Validate())
+		assert.NoError(t, MongoClientOptions{ReadConcern: "majority", WriteConcern: "majority"}.Validate())
+		assert.NoError(t, MongoClientOptions{WriteConcern: "2", WriteTimeout: time.Second}.Validate())
+		assert.Error(t, MongoClientOptions{MinPoolSize: 20, MaxPoolSize: 10}.Validate())
+		assert.Error(t, MongoClientOptions{ReadConcern: "strong"}.Validate())
+		assert.Error(t, MongoClientOptions{WriteConcern: "all"}.Validate())
+		assert.Error(t, MongoClientOptions{ConnectTimeout: -time.Second}.Validate())
+		assert.Error(t, SetMongoClientOptions(MongoClientOptions{OperationRetries: -1}))
+	})
+
+	t.Run("ClientOptions", func(t *testing.T) {
+		opts := MongoClientOptions{
+			MaxPoolSize:            50,
+			ServerSelectionTimeout: 5 * time.Second,
+			RetryWrites:            true,
+			ReadConcern:            "majority",
+			WriteConcern:           "1",
+		}
+		clientOptions := opts.clientOptions("mongodb://localhost:27017/?maxPoolSize=10&connectTimeoutMS=2000")
+
+		assert.Equal(t, uint64(50), *clientOptions.MaxPoolSize)
+		assert.Equal(t, 2*time.Second, *clientOptions.ConnectTimeout) // mantido da URI
+		assert.Equal(t, 5*time.Second, *clientOptions.ServerSelectionTimeout)
+		assert.True(t, *clientOptions.RetryWrites)
+		assert.False(t, *clientOptions.RetryReads)
+		assert.Equal(t, "majority", clientOptions.ReadConcern.GetLevel())
+		assert.Equal(t, 1, clientOptions.WriteConcern.GetW())
+		assert.NotNil(t, clientOptions.ServerMonitor)
+	})
+
+	t.Run("StorageRetry", func(t *testing.T) {
+		defer SetMongoClientOptions(DefaultMongoClientOptions())
+		assert.NoError(t, SetMongoClientOptions(MongoClientOptions{OperationRetries: 2, RetryBackoff: time.Millisecond}))
+
+		calls := 0
+		err := withStorageRetry(context.Background(), "failed to save property", func() error {
+			calls++
+			if calls < 3 {
+				return mongo.ErrClientDisconnected
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+
+		calls = 0
+		err = withStorageRetry(context.Background(), "failed to save property", func() error {
+			calls++
+			return mongo.ErrClientDisconnected
+		})
+		assert.True(t, errors.Is(err, ErrStorageUnavailable))
+		assert.Equal(t, 3, calls)
+
+		calls = 0
+		err = withStorageRetry(context.Background(), "failed to save property", func() error {
+			calls++
+			return errors.New("document too large")
+		})
+		assert.False(t, IsStorageUnavailable(err))
+		assert.Equal(t, "failed to save property: document too large", err.Error())
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Health", func(t *testing.T) {
+		tracker := newMongoHealthTracker()
+		assert.Equal(t, MongoStatusUnknown, tracker.snapshot().Status)
+
+		tracker.heartbeatSucceeded(serverAddress("db1:27017[-1]"))
+		tracker.heartbeatSucceeded("db2:27017")
+		assert.Equal(t, MongoStatusUp, tracker.snapshot().Status)
+
+		tracker.heartbeatFailed("db1:27017", errors.New("connection refused"))
+		assert.Equal(t, MongoStatusDegraded, tracker.snapshot().Status)
+
+		tracker.heartbeatFailed("db2:27017", errors.New("connection refused"))
+		health := tracker.snapshot()
+		assert.Equal(t, MongoStatusDown, health.Status)
+		assert.NotNil(t, health.DownSince)
+		assert.Equal(t, "connection refused", health.LastError)
+
+		tracker.heartbeatSucceeded(serverAddress("db1:27017[-7]"))
+		health = tracker.snapshot()
+		assert.Equal(t, MongoStatusDegraded, health.Status)
+		assert.Nil(t, health.DownSince)
+		assert.Equal(t, int64(1), health.Reconnects)
+	})
+}
+
+// memoryURLRepository é um URLRepository em memória que conta as consultas recebidas
+type memoryURLRepository struct {
+	urls         map[string]ProcessedURL
+	fingerprints map[string]PageFingerprint
+	lookups      int
+}
+
+func newMemoryURLRepository() *memoryURLRepository {
+	return &memoryURLRepository{urls: map[string]ProcessedURL{}, fingerprints: map[string]PageFingerprint{}}
+}
+
+func (m *memoryURLRepository) SaveProcessedURL(ctx context.Context, url ProcessedURL) error {
+	m.urls[url.URL] = url
+	return nil
+}
+
+func (m *memoryURLRepository) GetProcessedURL(ctx context.Context, url string) (*ProcessedURL, error) {
+	m.lookups++
+	if processed, exists := m.urls[url]; exists {
+		return &processed, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryURLRepository) GetProcessedURLsSince(ctx context.Context, since time.Time) ([]ProcessedURL, error) {
+	var result []ProcessedURL
+	for _, processed := range m.urls {
+		if !processed.ProcessedAt.Before(since) {
+			result = append(result, processed)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryURLRepository) IsURLProcessedRecently(ctx context.Context, url string, maxAge time.Duration) (bool, error) {
+	m.lookups++
+	processed, exists := m.urls[url]
+	return exists && processed.Status == "success" && time.Since(processed.ProcessedAt) < maxAge, nil
+}
+
+func (m *memoryURLRepository) SaveFingerprint(ctx context.Context, fingerprint PageFingerprint) error {
+	m.fingerprints[fingerprint.URL] = fingerprint
+	return nil
+}
+
+func (m *memoryURLRepository) GetFingerprint(ctx context.Context, url string) (*PageFingerprint, error) {
+	m.lookups++
+	if fingerprint, exists := m.fingerprints[url]; exists {
+		return &fingerprint, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryURLRepository) UpdateFingerprint(ctx context.Context, fingerprint PageFingerprint) error {
+	m.fingerprints[fingerprint.URL] = fingerprint
+	return nil
+}
+
+func (m *memoryURLRepository) GetFingerprintsRequiringUpdate(ctx context.Context, maxAge time.Duration) ([]PageFingerprint, error) {
+	return nil, nil
+}
+
+func (m *memoryURLRepository) CleanupOldRecords(ctx context.Context, maxAge time.Duration) error {
+	m.urls = map[string]ProcessedURL{}
+	m.fingerprints = map[string]PageFingerprint{}
+	return nil
+}
+
+func (m *memoryURLRepository) GetStatistics(ctx context.Context) (*URLStatistics, error) {
+	return &URLStatistics{TotalURLs: int64(len(m.urls))}, nil
+}
+
+func (m *memoryURLRepository) Close() {}
+
+func TestCachedURLRepository(t *testing.T) {
+	ctx := context.Background()
+	backend := newMemoryURLRepository()
+	cache, err := NewCachedURLRepository(backend, URLCacheOptions{Dir: t.TempDir(), TTL: time.Hour, SyncInterval: time.Minute})
+	assert.NoError(t, err)
+	defer cache.Close()
+
+	pageURL := "https://imobiliaria.com.br/imovel/1"
+
+	t.Run("CachesMissesAndWritesThrough", func(t *testing.T) {
+		recent, err := cache.IsURLProcessedRecently(ctx, pageURL, time.Hour)
+		assert.NoError(t, err)
+		assert.False(t, recent)
+		recent, _ = cache.IsURLProcessedRecently(ctx, pageURL, time.Hour)
+		assert.False(t, recent)
+		assert.Equal(t, 1, backend.lookups) // a ausência ficou em cache
+
+		assert.NoError(t, cache.SaveProcessedURL(ctx, ProcessedURL{URL: pageURL, ProcessedAt: time.Now(), Status: "success"}))
+		assert.Contains(t, backend.urls, pageURL)
+
+		recent, err = cache.IsURLProcessedRecently(ctx, pageURL, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, recent)
+		assert.Equal(t, 1, backend.lookups)
+	})
+
+	t.Run("Fingerprints", func(t *testing.T) {
+		assert.NoError(t, cache.SaveFingerprint(ctx, PageFingerprint{URL: pageURL, ContentHash: "abc", LastCrawled: time.Now()}))
+		fingerprint, err := cache.GetFingerprint(ctx, pageURL)
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", fingerprint.ContentHash)
+
+		assert.NoError(t, cache.UpdateFingerprint(ctx, PageFingerprint{URL: pageURL, ContentHash: "def"}))
+		fingerprint, _ = cache.GetFingerprint(ctx, pageURL)
+		assert.Equal(t, "def", fingerprint.ContentHash)
+		assert.Equal(t, 1, backend.lookups)
+	})
+
+	t.Run("SyncLoadsExternalWrites", func(t *testing.T) {
+		otherURL := "https://imobiliaria.com.br/imovel/2"
+		_, _ = cache.GetProcessedURL(ctx, otherURL) // ausência em cache
+		backend.urls[otherURL] = ProcessedURL{URL: otherURL, ProcessedAt: time.Now(), Status: "success"}
+
+		synced, err := cache.Sync(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, synced)
+
+		lookups := backend.lookups
+		recent, _ := cache.IsURLProcessedRecently(ctx, otherURL, time.Hour)
+		assert.True(t, recent)
+		assert.Equal(t, lookups, backend.lookups)
+		assert.True(t, cache.Stats().Hits > 0)
+	})
+
+	t.Run("CleanupDropsCache", func(t *testing.T) {
+		assert.NoError(t, cache.CleanupOldRecords(ctx, 0))
+		recent, _ := cache.IsURLProcessedRecently(ctx, pageURL, time.Hour)
+		assert.False(t, recent)
+	})
+
+	_, isMongo := MongoURLBackend(cache)
+	assert.False(t, isMongo)
+}
+
+// trackedURLRepository lista os fingerprints e o registro mais antigo, como o MongoURLRepository
+type trackedURLRepository struct {
+	*memoryURLRepository
+}
+
+func (m trackedURLRepository) GetFingerprintsSince(ctx context.Context, since time.Time) ([]PageFingerprint, error) {
+	var result []PageFingerprint
+	for _, fingerprint := range m.fingerprints {
+		if !fingerprint.LastCrawled.Before(since) {
+			result = append(result, fingerprint)
+		}
+	}
+	return result, nil
+}
+
+func (m trackedURLRepository) OldestRetained(ctx context.Context) (urls, fingerprints *time.Time, err error) {
+	for _, processed := range m.urls {
+		if urls == nil || processed.ProcessedAt.Before(*urls) {
+			at := processed.ProcessedAt
+			urls = &at
+		}
+	}
+	for _, fingerprint := range m.fingerprints {
+		if fingerprints == nil || fingerprint.LastCrawled.Before(*fingerprints) {
+			at := fingerprint.LastCrawled
+			fingerprints = &at
+		}
+	}
+	return urls, fingerprints, nil
+}
+
+func TestCachedURLRepository_TracksOtherInstances(t *testing.T) {
+	ctx := context.Background()
+	backend := trackedURLRepository{newMemoryURLRepository()}
+	cache, err := NewCachedURLRepository(backend, URLCacheOptions{Dir: t.TempDir(), TTL: 24 * time.Hour, SyncInterval: time.Minute})
+	assert.NoError(t, err)
+	defer cache.Close()
+
+	oldURL := "https://imobiliaria.com.br/imovel/1"
+	newURL := "https://imobiliaria.com.br/imovel/2"
+	now := time.Now()
+
+	// Fingerprint gravado por outra instância depois de uma ausência em cache
+	_, _ = cache.GetFingerprint(ctx, newURL)
+	backend.fingerprints[newURL] = PageFingerprint{URL: newURL, ContentHash: "abc", LastCrawled: now}
+	_, err = cache.Sync(ctx)
+	assert.NoError(t, err)
+	lookups := backend.lookups
+	fingerprint, err := cache.GetFingerprint(ctx, newURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", fingerprint.ContentHash)
+	assert.Equal(t, lookups, backend.lookups)
+
+	// Registros removidos pelo TTL ou pela poda no Mongo saem do cache
+	assert.NoError(t, cache.SaveProcessedURL(ctx, ProcessedURL{URL: oldURL, ProcessedAt: now.Add(-2 * time.Hour), Status: "success"}))
+	assert.NoError(t, cache.SaveProcessedURL(ctx, ProcessedURL{URL: newURL, ProcessedAt: now, Status: "success"}))
+	assert.NoError(t, cache.SaveFingerprint(ctx, PageFingerprint{URL: oldURL, LastCrawled: now.Add(-2 * time.Hour)}))
+	delete(backend.urls, oldURL)
+	delete(backend.fingerprints, oldURL)
+	_, err = cache.Sync(ctx)
+	assert.NoError(t, err)
+
+	processed, err := cache.GetProcessedURL(ctx, oldURL)
+	assert.NoError(t, err)
+	assert.Nil(t, processed)
+	fingerprint, err = cache.GetFingerprint(ctx, oldURL)
+	assert.NoError(t, err)
+	assert.Nil(t, fingerprint)
+	recent, _ := cache.IsURLProcessedRecently(ctx, newURL, time.Hour)
+	assert.True(t, recent)
+}
+
+func TestDomainConfig_Validation(t *testing.T) {
+	assert.Equal(t, "imobiliaria.com.br", NormalizeDomain("https://www.Imobiliaria.com.br:8080/imoveis"))
+	assert.Equal(t, "imobiliaria.com.br", NormalizeDomain("imobiliaria.com.br"))
+	assert.Equal(t, "", NormalizeDomain("  "))
+
+	config := DomainConfig{
+		Domain:          "imobiliaria.com.br",
+		RateLimit:       DomainRateLimit{Parallelism: 2, DelayMs: 1000},
+		IncludePatterns: []string{"/imove(l|is)/"},
+		ExcludePatterns: []string{"/blog/"},
+		Selectors:       map[string]string{"valor": ".preco"},
+	}
+	assert.NoError(t, config.Validate())
+	assert.True(t, config.AllowsURL("https://imobiliaria.com.br/imovel/10"))
+	assert.False(t, config.AllowsURL("https://imobiliaria.com.br/blog/imovel/10"))
+	assert.False(t, config.AllowsURL("https://imobiliaria.com.br/contato"))
+
+	config.ExcludePatterns = []string{"([a-z"}
+	assert.Error(t, config.Validate())
+
+	config.ExcludePatterns = nil
+	config.Selectors = map[string]string{"telefone": ".fone"}
+	assert.Error(t, config.Validate())
+
+	config.Selectors = nil
+	config.PostProcessScript = "if property.valor < 1000 then property.valor = property.valor * 1000 end"
+	assert.NoError(t, config.Validate())
+
+	config.PostProcessScript = "if property.valor then"
+	assert.Error(t, config.Validate())
+
+	config.PostProcessScript = ""
+	config.PhotoPolicy = PhotoPolicyRedirect
+	assert.NoError(t, config.Validate())
+	config.PhotoPolicy = "hotlink"
+	assert.Error(t, config.Validate())
+
+	config.PhotoPolicy = ""
+	config.RateLimit.Parallelism = 100
+	assert.Error(t, config.Validate())
+}
+
 // Benchmark tests
 func BenchmarkMongoRepository_Save(b *testing.B) {
 	// Skip if MongoDB not available
@@ -370,6 +1075,1223 @@ func BenchmarkMongoRepository_Save(b *testing.B) {
 }
 
 // Run the test suite
+func TestDerivedFields(t *testing.T) {
+	property := Property{
+		TipoImovel:      "Sobrado",
+		Valor:           350000,
+		AreaTotal:       300,
+		AreaUtil:        120,
+		Caracteristicas: []string{"Piscina", "2 vagas de garagem"},
+	}
+	ApplyDerivedFields(&property)
+	assert.Equal(t, "Sobrado", property.TipoImovel)
+	assert.Equal(t, TipoCasa, property.TipoNormalizado)
+	assert.Equal(t, 2916.67, property.ValorM2)
+	assert.Equal(t, []string{"garagem", "piscina"}, property.Amenidades)
+
+	// O Save e o backfill de comodidades sobrescrevem o campo, gravando null quando não há comodidades
+	raw, err := bson.Marshal(Property{Caracteristicas: []string{"Vista para o lago"}})
+	assert.NoError(t, err)
+	assert.Equal(t, bson.TypeNull, bson.Raw(raw).Lookup("amenidades").Type)
+	assert.Equal(t, bson.M{"$set": bson.M{"amenidades": []string{"piscina"}}}, amenitiesUpdate([]string{"Piscina aquecida"}))
+	assert.Equal(t, bson.M{"$set": bson.M{"amenidades": []string(nil)}}, amenitiesUpdate(nil))
+
+	assert.Equal(t, TipoApartamento, NormalizePropertyType("Apto", ""))
+	assert.Equal(t, TipoRural, NormalizePropertyType("Outro", "Chácara com pomar em Muzambinho"))
+	assert.Equal(t, TipoTerreno, NormalizePropertyType("", "Lote plano de 360m²"))
+	assert.Equal(t, TipoOutro, NormalizePropertyType("", "Oportunidade única"))
+	assert.Zero(t, CalculateValorM2(Property{Valor: 100000}))
+
+	// O backfill só grava os campos que mudaram e geocodifica imóveis sem localização
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		assert.Equal(t, "teste/1.0", r.Header.Get("User-Agent"))
+		if strings.HasPrefix(r.URL.Query().Get("q"), "Rua Inexistente") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat": "-21.3756", "lon": "-46.5251"}]`))
+	}))
+	defer server.Close()
+	geocoder := NewNominatimGeocoder(server.URL, "teste/1.0", time.Millisecond)
+
+	progress := BackfillProgress{}
+	update := backfillUpdate(context.Background(), property, nil, &progress)
+	assert.Empty(t, update)
+
+	stored := Property{TipoImovel: "Casa", Valor: 200000, AreaTotal: 100, Endereco: "Rua Inexistente, 10", Bairro: "Centro", Cidade: "Muzambinho"}
+	update = backfillUpdate(context.Background(), stored, geocoder, &progress)
+	assert.Equal(t, float64(2000), update["valor_m2"])
+	assert.Equal(t, TipoCasa, update["tipo_normalizado"])
+	assert.NotContains(t, update, "tipo_imovel")
+	location := update["location"].(*GeoPoint)
+	assert.Equal(t, -21.3756, location.Latitude())
+	assert.Equal(t, -46.5251, location.Longitude())
+	assert.Equal(t, 1, progress.Geocoded)
+	assert.Equal(t, []string{"Rua Inexistente, 10, Centro, Muzambinho, Brasil", "Centro, Muzambinho, Brasil"}, queries)
+
+	// Consultas repetidas usam o cache
+	_, err = geocoder.Geocode(context.Background(), "", "Centro", "Muzambinho")
+	assert.NoError(t, err)
+	assert.Len(t, queries, 2)
+}
+
+func TestSchemaMigrations(t *testing.T) {
+	legacy := bson.M{
+		"_id":             "legacy-1",
+		"tipo_imovel":     "Sobrado",
+		"valor":           300000.0,
+		"area_total":      150.0,
+		"fotos":           int32(0),
+		"caracteristicas": bson.A{"Piscina aquecida"},
+	}
+	raw, err := bson.Marshal(legacy)
+	assert.NoError(t, err)
+
+	property, migrated, err := decodeProperty(raw)
+	assert.NoError(t, err)
+	assert.NotNil(t, migrated)
+	assert.Equal(t, CurrentSchemaVersion(), property.SchemaVersion)
+	assert.Equal(t, SourceCrawler, property.Source)
+	assert.Equal(t, "Sobrado", property.TipoImovel)
+	assert.Equal(t, TipoCasa, property.TipoNormalizado)
+	assert.Equal(t, float64(2000), property.ValorM2)
+	assert.Equal(t, []string{"piscina"}, property.Amenidades)
+	assert.True(t, property.SemFotos)
+
+	// Sem comodidades o campo é gravado vazio, para não ser migrado de novo
+	doc := bson.M{"caracteristicas": bson.A{"Vista para o lago"}}
+	assert.NoError(t, migrateBaseline(doc))
+	assert.Contains(t, doc, "amenidades")
+	assert.Nil(t, doc["amenidades"])
+	assert.Equal(t, "legacy-1", property.ID)
+
+	// Documentos na versão atual são decodificados sem migração
+	current, err := bson.Marshal(bson.M{"_id": "current-1", "schema_version": CurrentSchemaVersion(), "tipo_imovel": "Sobrado"})
+	assert.NoError(t, err)
+	property, migrated, err = decodeProperty(current)
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+	assert.Equal(t, "Sobrado", property.TipoImovel)
+
+	// Documentos já migrados pela v2 antiga recebem o tipo da taxonomia sem alterar tipo_imovel
+	doc = bson.M{"schema_version": 7, "tipo_imovel": "Chalé", "descricao": "Chalé na serra"}
+	changed, err := MigrateDocument(doc)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "Chalé", doc["tipo_imovel"])
+	assert.Equal(t, TipoOutro, doc["tipo_normalizado"])
+
+	changed, err = MigrateDocument(bson.M{"schema_version": CurrentSchemaVersion()})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Error(t, RegisterSchemaMigration(SchemaMigration{Version: 1, Migrate: migrateBaseline}))
+	assert.Error(t, RegisterSchemaMigration(SchemaMigration{Version: 99}))
+}
+
+func TestShortID(t *testing.T) {
+	property := Property{URL: "https://www.imobiliaria.com.br/imovel/123"}
+	shortID := GenerateShortID(property)
+	assert.Len(t, shortID, ShortIDLength)
+	assert.True(t, ValidShortID(shortID))
+
+	// A URL canônica (normalizada) define o ID: variações da mesma URL geram o mesmo ID
+	assert.Equal(t, shortID, GenerateShortID(Property{URL: "https://www.imobiliaria.com.br/Imovel/123?utm_source=feed"}))
+	assert.NotEqual(t, shortID, GenerateShortID(Property{URL: "https://www.imobiliaria.com.br/imovel/124"}))
+
+	// Unidades de um anúncio multi-unidade têm IDs próprios
+	unit := Property{URL: property.URL, ParentID: GenerateParentID(property.URL), Quartos: 2}
+	assert.NotEqual(t, shortID, GenerateShortID(unit))
+	unit.Quartos = 3
+	assert.NotEqual(t, GenerateShortID(Property{URL: property.URL, ParentID: unit.ParentID, Quartos: 2}), GenerateShortID(unit))
+
+	assert.False(t, ValidShortID("abc"))
+	assert.False(t, ValidShortID("ilou012345"))
+
+	// Documentos antigos recebem o ID na migração; um ID já gravado é mantido
+	doc := bson.M{"_id": "legacy-1", "url": "https:/www.imobiliaria.com.br/imovel/123"}
+	assert.NoError(t, migrateShortID(doc))
+	assert.Equal(t, shortID, doc["short_id"])
+	doc = bson.M{"_id": "moved-1", "url": "https:/www.imobiliaria.com.br/novo/123", "short_id": shortID}
+	assert.NoError(t, migrateShortID(doc))
+	assert.Equal(t, shortID, doc["short_id"])
+}
+
+func TestBuildDuplicateClusters(t *testing.T) {
+	description := "Casa ampla com tres quartos sendo uma suite, sala para dois ambientes, cozinha planejada, quintal e garagem para dois carros"
+	properties := []Property{
+		{URL: "https://www.imobiliaria-a.com.br/imovel/1", Cidade: "Muzambinho", Bairro: "Centro", TipoImovel: "casa", Quartos: 3, AreaUtil: 120, Valor: 450000, Descricao: description},
+		{URL: "https://imobiliaria-b.com.br/casa-centro", Cidade: "Muzambinho", Bairro: "Centro", TipoImovel: "Casa", Quartos: 3, AreaUtil: 122, Valor: 490000, Descricao: description + "!"},
+		// Mesmo domínio do primeiro: não é duplicata entre imobiliárias
+		{URL: "https://imobiliaria-a.com.br/imovel/2", Cidade: "Muzambinho", Bairro: "Centro", TipoImovel: "Casa", Quartos: 3, AreaUtil: 120, Valor: 455000, Descricao: description},
+		// Área muito diferente
+		{URL: "https://imobiliaria-c.com.br/imovel/9", Cidade: "Muzambinho", Bairro: "Centro", TipoImovel: "Casa", Quartos: 3, AreaUtil: 200, Valor: 700000, Descricao: description},
+		// Outro bairro
+		{URL: "https://imobiliaria-d.com.br/imovel/5", Cidade: "Muzambinho", Bairro: "Jardim", TipoImovel: "Casa", Quartos: 3, AreaUtil: 120, Valor: 300000, Descricao: description},
+	}
+
+	clusters := BuildDuplicateClusters(properties, DuplicateReportOptions{})
+	assert.Len(t, clusters, 1)
+	cluster := clusters[0]
+	assert.Equal(t, 2, cluster.Agencies)
+	assert.Len(t, cluster.Listings, 3)
+	assert.Equal(t, 450000.0, cluster.MinValor)
+	assert.Equal(t, 490000.0, cluster.MaxValor)
+	assert.Equal(t, 40000.0, cluster.PriceSpread)
+	assert.Equal(t, 8.89, cluster.PriceSpreadPct)
+	assert.Equal(t, "imobiliaria-a.com.br", cluster.Listings[0].Domain)
+
+	// Descrições diferentes não formam grupo
+	different := []Property{properties[0], properties[1]}
+	different[1].Descricao = "Apartamento no centro com vista para a praça, elevador, portaria e área de lazer completa"
+	assert.Empty(t, BuildDuplicateClusters(different, DuplicateReportOptions{}))
+}
+
+func TestMergeDuplicateListings(t *testing.T) {
+	// Confiabilidade vem da métrica mais recente de cada domínio
+	series := []DomainQuality{
+		{Domain: "portal-a.com.br", Day: "2024-05-01", WithPricePct: 50, WithAddressPct: 50, WithRoomsPct: 50, WithPhotosPct: 50},
+		{Domain: "portal-a.com.br", Day: "2024-05-02", WithPricePct: 100, WithAddressPct: 100, WithRoomsPct: 100, WithPhotosPct: 100, AvgExtractionScore: 0.8, ScoredProperties: 10},
+		{Domain: "portal-b.com.br", Day: "2024-05-02", WithPricePct: 100, WithAddressPct: 100, WithRoomsPct: 50, WithPhotosPct: 50},
+	}
+	weights := SourceReliabilityWeights(series)
+	assert.Equal(t, 0.9, weights["portal-a.com.br"])
+	assert.Equal(t, 0.75, weights["portal-b.com.br"])
+	assert.Equal(t, minSourceReliability, SourceReliability(DomainQuality{}))
+
+	listings := []Property{
+		{ID: "665f1c2e9b1e8a0001000003", URL: "https://portal-b.com.br/imovel/7", Endereco: "rua das flores, 120", Cidade: "Muzambinho", Valor: 470000, Quartos: 4, AreaUtil: 120,
+			FieldConfidence: map[string]float64{"valor": 0.9, "quartos": 0.95}},
+		{ID: "665f1c2e9b1e8a0001000001", URL: "https://www.portal-a.com.br/casa/1", Endereco: "Rua das Flores, 120", Cidade: "Muzambinho", Valor: 450000, Quartos: 3, AreaUtil: 118,
+			FieldConfidence: map[string]float64{"valor": 0.6, "quartos": 0.5}},
+		// Domínio sem métricas: peso padrão
+		{ID: "665f1c2e9b1e8a0001000002", URL: "https://portal-c.com.br/anuncio/3", Cidade: "Muzambinho", Valor: 470000, Quartos: 4, AreaUtil: 120},
+	}
+	canonical := MergeDuplicateListings(listings, weights)
+
+	assert.Len(t, canonical.Sources, 3)
+	assert.Equal(t, "https://www.portal-a.com.br/casa/1", canonical.Sources[0].URL)
+	assert.Equal(t, DefaultSourceReliability, canonical.Sources[2].Weight)
+	assert.Equal(t, 450000.0, canonical.Sources[0].Original.Valor)
+
+	// Duas fontes concordam no preço e superam a mais confiável
+	merged := canonical.Property
+	assert.Equal(t, 470000.0, merged.Valor)
+	assert.Equal(t, "https://portal-b.com.br/imovel/7", canonical.FieldSources["valor"])
+	assert.Equal(t, 0.631, merged.FieldConfidence["valor"])
+	assert.Equal(t, 4, merged.Quartos)
+	// Mesmo endereço com caixa diferente: prevalece a grafia da fonte mais confiável
+	assert.Equal(t, "Rua das Flores, 120", merged.Endereco)
+	assert.Equal(t, 1.0, merged.FieldConfidence["endereco"])
+	assert.Equal(t, "https://www.portal-a.com.br/casa/1", merged.URL)
+	assert.Equal(t, "Muzambinho", canonical.Cidade)
+	assert.NotContains(t, canonical.FieldSources, "cep")
+
+	// Primeira fusão: ID do anúncio mais antigo, independente da ordem
+	assert.Equal(t, "665f1c2e9b1e8a0001000001", canonical.ID)
+	assert.Empty(t, merged.CanonicalID)
+	reversed := []Property{listings[2], listings[1], listings[0]}
+	assert.Equal(t, canonical.ID, MergeDuplicateListings(reversed, weights).ID)
+
+	// Fusões seguintes mantêm o canonical_id mesmo com outro anúncio (novo ou de URL nova) no grupo
+	linked := []Property{listings[1], listings[2]}
+	linked[0].ID = "665f1c2e9b1e8a0001000009" // nova versão do anúncio
+	linked[0].CanonicalID = canonical.ID
+	linked[1].CanonicalID = canonical.ID
+	newcomer := Property{ID: "665f1c2e9b1e8a0001000000", URL: "https://portal-d.com.br/novo/1"}
+	assert.Equal(t, canonical.ID, CanonicalAnchor(append(linked, newcomer)))
+}
+
+func TestObjectIDAfter(t *testing.T) {
+	asOf := time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC)
+	id := objectIDAfter(asOf)
+	assert.Equal(t, asOf.Add(time.Second).Unix(), id.Timestamp().Unix())
+
+	// Qualquer ObjectID gerado até asOf é menor que o limite
+	before := primitive.NewObjectIDFromTimestamp(asOf)
+	assert.Less(t, before.Hex(), id.Hex())
+	after := primitive.NewObjectIDFromTimestamp(asOf.Add(time.Second))
+	assert.GreaterOrEqual(t, after.Hex(), id.Hex())
+}
+
+func TestLatestVersionsMultiUnit(t *testing.T) {
+	listing := "https://construtora.com.br/residencial-aurora"
+	parent := GenerateParentID(listing)
+	lots := "https://loteadora.com.br/jardim-das-flores"
+	lotsParent := GenerateParentID(lots)
+
+	// Versões da mais recente para a mais antiga, como no cursor do FindAsOf
+	versions := []Property{
+		{ID: "9", URL: listing, ParentID: parent, Quartos: 2, Valor: 410000},
+		{ID: "8", URL: listing, ParentID: parent, Quartos: 3, Valor: 520000},
+		{ID: "7", URL: listing, ParentID: parent, Quartos: 2, Valor: 390000},
+		{ID: "6", URL: listing, ParentID: parent, Quartos: 3, Valor: 500000},
+		{ID: "5", URL: lots, ParentID: lotsParent, Loteamento: &Loteamento{}},
+		{ID: "4", URL: lots, ParentID: lotsParent, Lote: "Quadra A, Lote 1", Valor: 90000},
+		{ID: "3", URL: lots, ParentID: lotsParent, Lote: "Quadra A, Lote 2", Valor: 95000},
+		{ID: "2", URL: "https://imobiliaria.com.br/casa", Quartos: 4, Valor: 700000},
+		{ID: "1", URL: "https://imobiliaria.com.br/casa", Quartos: 3, Valor: 720000},
+	}
+
+	keep := latestVersions()
+	var ids []string
+	for _, version := range versions {
+		if keep(version) {
+			ids = append(ids, version.ID)
+		}
+	}
+
+	// Cada unidade mantém a própria versão mais recente; o anúncio simples, só a última
+	assert.Equal(t, []string{"9", "8", "5", "4", "3", "2"}, ids)
+}
+
+func TestDetectFeatures(t *testing.T) {
+	defer SetFeatureKeywords(nil)
+
+	property := Property{
+		Descricao:       "Chácara com poço semi-artesiano e placas solares",
+		Caracteristicas: []string{"Aquecedor a gás", "Piscina"},
+	}
+	ApplyDerivedFields(&property)
+	assert.True(t, property.EnergiaSolar)
+	assert.True(t, property.PocoArtesiano)
+	assert.True(t, property.AquecimentoGas)
+
+	// "poço" sozinho ou "solar" em outro contexto não bastam
+	plain := Property{Descricao: "Casa com poço de luz e orientação solar da manhã"}
+	assert.Empty(t, DetectFeatures(plain))
+
+	// Termos extras configuráveis
+	assert.NoError(t, SetFeatureKeywords(map[string][]string{FeaturePocoArtesiano: {"Água de poço"}}))
+	assert.True(t, DetectFeatures(Property{Descricao: "Sítio com água de poço"})[FeaturePocoArtesiano])
+	assert.Error(t, SetFeatureKeywords(map[string][]string{"piscina_aquecida": {"piscina aquecida"}}))
+
+	// Documentos da versão 2 recebem os recursos na migração
+	doc := bson.M{"schema_version": 2, "descricao": "Sítio com energia solar"}
+	changed, err := MigrateDocument(doc)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, true, doc["energia_solar"])
+	assert.Equal(t, false, doc["poco_artesiano"])
+}
+
+func TestAuctionDetection(t *testing.T) {
+	property := Property{
+		URL:        "https://imobiliaria.com.br/imovel/10",
+		Descricao:  "Casa em leilão judicial. 1ª praça: 15/03/2025 às 14h. Lance mínimo: R$ 250.000,00",
+		ValorTexto: "R$ 250.000,00",
+		Valor:      250000,
+	}
+	ApplyDerivedFields(&property)
+	assert.True(t, property.Leilao)
+	if assert.NotNil(t, property.LeilaoData) {
+		assert.Equal(t, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), *property.LeilaoData)
+	}
+	assert.Equal(t, 250000.0, property.LanceMinimo)
+
+	// Portais de leilão dispensam palavras-chave
+	assert.True(t, IsAuctionListing(Property{URL: "https://www.megaleiloes.com.br/imoveis/casa-123"}))
+	assert.True(t, IsAuctionListing(Property{URL: "https://leilaoimovel.com.br/imovel/9"}))
+
+	regular := Property{URL: "https://imobiliaria.com.br/imovel/11", Descricao: "Casa com 3 quartos e praça em frente"}
+	ApplyDerivedFields(&regular)
+	assert.False(t, regular.Leilao)
+	assert.Nil(t, regular.LeilaoData)
+
+	assert.Nil(t, ParseAuctionDate("leilão em 31/02/2025"))
+	assert.Equal(t, 1500.5, ParseMinimumBid("lance inicial de 1500,50"))
+}
+
+func TestCommercialLease(t *testing.T) {
+	property := Property{
+		TipoImovel: "Sala",
+		Descricao:  "Sala comercial em corredor comercial (ZC2), testada de 12,5m. Venda R$ 850.000,00 ou aluguel R$ 3.500,00",
+		ValorTexto: "R$ 3.500,00",
+		Valor:      3500,
+		AreaUtil:   80,
+	}
+	ApplyDerivedFields(&property)
+	assert.Equal(t, FinalidadeComercial, property.Finalidade)
+	assert.Equal(t, 850000.0, property.ValorVenda)
+	assert.Equal(t, 3500.0, property.ValorAluguel)
+	assert.Equal(t, 12.5, property.Testada)
+	assert.Equal(t, []string{"comercial", "corredor_comercial"}, property.Zoneamento)
+
+	// Sem menção explícita, o texto do preço decide entre venda e aluguel
+	venda, aluguel := SplitSaleAndRent(Property{Valor: 2200, ValorTexto: "2.200 / mês"})
+	assert.Equal(t, 0.0, venda)
+	assert.Equal(t, 2200.0, aluguel)
+	venda, aluguel = SplitSaleAndRent(Property{Valor: 450000, ValorTexto: "R$ 450.000"})
+	assert.Equal(t, 450000.0, venda)
+	assert.Equal(t, 0.0, aluguel)
+
+	assert.Equal(t, FinalidadeResidencial, DetectFinalidade(Property{TipoImovel: "Apartamento"}))
+	assert.Equal(t, FinalidadeRural, DetectFinalidade(Property{TipoImovel: "Chácara"}))
+	assert.Equal(t, 10.0, ParseFrontage("Terreno com 10 metros de frente"))
+	assert.Nil(t, DetectZoning("Casa ampla perto do centro"))
+}
+
+func TestVacationRental(t *testing.T) {
+	property := Property{
+		TipoImovel: "Chalé",
+		Descricao:  "Chalé para temporada na serra, diária de R$ 450,00 para até 4 pessoas. Mínimo de 2 noites nos feriados.",
+		ValorTexto: "R$ 450,00",
+		Valor:      450,
+		AreaUtil:   60,
+	}
+	ApplyDerivedFields(&property)
+	assert.Equal(t, FinalidadeTemporada, property.Finalidade)
+	assert.Equal(t, 450.0, property.ValorDiaria)
+	assert.Equal(t, 2, property.MinimoNoites)
+	assert.Zero(t, property.ValorVenda)
+	assert.Zero(t, property.ValorAluguel)
+
+	// Sem termos de temporada, o texto do preço por noite basta; a diária vem do Valor
+	daily := Property{Valor: 300, ValorTexto: "R$ 300 / noite", Descricao: "Casa com piscina, 3 diárias mínimas"}
+	assert.True(t, IsVacationRental(daily))
+	assert.Equal(t, 300.0, ParseDailyRate(daily))
+	assert.Equal(t, 3, ParseMinimumNights(daily.Descricao))
+
+	// Venda e aluguel mensal não são temporada
+	assert.False(t, IsVacationRental(Property{Valor: 450000, ValorTexto: "R$ 450.000", Descricao: "Casa com 3 quartos no centro"}))
+	assert.False(t, IsVacationRental(Property{Valor: 2200, ValorTexto: "R$ 2.200 / mês", Descricao: "Apartamento para aluguel"}))
+
+	// Termos de temporada sem diária, ou em anúncio de venda, não bastam
+	sale := Property{
+		Valor:      450000,
+		ValorTexto: "R$ 450.000",
+		Descricao:  "Chácara à venda com pomar e piscina, ótima para temporada",
+	}
+	ApplyDerivedFields(&sale)
+	assert.NotEqual(t, FinalidadeTemporada, sale.Finalidade)
+	assert.Equal(t, 450000.0, sale.ValorVenda)
+	assert.False(t, IsVacationRental(Property{Valor: 350, ValorTexto: "R$ 350", Descricao: "Check in a partir das 14h, casa de campo para temporada"}))
+	assert.False(t, IsVacationRental(Property{Valor: 380000, ValorTexto: "R$ 380.000", Descricao: "Vendo casa de praia, diária de R$ 500 na temporada"}))
+
+	// Diárias não entram na comparação com a mediana de venda do bairro
+	property.ValorM2 = 7.5
+	_, signals := ScoreScam(property, 5000)
+	assert.NotContains(t, signals, ScamSignalLowPrice)
+
+	// Documentos antigos são marcados pela migração
+	doc := bson.M{"descricao": "Aluguel por temporada, R$ 600 por noite", "valor": 600.0, "valor_venda": 600.0}
+	assert.NoError(t, migrateVacationRental(doc))
+	assert.Equal(t, FinalidadeTemporada, doc["finalidade"])
+	assert.Equal(t, 600.0, doc["valor_diaria"])
+	assert.NotContains(t, doc, "valor_venda")
+
+	// Anúncios de venda continuam com o valor de venda
+	doc = bson.M{"descricao": "Chácara à venda, ótima para temporada", "valor": 450000.0, "valor_venda": 450000.0}
+	assert.NoError(t, migrateVacationRental(doc))
+	assert.NotContains(t, doc, "finalidade")
+	assert.Equal(t, 450000.0, doc["valor_venda"])
+}
+
+func TestDailyExchangeRates(t *testing.T) {
+	calls := 0
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "BRL", r.URL.Query().Get("from"))
+		assert.Equal(t, "USD,EUR", r.URL.Query().Get("to"))
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"amount": 1.0, "base": "BRL", "date": "2024-03-01", "rates": {"USD": 0.2, "EUR": 0.185}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	rates := NewDailyExchangeRates(NewHTTPExchangeRateProvider(server.URL), []string{"usd", " EUR", "BRL"})
+	rates.now = func() time.Time { return now }
+	assert.Equal(t, []string{"USD", "EUR"}, rates.Currencies())
+	assert.True(t, rates.Supports("eur"))
+	assert.False(t, rates.Supports("GBP"))
+
+	// O provedor é consultado uma vez por dia
+	current, err := rates.Rates(context.Background())
+	assert.NoError(t, err)
+	_, err = rates.Rates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	property := Property{Valor: 450000}
+	ApplyExchangeRates(&property, current)
+	assert.Equal(t, map[string]float64{"USD": 90000, "EUR": 83250}, property.ValorConvertido)
+	assert.Equal(t, "2024-03-01", property.CambioData.Format("2006-01-02"))
+
+	unpriced := Property{}
+	ApplyExchangeRates(&unpriced, current)
+	assert.Nil(t, unpriced.ValorConvertido)
+
+	// No dia seguinte, uma falha do provedor mantém as últimas cotações e adia a nova tentativa
+	failing = true
+	now = now.Add(24 * time.Hour)
+	stale, err := rates.Rates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, current, stale)
+	_, _ = rates.Rates(context.Background())
+	assert.Equal(t, 2, calls)
+
+	// Sem cotações anteriores a falha é informada
+	empty := NewDailyExchangeRates(NewHTTPExchangeRateProvider(server.URL), []string{"USD", "EUR"})
+	_, err = empty.Rates(context.Background())
+	assert.Error(t, err)
+}
+
+// blockImage gera uma imagem em blocos 9x8 com tons distintos (mesmo padrão em qualquer tamanho)
+func blockImage(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cellX, cellY := x*9/width, y*8/height
+			img.SetGray(x, y, color.Gray{Y: uint8((cellX*37 + cellY*91) * 53 % 256)})
+		}
+	}
+	return img
+}
+
+func TestPhotoFraudDetection(t *testing.T) {
+	// O hash resiste ao redimensionamento e imagens uniformes (placeholders) geram hash zerado
+	hash := PerceptualHash(blockImage(90, 80))
+	assert.NotZero(t, hash)
+	assert.Equal(t, hash, PerceptualHash(blockImage(180, 160)))
+	assert.Zero(t, PerceptualHash(image.NewGray(image.Rect(0, 0, 90, 80))))
+
+	photo := FormatSimHash(hash)
+	properties := []Property{
+		{URL: "https://a.com/1", Cidade: "Muzambinho", Bairro: "Centro", Valor: 450000, FotoHashes: []string{photo}},
+		{URL: "https://b.com/2", Cidade: "Muzambinho", Bairro: "Jardim", Valor: 180000, FotoHashes: []string{photo, "00000000000000ff"}},
+		// Mesmo imóvel republicado por outra imobiliária com preço parecido
+		{URL: "https://c.com/3", Cidade: "Muzambinho", Bairro: "Centro", Valor: 440000, FotoHashes: []string{photo}},
+		// Unidades do mesmo anúncio compartilham fotos
+		{URL: "https://d.com/4#1", ParentID: "d4", Bairro: "Vila Nova", Valor: 200000, FotoHashes: []string{"1111111111111111"}},
+		{URL: "https://d.com/4#2", ParentID: "d4", Bairro: "Vila Nova", Valor: 350000, FotoHashes: []string{"1111111111111111"}},
+		// Placeholders não identificam o imóvel
+		{URL: "https://e.com/5", Bairro: "Centro", Valor: 100000, FotoHashes: []string{FormatSimHash(0)}},
+		{URL: "https://f.com/6", Bairro: "Jardim", Valor: 900000, FotoHashes: []string{FormatSimHash(0)}},
+	}
+
+	// Um item por par: a-b e b-c divergem; a e c são o mesmo imóvel republicado
+	reviews := FindPhotoConflicts(properties)
+	assert.Len(t, reviews, 2)
+	pairs := make(map[string]FraudReview)
+	for _, review := range reviews {
+		assert.Equal(t, FraudReviewPending, review.Status)
+		assert.Equal(t, []string{photo}, review.SharedPhotos)
+		assert.Equal(t, []string{FraudReasonLocation, FraudReasonPrice}, review.Reasons)
+		assert.Len(t, review.ID, 24)
+		assert.Len(t, review.Listings, 2)
+		pairs[review.Listings[0].URL+" "+review.Listings[1].URL] = review
+	}
+	assert.Contains(t, pairs, "https://a.com/1 https://b.com/2")
+	assert.Contains(t, pairs, "https://b.com/2 https://c.com/3")
+
+	// O ID do par não muda quando outro anúncio passa a usar a mesma foto
+	withNewcomer := append(append([]Property(nil), properties...),
+		Property{URL: "https://g.com/7", Cidade: "Guaxupé", Bairro: "Centro", Valor: 450000, FotoHashes: []string{photo}})
+	ids := make(map[string]bool)
+	for _, review := range FindPhotoConflicts(withNewcomer) {
+		ids[review.ID] = true
+	}
+	assert.Len(t, ids, 5)
+	for _, review := range reviews {
+		assert.True(t, ids[review.ID])
+	}
+
+	// Cidade ou bairro ausentes não contam como localização diferente
+	partial := []Property{
+		{URL: "https://a.com/1", Cidade: "Muzambinho", Bairro: "Centro", Valor: 450000, FotoHashes: []string{photo}},
+		{URL: "https://h.com/8", Cidade: "Muzambinho", Valor: 445000, FotoHashes: []string{photo}},
+		{URL: "https://i.com/9", Valor: 450000, FotoHashes: []string{photo}},
+	}
+	assert.Empty(t, FindPhotoConflicts(partial))
+
+	// HTTPPhotoHasher baixa as fotos, ignora as que falham e reaproveita o cache
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		png.Encode(w, blockImage(90, 80))
+	}))
+	defer server.Close()
+
+	hasher := NewHTTPPhotoHasher("test-agent", 2, nil)
+	urlsToHash := []string{server.URL + "/missing.png", server.URL + "/1.png", server.URL + "/2.png"}
+	assert.Equal(t, []string{photo}, hasher.HashPhotos(context.Background(), urlsToHash))
+	assert.Equal(t, []string{photo}, hasher.HashPhotos(context.Background(), urlsToHash[1:]))
+	assert.Equal(t, 3, requests)
+}
+
+func TestScamScore(t *testing.T) {
+	noContact := false
+	scam := Property{
+		Cidade:     "Muzambinho",
+		Bairro:     "Centro",
+		Valor:      90000,
+		AreaUtil:   120,
+		Descricao:  "Casa 3 quartos, URGENTE! Motivo de viagem, depósito antecipado para reservar.",
+		FotoURLs:   []string{"https://imob.com/foto1.jpg", "https://images.unsplash.com/photo-123.jpg"},
+		TemContato: &noContact,
+	}
+	ApplyDerivedFields(&scam)
+	ApplyScamScore(&scam, 4000)
+	assert.Equal(t, 100, scam.RiscoGolpe)
+	assert.True(t, scam.SuspeitoGolpe)
+	assert.Equal(t, []string{ScamSignalLowPrice, ScamSignalStockPhotos, ScamSignalNoContact, ScamSignalUrgency}, scam.SinaisGolpe)
+
+	// Preço baixo sozinho não basta; sem mediana conhecida o preço não é avaliado
+	cheap := Property{Valor: 150000, AreaUtil: 100, Descricao: "Ligue (35) 99876-5432"}
+	ApplyDerivedFields(&cheap)
+	ApplyScamScore(&cheap, 4000)
+	assert.Equal(t, 40, cheap.RiscoGolpe)
+	assert.False(t, cheap.SuspeitoGolpe)
+	ApplyScamScore(&cheap, 0)
+	assert.Zero(t, cheap.RiscoGolpe)
+
+	// Leilões são naturalmente abaixo da mediana e contato não verificado não conta
+	auction := Property{Valor: 100000, AreaUtil: 100, Descricao: "Leilão judicial, oportunidade única"}
+	ApplyDerivedFields(&auction)
+	assert.True(t, auction.Leilao)
+	score, signals := ScoreScam(auction, 4000)
+	assert.Equal(t, 20, score)
+	assert.Equal(t, []string{ScamSignalUrgency}, signals)
+
+	assert.True(t, HasContactInfo("WhatsApp: (35) 3571-1234"))
+	assert.True(t, HasContactInfo("contato@imobiliaria.com.br"))
+	assert.False(t, HasContactInfo("Casa com 3 quartos por R$ 450.000,00, CEP 37890-000"))
+	assert.True(t, IsStockPhoto("https://media.istockphoto.com/id/1/photo.jpg"))
+	assert.False(t, IsStockPhoto("https://imobiliaria.com.br/fotos/1.jpg"))
+}
+
+func TestCrawlTags(t *testing.T) {
+	tags, err := ParseCrawlTags(" Muzambinho-Weekly, campanha:2024.q3,,muzambinho-weekly ")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"muzambinho-weekly", "campanha:2024.q3"}, tags)
+
+	_, err = ParseCrawlTags("tag com espaço")
+	assert.Error(t, err)
+	_, err = NormalizeCrawlTags([]string{"-inicio"})
+	assert.Error(t, err)
+	_, err = NormalizeCrawlTags([]string{strings.Repeat("a", 65)})
+	assert.Error(t, err)
+	tags, err = ParseCrawlTags("")
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+
+	// Sem tags, o repositório original é usado diretamente
+	path := filepath.Join(t.TempDir(), "properties.ndjson")
+	sinks, err := OpenSinks([]SinkSpec{{Kind: SinkFile, Target: path}}, nil)
+	assert.NoError(t, err)
+	sinkRepo := NewSinkRepository(nil, sinks)
+	assert.Same(t, sinkRepo, NewTaggedRepository(sinkRepo, nil))
+
+	// As tags do job são acrescentadas às do imóvel, sem repetições
+	repo := NewTaggedRepository(sinkRepo, []string{"muzambinho-weekly", "campanha"})
+	ctx := context.Background()
+	assert.NoError(t, repo.Save(ctx, Property{URL: "https://imobiliaria.com.br/imovel/1", Tags: []string{"campanha", "anterior"}}))
+	assert.NoError(t, repo.Save(ctx, Property{URL: "https://imobiliaria.com.br/imovel/2"}))
+	sinkRepo.Close()
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	var first, second Property
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, []string{"campanha", "anterior", "muzambinho-weekly"}, first.Tags)
+	assert.Equal(t, []string{"muzambinho-weekly", "campanha"}, second.Tags)
+}
+
+func TestDuplicateUpdate(t *testing.T) {
+	existing := Property{URL: "https://imobiliaria.com.br/imovel/1", Descricao: "Casa com 3 quartos"}
+	property := existing
+	assert.Empty(t, duplicateUpdate(existing, property, nil))
+
+	// A tradução feita nesta visita completa o registro salvo, junto com as tags do job
+	property.DescricaoEN = "House with 3 bedrooms"
+	update := duplicateUpdate(existing, property, []string{"campanha"})
+	assert.Equal(t, bson.M{"descricao_en": "House with 3 bedrooms"}, update["$set"])
+	assert.Equal(t, bson.M{"tags": bson.M{"$each": []string{"campanha"}}}, update["$addToSet"])
+
+	// A tradução já salva não é substituída
+	existing.DescricaoEN = "3 bedroom house"
+	assert.Empty(t, duplicateUpdate(existing, property, nil))
+
+	// A avaliação de condição é gravada com o nível e a confiança
+	property.Condicao = "reformado"
+	property.CondicaoNivel = 3
+	property.CondicaoConfianca = 0.8
+	update = duplicateUpdate(existing, property, nil)
+	assert.Equal(t, bson.M{"condicao": "reformado", "condicao_nivel": 3, "condicao_confianca": 0.8}, update["$set"])
+	assert.NotContains(t, update, "$addToSet")
+	existing.Condicao = "usado"
+	assert.Empty(t, duplicateUpdate(existing, property, nil))
+}
+
+func TestRequestAudit(t *testing.T) {
+	ctx := context.Background()
+
+	// Destino vazio desativa; destinos inválidos ou mongo sem MongoDB falham
+	repo, err := OpenRequestAudit(ctx, "", nil, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, repo)
+	_, err = OpenRequestAudit(ctx, "mongo", nil, 0)
+	assert.Error(t, err)
+	_, err = OpenRequestAudit(ctx, "file:", nil, 0)
+	assert.Error(t, err)
+	_, err = OpenRequestAudit(ctx, "s3://bucket", nil, 0)
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	repo, err = OpenRequestAudit(ctx, "file:"+path, nil, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	base := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	entries := []RequestAuditEntry{
+		{Time: base, JobID: "job-1", Method: "GET", URL: "https://imobiliaria.com.br/", Domain: "imobiliaria.com.br", StatusCode: 200, Bytes: 1000, DurationMs: 100},
+		{Time: base.Add(2 * time.Second), JobID: "job-1", Method: "GET", URL: "https://imobiliaria.com.br/imovel/1", Domain: "imobiliaria.com.br", StatusCode: 404, Bytes: 200, DurationMs: 300},
+		{Time: base.Add(5 * time.Second), JobID: "job-2", Method: "GET", URL: "https://outra.com.br/", Domain: "outra.com.br", Error: "timeout", DurationMs: 5000},
+	}
+	assert.NoError(t, repo.SaveRequestAudits(ctx, entries[:2]))
+	assert.NoError(t, repo.SaveRequestAudits(ctx, entries[2:]))
+
+	// Consultas retornam do mais recente ao mais antigo
+	found, err := repo.FindRequestAudits(ctx, RequestAuditFilter{})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 3) {
+		assert.Equal(t, "https://outra.com.br/", found[0].URL)
+		assert.Equal(t, "https://imobiliaria.com.br/", found[2].URL)
+	}
+	found, err = repo.FindRequestAudits(ctx, RequestAuditFilter{JobID: "job-1", Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, 404, found[0].StatusCode)
+	}
+	found, err = repo.FindRequestAudits(ctx, RequestAuditFilter{Domain: "www.imobiliaria.com.br", StatusCode: 200})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	found, err = repo.FindRequestAudits(ctx, RequestAuditFilter{Since: base.Add(time.Second), Until: base.Add(3 * time.Second)})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// Resumo por domínio, do mais requisitado ao menos
+	summaries := SummarizeRequestAudits(entries)
+	if assert.Len(t, summaries, 2) {
+		assert.Equal(t, "imobiliaria.com.br", summaries[0].Domain)
+		assert.Equal(t, 2, summaries[0].Requests)
+		assert.Equal(t, 1, summaries[0].Errors)
+		assert.Equal(t, int64(1200), summaries[0].Bytes)
+		assert.Equal(t, int64(200), summaries[0].AvgDurationMs)
+		assert.Equal(t, int64(2000), summaries[0].MinIntervalMs)
+		assert.Equal(t, 1, summaries[1].Errors)
+		assert.Equal(t, int64(-1), summaries[1].MinIntervalMs)
+	}
+}
+
+func TestCrawlWindow(t *testing.T) {
+	window, err := ParseCrawlWindow(" 22:30-06:00 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "22:30-06:00", window.String())
+	assert.True(t, window.Contains(23*60))
+	assert.True(t, window.Contains(5*60+59))
+	assert.False(t, window.Contains(6*60))
+	assert.False(t, window.Contains(12*60))
+
+	for _, invalid := range []string{"00:00", "25:00-06:00", "06:00-06:00", "24:00-06:00", "6h-8h", "06:00-24:30"} {
+		_, err := ParseCrawlWindow(invalid)
+		assert.Error(t, err, invalid)
+	}
+	window, err = ParseCrawlWindow("18:00-24:00")
+	assert.NoError(t, err)
+	assert.True(t, window.Contains(23*60+59))
+
+	// Sem timezone as janelas seguem o horário de Brasília
+	location, err := LoadCrawlLocation("")
+	assert.NoError(t, err)
+	noon := time.Date(2024, 6, 10, 12, 0, 0, 0, location)
+	config := DomainConfig{Domain: "imobiliaria.com.br", CrawlWindows: []string{"00:00-06:00", "22:00-23:00"}}
+	assert.NoError(t, config.Validate())
+	assert.False(t, config.InCrawlWindow(noon))
+	assert.True(t, config.InCrawlWindow(noon.Add(-8*time.Hour)))
+	assert.True(t, config.NextCrawlWindow(noon).Equal(time.Date(2024, 6, 10, 22, 0, 0, 0, location)))
+	assert.True(t, config.NextCrawlWindow(noon.Add(11*time.Hour)).Equal(time.Date(2024, 6, 11, 0, 0, 0, 0, location)))
+	assert.True(t, config.NextCrawlWindow(noon.Add(-8*time.Hour)).Equal(noon.Add(-8*time.Hour)))
+
+	// Janelas em outro fuso
+	config.Timezone = "UTC"
+	assert.True(t, config.InCrawlWindow(time.Date(2024, 6, 10, 3, 0, 0, 0, time.UTC)))
+	assert.False(t, config.InCrawlWindow(time.Date(2024, 6, 10, 7, 0, 0, 0, time.UTC)))
+
+	// Sem janelas qualquer horário é permitido
+	assert.True(t, (&DomainConfig{Domain: "outra.com.br"}).InCrawlWindow(noon))
+
+	config.CrawlWindows = []string{"06:00"}
+	assert.Error(t, config.Validate())
+	config.CrawlWindows = []string{"00:00-06:00"}
+	config.Timezone = "America/Nowhere"
+	assert.Error(t, config.Validate())
+}
+
+func TestPOIDistances(t *testing.T) {
+	var overpassQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			overpassQueries = append(overpassQueries, r.FormValue("data"))
+			w.Write([]byte(`{"elements": [
+				{"type": "node", "lat": -21.3800, "lon": -46.5251, "tags": {"amenity": "school"}},
+				{"type": "way", "center": {"lat": -21.3656, "lon": -46.5251}, "tags": {"amenity": "school"}},
+				{"type": "way", "center": {"lat": -21.4656, "lon": -46.5251}, "tags": {"amenity": "hospital"}}
+			]}`))
+			return
+		}
+		if r.URL.Query().Get("q") == "Cidade Inexistente, Brasil" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat": "-21.3756", "lon": "-46.5251"}]`))
+	}))
+	defer server.Close()
+
+	geocoder := NewNominatimGeocoder(server.URL, "teste/1.0", time.Millisecond)
+	finder := NewOverpassPOIFinder(server.URL, "teste/1.0", 10, geocoder)
+	ctx := context.Background()
+
+	pois, err := finder.FindPOIs(ctx, "Muzambinho")
+	if !assert.NoError(t, err) || !assert.NotNil(t, pois) {
+		return
+	}
+	assert.Len(t, pois.Escolas, 2)
+	assert.Len(t, pois.Hospitais, 1)
+	if assert.Len(t, overpassQueries, 1) {
+		assert.Contains(t, overpassQueries[0], "(around:10000,-21.375600,-46.525100)")
+	}
+
+	// A cidade fica em cache: o mesmo nome (com outra grafia) não repete as consultas
+	_, err = finder.FindPOIs(ctx, "MUZAMBINHO")
+	assert.NoError(t, err)
+	assert.Len(t, overpassQueries, 1)
+
+	distances := pois.Distances(NewGeoPoint(-21.3756, -46.5251))
+	assert.Equal(t, &POIDistances{CentroKm: 0.01, EscolaKm: 0.49, HospitalKm: 10.02}, distances)
+	assert.Nil(t, pois.Distances(nil))
+
+	missing, err := finder.FindPOIs(ctx, "Cidade Inexistente")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+	assert.Nil(t, missing.Distances(NewGeoPoint(-21.3756, -46.5251)))
+
+	// O backfill usa a localização recém-geocodificada e só grava distâncias que mudaram
+	progress := BackfillProgress{}
+	update := bson.M{"location": NewGeoPoint(-21.3800, -46.5251)}
+	backfillPOIDistances(ctx, Property{Cidade: "Muzambinho"}, finder, update, &progress)
+	assert.Equal(t, &POIDistances{CentroKm: 0.49, EscolaKm: 0.01, HospitalKm: 9.53}, update["distancias_poi"])
+	assert.Equal(t, 1, progress.POIEnriched)
+
+	stored := Property{Cidade: "Muzambinho", Location: NewGeoPoint(-21.3800, -46.5251), DistanciasPOI: &POIDistances{CentroKm: 0.49, EscolaKm: 0.01, HospitalKm: 9.53}}
+	update = bson.M{}
+	backfillPOIDistances(ctx, stored, finder, update, &progress)
+	assert.Empty(t, update)
+}
+
+func TestKubernetesLeaseRepository(t *testing.T) {
+	// API do Kubernetes simulada: uma Lease com resourceVersion e conflito (409) em versões antigas
+	var mutex sync.Mutex
+	var stored *kubernetesLease
+	version := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		assert.Equal(t, "Bearer secret-token", r.Header.Get("Authorization"))
+
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/apis/coordination.k8s.io/v1/namespaces/crawler/leases/scheduler", r.URL.Path)
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+		case http.MethodPost, http.MethodPut:
+			var lease kubernetesLease
+			json.NewDecoder(r.Body).Decode(&lease)
+			if (r.Method == http.MethodPost && stored != nil) ||
+				(r.Method == http.MethodPut && lease.Metadata.ResourceVersion != fmt.Sprint(version)) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			version++
+			lease.Metadata.ResourceVersion = fmt.Sprint(version)
+			stored = &lease
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("secret-token\n"), 0o600))
+	leases := NewKubernetesLeaseRepository(server.URL, "crawler", tokenFile, nil)
+	ctx := context.Background()
+
+	lease, err := leases.FindLeaderLease(ctx, "scheduler")
+	assert.NoError(t, err)
+	assert.Nil(t, lease)
+
+	acquired, err := leases.AcquireLeaderLease(ctx, "scheduler", "api-0", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = leases.AcquireLeaderLease(ctx, "scheduler", "api-1", 30*time.Second)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// Renovação pelo líder mantém o acquireTime
+	acquiredAt := stored.Spec.AcquireTime
+	acquired, err = leases.AcquireLeaderLease(ctx, "scheduler", "api-0", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, acquiredAt, stored.Spec.AcquireTime)
+
+	lease, err = leases.FindLeaderLease(ctx, "scheduler")
+	assert.NoError(t, err)
+	assert.Equal(t, "api-0", lease.Holder)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), lease.ExpiresAt, 2*time.Second)
+
+	// A réplica que libera a lease permite o failover imediato
+	assert.NoError(t, leases.ReleaseLeaderLease(ctx, "scheduler", "api-1")) // não é o holder: nada muda
+	assert.Equal(t, "api-0", stored.Spec.HolderIdentity)
+	assert.NoError(t, leases.ReleaseLeaderLease(ctx, "scheduler", "api-0"))
+	acquired, err = leases.AcquireLeaderLease(ctx, "scheduler", "api-1", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, 1, stored.Spec.LeaseTransitions)
+	assert.Equal(t, 30, stored.Spec.LeaseDurationSeconds)
+
+	// Lease vencida é assumida por outra réplica
+	stored.Spec.RenewTime = time.Now().Add(-time.Minute).UTC().Format(kubernetesMicroTimeFormat)
+	acquired, err = leases.AcquireLeaderLease(ctx, "scheduler", "api-0", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, 2, stored.Spec.LeaseTransitions)
+}
+
+func TestPropertyPatch(t *testing.T) {
+	city, rooms, price := " Guaxupé ", 3, 450000.0
+	property := Property{Cidade: "Muzambinho", Quartos: 3, Valor: 400000, URL: "https://imob.com.br/imovel/1"}
+
+	t.Run("applies only changed fields", func(t *testing.T) {
+		p := property
+		changes, err := PropertyPatch{Cidade: &city, Quartos: &rooms, Valor: &price}.Apply(&p)
+		assert.NoError(t, err)
+		assert.Equal(t, "Guaxupé", p.Cidade)
+		assert.Equal(t, 450000.0, p.Valor)
+		assert.Equal(t, []FieldCorrection{
+			{Field: "cidade", OldValue: "Muzambinho", NewValue: "Guaxupé"},
+			{Field: "valor", OldValue: 400000.0, NewValue: 450000.0},
+		}, changes)
+	})
+
+	t.Run("rejects negative values", func(t *testing.T) {
+		negative := -1
+		p := property
+		_, err := PropertyPatch{Quartos: &negative}.Apply(&p)
+		assert.ErrorIs(t, err, ErrInvalidPropertyPatch)
+	})
+
+	t.Run("empty patch", func(t *testing.T) {
+		assert.True(t, PropertyPatch{}.IsEmpty())
+		assert.False(t, PropertyPatch{Cidade: &city}.IsEmpty())
+	})
+
+	t.Run("crawled version keeps corrected fields", func(t *testing.T) {
+		corrected := Property{Cidade: "Guaxupé", Bairro: "Centro", ManuallyCorrected: true, CorrectedFields: []string{"cidade"}}
+		corrected.SetProvenance("cidade", ExtractorManual, "")
+
+		crawled := Property{Cidade: "Muzambinho", Bairro: "Jardim"}
+		ApplyManualCorrections(&crawled, corrected)
+		assert.Equal(t, "Guaxupé", crawled.Cidade)
+		assert.Equal(t, "Jardim", crawled.Bairro, "campos não corrigidos seguem o crawling")
+		assert.True(t, crawled.ManuallyCorrected)
+		assert.Equal(t, ExtractorManual, crawled.Provenance["cidade"].Extractor)
+		assert.Equal(t, 1.0, crawled.FieldConfidence["cidade"])
+
+		untouched := Property{Cidade: "Muzambinho"}
+		ApplyManualCorrections(&untouched, Property{Cidade: "Guaxupé"})
+		assert.Equal(t, "Muzambinho", untouched.Cidade)
+	})
+
+	t.Run("update sets only corrected fields", func(t *testing.T) {
+		p := property
+		p.Bairro = "Centro"
+		p.ValorM2 = 4000
+		changes, err := PropertyPatch{Cidade: &city, Valor: &price}.Apply(&p)
+		assert.NoError(t, err)
+		for _, change := range changes {
+			p.SetProvenance(change.Field, ExtractorManual, "")
+			setFieldConfidence(&p, change.Field, 1)
+		}
+		p.CorrectedFields = []string{"cidade", "valor"}
+		p.Hash = "novo-hash"
+
+		update, err := correctionUpdate(p, changes)
+		assert.NoError(t, err)
+		assert.Equal(t, "Guaxupé", update["cidade"])
+		assert.Equal(t, 450000.0, update["valor"])
+		assert.Equal(t, p.Provenance["cidade"], update["provenance.cidade"])
+		assert.Equal(t, 1.0, update["field_confidence.valor"])
+		assert.Equal(t, "novo-hash", update["hash"])
+		assert.Equal(t, true, update["manually_corrected"])
+		assert.NotContains(t, update, "bairro")
+		assert.NotContains(t, update, "valor_m2", "campos derivados ficam para o próximo Save")
+		assert.NotContains(t, update, "provenance", "a origem dos outros campos não é sobrescrita")
+	})
+}
+
+func TestDomainConfig_LearnedSkipPaths(t *testing.T) {
+	assert.Equal(t, "/blog", URLPathPrefix("https://imob.com.br/Blog/dicas-de-financiamento"))
+	assert.Equal(t, "/contato", URLPathPrefix("https://imob.com.br/contato?origem=menu"))
+	assert.Equal(t, "", URLPathPrefix("https://imob.com.br/"))
+
+	config := DomainConfig{Domain: "imob.com.br", LearnedSkipPaths: []string{"/blog", "/contato"}}
+	assert.False(t, config.AllowsURL("https://imob.com.br/blog/post-1"))
+	assert.False(t, config.AllowsURL("https://imob.com.br/contato"))
+	assert.True(t, config.AllowsURL("https://imob.com.br/blogueiros-imoveis"), "o segmento inteiro precisa coincidir")
+	assert.True(t, config.AllowsURL("https://imob.com.br/imovel/123"))
+	assert.True(t, config.AllowsURL("https://imob.com.br/"))
+
+	policy := SkipPathPolicy{MinCrawls: 3, MinPages: 5}
+	assert.True(t, policy.Qualifies(PathStats{Crawls: 3, NonProperty: 8}))
+	assert.False(t, policy.Qualifies(PathStats{Crawls: 2, NonProperty: 8}))
+	assert.False(t, policy.Qualifies(PathStats{Crawls: 3, NonProperty: 8, Productive: 1}))
+	assert.False(t, policy.Qualifies(PathStats{Crawls: 3, NonProperty: 8, Ignored: true}))
+}
+
+func TestDiscoverySources(t *testing.T) {
+	config := DomainConfig{Domain: "imob.com.br", DisabledDiscoverySources: []string{DiscoverySourceInteractiveForm}}
+	assert.NoError(t, config.Validate())
+	assert.False(t, config.DiscoverySourceEnabled(DiscoverySourceInteractiveForm))
+	assert.True(t, config.DiscoverySourceEnabled(DiscoverySourcePagination))
+
+	config.DisabledDiscoverySources = []string{DiscoverySourceSeed}
+	assert.Error(t, config.Validate(), "seeds não podem ser desativadas")
+
+	yields := AggregateSourceYield([]CrawlReport{
+		{Sources: map[string]SourceYield{
+			DiscoverySourcePagination:      {Visited: 40, Properties: 30},
+			DiscoverySourceInteractiveForm: {Visited: 50, Properties: 1},
+		}},
+		{Sources: map[string]SourceYield{DiscoverySourcePagination: {Visited: 20, Properties: 15}}},
+		{},
+	})
+	if assert.Len(t, yields, 2) {
+		assert.Equal(t, DiscoverySourceYield{Source: DiscoverySourcePagination, Visited: 60, Properties: 45, Yield: 0.75, Crawls: 2}, yields[0])
+		assert.Equal(t, DiscoverySourceInteractiveForm, yields[1].Source)
+		assert.InDelta(t, 0.02, yields[1].Yield, 0.001)
+	}
+}
+
+func TestSummarizeCrawlJobCosts(t *testing.T) {
+	costs := []CrawlJobCost{
+		{JobID: "crawl-1", Mode: "incremental", Properties: 40, Bytes: 20 << 20, TotalCostUSD: 0.40},
+		{JobID: "crawl-2", Mode: "incremental", Properties: 60, Bytes: 30 << 20, TotalCostUSD: 0.60},
+		{JobID: "crawl-3", Mode: "incremental", AIEnabled: true, Properties: 50, AIPromptTokens: 90000, AIOutputTokens: 10000, TotalCostUSD: 2.50},
+		{JobID: "crawl-4", Mode: "full", Properties: 0, Bytes: 10 << 20, TotalCostUSD: 0.30},
+	}
+
+	// Do menor custo por imóvel ao maior; grupos sem imóveis no fim
+	summaries := SummarizeCrawlJobCosts(costs)
+	if assert.Len(t, summaries, 3) {
+		assert.Equal(t, "incremental", summaries[0].Mode)
+		assert.False(t, summaries[0].AIEnabled)
+		assert.Equal(t, 2, summaries[0].Jobs)
+		assert.Equal(t, int64(100), summaries[0].Properties)
+		assert.InDelta(t, 0.50, summaries[0].AvgCostPerJob, 1e-9)
+		assert.InDelta(t, 0.01, summaries[0].CostPerProperty, 1e-9)
+		assert.InDelta(t, 2.0, summaries[0].PropertiesPerMByte, 1e-9)
+
+		assert.True(t, summaries[1].AIEnabled)
+		assert.Equal(t, int64(100000), summaries[1].AITokens)
+		assert.InDelta(t, 0.05, summaries[1].CostPerProperty, 1e-9)
+
+		assert.Equal(t, "full", summaries[2].Mode)
+		assert.Zero(t, summaries[2].CostPerProperty)
+	}
+	assert.Empty(t, SummarizeCrawlJobCosts(nil))
+}
+
+func TestCitySitesFreshness(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	sla := 48 * time.Hour
+
+	fresh := CitySites{LastRefresh: now.Add(-30 * time.Hour)}
+	freshness := fresh.EvaluateFreshness(sla, now)
+	assert.False(t, freshness.Stale)
+	assert.Equal(t, 48, freshness.SLAHours)
+	assert.Equal(t, 30.0, freshness.AgeHours)
+
+	// SLA próprio da cidade substitui o padrão
+	fresh.RefreshSLAHours = 24
+	freshness = fresh.EvaluateFreshness(sla, now)
+	assert.True(t, freshness.Stale)
+	assert.Equal(t, StaleNotScheduled, freshness.Reason)
+
+	// Sem atualização, o prazo conta desde a descoberta dos sites
+	discovered := CitySites{LastDiscovery: now.Add(-50 * time.Hour)}
+	freshness = discovered.EvaluateFreshness(sla, now)
+	assert.True(t, freshness.Stale)
+	assert.Nil(t, freshness.LastRefresh)
+	assert.Equal(t, StaleNeverRefreshed, freshness.Reason)
+
+	// Falhas depois da última atualização
+	failing := CitySites{LastRefresh: now.Add(-60 * time.Hour), LastRefreshAttempt: now.Add(-time.Hour), RefreshFailures: 2}
+	assert.Equal(t, StaleRefreshFailing, failing.EvaluateFreshness(sla, now).Reason)
+
+	// SLA zero desativa a verificação
+	assert.False(t, discovered.EvaluateFreshness(0, now).Stale)
+}
+
+func TestSinkRepository(t *testing.T) {
+	specs, err := ParseSinkSpecs([]string{"mongo", " stdout", "file:data/out.ndjson", "https://hooks.example.com/in", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []SinkSpec{
+		{Kind: SinkMongo},
+		{Kind: SinkStdout},
+		{Kind: SinkFile, Target: "data/out.ndjson"},
+		{Kind: SinkHTTP, Target: "https://hooks.example.com/in"},
+	}, specs)
+	assert.True(t, HasSink(specs, SinkMongo))
+	_, err = ParseSinkSpecs([]string{"kafka"})
+	assert.Error(t, err)
+	_, err = ParseSinkSpecs(nil)
+	assert.Error(t, err)
+
+	var received []Property
+	var mutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var property Property
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&property))
+		mutex.Lock()
+		received = append(received, property)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "properties.ndjson")
+	sinks, err := OpenSinks([]SinkSpec{{Kind: SinkFile, Target: path}, {Kind: SinkHTTP, Target: server.URL}}, nil)
+	assert.NoError(t, err)
+	repo := NewSinkRepository(nil, sinks)
+
+	ctx := context.Background()
+	assert.NoError(t, repo.Save(ctx, Property{URL: "https://imobiliaria.com.br/imovel/1", Valor: 350000}))
+	assert.NoError(t, repo.Save(ctx, Property{URL: "https://imobiliaria.com.br/imovel/2", Valor: 420000}))
+	repo.Close()
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 2)
+	assert.Len(t, received, 2)
+
+	// Sem repositório principal as consultas não são suportadas
+	_, err = repo.FindAll(ctx)
+	assert.Error(t, err)
+	stored, err := repo.FindByURL(ctx, "https://imobiliaria.com.br/imovel/1")
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// Uma saída com falha não impede as demais e o erro identifica a saída
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	buffer := &bytes.Buffer{}
+	repo = NewSinkRepository(nil, []PropertySink{NewJSONLinesSink("buffer", nopWriteCloser{buffer}), NewHTTPSink(failing.URL)})
+	err = repo.Save(ctx, Property{URL: "https://imobiliaria.com.br/imovel/3"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), failing.URL)
+	assert.Contains(t, buffer.String(), "imovel/3")
+}
+
 func TestMongoRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(MongoRepositoryTestSuite))
 }
+
+func TestAnonymizer(t *testing.T) {
+	anonymizer := NewAnonymizer("salt-teste")
+	original := Property{
+		ID:        "64b7f0c2e4b0a1b2c3d4e5f6",
+		Endereco:  "Rua das Flores, 123 - Centro, Muzambinho - MG, 37890-123",
+		Cidade:    "Muzambinho",
+		Bairro:    "Centro",
+		CEP:       "37890-123",
+		Descricao: "Casa com 3 quartos. Fale com o corretor João Silva (35) 99999-1234 ou contato@silvaimoveis.com.br, CRECI/MG 12345-J. Imobiliária Horizonte, veja www.silvaimoveis.com.br/casa",
+		Valor:     450000,
+		AreaTotal: 250,
+		URL:       "https://www.silvaimoveis.com.br/imovel/casa-centro-4521?ref=77",
+		ShortID:   "abcdefghjk",
+		Location:  NewGeoPoint(-21.3769, -46.5255),
+		FotoURLs:  []string{"https://www.silvaimoveis.com.br/fotos/1.jpg"},
+		Fotos:     1,
+		DiscoveryPath: []DiscoveryStep{
+			{URL: "https://www.silvaimoveis.com.br/venda?pagina=2", Kind: "pagination", Page: 2},
+		},
+		ArchiveURL:   "https://web.archive.org/web/2024/https://www.silvaimoveis.com.br/imovel/casa-centro-4521",
+		CustomFields: map[string]interface{}{"corretor": "João Silva"},
+	}
+
+	anonymized := anonymizer.Anonymize(original)
+
+	// Contatos, nomes e registro removidos; o restante do texto é mantido
+	for _, leaked := range []string{"99999-1234", "contato@", "silvaimoveis", "12345", "João", "Horizonte"} {
+		assert.NotContains(t, anonymized.Descricao, leaked)
+	}
+	assert.Contains(t, anonymized.Descricao, "Casa com 3 quartos")
+	assert.Contains(t, anonymized.Descricao, "[telefone]")
+	assert.Contains(t, anonymized.Descricao, "[email]")
+	assert.Contains(t, anonymized.Descricao, "CRECI [removido]")
+
+	// Número do endereço no mesmo quarteirão e CEP só com a região
+	assert.NotContains(t, anonymized.Endereco, "123")
+	assert.Contains(t, anonymized.Endereco, "Rua das Flores, 1")
+	assert.Contains(t, anonymized.Endereco, "37890-000")
+	assert.Equal(t, "37890-000", anonymized.CEP)
+
+	// Domínio trocado por apelido estável e números do caminho trocados
+	assert.NotContains(t, anonymized.URL, "silvaimoveis")
+	assert.NotContains(t, anonymized.URL, "4521")
+	assert.Contains(t, anonymized.URL, "/imovel/casa-centro-")
+	assert.True(t, strings.HasPrefix(anonymized.URL, "https://site-"))
+	assert.Equal(t, anonymized.URL, anonymizer.Anonymize(original).URL)
+	assert.Equal(t, strings.Split(anonymized.URL, "/")[2], strings.Split(anonymized.DiscoveryPath[0].URL, "/")[2])
+	assert.NotEqual(t, anonymized.URL, NewAnonymizer("outro-salt").Anonymize(original).URL)
+
+	// Localização deslocada em poucas centenas de metros
+	assert.NotEqual(t, original.Location.Latitude(), anonymized.Location.Latitude())
+	assert.InDelta(t, original.Location.Latitude(), anonymized.Location.Latitude(), anonymizeJitterDegrees)
+	assert.InDelta(t, original.Location.Longitude(), anonymized.Location.Longitude(), anonymizeJitterDegrees)
+
+	// Identificadores regenerados e dados que apontam para o anúncio original removidos
+	assert.NotEqual(t, original.ID, anonymized.ID)
+	assert.Len(t, anonymized.ID, 24)
+	assert.True(t, ValidShortID(anonymized.ShortID))
+	assert.NotEqual(t, original.ShortID, anonymized.ShortID)
+	assert.Equal(t, GeneratePropertyHash(anonymized), anonymized.Hash)
+	assert.Nil(t, anonymized.FotoURLs)
+	assert.Empty(t, anonymized.ArchiveURL)
+	assert.Nil(t, anonymized.CustomFields)
+
+	// Dados de mercado mantidos e original intacto
+	assert.Equal(t, original.Valor, anonymized.Valor)
+	assert.Equal(t, original.AreaTotal, anonymized.AreaTotal)
+	assert.Equal(t, 1, anonymized.Fotos)
+	assert.Equal(t, "Muzambinho", anonymized.Cidade)
+	assert.Contains(t, original.Descricao, "João Silva")
+}
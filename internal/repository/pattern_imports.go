@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PatternImportCollection guarda as importações de padrões aguardando aprovação e as já aplicadas
+const PatternImportCollection = "pattern_imports"
+
+// Situações de uma importação de padrões
+const (
+	PatternImportPending = "pending"
+	PatternImportApplied = "applied"
+)
+
+// PatternImportRecord é uma importação de padrões persistida. As pendentes expiram pelo índice TTL
+// de expires_at; as aplicadas ficam só com a chave de replay (checksum do arquivo e dos padrões
+// ativos quando foi aplicado), sem o arquivo
+type PatternImportRecord struct {
+	ID        string     `bson:"_id"`
+	Status    string     `bson:"status"`
+	ReplayKey string     `bson:"replay_key"`
+	Preview   []byte     `bson:"preview,omitempty"` // prévia em JSON
+	Data      []byte     `bson:"data,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
+}
+
+// PatternImportRepository guarda a fila de aprovação das importações de padrões
+type PatternImportRepository interface {
+	SavePatternImport(ctx context.Context, record PatternImportRecord) error
+	// FindPendingPatternImports retorna as importações pendentes não vencidas, da mais antiga para a mais recente
+	FindPendingPatternImports(ctx context.Context) ([]PatternImportRecord, error)
+	// TakePatternImport remove e retorna a importação pendente (nil se não existir); só uma
+	// réplica consegue aprovar ou rejeitar cada importação
+	TakePatternImport(ctx context.Context, id string) (*PatternImportRecord, error)
+	MarkPatternImportApplied(ctx context.Context, replayKey string, appliedAt time.Time) error
+	PatternImportApplied(ctx context.Context, replayKey string) (bool, error)
+}
+
+// patternImports retorna a coleção das importações de padrões
+func (r *MongoRepository) patternImports() *mongo.Collection {
+	return r.collection.Database().Collection(PatternImportCollection)
+}
+
+// ConfigurePatternImports cria os índices da coleção (expiração das pendentes e chave de replay)
+func (r *MongoRepository) ConfigurePatternImports(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "replay_key", Value: 1}}},
+	}
+	if _, err := r.patternImports().Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create pattern import indexes: %v", err)
+	}
+	return nil
+}
+
+// SavePatternImport grava uma importação pendente
+func (r *MongoRepository) SavePatternImport(ctx context.Context, record PatternImportRecord) error {
+	record.Status = PatternImportPending
+	if _, err := r.patternImports().InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("failed to save pattern import: %v", err)
+	}
+	return nil
+}
+
+// FindPendingPatternImports retorna as importações pendentes não vencidas
+func (r *MongoRepository) FindPendingPatternImports(ctx context.Context) ([]PatternImportRecord, error) {
+	filter := bson.M{"status": PatternImportPending, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.patternImports().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pattern imports: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []PatternImportRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode pattern imports: %v", err)
+	}
+	return records, nil
+}
+
+// TakePatternImport remove e retorna a importação pendente
+func (r *MongoRepository) TakePatternImport(ctx context.Context, id string) (*PatternImportRecord, error) {
+	var record PatternImportRecord
+	err := r.patternImports().FindOneAndDelete(ctx, bson.M{"_id": id, "status": PatternImportPending}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to take pattern import: %v", err)
+	}
+	return &record, nil
+}
+
+// MarkPatternImportApplied registra a chave de replay de uma importação aplicada
+func (r *MongoRepository) MarkPatternImportApplied(ctx context.Context, replayKey string, appliedAt time.Time) error {
+	_, err := r.patternImports().UpdateOne(ctx,
+		bson.M{"_id": PatternImportApplied + ":" + replayKey},
+		bson.M{"$set": bson.M{"status": PatternImportApplied, "replay_key": replayKey, "created_at": appliedAt}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark pattern import as applied: %v", err)
+	}
+	return nil
+}
+
+// PatternImportApplied informa se a importação com a chave de replay já foi aplicada
+func (r *MongoRepository) PatternImportApplied(ctx context.Context, replayKey string) (bool, error) {
+	count, err := r.patternImports().CountDocuments(ctx, bson.M{"status": PatternImportApplied, "replay_key": replayKey})
+	if err != nil {
+		return false, fmt.Errorf("failed to check pattern import: %v", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,518 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // formatos aceitos por image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Coleção e limites da detecção de fotos repetidas
+const (
+	FraudReviewCollection = "fraud_reviews"
+
+	DefaultPhotoHashLimit = 8        // fotos por anúncio que recebem hash perceptual
+	MaxPhotoURLs          = 30       // URLs de fotos guardadas por anúncio
+	maxPhotoSize          = 10 << 20 // 10MB por imagem
+	maxPhotoPixels        = 50000000 // dimensões máximas decodificadas (ex: 8000x6000)
+	photoPriceTolerance   = 0.15     // diferença de preço aceita entre anúncios do mesmo imóvel
+)
+
+// Situações de um item da fila de revisão
+const (
+	FraudReviewPending   = "pending"
+	FraudReviewConfirmed = "confirmed"
+	FraudReviewDismissed = "dismissed"
+)
+
+// Motivos pelos quais anúncios com a mesma foto foram considerados suspeitos
+const (
+	FraudReasonLocation = "localizacao_diferente"
+	FraudReasonAddress  = "endereco_diferente"
+	FraudReasonPrice    = "preco_diferente"
+)
+
+// PhotoHasher calcula os hashes perceptuais das fotos de um anúncio (fotos que não puderem ser
+// baixadas ou decodificadas são ignoradas)
+type PhotoHasher interface {
+	HashPhotos(ctx context.Context, urls []string) []string
+}
+
+// HTTPPhotoHasher baixa as fotos e calcula o dHash de cada uma, guardando o resultado por URL
+type HTTPPhotoHasher struct {
+	UserAgent string
+	Limit     int
+
+	client *http.Client
+	mutex  sync.Mutex
+	hashes map[string]string
+}
+
+// NewHTTPPhotoHasher cria o hasher; limit <= 0 usa DefaultPhotoHashLimit fotos por anúncio. As URLs
+// das fotos vêm dos anúncios, então client deve recusar endereços internos (ex:
+// crawler.NewGuardedClient); nil usa um cliente sem essa proteção, apenas para testes
+func NewHTTPPhotoHasher(userAgent string, limit int, client *http.Client) *HTTPPhotoHasher {
+	if limit <= 0 {
+		limit = DefaultPhotoHashLimit
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 20 * time.Second}
+	}
+	return &HTTPPhotoHasher{
+		UserAgent: userAgent,
+		Limit:     limit,
+		client:    client,
+		hashes:    make(map[string]string),
+	}
+}
+
+// HashPhotos retorna os hashes distintos das primeiras fotos do anúncio
+func (ph *HTTPPhotoHasher) HashPhotos(ctx context.Context, urls []string) []string {
+	seen := make(map[string]bool)
+	var hashes []string
+	for i, photoURL := range urls {
+		if i >= ph.Limit {
+			break
+		}
+		hash, err := ph.hashURL(ctx, photoURL)
+		if err != nil || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// hashURL baixa e calcula o hash de uma foto (com cache)
+func (ph *HTTPPhotoHasher) hashURL(ctx context.Context, photoURL string) (string, error) {
+	ph.mutex.Lock()
+	cached, exists := ph.hashes[photoURL]
+	ph.mutex.Unlock()
+	if exists {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, photoURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create photo request: %v", err)
+	}
+	if ph.UserAgent != "" {
+		req.Header.Set("User-Agent", ph.UserAgent)
+	}
+	resp, err := ph.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("photo request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("photo %s returned status %d", photoURL, resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxPhotoSize {
+		return "", fmt.Errorf("photo %s exceeds %d bytes", photoURL, maxPhotoSize)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPhotoSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read photo %s: %v", photoURL, err)
+	}
+	if len(data) > maxPhotoSize {
+		return "", fmt.Errorf("photo %s exceeds %d bytes", photoURL, maxPhotoSize)
+	}
+	// Imagens pequenas no arquivo podem declarar dimensões enormes (decodificação estouraria a memória)
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode photo %s: %v", photoURL, err)
+	}
+	if imgConfig.Width*imgConfig.Height > maxPhotoPixels {
+		return "", fmt.Errorf("photo %s exceeds %d pixels", photoURL, maxPhotoPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode photo %s: %v", photoURL, err)
+	}
+	hash := FormatSimHash(PerceptualHash(img))
+
+	ph.mutex.Lock()
+	ph.hashes[photoURL] = hash
+	ph.mutex.Unlock()
+	return hash, nil
+}
+
+// PerceptualHash calcula o dHash de 64 bits da imagem: reduzida a 9x8 em tons de cinza, cada bit
+// indica se um pixel é mais claro que o vizinho à direita. Recompressão, redimensionamento e
+// marcas d'água leves preservam o hash.
+func PerceptualHash(img image.Image) uint64 {
+	const width, height = 9, 8
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+
+	var gray [height][width]float64
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			gray[y][x] = averageLuminance(img, x0, y0, max(x1, x0+1), max(y1, y0+1))
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if gray[y][x] < gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// averageLuminance calcula a luminância média do bloco, amostrando no máximo 8x8 pixels
+func averageLuminance(img image.Image, x0, y0, x1, y1 int) float64 {
+	stepX := max((x1-x0)/8, 1)
+	stepY := max((y1-y0)/8, 1)
+	var sum float64
+	var count int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			count++
+		}
+	}
+	return sum / float64(count)
+}
+
+// FraudListing é um anúncio de um grupo com fotos repetidas
+type FraudListing struct {
+	URL      string  `bson:"url" json:"url"`
+	Endereco string  `bson:"endereco,omitempty" json:"endereco,omitempty"`
+	Bairro   string  `bson:"bairro,omitempty" json:"bairro,omitempty"`
+	Cidade   string  `bson:"cidade,omitempty" json:"cidade,omitempty"`
+	Valor    float64 `bson:"valor" json:"valor"`
+}
+
+// FraudReview é um item da fila de revisão: um par de anúncios que usa as mesmas fotos com
+// endereço ou preço diferentes. O ID deriva das duas URLs, então novas varreduras atualizam o
+// mesmo item mesmo quando outros anúncios passam a compartilhar as fotos.
+type FraudReview struct {
+	ID           string         `bson:"_id" json:"id"`
+	Listings     []FraudListing `bson:"listings" json:"listings"`
+	SharedPhotos []string       `bson:"shared_photos" json:"shared_photos"` // hashes perceptuais em comum
+	Reasons      []string       `bson:"reasons" json:"reasons"`
+	Status       string         `bson:"status" json:"status"`
+	CreatedAt    time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `bson:"updated_at" json:"updated_at"`
+	ResolvedAt   *time.Time     `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+}
+
+// PhotoFraudScan resume uma varredura de fotos repetidas
+type PhotoFraudScan struct {
+	Listings       int `json:"listings"`        // anúncios com hashes de fotos comparados
+	Groups         int `json:"groups"`          // pares suspeitos encontrados
+	Flagged        int `json:"flagged"`         // URLs marcadas com suspeito_fraude
+	PendingReviews int `json:"pending_reviews"` // itens aguardando revisão
+}
+
+// PhotoFraudRepository é implementado por repositórios capazes de detectar fotos repetidas e
+// manter a fila de revisão
+type PhotoFraudRepository interface {
+	DetectPhotoFraud(ctx context.Context) (*PhotoFraudScan, error)
+	FindFraudReviews(ctx context.Context, status string, limit int) ([]FraudReview, error)
+	ResolveFraudReview(ctx context.Context, id, status string) (*FraudReview, error)
+}
+
+// ErrFraudReviewNotFound indica que o item da fila de revisão não existe
+var ErrFraudReviewNotFound = errors.New("fraud review not found")
+
+// photoConflictReasons indica por que dois anúncios com a mesma foto não são o mesmo imóvel
+// republicado (nil se cidade, bairro, endereço e preço forem compatíveis). Campos vazios em um
+// dos anúncios não são comparados: falta de dado não é divergência
+func photoConflictReasons(a, b Property) []string {
+	differs := func(x, y string) bool {
+		x, y = utils.NormalizeText(x), utils.NormalizeText(y)
+		return x != "" && y != "" && x != y
+	}
+
+	var reasons []string
+	if differs(a.Cidade, b.Cidade) || differs(a.Bairro, b.Bairro) {
+		reasons = append(reasons, FraudReasonLocation)
+	}
+	if differs(a.Endereco, b.Endereco) {
+		reasons = append(reasons, FraudReasonAddress)
+	}
+	if a.Valor > 0 && b.Valor > 0 && math.Abs(a.Valor-b.Valor)/math.Max(a.Valor, b.Valor) > photoPriceTolerance {
+		reasons = append(reasons, FraudReasonPrice)
+	}
+	return reasons
+}
+
+// FindPhotoConflicts encontra os pares de anúncios (uma versão por URL) que compartilham fotos
+// idênticas com localização, endereço ou preço diferentes, um item de revisão por par. Unidades
+// do mesmo anúncio (ParentID) não são comparadas entre si.
+func FindPhotoConflicts(properties []Property) []FraudReview {
+	byPhoto := make(map[string][]int)
+	for i, property := range properties {
+		for _, hash := range property.FotoHashes {
+			// Hash zerado vem de imagens uniformes (placeholders), que não identificam o imóvel
+			if hash != "" && hash != FormatSimHash(0) {
+				byPhoto[hash] = append(byPhoto[hash], i)
+			}
+		}
+	}
+
+	type pairKey struct{ a, b int }
+	pairs := make(map[pairKey]*FraudReview)
+	for hash, indexes := range byPhoto {
+		for x := 0; x < len(indexes); x++ {
+			for y := x + 1; y < len(indexes); y++ {
+				first, second := indexes[x], indexes[y]
+				a, b := properties[first], properties[second]
+				if a.URL == b.URL || (a.ParentID != "" && a.ParentID == b.ParentID) {
+					continue
+				}
+				if a.URL > b.URL {
+					first, second = second, first
+				}
+				key := pairKey{first, second}
+				review, exists := pairs[key]
+				if !exists {
+					pairReasons := photoConflictReasons(a, b)
+					if len(pairReasons) == 0 {
+						continue
+					}
+					review = &FraudReview{Status: FraudReviewPending, Reasons: pairReasons}
+					pairs[key] = review
+				}
+				review.SharedPhotos = mergeSorted(review.SharedPhotos, []string{hash})
+			}
+		}
+	}
+
+	reviews := make([]FraudReview, 0, len(pairs))
+	for key, review := range pairs {
+		for _, index := range []int{key.a, key.b} {
+			property := properties[index]
+			review.Listings = append(review.Listings, FraudListing{
+				URL:      property.URL,
+				Endereco: property.Endereco,
+				Bairro:   property.Bairro,
+				Cidade:   property.Cidade,
+				Valor:    property.Valor,
+			})
+		}
+		review.ID = fmt.Sprintf("%x", sha256.Sum256([]byte(review.Listings[0].URL+"|"+review.Listings[1].URL)))[:24]
+		reviews = append(reviews, *review)
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].ID < reviews[j].ID })
+	return reviews
+}
+
+// mergeSorted une duas listas sem repetição, em ordem alfabética
+func mergeSorted(current, values []string) []string {
+	seen := make(map[string]bool, len(current)+len(values))
+	var merged []string
+	for _, value := range append(append([]string(nil), current...), values...) {
+		if !seen[value] {
+			seen[value] = true
+			merged = append(merged, value)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// fraudReviews retorna a coleção da fila de revisão
+func (r *MongoRepository) fraudReviews() *mongo.Collection {
+	return r.collection.Database().Collection(FraudReviewCollection)
+}
+
+// DetectPhotoFraud compara os hashes das fotos da versão mais recente de cada anúncio, marca os
+// anúncios dos pares suspeitos com suspeito_fraude e os envia à fila de revisão. Pares já
+// descartados por um revisor não voltam a ser marcados.
+func (r *MongoRepository) DetectPhotoFraud(ctx context.Context) (*PhotoFraudScan, error) {
+	projection := bson.M{
+		"url": 1, "endereco": 1, "bairro": 1, "cidade": 1, "valor": 1, "parent_id": 1, "foto_hashes": 1,
+	}
+	findOpts := options.Find().SetProjection(projection).SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"foto_hashes.0": bson.M{"$exists": true}}, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find properties with photo hashes: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var versions []Property
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode properties with photo hashes: %v", err)
+	}
+
+	// Cada versão do conteúdo é um documento: vale a mais recente de cada URL
+	latest := make(map[string]int)
+	var properties []Property
+	for _, version := range versions {
+		if index, exists := latest[version.URL]; exists {
+			properties[index] = version
+			continue
+		}
+		latest[version.URL] = len(properties)
+		properties = append(properties, version)
+	}
+
+	scan := &PhotoFraudScan{Listings: len(properties)}
+	flagged := make(map[string]bool)
+	now := time.Now()
+	for _, review := range FindPhotoConflicts(properties) {
+		var existing FraudReview
+		err := r.fraudReviews().FindOne(ctx, bson.M{"_id": review.ID}).Decode(&existing)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("failed to load fraud review: %v", err)
+		}
+		if err == nil && existing.Status == FraudReviewDismissed {
+			continue
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"listings":      review.Listings,
+				"shared_photos": review.SharedPhotos,
+				"reasons":       review.Reasons,
+				"updated_at":    now,
+			},
+			"$setOnInsert": bson.M{"status": FraudReviewPending, "created_at": now},
+		}
+		if _, err := r.fraudReviews().UpdateOne(ctx, bson.M{"_id": review.ID}, update, options.Update().SetUpsert(true)); err != nil {
+			return nil, fmt.Errorf("failed to save fraud review: %v", err)
+		}
+
+		urls := fraudListingURLs(review.Listings)
+		if _, err := r.collection.UpdateMany(ctx, bson.M{"url": bson.M{"$in": urls}}, bson.M{"$set": bson.M{"suspeito_fraude": true}}); err != nil {
+			return nil, fmt.Errorf("failed to flag suspicious properties: %v", err)
+		}
+		scan.Groups++
+		for _, url := range urls {
+			flagged[url] = true
+		}
+	}
+	scan.Flagged = len(flagged)
+
+	pending, err := r.fraudReviews().CountDocuments(ctx, bson.M{"status": FraudReviewPending})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending fraud reviews: %v", err)
+	}
+	scan.PendingReviews = int(pending)
+	return scan, nil
+}
+
+// FindFraudReviews lista a fila de revisão (todas as situações se status for vazio), mais
+// recentes primeiro
+func (r *MongoRepository) FindFraudReviews(ctx context.Context, status string, limit int) ([]FraudReview, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	findOpts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	if limit > 0 {
+		findOpts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.fraudReviews().Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fraud reviews: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []FraudReview{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to decode fraud reviews: %v", err)
+	}
+	return reviews, nil
+}
+
+// ResolveFraudReview registra a decisão do revisor: confirmed mantém os anúncios marcados e
+// dismissed remove a marcação dos anúncios que não estão em outro par (e impede que o par volte
+// à fila)
+func (r *MongoRepository) ResolveFraudReview(ctx context.Context, id, status string) (*FraudReview, error) {
+	if status != FraudReviewConfirmed && status != FraudReviewDismissed {
+		return nil, fmt.Errorf("invalid review status %q (use %s or %s)", status, FraudReviewConfirmed, FraudReviewDismissed)
+	}
+
+	now := time.Now()
+	var review FraudReview
+	err := r.fraudReviews().FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": status, "resolved_at": now, "updated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrFraudReviewNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve fraud review: %v", err)
+	}
+
+	if status == FraudReviewDismissed {
+		// Anúncios que seguem em outro par pendente ou confirmado continuam marcados
+		still := make(map[string]bool)
+		others, err := r.fraudReviews().Find(ctx, bson.M{
+			"_id":          bson.M{"$ne": id},
+			"status":       bson.M{"$ne": FraudReviewDismissed},
+			"listings.url": bson.M{"$in": fraudListingURLs(review.Listings)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find related fraud reviews: %v", err)
+		}
+		var related []FraudReview
+		if err := others.All(ctx, &related); err != nil {
+			return nil, fmt.Errorf("failed to decode related fraud reviews: %v", err)
+		}
+		for _, other := range related {
+			for _, url := range fraudListingURLs(other.Listings) {
+				still[url] = true
+			}
+		}
+
+		var urls []string
+		for _, url := range fraudListingURLs(review.Listings) {
+			if !still[url] {
+				urls = append(urls, url)
+			}
+		}
+		if len(urls) == 0 {
+			return &review, nil
+		}
+		if _, err := r.collection.UpdateMany(ctx, bson.M{"url": bson.M{"$in": urls}}, bson.M{"$set": bson.M{"suspeito_fraude": false}}); err != nil {
+			return nil, fmt.Errorf("failed to clear fraud flag: %v", err)
+		}
+	}
+	return &review, nil
+}
+
+// fraudListingURLs retorna as URLs dos anúncios de um item de revisão
+func fraudListingURLs(listings []FraudListing) []string {
+	urls := make([]string, 0, len(listings))
+	for _, listing := range listings {
+		urls = append(urls, listing.URL)
+	}
+	return urls
+}
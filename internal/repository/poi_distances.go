@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// POIDistances guarda a distância em km do imóvel até os pontos de interesse mais próximos
+// (0 quando desconhecida)
+type POIDistances struct {
+	CentroKm   float64 `bson:"centro_km,omitempty" json:"centro_km,omitempty"`
+	EscolaKm   float64 `bson:"escola_km,omitempty" json:"escola_km,omitempty"`
+	HospitalKm float64 `bson:"hospital_km,omitempty" json:"hospital_km,omitempty"`
+}
+
+// CityPOIs são os pontos de interesse de uma cidade: o centro e as escolas e hospitais próximos
+type CityPOIs struct {
+	Centro    *GeoPoint
+	Escolas   []GeoPoint
+	Hospitais []GeoPoint
+}
+
+// Distances calcula as distâncias da localização até o centro e à escola e ao hospital mais
+// próximos (nil sem localização ou sem pontos de interesse)
+func (c *CityPOIs) Distances(location *GeoPoint) *POIDistances {
+	if c == nil || location == nil || len(location.Coordinates) < 2 {
+		return nil
+	}
+	origin := [2]float64{location.Longitude(), location.Latitude()}
+	kilometers := func(point GeoPoint) float64 {
+		return distanceMeters(origin, [2]float64{point.Longitude(), point.Latitude()}) / 1000
+	}
+	nearest := func(points []GeoPoint) float64 {
+		best := 0.0
+		for i, point := range points {
+			if distance := kilometers(point); i == 0 || distance < best {
+				best = distance
+			}
+		}
+		return best
+	}
+	// Arredonda em 10m, sem zerar imóveis a menos de 5m do ponto (0 significa desconhecida)
+	round := func(km float64) float64 {
+		return math.Max(math.Round(km*100)/100, 0.01)
+	}
+
+	distances := &POIDistances{}
+	if c.Centro != nil {
+		distances.CentroKm = round(kilometers(*c.Centro))
+	}
+	if len(c.Escolas) > 0 {
+		distances.EscolaKm = round(nearest(c.Escolas))
+	}
+	if len(c.Hospitais) > 0 {
+		distances.HospitalKm = round(nearest(c.Hospitais))
+	}
+	if *distances == (POIDistances{}) {
+		return nil
+	}
+	return distances
+}
+
+// POIFinder fornece os pontos de interesse de uma cidade (nil quando a cidade não é encontrada)
+type POIFinder interface {
+	FindPOIs(ctx context.Context, cidade string) (*CityPOIs, error)
+}
+
+// OverpassPOIFinder localiza o centro da cidade pelo Nominatim e busca as escolas e hospitais
+// num raio ao redor dele na Overpass API (dados do OpenStreetMap). Os resultados ficam em cache
+// por cidade: cada cidade custa duas consultas, independente do número de anúncios.
+type OverpassPOIFinder struct {
+	OverpassURL string
+	UserAgent   string
+	RadiusKm    float64
+
+	geocoder *NominatimGeocoder
+	client   *http.Client
+	mutex    sync.Mutex
+	cities   map[string]*CityPOIs
+}
+
+// NewOverpassPOIFinder cria o localizador; radiusKm <= 0 usa 15km ao redor do centro
+func NewOverpassPOIFinder(overpassURL, userAgent string, radiusKm float64, geocoder *NominatimGeocoder) *OverpassPOIFinder {
+	if radiusKm <= 0 {
+		radiusKm = 15
+	}
+	return &OverpassPOIFinder{
+		OverpassURL: overpassURL,
+		UserAgent:   userAgent,
+		RadiusKm:    radiusKm,
+		geocoder:    geocoder,
+		client:      &http.Client{Timeout: 90 * time.Second},
+		cities:      make(map[string]*CityPOIs),
+	}
+}
+
+// FindPOIs retorna o centro, as escolas e os hospitais da cidade (com cache)
+func (f *OverpassPOIFinder) FindPOIs(ctx context.Context, cidade string) (*CityPOIs, error) {
+	key := utils.NormalizeText(cidade)
+	if key == "" {
+		return nil, nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if pois, cached := f.cities[key]; cached {
+		return pois, nil
+	}
+
+	centro, err := f.geocoder.GeocodeCity(ctx, cidade)
+	if err != nil {
+		return nil, err
+	}
+	if centro == nil {
+		f.cities[key] = nil
+		return nil, nil
+	}
+
+	pois, err := f.queryOverpass(ctx, centro)
+	if err != nil {
+		return nil, err
+	}
+	f.cities[key] = pois
+	return pois, nil
+}
+
+// queryOverpass busca escolas e hospitais (nós, vias e relações) no raio ao redor do centro
+func (f *OverpassPOIFinder) queryOverpass(ctx context.Context, centro *GeoPoint) (*CityPOIs, error) {
+	around := fmt.Sprintf("(around:%.0f,%f,%f)", f.RadiusKm*1000, centro.Latitude(), centro.Longitude())
+	query := `[out:json][timeout:60];(nwr["amenity"="school"]` + around + `;nwr["amenity"="hospital"]` + around + `;);out center;`
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.OverpassURL, strings.NewReader(url.Values{"data": {query}}.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create overpass request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", f.UserAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("overpass request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("overpass returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Elements []struct {
+			Lat    float64 `json:"lat"`
+			Lon    float64 `json:"lon"`
+			Center *struct {
+				Lat float64 `json:"lat"`
+				Lon float64 `json:"lon"`
+			} `json:"center"`
+			Tags map[string]string `json:"tags"`
+		} `json:"elements"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid overpass response: %v", err)
+	}
+
+	pois := &CityPOIs{Centro: centro}
+	for _, element := range result.Elements {
+		latitude, longitude := element.Lat, element.Lon
+		if element.Center != nil {
+			latitude, longitude = element.Center.Lat, element.Center.Lon
+		}
+		if latitude == 0 && longitude == 0 {
+			continue
+		}
+		switch element.Tags["amenity"] {
+		case "school":
+			pois.Escolas = append(pois.Escolas, *NewGeoPoint(latitude, longitude))
+		case "hospital":
+			pois.Hospitais = append(pois.Hospitais, *NewGeoPoint(latitude, longitude))
+		}
+	}
+	return pois, nil
+}
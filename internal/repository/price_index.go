@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Coleção e limites do índice de preços
+const (
+	PriceIndexCollection  = "indices"
+	PriceIndexMonthFormat = "2006-01"
+
+	priceIndexMinSamples    = 3      // bairros com menos anúncios não entram no índice
+	priceIndexMinArea       = 10     // m²; áreas menores costumam ser erro de extração
+	priceIndexMinPricePerM2 = 100    // R$/m²; valores fora da faixa são descartados
+	priceIndexMaxPricePerM2 = 100000 // R$/m²
+)
+
+// PriceIndexPoint é a mediana do R$/m² de uma cidade (Bairro vazio) ou bairro em um mês
+type PriceIndexPoint struct {
+	Cidade           string    `bson:"cidade" json:"cidade"`
+	Bairro           string    `bson:"bairro,omitempty" json:"bairro,omitempty"`
+	CityKey          string    `bson:"city_key" json:"-"`
+	BairroKey        string    `bson:"bairro_key" json:"-"`
+	Month            string    `bson:"month" json:"month"` // AAAA-MM
+	MedianPricePerM2 float64   `bson:"median_price_per_m2" json:"median_price_per_m2"`
+	Samples          int       `bson:"samples" json:"samples"`
+	ComputedAt       time.Time `bson:"computed_at" json:"computed_at"`
+}
+
+// PriceIndexRepository é implementado por repositórios capazes de calcular e consultar o índice
+type PriceIndexRepository interface {
+	ComputePriceIndex(ctx context.Context, month time.Time) ([]PriceIndexPoint, error)
+	FindPriceIndex(ctx context.Context, cidade, bairro string) ([]PriceIndexPoint, error)
+}
+
+// priceSample é o R$/m² de um anúncio usado no cálculo
+type priceSample struct {
+	Cidade       string  `bson:"cidade"`
+	Bairro       string  `bson:"bairro"`
+	Valor        float64 `bson:"valor"`
+	ValorVenda   float64 `bson:"valor_venda"`
+	ValorAluguel float64 `bson:"valor_aluguel"`
+	Area         float64 `bson:"area"`
+}
+
+// ComputePriceIndex calcula a mediana do R$/m² de venda por cidade e bairro a partir dos anúncios
+// atuais (aluguéis ficam de fora) e grava os pontos do mês na coleção indices (recalcular o mesmo mês os substitui)
+func (r *MongoRepository) ComputePriceIndex(ctx context.Context, month time.Time) ([]PriceIndexPoint, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"valor":  bson.M{"$gt": 0},
+			"cidade": bson.M{"$nin": bson.A{"", nil}},
+			"leilao": bson.M{"$ne": true}, // lances de leilão distorcem as medianas
+			// diárias de temporada não são comparáveis ao preço de venda
+			"finalidade": bson.M{"$ne": FinalidadeTemporada},
+		}},
+		bson.M{"$project": bson.M{
+			"cidade":        1,
+			"bairro":        1,
+			"valor":         1,
+			"valor_venda":   1,
+			"valor_aluguel": 1,
+			"area": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$area_util", 0}}, "$area_util", "$area_total",
+			}},
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate price samples: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var samples []priceSample
+	for cursor.Next(ctx) {
+		var sample priceSample
+		if err := cursor.Decode(&sample); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price samples: %v", err)
+	}
+
+	points := buildPriceIndex(samples, month.Format(PriceIndexMonthFormat), time.Now())
+	if len(points) == 0 {
+		return points, nil
+	}
+
+	indices := r.collection.Database().Collection(PriceIndexCollection)
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "city_key", Value: 1}, {Key: "bairro_key", Value: 1}, {Key: "month", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := indices.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return nil, fmt.Errorf("failed to create price index indexes: %v", err)
+	}
+
+	models := make([]mongo.WriteModel, 0, len(points))
+	for _, point := range points {
+		filter := bson.M{"city_key": point.CityKey, "bairro_key": point.BairroKey, "month": point.Month}
+		models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(point).SetUpsert(true))
+	}
+	if _, err := indices.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return nil, fmt.Errorf("failed to save price index: %v", err)
+	}
+	return points, nil
+}
+
+// FindPriceIndex retorna a série mensal da cidade ou, se informado, do bairro
+func (r *MongoRepository) FindPriceIndex(ctx context.Context, cidade, bairro string) ([]PriceIndexPoint, error) {
+	filter := bson.M{
+		"city_key":   utils.NormalizeText(cidade),
+		"bairro_key": utils.NormalizeText(bairro),
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "month", Value: 1}})
+
+	cursor, err := r.collection.Database().Collection(PriceIndexCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find price index: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	points := []PriceIndexPoint{}
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, fmt.Errorf("failed to decode price index: %v", err)
+	}
+	return points, nil
+}
+
+// buildPriceIndex agrupa as amostras válidas por cidade e bairro e calcula as medianas
+func buildPriceIndex(samples []priceSample, month string, computedAt time.Time) []PriceIndexPoint {
+	type group struct {
+		cidade, bairro string
+		values         []float64
+	}
+	groups := make(map[[2]string]*group)
+	add := func(cityKey, bairroKey, cidade, bairro string, value float64) {
+		key := [2]string{cityKey, bairroKey}
+		g, exists := groups[key]
+		if !exists {
+			g = &group{cidade: cidade, bairro: bairro}
+			groups[key] = g
+		}
+		g.values = append(g.values, value)
+	}
+
+	for _, sample := range samples {
+		// Só anúncios de venda: o aluguel mensal por m² derrubaria a mediana
+		if sample.ValorAluguel > 0 || sample.ValorVenda <= 0 {
+			continue
+		}
+		if sample.Valor <= 0 || sample.Area < priceIndexMinArea {
+			continue
+		}
+		pricePerM2 := sample.Valor / sample.Area
+		if pricePerM2 < priceIndexMinPricePerM2 || pricePerM2 > priceIndexMaxPricePerM2 {
+			continue
+		}
+		cityKey := utils.NormalizeText(sample.Cidade)
+		if cityKey == "" {
+			continue
+		}
+
+		add(cityKey, "", sample.Cidade, "", pricePerM2)
+		if bairroKey := utils.NormalizeText(sample.Bairro); bairroKey != "" {
+			add(cityKey, bairroKey, sample.Cidade, sample.Bairro, pricePerM2)
+		}
+	}
+
+	points := make([]PriceIndexPoint, 0, len(groups))
+	for key, g := range groups {
+		if len(g.values) < priceIndexMinSamples {
+			continue
+		}
+		points = append(points, PriceIndexPoint{
+			Cidade:           g.cidade,
+			Bairro:           g.bairro,
+			CityKey:          key[0],
+			BairroKey:        key[1],
+			Month:            month,
+			MedianPricePerM2: median(g.values),
+			Samples:          len(g.values),
+			ComputedAt:       computedAt,
+		})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].CityKey != points[j].CityKey {
+			return points[i].CityKey < points[j].CityKey
+		}
+		return points[i].BairroKey < points[j].BairroKey
+	})
+	return points
+}
+
+// median retorna a mediana dos valores (arredondada em centavos)
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	result := sorted[middle]
+	if len(sorted)%2 == 0 {
+		result = (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return float64(int64(result*100+0.5)) / 100
+}
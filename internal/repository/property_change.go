@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Tipos de mudança detectados entre a versão armazenada e a recém-extraída de um anúncio
+const (
+	ChangePrice       = "price"       // valor alterado
+	ChangeDescription = "description" // descrição reescrita (não apenas pequenos ajustes)
+	ChangePhotos      = "photos"      // quantidade de fotos alterada
+	ChangeStatus      = "status"      // disponível → vendido/alugado/reservado (ou o contrário)
+	ChangeDetails     = "details"     // endereço, quartos, banheiros, área ou tipo
+	ChangeCosmetic    = "cosmetic"    // nada relevante mudou (ex.: datas, contadores, layout)
+)
+
+// materialChanges são as mudanças que justificam reprocessar o anúncio com IA
+var materialChanges = map[string]bool{
+	ChangePrice:       true,
+	ChangeDescription: true,
+	ChangeStatus:      true,
+	ChangeDetails:     true,
+}
+
+// descriptionSimilarityThreshold é a similaridade mínima (Jaccard das palavras) para
+// considerar que a descrição sofreu apenas ajustes cosméticos
+const descriptionSimilarityThreshold = 0.9
+
+// listingStatusKeywords identificam anúncios que deixaram de estar disponíveis
+var listingStatusKeywords = []struct {
+	keyword string
+	status  string
+}{
+	{"vendido", "vendido"},
+	{"alugado", "alugado"},
+	{"reservado", "reservado"},
+	{"indisponivel", "indisponivel"},
+	{"negociado", "vendido"},
+}
+
+// PropertyChange descreve a diferença entre duas versões de um anúncio
+type PropertyChange struct {
+	Kinds          []string `json:"kinds"`
+	Material       bool     `json:"material"`
+	PreviousValor  float64  `json:"previous_valor,omitempty"`
+	CurrentValor   float64  `json:"current_valor,omitempty"`
+	PreviousStatus string   `json:"previous_status,omitempty"`
+	CurrentStatus  string   `json:"current_status,omitempty"`
+}
+
+// ExtractionSnapshot guarda os campos comparados por ClassifyPropertyChange como foram extraídos
+// da página, antes da correção por IA, para que a próxima visita compare a extração nova com a
+// extração anterior e não com o registro corrigido
+type ExtractionSnapshot struct {
+	Endereco   string  `bson:"endereco" json:"endereco"`
+	Descricao  string  `bson:"descricao" json:"descricao"`
+	Valor      float64 `bson:"valor" json:"valor"`
+	ValorTexto string  `bson:"valor_texto" json:"valor_texto"`
+	Quartos    int     `bson:"quartos" json:"quartos"`
+	Banheiros  int     `bson:"banheiros" json:"banheiros"`
+	AreaTotal  float64 `bson:"area_total" json:"area_total"`
+	TipoImovel string  `bson:"tipo_imovel" json:"tipo_imovel"`
+	Fotos      int     `bson:"fotos" json:"fotos"`
+}
+
+// NewExtractionSnapshot copia os campos comparados do anúncio recém-extraído
+func NewExtractionSnapshot(p Property) *ExtractionSnapshot {
+	return &ExtractionSnapshot{
+		Endereco:   p.Endereco,
+		Descricao:  p.Descricao,
+		Valor:      p.Valor,
+		ValorTexto: p.ValorTexto,
+		Quartos:    p.Quartos,
+		Banheiros:  p.Banheiros,
+		AreaTotal:  p.AreaTotal,
+		TipoImovel: p.TipoImovel,
+		Fotos:      p.Fotos,
+	}
+}
+
+// Property retorna a extração anterior no formato usado por ClassifyPropertyChange
+func (s ExtractionSnapshot) Property() Property {
+	return Property{
+		Endereco:   s.Endereco,
+		Descricao:  s.Descricao,
+		Valor:      s.Valor,
+		ValorTexto: s.ValorTexto,
+		Quartos:    s.Quartos,
+		Banheiros:  s.Banheiros,
+		AreaTotal:  s.AreaTotal,
+		TipoImovel: s.TipoImovel,
+		Fotos:      s.Fotos,
+	}
+}
+
+// PropertyLookupRepository é implementado por repositórios capazes de buscar o anúncio salvo de uma URL
+type PropertyLookupRepository interface {
+	FindByURL(ctx context.Context, url string) (*Property, error)
+}
+
+// ClassifyPropertyChange compara o anúncio armazenado com o recém-extraído
+func ClassifyPropertyChange(stored, current Property) PropertyChange {
+	change := PropertyChange{}
+	add := func(kind string) {
+		change.Kinds = append(change.Kinds, kind)
+		if materialChanges[kind] {
+			change.Material = true
+		}
+	}
+
+	if math.Round(stored.Valor) != math.Round(current.Valor) {
+		change.PreviousValor = stored.Valor
+		change.CurrentValor = current.Valor
+		add(ChangePrice)
+	}
+
+	if descriptionSimilarity(stored.Descricao, current.Descricao) < descriptionSimilarityThreshold {
+		add(ChangeDescription)
+	}
+
+	if stored.Fotos != current.Fotos {
+		add(ChangePhotos)
+	}
+
+	previousStatus, currentStatus := ListingStatus(stored), ListingStatus(current)
+	if previousStatus != currentStatus {
+		change.PreviousStatus = previousStatus
+		change.CurrentStatus = currentStatus
+		add(ChangeStatus)
+	}
+
+	if utils.NormalizeText(stored.Endereco) != utils.NormalizeText(current.Endereco) ||
+		stored.Quartos != current.Quartos ||
+		stored.Banheiros != current.Banheiros ||
+		math.Round(stored.AreaTotal) != math.Round(current.AreaTotal) ||
+		!strings.EqualFold(stored.TipoImovel, current.TipoImovel) {
+		add(ChangeDetails)
+	}
+
+	if len(change.Kinds) == 0 {
+		change.Kinds = []string{ChangeCosmetic}
+	}
+	return change
+}
+
+// ListingStatus deduz a situação do anúncio pelo texto (disponivel, vendido, alugado, ...)
+func ListingStatus(p Property) string {
+	text := " " + utils.NormalizeText(p.ValorTexto+" "+p.Descricao) + " "
+	for _, entry := range listingStatusKeywords {
+		if strings.Contains(text, " "+entry.keyword+" ") {
+			return entry.status
+		}
+	}
+	return "disponivel"
+}
+
+// descriptionSimilarity calcula a similaridade de Jaccard entre as palavras das descrições
+func descriptionSimilarity(a, b string) float64 {
+	wordsA := strings.Fields(utils.NormalizeText(a))
+	wordsB := strings.Fields(utils.NormalizeText(b))
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	set := make(map[string]int, len(wordsA))
+	for _, word := range wordsA {
+		set[word] = 1
+	}
+	for _, word := range wordsB {
+		set[word] |= 2
+	}
+
+	intersection := 0
+	for _, membership := range set {
+		if membership == 3 {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(set))
+}
+
+// FindByURL retorna o anúncio salvo mais recentemente para a URL (nil se não existir)
+func (r *MongoRepository) FindByURL(ctx context.Context, url string) (*Property, error) {
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+
+	raw, err := r.collection.FindOne(ctx, bson.M{"url": normalizeURL(url)}, findOptions).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property by url: %v", err)
+	}
+	property, _, err := decodeProperty(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode property: %v", err)
+	}
+	return &property, nil
+}
+
+// ErrPropertyNotFound indica que o imóvel (ou a foto pedida do imóvel) não existe
+var ErrPropertyNotFound = errors.New("property not found")
+
+// PropertyIDLookupRepository é implementado por repositórios capazes de buscar o imóvel pelo ID
+type PropertyIDLookupRepository interface {
+	FindByID(ctx context.Context, id string) (*Property, error)
+}
+
+// FindByID retorna o imóvel pelo ID (ErrPropertyNotFound se não existir)
+func (r *MongoRepository) FindByID(ctx context.Context, id string) (*Property, error) {
+	raw, err := r.collection.FindOne(ctx, propertyIDFilter(id)).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPropertyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property by id: %v", err)
+	}
+	property, _, err := decodeProperty(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode property: %v", err)
+	}
+	return &property, nil
+}
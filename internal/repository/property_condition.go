@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// Rótulos de condição do imóvel, do pior para o melhor estado
+const (
+	CondicaoPrecisaReforma = "precisa_reforma"
+	CondicaoUsado          = "usado"
+	CondicaoReformado      = "reformado"
+	CondicaoNovo           = "novo"
+)
+
+// conditionLevels mapeia cada rótulo para seu valor ordinal (1 = pior, 4 = melhor)
+var conditionLevels = map[string]int{
+	CondicaoPrecisaReforma: 1,
+	CondicaoUsado:          2,
+	CondicaoReformado:      3,
+	CondicaoNovo:           4,
+}
+
+// NormalizeCondition converte variações de texto para um dos rótulos conhecidos, comparando
+// palavras inteiras ("renovado" contém "nova", mas é reformado).
+// Retorna string vazia se o texto não corresponder a nenhuma condição.
+func NormalizeCondition(condicao string) string {
+	replacer := strings.NewReplacer("_", " ", "-", " ")
+	normalized := utils.NormalizeText(replacer.Replace(condicao))
+	words := strings.Fields(normalized)
+
+	switch {
+	case len(words) == 0:
+		return ""
+	case hasConditionWord(words, "precisa", "reformar"):
+		return CondicaoPrecisaReforma
+	case hasConditionPrefix(words, "reformad", "renovad"):
+		return CondicaoReformado
+	case hasConditionWord(words, "novo", "nova", "novos", "novas", "lancamento"):
+		return CondicaoNovo
+	case hasConditionPrefix(words, "usad", "conservad") || strings.Contains(" "+normalized+" ", " bom estado "):
+		return CondicaoUsado
+	}
+
+	return ""
+}
+
+// hasConditionWord informa se alguma das palavras é exatamente um dos termos
+func hasConditionWord(words []string, terms ...string) bool {
+	for _, word := range words {
+		for _, term := range terms {
+			if word == term {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasConditionPrefix informa se alguma das palavras começa com um dos radicais (reformado/a/os/as)
+func hasConditionPrefix(words []string, stems ...string) bool {
+	for _, word := range words {
+		for _, stem := range stems {
+			if strings.HasPrefix(word, stem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ConditionLevel retorna o valor ordinal de uma condição (0 se desconhecida)
+func ConditionLevel(condicao string) int {
+	return conditionLevels[NormalizeCondition(condicao)]
+}
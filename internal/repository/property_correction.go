@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PropertyCorrectionCollection guarda o histórico das correções manuais dos imóveis
+const PropertyCorrectionCollection = "property_corrections"
+
+// ExtractorManual identifica na proveniência os campos corrigidos manualmente pela API
+const ExtractorManual = "manual"
+
+// ErrInvalidPropertyPatch indica uma correção vazia ou com valores inválidos
+var ErrInvalidPropertyPatch = errors.New("invalid property patch")
+
+// PropertyPatch contém os campos corrigidos manualmente; campos nil não são alterados
+type PropertyPatch struct {
+	Endereco        *string   `json:"endereco,omitempty"`
+	Cidade          *string   `json:"cidade,omitempty"`
+	Bairro          *string   `json:"bairro,omitempty"`
+	CEP             *string   `json:"cep,omitempty"`
+	Descricao       *string   `json:"descricao,omitempty"`
+	Valor           *float64  `json:"valor,omitempty"`
+	Quartos         *int      `json:"quartos,omitempty"`
+	Banheiros       *int      `json:"banheiros,omitempty"`
+	AreaTotal       *float64  `json:"area_total,omitempty"`
+	AreaUtil        *float64  `json:"area_util,omitempty"`
+	TipoImovel      *string   `json:"tipo_imovel,omitempty"`
+	Caracteristicas *[]string `json:"caracteristicas,omitempty"`
+}
+
+// FieldCorrection registra o valor anterior e o corrigido de um campo
+type FieldCorrection struct {
+	Field    string      `bson:"field" json:"field"`
+	OldValue interface{} `bson:"old_value" json:"old_value"`
+	NewValue interface{} `bson:"new_value" json:"new_value"`
+}
+
+// PropertyCorrection é uma entrada do histórico de correções manuais de um imóvel
+type PropertyCorrection struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID  string             `bson:"property_id" json:"property_id"`
+	URL         string             `bson:"url" json:"url"`
+	Changes     []FieldCorrection  `bson:"changes" json:"changes"`
+	Reason      string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	CorrectedBy string             `bson:"corrected_by,omitempty" json:"corrected_by,omitempty"`
+	CorrectedAt time.Time          `bson:"corrected_at" json:"corrected_at"`
+}
+
+// PropertyCorrectionRepository é implementado por repositórios capazes de corrigir imóveis
+// manualmente e guardar o histórico das correções
+type PropertyCorrectionRepository interface {
+	CorrectProperty(ctx context.Context, id string, patch PropertyPatch, correctedBy, reason string) (*Property, *PropertyCorrection, error)
+	FindPropertyCorrections(ctx context.Context, id string) ([]PropertyCorrection, error)
+}
+
+// correctedFieldCopiers copiam um campo corrigido do imóvel salvo para a versão recém-extraída
+var correctedFieldCopiers = map[string]func(dst *Property, src Property){
+	"endereco":        func(dst *Property, src Property) { dst.Endereco = src.Endereco },
+	"cidade":          func(dst *Property, src Property) { dst.Cidade = src.Cidade },
+	"bairro":          func(dst *Property, src Property) { dst.Bairro = src.Bairro },
+	"cep":             func(dst *Property, src Property) { dst.CEP = src.CEP },
+	"descricao":       func(dst *Property, src Property) { dst.Descricao = src.Descricao },
+	"valor":           func(dst *Property, src Property) { dst.Valor = src.Valor },
+	"quartos":         func(dst *Property, src Property) { dst.Quartos = src.Quartos },
+	"banheiros":       func(dst *Property, src Property) { dst.Banheiros = src.Banheiros },
+	"area_total":      func(dst *Property, src Property) { dst.AreaTotal = src.AreaTotal },
+	"area_util":       func(dst *Property, src Property) { dst.AreaUtil = src.AreaUtil },
+	"tipo_imovel":     func(dst *Property, src Property) { dst.TipoImovel = src.TipoImovel },
+	"caracteristicas": func(dst *Property, src Property) { dst.Caracteristicas = src.Caracteristicas },
+}
+
+// Apply valida a correção e a aplica ao imóvel, retornando apenas os campos cujo valor mudou
+func (patch PropertyPatch) Apply(p *Property) ([]FieldCorrection, error) {
+	var changes []FieldCorrection
+	var invalid []string
+
+	setString := func(field string, target *string, value *string) {
+		if value == nil {
+			return
+		}
+		if corrected := strings.TrimSpace(*value); corrected != *target {
+			changes = append(changes, FieldCorrection{Field: field, OldValue: *target, NewValue: corrected})
+			*target = corrected
+		}
+	}
+	setFloat := func(field string, target *float64, value *float64) {
+		if value == nil {
+			return
+		}
+		if *value < 0 {
+			invalid = append(invalid, field)
+		} else if *value != *target {
+			changes = append(changes, FieldCorrection{Field: field, OldValue: *target, NewValue: *value})
+			*target = *value
+		}
+	}
+	setInt := func(field string, target *int, value *int) {
+		if value == nil {
+			return
+		}
+		if *value < 0 {
+			invalid = append(invalid, field)
+		} else if *value != *target {
+			changes = append(changes, FieldCorrection{Field: field, OldValue: *target, NewValue: *value})
+			*target = *value
+		}
+	}
+
+	setString("endereco", &p.Endereco, patch.Endereco)
+	setString("cidade", &p.Cidade, patch.Cidade)
+	setString("bairro", &p.Bairro, patch.Bairro)
+	setString("cep", &p.CEP, patch.CEP)
+	setString("descricao", &p.Descricao, patch.Descricao)
+	setFloat("valor", &p.Valor, patch.Valor)
+	setInt("quartos", &p.Quartos, patch.Quartos)
+	setInt("banheiros", &p.Banheiros, patch.Banheiros)
+	setFloat("area_total", &p.AreaTotal, patch.AreaTotal)
+	setFloat("area_util", &p.AreaUtil, patch.AreaUtil)
+	setString("tipo_imovel", &p.TipoImovel, patch.TipoImovel)
+
+	if patch.Caracteristicas != nil {
+		var features []string
+		for _, feature := range *patch.Caracteristicas {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				features = append(features, feature)
+			}
+		}
+		if strings.Join(features, "\n") != strings.Join(p.Caracteristicas, "\n") {
+			changes = append(changes, FieldCorrection{Field: "caracteristicas", OldValue: p.Caracteristicas, NewValue: features})
+			p.Caracteristicas = features
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("%w: negative values for %s", ErrInvalidPropertyPatch, strings.Join(invalid, ", "))
+	}
+	return changes, nil
+}
+
+// IsEmpty informa se a correção não altera nenhum campo
+func (patch PropertyPatch) IsEmpty() bool {
+	return patch == PropertyPatch{}
+}
+
+// ApplyManualCorrections mantém na versão recém-extraída os campos corrigidos manualmente no
+// imóvel salvo, para que um novo crawling não desfaça a correção
+func ApplyManualCorrections(p *Property, corrected Property) {
+	if !corrected.ManuallyCorrected {
+		return
+	}
+	for _, field := range corrected.CorrectedFields {
+		copyField, ok := correctedFieldCopiers[field]
+		if !ok {
+			continue
+		}
+		copyField(p, corrected)
+		if origin, ok := corrected.Provenance[field]; ok {
+			if p.Provenance == nil {
+				p.Provenance = make(map[string]FieldProvenance)
+			}
+			p.Provenance[field] = origin
+		}
+		setFieldConfidence(p, field, 1)
+	}
+	p.ManuallyCorrected = true
+	p.CorrectedFields = corrected.CorrectedFields
+}
+
+// propertyCorrections retorna a coleção do histórico de correções
+func (r *MongoRepository) propertyCorrections() *mongo.Collection {
+	return r.collection.Database().Collection(PropertyCorrectionCollection)
+}
+
+// propertyIDFilter busca o imóvel pelo ObjectID ou, se o ID não for hexadecimal, pelo texto
+func propertyIDFilter(id string) bson.M {
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		return bson.M{"_id": objectID}
+	}
+	return bson.M{"_id": id}
+}
+
+// findCorrectedProperty retorna a versão mais recente da URL com correções manuais (nil se não
+// houver). Unidades de anúncios divididos compartilham a URL e não herdam correções entre si.
+func (r *MongoRepository) findCorrectedProperty(ctx context.Context, property Property) (*Property, error) {
+	if property.ParentID != "" {
+		return nil, nil
+	}
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	raw, err := r.collection.FindOne(ctx, bson.M{"url": property.URL, "manually_corrected": true}, findOptions).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find manually corrected property: %v", err)
+	}
+	corrected, _, err := decodeProperty(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode property: %v", err)
+	}
+	return &corrected, nil
+}
+
+// CorrectProperty aplica a correção manual, marca os campos como corrigidos (protegidos contra
+// novos crawlings) e registra a alteração no histórico. Só os campos corrigidos (com origem e
+// confiança) e o hash são gravados; os campos derivados são recalculados no próximo Save ou
+// backfill. Sem mudanças, retorna o imóvel sem gravar nada e a correção nil.
+func (r *MongoRepository) CorrectProperty(ctx context.Context, id string, patch PropertyPatch, correctedBy, reason string) (*Property, *PropertyCorrection, error) {
+	property, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes, err := patch.Apply(property)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(changes) == 0 {
+		return property, nil, nil
+	}
+
+	fields := make(map[string]bool, len(property.CorrectedFields))
+	for _, field := range property.CorrectedFields {
+		fields[field] = true
+	}
+	for _, change := range changes {
+		if !fields[change.Field] {
+			fields[change.Field] = true
+			property.CorrectedFields = append(property.CorrectedFields, change.Field)
+		}
+		property.SetProvenance(change.Field, ExtractorManual, "")
+		setFieldConfidence(property, change.Field, 1)
+	}
+	property.ManuallyCorrected = true
+
+	// O hash acompanha o conteúdo corrigido, como em Save
+	property.Hash = GeneratePropertyHash(*property)
+
+	update, err := correctionUpdate(*property, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := r.collection.UpdateOne(ctx, propertyIDFilter(id), bson.M{"$set": update}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, nil, fmt.Errorf("%w: another property already has the corrected content", ErrInvalidPropertyPatch)
+		}
+		return nil, nil, fmt.Errorf("failed to correct property: %v", err)
+	}
+
+	correction := &PropertyCorrection{
+		PropertyID:  property.ID,
+		URL:         property.URL,
+		Changes:     changes,
+		Reason:      reason,
+		CorrectedBy: correctedBy,
+		CorrectedAt: time.Now(),
+	}
+	result, err := r.propertyCorrections().InsertOne(ctx, correction)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record property correction: %v", err)
+	}
+	if objectID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		correction.ID = objectID
+	}
+	return property, correction, nil
+}
+
+// correctionUpdate monta o $set da correção: os campos alterados (pelo nome bson), a origem e a
+// confiança de cada um, a lista de campos corrigidos e o hash
+func correctionUpdate(property Property, changes []FieldCorrection) (bson.M, error) {
+	data, err := bson.Marshal(property)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode property: %v", err)
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode property: %v", err)
+	}
+
+	update := bson.M{
+		"corrected_fields":   property.CorrectedFields,
+		"manually_corrected": true,
+		"hash":               property.Hash,
+	}
+	for _, change := range changes {
+		// Campos com omitempty que ficaram vazios não aparecem no documento
+		update[change.Field] = doc[change.Field]
+		update["provenance."+change.Field] = property.Provenance[change.Field]
+		update["field_confidence."+change.Field] = property.FieldConfidence[change.Field]
+	}
+	return update, nil
+}
+
+// FindPropertyCorrections retorna o histórico de correções do imóvel, da mais recente para a
+// mais antiga. O histórico segue a URL, então as correções feitas em versões anteriores do
+// mesmo anúncio também aparecem
+func (r *MongoRepository) FindPropertyCorrections(ctx context.Context, id string) ([]PropertyCorrection, error) {
+	property, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "corrected_at", Value: -1}})
+	cursor, err := r.propertyCorrections().Find(ctx, bson.M{"url": property.URL}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property corrections: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	corrections := []PropertyCorrection{}
+	if err := cursor.All(ctx, &corrections); err != nil {
+		return nil, fmt.Errorf("failed to decode property corrections: %v", err)
+	}
+	return corrections, nil
+}
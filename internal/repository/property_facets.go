@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// priceBandBoundaries define as faixas de preço (em reais) usadas nas facetas
+var priceBandBoundaries = []float64{0, 200000, 400000, 700000, 1000000, 2000000}
+
+// maxRoomsFacet agrupa imóveis com muitos quartos em um único valor ("5+")
+const maxRoomsFacet = 5
+
+// FacetCount representa a quantidade de imóveis para um valor de faceta
+type FacetCount struct {
+	Value string  `json:"value"`
+	Count int64   `json:"count"`
+	Min   float64 `json:"min,omitempty"` // limites da faixa de preço
+	Max   float64 `json:"max,omitempty"` // 0 = sem limite superior
+}
+
+// PropertyFacets reúne as contagens usadas pelas interfaces para montar os filtros
+type PropertyFacets struct {
+	Cidade      string       `json:"cidade,omitempty"`
+	Total       int64        `json:"total"`
+	Bairros     []FacetCount `json:"bairros"`
+	TiposImovel []FacetCount `json:"tipos_imovel"`
+	FaixasPreco []FacetCount `json:"faixas_preco"`
+	Quartos     []FacetCount `json:"quartos"`
+	Finalidades []FacetCount `json:"finalidades"`
+}
+
+// FacetRepository é implementado por repositórios capazes de agregar facetas de busca
+type FacetRepository interface {
+	Facets(ctx context.Context, cidade string) (*PropertyFacets, error)
+}
+
+// facetBucket é o resultado de um grupo da agregação ($group ou $bucket)
+type facetBucket struct {
+	ID    interface{} `bson:"_id"`
+	Count int64       `bson:"count"`
+}
+
+// facetAggregation é o documento único retornado pelo estágio $facet
+type facetAggregation struct {
+	Total       []facetBucket `bson:"total"`
+	Bairros     []facetBucket `bson:"bairros"`
+	TiposImovel []facetBucket `bson:"tipos_imovel"`
+	FaixasPreco []facetBucket `bson:"faixas_preco"`
+	Quartos     []facetBucket `bson:"quartos"`
+	Finalidades []facetBucket `bson:"finalidades"`
+}
+
+// Facets calcula, em uma única agregação, as contagens por bairro, tipo, faixa de preço e quartos
+func (r *MongoRepository) Facets(ctx context.Context, cidade string) (*PropertyFacets, error) {
+	match := bson.M{}
+	if cidade != "" {
+		match["cidade"] = bson.M{"$regex": utils.NormalizeText(cidade), "$options": "i"}
+	}
+
+	boundaries := bson.A{}
+	for _, boundary := range priceBandBoundaries {
+		boundaries = append(boundaries, boundary)
+	}
+
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{"", nil}}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$facet": bson.M{
+			"total":        bson.A{bson.M{"$count": "count"}},
+			"bairros":      countBy("bairro"),
+			"tipos_imovel": countBy("tipo_normalizado"),
+			"finalidades":  countBy("finalidade"),
+			"faixas_preco": bson.A{
+				bson.M{"$match": bson.M{"valor": bson.M{"$gt": 0}}},
+				bson.M{"$bucket": bson.M{
+					"groupBy":    "$valor",
+					"boundaries": boundaries,
+					"default":    "acima",
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}},
+			},
+			"quartos": bson.A{
+				bson.M{"$match": bson.M{"quartos": bson.M{"$gt": 0}}},
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$min": bson.A{"$quartos", maxRoomsFacet}},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate facets: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []facetAggregation
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode facets: %v", err)
+	}
+
+	var aggregation facetAggregation
+	if len(results) > 0 {
+		aggregation = results[0]
+	}
+
+	facets := buildPropertyFacets(aggregation)
+	facets.Cidade = cidade
+	return facets, nil
+}
+
+// buildPropertyFacets converte o resultado bruto da agregação nas facetas ordenadas
+func buildPropertyFacets(aggregation facetAggregation) *PropertyFacets {
+	facets := &PropertyFacets{
+		Bairros:     countsByValue(aggregation.Bairros),
+		TiposImovel: countsByValue(aggregation.TiposImovel),
+		FaixasPreco: priceBandCounts(aggregation.FaixasPreco),
+		Quartos:     roomCounts(aggregation.Quartos),
+		Finalidades: countsByValue(aggregation.Finalidades),
+	}
+	if len(aggregation.Total) > 0 {
+		facets.Total = aggregation.Total[0].Count
+	}
+	return facets
+}
+
+// countsByValue ordena por quantidade (decrescente) e, em caso de empate, por nome
+func countsByValue(buckets []facetBucket) []FacetCount {
+	counts := make([]FacetCount, 0, len(buckets))
+	for _, bucket := range buckets {
+		value, ok := bucket.ID.(string)
+		if !ok || value == "" {
+			continue
+		}
+		counts = append(counts, FacetCount{Value: value, Count: bucket.Count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
+
+// priceBandCounts retorna todas as faixas de preço em ordem crescente (inclusive as vazias)
+func priceBandCounts(buckets []facetBucket) []FacetCount {
+	byLower := make(map[float64]int64)
+	var above int64
+	for _, bucket := range buckets {
+		if lower, ok := facetNumber(bucket.ID); ok {
+			byLower[lower] = bucket.Count
+		} else {
+			above = bucket.Count
+		}
+	}
+
+	counts := make([]FacetCount, 0, len(priceBandBoundaries))
+	for i := 0; i < len(priceBandBoundaries)-1; i++ {
+		lower, upper := priceBandBoundaries[i], priceBandBoundaries[i+1]
+		counts = append(counts, FacetCount{
+			Value: fmt.Sprintf("%.0f-%.0f", lower, upper),
+			Count: byLower[lower],
+			Min:   lower,
+			Max:   upper,
+		})
+	}
+
+	last := priceBandBoundaries[len(priceBandBoundaries)-1]
+	counts = append(counts, FacetCount{
+		Value: fmt.Sprintf("%.0f+", last),
+		Count: above,
+		Min:   last,
+	})
+	return counts
+}
+
+// roomCounts ordena por número de quartos; o último grupo representa "5+"
+func roomCounts(buckets []facetBucket) []FacetCount {
+	counts := make([]FacetCount, 0, len(buckets))
+	for _, bucket := range buckets {
+		rooms, ok := facetNumber(bucket.ID)
+		if !ok || rooms <= 0 {
+			continue
+		}
+		value := strconv.Itoa(int(rooms))
+		if int(rooms) >= maxRoomsFacet {
+			value += "+"
+		}
+		counts = append(counts, FacetCount{Value: value, Count: bucket.Count, Min: rooms})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Min < counts[j].Min
+	})
+	return counts
+}
+
+// facetNumber converte os tipos numéricos retornados pelo Mongo
+func facetNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
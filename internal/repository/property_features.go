@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// Recursos de infraestrutura reconhecidos no texto dos anúncios (campos booleanos filtráveis),
+// comuns em imóveis rurais e de cidades pequenas
+const (
+	FeatureEnergiaSolar   = "energia_solar"
+	FeaturePocoArtesiano  = "poco_artesiano"
+	FeatureAquecimentoGas = "aquecimento_gas"
+)
+
+var (
+	featureMutex sync.RWMutex
+
+	// featureKeywords mapeia cada recurso para as variações de texto (sem acentos, em minúsculas)
+	featureKeywords = defaultFeatureKeywords()
+)
+
+// defaultFeatureKeywords retorna as variações reconhecidas sem arquivo de configuração
+func defaultFeatureKeywords() map[string][]string {
+	return map[string][]string{
+		FeatureEnergiaSolar: {
+			"energia solar", "placa solar", "placas solares", "painel solar", "paineis solares",
+			"fotovoltaica", "fotovoltaico", "usina solar",
+		},
+		FeaturePocoArtesiano: {
+			"poco artesiano", "poco semi artesiano", "poco semiartesiano", "poco tubular", "poco profundo",
+		},
+		FeatureAquecimentoGas: {
+			"aquecimento a gas", "aquecimento gas", "aquecedor a gas", "aquecedor gas", "aquecedor de passagem",
+		},
+	}
+}
+
+// LoadFeatureKeywords lê um JSON {"energia_solar": ["termo", ...], ...} e acrescenta os termos às
+// variações padrão; arquivo inexistente mantém apenas os padrões
+func LoadFeatureKeywords(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read feature keywords: %v", err)
+	}
+
+	var extra map[string][]string
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return fmt.Errorf("invalid feature keywords file: %v", err)
+	}
+	return SetFeatureKeywords(extra)
+}
+
+// SetFeatureKeywords acrescenta termos às variações padrão de cada recurso
+func SetFeatureKeywords(extra map[string][]string) error {
+	keywords := defaultFeatureKeywords()
+	for feature, terms := range extra {
+		if _, known := keywords[feature]; !known {
+			return fmt.Errorf("unknown feature %q (use %s, %s or %s)", feature,
+				FeatureEnergiaSolar, FeaturePocoArtesiano, FeatureAquecimentoGas)
+		}
+		for _, term := range terms {
+			if term = normalizeFeatureText(term); term != "" {
+				keywords[feature] = append(keywords[feature], term)
+			}
+		}
+	}
+
+	featureMutex.Lock()
+	featureKeywords = keywords
+	featureMutex.Unlock()
+	return nil
+}
+
+// normalizeFeatureText normaliza o texto tratando hífens e sublinhados como espaços
+func normalizeFeatureText(text string) string {
+	return utils.NormalizeText(strings.NewReplacer("-", " ", "_", " ").Replace(text))
+}
+
+// DetectFeatures retorna os recursos citados nas características ou na descrição
+func DetectFeatures(property Property) map[string]bool {
+	text := " " + normalizeFeatureText(strings.Join(property.Caracteristicas, " ; ")+" "+property.Descricao) + " "
+
+	featureMutex.RLock()
+	defer featureMutex.RUnlock()
+
+	found := make(map[string]bool)
+	for feature, terms := range featureKeywords {
+		for _, term := range terms {
+			if strings.Contains(text, " "+term+" ") {
+				found[feature] = true
+				break
+			}
+		}
+	}
+	return found
+}
+
+// applyFeatures preenche os campos booleanos de recursos do imóvel
+func applyFeatures(property *Property) {
+	features := DetectFeatures(*property)
+	property.EnergiaSolar = features[FeatureEnergiaSolar]
+	property.PocoArtesiano = features[FeaturePocoArtesiano]
+	property.AquecimentoGas = features[FeatureAquecimentoGas]
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PropertyHistoryRepository é implementado por repositórios capazes de reconstruir o acervo em
+// uma data passada. Cada versão de um anúncio é um documento próprio (o hash muda com o
+// conteúdo) e o _id (ObjectID) registra quando a versão foi vista pela primeira vez.
+type PropertyHistoryRepository interface {
+	FindAsOf(ctx context.Context, asOf time.Time) ([]Property, error)
+}
+
+// FindAsOf retorna, para cada anúncio, a versão mais recente gravada até asOf. As unidades de um
+// anúncio dividido (mesma URL) são acompanhadas separadamente (ver propertyVersionKey). Documentos
+// sem ObjectID (sem data de criação) não entram no resultado.
+func (r *MongoRepository) FindAsOf(ctx context.Context, asOf time.Time) ([]Property, error) {
+	filter := bson.M{"_id": bson.M{"$type": "objectId", "$lt": objectIDAfter(asOf)}}
+	opts := options.Find().SetSort(bson.M{"_id": -1}).SetAllowDiskUse(true)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	properties, err := r.decodePropertiesFunc(ctx, cursor, latestVersions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode property history: %v", err)
+	}
+	if properties == nil {
+		properties = []Property{}
+	}
+
+	sort.SliceStable(properties, func(i, j int) bool {
+		if properties[i].Valor != properties[j].Valor {
+			return properties[i].Valor > properties[j].Valor
+		}
+		return properties[i].ID < properties[j].ID
+	})
+	return properties, nil
+}
+
+// latestVersions mantém a primeira versão vista de cada anúncio; com as versões da mais recente
+// para a mais antiga, é a versão vigente na data
+func latestVersions() func(Property) bool {
+	seen := make(map[string]bool)
+	return func(property Property) bool {
+		key := propertyVersionKey(property)
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		return true
+	}
+}
+
+// propertyVersionKey identifica um anúncio entre as versões: URL, anúncio de origem (parent_id) e
+// a unidade dentro dele (lote, registro do loteamento ou opção de quartos). Anúncios sem URL são
+// identificados pelo próprio hash.
+func propertyVersionKey(property Property) string {
+	if property.URL == "" {
+		return "hash|" + property.Hash
+	}
+
+	unit := ""
+	switch {
+	case property.ParentID == "":
+	case property.Lote != "":
+		unit = "lote:" + normalizeContent(property.Lote)
+	case property.Loteamento != nil:
+		unit = "loteamento"
+	default:
+		unit = fmt.Sprintf("quartos:%d", property.Quartos)
+	}
+	return property.URL + "|" + property.ParentID + "|" + unit
+}
+
+// objectIDAfter retorna o menor ObjectID gerado após o segundo de t (limite exclusivo das consultas)
+func objectIDAfter(t time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()+1))
+	return id
+}
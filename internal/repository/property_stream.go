@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// propertyStreamBatchSize é a quantidade de documentos pedida ao Mongo por vez na exportação em
+// fluxo; o próximo lote só é lido depois que o anterior foi enviado ao cliente
+const propertyStreamBatchSize = 200
+
+// PropertyStreamRepository é implementado por repositórios capazes de percorrer os imóveis em
+// ordem de _id sem carregar o resultado inteiro em memória
+type PropertyStreamRepository interface {
+	StreamProperties(ctx context.Context, filter PropertyFilter, afterID string, limit int, fn func(Property) error) error
+}
+
+// StreamProperties chama fn para cada imóvel do filtro com _id maior que afterID (vazio começa do
+// início), em ordem de _id, até limit imóveis (0 sem limite). Um erro de fn interrompe a leitura
+func (r *MongoRepository) StreamProperties(ctx context.Context, filter PropertyFilter, afterID string, limit int, fn func(Property) error) error {
+	mongoFilter := propertySearchFilter(filter)
+	if afterID != "" {
+		var after interface{} = afterID
+		if objectID, err := primitive.ObjectIDFromHex(afterID); err == nil {
+			after = objectID
+		}
+		mongoFilter["_id"] = bson.M{"$gt": after}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetBatchSize(propertyStreamBatchSize)
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, mongoFilter, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to stream properties: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		// Documentos em versões antigas do schema são migrados só na leitura
+		property, _, err := decodeProperty(cursor.Current)
+		if err != nil {
+			return fmt.Errorf("failed to decode property: %v", err)
+		}
+		if err := fn(property); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to stream properties: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Coleção das métricas de qualidade dos dados por domínio
+const (
+	QualityMetricsCollection = "quality_metrics"
+	QualityMetricsDayFormat  = "2006-01-02"
+)
+
+// DomainQuality resume a qualidade dos anúncios de um domínio em um dia: percentual de anúncios
+// com preço, endereço, quartos e fotos, e a nota média de extração (média de field_confidence)
+type DomainQuality struct {
+	Domain             string    `bson:"domain" json:"domain"`
+	Day                string    `bson:"day" json:"day"` // AAAA-MM-DD
+	Properties         int       `bson:"properties" json:"properties"`
+	WithPricePct       float64   `bson:"with_price_pct" json:"with_price_pct"`
+	WithAddressPct     float64   `bson:"with_address_pct" json:"with_address_pct"`
+	WithRoomsPct       float64   `bson:"with_rooms_pct" json:"with_rooms_pct"`
+	WithPhotosPct      float64   `bson:"with_photos_pct" json:"with_photos_pct"`
+	AvgExtractionScore float64   `bson:"avg_extraction_score" json:"avg_extraction_score"` // 0 a 1
+	ScoredProperties   int       `bson:"scored_properties" json:"scored_properties"`       // anúncios com field_confidence
+	ComputedAt         time.Time `bson:"computed_at" json:"computed_at"`
+}
+
+// QualityMetricsRepository é implementado por repositórios capazes de calcular e consultar as
+// métricas de qualidade por domínio
+type QualityMetricsRepository interface {
+	ComputeQualityMetrics(ctx context.Context, day time.Time) ([]DomainQuality, error)
+	FindQualityMetrics(ctx context.Context, domain string, since time.Time) ([]DomainQuality, error)
+}
+
+// qualitySample contém os campos de um anúncio usados nas métricas
+type qualitySample struct {
+	URL             string             `bson:"url"`
+	Valor           float64            `bson:"valor"`
+	Endereco        string             `bson:"endereco"`
+	Quartos         int                `bson:"quartos"`
+	Fotos           int                `bson:"fotos"`
+	FieldConfidence map[string]float64 `bson:"field_confidence"`
+}
+
+// ComputeQualityMetrics calcula as métricas de cada domínio a partir dos anúncios do crawler e
+// grava os resultados do dia (recalcular o mesmo dia os substitui)
+func (r *MongoRepository) ComputeQualityMetrics(ctx context.Context, day time.Time) ([]DomainQuality, error) {
+	projection := bson.M{"url": 1, "valor": 1, "endereco": 1, "quartos": 1, "fotos": 1, "field_confidence": 1}
+	cursor, err := r.collection.Find(ctx, bson.M{"source": bson.M{"$ne": SourceImport}}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quality samples: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var samples []qualitySample
+	for cursor.Next(ctx) {
+		var sample qualitySample
+		if err := cursor.Decode(&sample); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quality samples: %v", err)
+	}
+
+	metrics := buildQualityMetrics(samples, day.Format(QualityMetricsDayFormat), time.Now())
+	if len(metrics) == 0 {
+		return metrics, nil
+	}
+
+	collection := r.collection.Database().Collection(QualityMetricsCollection)
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "domain", Value: 1}, {Key: "day", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return nil, fmt.Errorf("failed to create quality metrics indexes: %v", err)
+	}
+
+	models := make([]mongo.WriteModel, 0, len(metrics))
+	for _, metric := range metrics {
+		filter := bson.M{"domain": metric.Domain, "day": metric.Day}
+		models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(metric).SetUpsert(true))
+	}
+	if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return nil, fmt.Errorf("failed to save quality metrics: %v", err)
+	}
+	return metrics, nil
+}
+
+// FindQualityMetrics retorna a série diária das métricas a partir de since, de todos os domínios
+// ou apenas do informado
+func (r *MongoRepository) FindQualityMetrics(ctx context.Context, domain string, since time.Time) ([]DomainQuality, error) {
+	filter := bson.M{"day": bson.M{"$gte": since.Format(QualityMetricsDayFormat)}}
+	if domain = NormalizeDomain(domain); domain != "" {
+		filter["domain"] = domain
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "domain", Value: 1}, {Key: "day", Value: 1}})
+
+	cursor, err := r.collection.Database().Collection(QualityMetricsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quality metrics: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	metrics := []DomainQuality{}
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, fmt.Errorf("failed to decode quality metrics: %v", err)
+	}
+	return metrics, nil
+}
+
+// buildQualityMetrics agrupa os anúncios por domínio e calcula os percentuais e a nota média
+func buildQualityMetrics(samples []qualitySample, day string, computedAt time.Time) []DomainQuality {
+	type totals struct {
+		properties, price, address, rooms, photos, scored int
+		score                                             float64
+	}
+	byDomain := make(map[string]*totals)
+	for _, sample := range samples {
+		domain := NormalizeDomain(sample.URL)
+		if domain == "" {
+			continue
+		}
+		t, exists := byDomain[domain]
+		if !exists {
+			t = &totals{}
+			byDomain[domain] = t
+		}
+
+		t.properties++
+		if sample.Valor > 0 {
+			t.price++
+		}
+		if sample.Endereco != "" {
+			t.address++
+		}
+		if sample.Quartos > 0 {
+			t.rooms++
+		}
+		if sample.Fotos > 0 {
+			t.photos++
+		}
+		if len(sample.FieldConfidence) > 0 {
+			sum := 0.0
+			for _, confidence := range sample.FieldConfidence {
+				sum += confidence
+			}
+			t.score += sum / float64(len(sample.FieldConfidence))
+			t.scored++
+		}
+	}
+
+	percent := func(count, total int) float64 {
+		return math.Round(float64(count)/float64(total)*1000) / 10
+	}
+	metrics := make([]DomainQuality, 0, len(byDomain))
+	for domain, t := range byDomain {
+		metric := DomainQuality{
+			Domain:           domain,
+			Day:              day,
+			Properties:       t.properties,
+			WithPricePct:     percent(t.price, t.properties),
+			WithAddressPct:   percent(t.address, t.properties),
+			WithRoomsPct:     percent(t.rooms, t.properties),
+			WithPhotosPct:    percent(t.photos, t.properties),
+			ScoredProperties: t.scored,
+			ComputedAt:       computedAt,
+		}
+		if t.scored > 0 {
+			metric.AvgExtractionScore = math.Round(t.score/float64(t.scored)*1000) / 1000
+		}
+		metrics = append(metrics, metric)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Properties != metrics[j].Properties {
+			return metrics[i].Properties > metrics[j].Properties
+		}
+		return metrics[i].Domain < metrics[j].Domain
+	})
+	return metrics
+}
+
+// LatestQualityMetrics retorna a métrica mais recente de cada domínio da série
+func LatestQualityMetrics(series []DomainQuality) []DomainQuality {
+	latest := make(map[string]DomainQuality)
+	for _, metric := range series {
+		if current, exists := latest[metric.Domain]; !exists || metric.Day > current.Day {
+			latest[metric.Domain] = metric
+		}
+	}
+
+	metrics := make([]DomainQuality, 0, len(latest))
+	for _, metric := range latest {
+		metrics = append(metrics, metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Properties != metrics[j].Properties {
+			return metrics[i].Properties > metrics[j].Properties
+		}
+		return metrics[i].Domain < metrics[j].Domain
+	})
+	return metrics
+}
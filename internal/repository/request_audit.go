@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RequestAuditCollection guarda o registro de auditoria das requisições HTTP de saída
+const RequestAuditCollection = "request_audit"
+
+// Destinos aceitos em REQUEST_AUDIT
+const (
+	RequestAuditMongo = "mongo"
+	RequestAuditFile  = "file"
+)
+
+// DefaultRequestAuditLimit é o número de registros retornados quando o filtro não define limite
+const DefaultRequestAuditLimit = 100
+
+// RequestAuditEntry é uma requisição HTTP feita pelo crawler
+type RequestAuditEntry struct {
+	Time       time.Time `bson:"time" json:"time"`
+	JobID      string    `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	Method     string    `bson:"method" json:"method"`
+	URL        string    `bson:"url" json:"url"`
+	Domain     string    `bson:"domain" json:"domain"`
+	StatusCode int       `bson:"status_code" json:"status_code"` // 0 quando a requisição falhou sem resposta
+	Bytes      int64     `bson:"bytes" json:"bytes"`             // bytes do corpo lidos
+	DurationMs int64     `bson:"duration_ms" json:"duration_ms"` // do envio ao fim da leitura do corpo
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// RequestAuditFilter seleciona os registros de auditoria (campos vazios não filtram)
+type RequestAuditFilter struct {
+	JobID      string
+	Domain     string
+	StatusCode int
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+}
+
+// Matches indica se o registro passa pelo filtro (o limite não é considerado)
+func (f RequestAuditFilter) Matches(entry RequestAuditEntry) bool {
+	switch {
+	case f.JobID != "" && entry.JobID != f.JobID:
+		return false
+	case f.Domain != "" && entry.Domain != NormalizeDomain(f.Domain):
+		return false
+	case f.StatusCode != 0 && entry.StatusCode != f.StatusCode:
+		return false
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && entry.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// limit retorna o limite do filtro ou o padrão
+func (f RequestAuditFilter) limit() int {
+	if f.Limit > 0 {
+		return f.Limit
+	}
+	return DefaultRequestAuditLimit
+}
+
+// RequestAuditRepository guarda e consulta o registro de auditoria das requisições
+type RequestAuditRepository interface {
+	SaveRequestAudits(ctx context.Context, entries []RequestAuditEntry) error
+	// FindRequestAudits retorna os registros do filtro, do mais recente ao mais antigo
+	FindRequestAudits(ctx context.Context, filter RequestAuditFilter) ([]RequestAuditEntry, error)
+}
+
+// OpenRequestAudit abre o destino configurado em REQUEST_AUDIT: "mongo" (coleção request_audit,
+// com expiração após ttl; 0 mantém os registros) ou "file:caminho.ndjson". Vazio desativa (nil).
+func OpenRequestAudit(ctx context.Context, spec string, primary PropertyRepository, ttl time.Duration) (RequestAuditRepository, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return nil, nil
+	case spec == RequestAuditMongo:
+		mongoRepo, ok := primary.(*MongoRepository)
+		if !ok {
+			return nil, errors.New("mongo request audit requires the MongoDB property repository")
+		}
+		if err := mongoRepo.ConfigureRequestAudit(ctx, ttl); err != nil {
+			return nil, err
+		}
+		return mongoRepo, nil
+	case strings.HasPrefix(spec, RequestAuditFile+":") && len(spec) > len(RequestAuditFile)+1:
+		return NewFileRequestAuditRepository(spec[len(RequestAuditFile)+1:]), nil
+	default:
+		return nil, fmt.Errorf("invalid request audit destination %q (use mongo or file:<path>)", spec)
+	}
+}
+
+// requestAudits retorna a coleção do registro de auditoria
+func (r *MongoRepository) requestAudits() *mongo.Collection {
+	return r.collection.Database().Collection(RequestAuditCollection)
+}
+
+// ConfigureRequestAudit cria os índices da coleção de auditoria; com ttl > 0 os registros
+// expiram automaticamente
+func (r *MongoRepository) ConfigureRequestAudit(ctx context.Context, ttl time.Duration) error {
+	timeIndex := mongo.IndexModel{Keys: bson.D{{Key: "time", Value: -1}}}
+	if ttl > 0 {
+		timeIndex.Options = options.Index().SetExpireAfterSeconds(int32(ttl.Seconds()))
+	}
+	indexes := []mongo.IndexModel{
+		timeIndex,
+		{Keys: bson.D{{Key: "domain", Value: 1}, {Key: "time", Value: -1}}},
+		{Keys: bson.D{{Key: "job_id", Value: 1}, {Key: "time", Value: -1}}},
+	}
+	if _, err := r.requestAudits().Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create request audit indexes: %v", err)
+	}
+	return nil
+}
+
+// SaveRequestAudits grava um lote de registros de auditoria
+func (r *MongoRepository) SaveRequestAudits(ctx context.Context, entries []RequestAuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		documents = append(documents, entry)
+	}
+	return withStorageRetry(ctx, "failed to save request audit", func() error {
+		_, err := r.requestAudits().InsertMany(ctx, documents)
+		return err
+	})
+}
+
+// FindRequestAudits retorna os registros do filtro, do mais recente ao mais antigo
+func (r *MongoRepository) FindRequestAudits(ctx context.Context, filter RequestAuditFilter) ([]RequestAuditEntry, error) {
+	query := bson.M{}
+	if filter.JobID != "" {
+		query["job_id"] = filter.JobID
+	}
+	if filter.Domain != "" {
+		query["domain"] = NormalizeDomain(filter.Domain)
+	}
+	if filter.StatusCode != 0 {
+		query["status_code"] = filter.StatusCode
+	}
+	timeRange := bson.M{}
+	if !filter.Since.IsZero() {
+		timeRange["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		timeRange["$lte"] = filter.Until
+	}
+	if len(timeRange) > 0 {
+		query["time"] = timeRange
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: -1}}).SetLimit(int64(filter.limit()))
+	cursor, err := r.requestAudits().Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request audit: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []RequestAuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode request audit: %v", err)
+	}
+	return entries, nil
+}
+
+// FileRequestAuditRepository grava o registro de auditoria como JSON delimitado por linha
+// (NDJSON); as consultas percorrem o arquivo
+type FileRequestAuditRepository struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileRequestAuditRepository cria o registro no arquivo (criado na primeira gravação)
+func NewFileRequestAuditRepository(path string) *FileRequestAuditRepository {
+	return &FileRequestAuditRepository{path: path}
+}
+
+// SaveRequestAudits acrescenta os registros ao arquivo
+func (fr *FileRequestAuditRepository) SaveRequestAudits(ctx context.Context, entries []RequestAuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	file, err := os.OpenFile(fr.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open request audit file: %v", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write request audit: %v", err)
+		}
+	}
+	return writer.Flush()
+}
+
+// FindRequestAudits percorre o arquivo e retorna os registros do filtro, do mais recente ao mais
+// antigo; linhas inválidas são ignoradas
+func (fr *FileRequestAuditRepository) FindRequestAudits(ctx context.Context, filter RequestAuditFilter) ([]RequestAuditEntry, error) {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	entries := []RequestAuditEntry{}
+	file, err := os.Open(fr.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request audit file: %v", err)
+	}
+	defer file.Close()
+
+	// O arquivo está em ordem cronológica: mantém apenas os últimos registros do filtro
+	limit := filter.limit()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry RequestAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || !filter.Matches(entry) {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) >= 2*limit {
+			entries = append(entries[:0], entries[len(entries)-limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read request audit file: %v", err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// RequestAuditSummary resume as requisições feitas a um domínio: volume, erros e ritmo. O menor
+// intervalo entre requisições consecutivas mostra se o atraso configurado foi respeitado.
+type RequestAuditSummary struct {
+	Domain            string    `json:"domain"`
+	Requests          int       `json:"requests"`
+	Errors            int       `json:"errors"` // falhas de rede e status >= 400
+	Bytes             int64     `json:"bytes"`
+	AvgDurationMs     int64     `json:"avg_duration_ms"`
+	MinIntervalMs     int64     `json:"min_interval_ms"` // -1 com uma única requisição
+	RequestsPerMinute float64   `json:"requests_per_minute"`
+	FirstRequest      time.Time `json:"first_request"`
+	LastRequest       time.Time `json:"last_request"`
+}
+
+// SummarizeRequestAudits agrupa os registros por domínio, do domínio mais requisitado ao menos
+func SummarizeRequestAudits(entries []RequestAuditEntry) []RequestAuditSummary {
+	byDomain := make(map[string][]RequestAuditEntry)
+	for _, entry := range entries {
+		byDomain[entry.Domain] = append(byDomain[entry.Domain], entry)
+	}
+
+	summaries := make([]RequestAuditSummary, 0, len(byDomain))
+	for domain, domainEntries := range byDomain {
+		sort.Slice(domainEntries, func(i, j int) bool { return domainEntries[i].Time.Before(domainEntries[j].Time) })
+
+		summary := RequestAuditSummary{
+			Domain:        domain,
+			Requests:      len(domainEntries),
+			MinIntervalMs: -1,
+			FirstRequest:  domainEntries[0].Time,
+			LastRequest:   domainEntries[len(domainEntries)-1].Time,
+		}
+		var totalDuration int64
+		for i, entry := range domainEntries {
+			if entry.StatusCode == 0 || entry.StatusCode >= 400 {
+				summary.Errors++
+			}
+			summary.Bytes += entry.Bytes
+			totalDuration += entry.DurationMs
+			if i > 0 {
+				interval := entry.Time.Sub(domainEntries[i-1].Time).Milliseconds()
+				if summary.MinIntervalMs < 0 || interval < summary.MinIntervalMs {
+					summary.MinIntervalMs = interval
+				}
+			}
+		}
+		summary.AvgDurationMs = totalDuration / int64(len(domainEntries))
+		if span := summary.LastRequest.Sub(summary.FirstRequest); span > 0 {
+			summary.RequestsPerMinute = float64(summary.Requests-1) / span.Minutes()
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Requests != summaries[j].Requests {
+			return summaries[i].Requests > summaries[j].Requests
+		}
+		return summaries[i].Domain < summaries[j].Domain
+	})
+	return summaries
+}
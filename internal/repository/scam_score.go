@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Sinais que compõem o risco de golpe de um anúncio
+const (
+	ScamSignalLowPrice    = "preco_abaixo_mediana"
+	ScamSignalStockPhotos = "fotos_de_banco"
+	ScamSignalNoContact   = "sem_contato"
+	ScamSignalUrgency     = "urgencia"
+)
+
+// Pesos (0 a 100) e limites do risco de golpe
+const (
+	ScamScoreThreshold = 50 // a partir deste risco o anúncio é marcado com suspeito_golpe
+
+	scamWeightLowPrice    = 40
+	scamWeightStockPhotos = 25
+	scamWeightNoContact   = 15
+	scamWeightUrgency     = 20
+
+	scamLowPriceRatio  = 0.5       // R$/m² abaixo de metade da mediana do bairro
+	priceMedianMaxAge  = time.Hour // validade das medianas consultadas no índice de preços
+	priceMedianCityKey = ""        // bairro_key dos pontos do índice da cidade inteira
+)
+
+// stockPhotoDomains são bancos de imagens: fotos deles não mostram o imóvel anunciado
+var stockPhotoDomains = []string{
+	"shutterstock.com", "istockphoto.com", "gettyimages.com", "freepik.com", "unsplash.com",
+	"pexels.com", "pixabay.com", "depositphotos.com", "dreamstime.com", "123rf.com",
+	"stock.adobe.com", "alamy.com",
+}
+
+// scamUrgencyPhrases são apelos comuns em golpes (texto normalizado, sem acentos)
+var scamUrgencyPhrases = []string{
+	"urgente", "preciso vender rapido", "venda rapida", "so hoje", "ultimos dias",
+	"abaixo do valor de mercado", "abaixo do preco de mercado", "oportunidade unica",
+	"nao perca", "motivo de viagem", "mudanca para o exterior", "deposito antecipado",
+	"sinal para reservar", "reserve com pix", "pix para reserva", "sem visita",
+}
+
+var (
+	// contactPhonePattern encontra telefones brasileiros ("(35) 99999-1234", "35 3571-1234")
+	contactPhonePattern = regexp.MustCompile(`\(?\b\d{2}\)?[\s.-]?9?\d{4}[\s.-]?\d{4}\b`)
+
+	// contactEmailPattern encontra endereços de e-mail
+	contactEmailPattern = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+)
+
+// HasContactInfo indica se o texto traz telefone, WhatsApp ou e-mail para contato
+func HasContactInfo(text string) bool {
+	lower := strings.ToLower(text)
+	if strings.Contains(lower, "wa.me/") || strings.Contains(lower, "whatsapp") {
+		return true
+	}
+	return contactPhonePattern.MatchString(text) || contactEmailPattern.MatchString(text)
+}
+
+// IsStockPhoto indica se a foto vem de um banco de imagens
+func IsStockPhoto(photoURL string) bool {
+	parsed, err := url.Parse(photoURL)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	for _, domain := range stockPhotoDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreScam calcula o risco de golpe do anúncio (0 a 100) e os sinais encontrados. A mediana do
+// R$/m² do bairro é opcional (0 ignora o sinal de preço); leilões, aluguéis e temporada não são
+// comparados com ela, e o sinal de contato só vale para páginas em que a presença de contato foi verificada.
+func ScoreScam(property Property, medianPricePerM2 float64) (int, []string) {
+	score := 0
+	var signals []string
+
+	if medianPricePerM2 > 0 && property.ValorM2 > 0 && !property.Leilao && property.ValorAluguel == 0 &&
+		property.Finalidade != FinalidadeTemporada && property.ValorM2 < medianPricePerM2*scamLowPriceRatio {
+		score += scamWeightLowPrice
+		signals = append(signals, ScamSignalLowPrice)
+	}
+
+	for _, photo := range property.FotoURLs {
+		if IsStockPhoto(photo) {
+			score += scamWeightStockPhotos
+			signals = append(signals, ScamSignalStockPhotos)
+			break
+		}
+	}
+
+	if property.TemContato != nil && !*property.TemContato && !HasContactInfo(property.Descricao) {
+		score += scamWeightNoContact
+		signals = append(signals, ScamSignalNoContact)
+	}
+
+	text := " " + utils.NormalizeText(property.Descricao+" "+property.ValorTexto) + " "
+	for _, phrase := range scamUrgencyPhrases {
+		if strings.Contains(text, " "+phrase+" ") {
+			score += scamWeightUrgency
+			signals = append(signals, ScamSignalUrgency)
+			break
+		}
+	}
+
+	return int(math.Min(float64(score), 100)), signals
+}
+
+// ApplyScamScore grava o risco de golpe, os sinais e a marcação suspeito_golpe no anúncio
+func ApplyScamScore(property *Property, medianPricePerM2 float64) {
+	property.RiscoGolpe, property.SinaisGolpe = ScoreScam(*property, medianPricePerM2)
+	property.SuspeitoGolpe = property.RiscoGolpe >= ScamScoreThreshold
+}
+
+// priceMedianEntry é a mediana consultada de um bairro (ou cidade) e quando expira
+type priceMedianEntry struct {
+	value     float64
+	expiresAt time.Time
+}
+
+// priceMedianCache guarda as medianas do índice de preços para não consultar a coleção a cada
+// imóvel salvo
+type priceMedianCache struct {
+	mutex   sync.Mutex
+	entries map[string]priceMedianEntry
+}
+
+// newPriceMedianCache cria o cache de medianas
+func newPriceMedianCache() *priceMedianCache {
+	return &priceMedianCache{entries: make(map[string]priceMedianEntry)}
+}
+
+// neighborhoodMedian retorna a mediana mais recente do R$/m² do bairro ou, se o bairro não tiver
+// índice, da cidade (0 se nenhum existir)
+func (r *MongoRepository) neighborhoodMedian(ctx context.Context, cidade, bairro string) float64 {
+	cityKey, bairroKey := utils.NormalizeText(cidade), utils.NormalizeText(bairro)
+	if cityKey == "" || r.priceMedians == nil {
+		return 0
+	}
+
+	cacheKey := cityKey + "|" + bairroKey
+	r.priceMedians.mutex.Lock()
+	entry, exists := r.priceMedians.entries[cacheKey]
+	r.priceMedians.mutex.Unlock()
+	if exists && time.Now().Before(entry.expiresAt) {
+		return entry.value
+	}
+
+	value, err := r.latestMedian(ctx, cityKey, bairroKey)
+	if err == nil && value == 0 && bairroKey != priceMedianCityKey {
+		value, err = r.latestMedian(ctx, cityKey, priceMedianCityKey)
+	}
+	if err != nil {
+		// Falhas de consulta não entram no cache: a próxima chamada tenta de novo
+		log.Printf("Warning: Failed to load price median: %v", err)
+		return 0
+	}
+
+	r.priceMedians.mutex.Lock()
+	r.priceMedians.entries[cacheKey] = priceMedianEntry{value: value, expiresAt: time.Now().Add(priceMedianMaxAge)}
+	r.priceMedians.mutex.Unlock()
+	return value
+}
+
+// latestMedian consulta o ponto mais recente do índice de preços (0 se não houver)
+func (r *MongoRepository) latestMedian(ctx context.Context, cityKey, bairroKey string) (float64, error) {
+	var point PriceIndexPoint
+	err := r.collection.Database().Collection(PriceIndexCollection).FindOne(ctx,
+		bson.M{"city_key": cityKey, "bairro_key": bairroKey},
+		options.FindOne().SetSort(bson.D{{Key: "month", Value: -1}}),
+	).Decode(&point)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find price median: %v", err)
+	}
+	return point.MedianPricePerM2, nil
+}
+
+// applyScamScore calcula o risco de golpe comparando o preço com a mediana do bairro
+func (r *MongoRepository) applyScamScore(ctx context.Context, property *Property) {
+	median := 0.0
+	if property.ValorM2 > 0 {
+		median = r.neighborhoodMedian(ctx, property.Cidade, property.Bairro)
+	}
+	ApplyScamScore(property, median)
+}
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SchemaMigration converte um documento da versão anterior para Version. Ao mudar o formato de
+// Property, registre uma migração com a próxima versão: documentos antigos são migrados na
+// leitura e pelo backfill (-schema-only).
+type SchemaMigration struct {
+	Version     int
+	Description string
+	Migrate     func(doc bson.M) error
+}
+
+var (
+	migrationsMutex  sync.RWMutex
+	schemaMigrations = []SchemaMigration{
+		{Version: 1, Description: "origem, comodidades e sem_fotos em documentos antigos", Migrate: migrateBaseline},
+		{Version: 2, Description: "preço por m² e tipo da taxonomia", Migrate: migrateDerivedFields},
+		{Version: 3, Description: "energia solar, poço artesiano e aquecimento a gás", Migrate: migrateFeatures},
+		{Version: 4, Description: "sinalização de leilões", Migrate: migrateAuction},
+		{Version: 5, Description: "finalidade, venda x aluguel, testada e zoneamento", Migrate: migrateCommercialLease},
+		{Version: 6, Description: "ID curto do anúncio", Migrate: migrateShortID},
+		{Version: 7, Description: "aluguel por temporada (diária e estadia mínima)", Migrate: migrateVacationRental},
+		{Version: 8, Description: "tipo da taxonomia em campo próprio", Migrate: migrateNormalizedType},
+	}
+)
+
+// RegisterSchemaMigration adiciona uma migração ao registro (ex: em init de outro pacote)
+func RegisterSchemaMigration(migration SchemaMigration) error {
+	if migration.Version <= 0 || migration.Migrate == nil {
+		return fmt.Errorf("invalid schema migration %d", migration.Version)
+	}
+
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+	for _, existing := range schemaMigrations {
+		if existing.Version == migration.Version {
+			return fmt.Errorf("schema migration %d already registered", migration.Version)
+		}
+	}
+	schemaMigrations = append(schemaMigrations, migration)
+	sort.Slice(schemaMigrations, func(i, j int) bool {
+		return schemaMigrations[i].Version < schemaMigrations[j].Version
+	})
+	return nil
+}
+
+// CurrentSchemaVersion é a versão dos documentos gravados (a maior migração registrada)
+func CurrentSchemaVersion() int {
+	migrationsMutex.RLock()
+	defer migrationsMutex.RUnlock()
+	latest := 0
+	for _, migration := range schemaMigrations {
+		if migration.Version > latest {
+			latest = migration.Version
+		}
+	}
+	return latest
+}
+
+// documentSchemaVersion lê schema_version do documento (0 quando ausente)
+func documentSchemaVersion(doc bson.M) int {
+	return bsonInt(doc["schema_version"])
+}
+
+// bsonInt converte um número BSON em int (0 se não for número)
+func bsonInt(value interface{}) int {
+	switch number := value.(type) {
+	case int32:
+		return int(number)
+	case int64:
+		return int(number)
+	case int:
+		return number
+	case float64:
+		return int(number)
+	}
+	return 0
+}
+
+// MigrateDocument aplica ao documento as migrações posteriores à sua versão; retorna se mudou
+func MigrateDocument(doc bson.M) (bool, error) {
+	version := documentSchemaVersion(doc)
+	latest := CurrentSchemaVersion()
+	if version >= latest {
+		return false, nil
+	}
+
+	migrationsMutex.RLock()
+	migrations := append([]SchemaMigration(nil), schemaMigrations...)
+	migrationsMutex.RUnlock()
+
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+		if err := migration.Migrate(doc); err != nil {
+			return false, fmt.Errorf("schema migration %d failed: %v", migration.Version, err)
+		}
+	}
+	doc["schema_version"] = latest
+	return true, nil
+}
+
+// migrateBaseline (v1) preenche os campos que não existiam nos primeiros documentos
+func migrateBaseline(doc bson.M) error {
+	if source, _ := doc["source"].(string); source == "" {
+		doc["source"] = SourceCrawler
+	}
+	if _, exists := doc["amenidades"]; !exists {
+		doc["amenidades"] = NormalizeAmenities(bsonStrings(doc["caracteristicas"]))
+	}
+	if _, exists := doc["sem_fotos"]; !exists {
+		doc["sem_fotos"] = bsonInt(doc["fotos"]) == 0
+	}
+	return nil
+}
+
+// migrateDerivedFields (v2) calcula o preço por m² e o tipo da taxonomia (em tipo_normalizado;
+// tipo_imovel mantém o valor do site)
+func migrateDerivedFields(doc bson.M) error {
+	var property Property
+	if err := convertBSON(doc, &property); err != nil {
+		return err
+	}
+	ApplyDerivedFields(&property)
+	if property.ValorM2 > 0 {
+		doc["valor_m2"] = property.ValorM2
+	}
+	doc["tipo_normalizado"] = property.TipoNormalizado
+	return nil
+}
+
+// migrateNormalizedType (v8) preenche tipo_normalizado nos documentos migrados quando a v2 ainda
+// gravava a taxonomia em tipo_imovel
+func migrateNormalizedType(doc bson.M) error {
+	if tipo, _ := doc["tipo_normalizado"].(string); tipo != "" {
+		return nil
+	}
+	tipo, _ := doc["tipo_imovel"].(string)
+	endereco, _ := doc["endereco"].(string)
+	descricao, _ := doc["descricao"].(string)
+	doc["tipo_normalizado"] = NormalizePropertyType(tipo, endereco+" "+descricao)
+	return nil
+}
+
+// migrateFeatures (v3) detecta os recursos de infraestrutura no texto do anúncio
+func migrateFeatures(doc bson.M) error {
+	var property Property
+	if err := convertBSON(doc, &property); err != nil {
+		return err
+	}
+	applyFeatures(&property)
+	doc["energia_solar"] = property.EnergiaSolar
+	doc["poco_artesiano"] = property.PocoArtesiano
+	doc["aquecimento_gas"] = property.AquecimentoGas
+	return nil
+}
+
+// migrateAuction (v4) sinaliza anúncios de leilão com data e lance mínimo
+func migrateAuction(doc bson.M) error {
+	var property Property
+	if err := convertBSON(doc, &property); err != nil {
+		return err
+	}
+	applyAuction(&property)
+	doc["leilao"] = property.Leilao
+	if property.LeilaoData != nil {
+		doc["leilao_data"] = *property.LeilaoData
+	}
+	if property.LanceMinimo > 0 {
+		doc["lance_minimo"] = property.LanceMinimo
+	}
+	return nil
+}
+
+// migrateCommercialLease (v5) separa venda e aluguel e detecta finalidade, testada e zoneamento
+func migrateCommercialLease(doc bson.M) error {
+	var property Property
+	if err := convertBSON(doc, &property); err != nil {
+		return err
+	}
+	applyCommercialLease(&property)
+	fields := map[string]interface{}{
+		"finalidade":    property.Finalidade,
+		"valor_venda":   property.ValorVenda,
+		"valor_aluguel": property.ValorAluguel,
+		"testada":       property.Testada,
+	}
+	for key, value := range fields {
+		if value != "" && value != 0.0 {
+			doc[key] = value
+		}
+	}
+	if property.Zoneamento != nil {
+		doc["zoneamento"] = property.Zoneamento
+	}
+	return nil
+}
+
+// bsonStrings converte uma lista BSON em []string
+func bsonStrings(value interface{}) []string {
+	var result []string
+	if list, ok := value.(bson.A); ok {
+		for _, item := range list {
+			if text, ok := item.(string); ok {
+				result = append(result, text)
+			}
+		}
+	}
+	return result
+}
+
+// convertBSON converte um documento genérico na estrutura de destino
+func convertBSON(doc bson.M, target interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode document: %v", err)
+	}
+	if err := bson.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to decode document: %v", err)
+	}
+	return nil
+}
+
+// decodeProperties lê os imóveis do cursor migrando em memória os documentos de versões
+// anteriores; os documentos migrados são regravados em segundo plano (migração preguiçosa)
+func (r *MongoRepository) decodeProperties(ctx context.Context, cursor *mongo.Cursor) ([]Property, error) {
+	return r.decodePropertiesFunc(ctx, cursor, nil)
+}
+
+// decodePropertiesFunc é decodeProperties com um filtro: keep decide, na ordem do cursor, quais
+// imóveis entram no resultado (nil mantém todos). Os descartados não ficam em memória.
+func (r *MongoRepository) decodePropertiesFunc(ctx context.Context, cursor *mongo.Cursor, keep func(Property) bool) ([]Property, error) {
+	var properties []Property
+	var migrated []bson.M
+	for cursor.Next(ctx) {
+		property, doc, err := decodeProperty(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			migrated = append(migrated, doc)
+		}
+		if keep != nil && !keep(property) {
+			continue
+		}
+		properties = append(properties, property)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(migrated) > 0 {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := r.saveMigratedDocuments(ctx, migrated); err != nil {
+				log.Printf("Failed to persist migrated property documents: %v", err)
+			}
+		}()
+	}
+	return properties, nil
+}
+
+// decodeProperty decodifica um documento; se estava em versão anterior, retorna também o
+// documento migrado para ser regravado
+func decodeProperty(raw bson.Raw) (Property, bson.M, error) {
+	var property Property
+	if version, ok := raw.Lookup("schema_version").AsInt64OK(); ok && int(version) >= CurrentSchemaVersion() {
+		err := bson.Unmarshal(raw, &property)
+		return property, nil, err
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return property, nil, fmt.Errorf("failed to decode property: %v", err)
+	}
+	if _, err := MigrateDocument(doc); err != nil {
+		return property, nil, err
+	}
+	if err := convertBSON(doc, &property); err != nil {
+		return property, nil, err
+	}
+	return property, doc, nil
+}
+
+// saveMigratedDocuments regrava os documentos migrados (apenas se ainda estiverem na versão antiga)
+func (r *MongoRepository) saveMigratedDocuments(ctx context.Context, docs []bson.M) error {
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		id := doc["_id"]
+		fields := bson.M{}
+		for key, value := range doc {
+			if key != "_id" {
+				fields[key] = value
+			}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "schema_version": bson.M{"$not": bson.M{"$gte": doc["schema_version"]}}}).
+			SetUpdate(bson.M{"$set": fields}))
+	}
+
+	return withStorageRetry(ctx, "failed to save migrated properties", func() error {
+		_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		return err
+	})
+}
+
+// MigrateSchema migra em lotes todos os documentos em versões anteriores; retorna quantos migrou
+func (r *MongoRepository) MigrateSchema(ctx context.Context, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	filter := bson.M{"schema_version": bson.M{"$not": bson.M{"$gte": CurrentSchemaVersion()}}}
+
+	migrated := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return migrated, err
+		}
+
+		var docs []bson.M
+		err := withStorageRetry(ctx, "failed to load properties for schema migration", func() error {
+			cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(int64(batchSize)))
+			if err != nil {
+				return err
+			}
+			docs = nil
+			return cursor.All(ctx, &docs)
+		})
+		if err != nil {
+			return migrated, err
+		}
+		if len(docs) == 0 {
+			return migrated, nil
+		}
+
+		for _, doc := range docs {
+			if _, err := MigrateDocument(doc); err != nil {
+				return migrated, fmt.Errorf("property %v: %v", doc["_id"], err)
+			}
+		}
+		if err := r.saveMigratedDocuments(ctx, docs); err != nil {
+			return migrated, err
+		}
+		migrated += len(docs)
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ShortIDLength é o tamanho dos IDs curtos dos anúncios (50 bits do hash)
+const ShortIDLength = 10
+
+// shortIDEncoding usa o alfabeto de Crockford em minúsculas (sem i, l, o e u, fáceis de confundir)
+var shortIDEncoding = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+
+// GenerateShortID gera o ID curto do anúncio a partir da URL canônica, então o ID muda junto com a
+// URL (o anúncio em outra URL é outro registro). Unidades de um anúncio multi-unidade (mesma URL)
+// são diferenciadas pela quantidade de quartos; o projeto e os lotes de um loteamento, pelo
+// identificador do lote
+func GenerateShortID(property Property) string {
+	key := "short|" + normalizeURL(property.URL)
+	switch {
+	case property.Loteamento != nil:
+		key += "#loteamento"
+	case property.Lote != "":
+		key += "#lote:" + normalizeContent(property.Lote)
+	case property.ParentID != "":
+		key += "#" + strconv.Itoa(property.Quartos)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return shortIDEncoding.EncodeToString(sum[:])[:ShortIDLength]
+}
+
+// ValidShortID indica se o texto tem o formato de um ID curto
+func ValidShortID(id string) bool {
+	if len(id) != ShortIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !strings.ContainsRune("0123456789abcdefghjkmnpqrstvwxyz", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// PropertyShortIDRepository é implementado por repositórios capazes de buscar o imóvel pelo ID curto
+type PropertyShortIDRepository interface {
+	FindByShortID(ctx context.Context, shortID string) (*Property, error)
+}
+
+// FindByShortID retorna o anúncio salvo mais recentemente com o ID curto (ErrPropertyNotFound se
+// não existir)
+func (r *MongoRepository) FindByShortID(ctx context.Context, shortID string) (*Property, error) {
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+
+	raw, err := r.collection.FindOne(ctx, bson.M{"short_id": shortID}, findOptions).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPropertyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property by short id: %v", err)
+	}
+	property, _, err := decodeProperty(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode property: %v", err)
+	}
+	return &property, nil
+}
+
+// migrateShortID (v6) gera o ID curto dos anúncios gravados antes dele
+func migrateShortID(doc bson.M) error {
+	if shortID, _ := doc["short_id"].(string); shortID != "" {
+		return nil
+	}
+	var property Property
+	if err := convertBSON(doc, &property); err != nil {
+		return err
+	}
+	if property.URL != "" {
+		doc["short_id"] = GenerateShortID(property)
+	}
+	return nil
+}
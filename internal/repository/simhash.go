@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// DefaultSimHashThreshold é a distância de Hamming (em 64 bits) até a qual duas páginas
+// são consideradas iguais
+const DefaultSimHashThreshold = 3
+
+// Pesos das características estruturais no simhash (o texto da página tem peso 1)
+const (
+	simHashDescriptionWeight = 3
+	simHashAddressWeight     = 4
+	simHashPriceWeight       = 6
+	simHashShingleSize       = 3
+)
+
+// fingerprintNoisePatterns removem trechos que mudam a cada visita sem alterar o anúncio
+// (datas, horários, "há 2 dias", contadores de visualização)
+var fingerprintNoisePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b\d{1,2}[/.-]\d{1,2}[/.-]\d{2,4}\b`),
+	regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}(t[\d:.]+z?)?\b`),
+	regexp.MustCompile(`\b\d{1,2}:\d{2}(:\d{2})?\s*(h|hs|am|pm)?\b`),
+	regexp.MustCompile(`\bh[aá]\s+\d+\s+(segundos?|minutos?|horas?|dias?|semanas?|m[eê]s(es)?)\b`),
+	regexp.MustCompile(`\b\d+\s+(visualiza[cç][oõ]es|visitas|views|pessoas\s+visualizaram)\b`),
+	regexp.MustCompile(`\b(atualizado|publicado|postado)\s+(em|h[aá])\s+\S+`),
+}
+
+// ContentSignature reúne as assinaturas de conteúdo de uma página
+type ContentSignature struct {
+	ContentHash string // SHA-256 de preços, endereços e descrições normalizados
+	PriceHash   string // SHA-256 apenas dos valores (qualquer mudança de preço é real)
+	SimHash     string // simhash de 64 bits (hex) do texto + características estruturais
+}
+
+// GenerateContentSignature calcula as assinaturas ignorando ruído como datas e contadores
+func GenerateContentSignature(pageText string, prices, addresses, descriptions []string) ContentSignature {
+	prices = stripFingerprintNoise(prices)
+	addresses = stripFingerprintNoise(addresses)
+	descriptions = stripFingerprintNoise(descriptions)
+
+	priceValues := normalizePriceValues(prices)
+	priceHash := sha256.Sum256([]byte(strings.Join(priceValues, "|")))
+
+	features := make(map[string]int)
+	addShingles(features, "text:", NormalizeFingerprintText(pageText), 1)
+	for _, description := range descriptions {
+		addShingles(features, "desc:", utils.NormalizeText(description), simHashDescriptionWeight)
+	}
+	for _, address := range addresses {
+		features["addr:"+utils.NormalizeText(address)] += simHashAddressWeight
+	}
+	for _, value := range priceValues {
+		features["price:"+value] += simHashPriceWeight
+	}
+
+	return ContentSignature{
+		ContentHash: GenerateContentHash(prices, addresses, descriptions),
+		PriceHash:   fmt.Sprintf("%x", priceHash),
+		SimHash:     FormatSimHash(ComputeSimHash(features)),
+	}
+}
+
+// NormalizeFingerprintText remove ruído e normaliza o texto (minúsculas, sem acentos)
+func NormalizeFingerprintText(text string) string {
+	text = strings.ToLower(text)
+	for _, pattern := range fingerprintNoisePatterns {
+		text = pattern.ReplaceAllString(text, " ")
+	}
+	return utils.NormalizeText(text)
+}
+
+// ComputeSimHash calcula o simhash de 64 bits de um conjunto de características ponderadas
+func ComputeSimHash(features map[string]int) uint64 {
+	var vector [64]int
+	for feature, weight := range features {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(feature))
+		hash := hasher.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if hash&(1<<uint(bit)) != 0 {
+				vector[bit] += weight
+			} else {
+				vector[bit] -= weight
+			}
+		}
+	}
+
+	var simhash uint64
+	for bit := 0; bit < 64; bit++ {
+		if vector[bit] > 0 {
+			simhash |= 1 << uint(bit)
+		}
+	}
+	return simhash
+}
+
+// HammingDistance conta os bits diferentes entre dois simhashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// FormatSimHash serializa o simhash em hexadecimal (uint64 não cabe em int64 do BSON)
+func FormatSimHash(simhash uint64) string {
+	return fmt.Sprintf("%016x", simhash)
+}
+
+// ParseSimHash converte o simhash armazenado de volta para uint64
+func ParseSimHash(value string) (uint64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	simhash, err := strconv.ParseUint(value, 16, 64)
+	return simhash, err == nil
+}
+
+// SimHashDistance retorna a distância entre dois simhashes serializados (-1 se algum for inválido)
+func SimHashDistance(a, b string) int {
+	first, okA := ParseSimHash(a)
+	second, okB := ParseSimHash(b)
+	if !okA || !okB {
+		return -1
+	}
+	return HammingDistance(first, second)
+}
+
+// stripFingerprintNoise aplica NormalizeFingerprintText preservando símbolos de preço
+func stripFingerprintNoise(values []string) []string {
+	cleaned := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(value)
+		for _, pattern := range fingerprintNoisePatterns {
+			value = pattern.ReplaceAllString(value, " ")
+		}
+		if value = strings.Join(strings.Fields(value), " "); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	return cleaned
+}
+
+// normalizePriceValues reduz cada preço aos dígitos, sem repetição e em ordem de aparição
+func normalizePriceValues(prices []string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, price := range prices {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, price)
+		digits = strings.TrimLeft(digits, "0")
+		if digits != "" && !seen[digits] {
+			seen[digits] = true
+			values = append(values, digits)
+		}
+	}
+	return values
+}
+
+// addShingles adiciona sequências de palavras (shingles) como características do simhash
+func addShingles(features map[string]int, prefix, text string, weight int) {
+	words := strings.Fields(text)
+	if len(words) < simHashShingleSize {
+		if len(words) > 0 {
+			features[prefix+strings.Join(words, " ")] += weight
+		}
+		return
+	}
+	for i := 0; i+simHashShingleSize <= len(words); i++ {
+		features[prefix+strings.Join(words[i:i+simHashShingleSize], " ")] += weight
+	}
+}
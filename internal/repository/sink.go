@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tipos de saída aceitos em OUTPUT_SINKS / -sinks
+const (
+	SinkMongo  = "mongo"
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkHTTP   = "http"
+)
+
+// errSinkQueryUnsupported é retornado pelas consultas quando nenhuma saída é um repositório
+var errSinkQueryUnsupported = errors.New("query not supported by the configured output sinks")
+
+// PropertySink é um destino dos imóveis extraídos (banco, arquivo, webhook...)
+type PropertySink interface {
+	Name() string
+	Write(ctx context.Context, property Property) error
+	Close() error
+}
+
+// SinkSpec descreve uma saída: "mongo", "stdout", "file:caminho.ndjson" ou uma URL http(s)
+type SinkSpec struct {
+	Kind   string
+	Target string
+}
+
+// ParseSinkSpecs interpreta a lista de saídas configurada para a execução
+func ParseSinkSpecs(entries []string) ([]SinkSpec, error) {
+	var specs []SinkSpec
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case entry == SinkMongo || entry == SinkStdout:
+			specs = append(specs, SinkSpec{Kind: entry})
+		case strings.HasPrefix(entry, "http://") || strings.HasPrefix(entry, "https://"):
+			specs = append(specs, SinkSpec{Kind: SinkHTTP, Target: entry})
+		case strings.HasPrefix(entry, SinkFile+":") && len(entry) > len(SinkFile)+1:
+			specs = append(specs, SinkSpec{Kind: SinkFile, Target: entry[len(SinkFile)+1:]})
+		default:
+			return nil, fmt.Errorf("invalid output sink %q (use mongo, stdout, file:<path> or an http(s) URL)", entry)
+		}
+	}
+	if len(specs) == 0 {
+		return nil, errors.New("no output sink configured")
+	}
+	return specs, nil
+}
+
+// HasSink indica se a lista contém uma saída do tipo informado
+func HasSink(specs []SinkSpec, kind string) bool {
+	for _, spec := range specs {
+		if spec.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenSinks cria as saídas; primary é o repositório usado pela saída "mongo"
+func OpenSinks(specs []SinkSpec, primary PropertyRepository) ([]PropertySink, error) {
+	var sinks []PropertySink
+	for _, spec := range specs {
+		switch spec.Kind {
+		case SinkMongo:
+			if primary == nil {
+				return nil, errors.New("mongo sink requires a property repository")
+			}
+			sinks = append(sinks, &RepositorySink{repo: primary})
+		case SinkStdout:
+			sinks = append(sinks, NewJSONLinesSink(SinkStdout, nopWriteCloser{os.Stdout}))
+		case SinkFile:
+			file, err := os.OpenFile(spec.Target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				closeSinks(sinks)
+				return nil, fmt.Errorf("failed to open sink file: %v", err)
+			}
+			sinks = append(sinks, NewJSONLinesSink(SinkFile+":"+spec.Target, file))
+		case SinkHTTP:
+			sinks = append(sinks, NewHTTPSink(spec.Target))
+		}
+	}
+	return sinks, nil
+}
+
+// closeSinks fecha as saídas já abertas (falha ao abrir as seguintes)
+func closeSinks(sinks []PropertySink) {
+	for _, sink := range sinks {
+		sink.Close()
+	}
+}
+
+// RepositorySink grava os imóveis em um PropertyRepository (MongoDB)
+type RepositorySink struct {
+	repo PropertyRepository
+}
+
+// Name identifica a saída
+func (rs *RepositorySink) Name() string {
+	return SinkMongo
+}
+
+// Write grava o imóvel no repositório
+func (rs *RepositorySink) Write(ctx context.Context, property Property) error {
+	return rs.repo.Save(ctx, property)
+}
+
+// Close não fecha o repositório: ele pertence a quem o criou
+func (rs *RepositorySink) Close() error {
+	return nil
+}
+
+// JSONLinesSink escreve um imóvel por linha em JSON (NDJSON) em um arquivo ou na saída padrão
+type JSONLinesSink struct {
+	name    string
+	mutex   sync.Mutex
+	writer  io.WriteCloser
+	encoder *json.Encoder
+}
+
+// NewJSONLinesSink cria a saída NDJSON sobre o writer
+func NewJSONLinesSink(name string, writer io.WriteCloser) *JSONLinesSink {
+	return &JSONLinesSink{name: name, writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+// Name identifica a saída
+func (js *JSONLinesSink) Name() string {
+	return js.name
+}
+
+// Write escreve o imóvel como uma linha JSON
+func (js *JSONLinesSink) Write(ctx context.Context, property Property) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	if err := js.encoder.Encode(property); err != nil {
+		return fmt.Errorf("failed to write property to %s: %v", js.name, err)
+	}
+	return nil
+}
+
+// Close fecha o arquivo
+func (js *JSONLinesSink) Close() error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	return js.writer.Close()
+}
+
+// nopWriteCloser evita fechar a saída padrão
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// HTTPSink envia cada imóvel em JSON via POST (webhook de outro pipeline)
+type HTTPSink struct {
+	URL    string
+	client *http.Client
+}
+
+// NewHTTPSink cria a saída HTTP
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{URL: url, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Name identifica a saída
+func (hs *HTTPSink) Name() string {
+	return hs.URL
+}
+
+// Write envia o imóvel ao endpoint
+func (hs *HTTPSink) Write(ctx context.Context, property Property) error {
+	payload, err := json.Marshal(property)
+	if err != nil {
+		return fmt.Errorf("failed to encode property: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hs.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create sink request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send property to %s: %v", hs.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sink %s returned status %d", hs.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// Close não mantém recursos abertos
+func (hs *HTTPSink) Close() error {
+	return nil
+}
+
+// SinkRepository expõe as saídas como PropertyRepository para os engines: Save grava em todas
+// as saídas em paralelo; as consultas usam o repositório principal (nil se não houver Mongo)
+type SinkRepository struct {
+	primary PropertyRepository
+	sinks   []PropertySink
+}
+
+// NewSinkRepository combina as saídas da execução
+func NewSinkRepository(primary PropertyRepository, sinks []PropertySink) *SinkRepository {
+	return &SinkRepository{primary: primary, sinks: sinks}
+}
+
+// Save grava o imóvel em todas as saídas; falhas de uma saída não impedem as demais
+func (sr *SinkRepository) Save(ctx context.Context, property Property) error {
+	if len(sr.sinks) == 1 {
+		return sr.sinks[0].Write(ctx, property)
+	}
+
+	errs := make([]error, len(sr.sinks))
+	var wg sync.WaitGroup
+	for i, sink := range sr.sinks {
+		wg.Add(1)
+		go func(i int, sink PropertySink) {
+			defer wg.Done()
+			errs[i] = sink.Write(ctx, property)
+		}(i, sink)
+	}
+	wg.Wait()
+
+	var messages []string
+	var storageErr error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s: %v", sr.sinks[i].Name(), err))
+		if storageErr == nil && IsStorageUnavailable(err) {
+			storageErr = err
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	// Mantém o erro de indisponibilidade do banco para a classificação dos engines
+	if storageErr != nil {
+		return storageErr
+	}
+	return fmt.Errorf("failed to write to output sinks: %s", strings.Join(messages, "; "))
+}
+
+// FindAll consulta o repositório principal
+func (sr *SinkRepository) FindAll(ctx context.Context) ([]Property, error) {
+	if sr.primary == nil {
+		return nil, errSinkQueryUnsupported
+	}
+	return sr.primary.FindAll(ctx)
+}
+
+// FindWithFilters consulta o repositório principal
+func (sr *SinkRepository) FindWithFilters(ctx context.Context, filter PropertyFilter, pagination PaginationParams) (*PropertySearchResult, error) {
+	if sr.primary == nil {
+		return nil, errSinkQueryUnsupported
+	}
+	return sr.primary.FindWithFilters(ctx, filter, pagination)
+}
+
+// ClearAll limpa o repositório principal
+func (sr *SinkRepository) ClearAll(ctx context.Context) error {
+	if sr.primary == nil {
+		return errSinkQueryUnsupported
+	}
+	return sr.primary.ClearAll(ctx)
+}
+
+// FindByURL permite a classificação de mudanças do crawling incremental (nil sem repositório)
+func (sr *SinkRepository) FindByURL(ctx context.Context, url string) (*Property, error) {
+	if lookup, ok := sr.primary.(PropertyLookupRepository); ok {
+		return lookup.FindByURL(ctx, url)
+	}
+	return nil, nil
+}
+
+// SaveArchivedListing grava no repositório principal o anúncio removido completado com a cópia
+// arquivada (as saídas recebem apenas os anúncios salvos pelo crawling)
+func (sr *SinkRepository) SaveArchivedListing(ctx context.Context, property Property) error {
+	if store, ok := sr.primary.(ArchivedListingRepository); ok {
+		return store.SaveArchivedListing(ctx, property)
+	}
+	return errArchivedListingUnsupported
+}
+
+// Close fecha as saídas (o repositório principal é fechado por quem o criou)
+func (sr *SinkRepository) Close() {
+	closeSinks(sr.sinks)
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PathStatsCollection acumula, por domínio e caminho, a classificação das páginas entre crawlings
+const PathStatsCollection = "path_classification_stats"
+
+// ErrSkipPathNotFound indica que o caminho não está na lista aprendida do domínio
+var ErrSkipPathNotFound = errors.New("learned skip path not found")
+
+// PathClassification é a amostra de um crawling para um caminho: páginas que não são anúncios e
+// páginas que são anúncios ou levaram a um anúncio
+type PathClassification struct {
+	Domain      string `json:"domain"`
+	Path        string `json:"path"`
+	NonProperty int    `json:"non_property"`
+	Productive  int    `json:"productive"`
+}
+
+// PathStats é o acumulado de um caminho entre os crawlings
+type PathStats struct {
+	Domain        string     `bson:"domain" json:"domain"`
+	Path          string     `bson:"path" json:"path"`
+	Crawls        int        `bson:"crawls" json:"crawls"`
+	NonProperty   int        `bson:"non_property" json:"non_property"`
+	Productive    int        `bson:"productive" json:"productive"`
+	Learned       bool       `bson:"learned" json:"learned"`
+	Ignored       bool       `bson:"ignored,omitempty" json:"ignored,omitempty"` // removido pelo operador: não volta à lista
+	LastCrawledAt time.Time  `bson:"last_crawled_at" json:"last_crawled_at"`
+	LearnedAt     *time.Time `bson:"learned_at,omitempty" json:"learned_at,omitempty"`
+}
+
+// SkipPathPolicy define quando um caminho entra na lista aprendida do domínio: classificado como
+// não-anúncio em pelo menos MinCrawls crawlings e MinPages páginas, sem nunca levar a um anúncio
+type SkipPathPolicy struct {
+	MinCrawls int
+	MinPages  int
+}
+
+// Qualifies informa se o acumulado justifica pular o caminho
+func (p SkipPathPolicy) Qualifies(stats PathStats) bool {
+	return !stats.Ignored && stats.Productive == 0 &&
+		stats.Crawls >= p.MinCrawls && stats.NonProperty >= p.MinPages
+}
+
+// SkipPathRepository é implementado por repositórios capazes de acumular a classificação dos
+// caminhos e manter a lista aprendida de caminhos ignorados por domínio
+type SkipPathRepository interface {
+	// RecordPathStats soma as amostras de um crawling e retorna os caminhos que passaram a ser ignorados
+	RecordPathStats(ctx context.Context, samples []PathClassification, policy SkipPathPolicy) ([]PathStats, error)
+	FindPathStats(ctx context.Context, domain string) ([]PathStats, error)
+	// ForgetSkipPath remove o caminho da lista aprendida e impede que ele seja aprendido de novo
+	ForgetSkipPath(ctx context.Context, domain, path string) error
+}
+
+// URLPathPrefix retorna o primeiro segmento do caminho da URL em minúsculas (ex: "/blog" para
+// https://imob.com.br/Blog/dicas); vazio para a página inicial
+func URLPathPrefix(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	segment := strings.SplitN(strings.Trim(parsed.Path, "/"), "/", 2)[0]
+	if segment == "" {
+		return ""
+	}
+	return "/" + strings.ToLower(segment)
+}
+
+// pathStats retorna a coleção dos acumulados por caminho
+func (r *MongoDomainConfigRepository) pathStats() *mongo.Collection {
+	return r.collection.Database().Collection(PathStatsCollection)
+}
+
+// RecordPathStats soma as amostras ao acumulado e grava os caminhos qualificados em
+// learned_skip_paths da configuração do domínio (criada se ainda não existir)
+func (r *MongoDomainConfigRepository) RecordPathStats(ctx context.Context, samples []PathClassification, policy SkipPathPolicy) ([]PathStats, error) {
+	collection := r.pathStats()
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "domain", Value: 1}, {Key: "path", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return nil, fmt.Errorf("failed to create path stats indexes: %v", err)
+	}
+
+	now := time.Now()
+	var learned []PathStats
+	for _, sample := range samples {
+		domain := NormalizeDomain(sample.Domain)
+		var stats PathStats
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{"domain": domain, "path": sample.Path},
+			bson.M{
+				"$inc": bson.M{"crawls": 1, "non_property": sample.NonProperty, "productive": sample.Productive},
+				"$set": bson.M{"last_crawled_at": now},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&stats)
+		if err != nil {
+			return learned, fmt.Errorf("failed to record path stats: %v", err)
+		}
+		if stats.Learned || !policy.Qualifies(stats) {
+			continue
+		}
+
+		if err := r.addSkipPath(ctx, domain, sample.Path); err != nil {
+			return learned, err
+		}
+		if _, err := collection.UpdateOne(ctx,
+			bson.M{"domain": domain, "path": sample.Path},
+			bson.M{"$set": bson.M{"learned": true, "learned_at": now}},
+		); err != nil {
+			return learned, fmt.Errorf("failed to mark learned skip path: %v", err)
+		}
+		stats.Learned, stats.LearnedAt = true, &now
+		learned = append(learned, stats)
+	}
+	return learned, nil
+}
+
+// addSkipPath acrescenta o caminho à lista aprendida do domínio
+func (r *MongoDomainConfigRepository) addSkipPath(ctx context.Context, domain, path string) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"domain": domain},
+		bson.M{
+			"$addToSet":    bson.M{"learned_skip_paths": path},
+			"$set":         bson.M{"updated_at": now},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add learned skip path: %v", err)
+	}
+	return nil
+}
+
+// FindPathStats retorna o acumulado dos caminhos do domínio, os aprendidos primeiro
+func (r *MongoDomainConfigRepository) FindPathStats(ctx context.Context, domain string) ([]PathStats, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "learned", Value: -1}, {Key: "non_property", Value: -1}})
+	cursor, err := r.pathStats().Find(ctx, bson.M{"domain": NormalizeDomain(domain)}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find path stats: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	stats := []PathStats{}
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode path stats: %v", err)
+	}
+	return stats, nil
+}
+
+// ForgetSkipPath remove o caminho da lista aprendida e marca o acumulado como ignorado
+func (r *MongoDomainConfigRepository) ForgetSkipPath(ctx context.Context, domain, path string) error {
+	domain = NormalizeDomain(domain)
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"domain": domain, "learned_skip_paths": path},
+		bson.M{"$pull": bson.M{"learned_skip_paths": path}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove learned skip path: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrSkipPathNotFound
+	}
+
+	if _, err := r.pathStats().UpdateOne(ctx,
+		bson.M{"domain": domain, "path": path},
+		bson.M{"$set": bson.M{"learned": false, "ignored": true}},
+	); err != nil {
+		return fmt.Errorf("failed to update path stats: %v", err)
+	}
+	log.Printf("Learned skip path removed: %s%s", domain, path)
+	return nil
+}
@@ -0,0 +1,460 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+// Prefixos das chaves no Badger
+const (
+	urlCacheProcessedPrefix   = "u:"
+	urlCacheFingerprintPrefix = "f:"
+)
+
+// URLCacheOptions configura o cache local de URLs processadas e fingerprints
+type URLCacheOptions struct {
+	Dir          string        // diretório do Badger
+	TTL          time.Duration // validade das entradas em cache
+	SyncInterval time.Duration // intervalo da sincronização com o repositório (0 desativa)
+}
+
+// URLCacheStats resume o uso do cache
+type URLCacheStats struct {
+	Hits     int64     `json:"hits"`
+	Misses   int64     `json:"misses"`
+	Synced   int64     `json:"synced"`
+	LastSync time.Time `json:"last_sync,omitempty"`
+}
+
+// urlSyncSource é implementado pelos repositórios que listam os fingerprints gravados por outras
+// instâncias e informam o que o TTL e a poda por tamanho já removeram (MongoURLRepository)
+type urlSyncSource interface {
+	GetFingerprintsSince(ctx context.Context, since time.Time) ([]PageFingerprint, error)
+	OldestRetained(ctx context.Context) (urls, fingerprints *time.Time, err error)
+}
+
+// CachedURLRepository mantém em um BadgerDB local as URLs processadas e os fingerprints
+// consultados a cada URL (ShouldProcessURL), evitando idas ao Mongo. As gravações são
+// write-through (repositório e cache) e a sincronização periódica traz as URLs e os fingerprints
+// gravados por outras instâncias. Entradas anteriores ao registro mais antigo do repositório
+// (removidas pelo TTL ou pela poda) são descartadas na leitura. Ausências também são guardadas,
+// com validade de um intervalo de sync; sem urlSyncSource, os fingerprints também.
+type CachedURLRepository struct {
+	backend URLRepository
+	db      *badger.DB
+	options URLCacheOptions
+
+	hits   int64
+	misses int64
+	synced int64
+
+	// Data (UnixNano) do registro mais antigo de cada coleção na última sincronização
+	urlCutoff         int64
+	fingerprintCutoff int64
+
+	syncMutex sync.Mutex
+	lastSync  time.Time
+	closed    bool
+	stopSync  chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCachedURLRepository abre (ou cria) o cache no diretório informado à frente do repositório
+func NewCachedURLRepository(backend URLRepository, opts URLCacheOptions) (*CachedURLRepository, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("url cache directory is required")
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+
+	db, err := badger.Open(badger.DefaultOptions(opts.Dir).WithLoggingLevel(badger.WARNING))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open url cache: %v", err)
+	}
+
+	return &CachedURLRepository{
+		backend:  backend,
+		db:       db,
+		options:  opts,
+		stopSync: make(chan struct{}),
+	}, nil
+}
+
+// Backend retorna o repositório por trás do cache
+func (r *CachedURLRepository) Backend() URLRepository {
+	return r.backend
+}
+
+// SaveProcessedURL grava no repositório e atualiza o cache
+func (r *CachedURLRepository) SaveProcessedURL(ctx context.Context, url ProcessedURL) error {
+	if err := r.backend.SaveProcessedURL(ctx, url); err != nil {
+		return err
+	}
+	r.store(urlCacheProcessedPrefix+url.URL, &url)
+	return nil
+}
+
+// GetProcessedURL consulta o cache e, na falta, o repositório
+func (r *CachedURLRepository) GetProcessedURL(ctx context.Context, url string) (*ProcessedURL, error) {
+	var cached ProcessedURL
+	found, present := r.load(urlCacheProcessedPrefix+url, &cached)
+	if found && !(present && r.evicted(urlCacheProcessedPrefix+url, cached.ProcessedAt, &r.urlCutoff)) {
+		if !present {
+			return nil, nil
+		}
+		return &cached, nil
+	}
+
+	processed, err := r.backend.GetProcessedURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	r.store(urlCacheProcessedPrefix+url, processed)
+	return processed, nil
+}
+
+// GetProcessedURLsSince é delegado ao repositório
+func (r *CachedURLRepository) GetProcessedURLsSince(ctx context.Context, since time.Time) ([]ProcessedURL, error) {
+	return r.backend.GetProcessedURLsSince(ctx, since)
+}
+
+// IsURLProcessedRecently responde a partir do registro da URL (em cache ou carregado do repositório)
+func (r *CachedURLRepository) IsURLProcessedRecently(ctx context.Context, url string, maxAge time.Duration) (bool, error) {
+	processed, err := r.GetProcessedURL(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	if processed == nil || processed.Status != "success" {
+		return false, nil
+	}
+	return !processed.ProcessedAt.Before(time.Now().Add(-maxAge)), nil
+}
+
+// SaveFingerprint grava no repositório e atualiza o cache
+func (r *CachedURLRepository) SaveFingerprint(ctx context.Context, fingerprint PageFingerprint) error {
+	if err := r.backend.SaveFingerprint(ctx, fingerprint); err != nil {
+		return err
+	}
+	r.store(urlCacheFingerprintPrefix+fingerprint.URL, &fingerprint)
+	return nil
+}
+
+// GetFingerprint consulta o cache e, na falta, o repositório
+func (r *CachedURLRepository) GetFingerprint(ctx context.Context, url string) (*PageFingerprint, error) {
+	var cached PageFingerprint
+	found, present := r.load(urlCacheFingerprintPrefix+url, &cached)
+	if found && !(present && r.evicted(urlCacheFingerprintPrefix+url, cached.LastCrawled, &r.fingerprintCutoff)) {
+		if !present {
+			return nil, nil
+		}
+		return &cached, nil
+	}
+
+	fingerprint, err := r.backend.GetFingerprint(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	r.store(urlCacheFingerprintPrefix+url, fingerprint)
+	return fingerprint, nil
+}
+
+// UpdateFingerprint atualiza no repositório e no cache
+func (r *CachedURLRepository) UpdateFingerprint(ctx context.Context, fingerprint PageFingerprint) error {
+	if err := r.backend.UpdateFingerprint(ctx, fingerprint); err != nil {
+		r.delete(urlCacheFingerprintPrefix + fingerprint.URL)
+		return err
+	}
+	r.store(urlCacheFingerprintPrefix+fingerprint.URL, &fingerprint)
+	return nil
+}
+
+// GetFingerprintsRequiringUpdate é delegado ao repositório
+func (r *CachedURLRepository) GetFingerprintsRequiringUpdate(ctx context.Context, maxAge time.Duration) ([]PageFingerprint, error) {
+	return r.backend.GetFingerprintsRequiringUpdate(ctx, maxAge)
+}
+
+// CleanupOldRecords limpa o repositório e descarta o cache (registros removidos não podem sobrar)
+func (r *CachedURLRepository) CleanupOldRecords(ctx context.Context, maxAge time.Duration) error {
+	if err := r.backend.CleanupOldRecords(ctx, maxAge); err != nil {
+		return err
+	}
+	if err := r.db.DropAll(); err != nil {
+		return fmt.Errorf("failed to clear url cache: %v", err)
+	}
+	return nil
+}
+
+// GetStatistics é delegado ao repositório
+func (r *CachedURLRepository) GetStatistics(ctx context.Context) (*URLStatistics, error) {
+	return r.backend.GetStatistics(ctx)
+}
+
+// Close encerra a sincronização e fecha o cache; o repositório continua sob
+// responsabilidade de quem o criou
+func (r *CachedURLRepository) Close() {
+	r.closeOnce.Do(func() {
+		close(r.stopSync)
+
+		r.syncMutex.Lock()
+		defer r.syncMutex.Unlock()
+		r.closed = true
+		if err := r.db.Close(); err != nil {
+			log.Printf("Error closing url cache: %v", err)
+		}
+	})
+}
+
+// StartSync aquece o cache e inicia a sincronização periódica com o repositório (SyncInterval)
+func (r *CachedURLRepository) StartSync(ctx context.Context) {
+	if r.options.SyncInterval <= 0 {
+		return
+	}
+
+	go func() {
+		if count, err := r.Sync(ctx); err != nil {
+			log.Printf("Warning: URL cache sync failed: %v", err)
+		} else {
+			log.Printf("URL cache warmed with %d processed URLs", count)
+		}
+
+		ticker := time.NewTicker(r.options.SyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopSync:
+				return
+			case <-ticker.C:
+				if _, err := r.Sync(ctx); err != nil {
+					log.Printf("Warning: URL cache sync failed: %v", err)
+					continue
+				}
+				r.compact()
+			}
+		}
+	}()
+}
+
+// Sync carrega no cache as URLs processadas e os fingerprints gravados desde a última
+// sincronização (na primeira, os das últimas TTL), atualiza os limites de retenção do repositório
+// e retorna quantas URLs foram atualizadas
+func (r *CachedURLRepository) Sync(ctx context.Context) (int, error) {
+	r.syncMutex.Lock()
+	defer r.syncMutex.Unlock()
+
+	if r.closed {
+		return 0, fmt.Errorf("url cache is closed")
+	}
+	since := r.lastSync
+	if since.IsZero() {
+		since = time.Now().Add(-r.options.TTL)
+	}
+	started := time.Now()
+
+	urls, err := r.backend.GetProcessedURLsSince(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+	var fingerprints []PageFingerprint
+	source, tracked := r.backend.(urlSyncSource)
+	if tracked {
+		if fingerprints, err = source.GetFingerprintsSince(ctx, since); err != nil {
+			return 0, err
+		}
+	}
+
+	batch := r.db.NewWriteBatch()
+	defer batch.Cancel()
+	for i := range urls {
+		if err := r.batchStore(batch, urlCacheProcessedPrefix+urls[i].URL, &urls[i]); err != nil {
+			return 0, err
+		}
+	}
+	for i := range fingerprints {
+		if err := r.batchStore(batch, urlCacheFingerprintPrefix+fingerprints[i].URL, &fingerprints[i]); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write url cache: %v", err)
+	}
+
+	if tracked {
+		oldestURL, oldestFingerprint, err := source.OldestRetained(ctx)
+		if err != nil {
+			return 0, err
+		}
+		// Coleção vazia: tudo o que foi gravado antes da sincronização já foi removido
+		atomic.StoreInt64(&r.urlCutoff, cutoffNanos(oldestURL, started))
+		atomic.StoreInt64(&r.fingerprintCutoff, cutoffNanos(oldestFingerprint, started))
+	}
+
+	r.lastSync = started
+	atomic.AddInt64(&r.synced, int64(len(urls)))
+	return len(urls), nil
+}
+
+// batchStore grava o registro no lote da sincronização
+func (r *CachedURLRepository) batchStore(batch *badger.WriteBatch, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	if err := batch.SetEntry(badger.NewEntry([]byte(key), data).WithTTL(r.options.TTL)); err != nil {
+		return fmt.Errorf("failed to write url cache: %v", err)
+	}
+	return nil
+}
+
+// cutoffNanos converte a data do registro mais antigo; nil (coleção vazia) usa o início da
+// sincronização e uma data zerada desativa o descarte
+func cutoffNanos(oldest *time.Time, started time.Time) int64 {
+	if oldest == nil {
+		return started.UnixNano()
+	}
+	if oldest.Before(time.Unix(0, 0)) {
+		return 0
+	}
+	return oldest.UnixNano()
+}
+
+// evicted descarta a entrada anterior ao registro mais antigo do repositório (já removida de lá)
+// e a conta como falta
+func (r *CachedURLRepository) evicted(key string, at time.Time, cutoff *int64) bool {
+	limit := atomic.LoadInt64(cutoff)
+	if limit == 0 || !at.Before(time.Unix(0, limit)) {
+		return false
+	}
+	r.delete(key)
+	atomic.AddInt64(&r.hits, -1)
+	atomic.AddInt64(&r.misses, 1)
+	return true
+}
+
+// compact recupera espaço do value log; ErrNoRewrite indica que não havia o que compactar
+func (r *CachedURLRepository) compact() {
+	r.syncMutex.Lock()
+	defer r.syncMutex.Unlock()
+
+	if r.closed {
+		return
+	}
+	if err := r.db.RunValueLogGC(0.5); err != nil && !errors.Is(err, badger.ErrNoRewrite) {
+		log.Printf("Warning: URL cache GC failed: %v", err)
+	}
+}
+
+// Stats retorna as estatísticas de uso do cache
+func (r *CachedURLRepository) Stats() URLCacheStats {
+	r.syncMutex.Lock()
+	lastSync := r.lastSync
+	r.syncMutex.Unlock()
+
+	return URLCacheStats{
+		Hits:     atomic.LoadInt64(&r.hits),
+		Misses:   atomic.LoadInt64(&r.misses),
+		Synced:   atomic.LoadInt64(&r.synced),
+		LastSync: lastSync,
+	}
+}
+
+// load lê a entrada do cache; found indica se a chave existe e present se ela guarda um
+// registro (false para ausências em cache)
+func (r *CachedURLRepository) load(key string, target interface{}) (found, present bool) {
+	var data []byte
+	err := r.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		data, err = item.ValueCopy(nil)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, badger.ErrKeyNotFound) {
+			log.Printf("Warning: Failed to read url cache: %v", err)
+		}
+		atomic.AddInt64(&r.misses, 1)
+		return false, false
+	}
+
+	atomic.AddInt64(&r.hits, 1)
+	if len(data) == 0 {
+		return true, false
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		atomic.AddInt64(&r.hits, -1)
+		atomic.AddInt64(&r.misses, 1)
+		return false, false
+	}
+	return true, true
+}
+
+// store grava o registro no cache; nil guarda a ausência até a próxima sincronização
+func (r *CachedURLRepository) store(key string, value interface{}) {
+	data := []byte{}
+	ttl := r.options.TTL
+	switch v := value.(type) {
+	case *ProcessedURL:
+		if v == nil {
+			ttl = r.missingTTL()
+			break
+		}
+		data, _ = json.Marshal(v)
+	case *PageFingerprint:
+		if v == nil {
+			ttl = r.missingTTL()
+			break
+		}
+		data, _ = json.Marshal(v)
+		// Sem urlSyncSource, as gravações de outras instâncias só aparecem quando a entrada expira
+		if _, tracked := r.backend.(urlSyncSource); !tracked {
+			ttl = r.missingTTL()
+		}
+	}
+
+	err := r.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry([]byte(key), data).WithTTL(ttl))
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to write url cache: %v", err)
+	}
+}
+
+// delete remove a entrada do cache
+func (r *CachedURLRepository) delete(key string) {
+	err := r.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to write url cache: %v", err)
+	}
+}
+
+// missingTTL é a validade de uma ausência em cache: até a próxima sincronização
+func (r *CachedURLRepository) missingTTL() time.Duration {
+	if r.options.SyncInterval > 0 && r.options.SyncInterval < r.options.TTL {
+		return r.options.SyncInterval
+	}
+	return r.options.TTL
+}
+
+// MongoURLBackend retorna o MongoURLRepository, inclusive quando está atrás do cache
+func MongoURLBackend(repo URLRepository) (*MongoURLRepository, bool) {
+	if cached, ok := repo.(*CachedURLRepository); ok {
+		repo = cached.Backend()
+	}
+	mongoRepo, ok := repo.(*MongoURLRepository)
+	return mongoRepo, ok
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// URLLease registra que um job (owner) está buscando a URL até ExpiresAt
+type URLLease struct {
+	URL        string    `bson:"_id" json:"url"`
+	Owner      string    `bson:"owner" json:"owner"`
+	AcquiredAt time.Time `bson:"acquired_at" json:"acquired_at"`
+	ExpiresAt  time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// URLLeaseRepository é implementado por repositórios capazes de manter o registro compartilhado
+// de URLs em andamento, usado para que jobs simultâneos não busquem a mesma URL
+type URLLeaseRepository interface {
+	// AcquireURLLease obtém (ou renova) a lease da URL; false se outro owner detém uma lease válida
+	AcquireURLLease(ctx context.Context, url, owner string, ttl time.Duration) (bool, error)
+	// ReleaseURLLease libera a lease da URL se ela pertencer ao owner
+	ReleaseURLLease(ctx context.Context, url, owner string) error
+}
+
+// AcquireURLLease grava a lease com upsert condicionado: o documento só é atualizado se a lease
+// for do próprio owner ou estiver vencida; caso contrário o upsert colide com o _id existente
+func (r *MongoURLRepository) AcquireURLLease(ctx context.Context, url, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": url,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"owner":       owner,
+		"acquired_at": now,
+		"expires_at":  now.Add(ttl),
+	}}
+
+	_, err := r.leaseCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire URL lease: %v", err)
+	}
+	return true, nil
+}
+
+// ReleaseURLLease remove a lease da URL mantida pelo owner
+func (r *MongoURLRepository) ReleaseURLLease(ctx context.Context, url, owner string) error {
+	_, err := r.leaseCollection.DeleteOne(ctx, bson.M{"_id": url, "owner": owner})
+	if err != nil {
+		return fmt.Errorf("failed to release URL lease: %v", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -26,12 +27,20 @@ type ProcessedURL struct {
 type PageFingerprint struct {
 	URL            string    `bson:"_id" json:"url"`
 	ContentHash    string    `bson:"content_hash" json:"content_hash"`
+	PriceHash      string    `bson:"price_hash,omitempty" json:"price_hash,omitempty"`
+	SimHash        string    `bson:"simhash,omitempty" json:"simhash,omitempty"`
 	LastModified   time.Time `bson:"last_modified" json:"last_modified"`
 	PropertyCount  int       `bson:"property_count" json:"property_count"`
 	LastCrawled    time.Time `bson:"last_crawled" json:"last_crawled"`
 	ChangeDetected bool      `bson:"change_detected" json:"change_detected"`
 	AIProcessed    bool      `bson:"ai_processed" json:"ai_processed"`
 	ProcessingTime float64   `bson:"processing_time" json:"processing_time"` // em segundos
+
+	// Histórico de mudanças usado no intervalo adaptativo de revisita (ver crawler/revisit_policy.go)
+	CrawlCount      int           `bson:"crawl_count,omitempty" json:"crawl_count,omitempty"`
+	ChangeCount     int           `bson:"change_count,omitempty" json:"change_count,omitempty"`
+	RevisitInterval time.Duration `bson:"revisit_interval,omitempty" json:"revisit_interval,omitempty"`
+	NextVisit       time.Time     `bson:"next_visit,omitempty" json:"next_visit,omitempty"`
 }
 
 // URLRepository define as operações para gerenciar URLs processadas
@@ -68,20 +77,21 @@ type MongoURLRepository struct {
 	client                *mongo.Client
 	urlCollection         *mongo.Collection
 	fingerprintCollection *mongo.Collection
+	leaseCollection       *mongo.Collection // URLs em andamento compartilhadas entre jobs (ver url_lease.go)
+
+	// Retenção configurada e resultado da última poda (ver url_retention.go)
+	retentionMutex sync.RWMutex
+	retention      RetentionPolicy
+	lastPrune      *PruneResult
 }
 
 // NewMongoURLRepository cria um novo repositório de URLs
 func NewMongoURLRepository(uri, dbName string) (*MongoURLRepository, error) {
-	clientOptions := options.Client().ApplyURI(uri)
-	client, err := mongo.Connect(context.Background(), clientOptions)
+	client, err := connectMongo(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := client.Ping(context.Background(), nil); err != nil {
-		return nil, err
-	}
-
 	db := client.Database(dbName)
 	urlCollection := db.Collection("processed_urls")
 	fingerprintCollection := db.Collection("page_fingerprints")
@@ -90,6 +100,7 @@ func NewMongoURLRepository(uri, dbName string) (*MongoURLRepository, error) {
 		client:                client,
 		urlCollection:         urlCollection,
 		fingerprintCollection: fingerprintCollection,
+		leaseCollection:       db.Collection("url_leases"),
 	}
 
 	// Criar índices necessários
@@ -140,6 +151,19 @@ func (r *MongoURLRepository) createIndexes() error {
 		return fmt.Errorf("failed to create fingerprint indexes: %v", err)
 	}
 
+	// Leases expiradas são removidas pelo próprio Mongo (a aquisição já ignora as vencidas)
+	leaseIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+	if _, err := r.leaseCollection.Indexes().CreateOne(ctx, leaseIndex); err != nil {
+		return fmt.Errorf("failed to create URL lease index: %v", err)
+	}
+
+	if err := r.createDeadFilterIndexes(ctx); err != nil {
+		return err
+	}
+
 	log.Printf("URL repository indexes created successfully")
 	return nil
 }
@@ -150,12 +174,10 @@ func (r *MongoURLRepository) SaveProcessedURL(ctx context.Context, url Processed
 	filter := bson.M{"_id": url.URL}
 	update := bson.M{"$set": url}
 
-	_, err := r.urlCollection.UpdateOne(ctx, filter, update, opts)
-	if err != nil {
-		return fmt.Errorf("failed to save processed URL: %v", err)
-	}
-
-	return nil
+	return withStorageRetry(ctx, "failed to save processed URL", func() error {
+		_, err := r.urlCollection.UpdateOne(ctx, filter, update, opts)
+		return err
+	})
 }
 
 // GetProcessedURL recupera uma URL processada
@@ -189,6 +211,23 @@ func (r *MongoURLRepository) GetProcessedURLsSince(ctx context.Context, since ti
 	return urls, nil
 }
 
+// GetFingerprintsSince recupera os fingerprints gravados desde uma data (last_crawled)
+func (r *MongoURLRepository) GetFingerprintsSince(ctx context.Context, since time.Time) ([]PageFingerprint, error) {
+	filter := bson.M{"last_crawled": bson.M{"$gte": since}}
+	cursor, err := r.fingerprintCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fingerprints: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var fingerprints []PageFingerprint
+	if err := cursor.All(ctx, &fingerprints); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprints: %v", err)
+	}
+
+	return fingerprints, nil
+}
+
 // IsURLProcessedRecently verifica se uma URL foi processada recentemente
 func (r *MongoURLRepository) IsURLProcessedRecently(ctx context.Context, url string, maxAge time.Duration) (bool, error) {
 	since := time.Now().Add(-maxAge)
@@ -212,12 +251,10 @@ func (r *MongoURLRepository) SaveFingerprint(ctx context.Context, fingerprint Pa
 	filter := bson.M{"_id": fingerprint.URL}
 	update := bson.M{"$set": fingerprint}
 
-	_, err := r.fingerprintCollection.UpdateOne(ctx, filter, update, opts)
-	if err != nil {
-		return fmt.Errorf("failed to save fingerprint: %v", err)
-	}
-
-	return nil
+	return withStorageRetry(ctx, "failed to save fingerprint", func() error {
+		_, err := r.fingerprintCollection.UpdateOne(ctx, filter, update, opts)
+		return err
+	})
 }
 
 // GetFingerprint recupera um fingerprint de página
@@ -353,6 +390,13 @@ func (r *MongoURLRepository) GetStatistics(ctx context.Context) (*URLStatistics,
 		return nil, fmt.Errorf("failed to count AI savings: %v", err)
 	}
 
+	var lastCleanup time.Time
+	r.retentionMutex.RLock()
+	if r.lastPrune != nil {
+		lastCleanup = r.lastPrune.RanAt
+	}
+	r.retentionMutex.RUnlock()
+
 	return &URLStatistics{
 		TotalURLs:       totalURLs,
 		ProcessedToday:  processedToday,
@@ -360,7 +404,7 @@ func (r *MongoURLRepository) GetStatistics(ctx context.Context) (*URLStatistics,
 		FailedToday:     failedToday,
 		SkippedToday:    skippedToday,
 		TotalAISavings:  aiSavings,
-		LastCleanup:     time.Now(), // Seria melhor armazenar isso separadamente
+		LastCleanup:     lastCleanup,
 	}, nil
 }
 
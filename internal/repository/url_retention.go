@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Nomes dos índices TTL (usados para atualizar ou remover a expiração)
+const (
+	processedURLTTLIndex = "processed_at_ttl"
+	fingerprintTTLIndex  = "last_crawled_ttl"
+	pruneBatchSize       = 1000
+)
+
+// RetentionPolicy define por quanto tempo e quantos registros de URLs/fingerprints são mantidos.
+// Valores zero desativam o respectivo limite.
+type RetentionPolicy struct {
+	URLTTL          time.Duration `json:"url_ttl"`
+	FingerprintTTL  time.Duration `json:"fingerprint_ttl"`
+	MaxURLs         int64         `json:"max_urls"`
+	MaxFingerprints int64         `json:"max_fingerprints"`
+}
+
+// PruneResult resume uma execução da manutenção por limite de tamanho
+type PruneResult struct {
+	URLsRemoved         int64     `json:"urls_removed"`
+	FingerprintsRemoved int64     `json:"fingerprints_removed"`
+	RanAt               time.Time `json:"ran_at"`
+}
+
+// CollectionStorageStats descreve o tamanho de uma coleção
+type CollectionStorageStats struct {
+	Name           string     `json:"name"`
+	Documents      int64      `json:"documents"`
+	DataSizeBytes  int64      `json:"data_size_bytes"`
+	StorageBytes   int64      `json:"storage_bytes"`
+	IndexSizeBytes int64      `json:"index_size_bytes"`
+	Oldest         *time.Time `json:"oldest,omitempty"`
+	Newest         *time.Time `json:"newest,omitempty"`
+	TTLSeconds     int64      `json:"ttl_seconds"`
+	MaxDocuments   int64      `json:"max_documents"`
+}
+
+// URLStorageStats reúne os tamanhos das coleções de URLs e fingerprints
+type URLStorageStats struct {
+	ProcessedURLs CollectionStorageStats `json:"processed_urls"`
+	Fingerprints  CollectionStorageStats `json:"fingerprints"`
+	LastPrune     *PruneResult           `json:"last_prune,omitempty"`
+}
+
+// ConfigureRetention cria (ou atualiza) os índices TTL e guarda os limites de tamanho
+func (r *MongoURLRepository) ConfigureRetention(ctx context.Context, policy RetentionPolicy) error {
+	r.retentionMutex.Lock()
+	r.retention = policy
+	r.retentionMutex.Unlock()
+
+	if err := ensureTTLIndex(ctx, r.urlCollection, processedURLTTLIndex, "processed_at", policy.URLTTL); err != nil {
+		return err
+	}
+	return ensureTTLIndex(ctx, r.fingerprintCollection, fingerprintTTLIndex, "last_crawled", policy.FingerprintTTL)
+}
+
+// ensureTTLIndex cria o índice TTL; se já existir com outra expiração, atualiza via collMod.
+// TTL zero remove o índice.
+func ensureTTLIndex(ctx context.Context, collection *mongo.Collection, name, field string, ttl time.Duration) error {
+	if ttl <= 0 {
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil && !strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("failed to drop TTL index %s: %v", name, err)
+		}
+		return nil
+	}
+
+	seconds := int32(ttl.Seconds())
+	model := mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetName(name).SetExpireAfterSeconds(seconds),
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, model)
+	if err == nil {
+		return nil
+	}
+
+	// Índice existente com outra expiração: atualiza sem recriar
+	command := bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "index", Value: bson.D{{Key: "name", Value: name}, {Key: "expireAfterSeconds", Value: seconds}}},
+	}
+	if modErr := collection.Database().RunCommand(ctx, command).Err(); modErr != nil {
+		return fmt.Errorf("failed to create TTL index %s: %v (collMod: %v)", name, err, modErr)
+	}
+	return nil
+}
+
+// PruneToLimits remove os registros menos recentemente processados quando as coleções
+// ultrapassam os limites configurados (poda no estilo LRU)
+func (r *MongoURLRepository) PruneToLimits(ctx context.Context) (*PruneResult, error) {
+	policy := r.retentionPolicy()
+	result := &PruneResult{RanAt: time.Now()}
+
+	removed, err := pruneOldest(ctx, r.urlCollection, "processed_at", policy.MaxURLs)
+	result.URLsRemoved = removed
+	if err != nil {
+		return result, fmt.Errorf("failed to prune processed URLs: %v", err)
+	}
+
+	removed, err = pruneOldest(ctx, r.fingerprintCollection, "last_crawled", policy.MaxFingerprints)
+	result.FingerprintsRemoved = removed
+	if err != nil {
+		return result, fmt.Errorf("failed to prune fingerprints: %v", err)
+	}
+
+	r.retentionMutex.Lock()
+	r.lastPrune = result
+	r.retentionMutex.Unlock()
+
+	if result.URLsRemoved > 0 || result.FingerprintsRemoved > 0 {
+		log.Printf("URL repository pruned: removed %d URLs and %d fingerprints",
+			result.URLsRemoved, result.FingerprintsRemoved)
+	}
+
+	return result, nil
+}
+
+// pruneOldest apaga, em lotes, os documentos mais antigos pelo campo informado até o limite
+func pruneOldest(ctx context.Context, collection *mongo.Collection, field string, limit int64) (int64, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	total, err := collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var removed int64
+	for excess := total - limit; excess > 0; excess = total - removed - limit {
+		batch := excess
+		if batch > pruneBatchSize {
+			batch = pruneBatchSize
+		}
+
+		findOptions := options.Find().
+			SetSort(bson.D{{Key: field, Value: 1}}).
+			SetLimit(batch).
+			SetProjection(bson.M{"_id": 1})
+		cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+		if err != nil {
+			return removed, err
+		}
+
+		var docs []struct {
+			ID string `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return removed, err
+		}
+		if len(docs) == 0 {
+			break
+		}
+
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.ID
+		}
+
+		deleted, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return removed, err
+		}
+		removed += deleted.DeletedCount
+		if deleted.DeletedCount == 0 {
+			break
+		}
+	}
+
+	return removed, nil
+}
+
+// StartMaintenance executa a poda por tamanho periodicamente até o contexto ser cancelado
+func (r *MongoURLRepository) StartMaintenance(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := r.PruneToLimits(ctx); err != nil {
+				log.Printf("Warning: URL repository maintenance failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// GetStorageStats retorna documentos, tamanhos e faixa de datas das coleções
+func (r *MongoURLRepository) GetStorageStats(ctx context.Context) (*URLStorageStats, error) {
+	policy := r.retentionPolicy()
+
+	urlStats, err := collectionStorageStats(ctx, r.urlCollection, "processed_at")
+	if err != nil {
+		return nil, err
+	}
+	urlStats.TTLSeconds = int64(policy.URLTTL.Seconds())
+	urlStats.MaxDocuments = policy.MaxURLs
+
+	fingerprintStats, err := collectionStorageStats(ctx, r.fingerprintCollection, "last_crawled")
+	if err != nil {
+		return nil, err
+	}
+	fingerprintStats.TTLSeconds = int64(policy.FingerprintTTL.Seconds())
+	fingerprintStats.MaxDocuments = policy.MaxFingerprints
+
+	r.retentionMutex.RLock()
+	lastPrune := r.lastPrune
+	r.retentionMutex.RUnlock()
+
+	return &URLStorageStats{
+		ProcessedURLs: *urlStats,
+		Fingerprints:  *fingerprintStats,
+		LastPrune:     lastPrune,
+	}, nil
+}
+
+// collectionStorageStats lê collStats e as datas extremas do campo de recência
+func collectionStorageStats(ctx context.Context, collection *mongo.Collection, dateField string) (*CollectionStorageStats, error) {
+	stats := &CollectionStorageStats{Name: collection.Name()}
+
+	var raw bson.M
+	err := collection.Database().RunCommand(ctx, bson.D{{Key: "collStats", Value: collection.Name()}}).Decode(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for %s: %v", collection.Name(), err)
+	}
+	stats.Documents = bsonInt64(raw["count"])
+	stats.DataSizeBytes = bsonInt64(raw["size"])
+	stats.StorageBytes = bsonInt64(raw["storageSize"])
+	stats.IndexSizeBytes = bsonInt64(raw["totalIndexSize"])
+
+	for _, order := range []int{1, -1} {
+		var doc bson.M
+		findOptions := options.FindOne().SetSort(bson.D{{Key: dateField, Value: order}}).SetProjection(bson.M{dateField: 1})
+		if err := collection.FindOne(ctx, bson.M{}, findOptions).Decode(&doc); err != nil {
+			continue
+		}
+		if value, ok := doc[dateField].(interface{ Time() time.Time }); ok {
+			t := value.Time()
+			if order == 1 {
+				stats.Oldest = &t
+			} else {
+				stats.Newest = &t
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// OldestRetained retorna a data do registro mais antigo ainda guardado em cada coleção
+// (processed_at e last_crawled; nil se vazia). Registros anteriores foram removidos pelo TTL ou
+// pela poda por tamanho
+func (r *MongoURLRepository) OldestRetained(ctx context.Context) (urls, fingerprints *time.Time, err error) {
+	if urls, err = oldestDate(ctx, r.urlCollection, "processed_at"); err != nil {
+		return nil, nil, err
+	}
+	if fingerprints, err = oldestDate(ctx, r.fingerprintCollection, "last_crawled"); err != nil {
+		return nil, nil, err
+	}
+	return urls, fingerprints, nil
+}
+
+// oldestDate lê a menor data do campo (pelo índice TTL, quando existe)
+func oldestDate(ctx context.Context, collection *mongo.Collection, field string) (*time.Time, error) {
+	var doc bson.M
+	findOptions := options.FindOne().SetSort(bson.D{{Key: field, Value: 1}}).SetProjection(bson.M{field: 1})
+	if err := collection.FindOne(ctx, bson.M{}, findOptions).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find oldest %s in %s: %v", field, collection.Name(), err)
+	}
+	if value, ok := doc[field].(interface{ Time() time.Time }); ok {
+		t := value.Time()
+		return &t, nil
+	}
+	return nil, nil
+}
+
+// bsonInt64 converte os tipos numéricos retornados pelos comandos do Mongo
+func bsonInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func (r *MongoURLRepository) retentionPolicy() RetentionPolicy {
+	r.retentionMutex.RLock()
+	defer r.retentionMutex.RUnlock()
+	return r.retention
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FinalidadeTemporada marca o aluguel por temporada (diárias), fora das estatísticas de venda e
+// aluguel mensal
+const FinalidadeTemporada = "temporada"
+
+// saleWordings indicam anúncio de venda no texto normalizado; "ótima para temporada" em um
+// anúncio de venda não o torna aluguel por temporada
+var saleWordings = []string{"a venda", "vendo", "vende se", "para venda", "valor de venda", "preco de venda"}
+
+// maxDailyRate é a maior diária plausível: preços acima disso são de venda ou aluguel mensal
+const maxDailyRate = 20000.0
+
+// dailyPriceMarkers indicam que o valor anunciado é por dia ou por noite
+var dailyPriceMarkers = []string{"diaria", "diarias", "dia", "noite", "pernoite"}
+
+var (
+	// dailyValuePatterns capturam o valor da diária ("diária: R$ 350", "R$ 400 por noite")
+	dailyValuePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(?:di[áa]rias?|pernoite)[^0-9]{0,25}?` + moneyPattern),
+		regexp.MustCompile(`(?i)` + moneyPattern + `\s*(?:/\s*(?:dia|noite|di[áa]ria)|por\s+(?:dia|noite|di[áa]ria)|a\s+di[áa]ria|a\s+noite)`),
+	}
+
+	// minimumNightsPatterns capturam a estadia mínima ("mínimo de 3 noites", "2 diárias mínimas")
+	minimumNightsPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)m[ií]nimo\s+(?:de\s+)?(\d{1,2})\s+(?:noites|di[áa]rias|dias)`),
+		regexp.MustCompile(`(?i)(\d{1,2})\s+(?:noites|di[áa]rias)\s+m[ií]nimas?`),
+		regexp.MustCompile(`(?i)estadia\s+m[ií]nima\s*(?:de|:)?\s*(\d{1,2})\b`),
+	}
+)
+
+// IsVacationRental indica se o anúncio é de aluguel por temporada. Termos como "para temporada"
+// ou "check-in" aparecem também em anúncios de venda, então é preciso o preço por diária (no texto
+// do preço ou citado na descrição), sem menção a venda e sem preço na escala de venda.
+func IsVacationRental(property Property) bool {
+	if property.Valor > maxDailyRate {
+		return false
+	}
+	text := " " + utils.NormalizeText(strings.Join([]string{
+		property.TipoImovel, property.ValorTexto, property.Descricao, strings.Join(property.Caracteristicas, " "),
+	}, " ")) + " "
+	for _, wording := range saleWordings {
+		if strings.Contains(text, " "+wording+" ") {
+			return false
+		}
+	}
+	if isDailyPriceText(property.ValorTexto) {
+		return true
+	}
+	return parseDailyValue(property.ValorTexto+" "+property.Descricao) > 0
+}
+
+// isDailyPriceText indica se o texto do preço é por dia ou por noite ("R$ 350/diária")
+func isDailyPriceText(priceText string) bool {
+	normalized := " " + utils.NormalizeText(strings.NewReplacer("/", " ", "-", " ").Replace(priceText)) + " "
+	for _, marker := range dailyPriceMarkers {
+		if strings.Contains(normalized, " "+marker+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDailyRate retorna o valor da diária citado no anúncio; sem menção explícita, o Valor
+// quando o texto do preço é por dia ou noite (0 se não houver)
+func ParseDailyRate(property Property) float64 {
+	if value := parseDailyValue(property.ValorTexto + " " + property.Descricao); value > 0 {
+		return value
+	}
+	if property.Valor > 0 && isDailyPriceText(property.ValorTexto) {
+		return property.Valor
+	}
+	return 0
+}
+
+// parseDailyValue retorna a primeira diária plausível citada no texto (0 se não houver)
+func parseDailyValue(text string) float64 {
+	for _, pattern := range dailyValuePatterns {
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			if value, _ := utils.ParseBrazilianNumber(match[1]); value > 0 && value <= maxDailyRate {
+				return value
+			}
+		}
+	}
+	return 0
+}
+
+// ParseMinimumNights retorna a quantidade mínima de noites citada no texto (0 se não houver)
+func ParseMinimumNights(text string) int {
+	for _, pattern := range minimumNightsPatterns {
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			if nights, err := strconv.Atoi(match[1]); err == nil && nights > 0 && nights <= 60 {
+				return nights
+			}
+		}
+	}
+	return 0
+}
+
+// applyVacationRental marca o aluguel por temporada com a diária e a estadia mínima. O valor da
+// diária não é venda nem aluguel mensal, por isso ValorVenda e ValorAluguel são zerados
+func applyVacationRental(property *Property) {
+	if !IsVacationRental(*property) {
+		property.ValorDiaria = 0
+		property.MinimoNoites = 0
+		return
+	}
+	property.Finalidade = FinalidadeTemporada
+	property.ValorVenda = 0
+	property.ValorAluguel = 0
+	property.ValorDiaria = ParseDailyRate(*property)
+	property.MinimoNoites = ParseMinimumNights(property.ValorTexto + " " + property.Descricao)
+}
+
+// migrateVacationRental (v7) marca o aluguel por temporada nos documentos gravados antes dele.
+// Venda e aluguel só são removidos quando guardavam a própria diária.
+func migrateVacationRental(doc bson.M) error {
+	var property Property
+	if err := convertBSON(doc, &property); err != nil {
+		return err
+	}
+	if !IsVacationRental(property) {
+		return nil
+	}
+	applyVacationRental(&property)
+	doc["finalidade"] = property.Finalidade
+	for _, key := range []string{"valor_venda", "valor_aluguel"} {
+		if value, ok := doc[key].(float64); ok && (value == property.ValorDiaria || value == property.Valor) {
+			delete(doc, key)
+		}
+	}
+	if property.ValorDiaria > 0 {
+		doc["valor_diaria"] = property.ValorDiaria
+	}
+	if property.MinimoNoites > 0 {
+		doc["minimo_noites"] = property.MinimoNoites
+	}
+	return nil
+}
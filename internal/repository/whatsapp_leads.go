@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WhatsAppLeadCollection guarda os trechos de anúncio encontrados nas mensagens pré-preenchidas
+// dos links de WhatsApp (wa.me) publicados como "catálogo via WhatsApp"
+const WhatsAppLeadCollection = "whatsapp_leads"
+
+// MaxWhatsAppLeadConfidence é a confiança máxima de um lead: o texto da mensagem é curto e não
+// passa pela validação dos anúncios, então um lead nunca vale como um imóvel extraído da página
+const MaxWhatsAppLeadConfidence = 0.5
+
+// WhatsAppLead é um imóvel de baixa confiança montado a partir do texto pré-preenchido de um
+// link de WhatsApp (ex: "Olá! Tenho interesse na Casa 3 quartos no Centro - R$ 450.000")
+type WhatsAppLead struct {
+	ID          string    `bson:"_id" json:"id"`
+	Domain      string    `bson:"domain" json:"domain"`
+	PageURL     string    `bson:"page_url" json:"page_url"` // página onde o link foi encontrado
+	Phone       string    `bson:"phone,omitempty" json:"phone,omitempty"`
+	Text        string    `bson:"text" json:"text"`
+	TipoImovel  string    `bson:"tipo_imovel,omitempty" json:"tipo_imovel,omitempty"`
+	Quartos     int       `bson:"quartos,omitempty" json:"quartos,omitempty"`
+	Banheiros   int       `bson:"banheiros,omitempty" json:"banheiros,omitempty"`
+	AreaTotal   float64   `bson:"area_total,omitempty" json:"area_total,omitempty"`
+	Valor       float64   `bson:"valor,omitempty" json:"valor,omitempty"`
+	Bairro      string    `bson:"bairro,omitempty" json:"bairro,omitempty"`
+	Cidade      string    `bson:"cidade,omitempty" json:"cidade,omitempty"`
+	Codigo      string    `bson:"codigo,omitempty" json:"codigo,omitempty"` // código de referência do anúncio
+	Confidence  float64   `bson:"confidence" json:"confidence"`
+	Hits        int       `bson:"hits" json:"hits"`
+	FirstSeenAt time.Time `bson:"first_seen_at" json:"first_seen_at"`
+	LastSeenAt  time.Time `bson:"last_seen_at" json:"last_seen_at"`
+}
+
+// WhatsAppLeadFilter filtra a listagem de leads (campos vazios não filtram)
+type WhatsAppLeadFilter struct {
+	Domain string
+	Cidade string
+	Limit  int
+}
+
+// WhatsAppLeadRepository é implementado por repositórios capazes de guardar os leads dos links
+// de WhatsApp
+type WhatsAppLeadRepository interface {
+	// SaveWhatsAppLeads grava os leads; um lead já conhecido tem a última ocorrência atualizada
+	SaveWhatsAppLeads(ctx context.Context, leads []WhatsAppLead) error
+	// FindWhatsAppLeads retorna os leads vistos mais recentemente primeiro
+	FindWhatsAppLeads(ctx context.Context, filter WhatsAppLeadFilter) ([]WhatsAppLead, error)
+}
+
+// WhatsAppLeadID identifica o lead pelo domínio, telefone e texto da mensagem normalizados: o
+// mesmo link repetido em várias páginas do site é um único lead
+func WhatsAppLeadID(domain, phone, text string) string {
+	data := fmt.Sprintf("whatsapp|%s|%s|%s", NormalizeDomain(domain), phone, strings.ToLower(strings.Join(strings.Fields(text), " ")))
+	hash := sha256.Sum256([]byte(data))
+	return fmt.Sprintf("%x", hash)[:24]
+}
+
+// whatsAppLeads retorna a coleção dos leads de WhatsApp
+func (r *MongoRepository) whatsAppLeads() *mongo.Collection {
+	return r.collection.Database().Collection(WhatsAppLeadCollection)
+}
+
+// SaveWhatsAppLeads grava (ou renova) cada lead e soma a ocorrência
+func (r *MongoRepository) SaveWhatsAppLeads(ctx context.Context, leads []WhatsAppLead) error {
+	if len(leads) == 0 {
+		return nil
+	}
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(leads))
+	for _, lead := range leads {
+		if lead.ID == "" {
+			lead.ID = WhatsAppLeadID(lead.Domain, lead.Phone, lead.Text)
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": lead.ID}).
+			SetUpdate(bson.M{
+				"$inc": bson.M{"hits": 1},
+				"$set": bson.M{
+					"domain":       NormalizeDomain(lead.Domain),
+					"page_url":     lead.PageURL,
+					"phone":        lead.Phone,
+					"text":         lead.Text,
+					"tipo_imovel":  lead.TipoImovel,
+					"quartos":      lead.Quartos,
+					"banheiros":    lead.Banheiros,
+					"area_total":   lead.AreaTotal,
+					"valor":        lead.Valor,
+					"bairro":       lead.Bairro,
+					"cidade":       lead.Cidade,
+					"codigo":       lead.Codigo,
+					"confidence":   lead.Confidence,
+					"last_seen_at": now,
+				},
+				"$setOnInsert": bson.M{"first_seen_at": now},
+			}).
+			SetUpsert(true))
+	}
+	if _, err := r.whatsAppLeads().BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to save whatsapp leads: %v", err)
+	}
+	return nil
+}
+
+// FindWhatsAppLeads retorna os leads do filtro, os vistos mais recentemente primeiro
+func (r *MongoRepository) FindWhatsAppLeads(ctx context.Context, filter WhatsAppLeadFilter) ([]WhatsAppLead, error) {
+	query := bson.M{}
+	if filter.Domain != "" {
+		query["domain"] = NormalizeDomain(filter.Domain)
+	}
+	if filter.Cidade != "" {
+		query["cidade"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Cidade) + "$", "$options": "i"}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.whatsAppLeads().Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find whatsapp leads: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	leads := []WhatsAppLead{}
+	if err := cursor.All(ctx, &leads); err != nil {
+		return nil, fmt.Errorf("failed to decode whatsapp leads: %v", err)
+	}
+	return leads, nil
+}
@@ -0,0 +1,258 @@
+package sdkgen
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// GenerateGo gera os arquivos do cliente Go (modelos e operações); o cliente HTTP base (Client,
+// do e os auxiliares de query) fica no pacote, escrito à mão
+func GenerateGo(spec map[string]interface{}, options Options) (map[string][]byte, error) {
+	a, err := build(spec, options)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	models, err := formatGo(renderGoModels(a, options.GoPackage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go models: %v", err)
+	}
+	files["models_gen.go"] = models
+
+	operations, err := formatGo(renderGoOperations(a, options.GoPackage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go operations: %v", err)
+	}
+	files["operations_gen.go"] = operations
+	return files, nil
+}
+
+// renderGoModels escreve os tipos dos schemas e dos parâmetros das operações
+func renderGoModels(a *api, pkg string) string {
+	var b strings.Builder
+	usesTime := false
+	for _, m := range a.Models {
+		if m.Alias != nil && m.Alias.UsesTime {
+			usesTime = true
+		}
+		for _, f := range m.Fields {
+			usesTime = usesTime || f.Type.UsesTime
+		}
+	}
+	for _, op := range a.Operations {
+		for _, p := range op.Params {
+			usesTime = usesTime || p.Type.UsesTime
+		}
+	}
+
+	fmt.Fprintf(&b, "// %s\n\npackage %s\n\n", GeneratedHeader, pkg)
+	if usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+
+	for _, m := range a.Models {
+		writeGoComment(&b, "", m.Name, m.Description)
+		if m.Alias != nil {
+			fmt.Fprintf(&b, "type %s = %s\n\n", m.Name, m.Alias.Go)
+			continue
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", m.Name)
+		for _, f := range m.Fields {
+			fieldType := f.Type.Go
+			if f.Type.IsStruct {
+				fieldType = "*" + fieldType
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s,omitempty\"`", f.GoName, fieldType, f.JSON)
+			if f.Description != "" {
+				fmt.Fprintf(&b, " // %s", f.Description)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, op := range a.Operations {
+		if op.ParamsType == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "// %s são os parâmetros de query e cabeçalho de %s (valores zero não são enviados)\n", op.ParamsType, op.GoName)
+		fmt.Fprintf(&b, "type %s struct {\n", op.ParamsType)
+		for _, p := range op.Params {
+			fmt.Fprintf(&b, "\t%s %s", p.GoName, p.Type.Go)
+			comment := p.Name
+			if p.In == "header" {
+				comment = "cabeçalho " + p.Name
+			}
+			if p.Description != "" {
+				comment += ": " + p.Description
+			}
+			fmt.Fprintf(&b, " // %s\n", comment)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+// renderGoOperations escreve um método do Client por operação
+func renderGoOperations(a *api, pkg string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\npackage %s\n\n", GeneratedHeader, pkg)
+
+	usesIO, usesURL, usesFmt := false, false, false
+	for _, op := range a.Operations {
+		usesIO = usesIO || op.RawBody
+		usesURL = usesURL || len(op.PathParams) > 0
+		for _, p := range op.PathParams {
+			usesFmt = usesFmt || p.Type.Go != "string"
+		}
+	}
+	b.WriteString("import (\n\t\"context\"\n")
+	if usesFmt {
+		b.WriteString("\t\"fmt\"\n")
+	}
+	if usesIO {
+		b.WriteString("\t\"io\"\n")
+	}
+	b.WriteString("\t\"net/http\"\n")
+	if usesURL {
+		b.WriteString("\t\"net/url\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	for _, op := range a.Operations {
+		writeGoOperation(&b, op)
+	}
+	return b.String()
+}
+
+// writeGoOperation escreve o método da operação
+func writeGoOperation(b *strings.Builder, op operation) {
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	fmt.Fprintf(b, "// %s: %s\n//\n// %s %s\n", op.GoName, summary, op.Method, op.Path)
+
+	args := []string{"ctx context.Context"}
+	for _, p := range op.PathParams {
+		args = append(args, p.GoName+" "+p.Type.Go)
+	}
+	if op.ParamsType != "" {
+		args = append(args, "params *"+op.ParamsType)
+	}
+	switch {
+	case op.Body != nil:
+		bodyType := op.Body.Go
+		if op.Body.IsStruct {
+			bodyType = "*" + bodyType
+		}
+		args = append(args, "body "+bodyType)
+	case op.RawBody:
+		args = append(args, "body io.Reader", "contentType string")
+	}
+
+	result, zero, out := "error", "", "nil"
+	switch {
+	case op.Response != nil && op.Response.IsStruct:
+		result, zero, out = "(*"+op.Response.Go+", error)", "nil", "&result"
+	case op.Response != nil:
+		result, zero, out = "("+op.Response.Go+", error)", "nil", "&result"
+	case op.RawResponse:
+		result, zero, out = "([]byte, error)", "nil", "&result"
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", op.GoName, strings.Join(args, ", "), result)
+
+	fmt.Fprintf(b, "\treq := request{method: http.Method%s, path: %s}\n", methodConstant(op.Method), goPathExpression(op))
+	if op.ParamsType != "" {
+		b.WriteString("\tif params != nil {\n")
+		for _, p := range op.Params {
+			if p.In == "header" {
+				fmt.Fprintf(b, "\t\treq.setHeader(%q, params.%s)\n", p.Name, p.GoName)
+			} else {
+				fmt.Fprintf(b, "\t\treq.addQuery(%q, params.%s)\n", p.Name, p.GoName)
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+	switch {
+	case op.Body != nil && op.Body.IsStruct:
+		b.WriteString("\tif body != nil {\n\t\treq.body = body\n\t}\n")
+	case op.Body != nil:
+		b.WriteString("\treq.body = body\n")
+	case op.RawBody:
+		b.WriteString("\treq.rawBody, req.contentType = body, contentType\n")
+	}
+
+	switch {
+	case op.Response != nil:
+		fmt.Fprintf(b, "\tvar result %s\n", op.Response.Go)
+	case op.RawResponse:
+		b.WriteString("\tvar result []byte\n")
+	}
+	if zero == "" {
+		b.WriteString("\treturn c.do(ctx, req, nil)\n}\n\n")
+		return
+	}
+	fmt.Fprintf(b, "\tif err := c.do(ctx, req, %s); err != nil {\n\t\treturn %s, err\n\t}\n", out, zero)
+	if op.Response != nil && op.Response.IsStruct {
+		b.WriteString("\treturn &result, nil\n}\n\n")
+	} else {
+		b.WriteString("\treturn result, nil\n}\n\n")
+	}
+}
+
+// goPathExpression monta o caminho com os parâmetros escapados ("/cities/" + url.PathEscape(city))
+func goPathExpression(op operation) string {
+	var parts []string
+	literal := ""
+	params := make(map[string]param, len(op.PathParams))
+	for _, p := range op.PathParams {
+		params[p.Name] = p
+	}
+	for i, segment := range strings.Split(op.Path, "/") {
+		if i > 0 {
+			literal += "/"
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			p := params[segment[1:len(segment)-1]]
+			parts = append(parts, fmt.Sprintf("%q", literal))
+			value := p.GoName
+			if p.Type.Go != "string" {
+				value = "fmt.Sprint(" + value + ")"
+			}
+			parts = append(parts, "url.PathEscape("+value+")")
+			literal = ""
+			continue
+		}
+		literal += segment
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// methodConstant retorna o sufixo da constante http.Method* do método
+func methodConstant(method string) string {
+	return strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+}
+
+// writeGoComment escreve o comentário de documentação do tipo
+func writeGoComment(b *strings.Builder, indent, name, description string) {
+	if description == "" {
+		fmt.Fprintf(b, "%s// %s é gerado da especificação OpenAPI\n", indent, name)
+		return
+	}
+	fmt.Fprintf(b, "%s// %s: %s\n", indent, name, description)
+}
+
+// formatGo formata o código gerado
+func formatGo(source string) ([]byte, error) {
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("%v\n%s", err, source)
+	}
+	return formatted, nil
+}
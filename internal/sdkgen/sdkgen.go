@@ -0,0 +1,522 @@
+// Package sdkgen gera os clientes da API (Go e TypeScript) a partir da especificação OpenAPI, com
+// os modelos tipados dos schemas e um método por operação.
+package sdkgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GeneratedHeader marca os arquivos gerados (reconhecido pelo go vet e pelos linters)
+const GeneratedHeader = "Code generated by sdkgen from the OpenAPI specification. DO NOT EDIT."
+
+// Options configura a geração dos clientes
+type Options struct {
+	GoPackage string   // pacote dos arquivos Go gerados
+	SkipTags  []string // operações dessas tags não entram nos clientes (ex.: interface web)
+}
+
+// DefaultOptions retorna as opções usadas nos clientes publicados
+func DefaultOptions() Options {
+	return Options{GoPackage: "client", SkipTags: []string{"Web Interface"}}
+}
+
+// typeRef é um tipo resolvido de um schema, nas duas linguagens
+type typeRef struct {
+	Go       string
+	TS       string
+	IsStruct bool // tipo nomeado com campos (retornado/referenciado por ponteiro no Go)
+	UsesTime bool
+}
+
+// field é um campo de um modelo
+type field struct {
+	GoName      string
+	JSON        string
+	Type        typeRef
+	Description string
+}
+
+// model é um tipo nomeado: struct com campos ou apelido de outro tipo
+type model struct {
+	Name        string
+	Description string
+	Fields      []field
+	Alias       *typeRef // tipos sem campos (ex.: mapas livres)
+}
+
+// param é um parâmetro de caminho, query ou cabeçalho
+type param struct {
+	GoName      string
+	Name        string
+	In          string
+	Type        typeRef
+	Description string
+}
+
+// operation é um endpoint da API
+type operation struct {
+	GoName      string
+	TSName      string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	PathParams  []param
+	Params      []param // query e cabeçalho, agrupados no tipo <Operação>Params
+	ParamsType  string
+	Body        *typeRef // corpo JSON
+	RawBody     bool     // corpo em outro formato (CSV, NDJSON...), enviado como está
+	BodyTypes   []string // content types aceitos no corpo bruto
+	Response    *typeRef // resposta JSON
+	RawResponse bool     // resposta em outro formato (imagem, GeoJSON...), retornada em bytes
+}
+
+// api é a representação intermediária usada pelos geradores de cada linguagem
+type api struct {
+	Title      string
+	Version    string
+	Models     []*model
+	Operations []operation
+}
+
+// builder resolve os schemas da especificação em modelos nomeados
+type builder struct {
+	spec   map[string]interface{}
+	models map[string]*model
+	order  []string
+	names  map[string]bool
+}
+
+// build monta a representação intermediária da especificação
+func build(spec map[string]interface{}, options Options) (*api, error) {
+	b := &builder{
+		spec:   spec,
+		models: make(map[string]*model),
+		names:  make(map[string]bool),
+	}
+
+	info, _ := spec["info"].(map[string]interface{})
+	result := &api{Title: stringOf(info["title"]), Version: stringOf(info["version"])}
+
+	schemas := mapAt(spec, "components", "schemas")
+	componentNames := sortedKeys(schemas)
+	// Os nomes dos componentes são reservados antes dos tipos gerados para as operações
+	for _, name := range componentNames {
+		b.names[exportedName(name)] = true
+	}
+	for _, name := range componentNames {
+		schema, _ := schemas[name].(map[string]interface{})
+		b.define(exportedName(name), schema)
+	}
+
+	skip := make(map[string]bool, len(options.SkipTags))
+	for _, tag := range options.SkipTags {
+		skip[tag] = true
+	}
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	usedNames := make(map[string]bool)
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+			raw, ok := item[method].(map[string]interface{})
+			if !ok || hasTag(raw, skip) {
+				continue
+			}
+			op, err := b.operation(method, path, raw, usedNames)
+			if err != nil {
+				return nil, err
+			}
+			result.Operations = append(result.Operations, op)
+		}
+	}
+
+	for _, name := range b.order {
+		result.Models = append(result.Models, b.models[name])
+	}
+	return result, nil
+}
+
+// operation converte uma operação da especificação
+func (b *builder) operation(method, path string, raw map[string]interface{}, usedNames map[string]bool) (operation, error) {
+	id := stringOf(raw["operationId"])
+	if id == "" {
+		id = method + " " + path
+	}
+	goName := uniqueName(exportedName(id), usedNames)
+	op := operation{
+		GoName:      goName,
+		TSName:      lowerFirst(goName),
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Summary:     oneLine(stringOf(raw["summary"])),
+		Description: strings.TrimSpace(stringOf(raw["description"])),
+	}
+
+	params, _ := raw["parameters"].([]interface{})
+	pathParams := make(map[string]param)
+	paramNames := make(map[string]bool)
+	for _, entry := range params {
+		p, _ := b.resolve(entry).(map[string]interface{})
+		if p == nil {
+			continue
+		}
+		schema, _ := p["schema"].(map[string]interface{})
+		name := stringOf(p["name"])
+		converted := param{
+			Name:        name,
+			In:          stringOf(p["in"]),
+			Type:        b.typeOf(schema, goName+exportedName(name)),
+			Description: oneLine(stringOf(p["description"])),
+		}
+		switch converted.In {
+		case "path":
+			converted.GoName = argumentName(name)
+			pathParams[name] = converted
+		case "query", "header":
+			converted.GoName = uniqueName(exportedName(name), paramNames)
+			op.Params = append(op.Params, converted)
+		}
+	}
+	// Parâmetros de caminho na ordem em que aparecem no caminho
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := segment[1 : len(segment)-1]
+			p, ok := pathParams[name]
+			if !ok {
+				p = param{GoName: argumentName(name), Name: name, In: "path", Type: typeRef{Go: "string", TS: "string"}}
+			}
+			op.PathParams = append(op.PathParams, p)
+		}
+	}
+	if len(op.Params) > 0 {
+		op.ParamsType = b.reserve(goName + "Params")
+	}
+
+	if body, ok := b.resolve(raw["requestBody"]).(map[string]interface{}); ok {
+		content, _ := body["content"].(map[string]interface{})
+		if media, ok := content["application/json"].(map[string]interface{}); ok {
+			schema, _ := media["schema"].(map[string]interface{})
+			ref := b.typeOf(schema, goName+"Request")
+			op.Body = &ref
+		} else if len(content) > 0 {
+			op.RawBody = true
+			op.BodyTypes = sortedKeys(content)
+		}
+	}
+
+	responses, _ := raw["responses"].(map[string]interface{})
+	for _, code := range []string{"200", "201", "202"} {
+		response, ok := b.resolve(responses[code]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		content, _ := response["content"].(map[string]interface{})
+		if media, ok := content["application/json"].(map[string]interface{}); ok {
+			schema, _ := media["schema"].(map[string]interface{})
+			ref := b.typeOf(schema, goName+"Response")
+			op.Response = &ref
+		} else if len(content) > 0 {
+			op.RawResponse = true
+		}
+		break
+	}
+	return op, nil
+}
+
+// typeOf resolve o tipo do schema; objetos com campos viram modelos nomeados por hint
+func (b *builder) typeOf(schema map[string]interface{}, hint string) typeRef {
+	if schema == nil {
+		return typeRef{Go: "interface{}", TS: "unknown"}
+	}
+	if ref := stringOf(schema["$ref"]); ref != "" {
+		name := ref[strings.LastIndex(ref, "/")+1:]
+		goName := exportedName(name)
+		target, _ := b.resolve(schema).(map[string]interface{})
+		if properties, _ := target["properties"].(map[string]interface{}); len(properties) == 0 {
+			return typeRef{Go: goName, TS: goName}
+		}
+		return typeRef{Go: goName, TS: goName, IsStruct: true}
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if variants, ok := schema[key].([]interface{}); ok && len(variants) == 1 {
+			variant, _ := variants[0].(map[string]interface{})
+			return b.typeOf(variant, hint)
+		}
+	}
+
+	switch stringOf(schema["type"]) {
+	case "string":
+		switch stringOf(schema["format"]) {
+		case "date-time":
+			return typeRef{Go: "time.Time", TS: "string", UsesTime: true}
+		}
+		return typeRef{Go: "string", TS: "string"}
+	case "integer":
+		if stringOf(schema["format"]) == "int64" {
+			return typeRef{Go: "int64", TS: "number"}
+		}
+		return typeRef{Go: "int", TS: "number"}
+	case "number":
+		return typeRef{Go: "float64", TS: "number"}
+	case "boolean":
+		return typeRef{Go: "bool", TS: "boolean"}
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		elem := b.typeOf(items, singular(hint))
+		return typeRef{Go: "[]" + elem.Go, TS: arrayTS(elem.TS), UsesTime: elem.UsesTime}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+		name := b.reserve(hint)
+		b.define(name, schema)
+		return typeRef{Go: name, TS: name, IsStruct: true}
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		elem := b.typeOf(additional, hint+"Value")
+		return typeRef{Go: "map[string]" + elem.Go, TS: "Record<string, " + elem.TS + ">", UsesTime: elem.UsesTime}
+	}
+	if stringOf(schema["type"]) == "object" {
+		return typeRef{Go: "map[string]interface{}", TS: "Record<string, unknown>"}
+	}
+	return typeRef{Go: "interface{}", TS: "unknown"}
+}
+
+// define registra o modelo name com os campos do schema (ou como apelido, sem campos)
+func (b *builder) define(name string, schema map[string]interface{}) {
+	m := &model{Name: name, Description: oneLine(stringOf(schema["description"]))}
+	b.models[name] = m
+	b.order = append(b.order, name)
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		alias := b.typeOf(withoutDescription(schema), name+"Value")
+		m.Alias = &alias
+		return
+	}
+
+	fieldNames := make(map[string]bool)
+	for _, jsonName := range sortedKeys(properties) {
+		property, _ := properties[jsonName].(map[string]interface{})
+		m.Fields = append(m.Fields, field{
+			GoName:      uniqueName(exportedName(jsonName), fieldNames),
+			JSON:        jsonName,
+			Type:        b.typeOf(property, name+exportedName(jsonName)),
+			Description: oneLine(stringOf(property["description"])),
+		})
+	}
+}
+
+// reserve retorna um nome de tipo ainda livre a partir de hint
+func (b *builder) reserve(hint string) string {
+	return uniqueName(hint, b.names)
+}
+
+// resolve segue um $ref local (#/components/...) até o objeto referenciado
+func (b *builder) resolve(value interface{}) interface{} {
+	for i := 0; i < 10; i++ {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		ref := stringOf(entry["$ref"])
+		if !strings.HasPrefix(ref, "#/") {
+			return value
+		}
+		var current interface{} = b.spec
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			node, _ := current.(map[string]interface{})
+			current = node[part]
+		}
+		value = current
+	}
+	return value
+}
+
+// hasTag informa se a operação tem alguma das tags
+func hasTag(operation map[string]interface{}, tags map[string]bool) bool {
+	list, _ := operation["tags"].([]interface{})
+	for _, tag := range list {
+		if tags[stringOf(tag)] {
+			return true
+		}
+	}
+	return false
+}
+
+// mapAt percorre mapas aninhados pelas chaves
+func mapAt(root map[string]interface{}, keys ...string) map[string]interface{} {
+	current := root
+	for _, key := range keys {
+		next, _ := current[key].(map[string]interface{})
+		if next == nil {
+			return map[string]interface{}{}
+		}
+		current = next
+	}
+	return current
+}
+
+// withoutDescription copia o schema sem a descrição (usado nos apelidos)
+func withoutDescription(schema map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		if key != "description" {
+			copied[key] = value
+		}
+	}
+	return copied
+}
+
+// initialisms são mantidas em maiúsculas nos nomes Go (property_id -> PropertyID)
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "API": true, "AI": true, "HTTP": true, "HTTPS": true,
+	"SLA": true, "DNS": true, "TLS": true, "IP": true, "JSON": true, "CSV": true, "HTML": true,
+	"TCP": true, "USD": true, "UTC": true, "CEP": true,
+}
+
+// exportedName converte um nome JSON/operação em identificador Go exportado
+// (last_refresh_at -> LastRefreshAt, job_id -> JobID, getAllCities -> GetAllCities)
+func exportedName(name string) string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		w := []rune(strings.ToLower(word))
+		w[0] = unicode.ToUpper(w[0])
+		b.WriteString(string(w))
+	}
+	result := b.String()
+	if result == "" {
+		return "Value"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "N" + result
+	}
+	return result
+}
+
+// lowerFirst deixa o identificador com a primeira palavra minúscula (JobID -> jobID, URL -> url)
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	switch {
+	case i == 0:
+		return name
+	case i == len(runes):
+		return strings.ToLower(name)
+	case i > 1:
+		i-- // a última maiúscula começa a próxima palavra (URLPath -> urlPath)
+	}
+	return strings.ToLower(string(runes[:i])) + string(runes[i:])
+}
+
+// reservedArguments são palavras reservadas do Go e do TypeScript e os nomes usados pelos métodos
+// gerados, que não podem nomear os parâmetros de caminho
+var reservedArguments = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true,
+	"var": true, "class": true, "delete": true, "new": true, "this": true, "function": true,
+	"in": true, "enum": true, "export": true, "extends": true, "typeof": true, "void": true,
+	"with": true, "ctx": true, "params": true, "body": true, "contentType": true, "c": true,
+	"path": true, "query": true, "header": true, "out": true, "options": true, "url": true,
+	"fmt": true, "http": true, "io": true, "req": true, "result": true, "err": true,
+}
+
+// argumentName nomeia o argumento de um parâmetro de caminho (job_id -> jobID)
+func argumentName(name string) string {
+	argument := lowerFirst(exportedName(name))
+	if reservedArguments[argument] {
+		argument += "Param"
+	}
+	return argument
+}
+
+// uniqueName retorna name, ou name com um número, sem repetir os nomes já usados
+func uniqueName(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// singular remove o plural simples do nome usado nos itens das listas
+func singular(name string) string {
+	if strings.HasSuffix(name, "ies") {
+		return strings.TrimSuffix(name, "ies") + "y"
+	}
+	if strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") {
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
+
+// arrayTS escreve o tipo de lista em TypeScript
+func arrayTS(elem string) string {
+	if strings.ContainsAny(elem, " <|") {
+		return "Array<" + elem + ">"
+	}
+	return elem + "[]"
+}
+
+// oneLine junta as linhas de uma descrição
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// stringOf converte o valor do documento em texto (vazio para ausente)
+func stringOf(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// sortedKeys retorna as chaves do mapa em ordem
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+package sdkgen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/dujoseaugusto/go-crawler-project/api/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /properties:
+    get:
+      operationId: getProperties
+      summary: Listar imóveis
+      parameters:
+        - name: cidade
+          in: query
+          schema:
+            type: string
+        - name: updated_since
+          in: query
+          schema:
+            type: string
+            format: date-time
+      responses:
+        200:
+          description: Imóveis
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  items:
+                    type: array
+                    items:
+                      $ref: '#/components/schemas/Property'
+                  total:
+                    type: integer
+                    format: int64
+  /cities/{city}/sites/{url}:
+    delete:
+      operationId: removeSiteFromCity
+      parameters:
+        - name: city
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: url
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        200:
+          description: Removido
+  /properties/import:
+    post:
+      operationId: importProperties
+      requestBody:
+        content:
+          text/csv:
+            schema:
+              type: string
+      responses:
+        200:
+          description: Importados
+  /docs:
+    get:
+      operationId: getDocs
+      tags: [Web Interface]
+      responses:
+        200:
+          description: Página
+components:
+  schemas:
+    Property:
+      description: Imóvel coletado
+      properties:
+        id:
+          type: string
+        valor:
+          type: number
+          description: Preço em reais
+        created_at:
+          type: string
+          format: date-time
+`
+
+func loadTestSpec(t *testing.T) map[string]interface{} {
+	spec, err := openapi.ParseSpec([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("failed to parse test spec: %v", err)
+	}
+	return spec
+}
+
+func TestGenerateGo(t *testing.T) {
+	files, err := GenerateGo(loadTestSpec(t), DefaultOptions())
+	assert.NoError(t, err)
+
+	// gofmt alinha os campos; compara sem os espaços repetidos
+	models := strings.Join(strings.Fields(string(files["models_gen.go"])), " ")
+	operations := string(files["operations_gen.go"])
+	for name, source := range files {
+		_, err := parser.ParseFile(token.NewFileSet(), name, source, 0)
+		assert.NoError(t, err, name)
+		assert.True(t, strings.HasPrefix(string(source), "// "+GeneratedHeader), name)
+	}
+
+	// Modelos tipados, com os parâmetros de query e a resposta inline
+	assert.Contains(t, models, "type Property struct")
+	assert.Contains(t, models, "Valor float64 `json:\"valor,omitempty\"` // Preço em reais")
+	assert.Contains(t, models, "CreatedAt time.Time `json:\"created_at,omitempty\"`")
+	assert.Contains(t, models, "Items []Property `json:\"items,omitempty\"`")
+	assert.Contains(t, models, "Total int64 `json:\"total,omitempty\"`")
+	assert.Contains(t, models, "UpdatedSince time.Time // updated_since")
+
+	// Operações: parâmetros de caminho escapados, sem sombrear os pacotes importados
+	assert.Contains(t, operations, "func (c *Client) GetProperties(ctx context.Context, params *GetPropertiesParams) (*GetPropertiesResponse, error)")
+	assert.Contains(t, operations, "func (c *Client) RemoveSiteFromCity(ctx context.Context, city string, urlParam string) error")
+	assert.Contains(t, operations, `"/cities/" + url.PathEscape(city) + "/sites/" + url.PathEscape(urlParam)`)
+	assert.Contains(t, operations, "func (c *Client) ImportProperties(ctx context.Context, body io.Reader, contentType string) error")
+
+	// Tags ignoradas ficam fora do cliente
+	assert.NotContains(t, operations, "GetDocs")
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	files, err := GenerateTypeScript(loadTestSpec(t), DefaultOptions())
+	assert.NoError(t, err)
+
+	models := string(files["src/models.ts"])
+	client := string(files["src/client.ts"])
+
+	assert.Contains(t, models, "export interface Property {")
+	assert.Contains(t, models, "  /** Preço em reais */\n  valor?: number;")
+	assert.Contains(t, models, "items?: Property[];")
+	assert.Contains(t, models, "export interface GetPropertiesParams {")
+
+	assert.Contains(t, client, "async getProperties(params: models.GetPropertiesParams = {}): Promise<models.GetPropertiesResponse>")
+	assert.Contains(t, client, "query: { cidade: params[\"cidade\"], updated_since: params[\"updated_since\"] }")
+	assert.Contains(t, client, "path: `/cities/${encodeURIComponent(String(city))}/sites/${encodeURIComponent(String(urlParam))}`")
+	assert.Contains(t, client, "async importProperties(body: BodyInit, contentType: string): Promise<void>")
+	assert.NotContains(t, client, "getDocs")
+}
+
+func TestExportedName(t *testing.T) {
+	assert.Equal(t, "JobID", exportedName("job_id"))
+	assert.Equal(t, "SourceURL", exportedName("source_url"))
+	assert.Equal(t, "SetCityRefreshSLA", exportedName("setCityRefreshSla"))
+	assert.Equal(t, "urlParam", argumentName("url"))
+	assert.Equal(t, "city", argumentName("city"))
+}
@@ -0,0 +1,190 @@
+package sdkgen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GenerateTypeScript gera os arquivos do cliente TypeScript (src/models.ts e src/client.ts); o
+// cliente HTTP base (src/runtime.ts) fica no pacote, escrito à mão
+func GenerateTypeScript(spec map[string]interface{}, options Options) (map[string][]byte, error) {
+	a, err := build(spec, options)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"src/models.ts": []byte(renderTSModels(a)),
+		"src/client.ts": []byte(renderTSClient(a)),
+	}, nil
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsProperty escreve o nome da propriedade, entre aspas quando não é um identificador
+func tsProperty(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// renderTSModels escreve as interfaces dos schemas e dos parâmetros das operações
+func renderTSModels(a *api) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", GeneratedHeader)
+
+	for _, m := range a.Models {
+		writeTSComment(&b, "", m.Description)
+		if m.Alias != nil {
+			fmt.Fprintf(&b, "export type %s = %s;\n\n", m.Name, m.Alias.TS)
+			continue
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", m.Name)
+		for _, f := range m.Fields {
+			writeTSComment(&b, "  ", f.Description)
+			fmt.Fprintf(&b, "  %s?: %s;\n", tsProperty(f.JSON), f.Type.TS)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, op := range a.Operations {
+		if op.ParamsType == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "/** Parâmetros de query e cabeçalho de %s */\n", op.TSName)
+		fmt.Fprintf(&b, "export interface %s {\n", op.ParamsType)
+		for _, p := range op.Params {
+			writeTSComment(&b, "  ", p.Description)
+			fmt.Fprintf(&b, "  %s?: %s;\n", tsProperty(p.Name), p.Type.TS)
+		}
+		b.WriteString("}\n\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// renderTSClient escreve a classe do cliente com um método por operação
+func renderTSClient(a *api) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", GeneratedHeader)
+	b.WriteString("import { BaseClient } from './runtime.js';\n")
+	b.WriteString("import type * as models from './models.js';\n\n")
+
+	title := a.Title
+	if title == "" {
+		title = "API"
+	}
+	fmt.Fprintf(&b, "/** Cliente da %s %s */\n", title, a.Version)
+	b.WriteString("export class GoCrawlerClient extends BaseClient {\n")
+	for i, op := range a.Operations {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeTSOperation(&b, op)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeTSOperation escreve o método da operação
+func writeTSOperation(b *strings.Builder, op operation) {
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	fmt.Fprintf(b, "  /** %s (%s %s) */\n", escapeTSComment(summary), op.Method, op.Path)
+
+	var args []string
+	for _, p := range op.PathParams {
+		args = append(args, p.GoName+": "+qualifiedTS(p.Type))
+	}
+	switch {
+	case op.Body != nil:
+		args = append(args, "body: "+qualifiedTS(*op.Body))
+	case op.RawBody:
+		args = append(args, "body: BodyInit", "contentType: string")
+	}
+	if op.ParamsType != "" {
+		args = append(args, "params: models."+op.ParamsType+" = {}")
+	}
+
+	result, responseType := "void", "none"
+	switch {
+	case op.Response != nil:
+		result, responseType = qualifiedTS(*op.Response), "json"
+	case op.RawResponse:
+		result, responseType = "Blob", "blob"
+	}
+
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", op.TSName, strings.Join(args, ", "), result)
+	fmt.Fprintf(b, "    return this.request<%s>({\n", result)
+	fmt.Fprintf(b, "      method: '%s',\n", op.Method)
+	fmt.Fprintf(b, "      path: %s,\n", tsPathExpression(op))
+
+	var query, headers []string
+	for _, p := range op.Params {
+		entry := fmt.Sprintf("%s: params[%q]", tsProperty(p.Name), p.Name)
+		if p.In == "header" {
+			headers = append(headers, entry)
+		} else {
+			query = append(query, entry)
+		}
+	}
+	if len(query) > 0 {
+		fmt.Fprintf(b, "      query: { %s },\n", strings.Join(query, ", "))
+	}
+	if len(headers) > 0 {
+		fmt.Fprintf(b, "      headers: { %s },\n", strings.Join(headers, ", "))
+	}
+	switch {
+	case op.Body != nil:
+		b.WriteString("      body,\n")
+	case op.RawBody:
+		b.WriteString("      rawBody: body,\n      contentType,\n")
+	}
+	fmt.Fprintf(b, "      responseType: '%s',\n", responseType)
+	b.WriteString("    });\n  }\n")
+}
+
+// qualifiedTS prefixa os tipos nomeados com o namespace dos modelos
+// (Property[] -> models.Property[], Record<string, City> -> Record<string, models.City>)
+func qualifiedTS(ref typeRef) string {
+	return tsTypeName.ReplaceAllStringFunc(ref.TS, func(name string) string {
+		switch name {
+		case "string", "number", "boolean", "unknown", "Record", "Array":
+			return name
+		}
+		return "models." + name
+	})
+}
+
+var tsTypeName = regexp.MustCompile(`\b[A-Za-z_][A-Za-z0-9_]*\b`)
+
+// tsPathExpression monta o caminho com os parâmetros escapados (`/cities/${encodeURIComponent(city)}`)
+func tsPathExpression(op operation) string {
+	params := make(map[string]param, len(op.PathParams))
+	for _, p := range op.PathParams {
+		params[p.Name] = p
+	}
+	segments := strings.Split(op.Path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			p := params[segment[1:len(segment)-1]]
+			segments[i] = "${encodeURIComponent(String(" + p.GoName + "))}"
+		}
+	}
+	return "`" + strings.Join(segments, "/") + "`"
+}
+
+// writeTSComment escreve o comentário JSDoc (nada sem descrição)
+func writeTSComment(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s/** %s */\n", indent, escapeTSComment(description))
+}
+
+// escapeTSComment impede que a descrição feche o comentário
+func escapeTSComment(text string) string {
+	return strings.ReplaceAll(text, "*/", "*\\/")
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// CitySitesExportVersion é a versão do formato do arquivo de cidades e sites
+const CitySitesExportVersion = 1
+
+// ErrInvalidCitySitesFile indica um arquivo de cidades e sites ilegível ou inconsistente
+var ErrInvalidCitySitesFile = errors.New("invalid city sites file")
+
+// CitySitesExport é o arquivo com todas as cidades, seus sites e as configurações por domínio,
+// usado para promover as listas curadas entre ambientes (ex: staging -> produção). Estatísticas
+// de crawling não são exportadas
+type CitySitesExport struct {
+	Version    int                       `json:"version"`
+	ExportedAt time.Time                 `json:"exported_at"`
+	Cities     []CityExport              `json:"cities"`
+	Domains    []repository.DomainConfig `json:"domains,omitempty"`
+}
+
+// CityExport é uma cidade do arquivo exportado
+type CityExport struct {
+	City            string       `json:"city"`
+	State           string       `json:"state"`
+	Region          string       `json:"region,omitempty"`
+	Status          string       `json:"status,omitempty"`
+	RefreshSLAHours int          `json:"refresh_sla_hours,omitempty"`
+	Sites           []SiteExport `json:"sites"`
+}
+
+// SiteExport é um site de uma cidade do arquivo exportado
+type SiteExport struct {
+	URL             string   `json:"url"`
+	Name            string   `json:"name,omitempty"`
+	Domain          string   `json:"domain,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	DiscoveryMethod string   `json:"discovery_method,omitempty"`
+	Source          string   `json:"source,omitempty"`
+	ReferenceURLs   []string `json:"reference_urls,omitempty"`
+}
+
+// CitySitesImportOptions define como o arquivo é aplicado
+type CitySitesImportOptions struct {
+	Replace bool // substitui os sites das cidades do arquivo em vez de juntar
+	DryRun  bool // apenas calcula o resultado, sem gravar
+}
+
+// CitySitesImportResult resume a importação do arquivo de cidades e sites
+type CitySitesImportResult struct {
+	Cities        int  `json:"cities"`
+	CitiesCreated int  `json:"cities_created"`
+	SitesAdded    int  `json:"sites_added"`
+	SitesUpdated  int  `json:"sites_updated"`
+	SitesRemoved  int  `json:"sites_removed"` // apenas com replace
+	Domains       int  `json:"domains"`
+	Replace       bool `json:"replace"`
+	DryRun        bool `json:"dry_run"`
+}
+
+// SetDomainConfigService inclui as configurações por domínio na exportação e na importação
+func (s *CitySitesService) SetDomainConfigService(domainConfigs *DomainConfigService) {
+	s.domainConfigs = domainConfigs
+}
+
+// ExportCitySites monta o arquivo com todas as cidades, seus sites e as configurações por domínio
+func (s *CitySitesService) ExportCitySites(ctx context.Context) (*CitySitesExport, error) {
+	cities, err := s.repository.FindAllCities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cities: %v", err)
+	}
+
+	export := &CitySitesExport{
+		Version:    CitySitesExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Cities:     make([]CityExport, 0, len(cities)),
+	}
+	for _, city := range cities {
+		cityExport := CityExport{
+			City:            city.City,
+			State:           city.State,
+			Region:          city.Region,
+			Status:          city.Status,
+			RefreshSLAHours: city.RefreshSLAHours,
+			Sites:           make([]SiteExport, 0, len(city.Sites)),
+		}
+		for _, site := range city.Sites {
+			cityExport.Sites = append(cityExport.Sites, SiteExport{
+				URL:             site.URL,
+				Name:            site.Name,
+				Domain:          site.Domain,
+				Status:          site.Status,
+				DiscoveryMethod: site.DiscoveryMethod,
+				Source:          site.Source,
+				ReferenceURLs:   site.ReferenceURLs,
+			})
+		}
+		export.Cities = append(export.Cities, cityExport)
+	}
+
+	if s.domainConfigs != nil {
+		configs, err := s.domainConfigs.GetAllDomainConfigs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range configs {
+			// Caminhos aprendidos pertencem ao crawler de cada ambiente
+			config.ID = ""
+			config.LearnedSkipPaths = nil
+			export.Domains = append(export.Domains, config)
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cities":  len(export.Cities),
+		"domains": len(export.Domains),
+	}).Info("City sites exported")
+
+	return export, nil
+}
+
+// ImportCitySites aplica o arquivo exportado por ExportCitySites. Todo o arquivo é validado antes
+// de gravar; sites já cadastrados (mesma URL ou domínio na cidade) recebem o nome, o status e as
+// URLs de referência do arquivo e mantêm as estatísticas
+func (s *CitySitesService) ImportCitySites(ctx context.Context, export CitySitesExport, opts CitySitesImportOptions) (*CitySitesImportResult, error) {
+	if err := s.validateCitySitesExport(&export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCitySitesFile, err)
+	}
+
+	result := &CitySitesImportResult{
+		Cities:  len(export.Cities),
+		Domains: len(export.Domains),
+		Replace: opts.Replace,
+		DryRun:  opts.DryRun,
+	}
+	for _, cityExport := range export.Cities {
+		cityData, err := s.repository.FindByCity(ctx, cityExport.City, cityExport.State)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find city: %v", err)
+		}
+		if cityData == nil {
+			cityData = &repository.CitySites{
+				City:          cityExport.City,
+				State:         cityExport.State,
+				Status:        "active",
+				LastDiscovery: time.Now(),
+			}
+			result.CitiesCreated++
+		}
+		if cityExport.Region != "" {
+			cityData.Region = cityExport.Region
+		}
+		if cityExport.Status != "" {
+			cityData.Status = cityExport.Status
+		}
+		cityData.RefreshSLAHours = cityExport.RefreshSLAHours
+
+		previous := cityData.Sites
+		if opts.Replace {
+			cityData.Sites = nil
+		}
+		for _, siteExport := range cityExport.Sites {
+			site, known := importedSite(siteExport, previous)
+			if existing := findImportedSite(cityData.Sites, site); existing != nil {
+				*existing = site
+			} else {
+				cityData.Sites = append(cityData.Sites, site)
+			}
+			if known {
+				result.SitesUpdated++
+			} else {
+				result.SitesAdded++
+			}
+		}
+		if opts.Replace {
+			for _, site := range previous {
+				if findImportedSite(cityData.Sites, site) == nil {
+					result.SitesRemoved++
+				}
+			}
+		}
+		cityData.UpdateStats()
+
+		if opts.DryRun {
+			continue
+		}
+		if err := s.repository.SaveCitySites(ctx, *cityData); err != nil {
+			s.logger.WithError(err).Error("Failed to save imported city sites", err)
+			return nil, fmt.Errorf("failed to save city: %v", err)
+		}
+	}
+
+	if !opts.DryRun {
+		for _, config := range export.Domains {
+			if _, err := s.domainConfigs.SaveDomainConfig(ctx, config.Domain, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"cities":         result.Cities,
+		"cities_created": result.CitiesCreated,
+		"sites_added":    result.SitesAdded,
+		"sites_updated":  result.SitesUpdated,
+		"sites_removed":  result.SitesRemoved,
+		"domains":        result.Domains,
+		"replace":        opts.Replace,
+		"dry_run":        opts.DryRun,
+	}).Info("City sites imported")
+
+	return result, nil
+}
+
+// validateCitySitesExport verifica a versão, as cidades, os sites e as configurações por domínio
+// do arquivo, normalizando UF e domínios
+func (s *CitySitesService) validateCitySitesExport(export *CitySitesExport) error {
+	if export.Version != CitySitesExportVersion {
+		return fmt.Errorf("unsupported city sites export version %d (expected %d)", export.Version, CitySitesExportVersion)
+	}
+
+	seen := make(map[string]bool, len(export.Cities))
+	for i := range export.Cities {
+		city := &export.Cities[i]
+		city.City = strings.TrimSpace(city.City)
+		city.State = strings.ToUpper(strings.TrimSpace(city.State))
+		if city.City == "" || len(city.State) != 2 {
+			return fmt.Errorf("invalid city at index %d: %q/%q", i, city.City, city.State)
+		}
+		key := strings.ToLower(city.City) + "-" + city.State
+		if seen[key] {
+			return fmt.Errorf("duplicate city %s/%s", city.City, city.State)
+		}
+		seen[key] = true
+		if city.RefreshSLAHours < 0 {
+			return fmt.Errorf("invalid refresh_sla_hours for %s/%s", city.City, city.State)
+		}
+
+		for j := range city.Sites {
+			site := &city.Sites[j]
+			site.URL = strings.TrimSpace(site.URL)
+			if !strings.HasPrefix(site.URL, "http://") && !strings.HasPrefix(site.URL, "https://") {
+				return fmt.Errorf("invalid site url %q in %s/%s", site.URL, city.City, city.State)
+			}
+			if site.Domain == "" {
+				site.Domain = repository.NormalizeDomain(site.URL)
+			}
+		}
+	}
+
+	if len(export.Domains) > 0 && s.domainConfigs == nil {
+		return fmt.Errorf("domain configs not available to import %d domains", len(export.Domains))
+	}
+	for i := range export.Domains {
+		config := &export.Domains[i]
+		config.Domain = repository.NormalizeDomain(config.Domain)
+		if config.Domain == "" {
+			return fmt.Errorf("invalid domain at index %d", i)
+		}
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid domain config %s: %v", config.Domain, err)
+		}
+	}
+	return nil
+}
+
+// importedSite converte o site do arquivo, mantendo as estatísticas do site já cadastrado; retorna
+// true se a cidade já tinha o site
+func importedSite(siteExport SiteExport, previous []repository.SiteInfo) (repository.SiteInfo, bool) {
+	site := repository.SiteInfo{DiscoveredAt: time.Now()}
+	existing := findImportedSite(previous, repository.SiteInfo{URL: siteExport.URL, Domain: siteExport.Domain})
+	if existing != nil {
+		site = *existing
+	}
+
+	site.URL = siteExport.URL
+	site.Domain = siteExport.Domain
+	site.Name = siteExport.Name
+	site.Status = siteExport.Status
+	if site.Status == "" {
+		site.Status = "active"
+	}
+	site.DiscoveryMethod = siteExport.DiscoveryMethod
+	if site.DiscoveryMethod == "" {
+		site.DiscoveryMethod = "import"
+	}
+	site.Source = siteExport.Source
+	site.ReferenceURLs = siteExport.ReferenceURLs
+	return site, existing != nil
+}
+
+// findImportedSite retorna o site da lista com a mesma URL ou domínio (nil se não houver)
+func findImportedSite(sites []repository.SiteInfo, site repository.SiteInfo) *repository.SiteInfo {
+	domain := repository.NormalizeDomain(site.Domain)
+	for i := range sites {
+		existing := &sites[i]
+		if existing.URL == site.URL || repository.NormalizeDomain(existing.Domain) == domain {
+			return existing
+		}
+	}
+	return nil
+}
+
+// MarshalCitySitesExport codifica o arquivo em JSON ou YAML (format "yaml"), com as chaves na
+// ordem dos campos e os nomes usados pela API
+func MarshalCitySitesExport(export *CitySitesExport, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode city sites export: %v", err)
+	}
+	if format != "yaml" {
+		return data, nil
+	}
+
+	// JSON também é YAML: o documento lido mantém a ordem das chaves e só perde o estilo de fluxo
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to convert city sites export to yaml: %v", err)
+	}
+	resetYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("failed to encode city sites export as yaml: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode city sites export as yaml: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resetYAMLStyle troca o estilo de fluxo e as aspas do JSON pelo estilo em blocos do YAML
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// ParseCitySitesExport lê o arquivo em JSON ou YAML; campos desconhecidos são rejeitados para
+// que erros de digitação no arquivo curado não passem despercebidos
+func ParseCitySitesExport(data []byte) (*CitySitesExport, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCitySitesFile, err)
+	}
+	if document == nil {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalidCitySitesFile)
+	}
+
+	// Os nomes dos campos são os do JSON: o documento YAML é convertido antes de decodificar
+	normalized, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCitySitesFile, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.DisallowUnknownFields()
+
+	var export CitySitesExport
+	if err := decoder.Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCitySitesFile, err)
+	}
+	return &export, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,6 +17,8 @@ type CitySitesService struct {
 	repository      repository.CitySitesRepository
 	discoveryEngine *crawler.SiteDiscoveryEngine
 	logger          *logger.Logger
+	refreshSLA      time.Duration        // SLA padrão de atualização das cidades (0 desativa)
+	domainConfigs   *DomainConfigService // configurações por domínio da exportação (opcional)
 }
 
 // NewCitySitesService cria um novo serviço de sites por cidade
@@ -92,6 +95,11 @@ func (s *CitySitesService) GetActiveDiscoveryJobs() []*repository.DiscoveryJob {
 	return s.discoveryEngine.GetActiveJobs()
 }
 
+// SetRefreshSLA define o SLA padrão de atualização usado no campo freshness das cidades
+func (s *CitySitesService) SetRefreshSLA(sla time.Duration) {
+	s.refreshSLA = sla
+}
+
 // GetAllCities retorna todas as cidades cadastradas
 func (s *CitySitesService) GetAllCities(ctx context.Context) ([]repository.CitySites, error) {
 	cities, err := s.repository.FindAllCities(ctx)
@@ -100,6 +108,12 @@ func (s *CitySitesService) GetAllCities(ctx context.Context) ([]repository.CityS
 		return nil, fmt.Errorf("failed to get cities: %v", err)
 	}
 
+	now := time.Now()
+	for i := range cities {
+		freshness := cities[i].EvaluateFreshness(s.refreshSLA, now)
+		cities[i].Freshness = &freshness
+	}
+
 	s.logger.WithField("cities_count", len(cities)).Debug("Retrieved all cities")
 	return cities, nil
 }
@@ -116,9 +130,27 @@ func (s *CitySitesService) GetCityByName(ctx context.Context, city, state string
 		return nil, fmt.Errorf("city not found: %s-%s", city, state)
 	}
 
+	freshness := cityData.EvaluateFreshness(s.refreshSLA, time.Now())
+	cityData.Freshness = &freshness
 	return cityData, nil
 }
 
+// SetCityRefreshSLA define o SLA de atualização da cidade em horas (0 volta ao SLA padrão)
+func (s *CitySitesService) SetCityRefreshSLA(ctx context.Context, city, state string, hours int) error {
+	refreshRepo, ok := s.repository.(repository.CityRefreshRepository)
+	if !ok {
+		return errors.New("city refresh sla not supported by city sites repository")
+	}
+	if hours < 0 {
+		return fmt.Errorf("invalid refresh sla: %d hours", hours)
+	}
+	if err := refreshRepo.SetCityRefreshSLA(ctx, city, state, hours); err != nil {
+		s.logger.WithError(err).Error("Failed to set city refresh SLA", err)
+		return err
+	}
+	return nil
+}
+
 // GetCitiesByNames retorna cidades específicas por nome
 func (s *CitySitesService) GetCitiesByNames(ctx context.Context, cities []string) ([]repository.CitySites, error) {
 	if len(cities) == 0 {
@@ -422,3 +454,132 @@ func (s *CitySitesService) GetCitiesByRegion(ctx context.Context, region string)
 
 	return cities, nil
 }
+
+// SiteMigrationOptions define os arquivos migrados e a cidade usada quando nenhuma é deduzida
+type SiteMigrationOptions struct {
+	Files        []string `json:"files"`
+	DefaultCity  string   `json:"default_city,omitempty"`
+	DefaultState string   `json:"default_state,omitempty"`
+	DryRun       bool     `json:"dry_run"` // apenas calcula o resultado, sem gravar
+}
+
+// SiteMigrationResult resume a migração dos arquivos de sites para o repositório
+type SiteMigrationResult struct {
+	Files         []string                     `json:"files"`
+	URLs          int                          `json:"urls"`
+	Cities        int                          `json:"cities"`
+	SitesAdded    int                          `json:"sites_added"`
+	SitesExisting int                          `json:"sites_existing"` // já cadastrados; recebem as URLs de referência
+	Unresolved    []string                     `json:"unresolved"`     // URLs sem cidade deduzida
+	DryRun        bool                         `json:"dry_run"`
+	Groups        []crawler.SiteMigrationGroup `json:"groups"`
+}
+
+// MigrateSiteFiles importa List-site.ini / sites.json para o repositório de sites por cidade,
+// agrupando as URLs por cidade e domínio. Sites já cadastrados (mesmo domínio na cidade) não são
+// duplicados. Pode ser executada novamente sem efeitos colaterais.
+func (s *CitySitesService) MigrateSiteFiles(ctx context.Context, opts SiteMigrationOptions) (*SiteMigrationResult, error) {
+	if len(opts.Files) == 0 {
+		return nil, fmt.Errorf("no files to migrate")
+	}
+
+	var entries []crawler.SiteFileEntry
+	for _, file := range opts.Files {
+		fileEntries, err := crawler.LoadSiteFile(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	existing, err := s.repository.FindAllCities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cities: %v", err)
+	}
+	known := make([]repository.CityInfo, 0, len(existing))
+	for _, city := range existing {
+		known = append(known, repository.CityInfo{Name: city.City, State: city.State, Region: city.Region})
+	}
+
+	var fallback *repository.CityInfo
+	if opts.DefaultCity != "" {
+		fallback = &repository.CityInfo{Name: opts.DefaultCity, State: strings.ToUpper(opts.DefaultState)}
+	}
+	groups, unresolved := crawler.PlanSiteMigration(entries, known, fallback)
+
+	result := &SiteMigrationResult{
+		Files:      opts.Files,
+		URLs:       len(entries),
+		Cities:     len(groups),
+		Unresolved: unresolved,
+		DryRun:     opts.DryRun,
+		Groups:     groups,
+	}
+	for _, group := range groups {
+		cityData, err := s.repository.FindByCity(ctx, group.City.Name, group.City.State)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find city: %v", err)
+		}
+		if cityData == nil {
+			cityData = &repository.CitySites{
+				City:          group.City.Name,
+				State:         strings.ToUpper(group.City.State),
+				Region:        group.City.Region,
+				Status:        "active",
+				LastDiscovery: time.Now(),
+			}
+		}
+
+		for _, site := range group.Sites {
+			if mergeMigratedSite(cityData, site) {
+				result.SitesExisting++
+			} else {
+				cityData.AddSite(site)
+				result.SitesAdded++
+			}
+		}
+
+		if opts.DryRun {
+			continue
+		}
+		if err := s.repository.SaveCitySites(ctx, *cityData); err != nil {
+			s.logger.WithError(err).Error("Failed to save migrated city sites", err)
+			return nil, fmt.Errorf("failed to save city: %v", err)
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"files":          opts.Files,
+		"urls":           result.URLs,
+		"cities":         result.Cities,
+		"sites_added":    result.SitesAdded,
+		"sites_existing": result.SitesExisting,
+		"unresolved":     len(result.Unresolved),
+		"dry_run":        opts.DryRun,
+	}).Info("Site files migrated to city sites repository")
+
+	return result, nil
+}
+
+// mergeMigratedSite junta as URLs de referência ao site já cadastrado com o mesmo domínio;
+// retorna false se a cidade ainda não tem o site
+func mergeMigratedSite(cityData *repository.CitySites, site repository.SiteInfo) bool {
+	domain := repository.NormalizeDomain(site.Domain)
+	for i := range cityData.Sites {
+		existing := &cityData.Sites[i]
+		if existing.URL != site.URL && repository.NormalizeDomain(existing.Domain) != domain {
+			continue
+		}
+		for _, referenceURL := range site.ReferenceURLs {
+			found := false
+			for _, current := range existing.ReferenceURLs {
+				found = found || current == referenceURL
+			}
+			if !found {
+				existing.ReferenceURLs = append(existing.ReferenceURLs, referenceURL)
+			}
+		}
+		return true
+	}
+	return false
+}
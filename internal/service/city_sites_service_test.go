@@ -2,6 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -333,3 +338,208 @@ func TestCitySitesService_CleanupInactiveSites(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+
+func TestCitySitesService_MigrateSiteFiles(t *testing.T) {
+	mockRepo := &MockCitySitesRepository{}
+	service := NewCitySitesService(mockRepo)
+
+	listFile := filepath.Join(t.TempDir(), "List-site.ini")
+	assert.NoError(t, os.WriteFile(listFile, []byte(`https://www.imobr.com.br/detalhes/casa/venda/muzambinho/mg/bairro-centro/3462038/1
+https://www.josimaralves.com.br/imovel/2505468/chacara-a-venda-em-muzambinho-mg
+https://www.imoveiscasanova.com.br/imovel/220334
+`), 0644))
+
+	// imobr.com.br já está cadastrado: recebe apenas a URL de referência
+	existingCity := &repository.CitySites{
+		City:  "Muzambinho",
+		State: "MG",
+		Sites: []repository.SiteInfo{
+			{URL: "https://www.imobr.com.br/", Domain: "www.imobr.com.br", Status: "active"},
+		},
+	}
+	mockRepo.On("FindAllCities", mock.Anything).Return([]repository.CitySites{*existingCity}, nil)
+	mockRepo.On("FindByCity", mock.Anything, "Muzambinho", "MG").Return(existingCity, nil)
+	mockRepo.On("SaveCitySites", mock.Anything, mock.MatchedBy(func(cs repository.CitySites) bool {
+		return cs.City == "Muzambinho" && len(cs.Sites) == 2 && len(cs.Sites[0].ReferenceURLs) == 1 &&
+			cs.Sites[1].DiscoveryMethod == "migration"
+	})).Return(nil).Once()
+
+	ctx := context.Background()
+	result, err := service.MigrateSiteFiles(ctx, SiteMigrationOptions{Files: []string{listFile}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.URLs)
+	assert.Equal(t, 1, result.Cities)
+	assert.Equal(t, 1, result.SitesAdded)
+	assert.Equal(t, 1, result.SitesExisting)
+	assert.Equal(t, []string{"https://www.imoveiscasanova.com.br/imovel/220334"}, result.Unresolved)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCitySitesService_MigrateSiteFiles_DryRun(t *testing.T) {
+	mockRepo := &MockCitySitesRepository{}
+	service := NewCitySitesService(mockRepo)
+
+	listFile := filepath.Join(t.TempDir(), "List-site.ini")
+	assert.NoError(t, os.WriteFile(listFile, []byte("https://www.imoveiscasanova.com.br/imovel/220334\n"), 0644))
+
+	mockRepo.On("FindAllCities", mock.Anything).Return([]repository.CitySites{}, nil)
+	mockRepo.On("FindByCity", mock.Anything, "Guaxupé", "MG").Return(nil, nil)
+
+	ctx := context.Background()
+	result, err := service.MigrateSiteFiles(ctx, SiteMigrationOptions{
+		Files:        []string{listFile},
+		DefaultCity:  "Guaxupé",
+		DefaultState: "mg",
+		DryRun:       true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.SitesAdded)
+	assert.Empty(t, result.Unresolved)
+	mockRepo.AssertNotCalled(t, "SaveCitySites", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+// MockDomainConfigRepository é um mock do repositório de configurações por domínio
+type MockDomainConfigRepository struct {
+	mock.Mock
+}
+
+func (m *MockDomainConfigRepository) SaveDomainConfig(ctx context.Context, config repository.DomainConfig) (*repository.DomainConfig, error) {
+	args := m.Called(ctx, config)
+	return &config, args.Error(0)
+}
+
+func (m *MockDomainConfigRepository) FindDomainConfig(ctx context.Context, domain string) (*repository.DomainConfig, error) {
+	return nil, nil
+}
+
+func (m *MockDomainConfigRepository) FindAllDomainConfigs(ctx context.Context) ([]repository.DomainConfig, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]repository.DomainConfig), args.Error(1)
+}
+
+func (m *MockDomainConfigRepository) DeleteDomainConfig(ctx context.Context, domain string) error {
+	return nil
+}
+
+func (m *MockDomainConfigRepository) Close() {}
+
+func TestCitySitesService_ExportImportCitySites(t *testing.T) {
+	ctx := context.Background()
+
+	// Staging: cidade curada com estatísticas de crawling e a configuração do domínio
+	stagingRepo := &MockCitySitesRepository{}
+	stagingDomains := &MockDomainConfigRepository{}
+	staging := NewCitySitesService(stagingRepo)
+	staging.SetDomainConfigService(NewDomainConfigService(stagingDomains))
+
+	stagingRepo.On("FindAllCities", mock.Anything).Return([]repository.CitySites{{
+		City:            "Muzambinho",
+		State:           "MG",
+		RefreshSLAHours: 12,
+		Sites: []repository.SiteInfo{
+			{URL: "https://www.imobr.com.br/", Name: "Imobr", Domain: "www.imobr.com.br", Status: "active", PropertiesFound: 40},
+			{URL: "https://www.casanova.com.br/", Domain: "www.casanova.com.br", Status: "active", ReferenceURLs: []string{"https://www.casanova.com.br/imovel/1"}},
+		},
+	}}, nil)
+	stagingDomains.On("FindAllDomainConfigs", mock.Anything).Return([]repository.DomainConfig{{
+		ID:               "abc",
+		Domain:           "imobr.com.br",
+		RateLimit:        repository.DomainRateLimit{Parallelism: 2, DelayMs: 1500},
+		Selectors:        map[string]string{"valor": ".price"},
+		Notes:            "2024",
+		LearnedSkipPaths: []string{"/blog"},
+	}}, nil)
+
+	export, err := staging.ExportCitySites(ctx)
+	assert.NoError(t, err)
+	data, err := MarshalCitySitesExport(export, "yaml")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "version: 1\n"))
+	assert.NotContains(t, string(data), "properties_found")
+	assert.NotContains(t, string(data), "/blog")
+
+	parsed, err := ParseCitySitesExport(data)
+	assert.NoError(t, err)
+	assert.Equal(t, export.Cities, parsed.Cities)
+	assert.Equal(t, "2024", parsed.Domains[0].Notes)
+
+	// Produção: imobr.com.br já cadastrado (mantém as estatísticas) e um site fora do arquivo
+	prodRepo := &MockCitySitesRepository{}
+	prodDomains := &MockDomainConfigRepository{}
+	prod := NewCitySitesService(prodRepo)
+	prod.SetDomainConfigService(NewDomainConfigService(prodDomains))
+
+	prodRepo.On("FindByCity", mock.Anything, "Muzambinho", "MG").Return(&repository.CitySites{
+		City:  "Muzambinho",
+		State: "MG",
+		Sites: []repository.SiteInfo{
+			{URL: "https://imobr.com.br", Domain: "imobr.com.br", Status: "inactive", PropertiesFound: 7},
+			{URL: "https://www.antiga.com.br/", Domain: "www.antiga.com.br", Status: "active"},
+		},
+	}, nil)
+	prodRepo.On("SaveCitySites", mock.Anything, mock.MatchedBy(func(cs repository.CitySites) bool {
+		return len(cs.Sites) == 2 && cs.RefreshSLAHours == 12 &&
+			cs.Sites[0].Status == "active" && cs.Sites[0].PropertiesFound == 7 && cs.Sites[0].Name == "Imobr" &&
+			cs.Sites[1].DiscoveryMethod == "import"
+	})).Return(nil).Once()
+	prodDomains.On("SaveDomainConfig", mock.Anything, mock.MatchedBy(func(dc repository.DomainConfig) bool {
+		return dc.Domain == "imobr.com.br" && dc.RateLimit.DelayMs == 1500 && dc.Selectors["valor"] == ".price"
+	})).Return(nil).Once()
+
+	result, err := prod.ImportCitySites(ctx, *parsed, CitySitesImportOptions{Replace: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Cities)
+	assert.Equal(t, 0, result.CitiesCreated)
+	assert.Equal(t, 1, result.SitesAdded)
+	assert.Equal(t, 1, result.SitesUpdated)
+	assert.Equal(t, 1, result.SitesRemoved)
+	assert.Equal(t, 1, result.Domains)
+	prodRepo.AssertExpectations(t)
+	prodDomains.AssertExpectations(t)
+
+	// Arquivos de outra versão ou com campos desconhecidos são rejeitados sem gravar nada
+	parsed.Version = 2
+	_, err = prod.ImportCitySites(ctx, *parsed, CitySitesImportOptions{})
+	assert.ErrorIs(t, err, ErrInvalidCitySitesFile)
+
+	_, err = ParseCitySitesExport([]byte("{\"version\": 1, \"citys\": []}"))
+	assert.ErrorIs(t, err, ErrInvalidCitySitesFile)
+}
+
+// failingDomainConfigRepository devolve o mesmo erro em todas as buscas e remoções
+type failingDomainConfigRepository struct {
+	MockDomainConfigRepository
+	err error
+}
+
+func (r *failingDomainConfigRepository) FindDomainConfig(ctx context.Context, domain string) (*repository.DomainConfig, error) {
+	return nil, r.err
+}
+
+func (r *failingDomainConfigRepository) DeleteDomainConfig(ctx context.Context, domain string) error {
+	return r.err
+}
+
+func TestDomainConfigService_NotFoundIsDistinctFromRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+
+	// Domínio sem configuração: erro de "não encontrado" (404 no handler)
+	missing := NewDomainConfigService(&MockDomainConfigRepository{})
+	_, err := missing.GetDomainConfig(ctx, "imobiliaria.com.br")
+	assert.ErrorIs(t, err, repository.ErrDomainConfigNotFound)
+
+	notFound := NewDomainConfigService(&failingDomainConfigRepository{err: fmt.Errorf("%w: imobiliaria.com.br", repository.ErrDomainConfigNotFound)})
+	assert.ErrorIs(t, notFound.DeleteDomainConfig(ctx, "imobiliaria.com.br"), repository.ErrDomainConfigNotFound)
+
+	// Falha do banco não vira "não encontrado" (500 no handler)
+	broken := NewDomainConfigService(&failingDomainConfigRepository{err: errors.New("server selection timeout")})
+	_, err = broken.GetDomainConfig(ctx, "imobiliaria.com.br")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrDomainConfigNotFound)
+	err = broken.DeleteDomainConfig(ctx, "imobiliaria.com.br")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrDomainConfigNotFound)
+}
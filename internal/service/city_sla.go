@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// StaleCityAlert indica uma cidade cujos dados não foram atualizados dentro do SLA
+type StaleCityAlert struct {
+	City             string     `json:"city"`
+	State            string     `json:"state"`
+	SLAHours         int        `json:"sla_hours"`
+	AgeHours         float64    `json:"age_hours"`
+	Reason           string     `json:"reason"` // never_refreshed, not_scheduled ou refresh_failing
+	LastRefresh      *time.Time `json:"last_refresh,omitempty"`
+	RefreshFailures  int        `json:"refresh_failures"`
+	LastRefreshError string     `json:"last_refresh_error,omitempty"`
+	DetectedAt       time.Time  `json:"detected_at"`
+}
+
+// Event identifica o alerta de cidade desatualizada
+func (a StaleCityAlert) Event() string {
+	return "city_refresh_sla"
+}
+
+// Subject é o assunto do e-mail do alerta
+func (a StaleCityAlert) Subject() string {
+	return fmt.Sprintf("Dados desatualizados: %s-%s", a.City, a.State)
+}
+
+// Summary descreve o alerta em uma linha
+func (a StaleCityAlert) Summary() string {
+	var cause string
+	switch a.Reason {
+	case repository.StaleRefreshFailing:
+		cause = fmt.Sprintf("%d crawlings seguidos falharam (%s)", a.RefreshFailures, a.LastRefreshError)
+	case repository.StaleNeverRefreshed:
+		cause = "nenhum crawling concluído desde o cadastro"
+	default:
+		cause = "nenhum crawling tentou atualizar a cidade"
+	}
+	return fmt.Sprintf("%s-%s sem atualização há %.0fh (SLA de %dh): %s", a.City, a.State, a.AgeHours, a.SLAHours, cause)
+}
+
+// cityRefreshSLA retorna o SLA padrão de atualização das cidades (0 desativado)
+func (s *PropertyService) cityRefreshSLA() time.Duration {
+	if s.config == nil {
+		return 0
+	}
+	return s.config.CityRefreshSLA
+}
+
+// recordCityRefresh registra o crawling nas cidades cujos sites estavam entre as seeds: sem erro
+// a cidade foi atualizada; jobs cancelados não contam como atualização nem como falha
+func (s *PropertyService) recordCityRefresh(ctx context.Context, jobID string, seeds []string, crawlErr error) {
+	refreshRepo, ok := s.citySitesRepo.(repository.CityRefreshRepository)
+	if !ok {
+		return
+	}
+	if control := s.jobs.Control(jobID); control != nil && control.Cancelled() {
+		return
+	}
+
+	cities, err := s.citySitesRepo.FindAllCities(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to load cities to record refresh")
+		return
+	}
+	seeded := make(map[string]bool, len(seeds))
+	for _, seed := range seeds {
+		seeded[repository.NormalizeDomain(seed)] = true
+	}
+
+	now := time.Now()
+	for _, city := range cities {
+		covered := false
+		for _, site := range city.Sites {
+			if seeded[repository.NormalizeDomain(site.URL)] || seeded[repository.NormalizeDomain(site.Domain)] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			continue
+		}
+		if err := refreshRepo.RecordCityRefresh(ctx, city.City, city.State, now, crawlErr); err != nil {
+			s.logger.WithField("city", city.City).WithError(err).Warn("Failed to record city refresh")
+		}
+	}
+}
+
+// CheckCitySLAs procura as cidades desatualizadas além do SLA e alerta cada atraso uma única vez
+// (um novo alerta só depois de a cidade ser atualizada e atrasar de novo)
+func (s *PropertyService) CheckCitySLAs(ctx context.Context) ([]StaleCityAlert, error) {
+	if s.citySitesRepo == nil {
+		return nil, errors.New("city sites repository not configured")
+	}
+	cities, err := s.citySitesRepo.FindAllCities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var stale []StaleCityAlert
+	for _, city := range cities {
+		freshness := city.EvaluateFreshness(s.cityRefreshSLA(), now)
+		if !freshness.Stale {
+			continue
+		}
+		alert := StaleCityAlert{
+			City:             city.City,
+			State:            city.State,
+			SLAHours:         freshness.SLAHours,
+			AgeHours:         freshness.AgeHours,
+			Reason:           freshness.Reason,
+			LastRefresh:      freshness.LastRefresh,
+			RefreshFailures:  city.RefreshFailures,
+			LastRefreshError: city.LastRefreshError,
+			DetectedAt:       now,
+		}
+		stale = append(stale, alert)
+		if s.markSLAAlerted(city.GetCityKey(), city.LastRefresh) {
+			s.sendStaleCityAlert(ctx, alert)
+		}
+	}
+	return stale, nil
+}
+
+// markSLAAlerted registra o alerta do atraso atual da cidade; false se ele já foi enviado
+func (s *PropertyService) markSLAAlerted(key string, lastRefresh time.Time) bool {
+	s.slaMutex.Lock()
+	defer s.slaMutex.Unlock()
+	if s.slaAlerted == nil {
+		s.slaAlerted = make(map[string]time.Time)
+	}
+	if alerted, exists := s.slaAlerted[key]; exists && alerted.Equal(lastRefresh) {
+		return false
+	}
+	s.slaAlerted[key] = lastRefresh
+	return true
+}
+
+// sendStaleCityAlert registra o alerta e o envia aos notificadores configurados
+func (s *PropertyService) sendStaleCityAlert(ctx context.Context, alert StaleCityAlert) {
+	s.logger.WithFields(map[string]interface{}{
+		"city":      alert.City,
+		"state":     alert.State,
+		"age_hours": alert.AgeHours,
+		"sla_hours": alert.SLAHours,
+		"reason":    alert.Reason,
+	}).Warn("City data refresh SLA exceeded")
+
+	for _, notifier := range s.alerts {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			s.logger.WithError(err).Warn("Failed to send city SLA alert")
+		}
+	}
+}
+
+// StartCitySLAMonitor verifica periodicamente o SLA de atualização das cidades até o contexto
+// ser cancelado
+func (s *PropertyService) StartCitySLAMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || s.cityRefreshSLA() <= 0 || s.citySitesRepo == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !s.leader.IsLeader() {
+				s.logger.Debug("Not the leader, skipping scheduled city SLA check")
+			} else if _, err := s.CheckCitySLAs(ctx); err != nil {
+				s.logger.WithError(err).Warn("City SLA check failed")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// DomainConfigService gerencia as configurações de crawling por domínio
+type DomainConfigService struct {
+	repository repository.DomainConfigRepository
+	logger     *logger.Logger
+}
+
+// NewDomainConfigService cria um novo serviço de configurações por domínio
+func NewDomainConfigService(repo repository.DomainConfigRepository) *DomainConfigService {
+	return &DomainConfigService{
+		repository: repo,
+		logger:     logger.NewLogger("domain_config_service"),
+	}
+}
+
+// GetDomainConfig retorna a configuração de um domínio
+func (s *DomainConfigService) GetDomainConfig(ctx context.Context, domain string) (*repository.DomainConfig, error) {
+	normalized := repository.NormalizeDomain(domain)
+	if normalized == "" {
+		return nil, fmt.Errorf("invalid domain: %s", domain)
+	}
+
+	config, err := s.repository.FindDomainConfig(ctx, normalized)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get domain config", err)
+		return nil, fmt.Errorf("failed to get domain config: %w", err)
+	}
+
+	if config == nil {
+		return nil, fmt.Errorf("%w: %s", repository.ErrDomainConfigNotFound, normalized)
+	}
+
+	return config, nil
+}
+
+// GetAllDomainConfigs retorna todas as configurações cadastradas
+func (s *DomainConfigService) GetAllDomainConfigs(ctx context.Context) ([]repository.DomainConfig, error) {
+	configs, err := s.repository.FindAllDomainConfigs(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get domain configs", err)
+		return nil, fmt.Errorf("failed to get domain configs: %v", err)
+	}
+
+	return configs, nil
+}
+
+// SaveDomainConfig valida e grava a configuração de um domínio (cria ou substitui)
+func (s *DomainConfigService) SaveDomainConfig(ctx context.Context, domain string, config repository.DomainConfig) (*repository.DomainConfig, error) {
+	config.Domain = repository.NormalizeDomain(domain)
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid domain config: %v", err)
+	}
+
+	saved, err := s.repository.SaveDomainConfig(ctx, config)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to save domain config", err)
+		return nil, fmt.Errorf("failed to save domain config: %v", err)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"domain":     config.Domain,
+		"enable_js":  config.EnableJS,
+		"rate_limit": config.RateLimit,
+		"include":    len(config.IncludePatterns),
+		"exclude":    len(config.ExcludePatterns),
+		"selectors":  len(config.Selectors),
+	}).Info("Domain config saved")
+
+	return saved, nil
+}
+
+// DeleteDomainConfig remove a configuração de um domínio
+func (s *DomainConfigService) DeleteDomainConfig(ctx context.Context, domain string) error {
+	normalized := repository.NormalizeDomain(domain)
+
+	if err := s.repository.DeleteDomainConfig(ctx, normalized); err != nil {
+		s.logger.WithError(err).Error("Failed to delete domain config", err)
+		return fmt.Errorf("failed to delete domain config: %w", err)
+	}
+
+	s.logger.WithField("domain", normalized).Info("Domain config deleted")
+	return nil
+}
+
+// GetSkipPathStats retorna o acumulado da classificação dos caminhos do domínio, incluindo os
+// caminhos aprendidos (learned_skip_paths)
+func (s *DomainConfigService) GetSkipPathStats(ctx context.Context, domain string) ([]repository.PathStats, error) {
+	normalized := repository.NormalizeDomain(domain)
+	if normalized == "" {
+		return nil, fmt.Errorf("invalid domain: %s", domain)
+	}
+	store, ok := s.repository.(repository.SkipPathRepository)
+	if !ok {
+		return nil, errors.New("learned skip paths not supported by domain config repository")
+	}
+	return store.FindPathStats(ctx, normalized)
+}
+
+// ForgetSkipPath remove um caminho da lista aprendida do domínio; ele não é aprendido de novo
+func (s *DomainConfigService) ForgetSkipPath(ctx context.Context, domain, path string) error {
+	normalized := repository.NormalizeDomain(domain)
+	store, ok := s.repository.(repository.SkipPathRepository)
+	if !ok {
+		return errors.New("learned skip paths not supported by domain config repository")
+	}
+	if err := store.ForgetSkipPath(ctx, normalized, path); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"domain": normalized,
+		"path":   path,
+	}).Info("Learned skip path removed")
+	return nil
+}
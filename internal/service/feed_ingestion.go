@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// CrawlModeFeed identifica os jobs que crawleiam apenas as entradas novas dos feeds
+const CrawlModeFeed = "feed"
+
+// FeedIngestionStatus resume os feeds acompanhados
+type FeedIngestionStatus struct {
+	Feeds   []crawler.FeedStatus `json:"feeds"`
+	Pending int                  `json:"pending"` // entradas aguardando o próximo crawling
+}
+
+// StartFeedIngestion lê os feeds RSS/Atom periodicamente e dispara um job de crawling apenas
+// com as entradas novas (sem recrawlear os sites). Se outro job estiver rodando, as entradas
+// aguardam e são visitadas com prioridade no próximo crawling.
+func (s *PropertyService) StartFeedIngestion(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// Só o líder dispara crawlings agendados; as demais réplicas atendem apenas a API
+			if feeds := s.feedURLs(); len(feeds) > 0 && s.leader.IsLeader() {
+				s.feeds.Poll(ctx, feeds)
+				if pending := s.feeds.PendingCount(); pending > 0 && !s.crawlJobRunning() {
+					job := s.StartFeedCrawlJob()
+					s.logger.WithFields(map[string]interface{}{
+						"job_id":  job.ID,
+						"entries": pending,
+					}).Info("Feed crawl job started")
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// StartFeedCrawlJob inicia um job que visita apenas as entradas pendentes dos feeds
+func (s *PropertyService) StartFeedCrawlJob() *repository.CrawlJob {
+	return s.startJob(nil, CrawlModeFeed, nil, s.crawlFeedEntries)
+}
+
+// GetFeedStatus retorna o estado dos feeds e as entradas pendentes
+func (s *PropertyService) GetFeedStatus() FeedIngestionStatus {
+	return FeedIngestionStatus{
+		Feeds:   s.feeds.Status(),
+		Pending: s.feeds.PendingCount(),
+	}
+}
+
+// crawlFeedEntries visita as entradas pendentes dos feeds como páginas de anúncio
+func (s *PropertyService) crawlFeedEntries(ctx context.Context, jobID string) error {
+	entries := s.feeds.TakePending()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	simpleCrawler, release, err := s.newSimpleCrawler(jobID)
+	if err != nil {
+		s.feeds.Complete(entries, nil)
+		return err
+	}
+	defer release()
+
+	// Entradas que falharam ou não foram visitadas (job cancelado) voltam para a fila
+	simpleCrawler.SetPriorityEntries(entries)
+	defer func() { s.feeds.Complete(entries, simpleCrawler.CrawledPriorityEntries()) }()
+	if err := simpleCrawler.Start(ctx, nil); err != nil {
+		return fmt.Errorf("erro no crawling dos feeds: %v", err)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"job_id":  jobID,
+		"entries": len(entries),
+	}).Info("Feed entries crawled")
+	return nil
+}
+
+// feedURLs junta os feeds da configuração (FEED_URLS) e os cadastrados por domínio
+func (s *PropertyService) feedURLs() []string {
+	unique := make(map[string]bool)
+	for _, feed := range s.config.FeedURLs {
+		if feed != "" {
+			unique[feed] = true
+		}
+	}
+	if s.configWatcher != nil {
+		if snapshot := s.configWatcher.Current(); snapshot != nil {
+			for _, domainConfig := range snapshot.Domains {
+				for _, feed := range domainConfig.Feeds {
+					unique[feed] = true
+				}
+			}
+		}
+	}
+
+	feeds := make([]string, 0, len(unique))
+	for feed := range unique {
+		feeds = append(feeds, feed)
+	}
+	sort.Strings(feeds)
+	return feeds
+}
+
+// crawlJobRunning indica se há algum job de crawling em andamento
+func (s *PropertyService) crawlJobRunning() bool {
+	for _, job := range s.jobs.GetJobs() {
+		if !job.IsFinished() {
+			return true
+		}
+	}
+	return false
+}
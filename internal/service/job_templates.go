@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// ErrInvalidJobTemplate indica parâmetros inválidos num template ou na sua execução
+var ErrInvalidJobTemplate = errors.New("invalid job template")
+
+// TemplateRunOverrides substitui parâmetros do template numa execução; campos vazios ou nil
+// mantêm os valores do template e as tags são somadas às do template
+type TemplateRunOverrides struct {
+	Cities     []string
+	Domains    []string
+	Tags       []string
+	Mode       string
+	EnableAI   *bool
+	MaxPages   *int
+	MaxMinutes *int
+}
+
+// GetJobTemplates retorna os templates cadastrados e os padrão que não foram substituídos,
+// em ordem alfabética
+func (s *PropertyService) GetJobTemplates(ctx context.Context) ([]repository.CrawlJobTemplate, error) {
+	templates := make(map[string]repository.CrawlJobTemplate)
+	for _, template := range repository.DefaultJobTemplates() {
+		templates[template.Name] = template
+	}
+	if templateRepo, ok := s.repo.(repository.JobTemplateRepository); ok {
+		stored, err := templateRepo.FindJobTemplates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, template := range stored {
+			templates[template.Name] = template
+		}
+	}
+
+	result := make([]repository.CrawlJobTemplate, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, template)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// GetJobTemplate retorna o template cadastrado com o nome ou, sem cadastro, o template padrão
+func (s *PropertyService) GetJobTemplate(ctx context.Context, name string) (*repository.CrawlJobTemplate, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if templateRepo, ok := s.repo.(repository.JobTemplateRepository); ok {
+		template, err := templateRepo.FindJobTemplate(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if template != nil {
+			return template, nil
+		}
+	}
+	for _, template := range repository.DefaultJobTemplates() {
+		if template.Name == name {
+			return &template, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", repository.ErrJobTemplateNotFound, name)
+}
+
+// SaveJobTemplate valida e grava um template (cria ou substitui, inclusive os padrão)
+func (s *PropertyService) SaveJobTemplate(ctx context.Context, template repository.CrawlJobTemplate) (*repository.CrawlJobTemplate, error) {
+	templateRepo, ok := s.repo.(repository.JobTemplateRepository)
+	if !ok {
+		return nil, errors.New("job templates not supported by property repository")
+	}
+	if err := template.Normalize(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobTemplate, err)
+	}
+
+	saved, err := templateRepo.SaveJobTemplate(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"template":  saved.Name,
+		"mode":      saved.Mode,
+		"domains":   len(saved.Domains),
+		"max_pages": saved.MaxPages,
+	}).Info("Job template saved")
+	return saved, nil
+}
+
+// DeleteJobTemplate remove um template cadastrado; um template padrão substituído volta a valer
+func (s *PropertyService) DeleteJobTemplate(ctx context.Context, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	templateRepo, ok := s.repo.(repository.JobTemplateRepository)
+	if !ok {
+		return errors.New("job templates not supported by property repository")
+	}
+	if err := templateRepo.DeleteJobTemplate(ctx, name); err != nil {
+		if errors.Is(err, repository.ErrJobTemplateNotFound) {
+			return fmt.Errorf("%w: %s", repository.ErrJobTemplateNotFound, name)
+		}
+		return err
+	}
+	s.logger.WithField("template", name).Info("Job template deleted")
+	return nil
+}
+
+// StartTemplateJob dispara um job de crawling com os parâmetros do template e as substituições
+// informadas; o job para de agendar visitas ao esgotar o orçamento de páginas ou de tempo
+func (s *PropertyService) StartTemplateJob(ctx context.Context, name string, overrides TemplateRunOverrides) (*repository.CrawlJob, error) {
+	template, err := s.GetJobTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	params := applyTemplateOverrides(*template, overrides)
+	if err := params.Normalize(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobTemplate, err)
+	}
+
+	job, _ := s.jobs.CreateTemplateJob(params)
+	s.logger.WithFields(map[string]interface{}{
+		"job_id":    job.ID,
+		"template":  params.Name,
+		"mode":      params.Mode,
+		"domains":   params.Domains,
+		"max_pages": params.MaxPages,
+		"max_min":   params.MaxMinutes,
+	}).Info("Starting crawl job from template")
+
+	return s.runJob(job, params.Mode, func(ctx context.Context, jobID string) error {
+		return s.forceCrawling(ctx, params, jobID)
+	}), nil
+}
+
+// applyTemplateOverrides aplica as substituições de uma execução sobre uma cópia do template
+func applyTemplateOverrides(template repository.CrawlJobTemplate, overrides TemplateRunOverrides) repository.CrawlJobTemplate {
+	if len(overrides.Cities) > 0 {
+		template.Cities = overrides.Cities
+	}
+	if len(overrides.Domains) > 0 {
+		template.Domains = overrides.Domains
+	}
+	if overrides.Mode != "" {
+		template.Mode = overrides.Mode
+	}
+	if overrides.EnableAI != nil {
+		template.EnableAI = overrides.EnableAI
+	}
+	if overrides.MaxPages != nil {
+		template.MaxPages = *overrides.MaxPages
+	}
+	if overrides.MaxMinutes != nil {
+		template.MaxMinutes = *overrides.MaxMinutes
+	}
+	template.Tags = append(append([]string(nil), template.Tags...), overrides.Tags...)
+	return template
+}
+
+// filterURLsByDomain mantém apenas as URLs dos domínios informados (já normalizados)
+func filterURLsByDomain(urls []string, domains []string) []string {
+	allowed := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		allowed[domain] = true
+	}
+
+	var filtered []string
+	for _, rawURL := range urls {
+		if allowed[repository.NormalizeDomain(rawURL)] {
+			filtered = append(filtered, rawURL)
+		}
+	}
+	return filtered
+}
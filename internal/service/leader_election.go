@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/logger"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// Backends da eleição de líder (LEADER_ELECTION)
+const (
+	LeaderElectionMongo      = "mongo"
+	LeaderElectionKubernetes = "kubernetes"
+)
+
+// LeaderElector mantém a lease de liderança entre as réplicas da API: só o líder executa as
+// tarefas agendadas (feeds, índice de preços, métricas de qualidade). A lease é renovada a cada
+// terço da validade; se a renovação falhar, a instância deixa de se considerar líder quando a
+// lease vence, e outra réplica assume na tentativa seguinte.
+type LeaderElector struct {
+	leases   repository.LeaderLeaseRepository
+	name     string
+	identity string
+	ttl      time.Duration
+	logger   *logger.Logger
+
+	mutex       sync.RWMutex
+	stopped     bool // lease devolvida no encerramento: não disputa mais a liderança
+	leader      bool
+	leaderSince time.Time
+	expiresAt   time.Time
+	lastError   string
+}
+
+// LeaderStatus expõe o estado da eleição na instância e a lease atual
+type LeaderStatus struct {
+	Enabled     bool                    `json:"enabled"`
+	Identity    string                  `json:"identity,omitempty"`
+	Leader      bool                    `json:"leader"`
+	LeaderSince *time.Time              `json:"leader_since,omitempty"`
+	Lease       *repository.LeaderLease `json:"lease,omitempty"`
+	LastError   string                  `json:"last_error,omitempty"`
+}
+
+// NewLeaderElector cria o eleitor; identity vazio usa o hostname (o nome do pod no Kubernetes)
+// e o pid
+func NewLeaderElector(leases repository.LeaderLeaseRepository, name, identity string, ttl time.Duration) *LeaderElector {
+	if identity == "" {
+		identity = defaultLeaderIdentity()
+	}
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &LeaderElector{
+		leases:   leases,
+		name:     name,
+		identity: identity,
+		ttl:      ttl,
+		logger:   logger.NewLogger("leader_election"),
+	}
+}
+
+// defaultLeaderIdentity identifica a instância pelo hostname e pelo processo
+func defaultLeaderIdentity() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Start tenta obter a liderança imediatamente e segue renovando (ou disputando) a lease em
+// segundo plano até o contexto ser cancelado
+func (e *LeaderElector) Start(ctx context.Context) {
+	e.tryAcquire(ctx)
+
+	go func() {
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.tryAcquire(ctx)
+			}
+		}
+	}()
+}
+
+// IsLeader informa se a instância detém uma lease válida (um eleitor nil sempre é líder:
+// sem eleição, toda réplica agenda as tarefas)
+func (e *LeaderElector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.leader && time.Now().Before(e.expiresAt)
+}
+
+// Identity retorna o identificador da instância na eleição
+func (e *LeaderElector) Identity() string {
+	return e.identity
+}
+
+// Release devolve a lease no encerramento, para que outra réplica assuma sem esperar a validade
+func (e *LeaderElector) Release(ctx context.Context) error {
+	e.mutex.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.stopped = true
+	e.mutex.Unlock()
+
+	if !wasLeader {
+		return nil
+	}
+	if err := e.leases.ReleaseLeaderLease(ctx, e.name, e.identity); err != nil {
+		return err
+	}
+	e.logger.WithField("identity", e.identity).Info("Leadership released")
+	return nil
+}
+
+// Status retorna o estado da eleição e a lease atual
+func (e *LeaderElector) Status(ctx context.Context) (*LeaderStatus, error) {
+	lease, err := e.leases.FindLeaderLease(ctx, e.name)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	status := &LeaderStatus{
+		Enabled:   true,
+		Identity:  e.identity,
+		Leader:    e.leader && time.Now().Before(e.expiresAt),
+		Lease:     lease,
+		LastError: e.lastError,
+	}
+	if status.Leader {
+		since := e.leaderSince
+		status.LeaderSince = &since
+	}
+	return status, nil
+}
+
+// tryAcquire obtém ou renova a lease e registra as trocas de liderança
+func (e *LeaderElector) tryAcquire(ctx context.Context) {
+	e.mutex.RLock()
+	stopped := e.stopped
+	e.mutex.RUnlock()
+	if stopped {
+		return
+	}
+
+	attemptedAt := time.Now()
+	acquired, err := e.leases.AcquireLeaderLease(ctx, e.name, e.identity, e.ttl)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stopped {
+		return
+	}
+	if err != nil {
+		// Mantém a liderança até a lease vencer: a renovação pode dar certo na próxima tentativa
+		e.lastError = err.Error()
+		e.logger.WithError(err).Warn("Failed to renew leader lease")
+		return
+	}
+	e.lastError = ""
+
+	// Uma lease vencida durante falhas de renovação conta como liderança perdida
+	wasLeader := e.leader && attemptedAt.Before(e.expiresAt)
+	switch {
+	case acquired && !wasLeader:
+		e.leaderSince = attemptedAt
+		e.logger.WithField("identity", e.identity).Info("Leadership acquired")
+	case !acquired && wasLeader:
+		e.logger.WithField("identity", e.identity).Warn("Leadership lost")
+	}
+	e.leader = acquired
+	if acquired {
+		e.expiresAt = attemptedAt.Add(e.ttl)
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/crawler"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+	"github.com/dujoseaugusto/go-crawler-project/internal/utils"
+)
+
+// Formatos aceitos na importação de imóveis externos
+const (
+	ImportFormatNDJSON = "ndjson"
+	ImportFormatCSV    = "csv"
+)
+
+// maxImportErrors limita quantos erros por registro são devolvidos no relatório
+const maxImportErrors = 100
+
+// ImportRecordError descreve um registro rejeitado na importação
+type ImportRecordError struct {
+	Line   int      `json:"line"`
+	Reason string   `json:"reason"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ImportReport resume o resultado de uma importação
+type ImportReport struct {
+	Format     string              `json:"format"`
+	Source     string              `json:"source"`
+	Total      int                 `json:"total"`
+	Imported   int                 `json:"imported"`
+	Duplicates int                 `json:"duplicates"` // repetidos dentro do próprio arquivo
+	Invalid    int                 `json:"invalid"`
+	Failed     int                 `json:"failed"`
+	Errors     []ImportRecordError `json:"errors,omitempty"`
+}
+
+// importRecord é um imóvel lido do arquivo (ou o erro de leitura da linha)
+type importRecord struct {
+	line     int
+	property repository.Property
+	err      error
+}
+
+// ImportProperties importa imóveis de fontes externas (NDJSON ou CSV) passando pela mesma
+// validação, enriquecimento e deduplicação por hash usadas no crawling
+func (s *PropertyService) ImportProperties(ctx context.Context, reader io.Reader, format string) (*ImportReport, error) {
+	var records []importRecord
+	var err error
+
+	switch format {
+	case ImportFormatNDJSON:
+		records, err = readNDJSONProperties(reader)
+	case ImportFormatCSV:
+		records, err = readCSVProperties(reader)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Format: format,
+		Source: repository.SourceImport,
+		Total:  len(records),
+	}
+	validator := crawler.NewPropertyValidator()
+	seen := make(map[string]bool)
+
+	for _, record := range records {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		if record.err != nil {
+			report.Invalid++
+			report.addError(ImportRecordError{Line: record.line, Reason: record.err.Error()})
+			continue
+		}
+
+		property, err := importableProperty(record.property)
+		if err != nil {
+			report.Invalid++
+			report.addError(ImportRecordError{Line: record.line, Reason: err.Error()})
+			continue
+		}
+		repository.RecordFilledFields(&property, repository.ExtractorImport)
+
+		validation := validator.ValidateProperty(&property)
+		if !validation.IsValid {
+			report.Invalid++
+			report.addError(ImportRecordError{Line: record.line, Reason: "validation failed", Errors: validation.Errors})
+			continue
+		}
+		enhanced := validator.EnhanceProperty(&property)
+
+		hash := repository.GeneratePropertyHash(*enhanced)
+		if seen[hash] {
+			report.Duplicates++
+			continue
+		}
+		seen[hash] = true
+		enhanced.Hash = hash
+		enhanced.ShortID = repository.GenerateShortID(*enhanced)
+
+		if err := s.repo.Save(ctx, *enhanced); err != nil {
+			report.Failed++
+			report.addError(ImportRecordError{Line: record.line, Reason: err.Error()})
+			continue
+		}
+		report.Imported++
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"format":     report.Format,
+		"total":      report.Total,
+		"imported":   report.Imported,
+		"duplicates": report.Duplicates,
+		"invalid":    report.Invalid,
+		"failed":     report.Failed,
+	}).Info("Property import completed")
+
+	return report, nil
+}
+
+// importableProperty copia do registro enviado apenas os campos de anúncio aceitos na importação.
+// Os campos mantidos pelo servidor (ID, hash, ID curto, fotos, sinais de fraude e golpe, correções
+// manuais, registro canônico, tags, proveniência) nunca vêm do arquivo
+func importableProperty(record repository.Property) (repository.Property, error) {
+	if record.URL != "" {
+		parsed, err := url.Parse(record.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return repository.Property{}, fmt.Errorf("invalid url: only http(s) urls are accepted")
+		}
+	}
+
+	return repository.Property{
+		Endereco:        record.Endereco,
+		Cidade:          record.Cidade,
+		Bairro:          record.Bairro,
+		CEP:             record.CEP,
+		Descricao:       record.Descricao,
+		Valor:           record.Valor,
+		ValorTexto:      record.ValorTexto,
+		Quartos:         record.Quartos,
+		Banheiros:       record.Banheiros,
+		AreaTotal:       record.AreaTotal,
+		AreaUtil:        record.AreaUtil,
+		TipoImovel:      record.TipoImovel,
+		URL:             record.URL,
+		Caracteristicas: record.Caracteristicas,
+		Source:          repository.SourceImport,
+	}, nil
+}
+
+func (r *ImportReport) addError(recordError ImportRecordError) {
+	if len(r.Errors) < maxImportErrors {
+		r.Errors = append(r.Errors, recordError)
+	}
+}
+
+// readNDJSONProperties lê um imóvel JSON por linha (linhas em branco são ignoradas)
+func readNDJSONProperties(reader io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var records []importRecord
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var property repository.Property
+		if err := json.Unmarshal([]byte(text), &property); err != nil {
+			records = append(records, importRecord{line: line, err: fmt.Errorf("invalid JSON: %v", err)})
+			continue
+		}
+		records = append(records, importRecord{line: line, property: property})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading NDJSON: %v", err)
+	}
+	return records, nil
+}
+
+// readCSVProperties lê um CSV com cabeçalho usando os mesmos nomes de campo do JSON
+// (endereco, cidade, bairro, valor, quartos, ...). Características são separadas por "|" ou ";".
+func readCSVProperties(reader io.Reader) ([]importRecord, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, exists := columns["url"]; !exists {
+		return nil, fmt.Errorf("CSV header must contain the url column")
+	}
+
+	var records []importRecord
+	line := 1
+	for {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			records = append(records, importRecord{line: line, err: fmt.Errorf("invalid CSV row: %v", err)})
+			continue
+		}
+
+		property, err := propertyFromCSVRow(columns, row)
+		records = append(records, importRecord{line: line, property: property, err: err})
+	}
+
+	return records, nil
+}
+
+// propertyFromCSVRow converte uma linha do CSV em imóvel
+func propertyFromCSVRow(columns map[string]int, row []string) (repository.Property, error) {
+	get := func(name string) string {
+		if i, exists := columns[name]; exists && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	property := repository.Property{
+		Endereco:   get("endereco"),
+		Cidade:     get("cidade"),
+		Bairro:     get("bairro"),
+		CEP:        get("cep"),
+		Descricao:  get("descricao"),
+		ValorTexto: get("valor_texto"),
+		TipoImovel: get("tipo_imovel"),
+		URL:        get("url"),
+	}
+
+	var err error
+	if property.Valor, err = parseImportNumber(get("valor")); err != nil {
+		return property, fmt.Errorf("invalid valor: %v", err)
+	}
+	if property.AreaTotal, err = parseImportNumber(get("area_total")); err != nil {
+		return property, fmt.Errorf("invalid area_total: %v", err)
+	}
+	if property.AreaUtil, err = parseImportNumber(get("area_util")); err != nil {
+		return property, fmt.Errorf("invalid area_util: %v", err)
+	}
+
+	quartos, err := parseImportNumber(get("quartos"))
+	if err != nil {
+		return property, fmt.Errorf("invalid quartos: %v", err)
+	}
+	banheiros, err := parseImportNumber(get("banheiros"))
+	if err != nil {
+		return property, fmt.Errorf("invalid banheiros: %v", err)
+	}
+	property.Quartos = int(quartos)
+	property.Banheiros = int(banheiros)
+
+	if features := get("caracteristicas"); features != "" {
+		for _, feature := range strings.FieldsFunc(features, func(r rune) bool { return r == '|' || r == ';' }) {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				property.Caracteristicas = append(property.Caracteristicas, feature)
+			}
+		}
+	}
+
+	if property.ValorTexto == "" && property.Valor > 0 {
+		property.ValorTexto = get("valor")
+	}
+
+	return property, nil
+}
+
+// parseImportNumber aceita números nos formatos "450000", "450000.50", "450.000" e "R$ 450.000,50"
+// (utils.ParseBrazilianNumber, o mesmo dos crawlers)
+func parseImportNumber(raw string) (float64, error) {
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(raw), "R$"))
+	value = strings.ReplaceAll(value, " ", "")
+	if value == "" {
+		return 0, nil
+	}
+
+	number, ok := utils.ParseBrazilianNumber(value)
+	if !ok {
+		return 0, fmt.Errorf("not a number: %q", raw)
+	}
+	return number, nil
+}
@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dujoseaugusto/go-crawler-project/internal/ai"
@@ -23,7 +24,25 @@ type PropertyService struct {
 	config         *config.Config
 	logger         *logger.Logger
 	patternLearner *crawler.PatternLearner
+	patternImports *crawler.PatternImportQueue
 	contentLearner *crawler.ContentBasedPatternLearner
+	configWatcher  *config.ConfigWatcher
+	shutdown       *crawler.ShutdownCoordinator
+	jobs           *crawler.CrawlJobManager
+	throttle       *crawler.DomainThrottle          // compartilhado entre crawlings: atraso aprendido com 429/503
+	crawlErrors    *crawler.ErrorTracker            // erros por tipo/domínio acumulados entre crawlings
+	alerts         []AlertNotifier                  // destinos dos alertas de queda no volume e de cidades desatualizadas
+	feeds          *crawler.FeedIngester            // anúncios novos vindos de feeds RSS/Atom, crawleados com prioridade
+	exchangeRates  *repository.DailyExchangeRates   // cotações do dia para ?currency= (nil desativa)
+	explainer      *crawler.ClassificationExplainer // explicação das decisões de classificação (GET /debug/classify)
+	requestAudit   *crawler.RequestAuditLog         // auditoria das requisições de saída (nil desativa)
+	photoProxy     *crawler.PhotoProxy              // fotos dos anúncios servidas pela API (GET /properties/{id}/photos/{n})
+	leader         *LeaderElector                   // eleição entre réplicas: só o líder executa as tarefas agendadas (nil: sempre executa)
+	skipPaths      repository.SkipPathRepository    // acumulado dos caminhos sem anúncios por domínio (nil: não aprende)
+	coverageMutex  sync.RWMutex
+	lastCoverage   []repository.DomainCoverage
+	slaMutex       sync.Mutex
+	slaAlerted     map[string]time.Time // cidade -> última atualização já alertada (um alerta por atraso)
 }
 
 // CleanupOptions define as opções para limpeza do banco
@@ -49,8 +68,14 @@ func NewPropertyService(repo repository.PropertyRepository, urlRepo repository.U
 		citySitesRepo:  nil, // Será inicializado quando necessário
 		config:         cfg,
 		logger:         logger.NewLogger("property_service"),
+		alerts:         newAlertNotifiers(cfg),
 		patternLearner: nil, // Será definido quando necessário
 		contentLearner: nil, // Será definido quando necessário
+		jobs:           crawler.NewCrawlJobManager(),
+		throttle:       newSharedThrottle(cfg),
+		crawlErrors:    crawler.NewErrorTracker(),
+		feeds:          crawler.NewFeedIngester(urlRepo),
+		explainer:      crawler.NewClassificationExplainer(nil, nil),
 	}
 }
 
@@ -62,8 +87,14 @@ func NewPropertyServiceWithCitySites(repo repository.PropertyRepository, urlRepo
 		citySitesRepo:  citySitesRepo,
 		config:         cfg,
 		logger:         logger.NewLogger("property_service"),
+		alerts:         newAlertNotifiers(cfg),
 		patternLearner: nil, // Será definido quando necessário
 		contentLearner: nil, // Será definido quando necessário
+		jobs:           crawler.NewCrawlJobManager(),
+		throttle:       newSharedThrottle(cfg),
+		crawlErrors:    crawler.NewErrorTracker(),
+		feeds:          crawler.NewFeedIngester(urlRepo),
+		explainer:      crawler.NewClassificationExplainer(nil, nil),
 	}
 }
 
@@ -73,6 +104,120 @@ func (s *PropertyService) SetPatternLearner(patternLearner *crawler.PatternLearn
 	s.logger.Info("PatternLearner set for intelligent crawling")
 }
 
+// SetPatternImports define a fila de aprovação das importações de padrões de URL
+func (s *PropertyService) SetPatternImports(imports *crawler.PatternImportQueue) {
+	s.patternImports = imports
+}
+
+// SetIdempotencyStore guarda as chaves de idempotência dos disparos de crawling no store informado
+func (s *PropertyService) SetIdempotencyStore(store repository.IdempotencyKeyRepository) {
+	s.jobs.SetIdempotencyStore(store)
+}
+
+// PatternImports retorna a fila de aprovação das importações de padrões (nil se não configurada)
+func (s *PropertyService) PatternImports() *crawler.PatternImportQueue {
+	return s.patternImports
+}
+
+// SetConfigWatcher define o observador de configuração aplicado aos crawlers em execução
+func (s *PropertyService) SetConfigWatcher(watcher *config.ConfigWatcher) {
+	s.configWatcher = watcher
+	s.logger.Info("Config watcher set for runtime configuration reload")
+}
+
+// GetConfigChanges retorna as alterações de configuração aplicadas pelos recarregamentos (arquivo
+// de runtime e configurações por domínio), da mais recente para a mais antiga, até limit
+func (s *PropertyService) GetConfigChanges(limit int) []config.ConfigChange {
+	changes := []config.ConfigChange{}
+	if s.configWatcher == nil {
+		return changes
+	}
+	auditLog := s.configWatcher.AuditLog()
+	for i := len(auditLog) - 1; i >= 0 && len(changes) < limit; i-- {
+		changes = append(changes, auditLog[i])
+	}
+	return changes
+}
+
+// SetShutdownCoordinator define o coordenador de encerramento usado pelos crawlings em execução
+func (s *PropertyService) SetShutdownCoordinator(shutdown *crawler.ShutdownCoordinator) {
+	s.shutdown = shutdown
+}
+
+// SetExchangeRates define as cotações usadas para exibir os preços em outras moedas
+func (s *PropertyService) SetExchangeRates(rates *repository.DailyExchangeRates) {
+	s.exchangeRates = rates
+}
+
+// SetRequestAuditLog ativa o registro de auditoria das requisições HTTP feitas pelos crawlings
+func (s *PropertyService) SetRequestAuditLog(audit *crawler.RequestAuditLog) {
+	s.requestAudit = audit
+	crawler.SetRequestAuditLog(audit)
+}
+
+// RequestAuditEnabled indica se o registro de auditoria das requisições está ativo
+func (s *PropertyService) RequestAuditEnabled() bool {
+	return s.requestAudit != nil
+}
+
+// FindRequestAudits retorna as requisições registradas, da mais recente à mais antiga; os
+// registros pendentes são gravados antes da consulta
+func (s *PropertyService) FindRequestAudits(ctx context.Context, filter repository.RequestAuditFilter) ([]repository.RequestAuditEntry, error) {
+	if s.requestAudit == nil {
+		return nil, errors.New("request audit not enabled (REQUEST_AUDIT)")
+	}
+	s.requestAudit.Flush(ctx)
+	return s.requestAudit.Repository().FindRequestAudits(ctx, filter)
+}
+
+// SummarizeRequestAudits resume por domínio as requisições registradas que passam pelo filtro
+func (s *PropertyService) SummarizeRequestAudits(ctx context.Context, filter repository.RequestAuditFilter) ([]repository.RequestAuditSummary, error) {
+	entries, err := s.FindRequestAudits(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return repository.SummarizeRequestAudits(entries), nil
+}
+
+// SetClassificationExplainer substitui o explicador de classificação (ex: com IA e curvas de calibração)
+func (s *PropertyService) SetClassificationExplainer(explainer *crawler.ClassificationExplainer) {
+	s.explainer = explainer
+}
+
+// ConvertPrices prepara os imóveis para a moeda pedida (?currency=USD): mantém apenas o valor
+// nessa moeda e converte com as cotações do dia os imóveis salvos antes da conversão existir
+func (s *PropertyService) ConvertPrices(ctx context.Context, properties []repository.Property, currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if s.exchangeRates == nil {
+		return fmt.Errorf("conversão de moedas desativada (configure EXCHANGE_RATES_URL)")
+	}
+	if !s.exchangeRates.Supports(currency) {
+		return fmt.Errorf("moeda não suportada: %s (disponíveis: %s)", currency, strings.Join(s.exchangeRates.Currencies(), ", "))
+	}
+
+	var rates *repository.ExchangeRates
+	for i := range properties {
+		property := &properties[i]
+		value, converted := property.ValorConvertido[currency]
+		if !converted && property.Valor > 0 {
+			if rates == nil {
+				var err error
+				if rates, err = s.exchangeRates.Rates(ctx); err != nil {
+					return fmt.Errorf("failed to load exchange rates: %v", err)
+				}
+			}
+			repository.ApplyExchangeRates(property, rates)
+			value, converted = property.ValorConvertido[currency]
+		}
+
+		property.ValorConvertido = nil
+		if converted {
+			property.ValorConvertido = map[string]float64{currency: value}
+		}
+	}
+	return nil
+}
+
 // SetContentLearner define o ContentBasedPatternLearner treinado para uso no crawling
 func (s *PropertyService) SetContentLearner(contentLearner *crawler.ContentBasedPatternLearner) {
 	s.contentLearner = contentLearner
@@ -90,12 +235,732 @@ func (s *PropertyService) GetAllProperties(ctx context.Context) ([]repository.Pr
 	return s.repo.FindAll(ctx)
 }
 
+// GetPropertiesAsOf reconstrói o acervo como estava na data informada (histórico de versões)
+func (s *PropertyService) GetPropertiesAsOf(ctx context.Context, asOf time.Time) ([]repository.Property, error) {
+	historyRepo, ok := s.repo.(repository.PropertyHistoryRepository)
+	if !ok {
+		return nil, errors.New("as-of queries not supported by property repository")
+	}
+	return historyRepo.FindAsOf(ctx, asOf)
+}
+
 func (s *PropertyService) SearchProperties(ctx context.Context, filter repository.PropertyFilter, pagination repository.PaginationParams) (*repository.PropertySearchResult, error) {
 	return s.repo.FindWithFilters(ctx, filter, pagination)
 }
 
+// GetPropertyFacets retorna as contagens por bairro, tipo, faixa de preço e quartos da cidade
+func (s *PropertyService) GetPropertyFacets(ctx context.Context, cidade string) (*repository.PropertyFacets, error) {
+	facetRepo, ok := s.repo.(repository.FacetRepository)
+	if !ok {
+		return nil, errors.New("facets not supported by property repository")
+	}
+	return facetRepo.Facets(ctx, cidade)
+}
+
+// GetPriceIndex retorna a série mensal da mediana do R$/m² da cidade ou do bairro
+func (s *PropertyService) GetPriceIndex(ctx context.Context, cidade, bairro string) ([]repository.PriceIndexPoint, error) {
+	indexRepo, ok := s.repo.(repository.PriceIndexRepository)
+	if !ok {
+		return nil, errors.New("price index not supported by property repository")
+	}
+	return indexRepo.FindPriceIndex(ctx, cidade, bairro)
+}
+
+// GetWhatsAppLeads lista os leads de baixa confiança lidos dos links de WhatsApp dos catálogos
+func (s *PropertyService) GetWhatsAppLeads(ctx context.Context, filter repository.WhatsAppLeadFilter) ([]repository.WhatsAppLead, error) {
+	leadRepo, ok := s.repo.(repository.WhatsAppLeadRepository)
+	if !ok {
+		return nil, errors.New("whatsapp leads not supported by property repository")
+	}
+	return leadRepo.FindWhatsAppLeads(ctx, filter)
+}
+
+// GetBairroBoundaries infere os limites aproximados dos bairros da cidade a partir dos anúncios
+// geocodificados (bairros com menos de minListings anúncios ficam de fora)
+func (s *PropertyService) GetBairroBoundaries(ctx context.Context, cidade string, minListings int) (*repository.GeoJSONFeatureCollection, error) {
+	boundaryRepo, ok := s.repo.(repository.BairroBoundaryRepository)
+	if !ok {
+		return nil, errors.New("bairro boundaries not supported by property repository")
+	}
+	return boundaryRepo.InferBairroBoundaries(ctx, cidade, minListings)
+}
+
+// GetDuplicateReport lista os grupos de anúncios do mesmo imóvel publicados por imobiliárias diferentes
+func (s *PropertyService) GetDuplicateReport(ctx context.Context, cidade string, opts repository.DuplicateReportOptions) ([]repository.DuplicateCluster, error) {
+	reportRepo, ok := s.repo.(repository.DuplicateReportRepository)
+	if !ok {
+		return nil, errors.New("duplicate report not supported by property repository")
+	}
+	return reportRepo.FindDuplicateClusters(ctx, cidade, opts)
+}
+
+// MergeDuplicates funde os anúncios duplicados da cidade entre portais em registros canônicos
+func (s *PropertyService) MergeDuplicates(ctx context.Context, cidade string, opts repository.DuplicateReportOptions) (*repository.CanonicalMergeResult, error) {
+	canonicalRepo, ok := s.repo.(repository.CanonicalPropertyRepository)
+	if !ok {
+		return nil, errors.New("canonical merge not supported by property repository")
+	}
+
+	result, err := canonicalRepo.MergeDuplicateProperties(ctx, cidade, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"cidade":  cidade,
+		"merged":  result.Merged,
+		"sources": result.Sources,
+		"removed": result.Removed,
+	}).Info("Duplicate listings merged into canonical records")
+	return result, nil
+}
+
+// MergeAllDuplicates funde as duplicatas de todas as cidades com imóveis. Uma cidade com erro não
+// interrompe as demais; o primeiro erro é retornado no fim
+func (s *PropertyService) MergeAllDuplicates(ctx context.Context) ([]repository.CanonicalMergeResult, error) {
+	canonicalRepo, ok := s.repo.(repository.CanonicalPropertyRepository)
+	if !ok {
+		return nil, errors.New("canonical merge not supported by property repository")
+	}
+
+	cities, err := canonicalRepo.FindPropertyCities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []repository.CanonicalMergeResult
+	var firstErr error
+	for _, city := range cities {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		result, err := s.MergeDuplicates(ctx, city, repository.DuplicateReportOptions{})
+		if err != nil {
+			s.logger.WithError(err).WithField("cidade", city).Warn("Canonical merge failed")
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		results = append(results, *result)
+	}
+	return results, firstErr
+}
+
+// StartCanonicalMergeJob refaz periodicamente a fusão das duplicatas de todas as cidades, para os
+// registros canônicos acompanharem os novos anúncios, até o contexto ser cancelado
+func (s *PropertyService) StartCanonicalMergeJob(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	if _, ok := s.repo.(repository.CanonicalPropertyRepository); !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !s.leader.IsLeader() {
+				s.logger.Debug("Not the leader, skipping scheduled canonical merge")
+			} else if _, err := s.MergeAllDuplicates(ctx); err != nil {
+				s.logger.WithError(err).Warn("Scheduled canonical merge failed")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// GetCanonicalProperties lista os registros canônicos da cidade (todas se vazia)
+func (s *PropertyService) GetCanonicalProperties(ctx context.Context, cidade string, limit int) ([]repository.CanonicalProperty, error) {
+	canonicalRepo, ok := s.repo.(repository.CanonicalPropertyRepository)
+	if !ok {
+		return nil, errors.New("canonical merge not supported by property repository")
+	}
+	return canonicalRepo.FindCanonicalProperties(ctx, cidade, limit)
+}
+
+// GetCanonicalProperty retorna o registro canônico com os anúncios de origem
+func (s *PropertyService) GetCanonicalProperty(ctx context.Context, id string) (*repository.CanonicalProperty, error) {
+	canonicalRepo, ok := s.repo.(repository.CanonicalPropertyRepository)
+	if !ok {
+		return nil, errors.New("canonical merge not supported by property repository")
+	}
+	return canonicalRepo.FindCanonicalProperty(ctx, id)
+}
+
+// DetectPhotoFraud compara as fotos dos anúncios e envia os grupos suspeitos à fila de revisão
+func (s *PropertyService) DetectPhotoFraud(ctx context.Context) (*repository.PhotoFraudScan, error) {
+	fraudRepo, ok := s.repo.(repository.PhotoFraudRepository)
+	if !ok {
+		return nil, errors.New("photo fraud detection not supported by property repository")
+	}
+
+	scan, err := fraudRepo.DetectPhotoFraud(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"groups":  scan.Groups,
+		"flagged": scan.Flagged,
+	}).Info("Photo fraud detection completed")
+	return scan, nil
+}
+
+// GetFraudReviews lista a fila de revisão de anúncios com fotos repetidas
+func (s *PropertyService) GetFraudReviews(ctx context.Context, status string, limit int) ([]repository.FraudReview, error) {
+	fraudRepo, ok := s.repo.(repository.PhotoFraudRepository)
+	if !ok {
+		return nil, errors.New("photo fraud detection not supported by property repository")
+	}
+	return fraudRepo.FindFraudReviews(ctx, status, limit)
+}
+
+// ResolveFraudReview registra a decisão do revisor (confirmed ou dismissed)
+func (s *PropertyService) ResolveFraudReview(ctx context.Context, id, status string) (*repository.FraudReview, error) {
+	fraudRepo, ok := s.repo.(repository.PhotoFraudRepository)
+	if !ok {
+		return nil, errors.New("photo fraud detection not supported by property repository")
+	}
+	return fraudRepo.ResolveFraudReview(ctx, id, status)
+}
+
+// SetPhotoProxy define o proxy das fotos dos anúncios
+func (s *PropertyService) SetPhotoProxy(proxy *crawler.PhotoProxy) {
+	s.photoProxy = proxy
+}
+
+// SetLeaderElector define a eleição de líder que restringe as tarefas agendadas a uma réplica
+func (s *PropertyService) SetLeaderElector(elector *LeaderElector) {
+	s.leader = elector
+}
+
+// SetSkipPathStore ativa o aprendizado dos caminhos que nunca levam a anúncios, gravados na
+// configuração de cada domínio
+func (s *PropertyService) SetSkipPathStore(store repository.SkipPathRepository) {
+	s.skipPaths = store
+}
+
+// GetLeaderStatus retorna o estado da eleição de líder; desativada, toda réplica é líder
+func (s *PropertyService) GetLeaderStatus(ctx context.Context) (*LeaderStatus, error) {
+	if s.leader == nil {
+		return &LeaderStatus{Leader: true}, nil
+	}
+	return s.leader.Status(ctx)
+}
+
+// FindPropertyByShortID retorna o imóvel pelo ID curto (ErrPropertyNotFound se não existir)
+func (s *PropertyService) FindPropertyByShortID(ctx context.Context, shortID string) (*repository.Property, error) {
+	lookup, ok := s.repo.(repository.PropertyShortIDRepository)
+	if !ok {
+		return nil, errors.New("property lookup by short id not supported by property repository")
+	}
+	return lookup.FindByShortID(ctx, shortID)
+}
+
+// KnownCrawlURL indica se a URL pode ser aberta pelo redirecionamento do ID curto: http(s) em um
+// domínio crawleado (sites das cidades, arquivo de sites ou configuração por domínio) ou em um
+// subdomínio dele. Imóveis importados trazem qualquer URL, e o redirecionamento não pode levar a
+// outro site
+func (s *PropertyService) KnownCrawlURL(ctx context.Context, rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return false
+	}
+	domain := repository.NormalizeDomain(parsed.Hostname())
+
+	var seeds []string
+	if s.citySitesRepo != nil {
+		if sites, err := s.citySitesRepo.GetAllActiveSites(ctx); err == nil {
+			seeds = append(seeds, sites...)
+		} else {
+			s.logger.WithError(err).Warn("Failed to load city sites for short id redirect")
+		}
+	}
+	if s.config.SitesFile != "" {
+		if sites, err := crawler.LoadURLsFromFile(s.config.SitesFile); err == nil {
+			seeds = append(seeds, sites...)
+		}
+	}
+	if s.configWatcher != nil {
+		if snapshot := s.configWatcher.Current(); snapshot != nil {
+			for name := range snapshot.Domains {
+				seeds = append(seeds, name)
+			}
+		}
+	}
+
+	for _, seed := range seeds {
+		known := repository.NormalizeDomain(seed)
+		if known != "" && (domain == known || strings.HasSuffix(domain, "."+known)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertyPhoto entrega a foto n (a partir de 0) do imóvel pelo proxy de fotos
+func (s *PropertyService) PropertyPhoto(ctx context.Context, id string, n int) (*crawler.ProxiedPhoto, error) {
+	if s.photoProxy == nil {
+		return nil, errors.New("photo proxy not configured")
+	}
+	lookup, ok := s.repo.(repository.PropertyIDLookupRepository)
+	if !ok {
+		return nil, errors.New("property lookup by id not supported by property repository")
+	}
+
+	property, err := lookup.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n >= len(property.FotoURLs) {
+		return nil, fmt.Errorf("%w: property %s has %d photos", repository.ErrPropertyNotFound, id, len(property.FotoURLs))
+	}
+	return s.photoProxy.Fetch(ctx, property.FotoURLs[n], property.URL)
+}
+
+// CorrectProperty corrige manualmente campos do imóvel; os campos corrigidos não são mais
+// sobrescritos pelo crawling e a alteração fica registrada no histórico
+func (s *PropertyService) CorrectProperty(ctx context.Context, id string, patch repository.PropertyPatch, correctedBy, reason string) (*repository.Property, *repository.PropertyCorrection, error) {
+	correctionRepo, ok := s.repo.(repository.PropertyCorrectionRepository)
+	if !ok {
+		return nil, nil, errors.New("property corrections not supported by property repository")
+	}
+	if patch.IsEmpty() {
+		return nil, nil, fmt.Errorf("%w: no fields to correct", repository.ErrInvalidPropertyPatch)
+	}
+
+	property, correction, err := correctionRepo.CorrectProperty(ctx, id, patch, correctedBy, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+	if correction != nil {
+		fields := make([]string, 0, len(correction.Changes))
+		for _, change := range correction.Changes {
+			fields = append(fields, change.Field)
+		}
+		s.logger.WithFields(map[string]interface{}{
+			"property_id":  id,
+			"fields":       fields,
+			"corrected_by": correctedBy,
+		}).Info("Property manually corrected")
+	}
+	return property, correction, nil
+}
+
+// GetPropertyCorrections retorna o histórico de correções manuais do imóvel
+func (s *PropertyService) GetPropertyCorrections(ctx context.Context, id string) ([]repository.PropertyCorrection, error) {
+	correctionRepo, ok := s.repo.(repository.PropertyCorrectionRepository)
+	if !ok {
+		return nil, errors.New("property corrections not supported by property repository")
+	}
+	return correctionRepo.FindPropertyCorrections(ctx, id)
+}
+
+// BrokenPhotos lista as fotos que a origem não entregou ao proxy de fotos
+func (s *PropertyService) BrokenPhotos() []crawler.BrokenPhoto {
+	if s.photoProxy == nil {
+		return nil
+	}
+	return s.photoProxy.BrokenPhotos()
+}
+
+// ComputePriceIndex recalcula o índice de preços do mês corrente
+func (s *PropertyService) ComputePriceIndex(ctx context.Context) ([]repository.PriceIndexPoint, error) {
+	indexRepo, ok := s.repo.(repository.PriceIndexRepository)
+	if !ok {
+		return nil, errors.New("price index not supported by property repository")
+	}
+
+	points, err := indexRepo.ComputePriceIndex(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithField("points", len(points)).Info("Price index computed")
+	return points, nil
+}
+
+// StartPriceIndexJob recalcula o índice de preços periodicamente até o contexto ser cancelado
+func (s *PropertyService) StartPriceIndexJob(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	if _, ok := s.repo.(repository.PriceIndexRepository); !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !s.leader.IsLeader() {
+				s.logger.Debug("Not the leader, skipping scheduled price index computation")
+			} else if _, err := s.ComputePriceIndex(ctx); err != nil {
+				s.logger.WithError(err).Warn("Price index computation failed")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// StartCrawlJobCleanup remove periodicamente os jobs finalizados há mais de retention até o
+// contexto ser cancelado
+func (s *PropertyService) StartCrawlJobCleanup(ctx context.Context, interval, retention time.Duration) {
+	if interval <= 0 || retention <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !s.leader.IsLeader() {
+				s.logger.Debug("Not the leader, skipping scheduled crawl job cleanup")
+				continue
+			}
+			s.jobs.CleanupOldJobs(retention)
+		}
+	}()
+}
+
+// QualityReport reúne as métricas de qualidade mais recentes de cada domínio e a série diária
+type QualityReport struct {
+	Domains []repository.DomainQuality `json:"domains"`
+	Trend   []repository.DomainQuality `json:"trend"`
+}
+
+// GetQualityReport retorna as métricas de qualidade dos últimos days dias, de todos os domínios
+// ou apenas do informado; sem métricas gravadas, calcula as do dia
+func (s *PropertyService) GetQualityReport(ctx context.Context, domain string, days int) (*QualityReport, error) {
+	qualityRepo, ok := s.repo.(repository.QualityMetricsRepository)
+	if !ok {
+		return nil, errors.New("quality metrics not supported by property repository")
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	trend, err := qualityRepo.FindQualityMetrics(ctx, domain, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(trend) == 0 {
+		if _, err := s.ComputeQualityMetrics(ctx); err != nil {
+			return nil, err
+		}
+		if trend, err = qualityRepo.FindQualityMetrics(ctx, domain, since); err != nil {
+			return nil, err
+		}
+	}
+	return &QualityReport{Domains: repository.LatestQualityMetrics(trend), Trend: trend}, nil
+}
+
+// ComputeQualityMetrics recalcula as métricas de qualidade do dia
+func (s *PropertyService) ComputeQualityMetrics(ctx context.Context) ([]repository.DomainQuality, error) {
+	qualityRepo, ok := s.repo.(repository.QualityMetricsRepository)
+	if !ok {
+		return nil, errors.New("quality metrics not supported by property repository")
+	}
+
+	metrics, err := qualityRepo.ComputeQualityMetrics(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithField("domains", len(metrics)).Info("Quality metrics computed")
+	return metrics, nil
+}
+
+// StartQualityMetricsJob recalcula as métricas de qualidade periodicamente até o contexto ser cancelado
+func (s *PropertyService) StartQualityMetricsJob(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	if _, ok := s.repo.(repository.QualityMetricsRepository); !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !s.leader.IsLeader() {
+				s.logger.Debug("Not the leader, skipping scheduled quality metrics computation")
+			} else if _, err := s.ComputeQualityMetrics(ctx); err != nil {
+				s.logger.WithError(err).Warn("Quality metrics computation failed")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// newSharedThrottle cria o limitador compartilhado entre crawlings, com o orçamento global
+// de páginas por minuto quando configurado (CRAWL_PAGES_PER_MINUTE)
+func newSharedThrottle(cfg *config.Config) *crawler.DomainThrottle {
+	throttle := crawler.NewDomainThrottle()
+	if cfg != nil && cfg.CrawlPagesPerMinute > 0 {
+		throttle.SetGlobalRateController(crawler.NewGlobalRateController(cfg.CrawlPagesPerMinute, cfg.CrawlMaxDomainParallelism))
+	}
+	return throttle
+}
+
+// GetGlobalRate retorna o estado do orçamento global de páginas por minuto (nil se desativado)
+func (s *PropertyService) GetGlobalRate() *crawler.GlobalRateStats {
+	return s.throttle.GlobalRate()
+}
+
+// GetDomainRates retorna o atraso efetivo por domínio (configurado + ajuste adaptativo por 429/503)
+func (s *PropertyService) GetDomainRates() []crawler.DomainRateStats {
+	return s.throttle.Stats()
+}
+
+// GetCrawlErrors retorna os erros de crawling agregados por tipo e domínio, com amostras recentes
+func (s *PropertyService) GetCrawlErrors(errorType, domain string, limit int) *crawler.ErrorReport {
+	return s.crawlErrors.Report(errorType, domain, limit)
+}
+
+// GetAITokenSavings retorna os tokens economizados pela sanitização do conteúdo enviado à IA
+func (s *PropertyService) GetAITokenSavings() ai.SanitizerStats {
+	return ai.GetSanitizerStats()
+}
+
+// ProbeDomain diagnostica DNS, TCP, TLS e HTTP do domínio a partir do ambiente do crawler
+func (s *PropertyService) ProbeDomain(ctx context.Context, domain string) *crawler.DomainProbe {
+	return crawler.NewDomainProber().Probe(ctx, domain)
+}
+
+// GetDNSCacheStats retorna o uso do cache de DNS dos crawlers e os hosts com falhas de resolução
+func (s *PropertyService) GetDNSCacheStats() (crawler.DNSCacheStats, error) {
+	cache := crawler.CurrentDNSCache()
+	if cache == nil {
+		return crawler.DNSCacheStats{}, errors.New("dns cache disabled")
+	}
+	return cache.Stats(), nil
+}
+
+// ExplainClassification baixa a URL e explica cada etapa da classificação (padrões, heurísticas,
+// IA) e a decisão do engine informado
+func (s *PropertyService) ExplainClassification(ctx context.Context, rawURL, engine string) (*crawler.ClassificationExplanation, error) {
+	return s.explainer.Explain(ctx, rawURL, engine, s.patternLearner)
+}
+
+// GetURLStorageStats retorna o tamanho das coleções de URLs processadas e fingerprints
+func (s *PropertyService) GetURLStorageStats(ctx context.Context) (*repository.URLStorageStats, error) {
+	mongoURLRepo, ok := repository.MongoURLBackend(s.urlRepo)
+	if !ok {
+		return nil, errors.New("storage stats not supported by URL repository")
+	}
+	return mongoURLRepo.GetStorageStats(ctx)
+}
+
+// PruneURLStorage aplica imediatamente os limites de tamanho das coleções de URLs
+func (s *PropertyService) PruneURLStorage(ctx context.Context) (*repository.PruneResult, error) {
+	mongoURLRepo, ok := repository.MongoURLBackend(s.urlRepo)
+	if !ok {
+		return nil, errors.New("pruning not supported by URL repository")
+	}
+	return mongoURLRepo.PruneToLimits(ctx)
+}
+
+// defaultIdempotencyWindow é a janela das chaves de idempotência sem CRAWL_IDEMPOTENCY_WINDOW
+const defaultIdempotencyWindow = 24 * time.Hour
+
+// StartCrawlJob registra um job de crawling e o executa em segundo plano; os imóveis salvos
+// recebem as tags do job. O modo escolhe o engine: incremental roda o engine incremental e
+// qualquer outro valor, o crawler recursivo (full)
+func (s *PropertyService) StartCrawlJob(cities []string, mode string, tags []string) *repository.CrawlJob {
+	if mode != repository.CrawlModeIncremental {
+		mode = repository.CrawlModeFull
+	}
+	return s.startJob(cities, mode, tags, func(ctx context.Context, jobID string) error {
+		return s.forceCrawling(ctx, repository.CrawlJobTemplate{Cities: cities, Mode: mode}, jobID)
+	})
+}
+
+// StartJobOnce dispara o job com start apenas na primeira vez que a chave de idempotência
+// aparece dentro de CRAWL_IDEMPOTENCY_WINDOW; repetições com o mesmo fingerprint dos parâmetros
+// retornam o job original (replayed=true). Sem chave, start é sempre executado.
+func (s *PropertyService) StartJobOnce(key, fingerprint string, start func() (*repository.CrawlJob, error)) (job *repository.CrawlJob, replayed bool, err error) {
+	if key == "" {
+		job, err = start()
+		return job, false, err
+	}
+
+	window := defaultIdempotencyWindow
+	if s.config != nil && s.config.CrawlIdempotencyWindow > 0 {
+		window = s.config.CrawlIdempotencyWindow
+	}
+	job, replayed, err = s.jobs.CreateOnce(key, fingerprint, window, start)
+	if replayed {
+		s.logger.WithFields(map[string]interface{}{
+			"job_id":          job.ID,
+			"idempotency_key": key,
+		}).Info("Repeated idempotency key, returning original crawl job")
+	}
+	return job, replayed, err
+}
+
+// startJob executa run em segundo plano como um job de crawling acompanhado pela API
+func (s *PropertyService) startJob(cities []string, mode string, tags []string, run func(ctx context.Context, jobID string) error) *repository.CrawlJob {
+	job, _ := s.jobs.CreateJob(cities, mode, tags)
+	return s.runJob(job, mode, run)
+}
+
+// runJob executa run em segundo plano para o job já registrado
+func (s *PropertyService) runJob(job *repository.CrawlJob, mode string, run func(ctx context.Context, jobID string) error) *repository.CrawlJob {
+	go func() {
+		jobTags := map[string]string{"job_id": job.ID, "mode": mode}
+		defer func() {
+			// Um panic no job não derruba a API: o job é marcado como falho e reportado
+			if r := recover(); r != nil {
+				logger.CapturePanic(r, "property_service", jobTags)
+				s.jobs.MarkFinished(job.ID, fmt.Errorf("panic: %v", r))
+				s.logger.WithField("job_id", job.ID).Errorf("Crawl job panicked: %v", r)
+			}
+		}()
+
+		// Contexto independente: o job não deve ser cancelado quando a requisição HTTP terminar
+		s.jobs.MarkRunning(job.ID)
+		started := time.Now()
+		err := run(context.Background(), job.ID)
+		s.jobs.MarkFinished(job.ID, err)
+		s.recordJobCost(job.ID, time.Since(started))
+
+		if err != nil {
+			s.logger.WithField("job_id", job.ID).Report("Crawl job failed", err, jobTags)
+		} else {
+			s.logger.WithField("job_id", job.ID).Info("Crawl job finished")
+		}
+	}()
+
+	return job
+}
+
+// recordJobCost anexa ao job terminado a estimativa de custo e a grava quando o repositório
+// suporta; o consumo da IA é só o das chamadas feitas pelo próprio job
+func (s *PropertyService) recordJobCost(jobID string, duration time.Duration) {
+	job := s.jobs.GetJob(jobID)
+	control := s.jobs.Control(jobID)
+	if job == nil || control == nil {
+		return
+	}
+
+	var rates crawler.CostRates
+	if s.config != nil {
+		rates = crawler.CostRates{
+			AIInputPerMillion:  s.config.AIInputPricePerMillion,
+			AIOutputPerMillion: s.config.AIOutputPricePerMillion,
+			ComputePerHour:     s.config.CrawlComputePricePerHour,
+			BandwidthPerGB:     s.config.CrawlBandwidthPricePerGB,
+			ProxyPerGB:         s.config.CrawlProxyPricePerGB,
+		}
+	}
+	cost := crawler.EstimateJobCost(job, control.Usage(), duration, rates)
+	s.jobs.SetCost(jobID, cost)
+
+	costRepo, ok := s.repo.(repository.CrawlJobCostRepository)
+	if !ok {
+		return
+	}
+	if err := costRepo.SaveCrawlJobCost(context.Background(), *cost); err != nil {
+		s.logger.WithField("job_id", jobID).WithError(err).Warn("Failed to save crawl job cost")
+	}
+}
+
+// GetCrawlJobCosts retorna os custos dos jobs do filtro e o resumo por modo
+func (s *PropertyService) GetCrawlJobCosts(ctx context.Context, filter repository.CrawlJobCostFilter) ([]repository.CrawlJobCost, []repository.CrawlCostSummary, error) {
+	costRepo, ok := s.repo.(repository.CrawlJobCostRepository)
+	if !ok {
+		return nil, nil, errors.New("crawl job costs not supported by property repository")
+	}
+	costs, err := costRepo.FindCrawlJobCosts(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return costs, repository.SummarizeCrawlJobCosts(costs), nil
+}
+
+// GetClassifierExperiments retorna os relatórios dos experimentos do classificador dos jobs em
+// memória, do mais recente para o mais antigo (jobID opcional filtra um job)
+func (s *PropertyService) GetClassifierExperiments(jobID string) []repository.ClassifierExperimentReport {
+	reports := []repository.ClassifierExperimentReport{}
+	for _, job := range s.jobs.GetJobs() {
+		if job.ClassifierExperiment == nil || (jobID != "" && job.ID != jobID) {
+			continue
+		}
+		report := *job.ClassifierExperiment
+		report.JobID = job.ID
+		report.CompletedAt = job.CompletedAt
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// GetCrawlJob retorna o estado de um job de crawling
+func (s *PropertyService) GetCrawlJob(jobID string) (*repository.CrawlJob, error) {
+	job := s.jobs.GetJob(jobID)
+	if job == nil {
+		return nil, fmt.Errorf("%w: %s", crawler.ErrCrawlJobNotFound, jobID)
+	}
+	return job, nil
+}
+
+// GetCrawlJobs retorna todos os jobs de crawling conhecidos
+func (s *PropertyService) GetCrawlJobs() []*repository.CrawlJob {
+	return s.jobs.GetJobs()
+}
+
+// CancelCrawlJob interrompe novas visitas do job; requisições em andamento terminam normalmente
+func (s *PropertyService) CancelCrawlJob(jobID string) (*repository.CrawlJob, error) {
+	return s.jobs.CancelJob(jobID)
+}
+
+// PauseCrawlJobDomain pausa novas visitas a um domínio dentro do job
+func (s *PropertyService) PauseCrawlJobDomain(jobID, domain string) (*repository.CrawlJob, error) {
+	return s.jobs.PauseDomain(jobID, domain, false)
+}
+
+// ResumeCrawlJobDomain retoma as visitas a um domínio pausado
+func (s *PropertyService) ResumeCrawlJobDomain(jobID, domain string) (*repository.CrawlJob, error) {
+	return s.jobs.PauseDomain(jobID, domain, true)
+}
+
 // ForceCrawling inicia manualmente o processo de coleta de dados usando o sistema incremental
 func (s *PropertyService) ForceCrawling(ctx context.Context, cities []string) error {
+	return s.forceCrawling(ctx, repository.CrawlJobTemplate{Cities: cities}, "")
+}
+
+// GetLatestCoverage retorna a estimativa de cobertura por domínio do último crawling concluído
+func (s *PropertyService) GetLatestCoverage() []repository.DomainCoverage {
+	s.coverageMutex.RLock()
+	defer s.coverageMutex.RUnlock()
+	return s.lastCoverage
+}
+
+// forceCrawling executa o crawling com os parâmetros informados (cidades, domínios e IA),
+// opcionalmente controlado por um job (cancelamento/pausa/orçamento)
+func (s *PropertyService) forceCrawling(ctx context.Context, params repository.CrawlJobTemplate, jobID string) error {
+	cities := params.Cities
 	s.logger.WithFields(map[string]interface{}{
 		"cities": cities,
 	}).Info("Starting incremental crawling process")
@@ -123,6 +988,7 @@ func (s *PropertyService) ForceCrawling(ctx context.Context, cities []string) er
 
 	// Fallback para sites.json se não conseguiu carregar do banco ou não há sites
 	if len(urls) == 0 {
+		s.logger.Warn(crawler.SitesFileDeprecationWarning)
 		urls, err = s.loadURLsFromFile(s.config.SitesFile)
 		if err != nil {
 			s.logger.Error("Failed to load URLs from file", err)
@@ -131,6 +997,14 @@ func (s *PropertyService) ForceCrawling(ctx context.Context, cities []string) er
 		source = "sites_json_fallback"
 	}
 
+	// Templates podem restringir o crawling a alguns domínios
+	if len(params.Domains) > 0 {
+		urls = filterURLsByDomain(urls, params.Domains)
+		if len(urls) == 0 {
+			return fmt.Errorf("nenhum site ativo para os domínios %v", params.Domains)
+		}
+	}
+
 	s.logger.WithFields(map[string]interface{}{
 		"urls_count": len(urls),
 		"source":     source,
@@ -139,12 +1013,15 @@ func (s *PropertyService) ForceCrawling(ctx context.Context, cities []string) er
 
 	// Inicializar o serviço de IA (opcional)
 	var aiService *ai.GeminiService
-	if aiSvc, err := ai.NewGeminiService(ctx); err == nil {
+	if params.EnableAI != nil && !*params.EnableAI {
+		s.logger.Info("AI service disabled for this crawl")
+	} else if aiSvc, err := ai.NewGeminiService(ctx); err == nil {
 		aiService = aiSvc
 		s.logger.Info("AI service initialized successfully")
 	} else {
 		s.logger.WithError(err).Warn("AI service not available, continuing without AI")
 	}
+	s.jobs.Control(jobID).SetAIEnabled(aiService != nil)
 
 	// Configuração do sistema incremental
 	config := crawler.IncrementalConfig{
@@ -158,15 +1035,64 @@ func (s *PropertyService) ForceCrawling(ctx context.Context, cities []string) er
 		UserAgent:            "Go-Crawler-Incremental/1.0",
 	}
 
+	// Modo incremental (templates): engine com fingerprints e revisita adaptativa. As entradas
+	// dos feeds continuam na fila para o próximo job de feeds ou full, que as marca como vistas
+	// ou as reenfileira; os recursos do crawler recursivo (prioridade dos feeds, aprendizado de
+	// caminhos, PDF, Wayback, leads de WhatsApp e experimento do classificador) só rodam no full
+	if params.Mode == repository.CrawlModeIncremental {
+		return s.incrementalCrawl(ctx, urls, aiService, config, jobID)
+	}
+
 	// USAR CRAWLER RECURSIVO SIMPLES
 	s.logger.Info("Using simple recursive crawler for better navigation and property discovery")
-	simpleCrawler := crawler.NewSimpleRecursiveCrawler(s.repo, s.urlRepo)
+	simpleCrawler, release, err := s.newSimpleCrawler(jobID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Anúncios novos dos feeds entram na fila antes das seeds; os não crawleados voltam para a fila
+	entries := s.feeds.TakePending()
+	simpleCrawler.SetPriorityEntries(entries)
+	defer func() { s.feeds.Complete(entries, simpleCrawler.CrawledPriorityEntries()) }()
 
 	s.logger.Info("Starting simple recursive crawler engine")
 	if err := simpleCrawler.Start(ctx, urls); err != nil {
 		s.logger.Error("Incremental crawler engine failed", err)
+		s.recordCityRefresh(ctx, jobID, urls, err)
 		return fmt.Errorf("erro no crawler incremental: %v", err)
 	}
+	s.recordCityRefresh(ctx, jobID, urls, nil)
+
+	// Guardar a estimativa de cobertura por domínio
+	coverage := simpleCrawler.CoverageReport()
+	if len(coverage) > 0 {
+		s.coverageMutex.Lock()
+		s.lastCoverage = coverage
+		s.coverageMutex.Unlock()
+
+		if jobID != "" {
+			s.jobs.SetCoverage(jobID, coverage)
+		}
+	}
+
+	// Relatório do experimento A/B do classificador, quando ativo
+	if report := simpleCrawler.ClassifierExperimentReport(); report != nil {
+		if jobID != "" {
+			s.jobs.SetClassifierExperiment(jobID, report)
+		}
+		s.logger.WithFields(map[string]interface{}{
+			"experiment":      report.Name,
+			"sampled":         report.Sampled,
+			"divergences":     report.Divergences,
+			"divergence_rate": report.DivergenceRate,
+		}).Info("Classifier experiment completed")
+	}
+
+	// Histórico de volume por domínio e alertas de queda (crawlings cancelados ficam de fora)
+	if control := s.jobs.Control(jobID); control == nil || !control.Cancelled() {
+		s.checkCrawlYield(ctx, jobID, urls, coverage, simpleCrawler.SourceYields())
+	}
 
 	// Log das estatísticas finais (simplificado para o crawler recursivo)
 	s.logger.WithFields(map[string]interface{}{
@@ -182,18 +1108,170 @@ func (s *PropertyService) ForceCrawling(ctx context.Context, cities []string) er
 	return nil
 }
 
-// loadURLsFromFile carrega URLs do arquivo de configuração
-func (s *PropertyService) loadURLsFromFile(filePath string) ([]string, error) {
-	var urls []string
-	data, err := os.ReadFile(filePath)
+// incrementalCrawl executa o engine incremental para os jobs no modo incremental. Páginas sem
+// mudanças são puladas, então o volume por domínio não alimenta os alertas de queda
+func (s *PropertyService) incrementalCrawl(ctx context.Context, urls []string, aiService *ai.GeminiService, config crawler.IncrementalConfig, jobID string) error {
+	s.logger.Info("Using incremental crawler engine")
+	engine, release, err := s.newIncrementalEngine(jobID, aiService, config)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler arquivo %s: %v", filePath, err)
+		return err
 	}
+	defer release()
 
-	if err := json.Unmarshal(data, &urls); err != nil {
-		return nil, fmt.Errorf("erro ao fazer parse do JSON: %v", err)
+	if err := engine.Start(ctx, urls); err != nil {
+		s.logger.Error("Incremental crawler engine failed", err)
+		s.recordCityRefresh(ctx, jobID, urls, err)
+		return fmt.Errorf("erro no crawler incremental: %v", err)
 	}
+	s.recordCityRefresh(ctx, jobID, urls, nil)
+
+	stats := engine.GetStatistics()
+	s.logger.WithFields(map[string]interface{}{
+		"total_urls":     len(urls),
+		"processed_urls": stats.ProcessedURLs,
+		"skipped_urls":   stats.SkippedURLs,
+		"new_properties": stats.NewProperties,
+		"crawler_type":   "incremental",
+	}).Info("Incremental crawling process completed")
+	return nil
+}
 
+// newIncrementalEngine cria o engine incremental ligado ao job, ao runtime config e ao
+// encerramento coordenado; release deve ser chamado ao fim do crawling. Os ganchos por página
+// de newSimpleCrawler não existem no engine incremental
+func (s *PropertyService) newIncrementalEngine(jobID string, aiService *ai.GeminiService, config crawler.IncrementalConfig) (*crawler.IncrementalCrawlerEngine, func(), error) {
+	if s.shutdown != nil && s.shutdown.InProgress() {
+		return nil, nil, fmt.Errorf("crawling não iniciado: aplicação em encerramento")
+	}
+
+	control := s.jobs.Control(jobID)
+	engine := crawler.NewIncrementalCrawlerEngine(repository.NewTaggedRepository(s.repo, control.Tags()), s.urlRepo, aiService, config)
+	engine.SetJobControl(control)
+	engine.SetDomainThrottle(s.throttle)
+	engine.SetErrorTracker(s.crawlErrors)
+	engine.SetPanicSnapshotDir(s.config.PanicSnapshotDir)
+	engine.SetPageLimits(crawler.PageLimits{
+		RequestTimeout: s.config.CrawlRequestTimeout,
+		MaxDuration:    s.config.CrawlPageMaxDuration,
+		MaxBytes:       s.config.CrawlPageMaxBytes,
+	})
+	if s.config.CrawlStrictDomains {
+		engine.SetDomainAllowlist(crawler.NewDomainAllowlist(s.config.CrawlAllowSubdomains))
+	}
+	if frontier := s.sharedFrontier(jobID); frontier != nil {
+		engine.SetSharedFrontier(frontier)
+	}
+
+	var releases []func()
+	if s.configWatcher != nil {
+		s.configWatcher.Register(engine)
+		releases = append(releases, func() { s.configWatcher.Unregister(engine) })
+	}
+	if s.shutdown != nil {
+		releases = append(releases, engine.RegisterShutdown(s.shutdown, s.config.CheckpointFile))
+	}
+
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	return engine, release, nil
+}
+
+// newSimpleCrawler cria o crawler recursivo ligado ao job, ao runtime config e ao encerramento
+// coordenado; release deve ser chamado ao fim do crawling
+func (s *PropertyService) newSimpleCrawler(jobID string) (*crawler.SimpleRecursiveCrawler, func(), error) {
+	if s.shutdown != nil && s.shutdown.InProgress() {
+		return nil, nil, fmt.Errorf("crawling não iniciado: aplicação em encerramento")
+	}
+
+	control := s.jobs.Control(jobID)
+	simpleCrawler := crawler.NewSimpleRecursiveCrawler(repository.NewTaggedRepository(s.repo, control.Tags()), s.urlRepo)
+	simpleCrawler.SetJobControl(control)
+	simpleCrawler.SetDomainThrottle(s.throttle)
+	simpleCrawler.SetErrorTracker(s.crawlErrors)
+	simpleCrawler.SetPanicSnapshotDir(s.config.PanicSnapshotDir)
+	simpleCrawler.SetPageLimits(crawler.PageLimits{
+		RequestTimeout: s.config.CrawlRequestTimeout,
+		MaxDuration:    s.config.CrawlPageMaxDuration,
+		MaxBytes:       s.config.CrawlPageMaxBytes,
+	})
+	if s.config.CrawlStrictDomains {
+		simpleCrawler.SetDomainAllowlist(crawler.NewDomainAllowlist(s.config.CrawlAllowSubdomains))
+	}
+	if s.skipPaths != nil && s.config.SkipPathMinCrawls > 0 {
+		simpleCrawler.SetSkipPathLearner(crawler.NewSkipPathLearner(s.skipPaths, repository.SkipPathPolicy{
+			MinCrawls: s.config.SkipPathMinCrawls,
+			MinPages:  s.config.SkipPathMinPages,
+		}))
+	}
+	if s.config.PDFTextExtraction {
+		if pdf, err := crawler.NewPopplerPDFExtractor(s.config.PDFToTextPath); err != nil {
+			s.logger.WithError(err).Warn("PDF text extraction disabled")
+		} else {
+			simpleCrawler.SetPDFExtractor(pdf)
+		}
+	}
+	if s.config.WaybackFallback {
+		simpleCrawler.SetArchiveFetcher(crawler.NewWaybackFetcher(s.config))
+	}
+	if leadStore, ok := s.repo.(repository.WhatsAppLeadRepository); ok && s.config.WhatsAppLeads {
+		simpleCrawler.SetWhatsAppLeadStore(leadStore)
+	}
+	if s.config.ClassifierExperimentSampleRate > 0 {
+		if experiment, err := crawler.NewClassifierExperiment(s.config, simpleCrawler.PreciseClassifier()); err != nil {
+			s.logger.WithError(err).Warn("Classifier experiment disabled")
+		} else {
+			simpleCrawler.SetClassifierExperiment(experiment)
+		}
+	}
+	if frontier := s.sharedFrontier(jobID); frontier != nil {
+		simpleCrawler.SetSharedFrontier(frontier)
+	}
+
+	var releases []func()
+	if s.configWatcher != nil {
+		s.configWatcher.Register(simpleCrawler)
+		releases = append(releases, func() { s.configWatcher.Unregister(simpleCrawler) })
+	}
+	if s.shutdown != nil {
+		releases = append(releases, simpleCrawler.RegisterShutdown(s.shutdown, s.config.CheckpointFile))
+	}
+
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	return simpleCrawler, release, nil
+}
+
+// sharedFrontier cria o frontier compartilhado do job (nil sem Mongo ou com FRONTIER_LEASE_TTL=0)
+func (s *PropertyService) sharedFrontier(jobID string) *crawler.SharedFrontier {
+	leases := s.urlLeases()
+	if leases == nil || s.config.FrontierLeaseTTL <= 0 {
+		return nil
+	}
+	return crawler.NewSharedFrontier(leases, crawler.FrontierOwner(jobID), s.config.FrontierLeaseTTL)
+}
+
+// urlLeases retorna o registro compartilhado de URLs em andamento (nil sem Mongo); o cache
+// local é ignorado, as leases são sempre consultadas no backend
+func (s *PropertyService) urlLeases() repository.URLLeaseRepository {
+	mongoURLRepo, ok := repository.MongoURLBackend(s.urlRepo)
+	if !ok {
+		return nil
+	}
+	return mongoURLRepo
+}
+
+// loadURLsFromFile carrega URLs do arquivo de configuração (JSON, CSV, YAML, OPML ou texto)
+func (s *PropertyService) loadURLsFromFile(filePath string) ([]string, error) {
+	urls, err := crawler.LoadURLsFromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar arquivo %s: %v", filePath, err)
+	}
 	return urls, nil
 }
 
@@ -244,9 +1322,9 @@ func (s *PropertyService) CleanupDatabase(ctx context.Context, options CleanupOp
 
 	// Limpar URLs processadas
 	if options.URLs {
-		if mongoURLRepo, ok := s.urlRepo.(*repository.MongoURLRepository); ok {
-			// Limpa tudo (idade 0)
-			if err := mongoURLRepo.CleanupOldRecords(ctx, 0); err != nil {
+		if _, ok := repository.MongoURLBackend(s.urlRepo); ok {
+			// Limpa tudo (idade 0), incluindo o cache local quando houver
+			if err := s.urlRepo.CleanupOldRecords(ctx, 0); err != nil {
 				result.Error = fmt.Sprintf("Erro ao limpar URLs: %v", err)
 				s.logger.Error("Erro ao limpar URLs", err)
 				return result
@@ -286,33 +1364,14 @@ func (s *PropertyService) loadReferenceURLs() ([]string, error) {
 		return nil, fmt.Errorf("arquivo de referência não encontrado: %s", referenceFile)
 	}
 
-	content, err := os.ReadFile(referenceFile)
+	entries, err := crawler.LoadSiteFile(referenceFile)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao ler arquivo de referência: %v", err)
 	}
 
-	lines := strings.Split(string(content), "\n")
-	var urls []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Ignorar linhas vazias e comentários
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Remover numeração se existir (ex: "1|https://...")
-		if strings.Contains(line, "|") {
-			parts := strings.SplitN(line, "|", 2)
-			if len(parts) == 2 {
-				line = strings.TrimSpace(parts[1])
-			}
-		}
-
-		// Validar se é uma URL válida
-		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
-			urls = append(urls, line)
-		}
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, entry.URL)
 	}
 
 	return urls, nil
@@ -2,6 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -265,6 +272,398 @@ func TestSearchProperties_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestImportProperties_CSV(t *testing.T) {
+	service, mockRepo, _ := setupTestService()
+	ctx := context.Background()
+
+	csvData := "url,endereco,cidade,valor,quartos,caracteristicas\n" +
+		"https://externo.com/imovel/1,Rua das Flores 100,Muzambinho,\"R$ 450.000,00\",3,piscina|garagem\n" +
+		"https://externo.com/imovel/1,Rua das Flores 100,Muzambinho,\"R$ 450.000,00\",3,piscina|garagem\n" +
+		",Rua sem URL 5,Muzambinho,300000,2,\n" +
+		"https://externo.com/imovel/3,Rua B 20,Muzambinho,abc,2,\n"
+
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(p repository.Property) bool {
+		return p.Source == repository.SourceImport && p.Valor == 450000 && p.Quartos == 3 && len(p.Caracteristicas) == 2
+	})).Return(nil).Once()
+
+	report, err := service.ImportProperties(ctx, strings.NewReader(csvData), ImportFormatCSV)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, 1, report.Duplicates)
+	assert.Equal(t, 2, report.Invalid)
+	assert.Len(t, report.Errors, 2)
+	mockRepo.AssertExpectations(t)
+
+	_, err = service.ImportProperties(ctx, strings.NewReader("endereco\nRua A"), ImportFormatCSV)
+	assert.Error(t, err)
+}
+
+func TestImportProperties_NDJSON(t *testing.T) {
+	service, mockRepo, _ := setupTestService()
+	ctx := context.Background()
+
+	// Campos mantidos pelo servidor enviados no arquivo são descartados
+	ndjson := `{"url":"https://externo.com/imovel/9","endereco":"Av. Brasil 900","cidade":"Guaxupé","valor":780000,"source":"crawler","id":"abc","hash":"h","short_id":"zzzzzzzzzz","foto_urls":["http://169.254.169.254/latest"],"manually_corrected":true,"corrected_fields":["valor"],"suspeito_fraude":true,"risco_golpe":90,"canonical_id":"c1","tags":["vip"]}
+
+{invalid
+{"url":"javascript:alert(1)","endereco":"Rua C 1","cidade":"Guaxupé","valor":100000}`
+
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(p repository.Property) bool {
+		return p.Source == repository.SourceImport && p.URL == "https://externo.com/imovel/9" && p.ID == "" &&
+			p.Hash == repository.GeneratePropertyHash(p) && p.ShortID == repository.GenerateShortID(p) &&
+			len(p.FotoURLs) == 0 && !p.ManuallyCorrected && len(p.CorrectedFields) == 0 && !p.SuspeitoFraude &&
+			p.RiscoGolpe == 0 && p.CanonicalID == "" && len(p.Tags) == 0
+	})).Return(nil).Once()
+
+	report, err := service.ImportProperties(ctx, strings.NewReader(ndjson), ImportFormatNDJSON)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, 2, report.Invalid)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Equal(t, 4, report.Errors[1].Line)
+	mockRepo.AssertExpectations(t)
+
+	assert.Equal(t, 1250000.5, mustParseImportNumber(t, "1.250.000,50"))
+	assert.Equal(t, 450000.0, mustParseImportNumber(t, "450.000"))
+	assert.Equal(t, 85.5, mustParseImportNumber(t, "85.5"))
+	assert.Equal(t, 85.5, mustParseImportNumber(t, "85,5"))
+	assert.Equal(t, 1234.56, mustParseImportNumber(t, "1,234.56"))
+	assert.Equal(t, 450000.5, mustParseImportNumber(t, "R$ 450.000,50"))
+	_, err = parseImportNumber("sob consulta")
+	assert.Error(t, err)
+}
+
+func mustParseImportNumber(t *testing.T, raw string) float64 {
+	value, err := parseImportNumber(raw)
+	assert.NoError(t, err)
+	return value
+}
+
+func TestDetectYieldAnomaly(t *testing.T) {
+	opts := YieldAlertOptions{History: 10, MinReports: 3, DropRatio: 0.5, StdDevs: 2}
+	history := []repository.CrawlReport{{Properties: 100}, {Properties: 110}, {Properties: 90}, {Properties: 100}}
+
+	alert, anomalous := detectYieldAnomaly(repository.CrawlReport{Domain: "imobiliaria.com.br", City: "Muzambinho", Properties: 10}, history, opts)
+	assert.True(t, anomalous)
+	assert.Equal(t, 100.0, alert.HistoricalMean)
+	assert.Equal(t, 90.0, alert.DropPercent)
+	assert.Equal(t, 4, alert.Reports)
+	assert.Contains(t, alert.Summary(), "imobiliaria.com.br (Muzambinho)")
+
+	// Queda dentro da variação normal do domínio
+	_, anomalous = detectYieldAnomaly(repository.CrawlReport{Properties: 80}, history, opts)
+	assert.False(t, anomalous)
+
+	// Histórico insuficiente
+	_, anomalous = detectYieldAnomaly(repository.CrawlReport{Properties: 0}, history[:2], opts)
+	assert.False(t, anomalous)
+
+	// Domínio historicamente volátil: queda de 60% ainda está dentro de 2 desvios
+	volatile := []repository.CrawlReport{{Properties: 10}, {Properties: 190}, {Properties: 20}, {Properties: 180}}
+	_, anomalous = detectYieldAnomaly(repository.CrawlReport{Properties: 40}, volatile, opts)
+	assert.False(t, anomalous)
+}
+
+// recordingNotifier guarda os alertas enviados
+type recordingNotifier struct {
+	alerts []Alert
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestCheckCitySLAs(t *testing.T) {
+	now := time.Now()
+	cityRepo := &MockCitySitesRepository{}
+	cityRepo.On("FindAllCities", mock.Anything).Return([]repository.CitySites{
+		{City: "Muzambinho", State: "MG", LastRefresh: now.Add(-2 * time.Hour)},
+		{City: "Alfenas", State: "MG", LastRefresh: now.Add(-72 * time.Hour), LastRefreshAttempt: now.Add(-time.Hour), RefreshFailures: 3, LastRefreshError: "timeout"},
+		{City: "Guaxupé", State: "MG", LastDiscovery: now.Add(-100 * time.Hour), RefreshSLAHours: 24},
+	}, nil)
+
+	service, _, _ := setupTestService()
+	service.citySitesRepo = cityRepo
+	service.config.CityRefreshSLA = 48 * time.Hour
+	notifier := &recordingNotifier{}
+	service.alerts = []AlertNotifier{notifier}
+
+	stale, err := service.CheckCitySLAs(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, stale, 2) {
+		assert.Equal(t, "Alfenas", stale[0].City)
+		assert.Equal(t, repository.StaleRefreshFailing, stale[0].Reason)
+		assert.Contains(t, stale[0].Summary(), "3 crawlings seguidos falharam")
+		assert.Equal(t, "Guaxupé", stale[1].City)
+		assert.Equal(t, repository.StaleNeverRefreshed, stale[1].Reason)
+		assert.Equal(t, 24, stale[1].SLAHours)
+	}
+	assert.Len(t, notifier.alerts, 2)
+	assert.Equal(t, "city_refresh_sla", notifier.alerts[0].Event())
+
+	// O mesmo atraso não é alertado de novo
+	_, err = service.CheckCitySLAs(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, notifier.alerts, 2)
+}
+
+// memoryLeaderLeases é um backend de eleição em memória com a mesma regra das leases do Mongo
+type memoryLeaderLeases struct {
+	lease *repository.LeaderLease
+	err   error
+}
+
+func (m *memoryLeaderLeases) AcquireLeaderLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	now := time.Now()
+	if m.lease != nil && m.lease.Holder != holder && m.lease.ExpiresAt.After(now) {
+		return false, nil
+	}
+	m.lease = &repository.LeaderLease{Name: name, Holder: holder, RenewedAt: now, ExpiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (m *memoryLeaderLeases) ReleaseLeaderLease(ctx context.Context, name, holder string) error {
+	if m.lease != nil && m.lease.Holder == holder {
+		m.lease.ExpiresAt = time.Now()
+	}
+	return nil
+}
+
+func (m *memoryLeaderLeases) FindLeaderLease(ctx context.Context, name string) (*repository.LeaderLease, error) {
+	return m.lease, nil
+}
+
+func TestLeaderElector(t *testing.T) {
+	ctx := context.Background()
+	leases := &memoryLeaderLeases{}
+	first := NewLeaderElector(leases, "scheduler", "api-0", time.Minute)
+	second := NewLeaderElector(leases, "scheduler", "api-1", time.Minute)
+
+	first.tryAcquire(ctx)
+	second.tryAcquire(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	status, err := second.Status(ctx)
+	assert.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Leader)
+	assert.Equal(t, "api-0", status.Lease.Holder)
+
+	// Failover: a lease devolvida no encerramento é assumida na tentativa seguinte
+	assert.NoError(t, first.Release(ctx))
+	second.tryAcquire(ctx)
+	first.tryAcquire(ctx) // encerrado, não disputa mais
+	assert.False(t, first.IsLeader())
+	assert.True(t, second.IsLeader())
+
+	// Falhas de renovação mantêm a liderança só até a lease vencer
+	short := NewLeaderElector(&memoryLeaderLeases{}, "scheduler", "api-2", 20*time.Millisecond)
+	short.tryAcquire(ctx)
+	assert.True(t, short.IsLeader())
+	short.leases.(*memoryLeaderLeases).err = errors.New("connection refused")
+	short.tryAcquire(ctx)
+	assert.True(t, short.IsLeader())
+	time.Sleep(30 * time.Millisecond)
+	assert.False(t, short.IsLeader())
+
+	// Sem eleição, toda réplica executa as tarefas agendadas
+	var disabled *LeaderElector
+	assert.True(t, disabled.IsLeader())
+	service, _, _ := setupTestService()
+	status, err = service.GetLeaderStatus(ctx)
+	assert.NoError(t, err)
+	assert.False(t, status.Enabled)
+	assert.True(t, status.Leader)
+}
+
+func TestJobTemplates(t *testing.T) {
+	service, _, _ := setupTestService()
+	ctx := context.Background()
+
+	// Sem suporte no repositório, apenas os templates padrão ficam disponíveis
+	templates, err := service.GetJobTemplates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 3)
+	assert.Equal(t, "daily-incremental", templates[0].Name)
+
+	template, err := service.GetJobTemplate(ctx, " Weekly-Full ")
+	assert.NoError(t, err)
+	assert.Equal(t, repository.CrawlModeFull, template.Mode)
+	assert.True(t, template.Builtin)
+
+	_, err = service.GetJobTemplate(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrJobTemplateNotFound)
+	_, err = service.SaveJobTemplate(ctx, repository.CrawlJobTemplate{Name: "custom"})
+	assert.Error(t, err)
+
+	// Substituições da execução: domínios trocados, tags somadas e orçamento zerado
+	validation, err := service.GetJobTemplate(ctx, "new-site-validation")
+	assert.NoError(t, err)
+	noLimit := 0
+	params := applyTemplateOverrides(*validation, TemplateRunOverrides{
+		Domains:  []string{"https://www.Imobiliaria.com.br/imoveis"},
+		Tags:     []string{"Lote-3"},
+		MaxPages: &noLimit,
+	})
+	assert.NoError(t, params.Normalize())
+	assert.Equal(t, []string{"imobiliaria.com.br"}, params.Domains)
+	assert.Equal(t, []string{"new-site-validation", "lote-3"}, params.Tags)
+	assert.Equal(t, 0, params.MaxPages)
+	assert.Equal(t, 15*time.Minute, params.MaxDuration())
+	assert.False(t, *params.EnableAI)
+	assert.Equal(t, []string{"new-site-validation"}, validation.Tags) // o template não é alterado
+
+	urls := filterURLsByDomain([]string{"https://imobiliaria.com.br/venda", "https://outra.com.br"}, params.Domains)
+	assert.Equal(t, []string{"https://imobiliaria.com.br/venda"}, urls)
+
+	// Parâmetros inválidos
+	invalid := applyTemplateOverrides(*validation, TemplateRunOverrides{Mode: "turbo"})
+	assert.Error(t, invalid.Normalize())
+	negative := -1
+	_, err = service.StartTemplateJob(ctx, "weekly-full", TemplateRunOverrides{MaxPages: &negative})
+	assert.ErrorIs(t, err, ErrInvalidJobTemplate)
+}
+
+// streamingPropertyRepo percorre os imóveis em memória, em ordem de ID
+type streamingPropertyRepo struct {
+	MockPropertyRepository
+	properties []repository.Property
+	block      chan struct{} // quando informado, segura a exportação até ser fechado
+}
+
+func (r *streamingPropertyRepo) StreamProperties(ctx context.Context, filter repository.PropertyFilter, afterID string, limit int, fn func(repository.Property) error) error {
+	if r.block != nil {
+		<-r.block
+	}
+	sent := 0
+	for _, property := range r.properties {
+		if property.ID <= afterID || (limit > 0 && sent == limit) {
+			continue
+		}
+		if err := fn(property); err != nil {
+			return err
+		}
+		sent++
+	}
+	return nil
+}
+
+func TestStreamProperties(t *testing.T) {
+	service, _, _ := setupTestService()
+	repo := &streamingPropertyRepo{properties: []repository.Property{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}}}
+	service.repo = repo
+	ctx := context.Background()
+	filter := repository.PropertyFilter{Cidade: "Muzambinho"}
+
+	collect := func(cursor string, limit int) ([]string, string, error) {
+		var ids []string
+		last := cursor
+		err := service.StreamProperties(ctx, filter, cursor, limit, func(property repository.Property, next string) error {
+			ids = append(ids, property.ID)
+			last = next
+			return nil
+		})
+		return ids, last, err
+	}
+
+	// Primeira parte limitada e retomada pelo cursor do último imóvel
+	ids, cursor, err := collect("", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a1", "a2"}, ids)
+	ids, _, err = collect(cursor, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a3"}, ids)
+
+	// O cursor não vale para outro filtro nem quando adulterado
+	err = service.StreamProperties(ctx, repository.PropertyFilter{Cidade: "Guaxupé"}, cursor, 0, nil)
+	assert.ErrorIs(t, err, ErrInvalidPropertyCursor)
+	_, _, err = collect("não-é-cursor", 0)
+	assert.ErrorIs(t, err, ErrInvalidPropertyCursor)
+
+	// Com todas as vagas ocupadas a exportação seguinte é recusada
+	repo.block = make(chan struct{})
+	done := make(chan error, maxConcurrentPropertyStreams)
+	for i := 0; i < maxConcurrentPropertyStreams; i++ {
+		go func() {
+			_, _, err := collect("", 0)
+			done <- err
+		}()
+	}
+	assert.Eventually(t, func() bool { return len(propertyStreamSlots) == maxConcurrentPropertyStreams }, time.Second, 5*time.Millisecond)
+	_, _, err = collect("", 0)
+	assert.ErrorIs(t, err, ErrTooManyPropertyStreams)
+	close(repo.block)
+	for i := 0; i < maxConcurrentPropertyStreams; i++ {
+		assert.NoError(t, <-done)
+	}
+}
+
+func TestIncrementalCrawlLeavesFeedEntriesPending(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Novos</title>
+			<item><title>Casa 3 quartos</title><link>https://portal.com.br/imovel/1</link></item>
+		</channel></rss>`)
+	}))
+	defer feed.Close()
+
+	seed := "https://portal.com.br/imoveis"
+	sitesFile := filepath.Join(t.TempDir(), "sites.json")
+	assert.NoError(t, os.WriteFile(sitesFile, []byte(`["`+seed+`"]`), 0o644))
+
+	mockRepo := &MockPropertyRepository{}
+	mockURLRepo := &MockURLRepository{}
+	mockURLRepo.On("IsURLProcessedRecently", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	mockURLRepo.On("GetProcessedURLsSince", mock.Anything, mock.Anything).Return([]repository.ProcessedURL{}, nil)
+	mockURLRepo.On("CleanupOldRecords", mock.Anything, mock.Anything).Return(nil)
+	// A seed foi crawleada agora há pouco: o engine incremental a pula sem buscar a página
+	mockURLRepo.On("GetFingerprint", mock.Anything, seed).Return(&repository.PageFingerprint{
+		URL:             seed,
+		LastCrawled:     time.Now(),
+		RevisitInterval: 24 * time.Hour,
+	}, nil)
+
+	service := NewPropertyService(mockRepo, mockURLRepo, &config.Config{SitesFile: sitesFile})
+	ctx := context.Background()
+	service.feeds.Poll(ctx, []string{feed.URL})
+	assert.Equal(t, 1, service.feeds.PendingCount())
+
+	// Os recursos do crawler recursivo só rodam no modo full: a entrada fica para o próximo job
+	disabled := false
+	err := service.forceCrawling(ctx, repository.CrawlJobTemplate{Mode: repository.CrawlModeIncremental, EnableAI: &disabled}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, service.feeds.PendingCount())
+	mockURLRepo.AssertCalled(t, "GetFingerprint", mock.Anything, seed)
+}
+
+func TestKnownCrawlURL(t *testing.T) {
+	sitesFile := filepath.Join(t.TempDir(), "sites.json")
+	assert.NoError(t, os.WriteFile(sitesFile, []byte(`["https://www.imobiliaria.com.br/imoveis"]`), 0o644))
+	service, _, _ := setupTestService()
+	service.config.SitesFile = sitesFile
+	ctx := context.Background()
+
+	assert.True(t, service.KnownCrawlURL(ctx, "https://imobiliaria.com.br/imovel/1"))
+	assert.True(t, service.KnownCrawlURL(ctx, "http://www.imobiliaria.com.br/imovel/1"))
+	assert.True(t, service.KnownCrawlURL(ctx, "https://lancamentos.imobiliaria.com.br/imovel/2"))
+
+	// Redirecionamento aberto: outros sites, esquemas e URLs sem host são recusados
+	assert.False(t, service.KnownCrawlURL(ctx, "https://golpe.com/imovel/1"))
+	assert.False(t, service.KnownCrawlURL(ctx, "https://imobiliaria.com.br.golpe.com/imovel/1"))
+	assert.False(t, service.KnownCrawlURL(ctx, "https://falsaimobiliaria.com.br/imovel/1"))
+	assert.False(t, service.KnownCrawlURL(ctx, "javascript:alert(1)"))
+	assert.False(t, service.KnownCrawlURL(ctx, "//imobiliaria.com.br/imovel/1"))
+	assert.False(t, service.KnownCrawlURL(ctx, ""))
+}
+
 // Benchmark tests
 func BenchmarkSaveProperty(b *testing.B) {
 	service, mockRepo, _ := setupTestService()
@@ -306,3 +705,33 @@ func BenchmarkGetAllProperties(b *testing.B) {
 		service.GetAllProperties(ctx)
 	}
 }
+
+// staticDomainConfigSource devolve as configurações por domínio definidas no teste
+type staticDomainConfigSource struct {
+	configs []repository.DomainConfig
+}
+
+func (s *staticDomainConfigSource) FindAllDomainConfigs(ctx context.Context) ([]repository.DomainConfig, error) {
+	return s.configs, nil
+}
+
+func TestGetConfigChanges(t *testing.T) {
+	service, _, _ := setupTestService()
+	assert.Empty(t, service.GetConfigChanges(10))
+
+	source := &staticDomainConfigSource{configs: []repository.DomainConfig{{Domain: "site.com"}}}
+	watcher := config.NewConfigWatcher("", source, time.Minute)
+	assert.NoError(t, watcher.Reload(context.Background()))
+	service.SetConfigWatcher(watcher)
+
+	source.configs = []repository.DomainConfig{{Domain: "site.com", EnableJS: true}}
+	assert.NoError(t, watcher.Reload(context.Background()))
+
+	// Da alteração mais recente para a mais antiga
+	changes := service.GetConfigChanges(10)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "enable_js", changes[0].Field)
+	assert.Equal(t, 2, changes[0].Version)
+	assert.Equal(t, "created", changes[1].NewValue)
+	assert.Len(t, service.GetConfigChanges(1), 1)
+}
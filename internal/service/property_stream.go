@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// maxConcurrentPropertyStreams limita as exportações em fluxo simultâneas (cada uma mantém um
+// cursor aberto no Mongo enquanto o cliente lê)
+const maxConcurrentPropertyStreams = 4
+
+// propertyCursorVersion é a versão do formato do token de continuação
+const propertyCursorVersion = "1"
+
+var (
+	// ErrTooManyPropertyStreams indica que todas as vagas de exportação em fluxo estão ocupadas
+	ErrTooManyPropertyStreams = errors.New("too many concurrent property streams")
+	// ErrInvalidPropertyCursor indica um token de continuação ilegível ou de outro filtro
+	ErrInvalidPropertyCursor = errors.New("invalid property stream cursor")
+)
+
+var propertyStreamSlots = make(chan struct{}, maxConcurrentPropertyStreams)
+
+// PropertyStreamCheckpoint é a linha de controle intercalada com os imóveis no NDJSON: o cursor
+// retoma a exportação logo depois do último imóvel enviado
+type PropertyStreamCheckpoint struct {
+	Cursor string `json:"cursor"`
+	Count  int    `json:"count"`           // imóveis enviados nesta resposta
+	Done   bool   `json:"done,omitempty"`  // não há mais imóveis depois do cursor
+	Error  string `json:"error,omitempty"` // a exportação foi interrompida; retome pelo cursor
+}
+
+// StreamProperties percorre os imóveis do filtro em ordem de _id a partir do token de continuação
+// (vazio começa do início), chamando emit com cada imóvel e o token que retoma logo depois dele.
+// Retorna ErrInvalidPropertyCursor e ErrTooManyPropertyStreams antes de emitir qualquer imóvel
+func (s *PropertyService) StreamProperties(ctx context.Context, filter repository.PropertyFilter, cursor string, limit int, emit func(property repository.Property, next string) error) error {
+	streamRepo, ok := s.repo.(repository.PropertyStreamRepository)
+	if !ok {
+		return errors.New("property streaming not supported by property repository")
+	}
+	afterID, err := DecodePropertyCursor(cursor, filter)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case propertyStreamSlots <- struct{}{}:
+		defer func() { <-propertyStreamSlots }()
+	default:
+		return ErrTooManyPropertyStreams
+	}
+
+	return streamRepo.StreamProperties(ctx, filter, afterID, limit, func(property repository.Property) error {
+		return emit(property, EncodePropertyCursor(property.ID, filter))
+	})
+}
+
+// EncodePropertyCursor gera o token de continuação depois do imóvel; o token guarda também a
+// impressão do filtro, para não ser reutilizado numa exportação com outros filtros
+func EncodePropertyCursor(lastID string, filter repository.PropertyFilter) string {
+	raw := propertyCursorVersion + ":" + propertyFilterKey(filter) + ":" + lastID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodePropertyCursor retorna o _id do último imóvel enviado (vazio para o token vazio)
+func DecodePropertyCursor(cursor string, filter repository.PropertyFilter) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPropertyCursor, err)
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != propertyCursorVersion || parts[2] == "" {
+		return "", fmt.Errorf("%w: malformed token", ErrInvalidPropertyCursor)
+	}
+	if parts[1] != propertyFilterKey(filter) {
+		return "", fmt.Errorf("%w: token belongs to another filter", ErrInvalidPropertyCursor)
+	}
+	return parts[2], nil
+}
+
+// propertyFilterKey resume o filtro em 12 caracteres hexadecimais
+func propertyFilterKey(filter repository.PropertyFilter) string {
+	data, _ := json.Marshal(filter)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
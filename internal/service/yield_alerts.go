@@ -0,0 +1,331 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/dujoseaugusto/go-crawler-project/internal/config"
+	"github.com/dujoseaugusto/go-crawler-project/internal/repository"
+)
+
+// YieldAlert indica que um crawling rendeu muito menos imóveis que a média histórica do
+// domínio (sinal de bloqueio ou de mudança no layout do site)
+type YieldAlert struct {
+	JobID          string    `json:"job_id,omitempty"`
+	Domain         string    `json:"domain"`
+	City           string    `json:"city,omitempty"`
+	Properties     int       `json:"properties"`
+	HistoricalMean float64   `json:"historical_mean"`
+	StdDev         float64   `json:"std_dev"`
+	DropPercent    float64   `json:"drop_percent"`
+	Reports        int       `json:"reports"` // crawlings anteriores usados na média
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+// YieldAlertOptions define os limites estatísticos da detecção
+type YieldAlertOptions struct {
+	History    int     // crawlings anteriores considerados
+	MinReports int     // histórico mínimo para avaliar o domínio
+	DropRatio  float64 // queda mínima em relação à média (0.5 = metade)
+	StdDevs    float64 // e abaixo de média - StdDevs*desvio padrão
+}
+
+// Alert é um alerta enviado aos notificadores: queda no volume de um domínio ou cidade com os
+// dados desatualizados além do SLA
+type Alert interface {
+	Event() string   // identificador do tipo de alerta no webhook
+	Subject() string // assunto do e-mail
+	Summary() string // descrição em uma linha
+}
+
+// AlertNotifier envia os alertas a um canal externo
+type AlertNotifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// WebhookNotifier envia o alerta em JSON via POST
+type WebhookNotifier struct {
+	URL    string
+	client *http.Client
+}
+
+// NewWebhookNotifier cria o notificador de webhook
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Notify envia o alerta ao webhook
+func (wn *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"event": alert.Event(),
+		"text":  alert.Summary(),
+		"alert": alert,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailNotifier envia o alerta por e-mail via SMTP
+type EmailNotifier struct {
+	Addr     string // host:porta
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Notify envia o alerta por e-mail
+func (en *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	var auth smtp.Auth
+	if en.Username != "" {
+		host := en.Addr
+		if idx := strings.LastIndex(host, ":"); idx >= 0 {
+			host = host[:idx]
+		}
+		auth = smtp.PlainAuth("", en.Username, en.Password, host)
+	}
+
+	subject := "[go-crawler] " + alert.Subject()
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		en.From, strings.Join(en.To, ", "), subject, alert.Summary())
+
+	if err := smtp.SendMail(en.Addr, auth, en.From, en.To, []byte(body)); err != nil {
+		return fmt.Errorf("failed to send alert email: %v", err)
+	}
+	return nil
+}
+
+// Event identifica o alerta de queda no volume
+func (a YieldAlert) Event() string {
+	return "crawl_yield_anomaly"
+}
+
+// Subject é o assunto do e-mail do alerta
+func (a YieldAlert) Subject() string {
+	return fmt.Sprintf("Queda no volume de imóveis: %s", a.Domain)
+}
+
+// Summary descreve o alerta em uma linha
+func (a YieldAlert) Summary() string {
+	location := a.Domain
+	if a.City != "" {
+		location = fmt.Sprintf("%s (%s)", a.Domain, a.City)
+	}
+	return fmt.Sprintf("Crawling de %s encontrou %d imóveis, %.0f%% abaixo da média de %.1f nos últimos %d crawlings",
+		location, a.Properties, a.DropPercent, a.HistoricalMean, a.Reports)
+}
+
+// newAlertNotifiers cria os notificadores configurados (webhook e/ou e-mail)
+func newAlertNotifiers(cfg *config.Config) []AlertNotifier {
+	var notifiers []AlertNotifier
+	if cfg.AlertWebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.AlertWebhookURL))
+	}
+	if cfg.AlertSMTPAddr != "" && cfg.AlertEmailFrom != "" && len(cfg.AlertEmailTo) > 0 {
+		notifiers = append(notifiers, &EmailNotifier{
+			Addr:     cfg.AlertSMTPAddr,
+			Username: cfg.AlertSMTPUsername,
+			Password: cfg.AlertSMTPPassword,
+			From:     cfg.AlertEmailFrom,
+			To:       cfg.AlertEmailTo,
+		})
+	}
+	return notifiers
+}
+
+// yieldAlertOptions lê os limites da detecção da configuração
+func yieldAlertOptions(cfg *config.Config) YieldAlertOptions {
+	return YieldAlertOptions{
+		History:    cfg.YieldAlertHistory,
+		MinReports: cfg.YieldAlertMinReports,
+		DropRatio:  cfg.YieldAlertDropRatio,
+		StdDevs:    cfg.YieldAlertStdDevs,
+	}
+}
+
+// detectYieldAnomaly compara o volume atual com o histórico do domínio: há anomalia quando
+// o volume cai pelo menos DropRatio abaixo da média e também fica StdDevs desvios abaixo dela
+func detectYieldAnomaly(current repository.CrawlReport, history []repository.CrawlReport, opts YieldAlertOptions) (YieldAlert, bool) {
+	if opts.History > 0 && len(history) > opts.History {
+		history = history[:opts.History]
+	}
+	if len(history) == 0 || len(history) < opts.MinReports {
+		return YieldAlert{}, false
+	}
+
+	var sum float64
+	for _, report := range history {
+		sum += float64(report.Properties)
+	}
+	mean := sum / float64(len(history))
+	if mean <= 0 {
+		return YieldAlert{}, false
+	}
+
+	var variance float64
+	for _, report := range history {
+		diff := float64(report.Properties) - mean
+		variance += diff * diff
+	}
+	stdDev := math.Sqrt(variance / float64(len(history)))
+
+	value := float64(current.Properties)
+	if value > mean*(1-opts.DropRatio) || value >= mean-opts.StdDevs*stdDev {
+		return YieldAlert{}, false
+	}
+
+	return YieldAlert{
+		JobID:          current.JobID,
+		Domain:         current.Domain,
+		City:           current.City,
+		Properties:     current.Properties,
+		HistoricalMean: math.Round(mean*10) / 10,
+		StdDev:         math.Round(stdDev*10) / 10,
+		DropPercent:    math.Round((1 - value/mean) * 100),
+		Reports:        len(history),
+		DetectedAt:     current.CrawledAt,
+	}, true
+}
+
+// checkCrawlYield grava o volume de imóveis por domínio do crawling, com o rendimento por origem
+// de descoberta, e alerta os domínios com queda anormal em relação ao histórico. Domínios
+// semeados sem nenhum imóvel entram com zero.
+func (s *PropertyService) checkCrawlYield(ctx context.Context, jobID string, seeds []string, coverage []repository.DomainCoverage, sources map[string]map[string]repository.SourceYield) {
+	reportRepo, ok := s.repo.(repository.CrawlReportRepository)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	cities := s.domainCities(ctx)
+	yields := make(map[string]int)
+	for _, seed := range seeds {
+		domain := repository.NormalizeDomain(seed)
+		if _, exists := yields[domain]; domain != "" && !exists {
+			yields[domain] = 0
+		}
+	}
+	for _, entry := range coverage {
+		yields[entry.Domain] = entry.DiscoveredURLs
+	}
+
+	opts := yieldAlertOptions(s.config)
+	reports := make([]repository.CrawlReport, 0, len(yields))
+	for domain, properties := range yields {
+		report := repository.CrawlReport{
+			JobID:      jobID,
+			Domain:     domain,
+			City:       cities[domain],
+			Properties: properties,
+			CrawledAt:  now,
+			Sources:    sources[domain],
+		}
+		reports = append(reports, report)
+
+		history, err := reportRepo.RecentCrawlReports(ctx, domain, opts.History)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to load crawl history")
+			continue
+		}
+		if alert, anomalous := detectYieldAnomaly(report, history, opts); anomalous {
+			s.sendYieldAlert(ctx, alert)
+		}
+	}
+
+	if err := reportRepo.SaveCrawlReports(ctx, reports); err != nil {
+		s.logger.WithError(err).Warn("Failed to save crawl reports")
+	}
+}
+
+// GetDiscoverySourceYield soma o rendimento por origem de descoberta dos últimos crawlings do
+// domínio e marca as origens desativadas na configuração do domínio
+func (s *PropertyService) GetDiscoverySourceYield(ctx context.Context, domain string, crawls int) ([]repository.DiscoverySourceYield, error) {
+	reportRepo, ok := s.repo.(repository.CrawlReportRepository)
+	if !ok {
+		return nil, errors.New("crawl reports not supported by property repository")
+	}
+
+	reports, err := reportRepo.RecentCrawlReports(ctx, domain, crawls)
+	if err != nil {
+		return nil, err
+	}
+	yields := repository.AggregateSourceYield(reports)
+
+	if s.configWatcher != nil {
+		if domainConfig, exists := s.configWatcher.Current().DomainConfig(domain); exists {
+			for i := range yields {
+				yields[i].Disabled = !domainConfig.DiscoverySourceEnabled(yields[i].Source)
+			}
+		}
+	}
+	return yields, nil
+}
+
+// sendYieldAlert registra o alerta e o envia aos notificadores configurados
+func (s *PropertyService) sendYieldAlert(ctx context.Context, alert YieldAlert) {
+	s.logger.WithFields(map[string]interface{}{
+		"domain":          alert.Domain,
+		"city":            alert.City,
+		"properties":      alert.Properties,
+		"historical_mean": alert.HistoricalMean,
+		"drop_percent":    alert.DropPercent,
+	}).Warn("Crawl yield anomaly detected")
+
+	for _, notifier := range s.alerts {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			s.logger.WithError(err).Warn("Failed to send yield alert")
+		}
+	}
+}
+
+// domainCities mapeia o domínio de cada site cadastrado para a sua cidade
+func (s *PropertyService) domainCities(ctx context.Context) map[string]string {
+	cities := make(map[string]string)
+	if s.citySitesRepo == nil {
+		return cities
+	}
+
+	allCities, err := s.citySitesRepo.FindAllCities(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to load city sites for crawl reports")
+		return cities
+	}
+	for _, city := range allCities {
+		for _, site := range city.Sites {
+			domain := repository.NormalizeDomain(site.Domain)
+			if domain == "" {
+				domain = repository.NormalizeDomain(site.URL)
+			}
+			if domain != "" {
+				cities[domain] = city.City
+			}
+		}
+	}
+	return cities
+}
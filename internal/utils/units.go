@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unidades de área aceitas na conversão
+const (
+	AreaSquareMeters     = "m2"
+	AreaHectare          = "ha"
+	AreaAlqueire         = "alqueire" // alqueire mineiro (o usual na região atendida)
+	AreaAlqueirePaulista = "alqueire_paulista"
+	AreaAlqueireBaiano   = "alqueire_baiano"
+)
+
+// Equivalência das unidades em m²
+const (
+	SquareMetersPerHectare          = 10000.0
+	SquareMetersPerAlqueire         = 48400.0
+	SquareMetersPerAlqueirePaulista = 24200.0
+	SquareMetersPerAlqueireBaiano   = 96800.0
+)
+
+// areaFactors convertem cada unidade para m²
+var areaFactors = map[string]float64{
+	AreaSquareMeters:     1,
+	AreaHectare:          SquareMetersPerHectare,
+	AreaAlqueire:         SquareMetersPerAlqueire,
+	AreaAlqueirePaulista: SquareMetersPerAlqueirePaulista,
+	AreaAlqueireBaiano:   SquareMetersPerAlqueireBaiano,
+}
+
+// areaUnitLabels são os rótulos de exibição de cada unidade
+var areaUnitLabels = map[string]string{
+	AreaSquareMeters:     "m²",
+	AreaHectare:          "ha",
+	AreaAlqueire:         "alqueires",
+	AreaAlqueirePaulista: "alqueires paulistas",
+	AreaAlqueireBaiano:   "alqueires baianos",
+}
+
+// areaUnitAliases mapeiam as grafias encontradas nos anúncios (já normalizadas) para as unidades
+var areaUnitAliases = map[string]string{
+	"m2": AreaSquareMeters, "metro quadrado": AreaSquareMeters,
+	"metros quadrados": AreaSquareMeters, "ha": AreaHectare, "hectare": AreaHectare,
+	"hectares": AreaHectare, "alq": AreaAlqueire, "alqueire": AreaAlqueire, "alqueires": AreaAlqueire,
+	"alqueire mineiro": AreaAlqueire, "alqueires mineiros": AreaAlqueire,
+	"alqueire goiano": AreaAlqueire, "alqueires goianos": AreaAlqueire,
+	"alqueire paulista": AreaAlqueirePaulista, "alqueires paulistas": AreaAlqueirePaulista,
+	"alqueire baiano": AreaAlqueireBaiano, "alqueires baianos": AreaAlqueireBaiano,
+}
+
+// Números no formato brasileiro ("450.000,00", "1.500", "85,5") ou simples ("85.5", "1200")
+const numberExpression = `\d{1,3}(?:\.\d{3})+(?:,\d+)?|\d+(?:[.,]\d+)?`
+
+var (
+	numberPattern    = regexp.MustCompile(numberExpression)
+	thousandsPattern = regexp.MustCompile(`^\d{1,3}(?:\.\d{3})+$`)
+
+	// amountPattern captura o número e o multiplicador por extenso ("350 mil", "1,2 milhão")
+	amountPattern = regexp.MustCompile(`(?i)(` + numberExpression + `)(?:\s*(milh[õo]es|milh[ãa]o|mil\b|mi\b|k\b))?`)
+
+	// areaPattern captura o número e a unidade de área ("300 m²", "5 ha", "2 alqueires paulistas")
+	areaPattern = regexp.MustCompile(`(?i)(` + numberExpression + `)\s*(m²|m2\b|metros?\s+quadrados?|hectares?|ha\b|alqueires?(?:\s+(?:mineiros?|paulistas?|baianos?|goianos?))?|alq\b\.?)`)
+)
+
+// ParseBrazilianNumber converte um número formatado ("450.000,00", "1.500", "85,5", "85.5")
+// em float64. Pontos seguidos de grupos de três dígitos são separadores de milhar.
+func ParseBrazilianNumber(raw string) (float64, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, false
+	}
+
+	lastComma := strings.LastIndex(raw, ",")
+	lastDot := strings.LastIndex(raw, ".")
+	switch {
+	case lastComma >= 0 && lastDot > lastComma:
+		// Formato internacional ("1,234.56")
+		raw = strings.ReplaceAll(raw, ",", "")
+	case lastComma >= 0:
+		raw = strings.ReplaceAll(raw[:lastComma], ".", "") + "." + raw[lastComma+1:]
+		raw = strings.ReplaceAll(raw, ",", "")
+	case strings.Count(raw, ".") > 1 || thousandsPattern.MatchString(raw):
+		raw = strings.ReplaceAll(raw, ".", "")
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// ParseNumbers retorna todos os números formatados encontrados no texto, na ordem em que aparecem
+func ParseNumbers(text string) []float64 {
+	var values []float64
+	for _, raw := range numberPattern.FindAllString(text, -1) {
+		if value, ok := ParseBrazilianNumber(raw); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ParseAmount extrai o valor monetário de um texto ("R$ 450.000,00", "R$ 350 mil",
+// "1,2 milhão"). Quando há "R$" o número seguinte é usado; 0 se não houver valor.
+func ParseAmount(text string) float64 {
+	if index := strings.Index(strings.ToUpper(text), "R$"); index >= 0 {
+		text = text[index+2:]
+	}
+	match := amountPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+	value, ok := ParseBrazilianNumber(match[1])
+	if !ok {
+		return 0
+	}
+
+	switch multiplier := strings.ToLower(match[2]); {
+	case strings.HasPrefix(multiplier, "milh"), multiplier == "mi":
+		value *= 1000000
+	case multiplier == "mil", multiplier == "k":
+		value *= 1000
+	}
+	return value
+}
+
+// NormalizeAreaUnit converte a grafia da unidade ("hectares", "alqueire paulista", "m²") para a
+// unidade canônica; false se a unidade não for reconhecida
+func NormalizeAreaUnit(unit string) (string, bool) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(unit), "."))), " ")
+	if normalized == "m²" {
+		return AreaSquareMeters, true
+	}
+	if _, exists := areaFactors[normalized]; exists {
+		return normalized, true
+	}
+	canonical, exists := areaUnitAliases[NormalizeText(normalized)]
+	return canonical, exists
+}
+
+// ConvertArea converte a área entre unidades (m2, ha, alqueire, alqueire_paulista, alqueire_baiano)
+func ConvertArea(value float64, from, to string) (float64, error) {
+	fromUnit, ok := NormalizeAreaUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown area unit: %s", from)
+	}
+	toUnit, ok := NormalizeAreaUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown area unit: %s", to)
+	}
+	return value * areaFactors[fromUnit] / areaFactors[toUnit], nil
+}
+
+// ParseArea extrai a primeira área citada no texto e a converte para m² ("300 m²" -> 300,
+// "5 ha" -> 50000, "2 alqueires" -> 96800); 0 se não houver área
+func ParseArea(text string) float64 {
+	for _, match := range areaPattern.FindAllStringSubmatch(text, -1) {
+		value, ok := ParseBrazilianNumber(match[1])
+		if !ok || value <= 0 {
+			continue
+		}
+		unit, ok := NormalizeAreaUnit(match[2])
+		if !ok {
+			continue
+		}
+		return value * areaFactors[unit]
+	}
+	return 0
+}
+
+// FormatBrazilianNumber formata o número com separador de milhar "." e decimais com ","
+func FormatBrazilianNumber(value float64, decimals int) string {
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction := formatted, ""
+	if index := strings.Index(formatted, "."); index >= 0 {
+		integer, fraction = formatted[:index], formatted[index+1:]
+	}
+
+	var b strings.Builder
+	if value < 0 && strings.Trim(formatted, "0.") != "" {
+		b.WriteString("-")
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(".")
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(",")
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// FormatCurrency formata o valor em reais ("R$ 450.000,00")
+func FormatCurrency(value float64) string {
+	return "R$ " + FormatBrazilianNumber(value, 2)
+}
+
+// FormatArea formata a área em m² para exibição: metros quadrados abaixo de 1 ha
+// ("360 m²") e hectares a partir dele ("4,84 ha")
+func FormatArea(squareMeters float64) string {
+	if squareMeters >= SquareMetersPerHectare {
+		return FormatBrazilianNumber(squareMeters/SquareMetersPerHectare, 2) + " ha"
+	}
+	decimals := 0
+	if squareMeters != math.Trunc(squareMeters) {
+		decimals = 2
+	}
+	return FormatBrazilianNumber(squareMeters, decimals) + " m²"
+}
+
+// FormatAreaIn formata a área já convertida para a unidade informada ("2,50 alqueires")
+func FormatAreaIn(value float64, unit string) string {
+	if canonical, ok := NormalizeAreaUnit(unit); ok {
+		unit = areaUnitLabels[canonical]
+	}
+	return FormatBrazilianNumber(value, 2) + " " + unit
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseBrazilianNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		ok       bool
+	}{
+		{"450.000,00", 450000, true},
+		{"1.500", 1500, true},
+		{"1.234.567", 1234567, true},
+		{"85,5", 85.5, true},
+		{"85.5", 85.5, true},
+		{"1,234.56", 1234.56, true},
+		{"1200", 1200, true},
+		{"", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, ok := ParseBrazilianNumber(tt.input)
+			if ok != tt.ok || result != tt.expected {
+				t.Errorf("ParseBrazilianNumber(%q) = %v, %v; expected %v, %v", tt.input, result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"R$ 450.000,00", 450000},
+		{"R$ 350 mil", 350000},
+		{"Valor: R$ 1,2 milhão", 1200000},
+		{"2,5 milhões", 2500000},
+		{"3 quartos por R$ 280.000", 280000},
+		{"R$ 1.800/mês", 1800},
+		{"Consulte", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := ParseAmount(tt.input); result != tt.expected {
+				t.Errorf("ParseAmount(%q) = %v; expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseArea(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"Casa com 150m² de área", 150},
+		{"Terreno de 1.200 m2", 1200},
+		{"85,5 metros quadrados", 85.5},
+		{"Sítio com 5 hectares", 50000},
+		{"Fazenda de 2 alqueires", 96800},
+		{"Chácara de 1 alqueire paulista", 24200},
+		{"3 quartos, sem área informada", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := ParseArea(tt.input); result != tt.expected {
+				t.Errorf("ParseArea(%q) = %v; expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertArea(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from     string
+		to       string
+		expected float64
+	}{
+		{1, "alqueire", "ha", 4.84},
+		{10, "ha", "m2", 100000},
+		{24200, "m²", "alqueire paulista", 1},
+		{2, "Alqueires", "hectares", 9.68},
+	}
+
+	for _, tt := range tests {
+		result, err := ConvertArea(tt.value, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("ConvertArea(%v, %q, %q) returned error: %v", tt.value, tt.from, tt.to, err)
+		}
+		if math.Abs(result-tt.expected) > 1e-9 {
+			t.Errorf("ConvertArea(%v, %q, %q) = %v; expected %v", tt.value, tt.from, tt.to, result, tt.expected)
+		}
+	}
+
+	if _, err := ConvertArea(1, "acre", "ha"); err == nil {
+		t.Error("ConvertArea should reject unknown units")
+	}
+}
+
+func TestFormatBrazilianNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{"currency", FormatCurrency(450000), "R$ 450.000,00"},
+		{"negative", FormatBrazilianNumber(-1234.5, 1), "-1.234,5"},
+		{"small area", FormatArea(360), "360 m²"},
+		{"hectares", FormatArea(48400), "4,84 ha"},
+		{"alqueires", FormatAreaIn(2.5, "alqueire"), "2,50 alqueires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.result != tt.expected {
+				t.Errorf("got %q; expected %q", tt.result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Painel administrativo: métricas de qualidade dos dados por domínio (GET /stats/quality)
+
+document.addEventListener('DOMContentLoaded', function() {
+    document.getElementById('qualityForm').addEventListener('submit', function(event) {
+        event.preventDefault();
+        loadQuality();
+    });
+    loadQuality();
+});
+
+// Carregar métricas de qualidade da API
+async function loadQuality() {
+    const params = new URLSearchParams();
+    const domain = document.getElementById('domain').value.trim();
+    if (domain) params.append('domain', domain);
+    params.append('days', document.getElementById('days').value);
+
+    try {
+        const response = await fetch(`/stats/quality?${params.toString()}`);
+        if (!response.ok) {
+            throw new Error(`Erro na API: ${response.status} ${response.statusText}`);
+        }
+        const data = await response.json();
+        renderQuality(data.data.domains || []);
+        renderTrend(data.data.trend || []);
+    } catch (error) {
+        console.error('Erro ao carregar métricas de qualidade:', error);
+        document.getElementById('qualityTable').innerHTML =
+            `<tr><td colspan="8" class="text-danger text-center">${escapeHtml(error.message)}</td></tr>`;
+    }
+}
+
+// Exibir as métricas mais recentes de cada domínio
+function renderQuality(domains) {
+    const table = document.getElementById('qualityTable');
+    if (domains.length === 0) {
+        table.innerHTML = '<tr><td colspan="8" class="text-muted text-center">Nenhuma métrica disponível</td></tr>';
+        return;
+    }
+    table.innerHTML = domains.map(metric => `
+        <tr>
+            <td>${escapeHtml(metric.domain)}</td>
+            <td class="text-end">${metric.properties}</td>
+            ${percentCell(metric.with_price_pct)}
+            ${percentCell(metric.with_address_pct)}
+            ${percentCell(metric.with_rooms_pct)}
+            ${percentCell(metric.with_photos_pct)}
+            <td class="text-end">${formatScore(metric)}</td>
+            <td class="text-end text-muted">${escapeHtml(metric.day)}</td>
+        </tr>`).join('');
+}
+
+// Exibir a série diária, com a variação em relação ao dia anterior do mesmo domínio
+function renderTrend(trend) {
+    const previous = {};
+    const rows = trend.map(metric => {
+        const before = previous[metric.domain];
+        previous[metric.domain] = metric;
+        return `
+        <tr>
+            <td>${escapeHtml(metric.domain)}</td>
+            <td>${escapeHtml(metric.day)}</td>
+            <td class="text-end">${metric.properties}</td>
+            ${percentCell(metric.with_price_pct, before && before.with_price_pct)}
+            ${percentCell(metric.with_address_pct, before && before.with_address_pct)}
+            ${percentCell(metric.with_rooms_pct, before && before.with_rooms_pct)}
+            ${percentCell(metric.with_photos_pct, before && before.with_photos_pct)}
+            <td class="text-end">${formatScore(metric)}</td>
+        </tr>`;
+    });
+    document.getElementById('trendTable').innerHTML = rows.join('');
+}
+
+// Célula de percentual colorida pela faixa, com a variação quando informada
+function percentCell(value, previous) {
+    let color = 'text-danger';
+    if (value >= 90) color = 'text-success';
+    else if (value >= 60) color = 'text-warning';
+
+    let delta = '';
+    if (typeof previous === 'number' && previous !== value) {
+        const diff = value - previous;
+        delta = ` <small class="${diff > 0 ? 'text-success' : 'text-danger'}">(${diff > 0 ? '+' : ''}${diff.toFixed(1)})</small>`;
+    }
+    return `<td class="text-end ${color}">${value.toFixed(1)}%${delta}</td>`;
+}
+
+// Nota média de extração (sem anúncios com notas, exibe "-")
+function formatScore(metric) {
+    if (!metric.scored_properties) return '<span class="text-muted">-</span>';
+    return metric.avg_extraction_score.toFixed(2);
+}
+
+function escapeHtml(text) {
+    const div = document.createElement('div');
+    div.textContent = text == null ? '' : String(text);
+    return div.innerHTML;
+}